@@ -43,6 +43,7 @@ import (
 	"github.com/pingcap/tidb/util/stmtsummary"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testleak"
+	"github.com/pingcap/tidb/util/txninfo"
 	dto "github.com/prometheus/client_model/go"
 )
 
@@ -93,6 +94,10 @@ func (msm *mockSessionManager) Kill(cid uint64, query bool) {
 func (msm *mockSessionManager) KillAllConnections() {
 }
 
+func (msm *mockSessionManager) ShowTxnList() []*txninfo.TxnInfo {
+	return nil
+}
+
 func (msm *mockSessionManager) UpdateTLSConfig(cfg *tls.Config) {
 }
 
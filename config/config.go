@@ -115,6 +115,9 @@ type Config struct {
 	PreparedPlanCache          PreparedPlanCache  `toml:"prepared-plan-cache" json:"prepared-plan-cache"`
 	OpenTracing                OpenTracing        `toml:"opentracing" json:"opentracing"`
 	ProxyProtocol              ProxyProtocol      `toml:"proxy-protocol" json:"proxy-protocol"`
+	XProtocol                  XProtocol          `toml:"x-protocol" json:"x-protocol"`
+	Audit                      Audit              `toml:"audit" json:"audit"`
+	WorkerPool                 WorkerPool         `toml:"worker-pool" json:"worker-pool"`
 	PDClient                   tikvcfg.PDClient   `toml:"pd-client" json:"pd-client"`
 	TiKVClient                 tikvcfg.TiKVClient `toml:"tikv-client" json:"tikv-client"`
 	Binlog                     Binlog             `toml:"binlog" json:"binlog"`
@@ -144,6 +147,8 @@ type Config struct {
 	IsolationRead IsolationRead `toml:"isolation-read" json:"isolation-read"`
 	// MaxServerConnections is the maximum permitted number of simultaneous client connections.
 	MaxServerConnections uint32 `toml:"max-server-connections" json:"max-server-connections"`
+	// MaxConnectionsPerIP is the maximum permitted number of simultaneous client connections from the same IP.
+	MaxConnectionsPerIP uint32 `toml:"max-connections-per-ip" json:"max-connections-per-ip"`
 	// NewCollationsEnabledOnFirstBootstrap indicates if the new collations are enabled, it effects only when a TiDB cluster bootstrapped on the first time.
 	NewCollationsEnabledOnFirstBootstrap bool `toml:"new_collations_enabled_on_first_bootstrap" json:"new_collations_enabled_on_first_bootstrap"`
 	// Experimental contains parameters for experimental features.
@@ -312,12 +317,28 @@ type Log struct {
 	// File log config.
 	File logutil.FileLogConfig `toml:"file" json:"file"`
 
-	EnableSlowLog       bool   `toml:"enable-slow-log" json:"enable-slow-log"`
-	SlowQueryFile       string `toml:"slow-query-file" json:"slow-query-file"`
-	SlowThreshold       uint64 `toml:"slow-threshold" json:"slow-threshold"`
-	ExpensiveThreshold  uint   `toml:"expensive-threshold" json:"expensive-threshold"`
-	QueryLogMaxLen      uint64 `toml:"query-log-max-len" json:"query-log-max-len"`
-	RecordPlanInSlowLog uint32 `toml:"record-plan-in-slow-log" json:"record-plan-in-slow-log"`
+	EnableSlowLog       bool    `toml:"enable-slow-log" json:"enable-slow-log"`
+	SlowQueryFile       string  `toml:"slow-query-file" json:"slow-query-file"`
+	SlowThreshold       uint64  `toml:"slow-threshold" json:"slow-threshold"`
+	SlowLogSampleRate   float64 `toml:"slow-log-sample-rate" json:"slow-log-sample-rate"`
+	ExpensiveThreshold  uint    `toml:"expensive-threshold" json:"expensive-threshold"`
+	QueryLogMaxLen      uint64  `toml:"query-log-max-len" json:"query-log-max-len"`
+	RecordPlanInSlowLog uint32  `toml:"record-plan-in-slow-log" json:"record-plan-in-slow-log"`
+	// SlowLogFormat is the format of the slow log, one of text or json.
+	SlowLogFormat string `toml:"slow-log-format" json:"slow-log-format"`
+	// SlowLogMaxSize, SlowLogMaxDays and SlowLogMaxBackups rotate the slow log file, 0 means using the ones of the
+	// log file.
+	SlowLogMaxSize    uint `toml:"slow-log-max-size" json:"slow-log-max-size"`
+	SlowLogMaxDays    uint `toml:"slow-log-max-days" json:"slow-log-max-days"`
+	SlowLogMaxBackups uint `toml:"slow-log-max-backups" json:"slow-log-max-backups"`
+	// SlowLogCompress indicates whether to compress the rotated slow log files by gzip.
+	SlowLogCompress bool `toml:"slow-log-compress" json:"slow-log-compress"`
+	// SlowLogAboveSampleRate is the fraction of the queries between slow-threshold and slow-log-above-sample-ceiling
+	// that are logged, 1 logs all of them.
+	SlowLogAboveSampleRate float64 `toml:"slow-log-above-sample-rate" json:"slow-log-above-sample-rate"`
+	// SlowLogAboveSampleCeiling is the query time in milliseconds above which the queries are always logged, 0 means
+	// twice slow-threshold.
+	SlowLogAboveSampleCeiling uint64 `toml:"slow-log-above-sample-ceiling" json:"slow-log-above-sample-ceiling"`
 }
 
 func (l *Log) getDisableTimestamp() bool {
@@ -365,6 +386,15 @@ type Security struct {
 	SpilledFileEncryptionMethod string `toml:"spilled-file-encryption-method" json:"spilled-file-encryption-method"`
 	// EnableSEM prevents SUPER users from having full access.
 	EnableSEM bool `toml:"enable-sem" json:"enable-sem"`
+	// SessionTokenSigningKey is the path of the file containing the key to sign the session tokens, which are
+	// used to migrate sessions between tidb-servers. All the tidb-servers in the cluster should use the same key.
+	SessionTokenSigningKey string `toml:"session-token-signing-key" json:"session-token-signing-key"`
+	// CachingSha2PasswordPrivateKey is the path of the RSA private key in PEM format, which is used to exchange the
+	// password of caching_sha2_password over the insecure connections. A key is generated if it's empty.
+	CachingSha2PasswordPrivateKey string `toml:"caching-sha2-password-private-key" json:"caching-sha2-password-private-key"`
+	// SSLReloadInterval is the interval in seconds to check whether the files of ssl-ca, ssl-cert and ssl-key are
+	// rewritten, they're reloaded for the new connections if so. 0 disables the check.
+	SSLReloadInterval uint `toml:"ssl-reload-interval" json:"ssl-reload-interval"`
 }
 
 // The ErrConfigValidationFailed error is used so that external callers can do a type assertion
@@ -470,14 +500,67 @@ type OpenTracingReporter struct {
 
 // ProxyProtocol is the PROXY protocol section of the config.
 type ProxyProtocol struct {
-	// PROXY protocol acceptable client networks.
+	// PROXY protocol acceptable client networks, the IP addresses or the CIDRs separated by commas.
 	// Empty string means disable PROXY protocol,
 	// * means all networks.
+	// Both v1 and v2 headers are accepted, the header is required for the connections from these networks.
 	Networks string `toml:"networks" json:"networks"`
 	// PROXY protocol header read timeout, Unit is second.
 	HeaderTimeout uint `toml:"header-timeout" json:"header-timeout"`
 }
 
+// XProtocol is the MySQL X Protocol section of the config.
+type XProtocol struct {
+	// Enable indicates whether to listen on the X Protocol port.
+	Enable bool `toml:"enable" json:"enable"`
+	// Port is the X Protocol port, the host is the same as the MySQL protocol.
+	Port uint `toml:"port" json:"port"`
+}
+
+// WorkerPool is the worker pool section of the config.
+type WorkerPool struct {
+	// Enable indicates whether to execute the requests of the connections in the worker pool.
+	Enable bool `toml:"enable" json:"enable"`
+	// Size is the number of the workers, 0 means 4 times of GOMAXPROCS.
+	Size uint `toml:"size" json:"size"`
+	// MaxQueueSize is the max number of the queued requests, the requests are rejected when the queue is full.
+	// 0 means no limit.
+	MaxQueueSize uint `toml:"max-queue-size" json:"max-queue-size"`
+	// MaxQueueTime is the max time in milliseconds a request waits in the queue, the request is rejected after it.
+	// 0 means no limit.
+	MaxQueueTime uint64 `toml:"max-queue-time" json:"max-queue-time"`
+}
+
+// Audit is the audit log section of the config.
+type Audit struct {
+	// Enable indicates whether to write the audit log.
+	Enable bool `toml:"enable" json:"enable"`
+	// EventClasses are the classes of the audited events, which are connection, query, ddl and privilege.
+	EventClasses []string `toml:"event-classes" json:"event-classes"`
+	// Sinks are the names of the sinks which the events are written to. The "file" sink is built in,
+	// the other sinks are registered by plugins.
+	Sinks []string `toml:"sinks" json:"sinks"`
+	// File is the config of the "file" sink, the events are written into the file as JSON lines.
+	File logutil.FileLogConfig `toml:"file" json:"file"`
+	// Filters select the audited events.
+	Filters []AuditFilter `toml:"filters" json:"filters"`
+}
+
+// AuditFilter is a rule to select the audited events. An event matches the rule if it matches all the non-empty
+// fields of the rule.
+type AuditFilter struct {
+	// Classes are the event classes.
+	Classes []string `toml:"classes" json:"classes"`
+	// Users are the patterns of the user names.
+	Users []string `toml:"users" json:"users"`
+	// Databases are the patterns of the database names.
+	Databases []string `toml:"databases" json:"databases"`
+	// Tables are the patterns of the tables in the form of `db.table`.
+	Tables []string `toml:"tables" json:"tables"`
+	// Exclude indicates the matched events are not audited.
+	Exclude bool `toml:"exclude" json:"exclude"`
+}
+
 // Binlog is the config for binlog.
 type Binlog struct {
 	Enable bool `toml:"enable" json:"enable"`
@@ -495,12 +578,15 @@ type Binlog struct {
 type PessimisticTxn struct {
 	// The max count of retry for a single statement in a pessimistic transaction.
 	MaxRetryCount uint `toml:"max-retry-count" json:"max-retry-count"`
+	// The max count of the deadlock events kept in information_schema.deadlocks, 0 disables the deadlock history.
+	DeadlockHistoryCapacity uint `toml:"deadlock-history-capacity" json:"deadlock-history-capacity"`
 }
 
 // DefaultPessimisticTxn returns the default configuration for PessimisticTxn
 func DefaultPessimisticTxn() PessimisticTxn {
 	return PessimisticTxn{
-		MaxRetryCount: 256,
+		MaxRetryCount:           256,
+		DeadlockHistoryCapacity: 10,
 	}
 }
 
@@ -524,6 +610,11 @@ type StmtSummary struct {
 	RefreshInterval int `toml:"refresh-interval" json:"refresh-interval"`
 	// The maximum history size of statement summary.
 	HistorySize int `toml:"history-size" json:"history-size"`
+	// The interval of persisting the history of statement summary to mysql.statements_summary_history, it's
+	// counted in seconds. 0 disables the persistence.
+	PersistInterval int `toml:"persist-interval" json:"persist-interval"`
+	// The maximum days to keep the persisted history of statement summary, 0 means keeping it forever.
+	PersistMaxDays int `toml:"persist-max-days" json:"persist-max-days"`
 }
 
 // IsolationRead is the config for isolation read.
@@ -541,6 +632,15 @@ type Experimental struct {
 	EnableGlobalKill bool `toml:"enable-global-kill" json:"-"`
 }
 
+// DefAuditLogFile is the default file name of the audit log.
+const DefAuditLogFile = "tidb-audit.log"
+
+func newAuditFileLogConfig() logutil.FileLogConfig {
+	cfg := logutil.NewFileLogConfig(logutil.DefaultLogMaxSize)
+	cfg.Filename = DefAuditLogFile
+	return cfg
+}
+
 var defTiKVCfg = tikvcfg.DefaultConfig()
 var defaultConf = Config{
 	Host:                         DefHost,
@@ -572,6 +672,7 @@ var defaultConf = Config{
 	RepairMode:                   false,
 	RepairTableList:              []string{},
 	MaxServerConnections:         0,
+	MaxConnectionsPerIP:          0,
 	TxnLocalLatches:              defTiKVCfg.TxnLocalLatches,
 	LowerCaseTableNames:          2,
 	GracefulWaitBeforeShutdown:   0,
@@ -590,6 +691,9 @@ var defaultConf = Config{
 		QueryLogMaxLen:      logutil.DefaultQueryLogMaxLen,
 		RecordPlanInSlowLog: logutil.DefaultRecordPlanInSlowLog,
 		EnableSlowLog:       logutil.DefaultTiDBEnableSlowLog,
+		SlowLogFormat:       logutil.SlowLogFormatText,
+
+		SlowLogAboveSampleRate: 1,
 	},
 	Status: Status{
 		ReportStatus:    true,
@@ -627,6 +731,22 @@ var defaultConf = Config{
 		Networks:      "",
 		HeaderTimeout: 5,
 	},
+	XProtocol: XProtocol{
+		Enable: false,
+		Port:   33060,
+	},
+	Audit: Audit{
+		Enable:       false,
+		EventClasses: []string{"connection", "query", "ddl", "privilege"},
+		Sinks:        []string{"file"},
+		File:         newAuditFileLogConfig(),
+	},
+	WorkerPool: WorkerPool{
+		Enable:       false,
+		Size:         0,
+		MaxQueueSize: 1024,
+		MaxQueueTime: 5000,
+	},
 	PreparedPlanCache: PreparedPlanCache{
 		Enabled:          false,
 		Capacity:         100,
@@ -654,6 +774,8 @@ var defaultConf = Config{
 		MaxSQLLength:        4096,
 		RefreshInterval:     1800,
 		HistorySize:         24,
+		PersistInterval:     300,
+		PersistMaxDays:      7,
 	},
 	IsolationRead: IsolationRead{
 		Engines: []string{"tikv", "tiflash", "tidb"},
@@ -847,6 +969,9 @@ func (c *Config) Valid() error {
 	if c.Log.File.MaxSize > MaxLogFileSize {
 		return fmt.Errorf("invalid max log file size=%v which is larger than max=%v", c.Log.File.MaxSize, MaxLogFileSize)
 	}
+	if c.Audit.File.MaxSize > MaxLogFileSize {
+		return fmt.Errorf("invalid max audit log file size=%v which is larger than max=%v", c.Audit.File.MaxSize, MaxLogFileSize)
+	}
 	c.OOMAction = strings.ToLower(c.OOMAction)
 	if c.OOMAction != OOMActionLog && c.OOMAction != OOMActionCancel {
 		return fmt.Errorf("unsupported OOMAction %v, TiDB only supports [%v, %v]", c.OOMAction, OOMActionLog, OOMActionCancel)
@@ -874,6 +999,16 @@ func (c *Config) Valid() error {
 		return fmt.Errorf("txn-total-size-limit should be less than %d", 10<<30)
 	}
 
+	if c.Log.SlowLogSampleRate > 1 || c.Log.SlowLogSampleRate < 0 {
+		return fmt.Errorf("slow-log-sample-rate in [Log] must be greater than or equal to 0 and less than or equal to 1")
+	}
+	if c.Log.SlowLogAboveSampleRate > 1 || c.Log.SlowLogAboveSampleRate < 0 {
+		return fmt.Errorf("slow-log-above-sample-rate in [Log] must be greater than or equal to 0 and less than or equal to 1")
+	}
+	if c.Log.SlowLogFormat != logutil.SlowLogFormatText && c.Log.SlowLogFormat != logutil.SlowLogFormatJSON {
+		return fmt.Errorf("slow-log-format in [Log] must be one of %s or %s", logutil.SlowLogFormatText, logutil.SlowLogFormatJSON)
+	}
+
 	if c.Performance.MemoryUsageAlarmRatio > 1 || c.Performance.MemoryUsageAlarmRatio < 0 {
 		return fmt.Errorf("memory-usage-alarm-ratio in [Performance] must be greater than or equal to 0 and less than or equal to 1")
 	}
@@ -887,6 +1022,16 @@ func (c *Config) Valid() error {
 	if c.StmtSummary.RefreshInterval <= 0 {
 		return fmt.Errorf("refresh-interval in [stmt-summary] should be greater than 0")
 	}
+	if c.StmtSummary.PersistInterval < 0 {
+		return fmt.Errorf("persist-interval in [stmt-summary] should be greater than or equal to 0")
+	}
+	if c.StmtSummary.PersistMaxDays < 0 {
+		return fmt.Errorf("persist-max-days in [stmt-summary] should be greater than or equal to 0")
+	}
+
+	if c.PessimisticTxn.DeadlockHistoryCapacity > 10000 {
+		return fmt.Errorf("deadlock-history-capacity in [pessimistic-txn] should be less than or equal to 10000")
+	}
 
 	if c.PreparedPlanCache.Capacity < 1 {
 		return fmt.Errorf("capacity in [prepared-plan-cache] should be at least 1")
@@ -949,7 +1094,17 @@ var TableLockDelayClean = func() uint64 {
 
 // ToLogConfig converts *Log to *logutil.LogConfig.
 func (l *Log) ToLogConfig() *logutil.LogConfig {
-	return logutil.NewLogConfig(l.Level, l.Format, l.SlowQueryFile, l.File, l.getDisableTimestamp(), func(config *zaplog.Config) { config.DisableErrorVerbose = l.getDisableErrorStack() })
+	c := logutil.NewLogConfig(l.Level, l.Format, l.SlowQueryFile, l.File, l.getDisableTimestamp(), func(config *zaplog.Config) { config.DisableErrorVerbose = l.getDisableErrorStack() })
+	if l.SlowLogFormat != "" {
+		c.SlowQueryFormat = l.SlowLogFormat
+	}
+	c.SlowQueryRotation = logutil.SlowLogRotation{
+		MaxSize:    int(l.SlowLogMaxSize),
+		MaxDays:    int(l.SlowLogMaxDays),
+		MaxBackups: int(l.SlowLogMaxBackups),
+		Compress:   l.SlowLogCompress,
+	}
+	return c
 }
 
 // ToTracingConfig converts *OpenTracing to *tracing.Configuration.
@@ -48,12 +48,18 @@ const (
 	typeAddIndexWorker     backfillWorkerType = 0
 	typeUpdateColumnWorker backfillWorkerType = 1
 	typeCleanUpIndexWorker backfillWorkerType = 2
+	// typeReorgPartitionWorker backfills the rows into the reorganized partitions.
+	typeReorgPartitionWorker backfillWorkerType = 3
+	// typeConvertCharsetWorker checks the rows can be converted to the target charset.
+	typeConvertCharsetWorker backfillWorkerType = 4
 )
 
 // By now the DDL jobs that need backfilling include:
 // 1: add-index
 // 2: modify-column-type
 // 3: clean-up global index
+// 4: reorganize partition
+// 5: convert table charset
 //
 // They all have a write reorganization state to back fill data into the rows existed.
 // Backfilling is time consuming, to accelerate this process, TiDB has built some sub
@@ -110,6 +116,10 @@ func (bWT backfillWorkerType) String() string {
 		return "update column"
 	case typeCleanUpIndexWorker:
 		return "clean up index"
+	case typeReorgPartitionWorker:
+		return "reorganize partition"
+	case typeConvertCharsetWorker:
+		return "convert charset"
 	default:
 		return "unknown"
 	}
@@ -378,7 +388,7 @@ func (w *worker) waitTaskResults(workers []*backfillWorker, taskCnt int,
 
 // handleReorgTasks sends tasks to workers, and waits for all the running workers to return results,
 // there are taskCnt running workers.
-func (w *worker) handleReorgTasks(reorgInfo *reorgInfo, totalAddedCount *int64, workers []*backfillWorker, batchTasks []*reorgBackfillTask) error {
+func (w *worker) handleReorgTasks(reorgInfo *reorgInfo, totalAddedCount *int64, workers []*backfillWorker, batchTasks []*reorgBackfillTask, ingester *indexIngester) error {
 	for i, task := range batchTasks {
 		workers[i].taskCh <- task
 	}
@@ -387,6 +397,20 @@ func (w *worker) handleReorgTasks(reorgInfo *reorgInfo, totalAddedCount *int64,
 	taskCnt := len(batchTasks)
 	startTime := time.Now()
 	nextKey, taskAddedCount, err := w.waitTaskResults(workers, taskCnt, totalAddedCount, startKey)
+	if ingester != nil {
+		// The entries in the local engine are lost if the DDL owner changes, so the reorg handle
+		// can only be moved forward after the entries before it are written into the storage.
+		written, err1 := ingester.ingest()
+		*totalAddedCount += int64(written)
+		taskAddedCount += int64(written)
+		w.reorgCtx.increaseRowCount(int64(written))
+		if err1 != nil {
+			if err == nil {
+				err = err1
+			}
+			nextKey = startKey
+		}
+	}
 	elapsedTime := time.Since(startTime)
 	if err == nil {
 		err = w.isReorgRunnable(reorgInfo.d)
@@ -445,7 +469,7 @@ func tryDecodeToHandleString(key kv.Key) string {
 
 // sendRangeTaskToWorkers sends tasks to workers, and returns remaining kvRanges that is not handled.
 func (w *worker) sendRangeTaskToWorkers(workers []*backfillWorker, reorgInfo *reorgInfo,
-	totalAddedCount *int64, kvRanges []kv.KeyRange) ([]kv.KeyRange, error) {
+	totalAddedCount *int64, kvRanges []kv.KeyRange, ingester *indexIngester) ([]kv.KeyRange, error) {
 	batchTasks := make([]*reorgBackfillTask, 0, len(workers))
 	physicalTableID := reorgInfo.PhysicalTableID
 
@@ -467,7 +491,7 @@ func (w *worker) sendRangeTaskToWorkers(workers []*backfillWorker, reorgInfo *re
 	}
 
 	// Wait tasks finish.
-	err := w.handleReorgTasks(reorgInfo, totalAddedCount, workers, batchTasks)
+	err := w.handleReorgTasks(reorgInfo, totalAddedCount, workers, batchTasks, ingester)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -563,6 +587,28 @@ func (w *worker) writePhysicalTableRecord(t table.PhysicalTable, bfWorkerType ba
 		closeBackfillWorkers(backfillWorkers)
 	}()
 
+	var ingester *indexIngester
+	if bfWorkerType == typeAddIndexWorker && canIngestIndex(indexInfo) {
+		if err := loadDDLReorgVars(w); err != nil {
+			logutil.BgLogger().Error("[ddl] load DDL reorganization variable failed", zap.Error(err))
+		}
+		if variable.GetDDLEnableFastReorg() {
+			ingester, err = newIndexIngester(reorgInfo.d.store, t, indexInfo, job)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			defer ingester.close()
+		}
+	}
+
+	var reorgTbl table.PartitionedTable
+	if bfWorkerType == typeReorgPartitionWorker {
+		reorgTbl, err = getReorganizedTable(reorgInfo.d, job.SchemaID, t.Meta())
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	for {
 		kvRanges, err := splitTableRanges(t, reorgInfo.d.store, startKey, endKey)
 		if err != nil {
@@ -599,6 +645,9 @@ func (w *worker) writePhysicalTableRecord(t table.PhysicalTable, bfWorkerType ba
 			case typeAddIndexWorker:
 				idxWorker := newAddIndexWorker(sessCtx, w, i, t, indexInfo, decodeColMap, reorgInfo.ReorgMeta.SQLMode)
 				idxWorker.priority = job.Priority
+				if ingester != nil {
+					idxWorker.engine = ingester.engine
+				}
 				backfillWorkers = append(backfillWorkers, idxWorker.backfillWorker)
 				go idxWorker.backfillWorker.run(reorgInfo.d, idxWorker)
 			case typeUpdateColumnWorker:
@@ -611,6 +660,16 @@ func (w *worker) writePhysicalTableRecord(t table.PhysicalTable, bfWorkerType ba
 				idxWorker.priority = job.Priority
 				backfillWorkers = append(backfillWorkers, idxWorker.backfillWorker)
 				go idxWorker.backfillWorker.run(reorgInfo.d, idxWorker)
+			case typeReorgPartitionWorker:
+				partWorker := newReorgPartitionWorker(sessCtx, w, i, t, reorgTbl, decodeColMap)
+				partWorker.priority = job.Priority
+				backfillWorkers = append(backfillWorkers, partWorker.backfillWorker)
+				go partWorker.backfillWorker.run(reorgInfo.d, partWorker)
+			case typeConvertCharsetWorker:
+				convertWorker := newConvertCharsetWorker(sessCtx, w, i, t, reorgInfo.charsetConverters)
+				convertWorker.priority = job.Priority
+				backfillWorkers = append(backfillWorkers, convertWorker.backfillWorker)
+				go convertWorker.backfillWorker.run(reorgInfo.d, convertWorker)
 			default:
 				return errors.New("unknow backfill type")
 			}
@@ -643,7 +702,7 @@ func (w *worker) writePhysicalTableRecord(t table.PhysicalTable, bfWorkerType ba
 			zap.Int("regionCnt", len(kvRanges)),
 			zap.String("startHandle", tryDecodeToHandleString(startKey)),
 			zap.String("endHandle", tryDecodeToHandleString(endKey)))
-		remains, err := w.sendRangeTaskToWorkers(backfillWorkers, reorgInfo, &totalAddedCount, kvRanges)
+		remains, err := w.sendRangeTaskToWorkers(backfillWorkers, reorgInfo, &totalAddedCount, kvRanges, ingester)
 		if err != nil {
 			return errors.Trace(err)
 		}
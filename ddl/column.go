@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/charset"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
@@ -711,6 +712,9 @@ func needChangeColumnData(oldCol, newCol *model.ColumnInfo) bool {
 			return toUnsigned != originUnsigned
 		}
 
+		if types.IsString(oldCol.Tp) && needChangeCharsetData(&oldCol.FieldType, &newCol.FieldType) {
+			return true
+		}
 		return needTruncationOrToggleSign()
 	}
 
@@ -719,7 +723,8 @@ func needChangeColumnData(oldCol, newCol *model.ColumnInfo) bool {
 	case mysql.TypeVarchar, mysql.TypeString, mysql.TypeVarString, mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
 		switch newCol.Tp {
 		case mysql.TypeVarchar, mysql.TypeString, mysql.TypeVarString, mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
-			return needTruncationOrToggleSign()
+			return needTruncationOrToggleSign() || types.IsTypeVarchar(oldCol.Tp) != types.IsTypeVarchar(newCol.Tp) ||
+				needChangeCharsetData(&oldCol.FieldType, &newCol.FieldType)
 		}
 	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
 		switch newCol.Tp {
@@ -736,6 +741,15 @@ func needChangeColumnData(oldCol, newCol *model.ColumnInfo) bool {
 	return true
 }
 
+// needChangeCharsetData returns whether the data of the string column need to be converted when the charset is changed.
+// The data of utf8 is valid utf8mb4, so it's changed without reorg.
+func needChangeCharsetData(origin, to *types.FieldType) bool {
+	if !types.IsString(origin.Tp) || !types.IsString(to.Tp) || origin.Charset == to.Charset {
+		return false
+	}
+	return !(origin.Charset == charset.CharsetUTF8 && to.Charset == charset.CharsetUTF8MB4)
+}
+
 func isElemsChangedToModifyColumn(oldElems, newElems []string) bool {
 	if len(newElems) < len(oldElems) {
 		return true
@@ -894,6 +908,13 @@ func rollbackModifyColumnJobWithData(t *meta.Meta, tblInfo *model.TableInfo, job
 		// be removed from the tableInfo as well.
 		tblInfo.Columns = tblInfo.Columns[:len(tblInfo.Columns)-1]
 		tblInfo.Indices = tblInfo.Indices[:len(tblInfo.Indices)-len(jobParam.changingIdxs)]
+		// The job may be cancelled after the reorg info is initialized but before the backfill workers are started,
+		// so the reorg handle of the changing elements should be cleaned up here.
+		if job.SnapshotVer != 0 {
+			if err = t.RemoveDDLReorgHandle(job, BuildElements(jobParam.changingCol, jobParam.changingIdxs)); err != nil {
+				return ver, errors.Trace(err)
+			}
+		}
 	}
 	ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, true)
 	if err != nil {
@@ -1019,6 +1040,8 @@ func (w *worker) doModifyColumnTypeWithData(
 				// If timeout, we should return, check for the owner and re-wait job done.
 				return ver, nil
 			}
+			// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+			w.reorgCtx.cleanNotifyReorgCancel()
 			if needRollbackData(err) {
 				if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
 					logutil.BgLogger().Warn("[ddl] run modify column job failed, RemoveDDLReorgHandle failed, can't convert job to rollback",
@@ -1030,8 +1053,6 @@ func (w *worker) doModifyColumnTypeWithData(
 				job.State = model.JobStateRollingback
 				return ver, errors.Trace(err)
 			}
-			// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
-			w.reorgCtx.cleanNotifyReorgCancel()
 			return ver, errors.Trace(err)
 		}
 		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
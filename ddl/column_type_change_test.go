@@ -317,10 +317,14 @@ func (s *testColumnTypeChangeSuite) TestColumnTypeChangeFromIntegerToOthers(c *C
 
 	// integer to string
 	prepare(tk)
-	tk.MustGetErrCode("alter table t modify a varchar(10)", mysql.ErrUnsupportedDDLOperation)
+	tk.MustExec("alter table t modify a varchar(10)")
+	modifiedColumn := getModifyColumn(c, tk.Se, "test", "t", "a", false)
+	c.Assert(modifiedColumn, NotNil)
+	c.Assert(modifiedColumn.Tp, Equals, parser_mysql.TypeVarchar)
+	tk.MustQuery("select a from t").Check(testkit.Rows("1"))
 
 	tk.MustExec("alter table t modify b char(10)")
-	modifiedColumn := getModifyColumn(c, tk.Se, "test", "t", "b", false)
+	modifiedColumn = getModifyColumn(c, tk.Se, "test", "t", "b", false)
 	c.Assert(modifiedColumn, NotNil)
 	c.Assert(modifiedColumn.Tp, Equals, parser_mysql.TypeString)
 	tk.MustQuery("select b from t").Check(testkit.Rows("11"))
@@ -331,7 +335,7 @@ func (s *testColumnTypeChangeSuite) TestColumnTypeChangeFromIntegerToOthers(c *C
 	c.Assert(modifiedColumn.Tp, Equals, parser_mysql.TypeString)
 	tk.MustQuery("select c from t").Check(testkit.Rows("111\x00\x00\x00\x00\x00\x00\x00"))
 
-	tk.MustGetErrCode("alter table t modify d varbinary(10)", mysql.ErrUnsupportedDDLOperation)
+	tk.MustExec("alter table t modify d varbinary(10)")
 
 	tk.MustExec("alter table t modify e blob(10)")
 	modifiedColumn = getModifyColumn(c, tk.Se, "test", "t", "e", false)
@@ -757,15 +761,15 @@ func (s *testColumnTypeChangeSuite) TestColumnTypeChangeFromNumericToOthers(c *C
 	// varchar
 	reset(tk)
 	tk.MustExec("insert into t values (-258.12345, 333.33, 2000000.20000002, 323232323.3232323232, -111.11111111, -222222222222.222222222222222, b'10101')")
-	tk.MustGetErrCode("alter table t modify d varchar(30)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify n varchar(30)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify r varchar(30)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify db varchar(30)", mysql.ErrUnsupportedDDLOperation)
+	tk.MustExec("alter table t modify d varchar(30)")
+	tk.MustExec("alter table t modify n varchar(30)")
+	tk.MustExec("alter table t modify r varchar(30)")
+	tk.MustExec("alter table t modify db varchar(30)")
 	// MySQL will get "-111.111" rather than "-111.111115" at TiDB.
-	tk.MustGetErrCode("alter table t modify f32 varchar(30)", mysql.ErrUnsupportedDDLOperation)
+	tk.MustExec("alter table t modify f32 varchar(30)")
 	// MySQL will get "ERROR 1406 (22001): Data truncation: Data too long for column 'f64' at row 1".
-	tk.MustGetErrCode("alter table t modify f64 varchar(30)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify b varchar(30)", mysql.ErrUnsupportedDDLOperation)
+	tk.MustExec("alter table t modify f64 varchar(30)")
+	tk.MustExec("alter table t modify b varchar(30)")
 	tk.MustQuery("select * from t").Check(testkit.Rows("-258.1234500 333.33 2000000.20000002 323232323.32323235 -111.111115 -222222222222.22223 \x15"))
 
 	// binary
@@ -784,15 +788,15 @@ func (s *testColumnTypeChangeSuite) TestColumnTypeChangeFromNumericToOthers(c *C
 	// varbinary
 	reset(tk)
 	tk.MustExec("insert into t values (-258.12345, 333.33, 2000000.20000002, 323232323.3232323232, -111.11111111, -222222222222.222222222222222, b'10101')")
-	tk.MustGetErrCode("alter table t modify d varbinary(30)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify n varbinary(30)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify r varbinary(30)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify db varbinary(30)", mysql.ErrUnsupportedDDLOperation)
+	tk.MustExec("alter table t modify d varbinary(30)")
+	tk.MustExec("alter table t modify n varbinary(30)")
+	tk.MustExec("alter table t modify r varbinary(30)")
+	tk.MustExec("alter table t modify db varbinary(30)")
 	// MySQL will get "-111.111" rather than "-111.111115" at TiDB.
-	tk.MustGetErrCode("alter table t modify f32 varbinary(30)", mysql.ErrUnsupportedDDLOperation)
+	tk.MustExec("alter table t modify f32 varbinary(30)")
 	// MySQL will get "ERROR 1406 (22001): Data truncation: Data too long for column 'f64' at row 1".
-	tk.MustGetErrCode("alter table t modify f64 varbinary(30)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify b varbinary(30)", mysql.ErrUnsupportedDDLOperation)
+	tk.MustExec("alter table t modify f64 varbinary(30)")
+	tk.MustExec("alter table t modify b varbinary(30)")
 	tk.MustQuery("select * from t").Check(testkit.Rows("-258.1234500 333.33 2000000.20000002 323232323.32323235 -111.111115 -222222222222.22223 \x15"))
 
 	// blob
@@ -1070,11 +1074,11 @@ func (s *testColumnTypeChangeSuite) TestColumnTypeChangeFromDateTimeTypeToOthers
 	// varchar
 	reset(tk)
 	tk.MustExec("insert into t values ('2020-10-30', '19:38:25.001', 20201030082133.455555, 20201030082133.455555, 2020)")
-	tk.MustGetErrCode("alter table t modify d varchar(30)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify t varchar(30)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify dt varchar(30)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify tmp varchar(30)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify y varchar(30)", mysql.ErrUnsupportedDDLOperation)
+	tk.MustExec("alter table t modify d varchar(30)")
+	tk.MustExec("alter table t modify t varchar(30)")
+	tk.MustExec("alter table t modify dt varchar(30)")
+	tk.MustExec("alter table t modify tmp varchar(30)")
+	tk.MustExec("alter table t modify y varchar(30)")
 	tk.MustQuery("select * from t").Check(testkit.Rows("2020-10-30 19:38:25.001 2020-10-30 08:21:33.455555 2020-10-30 08:21:33.455555 2020"))
 
 	// binary
@@ -1094,11 +1098,11 @@ func (s *testColumnTypeChangeSuite) TestColumnTypeChangeFromDateTimeTypeToOthers
 	// varbinary
 	reset(tk)
 	tk.MustExec("insert into t values ('2020-10-30', '19:38:25.001', 20201030082133.455555, 20201030082133.455555, 2020)")
-	tk.MustGetErrCode("alter table t modify d varbinary(30)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify t varbinary(30)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify dt varbinary(30)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify tmp varbinary(30)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify y varbinary(30)", mysql.ErrUnsupportedDDLOperation)
+	tk.MustExec("alter table t modify d varbinary(30)")
+	tk.MustExec("alter table t modify t varbinary(30)")
+	tk.MustExec("alter table t modify dt varbinary(30)")
+	tk.MustExec("alter table t modify tmp varbinary(30)")
+	tk.MustExec("alter table t modify y varbinary(30)")
 	tk.MustQuery("select * from t").Check(testkit.Rows("2020-10-30 19:38:25.001 2020-10-30 08:21:33.455555 2020-10-30 08:21:33.455555 2020"))
 
 	// text
@@ -1336,15 +1340,15 @@ func (s *testColumnTypeChangeSuite) TestColumnTypeChangeFromJsonToOthers(c *C) {
 	// varchar
 	reset(tk)
 	tk.MustExec("insert into t values ('{\"obj\": 100}', '[-1, 0, 1]', 'null', 'true', 'false', '-22', '22', '323232323.3232323232', '\"json string\"')")
-	tk.MustGetErrCode("alter table t modify obj varchar(20)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify arr varchar(20)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify nil varchar(20)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify t varchar(20)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify f varchar(20)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify i varchar(20)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify ui varchar(20)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify f64 varchar(20)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify str varchar(20)", mysql.ErrUnsupportedDDLOperation)
+	tk.MustExec("alter table t modify obj varchar(20)")
+	tk.MustExec("alter table t modify arr varchar(20)")
+	tk.MustExec("alter table t modify nil varchar(20)")
+	tk.MustExec("alter table t modify t varchar(20)")
+	tk.MustExec("alter table t modify f varchar(20)")
+	tk.MustExec("alter table t modify i varchar(20)")
+	tk.MustExec("alter table t modify ui varchar(20)")
+	tk.MustExec("alter table t modify f64 varchar(20)")
+	tk.MustExec("alter table t modify str varchar(20)")
 	tk.MustQuery("select * from t").Check(testkit.Rows("{\"obj\": 100} [-1, 0, 1] null true false -22 22 323232323.32323235 \"json string\""))
 
 	// binary
@@ -1372,15 +1376,15 @@ func (s *testColumnTypeChangeSuite) TestColumnTypeChangeFromJsonToOthers(c *C) {
 	// varbinary
 	reset(tk)
 	tk.MustExec("insert into t values ('{\"obj\": 100}', '[-1, 0, 1]', 'null', 'true', 'false', '-22', '22', '323232323.3232323232', '\"json string\"')")
-	tk.MustGetErrCode("alter table t modify obj varbinary(20)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify arr varbinary(20)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify nil varbinary(20)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify t varbinary(20)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify f varbinary(20)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify i varbinary(20)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify ui varbinary(20)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify f64 varbinary(20)", mysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t modify str varbinary(20)", mysql.ErrUnsupportedDDLOperation)
+	tk.MustExec("alter table t modify obj varbinary(20)")
+	tk.MustExec("alter table t modify arr varbinary(20)")
+	tk.MustExec("alter table t modify nil varbinary(20)")
+	tk.MustExec("alter table t modify t varbinary(20)")
+	tk.MustExec("alter table t modify f varbinary(20)")
+	tk.MustExec("alter table t modify i varbinary(20)")
+	tk.MustExec("alter table t modify ui varbinary(20)")
+	tk.MustExec("alter table t modify f64 varbinary(20)")
+	tk.MustExec("alter table t modify str varbinary(20)")
 	tk.MustQuery("select * from t").Check(testkit.Rows("{\"obj\": 100} [-1, 0, 1] null true false -22 22 323232323.32323235 \"json string\""))
 
 	// blob
@@ -1752,3 +1756,103 @@ func (s *testColumnTypeChangeSuite) TestDDLExitWhenCancelMeetPanic(c *C) {
 	c.Assert(job.ErrorCount, Equals, int64(4))
 	c.Assert(job.Error.Error(), Equals, "[ddl:-1]panic in handling DDL logic and error count beyond the limitation 3, cancelled")
 }
+
+func (s *testColumnTypeChangeSuite) TestColumnTypeChangeBetweenVarcharAndCharset(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	// Enable column change variable.
+	tk.Se.GetSessionVars().EnableChangeColumnType = true
+	defer func() {
+		tk.Se.GetSessionVars().EnableChangeColumnType = false
+	}()
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int primary key, a int, b varchar(10) charset utf8mb4, c char(5), d varchar(10), index idx_a(a))")
+	tk.MustExec("insert into t values (1, 1, 'abc', 'x', 'hello'), (2, 22, '测试', 'y', 'hi  ')")
+
+	// The column type change between varchar and non-varchar is done by reorg.
+	tk.MustExec("alter table t modify column a varchar(5)")
+	tk.MustQuery("select a from t use index(idx_a) where a = '22'").Check(testkit.Rows("22"))
+	tk.MustExec("alter table t modify column c varchar(5)")
+	tk.MustExec("alter table t modify column d char(10)")
+	tk.MustQuery("select concat(d, '|') from t").Check(testkit.Rows("hello|", "hi|"))
+	rows := tk.MustQuery("admin show ddl jobs 1").Rows()
+	c.Assert(rows[0][3], Equals, "modify column")
+	c.Assert(rows[0][7], Equals, "2")
+	tk.MustGetErrCode("alter table t modify column d char(3)", mysql.ErrDataTooLong)
+	tk.MustExec("admin check table t")
+
+	// The charset change is done by reorg.
+	tk.MustGetErrCode("alter table t modify column b varchar(10) charset ascii", mysql.ErrTruncatedWrongValueForField)
+	tk.MustExec("update t set b = 'ab' where id = 2")
+	tk.MustExec("alter table t modify column b varchar(10) charset ascii")
+	tk.MustQuery("select character_set_name from information_schema.columns where table_schema = 'test' and table_name = 't' and column_name = 'b'").
+		Check(testkit.Rows("ascii"))
+	tk.MustQuery("select b from t").Check(testkit.Rows("abc", "ab"))
+	tk.MustExec("admin check table t")
+}
+
+func (s *testColumnTypeChangeSuite) TestColumnTypeChangeBetweenVarcharAndCharsetWithDML(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	// Enable column change variable.
+	tk.Se.GetSessionVars().EnableChangeColumnType = true
+	defer func() {
+		tk.Se.GetSessionVars().EnableChangeColumnType = false
+	}()
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int primary key, a char(10), b varchar(10) charset utf8mb4, index idx_a(a))")
+	tk.MustExec("insert into t values (1, 'a', 'x'), (2, 'b', 'y')")
+
+	// use new session to run DML in callback function.
+	internalTK := testkit.NewTestKit(c, s.store)
+	internalTK.MustExec("use test")
+
+	originalHook := s.dom.DDL().GetHook()
+	defer s.dom.DDL().(ddl.DDLForTest).SetHook(originalHook)
+
+	tbl := testGetTableByName(c, tk.Se, "test", "t")
+	hook := &ddl.TestDDLCallback{}
+	var checkErr error
+	var dmls []string
+	dmlDone := false
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if checkErr != nil || dmlDone || tbl.Meta().ID != job.TableID {
+			return
+		}
+		// The DML in the write reorganization state writes both the old and the changing column.
+		if job.SchemaState == model.StateWriteReorganization {
+			dmlDone = true
+			for _, sql := range dmls {
+				if _, checkErr = internalTK.Exec(sql); checkErr != nil {
+					return
+				}
+			}
+		}
+	}
+	s.dom.DDL().(ddl.DDLForTest).SetHook(hook)
+
+	dmls = []string{
+		"insert into t values (3, 'c', 'z')",
+		"update t set a = 'bb', b = 'yy' where id = 2",
+		"delete from t where id = 1",
+	}
+	tk.MustExec("alter table t modify column a varchar(10)")
+	c.Assert(checkErr, IsNil)
+	c.Assert(dmlDone, IsTrue)
+	tk.MustQuery("select * from t").Check(testkit.Rows("2 bb yy", "3 c z"))
+	tk.MustQuery("select a from t use index(idx_a) where a = 'bb'").Check(testkit.Rows("bb"))
+	tk.MustExec("admin check table t")
+
+	dmls = []string{
+		"insert into t values (4, 'd', 'w')",
+		"update t set b = 'zz' where id = 3",
+	}
+	dmlDone = false
+	tk.MustExec("alter table t modify column b varchar(10) charset ascii")
+	c.Assert(checkErr, IsNil)
+	c.Assert(dmlDone, IsTrue)
+	tk.MustQuery("select * from t").Check(testkit.Rows("2 bb yy", "3 c zz", "4 d w"))
+	tk.MustExec("admin check table t")
+}
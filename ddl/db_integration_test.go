@@ -596,7 +596,7 @@ func (s *testIntegrationSuite5) TestErrnoErrorCode(c *C) {
 	tk.MustExec("alter table test_drop_columns add column c2 int first, add column c3 int after c1")
 	sql = "alter table test_drop_columns drop column c1, drop column c2, drop column c3;"
 	tk.MustGetErrCode(sql, errno.ErrCantRemoveAllFields)
-	sql = "alter table test_drop_columns drop column c1, add column c2 int;"
+	sql = "alter table test_drop_columns drop column c1, add index idx_c1(c1);"
 	tk.MustGetErrCode(sql, errno.ErrUnsupportedDDLOperation)
 	sql = "alter table test_drop_columns drop column c1, drop column c1;"
 	tk.MustGetErrCode(sql, errno.ErrCantDropFieldOrKey)
@@ -830,10 +830,11 @@ func (s *testIntegrationSuite4) TestChangingTableCharset(c *C) {
 	}
 	checkCharset(charset.CharsetUTF8MB4, charset.CollationUTF8MB4)
 
-	// Test when column charset can not convert to the target charset.
+	// Test when column charset is converted to the target charset.
 	tk.MustExec("drop table t;")
 	tk.MustExec("create table t(a varchar(10) character set ascii) charset utf8mb4")
-	tk.MustGetErrCode("alter table t convert to charset utf8mb4;", errno.ErrUnsupportedDDLOperation)
+	tk.MustExec("alter table t convert to charset utf8mb4;")
+	checkCharset(charset.CharsetUTF8MB4, charset.CollationUTF8MB4)
 
 	tk.MustExec("drop table t;")
 	tk.MustExec("create table t(a varchar(10) character set utf8) charset utf8")
@@ -930,6 +931,55 @@ func (s *testIntegrationSuite4) TestChangingTableCharset(c *C) {
 	}
 }
 
+func (s *testIntegrationSuite4) TestConvertTableCharset(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	checkCharset := func(chs, coll string) {
+		tbl := testGetTableByName(c, s.ctx, "test", "t")
+		c.Assert(tbl.Meta().Charset, Equals, chs)
+		c.Assert(tbl.Meta().Collate, Equals, coll)
+		for _, col := range tbl.Meta().Columns {
+			if col.Tp == mysql.TypeLong {
+				continue
+			}
+			c.Assert(col.Charset, Equals, chs)
+			c.Assert(col.Collate, Equals, coll)
+		}
+	}
+
+	// The rows which can't be converted roll back the job.
+	tk.MustExec("create table t(a varchar(10), b int, c varchar(10), index idx(c)) charset utf8mb4")
+	tk.MustExec("insert into t values ('a', 1, 'x'), ('b', 2, 'y'), ('😀', 3, 'z')")
+	tk.MustGetErrCode("alter table t convert to charset utf8", errno.ErrInvalidCharacterString)
+	checkCharset(charset.CharsetUTF8MB4, charset.CollationUTF8MB4)
+	tk.MustExec("insert into t values ('🍣', 4, 'w')")
+	tk.MustExec("delete from t where b in (3, 4)")
+	tk.MustExec("alter table t convert to charset utf8")
+	checkCharset(charset.CharsetUTF8, charset.CollationUTF8)
+	tk.MustQuery("select a, b, c from t use index(idx) order by c").Check(testkit.Rows("a 1 x", "b 2 y"))
+	tk.MustGetErrCode("insert into t values ('😀', 3, 'z')", errno.ErrTruncatedWrongValueForField)
+
+	// The default values are checked before the job is submitted.
+	tk.MustExec("drop table t")
+	tk.MustExec("create table t(a varchar(10) default 'é') charset latin1")
+	tk.MustGetErrCode("alter table t convert to charset ascii", errno.ErrInvalidCharacterString)
+	checkCharset(charset.CharsetLatin1, charset.CollationLatin1)
+	tk.MustExec("alter table t convert to charset utf8")
+	checkCharset(charset.CharsetUTF8, charset.CollationUTF8)
+
+	// The partitions are checked one by one.
+	tk.MustExec("drop table t")
+	tk.MustExec("create table t(a varchar(10), b int) charset utf8mb4 partition by range (b) (partition p0 values less than (10), partition p1 values less than (20))")
+	tk.MustExec("insert into t values ('a', 1), ('😀', 11)")
+	tk.MustGetErrCode("alter table t convert to charset utf8", errno.ErrInvalidCharacterString)
+	checkCharset(charset.CharsetUTF8MB4, charset.CollationUTF8MB4)
+	tk.MustExec("update t set a = 'b' where b = 11")
+	tk.MustExec("alter table t convert to charset utf8")
+	checkCharset(charset.CharsetUTF8, charset.CollationUTF8)
+	tk.MustQuery("select a, b from t order by b").Check(testkit.Rows("a 1", "b 11"))
+}
+
 func (s *testIntegrationSuite5) TestModifyColumnOption(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("create database if not exists test")
@@ -1827,20 +1877,64 @@ func (s *testIntegrationSuite3) TestAlterTableAddUniqueOnPartionRangeColumn(c *C
 	tk.MustGetErrCode("alter table t add unique index idx_b(b)", errno.ErrUniqueKeyNeedAllFieldsInPf)
 }
 
-func (s *testIntegrationSuite5) TestFulltextIndexIgnore(c *C) {
+func (s *testIntegrationSuite5) TestFulltextIndexType(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
 	tk.MustExec("drop table if exists t_ft")
 	defer tk.MustExec("drop table if exists t_ft")
-	// Make sure that creating and altering to add a fulltext key gives the correct warning
-	s.assertWarningExec(tk, c, "create table t_ft (a text, fulltext key (a))", ddl.ErrTableCantHandleFt)
-	s.assertWarningExec(tk, c, "alter table t_ft add fulltext key (a)", ddl.ErrTableCantHandleFt)
+	// Make sure that the fulltext keys created and added are reported as FULLTEXT indexes.
+	tk.MustExec("create table t_ft (a text, b varchar(100), fulltext key ft_a (a))")
+	tk.MustExec("alter table t_ft add fulltext key ft_ab (a, b)")
+	tk.MustExec("create fulltext index ft_b on t_ft (b)")
+
+	tk.MustQuery("show index from t_ft").Check(testkit.Rows(
+		"t_ft 1 ft_a 1 a A 0 <nil> <nil> YES FULLTEXT   YES NULL NO",
+		"t_ft 1 ft_ab 1 a A 0 <nil> <nil> YES FULLTEXT   YES NULL NO",
+		"t_ft 1 ft_ab 2 b A 0 <nil> <nil> YES FULLTEXT   YES NULL NO",
+		"t_ft 1 ft_b 1 b A 0 <nil> <nil> YES FULLTEXT   YES NULL NO"))
+	tk.MustQuery("select index_name, seq_in_index, column_name, index_type from information_schema.statistics " +
+		"where table_schema='test' and table_name='t_ft' order by index_name, seq_in_index").Check(testkit.Rows(
+		"ft_a 1 a FULLTEXT",
+		"ft_ab 1 a FULLTEXT",
+		"ft_ab 2 b FULLTEXT",
+		"ft_b 1 b FULLTEXT"))
+}
+
+func (s *testIntegrationSuite5) TestFulltextIndex(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t_ft")
+	defer tk.MustExec("drop table if exists t_ft")
+	tk.MustExec("create table t_ft (id int primary key, a varchar(100), b text, c int, fulltext key ft_ab (a, b))")
+	tk.MustQuery("show create table t_ft").Check(testkit.Rows("t_ft CREATE TABLE `t_ft` (\n" +
+		"  `id` int(11) NOT NULL,\n" +
+		"  `a` varchar(100) DEFAULT NULL,\n" +
+		"  `b` text DEFAULT NULL,\n" +
+		"  `c` int(11) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */,\n" +
+		"  FULLTEXT KEY `ft_ab` (`a`,`b`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
 
-	// Make sure table t_ft still has no indexes even after it was created and altered
-	r := tk.MustQuery("show index from t_ft")
-	c.Assert(r.Rows(), HasLen, 0)
-	r = tk.MustQuery("select * from information_schema.statistics where table_schema='test' and table_name='t_ft'")
-	c.Assert(r.Rows(), HasLen, 0)
+	// The index is backfilled by the words of the existing rows.
+	tk.MustExec("insert into t_ft values (1, 'TiDB database', null, 1), (2, null, 'MySQL database', 2)")
+	tk.MustExec("alter table t_ft add fulltext key (b)")
+	tk.MustExec("create fulltext index ft_a on t_ft (a)")
+	tk.MustExec("alter table t_ft drop index ft_a")
+	tk.MustExec("alter table t_ft modify column a varchar(200)")
+	tk.MustExec("admin check table t_ft")
+
+	tk.MustGetErrCode("alter table t_ft add fulltext key (c)", errno.ErrBadFtColumn)
+	tk.MustGetErrCode("alter table t_ft add fulltext key (a(10))", errno.ErrWrongSubKey)
+	tk.MustGetErrCode("alter table t_ft add fulltext key ((lower(a)))", errno.ErrUnsupportedDDLOperation)
+	tk.MustExec("set @@tidb_enable_change_column_type = 1")
+	tk.MustGetErrCode("alter table t_ft modify column b int", errno.ErrBadFtColumn)
+	tk.MustExec("set @@tidb_enable_change_column_type = default")
+	_, err := tk.Exec("admin check index t_ft ft_ab")
+	c.Assert(err, ErrorMatches, ".*is a FULLTEXT index, which can't be checked")
+	tk.MustGetErrCode("create table t_ft2 (a varbinary(100), fulltext key (a))", errno.ErrBadFtColumn)
+	tk.MustGetErrCode("create table t_ft2 (id int, a text, fulltext key (a)) partition by hash(id) partitions 2", errno.ErrFulltextNotSupportedWithPartitioning)
+	tk.MustGetErrCode("create temporary table t_ft2 (a text, fulltext key (a))", errno.ErrInnodbNoFtTempTable)
+	tk.MustGetErrCode("create spatial index idx on t_ft (a)", errno.ErrUnsupportedDDLOperation)
 }
 
 func (s *testIntegrationSuite1) TestTreatOldVersionUTF8AsUTF8MB4(c *C) {
@@ -2773,3 +2867,72 @@ func (s *testIntegrationSuite3) TestIssue21835(c *C) {
 	_, err := tk.Exec("create table t( col decimal(1,2) not null default 0);")
 	c.Assert(err.Error(), Equals, "[types:1427]For float(M,D), double(M,D) or decimal(M,D), M must be >= D (column 'col').")
 }
+
+func (s *testIntegrationSuite7) TestAddIndexByFastReorg(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@global.tidb_ddl_enable_fast_reorg = 1")
+	defer func() {
+		tk.MustExec("set @@global.tidb_ddl_enable_fast_reorg = default")
+		variable.SetDDLEnableFastReorg(false)
+	}()
+	tk.MustQuery("select @@global.tidb_ddl_enable_fast_reorg").Check(testkit.Rows("1"))
+
+	values := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d, 's%d')", i, i%10, i))
+	}
+	tk.MustExec("drop table if exists t, tp")
+	tk.MustExec("create table t(a int primary key, b int, c varchar(10))")
+	tk.MustExec("insert into t values " + strings.Join(values, ", "))
+	tk.MustExec("alter table t add index idx_b(b)")
+	tk.MustExec("alter table t add unique index idx_c(c)")
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select count(*) from t use index(idx_b) where b = 3").Check(testkit.Rows("10"))
+	tk.MustQuery("select a from t use index(idx_c) where c = 's42'").Check(testkit.Rows("42"))
+	tk.MustGetErrCode("alter table t add unique index idx_b2(b)", errno.ErrDupEntry)
+	tk.MustExec("admin check table t")
+
+	tk.MustExec("create table tp(a varchar(10), b int, c int, primary key(a, b) clustered) partition by hash(b) partitions 3")
+	tk.MustExec("insert into tp select c, a, b from t")
+	tk.MustExec("alter table tp add index idx_c(c)")
+	tk.MustExec("alter table tp add unique index idx_ab(b, a)")
+	tk.MustExec("admin check table tp")
+	tk.MustQuery("select count(*) from tp use index(idx_c) where c = 3").Check(testkit.Rows("10"))
+}
+
+func (s *testIntegrationSuite7) TestMultiSchemaChange(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int, c int, d int, index idx_c(c))")
+	tk.MustExec("insert into t values (1, 1, 1, 1), (2, 2, 1, 2), (3, 3, 3, 3)")
+
+	tk.MustExec("alter table t add column e int default 5 after a, drop column d, add index idx_b(b), drop index idx_c, add column f int first")
+	tk.MustQuery("select * from t").Check(testkit.Rows("<nil> 1 5 1 1", "<nil> 2 5 2 1", "<nil> 3 5 3 3"))
+	tk.MustQuery("select index_name from information_schema.statistics where table_schema = 'test' and table_name = 't' order by index_name").
+		Check(testkit.Rows("PRIMARY", "idx_b"))
+	tk.MustQuery("select b from t use index(idx_b) where b > 1").Check(testkit.Rows("2", "3"))
+	tk.MustExec("admin check table t")
+	rows := tk.MustQuery("admin show ddl jobs 1").Rows()
+	c.Assert(rows[0][3], Equals, "alter table multi-schema change")
+
+	tk.MustExec("alter table t rename index idx_b to idx_b1, add column g int default 7, drop column f")
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 5 1 1 7"))
+	tk.MustExec("admin check index t idx_b1")
+
+	// All the sub-jobs are rolled back if one of them fails.
+	tk.MustGetErrCode("alter table t add column h int, add index idx_e(e), add unique index idx_c(c)", errno.ErrDupEntry)
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 5 1 1 7"))
+	tk.MustQuery("select index_name from information_schema.statistics where table_schema = 'test' and table_name = 't' order by index_name").
+		Check(testkit.Rows("PRIMARY", "idx_b1"))
+	tk.MustExec("admin check table t")
+	tk.MustGetErrCode("alter table t add column h int, drop column not_exist", errno.ErrCantDropFieldOrKey)
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 5 1 1 7"))
+
+	// The same column or index can't be changed more than once.
+	tk.MustGetErrCode("alter table t drop column b, add index idx_b2(b)", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("alter table t add column h int after g, drop column g", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("alter table t drop index idx_b1, rename index idx_b1 to idx_b2", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("alter table t add column h int, modify column c bigint", errno.ErrUnsupportedDDLOperation)
+}
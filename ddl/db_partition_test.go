@@ -2104,6 +2104,175 @@ func (s *testIntegrationSuite7) TestExchangePartitionExpressIndex(c *C) {
 
 }
 
+func (s *testIntegrationSuite7) TestExchangePartitionValidation(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_enable_exchange_partition=1")
+	defer tk.MustExec("set @@tidb_enable_exchange_partition=0")
+	tk.MustExec("set @@session.tidb_enable_list_partition = ON")
+	tk.MustExec("drop table if exists pt, nt")
+
+	// The NULL value only belongs to the first range or hash partition.
+	tk.MustExec("create table pt (a int) partition by range (a) (partition p0 values less than (10), partition p1 values less than (maxvalue))")
+	tk.MustExec("create table nt (a int)")
+	tk.MustExec("insert into nt values (NULL), (1)")
+	tk.MustGetErrCode("alter table pt exchange partition p1 with table nt", tmysql.ErrRowDoesNotMatchPartition)
+	tk.MustExec("alter table pt exchange partition p0 with table nt")
+	tk.MustQuery("select * from pt partition (p0)").Sort().Check(testkit.Rows("1", "<nil>"))
+
+	tk.MustExec("drop table pt")
+	tk.MustExec("create table pt (a int) partition by hash (a) partitions 3")
+	tk.MustExec("insert into nt values (NULL)")
+	tk.MustGetErrCode("alter table pt exchange partition p1 with table nt", tmysql.ErrRowDoesNotMatchPartition)
+	tk.MustExec("alter table pt exchange partition p0 with table nt")
+	// The remainder of a negative value is turned into the positive one.
+	tk.MustExec("insert into nt values (-4), (2)")
+	tk.MustGetErrCode("alter table pt exchange partition p1 with table nt", tmysql.ErrRowDoesNotMatchPartition)
+	tk.MustExec("delete from nt where a = 2")
+	tk.MustExec("alter table pt exchange partition p1 with table nt")
+	tk.MustQuery("select * from pt partition (p1)").Check(testkit.Rows("-4"))
+
+	// The values of the list partition may be strings or NULL.
+	tk.MustExec("drop table pt, nt")
+	tk.MustExec("create table pt (a varchar(10)) partition by list columns (a) (partition p0 values in ('a', NULL), partition p1 values in ('b'))")
+	tk.MustExec("create table nt (a varchar(10))")
+	tk.MustExec("insert into nt values ('a'), (NULL)")
+	tk.MustGetErrCode("alter table pt exchange partition p1 with table nt", tmysql.ErrRowDoesNotMatchPartition)
+	tk.MustExec("alter table pt exchange partition p0 with table nt")
+	tk.MustQuery("select * from pt partition (p0)").Sort().Check(testkit.Rows("<nil>", "a"))
+
+	tk.MustExec("drop table pt, nt")
+	tk.MustExec("create table pt (a int) partition by list (a) (partition p0 values in (1, 2), partition p1 values in (3, NULL))")
+	tk.MustExec("create table nt (a int)")
+	tk.MustExec("insert into nt values (NULL), (0)")
+	tk.MustGetErrCode("alter table pt exchange partition p1 with table nt", tmysql.ErrRowDoesNotMatchPartition)
+	tk.MustExec("delete from nt where a = 0")
+	tk.MustExec("alter table pt exchange partition p1 with table nt")
+
+	// The list columns partition with multiple columns.
+	tk.MustExec("drop table pt, nt")
+	tk.MustExec("create table pt (a int, b int) partition by list columns (a, b) (partition p0 values in ((1, 2), (3, 4)), partition p1 values in ((5, 6)))")
+	tk.MustExec("create table nt (a int, b int)")
+	tk.MustExec("insert into nt values (1, 4)")
+	tk.MustGetErrCode("alter table pt exchange partition p0 with table nt", tmysql.ErrRowDoesNotMatchPartition)
+	tk.MustExec("alter table pt exchange partition p0 with table nt without validation")
+	tk.MustExec("insert into nt values (1, 2), (3, 4)")
+	tk.MustExec("alter table pt exchange partition p0 with table nt")
+	tk.MustQuery("select * from pt partition (p0)").Sort().Check(testkit.Rows("1 2", "3 4"))
+}
+
+func (s *testIntegrationSuite7) TestReorganizePartition(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@session.tidb_enable_list_partition = ON")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec(`create table t (a int, b varchar(10), key(b)) partition by range (a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20),
+		partition p2 values less than (30))`)
+	tk.MustExec("insert into t values (1, 'a'), (5, 'b'), (12, 'c'), (18, 'd'), (25, 'e')")
+
+	// Split a partition.
+	tk.MustExec("alter table t reorganize partition p0 into (partition p0a values less than (5), partition p0b values less than (10))")
+	tk.MustQuery("select * from t partition (p0a)").Check(testkit.Rows("1 a"))
+	tk.MustQuery("select * from t partition (p0b)").Check(testkit.Rows("5 b"))
+	tk.MustQuery("select a from t use index(b) where b = 'b'").Check(testkit.Rows("5"))
+	tk.MustExec("admin check table t")
+
+	// Merge the partitions.
+	tk.MustExec("alter table t reorganize partition p0b, p1 into (partition p1 values less than (20))")
+	tk.MustQuery("select * from t partition (p1)").Sort().Check(testkit.Rows("12 c", "18 d", "5 b"))
+	tk.MustExec("admin check table t")
+
+	// The last partition can be extended.
+	tk.MustExec("alter table t reorganize partition p2 into (partition p2 values less than (25), partition p3 values less than (maxvalue))")
+	tk.MustQuery("select * from t partition (p3)").Check(testkit.Rows("25 e"))
+	tk.MustExec("insert into t values (100, 'f')")
+	tk.MustQuery("select a from t partition (p3)").Sort().Check(testkit.Rows("100", "25"))
+	tk.MustExec("admin check table t")
+	c.Assert(tk.MustQuery("admin show ddl jobs 1").Rows()[0][3], Equals, "reorganize partition")
+
+	tk.MustGetErrCode("alter table t reorganize partition p0a, p3 into (partition p0 values less than (maxvalue))", tmysql.ErrConsecutiveReorgPartitions)
+	tk.MustGetErrCode("alter table t reorganize partition p0a into (partition p0 values less than (4))", tmysql.ErrReorgOutsideRange)
+	tk.MustGetErrCode("alter table t reorganize partition p0a into (partition p0 values less than (25))", tmysql.ErrRangeNotIncreasing)
+	tk.MustGetErrCode("alter table t reorganize partition p3 into (partition p3 values less than (100))", tmysql.ErrReorgOutsideRange)
+	tk.MustGetErrCode("alter table t reorganize partition p4 into (partition p4 values less than (maxvalue))", tmysql.ErrDropPartitionNonExistent)
+	tk.MustGetErrCode("alter table t reorganize partition", tmysql.ErrReorgNoParam)
+
+	// The rows of the list partitions are moved by their values.
+	tk.MustExec("drop table t")
+	tk.MustExec(`create table t (a int primary key, b int) partition by list (a) (
+		partition p0 values in (1, 2, 3),
+		partition p1 values in (4, 5))`)
+	tk.MustExec("insert into t values (1, 1), (2, 2), (4, 4)")
+	tk.MustExec("alter table t reorganize partition p0 into (partition p0 values in (1), partition p2 values in (2, 3, 6))")
+	tk.MustQuery("select * from t partition (p2)").Check(testkit.Rows("2 2"))
+	tk.MustExec("insert into t values (6, 6)")
+	tk.MustQuery("select * from t partition (p2)").Sort().Check(testkit.Rows("2 2", "6 6"))
+	tk.MustExec("admin check table t")
+	tk.MustGetErrCode("alter table t reorganize partition p1 into (partition p1 values in (4))", tmysql.ErrReorgOutsideRange)
+
+	tk.MustExec("drop table t")
+	tk.MustExec("create table t (a int) partition by hash (a) partitions 4")
+	tk.MustGetErrCode("alter table t reorganize partition p0 into (partition p0)", tmysql.ErrOnlyOnRangeListPartition)
+	tk.MustExec("drop table t")
+	tk.MustExec("create table t (a int)")
+	tk.MustGetErrCode("alter table t reorganize partition p0 into (partition p0 values less than (10))", tmysql.ErrPartitionMgmtOnNonpartitioned)
+}
+
+func (s *testIntegrationSuite7) TestReorganizePartitionWithDML(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec(`create table t (a int, b int, unique key(a, b)) partition by range (a) (
+		partition p0 values less than (100),
+		partition p1 values less than (maxvalue))`)
+	for i := 0; i < 50; i++ {
+		tk.MustExec("insert into t values (?, ?)", i*2, i)
+	}
+
+	tk1 := testkit.NewTestKit(c, s.store)
+	tk1.MustExec("use test")
+	var checkErr error
+	states := make(map[model.SchemaState]struct{})
+	hook := &ddl.TestDDLCallback{}
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if job.Type != model.ActionReorganizePartition || checkErr != nil {
+			return
+		}
+		if _, ok := states[job.SchemaState]; ok {
+			return
+		}
+		states[job.SchemaState] = struct{}{}
+		// The rows are changed in every state, and they must be in the new partitions after the job is done.
+		v := 1 + len(states)*2
+		for _, sql := range []string{
+			fmt.Sprintf("insert into t values (%d, 1000)", v),
+			fmt.Sprintf("update t set b = b + 1000 where a = %d", v-1),
+			fmt.Sprintf("delete from t where a = %d", 51+v),
+			fmt.Sprintf("update t set a = %d where a = %d", 200+v, 71+v),
+		} {
+			if _, checkErr = tk1.Exec(sql); checkErr != nil {
+				return
+			}
+		}
+	}
+	originalHook := s.dom.DDL().GetHook()
+	defer s.dom.DDL().(ddl.DDLForTest).SetHook(originalHook)
+	s.dom.DDL().(ddl.DDLForTest).SetHook(hook)
+	tk.MustExec("alter table t reorganize partition p0 into (partition p0a values less than (50), partition p0b values less than (100))")
+	c.Assert(checkErr, IsNil)
+	c.Assert(len(states), Equals, 5)
+
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("50"))
+	tk.MustQuery("select count(*) from t partition (p0a) where a >= 50").Check(testkit.Rows("0"))
+	tk.MustQuery("select count(*) from t partition (p0b) where a < 50").Check(testkit.Rows("0"))
+	tk.MustQuery("select count(*) from t partition (p0a, p0b)").Check(testkit.Rows("45"))
+	tk.MustQuery("select a, b from t where b >= 1000 order by a").Check(testkit.Rows(
+		"2 1001", "3 1000", "4 1002", "5 1000", "6 1003", "7 1000", "8 1004", "9 1000", "10 1005", "11 1000"))
+}
+
 func (s *testIntegrationSuite4) TestAddPartitionTooManyPartitions(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -3037,7 +3206,7 @@ func (s *testIntegrationSuite3) TestPartitionErrorCode(c *C) {
 	c.Assert(ddl.ErrCoalesceOnlyOnHashPartition.Equal(err), IsTrue)
 
 	tk.MustGetErrCode(`alter table t_part reorganize partition p0, p1 into (
-			partition p0 values less than (1980));`, tmysql.ErrUnsupportedDDLOperation)
+			partition p0 values less than (15));`, tmysql.ErrReorgOutsideRange)
 
 	tk.MustGetErrCode("alter table t_part check partition p0, p1;", tmysql.ErrUnsupportedDDLOperation)
 	tk.MustGetErrCode("alter table t_part optimize partition p0,p1;", tmysql.ErrUnsupportedDDLOperation)
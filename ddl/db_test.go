@@ -36,6 +36,7 @@ import (
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/ddl"
 	testddlutil "github.com/pingcap/tidb/ddl/testutil"
+	ddlutil "github.com/pingcap/tidb/ddl/util"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/executor"
@@ -605,6 +606,230 @@ func (s *testDBSuite4) TestCancelAddIndex1(c *C) {
 	s.mustExec(tk, c, "alter table t drop index idx_c2")
 }
 
+func (s *testDBSuite4) TestPauseAndResumeAddIndex(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	s.mustExec(tk, c, "use test_db")
+	s.mustExec(tk, c, "drop table if exists t")
+	s.mustExec(tk, c, "create table t(c1 int, c2 int)")
+	defer s.mustExec(tk, c, "drop table t;")
+
+	for i := 0; i < 50; i++ {
+		s.mustExec(tk, c, "insert into t values (?, ?)", i, i)
+	}
+
+	tk1 := testkit.NewTestKit(c, s.store)
+	updateJobState := func(op string, jobID int64) error {
+		rs, err := tk1.Exec(fmt.Sprintf("admin %s ddl jobs %d", op, jobID))
+		if err != nil {
+			return err
+		}
+		chk := rs.NewChunk()
+		err = rs.Next(context.Background(), chk)
+		if err1 := rs.Close(); err == nil {
+			err = err1
+		}
+		if err == nil && chk.GetRow(0).GetString(1) != "successful" {
+			err = errors.New(chk.GetRow(0).GetString(1))
+		}
+		return err
+	}
+
+	var checkErr error
+	var jobID int64
+	hook := &ddl.TestDDLCallback{Do: s.dom}
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if jobID == 0 && job.Type == model.ActionAddIndex && job.State == model.JobStateRunning && job.SchemaState == model.StateWriteReorganization && job.SnapshotVer == 0 {
+			jobID = job.ID
+			checkErr = updateJobState("pause", job.ID)
+		}
+	}
+	originalHook := s.dom.DDL().GetHook()
+	s.dom.DDL().(ddl.DDLForTest).SetHook(hook)
+	defer s.dom.DDL().(ddl.DDLForTest).SetHook(originalHook)
+
+	done := make(chan error, 1)
+	go backgroundExec(s.store, "alter table t add index idx_c2(c2)", done)
+
+	// Wait for the job to be paused.
+	var pausedJob *model.Job
+	for i := 0; i < 100 && pausedJob == nil; i++ {
+		time.Sleep(20 * time.Millisecond)
+		err := kv.RunInNewTxn(context.Background(), s.store, false, func(ctx context.Context, txn kv.Transaction) error {
+			jobs, err := admin.GetDDLJobs(txn)
+			for _, job := range jobs {
+				if job.ID == jobID && job.IsPaused() {
+					pausedJob = job
+				}
+			}
+			return err
+		})
+		c.Assert(err, IsNil)
+	}
+	c.Assert(checkErr, IsNil)
+	c.Assert(pausedJob, NotNil)
+	c.Assert(pausedJob.SchemaState, Equals, model.StateWriteReorganization)
+	tk.MustQuery(fmt.Sprintf("select state from information_schema.ddl_jobs where job_id = %d", jobID)).Check(testkit.Rows("paused"))
+
+	// The paused job isn't rolled back, it continues after it's resumed.
+	c.Assert(updateJobState("resume", jobID), IsNil)
+	select {
+	case err := <-done:
+		c.Assert(err, IsNil)
+	case <-time.After(10 * time.Second):
+		c.Fatal("the resumed job isn't finished")
+	}
+	s.mustExec(tk, c, "admin check table t")
+	tk.MustQuery("select count(*) from t use index(idx_c2)").Check(testkit.Rows("50"))
+}
+
+func (s *testDBSuite4) TestConcurrentDDLOfDifferentTables(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	s.mustExec(tk, c, "use test_db")
+	s.mustExec(tk, c, "drop table if exists t1, t2, t3")
+	s.mustExec(tk, c, "create table t1(a int)")
+	s.mustExec(tk, c, "create table t2(a int)")
+	s.mustExec(tk, c, "create table t3(a int)")
+	defer s.mustExec(tk, c, "drop table t1, t2, t3")
+	tk.MustExec("set @@global.tidb_enable_concurrent_ddl = 1")
+	defer tk.MustExec("set @@global.tidb_enable_concurrent_ddl = default")
+
+	// Find a table whose jobs are in the different table job queue from the ones of t1.
+	t1ID := s.testGetTable(c, "t1").Meta().ID
+	otherTable := "t2"
+	if s.testGetTable(c, "t2").Meta().ID%meta.TableJobListKeyCnt == t1ID%meta.TableJobListKeyCnt {
+		otherTable = "t3"
+	}
+
+	blocked := make(chan struct{})
+	resume := make(chan struct{})
+	var once, resumeOnce sync.Once
+	resumeJob := func() { resumeOnce.Do(func() { close(resume) }) }
+	hook := &ddl.TestDDLCallback{Do: s.dom}
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if job.TableID == t1ID && job.Type == model.ActionAddColumn {
+			once.Do(func() {
+				close(blocked)
+				<-resume
+			})
+		}
+	}
+	originalHook := s.dom.DDL().GetHook()
+	s.dom.DDL().(ddl.DDLForTest).SetHook(hook)
+	defer s.dom.DDL().(ddl.DDLForTest).SetHook(originalHook)
+	// Resume the job before the hook is reset, the reset waits for the hook to return.
+	defer resumeJob()
+
+	done := make(chan error, 1)
+	go backgroundExec(s.store, "alter table t1 add column b int", done)
+	select {
+	case <-blocked:
+	case <-time.After(10 * time.Second):
+		c.Fatal("the job of t1 isn't run")
+	}
+
+	// The job of the other table isn't blocked by the running job of t1.
+	otherDone := make(chan error, 1)
+	go backgroundExec(s.store, fmt.Sprintf("alter table %s add column b int", otherTable), otherDone)
+	select {
+	case err := <-otherDone:
+		c.Assert(err, IsNil)
+	case <-time.After(10 * time.Second):
+		c.Fatal("the job of the other table is blocked by the job of t1")
+	}
+	resumeJob()
+	c.Assert(<-done, IsNil)
+	tk.MustQuery("select b from t1").Check(testkit.Rows())
+	tk.MustQuery(fmt.Sprintf("select b from %s", otherTable)).Check(testkit.Rows())
+}
+
+func (s *testDBSuite4) TestSubscribeDDLEvents(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test_db")
+	tk.MustExec("drop table if exists t_event")
+	startVer := s.dom.InfoSchema().SchemaMetaVersion()
+	subscriber := ddlutil.NewSubscriber(s.store, startVer)
+	subscriber.SetInterval(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	tk.MustExec("create table t_event (a int)")
+	tk.MustExec("alter table t_event add column b int")
+	events, err := subscriber.Next(ctx, 1)
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 1)
+	c.Assert(events[0].Type, Equals, model.ActionCreateTable)
+	c.Assert(events[0].SchemaName, Equals, "test_db")
+	c.Assert(events[0].TableName, Equals, "t_event")
+	c.Assert(events[0].Query, Equals, "create table t_event (a int)")
+	c.Assert(events[0].SchemaVersion, Greater, startVer)
+	events, err = subscriber.Next(ctx, 0)
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 1)
+	c.Assert(events[0].Type, Equals, model.ActionAddColumn)
+	c.Assert(events[0].TypeName, Equals, "add column")
+	c.Assert(events[0].SchemaVersion, Equals, s.dom.InfoSchema().SchemaMetaVersion())
+	c.Assert(subscriber.Version(), Equals, events[0].SchemaVersion)
+
+	// The failed jobs aren't notified.
+	_, err = tk.Exec("alter table t_event add index idx_err(c)")
+	c.Assert(err, NotNil)
+	timeoutCtx, timeoutCancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer timeoutCancel()
+	_, err = subscriber.Next(timeoutCtx, 0)
+	c.Assert(err, Equals, context.DeadlineExceeded)
+}
+
+func (s *testDBSuite4) TestGeneratedInvisiblePK(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test_db")
+	tk.MustExec("drop table if exists t_gipk, t_pk")
+	tk.MustExec("set @@session.tidb_enable_generated_invisible_primary_key = 1")
+	defer tk.MustExec("drop table if exists t_gipk, t_pk")
+	tk.MustExec("create table t_gipk (a int, b varchar(10))")
+	tk.MustExec("create table t_pk (a int primary key nonclustered, b int)")
+
+	tbl := testGetTableByName(c, tk.Se, "test_db", "t_gipk")
+	c.Assert(tbl.Meta().PKIsHandle, IsTrue)
+	c.Assert(tbl.Meta().AutoRandomBits, Greater, uint64(0))
+	c.Assert(tables.IsGeneratedInvisiblePK(tbl.Meta().Columns[0]), IsTrue)
+	tbl = testGetTableByName(c, tk.Se, "test_db", "t_pk")
+	c.Assert(model.FindColumnInfo(tbl.Meta().Columns, tables.GeneratedInvisiblePKName), IsNil)
+
+	tk.MustExec("insert into t_gipk values (1, 'a'), (2, 'b')")
+	tk.MustExec("insert into t_gipk (b) values ('c')")
+	tk.MustQuery("select * from t_gipk order by b").Check(testkit.Rows("1 a", "2 b", "<nil> c"))
+	tk.MustQuery("select * from t_gipk where a = 1").Check(testkit.Rows("1 a"))
+	tk.MustQuery("show create table t_gipk").Check(testkit.Rows("t_gipk CREATE TABLE `t_gipk` (\n" +
+		"  `a` int(11) DEFAULT NULL,\n" +
+		"  `b` varchar(10) DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustQuery("show columns from t_gipk").Check(testkit.Rows("a int(11) YES  <nil> ", "b varchar(10) YES  <nil> "))
+	tk.MustGetErrCode("select _tidb_gipk from t_gipk", errno.ErrBadField)
+	tk.MustGetErrCode("select * from t_gipk where _tidb_gipk = 1", errno.ErrBadField)
+	tk.MustGetErrCode("alter table t_gipk add index idx(_tidb_gipk)", errno.ErrKeyColumnDoesNotExits)
+	tk.MustGetErrCode("alter table t_gipk drop column _tidb_gipk", errno.ErrCantDropFieldOrKey)
+
+	tk.MustExec("set @@session.tidb_show_generated_invisible_primary_key = 1")
+	rows := tk.MustQuery("select * from t_gipk order by b").Rows()
+	c.Assert(rows, HasLen, 3)
+	c.Assert(rows[0], HasLen, 3)
+	tk.MustQuery("select count(distinct _tidb_gipk) from t_gipk").Check(testkit.Rows("3"))
+	pk := rows[0][0].(string)
+	tk.MustQuery("select a, b from t_gipk where _tidb_gipk = " + pk).Check(testkit.Rows("1 a"))
+	tk.MustQuery("select * from t_gipk where _tidb_gipk = " + pk).Check(testkit.Rows(pk + " 1 a"))
+	showCreate := tk.MustQuery("show create table t_gipk").Rows()[0][1].(string)
+	c.Assert(strings.Contains(showCreate, "`_tidb_gipk` bigint(20) NOT NULL /*T![auto_rand] AUTO_RANDOM(5) */"), IsTrue, Commentf("%s", showCreate))
+	c.Assert(strings.Contains(showCreate, "PRIMARY KEY (`_tidb_gipk`) /*T![clustered_index] CLUSTERED */"), IsTrue, Commentf("%s", showCreate))
+	tk.MustQuery("select column_name from information_schema.columns where table_schema = 'test_db' and table_name = 't_gipk'").
+		Check(testkit.Rows("_tidb_gipk", "a", "b"))
+
+	tk.MustExec("set @@session.tidb_show_generated_invisible_primary_key = 0")
+	tk.MustExec("update t_gipk set b = 'd' where a = 1")
+	tk.MustExec("delete from t_gipk where a = 2")
+	tk.MustQuery("select * from t_gipk order by b").Check(testkit.Rows("<nil> c", "1 d"))
+	tk.MustQuery("select count(*) from t_gipk use index(primary)").Check(testkit.Rows("2"))
+}
+
 // TestCancelDropIndex tests cancel ddl job which type is drop primary key.
 func (s *testDBSuite4) TestCancelDropPrimaryKey(c *C) {
 	idxName := "primary"
@@ -2410,7 +2635,7 @@ func (s *testDBSuite5) TestRenameColumn(c *C) {
 
 	// Test renaming the column with foreign key.
 	tk.MustExec("drop table test_rename_column")
-	tk.MustExec("create table test_rename_column_base (base int)")
+	tk.MustExec("create table test_rename_column_base (base int, key(base))")
 	tk.MustExec("create table test_rename_column (col int, foreign key (col) references test_rename_column_base(base))")
 
 	tk.MustGetErrCode("alter table test_rename_column rename column col to col1", errno.ErrFKIncompatibleColumns)
@@ -2999,7 +3224,7 @@ func (s *testDBSuite2) TestCreateTableWithEnumCol(c *C) {
 func (s *testDBSuite2) TestTableForeignKey(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
-	tk.MustExec("create table t1 (a int, b int);")
+	tk.MustExec("create table t1 (a int, b int, key(b));")
 	// test create table with foreign key.
 	failSQL := "create table t2 (c int, foreign key (a) references t1(a));"
 	tk.MustGetErrCode(failSQL, errno.ErrKeyColumnDoesNotExits)
@@ -3026,6 +3251,284 @@ func (s *testDBSuite2) TestTableForeignKey(c *C) {
 	tk.MustExec("drop table if exists t1,t2,t3,t4;")
 }
 
+func (s *testDBSuite2) TestForeignKeyMetadata(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists fk_child, fk_child2, fk_self, fk_parent")
+	tk.MustExec("create table fk_parent (id int primary key, a int, b varchar(10), c bigint unsigned, key(a, b))")
+
+	// The referred columns must exist, be compatible and be the leading columns of an index.
+	tk.MustGetErrCode("create table fk_child (pid int, foreign key (pid) references fk_parent(x))", errno.ErrKeyColumnDoesNotExits)
+	tk.MustGetErrCode("create table fk_child (pid bigint, foreign key (pid) references fk_parent(id))", errno.ErrFKIncompatibleColumns)
+	tk.MustGetErrCode("create table fk_child (pid bigint, foreign key (pid) references fk_parent(c))", errno.ErrFKIncompatibleColumns)
+	tk.MustGetErrCode("create table fk_child (b varchar(20) charset latin1, foreign key (b) references fk_parent(b))", errno.ErrFKIncompatibleColumns)
+	tk.MustGetErrCode("create table fk_child (b varchar(10), foreign key (b) references fk_parent(b))", errno.ErrFkNoIndexParent)
+	tk.MustGetErrCode("create table fk_child (pid int not null, foreign key (pid) references fk_parent(id) on delete set null)", errno.ErrFkColumnNotNull)
+	// The referred table which doesn't exist is allowed.
+	tk.MustExec("create table fk_child2 (pid int, foreign key fk_none (pid) references fk_not_exists(id))")
+
+	// The index used by the foreign key is created automatically.
+	tk.MustExec("create table fk_child (id int, a int, b varchar(20), constraint fk_1 foreign key (a, b) references fk_parent(a, b) on delete cascade on update set null)")
+	tk.MustQuery("show create table fk_child").Check(testkit.Rows("fk_child CREATE TABLE `fk_child` (\n" +
+		"  `id` int(11) DEFAULT NULL,\n" +
+		"  `a` int(11) DEFAULT NULL,\n" +
+		"  `b` varchar(20) DEFAULT NULL,\n" +
+		"  KEY `fk_1` (`a`,`b`),\n" +
+		"  CONSTRAINT `fk_1` FOREIGN KEY (`a`,`b`) REFERENCES `fk_parent` (`a`,`b`) ON DELETE CASCADE ON UPDATE SET NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustGetErrCode("alter table fk_child drop index fk_1", errno.ErrDropIndexFk)
+	tk.MustExec("alter table fk_child add index idx_a_b (a, b)")
+	tk.MustExec("alter table fk_child drop index fk_1")
+	tk.MustGetErrCode("alter table fk_child drop index idx_a_b", errno.ErrDropIndexFk)
+
+	// The self-referential foreign key.
+	tk.MustGetErrCode("create table fk_self (id int, pid int, foreign key (pid) references fk_self(id))", errno.ErrFkNoIndexParent)
+	tk.MustExec("create table fk_self (id int primary key, pid int, foreign key (pid) references fk_self(id))")
+
+	// Add foreign key.
+	tk.MustGetErrCode("alter table fk_child add foreign key (b) references fk_parent(b)", errno.ErrFkNoIndexParent)
+	tk.MustGetErrCode("alter table fk_child add constraint fk_1 foreign key (id) references fk_parent(id)", errno.ErrCannotAddForeign)
+	tk.MustExec("alter table fk_child add foreign key (id) references fk_parent(id)")
+	tk.MustQuery("select index_name, column_name from information_schema.statistics where table_name = 'fk_child' order by index_name, seq_in_index").Check(
+		testkit.Rows("id id", "idx_a_b a", "idx_a_b b"))
+	tk.MustQuery("select constraint_name, unique_constraint_name, match_option, update_rule, delete_rule, table_name, referenced_table_name " +
+		"from information_schema.referential_constraints where constraint_schema = 'test' and table_name like 'fk_child%' order by constraint_name").Check(testkit.Rows(
+		"fk_1 a NONE SET NULL CASCADE fk_child fk_parent",
+		"fk_none <nil> NONE NO ACTION NO ACTION fk_child2 fk_not_exists",
+		"id PRIMARY NONE NO ACTION NO ACTION fk_child fk_parent"))
+	tk.MustExec("alter table fk_child drop foreign key id")
+	tk.MustExec("alter table fk_child drop index id")
+	tk.MustExec("drop table fk_child, fk_child2, fk_self, fk_parent")
+}
+
+func (s *testDBSuite2) TestRepairIndex(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use " + s.schemaName)
+	tk.MustExec("drop table if exists t_repair")
+	tk.MustExec("create table t_repair (a int primary key, b int, c varchar(10), key idx_b(b), unique key idx_c(c), key idx_bc(b, c))")
+	defer tk.MustExec("drop table if exists t_repair")
+	for i := 0; i < 20; i++ {
+		tk.MustExec("insert into t_repair values (?, ?, ?)", i, i%5, strconv.Itoa(i))
+	}
+	tbl := s.testGetTable(c, "t_repair")
+	idxInfo := tbl.Meta().FindIndexByName("idx_b")
+	oldIdxID := idxInfo.ID
+
+	// Make the index corrupted, an entry is missing and an entry doesn't have the record.
+	idx := tables.NewIndex(tbl.Meta().ID, tbl.Meta(), idxInfo)
+	txn, err := s.store.Begin()
+	c.Assert(err, IsNil)
+	sc := tk.Se.GetSessionVars().StmtCtx
+	c.Assert(idx.Delete(sc, txn.GetUnionStore(), types.MakeDatums(1), kv.IntHandle(1)), IsNil)
+	_, err = idx.Create(tk.Se, txn, types.MakeDatums(3), kv.IntHandle(100), nil)
+	c.Assert(err, IsNil)
+	c.Assert(txn.Commit(context.Background()), IsNil)
+	c.Assert(tk.ExecToErr("admin check index t_repair idx_b"), NotNil)
+
+	d := s.dom.DDL()
+	ident := ast.Ident{Schema: model.NewCIStr(s.schemaName), Name: model.NewCIStr("t_repair")}
+	c.Assert(d.RepairIndex(tk.Se, ident, model.NewCIStr("idx_b")), IsNil)
+	tk.MustExec("admin check table t_repair")
+	tk.MustQuery("select a from t_repair use index(idx_b) where b = 1").Check(testkit.Rows("1", "6", "11", "16"))
+	tk.MustQuery("select count(*) from t_repair use index(idx_b) where b = 3").Check(testkit.Rows("4"))
+
+	// The repaired index replaces the old one with the same name and position.
+	tbl = s.testGetTable(c, "t_repair")
+	c.Assert(tbl.Meta().Indices, HasLen, 3)
+	c.Assert(tbl.Meta().Indices[0].Name.L, Equals, "idx_b")
+	c.Assert(tbl.Meta().Indices[0].State, Equals, model.StatePublic)
+	c.Assert(tbl.Meta().Indices[0].ID, Not(Equals), oldIdxID)
+	c.Assert(tbl.Meta().Indices[1].Name.L, Equals, "idx_c")
+	c.Assert(tbl.Meta().Indices[2].Name.L, Equals, "idx_bc")
+	oldIdx := tables.NewIndex(tbl.Meta().ID, tbl.Meta(), &model.IndexInfo{ID: oldIdxID})
+	checkDelRangeDone(c, tk.Se, oldIdx)
+
+	// The unique index is repaired as well.
+	c.Assert(d.RepairIndex(tk.Se, ident, model.NewCIStr("idx_c")), IsNil)
+	tk.MustExec("admin check index t_repair idx_c")
+	_, err = tk.Exec("insert into t_repair values (100, 1, '1')")
+	c.Assert(err, NotNil)
+	c.Assert(kv.ErrKeyExists.Equal(err), IsTrue)
+
+	err = d.RepairIndex(tk.Se, ident, model.NewCIStr("idx_x"))
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[ddl:1091]index idx_x doesn't exist")
+	tk.MustExec("drop table if exists t_repair_clustered")
+	tk.MustExec("create table t_repair_clustered (a varchar(10), b int, primary key(a) clustered)")
+	defer tk.MustExec("drop table if exists t_repair_clustered")
+	err = d.RepairIndex(tk.Se, ast.Ident{Schema: model.NewCIStr(s.schemaName), Name: model.NewCIStr("t_repair_clustered")}, model.NewCIStr("primary"))
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[ddl:8200]Unsupported repair index: the clustered index is the table records")
+}
+
+func (s *testDBSuite2) TestCreateTableAsSelect(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use " + s.schemaName)
+	tk.MustExec("drop table if exists t_src, t_ctas")
+	tk.MustExec("create table t_src (a int, b int)")
+	tk.MustExec("insert into t_src values (1, 1), (2, 2), (3, 2)")
+	defer tk.MustExec("drop table if exists t_src, t_ctas")
+
+	// The table is invisible until the rows are inserted.
+	tk2 := testkit.NewTestKit(c, s.store)
+	tk2.MustExec("use " + s.schemaName)
+	var checkErr error
+	checked := false
+	hook := &ddl.TestDDLCallback{Do: s.dom}
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if checked || job.Type != model.ActionCreateTableAsSelect || job.SchemaState != model.StateWriteReorganization {
+			return
+		}
+		checked = true
+		if _, err := tk2.Exec("select * from t_ctas"); !infoschema.ErrTableNotExists.Equal(err) {
+			checkErr = errors.Errorf("unexpected error of select: %v", err)
+			return
+		}
+		if rows := tk2.MustQuery("show tables like 't_ctas'").Rows(); len(rows) != 0 {
+			checkErr = errors.Errorf("unexpected tables: %v", rows)
+			return
+		}
+		if _, err := tk2.Exec("drop table t_ctas"); err == nil {
+			checkErr = errors.New("the invisible table is dropped")
+		}
+	}
+	originalHook := s.dom.DDL().GetHook()
+	s.dom.DDL().(ddl.DDLForTest).SetHook(hook)
+	defer s.dom.DDL().(ddl.DDLForTest).SetHook(originalHook)
+	tk.MustExec("create table t_ctas (a int primary key) select a, b from t_src")
+	c.Assert(checkErr, IsNil)
+	c.Assert(checked, IsTrue)
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(3))
+	tk.MustQuery("select * from t_ctas").Check(testkit.Rows("1 1", "2 2", "3 2"))
+	tk.MustExec("drop table t_ctas")
+
+	// The table is dropped by the rollback of the job if the rows can't be inserted, its data is cleaned up.
+	var tblID int64
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if job.Type == model.ActionCreateTableAsSelect {
+			tblID = job.TableID
+		}
+	}
+	tk.MustGetErrCode("create table t_ctas (b int primary key) select a, b from t_src", errno.ErrDupEntry)
+	tk.MustGetErrCode("select * from t_ctas", errno.ErrNoSuchTable)
+	tk.MustQuery("show tables like 't_ctas'").Check(testkit.Rows())
+	tk.MustQuery(fmt.Sprintf("select count(*) from mysql.gc_delete_range_done where element_id = %d", tblID)).Check(testkit.Rows("1"))
+
+	// The job can be cancelled before the rows are inserted.
+	cancelled := false
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if cancelled || job.Type != model.ActionCreateTableAsSelect || job.SchemaState != model.StateWriteReorganization {
+			return
+		}
+		cancelled = true
+		txn, err := s.store.Begin()
+		if err != nil {
+			checkErr = errors.Trace(err)
+			return
+		}
+		errs, err := admin.CancelJobs(txn, []int64{job.ID})
+		if err != nil {
+			checkErr = errors.Trace(err)
+			return
+		}
+		if errs[0] != nil {
+			checkErr = errors.Trace(errs[0])
+			return
+		}
+		checkErr = txn.Commit(context.Background())
+	}
+	err := tk.ExecToErr("create table t_ctas select a, b from t_src")
+	c.Assert(checkErr, IsNil)
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[ddl:8214]Cancelled DDL job")
+	tk.MustGetErrCode("select * from t_ctas", errno.ErrNoSuchTable)
+}
+
+func (s *testDBSuite2) TestPreSplitIndexRegions(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use " + s.schemaName)
+	tk.MustExec("drop table if exists t_pre_split")
+	tk.MustExec("create table t_pre_split (a int, b int)")
+	defer tk.MustExec("drop table if exists t_pre_split")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t_pre_split values (%d, %d)", i, i%10))
+	}
+
+	// The index isn't split if the column isn't analyzed.
+	tk.MustExec("set @@tidb_pre_split_index_regions = 4")
+	tk.MustExec("alter table t_pre_split add index idx_a(a)")
+	c.Assert(tk.MustQuery("show table t_pre_split index idx_a regions").Rows(), HasLen, 1)
+
+	tk.MustExec("analyze table t_pre_split")
+	tk.MustExec("create index idx_ab on t_pre_split (a, b)")
+	c.Assert(tk.MustQuery("show table t_pre_split index idx_ab regions").Rows(), HasLen, 4)
+	// There are only 10 distinct values of b.
+	tk.MustExec("set @@tidb_pre_split_index_regions = 20")
+	tk.MustExec("alter table t_pre_split add index idx_b(b)")
+	c.Assert(tk.MustQuery("show table t_pre_split index idx_b regions").Rows(), HasLen, 10)
+	tk.MustQuery("select count(*) from t_pre_split use index(idx_b) where b = 3").Check(testkit.Rows("10"))
+	tk.MustExec("admin check table t_pre_split")
+
+	tk.MustExec("set @@tidb_pre_split_index_regions = 0")
+	tk.MustExec("alter table t_pre_split add index idx_ba(b, a)")
+	c.Assert(tk.MustQuery("show table t_pre_split index idx_ba regions").Rows(), HasLen, 1)
+}
+
+func (s *testDBSuite2) TestAddForeignKeyWithIndex(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists fk_child, fk_parent")
+	tk.MustExec("create table fk_parent (id int primary key)")
+	tk.MustExec("create table fk_child (id int, pid int)")
+	tk.MustExec("insert into fk_child values (1, 1), (2, 2)")
+	defer tk.MustExec("drop table if exists fk_child, fk_parent")
+
+	// The index used by the foreign key is rolled back with it.
+	var (
+		checkErr  error
+		cancelled bool
+	)
+	hook := &ddl.TestDDLCallback{Do: s.dom}
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if cancelled || checkErr != nil || job.Type != model.ActionMultiSchemaChange || job.SchemaState != model.StateWriteReorganization || job.IsCancelling() {
+			return
+		}
+		txn, err := s.store.Begin()
+		if err != nil {
+			checkErr = errors.Trace(err)
+			return
+		}
+		errs, err := admin.CancelJobs(txn, []int64{job.ID})
+		if err != nil {
+			checkErr = errors.Trace(err)
+			return
+		}
+		if errs[0] != nil {
+			checkErr = errors.Trace(errs[0])
+			return
+		}
+		checkErr = txn.Commit(context.Background())
+		cancelled = checkErr == nil
+	}
+	originalHook := s.dom.DDL().GetHook()
+	s.dom.DDL().(ddl.DDLForTest).SetHook(hook)
+	err := tk.ExecToErr("alter table fk_child add constraint fk_pid foreign key (pid) references fk_parent(id)")
+	s.dom.DDL().(ddl.DDLForTest).SetHook(originalHook)
+	c.Assert(checkErr, IsNil)
+	c.Assert(cancelled, IsTrue)
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[ddl:8214]Cancelled DDL job")
+	tk.MustQuery("select count(*) from information_schema.statistics where table_name = 'fk_child'").Check(testkit.Rows("0"))
+	tk.MustQuery("select count(*) from information_schema.referential_constraints where table_name = 'fk_child'").Check(testkit.Rows("0"))
+
+	// The index and the foreign key are added in one job.
+	tk.MustExec("alter table fk_child add constraint fk_pid foreign key (pid) references fk_parent(id)")
+	c.Assert(tk.MustQuery("admin show ddl jobs 1").Rows()[0][3], Equals, "alter table multi-schema change")
+	tk.MustQuery("select index_name, column_name from information_schema.statistics where table_name = 'fk_child'").Check(testkit.Rows("fk_pid pid"))
+	tk.MustQuery("select constraint_name from information_schema.referential_constraints where table_name = 'fk_child'").Check(testkit.Rows("fk_pid"))
+	tk.MustExec("admin check table fk_child")
+}
+
 func (s *testDBSuite3) TestFKOnGeneratedColumns(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -3459,6 +3962,50 @@ func (s *testDBSuite1) TestRenameMultiTables(c *C) {
 	tk.MustExec("drop database test")
 }
 
+func (s *testDBSuite1) TestRenameMultiTablesAtomic(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("create database if not exists test_rename_db1")
+	tk.MustExec("create database if not exists test_rename_db2")
+	defer tk.MustExec("drop database test_rename_db1")
+	defer tk.MustExec("drop database test_rename_db2")
+	tk.MustExec("use test_rename_db1")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("create table t2 (b int)")
+	tk.MustExec("insert into t1 values (1)")
+	tk.MustExec("insert into t2 values (2)")
+	ctx := tk.Se.(sessionctx.Context)
+	getTableID := func(db, tbl string) int64 {
+		t, err := domain.GetDomain(ctx).InfoSchema().TableByName(model.NewCIStr(db), model.NewCIStr(tbl))
+		c.Assert(err, IsNil)
+		return t.Meta().ID
+	}
+	tblID1, tblID2 := getTableID("test_rename_db1", "t1"), getTableID("test_rename_db1", "t2")
+
+	// Swap two tables in one schema version.
+	ver := domain.GetDomain(ctx).InfoSchema().SchemaMetaVersion()
+	tk.MustExec("rename table t1 to tmp, t2 to t1, tmp to t2")
+	c.Assert(domain.GetDomain(ctx).InfoSchema().SchemaMetaVersion(), Equals, ver+1)
+	c.Assert(getTableID("test_rename_db1", "t1"), Equals, tblID2)
+	c.Assert(getTableID("test_rename_db1", "t2"), Equals, tblID1)
+	tk.MustQuery("select * from t1").Check(testkit.Rows("2"))
+	tk.MustQuery("select * from t2").Check(testkit.Rows("1"))
+	tk.MustQuery("show tables").Check(testkit.Rows("t1", "t2"))
+
+	// A table can pass through another database.
+	tk.MustExec("rename table test_rename_db1.t1 to test_rename_db2.tmp, test_rename_db2.tmp to test_rename_db1.t3")
+	c.Assert(getTableID("test_rename_db1", "t3"), Equals, tblID2)
+	tk.MustQuery("show tables from test_rename_db2").Check(testkit.Rows())
+	tk.MustExec("insert into t3 values (3)")
+	tk.MustQuery("select * from t3").Check(testkit.Rows("2", "3"))
+
+	// Nothing is renamed if one of the renames fails.
+	tk.MustGetErrCode("rename table t2 to t4, t_not_exist to t5", errno.ErrFileNotFound)
+	tk.MustGetErrCode("rename table t2 to t4, t3 to t4", errno.ErrTableExists)
+	tk.MustQuery("show tables").Check(testkit.Rows("t2", "t3"))
+	c.Assert(getTableID("test_rename_db1", "t2"), Equals, tblID1)
+	c.Assert(getTableID("test_rename_db1", "t3"), Equals, tblID2)
+}
+
 func (s *testDBSuite2) TestAddNotNullColumn(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test_db")
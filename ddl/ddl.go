@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/parser/mysql"
 	pumpcli "github.com/pingcap/tidb-tools/tidb-binlog/pump_client"
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/ddl/placement"
 	"github.com/pingcap/tidb/ddl/util"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
@@ -95,6 +96,7 @@ type DDL interface {
 	AlterSchema(ctx sessionctx.Context, stmt *ast.AlterDatabaseStmt) error
 	DropSchema(ctx sessionctx.Context, schema model.CIStr) error
 	CreateTable(ctx sessionctx.Context, stmt *ast.CreateTableStmt) error
+	CreateTableAsSelect(ctx sessionctx.Context, stmt *ast.CreateTableStmt, insertSQL string) error
 	CreateView(ctx sessionctx.Context, stmt *ast.CreateViewStmt) error
 	DropTable(ctx sessionctx.Context, tableIdent ast.Ident) (err error)
 	RecoverTable(ctx sessionctx.Context, recoverInfo *RecoverInfo) (err error)
@@ -114,6 +116,11 @@ type DDL interface {
 	CreateSequence(ctx sessionctx.Context, stmt *ast.CreateSequenceStmt) error
 	DropSequence(ctx sessionctx.Context, tableIdent ast.Ident, ifExists bool) (err error)
 	AlterSequence(ctx sessionctx.Context, stmt *ast.AlterSequenceStmt) error
+	CreatePlacementPolicy(ctx sessionctx.Context, policy *placement.Policy, ifNotExists bool) error
+	AlterPlacementPolicy(ctx sessionctx.Context, policy *placement.Policy) error
+	DropPlacementPolicy(ctx sessionctx.Context, name model.CIStr, ifExists bool) error
+	AlterTablePlacementPolicy(ctx sessionctx.Context, ident ast.Ident, partitionName, policyName model.CIStr) error
+	RepairIndex(ctx sessionctx.Context, ident ast.Ident, indexName model.CIStr) error
 
 	// CreateSchemaWithInfo creates a database (schema) given its database info.
 	//
@@ -174,6 +181,8 @@ type DDL interface {
 type limitJobTask struct {
 	job *model.Job
 	err chan error
+	// workerTp is the type of the worker who handles the job.
+	workerTp workerType
 }
 
 // ddl is used to handle the statements that define the structure or schema of the database.
@@ -342,11 +351,15 @@ func (d *ddl) Start(ctxPool *pools.ResourcePool) error {
 			return errors.Trace(err)
 		}
 
-		d.workers = make(map[workerType]*worker, 2)
+		d.workers = make(map[workerType]*worker, 2+meta.TableJobListKeyCnt)
 		d.sessPool = newSessionPool(ctxPool)
 		d.delRangeMgr = d.newDeleteRangeManager(ctxPool == nil)
 		d.workers[generalWorker] = newWorker(d.ctx, generalWorker, d.sessPool, d.delRangeMgr)
 		d.workers[addIdxWorker] = newWorker(d.ctx, addIdxWorker, d.sessPool, d.delRangeMgr)
+		for i := 0; i < meta.TableJobListKeyCnt; i++ {
+			tp := tableWorker + workerType(i)
+			d.workers[tp] = newWorker(d.ctx, tp, d.sessPool, d.delRangeMgr)
+		}
 		for _, worker := range d.workers {
 			worker.wg.Add(1)
 			w := worker
@@ -486,19 +499,13 @@ func getJobCheckInterval(job *model.Job, i int) (time.Duration, bool) {
 	}
 }
 
-func (d *ddl) asyncNotifyWorker(job *model.Job) {
+func (d *ddl) asyncNotifyWorker(job *model.Job, workerTp workerType) {
 	// If the workers don't run, we needn't to notify workers.
 	if !RunWorker {
 		return
 	}
 
-	var worker *worker
-	jobTp := job.Type
-	if jobTp == model.ActionAddIndex || jobTp == model.ActionAddPrimaryKey {
-		worker = d.workers[addIdxWorker]
-	} else {
-		worker = d.workers[generalWorker]
-	}
+	worker := d.workers[workerTp]
 	if d.ownerManager.IsOwner() {
 		asyncNotify(worker.ddlJobCh)
 	} else {
@@ -516,14 +523,31 @@ func updateTickerInterval(ticker *time.Ticker, lease time.Duration, job *model.J
 	return time.NewTicker(chooseLeaseTime(lease, interval))
 }
 
+// isConcurrentDDLEnabled reports whether the jobs of different tables can run in the table job queues concurrently.
+func isConcurrentDDLEnabled(ctx sessionctx.Context) bool {
+	// The global variables are unavailable when bootstrapping.
+	if ctx.GetSessionVars().GlobalVarsAccessor == nil {
+		return false
+	}
+	val, err := variable.GetGlobalSystemVar(ctx.GetSessionVars(), variable.TiDBEnableConcurrentDDL)
+	if err != nil {
+		return false
+	}
+	return variable.TiDBOptOn(val)
+}
+
 // doDDLJob will return
 // - nil: found in history DDL job and no job error
 // - context.Cancel: job has been sent to worker, but not found in history DDL job before cancel
 // - other: found in history DDL job and return that job error
 func (d *ddl) doDDLJob(ctx sessionctx.Context, job *model.Job) error {
+	// The job is a sub-job of the multi-schema change job, it runs with the others later.
+	if collector, ok := ctx.Value(multiSchemaChangeKey).(*subJobCollector); ok {
+		return collector.appendSubJob(job)
+	}
 	// Get a global job ID and put the DDL job in the queue.
 	job.Query, _ = ctx.Value(sessionctx.QueryString).(string)
-	task := &limitJobTask{job, make(chan error)}
+	task := &limitJobTask{job, make(chan error), getJobWorkerType(job, isConcurrentDDLEnabled(ctx))}
 	d.limitJobCh <- task
 	// worker should restart to continue handling tasks in limitJobCh, and send back through task.err
 	err := <-task.err
@@ -531,7 +555,7 @@ func (d *ddl) doDDLJob(ctx sessionctx.Context, job *model.Job) error {
 	ctx.GetSessionVars().StmtCtx.IsDDLJobInQueue = true
 
 	// Notice worker that we push a new job and wait the job done.
-	d.asyncNotifyWorker(job)
+	d.asyncNotifyWorker(job, task.workerTp)
 	logutil.BgLogger().Info("[ddl] start DDL job", zap.String("job", job.String()), zap.String("query", job.Query))
 
 	var historyJob *model.Job
@@ -587,6 +611,10 @@ func (d *ddl) doDDLJob(ctx sessionctx.Context, job *model.Job) error {
 					}
 				}
 			}
+			if historyJob.Type == model.ActionCreateTableAsSelect {
+				// The rows inserted by `CREATE TABLE ... SELECT` are reported as the affected rows like MySQL.
+				ctx.GetSessionVars().StmtCtx.AddAffectedRows(uint64(historyJob.GetRowCount()))
+			}
 			logutil.BgLogger().Info("[ddl] DDL job is finished", zap.Int64("jobID", jobID))
 			return nil
 		}
@@ -49,6 +49,7 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/ttl"
 	"github.com/pingcap/tidb/types"
 	driver "github.com/pingcap/tidb/types/parser_driver"
 	"github.com/pingcap/tidb/util"
@@ -57,6 +58,9 @@ import (
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/dbterror"
 	"github.com/pingcap/tidb/util/domainutil"
+	"github.com/pingcap/tidb/util/encoding"
+	"github.com/pingcap/tidb/util/fulltext"
+	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tidb/util/set"
@@ -1460,7 +1464,19 @@ func buildTableInfo(
 		}
 
 		if constr.Tp == ast.ConstraintFulltext {
-			ctx.GetSessionVars().StmtCtx.AppendWarning(ErrTableCantHandleFt.GenWithStackByArgs())
+			idxInfo, err := buildFulltextIndexInfo(tbInfo, model.NewCIStr(constr.Name), constr.Keys, model.StatePublic)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if constr.Option != nil {
+				idxInfo.Comment, err = validateCommentLength(ctx.GetSessionVars(), idxInfo.Name.String(), constr.Option)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				idxInfo.Invisible = constr.Option.Visibility == ast.IndexVisibilityInvisible
+			}
+			idxInfo.ID = allocateIndexID(tbInfo)
+			tbInfo.Indices = append(tbInfo.Indices, idxInfo)
 			continue
 		}
 		if constr.Tp == ast.ConstraintCheck {
@@ -1506,6 +1522,15 @@ func buildTableInfo(
 		idxInfo.ID = allocateIndexID(tbInfo)
 		tbInfo.Indices = append(tbInfo.Indices, idxInfo)
 	}
+	for _, fk := range tbInfo.ForeignKeys {
+		idxInfo, err := buildFKIndexInfo(tbInfo, fk)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if idxInfo != nil {
+			tbInfo.Indices = append(tbInfo.Indices, idxInfo)
+		}
+	}
 	if tbInfo.IsCommonHandle {
 		// Ensure tblInfo's each non-unique secondary-index's len + primary-key's len <= MaxIndexLength for clustered index table.
 		var pkLen, idxLen int
@@ -1518,6 +1543,10 @@ func buildTableInfo(
 				// Only need check for non-unique secondary-index.
 				continue
 			}
+			if fulltext.IsIndex(idx) {
+				// The entries of the FULLTEXT index are the words, which are limited by fulltext.MaxTokenSize.
+				continue
+			}
 			idxLen, err = indexColumnsLen(tbInfo.Columns, idx.Columns)
 			if err != nil {
 				return
@@ -1619,6 +1648,9 @@ func checkTableInfoValidWithStmt(ctx sessionctx.Context, tbInfo *model.TableInfo
 		return errors.Trace(err)
 	}
 	if tbInfo.Partition != nil && s.Partition != nil {
+		if err := checkFulltextIndexOnPartitionedTable(tbInfo); err != nil {
+			return errors.Trace(err)
+		}
 		if err := checkPartitionDefinitionConstraints(ctx, tbInfo); err != nil {
 			return errors.Trace(err)
 		}
@@ -1632,6 +1664,16 @@ func checkTableInfoValidWithStmt(ctx sessionctx.Context, tbInfo *model.TableInfo
 	return nil
 }
 
+// checkFulltextIndexOnPartitionedTable checks there is no FULLTEXT index in the partitioned table.
+func checkFulltextIndexOnPartitionedTable(tbInfo *model.TableInfo) error {
+	for _, idx := range tbInfo.Indices {
+		if fulltext.IsIndex(idx) {
+			return ErrFulltextNotSupportedWithPartitioning
+		}
+	}
+	return nil
+}
+
 func checkPartitionDefinitionConstraints(ctx sessionctx.Context, tbInfo *model.TableInfo) error {
 	var err error
 	if err = checkPartitionNameUnique(tbInfo.Partition); err != nil {
@@ -1764,6 +1806,12 @@ func buildTableInfoWithStmt(ctx sessionctx.Context, s *ast.CreateTableStmt, dbCh
 	if err = handleTableOptions(s.Options, tbInfo); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if tbInfo.TTLInfo, err = getTTLInfoInOptions(s.Options, nil); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = ttl.CheckTTLInfo(tbInfo); err != nil {
+		return nil, errors.Trace(err)
+	}
 	return tbInfo, nil
 }
 
@@ -1788,6 +1836,19 @@ func (d *ddl) assignPartitionIDs(defs []model.PartitionDefinition) error {
 }
 
 func (d *ddl) CreateTable(ctx sessionctx.Context, s *ast.CreateTableStmt) (err error) {
+	return d.createTable(ctx, s, "")
+}
+
+// CreateTableAsSelect creates the table of a `CREATE TABLE ... SELECT` statement, the rows are inserted
+// by insertSQL before the table becomes public.
+func (d *ddl) CreateTableAsSelect(ctx sessionctx.Context, s *ast.CreateTableStmt, insertSQL string) error {
+	if s.IsTemporary {
+		return errors.New("the local temporary table created by select isn't created by a DDL job")
+	}
+	return d.createTable(ctx, s, insertSQL)
+}
+
+func (d *ddl) createTable(ctx sessionctx.Context, s *ast.CreateTableStmt, insertSQL string) (err error) {
 	ident := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
 	is := d.GetInfoSchemaWithInterceptor(ctx)
 	schema, ok := is.SchemaByName(ident.Schema)
@@ -1802,7 +1863,7 @@ func (d *ddl) CreateTable(ctx sessionctx.Context, s *ast.CreateTableStmt) (err e
 		if !ok {
 			return infoschema.ErrTableNotExists.GenWithStackByArgs(referIdent.Schema, referIdent.Name)
 		}
-		referTbl, err = is.TableByName(referIdent.Schema, referIdent.Name)
+		referTbl, err = infoschema.AttachLocalTemporaryTables(ctx.GetSessionVars(), is).TableByName(referIdent.Schema, referIdent.Name)
 		if err != nil {
 			return infoschema.ErrTableNotExists.GenWithStackByArgs(referIdent.Schema, referIdent.Name)
 		}
@@ -1813,7 +1874,12 @@ func (d *ddl) CreateTable(ctx sessionctx.Context, s *ast.CreateTableStmt) (err e
 	if s.ReferTable != nil {
 		tbInfo, err = buildTableInfoWithLike(ident, referTbl.Meta())
 	} else {
+		var withGIPK bool
+		s, withGIPK = withGeneratedInvisiblePK(ctx, s)
 		tbInfo, err = buildTableInfoWithStmt(ctx, s, schema.Charset, schema.Collate)
+		if err == nil && withGIPK {
+			err = hideGeneratedInvisiblePK(tbInfo)
+		}
 	}
 	if err != nil {
 		return errors.Trace(err)
@@ -1822,13 +1888,66 @@ func (d *ddl) CreateTable(ctx sessionctx.Context, s *ast.CreateTableStmt) (err e
 	if err = checkTableInfoValidWithStmt(ctx, tbInfo, s); err != nil {
 		return err
 	}
+	if err = checkTableForeignKeys(is, schema.Name, tbInfo, s.Constraints); err != nil {
+		return err
+	}
 
 	onExist := OnExistError
 	if s.IfNotExists {
 		onExist = OnExistIgnore
 	}
 
-	return d.CreateTableWithInfo(ctx, schema.Name, tbInfo, onExist, false /*tryRetainID*/)
+	if s.IsTemporary {
+		return d.createLocalTemporaryTable(ctx, schema, tbInfo, onExist)
+	}
+	return d.createTableWithInfo(ctx, schema.Name, tbInfo, onExist, insertSQL)
+}
+
+// withGeneratedInvisiblePK returns a copy of the statement with the generated invisible primary key
+// prepended if `tidb_enable_generated_invisible_primary_key` is enabled and the table has no primary key.
+// The generated key is an AUTO_RANDOM clustered primary key, which avoids the write hotspot of `_tidb_rowid`.
+func withGeneratedInvisiblePK(ctx sessionctx.Context, s *ast.CreateTableStmt) (*ast.CreateTableStmt, bool) {
+	if !ctx.GetSessionVars().EnableGeneratedInvisiblePK || s.IsTemporary || s.Partition != nil {
+		return s, false
+	}
+	for _, colDef := range s.Cols {
+		// AUTO_RANDOM is incompatible with AUTO_INCREMENT columns.
+		if containsColumnOption(colDef, ast.ColumnOptionPrimaryKey) || containsColumnOption(colDef, ast.ColumnOptionAutoIncrement) {
+			return s, false
+		}
+	}
+	for _, constr := range s.Constraints {
+		if constr.Tp == ast.ConstraintPrimaryKey {
+			return s, false
+		}
+	}
+	pkDef := &ast.ColumnDef{
+		Name: &ast.ColumnName{Name: model.NewCIStr(tables.GeneratedInvisiblePKName)},
+		Tp:   field_types.NewFieldType(mysql.TypeLonglong),
+		Options: []*ast.ColumnOption{
+			{Tp: ast.ColumnOptionPrimaryKey, PrimaryKeyTp: model.PrimaryKeyTypeClustered},
+			{Tp: ast.ColumnOptionAutoRandom, AutoRandomBitLength: types.UnspecifiedLength},
+		},
+	}
+	stmt := *s
+	stmt.Cols = append([]*ast.ColumnDef{pkDef}, s.Cols...)
+	return &stmt, true
+}
+
+// hideGeneratedInvisiblePK hides the generated invisible primary key built by withGeneratedInvisiblePK.
+func hideGeneratedInvisiblePK(tbInfo *model.TableInfo) error {
+	pkCol := model.FindColumnInfo(tbInfo.Columns, tables.GeneratedInvisiblePKName)
+	// Hidden columns of the expression indexes are dropped with the indexes,
+	// so the generated invisible primary key can't be used in any other indexes.
+	for _, idx := range tbInfo.Indices {
+		for _, idxCol := range idx.Columns {
+			if idxCol.Offset == pkCol.Offset {
+				return errKeyColumnDoesNotExits.GenWithStack("column does not exist: %s", pkCol.Name)
+			}
+		}
+	}
+	pkCol.Hidden = true
+	return nil
 }
 
 func (d *ddl) CreateTableWithInfo(
@@ -1837,6 +1956,19 @@ func (d *ddl) CreateTableWithInfo(
 	tbInfo *model.TableInfo,
 	onExist OnExist,
 	tryRetainID bool,
+) (err error) {
+	// FIXME: Implement `tryRetainID`
+	return d.createTableWithInfo(ctx, dbName, tbInfo, onExist, "")
+}
+
+// createTableWithInfo creates the table by a DDL job, if insertSQL isn't empty, the table is created
+// by `CREATE TABLE ... SELECT` and the rows are inserted by insertSQL in the job.
+func (d *ddl) createTableWithInfo(
+	ctx sessionctx.Context,
+	dbName model.CIStr,
+	tbInfo *model.TableInfo,
+	onExist OnExist,
+	insertSQL string,
 ) (err error) {
 	is := d.GetInfoSchemaWithInterceptor(ctx)
 	schema, ok := is.SchemaByName(dbName)
@@ -1867,7 +1999,6 @@ func (d *ddl) CreateTableWithInfo(
 		}
 	}
 
-	// FIXME: Implement `tryRetainID`
 	if err := d.assignTableID(tbInfo); err != nil {
 		return errors.Trace(err)
 	}
@@ -1890,6 +2021,9 @@ func (d *ddl) CreateTableWithInfo(
 		args = append(args, onExist == OnExistReplace, oldViewTblID)
 	case tbInfo.Sequence != nil:
 		actionType = model.ActionCreateSequence
+	case insertSQL != "":
+		actionType = model.ActionCreateTableAsSelect
+		args = append(args, insertSQL)
 	default:
 		actionType = model.ActionCreateTable
 	}
@@ -1902,6 +2036,14 @@ func (d *ddl) CreateTableWithInfo(
 		BinlogInfo: &model.HistoryInfo{},
 		Args:       args,
 	}
+	if actionType == model.ActionCreateTableAsSelect {
+		job.ReorgMeta = &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+		}
+		job.Priority = ctx.GetSessionVars().DDLReorgPriority
+	}
 
 	err = d.doDDLJob(ctx, job)
 	if err != nil {
@@ -1910,6 +2052,9 @@ func (d *ddl) CreateTableWithInfo(
 			ctx.GetSessionVars().StmtCtx.AppendNote(err)
 			err = nil
 		}
+	} else if actionType == model.ActionCreateTableAsSelect {
+		// The auto IDs are rebased by the job before the rows are inserted.
+		d.preSplitAndScatter(ctx, tbInfo, tbInfo.GetPartitionInfo())
 	} else if actionType == model.ActionCreateTable {
 		d.preSplitAndScatter(ctx, tbInfo, tbInfo.GetPartitionInfo())
 		if tbInfo.AutoIncID > 1 {
@@ -2236,6 +2381,48 @@ func handleTableOptions(options []*ast.TableOption, tbInfo *model.TableInfo) err
 	return nil
 }
 
+// getTTLInfoInOptions applies the TTL options to a copy of the TTL attributes of the table,
+// and returns nil if there are neither the TTL options nor the TTL attributes.
+func getTTLInfoInOptions(options []*ast.TableOption, ttlInfo *model.TTLInfo) (*model.TTLInfo, error) {
+	if ttlInfo != nil {
+		ttlInfo = ttlInfo.Clone()
+	}
+	// The TTL option may follow the other TTL options, so it's handled first.
+	for _, op := range options {
+		if op.Tp != ast.TableOptionTTL {
+			continue
+		}
+		if op.UintValue > uint64(math.MaxInt64) {
+			return nil, errors.Errorf("the TTL interval %d overflows int64", op.UintValue)
+		}
+		if ttlInfo == nil {
+			ttlInfo = &model.TTLInfo{Enable: true}
+		}
+		ttlInfo.ColumnName = op.ColumnName.Name
+		ttlInfo.IntervalValue = int64(op.UintValue)
+		ttlInfo.IntervalUnit = op.TimeUnitValue.Unit.String()
+	}
+	for _, op := range options {
+		switch op.Tp {
+		case ast.TableOptionTTLEnable, ast.TableOptionTTLJobWindowStart, ast.TableOptionTTLJobWindowEnd:
+			if ttlInfo == nil {
+				return nil, errors.Errorf("the TTL option is required to set the other TTL options")
+			}
+		default:
+			continue
+		}
+		switch op.Tp {
+		case ast.TableOptionTTLEnable:
+			ttlInfo.Enable = op.BoolValue
+		case ast.TableOptionTTLJobWindowStart:
+			ttlInfo.JobWindowStart = op.StrValue
+		case ast.TableOptionTTLJobWindowEnd:
+			ttlInfo.JobWindowEnd = op.StrValue
+		}
+	}
+	return ttlInfo, nil
+}
+
 func shardingBits(tblInfo *model.TableInfo) uint64 {
 	if tblInfo.ShardRowIDBits > 0 {
 		return tblInfo.ShardRowIDBits
@@ -2389,22 +2576,16 @@ func (d *ddl) AlterTable(ctx sessionctx.Context, ident ast.Ident, specs []*ast.A
 		if isSameTypeMultiSpecs(validSpecs) {
 			switch validSpecs[0].Tp {
 			case ast.AlterTableAddColumns:
-				err = d.AddColumns(ctx, ident, validSpecs)
+				return errors.Trace(d.AddColumns(ctx, ident, validSpecs))
 			case ast.AlterTableDropColumn:
-				err = d.DropColumns(ctx, ident, validSpecs)
-			default:
-				return errRunMultiSchemaChanges
+				return errors.Trace(d.DropColumns(ctx, ident, validSpecs))
 			}
-			if err != nil {
-				return errors.Trace(err)
-			}
-			return nil
 		}
-		return errRunMultiSchemaChanges
+		return errors.Trace(d.multiSchemaChange(ctx, ident, validSpecs))
 	}
 
 	for _, spec := range validSpecs {
-		var handledCharsetOrCollate bool
+		var handledCharsetOrCollate, handledTTL bool
 		switch spec.Tp {
 		case ast.AlterTableAddColumns:
 			if len(spec.NewColumns) != 1 {
@@ -2417,7 +2598,7 @@ func (d *ddl) AlterTable(ctx sessionctx.Context, ident ast.Ident, specs []*ast.A
 		case ast.AlterTableCoalescePartitions:
 			err = d.CoalescePartitions(ctx, ident, spec)
 		case ast.AlterTableReorganizePartition:
-			err = errors.Trace(errUnsupportedReorganizePartition)
+			err = d.ReorganizePartitions(ctx, ident, spec)
 		case ast.AlterTableCheckPartitions:
 			err = errors.Trace(errUnsupportedCheckPartition)
 		case ast.AlterTableRebuildPartition:
@@ -2426,6 +2607,8 @@ func (d *ddl) AlterTable(ctx sessionctx.Context, ident ast.Ident, specs []*ast.A
 			err = errors.Trace(errUnsupportedOptimizePartition)
 		case ast.AlterTableRemovePartitioning:
 			err = errors.Trace(errUnsupportedRemovePartition)
+		case ast.AlterTableRemoveTTL:
+			err = d.AlterTableRemoveTTL(ctx, ident)
 		case ast.AlterTableRepairPartition:
 			err = errors.Trace(errUnsupportedRepairPartition)
 		case ast.AlterTableDropColumn:
@@ -2470,13 +2653,14 @@ func (d *ddl) AlterTable(ctx sessionctx.Context, ident ast.Ident, specs []*ast.A
 				err = d.CreateIndex(ctx, ident, ast.IndexKeyTypeUnique, model.NewCIStr(constr.Name),
 					spec.Constraint.Keys, constr.Option, false) // IfNotExists should be not applied
 			case ast.ConstraintForeignKey:
-				// NOTE: we do not handle `symbol` and `index_name` well in the parser and we do not check ForeignKey already exists,
+				// NOTE: we do not handle `symbol` and `index_name` well in the parser,
 				// so we just also ignore the `if not exists` check.
 				err = d.CreateForeignKey(ctx, ident, model.NewCIStr(constr.Name), spec.Constraint.Keys, spec.Constraint.Refer)
 			case ast.ConstraintPrimaryKey:
 				err = d.CreatePrimaryKey(ctx, ident, model.NewCIStr(constr.Name), spec.Constraint.Keys, constr.Option)
 			case ast.ConstraintFulltext:
-				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrTableCantHandleFt)
+				err = d.CreateIndex(ctx, ident, ast.IndexKeyTypeFullText, model.NewCIStr(constr.Name),
+					spec.Constraint.Keys, constr.Option, constr.IfNotExists)
 			case ast.ConstraintCheck:
 				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("ADD CONSTRAINT CHECK"))
 			default:
@@ -2503,6 +2687,12 @@ func (d *ddl) AlterTable(ctx sessionctx.Context, ident ast.Ident, specs []*ast.A
 			} else {
 				err = errors.New("alter partition alter placement is experimental and it is switched off by tidb_enable_alter_placement")
 			}
+		case ast.AlterTablePlacementPolicy:
+			var partitionName model.CIStr
+			if len(spec.PartitionNames) > 0 {
+				partitionName = spec.PartitionNames[0]
+			}
+			err = d.AlterTablePlacementPolicy(ctx, ident, partitionName, spec.PolicyName)
 		case ast.AlterTablePartition:
 			// Prevent silent succeed if user executes ALTER TABLE x PARTITION BY ...
 			err = errors.New("alter table partition is unsupported")
@@ -2541,6 +2731,13 @@ func (d *ddl) AlterTable(ctx sessionctx.Context, ident ast.Ident, specs []*ast.A
 					needsOverwriteCols := needToOverwriteColCharset(spec.Options)
 					err = d.AlterTableCharsetAndCollate(ctx, ident, toCharset, toCollate, needsOverwriteCols)
 					handledCharsetOrCollate = true
+				case ast.TableOptionTTL, ast.TableOptionTTLEnable, ast.TableOptionTTLJobWindowStart, ast.TableOptionTTLJobWindowEnd:
+					// All the TTL options are applied by one job.
+					if handledTTL {
+						continue
+					}
+					err = d.AlterTableTTLInfo(ctx, ident, spec.Options)
+					handledTTL = true
 				default:
 					err = errUnsupportedAlterTableOption
 				}
@@ -2670,7 +2867,8 @@ func (d *ddl) getSchemaAndTableByIdent(ctx sessionctx.Context, tableIdent ast.Id
 		return nil, nil, infoschema.ErrDatabaseNotExists.GenWithStackByArgs(tableIdent.Schema)
 	}
 	t, err = is.TableByName(tableIdent.Schema, tableIdent.Name)
-	if err != nil {
+	// The table created by `CREATE TABLE ... SELECT` is invisible until its rows are inserted.
+	if err != nil || t.Meta().State == model.StateWriteReorganization {
 		return nil, nil, infoschema.ErrTableNotExists.GenWithStackByArgs(tableIdent.Schema, tableIdent.Name)
 	}
 	return schema, t, nil
@@ -3000,6 +3198,71 @@ func (d *ddl) CoalescePartitions(ctx sessionctx.Context, ident ast.Ident, spec *
 	return errors.Trace(err)
 }
 
+// ReorganizePartitions reorganizes the given range or list partitions into the new partitions, the rows
+// are moved into the new partitions by the reorganization.
+func (d *ddl) ReorganizePartitions(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
+	is := d.infoHandle.Get()
+	schema, ok := is.SchemaByName(ident.Schema)
+	if !ok {
+		return errors.Trace(infoschema.ErrDatabaseNotExists.GenWithStackByArgs(schema))
+	}
+	t, err := is.TableByName(ident.Schema, ident.Name)
+	if err != nil {
+		return errors.Trace(infoschema.ErrTableNotExists.GenWithStackByArgs(ident.Schema, ident.Name))
+	}
+	meta := t.Meta()
+	pi := meta.GetPartitionInfo()
+	if pi == nil {
+		return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	}
+	if pi.Type != model.PartitionTypeRange && pi.Type != model.PartitionTypeList {
+		return errOnlyOnRangeListPartition.GenWithStackByArgs("REORGANIZE")
+	}
+	if spec.OnAllPartitions {
+		return errors.Trace(ErrReorgNoParam)
+	}
+	if hasGlobalIndex(meta) || meta.TiFlashReplica != nil {
+		return errors.Trace(errUnsupportedReorganizePartition)
+	}
+
+	partNames := make([]string, len(spec.PartitionNames))
+	for i, partCIName := range spec.PartitionNames {
+		partNames[i] = partCIName.L
+	}
+	partInfo, err := buildAddedPartitionInfo(ctx, meta, spec)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err = d.assignPartitionIDs(partInfo.Definitions); err != nil {
+		return errors.Trace(err)
+	}
+	if err = checkReorganizePartition(ctx, meta, partNames, partInfo); err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionReorganizePartition,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{partNames, partInfo},
+		ReorgMeta: &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+		},
+		Priority: ctx.GetSessionVars().DDLReorgPriority,
+	}
+
+	err = d.doDDLJob(ctx, job)
+	if err == nil {
+		d.preSplitAndScatter(ctx, meta, partInfo)
+	}
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 func (d *ddl) TruncateTablePartition(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
 	is := d.infoHandle.Get()
 	schema, ok := is.SchemaByName(ident.Schema)
@@ -3465,6 +3728,53 @@ func checkModifyCharsetAndCollation(toCharset, toCollate, origCharset, origColla
 	return nil
 }
 
+// checkConvertCharsetAndCollation is like checkModifyCharsetAndCollation, besides, it allows converting
+// the data between the character sets supported by the conversions, the data is checked in the target
+// character set by the reorganization of the DDL job.
+func checkConvertCharsetAndCollation(toCharset, toCollate, origCharset, origCollate string, needRewriteCollationData bool) error {
+	if toCharset == origCharset || !isConvertibleCharset(origCharset) || !isConvertibleCharset(toCharset) {
+		return checkModifyCharsetAndCollation(toCharset, toCollate, origCharset, origCollate, needRewriteCollationData)
+	}
+	if !charset.ValidCharsetAndCollation(toCharset, toCollate) {
+		return ErrUnknownCharacterSet.GenWithStack("Unknown character set: '%s', collation: '%s'", toCharset, toCollate)
+	}
+	if needRewriteCollationData && collate.NewCollationEnabled() && !collate.CompatibleCollate(origCollate, toCollate) {
+		return errUnsupportedModifyCollation.GenWithStackByArgs(origCollate, toCollate)
+	}
+	return nil
+}
+
+// isConvertibleCharset reports whether the strings of the non-binary charset can be converted to
+// and from other charsets.
+func isConvertibleCharset(chs string) bool {
+	return chs != charset.CharsetBin && encoding.IsSupported(chs)
+}
+
+// checkConvertColumnCharset checks the default values and the elements of the column can be represented
+// in the target charset.
+func checkConvertColumnCharset(col *model.ColumnInfo, toCharset string) error {
+	if !encoding.NeedConvert(col.Charset, toCharset) {
+		return nil
+	}
+	converter, err := encoding.NewConverter(col.Charset, toCharset)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	vals := make([]string, 0, len(col.Elems)+2)
+	vals = append(vals, col.Elems...)
+	for _, v := range []interface{}{col.GetDefaultValue(), col.GetOriginDefaultValue()} {
+		if str, ok := v.(string); ok {
+			vals = append(vals, str)
+		}
+	}
+	for _, val := range vals {
+		if _, _, err = converter.Convert(nil, hack.Slice(val), encoding.InvalidCharError); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
 // CheckModifyTypeCompatible checks whether changes column type to another is compatible and can be changed.
 // If types are compatible and can be directly changed, nil err will be returned; otherwise the types are incompatible.
 // There are two cases when types incompatible:
@@ -3548,6 +3858,10 @@ func needReorgToChange(origin *types.FieldType, to *types.FieldType) (needOreg b
 	if mysql.HasUnsignedFlag(origin.Flag) != mysql.HasUnsignedFlag(to.Flag) {
 		return true, "can't change unsigned integer to signed or vice versa"
 	}
+	if types.IsString(origin.Tp) && types.IsString(to.Tp) && types.IsTypeVarchar(origin.Tp) != types.IsTypeVarchar(to.Tp) {
+		// The trailing spaces are handled differently, so the data need to be rewritten.
+		return true, "column type conversion between 'varchar' and 'non-varchar'"
+	}
 	return false, ""
 }
 
@@ -3613,15 +3927,20 @@ func checkModifyTypes(ctx sessionctx.Context, origin *types.FieldType, to *types
 			return errUnsupportedModifyColumn.GenWithStackByArgs(msg)
 		}
 	}
-	if types.IsTypeVarchar(origin.Tp) != types.IsTypeVarchar(to.Tp) {
-		unsupportedMsg := "column type conversion between 'varchar' and 'non-varchar' is currently unsupported yet"
-		return errUnsupportedModifyColumn.GenWithStackByArgs(unsupportedMsg)
-	}
 
 	err = checkModifyCharsetAndCollation(to.Charset, to.Collate, origin.Charset, origin.Collate, needRewriteCollationData)
-	// column type change can handle the charset change between these two types in the process of the reorg.
-	if err != nil && errUnsupportedModifyCharset.Equal(err) && canReorg {
-		return nil
+	if err != nil && errUnsupportedModifyCharset.Equal(err) {
+		// column type change can handle the charset change between these two types in the process of the reorg.
+		if canReorg {
+			return nil
+		}
+		if ctx.GetSessionVars().EnableChangeColumnType && needChangeCharsetData(origin, to) {
+			if mysql.HasPriKeyFlag(origin.Flag) {
+				msg := "tidb_enable_change_column_type is true and this column has primary key flag"
+				return errUnsupportedModifyColumn.GenWithStackByArgs(msg)
+			}
+			return nil
+		}
 	}
 	return errors.Trace(err)
 }
@@ -3898,6 +4217,10 @@ func (d *ddl) getModifiableColumnJob(ctx sessionctx.Context, ident ast.Ident, or
 		return nil, err
 	}
 
+	if err = checkModifyTTLColumn(t.Meta(), col.ColumnInfo, newCol.ColumnInfo); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	// As same with MySQL, we don't support modifying the stored status for generated columns.
 	if err = checkModifyGeneratedColumn(t, col, newCol, specNewColumn, spec.Position); err != nil {
 		return nil, errors.Trace(err)
@@ -3964,6 +4287,10 @@ func checkColumnWithIndexConstraint(tbInfo *model.TableInfo, originalCol, newCol
 		if skipCheckIfNotModify && !modified {
 			return
 		}
+		if fulltext.IsIndex(indexInfo) {
+			err = checkFulltextIndexInModifiableColumns(columns, indexInfo.Columns)
+			return
+		}
 		err = checkIndexInModifiableColumns(columns, indexInfo.Columns)
 		if err != nil {
 			return
@@ -4016,6 +4343,20 @@ func checkIndexInModifiableColumns(columns []*model.ColumnInfo, idxColumns []*mo
 	return nil
 }
 
+// checkFulltextIndexInModifiableColumns checks the columns of the FULLTEXT index can still be part of it.
+func checkFulltextIndexInModifiableColumns(columns []*model.ColumnInfo, idxColumns []*model.IndexColumn) error {
+	for _, ic := range idxColumns {
+		col := model.FindColumnInfo(columns, ic.Name.L)
+		if col == nil {
+			return errKeyColumnDoesNotExits.GenWithStack("column does not exist: %s", ic.Name)
+		}
+		if !isFulltextColumn(&col.FieldType) {
+			return ErrBadFtColumn.GenWithStackByArgs(ic.Name)
+		}
+	}
+	return nil
+}
+
 func checkAutoRandom(tableInfo *model.TableInfo, originCol *table.Column, specNewColumn *ast.ColumnDef) (uint64, error) {
 	var oldRandBits uint64
 	if originCol.IsPKHandleColumn(tableInfo) {
@@ -4138,6 +4479,10 @@ func (d *ddl) RenameColumn(ctx sessionctx.Context, ident ast.Ident, spec *ast.Al
 		return errFKIncompatibleColumns.GenWithStackByArgs(oldColName, fkInfo.Name)
 	}
 
+	if ttlInfo := tbl.Meta().TTLInfo; ttlInfo != nil && ttlInfo.ColumnName.L == oldColName.L {
+		return errUnsupportedTTLColumnChange.GenWithStackByArgs("rename", oldColName)
+	}
+
 	// Check generated expression.
 	for _, col := range allCols {
 		if col.GeneratedExpr == nil {
@@ -4283,6 +4628,56 @@ func (d *ddl) AlterTableComment(ctx sessionctx.Context, ident ast.Ident, spec *a
 	return errors.Trace(err)
 }
 
+// AlterTableTTLInfo sets the TTL attributes of the table by the TTL options.
+func (d *ddl) AlterTableTTLInfo(ctx sessionctx.Context, ident ast.Ident, options []*ast.TableOption) error {
+	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	tblInfo := tb.Meta().Clone()
+	if tblInfo.TTLInfo, err = getTTLInfoInOptions(options, tblInfo.TTLInfo); err != nil {
+		return errors.Trace(err)
+	}
+	if err = ttl.CheckTTLInfo(tblInfo); err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tblInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAlterTTLInfo,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{tblInfo.TTLInfo},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// AlterTableRemoveTTL removes the TTL attributes of the table.
+func (d *ddl) AlterTableRemoveTTL(ctx sessionctx.Context, ident ast.Ident) error {
+	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tb.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAlterTTLRemove,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 // AlterTableAutoIDCache updates the table comment information.
 func (d *ddl) AlterTableAutoIDCache(ctx sessionctx.Context, ident ast.Ident, newCache int64) error {
 	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ident)
@@ -4540,7 +4935,12 @@ func checkAlterTableCharset(tblInfo *model.TableInfo, dbInfo *model.DBInfo, toCh
 		return doNothing, err
 	}
 
-	if err = checkModifyCharsetAndCollation(toCharset, toCollate, origCharset, origCollate, false); err != nil {
+	if !needsOverwriteCols {
+		err = checkModifyCharsetAndCollation(toCharset, toCollate, origCharset, origCollate, false)
+	} else {
+		err = checkConvertCharsetAndCollation(toCharset, toCollate, origCharset, origCollate, false)
+	}
+	if err != nil {
 		return doNothing, err
 	}
 	if !needsOverwriteCols {
@@ -4560,13 +4960,16 @@ func checkAlterTableCharset(tblInfo *model.TableInfo, dbInfo *model.DBInfo, toCh
 		if len(col.Charset) == 0 {
 			continue
 		}
-		if err = checkModifyCharsetAndCollation(toCharset, toCollate, col.Charset, col.Collate, isColumnWithIndex(col.Name.L, tblInfo.Indices)); err != nil {
+		if err = checkConvertCharsetAndCollation(toCharset, toCollate, col.Charset, col.Collate, isColumnWithIndex(col.Name.L, tblInfo.Indices)); err != nil {
 			if strings.Contains(err.Error(), "Unsupported modifying collation") {
 				colErrMsg := "Unsupported converting collation of column '%s' from '%s' to '%s' when index is defined on it."
 				err = errUnsupportedModifyCollation.GenWithStack(colErrMsg, col.Name.L, col.Collate, toCollate)
 			}
 			return doNothing, err
 		}
+		if err = checkConvertColumnCharset(col, toCharset); err != nil {
+			return doNothing, err
+		}
 	}
 	return doNothing, nil
 }
@@ -5038,9 +5441,9 @@ func buildHiddenColumnInfo(ctx sessionctx.Context, indexPartSpecifications []*as
 
 func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.IndexKeyType, indexName model.CIStr,
 	indexPartSpecifications []*ast.IndexPartSpecification, indexOption *ast.IndexOption, ifNotExists bool) error {
-	// not support Spatial and FullText index
-	if keyType == ast.IndexKeyTypeFullText || keyType == ast.IndexKeyTypeSpatial {
-		return errUnsupportedIndexType.GenWithStack("FULLTEXT and SPATIAL index is not supported")
+	// not support Spatial index
+	if keyType == ast.IndexKeyTypeSpatial {
+		return errUnsupportedIndexType.GenWithStack("SPATIAL index is not supported")
 	}
 	unique := keyType == ast.IndexKeyTypeUnique
 	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
@@ -5079,6 +5482,10 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 
 	tblInfo := t.Meta()
 
+	if keyType == ast.IndexKeyTypeFullText {
+		return d.createFulltextIndex(ctx, schema, tblInfo, indexName, indexPartSpecifications, indexOption, ifNotExists)
+	}
+
 	// Build hidden columns if necessary.
 	hiddenCols, err := buildHiddenColumnInfo(ctx, indexPartSpecifications, indexName, t.Meta(), t.Cols())
 	if err != nil {
@@ -5144,7 +5551,7 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 			Warnings:      make(map[errors.ErrorID]*terror.Error),
 			WarningsCount: make(map[errors.ErrorID]int64),
 		},
-		Args:     []interface{}{unique, indexName, indexPartSpecifications, indexOption, hiddenCols, global},
+		Args:     []interface{}{unique, indexName, indexPartSpecifications, indexOption, hiddenCols, global, ctx.GetSessionVars().PreSplitIndexRegions},
 		Priority: ctx.GetSessionVars().DDLReorgPriority,
 	}
 
@@ -5158,6 +5565,46 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	return errors.Trace(err)
 }
 
+// createFulltextIndex adds the FULLTEXT index by the job of adding index, the index type in the option
+// tells the job to build the FULLTEXT index.
+func (d *ddl) createFulltextIndex(ctx sessionctx.Context, schema *model.DBInfo, tblInfo *model.TableInfo, indexName model.CIStr,
+	indexPartSpecifications []*ast.IndexPartSpecification, indexOption *ast.IndexOption, ifNotExists bool) error {
+	// Check before the job is put to the queue, like CreateIndex.
+	if _, err := buildFulltextIndexInfo(tblInfo, indexName, indexPartSpecifications, model.StatePublic); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := validateCommentLength(ctx.GetSessionVars(), indexName.String(), indexOption); err != nil {
+		return errors.Trace(err)
+	}
+	option := &ast.IndexOption{}
+	if indexOption != nil {
+		*option = *indexOption
+	}
+	option.Tp = model.IndexTypeFulltext
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tblInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAddIndex,
+		BinlogInfo: &model.HistoryInfo{},
+		ReorgMeta: &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+		},
+		Args:     []interface{}{false, indexName, indexPartSpecifications, option, []*model.ColumnInfo(nil), false, 0},
+		Priority: ctx.GetSessionVars().DDLReorgPriority,
+	}
+
+	err := d.doDDLJob(ctx, job)
+	if ErrDupKeyName.Equal(err) && ifNotExists {
+		ctx.GetSessionVars().StmtCtx.AppendNote(err)
+		return nil
+	}
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 func buildFKInfo(fkName model.CIStr, keys []*ast.IndexPartSpecification, refer *ast.ReferenceDef, cols []*table.Column, tbInfo *model.TableInfo) (*model.FKInfo, error) {
 	if len(keys) != len(refer.IndexPartSpecifications) {
 		return nil, infoschema.ErrForeignKeyNotMatch.GenWithStackByArgs("foreign key without name")
@@ -5215,9 +5662,15 @@ func buildFKInfo(fkName model.CIStr, keys []*ast.IndexPartSpecification, refer *
 				}
 			}
 		}
-		if table.FindCol(cols, key.Column.Name.O) == nil {
+		col := table.FindCol(cols, key.Column.Name.O)
+		if col == nil {
 			return nil, errKeyColumnDoesNotExits.GenWithStackByArgs(key.Column.Name)
 		}
+		// Check the SET NULL reference options of foreign key on NOT NULL columns.
+		if mysql.HasNotNullFlag(col.Flag) &&
+			(refer.OnDelete.ReferOpt == ast.ReferOptionSetNull || refer.OnUpdate.ReferOpt == ast.ReferOptionSetNull) {
+			return nil, errFkColumnNotNull.GenWithStackByArgs(col.Name, fkName)
+		}
 		fkInfo.Cols[i] = key.Column.Name
 	}
 
@@ -5244,10 +5697,30 @@ func (d *ddl) CreateForeignKey(ctx sessionctx.Context, ti ast.Ident, fkName mode
 		return errors.Trace(infoschema.ErrTableNotExists.GenWithStackByArgs(ti.Schema, ti.Name))
 	}
 
+	if fkName.L == "" {
+		fkNames := make(map[string]bool, len(t.Meta().ForeignKeys))
+		for _, fk := range t.Meta().ForeignKeys {
+			fkNames[fk.Name.L] = true
+		}
+		constr := &ast.Constraint{Tp: ast.ConstraintForeignKey, Keys: keys}
+		setEmptyConstraintName(fkNames, constr, true)
+		fkName = model.NewCIStr(constr.Name)
+	}
+	for _, fk := range t.Meta().ForeignKeys {
+		if fk.Name.L == fkName.L {
+			return infoschema.ErrCannotAddForeign
+		}
+	}
+
 	fkInfo, err := buildFKInfo(fkName, keys, refer, t.Cols(), t.Meta())
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if refTblInfo := getFKReferredTable(is, schema.Name, t.Meta(), refer); refTblInfo != nil {
+		if err = checkFKReferredTable(t.Meta(), fkInfo, refTblInfo); err != nil {
+			return errors.Trace(err)
+		}
+	}
 
 	job := &model.Job{
 		SchemaID:   schema.ID,
@@ -5257,11 +5730,25 @@ func (d *ddl) CreateForeignKey(ctx sessionctx.Context, ti ast.Ident, fkName mode
 		BinlogInfo: &model.HistoryInfo{},
 		Args:       []interface{}{fkInfo},
 	}
+	if hasFKIndex(t.Meta(), fkInfo.Cols, nil) {
+		err = d.doDDLJob(ctx, job)
+		err = d.callHookOnChanged(err)
+		return errors.Trace(err)
+	}
 
-	err = d.doDDLJob(ctx, job)
-	err = d.callHookOnChanged(err)
-	return errors.Trace(err)
-
+	// The index used by the foreign key is created with it in a multi-schema change job,
+	// so the index is rolled back if the foreign key can't be added.
+	collector := newSubJobCollector(t.Meta())
+	ctx.SetValue(multiSchemaChangeKey, collector)
+	err = d.CreateIndex(ctx, ti, ast.IndexKeyTypeNone, getFKIndexName(t.Meta(), fkName), buildFKIndexPartSpecifications(fkInfo.Cols), nil, false)
+	if err == nil {
+		err = d.doDDLJob(ctx, job)
+	}
+	ctx.ClearValue(multiSchemaChangeKey)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(d.doMultiSchemaChangeJob(ctx, schema, t.Meta(), collector.subJobs))
 }
 
 func (d *ddl) DropForeignKey(ctx sessionctx.Context, ti ast.Ident, fkName model.CIStr) error {
@@ -5334,6 +5821,10 @@ func (d *ddl) DropIndex(ctx sessionctx.Context, ti ast.Ident, indexName model.CI
 	if err != nil {
 		return errors.Trace(err)
 	}
+	err = checkDropIndexOnForeignKey(t.Meta(), indexInfo)
+	if err != nil {
+		return errors.Trace(err)
+	}
 
 	jobTp := model.ActionDropIndex
 	if isPK {
@@ -5380,9 +5871,24 @@ func isDroppableColumn(tblInfo *model.TableInfo, colName model.CIStr) error {
 	if fkInfo := getColumnForeignKeyInfo(colName.L, tblInfo.ForeignKeys); fkInfo != nil {
 		return errFkColumnCannotDrop.GenWithStackByArgs(colName, fkInfo.Name)
 	}
+	if tblInfo.TTLInfo != nil && tblInfo.TTLInfo.ColumnName.L == colName.L {
+		return errUnsupportedTTLColumnChange.GenWithStackByArgs("drop", colName)
+	}
 	return nil
 }
 
+// checkModifyTTLColumn checks whether the TTL column can be changed to newCol.
+// The TTL column can't be renamed, and it must be a time column.
+func checkModifyTTLColumn(tblInfo *model.TableInfo, originalCol, newCol *model.ColumnInfo) error {
+	if tblInfo.TTLInfo == nil || tblInfo.TTLInfo.ColumnName.L != originalCol.Name.L {
+		return nil
+	}
+	if newCol.Name.L != originalCol.Name.L {
+		return errUnsupportedTTLColumnChange.GenWithStackByArgs("rename", originalCol.Name)
+	}
+	return ttl.CheckTTLColumn(newCol)
+}
+
 // validateCommentLength checks comment length of table, column, index and partition.
 // If comment length is more than the standard length truncate it
 // and store the comment length upto the standard comment length size.
@@ -6025,34 +6531,12 @@ func buildPlacementSpecs(bundle *placement.Bundle, specs []*ast.PlacementSpec) (
 	return bundle, nil
 }
 
-func (d *ddl) AlterTableAlterPartition(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) (err error) {
-	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ident)
-	if err != nil {
-		return errors.Trace(err)
-	}
-
-	meta := tb.Meta()
-	if meta.Partition == nil {
-		return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
-	}
-
-	partitionID, err := tables.FindPartitionByName(meta, spec.PartitionNames[0].L)
-	if err != nil {
-		return errors.Trace(err)
-	}
-
-	oldBundle := infoschema.GetBundle(d.infoHandle.Get(), []int64{partitionID, meta.ID, schema.ID})
-
-	oldBundle.ID = placement.GroupID(partitionID)
-
-	bundle, err := buildPlacementSpecs(oldBundle, spec.PlacementSpecs)
-	if err != nil {
-		return errors.Trace(err)
-	}
-
+// finishPlacementBundle sets the key range of the physical table to the rules of the bundle, and
+// merges the rules without constraints of the same role.
+func finishPlacementBundle(bundle *placement.Bundle, physicalID int64, index int) {
 	extraCnt := map[placement.PeerRoleType]int{}
-	startKey := hex.EncodeToString(codec.EncodeBytes(nil, tablecodec.GenTableRecordPrefix(partitionID)))
-	endKey := hex.EncodeToString(codec.EncodeBytes(nil, tablecodec.GenTableRecordPrefix(partitionID+1)))
+	startKey := hex.EncodeToString(codec.EncodeBytes(nil, tablecodec.GenTableRecordPrefix(physicalID)))
+	endKey := hex.EncodeToString(codec.EncodeBytes(nil, tablecodec.GenTableRecordPrefix(physicalID+1)))
 	newRules := bundle.Rules[:0]
 	for i, rule := range bundle.Rules {
 		// merge all empty constraints
@@ -6098,9 +6582,36 @@ func (d *ddl) AlterTableAlterPartition(ctx sessionctx.Context, ident ast.Ident,
 		bundle.Index = 0
 		bundle.Override = false
 	} else {
-		bundle.Index = placement.RuleIndexPartition
+		bundle.Index = index
 		bundle.Override = true
 	}
+}
+
+func (d *ddl) AlterTableAlterPartition(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) (err error) {
+	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	meta := tb.Meta()
+	if meta.Partition == nil {
+		return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	}
+
+	partitionID, err := tables.FindPartitionByName(meta, spec.PartitionNames[0].L)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	oldBundle := infoschema.GetBundle(d.infoHandle.Get(), []int64{partitionID, meta.ID, schema.ID})
+
+	oldBundle.ID = placement.GroupID(partitionID)
+
+	bundle, err := buildPlacementSpecs(oldBundle, spec.PlacementSpecs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	finishPlacementBundle(bundle, partitionID, placement.RuleIndexPartition)
 
 	job := &model.Job{
 		SchemaID:   schema.ID,
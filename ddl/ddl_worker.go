@@ -15,6 +15,7 @@ package ddl
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"sync"
@@ -67,6 +68,9 @@ const (
 	generalWorker workerType = 0
 	// addIdxWorker is the worker who handles the operation of adding indexes.
 	addIdxWorker workerType = 1
+	// tableWorker is the worker who handles the jobs in the first table job queue,
+	// the worker who handles the jobs in the i-th table job queue is tableWorker + i.
+	tableWorker workerType = 2
 	// waitDependencyJobInterval is the interval when the dependency job doesn't be done.
 	waitDependencyJobInterval = 200 * time.Millisecond
 	// noneDependencyJob means a job has no dependency-job.
@@ -74,7 +78,7 @@ const (
 )
 
 // worker is used for handling DDL jobs.
-// Now we have two kinds of workers.
+// Now we have three kinds of workers.
 type worker struct {
 	id              int32
 	tp              workerType
@@ -87,6 +91,8 @@ type worker struct {
 	reorgCtx        *reorgCtx    // reorgCtx is used for reorganization.
 	delRangeManager delRangeManager
 	logCtx          context.Context
+	// subJob is the running sub-job of the multi-schema change job, it's nil for the other jobs.
+	subJob *subJob
 }
 
 func newWorker(ctx context.Context, tp workerType, sessPool *sessionPool, delRangeMgr delRangeManager) *worker {
@@ -113,7 +119,11 @@ func (w *worker) typeStr() string {
 	case addIdxWorker:
 		str = model.AddIndexStr
 	default:
-		str = "unknown"
+		if w.tp >= tableWorker && w.tp < tableWorker+meta.TableJobListKeyCnt {
+			str = fmt.Sprintf("table_%d", w.tp-tableWorker)
+		} else {
+			str = "unknown"
+		}
 	}
 	return str
 }
@@ -206,31 +216,37 @@ func asyncNotify(ch chan struct{}) {
 // The dependency-job's ID must less than the current job's ID, and we need the largest one in the list.
 func buildJobDependence(t *meta.Meta, curJob *model.Job) error {
 	// Jobs in the same queue are ordered. If we want to find a job's dependency-job, we need to look for
-	// it from the other queue. So if the job is "ActionAddIndex" job, we need find its dependency-job from DefaultJobList.
-	var jobs []*model.Job
-	var err error
+	// it from the other queues. So if the job is "ActionAddIndex" job, we need find its dependency-job from DefaultJobList
+	// and the table job queues. The general job queue and the table job queues are ordered by isPrecedingJobDone.
+	var jobListKeys []meta.JobListKeyType
 	switch curJob.Type {
 	case model.ActionAddIndex, model.ActionAddPrimaryKey:
-		jobs, err = t.GetAllDDLJobsInQueue(meta.DefaultJobListKey)
+		jobListKeys = append(jobListKeys, meta.DefaultJobListKey)
+		for i := 0; i < meta.TableJobListKeyCnt; i++ {
+			jobListKeys = append(jobListKeys, meta.TableJobListKey(i))
+		}
 	default:
-		jobs, err = t.GetAllDDLJobsInQueue(meta.AddIndexJobListKey)
-	}
-	if err != nil {
-		return errors.Trace(err)
+		jobListKeys = append(jobListKeys, meta.AddIndexJobListKey)
 	}
 
-	for _, job := range jobs {
-		if curJob.ID < job.ID {
-			continue
-		}
-		isDependent, err := curJob.IsDependentOn(job)
+	for _, jobListKey := range jobListKeys {
+		jobs, err := t.GetAllDDLJobsInQueue(jobListKey)
 		if err != nil {
 			return errors.Trace(err)
 		}
-		if isDependent {
-			logutil.BgLogger().Info("[ddl] current DDL job depends on other job", zap.String("currentJob", curJob.String()), zap.String("dependentJob", job.String()))
-			curJob.DependencyID = job.ID
-			break
+		for _, job := range jobs {
+			if curJob.ID < job.ID || job.ID <= curJob.DependencyID {
+				continue
+			}
+			isDependent, err := curJob.IsDependentOn(job)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if isDependent {
+				logutil.BgLogger().Info("[ddl] current DDL job depends on other job", zap.String("currentJob", curJob.String()), zap.String("dependentJob", job.String()))
+				curJob.DependencyID = job.ID
+				break
+			}
 		}
 	}
 	return nil
@@ -275,12 +291,7 @@ func (d *ddl) addBatchDDLJobs(tasks []*limitJobTask) {
 				return errors.Trace(err)
 			}
 
-			if job.Type == model.ActionAddIndex || job.Type == model.ActionAddPrimaryKey {
-				jobKey := meta.AddIndexJobListKey
-				err = t.EnQueueDDLJob(job, jobKey)
-			} else {
-				err = t.EnQueueDDLJob(job)
-			}
+			err = t.EnQueueDDLJob(job, getJobListKey(task.workerTp))
 			if err != nil {
 				return errors.Trace(err)
 			}
@@ -381,8 +392,16 @@ func (w *worker) finishDDLJob(t *meta.Meta, job *model.Job) (err error) {
 
 			// After rolling back an AddIndex operation, we need to use delete-range to delete the half-done index data.
 			err = w.deleteRange(job)
+		case model.ActionCreateTableAsSelect:
+			if job.State != model.JobStateRollbackDone {
+				break
+			}
+
+			// After rolling back a CreateTableAsSelect operation, we need to use delete-range to delete the inserted rows.
+			err = w.deleteRange(job)
 		case model.ActionDropSchema, model.ActionDropTable, model.ActionTruncateTable, model.ActionDropIndex, model.ActionDropPrimaryKey,
-			model.ActionDropTablePartition, model.ActionTruncateTablePartition, model.ActionDropColumn, model.ActionDropColumns, model.ActionModifyColumn:
+			model.ActionDropTablePartition, model.ActionTruncateTablePartition, model.ActionDropColumn, model.ActionDropColumns, model.ActionModifyColumn,
+			model.ActionMultiSchemaChange, model.ActionReorganizePartition, model.ActionRepairIndex:
 			err = w.deleteRange(job)
 		}
 	}
@@ -407,9 +426,37 @@ func (w *worker) finishDDLJob(t *meta.Meta, job *model.Job) (err error) {
 		updateRawArgs = false
 	}
 	err = t.AddHistoryDDLJob(job, updateRawArgs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Only the jobs which have changed the schema are notified, the cancelled and rolled back ones are skipped.
+	if job.IsSynced() && job.BinlogInfo.SchemaVersion > 0 {
+		err = t.AddDDLEvent(newDDLEvent(job))
+	}
 	return errors.Trace(err)
 }
 
+func newDDLEvent(job *model.Job) *meta.DDLEvent {
+	event := &meta.DDLEvent{
+		SchemaVersion: job.BinlogInfo.SchemaVersion,
+		JobID:         job.ID,
+		Type:          job.Type,
+		TypeName:      job.Type.String(),
+		SchemaID:      job.SchemaID,
+		TableID:       job.TableID,
+		SchemaName:    job.SchemaName,
+		Query:         job.Query,
+		FinishedTS:    job.BinlogInfo.FinishedTS,
+	}
+	if dbInfo := job.BinlogInfo.DBInfo; dbInfo != nil {
+		event.SchemaName = dbInfo.Name.O
+	}
+	if tblInfo := job.BinlogInfo.TableInfo; tblInfo != nil {
+		event.TableName = tblInfo.Name.O
+	}
+	return event
+}
+
 func finishRecoverTable(w *worker, t *meta.Meta, job *model.Job) error {
 	tbInfo := &model.TableInfo{}
 	var autoIncID, autoRandID, dropJobID, recoverTableCheckFlag int64
@@ -444,11 +491,63 @@ func isDependencyJobDone(t *meta.Meta, job *model.Job) (bool, error) {
 	return true, nil
 }
 
-func newMetaWithQueueTp(txn kv.Transaction, tp string) *meta.Meta {
-	if tp == model.AddIndexStr || tp == model.AddPrimaryKeyStr {
-		return meta.NewMeta(txn, meta.AddIndexJobListKey)
+// isPrecedingJobDone checks whether the jobs added before the job are done in the queues which are ordered
+// with the worker's queue. The jobs in the general job queue and the ones in the table job queues are run in
+// the order they're added, so only the jobs in different table job queues are run concurrently.
+// If a preceding job isn't done, it's set as the dependency-job of the job for waiting.
+func (w *worker) isPrecedingJobDone(t *meta.Meta, job *model.Job) (bool, error) {
+	var jobListKeys []meta.JobListKeyType
+	switch {
+	case w.tp == generalWorker:
+		for i := 0; i < meta.TableJobListKeyCnt; i++ {
+			jobListKeys = append(jobListKeys, meta.TableJobListKey(i))
+		}
+	case w.tp >= tableWorker:
+		jobListKeys = append(jobListKeys, meta.DefaultJobListKey)
+	}
+	for _, jobListKey := range jobListKeys {
+		// The jobs in a queue are ordered by their IDs, so we only need to check the first one.
+		firstJob, err := t.GetDDLJobByIdx(0, jobListKey)
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		if firstJob != nil && firstJob.ID < job.ID {
+			job.DependencyID = firstJob.ID
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// getJobWorkerType gets the type of the worker who handles the job.
+// The jobs which only change one table are handled by the table workers if concurrentDDL is true.
+func getJobWorkerType(job *model.Job, concurrentDDL bool) workerType {
+	switch job.Type {
+	case model.ActionAddIndex, model.ActionAddPrimaryKey:
+		return addIdxWorker
+	case model.ActionAddColumn, model.ActionAddColumns, model.ActionDropColumn, model.ActionDropColumns,
+		model.ActionModifyColumn, model.ActionSetDefaultValue, model.ActionRenameIndex, model.ActionDropIndex,
+		model.ActionDropPrimaryKey, model.ActionAlterIndexVisibility, model.ActionModifyTableComment,
+		model.ActionModifyTableCharsetAndCollate, model.ActionRebaseAutoID, model.ActionShardRowID,
+		model.ActionModifyTableAutoIdCache, model.ActionAddTablePartition, model.ActionDropTablePartition,
+		model.ActionTruncateTablePartition, model.ActionSetTiFlashReplica, model.ActionMultiSchemaChange,
+		model.ActionReorganizePartition, model.ActionRepairIndex, model.ActionAlterTTLInfo, model.ActionAlterTTLRemove:
+		if concurrentDDL && job.TableID > 0 {
+			return tableWorker + workerType(job.TableID%meta.TableJobListKeyCnt)
+		}
+	}
+	return generalWorker
+}
+
+// getJobListKey gets the key of the queue handled by the worker.
+func getJobListKey(tp workerType) meta.JobListKeyType {
+	switch {
+	case tp == addIdxWorker:
+		return meta.AddIndexJobListKey
+	case tp >= tableWorker:
+		return meta.TableJobListKey(int(tp - tableWorker))
 	}
-	return meta.NewMeta(txn)
+	return meta.DefaultJobListKey
 }
 
 // handleDDLJobQueue handles DDL jobs in DDL Job queue.
@@ -473,15 +572,23 @@ func (w *worker) handleDDLJobQueue(d *ddlCtx) error {
 			}
 
 			var err error
-			t := newMetaWithQueueTp(txn, w.typeStr())
+			t := meta.NewMeta(txn, getJobListKey(w.tp))
 			// We become the owner. Get the first job and run it.
 			job, err = w.getFirstDDLJob(t)
 			if job == nil || err != nil {
 				return errors.Trace(err)
 			}
+			// The paused job isn't run until it's resumed.
+			if job.IsPaused() {
+				job = nil
+				return nil
+			}
 			if isDone, err1 := isDependencyJobDone(t, job); err1 != nil || !isDone {
 				return errors.Trace(err1)
 			}
+			if isDone, err1 := w.isPrecedingJobDone(t, job); err1 != nil || !isDone {
+				return errors.Trace(err1)
+			}
 
 			if once {
 				w.waitSchemaSynced(d, job, waitTime)
@@ -670,6 +777,32 @@ func (w *worker) countForError(err error, job *model.Job) error {
 }
 
 // runDDLJob runs a DDL job. It returns the current schema version in this transaction and the error.
+// pauseDDLJob stops the backfill workers of the job if they are running, and then marks the job paused.
+// The workers save the reorg progress before they exit, so the job continues from it after it's resumed.
+func (w *worker) pauseDDLJob(job *model.Job) (ver int64, err error) {
+	if w.reorgCtx.doneCh != nil {
+		w.reorgCtx.notifyReorgPause()
+		reorgErr := <-w.reorgCtx.doneCh
+		rowCount, _, _ := w.reorgCtx.getRowCountAndKey()
+		job.SetRowCount(rowCount)
+		if job.ReorgMeta != nil {
+			w.mergeWarningsIntoJob(job)
+		}
+		w.reorgCtx.clean()
+		w.reorgCtx.cleanNotifyReorgPause()
+		logutil.Logger(w.logCtx).Info("[ddl] stop the backfill workers of the paused DDL job",
+			zap.String("job", job.String()), zap.Int64("handled rows", rowCount), zap.Error(reorgErr))
+	}
+	// Make sure RawArgs isn't overwritten when the job is updated.
+	if job.Args == nil && len(job.RawArgs) > 0 {
+		if err = json.Unmarshal(job.RawArgs, &job.Args); err != nil {
+			return ver, errors.Trace(err)
+		}
+	}
+	job.State = model.JobStatePaused
+	return ver, nil
+}
+
 func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
 	defer tidbutil.Recover(metrics.LabelDDLWorker, fmt.Sprintf("%s runDDLJob", w),
 		func() {
@@ -687,6 +820,10 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 	if job.IsFinished() {
 		return
 	}
+	// The cause of this job state is that the job is paused by client.
+	if job.IsPausing() {
+		return w.pauseDDLJob(job)
+	}
 	// The cause of this job state is that the job is cancelled by client.
 	if job.IsCancelling() {
 		return convertJob2RollbackJob(w, d, t, job)
@@ -710,6 +847,8 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onDropSchema(d, t, job)
 	case model.ActionCreateTable:
 		ver, err = onCreateTable(d, t, job)
+	case model.ActionCreateTableAsSelect:
+		ver, err = w.onCreateTableAsSelect(d, t, job)
 	case model.ActionRepairTable:
 		ver, err = onRepairTable(d, t, job)
 	case model.ActionCreateView:
@@ -722,6 +861,10 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onTruncateTablePartition(d, t, job)
 	case model.ActionExchangeTablePartition:
 		ver, err = w.onExchangeTablePartition(d, t, job)
+	case model.ActionReorganizePartition:
+		ver, err = w.onReorganizePartition(d, t, job)
+	case model.ActionAlterTablePlacement:
+		ver, err = onAlterTablePlacement(t, job)
 	case model.ActionAddColumn:
 		ver, err = onAddColumn(d, t, job)
 	case model.ActionAddColumns:
@@ -742,6 +885,8 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onDropIndex(t, job)
 	case model.ActionRenameIndex:
 		ver, err = onRenameIndex(t, job)
+	case model.ActionRepairIndex:
+		ver, err = w.onRepairIndex(d, t, job)
 	case model.ActionAddForeignKey:
 		ver, err = onCreateForeignKey(t, job)
 	case model.ActionDropForeignKey:
@@ -760,10 +905,14 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onModifyTableComment(t, job)
 	case model.ActionModifyTableAutoIdCache:
 		ver, err = onModifyTableAutoIDCache(t, job)
+	case model.ActionAlterTTLInfo:
+		ver, err = onAlterTTLInfo(t, job)
+	case model.ActionAlterTTLRemove:
+		ver, err = onAlterTTLRemove(t, job)
 	case model.ActionAddTablePartition:
 		ver, err = w.onAddTablePartition(d, t, job)
 	case model.ActionModifyTableCharsetAndCollate:
-		ver, err = onModifyTableCharsetAndCollate(t, job)
+		ver, err = w.onModifyTableCharsetAndCollate(d, t, job)
 	case model.ActionRecoverTable:
 		ver, err = w.onRecoverTable(d, t, job)
 	case model.ActionLockTable:
@@ -784,6 +933,8 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onAlterSequence(t, job)
 	case model.ActionRenameTables:
 		ver, err = onRenameTables(d, t, job)
+	case model.ActionMultiSchemaChange:
+		ver, err = w.onMultiSchemaChange(d, t, job)
 	default:
 		// Invalid job, cancel it.
 		job.State = model.JobStateCancelled
@@ -916,6 +1067,15 @@ func updateSchemaVersion(t *meta.Meta, job *model.Job) (int64, error) {
 		SchemaID: job.SchemaID,
 	}
 	switch job.Type {
+	case model.ActionCreateTableAsSelect:
+		// The table is created in the first step and dropped when the job is rolled back,
+		// these schema diffs are applied like the ones of creating and dropping tables.
+		diff.TableID = job.TableID
+		if job.IsRollingback() {
+			diff.Type = model.ActionDropTable
+		} else if job.SchemaState == model.StateNone {
+			diff.Type = model.ActionCreateTable
+		}
 	case model.ActionTruncateTable:
 		// Truncate table has two table ID, should be handled differently.
 		err = job.DecodeArgs(&diff.TableID)
@@ -958,18 +1118,27 @@ func updateSchemaVersion(t *meta.Meta, job *model.Job) (int64, error) {
 		if err != nil {
 			return 0, errors.Trace(err)
 		}
-		affects := make([]*model.AffectedOption, len(newSchemaIDs))
+		// A table may be renamed more than once in a job, e.g. when swapping tables,
+		// only its original schema and its final schema matter to the schema diff.
+		affects := make([]*model.AffectedOption, 0, len(newSchemaIDs))
+		affectByTableID := make(map[int64]*model.AffectedOption, len(newSchemaIDs))
 		for i, newSchemaID := range newSchemaIDs {
-			affects[i] = &model.AffectedOption{
+			if affect, ok := affectByTableID[tableIDs[i]]; ok {
+				affect.SchemaID = newSchemaID
+				continue
+			}
+			affect := &model.AffectedOption{
 				SchemaID:    newSchemaID,
 				TableID:     tableIDs[i],
 				OldTableID:  tableIDs[i],
 				OldSchemaID: oldSchemaIDs[i],
 			}
+			affectByTableID[tableIDs[i]] = affect
+			affects = append(affects, affect)
 		}
-		diff.TableID = tableIDs[0]
-		diff.SchemaID = newSchemaIDs[0]
-		diff.OldSchemaID = oldSchemaIDs[0]
+		diff.TableID = affects[0].TableID
+		diff.SchemaID = affects[0].SchemaID
+		diff.OldSchemaID = affects[0].OldSchemaID
 		diff.AffectedOpts = affects
 	case model.ActionExchangeTablePartition:
 		var (
@@ -1012,6 +1181,15 @@ func updateSchemaVersion(t *meta.Meta, job *model.Job) (int64, error) {
 				},
 			}
 		}
+	case model.ActionAlterTablePlacement:
+		// The infoschema reloads the placement bundles of the physical tables in the same way as altering the placement of partitions.
+		diff.Type = model.ActionAlterTableAlterPartition
+		diff.TableID = job.TableID
+		if len(job.CtxVars) > 0 {
+			for _, physicalID := range job.CtxVars[0].([]int64) {
+				diff.AffectedOpts = append(diff.AffectedOpts, &model.AffectedOption{TableID: physicalID})
+			}
+		}
 	default:
 		diff.TableID = job.TableID
 	}
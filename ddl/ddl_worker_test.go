@@ -117,7 +117,7 @@ func (s *testDDLSuite) TestNotifyDDLJob(c *C) {
 	}
 	// Test the notification mechanism of the owner and the server receiving the DDL request on the same TiDB.
 	// This DDL request is a general DDL job.
-	d.asyncNotifyWorker(job)
+	d.asyncNotifyWorker(job, getJobWorkerType(job, false))
 	select {
 	case <-d.workers[generalWorker].ddlJobCh:
 	default:
@@ -126,7 +126,7 @@ func (s *testDDLSuite) TestNotifyDDLJob(c *C) {
 	// Test the notification mechanism of the owner and the server receiving the DDL request on the same TiDB.
 	// This DDL request is a add index DDL job.
 	job.Type = model.ActionAddIndex
-	d.asyncNotifyWorker(job)
+	d.asyncNotifyWorker(job, getJobWorkerType(job, false))
 	select {
 	case <-d.workers[addIdxWorker].ddlJobCh:
 	default:
@@ -149,9 +149,9 @@ func (s *testDDLSuite) TestNotifyDDLJob(c *C) {
 		worker.close()
 	}
 	d1.ownerManager.RetireOwner()
-	d1.asyncNotifyWorker(job)
+	d1.asyncNotifyWorker(job, getJobWorkerType(job, false))
 	job.Type = model.ActionCreateTable
-	d1.asyncNotifyWorker(job)
+	d1.asyncNotifyWorker(job, getJobWorkerType(job, false))
 	testCheckOwner(c, d1, false)
 	select {
 	case <-d1.workers[addIdxWorker].ddlJobCh:
@@ -1388,8 +1388,60 @@ func (s *testDDLSuite) TestBuildJobDependence(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (s *testDDLSuite) TestTableJobQueues(c *C) {
+	store := testCreateStore(c, "test_table_job_queues")
+	defer func() {
+		err := store.Close()
+		c.Assert(err, IsNil)
+	}()
+
+	job1 := &model.Job{ID: 1, TableID: 5, Type: model.ActionAddColumn}
+	job2 := &model.Job{ID: 2, TableID: 6, Type: model.ActionCreateTable}
+	job3 := &model.Job{ID: 3, TableID: 6, Type: model.ActionAddColumn}
+	c.Assert(getJobWorkerType(job1, false), Equals, generalWorker)
+	c.Assert(getJobWorkerType(job1, true), Equals, tableWorker+1)
+	c.Assert(getJobWorkerType(job2, true), Equals, generalWorker)
+	c.Assert(getJobWorkerType(job3, true), Equals, tableWorker+2)
+	c.Assert(getJobWorkerType(&model.Job{TableID: 5, Type: model.ActionAddIndex}, true), Equals, addIdxWorker)
+	c.Assert(getJobListKey(tableWorker+2), DeepEquals, meta.TableJobListKey(2))
+	c.Assert(getJobListKey(generalWorker), DeepEquals, meta.DefaultJobListKey)
+
+	err := kv.RunInNewTxn(context.Background(), store, false, func(ctx context.Context, txn kv.Transaction) error {
+		t := meta.NewMeta(txn)
+		for _, job := range []*model.Job{job1, job2, job3} {
+			err := t.EnQueueDDLJob(job, getJobListKey(getJobWorkerType(job, true)))
+			c.Assert(err, IsNil)
+		}
+		// The add index job depends on the job in the table job queue.
+		job4 := &model.Job{ID: 4, TableID: 5, Type: model.ActionAddIndex}
+		err := buildJobDependence(t, job4)
+		c.Assert(err, IsNil)
+		c.Assert(job4.DependencyID, Equals, int64(1))
+
+		// The jobs in the general job queue and the table job queues are run in order.
+		generalWorker := &worker{tp: generalWorker}
+		isDone, err := generalWorker.isPrecedingJobDone(t, job2)
+		c.Assert(err, IsNil)
+		c.Assert(isDone, IsFalse)
+		c.Assert(job2.DependencyID, Equals, int64(1))
+		tableWorker2 := &worker{tp: tableWorker + 2}
+		isDone, err = tableWorker2.isPrecedingJobDone(t, job3)
+		c.Assert(err, IsNil)
+		c.Assert(isDone, IsFalse)
+		c.Assert(job3.DependencyID, Equals, int64(2))
+		// The jobs in different table job queues are run concurrently.
+		tableWorker1 := &worker{tp: tableWorker + 1}
+		isDone, err = tableWorker1.isPrecedingJobDone(t, job1)
+		c.Assert(err, IsNil)
+		c.Assert(isDone, IsTrue)
+		c.Assert(job1.DependencyID, Equals, int64(0))
+		return nil
+	})
+	c.Assert(err, IsNil)
+}
+
 func addDDLJob(c *C, d *ddl, job *model.Job) {
-	task := &limitJobTask{job, make(chan error)}
+	task := &limitJobTask{job, make(chan error), getJobWorkerType(job, false)}
 	d.limitJobCh <- task
 	err := <-task.err
 	c.Assert(err, IsNil)
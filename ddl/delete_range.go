@@ -268,7 +268,7 @@ func insertJobIntoDeleteRangeTable(ctx sessionctx.Context, job *model.Job) error
 				return errors.Trace(err)
 			}
 		}
-	case model.ActionDropTable, model.ActionTruncateTable:
+	case model.ActionDropTable, model.ActionTruncateTable, model.ActionCreateTableAsSelect:
 		tableID := job.TableID
 		// The startKey here is for compatibility with previous versions, old version did not endKey so don't have to deal with.
 		var startKey kv.Key
@@ -289,7 +289,7 @@ func insertJobIntoDeleteRangeTable(ctx sessionctx.Context, job *model.Job) error
 		startKey = tablecodec.EncodeTablePrefix(tableID)
 		endKey := tablecodec.EncodeTablePrefix(tableID + 1)
 		return doInsert(s, job.ID, tableID, startKey, endKey, now)
-	case model.ActionDropTablePartition, model.ActionTruncateTablePartition:
+	case model.ActionDropTablePartition, model.ActionTruncateTablePartition, model.ActionReorganizePartition:
 		var physicalTableIDs []int64
 		if err := job.DecodeArgs(&physicalTableIDs); err != nil {
 			return errors.Trace(err)
@@ -380,7 +380,7 @@ func insertJobIntoDeleteRangeTable(ctx sessionctx.Context, job *model.Job) error
 				return doBatchDeleteIndiceRange(s, job.ID, job.TableID, indexIDs, now)
 			}
 		}
-	case model.ActionModifyColumn:
+	case model.ActionModifyColumn, model.ActionRepairIndex:
 		var indexIDs []int64
 		var partitionIDs []int64
 		if err := job.DecodeArgs(&indexIDs, &partitionIDs); err != nil {
@@ -397,6 +397,21 @@ func insertJobIntoDeleteRangeTable(ctx sessionctx.Context, job *model.Job) error
 				return errors.Trace(err)
 			}
 		}
+	case model.ActionMultiSchemaChange:
+		info := &multiSchemaInfo{}
+		if err := job.DecodeArgs(info); err != nil {
+			return errors.Trace(err)
+		}
+		for _, sub := range info.SubJobs {
+			switch {
+			case sub.Type == model.ActionAddIndex && sub.State == model.JobStateRollbackDone,
+				sub.State == model.JobStateDone && (sub.Type == model.ActionDropIndex ||
+					sub.Type == model.ActionDropPrimaryKey || sub.Type == model.ActionDropColumn):
+				if err := insertJobIntoDeleteRangeTable(ctx, sub.toProxyJob(job)); err != nil {
+					return errors.Trace(err)
+				}
+			}
+		}
 	}
 	return nil
 }
@@ -14,27 +14,24 @@
 package ddl
 
 import (
+	"fmt"
+
 	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/types"
 )
 
 func onCreateForeignKey(t *meta.Meta, job *model.Job) (ver int64, _ error) {
-	schemaID := job.SchemaID
-	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, schemaID)
+	tblInfo, fkInfo, err := checkAddForeignKey(t, job)
 	if err != nil {
 		return ver, errors.Trace(err)
 	}
-
-	var fkInfo model.FKInfo
-	err = job.DecodeArgs(&fkInfo)
-	if err != nil {
-		job.State = model.JobStateCancelled
-		return ver, errors.Trace(err)
-	}
 	fkInfo.ID = allocateIndexID(tblInfo)
-	tblInfo.ForeignKeys = append(tblInfo.ForeignKeys, &fkInfo)
+	tblInfo.ForeignKeys = append(tblInfo.ForeignKeys, fkInfo)
 
 	originalState := fkInfo.State
 	switch fkInfo.State {
@@ -54,6 +51,26 @@ func onCreateForeignKey(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	}
 }
 
+// checkAddForeignKey checks the foreign key of the job can be added to the table.
+func checkAddForeignKey(t *meta.Meta, job *model.Job) (*model.TableInfo, *model.FKInfo, error) {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	fkInfo := &model.FKInfo{}
+	if err = job.DecodeArgs(fkInfo); err != nil {
+		job.State = model.JobStateCancelled
+		return nil, nil, errors.Trace(err)
+	}
+	for _, fk := range tblInfo.ForeignKeys {
+		if fk.Name.L == fkInfo.Name.L {
+			job.State = model.JobStateCancelled
+			return nil, nil, infoschema.ErrCannotAddForeign
+		}
+	}
+	return tblInfo, fkInfo, nil
+}
+
 func onDropForeignKey(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	schemaID := job.SchemaID
 	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, schemaID)
@@ -108,5 +125,163 @@ func onDropForeignKey(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	default:
 		return ver, ErrInvalidDDLState.GenWithStackByArgs("foreign key", fkInfo.State)
 	}
+}
+
+// isFKIndex checks whether the index can be used to look up the rows by the foreign key columns,
+// that is, the leading columns of the index are cols in the same order and none of them is a prefix.
+func isFKIndex(idxInfo *model.IndexInfo, cols []model.CIStr) bool {
+	if len(idxInfo.Columns) < len(cols) {
+		return false
+	}
+	for i, col := range cols {
+		idxCol := idxInfo.Columns[i]
+		if idxCol.Name.L != col.L || idxCol.Length != types.UnspecifiedLength {
+			return false
+		}
+	}
+	return true
+}
+
+// hasFKIndex checks whether there is a public index other than excluded in the table can be used by
+// the foreign key columns. The integer primary key used as the handle is also taken into account.
+func hasFKIndex(tblInfo *model.TableInfo, cols []model.CIStr, excluded *model.IndexInfo) bool {
+	if tblInfo.PKIsHandle && len(cols) == 1 {
+		if pkCol := tblInfo.GetPkColInfo(); pkCol != nil && pkCol.Name.L == cols[0].L {
+			return true
+		}
+	}
+	for _, idxInfo := range tblInfo.Indices {
+		if idxInfo == excluded || idxInfo.State != model.StatePublic {
+			continue
+		}
+		if isFKIndex(idxInfo, cols) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildFKIndexInfo builds the index for the foreign key if there isn't one can be used by its columns,
+// just like MySQL does. The index is named after the foreign key.
+func buildFKIndexInfo(tblInfo *model.TableInfo, fkInfo *model.FKInfo) (*model.IndexInfo, error) {
+	if hasFKIndex(tblInfo, fkInfo.Cols, nil) {
+		return nil, nil
+	}
+	idxInfo, err := buildIndexInfo(tblInfo, getFKIndexName(tblInfo, fkInfo.Name), buildFKIndexPartSpecifications(fkInfo.Cols), model.StatePublic)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	idxInfo.Tp = model.IndexTypeBtree
+	idxInfo.ID = allocateIndexID(tblInfo)
+	return idxInfo, nil
+}
+
+// getFKIndexName returns the name of the index created for the foreign key, it's named after the foreign key
+// and a suffix is added if the name is used by another index.
+func getFKIndexName(tblInfo *model.TableInfo, fkName model.CIStr) model.CIStr {
+	idxName := fkName
+	for i := 2; tblInfo.FindIndexByName(idxName.L) != nil; i++ {
+		idxName = model.NewCIStr(fmt.Sprintf("%s_%d", fkName.O, i))
+	}
+	return idxName
+}
 
+func buildFKIndexPartSpecifications(cols []model.CIStr) []*ast.IndexPartSpecification {
+	keys := make([]*ast.IndexPartSpecification, 0, len(cols))
+	for _, col := range cols {
+		keys = append(keys, &ast.IndexPartSpecification{
+			Column: &ast.ColumnName{Name: col},
+			Length: types.UnspecifiedLength,
+		})
+	}
+	return keys
+}
+
+// getFKReferredTable gets the table referred by the foreign key of tblInfo in the schema.
+// It returns nil if the referred table doesn't exist, which is allowed because TiDB always
+// works as `foreign_key_checks` is off.
+func getFKReferredTable(is infoschema.InfoSchema, schema model.CIStr, tblInfo *model.TableInfo, refer *ast.ReferenceDef) *model.TableInfo {
+	refSchema := refer.Table.Schema
+	if refSchema.L == "" {
+		refSchema = schema
+	}
+	if refSchema.L == schema.L && refer.Table.Name.L == tblInfo.Name.L {
+		return tblInfo
+	}
+	refTbl, err := is.TableByName(refSchema, refer.Table.Name)
+	if err != nil {
+		return nil
+	}
+	return refTbl.Meta()
+}
+
+// checkTableForeignKeys checks the tables referred by the foreign keys defined in the constraints of a new table.
+func checkTableForeignKeys(is infoschema.InfoSchema, schema model.CIStr, tblInfo *model.TableInfo, constraints []*ast.Constraint) error {
+	for _, constr := range constraints {
+		if constr.Tp != ast.ConstraintForeignKey {
+			continue
+		}
+		for _, fkInfo := range tblInfo.ForeignKeys {
+			if fkInfo.Name.L != model.NewCIStr(constr.Name).L {
+				continue
+			}
+			refTblInfo := getFKReferredTable(is, schema, tblInfo, constr.Refer)
+			if refTblInfo == nil {
+				break
+			}
+			if err := checkFKReferredTable(tblInfo, fkInfo, refTblInfo); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkFKReferredTable checks that the referred columns of the foreign key exist in the referred table,
+// are compatible with the foreign key columns and are the leading columns of an index.
+func checkFKReferredTable(tblInfo *model.TableInfo, fkInfo *model.FKInfo, refTblInfo *model.TableInfo) error {
+	for i, refColName := range fkInfo.RefCols {
+		refCol := model.FindColumnInfo(refTblInfo.Columns, refColName.L)
+		if refCol == nil {
+			return errKeyColumnDoesNotExits.GenWithStackByArgs(refColName)
+		}
+		col := model.FindColumnInfo(tblInfo.Columns, fkInfo.Cols[i].L)
+		if col == nil {
+			return errKeyColumnDoesNotExits.GenWithStackByArgs(fkInfo.Cols[i])
+		}
+		if !isFKColumnCompatible(col, refCol) {
+			return errFKIncompatibleColumns.GenWithStackByArgs(col.Name, fkInfo.Name)
+		}
+	}
+	if !hasFKIndex(refTblInfo, fkInfo.RefCols, nil) {
+		return errFkNoIndexParent.GenWithStackByArgs(fkInfo.Name, refTblInfo.Name)
+	}
+	return nil
+}
+
+// isFKColumnCompatible checks whether the foreign key column can refer to the referred column.
+// The string columns must have the same charset and collation while their lengths can be different,
+// and the other columns must have the same type, signedness, length and decimal.
+func isFKColumnCompatible(col, refCol *model.ColumnInfo) bool {
+	if types.IsString(col.Tp) || types.IsString(refCol.Tp) {
+		return types.IsString(col.Tp) && types.IsString(refCol.Tp) &&
+			col.Charset == refCol.Charset && col.Collate == refCol.Collate
+	}
+	if col.Tp != refCol.Tp || mysql.HasUnsignedFlag(col.Flag) != mysql.HasUnsignedFlag(refCol.Flag) {
+		return false
+	}
+	if col.Tp == mysql.TypeNewDecimal {
+		return col.Flen == refCol.Flen && col.Decimal == refCol.Decimal
+	}
+	return true
+}
+
+// checkDropIndexOnForeignKey checks whether the index is the only one can be used by a foreign key of the table.
+func checkDropIndexOnForeignKey(tblInfo *model.TableInfo, idxInfo *model.IndexInfo) error {
+	for _, fkInfo := range tblInfo.ForeignKeys {
+		if isFKIndex(idxInfo, fkInfo.Cols) && !hasFKIndex(tblInfo, fkInfo.Cols, idxInfo) {
+			return errDropIndexFk.GenWithStackByArgs(idxInfo.Name)
+		}
+	}
+	return nil
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/ddl/ingest"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
@@ -40,6 +41,7 @@ import (
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/fulltext"
 	"github.com/pingcap/tidb/util/logutil"
 	decoder "github.com/pingcap/tidb/util/rowDecoder"
 	"github.com/pingcap/tidb/util/timeutil"
@@ -61,7 +63,7 @@ func buildIndexColumns(columns []*model.ColumnInfo, indexPartSpecifications []*a
 	sumLength := 0
 	for _, ip := range indexPartSpecifications {
 		col = model.FindColumnInfo(columns, ip.Column.Name.L)
-		if col == nil {
+		if col == nil || tables.IsGeneratedInvisiblePK(col) {
 			return nil, errKeyColumnDoesNotExits.GenWithStack("column does not exist: %s", ip.Column.Name)
 		}
 
@@ -228,6 +230,52 @@ func buildIndexInfo(tblInfo *model.TableInfo, indexName model.CIStr, indexPartSp
 	return idxInfo, nil
 }
 
+// buildFulltextIndexInfo builds the FULLTEXT index. Its columns must be the non-binary string columns and no
+// prefix length is allowed, because the words of the whole values are indexed.
+func buildFulltextIndexInfo(tblInfo *model.TableInfo, indexName model.CIStr, indexPartSpecifications []*ast.IndexPartSpecification, state model.SchemaState) (*model.IndexInfo, error) {
+	if err := checkTooLongIndex(indexName); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if tblInfo.Partition != nil {
+		return nil, errors.Trace(ErrFulltextNotSupportedWithPartitioning)
+	}
+
+	idxColumns := make([]*model.IndexColumn, 0, len(indexPartSpecifications))
+	for _, ip := range indexPartSpecifications {
+		if ip.Expr != nil {
+			return nil, errUnsupportedIndexType.GenWithStack("FULLTEXT index on expressions is not supported")
+		}
+		col := model.FindColumnInfo(tblInfo.Columns, ip.Column.Name.L)
+		if col == nil || tables.IsGeneratedInvisiblePK(col) {
+			return nil, errKeyColumnDoesNotExits.GenWithStack("column does not exist: %s", ip.Column.Name)
+		}
+		if !isFulltextColumn(&col.FieldType) {
+			return nil, errors.Trace(ErrBadFtColumn.GenWithStackByArgs(col.Name))
+		}
+		if ip.Length != types.UnspecifiedLength {
+			return nil, errors.Trace(errIncorrectPrefixKey)
+		}
+		idxColumns = append(idxColumns, &model.IndexColumn{
+			Name:   col.Name,
+			Offset: col.Offset,
+			Length: types.UnspecifiedLength,
+		})
+	}
+
+	idxInfo := &model.IndexInfo{
+		Name:    indexName,
+		Columns: idxColumns,
+		State:   state,
+		Tp:      model.IndexTypeFulltext,
+	}
+	return idxInfo, nil
+}
+
+// isFulltextColumn checks whether the column of the type can be part of the FULLTEXT index.
+func isFulltextColumn(tp *types.FieldType) bool {
+	return types.IsString(tp.Tp) && tp.Charset != charset.CharsetBin
+}
+
 func addIndexColumnFlag(tblInfo *model.TableInfo, indexInfo *model.IndexInfo) {
 	if indexInfo.Primary {
 		for _, col := range indexInfo.Columns {
@@ -400,12 +448,13 @@ func (w *worker) onCreateIndex(d *ddlCtx, t *meta.Meta, job *model.Job, isPK boo
 		sqlMode                 mysql.SQLMode
 		warnings                []string
 		hiddenCols              []*model.ColumnInfo
+		preSplitRegions         int
 	)
 	if isPK {
 		// Notice: sqlMode and warnings is used to support non-strict mode.
 		err = job.DecodeArgs(&unique, &indexName, &indexPartSpecifications, &indexOption, &sqlMode, &warnings, &global)
 	} else {
-		err = job.DecodeArgs(&unique, &indexName, &indexPartSpecifications, &indexOption, &hiddenCols, &global)
+		err = job.DecodeArgs(&unique, &indexName, &indexPartSpecifications, &indexOption, &hiddenCols, &global, &preSplitRegions)
 	}
 	if err != nil {
 		job.State = model.JobStateCancelled
@@ -446,7 +495,11 @@ func (w *worker) onCreateIndex(d *ddlCtx, t *meta.Meta, job *model.Job, isPK boo
 			job.State = model.JobStateCancelled
 			return ver, errors.Trace(err)
 		}
-		indexInfo, err = buildIndexInfo(tblInfo, indexName, indexPartSpecifications, model.StateNone)
+		if indexOption != nil && indexOption.Tp == model.IndexTypeFulltext {
+			indexInfo, err = buildFulltextIndexInfo(tblInfo, indexName, indexPartSpecifications, model.StateNone)
+		} else {
+			indexInfo, err = buildIndexInfo(tblInfo, indexName, indexPartSpecifications, model.StateNone)
+		}
 		if err != nil {
 			job.State = model.JobStateCancelled
 			return ver, errors.Trace(err)
@@ -517,6 +570,11 @@ func (w *worker) onCreateIndex(d *ddlCtx, t *meta.Meta, job *model.Job, isPK boo
 		}
 		job.SchemaState = model.StateWriteOnly
 	case model.StateWriteOnly:
+		// The index is split before it's backfilled, so the backfill writes are spread over the regions.
+		// The entries of the FULLTEXT index are the words, which the column statistics don't tell.
+		if preSplitRegions > 1 && !fulltext.IsIndex(indexInfo) {
+			w.preSplitIndexRegions(d, tblInfo, indexInfo, preSplitRegions)
+		}
 		// write only -> reorganization
 		indexInfo.State = model.StateWriteReorganization
 		updateHiddenColumns(tblInfo, indexInfo, model.StateWriteReorganization)
@@ -534,44 +592,20 @@ func (w *worker) onCreateIndex(d *ddlCtx, t *meta.Meta, job *model.Job, isPK boo
 	case model.StateWriteReorganization:
 		// reorganization -> public
 		updateHiddenColumns(tblInfo, indexInfo, model.StatePublic)
-		tbl, err := getTable(d.store, schemaID, tblInfo)
-		if err != nil {
-			return ver, errors.Trace(err)
-		}
-
-		elements := []*meta.Element{{ID: indexInfo.ID, TypeKey: meta.IndexElementKey}}
-		reorgInfo, err := getReorgInfo(d, t, job, tbl, elements)
-		if err != nil || reorgInfo.first {
-			// If we run reorg firstly, we should update the job snapshot version
-			// and then run the reorg next time.
-			return ver, errors.Trace(err)
-		}
-
-		err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (addIndexErr error) {
-			defer util.Recover(metrics.LabelDDL, "onCreateIndex",
-				func() {
-					addIndexErr = errCancelledDDLJob.GenWithStack("add table `%v` index `%v` panic", tblInfo.Name, indexInfo.Name)
-				}, false)
-			return w.addTableIndex(tbl, indexInfo, reorgInfo)
-		})
-		if err != nil {
-			if errWaitReorgTimeout.Equal(err) {
-				// if timeout, we should return, check for the owner and re-wait job done.
-				return ver, nil
+		if w.subJob == nil || w.subJob.Revertible {
+			var done bool
+			done, ver, err = w.doReorgWorkForCreateIndex(d, t, job, tblInfo, indexInfo)
+			if !done {
+				return ver, err
 			}
-			if kv.ErrKeyExists.Equal(err) || errCancelledDDLJob.Equal(err) || errCantDecodeRecord.Equal(err) {
-				logutil.BgLogger().Warn("[ddl] run add index job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
-				ver, err = convertAddIdxJob2RollbackJob(t, job, tblInfo, indexInfo, err)
-				if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
-					logutil.BgLogger().Warn("[ddl] run add index job failed, convert job to rollback, RemoveDDLReorgHandle failed", zap.String("job", job.String()), zap.Error(err1))
-				}
+			if w.subJob != nil {
+				// The index is backfilled, it becomes public in the non-revertible phase of the multi-schema change.
+				// The reorg handle is removed, so the job is rolled back like the one which isn't backfilled.
+				w.subJob.Revertible = false
+				job.SnapshotVer = 0
+				return ver, nil
 			}
-			// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
-			w.reorgCtx.cleanNotifyReorgCancel()
-			return ver, errors.Trace(err)
 		}
-		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
-		w.reorgCtx.cleanNotifyReorgCancel()
 
 		indexInfo.State = model.StatePublic
 		// Set column index flag.
@@ -594,6 +628,50 @@ func (w *worker) onCreateIndex(d *ddlCtx, t *meta.Meta, job *model.Job, isPK boo
 	return ver, errors.Trace(err)
 }
 
+// doReorgWorkForCreateIndex backfills the index, done is true when all the rows are backfilled.
+func (w *worker) doReorgWorkForCreateIndex(d *ddlCtx, t *meta.Meta, job *model.Job,
+	tblInfo *model.TableInfo, indexInfo *model.IndexInfo) (done bool, ver int64, err error) {
+	tbl, err := getTable(d.store, job.SchemaID, tblInfo)
+	if err != nil {
+		return false, ver, errors.Trace(err)
+	}
+
+	elements := []*meta.Element{{ID: indexInfo.ID, TypeKey: meta.IndexElementKey}}
+	reorgInfo, err := getReorgInfo(d, t, job, tbl, elements)
+	if err != nil || reorgInfo.first {
+		// If we run reorg firstly, we should update the job snapshot version
+		// and then run the reorg next time.
+		return false, ver, errors.Trace(err)
+	}
+
+	err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (addIndexErr error) {
+		defer util.Recover(metrics.LabelDDL, "onCreateIndex",
+			func() {
+				addIndexErr = errCancelledDDLJob.GenWithStack("add table `%v` index `%v` panic", tblInfo.Name, indexInfo.Name)
+			}, false)
+		return w.addTableIndex(tbl, indexInfo, reorgInfo)
+	})
+	if err != nil {
+		if errWaitReorgTimeout.Equal(err) {
+			// if timeout, we should return, check for the owner and re-wait job done.
+			return false, ver, nil
+		}
+		if kv.ErrKeyExists.Equal(err) || errCancelledDDLJob.Equal(err) || errCantDecodeRecord.Equal(err) {
+			logutil.BgLogger().Warn("[ddl] run add index job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
+			ver, err = convertAddIdxJob2RollbackJob(t, job, tblInfo, indexInfo, err)
+			if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
+				logutil.BgLogger().Warn("[ddl] run add index job failed, convert job to rollback, RemoveDDLReorgHandle failed", zap.String("job", job.String()), zap.Error(err1))
+			}
+		}
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+		return false, ver, errors.Trace(err)
+	}
+	// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+	w.reorgCtx.cleanNotifyReorgCancel()
+	return true, ver, nil
+}
+
 func onDropIndex(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	tblInfo, indexInfo, err := checkDropIndex(t, job)
 	if err != nil {
@@ -815,6 +893,7 @@ type indexRecord struct {
 	vals   []types.Datum // It's the index values.
 	rsData []types.Datum // It's the restored data for handle.
 	skip   bool          // skip indicates that the index key is already exists, we should not add it.
+	row    []byte        // It's the raw row value, used to check whether the row is changed before ingesting the index.
 }
 
 type baseIndexWorker struct {
@@ -835,6 +914,8 @@ type baseIndexWorker struct {
 type addIndexWorker struct {
 	baseIndexWorker
 	index table.Index
+	// engine is not nil if the index is backfilled by the local sorted ingest.
+	engine *ingest.Engine
 
 	// The following attributes are used to reduce memory allocation.
 	idxKeyBufs         [][]byte
@@ -983,6 +1064,7 @@ func (w *baseIndexWorker) fetchRowColVals(txn kv.Transaction, taskRange reorgBac
 				if err1 != nil {
 					return false, errors.Trace(err1)
 				}
+				idxRecord.row = rawRow
 				w.idxRecords = append(w.idxRecords, idxRecord)
 			}
 			// If there are generated column, rowDecoder will use column value that not in idxInfo.Columns to calculate
@@ -1015,9 +1097,10 @@ func (w *addIndexWorker) initBatchCheckBufs(batchCount int) {
 	w.distinctCheckFlags = w.distinctCheckFlags[:0]
 }
 
-func (w *addIndexWorker) checkHandleExists(key kv.Key, value []byte, handle kv.Handle) error {
-	idxInfo := w.index.Meta()
-	tblInfo := w.table.Meta()
+// checkHandleExists checks whether the existing index entry is generated by the handle, and
+// returns the duplicate entry error if not.
+func checkHandleExists(index table.Index, tblInfo *model.TableInfo, key kv.Key, value []byte, handle kv.Handle) error {
+	idxInfo := index.Meta()
 	idxColLen := len(idxInfo.Columns)
 	h, err := tablecodec.DecodeIndexHandle(key, value, idxColLen)
 	if err != nil {
@@ -1032,7 +1115,7 @@ func (w *addIndexWorker) checkHandleExists(key kv.Key, value []byte, handle kv.H
 	if err != nil {
 		return err
 	}
-	indexName := w.index.Meta().Name.String()
+	indexName := idxInfo.Name.String()
 	valueStr := make([]string, 0, idxColLen)
 	for i, val := range values[:idxColLen] {
 		d, err := tablecodec.DecodeColumnValue(val, colInfos[i].Ft, time.Local)
@@ -1081,7 +1164,7 @@ func (w *addIndexWorker) batchCheckUniqueKey(txn kv.Transaction, idxRecords []*i
 	for i, key := range w.batchCheckKeys {
 		if val, found := batchVals[string(key)]; found {
 			if w.distinctCheckFlags[i] {
-				if err := w.checkHandleExists(key, val, idxRecords[i].handle); err != nil {
+				if err := checkHandleExists(w.index, w.table.Meta(), key, val, idxRecords[i].handle); err != nil {
 					return errors.Trace(err)
 				}
 			}
@@ -1113,6 +1196,10 @@ func (w *addIndexWorker) BackfillDataInTxn(handleRange reorgBackfillTask) (taskC
 		}
 	})
 
+	if w.engine != nil {
+		return w.backfillDataInEngine(handleRange)
+	}
+
 	oprStartTime := time.Now()
 	errInTxn = kv.RunInNewTxn(context.Background(), w.sessCtx.GetStore(), true, func(ctx context.Context, txn kv.Transaction) error {
 		taskCtx.addedCount = 0
@@ -1400,8 +1487,12 @@ func (w *worker) updateReorgInfoForPartitions(t table.PartitionedTable, reorg *r
 			if i == len(partitionIDs)-1 {
 				return true, nil
 			}
+			pid = partitionIDs[i+1]
+			break
 		}
-		pid = partitionIDs[i+1]
+	}
+	if pid == 0 {
+		return false, errors.Errorf("partition id not found %d", reorg.PhysicalTableID)
 	}
 
 	currentVer, err := getValidCurrentVersion(reorg.d.store)
@@ -0,0 +1,259 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/ddl/ingest"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	tikvstore "github.com/pingcap/tidb/store/tikv/kv"
+	"github.com/pingcap/tidb/store/tikv/oracle"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/util/fulltext"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// By now the index is backfilled in transactions, each transaction reads a batch of rows, checks
+// the duplications of the unique index by reading the index, and writes the index entries of
+// these rows. When "tidb_ddl_enable_fast_reorg" is on, the backfill workers only read the rows
+// and write the index entries into a local sorted engine, the duplications among the backfilled
+// rows are detected in the engine. After each round of backfill tasks, the worker master writes
+// the entries into the storage in the order of keys, so each transaction only touches a few
+// regions, and then moves the reorg handle forward. The entries are not ingested as SST files,
+// it's a buffered write, and the reorg handle is the checkpoint of the written entries: the
+// entries in the engine are dropped if the DDL owner changes, and are generated again.
+//
+// The rows may be changed by DML after they are read by the backfill workers. Since the DML in the
+// write reorganization state maintains the index by itself, an entry is written only if its row
+// is unchanged, and the row is locked until the entry is committed.
+
+// indexIngester writes the index entries buffered in the local sorted engine into the storage.
+type indexIngester struct {
+	engine   *ingest.Engine
+	store    kv.Storage
+	tblInfo  *model.TableInfo
+	index    table.Index
+	priority int
+}
+
+func newIndexIngester(store kv.Storage, t table.PhysicalTable, indexInfo *model.IndexInfo, job *model.Job) (*indexIngester, error) {
+	dir := filepath.Join(config.GetGlobalConfig().TempStoragePath, "ddl-ingest",
+		fmt.Sprintf("%d-%d-%d", job.ID, t.GetPhysicalID(), indexInfo.ID))
+	engine, err := ingest.OpenEngine(dir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &indexIngester{
+		engine:   engine,
+		store:    store,
+		tblInfo:  t.Meta(),
+		index:    tables.NewIndex(t.GetPhysicalID(), t.Meta(), indexInfo),
+		priority: job.Priority,
+	}, nil
+}
+
+// canIngestIndex checks whether the index can be backfilled by the local sorted engine.
+func canIngestIndex(indexInfo *model.IndexInfo) bool {
+	// The value of the global index depends on the partition which is handled, keep it simple.
+	// A row has an entry for each word in the FULLTEXT index, which is only created by table.Index.
+	return indexInfo != nil && !indexInfo.Global && !fulltext.IsIndex(indexInfo)
+}
+
+// ingest writes all the entries in the engine into the storage, and returns the number of the
+// written entries, which includes the ones written before an error is returned.
+func (ig *indexIngester) ingest() (int, error) {
+	startTime := time.Now()
+	cnt := ig.engine.Count()
+	written := 0
+	err := ig.engine.Import(int(variable.GetDDLReorgBatchSize()), func(kvs []ingest.KV) error {
+		n, err := ig.writeBatch(kvs)
+		written += n
+		return err
+	})
+	logSlowOperations(time.Since(startTime), "writeBufferedIndexKVs", 3000)
+	logutil.BgLogger().Info("[ddl] write the buffered index entries", zap.String("index", ig.index.Meta().Name.O),
+		zap.Int("count", cnt), zap.Int("written", written), zap.Duration("takeTime", time.Since(startTime)), zap.Error(err))
+	return written, errors.Trace(err)
+}
+
+// writeBatch writes a batch of entries in a transaction which starts at the earliest snapshot the
+// rows are read from, so the rows and the index entries changed by DML after that conflict with it,
+// and the rows don't have to be read again. If it conflicts, which is rare, the batch is written
+// again in a new transaction, which reads the rows and skips the entries of the changed ones.
+func (ig *indexIngester) writeBatch(kvs []ingest.KV) (int, error) {
+	readTS := kvs[0].ReadTS
+	for _, entry := range kvs[1:] {
+		if entry.ReadTS < readTS {
+			readTS = entry.ReadTS
+		}
+	}
+	ctx := context.Background()
+	txn, err := ig.store.BeginWithOption(kv.TransactionOption{}.SetTxnScope(oracle.GlobalTxnScope).SetStartTs(readTS))
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	written, err := ig.writeEntries(ctx, txn, kvs, nil)
+	if err != nil {
+		terror.Log(txn.Rollback())
+	} else {
+		err = txn.Commit(ctx)
+	}
+	if err == nil || !kv.IsTxnRetryableError(err) {
+		if err != nil {
+			written = 0
+		}
+		return written, errors.Trace(err)
+	}
+
+	logutil.BgLogger().Info("[ddl] rows are changed after they are read, check them before writing the index entries",
+		zap.String("index", ig.index.Meta().Name.O), zap.Error(err))
+	rowKeys := make([]kv.Key, 0, len(kvs))
+	for _, entry := range kvs {
+		rowKeys = append(rowKeys, entry.RowKey)
+	}
+	err = kv.RunInNewTxn(ctx, ig.store, true, func(ctx context.Context, txn kv.Transaction) error {
+		rows, err := txn.BatchGet(ctx, rowKeys)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		written, err = ig.writeEntries(ctx, txn, kvs, rows)
+		return errors.Trace(err)
+	})
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return written, nil
+}
+
+// writeEntries writes the entries in txn and locks their rows to notify us that someone deletes
+// or updates the rows before the entries are committed. If rows is not nil, the entries whose rows
+// are deleted or changed are skipped, the DML has maintained the index of them. It returns the
+// number of the written entries.
+func (ig *indexIngester) writeEntries(ctx context.Context, txn kv.Transaction, kvs []ingest.KV, rows map[string][]byte) (int, error) {
+	txn.SetOption(tikvstore.Priority, ig.priority)
+	// The key of a non-unique index entry contains the handle, it's generated by the same row
+	// if it exists, so only the unique index needs to be checked.
+	var idxVals map[string][]byte
+	if ig.index.Meta().Unique {
+		idxKeys := make([]kv.Key, 0, len(kvs))
+		for _, entry := range kvs {
+			idxKeys = append(idxKeys, entry.Key)
+		}
+		var err error
+		if idxVals, err = txn.BatchGet(ctx, idxKeys); err != nil {
+			return 0, errors.Trace(err)
+		}
+	}
+
+	lockKeys := make([]kv.Key, 0, len(kvs))
+	for _, entry := range kvs {
+		if rows != nil {
+			if row, ok := rows[string(entry.RowKey)]; !ok || !bytes.Equal(ingest.RowDigest(row), entry.RowDigest) {
+				continue
+			}
+		}
+		if val, ok := idxVals[string(entry.Key)]; ok {
+			// The entry is already written by the DML or a former round.
+			handle, err := tablecodec.DecodeRowKey(entry.RowKey)
+			if err != nil {
+				return 0, errors.Trace(err)
+			}
+			if err = checkHandleExists(ig.index, ig.tblInfo, entry.Key, val, handle); err != nil {
+				return 0, errors.Trace(err)
+			}
+			continue
+		}
+		lockKeys = append(lockKeys, entry.RowKey)
+		if err := txn.Set(entry.Key, entry.Val); err != nil {
+			return 0, errors.Trace(err)
+		}
+	}
+	if len(lockKeys) == 0 {
+		return 0, nil
+	}
+	return len(lockKeys), errors.Trace(txn.LockKeys(ctx, new(kv.LockCtx), lockKeys...))
+}
+
+func (ig *indexIngester) close() {
+	if err := ig.engine.Close(); err != nil {
+		logutil.BgLogger().Warn("[ddl] close the ingest engine failed", zap.Error(err))
+	}
+}
+
+// backfillDataInEngine reads a batch of rows and writes their index entries into the local sorted engine.
+func (w *addIndexWorker) backfillDataInEngine(handleRange reorgBackfillTask) (taskCtx backfillTaskContext, err error) {
+	oprStartTime := time.Now()
+	defer func() {
+		logSlowOperations(time.Since(oprStartTime), "AddIndexBackfillDataInEngine", 3000)
+	}()
+
+	// The transaction is only used to get a snapshot of the rows.
+	txn, err := w.sessCtx.GetStore().Begin()
+	if err != nil {
+		return taskCtx, errors.Trace(err)
+	}
+	defer func() {
+		if err1 := txn.Rollback(); err1 != nil {
+			logutil.BgLogger().Warn("[ddl] rollback the snapshot transaction failed", zap.Error(err1))
+		}
+	}()
+	idxRecords, nextKey, taskDone, err := w.fetchRowColVals(txn, handleRange)
+	if err != nil {
+		return taskCtx, errors.Trace(err)
+	}
+	taskCtx.nextKey = nextKey
+	taskCtx.done = taskDone
+
+	stmtCtx := w.sessCtx.GetSessionVars().StmtCtx
+	tblInfo, idxInfo := w.table.Meta(), w.index.Meta()
+	needRsData := tables.NeedRestoredData(idxInfo.Columns, tblInfo.Columns)
+	kvs := make([]ingest.KV, 0, len(idxRecords))
+	for _, record := range idxRecords {
+		taskCtx.scanCount++
+		key, distinct, err := w.index.GenIndexKey(stmtCtx, record.vals, record.handle, nil)
+		if err != nil {
+			return taskCtx, errors.Trace(err)
+		}
+		val, err := tablecodec.GenIndexValuePortal(stmtCtx, tblInfo, idxInfo, needRsData, distinct, false, record.vals, record.handle, 0, record.rsData)
+		if err != nil {
+			return taskCtx, errors.Trace(err)
+		}
+		kvs = append(kvs, ingest.KV{Key: key, Val: val, RowKey: record.key, RowDigest: ingest.RowDigest(record.row), ReadTS: txn.StartTS()})
+	}
+	if err = w.engine.WriteBatch(kvs); err != nil {
+		if dupErr, ok := errors.Cause(err).(*ingest.DuplicateKeyError); ok {
+			handle, err1 := tablecodec.DecodeRowKey(dupErr.RowKey)
+			if err1 != nil {
+				return taskCtx, errors.Trace(err1)
+			}
+			return taskCtx, errors.Trace(checkHandleExists(w.index, tblInfo, dupErr.Key, dupErr.Val, handle))
+		}
+		return taskCtx, errors.Trace(err)
+	}
+	// The entries are counted when they are written into the storage by the indexIngester.
+	return taskCtx, nil
+}
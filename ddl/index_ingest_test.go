@@ -0,0 +1,138 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"context"
+	"path/filepath"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/ddl/ingest"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+)
+
+var _ = Suite(&testIndexIngestSuite{})
+
+type testIndexIngestSuite struct {
+	store  kv.Storage
+	dbInfo *model.DBInfo
+}
+
+func (s *testIndexIngestSuite) SetUpSuite(c *C) {
+	s.store = testCreateStore(c, "test_index_ingest")
+	s.dbInfo = &model.DBInfo{
+		Name: model.NewCIStr("test_index_ingest"),
+		ID:   1,
+	}
+	err := kv.RunInNewTxn(context.Background(), s.store, true, func(ctx context.Context, txn kv.Transaction) error {
+		t := meta.NewMeta(txn)
+		return errors.Trace(t.CreateDatabase(s.dbInfo))
+	})
+	c.Check(err, IsNil, Commentf("err %v", errors.ErrorStack(err)))
+}
+
+func (s *testIndexIngestSuite) TearDownSuite(c *C) {
+	s.store.Close()
+}
+
+func (s *testIndexIngestSuite) TestWriteBufferedEntries(c *C) {
+	d := testNewDDLAndStart(
+		context.Background(),
+		c,
+		WithStore(s.store),
+		WithLease(testLease),
+	)
+	defer func() {
+		err := d.Stop()
+		c.Assert(err, IsNil)
+	}()
+	// create table t (c1 int primary key, c2 int);
+	tblInfo := testTableInfo(c, d, "t", 2)
+	tblInfo.Columns[0].Flag = mysql.PriKeyFlag | mysql.NotNullFlag
+	tblInfo.PKIsHandle = true
+	ctx := testNewContext(d)
+	c.Assert(ctx.NewTxn(context.Background()), IsNil)
+	testCreateTable(c, ctx, d, s.dbInfo, tblInfo)
+	tbl := testGetTable(c, d, s.dbInfo.ID, tblInfo.ID)
+
+	// insert t values (1, 1), (2, 2), (3, 3)
+	c.Assert(ctx.NewTxn(context.Background()), IsNil)
+	for i := int64(1); i <= 3; i++ {
+		_, err := tbl.AddRecord(ctx, types.MakeDatums(i, i))
+		c.Assert(err, IsNil)
+	}
+	txn, err := ctx.Txn(true)
+	c.Assert(err, IsNil)
+	c.Assert(txn.Commit(context.Background()), IsNil)
+
+	// Buffer the entries of the index on c2 like the backfill workers.
+	idxInfo := &model.IndexInfo{
+		ID:      1,
+		Name:    model.NewCIStr("idx"),
+		Columns: []*model.IndexColumn{{Name: tblInfo.Columns[1].Name, Offset: 1, Length: types.UnspecifiedLength}},
+		State:   model.StatePublic,
+	}
+	ig, err := newIndexIngester(s.store, tbl.(table.PhysicalTable), idxInfo, &model.Job{ID: 1})
+	c.Assert(err, IsNil)
+	c.Assert(ig.engine.Close(), IsNil)
+	ig.engine, err = ingest.OpenEngine(filepath.Join(c.MkDir(), "engine"))
+	c.Assert(err, IsNil)
+	defer ig.close()
+
+	snapshot, err := s.store.Begin()
+	c.Assert(err, IsNil)
+	kvs := make([]ingest.KV, 0, 3)
+	idxKeys := make([]kv.Key, 0, 3)
+	for i := int64(1); i <= 3; i++ {
+		handle := kv.IntHandle(i)
+		rowKey := tablecodec.EncodeRecordKey(tbl.RecordPrefix(), handle)
+		row, err := snapshot.Get(context.Background(), rowKey)
+		c.Assert(err, IsNil)
+		key, _, err := ig.index.GenIndexKey(ctx.GetSessionVars().StmtCtx, types.MakeDatums(i), handle, nil)
+		c.Assert(err, IsNil)
+		kvs = append(kvs, ingest.KV{Key: key, Val: []byte{'0'}, RowKey: rowKey, RowDigest: ingest.RowDigest(row), ReadTS: snapshot.StartTS()})
+		idxKeys = append(idxKeys, key)
+	}
+	c.Assert(snapshot.Rollback(), IsNil)
+	c.Assert(ig.engine.WriteBatch(kvs), IsNil)
+
+	// The row 2 is updated after it's read, the DML has maintained the index of it.
+	c.Assert(ctx.NewTxn(context.Background()), IsNil)
+	err = tbl.UpdateRecord(context.Background(), ctx, kv.IntHandle(2), types.MakeDatums(2, 2), types.MakeDatums(2, 20), []bool{false, true})
+	c.Assert(err, IsNil)
+	txn, err = ctx.Txn(true)
+	c.Assert(err, IsNil)
+	c.Assert(txn.Commit(context.Background()), IsNil)
+
+	// Only the entries of the unchanged rows are written and counted.
+	written, err := ig.ingest()
+	c.Assert(err, IsNil)
+	c.Assert(written, Equals, 2)
+	c.Assert(ig.engine.Count(), Equals, 0)
+	txn, err = s.store.Begin()
+	c.Assert(err, IsNil)
+	vals, err := txn.BatchGet(context.Background(), idxKeys)
+	c.Assert(err, IsNil)
+	c.Assert(txn.Rollback(), IsNil)
+	c.Assert(vals, HasLen, 2)
+	c.Assert(vals[string(idxKeys[0])], NotNil)
+	c.Assert(vals[string(idxKeys[2])], NotNil)
+}
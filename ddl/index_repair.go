@@ -0,0 +1,308 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// An index is repaired by rebuilding it from the records of the table. A copy of the index with a new ID
+// is added like a changing index of the modify column job: it goes through the delete-only and write-only
+// states so it's written along with the old index, then it's backfilled in the write-reorganization state.
+// At last, it takes the name and the position of the old index, and the old index data is cleaned up by
+// the delete range.
+
+// RepairIndex rebuilds the index from the records of the table, the index is kept available while it's rebuilt.
+func (d *ddl) RepairIndex(ctx sessionctx.Context, ident ast.Ident, indexName model.CIStr) error {
+	is := d.infoHandle.Get()
+	schema, ok := is.SchemaByName(ident.Schema)
+	if !ok {
+		return errors.Trace(infoschema.ErrDatabaseNotExists.GenWithStackByArgs(ident.Schema))
+	}
+	t, err := is.TableByName(ident.Schema, ident.Name)
+	if err != nil {
+		return errors.Trace(infoschema.ErrTableNotExists.GenWithStackByArgs(ident.Schema, ident.Name))
+	}
+	indexInfo := t.Meta().FindIndexByName(indexName.L)
+	if indexInfo == nil {
+		return ErrCantDropFieldOrKey.GenWithStack("index %s doesn't exist", indexName)
+	}
+	if err = checkRepairIndex(t.Meta(), indexInfo); err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    t.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionRepairIndex,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{indexInfo.Name},
+		ReorgMeta: &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+		},
+		Priority: ctx.GetSessionVars().DDLReorgPriority,
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+func checkRepairIndex(tblInfo *model.TableInfo, indexInfo *model.IndexInfo) error {
+	if indexInfo.State != model.StatePublic {
+		return errUnsupportedRepairIndex.GenWithStackByArgs("the index is being changed by another DDL job")
+	}
+	if indexInfo.Primary && tblInfo.IsCommonHandle {
+		return errUnsupportedRepairIndex.GenWithStackByArgs("the clustered index is the table records")
+	}
+	if indexInfo.Global {
+		return errUnsupportedRepairIndex.GenWithStackByArgs("global index")
+	}
+	return nil
+}
+
+func findIndexByID(indices []*model.IndexInfo, id int64) *model.IndexInfo {
+	for _, idx := range indices {
+		if idx.ID == id {
+			return idx
+		}
+	}
+	return nil
+}
+
+func getRepairIndexInfo(t *meta.Meta, job *model.Job) (tblInfo *model.TableInfo, indexName model.CIStr, repairingIdx *model.IndexInfo, err error) {
+	var repairingIdxID int64
+	if err = job.DecodeArgs(&indexName, &repairingIdxID); err != nil {
+		job.State = model.JobStateCancelled
+		return nil, indexName, nil, errors.Trace(err)
+	}
+	tblInfo, err = getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return nil, indexName, nil, errors.Trace(err)
+	}
+	if repairingIdxID != 0 {
+		repairingIdx = findIndexByID(tblInfo.Indices, repairingIdxID)
+	}
+	return tblInfo, indexName, repairingIdx, nil
+}
+
+func (w *worker) onRepairIndex(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	tblInfo, indexName, repairingIdx, err := getRepairIndexInfo(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if job.IsRollingback() {
+		return rollbackRepairIndex(t, job, tblInfo, repairingIdx)
+	}
+
+	indexInfo := tblInfo.FindIndexByName(indexName.L)
+	if indexInfo == nil {
+		if repairingIdx != nil {
+			job.State = model.JobStateRollingback
+		} else {
+			job.State = model.JobStateCancelled
+		}
+		return ver, ErrCantDropFieldOrKey.GenWithStack("index %s doesn't exist", indexName)
+	}
+	if repairingIdx == nil {
+		if err = checkRepairIndex(tblInfo, indexInfo); err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		repairingIdx = indexInfo.Clone()
+		repairingIdx.ID = allocateIndexID(tblInfo)
+		repairingIdx.Name = model.NewCIStr(genChangingIndexUniqueName(tblInfo, indexInfo))
+		repairingIdx.State = model.StateNone
+		tblInfo.Indices = append(tblInfo.Indices, repairingIdx)
+		logutil.BgLogger().Info("[ddl] run repair index job", zap.String("job", job.String()), zap.Reflect("indexInfo", repairingIdx))
+	}
+
+	originalState := repairingIdx.State
+	switch repairingIdx.State {
+	case model.StateNone:
+		// none -> delete only
+		repairingIdx.State = model.StateDeleteOnly
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, originalState != repairingIdx.State)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		// Make sure job args change after `updateVersionAndTableInfoWithCheck`, otherwise, the job args will
+		// be updated in `updateDDLJob` even if it meets an error in `updateVersionAndTableInfoWithCheck`.
+		job.SchemaState = model.StateDeleteOnly
+		job.Args = []interface{}{indexName, repairingIdx.ID}
+		metrics.GetBackfillProgressByLabel(metrics.LblAddIndex).Set(0)
+	case model.StateDeleteOnly:
+		// delete only -> write only
+		repairingIdx.State = model.StateWriteOnly
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != repairingIdx.State)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.SchemaState = model.StateWriteOnly
+	case model.StateWriteOnly:
+		// write only -> reorganization
+		repairingIdx.State = model.StateWriteReorganization
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != repairingIdx.State)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		// Initialize SnapshotVer to 0 for later reorganization check.
+		job.SnapshotVer = 0
+		job.SchemaState = model.StateWriteReorganization
+	case model.StateWriteReorganization:
+		var done bool
+		done, ver, err = w.doReorgWorkForRepairIndex(d, t, job, tblInfo, repairingIdx)
+		if !done {
+			return ver, errors.Trace(err)
+		}
+
+		// Replace the old index with the repaired one.
+		oldIdxID := indexInfo.ID
+		repairingIdx.Name = indexInfo.Name
+		repairingIdx.State = model.StatePublic
+		indices := make([]*model.IndexInfo, 0, len(tblInfo.Indices)-1)
+		for _, idx := range tblInfo.Indices {
+			switch idx.ID {
+			case oldIdxID:
+				indices = append(indices, repairingIdx)
+			case repairingIdx.ID:
+			default:
+				indices = append(indices, idx)
+			}
+		}
+		tblInfo.Indices = indices
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != repairingIdx.State)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		// Finish this job.
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+		// Refactor the job args to add the old index id into delete range table.
+		job.Args = []interface{}{[]int64{oldIdxID}, getPartitionIDs(tblInfo)}
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("index", repairingIdx.State)
+	}
+	return ver, errors.Trace(err)
+}
+
+// doReorgWorkForRepairIndex backfills the repairing index, done is true when all the rows are backfilled.
+func (w *worker) doReorgWorkForRepairIndex(d *ddlCtx, t *meta.Meta, job *model.Job,
+	tblInfo *model.TableInfo, repairingIdx *model.IndexInfo) (done bool, ver int64, err error) {
+	tbl, err := getTable(d.store, job.SchemaID, tblInfo)
+	if err != nil {
+		return false, ver, errors.Trace(err)
+	}
+
+	elements := []*meta.Element{{ID: repairingIdx.ID, TypeKey: meta.IndexElementKey}}
+	reorgInfo, err := getReorgInfo(d, t, job, tbl, elements)
+	if err != nil || reorgInfo.first {
+		// If we run reorg firstly, we should update the job snapshot version
+		// and then run the reorg next time.
+		return false, ver, errors.Trace(err)
+	}
+
+	err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (repairIndexErr error) {
+		defer util.Recover(metrics.LabelDDL, "onRepairIndex",
+			func() {
+				repairIndexErr = errCancelledDDLJob.GenWithStack("repair table `%v` index `%v` panic", tblInfo.Name, repairingIdx.Name)
+			}, false)
+		return w.addTableIndex(tbl, repairingIdx, reorgInfo)
+	})
+	if err != nil {
+		if errWaitReorgTimeout.Equal(err) {
+			// if timeout, we should return, check for the owner and re-wait job done.
+			return false, ver, nil
+		}
+		if kv.ErrKeyExists.Equal(err) || errCancelledDDLJob.Equal(err) || errCantDecodeRecord.Equal(err) {
+			logutil.BgLogger().Warn("[ddl] run repair index job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
+			job.State = model.JobStateRollingback
+			if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
+				logutil.BgLogger().Warn("[ddl] run repair index job failed, convert job to rollback, RemoveDDLReorgHandle failed", zap.String("job", job.String()), zap.Error(err1))
+			}
+		}
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+		return false, ver, errors.Trace(err)
+	}
+	// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+	w.reorgCtx.cleanNotifyReorgCancel()
+	return true, ver, nil
+}
+
+// rollbackRepairIndex removes the repairing index, the old index is kept as it is.
+func rollbackRepairIndex(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, repairingIdx *model.IndexInfo) (ver int64, err error) {
+	var idxIDs []int64
+	if repairingIdx != nil {
+		idxIDs = append(idxIDs, repairingIdx.ID)
+		indices := make([]*model.IndexInfo, 0, len(tblInfo.Indices)-1)
+		for _, idx := range tblInfo.Indices {
+			if idx.ID != repairingIdx.ID {
+				indices = append(indices, idx)
+			}
+		}
+		tblInfo.Indices = indices
+		// The job may be cancelled after the reorg info is initialized but before the backfill workers are started,
+		// so the reorg handle of the repairing index should be cleaned up here.
+		if job.SnapshotVer != 0 {
+			elements := []*meta.Element{{ID: repairingIdx.ID, TypeKey: meta.IndexElementKey}}
+			if err = t.RemoveDDLReorgHandle(job, elements); err != nil {
+				return ver, errors.Trace(err)
+			}
+		}
+	}
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+	// Refactor the job args to add the abandoned index id into delete range table.
+	job.Args = []interface{}{idxIDs, getPartitionIDs(tblInfo)}
+	return ver, nil
+}
+
+// rollingbackRepairIndex handles the repair index job which is cancelled by the users.
+func rollingbackRepairIndex(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	_, _, repairingIdx, err := getRepairIndexInfo(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if repairingIdx == nil {
+		// The job hasn't been handled and we cancel it directly.
+		job.State = model.JobStateCancelled
+		return ver, errCancelledDDLJob
+	}
+	// If the value of SnapshotVer isn't zero, it means the work is backfilling the repairing index.
+	if job.SchemaState == model.StateWriteReorganization && job.SnapshotVer != 0 {
+		// Repair index workers are started. need to ask them to exit.
+		logutil.Logger(w.logCtx).Info("[ddl] run the cancelling DDL job", zap.String("job", job.String()))
+		w.reorgCtx.notifyReorgCancel()
+		return w.onRepairIndex(d, t, job)
+	}
+	job.State = model.JobStateRollingback
+	return ver, errCancelledDDLJob
+}
@@ -0,0 +1,232 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/goleveldb/leveldb"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/twmb/murmur3"
+)
+
+// KV is an index entry buffered in the engine.
+type KV struct {
+	// Key and Val are the index key and value.
+	Key []byte
+	Val []byte
+	// RowKey is the key of the row which the index entry is generated from.
+	RowKey []byte
+	// RowDigest is the digest of the raw row value when the index entry is generated, and ReadTS is
+	// the timestamp of the snapshot which the row is read from. They are used to check whether the
+	// row is changed before the index entry is written.
+	RowDigest []byte
+	ReadTS    uint64
+}
+
+// RowDigest returns the digest of a raw row value, which is kept in the engine instead of the row.
+func RowDigest(row []byte) []byte {
+	h1, h2 := murmur3.Sum128(row)
+	digest := make([]byte, 16)
+	binary.BigEndian.PutUint64(digest, h1)
+	binary.BigEndian.PutUint64(digest[8:], h2)
+	return digest
+}
+
+// DuplicateKeyError is returned by WriteBatch if two different rows generate the same index key.
+type DuplicateKeyError struct {
+	Key []byte
+	// Val is the index value which is already in the engine.
+	Val []byte
+	// RowKey is the key of the row which generates the entry being written.
+	RowKey []byte
+}
+
+// Error implements the error interface.
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("duplicate index key %X in the local engine", e.Key)
+}
+
+// Engine is a local sorted buffer of the index entries generated by the backfill workers, which is
+// a goleveldb database in a temporary directory. The entries are taken out in the order of keys, so
+// a batch written into the storage only touches a few regions, and the duplications of unique
+// indexes among the backfilled rows are detected locally instead of reading the storage. Note that
+// the entries are still written into the storage by transactions, they are not ingested as SST files.
+type Engine struct {
+	dir string
+
+	mu    sync.Mutex
+	db    *leveldb.DB
+	count int
+}
+
+// OpenEngine opens an empty engine in the directory. The entries which are left by the former
+// DDL owner are dropped, they are not ingested and will be generated again.
+func OpenEngine(dir string) (*Engine, error) {
+	e := &Engine{dir: dir}
+	if err := e.reset(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *Engine) reset() error {
+	if e.db != nil {
+		if err := e.db.Close(); err != nil {
+			return errors.Trace(err)
+		}
+		e.db = nil
+	}
+	if err := os.RemoveAll(e.dir); err != nil {
+		return errors.Trace(err)
+	}
+	db, err := leveldb.OpenFile(e.dir, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	e.db, e.count = db, 0
+	return nil
+}
+
+// WriteBatch writes the entries into the engine. An entry overwrites the one with the same key
+// generated by the same row, which happens when a backfill task is retried, otherwise a
+// DuplicateKeyError is returned.
+func (e *Engine) WriteBatch(kvs []KV) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	batch := new(leveldb.Batch)
+	pending := make(map[string][]byte, len(kvs))
+	added := 0
+	for _, entry := range kvs {
+		existed, ok := pending[string(entry.Key)]
+		if !ok {
+			val, err := e.db.Get(entry.Key, nil)
+			if err != nil && err != leveldb.ErrNotFound {
+				return errors.Trace(err)
+			}
+			existed, ok = val, err == nil
+		}
+		if ok {
+			old, err := decodeKV(entry.Key, existed)
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(old.RowKey, entry.RowKey) {
+				return &DuplicateKeyError{Key: entry.Key, Val: old.Val, RowKey: entry.RowKey}
+			}
+		} else {
+			added++
+		}
+		val := encodeKV(entry)
+		pending[string(entry.Key)] = val
+		batch.Put(entry.Key, val)
+	}
+	if err := e.db.Write(batch, nil); err != nil {
+		return errors.Trace(err)
+	}
+	e.count += added
+	return nil
+}
+
+// Count returns the number of the entries in the engine.
+func (e *Engine) Count() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.count
+}
+
+// Import calls fn with the entries in the order of keys, batchSize entries each time. The entries
+// are removed from the engine after fn returns successfully, the others are kept if it fails.
+func (e *Engine) Import(batchSize int, fn func(kvs []KV) error) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.count == 0 {
+		return nil
+	}
+
+	it := e.db.NewIterator(nil, nil)
+	defer it.Release()
+	kvs := make([]KV, 0, batchSize)
+	for {
+		kvs = kvs[:0]
+		for len(kvs) < batchSize && it.Next() {
+			// The key and value are reused by the iterator, so copy them.
+			key := append([]byte(nil), it.Key()...)
+			entry, err := decodeKV(key, append([]byte(nil), it.Value()...))
+			if err != nil {
+				return err
+			}
+			kvs = append(kvs, entry)
+		}
+		if err := it.Error(); err != nil {
+			return errors.Trace(err)
+		}
+		if len(kvs) == 0 {
+			return nil
+		}
+		if err := fn(kvs); err != nil {
+			return errors.Trace(err)
+		}
+		batch := new(leveldb.Batch)
+		for _, entry := range kvs {
+			batch.Delete(entry.Key)
+		}
+		if err := e.db.Write(batch, nil); err != nil {
+			return errors.Trace(err)
+		}
+		e.count -= len(kvs)
+	}
+}
+
+// Close closes the engine and removes its directory.
+func (e *Engine) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.db != nil {
+		if err := e.db.Close(); err != nil {
+			return errors.Trace(err)
+		}
+		e.db = nil
+	}
+	return errors.Trace(os.RemoveAll(e.dir))
+}
+
+func encodeKV(entry KV) []byte {
+	buf := make([]byte, 0, len(entry.Val)+len(entry.RowKey)+len(entry.RowDigest)+3*binary.MaxVarintLen64+8)
+	buf = codec.EncodeCompactBytes(buf, entry.Val)
+	buf = codec.EncodeCompactBytes(buf, entry.RowKey)
+	buf = codec.EncodeCompactBytes(buf, entry.RowDigest)
+	return codec.EncodeUint(buf, entry.ReadTS)
+}
+
+func decodeKV(key, val []byte) (entry KV, err error) {
+	entry.Key = key
+	if val, entry.Val, err = codec.DecodeCompactBytes(val); err != nil {
+		return entry, errors.Trace(err)
+	}
+	if val, entry.RowKey, err = codec.DecodeCompactBytes(val); err != nil {
+		return entry, errors.Trace(err)
+	}
+	if val, entry.RowDigest, err = codec.DecodeCompactBytes(val); err != nil {
+		return entry, errors.Trace(err)
+	}
+	_, entry.ReadTS, err = codec.DecodeUint(val)
+	return entry, errors.Trace(err)
+}
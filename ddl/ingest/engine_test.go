@@ -0,0 +1,93 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ingest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/errors"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testEngineSuite{})
+
+type testEngineSuite struct{}
+
+func newKV(key, rowKey string) KV {
+	return KV{Key: []byte(key), Val: []byte("v" + key), RowKey: []byte(rowKey), RowDigest: RowDigest([]byte("row" + rowKey)), ReadTS: 1}
+}
+
+func (s *testEngineSuite) TestEngine(c *C) {
+	dir := filepath.Join(c.MkDir(), "engine")
+	e, err := OpenEngine(dir)
+	c.Assert(err, IsNil)
+
+	c.Assert(e.WriteBatch([]KV{newKV("k3", "r3"), newKV("k1", "r1")}), IsNil)
+	c.Assert(e.WriteBatch([]KV{newKV("k2", "r2"), newKV("k1", "r1")}), IsNil)
+	c.Assert(e.Count(), Equals, 3)
+
+	// The same key is generated by different rows.
+	err = e.WriteBatch([]KV{newKV("k4", "r4"), newKV("k2", "r5")})
+	dupErr, ok := errors.Cause(err).(*DuplicateKeyError)
+	c.Assert(ok, IsTrue, Commentf("%v", err))
+	c.Assert(string(dupErr.Key), Equals, "k2")
+	c.Assert(string(dupErr.Val), Equals, "vk2")
+	c.Assert(string(dupErr.RowKey), Equals, "r5")
+	err = e.WriteBatch([]KV{newKV("k5", "r5"), newKV("k5", "r6")})
+	_, ok = errors.Cause(err).(*DuplicateKeyError)
+	c.Assert(ok, IsTrue, Commentf("%v", err))
+	c.Assert(e.Count(), Equals, 3)
+
+	// The entries of a failed batch are kept in the engine.
+	var imported []string
+	failK3 := true
+	importFn := func(kvs []KV) error {
+		c.Assert(len(kvs), LessEqual, 2)
+		for _, entry := range kvs {
+			c.Assert(entry.RowDigest, DeepEquals, RowDigest([]byte("row"+string(entry.RowKey))))
+			c.Assert(entry.ReadTS, Equals, uint64(1))
+			if string(entry.Key) == "k3" && failK3 {
+				failK3 = false
+				return errors.New("mock import error")
+			}
+		}
+		for _, entry := range kvs {
+			imported = append(imported, fmt.Sprintf("%s:%s:%s", entry.Key, entry.Val, entry.RowKey))
+		}
+		return nil
+	}
+	c.Assert(e.Import(2, importFn), NotNil)
+	c.Assert(imported, DeepEquals, []string{"k1:vk1:r1", "k2:vk2:r2"})
+	c.Assert(e.Count(), Equals, 1)
+	c.Assert(e.Import(2, importFn), IsNil)
+	c.Assert(imported, DeepEquals, []string{"k1:vk1:r1", "k2:vk2:r2", "k3:vk3:r3"})
+
+	// The engine is empty after the import.
+	c.Assert(e.Count(), Equals, 0)
+	c.Assert(e.Import(2, func(kvs []KV) error {
+		return errors.New("unexpected import")
+	}), IsNil)
+	c.Assert(e.WriteBatch([]KV{newKV("k1", "r9")}), IsNil)
+
+	c.Assert(e.Close(), IsNil)
+	_, err = os.Stat(dir)
+	c.Assert(os.IsNotExist(err), IsTrue)
+}
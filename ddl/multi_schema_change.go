@@ -0,0 +1,552 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"encoding/json"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// An ALTER TABLE statement with several specs runs as a multi-schema change job, the job of each
+// spec is a sub-job of it, and they are run one step a time by proxy jobs, which share the ID and
+// the table of the multi-schema change job, so the handlers of the normal jobs are reused.
+//
+// The multi-schema change job runs in two phases. In the revertible phase, each sub-job runs until
+// it reaches the last state it can be rolled back from, e.g. the adding column stops at the write
+// reorganization state and the adding index stops after it's backfilled. The dropping jobs and the
+// renaming jobs can't be rolled back after they change the schema, so they are only checked. If any
+// sub-job fails in this phase, all of them are rolled back in the reverse order. In the
+// non-revertible phase, the sub-jobs are finished one by one and the job can't be cancelled anymore.
+
+// subJob is a sub-job of the multi-schema change job.
+type subJob struct {
+	Type        model.ActionType  `json:"type"`
+	RawArgs     json.RawMessage   `json:"raw_args"`
+	SchemaState model.SchemaState `json:"schema_state"`
+	SnapshotVer uint64            `json:"snapshot_ver"`
+	RowCount    int64             `json:"row_count"`
+	State       model.JobState    `json:"state"`
+	// Revertible becomes false when the sub-job reaches the last state it can be rolled back from.
+	Revertible bool `json:"revertible"`
+}
+
+// multiSchemaInfo is the argument of the multi-schema change job.
+type multiSchemaInfo struct {
+	SubJobs []*subJob `json:"sub_jobs"`
+	// Revertible becomes false when all the sub-jobs are non-revertible.
+	Revertible bool `json:"revertible"`
+}
+
+func (sub *subJob) isFinished() bool {
+	return sub.State == model.JobStateDone || sub.State == model.JobStateRollbackDone ||
+		sub.State == model.JobStateCancelled
+}
+
+// toProxyJob builds a job to run the sub-job by the handler of its type.
+func (sub *subJob) toProxyJob(job *model.Job) *model.Job {
+	return &model.Job{
+		ID:          job.ID,
+		Type:        sub.Type,
+		SchemaID:    job.SchemaID,
+		TableID:     job.TableID,
+		SchemaName:  job.SchemaName,
+		State:       sub.State,
+		RowCount:    sub.RowCount,
+		RawArgs:     sub.RawArgs,
+		SchemaState: sub.SchemaState,
+		SnapshotVer: sub.SnapshotVer,
+		StartTS:     job.StartTS,
+		Query:       job.Query,
+		BinlogInfo:  job.BinlogInfo,
+		Version:     job.Version,
+		ReorgMeta:   job.ReorgMeta,
+		Priority:    job.Priority,
+	}
+}
+
+// fromProxyJob saves the progress of the proxy job into the sub-job.
+func (sub *subJob) fromProxyJob(proxyJob *model.Job) error {
+	// The arguments may be changed by the handler.
+	if proxyJob.Args != nil {
+		rawArgs, err := json.Marshal(proxyJob.Args)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		sub.RawArgs = rawArgs
+	}
+	sub.State = proxyJob.State
+	sub.SchemaState = proxyJob.SchemaState
+	sub.SnapshotVer = proxyJob.SnapshotVer
+	sub.RowCount = proxyJob.GetRowCount()
+	return nil
+}
+
+// isRevertibleAfterStarted returns whether the sub-job can be rolled back after it changes the schema.
+func isRevertibleAfterStarted(tp model.ActionType) bool {
+	return tp == model.ActionAddColumn || tp == model.ActionAddIndex
+}
+
+func (info *multiSchemaInfo) isFinished() bool {
+	for _, sub := range info.SubJobs {
+		if !sub.isFinished() {
+			return false
+		}
+	}
+	return true
+}
+
+// needRollback returns whether some sub-jobs have changed the schema and must be rolled back.
+func (info *multiSchemaInfo) needRollback() bool {
+	for _, sub := range info.SubJobs {
+		if !sub.isFinished() && sub.SchemaState != model.StateNone {
+			return true
+		}
+	}
+	return false
+}
+
+func (info *multiSchemaInfo) rowCount() int64 {
+	var cnt int64
+	for _, sub := range info.SubJobs {
+		cnt += sub.RowCount
+	}
+	return cnt
+}
+
+func (w *worker) onMultiSchemaChange(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	info := &multiSchemaInfo{}
+	if err = job.DecodeArgs(info); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	defer job.SetRowCount(info.rowCount())
+
+	if job.IsRollingback() {
+		return w.rollbackSubJobs(d, t, job, info)
+	}
+
+	if info.Revertible {
+		for _, sub := range info.SubJobs {
+			if !sub.Revertible {
+				continue
+			}
+			if !isRevertibleAfterStarted(sub.Type) {
+				// Check it here, so the failure can be rolled back. It runs in the non-revertible phase.
+				proxyJob := sub.toProxyJob(job)
+				if err = checkSubJob(t, proxyJob); err != nil {
+					sub.State = model.JobStateCancelled
+					convertMultiSchemaChange2RollbackJob(job, info)
+					return ver, errors.Trace(err)
+				}
+				sub.Revertible = false
+				continue
+			}
+
+			var proxyJob *model.Job
+			ver, proxyJob, err = w.runSubJob(d, t, job, sub)
+			if sub.Type == model.ActionAddColumn && sub.SchemaState == model.StateWriteReorganization {
+				// The column is visible to the users after it becomes public.
+				sub.Revertible = false
+			}
+			if sub.State == model.JobStateCancelled || sub.State == model.JobStateRollingback {
+				logutil.BgLogger().Warn("[ddl] run multi-schema change sub-job failed, convert job to rollback",
+					zap.String("job", job.String()), zap.String("subJob", sub.Type.String()), zap.Error(proxyJob.Error))
+				convertMultiSchemaChange2RollbackJob(job, info)
+				if err == nil && proxyJob.Error != nil {
+					err = proxyJob.Error
+				}
+			}
+			return ver, errors.Trace(err)
+		}
+		// All the sub-jobs are ready, finish them from now on.
+		info.Revertible = false
+	}
+
+	for _, sub := range info.SubJobs {
+		if sub.isFinished() {
+			continue
+		}
+		if sub.Type == model.ActionAddColumn && sub.SchemaState == model.StateWriteReorganization {
+			if err = adjustAddingColumnToEnd(t, job, sub); err != nil {
+				return ver, errors.Trace(err)
+			}
+		}
+
+		var proxyJob *model.Job
+		ver, proxyJob, err = w.runSubJob(d, t, job, sub)
+		if sub.State == model.JobStateCancelled || sub.State == model.JobStateRollbackDone {
+			// It's not expected since the sub-jobs are checked in the revertible phase.
+			logutil.BgLogger().Error("[ddl] multi-schema change sub-job is cancelled in the non-revertible phase",
+				zap.String("job", job.String()), zap.String("subJob", sub.Type.String()), zap.Error(proxyJob.Error))
+		}
+		if info.isFinished() {
+			job.State = model.JobStateDone
+			job.SchemaState = model.StatePublic
+		}
+		return ver, errors.Trace(err)
+	}
+	job.State = model.JobStateDone
+	job.SchemaState = model.StatePublic
+	return ver, nil
+}
+
+// runSubJob runs a step of the sub-job.
+func (w *worker) runSubJob(d *ddlCtx, t *meta.Meta, job *model.Job, sub *subJob) (ver int64, proxyJob *model.Job, err error) {
+	proxyJob = sub.toProxyJob(job)
+	w.subJob = sub
+	ver, err = w.runDDLJob(d, t, proxyJob)
+	w.subJob = nil
+	if err1 := sub.fromProxyJob(proxyJob); err1 != nil {
+		return ver, proxyJob, errors.Trace(err1)
+	}
+	job.SchemaState = sub.SchemaState
+	return ver, proxyJob, errors.Trace(err)
+}
+
+// rollbackSubJobs rolls back the sub-jobs in the reverse order, so the adding columns are always
+// dropped from the end of the columns.
+func (w *worker) rollbackSubJobs(d *ddlCtx, t *meta.Meta, job *model.Job, info *multiSchemaInfo) (ver int64, err error) {
+	for _, sub := range info.SubJobs {
+		if !sub.isFinished() && sub.SchemaState == model.StateNone {
+			// The sub-job hasn't changed the schema, cancel it directly.
+			sub.State = model.JobStateCancelled
+		}
+	}
+	for i := len(info.SubJobs) - 1; i >= 0; i-- {
+		sub := info.SubJobs[i]
+		if sub.isFinished() {
+			continue
+		}
+		if sub.State != model.JobStateRollingback {
+			sub.State = model.JobStateCancelling
+		}
+		ver, _, err = w.runSubJob(d, t, job, sub)
+		if info.isFinished() {
+			job.State = model.JobStateRollbackDone
+			job.SchemaState = model.StateNone
+		}
+		return ver, errors.Trace(err)
+	}
+	job.State = model.JobStateRollbackDone
+	job.SchemaState = model.StateNone
+	return ver, nil
+}
+
+// convertMultiSchemaChange2RollbackJob rolls back the multi-schema change job, it's cancelled directly
+// if no sub-job has changed the schema.
+func convertMultiSchemaChange2RollbackJob(job *model.Job, info *multiSchemaInfo) {
+	if info.needRollback() {
+		job.State = model.JobStateRollingback
+	} else {
+		job.State = model.JobStateCancelled
+	}
+}
+
+// rollingbackMultiSchemaChange handles the multi-schema change job which is cancelled by the users.
+func rollingbackMultiSchemaChange(job *model.Job) (ver int64, err error) {
+	info := &multiSchemaInfo{}
+	if err = job.DecodeArgs(info); err != nil {
+		return ver, errors.Trace(err)
+	}
+	if !info.Revertible {
+		// Some sub-jobs may have been finished, so the others must be finished too.
+		job.State = model.JobStateRunning
+		return ver, nil
+	}
+	convertMultiSchemaChange2RollbackJob(job, info)
+	return ver, errCancelledDDLJob
+}
+
+// checkSubJob checks the sub-job which can't be rolled back after it's started.
+func checkSubJob(t *meta.Meta, proxyJob *model.Job) (err error) {
+	switch proxyJob.Type {
+	case model.ActionDropColumn:
+		_, _, _, err = checkDropColumn(t, proxyJob)
+	case model.ActionDropIndex, model.ActionDropPrimaryKey:
+		_, _, err = checkDropIndex(t, proxyJob)
+	case model.ActionRenameIndex:
+		_, _, _, err = checkRenameIndex(t, proxyJob)
+	case model.ActionAddForeignKey:
+		_, _, err = checkAddForeignKey(t, proxyJob)
+	}
+	return errors.Trace(err)
+}
+
+// adjustAddingColumnToEnd moves the adding column to the end of the columns before it becomes
+// public, since the other adding columns may be behind it. The offset is calculated again
+// because the columns in front of it may be changed by the former sub-jobs.
+func adjustAddingColumnToEnd(t *meta.Meta, job *model.Job, sub *subJob) error {
+	tblInfo, err := getTableInfo(t, job.TableID, job.SchemaID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	proxyJob := sub.toProxyJob(job)
+	col, pos, offset := &model.ColumnInfo{}, &ast.ColumnPosition{}, 0
+	if err = proxyJob.DecodeArgs(col, pos, &offset); err != nil {
+		return errors.Trace(err)
+	}
+	columnInfo := model.FindColumnInfo(tblInfo.Columns, col.Name.L)
+	if columnInfo == nil {
+		return nil
+	}
+
+	newCols := make([]*model.ColumnInfo, 0, len(tblInfo.Columns))
+	for _, c := range tblInfo.Columns {
+		if c != columnInfo {
+			newCols = append(newCols, c)
+		}
+	}
+	newCols = append(newCols, columnInfo)
+	offsetChanged := make(map[int]int)
+	for i, c := range newCols {
+		if c.Offset != i {
+			offsetChanged[c.Offset] = i
+			c.Offset = i
+		}
+	}
+	for _, idx := range tblInfo.Indices {
+		for _, c := range idx.Columns {
+			if newOffset, ok := offsetChanged[c.Offset]; ok {
+				c.Offset = newOffset
+			}
+		}
+	}
+	tblInfo.Columns = newCols
+
+	switch pos.Tp {
+	case ast.ColumnPositionFirst:
+		offset = 0
+	case ast.ColumnPositionAfter:
+		c := model.FindColumnInfo(tblInfo.Columns, pos.RelativeColumn.Name.L)
+		if c == nil {
+			return infoschema.ErrColumnNotExists.GenWithStackByArgs(pos.RelativeColumn, tblInfo.Name)
+		}
+		offset = c.Offset + 1
+	default:
+		offset = 0
+		for _, c := range tblInfo.Columns {
+			if c.State == model.StatePublic {
+				offset++
+			}
+		}
+	}
+	if sub.RawArgs, err = json.Marshal([]interface{}{col, pos, offset}); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(t.UpdateTable(job.SchemaID, tblInfo))
+}
+
+type multiSchemaChangeKeyType int
+
+func (k multiSchemaChangeKeyType) String() string {
+	return "multi_schema_change"
+}
+
+// multiSchemaChangeKey is the key of the subJobCollector in the session context.
+const multiSchemaChangeKey multiSchemaChangeKeyType = 0
+
+// subJobCollector collects the jobs of the specs as the sub-jobs, and checks the conflicts of them.
+type subJobCollector struct {
+	tblInfo *model.TableInfo
+	subJobs []*subJob
+	// columns and indexes are the columns and indexes changed by the sub-jobs.
+	columns map[string]struct{}
+	indexes map[string]struct{}
+	// refColumns are the columns referenced by the adding indexes.
+	refColumns map[string]struct{}
+}
+
+func newSubJobCollector(tblInfo *model.TableInfo) *subJobCollector {
+	return &subJobCollector{
+		tblInfo:    tblInfo,
+		columns:    make(map[string]struct{}),
+		indexes:    make(map[string]struct{}),
+		refColumns: make(map[string]struct{}),
+	}
+}
+
+func (c *subJobCollector) appendSubJob(job *model.Job) error {
+	var columns, indexes, refColumns []string
+	switch job.Type {
+	case model.ActionAddColumn:
+		columns = append(columns, job.Args[0].(*table.Column).Name.L)
+		if pos, ok := job.Args[1].(*ast.ColumnPosition); ok && pos != nil && pos.Tp == ast.ColumnPositionAfter {
+			refColumns = append(refColumns, pos.RelativeColumn.Name.L)
+		}
+	case model.ActionDropColumn:
+		colName := job.Args[0].(model.CIStr)
+		columns = append(columns, colName.L)
+		// The indexes covering the column are dropped with it.
+		for _, idx := range listIndicesWithColumn(colName.L, c.tblInfo.Indices) {
+			indexes = append(indexes, idx.Name.L)
+		}
+	case model.ActionAddIndex:
+		// The hidden columns of the expression index are appended to the columns like the adding columns,
+		// and they are not handled when the adding columns become public.
+		if hiddenCols := job.Args[4].([]*model.ColumnInfo); len(hiddenCols) > 0 {
+			return errRunMultiSchemaChanges
+		}
+		indexes = append(indexes, job.Args[1].(model.CIStr).L)
+		for _, spec := range job.Args[2].([]*ast.IndexPartSpecification) {
+			refColumns = append(refColumns, spec.Column.Name.L)
+		}
+	case model.ActionDropIndex, model.ActionDropPrimaryKey:
+		indexes = append(indexes, job.Args[0].(model.CIStr).L)
+	case model.ActionRenameIndex:
+		indexes = append(indexes, job.Args[0].(model.CIStr).L, job.Args[1].(model.CIStr).L)
+	case model.ActionAddForeignKey:
+		for _, col := range job.Args[0].(*model.FKInfo).Cols {
+			refColumns = append(refColumns, col.L)
+		}
+	default:
+		return errRunMultiSchemaChanges
+	}
+
+	for _, col := range columns {
+		if _, ok := c.columns[col]; ok {
+			return errOperateSameColumn.GenWithStackByArgs(col)
+		}
+		if _, ok := c.refColumns[col]; ok {
+			return errOperateSameColumn.GenWithStackByArgs(col)
+		}
+	}
+	for _, col := range refColumns {
+		if _, ok := c.columns[col]; ok {
+			return errOperateSameColumn.GenWithStackByArgs(col)
+		}
+	}
+	for _, idx := range indexes {
+		if _, ok := c.indexes[idx]; ok {
+			return errOperateSameIndex.GenWithStackByArgs(idx)
+		}
+	}
+	for _, col := range columns {
+		c.columns[col] = struct{}{}
+	}
+	for _, col := range refColumns {
+		c.refColumns[col] = struct{}{}
+	}
+	for _, idx := range indexes {
+		c.indexes[idx] = struct{}{}
+	}
+
+	rawArgs, err := json.Marshal(job.Args)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.subJobs = append(c.subJobs, &subJob{
+		Type:       job.Type,
+		RawArgs:    rawArgs,
+		Revertible: true,
+	})
+	return nil
+}
+
+// multiSchemaChange runs the specs of an ALTER TABLE statement in a multi-schema change job.
+func (d *ddl) multiSchemaChange(ctx sessionctx.Context, ident ast.Ident, specs []*ast.AlterTableSpec) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// The jobs of the specs are checked and built as usual, then they are collected instead of being run.
+	collector := newSubJobCollector(t.Meta())
+	ctx.SetValue(multiSchemaChangeKey, collector)
+	err = d.collectSubJobs(ctx, ident, specs)
+	ctx.ClearValue(multiSchemaChangeKey)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// All the specs are skipped by IF [NOT] EXISTS.
+	if len(collector.subJobs) == 0 {
+		return nil
+	}
+	return errors.Trace(d.doMultiSchemaChangeJob(ctx, schema, t.Meta(), collector.subJobs))
+}
+
+// doMultiSchemaChangeJob runs the collected sub-jobs in a multi-schema change job.
+func (d *ddl) doMultiSchemaChangeJob(ctx sessionctx.Context, schema *model.DBInfo, tblInfo *model.TableInfo, subJobs []*subJob) error {
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tblInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionMultiSchemaChange,
+		BinlogInfo: &model.HistoryInfo{},
+		ReorgMeta: &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+		},
+		Args:     []interface{}{&multiSchemaInfo{SubJobs: subJobs, Revertible: true}},
+		Priority: ctx.GetSessionVars().DDLReorgPriority,
+	}
+	err := d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+func (d *ddl) collectSubJobs(ctx sessionctx.Context, ident ast.Ident, specs []*ast.AlterTableSpec) (err error) {
+	for _, spec := range specs {
+		switch spec.Tp {
+		case ast.AlterTableAddColumns:
+			for _, col := range spec.NewColumns {
+				colSpec := *spec
+				colSpec.NewColumns = []*ast.ColumnDef{col}
+				if err = d.AddColumn(ctx, ident, &colSpec); err != nil {
+					return errors.Trace(err)
+				}
+			}
+		case ast.AlterTableDropColumn:
+			err = d.DropColumn(ctx, ident, spec)
+		case ast.AlterTableDropIndex:
+			err = d.DropIndex(ctx, ident, model.NewCIStr(spec.Name), spec.IfExists)
+		case ast.AlterTableDropPrimaryKey:
+			err = d.DropIndex(ctx, ident, model.NewCIStr(mysql.PrimaryKeyName), spec.IfExists)
+		case ast.AlterTableRenameIndex:
+			err = d.RenameIndex(ctx, ident, spec)
+		case ast.AlterTableAddConstraint:
+			constr := spec.Constraint
+			switch constr.Tp {
+			case ast.ConstraintKey, ast.ConstraintIndex:
+				err = d.CreateIndex(ctx, ident, ast.IndexKeyTypeNone, model.NewCIStr(constr.Name),
+					constr.Keys, constr.Option, constr.IfNotExists)
+			case ast.ConstraintUniq, ast.ConstraintUniqIndex, ast.ConstraintUniqKey:
+				err = d.CreateIndex(ctx, ident, ast.IndexKeyTypeUnique, model.NewCIStr(constr.Name),
+					constr.Keys, constr.Option, false)
+			case ast.ConstraintFulltext:
+				err = d.CreateIndex(ctx, ident, ast.IndexKeyTypeFullText, model.NewCIStr(constr.Name),
+					constr.Keys, constr.Option, constr.IfNotExists)
+			default:
+				return errRunMultiSchemaChanges
+			}
+		default:
+			return errRunMultiSchemaChanges
+		}
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
@@ -36,11 +36,14 @@ import (
 	"github.com/pingcap/tidb/domain/infosync"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/store/tikv"
+	tikvstore "github.com/pingcap/tidb/store/tikv/kv"
 	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	driver "github.com/pingcap/tidb/types/parser_driver"
@@ -49,7 +52,10 @@ import (
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
+	decoder "github.com/pingcap/tidb/util/rowDecoder"
 	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/timeutil"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/pd/pkg/slice"
 	"go.uber.org/zap"
 )
@@ -840,6 +846,109 @@ func checkDropTablePartition(meta *model.TableInfo, partLowerNames []string) err
 	return nil
 }
 
+// getReorganizedPartitionDefs returns the definitions of the partitions to reorganize in the order of the table.
+func getReorganizedPartitionDefs(tblInfo *model.TableInfo, partLowerNames []string) ([]model.PartitionDefinition, error) {
+	pi := tblInfo.Partition
+	names := make(map[string]struct{}, len(partLowerNames))
+	for _, pn := range partLowerNames {
+		names[pn] = struct{}{}
+	}
+	defs := make([]model.PartitionDefinition, 0, len(partLowerNames))
+	first := -1
+	for i, def := range pi.Definitions {
+		if _, ok := names[def.Name.L]; !ok {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		// Only the consecutive range partitions can be reorganized, so the new ranges replace the old ones.
+		if pi.Type == model.PartitionTypeRange && i != first+len(defs) {
+			return nil, errors.Trace(ErrConsecutiveReorgPartitions)
+		}
+		defs = append(defs, def)
+	}
+	if len(defs) != len(partLowerNames) {
+		return nil, errors.Trace(ErrDropPartitionNonExistent.GenWithStackByArgs("REORGANIZE"))
+	}
+	return defs, nil
+}
+
+// checkReorganizePartition checks the partitions to reorganize exist and the new partitions cover the same ranges
+// or values as them. Only the last range partition of the table can be extended.
+func checkReorganizePartition(ctx sessionctx.Context, tblInfo *model.TableInfo, partLowerNames []string, partInfo *model.PartitionInfo) error {
+	pi := tblInfo.Partition
+	oldDefs, err := getReorganizedPartitionDefs(tblInfo, partLowerNames)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	newTblInfo := tblInfo.Clone()
+	newPi := *pi
+	newPi.Definitions = tables.ReorganizedDefinitions(pi.Definitions, oldDefs, partInfo.Definitions)
+	newTblInfo.Partition = &newPi
+	if err = checkPartitionDefinitionConstraints(ctx, newTblInfo); err != nil {
+		return errors.Trace(err)
+	}
+
+	if pi.Type == model.PartitionTypeRange {
+		oldLast := oldDefs[len(oldDefs)-1]
+		newLast := partInfo.Definitions[len(partInfo.Definitions)-1]
+		isLast := oldLast.ID == pi.Definitions[len(pi.Definitions)-1].ID
+		if isRangeValueLess(ctx, tblInfo, newLast.LessThan, oldLast.LessThan) ||
+			(!isLast && isRangeValueLess(ctx, tblInfo, oldLast.LessThan, newLast.LessThan)) {
+			return errors.Trace(ErrReorgOutsideRange)
+		}
+		return nil
+	}
+
+	oldValues, err := getListPartitionValues(ctx, tblInfo, oldDefs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	newValues, err := getListPartitionValues(ctx, tblInfo, partInfo.Definitions)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	values := make(map[string]struct{}, len(newValues))
+	for _, v := range newValues {
+		values[v] = struct{}{}
+	}
+	for _, v := range oldValues {
+		if _, ok := values[v]; !ok {
+			return errors.Trace(ErrReorgOutsideRange)
+		}
+	}
+	return nil
+}
+
+// isRangeValueLess returns whether the range partition value a is less than b.
+func isRangeValueLess(ctx sessionctx.Context, tblInfo *model.TableInfo, a, b []string) bool {
+	tmp := tblInfo.Clone()
+	pi := *tblInfo.Partition
+	pi.Definitions = []model.PartitionDefinition{
+		{Name: model.NewCIStr("a"), LessThan: append([]string(nil), a...)},
+		{Name: model.NewCIStr("b"), LessThan: append([]string(nil), b...)},
+	}
+	tmp.Partition = &pi
+	return checkPartitionByRange(ctx, tmp) == nil
+}
+
+// getListPartitionValues returns the formatted values of the list partitions.
+func getListPartitionValues(ctx sessionctx.Context, tblInfo *model.TableInfo, defs []model.PartitionDefinition) ([]string, error) {
+	tmp := tblInfo.Clone()
+	pi := *tblInfo.Partition
+	pi.Definitions = make([]model.PartitionDefinition, len(defs))
+	for i, def := range defs {
+		pi.Definitions[i] = def
+		pi.Definitions[i].InValues = make([][]string, len(def.InValues))
+		for j, vs := range def.InValues {
+			pi.Definitions[i].InValues[j] = append([]string(nil), vs...)
+		}
+	}
+	tmp.Partition = &pi
+	return formatListPartitionValue(ctx, tmp)
+}
+
 // updateDroppingPartitionInfo move dropping partitions to DroppingDefinitions, and return partitionIDs
 func updateDroppingPartitionInfo(tblInfo *model.TableInfo, partLowerNames []string) []int64 {
 	oldDefs := tblInfo.Partition.Definitions
@@ -1131,6 +1240,416 @@ func onTruncateTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (int64, e
 	return ver, nil
 }
 
+// onReorganizePartition reorganizes the partitions into the new partitions. The new partitions are put into
+// AddingDefinitions and the old ones into DroppingDefinitions, so the rows are written into both of them.
+// After the rows of the old partitions are backfilled into the new ones, the new partitions replace the old
+// ones and the old partitions are dropped.
+func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if job.IsRollingback() {
+		return rollbackReorganizePartition(d, t, job, tblInfo)
+	}
+
+	var partNames []string
+	partInfo := &model.PartitionInfo{}
+	if err = job.DecodeArgs(&partNames, &partInfo); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	pi := tblInfo.Partition
+	originalState := job.SchemaState
+	switch job.SchemaState {
+	case model.StateNone:
+		var oldDefs []model.PartitionDefinition
+		oldDefs, err = getReorganizedPartitionDefs(tblInfo, partNames)
+		if err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		pi.DroppingDefinitions = oldDefs
+		updateAddingPartitionInfo(partInfo, tblInfo)
+		for _, def := range partInfo.Definitions {
+			pi.SetStateByID(def.ID, model.StateDeleteOnly)
+		}
+		// none -> delete only
+		job.SchemaState = model.StateDeleteOnly
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, originalState != job.SchemaState)
+	case model.StateDeleteOnly:
+		for _, def := range pi.AddingDefinitions {
+			pi.SetStateByID(def.ID, model.StateWriteOnly)
+		}
+		// delete only -> write only
+		job.SchemaState = model.StateWriteOnly
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != job.SchemaState)
+	case model.StateWriteOnly:
+		for _, def := range pi.AddingDefinitions {
+			pi.SetStateByID(def.ID, model.StateWriteReorganization)
+		}
+		// write only -> reorganization
+		job.SchemaState = model.StateWriteReorganization
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != job.SchemaState)
+	case model.StateWriteReorganization:
+		var done bool
+		done, err = w.doReorganizePartition(d, t, job, tblInfo)
+		if !done {
+			return ver, errors.Trace(err)
+		}
+		// The new partitions replace the old ones, the rows are still written into the old partitions
+		// since the servers on the previous schema version read them.
+		pi.Definitions = tables.ReorganizedDefinitions(pi.Definitions, pi.DroppingDefinitions, pi.AddingDefinitions)
+		removePartitionStates(pi, pi.AddingDefinitions)
+		for _, def := range pi.DroppingDefinitions {
+			pi.SetStateByID(def.ID, model.StateWriteOnly)
+		}
+		// reorganization -> delete reorganization
+		job.SchemaState = model.StateDeleteReorganization
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != job.SchemaState)
+	case model.StateDeleteReorganization:
+		physicalTableIDs := getPartitionIDsFromDefinitions(pi.DroppingDefinitions)
+		if err = dropRuleBundles(d, physicalTableIDs); err != nil {
+			return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
+		}
+		pi.AddingDefinitions, pi.DroppingDefinitions = nil, nil
+		pi.GCPartitionStates()
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
+		asyncNotifyEvent(d, &util.Event{Tp: model.ActionAddTablePartition, TableInfo: tblInfo, PartInfo: partInfo})
+		// A background job will be created to delete old partition data.
+		job.Args = []interface{}{physicalTableIDs}
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("partition", job.SchemaState)
+	}
+	return ver, errors.Trace(err)
+}
+
+// removePartitionStates removes the states of the given partitions, so they are public.
+func removePartitionStates(pi *model.PartitionInfo, defs []model.PartitionDefinition) {
+	states := make([]model.PartitionState, 0, len(pi.States))
+	for _, state := range pi.States {
+		found := false
+		for _, def := range defs {
+			if def.ID == state.ID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			states = append(states, state)
+		}
+	}
+	pi.States = states
+}
+
+// doReorganizePartition backfills the rows of the old partitions into the new partitions.
+// It returns true when the rows are backfilled.
+func (w *worker) doReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job, tblInfo *model.TableInfo) (done bool, _ error) {
+	tbl, err := getTable(d.store, job.SchemaID, tblInfo)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	physicalTableIDs := getPartitionIDsFromDefinitions(tblInfo.Partition.DroppingDefinitions)
+	elements := []*meta.Element{{ID: tblInfo.ID, TypeKey: meta.PartitionElementKey}}
+	reorgInfo, err := getReorgInfoFromPartitions(d, t, job, tbl, physicalTableIDs, elements)
+	if err != nil || reorgInfo.first {
+		// If we run reorg firstly, we should update the job snapshot version
+		// and then run the reorg next time.
+		return false, errors.Trace(err)
+	}
+
+	err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (reorgErr error) {
+		defer tidbutil.Recover(metrics.LabelDDL, "onReorganizePartition",
+			func() {
+				reorgErr = errCancelledDDLJob.GenWithStack("reorganize table `%v` partition panic", tblInfo.Name)
+			}, false)
+		return w.reorgPartitionRecords(tbl.(table.PartitionedTable), physicalTableIDs, reorgInfo)
+	})
+	if err != nil {
+		if errWaitReorgTimeout.Equal(err) {
+			// if timeout, we should return, check for the owner and re-wait job done.
+			return false, nil
+		}
+		if kv.ErrKeyExists.Equal(err) || errCancelledDDLJob.Equal(err) || errCantDecodeRecord.Equal(err) ||
+			table.ErrNoPartitionForGivenValue.Equal(err) {
+			logutil.BgLogger().Warn("[ddl] run reorganize partition job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
+			job.State = model.JobStateRollingback
+			if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
+				logutil.BgLogger().Warn("[ddl] run reorganize partition job failed, convert job to rollback, RemoveDDLReorgHandle failed", zap.String("job", job.String()), zap.Error(err1))
+			}
+		}
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+		return false, errors.Trace(err)
+	}
+	// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+	w.reorgCtx.cleanNotifyReorgCancel()
+	return true, nil
+}
+
+// rollbackReorganizePartition removes the new partitions, the old partitions are kept.
+func rollbackReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job, tblInfo *model.TableInfo) (ver int64, _ error) {
+	pi := tblInfo.Partition
+	physicalTableIDs := getPartitionIDsFromDefinitions(pi.AddingDefinitions)
+	if err := dropRuleBundles(d, physicalTableIDs); err != nil {
+		return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
+	}
+	pi.AddingDefinitions, pi.DroppingDefinitions = nil, nil
+	pi.GCPartitionStates()
+	ver, err := updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+	// A background job will be created to delete new partition data.
+	job.Args = []interface{}{physicalTableIDs}
+	return ver, nil
+}
+
+// reorgPartitionRecords backfills the rows of the given partitions into the reorganized partitions.
+func (w *worker) reorgPartitionRecords(tbl table.PartitionedTable, partitionIDs []int64, reorgInfo *reorgInfo) error {
+	var err error
+	var finish bool
+	for !finish {
+		p := tbl.GetPartition(reorgInfo.PhysicalTableID)
+		if p == nil {
+			return errCancelledDDLJob.GenWithStack("Can not find partition id %d for table %d", reorgInfo.PhysicalTableID, tbl.Meta().ID)
+		}
+		logutil.BgLogger().Info("[ddl] start to reorganize partition", zap.String("job", reorgInfo.Job.String()), zap.String("reorgInfo", reorgInfo.String()))
+		err = w.writePhysicalTableRecord(p, typeReorgPartitionWorker, nil, nil, nil, reorgInfo)
+		if err != nil {
+			break
+		}
+		finish, err = w.updateReorgInfoForPartitions(tbl, reorgInfo, partitionIDs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(err)
+}
+
+// getReorganizedTable returns the table in which the partitions in DroppingDefinitions are replaced
+// by the ones in AddingDefinitions.
+func getReorganizedTable(d *ddlCtx, schemaID int64, tblInfo *model.TableInfo) (table.PartitionedTable, error) {
+	newTblInfo := tblInfo.Clone()
+	pi := *tblInfo.Partition
+	pi.Definitions = tables.ReorganizedDefinitions(pi.Definitions, pi.DroppingDefinitions, pi.AddingDefinitions)
+	pi.AddingDefinitions, pi.DroppingDefinitions = nil, nil
+	newTblInfo.Partition = &pi
+	tbl, err := getTable(d.store, schemaID, newTblInfo)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return tbl.(table.PartitionedTable), nil
+}
+
+type reorgPartitionWorker struct {
+	*backfillWorker
+	reorgTbl      table.PartitionedTable
+	addingIDs     map[int64]struct{}
+	metricCounter prometheus.Counter
+
+	// The following attributes are used to reduce memory allocation.
+	rowRecords  []*reorgPartitionRecord
+	rowDecoder  *decoder.RowDecoder
+	rowMap      map[int64]types.Datum
+	defaultVals []types.Datum
+}
+
+type reorgPartitionRecord struct {
+	key       kv.Key // It's the record key in the old partition, which is used to lock the record.
+	newKey    kv.Key
+	vals      []byte
+	row       []types.Datum
+	handle    kv.Handle
+	partition table.PhysicalTable
+}
+
+func newReorgPartitionWorker(sessCtx sessionctx.Context, worker *worker, id int, t table.PhysicalTable, reorgTbl table.PartitionedTable, decodeColMap map[int64]decoder.Column) *reorgPartitionWorker {
+	// The rows are decoded in UTC, so the partition expressions are evaluated in UTC.
+	sessCtx.GetSessionVars().TimeZone = time.UTC
+	pi := reorgTbl.Meta().GetPartitionInfo()
+	addingIDs := make(map[int64]struct{}, len(pi.Definitions))
+	for _, def := range t.Meta().Partition.AddingDefinitions {
+		addingIDs[def.ID] = struct{}{}
+	}
+	return &reorgPartitionWorker{
+		backfillWorker: newBackfillWorker(sessCtx, worker, id, t),
+		reorgTbl:       reorgTbl,
+		addingIDs:      addingIDs,
+		metricCounter:  metrics.BackfillTotalCounter.WithLabelValues("reorg_partition_speed"),
+		rowDecoder:     decoder.NewRowDecoder(t, t.WritableCols(), decodeColMap),
+		rowMap:         make(map[int64]types.Datum, len(decodeColMap)),
+		defaultVals:    make([]types.Datum, len(t.WritableCols())),
+	}
+}
+
+func (w *reorgPartitionWorker) AddMetricInfo(cnt float64) {
+	w.metricCounter.Add(cnt)
+}
+
+func (w *reorgPartitionWorker) fetchRowColVals(txn kv.Transaction, taskRange reorgBackfillTask) ([]*reorgPartitionRecord, kv.Key, bool, error) {
+	w.rowRecords = w.rowRecords[:0]
+	startTime := time.Now()
+
+	// taskDone means that the added handle is out of taskRange.endHandle.
+	taskDone := false
+	var lastAccessedHandle kv.Key
+	oprStartTime := startTime
+	err := iterateSnapshotRows(w.sessCtx.GetStore(), w.priority, w.table, txn.StartTS(), taskRange.startKey, taskRange.endKey,
+		func(handle kv.Handle, recordKey kv.Key, rawRow []byte) (bool, error) {
+			oprEndTime := time.Now()
+			logSlowOperations(oprEndTime.Sub(oprStartTime), "iterateSnapshotRows in reorgPartitionWorker fetchRowColVals", 0)
+			oprStartTime = oprEndTime
+
+			taskDone = recordKey.Cmp(taskRange.endKey) > 0
+
+			if taskDone || len(w.rowRecords) >= w.batchCnt {
+				return false, nil
+			}
+
+			if err1 := w.getRowRecord(handle, recordKey, rawRow); err1 != nil {
+				return false, errors.Trace(err1)
+			}
+			lastAccessedHandle = recordKey
+			if recordKey.Cmp(taskRange.endKey) == 0 {
+				// If taskRange.endIncluded == false, we will not reach here when handle == taskRange.endHandle.
+				taskDone = true
+				return false, nil
+			}
+			return true, nil
+		})
+
+	if len(w.rowRecords) == 0 {
+		taskDone = true
+	}
+
+	logutil.BgLogger().Debug("[ddl] txn fetches handle info", zap.Uint64("txnStartTS", txn.StartTS()), zap.String("taskRange", taskRange.String()), zap.Duration("takeTime", time.Since(startTime)))
+	nextKey := taskRange.endKey.Next()
+	if !taskDone {
+		nextKey = lastAccessedHandle.Next()
+	}
+	return w.rowRecords, nextKey, taskDone, errors.Trace(err)
+}
+
+func (w *reorgPartitionWorker) getRowRecord(handle kv.Handle, recordKey []byte, rawRow []byte) error {
+	_, err := w.rowDecoder.DecodeAndEvalRowWithMap(w.sessCtx, handle, rawRow, time.UTC, timeutil.SystemLocation(), w.rowMap)
+	if err != nil {
+		return errors.Trace(errCantDecodeRecord.GenWithStackByArgs("partition", err))
+	}
+	defer w.cleanRowMap()
+
+	cols := w.table.WritableCols()
+	sysZone := timeutil.SystemLocation()
+	row := make([]types.Datum, len(cols))
+	for i, col := range cols {
+		val, ok := w.rowMap[col.ID]
+		if !ok {
+			val, err = tables.GetColDefaultValue(w.sessCtx, col, w.defaultVals)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if val.Kind() == types.KindMysqlTime {
+				t := val.GetMysqlTime()
+				if t.Type() == mysql.TypeTimestamp && sysZone != time.UTC {
+					if err = t.ConvertTimeZone(sysZone, time.UTC); err != nil {
+						return errors.Trace(err)
+					}
+					val.SetMysqlTime(t)
+				}
+			}
+		}
+		row[i] = val
+	}
+
+	p, err := w.reorgTbl.GetPartitionByRow(w.sessCtx, row)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := w.addingIDs[p.GetPhysicalID()]; !ok {
+		// The reorganized partitions must cover the rows of the old partitions.
+		return errors.Trace(table.ErrNoPartitionForGivenValue.GenWithStackByArgs("from reorganized partitions"))
+	}
+	w.rowRecords = append(w.rowRecords, &reorgPartitionRecord{
+		key:       recordKey,
+		newKey:    tablecodec.EncodeRecordKey(p.RecordPrefix(), handle),
+		vals:      rawRow,
+		row:       row,
+		handle:    handle,
+		partition: p,
+	})
+	return nil
+}
+
+func (w *reorgPartitionWorker) cleanRowMap() {
+	for id := range w.rowMap {
+		delete(w.rowMap, id)
+	}
+}
+
+// BackfillDataInTxn writes the rows and their indexes into the reorganized partitions in a transaction,
+// lock corresponding rowKey, if the value of rowKey is changed, the txn will rollback and retry.
+func (w *reorgPartitionWorker) BackfillDataInTxn(handleRange reorgBackfillTask) (taskCtx backfillTaskContext, errInTxn error) {
+	oprStartTime := time.Now()
+	errInTxn = kv.RunInNewTxn(context.Background(), w.sessCtx.GetStore(), true, func(ctx context.Context, txn kv.Transaction) error {
+		taskCtx.addedCount = 0
+		taskCtx.scanCount = 0
+		txn.SetOption(tikvstore.Priority, w.priority)
+
+		rowRecords, nextKey, taskDone, err := w.fetchRowColVals(txn, handleRange)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		taskCtx.nextKey = nextKey
+		taskCtx.done = taskDone
+
+		newKeys := make([]kv.Key, 0, len(rowRecords))
+		for _, rowRecord := range rowRecords {
+			newKeys = append(newKeys, rowRecord.newKey)
+		}
+		found, err := txn.BatchGet(ctx, newKeys)
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		for _, rowRecord := range rowRecords {
+			taskCtx.scanCount++
+			// The row is already written into the reorganized partition, we skip it.
+			if _, ok := found[string(rowRecord.newKey)]; ok {
+				continue
+			}
+
+			// Lock the row key to notify us that someone delete or update the row,
+			// then we should not backfill the row.
+			if err = txn.LockKeys(context.Background(), new(kv.LockCtx), rowRecord.key); err != nil {
+				return errors.Trace(err)
+			}
+			if err = txn.Set(rowRecord.newKey, rowRecord.vals); err != nil {
+				return errors.Trace(err)
+			}
+			for _, idx := range rowRecord.partition.Indices() {
+				vals, err := idx.FetchValues(rowRecord.row, nil)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				rsData := tables.TryGetHandleRestoredDataWrapper(rowRecord.partition, rowRecord.row, nil, idx.Meta())
+				if _, err = idx.Create(w.sessCtx, txn, vals, rowRecord.handle, rsData); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			taskCtx.addedCount++
+		}
+		return nil
+	})
+	logSlowOperations(time.Since(oprStartTime), "ReorgPartitionBackfillDataInTxn", 3000)
+
+	return
+}
+
 // onExchangeTablePartition exchange partition data
 func (w *worker) onExchangeTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	var (
@@ -1185,6 +1704,7 @@ func (w *worker) onExchangeTablePartition(d *ddlCtx, t *meta.Meta, job *model.Jo
 
 	index, _, err := getPartitionDef(pt, partName)
 	if err != nil {
+		job.State = model.JobStateCancelled
 		return ver, errors.Trace(err)
 	}
 
@@ -1342,12 +1862,7 @@ func checkExchangePartitionRecordValidation(w *worker, pt *model.TableInfo, inde
 		if pi.Num == 1 {
 			return nil
 		}
-		var buf strings.Builder
-		buf.WriteString("select 1 from %n.%n where mod(")
-		buf.WriteString(pi.Expr)
-		buf.WriteString(", %?) != %? limit 1")
-		sql = buf.String()
-		paramList = append(paramList, schemaName.L, tableName.L, pi.Num, index)
+		sql, paramList = buildCheckSQLForHashPartition(pi, index, schemaName, tableName)
 	case model.PartitionTypeRange:
 		// Table has only one partition and has the maximum value
 		if len(pi.Definitions) == 1 && strings.EqualFold(pi.Definitions[index].LessThan[0], partitionMaxValue) {
@@ -1360,11 +1875,7 @@ func checkExchangePartitionRecordValidation(w *worker, pt *model.TableInfo, inde
 			sql, paramList = buildCheckSQLForRangeColumnsPartition(pi, index, schemaName, tableName)
 		}
 	case model.PartitionTypeList:
-		if len(pi.Columns) == 0 {
-			sql, paramList = buildCheckSQLForListPartition(pi, index, schemaName, tableName)
-		} else if len(pi.Columns) == 1 {
-			sql, paramList = buildCheckSQLForListColumnsPartition(pi, index, schemaName, tableName)
-		}
+		sql, paramList = buildCheckSQLForListPartition(pi, index, schemaName, tableName)
 	default:
 		return errUnsupportedPartitionType.GenWithStackByArgs(pt.Name.O)
 	}
@@ -1391,6 +1902,22 @@ func checkExchangePartitionRecordValidation(w *worker, pt *model.TableInfo, inde
 	return nil
 }
 
+func buildCheckSQLForHashPartition(pi *model.PartitionInfo, index int, schemaName, tableName model.CIStr) (string, []interface{}) {
+	var buf strings.Builder
+	// The NULL value is put into the first partition, and the remainder of the negative value is
+	// turned into the positive one, see locateHashPartition.
+	buf.WriteString("select 1 from %n.%n where abs(mod(")
+	buf.WriteString(pi.Expr)
+	buf.WriteString(", %?)) != %?")
+	if index != 0 {
+		buf.WriteString(" or (")
+		buf.WriteString(pi.Expr)
+		buf.WriteString(") is null")
+	}
+	buf.WriteString(" limit 1")
+	return buf.String(), []interface{}{schemaName.L, tableName.L, pi.Num, index}
+}
+
 func buildCheckSQLForRangeExprPartition(pi *model.PartitionInfo, index int, schemaName, tableName model.CIStr) (string, []interface{}) {
 	var buf strings.Builder
 	paramList := make([]interface{}, 0, 4)
@@ -1402,21 +1929,26 @@ func buildCheckSQLForRangeExprPartition(pi *model.PartitionInfo, index int, sche
 		buf.WriteString(" >= %? limit 1")
 		paramList = append(paramList, schemaName.L, tableName.L, trimQuotation(pi.Definitions[index].LessThan[0]))
 		return buf.String(), paramList
-	} else if index == len(pi.Definitions)-1 && strings.EqualFold(pi.Definitions[index].LessThan[0], partitionMaxValue) {
-		buf.WriteString("select 1 from %n.%n where ")
-		buf.WriteString(pi.Expr)
-		buf.WriteString(" < %? limit 1")
-		paramList = append(paramList, schemaName.L, tableName.L, trimQuotation(pi.Definitions[index-1].LessThan[0]))
-		return buf.String(), paramList
-	} else {
+	}
+	// The NULL value is put into the first partition.
+	if index == len(pi.Definitions)-1 && strings.EqualFold(pi.Definitions[index].LessThan[0], partitionMaxValue) {
 		buf.WriteString("select 1 from %n.%n where ")
 		buf.WriteString(pi.Expr)
-		buf.WriteString(" < %? or ")
+		buf.WriteString(" < %? or (")
 		buf.WriteString(pi.Expr)
-		buf.WriteString(" >= %? limit 1")
-		paramList = append(paramList, schemaName.L, tableName.L, trimQuotation(pi.Definitions[index-1].LessThan[0]), trimQuotation(pi.Definitions[index].LessThan[0]))
+		buf.WriteString(") is null limit 1")
+		paramList = append(paramList, schemaName.L, tableName.L, trimQuotation(pi.Definitions[index-1].LessThan[0]))
 		return buf.String(), paramList
 	}
+	buf.WriteString("select 1 from %n.%n where ")
+	buf.WriteString(pi.Expr)
+	buf.WriteString(" < %? or ")
+	buf.WriteString(pi.Expr)
+	buf.WriteString(" >= %? or (")
+	buf.WriteString(pi.Expr)
+	buf.WriteString(") is null limit 1")
+	paramList = append(paramList, schemaName.L, tableName.L, trimQuotation(pi.Definitions[index-1].LessThan[0]), trimQuotation(pi.Definitions[index].LessThan[0]))
+	return buf.String(), paramList
 }
 
 func trimQuotation(str string) string {
@@ -1430,43 +1962,47 @@ func buildCheckSQLForRangeColumnsPartition(pi *model.PartitionInfo, index int, s
 		paramList = append(paramList, schemaName.L, tableName.L, colName, trimQuotation(pi.Definitions[index].LessThan[0]))
 		return "select 1 from %n.%n where %n >= %? limit 1", paramList
 	} else if index == len(pi.Definitions)-1 && strings.EqualFold(pi.Definitions[index].LessThan[0], partitionMaxValue) {
-		paramList = append(paramList, schemaName.L, tableName.L, colName, trimQuotation(pi.Definitions[index-1].LessThan[0]))
-		return "select 1 from %n.%n where %n < %? limit 1", paramList
+		paramList = append(paramList, schemaName.L, tableName.L, colName, trimQuotation(pi.Definitions[index-1].LessThan[0]), colName)
+		return "select 1 from %n.%n where %n < %? or %n is null limit 1", paramList
 	} else {
-		paramList = append(paramList, schemaName.L, tableName.L, colName, trimQuotation(pi.Definitions[index-1].LessThan[0]), colName, trimQuotation(pi.Definitions[index].LessThan[0]))
-		return "select 1 from %n.%n where %n < %? or %n >= %? limit 1", paramList
+		paramList = append(paramList, schemaName.L, tableName.L, colName, trimQuotation(pi.Definitions[index-1].LessThan[0]), colName, trimQuotation(pi.Definitions[index].LessThan[0]), colName)
+		return "select 1 from %n.%n where %n < %? or %n >= %? or %n is null limit 1", paramList
 	}
 }
 
+// buildCheckSQLForListPartition builds the SQL to find a row which isn't in the values of the list
+// partition. The values are compared by `<=>` since a list partition may contain the NULL value.
 func buildCheckSQLForListPartition(pi *model.PartitionInfo, index int, schemaName, tableName model.CIStr) (string, []interface{}) {
 	var buf strings.Builder
-	buf.WriteString("select 1 from %n.%n where ")
-	buf.WriteString(pi.Expr)
-	buf.WriteString(" not in (%?) limit 1")
-	inValues := getInValues(pi, index)
-
-	paramList := make([]interface{}, 0, 3)
-	paramList = append(paramList, schemaName.L, tableName.L, inValues)
-	return buf.String(), paramList
-}
-
-func buildCheckSQLForListColumnsPartition(pi *model.PartitionInfo, index int, schemaName, tableName model.CIStr) (string, []interface{}) {
-	colName := pi.Columns[0].L
-	var buf strings.Builder
-	buf.WriteString("select 1 from %n.%n where %n not in (%?) limit 1")
-	inValues := getInValues(pi, index)
-
-	paramList := make([]interface{}, 0, 4)
-	paramList = append(paramList, schemaName.L, tableName.L, colName, inValues)
-	return buf.String(), paramList
-}
-
-func getInValues(pi *model.PartitionInfo, index int) []string {
-	inValues := make([]string, 0, len(pi.Definitions[index].InValues))
-	for _, inValue := range pi.Definitions[index].InValues {
-		inValues = append(inValues, inValue...)
+	paramList := make([]interface{}, 0, 2+len(pi.Columns)*len(pi.Definitions[index].InValues))
+	paramList = append(paramList, schemaName.L, tableName.L)
+	buf.WriteString("select 1 from %n.%n where not (")
+	// The values are the restored expressions of the partition definition, write them to the
+	// origin sql string like pi.Expr.
+	for i, inValue := range pi.Definitions[index].InValues {
+		if i > 0 {
+			buf.WriteString(" or ")
+		}
+		buf.WriteString("(")
+		for j, val := range inValue {
+			if j > 0 {
+				buf.WriteString(" and ")
+			}
+			if len(pi.Columns) == 0 {
+				buf.WriteString("(")
+				buf.WriteString(pi.Expr)
+				buf.WriteString(")")
+			} else {
+				buf.WriteString("%n")
+				paramList = append(paramList, pi.Columns[j].L)
+			}
+			buf.WriteString(" <=> ")
+			buf.WriteString(val)
+		}
+		buf.WriteString(")")
 	}
-	return inValues
+	buf.WriteString(") limit 1")
+	return buf.String(), paramList
 }
 
 func checkAddPartitionTooManyPartitions(piDefs uint64) error {
@@ -22,4 +22,6 @@ var (
 	ErrInvalidConstraintFormat = errors.New("label constraint should be in format '{+|-}key=value'")
 	// ErrUnsupportedConstraint is from constraint.go.
 	ErrUnsupportedConstraint = errors.New("unsupported label constraint")
+	// ErrDuplicatePolicyOption is from policy.go.
+	ErrDuplicatePolicyOption = errors.New("duplicate placement policy option")
 )
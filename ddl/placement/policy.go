@@ -0,0 +1,104 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package placement
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/format"
+)
+
+// Policy is a named placement policy. A table or a partition using the policy gets the rules
+// built from the replicas and the constraints of each role. The constraints are in the same
+// format as the CONSTRAINTS option of the placement specs, e.g. `["+zone=sh"]` or `{"+zone=sh": 1}`.
+type Policy struct {
+	Name                string `json:"name"`
+	LeaderConstraints   string `json:"leader_constraints"`
+	Followers           uint64 `json:"followers"`
+	FollowerConstraints string `json:"follower_constraints"`
+	Voters              uint64 `json:"voters"`
+	VoterConstraints    string `json:"voter_constraints"`
+	Learners            uint64 `json:"learners"`
+	LearnerConstraints  string `json:"learner_constraints"`
+}
+
+// NewPolicy builds a placement policy from the options of CREATE or ALTER PLACEMENT POLICY.
+func NewPolicy(name string, options []*ast.PlacementPolicyOption) (*Policy, error) {
+	p := &Policy{Name: name}
+	seen := make(map[ast.PlacementPolicyOptionType]struct{}, len(options))
+	for _, opt := range options {
+		if _, ok := seen[opt.Tp]; ok {
+			var sb strings.Builder
+			if err := opt.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %s", ErrDuplicatePolicyOption, sb.String())
+		}
+		seen[opt.Tp] = struct{}{}
+		switch opt.Tp {
+		case ast.PlacementOptionLeaderConstraints:
+			p.LeaderConstraints = opt.StrValue
+		case ast.PlacementOptionFollowers:
+			p.Followers = opt.UintValue
+		case ast.PlacementOptionFollowerConstraints:
+			p.FollowerConstraints = opt.StrValue
+		case ast.PlacementOptionVoters:
+			p.Voters = opt.UintValue
+		case ast.PlacementOptionVoterConstraints:
+			p.VoterConstraints = opt.StrValue
+		case ast.PlacementOptionLearners:
+			p.Learners = opt.UintValue
+		case ast.PlacementOptionLearnerConstraints:
+			p.LearnerConstraints = opt.StrValue
+		}
+	}
+	return p, nil
+}
+
+// Specs returns the placement specs adding the rules of the policy.
+func (p *Policy) Specs() []*ast.PlacementSpec {
+	specs := []*ast.PlacementSpec{{
+		Tp:          ast.PlacementAdd,
+		Role:        ast.PlacementRoleLeader,
+		Replicas:    1,
+		Constraints: p.LeaderConstraints,
+	}}
+	if p.Followers > 0 || p.FollowerConstraints != "" {
+		specs = append(specs, &ast.PlacementSpec{
+			Tp:          ast.PlacementAdd,
+			Role:        ast.PlacementRoleFollower,
+			Replicas:    p.Followers,
+			Constraints: p.FollowerConstraints,
+		})
+	}
+	if p.Voters > 0 || p.VoterConstraints != "" {
+		specs = append(specs, &ast.PlacementSpec{
+			Tp:          ast.PlacementAdd,
+			Role:        ast.PlacementRoleVoter,
+			Replicas:    p.Voters,
+			Constraints: p.VoterConstraints,
+		})
+	}
+	if p.Learners > 0 || p.LearnerConstraints != "" {
+		specs = append(specs, &ast.PlacementSpec{
+			Tp:          ast.PlacementAdd,
+			Role:        ast.PlacementRoleLearner,
+			Replicas:    p.Learners,
+			Constraints: p.LearnerConstraints,
+		})
+	}
+	return specs
+}
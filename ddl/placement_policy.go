@@ -0,0 +1,354 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/ddl/placement"
+	"github.com/pingcap/tidb/domain/infosync"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// Placement policies are named sets of placement specs stored in mysql.tidb_placement_policy. A table
+// or a partition uses a policy by a row of mysql.tidb_placement_policy_object, and the rules of the
+// policy are put to PD as the rule bundle of each physical table. The policy of a partition overrides
+// the policy of its table, and the bundles of a table are rebuilt when the policies it uses change.
+
+const (
+	placementPolicyTable       = "tidb_placement_policy"
+	placementPolicyObjectTable = "tidb_placement_policy_object"
+)
+
+// CreatePlacementPolicy creates a placement policy.
+func (d *ddl) CreatePlacementPolicy(ctx sessionctx.Context, policy *placement.Policy, ifNotExists bool) error {
+	policy.Name = strings.ToLower(strings.TrimSpace(policy.Name))
+	if policy.Name == "" {
+		return errors.Errorf("placement policy name is empty")
+	}
+	if _, err := buildPolicyBundle(policy, 0, placement.RuleIndexTable); err != nil {
+		return errors.Trace(err)
+	}
+	_, err := d.execPlacementSQL(`INSERT INTO %n.%n (name, leader_constraints, followers, follower_constraints,
+		voters, voter_constraints, learners, learner_constraints) VALUES (%?, %?, %?, %?, %?, %?, %?, %?)`,
+		mysql.SystemDB, placementPolicyTable, policy.Name, policy.LeaderConstraints, policy.Followers, policy.FollowerConstraints,
+		policy.Voters, policy.VoterConstraints, policy.Learners, policy.LearnerConstraints)
+	if kv.ErrKeyExists.Equal(err) {
+		err = ErrPlacementPolicyExists.GenWithStackByArgs(policy.Name)
+		if ifNotExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+	}
+	return errors.Trace(err)
+}
+
+// AlterPlacementPolicy replaces the placement specs of a placement policy, and rebuilds the
+// rule bundles of the tables using it.
+func (d *ddl) AlterPlacementPolicy(ctx sessionctx.Context, policy *placement.Policy) error {
+	policy.Name = strings.ToLower(strings.TrimSpace(policy.Name))
+	if _, err := buildPolicyBundle(policy, 0, placement.RuleIndexTable); err != nil {
+		return errors.Trace(err)
+	}
+	policies, err := LoadPlacementPolicies(ctx, policy.Name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(policies) == 0 {
+		return ErrPlacementPolicyNotExists.GenWithStackByArgs(policy.Name)
+	}
+	_, err = d.execPlacementSQL(`UPDATE %n.%n SET leader_constraints = %?, followers = %?, follower_constraints = %?,
+		voters = %?, voter_constraints = %?, learners = %?, learner_constraints = %? WHERE name = %?`,
+		mysql.SystemDB, placementPolicyTable, policy.LeaderConstraints, policy.Followers, policy.FollowerConstraints,
+		policy.Voters, policy.VoterConstraints, policy.Learners, policy.LearnerConstraints, policy.Name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	objects, err := d.loadPolicyObjects(ctx, "WHERE policy_name = %?", policy.Name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	is := d.GetInfoSchemaWithInterceptor(ctx)
+	tableIDs := make(map[int64]struct{})
+	for objectID := range objects {
+		tbl, ok := is.TableByID(objectID)
+		if !ok {
+			tbl, _, _ = is.FindTableByPartitionID(objectID)
+		}
+		if tbl != nil {
+			tableIDs[tbl.Meta().ID] = struct{}{}
+		}
+	}
+	for tableID := range tableIDs {
+		tbl, ok := is.TableByID(tableID)
+		if !ok {
+			continue
+		}
+		dbInfo, ok := is.SchemaByTable(tbl.Meta())
+		if !ok {
+			continue
+		}
+		if err = d.alterTablePlacement(ctx, dbInfo, tbl.Meta(), nil); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// DropPlacementPolicy drops a placement policy which isn't used by any table or partition.
+func (d *ddl) DropPlacementPolicy(ctx sessionctx.Context, name model.CIStr, ifExists bool) error {
+	objects, err := d.loadPolicyObjects(ctx, "WHERE policy_name = %?", name.L)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	is := d.GetInfoSchemaWithInterceptor(ctx)
+	for objectID := range objects {
+		if _, ok := is.TableByID(objectID); ok {
+			return ErrPlacementPolicyInUse.GenWithStackByArgs(name.L)
+		}
+		if tbl, _, _ := is.FindTableByPartitionID(objectID); tbl != nil {
+			return ErrPlacementPolicyInUse.GenWithStackByArgs(name.L)
+		}
+	}
+	// The remaining objects are dropped tables and partitions.
+	if _, err = d.execPlacementSQL("DELETE FROM %n.%n WHERE policy_name = %?", mysql.SystemDB, placementPolicyObjectTable, name.L); err != nil {
+		return errors.Trace(err)
+	}
+	affectedRows, err := d.execPlacementSQL("DELETE FROM %n.%n WHERE name = %?", mysql.SystemDB, placementPolicyTable, name.L)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if affectedRows == 0 {
+		err = ErrPlacementPolicyNotExists.GenWithStackByArgs(name.L)
+		if ifExists {
+			ctx.GetSessionVars().StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// AlterTablePlacementPolicy sets the placement policy of a table, or of a partition if partitionName
+// isn't empty. An empty policyName removes the policy, then the partition falls back to the policy of
+// the table, and the table without a policy falls back to the placement rules of PD.
+func (d *ddl) AlterTablePlacementPolicy(ctx sessionctx.Context, ident ast.Ident, partitionName, policyName model.CIStr) error {
+	dbInfo, tb, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tblInfo := tb.Meta()
+	objectID := tblInfo.ID
+	if partitionName.L != "" {
+		if tblInfo.Partition == nil {
+			return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+		}
+		if objectID, err = tables.FindPartitionByName(tblInfo, partitionName.L); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if policyName.L != "" {
+		policies, err := LoadPlacementPolicies(ctx, policyName.L)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(policies) == 0 {
+			return ErrPlacementPolicyNotExists.GenWithStackByArgs(policyName.L)
+		}
+	}
+
+	if err = d.alterTablePlacement(ctx, dbInfo, tblInfo, map[int64]string{objectID: policyName.L}); err != nil {
+		return errors.Trace(err)
+	}
+	if policyName.L == "" {
+		_, err = d.execPlacementSQL("DELETE FROM %n.%n WHERE object_id = %?", mysql.SystemDB, placementPolicyObjectTable, objectID)
+	} else {
+		_, err = d.execPlacementSQL("REPLACE INTO %n.%n (object_id, policy_name) VALUES (%?, %?)",
+			mysql.SystemDB, placementPolicyObjectTable, objectID, policyName.L)
+	}
+	return errors.Trace(err)
+}
+
+// alterTablePlacement rebuilds the rule bundles of the physical tables of a table from the policies
+// they use, changes are the policies of the objects overriding the stored ones, and runs the job
+// putting the bundles.
+func (d *ddl) alterTablePlacement(ctx sessionctx.Context, dbInfo *model.DBInfo, tblInfo *model.TableInfo, changes map[int64]string) error {
+	objects, err := d.loadPolicyObjects(ctx, "")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for objectID, policyName := range changes {
+		objects[objectID] = policyName
+	}
+	policies, err := LoadPlacementPolicies(ctx, "")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	policyByName := make(map[string]*placement.Policy, len(policies))
+	for _, policy := range policies {
+		policyByName[policy.Name] = policy
+	}
+
+	var physicalIDs []int64
+	if pi := tblInfo.GetPartitionInfo(); pi != nil {
+		for _, def := range pi.Definitions {
+			physicalIDs = append(physicalIDs, def.ID)
+		}
+	} else {
+		physicalIDs = append(physicalIDs, tblInfo.ID)
+	}
+	bundles := make([]*placement.Bundle, 0, len(physicalIDs))
+	for _, physicalID := range physicalIDs {
+		index := placement.RuleIndexPartition
+		policy := policyByName[objects[physicalID]]
+		if physicalID == tblInfo.ID || policy == nil {
+			// The table isn't partitioned or the partition uses the policy of the table.
+			index = placement.RuleIndexTable
+			policy = policyByName[objects[tblInfo.ID]]
+		}
+		bundle := &placement.Bundle{ID: placement.GroupID(physicalID)}
+		if policy != nil {
+			if bundle, err = buildPolicyBundle(policy, physicalID, index); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		bundles = append(bundles, bundle)
+	}
+
+	job := &model.Job{
+		SchemaID:   dbInfo.ID,
+		TableID:    tblInfo.ID,
+		SchemaName: dbInfo.Name.L,
+		Type:       model.ActionAlterTablePlacement,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{physicalIDs, bundles},
+	}
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// buildPolicyBundle builds the rule bundle of a physical table using the placement policy.
+func buildPolicyBundle(policy *placement.Policy, physicalID int64, index int) (*placement.Bundle, error) {
+	bundle, err := buildPlacementSpecs(&placement.Bundle{ID: placement.GroupID(physicalID)}, policy.Specs())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	finishPlacementBundle(bundle, physicalID, index)
+	return bundle, nil
+}
+
+func onAlterTablePlacement(t *meta.Meta, job *model.Job) (ver int64, err error) {
+	var physicalIDs []int64
+	var bundles []*placement.Bundle
+	if err = job.DecodeArgs(&physicalIDs, &bundles); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if err = infosync.PutRuleBundles(context.TODO(), bundles); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
+	}
+	// used by ApplyDiff in updateSchemaVersion
+	job.CtxVars = []interface{}{physicalIDs}
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
+
+// LoadPlacementPolicies loads the placement policies ordered by the names. If name isn't empty, only
+// the policy of the name is loaded.
+func LoadPlacementPolicies(ctx sessionctx.Context, name string) ([]*placement.Policy, error) {
+	sql := `SELECT name, leader_constraints, followers, follower_constraints, voters, voter_constraints,
+		learners, learner_constraints FROM %n.%n`
+	args := []interface{}{mysql.SystemDB, placementPolicyTable}
+	if name != "" {
+		sql += " WHERE name = %?"
+		args = append(args, strings.ToLower(name))
+	}
+	exec := ctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(context.Background(), sql+" ORDER BY name", args...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rows, _, err := exec.ExecRestrictedStmt(context.Background(), stmt)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	policies := make([]*placement.Policy, 0, len(rows))
+	for _, row := range rows {
+		policies = append(policies, &placement.Policy{
+			Name:                row.GetString(0),
+			LeaderConstraints:   row.GetString(1),
+			Followers:           row.GetUint64(2),
+			FollowerConstraints: row.GetString(3),
+			Voters:              row.GetUint64(4),
+			VoterConstraints:    row.GetString(5),
+			Learners:            row.GetUint64(6),
+			LearnerConstraints:  row.GetString(7),
+		})
+	}
+	return policies, nil
+}
+
+// loadPolicyObjects loads the policies used by the tables and the partitions, the keys are their IDs.
+func (d *ddl) loadPolicyObjects(ctx sessionctx.Context, where string, args ...interface{}) (map[int64]string, error) {
+	exec := ctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(context.Background(), "SELECT object_id, policy_name FROM %n.%n "+where,
+		append([]interface{}{mysql.SystemDB, placementPolicyObjectTable}, args...)...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rows, _, err := exec.ExecRestrictedStmt(context.Background(), stmt)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	objects := make(map[int64]string, len(rows))
+	for _, row := range rows {
+		objects[row.GetInt64(0)] = row.GetString(1)
+	}
+	return objects, nil
+}
+
+// execPlacementSQL writes the placement policy tables in a session of the pool, so the changes are
+// committed by themselves rather than in the transaction of the statement calling the DDL.
+func (d *ddl) execPlacementSQL(sql string, args ...interface{}) (uint64, error) {
+	ctx, err := d.sessPool.get()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	defer d.sessPool.put(ctx)
+	_, err = ctx.(sqlexec.SQLExecutor).ExecuteInternal(context.Background(), sql, args...)
+	if err != nil {
+		return 0, err
+	}
+	return ctx.GetSessionVars().StmtCtx.AffectedRows(), nil
+}
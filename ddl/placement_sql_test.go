@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/tidb/ddl"
 	"github.com/pingcap/tidb/ddl/placement"
 	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/util/testkit"
 )
@@ -775,3 +776,118 @@ alter placement policy
 		testFunc(testcase.name, testcase.hook, testcase.expectErr)
 	}
 }
+
+func (s *testDBSuite1) TestPlacementPolicyObjects(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec(`create table t1 (c int) partition by range (c) (
+	partition p0 values less than (10),
+	partition p1 values less than (20))`)
+	tk.MustExec("create table t2 (c int)")
+	defer tk.MustExec("drop table if exists t1, t2")
+	d := s.dom.DDL()
+
+	// lastPlacementJob returns the physical table IDs and the rule bundles of the last job putting the bundles.
+	var physicalIDs []int64
+	var bundles []*placement.Bundle
+	var checkErr error
+	hook := &ddl.TestDDLCallback{Do: s.dom}
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if job.Type == model.ActionAlterTablePlacement {
+			checkErr = job.DecodeArgs(&physicalIDs, &bundles)
+		}
+	}
+	originalHook := d.GetHook()
+	d.(ddl.DDLForTest).SetHook(hook)
+	defer d.(ddl.DDLForTest).SetHook(originalHook)
+	lastPlacementJob := func() ([]int64, []*placement.Bundle) {
+		c.Assert(checkErr, IsNil)
+		ids, b := physicalIDs, bundles
+		physicalIDs, bundles = nil, nil
+		return ids, b
+	}
+
+	// Create the placement policies.
+	tk.MustExec(`create placement policy X followers=2 follower_constraints='["+zone=sh"]'`)
+	tk.MustExec(`create placement policy y leader_constraints='["+zone=bj"]' learners=1`)
+	err := tk.ExecToErr("create placement policy x followers=1")
+	c.Assert(ddl.ErrPlacementPolicyExists.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustExec("create placement policy if not exists x followers=1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 8240 Placement policy 'x' already exists"))
+	err = tk.ExecToErr(`create placement policy z follower_constraints='["+zone=sh"]'`)
+	c.Assert(ddl.ErrInvalidPlacementSpec.Equal(err), IsTrue, Commentf("err %v", err))
+	err = tk.ExecToErr("create placement policy z followers=1 followers=2")
+	c.Assert(err, ErrorMatches, ".*duplicate placement policy option: FOLLOWERS = 2")
+	tk.MustQuery("select * from information_schema.placement_policies").Check(testkit.Rows(
+		`x  2 ["+zone=sh"] 0  0 `,
+		`y ["+zone=bj"] 0  0  1 `))
+
+	// Use the policies by the tables and the partitions.
+	tk.MustExec("alter table t2 placement policy = X")
+	ids, bundles := lastPlacementJob()
+	tbl, err := s.dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t2"))
+	c.Assert(err, IsNil)
+	c.Assert(ids, DeepEquals, []int64{tbl.Meta().ID})
+	c.Assert(bundles[0].ID, Equals, placement.GroupID(tbl.Meta().ID))
+	c.Assert(bundles[0].Index, Equals, placement.RuleIndexTable)
+	c.Assert(bundles[0].Rules, HasLen, 2)
+	c.Assert(bundles[0].Rules[0].Role, Equals, placement.Follower)
+	c.Assert(bundles[0].Rules[0].Count, Equals, 2)
+	c.Assert(bundles[0].Rules[1].Role, Equals, placement.Leader)
+
+	tk.MustExec("alter table t1 placement policy x")
+	tk.MustExec("alter table t1 alter partition p1 placement policy = y")
+	ids, bundles = lastPlacementJob()
+	tbl, err = s.dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t1"))
+	c.Assert(err, IsNil)
+	partDefs := tbl.Meta().GetPartitionInfo().Definitions
+	c.Assert(ids, DeepEquals, []int64{partDefs[0].ID, partDefs[1].ID})
+	// p0 uses the policy of the table, and p1 uses its own policy.
+	c.Assert(bundles[0].Index, Equals, placement.RuleIndexTable)
+	c.Assert(bundles[0].Rules[0].Role, Equals, placement.Follower)
+	c.Assert(bundles[1].Index, Equals, placement.RuleIndexPartition)
+	c.Assert(bundles[1].Rules, HasLen, 2)
+	c.Assert(bundles[1].Rules[0].Role, Equals, placement.Leader)
+	c.Assert(bundles[1].Rules[1].Role, Equals, placement.Learner)
+
+	err = tk.ExecToErr("alter table t1 alter partition p2 placement policy = y")
+	c.Assert(table.ErrUnknownPartition.Equal(err), IsTrue, Commentf("err %v", err))
+	err = tk.ExecToErr("alter table t1 placement policy = z")
+	c.Assert(ddl.ErrPlacementPolicyNotExists.Equal(err), IsTrue, Commentf("err %v", err))
+	err = tk.ExecToErr("drop placement policy y")
+	c.Assert(ddl.ErrPlacementPolicyInUse.Equal(err), IsTrue, Commentf("err %v", err))
+
+	// Altering the policy rebuilds the bundles of the tables using it.
+	tk.MustExec("alter placement policy y voters=3")
+	_, bundles = lastPlacementJob()
+	voters := 0
+	for _, rule := range bundles[1].Rules {
+		if rule.Role == placement.Voter {
+			voters += rule.Count
+		}
+	}
+	c.Assert(voters, Equals, 3)
+	tk.MustQuery("select voters from information_schema.placement_policies where policy_name = 'y'").Check(testkit.Rows("3"))
+	err = tk.ExecToErr("alter placement policy z voters=3")
+	c.Assert(ddl.ErrPlacementPolicyNotExists.Equal(err), IsTrue, Commentf("err %v", err))
+
+	// Removing the policy of the partition falls back to the policy of the table.
+	tk.MustExec("alter table t1 alter partition p1 placement policy = default")
+	_, bundles = lastPlacementJob()
+	c.Assert(bundles[1].Index, Equals, placement.RuleIndexTable)
+	c.Assert(bundles[1].Rules[0].Role, Equals, placement.Follower)
+	tk.MustExec("drop placement policy y")
+	err = tk.ExecToErr("drop placement policy y")
+	c.Assert(ddl.ErrPlacementPolicyNotExists.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustExec("drop placement policy if exists y")
+
+	// The policies used by the dropped tables can be dropped.
+	tk.MustExec("alter table t1 placement policy default")
+	_, bundles = lastPlacementJob()
+	c.Assert(bundles[0].Rules, HasLen, 0)
+	tk.MustExec("drop table t2")
+	tk.MustExec("drop placement policy x")
+	tk.MustQuery("select * from information_schema.placement_policies").Check(testkit.Rows())
+	tk.MustQuery("select * from mysql.tidb_placement_policy_object").Check(testkit.Rows())
+}
@@ -41,6 +41,7 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/encoding"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tidb/util/ranger"
@@ -58,10 +59,18 @@ type reorgCtx struct {
 	doneCh chan error
 	// rowCount is used to simulate a job's row count.
 	rowCount int64
+	// lastRowCount and lastUpdateTime are the row count and the time of the last progress update,
+	// they're used to calculate the backfill speed and only accessed by the DDL worker.
+	lastRowCount   int64
+	lastUpdateTime time.Time
 	// notifyCancelReorgJob is used to notify the backfilling goroutine if the DDL job is cancelled.
 	// 0: job is not canceled.
 	// 1: job is canceled.
 	notifyCancelReorgJob int32
+	// notifyPauseReorgJob is used to notify the backfilling goroutine if the DDL job is paused.
+	// 0: job is not paused.
+	// 1: job is paused.
+	notifyPauseReorgJob int32
 	// doneHandle is used to simulate the handle that has been processed.
 
 	doneKey atomic.Value // nullable kv.Key
@@ -111,6 +120,18 @@ func (rc *reorgCtx) isReorgCanceled() bool {
 	return atomic.LoadInt32(&rc.notifyCancelReorgJob) == 1
 }
 
+func (rc *reorgCtx) notifyReorgPause() {
+	atomic.StoreInt32(&rc.notifyPauseReorgJob, 1)
+}
+
+func (rc *reorgCtx) cleanNotifyReorgPause() {
+	atomic.StoreInt32(&rc.notifyPauseReorgJob, 0)
+}
+
+func (rc *reorgCtx) isReorgPaused() bool {
+	return atomic.LoadInt32(&rc.notifyPauseReorgJob) == 1
+}
+
 func (rc *reorgCtx) setRowCount(count int64) {
 	atomic.StoreInt64(&rc.rowCount, count)
 }
@@ -179,6 +200,8 @@ func (w *worker) runReorgJob(t *meta.Meta, reorgInfo *reorgInfo, tblInfo *model.
 		w.reorgCtx.doneCh = make(chan error, 1)
 		// initial reorgCtx
 		w.reorgCtx.setRowCount(job.GetRowCount())
+		w.reorgCtx.lastRowCount = job.GetRowCount()
+		w.reorgCtx.lastUpdateTime = time.Now()
 		w.reorgCtx.setNextKey(reorgInfo.StartKey)
 		w.reorgCtx.setCurrentElement(reorgInfo.currElement)
 		w.reorgCtx.mu.warnings = make(map[errors.ErrorID]*terror.Error)
@@ -236,7 +259,8 @@ func (w *worker) runReorgJob(t *meta.Meta, reorgInfo *reorgInfo, tblInfo *model.
 		rowCount, doneKey, currentElement := w.reorgCtx.getRowCountAndKey()
 		// Update a job's RowCount.
 		job.SetRowCount(rowCount)
-		updateBackfillProgress(w, reorgInfo, tblInfo, rowCount)
+		progress := w.getReorgProgress(reorgInfo, tblInfo, rowCount, doneKey)
+		updateBackfillProgress(reorgInfo, progress, rowCount)
 
 		// Update a job's warnings.
 		w.mergeWarningsIntoJob(job)
@@ -247,6 +271,9 @@ func (w *worker) runReorgJob(t *meta.Meta, reorgInfo *reorgInfo, tblInfo *model.
 		// Since daemon-worker is triggered by timer to store the info half-way.
 		// you should keep these infos is read-only (like job) / atomic (like doneKey & element) / concurrent safe.
 		err := t.UpdateDDLReorgStartHandle(job, currentElement, doneKey)
+		if err == nil && progress != nil {
+			err = t.UpdateDDLReorgProgress(job, progress)
+		}
 
 		logutil.BgLogger().Info("[ddl] run reorg job wait timeout",
 			zap.Duration("waitTime", waitTimeout),
@@ -269,15 +296,34 @@ func (w *worker) mergeWarningsIntoJob(job *model.Job) {
 	w.reorgCtx.mu.Unlock()
 }
 
-func updateBackfillProgress(w *worker, reorgInfo *reorgInfo, tblInfo *model.TableInfo,
-	addedRowCount int64) {
-	if tblInfo == nil || addedRowCount == 0 {
+// getReorgProgress gets the progress of the reorganization job, it returns nil if the table is unknown.
+// The backfill speed is calculated from the rows reorganized since the last call.
+func (w *worker) getReorgProgress(reorgInfo *reorgInfo, tblInfo *model.TableInfo, rowCount int64, doneKey kv.Key) *meta.DDLReorgProgress {
+	if tblInfo == nil {
+		return nil
+	}
+	now := time.Now()
+	rowRate := float64(0)
+	if elapsed := now.Sub(w.reorgCtx.lastUpdateTime).Seconds(); elapsed > 0 && rowCount > w.reorgCtx.lastRowCount {
+		rowRate = float64(rowCount-w.reorgCtx.lastRowCount) / elapsed
+	}
+	w.reorgCtx.lastRowCount = rowCount
+	w.reorgCtx.lastUpdateTime = now
+	return &meta.DDLReorgProgress{
+		TotalRows: getTableTotalCount(w, tblInfo),
+		RowRate:   rowRate,
+		StartKey:  tryDecodeToHandleString(doneKey),
+		EndKey:    tryDecodeToHandleString(reorgInfo.EndKey),
+	}
+}
+
+func updateBackfillProgress(reorgInfo *reorgInfo, reorgProgress *meta.DDLReorgProgress, addedRowCount int64) {
+	if reorgProgress == nil || addedRowCount == 0 {
 		return
 	}
-	totalCount := getTableTotalCount(w, tblInfo)
 	progress := float64(0)
-	if totalCount > 0 {
-		progress = float64(addedRowCount) / float64(totalCount)
+	if reorgProgress.TotalRows > 0 {
+		progress = float64(addedRowCount) / float64(reorgProgress.TotalRows)
 	} else {
 		progress = 1
 	}
@@ -331,6 +377,11 @@ func (w *worker) isReorgRunnable(d *ddlCtx) error {
 		return errCancelledDDLJob
 	}
 
+	if w.reorgCtx.isReorgPaused() {
+		// Job is paused. The reorg progress is saved, so it can be continued after the job is resumed.
+		return errPausedDDLJob
+	}
+
 	if !d.isOwner() {
 		// If it's not the owner, we will try later, so here just returns an error.
 		logutil.BgLogger().Info("[ddl] DDL worker is not the DDL owner", zap.String("ID", d.uuid))
@@ -353,6 +404,9 @@ type reorgInfo struct {
 	PhysicalTableID int64
 	elements        []*meta.Element
 	currElement     *meta.Element
+	// charsetConverters are the converters of the columns checked by the modify table charset job,
+	// keyed by the column IDs.
+	charsetConverters map[int64]*encoding.Converter
 }
 
 func (r *reorgInfo) String() string {
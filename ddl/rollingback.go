@@ -110,7 +110,7 @@ func convertNotStartAddIdxJob2RollbackJob(t *meta.Meta, job *model.Job, occuredE
 // Since modifying column job has two types: normal-type and reorg-type, we should handle it respectively.
 // normal-type has only two states:    None -> Public
 // reorg-type has five states:         None -> Delete-only -> Write-only -> Write-org -> Public
-func rollingbackModifyColumn(t *meta.Meta, job *model.Job) (ver int64, err error) {
+func rollingbackModifyColumn(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
 	_, tblInfo, oldCol, jp, err := getModifyColumnInfo(t, job)
 	if err != nil {
 		return ver, err
@@ -120,7 +120,7 @@ func rollingbackModifyColumn(t *meta.Meta, job *model.Job) (ver int64, err error
 		if job.SchemaState == model.StateNone {
 			// When change null to not null, although state is unchanged with none, the oldCol flag's has been changed to preNullInsertFlag.
 			// To roll back this kind of normal job, it is necessary to mark the state as JobStateRollingback to restore the old col's flag.
-			if jp.modifyColumnTp == mysql.TypeNull && tblInfo.Columns[oldCol.Offset].Flag|mysql.PreventNullInsertFlag != 0 {
+			if jp.modifyColumnTp == mysql.TypeNull && tblInfo.Columns[oldCol.Offset].Flag&mysql.PreventNullInsertFlag != 0 {
 				job.State = model.JobStateRollingback
 				return ver, errCancelledDDLJob
 			}
@@ -138,6 +138,14 @@ func rollingbackModifyColumn(t *meta.Meta, job *model.Job) (ver int64, err error
 		job.State = model.JobStateCancelled
 		return ver, errCancelledDDLJob
 	}
+	// If the value of SnapshotVer isn't zero, it means the work is backfilling the changing column and indexes.
+	if job.SchemaState == model.StateWriteReorganization && job.SnapshotVer != 0 {
+		// Modify column workers are started. need to ask them to exit, the reorg handle will be removed
+		// and the job will be converted to a rolling back job when the workers are stopped.
+		logutil.Logger(w.logCtx).Info("[ddl] run the cancelling DDL job", zap.String("job", job.String()))
+		w.reorgCtx.notifyReorgCancel()
+		return w.onModifyColumn(d, t, job)
+	}
 	// The job has been in it's middle state and we roll it back.
 	job.State = model.JobStateRollingback
 	return ver, errCancelledDDLJob
@@ -322,6 +330,48 @@ func rollingbackAddTablePartition(t *meta.Meta, job *model.Job) (ver int64, err
 	return convertAddTablePartitionJob2RollbackJob(t, job, errCancelledDDLJob, tblInfo)
 }
 
+// rollingbackModifyTableCharsetAndCollate handles the modify table charset job which is cancelled by the users.
+func rollingbackModifyTableCharsetAndCollate(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	switch job.SchemaState {
+	case model.StateNone:
+		job.State = model.JobStateCancelled
+		return ver, errCancelledDDLJob
+	case model.StateWriteReorganization:
+		// If the value of SnapshotVer isn't zero, it means the work is checking the rows.
+		if job.SnapshotVer != 0 {
+			// convert charset workers are started. need to ask them to exit.
+			logutil.Logger(w.logCtx).Info("[ddl] run the cancelling DDL job", zap.String("job", job.String()))
+			w.reorgCtx.notifyReorgCancel()
+			return w.onModifyTableCharsetAndCollate(d, t, job)
+		}
+	}
+	job.State = model.JobStateRollingback
+	return ver, errCancelledDDLJob
+}
+
+// rollingbackReorganizePartition handles the reorganize partition job which is cancelled by the users.
+func rollingbackReorganizePartition(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	switch job.SchemaState {
+	case model.StateNone:
+		job.State = model.JobStateCancelled
+		return ver, errCancelledDDLJob
+	case model.StateWriteReorganization:
+		// If the value of SnapshotVer isn't zero, it means the work is backfilling the rows.
+		if job.SnapshotVer != 0 {
+			// reorganize partition workers are started. need to ask them to exit.
+			logutil.Logger(w.logCtx).Info("[ddl] run the cancelling DDL job", zap.String("job", job.String()))
+			w.reorgCtx.notifyReorgCancel()
+			return w.onReorganizePartition(d, t, job)
+		}
+	case model.StateDeleteReorganization:
+		// The new partitions have replaced the old ones, so the job can't be cancelled.
+		job.State = model.JobStateRunning
+		return ver, nil
+	}
+	job.State = model.JobStateRollingback
+	return ver, errCancelledDDLJob
+}
+
 func rollingbackDropTableOrView(t *meta.Meta, job *model.Job) error {
 	tblInfo, err := checkTableExistAndCancelNonExistJob(t, job, job.SchemaID)
 	if err != nil {
@@ -395,6 +445,32 @@ func rollingbackTruncateTable(t *meta.Meta, job *model.Job) (ver int64, err erro
 	return cancelOnlyNotHandledJob(job)
 }
 
+// rollingbackLockTables handles the lock/unlock tables job which is cancelled by the users.
+// The tables are locked or unlocked one by one, so the job can only be cancelled before any table is handled.
+func rollingbackLockTables(t *meta.Meta, job *model.Job) (ver int64, err error) {
+	arg := &lockTablesArg{}
+	if err = job.DecodeArgs(arg); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	if arg.IndexOfUnlock > 0 || arg.IndexOfLock > 0 {
+		// Some tables have been unlocked or locked, just continue to handle the others.
+		job.State = model.JobStateRunning
+		return ver, nil
+	}
+	if arg.IndexOfUnlock == len(arg.UnlockTables) && len(arg.LockTables) > 0 {
+		tbInfo, err := getTableInfo(t, arg.LockTables[0].TableID, arg.LockTables[0].SchemaID)
+		if err == nil && tbInfo.Lock != nil && tbInfo.Lock.State == model.TableLockStatePreLock {
+			// The first table is in the pre-lock state, which is only set by the running lock tables job.
+			// Cancelling the job here would leave the table locked by nobody, so the job has to be finished.
+			job.State = model.JobStateRunning
+			return ver, nil
+		}
+	}
+	job.State = model.JobStateCancelled
+	return ver, errCancelledDDLJob
+}
+
 func convertJob2RollbackJob(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
 	switch job.Type {
 	case model.ActionAddColumn:
@@ -424,14 +500,26 @@ func convertJob2RollbackJob(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 	case model.ActionTruncateTable:
 		ver, err = rollingbackTruncateTable(t, job)
 	case model.ActionModifyColumn:
-		ver, err = rollingbackModifyColumn(t, job)
+		ver, err = rollingbackModifyColumn(w, d, t, job)
 	case model.ActionRebaseAutoID, model.ActionShardRowID, model.ActionAddForeignKey,
 		model.ActionDropForeignKey, model.ActionRenameTable, model.ActionRenameTables,
-		model.ActionModifyTableCharsetAndCollate, model.ActionTruncateTablePartition,
+		model.ActionTruncateTablePartition,
 		model.ActionModifySchemaCharsetAndCollate, model.ActionRepairTable,
 		model.ActionModifyTableAutoIdCache, model.ActionAlterIndexVisibility,
 		model.ActionExchangeTablePartition:
 		ver, err = cancelOnlyNotHandledJob(job)
+	case model.ActionMultiSchemaChange:
+		ver, err = rollingbackMultiSchemaChange(job)
+	case model.ActionReorganizePartition:
+		ver, err = rollingbackReorganizePartition(w, d, t, job)
+	case model.ActionModifyTableCharsetAndCollate:
+		ver, err = rollingbackModifyTableCharsetAndCollate(w, d, t, job)
+	case model.ActionRepairIndex:
+		ver, err = rollingbackRepairIndex(w, d, t, job)
+	case model.ActionCreateTableAsSelect:
+		ver, err = rollingbackCreateTableAsSelect(job)
+	case model.ActionLockTable, model.ActionUnlockTable:
+		ver, err = rollingbackLockTables(t, job)
 	default:
 		job.State = model.JobStateCancelled
 		err = errCancelledDDLJob
@@ -15,6 +15,7 @@ package ddl_test
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 
 	. "github.com/pingcap/check"
@@ -24,6 +25,8 @@ import (
 	"github.com/pingcap/tidb/ddl"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/util/admin"
+	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/testkit"
 )
@@ -99,3 +102,100 @@ func (s *testRollingBackSuite) TestCancelAddIndexJobError(c *C) {
 	c.Assert(job.ErrorCount, Equals, int64(4))
 	c.Assert(job.Error.Error(), Equals, "[ddl:-1]rollback DDL job error count exceed the limit 3, cancelled it now")
 }
+
+// cancelJobInHook cancels the DDL job in a new transaction, it's used in the hook of the DDL worker.
+func (s *testRollingBackSuite) cancelJobInHook(jobID int64) error {
+	hookCtx := mock.NewContext()
+	hookCtx.Store = s.store
+	if err := hookCtx.NewTxn(context.Background()); err != nil {
+		return errors2.Trace(err)
+	}
+	txn, err := hookCtx.Txn(true)
+	if err != nil {
+		return errors2.Trace(err)
+	}
+	errs, err := admin.CancelJobs(txn, []int64{jobID})
+	if err != nil {
+		return errors2.Trace(err)
+	}
+	if errs[0] != nil {
+		return errors2.Trace(errs[0])
+	}
+	return errors2.Trace(txn.Commit(context.Background()))
+}
+
+// TestCancelModifyColumnAtEveryState cancels the modify column job at each of its schema states,
+// and checks the schema and data of the table are the same as the ones before the job.
+func (s *testRollingBackSuite) TestCancelModifyColumnAtEveryState(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.Se.GetSessionVars().EnableChangeColumnType = true
+	tk.MustExec("drop table if exists t_cancel_modify")
+	tk.MustExec("create table t_cancel_modify (a int primary key, b varchar(10), c int, index idx_b(b), index idx_bc(b, c))")
+	for i := 0; i < 64; i++ {
+		tk.MustExec("insert into t_cancel_modify values (?, ?, ?)", i, strconv.Itoa(i), i)
+	}
+	originCreateSQL := tk.MustQuery("show create table t_cancel_modify").Rows()[0][1]
+
+	testCases := []struct {
+		schemaState  model.SchemaState
+		reorgStarted bool
+	}{
+		{model.StateNone, false},
+		{model.StateDeleteOnly, false},
+		{model.StateWriteOnly, false},
+		{model.StateWriteReorganization, false},
+		{model.StateWriteReorganization, true},
+	}
+	tbl := testGetTableByName(c, tk.Se, "test", "t_cancel_modify")
+	var checkErr error
+	testCase := &testCases[0]
+	hook := &ddl.TestDDLCallback{Do: s.dom}
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if job.TableID != tbl.Meta().ID || job.Type != model.ActionModifyColumn {
+			return
+		}
+		if job.State != model.JobStateNone && job.State != model.JobStateRunning {
+			return
+		}
+		if job.SchemaState != testCase.schemaState || (job.SnapshotVer != 0) != testCase.reorgStarted {
+			return
+		}
+		checkErr = s.cancelJobInHook(job.ID)
+	}
+	d := s.dom.DDL()
+	originalHook := d.GetHook()
+	d.(ddl.DDLForTest).SetHook(hook)
+	defer d.(ddl.DDLForTest).SetHook(originalHook)
+
+	for i := range testCases {
+		testCase = &testCases[i]
+		checkErr = nil
+		_, err := tk.Exec("alter table t_cancel_modify modify column b int")
+		comment := Commentf("case %d, state %s, reorg started %v", i, testCase.schemaState, testCase.reorgStarted)
+		c.Assert(checkErr, IsNil, comment)
+		c.Assert(err, NotNil, comment)
+		c.Assert(err.Error(), Equals, "[ddl:8214]Cancelled DDL job", comment)
+
+		// The changing column and indexes should be removed from the table meta.
+		tbl = testGetTableByName(c, tk.Se, "test", "t_cancel_modify")
+		c.Assert(tbl.Meta().Columns, HasLen, 3, comment)
+		c.Assert(tbl.Meta().Indices, HasLen, 2, comment)
+		for j, col := range tbl.Meta().Columns {
+			c.Assert(col.Offset, Equals, j, comment)
+			c.Assert(col.State, Equals, model.StatePublic, comment)
+			c.Assert(col.ChangeStateInfo, IsNil, comment)
+		}
+		tk.MustQuery("show create table t_cancel_modify").Check(testkit.Rows(fmt.Sprintf("t_cancel_modify %s", originCreateSQL)))
+		tk.MustExec("admin check table t_cancel_modify")
+		tk.MustExec("insert into t_cancel_modify values (?, 'a', 1)", 100+i)
+		tk.MustExec("delete from t_cancel_modify where a = ?", 100+i)
+		tk.MustQuery("select count(*) from t_cancel_modify where b = '1'").Check(testkit.Rows("1"))
+	}
+
+	// The job can still be done after it's cancelled.
+	d.(ddl.DDLForTest).SetHook(originalHook)
+	tk.MustExec("alter table t_cancel_modify modify column b int")
+	tk.MustExec("admin check table t_cancel_modify")
+	tk.MustQuery("select b from t_cancel_modify where a = 10").Check(testkit.Rows("10"))
+}
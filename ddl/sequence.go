@@ -104,6 +104,7 @@ func handleSequenceOptions(seqOptions []*ast.SequenceOption, sequenceInfo *model
 			maxSetFlag = true
 		case ast.SequenceCache:
 			sequenceInfo.CacheValue = op.IntValue
+			sequenceInfo.Cache = true
 		case ast.SequenceNoCache:
 			sequenceInfo.Cache = false
 		case ast.SequenceCycle:
@@ -190,6 +191,8 @@ func alterSequenceOptions(sequenceOptions []*ast.SequenceOption, ident ast.Ident
 		restartFlag     bool
 		restartWithFlag bool
 		restartValue    int64
+		noMinFlag       bool
+		noMaxFlag       bool
 	)
 	// Override the old sequence value with new option.
 	for _, op := range sequenceOptions {
@@ -200,10 +203,17 @@ func alterSequenceOptions(sequenceOptions []*ast.SequenceOption, ident ast.Ident
 			oldSequence.Start = op.IntValue
 		case ast.SequenceMinValue:
 			oldSequence.MinValue = op.IntValue
+			noMinFlag = false
+		case ast.SequenceNoMinValue:
+			noMinFlag = true
 		case ast.SequenceMaxValue:
 			oldSequence.MaxValue = op.IntValue
+			noMaxFlag = false
+		case ast.SequenceNoMaxValue:
+			noMaxFlag = true
 		case ast.SequenceCache:
 			oldSequence.CacheValue = op.IntValue
+			oldSequence.Cache = true
 		case ast.SequenceNoCache:
 			oldSequence.Cache = false
 		case ast.SequenceCycle:
@@ -217,6 +227,21 @@ func alterSequenceOptions(sequenceOptions []*ast.SequenceOption, ident ast.Ident
 			restartValue = op.IntValue
 		}
 	}
+	// Reset the min/max value to the default one, which is adjusted with the sign of the new increment.
+	if noMinFlag {
+		if oldSequence.Increment >= 0 {
+			oldSequence.MinValue = model.DefaultPositiveSequenceMinValue
+		} else {
+			oldSequence.MinValue = model.DefaultNegativeSequenceMinValue
+		}
+	}
+	if noMaxFlag {
+		if oldSequence.Increment >= 0 {
+			oldSequence.MaxValue = model.DefaultPositiveSequenceMaxValue
+		} else {
+			oldSequence.MaxValue = model.DefaultNegativeSequenceMaxValue
+		}
+	}
 	if !validateSequenceOptions(oldSequence) {
 		return false, 0, ErrSequenceInvalidData.GenWithStackByArgs(ident.Schema.L, ident.Name.L)
 	}
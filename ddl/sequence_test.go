@@ -1047,6 +1047,71 @@ func (s *testSequenceSuite) TestAlterSequence(c *C) {
 	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("3005"))
 	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("3009"))
 	tk.MustExec("drop sequence if exists seq")
+
+	// Cache can be turned off and on again.
+	tk.MustExec("create sequence seq")
+	tk.MustExec("alter sequence seq nocache")
+	tk.MustQuery("show create sequence seq").Check(testkit.Rows("seq CREATE SEQUENCE `seq` " +
+		"start with 1 minvalue 1 maxvalue 9223372036854775806 increment by 1 nocache nocycle ENGINE=InnoDB"))
+	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("1"))
+	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("2"))
+	tk.MustExec("alter sequence seq cache 10")
+	tk.MustQuery("show create sequence seq").Check(testkit.Rows("seq CREATE SEQUENCE `seq` " +
+		"start with 1 minvalue 1 maxvalue 9223372036854775806 increment by 1 cache 10 nocycle ENGINE=InnoDB"))
+	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("3"))
+
+	// No minvalue/maxvalue reset the value to the default one.
+	tk.MustExec("alter sequence seq minvalue -5 maxvalue 100")
+	tk.MustQuery("show create sequence seq").Check(testkit.Rows("seq CREATE SEQUENCE `seq` " +
+		"start with 1 minvalue -5 maxvalue 100 increment by 1 cache 10 nocycle ENGINE=InnoDB"))
+	tk.MustExec("alter sequence seq no minvalue no maxvalue")
+	tk.MustQuery("show create sequence seq").Check(testkit.Rows("seq CREATE SEQUENCE `seq` " +
+		"start with 1 minvalue 1 maxvalue 9223372036854775806 increment by 1 cache 10 nocycle ENGINE=InnoDB"))
+	tk.MustExec("drop sequence if exists seq")
+}
+
+func (s *testSequenceSuite) TestSequenceBulkInsert(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop sequence if exists seq")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create sequence seq cache 2")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("create table t2 (a int, b int)")
+	tk.MustExec("insert into t1 values (1), (2), (3), (4), (5)")
+
+	// The values are allocated in one batch bigger than the cache size and keep the order.
+	tk.MustExec("insert into t2 select nextval(seq), a from t1 order by a")
+	tk.MustQuery("select * from t2 order by b").Check(testkit.Rows("1 1", "2 2", "3 3", "4 4", "5 5"))
+	tk.MustQuery("select lastval(seq)").Check(testkit.Rows("5"))
+	seqTable, err := s.dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("seq"))
+	c.Assert(err, IsNil)
+	base, end, round := seqTable.(*tables.TableCommon).GetSequenceCommon().GetSequenceBaseEndRound()
+	c.Assert(base, Equals, int64(5))
+	c.Assert(end, Equals, int64(5))
+	c.Assert(round, Equals, int64(0))
+	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("6"))
+	tk.MustExec("delete from t2")
+
+	// The nested nextval is still evaluated row by row.
+	tk.MustExec("insert into t2 select nextval(seq) + nextval(seq), a from t1 order by a")
+	tk.MustQuery("select * from t2 order by b").Check(testkit.Rows("15 1", "19 2", "23 3", "27 4", "31 5"))
+	tk.MustExec("delete from t2")
+
+	// A nocache sequence allocates exactly the needed values, the values cached before the alter are dropped.
+	tk.MustExec("alter sequence seq nocache")
+	tk.MustExec("insert into t2 select nextval(seq), a from t1 order by a")
+	tk.MustQuery("select * from t2 order by b").Check(testkit.Rows("18 1", "19 2", "20 3", "21 4", "22 5"))
+	tk.MustQuery("select nextval(seq)").Check(testkit.Rows("23"))
+	tk.MustExec("delete from t2")
+
+	// The batch of a cycle sequence is cut by the boundary.
+	tk.MustExec("drop sequence seq")
+	tk.MustExec("create sequence seq maxvalue 3 cache 2 cycle")
+	tk.MustExec("insert into t2 select nextval(seq), a from t1 order by a")
+	tk.MustQuery("select * from t2 order by b").Check(testkit.Rows("1 1", "2 2", "3 3", "1 4", "2 5"))
+	tk.MustExec("drop sequence seq")
+	tk.MustExec("drop table t1, t2")
 }
 
 func (s *testSequenceSuite) TestAlterSequencePrivilege(c *C) {
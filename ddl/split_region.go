@@ -15,14 +15,22 @@ package ddl
 
 import (
 	"context"
+	"math"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/statistics"
 	tikvstore "github.com/pingcap/tidb/store/tikv/kv"
+	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
 	"go.uber.org/zap"
 )
 
@@ -132,6 +140,93 @@ func splitIndexRegion(store kv.SplittableStore, tblInfo *model.TableInfo, scatte
 	return regionIDs
 }
 
+// preSplitIndexRegions splits the adding index into `num` regions before it's backfilled. The split keys are the
+// quantiles of the histogram of the first index column, which is read from the storage because the DDL owner may
+// not load the statistics of the table. The index isn't split if the column isn't analyzed, and the failures are
+// only logged like the other pre-splits.
+func (w *worker) preSplitIndexRegions(d *ddlCtx, tblInfo *model.TableInfo, indexInfo *model.IndexInfo, num int) {
+	sp, ok := d.store.(kv.SplittableStore)
+	if !ok {
+		return
+	}
+	sctx, err := w.sessPool.get()
+	if err != nil {
+		logutil.BgLogger().Warn("[ddl] pre split index regions failed", zap.Stringer("index", indexInfo.Name), zap.Error(err))
+		return
+	}
+	defer w.sessPool.put(sctx)
+
+	physicalIDs := []int64{tblInfo.ID}
+	if pi := tblInfo.GetPartitionInfo(); pi != nil && !indexInfo.Global {
+		physicalIDs = physicalIDs[:0]
+		for _, def := range pi.Definitions {
+			physicalIDs = append(physicalIDs, def.ID)
+		}
+	}
+	var splitKeys [][]byte
+	for _, physicalID := range physicalIDs {
+		keys, err := getIndexSplitKeysFromColumnStats(sctx, tblInfo, indexInfo, physicalID, num)
+		if err != nil {
+			logutil.BgLogger().Warn("[ddl] pre split index regions failed", zap.Stringer("index", indexInfo.Name), zap.Error(err))
+			return
+		}
+		splitKeys = append(splitKeys, keys...)
+	}
+	if len(splitKeys) == 0 {
+		return
+	}
+
+	var scatter bool
+	if val, err := variable.GetGlobalSystemVar(sctx.GetSessionVars(), variable.TiDBScatterRegion); err == nil {
+		scatter = variable.TiDBOptOn(val)
+	}
+	ctxWithTimeout, cancel := context.WithTimeout(context.Background(), sctx.GetSessionVars().GetSplitRegionTimeout())
+	defer cancel()
+	regionIDs, err := sp.SplitRegions(ctxWithTimeout, splitKeys, scatter, &tblInfo.ID)
+	if err != nil {
+		logutil.BgLogger().Warn("[ddl] pre split some index regions failed", zap.Stringer("index", indexInfo.Name),
+			zap.Int("successful region count", len(regionIDs)), zap.Error(err))
+	}
+	if scatter {
+		waitScatterRegionFinish(ctxWithTimeout, sp, regionIDs...)
+	}
+}
+
+// getIndexSplitKeysFromColumnStats returns the index keys which split the index of the physical table into `num`
+// regions by the histogram of the first index column.
+func getIndexSplitKeysFromColumnStats(sctx sessionctx.Context, tblInfo *model.TableInfo, indexInfo *model.IndexInfo,
+	physicalID int64, num int) ([][]byte, error) {
+	col := tblInfo.Columns[indexInfo.Columns[0].Offset]
+	ctx := context.Background()
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(ctx, "select count, upper_bound from mysql.stats_buckets where table_id = %? and is_index = 0 and hist_id = %? order by bucket_id", physicalID, col.ID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	sc := &stmtctx.StatementContext{TimeZone: time.UTC}
+	index := tables.NewIndex(physicalID, tblInfo, indexInfo)
+	points := make([]statistics.SplitPoint, 0, len(rows))
+	for _, row := range rows {
+		d := types.NewBytesDatum(row.GetBytes(1))
+		upper, err := d.ConvertTo(sc, &col.FieldType)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		// The index keys of the same value are greater than the key with the minimum handle.
+		key, _, err := index.GenIndexKey(sc, []types.Datum{upper}, kv.IntHandle(math.MinInt64), nil)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		points = append(points, statistics.SplitPoint{Value: key, Count: row.GetInt64(0)})
+	}
+	return statistics.SplitValues(points, num), nil
+}
+
 func waitScatterRegionFinish(ctx context.Context, store kv.SplittableStore, regionIDs ...uint64) {
 	for _, regionID := range regionIDs {
 		err := store.WaitScatterRegionFinish(ctx, regionID, 0)
@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/charset"
 	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
 	field_types "github.com/pingcap/parser/types"
 	"github.com/pingcap/tidb/ddl/placement"
 	"github.com/pingcap/tidb/ddl/util"
@@ -33,11 +34,22 @@ import (
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/meta/autoid"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/store/tikv"
+	tikvstore "github.com/pingcap/tidb/store/tikv/kv"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/ttl"
+	"github.com/pingcap/tidb/types"
+	tidbutil "github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/encoding"
 	"github.com/pingcap/tidb/util/gcutil"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
 )
 
 const tiflashCheckTiDBHTTPAPIHalfInterval = 2500 * time.Millisecond
@@ -596,6 +608,47 @@ func onRebaseAutoID(store kv.Storage, t *meta.Meta, job *model.Job, tp autoid.Al
 	return ver, nil
 }
 
+func onAlterTTLInfo(t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	ttlInfo := &model.TTLInfo{}
+	if err := job.DecodeArgs(ttlInfo); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	// The columns may be changed after the job is submitted.
+	tblInfo.TTLInfo = ttlInfo
+	if err = ttl.CheckTTLInfo(tblInfo); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
+
+func onAlterTTLRemove(t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	tblInfo.TTLInfo = nil
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
+
 func onModifyTableAutoIDCache(t *meta.Meta, job *model.Job) (int64, error) {
 	var cache int64
 	if err := job.DecodeArgs(&cache); err != nil {
@@ -816,10 +869,23 @@ func onModifyTableComment(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	return ver, nil
 }
 
-func onModifyTableCharsetAndCollate(t *meta.Meta, job *model.Job) (ver int64, _ error) {
+// convertingColumn is a column whose data is converted to the target charset by the modify table charset job,
+// its original charset and collation are kept to roll back the job.
+type convertingColumn struct {
+	ID      int64  `json:"id"`
+	Charset string `json:"charset"`
+	Collate string `json:"collate"`
+}
+
+// onModifyTableCharsetAndCollate changes the charset and collation of the table. If the data of some columns
+// may be not representable in the target charset, the columns are changed to the target charset first,
+// so the rows written since then are checked in it, then the existing rows are checked by the reorganization.
+// The job is rolled back if any of them can't be converted.
+func (w *worker) onModifyTableCharsetAndCollate(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	var toCharset, toCollate string
 	var needsOverwriteCols bool
-	if err := job.DecodeArgs(&toCharset, &toCollate, &needsOverwriteCols); err != nil {
+	var convertingCols []*convertingColumn
+	if err := job.DecodeArgs(&toCharset, &toCollate, &needsOverwriteCols, &convertingCols); err != nil {
 		job.State = model.JobStateCancelled
 		return ver, errors.Trace(err)
 	}
@@ -833,14 +899,76 @@ func onModifyTableCharsetAndCollate(t *meta.Meta, job *model.Job) (ver int64, _
 	if err != nil {
 		return ver, errors.Trace(err)
 	}
+	if job.IsRollingback() {
+		return rollbackModifyTableCharsetAndCollate(t, job, tblInfo, convertingCols)
+	}
 
-	// double check.
-	_, err = checkAlterTableCharset(tblInfo, dbInfo, toCharset, toCollate, needsOverwriteCols)
-	if err != nil {
-		job.State = model.JobStateCancelled
-		return ver, errors.Trace(err)
+	originalState := job.SchemaState
+	switch job.SchemaState {
+	case model.StateNone:
+		// double check.
+		_, err = checkAlterTableCharset(tblInfo, dbInfo, toCharset, toCollate, needsOverwriteCols)
+		if err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		if needsOverwriteCols {
+			convertingCols = getConvertingColumns(tblInfo, toCharset)
+		}
+		if len(convertingCols) == 0 {
+			// The data is representable in the target charset, only the meta is changed.
+			setTableCharsetAndCollate(tblInfo, toCharset, toCollate, needsOverwriteCols)
+			ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+			if err != nil {
+				return ver, errors.Trace(err)
+			}
+			job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+			return ver, nil
+		}
+		setColumnsCharsetAndCollate(tblInfo, convertingCols, func(*convertingColumn) (string, string) {
+			return toCharset, toCollate
+		})
+		job.Args = []interface{}{toCharset, toCollate, needsOverwriteCols, convertingCols}
+		// none -> write only
+		job.SchemaState = model.StateWriteOnly
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, originalState != job.SchemaState)
+	case model.StateWriteOnly:
+		// write only -> reorganization
+		job.SchemaState = model.StateWriteReorganization
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != job.SchemaState)
+	case model.StateWriteReorganization:
+		var done bool
+		done, err = w.doConvertTableCharset(d, t, job, tblInfo, convertingCols)
+		if !done {
+			return ver, errors.Trace(err)
+		}
+		setTableCharsetAndCollate(tblInfo, toCharset, toCollate, needsOverwriteCols)
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("table charset", job.SchemaState)
+	}
+	return ver, errors.Trace(err)
+}
+
+// getConvertingColumns returns the columns whose data may be not representable in the target charset.
+func getConvertingColumns(tblInfo *model.TableInfo, toCharset string) []*convertingColumn {
+	var cols []*convertingColumn
+	for _, col := range tblInfo.Columns {
+		if !field_types.HasCharset(&col.FieldType) || len(col.Charset) == 0 || col.Charset == charset.CharsetBin {
+			continue
+		}
+		if encoding.NeedConvert(col.Charset, toCharset) {
+			cols = append(cols, &convertingColumn{ID: col.ID, Charset: col.Charset, Collate: col.Collate})
+		}
 	}
+	return cols
+}
 
+func setTableCharsetAndCollate(tblInfo *model.TableInfo, toCharset, toCollate string, needsOverwriteCols bool) {
 	tblInfo.Charset = toCharset
 	tblInfo.Collate = toCollate
 
@@ -856,15 +984,218 @@ func onModifyTableCharsetAndCollate(t *meta.Meta, job *model.Job) (ver int64, _
 			}
 		}
 	}
+}
 
-	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+func setColumnsCharsetAndCollate(tblInfo *model.TableInfo, cols []*convertingColumn, getCharsetAndCollate func(*convertingColumn) (string, string)) {
+	for _, convCol := range cols {
+		for _, col := range tblInfo.Columns {
+			if col.ID == convCol.ID {
+				col.Charset, col.Collate = getCharsetAndCollate(convCol)
+				break
+			}
+		}
+	}
+}
+
+// doConvertTableCharset checks the rows can be converted to the target charset.
+// It returns true when all the rows are checked.
+func (w *worker) doConvertTableCharset(d *ddlCtx, t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, convertingCols []*convertingColumn) (done bool, _ error) {
+	tbl, err := getTable(d.store, job.SchemaID, tblInfo)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	converters := make(map[int64]*encoding.Converter, len(convertingCols))
+	for _, convCol := range convertingCols {
+		for _, col := range tblInfo.Columns {
+			// The virtual generated columns are not stored, and the elements of the enum and set columns
+			// are checked before the job is submitted, so they are skipped.
+			if col.ID != convCol.ID || col.IsGenerated() && !col.GeneratedStored ||
+				col.Tp == mysql.TypeEnum || col.Tp == mysql.TypeSet {
+				continue
+			}
+			if converters[col.ID], err = encoding.NewConverter(convCol.Charset, col.Charset); err != nil {
+				job.State = model.JobStateCancelled
+				return false, errors.Trace(err)
+			}
+		}
+	}
+	elements := []*meta.Element{{ID: convertingCols[0].ID, TypeKey: meta.ColumnElementKey}}
+	reorgInfo, err := getReorgInfo(d, t, job, tbl, elements)
+	if err != nil || reorgInfo.first {
+		// If we run reorg firstly, we should update the job snapshot version
+		// and then run the reorg next time.
+		return false, errors.Trace(err)
+	}
+	reorgInfo.charsetConverters = converters
+
+	err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (convertErr error) {
+		defer tidbutil.Recover(metrics.LabelDDL, "onModifyTableCharsetAndCollate",
+			func() {
+				convertErr = errCancelledDDLJob.GenWithStack("convert table `%v` charset panic", tblInfo.Name)
+			}, false)
+		return w.convertTableCharsetRecords(tbl, reorgInfo)
+	})
+	if err != nil {
+		if errWaitReorgTimeout.Equal(err) {
+			// if timeout, we should return, check for the owner and re-wait job done.
+			return false, nil
+		}
+		if encoding.ErrInvalidCharacterString.Equal(err) || errCancelledDDLJob.Equal(err) || errCantDecodeRecord.Equal(err) {
+			logutil.BgLogger().Warn("[ddl] run convert table charset job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
+			job.State = model.JobStateRollingback
+			if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
+				logutil.BgLogger().Warn("[ddl] run convert table charset job failed, convert job to rollback, RemoveDDLReorgHandle failed", zap.String("job", job.String()), zap.Error(err1))
+			}
+		}
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+		return false, errors.Trace(err)
+	}
+	// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+	w.reorgCtx.cleanNotifyReorgCancel()
+	return true, nil
+}
+
+// convertTableCharsetRecords checks the rows of the table, a partitioned table is checked partition by partition.
+func (w *worker) convertTableCharsetRecords(t table.Table, reorgInfo *reorgInfo) error {
+	tbl, ok := t.(table.PartitionedTable)
+	if !ok {
+		return w.writePhysicalTableRecord(t.(table.PhysicalTable), typeConvertCharsetWorker, nil, nil, nil, reorgInfo)
+	}
+	var err error
+	var finish bool
+	for !finish {
+		p := tbl.GetPartition(reorgInfo.PhysicalTableID)
+		if p == nil {
+			return errCancelledDDLJob.GenWithStack("Can not find partition id %d for table %d", reorgInfo.PhysicalTableID, t.Meta().ID)
+		}
+		err = w.writePhysicalTableRecord(p, typeConvertCharsetWorker, nil, nil, nil, reorgInfo)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		finish, err = w.updateReorgInfo(tbl, reorgInfo)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// rollbackModifyTableCharsetAndCollate restores the charset and collation of the converting columns.
+func rollbackModifyTableCharsetAndCollate(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, convertingCols []*convertingColumn) (ver int64, _ error) {
+	setColumnsCharsetAndCollate(tblInfo, convertingCols, func(convCol *convertingColumn) (string, string) {
+		return convCol.Charset, convCol.Collate
+	})
+	ver, err := updateVersionAndTableInfo(t, job, tblInfo, true)
 	if err != nil {
 		return ver, errors.Trace(err)
 	}
-	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
 	return ver, nil
 }
 
+// convertCharsetWorker checks the strings of the converting columns can be represented in the target charset.
+// The non-binary strings are stored in UTF-8 whatever the charset is, so the rows are only checked, not rewritten.
+type convertCharsetWorker struct {
+	*backfillWorker
+	converters    map[int64]*encoding.Converter
+	colTypes      map[int64]*types.FieldType
+	metricCounter prometheus.Counter
+
+	// rowMap is used to reduce memory allocation.
+	rowMap map[int64]types.Datum
+}
+
+func newConvertCharsetWorker(sessCtx sessionctx.Context, worker *worker, id int, t table.PhysicalTable, converters map[int64]*encoding.Converter) *convertCharsetWorker {
+	colTypes := make(map[int64]*types.FieldType, len(converters))
+	for _, col := range t.WritableCols() {
+		if _, ok := converters[col.ID]; ok {
+			colTypes[col.ID] = &col.FieldType
+		}
+	}
+	return &convertCharsetWorker{
+		backfillWorker: newBackfillWorker(sessCtx, worker, id, t),
+		converters:     converters,
+		colTypes:       colTypes,
+		metricCounter:  metrics.BackfillTotalCounter.WithLabelValues("convert_charset_speed"),
+		rowMap:         make(map[int64]types.Datum, len(colTypes)),
+	}
+}
+
+func (w *convertCharsetWorker) AddMetricInfo(cnt float64) {
+	w.metricCounter.Add(cnt)
+}
+
+func (w *convertCharsetWorker) checkRow(rawRow []byte) error {
+	for id := range w.rowMap {
+		delete(w.rowMap, id)
+	}
+	var err error
+	if rowcodec.IsNewFormat(rawRow) {
+		_, err = tablecodec.DecodeRowWithMapNew(rawRow, w.colTypes, time.UTC, w.rowMap)
+	} else {
+		_, err = tablecodec.DecodeRowWithMap(rawRow, w.colTypes, time.UTC, w.rowMap)
+	}
+	if err != nil {
+		return errors.Trace(errCantDecodeRecord.GenWithStackByArgs("column", err))
+	}
+	for id, val := range w.rowMap {
+		if val.IsNull() {
+			continue
+		}
+		if _, _, err = w.converters[id].Convert(nil, val.GetBytes(), encoding.InvalidCharError); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// BackfillDataInTxn checks the rows in the handle range in a transaction.
+func (w *convertCharsetWorker) BackfillDataInTxn(handleRange reorgBackfillTask) (taskCtx backfillTaskContext, errInTxn error) {
+	oprStartTime := time.Now()
+	errInTxn = kv.RunInNewTxn(context.Background(), w.sessCtx.GetStore(), true, func(ctx context.Context, txn kv.Transaction) error {
+		taskCtx.addedCount = 0
+		taskCtx.scanCount = 0
+		txn.SetOption(tikvstore.Priority, w.priority)
+
+		var lastAccessedHandle kv.Key
+		taskDone := false
+		err := iterateSnapshotRows(w.sessCtx.GetStore(), w.priority, w.table, txn.StartTS(), handleRange.startKey, handleRange.endKey,
+			func(handle kv.Handle, recordKey kv.Key, rawRow []byte) (bool, error) {
+				taskDone = recordKey.Cmp(handleRange.endKey) > 0
+				if taskDone || taskCtx.scanCount >= w.batchCnt {
+					return false, nil
+				}
+				if err1 := w.checkRow(rawRow); err1 != nil {
+					return false, errors.Trace(err1)
+				}
+				taskCtx.scanCount++
+				taskCtx.addedCount++
+				lastAccessedHandle = recordKey
+				if recordKey.Cmp(handleRange.endKey) == 0 {
+					taskDone = true
+					return false, nil
+				}
+				return true, nil
+			})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if taskCtx.scanCount == 0 {
+			taskDone = true
+		}
+		taskCtx.done = taskDone
+		taskCtx.nextKey = handleRange.endKey.Next()
+		if !taskDone {
+			taskCtx.nextKey = lastAccessedHandle.Next()
+		}
+		return nil
+	})
+	logSlowOperations(time.Since(oprStartTime), "ConvertCharsetBackfillDataInTxn", 3000)
+
+	return
+}
+
 func (w *worker) onSetTableFlashReplica(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	var replicaInfo ast.TiFlashReplicaSpec
 	if err := job.DecodeArgs(&replicaInfo); err != nil {
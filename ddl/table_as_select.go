@@ -0,0 +1,183 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"context"
+
+	"github.com/cznic/mathutil"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/ddl/util"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"go.uber.org/zap"
+)
+
+// The table of `CREATE TABLE ... SELECT` is created in the write-reorganization state, which is invisible to
+// the users, then the rows of the select are inserted by the DDL worker and the table becomes public in the
+// same job. If the rows can't be inserted, the job is rolled back, the table is dropped and its data is
+// cleaned up by the delete range, so the statement never leaves a half-filled table behind.
+
+func (w *worker) onCreateTableAsSelect(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	schemaID := job.SchemaID
+	tbInfo := &model.TableInfo{}
+	var insertSQL string
+	if err := job.DecodeArgs(tbInfo, &insertSQL); err != nil {
+		// Invalid arguments, cancel this job.
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	if job.IsRollingback() {
+		return rollbackCreateTableAsSelect(t, job)
+	}
+
+	switch job.SchemaState {
+	case model.StateNone:
+		err := checkTableNotExists(d, t, schemaID, tbInfo.Name.L)
+		if err != nil {
+			if infoschema.ErrDatabaseNotExists.Equal(err) || infoschema.ErrTableExists.Equal(err) {
+				job.State = model.JobStateCancelled
+			}
+			return ver, errors.Trace(err)
+		}
+		ver, err = updateSchemaVersion(t, job)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		// none -> write reorganization
+		tbInfo.State = model.StateWriteReorganization
+		tbInfo.UpdateTS = t.StartTS
+		if err = checkTableInfoValid(tbInfo); err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		// The auto IDs are rebased before the rows are inserted.
+		if err = t.CreateTableAndSetAutoID(schemaID, tbInfo, mathutil.MaxInt64(tbInfo.AutoIncID-1, 0), mathutil.MaxInt64(tbInfo.AutoRandID-1, 0)); err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.SchemaState = model.StateWriteReorganization
+		// Notify the statistics before the rows are inserted, so the inserted rows are counted.
+		asyncNotifyEvent(d, &util.Event{Tp: model.ActionCreateTable, TableInfo: tbInfo})
+		return ver, nil
+	case model.StateWriteReorganization:
+		tblInfo, err := checkTableExistAndCancelNonExistJob(t, job, schemaID)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		rowCount, err := w.insertTableAsSelect(job, tblInfo, insertSQL)
+		if err != nil {
+			logutil.BgLogger().Warn("[ddl] insert the rows of the table created by select failed, convert job to rollback",
+				zap.String("job", job.String()), zap.Error(err))
+			job.State = model.JobStateRollingback
+			return ver, errors.Trace(err)
+		}
+
+		// write reorganization -> public
+		tblInfo.State = model.StatePublic
+		tblInfo.UpdateTS = t.StartTS
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		// Finish this job.
+		job.SetRowCount(rowCount)
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+		return ver, nil
+	default:
+		return ver, ErrInvalidDDLState.GenWithStackByArgs("table", job.SchemaState)
+	}
+}
+
+// insertTableAsSelect inserts the rows of the select into the table which isn't public yet, it returns the
+// number of the inserted rows. Only the job writes the table before it's public, so if the table isn't empty,
+// the rows have been inserted before the job is retried, e.g. the job failed to update after the insert.
+func (w *worker) insertTableAsSelect(job *model.Job, tblInfo *model.TableInfo, insertSQL string) (int64, error) {
+	sctx, err := w.sessPool.get()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	defer w.sessPool.put(sctx)
+
+	ctx := context.Background()
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(ctx, "SELECT COUNT(*) FROM %n.%n", job.SchemaName, tblInfo.Name.O)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	rows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if cnt := rows[0].GetInt64(0); cnt > 0 {
+		logutil.BgLogger().Info("[ddl] the rows of the table created by select have been inserted",
+			zap.Int64("jobID", job.ID), zap.Int64("rowCount", cnt))
+		return cnt, nil
+	}
+
+	// The select is evaluated under the SQL mode of the statement.
+	sessVars := sctx.GetSessionVars()
+	sqlMode := sessVars.SQLMode
+	sessVars.SQLMode = job.ReorgMeta.SQLMode
+	defer func() {
+		sessVars.SQLMode = sqlMode
+	}()
+	if _, err = sctx.(sqlexec.SQLExecutor).ExecuteInternal(ctx, insertSQL); err != nil {
+		return 0, err
+	}
+	// The warnings, e.g. the duplicated rows ignored by `IGNORE`, are reported to the client like the ones of adding indices.
+	for _, warn := range sessVars.StmtCtx.GetWarnings() {
+		if tErr, ok := errors.Cause(warn.Err).(*terror.Error); ok {
+			if _, ok := job.ReorgMeta.Warnings[tErr.ID()]; !ok {
+				job.ReorgMeta.Warnings[tErr.ID()] = tErr
+			}
+			job.ReorgMeta.WarningsCount[tErr.ID()]++
+		}
+	}
+	return int64(sessVars.StmtCtx.AffectedRows()), nil
+}
+
+// rollbackCreateTableAsSelect drops the table which isn't public, its data is cleaned up by the delete range.
+func rollbackCreateTableAsSelect(t *meta.Meta, job *model.Job) (ver int64, err error) {
+	tblInfo, err := checkTableExistAndCancelNonExistJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	ver, err = updateSchemaVersion(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if err = t.DropTableOrView(job.SchemaID, job.TableID, true); err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+	// Refactor the job args to add the table data into delete range table.
+	job.Args = []interface{}{tablecodec.EncodeTablePrefix(job.TableID), getPartitionIDs(tblInfo)}
+	return ver, nil
+}
+
+// rollingbackCreateTableAsSelect handles the job of `CREATE TABLE ... SELECT` which is cancelled by the users.
+func rollingbackCreateTableAsSelect(job *model.Job) (ver int64, err error) {
+	if job.SchemaState == model.StateNone {
+		// The job hasn't been handled and we cancel it directly.
+		job.State = model.JobStateCancelled
+		return ver, errCancelledDDLJob
+	}
+	job.State = model.JobStateRollingback
+	return ver, errCancelledDDLJob
+}
@@ -0,0 +1,91 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/meta/autoid"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/table/temptable"
+	"github.com/pingcap/tidb/util/fulltext"
+)
+
+// createLocalTemporaryTable creates a local temporary table in the session.
+// A local temporary table is only visible to the session, so it's created without a DDL job,
+// and its data is kept in the session instead of the storage, which is spilled to the disk when it's too large.
+func (d *ddl) createLocalTemporaryTable(ctx sessionctx.Context, schema *model.DBInfo, tbInfo *model.TableInfo, onExist OnExist) error {
+	sessVars := ctx.GetSessionVars()
+	localTables, ok := sessVars.LocalTemporaryTables.(*infoschema.LocalTemporaryTables)
+	if !ok {
+		localTables = infoschema.NewLocalTemporaryTables()
+	}
+	// A local temporary table can have the same name as a normal table, it shadows the normal table.
+	if _, ok := localTables.TableByName(schema.Name, tbInfo.Name); ok {
+		err := infoschema.ErrTableExists.GenWithStackByArgs(ast.Ident{Schema: schema.Name, Name: tbInfo.Name})
+		if onExist == OnExistIgnore {
+			sessVars.StmtCtx.AppendNote(err)
+			return nil
+		}
+		return err
+	}
+	if err := checkTemporaryTableInfo(tbInfo); err != nil {
+		return err
+	}
+	if err := d.assignTableID(tbInfo); err != nil {
+		return errors.Trace(err)
+	}
+	if err := checkTableInfoValidExtra(tbInfo); err != nil {
+		return err
+	}
+	tbInfo.State = model.StatePublic
+	tbl, err := tables.TableFromMeta(autoid.NewAllocatorsFromTempTblInfo(tbInfo), tbInfo)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err = localTables.AddTable(schema, tbl); err != nil {
+		return err
+	}
+	sessVars.LocalTemporaryTables = localTables
+	if sessVars.TemporaryTableData == nil {
+		sessVars.TemporaryTableData = temptable.NewStorage(sessVars.TmpTableMemQuota)
+	}
+	return nil
+}
+
+// checkTemporaryTableInfo checks the options which can't be used on temporary tables.
+func checkTemporaryTableInfo(tbInfo *model.TableInfo) error {
+	switch {
+	case tbInfo.Partition != nil:
+		return ErrPartitionNoTemporary
+	case tbInfo.ContainsAutoRandomBits():
+		return ErrOptOnTemporaryTable.GenWithStackByArgs("auto_random")
+	case tbInfo.ShardRowIDBits > 0:
+		return ErrOptOnTemporaryTable.GenWithStackByArgs("shard_row_id_bits")
+	case tbInfo.PreSplitRegions > 0:
+		return ErrOptOnTemporaryTable.GenWithStackByArgs("pre_split_regions")
+	case tbInfo.Sequence != nil || tbInfo.View != nil:
+		return ErrOptOnTemporaryTable.GenWithStackByArgs("create view or sequence")
+	}
+	// The FULLTEXT index is read from the storage, which doesn't keep the data of temporary tables.
+	for _, idx := range tbInfo.Indices {
+		if fulltext.IsIndex(idx) {
+			return ErrFulltextNoTemporary
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
+)
+
+// DefaultSubscribeInterval is the default interval of a subscriber checking the new DDL events.
+const DefaultSubscribeInterval = 500 * time.Millisecond
+
+// GetDDLEvents gets at most limit events of the committed DDL jobs after the startVersion in the schema version order.
+func GetDDLEvents(store kv.Storage, startVersion int64, limit int) ([]*meta.DDLEvent, error) {
+	var events []*meta.DDLEvent
+	err := kv.RunInNewTxn(context.Background(), store, false, func(ctx context.Context, txn kv.Transaction) error {
+		var err error
+		events, err = meta.NewMeta(txn).GetDDLEvents(startVersion, limit)
+		return err
+	})
+	return events, errors.Trace(err)
+}
+
+// Subscriber subscribes the events of the committed DDL jobs, the events are received in the schema version order.
+// It isn't thread-safe.
+type Subscriber struct {
+	store    kv.Storage
+	version  int64
+	interval time.Duration
+}
+
+// NewSubscriber creates a subscriber which receives the DDL events after the startVersion.
+func NewSubscriber(store kv.Storage, startVersion int64) *Subscriber {
+	return &Subscriber{
+		store:    store,
+		version:  startVersion,
+		interval: DefaultSubscribeInterval,
+	}
+}
+
+// SetInterval sets the interval of checking the new DDL events.
+func (s *Subscriber) SetInterval(interval time.Duration) {
+	s.interval = interval
+}
+
+// Version returns the schema version of the last received event.
+func (s *Subscriber) Version() int64 {
+	return s.version
+}
+
+// Next blocks until there are new DDL events or the ctx is done, it returns at most limit events.
+func (s *Subscriber) Next(ctx context.Context, limit int) ([]*meta.DDLEvent, error) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		events, err := GetDDLEvents(s.store, s.version, limit)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(events) > 0 {
+			s.version = events[len(events)-1].SchemaVersion
+			return events, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
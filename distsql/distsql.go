@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/statistics"
+	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tipb/go-tipb"
@@ -73,8 +74,14 @@ func Select(ctx context.Context, sctx sessionctx.Context, kvReq *kv.Request, fie
 	if !sctx.GetSessionVars().EnableStreaming {
 		kvReq.Streaming = false
 	}
-	enabledRateLimitAction := sctx.GetSessionVars().EnabledRateLimitAction
-	resp := sctx.GetClient().Send(ctx, kvReq, sctx.GetSessionVars().KVVars, sctx.GetSessionVars().StmtCtx.MemTracker, enabledRateLimitAction)
+	var resp kv.Response
+	if isLocalTemporaryTableRequest(sctx, kvReq) {
+		// The data of local temporary tables is kept in the session, it's read by UnionScan instead.
+		resp = emptyResponse{}
+	} else {
+		enabledRateLimitAction := sctx.GetSessionVars().EnabledRateLimitAction
+		resp = sctx.GetClient().Send(ctx, kvReq, sctx.GetSessionVars().KVVars, sctx.GetSessionVars().StmtCtx.MemTracker, enabledRateLimitAction)
+	}
 	if resp == nil {
 		err := errors.New("client returns nil response")
 		return nil, err
@@ -230,3 +237,23 @@ func init() {
 		systemEndian = tipb.Endian_LittleEndian
 	}
 }
+
+func isLocalTemporaryTableRequest(sctx sessionctx.Context, kvReq *kv.Request) bool {
+	if len(kvReq.KeyRanges) == 0 || len(kvReq.KeyRanges[0].StartKey) < tablecodec.TableSplitKeyLen {
+		return false
+	}
+	return sctx.GetSessionVars().IsLocalTemporaryTable(tablecodec.DecodeTableID(kvReq.KeyRanges[0].StartKey))
+}
+
+// emptyResponse is a kv.Response which returns nothing.
+type emptyResponse struct{}
+
+// Next implements kv.Response interface.
+func (emptyResponse) Next(context.Context) (kv.ResultSubset, error) {
+	return nil, nil
+}
+
+// Close implements kv.Response interface.
+func (emptyResponse) Close() error {
+	return nil
+}
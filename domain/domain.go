@@ -40,22 +40,27 @@ import (
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/infoschema/perfschema"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/masking"
 	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/owner"
 	"github.com/pingcap/tidb/privilege/privileges"
+	"github.com/pingcap/tidb/resourcegroup"
+	"github.com/pingcap/tidb/rowpolicy"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/statistics/handle"
 	"github.com/pingcap/tidb/store/tikv"
 	"github.com/pingcap/tidb/store/tikv/oracle"
 	"github.com/pingcap/tidb/telemetry"
+	"github.com/pingcap/tidb/ttl"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/dbterror"
 	"github.com/pingcap/tidb/util/domainutil"
 	"github.com/pingcap/tidb/util/expensivequery"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/stmtsummary"
 	"go.etcd.io/etcd/clientv3"
 	"go.etcd.io/etcd/clientv3/concurrency"
 	"go.uber.org/zap"
@@ -1078,6 +1083,275 @@ func (do *Domain) TelemetryRotateSubWindowLoop(ctx sessionctx.Context) {
 	}()
 }
 
+// TTLJobLoop creates a goroutine that runs the TTL jobs regularly on the TTL owner.
+func (do *Domain) TTLJobLoop(ctx sessionctx.Context) {
+	ctx.GetSessionVars().InRestrictedSQL = true
+	do.wg.Add(1)
+	go func() {
+		defer func() {
+			do.wg.Done()
+			logutil.BgLogger().Info("TTLJobLoop exited.")
+			util.Recover(metrics.LabelDomain, "TTLJobLoop", nil, false)
+		}()
+		owner := do.newOwnerManager(ttl.Prompt, ttl.OwnerKey)
+		// The running job is interrupted when the domain exits.
+		jobCtx, cancel := context.WithCancel(context.Background())
+		go func() {
+			<-do.exit
+			cancel()
+		}()
+		manager := ttl.NewJobManager(ctx)
+		for {
+			select {
+			case <-do.exit:
+				owner.Cancel()
+				return
+			case <-time.After(ttl.CheckInterval):
+				if !owner.IsOwner() {
+					continue
+				}
+				err := manager.RunJobs(jobCtx, do.InfoSchema())
+				if err != nil {
+					logutil.BgLogger().Warn("TTLJobLoop run TTL jobs failed", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// ResourceGroupLoop loads the resource groups and creates a goroutine that reloads them regularly.
+func (do *Domain) ResourceGroupLoop(ctx sessionctx.Context) error {
+	ctx.GetSessionVars().InRestrictedSQL = true
+	manager := resourcegroup.GlobalManager()
+	if err := manager.Reload(ctx); err != nil {
+		return err
+	}
+	do.wg.Add(1)
+	go func() {
+		defer func() {
+			do.wg.Done()
+			logutil.BgLogger().Info("resourceGroupLoop exited.")
+			util.Recover(metrics.LabelDomain, "resourceGroupLoop", nil, false)
+		}()
+		for {
+			select {
+			case <-do.exit:
+				return
+			case <-time.After(resourcegroup.LoadInterval):
+				if err := manager.Reload(ctx); err != nil {
+					logutil.BgLogger().Warn("resourceGroupLoop reload resource groups failed", zap.Error(err))
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// RowPolicyLoop loads the row policies and creates a goroutine that reloads them regularly.
+func (do *Domain) RowPolicyLoop(ctx sessionctx.Context) error {
+	ctx.GetSessionVars().InRestrictedSQL = true
+	manager := rowpolicy.GlobalManager()
+	if err := manager.Reload(ctx); err != nil {
+		return err
+	}
+	do.wg.Add(1)
+	go func() {
+		defer func() {
+			do.wg.Done()
+			logutil.BgLogger().Info("rowPolicyLoop exited.")
+			util.Recover(metrics.LabelDomain, "rowPolicyLoop", nil, false)
+		}()
+		for {
+			select {
+			case <-do.exit:
+				return
+			case <-time.After(rowpolicy.LoadInterval):
+				if err := manager.Reload(ctx); err != nil {
+					logutil.BgLogger().Warn("rowPolicyLoop reload row policies failed", zap.Error(err))
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// MaskingRuleLoop loads the masking rules and creates a goroutine that reloads them regularly.
+func (do *Domain) MaskingRuleLoop(ctx sessionctx.Context) error {
+	ctx.GetSessionVars().InRestrictedSQL = true
+	manager := masking.GlobalManager()
+	if err := manager.Reload(ctx); err != nil {
+		return err
+	}
+	do.wg.Add(1)
+	go func() {
+		defer func() {
+			do.wg.Done()
+			logutil.BgLogger().Info("maskingRuleLoop exited.")
+			util.Recover(metrics.LabelDomain, "maskingRuleLoop", nil, false)
+		}()
+		for {
+			select {
+			case <-do.exit:
+				return
+			case <-time.After(masking.LoadInterval):
+				if err := manager.Reload(ctx); err != nil {
+					logutil.BgLogger().Warn("maskingRuleLoop reload masking rules failed", zap.Error(err))
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// PreparedXAKeepAliveLoop creates a goroutine that extends the TTL of the locks of the prepared XA transactions
+// regularly, so they survive until being committed or rolled back, even if the tidb-server preparing them exits.
+func (do *Domain) PreparedXAKeepAliveLoop(ctx sessionctx.Context) {
+	ctx.GetSessionVars().InRestrictedSQL = true
+	store, ok := do.store.(kv.PreparedTxnStorage)
+	if !ok {
+		return
+	}
+	do.wg.Add(1)
+	go func() {
+		defer func() {
+			do.wg.Done()
+			logutil.BgLogger().Info("preparedXAKeepAliveLoop exited.")
+			util.Recover(metrics.LabelDomain, "preparedXAKeepAliveLoop", nil, false)
+		}()
+		exec := ctx.(sqlexec.RestrictedSQLExecutor)
+		for {
+			select {
+			case <-do.exit:
+				return
+			case <-time.After(time.Duration(atomic.LoadUint64(&tikv.ManagedLockTTL)) * time.Millisecond / 2):
+				stmt, err := exec.ParseWithParams(context.Background(), "select start_ts, primary_key from mysql.xa_transactions where primary_key is not null")
+				if err != nil {
+					logutil.BgLogger().Warn("preparedXAKeepAliveLoop load prepared XA transactions failed", zap.Error(err))
+					continue
+				}
+				rows, _, err := exec.ExecRestrictedStmt(context.Background(), stmt)
+				if err != nil {
+					logutil.BgLogger().Warn("preparedXAKeepAliveLoop load prepared XA transactions failed", zap.Error(err))
+					continue
+				}
+				for _, row := range rows {
+					startTS, primary := row.GetUint64(0), row.GetBytes(1)
+					if err = store.KeepPreparedTxnAlive(context.Background(), primary, startTS); err != nil {
+						logutil.BgLogger().Warn("preparedXAKeepAliveLoop keep prepared XA transaction alive failed",
+							zap.Uint64("startTS", startTS), zap.Error(err))
+					}
+				}
+			}
+		}
+	}()
+}
+
+// readOnlyLoadInterval is the interval to load the read-only state from the global variables.
+const readOnlyLoadInterval = time.Second
+
+// ReadOnlyLoop creates a goroutine that syncs the read-only state of the tidb-server from the global variables
+// read_only, super_read_only and tidb_read_only_grace_period regularly.
+func (do *Domain) ReadOnlyLoop(ctx sessionctx.Context) {
+	ctx.GetSessionVars().InRestrictedSQL = true
+	do.wg.Add(1)
+	go func() {
+		defer func() {
+			do.wg.Done()
+			logutil.BgLogger().Info("readOnlyLoop exited.")
+			util.Recover(metrics.LabelDomain, "readOnlyLoop", nil, false)
+		}()
+		exec := ctx.(sqlexec.RestrictedSQLExecutor)
+		// Only the changes of the global variables are applied, so the read-only state set by this tidb-server
+		// isn't overwritten by the stale values.
+		var loaded map[string]string
+		for {
+			select {
+			case <-do.exit:
+				return
+			case <-time.After(readOnlyLoadInterval):
+				stmt, err := exec.ParseWithParams(context.Background(), "select variable_name, variable_value from mysql.global_variables where variable_name in (%?, %?, %?)",
+					variable.ServerReadOnly, variable.SuperReadOnly, variable.TiDBReadOnlyGracePeriod)
+				if err != nil {
+					logutil.BgLogger().Warn("readOnlyLoop load read-only state failed", zap.Error(err))
+					continue
+				}
+				rows, _, err := exec.ExecRestrictedStmt(context.Background(), stmt)
+				if err != nil {
+					logutil.BgLogger().Warn("readOnlyLoop load read-only state failed", zap.Error(err))
+					continue
+				}
+				vals := make(map[string]string, len(rows))
+				for _, row := range rows {
+					vals[row.GetString(0)] = row.GetString(1)
+				}
+				if loaded == nil || vals[variable.TiDBReadOnlyGracePeriod] != loaded[variable.TiDBReadOnlyGracePeriod] {
+					gracePeriod, err := strconv.ParseInt(vals[variable.TiDBReadOnlyGracePeriod], 10, 64)
+					if err == nil {
+						variable.SetReadOnlyGracePeriod(time.Duration(gracePeriod) * time.Second)
+					}
+				}
+				if loaded != nil && (vals[variable.ServerReadOnly] != loaded[variable.ServerReadOnly] || vals[variable.SuperReadOnly] != loaded[variable.SuperReadOnly]) {
+					variable.SetReadOnly(variable.TiDBOptOn(vals[variable.ServerReadOnly]), variable.TiDBOptOn(vals[variable.SuperReadOnly]))
+				}
+				loaded = vals
+			}
+		}
+	}()
+}
+
+// StmtSummaryPersistLoop loads the history of statement summary persisted before the tidb-server restarts, and
+// creates a goroutine that persists the ended history to mysql.statements_summary_history regularly.
+func (do *Domain) StmtSummaryPersistLoop(ctx sessionctx.Context) {
+	cfg := config.GetGlobalConfig().StmtSummary
+	if cfg.PersistInterval <= 0 {
+		return
+	}
+	ctx.GetSessionVars().InRestrictedSQL = true
+	exec := ctx.(sqlexec.RestrictedSQLExecutor)
+	instance, err := stmtSummaryInstance()
+	if err == nil {
+		err = stmtsummary.LoadPersistedHistory(context.Background(), exec, instance)
+	}
+	if err != nil {
+		logutil.BgLogger().Warn("stmtSummaryPersistLoop load persisted history failed", zap.Error(err))
+	}
+	do.wg.Add(1)
+	go func() {
+		defer func() {
+			do.wg.Done()
+			logutil.BgLogger().Info("stmtSummaryPersistLoop exited.")
+			util.Recover(metrics.LabelDomain, "stmtSummaryPersistLoop", nil, false)
+		}()
+		for {
+			select {
+			case <-do.exit:
+				return
+			case <-time.After(time.Duration(cfg.PersistInterval) * time.Second):
+				instance, err := stmtSummaryInstance()
+				if err == nil {
+					err = stmtsummary.PersistHistory(context.Background(), exec, instance)
+				}
+				if err != nil {
+					logutil.BgLogger().Warn("stmtSummaryPersistLoop persist history failed", zap.Error(err))
+				}
+				if err = stmtsummary.DeleteExpiredHistory(context.Background(), exec, cfg.PersistMaxDays); err != nil {
+					logutil.BgLogger().Warn("stmtSummaryPersistLoop delete expired history failed", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// stmtSummaryInstance returns the instance of the persisted history, which is the same as the instance of
+// cluster_statements_summary_history.
+func stmtSummaryInstance() (string, error) {
+	serverInfo, err := infosync.GetServerInfo()
+	if err != nil {
+		return "", err
+	}
+	return serverInfo.IP + ":" + strconv.FormatUint(uint64(serverInfo.StatusPort), 10), nil
+}
+
 // StatsHandle returns the statistic handle.
 func (do *Domain) StatsHandle() *handle.Handle {
 	return (*handle.Handle)(atomic.LoadPointer(&do.statsHandle))
@@ -1121,6 +1395,9 @@ func (do *Domain) UpdateTableStatsLoop(ctx sessionctx.Context) error {
 	}
 	atomic.StorePointer(&do.statsHandle, unsafe.Pointer(statsHandle))
 	do.ddl.RegisterStatsHandle(statsHandle)
+	if err := statsHandle.MarkInterruptedAnalyzeJobs(); err != nil {
+		logutil.BgLogger().Warn("mark the interrupted analyze jobs failed", zap.Error(err))
+	}
 	// Negative stats lease indicates that it is in test, it does not need update.
 	if do.statsLease >= 0 {
 		do.wg.Add(1)
@@ -1266,6 +1543,7 @@ func (do *Domain) updateStatsWorker(ctx sessionctx.Context, owner owner.Manager)
 				logutil.BgLogger().Debug("dump stats delta failed", zap.Error(err))
 			}
 			statsHandle.UpdateErrorRate(do.InfoSchema())
+			statsHandle.UpdateStatsHealthyMetrics(do.InfoSchema())
 		case <-loadFeedbackTicker.C:
 			statsHandle.UpdateStatsByLocalFeedback(do.InfoSchema())
 			if !owner.IsOwner() {
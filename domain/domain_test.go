@@ -42,6 +42,7 @@ import (
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tidb/util/testleak"
+	"github.com/pingcap/tidb/util/txninfo"
 	dto "github.com/prometheus/client_model/go"
 	"go.etcd.io/etcd/integration"
 )
@@ -262,6 +263,10 @@ func (msm *mockSessionManager) Kill(cid uint64, query bool) {}
 
 func (msm *mockSessionManager) KillAllConnections() {}
 
+func (msm *mockSessionManager) ShowTxnList() []*txninfo.TxnInfo {
+	return nil
+}
+
 func (msm *mockSessionManager) UpdateTLSConfig(cfg *tls.Config) {}
 
 func (msm *mockSessionManager) ServerID() uint64 {
@@ -0,0 +1,365 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package embedded runs a TiDB-compatible SQL engine inside the current process.
+//
+// The engine is backed by the mock storages used by TiDB's own tests (unistore
+// by default, or mocktikv), so no PD or TiKV cluster is required. It is meant
+// for tools that want to test against TiDB semantics without a real cluster:
+//
+//	db, err := embedded.Open()
+//	if err != nil {
+//		return err
+//	}
+//	defer db.Close()
+//	if err := db.Exec(ctx, "create table test.t (a int primary key)"); err != nil {
+//		return err
+//	}
+//	res, err := db.Query(ctx, "select a from test.t")
+//
+// The types in this package are the supported API for embedding; everything
+// reachable through them is considered stable across patch releases. The
+// internal packages they wrap are not.
+package embedded
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// StoreType is the storage engine backing an embedded instance.
+type StoreType = mockstore.StoreType
+
+const (
+	// MockTiKV is the storage based on goleveldb.
+	MockTiKV = mockstore.MockTiKV
+	// Unistore is the storage based on unistore, it is the default.
+	Unistore = mockstore.EmbedUnistore
+)
+
+// ErrClosed is returned when using a DB or Session that has been closed.
+var ErrClosed = errors.New("embedded: use of closed instance")
+
+// Hooks are called at the points of the instance lifecycle. All of them are optional.
+type Hooks struct {
+	// AfterBootstrap is called once the store is bootstrapped, before Open returns.
+	// Returning an error closes the instance and makes Open fail.
+	AfterBootstrap func(ctx context.Context, db *DB) error
+	// AfterSessionCreate is called for every session created by NewSession.
+	AfterSessionCreate func(ctx context.Context, se *Session) error
+	// BeforeClose is called once before the instance releases its resources.
+	BeforeClose func(db *DB)
+}
+
+type options struct {
+	storeType StoreType
+	path      string
+	hooks     Hooks
+}
+
+// Option configures an embedded instance.
+type Option func(*options)
+
+// WithStoreType chooses the storage engine.
+func WithStoreType(tp StoreType) Option {
+	return func(o *options) {
+		o.storeType = tp
+	}
+}
+
+// WithPath persists data under path. An empty path, the default, keeps all data in memory.
+func WithPath(path string) Option {
+	return func(o *options) {
+		o.path = path
+	}
+}
+
+// WithHooks registers lifecycle hooks.
+func WithHooks(hooks Hooks) Option {
+	return func(o *options) {
+		o.hooks = hooks
+	}
+}
+
+// DB is an embedded TiDB instance. It is safe for concurrent use.
+type DB struct {
+	store kv.Storage
+	dom   *domain.Domain
+	hooks Hooks
+
+	mu struct {
+		sync.Mutex
+		closed bool
+		// se executes the statements of DB.Exec and DB.Query.
+		se *Session
+	}
+}
+
+// Open creates the storage, bootstraps it and returns the instance.
+func Open(opts ...Option) (*DB, error) {
+	o := options{storeType: Unistore}
+	for _, f := range opts {
+		f(&o)
+	}
+	store, err := mockstore.NewMockStore(mockstore.WithStoreType(o.storeType), mockstore.WithPath(o.path))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	dom, err := session.BootstrapSession(store)
+	if err != nil {
+		terror.Log(store.Close())
+		return nil, errors.Trace(err)
+	}
+	db := &DB{store: store, dom: dom, hooks: o.hooks}
+	if db.hooks.AfterBootstrap != nil {
+		if err := db.hooks.AfterBootstrap(context.Background(), db); err != nil {
+			terror.Log(db.close())
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// Storage returns the underlying storage, for callers that need the internal APIs.
+func (db *DB) Storage() kv.Storage {
+	return db.store
+}
+
+// NewSession creates a session. Sessions are not safe for concurrent use and must be closed.
+func (db *DB) NewSession(ctx context.Context) (*Session, error) {
+	db.mu.Lock()
+	closed := db.mu.closed
+	db.mu.Unlock()
+	if closed {
+		return nil, ErrClosed
+	}
+	se, err := session.CreateSession(db.store)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	s := &Session{se: se}
+	if db.hooks.AfterSessionCreate != nil {
+		if err := db.hooks.AfterSessionCreate(ctx, s); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Exec executes sql, which may contain several statements, on a shared session and discards the results.
+func (db *DB) Exec(ctx context.Context, sql string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	se, err := db.sharedSessionLocked()
+	if err != nil {
+		return err
+	}
+	_, err = se.Exec(ctx, sql)
+	return err
+}
+
+// Query executes sql on a shared session, see Session.Query for the result.
+func (db *DB) Query(ctx context.Context, sql string) (*Result, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	se, err := db.sharedSessionLocked()
+	if err != nil {
+		return nil, err
+	}
+	return se.Query(ctx, sql)
+}
+
+func (db *DB) sharedSessionLocked() (*Session, error) {
+	if db.mu.closed {
+		return nil, ErrClosed
+	}
+	if db.mu.se == nil {
+		se, err := session.CreateSession(db.store)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		db.mu.se = &Session{se: se}
+	}
+	return db.mu.se, nil
+}
+
+// Close releases the instance. Sessions created by NewSession must be closed before.
+func (db *DB) Close() error {
+	db.mu.Lock()
+	if db.mu.closed {
+		db.mu.Unlock()
+		return nil
+	}
+	db.mu.closed = true
+	db.mu.Unlock()
+	if db.hooks.BeforeClose != nil {
+		db.hooks.BeforeClose(db)
+	}
+	return db.close()
+}
+
+func (db *DB) close() error {
+	if db.mu.se != nil {
+		db.mu.se.Close()
+		db.mu.se = nil
+	}
+	db.dom.Close()
+	return errors.Trace(db.store.Close())
+}
+
+// Session is a connection-like context with its own variables and transaction.
+type Session struct {
+	se session.Session
+}
+
+// Exec executes sql, which may contain several statements, and returns the number
+// of rows affected by the last one.
+func (s *Session) Exec(ctx context.Context, sql string) (uint64, error) {
+	err := s.execute(ctx, sql, func(rs sqlexec.RecordSet, _ bool) error {
+		return drainRecordSet(ctx, rs, nil)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return s.se.AffectedRows(), nil
+}
+
+// Query executes sql, which may contain several statements, and returns the rows
+// of the last statement. The result is nil if the last statement doesn't return rows.
+func (s *Session) Query(ctx context.Context, sql string) (*Result, error) {
+	var result *Result
+	err := s.execute(ctx, sql, func(rs sqlexec.RecordSet, last bool) (err error) {
+		if !last {
+			return drainRecordSet(ctx, rs, nil)
+		}
+		result, err = newResult(ctx, rs)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// execute runs the statements of sql one by one, calling fn on those that return rows.
+func (s *Session) execute(ctx context.Context, sql string, fn func(rs sqlexec.RecordSet, last bool) error) error {
+	stmts, err := s.se.Parse(ctx, sql)
+	if err != nil {
+		return err
+	}
+	for i, stmt := range stmts {
+		rs, err := s.se.ExecuteStmt(ctx, stmt)
+		if err != nil {
+			return err
+		}
+		if rs == nil {
+			continue
+		}
+		err = fn(rs, i == len(stmts)-1)
+		if closeErr := rs.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LastInsertID returns the last inserted auto_increment ID.
+func (s *Session) LastInsertID() uint64 {
+	return s.se.LastInsertID()
+}
+
+// Close releases the session, rolling back its transaction if any.
+func (s *Session) Close() {
+	s.se.Close()
+}
+
+// Result is the fully read result of a query.
+type Result struct {
+	// Columns are the names of the result columns.
+	Columns []string
+	// Rows hold the values of the rows. A value is nil for NULL, int64, uint64
+	// or float64 for numeric types, and a string for everything else, formatted
+	// as the MySQL protocol would.
+	Rows [][]interface{}
+}
+
+func newResult(ctx context.Context, rs sqlexec.RecordSet) (*Result, error) {
+	fields := rs.Fields()
+	result := &Result{Columns: make([]string, 0, len(fields))}
+	for _, f := range fields {
+		result.Columns = append(result.Columns, f.ColumnAsName.O)
+	}
+	err := drainRecordSet(ctx, rs, func(row chunk.Row) error {
+		values := make([]interface{}, row.Len())
+		for i := range values {
+			d := row.GetDatum(i, &fields[i].Column.FieldType)
+			switch d.Kind() {
+			case types.KindNull:
+			case types.KindInt64:
+				values[i] = d.GetInt64()
+			case types.KindUint64:
+				values[i] = d.GetUint64()
+			case types.KindFloat32, types.KindFloat64:
+				values[i] = d.GetFloat64()
+			default:
+				str, err := d.ToString()
+				if err != nil {
+					return err
+				}
+				values[i] = str
+			}
+		}
+		result.Rows = append(result.Rows, values)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// drainRecordSet reads all the rows of rs, calling fn for each of them if it is not nil.
+func drainRecordSet(ctx context.Context, rs sqlexec.RecordSet, fn func(chunk.Row) error) error {
+	req := rs.NewChunk()
+	for {
+		if err := rs.Next(ctx, req); err != nil {
+			return err
+		}
+		if req.NumRows() == 0 {
+			return nil
+		}
+		if fn == nil {
+			continue
+		}
+		iter := chunk.NewIterator4Chunk(req)
+		for row := iter.Begin(); row != iter.End(); row = iter.Next() {
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embedded_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/embedded"
+	"github.com/pingcap/tidb/util/testleak"
+)
+
+func TestT(t *testing.T) {
+	CustomVerboseFlag = true
+	TestingT(t)
+}
+
+var _ = Suite(&testEmbeddedSuite{})
+
+type testEmbeddedSuite struct{}
+
+func (s *testEmbeddedSuite) TestExecAndQuery(c *C) {
+	defer testleak.AfterTest(c)()
+	ctx := context.Background()
+	db, err := embedded.Open(embedded.WithStoreType(embedded.MockTiKV))
+	c.Assert(err, IsNil)
+	defer func() {
+		c.Assert(db.Close(), IsNil)
+	}()
+
+	c.Assert(db.Exec(ctx, "create table test.t (a int primary key, b varchar(10), c double)"), IsNil)
+	c.Assert(db.Exec(ctx, "insert into test.t values (1, 'x', 1.5), (2, null, null)"), IsNil)
+	res, err := db.Query(ctx, "select a, b as bb, c from test.t order by a")
+	c.Assert(err, IsNil)
+	c.Assert(res.Columns, DeepEquals, []string{"a", "bb", "c"})
+	c.Assert(res.Rows, DeepEquals, [][]interface{}{{int64(1), "x", 1.5}, {int64(2), nil, nil}})
+
+	res, err = db.Query(ctx, "set @a = 1")
+	c.Assert(err, IsNil)
+	c.Assert(res, IsNil)
+
+	se, err := db.NewSession(ctx)
+	c.Assert(err, IsNil)
+	defer se.Close()
+	affected, err := se.Exec(ctx, "use test; begin; update t set b = 'y'")
+	c.Assert(err, IsNil)
+	c.Assert(affected, Equals, uint64(2))
+	// The shared session doesn't see the uncommitted update.
+	res, err = db.Query(ctx, "select count(*) from test.t where b = 'y'")
+	c.Assert(err, IsNil)
+	c.Assert(res.Rows, DeepEquals, [][]interface{}{{int64(0)}})
+	_, err = se.Exec(ctx, "commit")
+	c.Assert(err, IsNil)
+	res, err = se.Query(ctx, "select count(*) from t where b = 'y'")
+	c.Assert(err, IsNil)
+	c.Assert(res.Rows, DeepEquals, [][]interface{}{{int64(2)}})
+
+	_, err = se.Query(ctx, "select * from no_such_table")
+	c.Assert(err, NotNil)
+}
+
+func (s *testEmbeddedSuite) TestHooks(c *C) {
+	defer testleak.AfterTest(c)()
+	ctx := context.Background()
+	var events []string
+	db, err := embedded.Open(embedded.WithHooks(embedded.Hooks{
+		AfterBootstrap: func(ctx context.Context, db *embedded.DB) error {
+			events = append(events, "bootstrap")
+			return db.Exec(ctx, "create database app")
+		},
+		AfterSessionCreate: func(ctx context.Context, se *embedded.Session) error {
+			events = append(events, "session")
+			_, err := se.Exec(ctx, "use app")
+			return err
+		},
+		BeforeClose: func(db *embedded.DB) {
+			events = append(events, "close")
+		},
+	}))
+	c.Assert(err, IsNil)
+	se, err := db.NewSession(ctx)
+	c.Assert(err, IsNil)
+	res, err := se.Query(ctx, "select database()")
+	c.Assert(err, IsNil)
+	c.Assert(res.Rows, DeepEquals, [][]interface{}{{"app"}})
+	se.Close()
+	c.Assert(db.Close(), IsNil)
+	c.Assert(db.Close(), IsNil)
+	c.Assert(events, DeepEquals, []string{"bootstrap", "session", "close"})
+
+	_, err = db.NewSession(ctx)
+	c.Assert(err, Equals, embedded.ErrClosed)
+	c.Assert(db.Exec(ctx, "select 1"), Equals, embedded.ErrClosed)
+
+	_, err = embedded.Open(embedded.WithHooks(embedded.Hooks{
+		AfterBootstrap: func(ctx context.Context, db *embedded.DB) error {
+			return errors.New("init failed")
+		},
+	}))
+	c.Assert(err, ErrorMatches, "init failed")
+}
@@ -824,6 +824,8 @@ const (
 	ErrInvalidFieldSize                                      = 3013
 	ErrInvalidArgumentForLogarithm                           = 3020
 	ErrAggregateOrderNonAggQuery                             = 3029
+	ErrGISDifferentSRIDs                                     = 3033
+	ErrGISInvalidData                                        = 3037
 	ErrIncorrectType                                         = 3064
 	ErrFieldInOrderNotSelect                                 = 3065
 	ErrAggregateInOrderNotSelect                             = 3066
@@ -851,6 +853,7 @@ const (
 	ErrInvalidJSONPathArrayCell                              = 3165
 	ErrInvalidEncryptionOption                               = 3184
 	ErrTooLongValueForType                                   = 3505
+	ErrGISUnsupportedArgument                                = 3516
 	ErrPKIndexCantBeInvisible                                = 3522
 	ErrGrantRole                                             = 3523
 	ErrRoleNotGranted                                        = 3530
@@ -878,6 +881,7 @@ const (
 	ErrWindowFunctionIgnoresFrame                            = 3599
 	ErrIllegalPrivilegeLevel                                 = 3619
 	ErrNotHintUpdatable                                      = 3637
+	ErrCredentialsContradictToHistory                        = 3638
 	ErrDataTruncatedFunctionalIndex                          = 3751
 	ErrDataOutOfRangeFunctionalIndex                         = 3752
 	ErrFunctionalIndexOnJSONOrGeometryFunction               = 3753
@@ -1035,6 +1039,15 @@ const (
 	ErrInvalidPlacementSpec               = 8234
 	ErrDDLReorgElementNotExist            = 8235
 	ErrPlacementPolicyCheck               = 8236
+	ErrCannotPauseDDLJob                  = 8237
+	ErrCannotResumeDDLJob                 = 8238
+	ErrPausedDDLJob                       = 8239
+	ErrPlacementPolicyExists              = 8240
+	ErrPlacementPolicyNotExists           = 8241
+	ErrPlacementPolicyInUse               = 8242
+	ErrServerBusy                         = 8243
+	ErrTooManyConnectionsFromHost         = 8244
+	ErrRowPolicyViolation                 = 8245
 
 	// TiKV/PD/TiFlash errors.
 	ErrPDServerTimeout           = 9001
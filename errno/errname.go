@@ -837,6 +837,8 @@ var MySQLErrName = map[uint16]*mysql.ErrMessage{
 	ErrInvalidFieldSize:                                      mysql.Message("Invalid size for column '%s'.", nil),
 	ErrInvalidArgumentForLogarithm:                           mysql.Message("Invalid argument for logarithm", nil),
 	ErrAggregateOrderNonAggQuery:                             mysql.Message("Expression #%d of ORDER BY contains aggregate function and applies to the result of a non-aggregated query", nil),
+	ErrGISDifferentSRIDs:                                     mysql.Message("Binary geometry function %s given two geometries of different srids: %d and %d, which should have been identical.", nil),
+	ErrGISInvalidData:                                        mysql.Message("Invalid GIS data provided to function %s.", nil),
 	ErrIncorrectType:                                         mysql.Message("Incorrect type for argument %s in function %s.", nil),
 	ErrFieldInOrderNotSelect:                                 mysql.Message("Expression #%d of ORDER BY clause is not in SELECT list, references column '%s' which is not in SELECT list; this is incompatible with %s", nil),
 	ErrAggregateInOrderNotSelect:                             mysql.Message("Expression #%d of ORDER BY clause is not in SELECT list, contains aggregate function; this is incompatible with %s", nil),
@@ -854,6 +856,7 @@ var MySQLErrName = map[uint16]*mysql.ErrMessage{
 	ErrInvalidJSONPathArrayCell:                              mysql.Message("A path expression is not a path to a cell in an array.", nil),
 	ErrInvalidEncryptionOption:                               mysql.Message("Invalid encryption option.", nil),
 	ErrTooLongValueForType:                                   mysql.Message("Too long enumeration/set value for column %s.", nil),
+	ErrGISUnsupportedArgument:                                mysql.Message("Calling geometry function %s with unsupported types of arguments.", nil),
 	ErrPKIndexCantBeInvisible:                                mysql.Message("A primary key index cannot be invisible", nil),
 	ErrWindowNoSuchWindow:                                    mysql.Message("Window name '%s' is not defined.", nil),
 	ErrWindowCircularityInWindowGraph:                        mysql.Message("There is a circularity in the window dependency graph.", nil),
@@ -880,6 +883,7 @@ var MySQLErrName = map[uint16]*mysql.ErrMessage{
 	ErrMaxExecTimeExceeded:                                   mysql.Message("Query execution was interrupted, max_execution_time exceeded.", nil),
 	ErrLockAcquireFailAndNoWaitSet:                           mysql.Message("Statement aborted because lock(s) could not be acquired immediately and NOWAIT is set.", nil),
 	ErrNotHintUpdatable:                                      mysql.Message("Variable '%s' cannot be set using SET_VAR hint.", nil),
+	ErrCredentialsContradictToHistory:                        mysql.Message("Cannot use these credentials for '%s@%s' because they contradict the password history policy", nil),
 	ErrDataTruncatedFunctionalIndex:                          mysql.Message("Data truncated for expression index '%s' at row %d", nil),
 	ErrDataOutOfRangeFunctionalIndex:                         mysql.Message("Value is out of range for expression index '%s' at row %d", nil),
 	ErrFunctionalIndexOnJSONOrGeometryFunction:               mysql.Message("Cannot create an expression index on a function that returns a JSON or GEOMETRY value", nil),
@@ -972,6 +976,7 @@ var MySQLErrName = map[uint16]*mysql.ErrMessage{
 	ErrReorgPanic:                 mysql.Message("Reorg worker panic", nil),
 	ErrInvalidDDLState:            mysql.Message("Invalid %s state: %v", nil),
 	ErrCancelledDDLJob:            mysql.Message("Cancelled DDL job", nil),
+	ErrPausedDDLJob:               mysql.Message("Paused DDL job", nil),
 	ErrRepairTable:                mysql.Message("Failed to repair table: %s", nil),
 	ErrLoadPrivilege:              mysql.Message("Load privilege table fail: %s", nil),
 	ErrInvalidPrivilegeType:       mysql.Message("unknown privilege type %s", nil),
@@ -994,6 +999,8 @@ var MySQLErrName = map[uint16]*mysql.ErrMessage{
 	ErrDDLJobNotFound:             mysql.Message("DDL Job:%v not found", nil),
 	ErrCancelFinishedDDLJob:       mysql.Message("This job:%v is finished, so can't be cancelled", nil),
 	ErrCannotCancelDDLJob:         mysql.Message("This job:%v is almost finished, can't be cancelled now", nil),
+	ErrCannotPauseDDLJob:          mysql.Message("This job:%v is %s, can't be paused now", nil),
+	ErrCannotResumeDDLJob:         mysql.Message("This job:%v is %s, only the paused job can be resumed", nil),
 	ErrUnknownAllocatorType:       mysql.Message("Invalid allocator type", nil),
 	ErrAutoRandReadFailed:         mysql.Message("Failed to read auto-random value from storage engine", nil),
 	ErrInvalidIncrementAndOffset:  mysql.Message("Invalid auto_increment settings: auto_increment_increment: %d, auto_increment_offset: %d, both of them must be in range [1..65535]", nil),
@@ -1039,9 +1046,16 @@ var MySQLErrName = map[uint16]*mysql.ErrMessage{
 	ErrPartitionStatsMissing: mysql.Message("Build table: %s global-level stats failed due to missing partition-level stats", nil),
 	ErrNotSupportedWithSem:   mysql.Message("Feature '%s' is not supported when security enhanced mode is enabled", nil),
 
-	ErrInvalidPlacementSpec:   mysql.Message("Invalid placement policy '%s': %s", nil),
-	ErrPlacementPolicyCheck:   mysql.Message("Placement policy didn't meet the constraint, reason: %s", nil),
-	ErrMultiStatementDisabled: mysql.Message("client has multi-statement capability disabled. Run SET GLOBAL tidb_multi_statement_mode='ON' after you understand the security risk", nil),
+	ErrInvalidPlacementSpec:     mysql.Message("Invalid placement policy '%s': %s", nil),
+	ErrPlacementPolicyCheck:     mysql.Message("Placement policy didn't meet the constraint, reason: %s", nil),
+	ErrPlacementPolicyExists:    mysql.Message("Placement policy '%-.192s' already exists", nil),
+	ErrPlacementPolicyNotExists: mysql.Message("Unknown placement policy '%-.192s'", nil),
+	ErrPlacementPolicyInUse:     mysql.Message("Placement policy '%-.192s' is still in use", nil),
+	ErrServerBusy:               mysql.Message("Server is busy: %s", nil),
+	ErrMultiStatementDisabled:   mysql.Message("client has multi-statement capability disabled. Run SET GLOBAL tidb_multi_statement_mode='ON' after you understand the security risk", nil),
+
+	ErrTooManyConnectionsFromHost: mysql.Message("Host '%-.64s' already has more than 'max-connections-per-ip' active connections", nil),
+	ErrRowPolicyViolation:         mysql.Message("The row violates the row policies of table '%-.192s'", nil),
 
 	// TiKV/PD errors.
 	ErrPDServerTimeout:           mysql.Message("PD server timeout", nil),
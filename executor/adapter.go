@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"math/rand"
 	"runtime/trace"
 	"strings"
 	"sync/atomic"
@@ -26,6 +27,7 @@ import (
 	"github.com/cznic/mathutil"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/log"
 	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/model"
@@ -46,6 +48,7 @@ import (
 	"github.com/pingcap/tidb/store/tikv/util"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/deadlockhistory"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/hint"
 	"github.com/pingcap/tidb/util/logutil"
@@ -66,6 +69,8 @@ var (
 	totalQueryProcHistogramInternal = metrics.TotalQueryProcHistogram.WithLabelValues(metrics.LblInternal)
 	totalCopProcHistogramInternal   = metrics.TotalCopProcHistogram.WithLabelValues(metrics.LblInternal)
 	totalCopWaitHistogramInternal   = metrics.TotalCopWaitHistogram.WithLabelValues(metrics.LblInternal)
+	sampledSlowLogCounter           = metrics.SampledQueryCounter
+	droppedSlowLogCounter           = metrics.SlowQueryDroppedCounter
 )
 
 // processinfoSetter is the interface use to set current running process info.
@@ -80,6 +85,8 @@ type recordSet struct {
 	stmt       *ExecStmt
 	lastErr    error
 	txnStartTS uint64
+	// rowsReturned indicates whether any row has been returned, the statement can't be retried after that.
+	rowsReturned bool
 }
 
 func (a *recordSet) Fields() []*ast.ResultField {
@@ -139,11 +146,28 @@ func (a *recordSet) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 	}()
 
 	err = Next(ctx, a.executor, req)
+	failpoint.Inject("recordSetWriteConflict", func(val failpoint.Value) {
+		if val.(bool) && err == nil && !a.rowsReturned {
+			err = kv.ErrWriteConflict.FastGenByArgs(a.txnStartTS, 0, 0, "recordSetWriteConflict")
+		}
+	})
+	for err != nil && !a.rowsReturned {
+		var e Executor
+		if e, err = a.stmt.retryRCStmt(ctx, err); e == nil {
+			break
+		}
+		terror.Log(a.executor.Close())
+		a.executor = e
+		err = Next(ctx, a.executor, req)
+	}
 	if err != nil {
 		a.lastErr = err
 		return err
 	}
 	numRows := req.NumRows()
+	if numRows > 0 {
+		a.rowsReturned = true
+	}
 	if numRows == 0 {
 		if a.stmt != nil {
 			a.stmt.Ctx.GetSessionVars().LastFoundRows = a.stmt.Ctx.GetSessionVars().StmtCtx.FoundRows()
@@ -167,11 +191,6 @@ func (a *recordSet) Close() error {
 	return err
 }
 
-// OnFetchReturned implements commandLifeCycle#OnFetchReturned
-func (a *recordSet) OnFetchReturned() {
-	a.stmt.LogSlowQuery(a.txnStartTS, a.lastErr == nil, true)
-}
-
 // ExecStmt implements the sqlexec.Statement interface, it builds a planner.Plan to an sqlexec.Statement.
 type ExecStmt struct {
 	// GoCtx stores parent go context.Context for a stmt.
@@ -193,6 +212,9 @@ type ExecStmt struct {
 	isSelectForUpdate bool
 	retryCount        uint
 	retryStartTime    time.Time
+	// sampled indicates whether the statement is sampled by tidb_slow_log_sample_rate, the sampled statements are
+	// written to the slow log even if they are fast, and refresh the samples of the statements summary.
+	sampled bool
 
 	// OutputNames will be set if using cached plan
 	OutputNames []*types.FieldName
@@ -629,6 +651,42 @@ func UpdateForUpdateTS(seCtx sessionctx.Context, newForUpdateTS uint64) error {
 	return nil
 }
 
+// recordDeadlock records the deadlock into the deadlock history. The wait chain starts from the statement trying to
+// lock the key, the transaction holding the lock is added to the chain if it's running on this tidb-server.
+func (a *ExecStmt) recordDeadlock(deadlock *tikvstore.ErrDeadlock) *deadlockhistory.DeadlockRecord {
+	sessVars := a.Ctx.GetSessionVars()
+	normalized, digest := sessVars.StmtCtx.SQLDigest()
+	record := &deadlockhistory.DeadlockRecord{
+		OccurTime:   time.Now(),
+		IsRetryable: deadlock.IsRetryable,
+		WaitChain: []deadlockhistory.WaitChainItem{{
+			TryLockTxn:     sessVars.TxnCtx.StartTS,
+			SQLDigest:      digest,
+			SQLDigestText:  normalized,
+			Key:            deadlock.LockKey,
+			TxnHoldingLock: deadlock.LockTs,
+		}},
+	}
+	if sm := a.Ctx.GetSessionManager(); sm != nil {
+		for _, pi := range sm.ShowProcessList() {
+			if pi.CurTxnStartTS != deadlock.LockTs || pi.StmtCtx == nil {
+				continue
+			}
+			normalized, digest := pi.StmtCtx.SQLDigest()
+			record.WaitChain = append(record.WaitChain, deadlockhistory.WaitChainItem{
+				TryLockTxn:    pi.CurTxnStartTS,
+				SQLDigest:     digest,
+				SQLDigestText: normalized,
+			})
+			break
+		}
+	}
+	history := deadlockhistory.GlobalDeadlockHistory
+	history.Resize(config.GetGlobalConfig().PessimisticTxn.DeadlockHistoryCapacity)
+	history.Push(record)
+	return record
+}
+
 // handlePessimisticLockError updates TS and rebuild executor if the err is write conflict.
 func (a *ExecStmt) handlePessimisticLockError(ctx context.Context, err error) (Executor, error) {
 	sessVars := a.Ctx.GetSessionVars()
@@ -638,8 +696,9 @@ func (a *ExecStmt) handlePessimisticLockError(ctx context.Context, err error) (E
 	txnCtx := sessVars.TxnCtx
 	var newForUpdateTS uint64
 	if deadlock, ok := errors.Cause(err).(*tikvstore.ErrDeadlock); ok {
+		record := a.recordDeadlock(deadlock)
 		if !deadlock.IsRetryable {
-			return nil, ErrDeadlock
+			return nil, ErrDeadlock.GenWithStackByArgs(record.ID, record.Digest())
 		}
 		logutil.Logger(ctx).Info("single statement deadlock, retry statement",
 			zap.Uint64("txn", txnCtx.StartTS),
@@ -698,6 +757,25 @@ func (a *ExecStmt) handlePessimisticLockError(ctx context.Context, err error) (E
 	return e, nil
 }
 
+// retryRCStmt retries the statement with a new for-update ts if it meets a write conflict or a retryable deadlock in a
+// read committed pessimistic transaction. The caller should make sure that no row has been returned to the client.
+// It returns a nil executor if the statement can't be retried.
+func (a *ExecStmt) retryRCStmt(ctx context.Context, err error) (Executor, error) {
+	if !a.Ctx.GetSessionVars().IsPessimisticReadConsistency() {
+		return nil, err
+	}
+	if deadlock, ok := errors.Cause(err).(*tikvstore.ErrDeadlock); ok {
+		if !deadlock.IsRetryable {
+			return nil, err
+		}
+	} else if !terror.ErrorEqual(kv.ErrWriteConflict, err) {
+		return nil, err
+	}
+	logutil.Logger(ctx).Debug("read committed statement meets retryable error before returning rows, retry statement",
+		zap.Uint64("txn", a.Ctx.GetSessionVars().TxnCtx.StartTS), zap.Error(err))
+	return a.handlePessimisticLockError(ctx, err)
+}
+
 type pessimisticTxn interface {
 	kv.Transaction
 	// KeysNeedToLock returns the keys need to be locked.
@@ -830,6 +908,7 @@ func (a *ExecStmt) FinishExecuteStmt(txnTS uint64, succ bool, hasMoreResults boo
 		// Only record the read keys in write statement which affect row more than 0.
 		a.Ctx.GetTxnWriteThroughputSLI().AddReadKeys(execDetail.ScanDetail.ProcessedKeys)
 	}
+	a.sampled = !sessVars.InRestrictedSQL && sampleSlowLog(variable.SlowLogSampleRate.Load())
 	// `LowSlowQuery` and `SummaryStmt` must be called before recording `PrevStmt`.
 	a.LogSlowQuery(txnTS, succ, hasMoreResults)
 	a.SummaryStmt(succ)
@@ -848,6 +927,8 @@ func (a *ExecStmt) FinishExecuteStmt(txnTS uint64, succ bool, hasMoreResults boo
 	} else {
 		sessionExecuteRunDurationGeneral.Observe(executeDuration.Seconds())
 	}
+	// The variables set by the SET_VAR hints only take effect in the statement.
+	sessVars.ClearStmtVars()
 }
 
 // CloseRecordSet will finish the execution of current statement and do some record work
@@ -875,9 +956,24 @@ func (a *ExecStmt) LogSlowQuery(txnTS uint64, succ bool, hasMoreResults bool) {
 	enable := cfg.Log.EnableSlowLog
 	// if the level is Debug, or trace is enabled, print slow logs anyway
 	force := level <= zapcore.DebugLevel || trace.IsEnabled()
-	if (!enable || costTime < threshold) && !force {
+	// statements below the threshold may still be sampled to establish latency baselines.
+	sampled := enable && costTime < threshold && a.sampled
+	if (!enable || costTime < threshold) && !force && !sampled {
 		return
 	}
+	// Statements just above the threshold may be sampled to keep the log usable when lots of statements are slow.
+	var sampleRate float64
+	dropped := false
+	if enable && !force && costTime >= threshold {
+		ceiling := time.Duration(cfg.Log.SlowLogAboveSampleCeiling) * time.Millisecond
+		if ceiling == 0 {
+			ceiling = 2 * threshold
+		}
+		if costTime < ceiling && cfg.Log.SlowLogAboveSampleRate < 1 {
+			sampleRate = cfg.Log.SlowLogAboveSampleRate
+			dropped = !sampleSlowLog(sampleRate)
+		}
+	}
 	sql := FormatSQL(a.GetTextToLog())
 	_, digest := sessVars.StmtCtx.SQLDigest()
 
@@ -928,10 +1024,12 @@ func (a *ExecStmt) LogSlowQuery(txnTS uint64, succ bool, hasMoreResults bool) {
 		TimeWaitTS:        sessVars.DurationWaitTS,
 		IndexNames:        indexNames,
 		StatsInfos:        statsInfos,
+		UsedStats:         sessVars.StmtCtx.UsedStatsString(),
 		CopTasks:          copTaskInfo,
 		ExecDetail:        execDetail,
 		MemMax:            memMax,
 		DiskMax:           diskMax,
+		OOMActions:        sessVars.StmtCtx.MemTracker.TriggeredActions(),
 		Succ:              succ,
 		Plan:              getPlanTree(a.Ctx, a.Plan),
 		PlanDigest:        planDigest,
@@ -944,7 +1042,11 @@ func (a *ExecStmt) LogSlowQuery(txnTS uint64, succ bool, hasMoreResults bool) {
 		PDTotal:           time.Duration(atomic.LoadInt64(&tikvExecDetail.WaitPDRespDuration)),
 		BackoffTotal:      time.Duration(atomic.LoadInt64(&tikvExecDetail.BackoffDuration)),
 		WriteSQLRespTotal: stmtDetail.WriteSQLRespDuration,
+		WriteSQLRespBytes: stmtDetail.WriteSQLRespBytes,
 		ExecRetryCount:    a.retryCount,
+		Sampled:           sampled,
+		SampleRate:        sampleRate,
+		ImplicitCasts:     sessVars.StmtCtx.GetImplicitCasts(),
 	}
 	if a.retryCount > 0 {
 		slowItems.ExecRetryTime = costTime - sessVars.DurationParse - sessVars.DurationCompile - time.Since(a.retryStartTime)
@@ -956,9 +1058,18 @@ func (a *ExecStmt) LogSlowQuery(txnTS uint64, succ bool, hasMoreResults bool) {
 		trace.Log(a.GoCtx, "details", sessVars.SlowLogFormat(slowItems))
 	}
 	if costTime < threshold {
-		logutil.SlowQueryLogger.Debug(sessVars.SlowLogFormat(slowItems))
+		if sampled {
+			sampledSlowLogCounter.Inc()
+			logutil.SlowQueryLogger.Warn(formatSlowLog(sessVars, slowItems))
+		} else {
+			logutil.SlowQueryLogger.Debug(formatSlowLog(sessVars, slowItems))
+		}
 	} else {
-		logutil.SlowQueryLogger.Warn(sessVars.SlowLogFormat(slowItems))
+		if dropped {
+			droppedSlowLogCounter.Inc()
+		} else {
+			logutil.SlowQueryLogger.Warn(formatSlowLog(sessVars, slowItems))
+		}
 		if sessVars.InRestrictedSQL {
 			totalQueryProcHistogramInternal.Observe(costTime.Seconds())
 			totalCopProcHistogramInternal.Observe(execDetail.TimeDetail.ProcessTime.Seconds())
@@ -994,6 +1105,22 @@ func (a *ExecStmt) LogSlowQuery(txnTS uint64, succ bool, hasMoreResults bool) {
 	}
 }
 
+// formatSlowLog formats the slow log in the configured format.
+func formatSlowLog(sessVars *variable.SessionVars, slowItems *variable.SlowQueryLogItems) string {
+	if config.GetGlobalConfig().Log.SlowLogFormat == logutil.SlowLogFormatJSON {
+		return sessVars.SlowLogJSONFormat(slowItems)
+	}
+	return sessVars.SlowLogFormat(slowItems)
+}
+
+// sampleSlowLog reports whether a statement below the slow threshold should be logged, given the sample rate.
+func sampleSlowLog(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	return rate >= 1 || rand.Float64() < rate
+}
+
 // getPlanTree will try to get the select plan tree if the plan is select or the select plan of delete/update/insert statement.
 func getPlanTree(sctx sessionctx.Context, p plannercore.Plan) string {
 	cfg := config.GetGlobalConfig()
@@ -1141,6 +1268,7 @@ func (a *ExecStmt) SummaryStmt(succ bool) {
 		StmtExecDetails: stmtDetail,
 		TiKVExecDetails: tikvExecDetail,
 		Prepared:        a.isPreparedStmt,
+		Sampled:         a.sampled,
 	}
 	if a.retryCount > 0 {
 		stmtExecInfo.ExecRetryTime = costTime - sessVars.DurationParse - sessVars.DurationCompile - time.Since(a.retryStartTime)
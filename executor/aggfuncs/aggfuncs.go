@@ -50,6 +50,11 @@ var (
 	_ AggFunc = (*percentileOriginal4Int)(nil)
 	_ AggFunc = (*percentileOriginal4Real)(nil)
 	_ AggFunc = (*percentileOriginal4Decimal)(nil)
+	_ AggFunc = (*percentileOriginal4Time)(nil)
+	_ AggFunc = (*percentileOriginal4Duration)(nil)
+	_ AggFunc = (*approxPercentilePartial1)(nil)
+	_ AggFunc = (*approxPercentilePartial2)(nil)
+	_ AggFunc = (*approxPercentileFinal)(nil)
 
 	// All the AggFunc implementations for "FIRSTROW" are listed here.
 	_ AggFunc = (*firstRow4Decimal)(nil)
@@ -204,6 +209,16 @@ type SlidingWindowAggFunc interface {
 	Slide(sctx sessionctx.Context, rows []chunk.Row, lastStart, lastEnd uint64, shiftStart, shiftEnd uint64, pr PartialResult) error
 }
 
+// BatchWindowFunc is the interface to append the final results of the window functions
+// whose results only depend on the position of the row in the partition, e.g. ntile,
+// for many rows at once.
+type BatchWindowFunc interface {
+	// AppendFinalResults2Chunk appends the final results of the next n rows of the
+	// partition to the chunk. It's equivalent to calling AppendFinalResult2Chunk n
+	// times, but avoids the per row function call and checks.
+	AppendFinalResults2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk, n int) error
+}
+
 // MaxMinSlidingWindowAggFunc is the interface to evaluate the max/min agg function using sliding window
 type MaxMinSlidingWindowAggFunc interface {
 	// SetWindowStart sets the start position of window
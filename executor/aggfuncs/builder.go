@@ -155,30 +155,54 @@ func buildApproxPercentile(sctx sessionctx.Context, aggFuncDesc *aggregation.Agg
 
 	base := basePercentile{percent: int(percent), baseAggFunc: baseAggFunc{args: aggFuncDesc.Args, ordinal: ordinal}}
 
-	evalType := aggFuncDesc.Args[0].GetType().EvalType()
-	if aggFuncDesc.Args[0].GetType().Tp == mysql.TypeBit {
-		evalType = types.ETString // same as other aggregate function
-	}
+	// The partial and final phases exchange serialized t-digests. The partial aggregation
+	// pushed down across union also outputs them, its result type is changed for this.
+	outputState := aggregation.IsApproxPercentileState(aggFuncDesc.RetTp)
+	valueTp := aggFuncDesc.Args[0].GetType()
 	switch aggFuncDesc.Mode {
-	case aggregation.CompleteMode, aggregation.Partial1Mode, aggregation.FinalMode:
-		switch evalType {
-		case types.ETInt:
-			return &percentileOriginal4Int{base}
-		case types.ETReal:
-			return &percentileOriginal4Real{base}
-		case types.ETDecimal:
-			return &percentileOriginal4Decimal{base}
-		case types.ETDatetime, types.ETTimestamp:
-			return &percentileOriginal4Time{base}
-		case types.ETDuration:
-			return &percentileOriginal4Duration{base}
-		default:
-			// Return NULL in any case
-			return &base
+	case aggregation.Partial1Mode:
+		outputState = true
+	case aggregation.Partial2Mode:
+		return newApproxPercentilePartial2(base)
+	case aggregation.FinalMode:
+		if outputState {
+			return newApproxPercentilePartial2(base)
 		}
+		valueTp = aggFuncDesc.RetTp
 	}
 
-	return nil
+	evalType := valueTp.EvalType()
+	if valueTp.Tp == mysql.TypeBit {
+		evalType = types.ETString // same as other aggregate function
+	}
+	var original AggFunc
+	switch evalType {
+	case types.ETInt:
+		original = &percentileOriginal4Int{baseApproxPercentile{base}}
+	case types.ETReal:
+		original = &percentileOriginal4Real{baseApproxPercentile{base}}
+	case types.ETDecimal:
+		base.frac = aggFuncDesc.RetTp.Decimal
+		original = &percentileOriginal4Decimal{baseApproxPercentile{base}}
+	case types.ETDuration:
+		base.frac = aggFuncDesc.RetTp.Decimal
+		original = &percentileOriginal4Duration{baseApproxPercentile{base}}
+	case types.ETDatetime, types.ETTimestamp:
+		// The time values are kept exactly, they can be merged by the parallel hash
+		// aggregation but can't be serialized, see `aggregation.ApproxPercentileSplittable`.
+		return &percentileOriginal4Time{base}
+	default:
+		// Return NULL in any case
+		return &base
+	}
+
+	switch {
+	case outputState:
+		return &approxPercentilePartial1{AggFunc: original, ordinal: ordinal}
+	case aggFuncDesc.Mode == aggregation.FinalMode:
+		return &approxPercentileFinal{AggFunc: original, arg: aggFuncDesc.Args[0]}
+	}
+	return original
 }
 
 // buildCount builds the AggFunc implementation for function "COUNT".
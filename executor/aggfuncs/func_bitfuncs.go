@@ -17,7 +17,9 @@ import (
 	"math"
 	"unsafe"
 
+	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 )
 
@@ -47,12 +49,77 @@ func (e *baseBitAggFunc) AppendFinalResult2Chunk(sctx sessionctx.Context, pr Par
 	return nil
 }
 
+// bitBatchInput is the input of the bit functions in the rows [begin, end) of col.
+type bitBatchInput struct {
+	col        *chunk.Column
+	begin, end int
+	pooled     bool
+}
+
+func (in *bitBatchInput) release() {
+	if in.pooled {
+		expression.PutColumn(in.col)
+	}
+}
+
+// batchInput evaluates the argument for all the rows at once. It only works if the rows are consecutive rows of
+// one chunk, which is the case of the rows of a group in the stream aggregation and the whole input of an
+// aggregation without group by. The argument is read directly if it's a column, otherwise it's evaluated by the
+// vectorized evaluation if the rows cover the whole chunk.
+func (e *baseBitAggFunc) batchInput(sctx sessionctx.Context, rows []chunk.Row) (in bitBatchInput, ok bool, err error) {
+	if len(rows) < 2 {
+		return in, false, nil
+	}
+	chk := rows[0].Chunk()
+	if chk == nil || chk.Sel() != nil {
+		return in, false, nil
+	}
+	begin := rows[0].Idx()
+	for i, row := range rows {
+		if row.Chunk() != chk || row.Idx() != begin+i {
+			return in, false, nil
+		}
+	}
+	end := begin + len(rows)
+	arg := e.args[0]
+	if col, isCol := arg.(*expression.Column); isCol && col.GetType().EvalType() == types.ETInt && !col.GetType().Hybrid() {
+		return bitBatchInput{col: chk.Column(col.Index), begin: begin, end: end}, true, nil
+	}
+	if begin != 0 || end != chk.NumRows() || !expression.Vectorizable(e.args) {
+		return in, false, nil
+	}
+	buf, err := expression.GetColumn(types.ETInt, len(rows))
+	if err != nil {
+		return in, false, err
+	}
+	if err = arg.VecEvalInt(sctx, chk, buf); err != nil {
+		expression.PutColumn(buf)
+		return in, false, err
+	}
+	return bitBatchInput{col: buf, begin: begin, end: end, pooled: true}, true, nil
+}
+
 type bitOrUint64 struct {
 	baseBitAggFunc
 }
 
 func (e *bitOrUint64) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
 	p := (*partialResult4BitFunc)(pr)
+	in, ok, err := e.batchInput(sctx, rowsInGroup)
+	if err != nil {
+		return memDelta, err
+	}
+	if ok {
+		defer in.release()
+		values := in.col.Uint64s()
+		for i := in.begin; i < in.end; i++ {
+			if in.col.IsNull(i) {
+				continue
+			}
+			*p |= values[i]
+		}
+		return memDelta, nil
+	}
 	for _, row := range rowsInGroup {
 		inputValue, isNull, err := e.args[0].EvalInt(sctx, row)
 		if err != nil {
@@ -78,6 +145,21 @@ type bitXorUint64 struct {
 
 func (e *bitXorUint64) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
 	p := (*partialResult4BitFunc)(pr)
+	in, ok, err := e.batchInput(sctx, rowsInGroup)
+	if err != nil {
+		return memDelta, err
+	}
+	if ok {
+		defer in.release()
+		values := in.col.Uint64s()
+		for i := in.begin; i < in.end; i++ {
+			if in.col.IsNull(i) {
+				continue
+			}
+			*p ^= values[i]
+		}
+		return memDelta, nil
+	}
 	for _, row := range rowsInGroup {
 		inputValue, isNull, err := e.args[0].EvalInt(sctx, row)
 		if err != nil {
@@ -139,6 +221,21 @@ func (e *bitAndUint64) ResetPartialResult(pr PartialResult) {
 
 func (e *bitAndUint64) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
 	p := (*partialResult4BitFunc)(pr)
+	in, ok, err := e.batchInput(sctx, rowsInGroup)
+	if err != nil {
+		return memDelta, err
+	}
+	if ok {
+		defer in.release()
+		values := in.col.Uint64s()
+		for i := in.begin; i < in.end; i++ {
+			if in.col.IsNull(i) {
+				continue
+			}
+			*p &= values[i]
+		}
+		return memDelta, nil
+	}
 	for _, row := range rowsInGroup {
 		inputValue, isNull, err := e.args[0].EvalInt(sctx, row)
 		if err != nil {
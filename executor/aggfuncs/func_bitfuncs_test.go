@@ -14,10 +14,16 @@
 package aggfuncs_test
 
 import (
+	"math"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/executor/aggfuncs"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/expression/aggregation"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
 )
 
 func (s *testSuite) TestMergePartialResult4BitFuncs(c *C) {
@@ -44,3 +50,45 @@ func (s *testSuite) TestMemBitFunc(c *C) {
 		s.testAggMemFunc(c, test)
 	}
 }
+
+func (s *testSuite) TestBatchUpdate4BitFuncs(c *C) {
+	uintTp := types.NewFieldType(mysql.TypeLonglong)
+	uintTp.Flag |= mysql.UnsignedFlag
+	decTp := types.NewFieldType(mysql.TypeNewDecimal)
+	decTp.Flen, decTp.Decimal = 30, 0
+	srcChk := chunk.NewChunkWithCapacity([]*types.FieldType{uintTp, decTp}, 8)
+	for i, v := range []uint64{math.MaxUint64, 1 << 63, 0xF0F0F0F0F0F0F0F0, 0, 0xFFFFFFFFFFFFFFFE} {
+		if i == 3 {
+			srcChk.AppendNull(0)
+			srcChk.AppendNull(1)
+			continue
+		}
+		srcChk.AppendUint64(0, v)
+		srcChk.AppendMyDecimal(1, types.NewDecFromUint(v))
+	}
+	rows := make([]chunk.Row, 0, srcChk.NumRows())
+	for i := 0; i < srcChk.NumRows(); i++ {
+		rows = append(rows, srcChk.GetRow(i))
+	}
+	funcs := []string{ast.AggFuncBitAnd, ast.AggFuncBitOr, ast.AggFuncBitXor}
+	expected := []uint64{1 << 63, math.MaxUint64, math.MaxUint64 ^ (1 << 63) ^ 0xF0F0F0F0F0F0F0F0 ^ 0xFFFFFFFFFFFFFFFE}
+	for i, name := range funcs {
+		for colIdx, tp := range []*types.FieldType{uintTp, decTp} {
+			args := []expression.Expression{&expression.Column{RetType: tp, Index: colIdx}}
+			desc, err := aggregation.NewAggFuncDesc(s.ctx, name, args, false)
+			c.Assert(err, IsNil)
+			aggFunc := aggfuncs.Build(s.ctx, desc, 0)
+			// Update the whole chunk at once, then a part of the chunk and the rows one by one.
+			for _, groups := range [][][]chunk.Row{{rows}, {rows[:1], rows[1:4], rows[4:]}, {rows[:1], rows[1:2], rows[2:3], rows[3:4], rows[4:]}} {
+				pr, _ := aggFunc.AllocPartialResult()
+				for _, group := range groups {
+					_, err = aggFunc.UpdatePartialResult(s.ctx, group, pr)
+					c.Assert(err, IsNil)
+				}
+				resultChk := chunk.NewChunkWithCapacity([]*types.FieldType{desc.RetTp}, 1)
+				c.Assert(aggFunc.AppendFinalResult2Chunk(s.ctx, pr, resultChk), IsNil)
+				c.Assert(resultChk.GetRow(0).GetUint64(0), Equals, expected[i], Commentf("%s over column %d", name, colIdx))
+			}
+		}
+	}
+}
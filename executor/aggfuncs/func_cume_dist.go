@@ -64,3 +64,20 @@ func (r *cumeDist) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialRe
 	chk.AppendFloat64(r.ordinal, float64(p.lastRank)/float64(numRows))
 	return nil
 }
+
+func (r *cumeDist) AppendFinalResults2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk, n int) error {
+	p := (*partialResult4CumeDist)(pr)
+	col := chk.Column(r.ordinal)
+	numRows := len(p.rows)
+	for i := 0; i < n; i++ {
+		// The rows in the same peer group share the same result, find the end of the group only once.
+		if p.curIdx == p.lastRank {
+			for p.lastRank < numRows && r.compareRows(p.rows[p.curIdx], p.rows[p.lastRank]) == 0 {
+				p.lastRank++
+			}
+		}
+		p.curIdx++
+		col.AppendFloat64(float64(p.lastRank) / float64(numRows))
+	}
+	return nil
+}
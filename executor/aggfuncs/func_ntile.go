@@ -85,3 +85,38 @@ func (n *ntile) AppendFinalResult2Chunk(_ sessionctx.Context, pr PartialResult,
 	}
 	return nil
 }
+
+func (n *ntile) AppendFinalResults2Chunk(_ sessionctx.Context, pr PartialResult, chk *chunk.Chunk, num int) error {
+	p := (*partialResult4Ntile)(pr)
+	col := chk.Column(n.ordinal)
+
+	// If the divisor is 0, the arg of NTILE would be NULL. So we just return NULL.
+	if n.n == 0 {
+		for i := 0; i < num; i++ {
+			col.AppendNull()
+		}
+		return nil
+	}
+
+	for num > 0 {
+		// Append the rows remained in the current group at once.
+		curMaxIdx := p.quotient
+		if p.curGroupIdx <= p.remainder {
+			curMaxIdx++
+		}
+		cnt := curMaxIdx - p.curIdx
+		if cnt > uint64(num) {
+			cnt = uint64(num)
+		}
+		for i := uint64(0); i < cnt; i++ {
+			col.AppendUint64(p.curGroupIdx)
+		}
+		num -= int(cnt)
+		p.curIdx += cnt
+		if p.curIdx == curMaxIdx {
+			p.curIdx = 0
+			p.curGroupIdx++
+		}
+	}
+	return nil
+}
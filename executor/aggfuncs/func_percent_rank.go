@@ -60,3 +60,22 @@ func (pr *percentRank) AppendFinalResult2Chunk(sctx sessionctx.Context, partial
 	chk.AppendFloat64(pr.ordinal, float64(p.lastRank-1)/float64(numRows-1))
 	return nil
 }
+
+func (pr *percentRank) AppendFinalResults2Chunk(sctx sessionctx.Context, partial PartialResult, chk *chunk.Chunk, n int) error {
+	p := (*partialResult4Rank)(partial)
+	col := chk.Column(pr.ordinal)
+	denominator := float64(len(p.rows) - 1)
+	for i := 0; i < n; i++ {
+		p.curIdx++
+		if p.curIdx == 1 {
+			p.lastRank = 1
+			col.AppendFloat64(0)
+			continue
+		}
+		if pr.compareRows(p.rows[p.curIdx-2], p.rows[p.curIdx-1]) != 0 {
+			p.lastRank = p.curIdx
+		}
+		col.AppendFloat64(float64(p.lastRank-1) / denominator)
+	}
+	return nil
+}
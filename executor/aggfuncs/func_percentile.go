@@ -16,11 +16,14 @@ package aggfuncs
 import (
 	"math"
 	"sort"
+	"time"
 	"unsafe"
 
+	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/selection"
 )
 
@@ -30,11 +33,7 @@ const (
 )
 
 var (
-	_ partialResult4Percentile = partialResult4PercentileInt{}
-	_ partialResult4Percentile = partialResult4PercentileReal{}
-	_ partialResult4Percentile = partialResult4PercentileDecimal{}
 	_ partialResult4Percentile = partialResult4PercentileTime{}
-	_ partialResult4Percentile = partialResult4PercentileDuration{}
 )
 
 func percentile(data sort.Interface, percent int) int {
@@ -72,37 +71,6 @@ type partialResult4Percentile interface {
 	MemSize() int64
 }
 
-type partialResult4PercentileInt []int64
-
-func (p partialResult4PercentileInt) Len() int           { return len(p) }
-func (p partialResult4PercentileInt) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
-func (p partialResult4PercentileInt) Less(i, j int) bool { return p[i] < p[j] }
-
-func (p partialResult4PercentileInt) MemSize() int64 {
-	return DefSliceSize + int64(len(p))*DefInt64Size
-}
-
-type partialResult4PercentileReal []float64
-
-func (p partialResult4PercentileReal) Len() int           { return len(p) }
-func (p partialResult4PercentileReal) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
-func (p partialResult4PercentileReal) Less(i, j int) bool { return p[i] < p[j] }
-
-func (p partialResult4PercentileReal) MemSize() int64 {
-	return DefSliceSize + int64(len(p))*DefFloat64Size
-}
-
-// TODO: use []*types.MyDecimal to prevent massive value copy
-type partialResult4PercentileDecimal []types.MyDecimal
-
-func (p partialResult4PercentileDecimal) Len() int           { return len(p) }
-func (p partialResult4PercentileDecimal) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
-func (p partialResult4PercentileDecimal) Less(i, j int) bool { return p[i].Compare(&p[j]) < 0 }
-
-func (p partialResult4PercentileDecimal) MemSize() int64 {
-	return DefSliceSize + int64(len(p))*int64(types.MyDecimalStructSize)
-}
-
 type partialResult4PercentileTime []types.Time
 
 func (p partialResult4PercentileTime) Len() int           { return len(p) }
@@ -113,34 +81,35 @@ func (p partialResult4PercentileTime) MemSize() int64 {
 	return DefSliceSize + int64(len(p))*DefInt64Size
 }
 
-type partialResult4PercentileDuration []types.Duration
-
-func (p partialResult4PercentileDuration) Len() int           { return len(p) }
-func (p partialResult4PercentileDuration) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
-func (p partialResult4PercentileDuration) Less(i, j int) bool { return p[i].Compare(p[j]) < 0 }
+// baseApproxPercentile estimates the percentile of numeric values by a t-digest, so the
+// memory usage of each group is bounded no matter how many rows it has.
+type baseApproxPercentile struct {
+	basePercentile
+}
 
-func (p partialResult4PercentileDuration) MemSize() int64 {
-	return DefSliceSize + int64(len(p))*DefInt64Size
+func (e *baseApproxPercentile) AllocPartialResult() (pr PartialResult, memDelta int64) {
+	return PartialResult(newTDigest()), DefTDigestSize
 }
 
-type percentileOriginal4Int struct {
-	basePercentile
+func (e *baseApproxPercentile) ResetPartialResult(pr PartialResult) {
+	(*tDigest)(pr).reset()
 }
 
-func (e *percentileOriginal4Int) AllocPartialResult() (pr PartialResult, memDelta int64) {
-	// TODO: Preserve appropriate capacity for data
-	pr = PartialResult(&partialResult4PercentileInt{})
-	return pr, DefSliceSize
+func (e *baseApproxPercentile) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) (memDelta int64, err error) {
+	p1, p2 := (*tDigest)(src), (*tDigest)(dst)
+	oldMemUsage := p2.MemUsage()
+	err = p2.merge(p1)
+	p1.reset()
+	return p2.MemUsage() - oldMemUsage, err
 }
 
-func (e *percentileOriginal4Int) ResetPartialResult(pr PartialResult) {
-	p := (*partialResult4PercentileInt)(pr)
-	*p = partialResult4PercentileInt{}
+type percentileOriginal4Int struct {
+	baseApproxPercentile
 }
 
 func (e *percentileOriginal4Int) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
-	p := (*partialResult4PercentileInt)(pr)
-	startMem := p.MemSize()
+	p := (*tDigest)(pr)
+	oldMemUsage := p.MemUsage()
 	for _, row := range rowsInGroup {
 		v, isNull, err := e.args[0].EvalInt(sctx, row)
 		if err != nil {
@@ -149,51 +118,47 @@ func (e *percentileOriginal4Int) UpdatePartialResult(sctx sessionctx.Context, ro
 		if isNull {
 			continue
 		}
-		*p = append(*p, v)
+		p.addInt(v)
 	}
-	endMem := p.MemSize()
-	return endMem - startMem, nil
-}
-
-func (e *percentileOriginal4Int) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) (memDelta int64, err error) {
-	p1, p2 := (*partialResult4PercentileInt)(src), (*partialResult4PercentileInt)(dst)
-	mergeBuff := make([]int64, len(*p1)+len(*p2))
-	copy(mergeBuff, *p2)
-	copy(mergeBuff[len(*p2):], *p1)
-	*p1 = nil
-	*p2 = mergeBuff
-	return 0, nil
+	return p.MemUsage() - oldMemUsage, nil
 }
 
 func (e *percentileOriginal4Int) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
-	p := (*partialResult4PercentileInt)(pr)
-	if len(*p) == 0 {
+	p := (*tDigest)(pr)
+	if len(p.ints) > 0 {
+		chk.AppendInt64(e.ordinal, p.ints[percentile(int64Slice(p.ints), e.percent)])
+		return nil
+	}
+	if err := p.flushExact(); err != nil {
+		return err
+	}
+	if p.count == 0 {
 		chk.AppendNull(e.ordinal)
 		return nil
 	}
-	index := percentile(p, e.percent)
-	chk.AppendInt64(e.ordinal, (*p)[index])
+	chk.AppendInt64(e.ordinal, float64ToInt64(math.Round(p.quantile(e.percent))))
 	return nil
 }
 
-type percentileOriginal4Real struct {
-	basePercentile
-}
-
-func (e *percentileOriginal4Real) AllocPartialResult() (pr PartialResult, memDelta int64) {
-	// TODO: Preserve appropriate capacity for data
-	pr = PartialResult(&partialResult4PercentileReal{})
-	return pr, DefSliceSize
+// float64ToInt64 converts f to int64, the values out of the range of int64 are clamped,
+// which happens when float64(math.MaxInt64) is rounded up to 2^63.
+func float64ToInt64(f float64) int64 {
+	if f >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	if f <= math.MinInt64 {
+		return math.MinInt64
+	}
+	return int64(f)
 }
 
-func (e *percentileOriginal4Real) ResetPartialResult(pr PartialResult) {
-	p := (*partialResult4PercentileReal)(pr)
-	*p = partialResult4PercentileReal{}
+type percentileOriginal4Real struct {
+	baseApproxPercentile
 }
 
 func (e *percentileOriginal4Real) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
-	p := (*partialResult4PercentileReal)(pr)
-	startMem := p.MemSize()
+	p := (*tDigest)(pr)
+	oldMemUsage := p.MemUsage()
 	for _, row := range rowsInGroup {
 		v, isNull, err := e.args[0].EvalReal(sctx, row)
 		if err != nil {
@@ -202,51 +167,31 @@ func (e *percentileOriginal4Real) UpdatePartialResult(sctx sessionctx.Context, r
 		if isNull {
 			continue
 		}
-		*p = append(*p, v)
+		p.add(v)
 	}
-	endMem := p.MemSize()
-	return endMem - startMem, nil
-}
-
-func (e *percentileOriginal4Real) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) (memDelta int64, err error) {
-	p1, p2 := (*partialResult4PercentileReal)(src), (*partialResult4PercentileReal)(dst)
-	mergeBuff := make([]float64, len(*p1)+len(*p2))
-	copy(mergeBuff, *p2)
-	copy(mergeBuff[len(*p2):], *p1)
-	*p1 = nil
-	*p2 = mergeBuff
-	return 0, nil
+	return p.MemUsage() - oldMemUsage, nil
 }
 
 func (e *percentileOriginal4Real) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
-	p := (*partialResult4PercentileReal)(pr)
-	if len(*p) == 0 {
+	p := (*tDigest)(pr)
+	if err := p.flushExact(); err != nil {
+		return err
+	}
+	if p.count == 0 {
 		chk.AppendNull(e.ordinal)
 		return nil
 	}
-	index := percentile(*p, e.percent)
-	chk.AppendFloat64(e.ordinal, (*p)[index])
+	chk.AppendFloat64(e.ordinal, p.quantile(e.percent))
 	return nil
 }
 
 type percentileOriginal4Decimal struct {
-	basePercentile
-}
-
-func (e *percentileOriginal4Decimal) AllocPartialResult() (pr PartialResult, memDelta int64) {
-	// TODO: Preserve appropriate capacity for data
-	pr = PartialResult(&partialResult4PercentileDecimal{})
-	return pr, DefSliceSize
-}
-
-func (e *percentileOriginal4Decimal) ResetPartialResult(pr PartialResult) {
-	p := (*partialResult4PercentileDecimal)(pr)
-	*p = partialResult4PercentileDecimal{}
+	baseApproxPercentile
 }
 
 func (e *percentileOriginal4Decimal) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
-	p := (*partialResult4PercentileDecimal)(pr)
-	startMem := p.MemSize()
+	p := (*tDigest)(pr)
+	oldMemUsage := p.MemUsage()
 	for _, row := range rowsInGroup {
 		v, isNull, err := e.args[0].EvalDecimal(sctx, row)
 		if err != nil {
@@ -255,106 +200,155 @@ func (e *percentileOriginal4Decimal) UpdatePartialResult(sctx sessionctx.Context
 		if isNull {
 			continue
 		}
-		*p = append(*p, *v)
+		if err = p.addDecimal(v); err != nil {
+			return 0, err
+		}
 	}
-	endMem := p.MemSize()
-	return endMem - startMem, nil
+	return p.MemUsage() - oldMemUsage, nil
 }
 
-func (e *percentileOriginal4Decimal) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) (memDelta int64, err error) {
-	p1, p2 := (*partialResult4PercentileDecimal)(src), (*partialResult4PercentileDecimal)(dst)
-	mergeBuff := make([]types.MyDecimal, len(*p1)+len(*p2))
-	copy(mergeBuff, *p2)
-	copy(mergeBuff[len(*p2):], *p1)
-	*p1 = nil
-	*p2 = mergeBuff
-	return 0, nil
+func (e *percentileOriginal4Decimal) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*tDigest)(pr)
+	result := new(types.MyDecimal)
+	if len(p.decimals) > 0 {
+		*result = p.decimals[percentile(decimalSlice(p.decimals), e.percent)]
+	} else {
+		if err := p.flushExact(); err != nil {
+			return err
+		}
+		if p.count == 0 {
+			chk.AppendNull(e.ordinal)
+			return nil
+		}
+		if err := result.FromFloat64(p.quantile(e.percent)); err != nil {
+			return err
+		}
+	}
+	if err := result.Round(result, e.frac, types.ModeHalfEven); err != nil {
+		return err
+	}
+	chk.AppendMyDecimal(e.ordinal, result)
+	return nil
 }
 
-func (e *percentileOriginal4Decimal) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
-	p := (*partialResult4PercentileDecimal)(pr)
-	if len(*p) == 0 {
+type percentileOriginal4Duration struct {
+	baseApproxPercentile
+}
+
+func (e *percentileOriginal4Duration) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
+	p := (*tDigest)(pr)
+	oldMemUsage := p.MemUsage()
+	for _, row := range rowsInGroup {
+		v, isNull, err := e.args[0].EvalDuration(sctx, row)
+		if err != nil {
+			return 0, err
+		}
+		if isNull {
+			continue
+		}
+		p.add(float64(v.Duration))
+	}
+	return p.MemUsage() - oldMemUsage, nil
+}
+
+func (e *percentileOriginal4Duration) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*tDigest)(pr)
+	if err := p.flushExact(); err != nil {
+		return err
+	}
+	if p.count == 0 {
 		chk.AppendNull(e.ordinal)
 		return nil
 	}
-	index := percentile(*p, e.percent)
-	chk.AppendMyDecimal(e.ordinal, &(*p)[index])
+	fsp, err := types.CheckFsp(e.frac)
+	if err != nil {
+		return err
+	}
+	chk.AppendDuration(e.ordinal, types.Duration{Duration: time.Duration(math.Round(p.quantile(e.percent))), Fsp: fsp})
 	return nil
 }
 
-type percentileOriginal4Time struct {
-	basePercentile
+// approxPercentilePartial1 updates the t-digest by the original input like the wrapped
+// function, but outputs the serialized t-digest as the partial result.
+type approxPercentilePartial1 struct {
+	AggFunc
+	ordinal int
 }
 
-func (e *percentileOriginal4Time) AllocPartialResult() (pr PartialResult, memDelta int64) {
-	// TODO: Preserve appropriate capacity for data
-	pr = PartialResult(&partialResult4PercentileTime{})
-	return pr, DefSliceSize
+func (e *approxPercentilePartial1) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	buf, err := (*tDigest)(pr).serialize()
+	if err != nil {
+		return err
+	}
+	chk.AppendBytes(e.ordinal, buf)
+	return nil
 }
 
-func (e *percentileOriginal4Time) ResetPartialResult(pr PartialResult) {
-	p := (*partialResult4PercentileTime)(pr)
-	*p = partialResult4PercentileTime{}
+// approxPercentilePartial2 merges the serialized t-digests and outputs the merged one.
+type approxPercentilePartial2 struct {
+	approxPercentilePartial1
+	arg expression.Expression
 }
 
-func (e *percentileOriginal4Time) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
-	p := (*partialResult4PercentileTime)(pr)
-	startMem := p.MemSize()
+func newApproxPercentilePartial2(base basePercentile) *approxPercentilePartial2 {
+	return &approxPercentilePartial2{
+		approxPercentilePartial1: approxPercentilePartial1{AggFunc: &baseApproxPercentile{base}, ordinal: base.ordinal},
+		arg:                      base.args[0],
+	}
+}
+
+func (e *approxPercentilePartial2) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
+	return mergeSerializedTDigests(sctx, e.arg, rowsInGroup, pr)
+}
+
+// approxPercentileFinal merges the serialized t-digests and outputs the percentile like the wrapped function.
+type approxPercentileFinal struct {
+	AggFunc
+	arg expression.Expression
+}
+
+func (e *approxPercentileFinal) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
+	return mergeSerializedTDigests(sctx, e.arg, rowsInGroup, pr)
+}
+
+func mergeSerializedTDigests(sctx sessionctx.Context, arg expression.Expression, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
+	p := (*tDigest)(pr)
+	oldMemUsage := p.MemUsage()
 	for _, row := range rowsInGroup {
-		v, isNull, err := e.args[0].EvalTime(sctx, row)
+		input, isNull, err := arg.EvalString(sctx, row)
 		if err != nil {
 			return 0, err
 		}
 		if isNull {
 			continue
 		}
-		*p = append(*p, v)
-	}
-	endMem := p.MemSize()
-	return endMem - startMem, nil
-}
-
-func (e *percentileOriginal4Time) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) (memDelta int64, err error) {
-	p1, p2 := (*partialResult4PercentileTime)(src), (*partialResult4PercentileTime)(dst)
-	mergeBuff := make(partialResult4PercentileTime, len(*p1)+len(*p2))
-	copy(mergeBuff, *p2)
-	copy(mergeBuff[len(*p2):], *p1)
-	*p1 = nil
-	*p2 = mergeBuff
-	return 0, nil
-}
-
-func (e *percentileOriginal4Time) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
-	p := (*partialResult4PercentileTime)(pr)
-	if len(*p) == 0 {
-		chk.AppendNull(e.ordinal)
-		return nil
+		if err = p.readAndMerge(hack.Slice(input)); err != nil {
+			return 0, err
+		}
 	}
-	index := percentile(p, e.percent)
-	chk.AppendTime(e.ordinal, (*p)[index])
-	return nil
+	return p.MemUsage() - oldMemUsage, nil
 }
 
-type percentileOriginal4Duration struct {
+type percentileOriginal4Time struct {
 	basePercentile
 }
 
-func (e *percentileOriginal4Duration) AllocPartialResult() (pr PartialResult, memDelta int64) {
+func (e *percentileOriginal4Time) AllocPartialResult() (pr PartialResult, memDelta int64) {
 	// TODO: Preserve appropriate capacity for data
 	pr = PartialResult(&partialResult4PercentileTime{})
 	return pr, DefSliceSize
 }
 
-func (e *percentileOriginal4Duration) ResetPartialResult(pr PartialResult) {
-	p := (*partialResult4PercentileDuration)(pr)
-	*p = partialResult4PercentileDuration{}
+func (e *percentileOriginal4Time) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4PercentileTime)(pr)
+	*p = partialResult4PercentileTime{}
 }
 
-func (e *percentileOriginal4Duration) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
-	p := (*partialResult4PercentileDuration)(pr)
+func (e *percentileOriginal4Time) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
+	p := (*partialResult4PercentileTime)(pr)
 	startMem := p.MemSize()
 	for _, row := range rowsInGroup {
-		v, isNull, err := e.args[0].EvalDuration(sctx, row)
+		v, isNull, err := e.args[0].EvalTime(sctx, row)
 		if err != nil {
 			return 0, err
 		}
@@ -367,23 +361,23 @@ func (e *percentileOriginal4Duration) UpdatePartialResult(sctx sessionctx.Contex
 	return endMem - startMem, nil
 }
 
-func (e *percentileOriginal4Duration) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) (memDelta int64, err error) {
-	p1, p2 := (*partialResult4PercentileDuration)(src), (*partialResult4PercentileDuration)(dst)
-	mergeBuff := make(partialResult4PercentileDuration, len(*p1)+len(*p2))
+func (e *percentileOriginal4Time) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) (memDelta int64, err error) {
+	p1, p2 := (*partialResult4PercentileTime)(src), (*partialResult4PercentileTime)(dst)
+	mergeBuff := make(partialResult4PercentileTime, len(*p1)+len(*p2))
 	copy(mergeBuff, *p2)
 	copy(mergeBuff[len(*p2):], *p1)
 	*p1 = nil
 	*p2 = mergeBuff
 	return 0, nil
 }
-func (e *percentileOriginal4Duration) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
-	p := (*partialResult4PercentileDuration)(pr)
+
+func (e *percentileOriginal4Time) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4PercentileTime)(pr)
 	if len(*p) == 0 {
 		chk.AppendNull(e.ordinal)
 		return nil
 	}
-	index := percentile(*p, e.percent)
-
-	chk.AppendDuration(e.ordinal, (*p)[index])
+	index := percentile(p, e.percent)
+	chk.AppendTime(e.ordinal, (*p)[index])
 	return nil
 }
@@ -14,12 +14,18 @@
 package aggfuncs_test
 
 import (
+	"math"
+	"math/rand"
 	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/executor/aggfuncs"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/expression/aggregation"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
 )
 
 func (s *testSuite) TestPercentile(c *C) {
@@ -35,3 +41,127 @@ func (s *testSuite) TestPercentile(c *C) {
 		s.testAggFunc(c, test)
 	}
 }
+
+func (s *testSuite) TestApproxPercentileTDigest(c *C) {
+	const numRows = 100000
+	ft := types.NewFieldType(mysql.TypeDouble)
+	args := []expression.Expression{
+		&expression.Column{RetType: ft, Index: 0},
+		&expression.Constant{Value: types.NewIntDatum(90), RetType: types.NewFieldType(mysql.TypeLong)},
+	}
+	desc, err := aggregation.NewAggFuncDesc(s.ctx, ast.AggFuncApproxPercentile, args, false)
+	c.Assert(err, IsNil)
+
+	// The values 0, 1, ..., numRows-1 are split into 4 shuffled parts, each of them is
+	// summarized by a partial function, then the serialized results are merged by a final function.
+	partialDesc, finalDesc := desc.Split([]int{0})
+	partialFunc := aggfuncs.Build(s.ctx, partialDesc, 0)
+	finalFunc := aggfuncs.Build(s.ctx, finalDesc, 0)
+	stateChk := chunk.NewChunkWithCapacity([]*types.FieldType{types.NewFieldType(mysql.TypeString)}, 4)
+	values := rand.Perm(numRows)
+	for part := 0; part < 4; part++ {
+		srcChk := chunk.NewChunkWithCapacity([]*types.FieldType{ft}, numRows/4)
+		for _, v := range values[part*numRows/4 : (part+1)*numRows/4] {
+			srcChk.AppendFloat64(0, float64(v))
+		}
+		pr, memUsage := partialFunc.AllocPartialResult()
+		iter := chunk.NewIterator4Chunk(srcChk)
+		for row := iter.Begin(); row != iter.End(); row = iter.Next() {
+			memDelta, err := partialFunc.UpdatePartialResult(s.ctx, []chunk.Row{row}, pr)
+			c.Assert(err, IsNil)
+			memUsage += memDelta
+		}
+		// The memory usage is bounded by the compression instead of the number of rows.
+		c.Assert(memUsage, Less, int64(16*1024))
+		c.Assert(partialFunc.AppendFinalResult2Chunk(s.ctx, pr, stateChk), IsNil)
+	}
+
+	finalPr, _ := finalFunc.AllocPartialResult()
+	_, err = finalFunc.UpdatePartialResult(s.ctx, []chunk.Row{stateChk.GetRow(0), stateChk.GetRow(1)}, finalPr)
+	c.Assert(err, IsNil)
+	// The final function also merges the partial results directly, like the parallel hash aggregation does.
+	pr, _ := finalFunc.AllocPartialResult()
+	_, err = finalFunc.UpdatePartialResult(s.ctx, []chunk.Row{stateChk.GetRow(2), stateChk.GetRow(3)}, pr)
+	c.Assert(err, IsNil)
+	_, err = finalFunc.MergePartialResult(s.ctx, pr, finalPr)
+	c.Assert(err, IsNil)
+
+	resultChk := chunk.NewChunkWithCapacity([]*types.FieldType{desc.RetTp}, 1)
+	c.Assert(finalFunc.AppendFinalResult2Chunk(s.ctx, finalPr, resultChk), IsNil)
+	result := resultChk.GetRow(0).GetFloat64(0)
+	c.Assert(math.Abs(result-0.9*numRows), Less, 0.01*numRows, Commentf("result: %v", result))
+
+	// A corrupted partial result reports an error instead of panicking.
+	stateChk.Reset()
+	stateChk.AppendBytes(0, []byte{1, 2, 3})
+	_, err = finalFunc.UpdatePartialResult(s.ctx, []chunk.Row{stateChk.GetRow(0)}, finalPr)
+	c.Assert(err, NotNil)
+}
+
+func (s *testSuite) TestApproxPercentileExactValues(c *C) {
+	// The values above 2^53 can't be represented by float64 exactly, so a few
+	// of them are selected on their native types instead of estimated.
+	base := int64(1) << 53
+	intTp := types.NewFieldType(mysql.TypeLonglong)
+	intChk := chunk.NewChunkWithCapacity([]*types.FieldType{intTp}, 5)
+	for _, v := range []int64{base + 7, base + 1, base + 5, base + 3, base + 9} {
+		intChk.AppendInt64(0, v)
+	}
+	decTp := types.NewFieldType(mysql.TypeNewDecimal)
+	decTp.Flen, decTp.Decimal = 30, 2
+	decChk := chunk.NewChunkWithCapacity([]*types.FieldType{decTp}, 5)
+	for _, v := range []string{"9007199254740993.07", "9007199254740993.01", "9007199254740993.05", "9007199254740993.03", "9007199254740993.09"} {
+		decChk.AppendMyDecimal(0, types.NewDecFromStringForTest(v))
+	}
+
+	for _, test := range []struct {
+		ft       *types.FieldType
+		srcChk   *chunk.Chunk
+		expected string
+	}{
+		{intTp, intChk, "9007199254740997"},
+		{decTp, decChk, "9007199254740993.05"},
+	} {
+		args := []expression.Expression{
+			&expression.Column{RetType: test.ft, Index: 0},
+			&expression.Constant{Value: types.NewIntDatum(50), RetType: types.NewFieldType(mysql.TypeLong)},
+		}
+		desc, err := aggregation.NewAggFuncDesc(s.ctx, ast.AggFuncApproxPercentile, args, false)
+		c.Assert(err, IsNil)
+		rows := make([]chunk.Row, 0, test.srcChk.NumRows())
+		for i := 0; i < test.srcChk.NumRows(); i++ {
+			rows = append(rows, test.srcChk.GetRow(i))
+		}
+		resultChk := chunk.NewChunkWithCapacity([]*types.FieldType{desc.RetTp}, 2)
+
+		// Complete mode.
+		finalFunc := aggfuncs.Build(s.ctx, desc, 0)
+		pr, _ := finalFunc.AllocPartialResult()
+		_, err = finalFunc.UpdatePartialResult(s.ctx, rows, pr)
+		c.Assert(err, IsNil)
+		c.Assert(finalFunc.AppendFinalResult2Chunk(s.ctx, pr, resultChk), IsNil)
+
+		// The exact values are also kept in the serialized partial results.
+		partialDesc, finalDesc := desc.Split([]int{0})
+		partialFunc := aggfuncs.Build(s.ctx, partialDesc, 0)
+		finalFunc = aggfuncs.Build(s.ctx, finalDesc, 0)
+		stateChk := chunk.NewChunkWithCapacity([]*types.FieldType{types.NewFieldType(mysql.TypeString)}, 2)
+		for _, part := range [][]chunk.Row{rows[:2], rows[2:]} {
+			pr, _ := partialFunc.AllocPartialResult()
+			_, err = partialFunc.UpdatePartialResult(s.ctx, part, pr)
+			c.Assert(err, IsNil)
+			c.Assert(partialFunc.AppendFinalResult2Chunk(s.ctx, pr, stateChk), IsNil)
+		}
+		pr, _ = finalFunc.AllocPartialResult()
+		_, err = finalFunc.UpdatePartialResult(s.ctx, []chunk.Row{stateChk.GetRow(0), stateChk.GetRow(1)}, pr)
+		c.Assert(err, IsNil)
+		c.Assert(finalFunc.AppendFinalResult2Chunk(s.ctx, pr, resultChk), IsNil)
+
+		for i := 0; i < 2; i++ {
+			d := resultChk.GetRow(i).GetDatum(0, desc.RetTp)
+			result, err := d.ToString()
+			c.Assert(err, IsNil)
+			c.Assert(result, Equals, test.expected)
+		}
+	}
+}
@@ -0,0 +1,389 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+	"unsafe"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/types"
+)
+
+const (
+	// tDigestCompression bounds the number of centroids kept after a compression,
+	// larger values make the estimation more accurate but use more memory.
+	tDigestCompression = 100
+	// tDigestMaxCentroids is the number of centroids which triggers a compression.
+	// Digests which never reach it keep all the values as singletons, so their percentiles are exact.
+	tDigestMaxCentroids = 5 * tDigestCompression
+
+	// The kinds of the exact values in a serialized digest.
+	tDigestExactNone    = 0
+	tDigestExactInt     = 1
+	tDigestExactDecimal = 2
+
+	tDigestCentroidSize = int64(unsafe.Sizeof(tDigestCentroid{}))
+	tDigestDecimalSize  = int64(unsafe.Sizeof(types.MyDecimal{}))
+	// DefTDigestSize is the size of tDigest.
+	DefTDigestSize = int64(unsafe.Sizeof(tDigest{}))
+)
+
+type tDigestCentroid struct {
+	mean  float64
+	count int64
+}
+
+// tDigest is the t-digest sketch introduced by Ted Dunning, it summarizes a distribution
+// by a bounded number of centroids which are small near the tails and large in the middle,
+// so the extreme percentiles are estimated accurately. Two digests can be merged, which
+// makes it usable as the partial result of a parallel or distributed aggregation.
+// See https://arxiv.org/abs/1902.04023 for details.
+//
+// The int and decimal values can't be represented by float64 exactly, so they are kept
+// in ints or decimals until there are more than tDigestMaxCentroids of them, and the
+// percentile of a few values is selected on the native type instead of estimated.
+type tDigest struct {
+	// centroids is sorted by mean only if sorted is true.
+	centroids []tDigestCentroid
+	sorted    bool
+	// count, min and max describe the centroids, they don't include the exact values.
+	count    int64
+	min, max float64
+	ints     []int64
+	decimals []types.MyDecimal
+}
+
+func newTDigest() *tDigest {
+	return &tDigest{sorted: true}
+}
+
+// MemUsage returns the memory usage of the digest.
+func (d *tDigest) MemUsage() int64 {
+	return DefTDigestSize + int64(cap(d.centroids))*tDigestCentroidSize +
+		int64(cap(d.ints))*DefInt64Size + int64(cap(d.decimals))*tDigestDecimalSize
+}
+
+func (d *tDigest) reset() {
+	*d = tDigest{centroids: d.centroids[:0], sorted: true, ints: d.ints[:0], decimals: d.decimals[:0]}
+}
+
+func (d *tDigest) isEmpty() bool {
+	return d.count == 0 && len(d.ints) == 0 && len(d.decimals) == 0
+}
+
+// canKeepExact checks whether the exact ints and decimals can be appended to d without mixing the kinds.
+func (d *tDigest) canKeepExact(ints, decimals int) bool {
+	return len(d.centroids) == 0 && (ints == 0 || len(d.decimals) == 0) && (decimals == 0 || len(d.ints) == 0)
+}
+
+func (d *tDigest) addInt(x int64) {
+	if !d.canKeepExact(1, 0) {
+		d.add(float64(x))
+		return
+	}
+	d.ints = append(d.ints, x)
+	if len(d.ints) > tDigestMaxCentroids {
+		// The ints can always be converted to float64.
+		_ = d.flushExact()
+	}
+}
+
+func (d *tDigest) addDecimal(x *types.MyDecimal) error {
+	if !d.canKeepExact(0, 1) {
+		f, err := x.ToFloat64()
+		if err != nil {
+			return err
+		}
+		d.add(f)
+		return nil
+	}
+	d.decimals = append(d.decimals, *x)
+	if len(d.decimals) > tDigestMaxCentroids {
+		return d.flushExact()
+	}
+	return nil
+}
+
+// flushExact moves the exact values into the centroids, the percentiles are estimated after that.
+func (d *tDigest) flushExact() error {
+	if len(d.ints) == 0 && len(d.decimals) == 0 {
+		return nil
+	}
+	for _, x := range d.ints {
+		d.addCentroid(tDigestCentroid{mean: float64(x), count: 1})
+	}
+	for i := range d.decimals {
+		f, err := d.decimals[i].ToFloat64()
+		if err != nil {
+			return err
+		}
+		d.addCentroid(tDigestCentroid{mean: f, count: 1})
+	}
+	d.ints, d.decimals = d.ints[:0], d.decimals[:0]
+	if len(d.centroids) > tDigestMaxCentroids {
+		d.compress()
+	}
+	return nil
+}
+
+type int64Slice []int64
+
+func (p int64Slice) Len() int           { return len(p) }
+func (p int64Slice) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p int64Slice) Less(i, j int) bool { return p[i] < p[j] }
+
+type decimalSlice []types.MyDecimal
+
+func (p decimalSlice) Len() int           { return len(p) }
+func (p decimalSlice) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p decimalSlice) Less(i, j int) bool { return p[i].Compare(&p[j]) < 0 }
+
+func (d *tDigest) add(x float64) {
+	d.addCentroid(tDigestCentroid{mean: x, count: 1})
+	if len(d.centroids) > tDigestMaxCentroids {
+		d.compress()
+	}
+}
+
+func (d *tDigest) addCentroid(c tDigestCentroid) {
+	if d.count == 0 || c.mean < d.min {
+		d.min = c.mean
+	}
+	if d.count == 0 || c.mean > d.max {
+		d.max = c.mean
+	}
+	d.count += c.count
+	d.centroids = append(d.centroids, c)
+	d.sorted = false
+}
+
+// merge merges src into d, the exact values are kept if both of them only have exact values of the same kind.
+func (d *tDigest) merge(src *tDigest) error {
+	if len(src.centroids) == 0 && d.canKeepExact(len(src.ints), len(src.decimals)) {
+		d.ints = append(d.ints, src.ints...)
+		d.decimals = append(d.decimals, src.decimals...)
+		if len(d.ints)+len(d.decimals) > tDigestMaxCentroids {
+			return d.flushExact()
+		}
+		return nil
+	}
+	if err := d.flushExact(); err != nil {
+		return err
+	}
+	if err := src.flushExact(); err != nil {
+		return err
+	}
+	if src.count == 0 {
+		return nil
+	}
+	min, max := src.min, src.max
+	if d.count > 0 {
+		min, max = math.Min(min, d.min), math.Max(max, d.max)
+	}
+	for _, c := range src.centroids {
+		d.addCentroid(c)
+	}
+	// addCentroid tracks the means, which are not the extremes of centroids holding several values.
+	d.min, d.max = min, max
+	if len(d.centroids) > tDigestMaxCentroids {
+		d.compress()
+	}
+	return nil
+}
+
+func (d *tDigest) sort() {
+	if d.sorted {
+		return
+	}
+	sort.Slice(d.centroids, func(i, j int) bool {
+		return d.centroids[i].mean < d.centroids[j].mean
+	})
+	d.sorted = true
+}
+
+// tDigestScale is the k1 scale function of t-digest, it maps a quantile to the index of a centroid.
+func tDigestScale(q float64) float64 {
+	return tDigestCompression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+// tDigestQuantileLimit is the inverse of tDigestScale.
+func tDigestQuantileLimit(k float64) float64 {
+	if k >= tDigestCompression/4 {
+		return 1
+	}
+	return (math.Sin(k*2*math.Pi/tDigestCompression) + 1) / 2
+}
+
+// compress merges adjacent centroids as long as the merged ones still fit in
+// one unit of the scale function.
+func (d *tDigest) compress() {
+	d.sort()
+	if len(d.centroids) <= 1 {
+		return
+	}
+	total := float64(d.count)
+	merged := d.centroids[:1]
+	cur := &merged[0]
+	soFar := float64(0)
+	limit := tDigestQuantileLimit(tDigestScale(0) + 1)
+	for _, c := range d.centroids[1:] {
+		if (soFar+float64(cur.count+c.count))/total <= limit {
+			cur.count += c.count
+			cur.mean += (c.mean - cur.mean) * float64(c.count) / float64(cur.count)
+			continue
+		}
+		soFar += float64(cur.count)
+		limit = tDigestQuantileLimit(tDigestScale(soFar/total) + 1)
+		merged = append(merged, c)
+		cur = &merged[len(merged)-1]
+	}
+	d.centroids = merged
+}
+
+// quantile returns the estimated value at the ordinal rank ceil(percent/100*count),
+// which is the mean of the centroid containing the rank.
+func (d *tDigest) quantile(percent int) float64 {
+	rank := int64(math.Ceil(float64(d.count) / 100 * float64(percent)))
+	if rank <= 1 {
+		return d.min
+	}
+	if rank >= d.count {
+		return d.max
+	}
+	d.sort()
+	soFar := int64(0)
+	for _, c := range d.centroids {
+		soFar += c.count
+		if soFar >= rank {
+			return math.Max(d.min, math.Min(d.max, c.mean))
+		}
+	}
+	return d.max
+}
+
+// serialize encodes the digest as: min, max, number of centroids, the mean and count of each centroid,
+// then the kind and number of the exact values followed by the values. An int is encoded as 8 bytes,
+// a decimal is encoded as its precision, frac and binary form.
+func (d *tDigest) serialize() ([]byte, error) {
+	buf := make([]byte, 24+16*len(d.centroids), 24+16*len(d.centroids)+9+8*len(d.ints))
+	binary.LittleEndian.PutUint64(buf, math.Float64bits(d.min))
+	binary.LittleEndian.PutUint64(buf[8:], math.Float64bits(d.max))
+	binary.LittleEndian.PutUint64(buf[16:], uint64(len(d.centroids)))
+	for i, c := range d.centroids {
+		binary.LittleEndian.PutUint64(buf[24+16*i:], math.Float64bits(c.mean))
+		binary.LittleEndian.PutUint64(buf[32+16*i:], uint64(c.count))
+	}
+	switch {
+	case len(d.ints) > 0:
+		buf = append(buf, tDigestExactInt)
+		buf = appendUint64(buf, uint64(len(d.ints)))
+		for _, x := range d.ints {
+			buf = appendUint64(buf, uint64(x))
+		}
+	case len(d.decimals) > 0:
+		buf = append(buf, tDigestExactDecimal)
+		buf = appendUint64(buf, uint64(len(d.decimals)))
+		for i := range d.decimals {
+			precision, frac := d.decimals[i].PrecisionAndFrac()
+			bin, err := d.decimals[i].ToBin(precision, frac)
+			if err != nil {
+				return nil, err
+			}
+			buf = append(buf, byte(precision), byte(frac))
+			buf = append(buf, bin...)
+		}
+	default:
+		buf = append(buf, tDigestExactNone)
+		buf = appendUint64(buf, 0)
+	}
+	return buf, nil
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	return append(buf, b[:]...)
+}
+
+// readAndMerge decodes a digest encoded by serialize and merges it into d.
+func (d *tDigest) readAndMerge(buf []byte) error {
+	if len(buf) < 24 {
+		return errors.New("Cannot read tDigest: unexpected end of data")
+	}
+	n := binary.LittleEndian.Uint64(buf[16:])
+	if n > tDigestMaxCentroids || uint64(len(buf)) < 24+16*n+9 {
+		return errors.New("Cannot read tDigest: invalid number of centroids")
+	}
+	src := &tDigest{
+		centroids: make([]tDigestCentroid, n),
+		min:       math.Float64frombits(binary.LittleEndian.Uint64(buf)),
+		max:       math.Float64frombits(binary.LittleEndian.Uint64(buf[8:])),
+	}
+	for i := range src.centroids {
+		c := &src.centroids[i]
+		c.mean = math.Float64frombits(binary.LittleEndian.Uint64(buf[24+16*i:]))
+		c.count = int64(binary.LittleEndian.Uint64(buf[32+16*i:]))
+		if c.count <= 0 {
+			return errors.New("Cannot read tDigest: invalid centroid")
+		}
+		src.count += c.count
+	}
+	buf = buf[24+16*n:]
+	kind, numExact := buf[0], binary.LittleEndian.Uint64(buf[1:])
+	buf = buf[9:]
+	if numExact > tDigestMaxCentroids {
+		return errors.New("Cannot read tDigest: invalid number of exact values")
+	}
+	switch kind {
+	case tDigestExactNone:
+		if numExact != 0 {
+			return errors.New("Cannot read tDigest: invalid number of exact values")
+		}
+	case tDigestExactInt:
+		if uint64(len(buf)) != 8*numExact {
+			return errors.New("Cannot read tDigest: invalid number of exact values")
+		}
+		src.ints = make([]int64, numExact)
+		for i := range src.ints {
+			src.ints[i] = int64(binary.LittleEndian.Uint64(buf[8*i:]))
+		}
+		buf = nil
+	case tDigestExactDecimal:
+		src.decimals = make([]types.MyDecimal, numExact)
+		for i := range src.decimals {
+			if len(buf) < 2 {
+				return errors.New("Cannot read tDigest: unexpected end of data")
+			}
+			precision, frac := int(buf[0]), int(buf[1])
+			if precision == 0 || precision > mysql.MaxDecimalWidth || frac > precision || len(buf)-2 < types.DecimalBinSize(precision, frac) {
+				return errors.New("Cannot read tDigest: invalid decimal")
+			}
+			binSize, err := src.decimals[i].FromBin(buf[2:], precision, frac)
+			if err != nil {
+				return err
+			}
+			buf = buf[2+binSize:]
+		}
+	default:
+		return errors.New("Cannot read tDigest: invalid kind of exact values")
+	}
+	if len(buf) != 0 {
+		return errors.New("Cannot read tDigest: unexpected trailing data")
+	}
+	return d.merge(src)
+}
@@ -79,6 +79,26 @@ func (s *testSuite) testWindowFunc(c *C, p windowTest) {
 		resultChk.Reset()
 	}
 	finalFunc.ResetPartialResult(finalPr)
+
+	batchFunc, ok := finalFunc.(aggfuncs.BatchWindowFunc)
+	if !ok {
+		return
+	}
+	// Append the results in two batches, they should be the same as appending them one by one.
+	for row := iter.Begin(); row != iter.End(); row = iter.Next() {
+		_, err = finalFunc.UpdatePartialResult(s.ctx, []chunk.Row{row}, finalPr)
+		c.Assert(err, IsNil)
+	}
+	c.Assert(batchFunc.AppendFinalResults2Chunk(s.ctx, finalPr, resultChk, p.numRows/2), IsNil)
+	c.Assert(batchFunc.AppendFinalResults2Chunk(s.ctx, finalPr, resultChk, p.numRows-p.numRows/2), IsNil)
+	c.Assert(resultChk.NumRows(), Equals, p.numRows)
+	for i := 0; i < p.numRows; i++ {
+		dt := resultChk.GetRow(i).GetDatum(0, desc.RetTp)
+		result, err := dt.CompareDatum(s.ctx.GetSessionVars().StmtCtx, &p.results[i])
+		c.Assert(err, IsNil)
+		c.Assert(result, Equals, 0, Commentf("%s: row %d", p.funcName, i))
+	}
+	finalFunc.ResetPartialResult(finalPr)
 }
 
 func (s *testSuite) testWindowAggMemFunc(c *C, p windowMemTest) {
@@ -170,6 +190,7 @@ func (s *testSuite) TestWindowFunctions(c *C) {
 		buildWindowTester(ast.WindowFuncCumeDist, mysql.TypeLonglong, 0, 1, 1, 1),
 		buildWindowTester(ast.WindowFuncCumeDist, mysql.TypeLonglong, 0, 0, 2, 1, 1),
 		buildWindowTester(ast.WindowFuncCumeDist, mysql.TypeLonglong, 0, 1, 4, 0.25, 0.5, 0.75, 1),
+		buildWindowTester(ast.WindowFuncCumeDist, mysql.TypeLonglong, 0, 0, 3, 1, 1, 1),
 
 		buildWindowTester(ast.WindowFuncDenseRank, mysql.TypeLonglong, 0, 0, 2, 1, 1),
 		buildWindowTester(ast.WindowFuncDenseRank, mysql.TypeLonglong, 0, 1, 4, 1, 2, 3, 4),
@@ -190,6 +211,7 @@ func (s *testSuite) TestWindowFunctions(c *C) {
 
 		buildWindowTester(ast.WindowFuncNtile, mysql.TypeLonglong, 3, 0, 4, 1, 1, 2, 3),
 		buildWindowTester(ast.WindowFuncNtile, mysql.TypeLonglong, 5, 0, 3, 1, 2, 3),
+		buildWindowTester(ast.WindowFuncNtile, mysql.TypeLonglong, 3, 0, 11, 1, 1, 1, 1, 2, 2, 2, 2, 3, 3, 3),
 
 		buildWindowTester(ast.WindowFuncPercentRank, mysql.TypeLonglong, 0, 1, 1, 0),
 		buildWindowTester(ast.WindowFuncPercentRank, mysql.TypeLonglong, 0, 0, 3, 0, 0, 0),
@@ -51,6 +51,7 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/ranger"
 	"github.com/pingcap/tidb/util/sqlexec"
@@ -66,6 +67,17 @@ type AnalyzeExec struct {
 	tasks []*analyzeTask
 	wg    *sync.WaitGroup
 	opts  map[ast.AnalyzeOptionType]uint64
+	// tableOpts are the options of each table, which replace opts for the tables having saved options.
+	tableOpts map[int64]map[ast.AnalyzeOptionType]uint64
+	// savedOpts are the options specified by the statement, which are saved for the analyzed tables.
+	savedOpts map[ast.AnalyzeOptionType]uint64
+}
+
+func (e *AnalyzeExec) optsOfTable(tableID int64) map[ast.AnalyzeOptionType]uint64 {
+	if opts, ok := e.tableOpts[tableID]; ok {
+		return opts
+	}
+	return e.opts
 }
 
 var (
@@ -91,14 +103,19 @@ func (e *AnalyzeExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	for i := 0; i < concurrency; i++ {
 		go e.analyzeWorker(taskCh, resultCh, i == 0)
 	}
+	statsHandle := domain.GetDomain(e.ctx).StatsHandle()
 	for _, task := range e.tasks {
 		statistics.AddNewAnalyzeJob(task.job)
+		if err := statsHandle.InsertAnalyzeJob(task.job); err != nil {
+			logutil.Logger(ctx).Warn("persist the analyze job failed", zap.Error(err))
+		}
 	}
+	progressDone := make(chan struct{})
+	go e.dumpAnalyzeJobsProgress(ctx, progressDone)
 	for _, task := range e.tasks {
 		taskCh <- task
 	}
 	close(taskCh)
-	statsHandle := domain.GetDomain(e.ctx).StatsHandle()
 	panicCnt := 0
 
 	pruneMode := variable.PartitionPruneMode(e.ctx.GetSessionVars().PartitionPruneMode.Load())
@@ -119,8 +136,12 @@ func (e *AnalyzeExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	// The meaning of key in map is the structure that used to store the tableID and indexID.
 	// The meaning of value in map is some additional information needed to build global-level stats.
 	globalStatsMap := make(map[globalStatsKey]globalStatsInfo)
+	analyzedTables := make(map[int64]struct{})
 	finishJobWithLogFn := func(ctx context.Context, job *statistics.AnalyzeJob, meetError bool) {
 		job.Finish(meetError)
+		if err := statsHandle.FinishAnalyzeJob(job); err != nil {
+			logutil.Logger(ctx).Warn("persist the state of the analyze job failed", zap.Error(err))
+		}
 		if job != nil {
 			logutil.Logger(ctx).Info(fmt.Sprintf("analyze table `%s`.`%s` has %s", job.DBName, job.TableName, job.State),
 				zap.String("partition", job.PartitionName),
@@ -145,6 +166,7 @@ func (e *AnalyzeExec) Next(ctx context.Context, req *chunk.Chunk) error {
 			finishJobWithLogFn(ctx, result.job, true)
 			continue
 		}
+		analyzedTables[result.TableID.TableID] = struct{}{}
 		statisticsID := result.TableID.GetStatisticsID()
 		for i, hg := range result.Hist {
 			if result.TableID.IsPartitionTable() && needGlobalStats {
@@ -174,15 +196,26 @@ func (e *AnalyzeExec) Next(ctx context.Context, req *chunk.Chunk) error {
 			finishJobWithLogFn(ctx, result.job, false)
 		}
 	}
+	close(progressDone)
 	for _, task := range e.tasks {
 		statistics.MoveToHistory(task.job)
 	}
 	if err != nil {
 		return err
 	}
+	if len(e.savedOpts) > 0 && len(analyzedTables) > 0 {
+		tableIDs := make([]int64, 0, len(analyzedTables))
+		for tableID := range analyzedTables {
+			tableIDs = append(tableIDs, tableID)
+		}
+		if err := statsHandle.SaveAnalyzeOptions(tableIDs, e.savedOpts); err != nil {
+			return err
+		}
+	}
 	if needGlobalStats {
+		partitionVersions := make(map[int64]map[int64]uint64)
 		for globalStatsID, info := range globalStatsMap {
-			globalStats, err := statsHandle.MergePartitionStats2GlobalStatsByTableID(e.ctx, e.opts, infoschema.GetInfoSchema(e.ctx), globalStatsID.tableID, info.isIndex, info.idxID)
+			globalStats, err := statsHandle.MergePartitionStats2GlobalStatsByTableID(e.ctx, e.optsOfTable(globalStatsID.tableID), infoschema.GetInfoSchema(e.ctx), globalStatsID.tableID, info.isIndex, info.idxID)
 			if err != nil {
 				if types.ErrPartitionStatsMissing.Equal(err) {
 					// When we find some partition-level stats are missing, we need to report warning.
@@ -198,11 +231,51 @@ func (e *AnalyzeExec) Next(ctx context.Context, req *chunk.Chunk) error {
 					logutil.Logger(ctx).Error("save global-level stats to storage failed", zap.Error(err))
 				}
 			}
+			versions, ok := partitionVersions[globalStatsID.tableID]
+			if !ok {
+				versions = make(map[int64]uint64, len(globalStats.PartitionVersions))
+				partitionVersions[globalStatsID.tableID] = versions
+			}
+			for partitionID, version := range globalStats.PartitionVersions {
+				if version > versions[partitionID] {
+					versions[partitionID] = version
+				}
+			}
+		}
+		for tableID, versions := range partitionVersions {
+			if err := statsHandle.SaveGlobalStatsVersions(tableID, versions); err != nil {
+				logutil.Logger(ctx).Error("save versions of the merged partition-level stats failed", zap.Error(err))
+			}
 		}
 	}
 	return statsHandle.Update(infoschema.GetInfoSchema(e.ctx))
 }
 
+// analyzeProgressDumpInterval is the interval to persist the progress of the running analyze jobs.
+var analyzeProgressDumpInterval = 3 * time.Second
+
+// dumpAnalyzeJobsProgress persists the progress of the running analyze jobs periodically until done is closed.
+func (e *AnalyzeExec) dumpAnalyzeJobsProgress(ctx context.Context, done <-chan struct{}) {
+	statsHandle := domain.GetDomain(e.ctx).StatsHandle()
+	ticker := time.NewTicker(analyzeProgressDumpInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			for _, task := range e.tasks {
+				if !task.job.IsRunning() {
+					continue
+				}
+				if err := statsHandle.UpdateAnalyzeJobProgress(task.job); err != nil {
+					logutil.Logger(ctx).Warn("persist the progress of the analyze job failed", zap.Error(err))
+				}
+			}
+		}
+	}
+}
+
 func getBuildStatsConcurrency(ctx sessionctx.Context) (int, error) {
 	sessionVars := ctx.GetSessionVars()
 	concurrency, err := variable.GetSessionSystemVar(sessionVars, variable.TiDBBuildStatsConcurrency)
@@ -221,6 +294,7 @@ const (
 	fastTask
 	pkIncrementalTask
 	idxIncrementalTask
+	virtualColTask
 )
 
 type analyzeTask struct {
@@ -230,6 +304,7 @@ type analyzeTask struct {
 	fastExec           *AnalyzeFastExec
 	idxIncrementalExec *analyzeIndexIncrementalExec
 	colIncrementalExec *analyzePKIncrementalExec
+	virtualColExec     *AnalyzeVirtualColumnsExec
 	job                *statistics.AnalyzeJob
 }
 
@@ -283,6 +358,9 @@ func (e *AnalyzeExec) analyzeWorker(taskCh <-chan *analyzeTask, resultCh chan<-
 		case idxIncrementalTask:
 			task.idxIncrementalExec.job = task.job
 			resultCh <- analyzeIndexIncremental(task.idxIncrementalExec)
+		case virtualColTask:
+			task.virtualColExec.job = task.job
+			resultCh <- analyzeVirtualColumns(task.virtualColExec)
 		}
 	}
 }
@@ -732,12 +810,48 @@ func (e *AnalyzeColumnsExec) buildStats(ranges []*ranger.Range, needExtStats boo
 		topNs = append(topNs, nil)
 		fms = append(fms, nil)
 	}
-	for i, col := range e.colsInfo {
-		if e.analyzeVer < 2 {
+	colHists, colCms, colTopNs, colFms, err := buildColumnsStatsFromSamples(e.ctx, e.colsInfo, collectors, e.opts, e.analyzeVer)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+	hists = append(hists, colHists...)
+	cms = append(cms, colCms...)
+	topNs = append(topNs, colTopNs...)
+	fms = append(fms, colFms...)
+	if needExtStats {
+		statsHandle := domain.GetDomain(e.ctx).StatsHandle()
+		extStats, err = statsHandle.BuildExtendedStats(e.tableID.GetStatisticsID(), e.colsInfo, collectors)
+		if err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
+	}
+	if handleHist != nil {
+		handleHist.ID = e.commonHandle.ID
+		if handleTopn != nil && handleTopn.TotalCount() > 0 {
+			handleHist.RemoveVals(handleTopn.TopN)
+		}
+		if handleCms != nil {
+			handleCms.CalcDefaultValForAnalyze(uint64(handleHist.NDV))
+		}
+		hists = append([]*statistics.Histogram{handleHist}, hists...)
+		cms = append([]*statistics.CMSketch{handleCms}, cms...)
+		fms = append([]*statistics.FMSketch{handleFms}, fms...)
+		topNs = append([]*statistics.TopN{handleTopn}, topNs...)
+	}
+	return hists, cms, topNs, fms, extStats, nil
+}
+
+// buildColumnsStatsFromSamples builds the column stats from the sample collectors whose samples are encoded values.
+func buildColumnsStatsFromSamples(ctx sessionctx.Context, colsInfo []*model.ColumnInfo, collectors []*statistics.SampleCollector,
+	opts map[ast.AnalyzeOptionType]uint64, analyzeVer int) (hists []*statistics.Histogram, cms []*statistics.CMSketch,
+	topNs []*statistics.TopN, fms []*statistics.FMSketch, err error) {
+	timeZone := ctx.GetSessionVars().Location()
+	for i, col := range colsInfo {
+		if analyzeVer < 2 {
 			// In analyze version 2, we don't collect TopN this way. We will collect TopN from samples in `BuildColumnHistAndTopN()` below.
-			err := collectors[i].ExtractTopN(uint32(e.opts[ast.AnalyzeOptNumTopN]), e.ctx.GetSessionVars().StmtCtx, &col.FieldType, timeZone)
+			err := collectors[i].ExtractTopN(uint32(opts[ast.AnalyzeOptNumTopN]), ctx.GetSessionVars().StmtCtx, &col.FieldType, timeZone)
 			if err != nil {
-				return nil, nil, nil, nil, nil, err
+				return nil, nil, nil, nil, err
 			}
 			topNs = append(topNs, collectors[i].TopN)
 		}
@@ -745,7 +859,7 @@ func (e *AnalyzeColumnsExec) buildStats(ranges []*ranger.Range, needExtStats boo
 			collectors[i].Samples[j].Ordinal = j
 			collectors[i].Samples[j].Value, err = tablecodec.DecodeColumnValue(s.Value.GetBytes(), &col.FieldType, timeZone)
 			if err != nil {
-				return nil, nil, nil, nil, nil, err
+				return nil, nil, nil, nil, err
 			}
 			// When collation is enabled, we store the Key representation of the sampling data. So we set it to kind `Bytes` here
 			// to avoid to convert it to its Key representation once more.
@@ -756,41 +870,141 @@ func (e *AnalyzeColumnsExec) buildStats(ranges []*ranger.Range, needExtStats boo
 		var hg *statistics.Histogram
 		var err error
 		var topn *statistics.TopN
-		if e.analyzeVer < 2 {
-			hg, err = statistics.BuildColumn(e.ctx, int64(e.opts[ast.AnalyzeOptNumBuckets]), col.ID, collectors[i], &col.FieldType)
+		if analyzeVer < 2 {
+			hg, err = statistics.BuildColumn(ctx, int64(opts[ast.AnalyzeOptNumBuckets]), col.ID, collectors[i], &col.FieldType)
 		} else {
-			hg, topn, err = statistics.BuildColumnHistAndTopN(e.ctx, int(e.opts[ast.AnalyzeOptNumBuckets]), int(e.opts[ast.AnalyzeOptNumTopN]), col.ID, collectors[i], &col.FieldType)
+			hg, topn, err = statistics.BuildColumnHistAndTopN(ctx, int(opts[ast.AnalyzeOptNumBuckets]), int(opts[ast.AnalyzeOptNumTopN]), col.ID, collectors[i], &col.FieldType)
 			topNs = append(topNs, topn)
 		}
 		if err != nil {
-			return nil, nil, nil, nil, nil, err
+			return nil, nil, nil, nil, err
 		}
 		hists = append(hists, hg)
 		collectors[i].CMSketch.CalcDefaultValForAnalyze(uint64(hg.NDV))
 		cms = append(cms, collectors[i].CMSketch)
 		fms = append(fms, collectors[i].FMSketch)
 	}
-	if needExtStats {
-		statsHandle := domain.GetDomain(e.ctx).StatsHandle()
-		extStats, err = statsHandle.BuildExtendedStats(e.tableID.GetStatisticsID(), e.colsInfo, collectors)
-		if err != nil {
-			return nil, nil, nil, nil, nil, err
+	return hists, cms, topNs, fms, nil
+}
+
+// AnalyzeVirtualColumnsExec represents Analyze virtual generated columns executor. The values of the virtual columns
+// are not stored in TiKV, so they are evaluated by scanning the table in TiDB.
+type AnalyzeVirtualColumnsExec struct {
+	ctx           sessionctx.Context
+	tableID       core.AnalyzeTableID
+	dbName        string
+	tableName     string
+	partitionName string
+	colsInfo      []*model.ColumnInfo
+	opts          map[ast.AnalyzeOptionType]uint64
+	analyzeVer    int
+	job           *statistics.AnalyzeJob
+}
+
+func analyzeVirtualColumns(e *AnalyzeVirtualColumnsExec) analyzeResult {
+	collectors, err := e.collectSamples()
+	if err != nil {
+		return analyzeResult{Err: err, job: e.job}
+	}
+	hists, cms, topNs, fms, err := buildColumnsStatsFromSamples(e.ctx, e.colsInfo, collectors, e.opts, e.analyzeVer)
+	if err != nil {
+		return analyzeResult{Err: err, job: e.job}
+	}
+	return analyzeResult{
+		TableID:  e.tableID,
+		Hist:     hists,
+		Cms:      cms,
+		TopNs:    topNs,
+		Fms:      fms,
+		Count:    collectors[0].Count + collectors[0].NullCount,
+		job:      e.job,
+		StatsVer: e.analyzeVer,
+	}
+}
+
+// collectSamples evaluates the virtual columns of all the rows by an internal query, and collects the samples in the
+// same encoded format as the samples collected by the coprocessor.
+func (e *AnalyzeVirtualColumnsExec) collectSamples() (_ []*statistics.SampleCollector, err error) {
+	dom := domain.GetDomain(e.ctx)
+	res, err := dom.SysSessionPool().Get()
+	if err != nil {
+		return nil, err
+	}
+	sctx := res.(sessionctx.Context)
+	exec := sctx.(sqlexec.SQLExecutor)
+	defer func() {
+		if _, err1 := exec.ExecuteInternal(context.TODO(), "rollback"); err1 != nil {
+			res.Close()
+			return
+		}
+		dom.SysSessionPool().Put(res)
+	}()
+	sctx.GetSessionVars().InRestrictedSQL = true
+
+	var sql strings.Builder
+	sqlexec.MustFormatSQL(&sql, "select ")
+	for i, col := range e.colsInfo {
+		if i > 0 {
+			sqlexec.MustFormatSQL(&sql, ", ")
 		}
+		sqlexec.MustFormatSQL(&sql, "%n", col.Name.O)
 	}
-	if handleHist != nil {
-		handleHist.ID = e.commonHandle.ID
-		if handleTopn != nil && handleTopn.TotalCount() > 0 {
-			handleHist.RemoveVals(handleTopn.TopN)
+	sqlexec.MustFormatSQL(&sql, " from %n.%n", e.dbName, e.tableName)
+	if e.partitionName != "" {
+		sqlexec.MustFormatSQL(&sql, " partition(%n)", e.partitionName)
+	}
+	ctx := context.TODO()
+	rs, err := exec.ExecuteInternal(ctx, sql.String())
+	if err != nil {
+		return nil, err
+	}
+	defer terror.Call(rs.Close)
+
+	collectors := make([]*statistics.SampleCollector, len(e.colsInfo))
+	collators := make([]collate.Collator, len(e.colsInfo))
+	for i, col := range e.colsInfo {
+		collectors[i] = &statistics.SampleCollector{
+			MaxSampleSize: int64(e.opts[ast.AnalyzeOptNumSamples]),
+			FMSketch:      statistics.NewFMSketch(maxSketchSize),
+			CMSketch:      statistics.NewCMSketch(int32(e.opts[ast.AnalyzeOptCMSketchDepth]), int32(e.opts[ast.AnalyzeOptCMSketchWidth])),
 		}
-		if handleCms != nil {
-			handleCms.CalcDefaultValForAnalyze(uint64(handleHist.NDV))
+		if col.EvalType() == types.ETString {
+			collators[i] = collate.GetCollator(col.Collate)
 		}
-		hists = append([]*statistics.Histogram{handleHist}, hists...)
-		cms = append([]*statistics.CMSketch{handleCms}, cms...)
-		fms = append([]*statistics.FMSketch{handleFms}, fms...)
-		topNs = append([]*statistics.TopN{handleTopn}, topNs...)
 	}
-	return hists, cms, topNs, fms, extStats, nil
+	sc := e.ctx.GetSessionVars().StmtCtx
+	req := rs.NewChunk()
+	for {
+		if err = rs.Next(ctx, req); err != nil {
+			return nil, err
+		}
+		if req.NumRows() == 0 {
+			break
+		}
+		for row := 0; row < req.NumRows(); row++ {
+			for i, col := range e.colsInfo {
+				d := req.GetRow(row).GetDatum(i, &col.FieldType)
+				if !d.IsNull() {
+					// Keep the same format as the samples collected by the coprocessor, which are the encoded values
+					// and the collation keys for the strings.
+					if collators[i] != nil {
+						d.SetBytesAsString(collators[i].Key(d.GetString()), d.Collation(), uint32(d.Length()))
+					}
+					encoded, err := tablecodec.EncodeValue(sc, nil, d)
+					if err != nil {
+						return nil, err
+					}
+					d = types.NewBytesDatum(encoded)
+				}
+				if err = collectors[i].Collect(sc, d); err != nil {
+					return nil, err
+				}
+			}
+		}
+		e.job.Update(int64(req.NumRows()))
+		req = chunk.Renew(req, e.ctx.GetSessionVars().MaxChunkSize)
+	}
+	return collectors, nil
 }
 
 func hasPkHist(handleCols core.HandleCols) bool {
@@ -174,6 +174,7 @@ func (s *testSuite1) TestAnalyzeParameters(c *C) {
 	}
 	tk.MustExec("insert into t values (19), (19), (19)")
 
+	tk.MustExec("set @@tidb_persist_analyze_options = 0")
 	tk.MustExec("set @@tidb_enable_fast_analyze = 1")
 	tk.MustExec("analyze table t with 30 samples")
 	is := infoschema.GetInfoSchema(tk.Se.(sessionctx.Context))
@@ -218,6 +219,135 @@ func (s *testSuite1) TestAnalyzeParameters(c *C) {
 	c.Assert(width, Equals, int32(20480))
 }
 
+func (s *testSuite1) TestAnalyzePersistedOptions(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, index idx(a))")
+	for i := 0; i < 20; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d)", i))
+	}
+	tk.MustExec("insert into t values (19), (19), (19)")
+	is := infoschema.GetInfoSchema(tk.Se.(sessionctx.Context))
+	table, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tableInfo := table.Meta()
+	h := s.dom.StatsHandle()
+	checkStats := func(buckets int, topN int) {
+		tbl := h.GetTableStats(tableInfo)
+		col, idx := tbl.Columns[1], tbl.Indices[1]
+		c.Assert(col.Len(), Equals, buckets)
+		c.Assert(idx.Len() <= buckets, IsTrue)
+		if topN == 0 {
+			c.Assert(col.TopN, IsNil)
+		} else {
+			c.Assert(len(col.TopN.TopN), Equals, topN)
+		}
+	}
+
+	tk.MustExec("analyze table t with 4 buckets, 0 topn")
+	checkStats(4, 0)
+	tk.MustQuery(fmt.Sprintf("select buckets, topn from mysql.analyze_options where table_id = %d", tableInfo.ID)).Check(testkit.Rows("4 0"))
+
+	// The options saved for the table are reused if they are not specified.
+	tk.MustExec("analyze table t")
+	checkStats(4, 0)
+	tk.MustExec("analyze table t index idx")
+	checkStats(4, 0)
+	tk.MustExec("analyze table t with 1 topn")
+	checkStats(4, 1)
+	tk.MustQuery(fmt.Sprintf("select buckets, topn from mysql.analyze_options where table_id = %d", tableInfo.ID)).Check(testkit.Rows("4 1"))
+
+	// The auto analyze reuses the saved options as well.
+	handle.AutoAnalyzeMinCnt = 0
+	tk.MustExec("set global tidb_auto_analyze_ratio = 0.2")
+	defer func() {
+		handle.AutoAnalyzeMinCnt = 1000
+		tk.MustExec("set global tidb_auto_analyze_ratio = 0.0")
+	}()
+	for i := 20; i < 40; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d)", i))
+	}
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	c.Assert(h.Update(is), IsNil)
+	c.Assert(h.HandleAutoAnalyze(is), IsTrue)
+	c.Assert(h.Update(is), IsNil)
+	c.Assert(h.GetTableStats(tableInfo).Count, Equals, int64(43))
+	checkStats(4, 1)
+
+	// The options aren't saved or reused if tidb_persist_analyze_options is off.
+	tk.MustExec("set @@tidb_persist_analyze_options = 0")
+	tk.MustExec("analyze table t with 8 buckets")
+	checkStats(8, 1)
+	tk.MustQuery(fmt.Sprintf("select buckets, topn from mysql.analyze_options where table_id = %d", tableInfo.ID)).Check(testkit.Rows("4 1"))
+	tk.MustExec("analyze table t")
+	c.Assert(h.GetTableStats(tableInfo).Columns[1].Len() > 8, IsTrue)
+}
+
+func (s *testSuite1) TestAnalyzeSampleRate(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int)")
+	values := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		values = append(values, fmt.Sprintf("(%d)", i))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+	_, err := tk.Exec("set @@tidb_analyze_sample_rate = 1.5")
+	c.Assert(err, NotNil)
+
+	tk.MustExec("analyze table t")
+	is := infoschema.GetInfoSchema(tk.Se.(sessionctx.Context))
+	table, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tableInfo := table.Meta()
+	tbl := s.dom.StatsHandle().GetTableStats(tableInfo)
+	c.Assert(tbl.Count, Equals, int64(1000))
+	c.Assert(tbl.Columns[1].Len() > 200, IsTrue)
+
+	// 1000 * 0.02 = 20 rows are sampled, so the histogram has 20 buckets at most.
+	tk.MustExec("set @@tidb_analyze_sample_rate = 0.02")
+	tk.MustExec("analyze table t")
+	tbl = s.dom.StatsHandle().GetTableStats(tableInfo)
+	c.Assert(tbl.Count, Equals, int64(1000))
+	c.Assert(tbl.Columns[1].Len() <= 20, IsTrue)
+	c.Assert(tbl.Columns[1].Histogram.NDV, Equals, int64(1000))
+
+	// The SAMPLES option takes precedence over the sample rate.
+	tk.MustExec("analyze table t with 10000 samples")
+	tbl = s.dom.StatsHandle().GetTableStats(tableInfo)
+	c.Assert(tbl.Columns[1].Len() > 200, IsTrue)
+}
+
+func (s *testSuite1) TestAnalyzeVirtualColumns(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b varchar(20), c varchar(20) as (lower(b)) virtual)")
+	values := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		values = append(values, fmt.Sprintf("(%d, 'A%d')", i, i%10))
+	}
+	tk.MustExec("insert into t(a, b) values " + strings.Join(values, ","))
+	tk.MustExec("analyze table t")
+	is := infoschema.GetInfoSchema(tk.Se.(sessionctx.Context))
+	table, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tableInfo := table.Meta()
+	tbl := s.dom.StatsHandle().GetTableStats(tableInfo)
+	col := tbl.Columns[tableInfo.Columns[2].ID]
+	c.Assert(col.Histogram.NDV, Equals, int64(10))
+	c.Assert(col.TotalRowCount(), Equals, float64(100))
+
+	// The stats of the virtual column are used for the predicates over both the column and its expression.
+	c.Assert(s.dom.StatsHandle().LoadNeededHistograms(), IsNil)
+	rows := tk.MustQuery("explain format = 'brief' select * from t where c = 'a1' order by a").Rows()
+	c.Assert(rows[0][1], Equals, "10.00")
+	rows = tk.MustQuery("explain format = 'brief' select * from t where lower(b) = 'a1' order by a").Rows()
+	c.Assert(rows[0][1], Equals, "10.00")
+}
+
 func (s *testSuite1) TestAnalyzeTooLongColumns(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -0,0 +1,227 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/auth"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/privilege/privileges"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/passwordvalidation"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// authPluginsOfUserSpecs returns the authentication plugins of the user specs of CREATE USER or ALTER USER, the
+// plugin is empty if it isn't specified. The parser drops the plugin of `IDENTIFIED WITH plugin`, so it's recovered
+// from the statement text, in which the user specs are separated by the top-level commas.
+func authPluginsOfUserSpecs(sctx sessionctx.Context, specs []*ast.UserSpec) ([]string, error) {
+	plugins := make([]string, len(specs))
+	tokens := tokenizeUserStmt(sctx.GetSessionVars().StmtCtx.OriginalSQL)
+	spec, depth := 0, 0
+	for i, tok := range tokens {
+		switch {
+		case tok == "(":
+			depth++
+		case tok == ")":
+			depth--
+		case tok == "," && depth == 0:
+			spec++
+		case strings.EqualFold(tok, "IDENTIFIED") && i+2 < len(tokens) && strings.EqualFold(tokens[i+1], "WITH") && spec < len(plugins):
+			plugins[spec] = strings.ToLower(strings.Trim(tokens[i+2], "'\"`"))
+		}
+	}
+	for _, plugin := range plugins {
+		switch plugin {
+		case "", mysql.AuthNativePassword, mysql.AuthCachingSha2Password, privileges.AuthLDAPSimple, privileges.AuthLDAPSASL:
+		default:
+			return nil, ErrPluginIsNotLoaded.GenWithStackByArgs(plugin)
+		}
+	}
+	return plugins, nil
+}
+
+// tokenizeUserStmt splits the statement into the identifiers, the quoted strings and the punctuations, the comments
+// are skipped except the content of the executable comments.
+func tokenizeUserStmt(sql string) []string {
+	var tokens []string
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '#' || (strings.HasPrefix(sql[i:], "--") && (i+2 == len(sql) || sql[i+2] <= ' ')):
+			if end := strings.IndexByte(sql[i:], '\n'); end >= 0 {
+				i += end + 1
+			} else {
+				i = len(sql)
+			}
+		case strings.HasPrefix(sql[i:], "/*!"):
+			// The content of an executable comment is a part of the statement, only skip its version.
+			i += 3
+			for i < len(sql) && sql[i] >= '0' && sql[i] <= '9' {
+				i++
+			}
+		case strings.HasPrefix(sql[i:], "/*"):
+			if end := strings.Index(sql[i+2:], "*/"); end >= 0 {
+				i += end + 4
+			} else {
+				i = len(sql)
+			}
+		case strings.HasPrefix(sql[i:], "*/"):
+			// The end of an executable comment.
+			i += 2
+		case c == '\'' || c == '"' || c == '`':
+			j := i + 1
+			for j < len(sql) {
+				if sql[j] == '\\' && c != '`' {
+					j += 2
+					continue
+				}
+				if sql[j] == c {
+					if j+1 < len(sql) && sql[j+1] == c {
+						j += 2
+						continue
+					}
+					break
+				}
+				j++
+			}
+			if j >= len(sql) {
+				j = len(sql) - 1
+			}
+			tokens = append(tokens, sql[i:j+1])
+			i = j + 1
+		case isIdentifierChar(c):
+			j := i + 1
+			for j < len(sql) && isIdentifierChar(sql[j]) {
+				j++
+			}
+			tokens = append(tokens, sql[i:j])
+			i = j
+		default:
+			tokens = append(tokens, sql[i:i+1])
+			i++
+		}
+	}
+	return tokens
+}
+
+func isIdentifierChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '$' || c >= 0x80
+}
+
+// defaultAuthPlugin returns the authentication plugin of the users created without IDENTIFIED WITH.
+func defaultAuthPlugin(sctx sessionctx.Context) (string, error) {
+	return sctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.DefaultAuthPlugin)
+}
+
+// userAuthPlugin returns the authentication plugin of the existing user.
+func userAuthPlugin(sctx sessionctx.Context, name string, host string) (string, error) {
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(context.TODO(), `SELECT plugin FROM %n.%n WHERE User=%? AND Host=%?;`, mysql.SystemDB, mysql.UserTable, name, host)
+	if err != nil {
+		return "", err
+	}
+	rows, _, err := exec.ExecRestrictedStmt(context.TODO(), stmt)
+	if err != nil {
+		return "", err
+	}
+	if len(rows) == 0 || rows[0].IsNull(0) || rows[0].GetString(0) == "" {
+		return mysql.AuthNativePassword, nil
+	}
+	return rows[0].GetString(0), nil
+}
+
+// encodeUserPassword encodes the password of the user spec into the authentication string of the plugin.
+func encodeUserPassword(spec *ast.UserSpec, plugin string) (string, bool) {
+	if privileges.IsLDAPAuthPlugin(plugin) {
+		// The authentication string of the LDAP users is their DN and group mapping, given by either BY or AS.
+		if spec.AuthOpt == nil {
+			return "", true
+		}
+		authString := spec.AuthOpt.HashString
+		if spec.AuthOpt.ByAuthString {
+			authString = spec.AuthOpt.AuthString
+		}
+		return authString, privileges.ValidateLDAPAuthString(authString) == nil
+	}
+	if plugin != mysql.AuthCachingSha2Password {
+		return spec.EncodedPassword()
+	}
+	if spec.AuthOpt == nil {
+		return "", true
+	}
+	if spec.AuthOpt.ByAuthString {
+		return privileges.EncodeCachingSha2Password(spec.AuthOpt.AuthString), true
+	}
+	return spec.AuthOpt.HashString, privileges.IsValidCachingSha2Password(spec.AuthOpt.HashString)
+}
+
+// encodePassword encodes the cleartext password into the authentication string of the plugin.
+func encodePassword(pwd string, plugin string) string {
+	if plugin == mysql.AuthCachingSha2Password {
+		return privileges.EncodeCachingSha2Password(pwd)
+	}
+	return auth.EncodePassword(pwd)
+}
+
+// validateUserPassword checks the cleartext password of the user spec against the password policy, the hashed
+// passwords and the authentication strings of the LDAP users can't be checked.
+func validateUserPassword(sctx sessionctx.Context, spec *ast.UserSpec, plugin string) error {
+	if spec.AuthOpt == nil || !spec.AuthOpt.ByAuthString || privileges.IsLDAPAuthPlugin(plugin) {
+		return nil
+	}
+	return validatePassword(sctx, spec.User.Username, spec.AuthOpt.AuthString)
+}
+
+// validatePassword checks the cleartext password of the user against the password policy, the unsatisfied
+// requirement is appended as a warning to explain the error.
+func validatePassword(sctx sessionctx.Context, user string, pwd string) error {
+	policy, err := passwordvalidation.LoadPolicy(sctx.GetSessionVars().GlobalVarsAccessor)
+	if err != nil {
+		return err
+	}
+	if reason := policy.Validate(user, pwd); reason != "" {
+		sctx.GetSessionVars().StmtCtx.AppendWarning(errors.New(reason))
+		return ErrNotValidPassword.GenWithStackByArgs()
+	}
+	return nil
+}
+
+// checkUserPasswordReuse checks the cleartext password of the user spec against the password reuse policy, the
+// hashed passwords can't be checked, and the passwords of the LDAP users aren't restricted.
+func checkUserPasswordReuse(sctx sessionctx.Context, spec *ast.UserSpec, plugin string) error {
+	if spec.AuthOpt == nil || !spec.AuthOpt.ByAuthString || privileges.IsLDAPAuthPlugin(plugin) {
+		return nil
+	}
+	return checkPasswordReuse(sctx, spec.User.Username, spec.User.Hostname, spec.AuthOpt.AuthString)
+}
+
+// checkPasswordReuse checks whether the cleartext password is in the password history of the user.
+func checkPasswordReuse(sctx sessionctx.Context, user, host, pwd string) error {
+	ok, err := privileges.CheckPasswordReuse(sctx, user, host, pwd)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrCredentialsContradictToHistory.GenWithStackByArgs(user, host)
+	}
+	return nil
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/ddl/placement"
+	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
@@ -131,7 +132,13 @@ func (e *BatchPointGetExec) Open(context.Context) error {
 		})
 	}
 	var batchGetter kv.BatchGetter = snapshot
-	if txn.Valid() {
+	if tempTables := infoschema.NewTemporaryTableRetriever(e.ctx.GetSessionVars()); tempTables != nil && tempTables.IsTemporaryTable(e.tblInfo.ID) {
+		// The committed data of local temporary tables is kept in the session instead of the storage.
+		batchGetter = tempTables
+		if txn.Valid() {
+			batchGetter = driver.NewBufferBatchGetter(txn.GetMemBuffer(), nil, tempTables)
+		}
+	} else if txn.Valid() {
 		lock := e.tblInfo.Lock
 		if e.lock {
 			batchGetter = driver.NewBufferBatchGetter(txn.GetMemBuffer(), &PessimisticLockCacheGetter{txnCtx: txnCtx}, snapshot)
@@ -197,6 +204,9 @@ func datumsContainNull(vals []types.Datum) bool {
 }
 
 func (e *BatchPointGetExec) initialize(ctx context.Context) error {
+	if err := consumeReadRequestRU(ctx, e.ctx); err != nil {
+		return err
+	}
 	var handleVals map[string][]byte
 	var indexKeys []kv.Key
 	var err error
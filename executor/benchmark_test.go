@@ -804,6 +804,7 @@ func baseBenchmarkWindowFunctionsWithSlidingWindow(b *testing.B, frameType ast.F
 func BenchmarkWindowFunctionsWithSlidingWindow(b *testing.B) {
 	baseBenchmarkWindowFunctionsWithSlidingWindow(b, ast.Rows)
 	baseBenchmarkWindowFunctionsWithSlidingWindow(b, ast.Ranges)
+	baseBenchmarkWindowFunctionsWithSlidingWindow(b, ast.Groups)
 }
 
 type hashJoinTestCase struct {
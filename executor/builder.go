@@ -50,6 +50,7 @@ import (
 	"github.com/pingcap/tidb/util/admin"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/execdetails"
+	"github.com/pingcap/tidb/util/fulltext"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/ranger"
 	"github.com/pingcap/tidb/util/rowcodec"
@@ -150,6 +151,10 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		return b.buildSelectLock(v)
 	case *plannercore.CancelDDLJobs:
 		return b.buildCancelDDLJobs(v)
+	case *plannercore.PauseDDLJobs:
+		return b.buildPauseDDLJobs(v)
+	case *plannercore.ResumeDDLJobs:
+		return b.buildResumeDDLJobs(v)
 	case *plannercore.ShowNextRowID:
 		return b.buildShowNextRowID(v)
 	case *plannercore.ShowDDL:
@@ -212,6 +217,8 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		return b.buildTableReader(v)
 	case *plannercore.PhysicalTableSample:
 		return b.buildTableSample(v)
+	case *plannercore.PhysicalFullTextReader:
+		return b.buildFullTextReader(v)
 	case *plannercore.PhysicalIndexReader:
 		return b.buildIndexReader(v)
 	case *plannercore.PhysicalIndexLookUpReader:
@@ -245,9 +252,35 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 }
 
 func (b *executorBuilder) buildCancelDDLJobs(v *plannercore.CancelDDLJobs) Executor {
-	e := &CancelDDLJobsExec{
+	e := b.buildCommandDDLJobs(v, v.JobIDs, admin.CancelJobs)
+	if e == nil {
+		return nil
+	}
+	return &CancelDDLJobsExec{e}
+}
+
+func (b *executorBuilder) buildPauseDDLJobs(v *plannercore.PauseDDLJobs) Executor {
+	e := b.buildCommandDDLJobs(v, v.JobIDs, admin.PauseJobs)
+	if e == nil {
+		return nil
+	}
+	return &PauseDDLJobsExec{e}
+}
+
+func (b *executorBuilder) buildResumeDDLJobs(v *plannercore.ResumeDDLJobs) Executor {
+	e := b.buildCommandDDLJobs(v, v.JobIDs, admin.ResumeJobs)
+	if e == nil {
+		return nil
+	}
+	return &ResumeDDLJobsExec{e}
+}
+
+// buildCommandDDLJobs updates the DDL jobs by fn in the transaction of the statement.
+func (b *executorBuilder) buildCommandDDLJobs(v plannercore.Plan, jobIDs []int64,
+	fn func(kv.Transaction, []int64) ([]error, error)) *CommandDDLJobsExec {
+	e := &CommandDDLJobsExec{
 		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
-		jobIDs:       v.JobIDs,
+		jobIDs:       jobIDs,
 	}
 	txn, err := e.ctx.Txn(true)
 	if err != nil {
@@ -255,7 +288,7 @@ func (b *executorBuilder) buildCancelDDLJobs(v *plannercore.CancelDDLJobs) Execu
 		return nil
 	}
 
-	e.errs, b.err = admin.CancelJobs(txn, e.jobIDs)
+	e.errs, b.err = fn(txn, e.jobIDs)
 	if b.err != nil {
 		return nil
 	}
@@ -444,6 +477,10 @@ func (b *executorBuilder) buildRecoverIndex(v *plannercore.RecoverIndex) Executo
 		b.err = errors.Errorf("index `%v` is not found in table `%v`.", v.IndexName, v.Table.Name.O)
 		return nil
 	}
+	if fulltext.IsIndex(index.Meta()) {
+		b.err = errors.Errorf("index `%v` is a FULLTEXT index, which can't be recovered.", v.IndexName)
+		return nil
+	}
 	e := &RecoverIndexExec{
 		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
 		columns:      buildIdxColsConcatHandleCols(tblInfo, index.Meta()),
@@ -504,6 +541,10 @@ func (b *executorBuilder) buildCleanupIndex(v *plannercore.CleanupIndex) Executo
 		b.err = errors.Errorf("index `%v` is not found in table `%v`.", v.IndexName, v.Table.Name.O)
 		return nil
 	}
+	if fulltext.IsIndex(index.Meta()) {
+		b.err = errors.Errorf("index `%v` is a FULLTEXT index, which can't be cleaned up.", v.IndexName)
+		return nil
+	}
 	e := &CleanupIndexExec{
 		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
 		columns:      buildIdxColsConcatHandleCols(tblInfo, index.Meta()),
@@ -537,6 +578,10 @@ func (b *executorBuilder) buildCheckIndexRange(v *plannercore.CheckIndexRange) E
 			break
 		}
 	}
+	if e.index != nil && fulltext.IsIndex(e.index) {
+		b.err = errors.Errorf("index `%v` is a FULLTEXT index, which can't be checked.", v.IndexName)
+		return nil
+	}
 	return e
 }
 
@@ -762,6 +807,7 @@ func (b *executorBuilder) buildInsert(v *plannercore.Insert) Executor {
 		hasRefCols:                v.NeedFillDefaultValue,
 		SelectExec:                selectExec,
 		rowLen:                    v.RowLen,
+		rowPolicyCheck:            v.RowPolicyCheck,
 	}
 	err := ivs.initInsertColumns()
 	if err != nil {
@@ -786,12 +832,13 @@ func (b *executorBuilder) buildLoadData(v *plannercore.LoadData) Executor {
 		return nil
 	}
 	insertVal := &InsertValues{
-		baseExecutor: newBaseExecutor(b.ctx, nil, v.ID()),
-		Table:        tbl,
-		Columns:      v.Columns,
-		GenExprs:     v.GenCols.Exprs,
-		isLoadData:   true,
-		txnInUse:     sync.Mutex{},
+		baseExecutor:   newBaseExecutor(b.ctx, nil, v.ID()),
+		Table:          tbl,
+		Columns:        v.Columns,
+		GenExprs:       v.GenCols.Exprs,
+		isLoadData:     true,
+		txnInUse:       sync.Mutex{},
+		rowPolicyCheck: v.RowPolicyCheck,
 	}
 	loadDataInfo := &LoadDataInfo{
 		row:                make([]types.Datum, 0, len(insertVal.insertColumns)),
@@ -811,6 +858,10 @@ func (b *executorBuilder) buildLoadData(v *plannercore.LoadData) Executor {
 		b.err = err
 		return nil
 	}
+	if err = loadDataInfo.initFieldDecoder(); err != nil {
+		b.err = err
+		return nil
+	}
 	loadDataExec := &LoadDataExec{
 		baseExecutor: newBaseExecutor(b.ctx, nil, v.ID()),
 		IsLocal:      v.IsLocal,
@@ -886,6 +937,7 @@ func (b *executorBuilder) buildDDL(v *plannercore.DDL) Executor {
 		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
 		stmt:         v.Statement,
 		is:           b.is,
+		selectCols:   v.SelectCols,
 	}
 	return e
 }
@@ -1021,6 +1073,9 @@ func (b *executorBuilder) buildUnionScanFromReader(reader Executor, v *plannerco
 		us.columns = x.columns
 		us.table = x.table
 		us.virtualColumnIndex = buildVirtualColumnIndex(us.Schema(), us.columns)
+	case *FullTextReaderExec:
+		// The FULLTEXT index entries and the rows in the mem buffer are read by the reader itself.
+		return originReader
 	default:
 		// The mem table will not be written by sql directly, so we can omit the union scan to avoid err reporting.
 		return originReader
@@ -1528,6 +1583,7 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executo
 			strings.ToLower(infoschema.TableTiDBHotRegions),
 			strings.ToLower(infoschema.TableSessionVar),
 			strings.ToLower(infoschema.TableConstraints),
+			strings.ToLower(infoschema.TableReferConst),
 			strings.ToLower(infoschema.TableTiFlashReplica),
 			strings.ToLower(infoschema.TableTiDBServersInfo),
 			strings.ToLower(infoschema.TableTiKVStoreStatus),
@@ -1538,7 +1594,23 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executo
 			strings.ToLower(infoschema.TablePlacementPolicy),
 			strings.ToLower(infoschema.TableClientErrorsSummaryGlobal),
 			strings.ToLower(infoschema.TableClientErrorsSummaryByUser),
-			strings.ToLower(infoschema.TableClientErrorsSummaryByHost):
+			strings.ToLower(infoschema.TableClientErrorsSummaryByHost),
+			strings.ToLower(infoschema.TableTiDBStatusReport),
+			strings.ToLower(infoschema.TablePlacementPolicies),
+			strings.ToLower(infoschema.TableStatsHealth),
+			strings.ToLower(infoschema.TableTransactionTimeoutHistory),
+			strings.ToLower(infoschema.TableDeadlocks),
+			strings.ToLower(infoschema.TableTiDBTrx),
+			strings.ToLower(infoschema.ClusterTableTiDBTrx),
+			strings.ToLower(infoschema.TableDataLockWaits),
+			strings.ToLower(infoschema.ClusterTableDataLockWaits),
+			strings.ToLower(infoschema.TableMemoryUsage),
+			strings.ToLower(infoschema.ClusterTableMemoryUsage),
+			strings.ToLower(infoschema.TableSessionResourceUsage),
+			strings.ToLower(infoschema.ClusterTableSessionResourceUsage),
+			strings.ToLower(infoschema.TablePreparedPlanCache),
+			strings.ToLower(infoschema.TableRowPolicies),
+			strings.ToLower(infoschema.TableMaskingRules):
 			return &MemTableReaderExec{
 				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
 				table:        v.Table,
@@ -1787,6 +1859,7 @@ func (b *executorBuilder) buildSplitRegion(v *plannercore.SplitRegion) Executor
 			upper:          v.Upper,
 			num:            v.Num,
 			valueLists:     v.ValueLists,
+			splitByStats:   v.SplitByStats,
 		}
 	}
 	handleCols := buildHandleColsForSplit(b.ctx.GetSessionVars().StmtCtx, v.TableInfo)
@@ -1859,6 +1932,7 @@ func (b *executorBuilder) buildUpdate(v *plannercore.Update) Executor {
 		tblID2table:               tblID2table,
 		tblColPosInfos:            v.TblColPosInfos,
 		assignFlag:                assignFlag,
+		rowPolicyChecks:           v.RowPolicyChecks,
 	}
 	return updateExec
 }
@@ -2074,6 +2148,10 @@ func (b *executorBuilder) buildAnalyzeColumnsPushdown(task plannercore.AnalyzeCo
 		CmsketchDepth: &depth,
 		CmsketchWidth: &width,
 	}
+	if task.SampleSize != 0 {
+		// Every region may hold all the rows to sample, so the sample size of a region is the same as the table.
+		e.analyzePB.ColReq.SampleSize = int64(task.SampleSize)
+	}
 	if task.TblInfo != nil {
 		e.analyzePB.ColReq.PrimaryColumnIds = tables.TryGetCommonPkColumnIds(task.TblInfo)
 		if task.TblInfo.IsCommonHandle {
@@ -2105,6 +2183,21 @@ func (b *executorBuilder) buildAnalyzeColumnsPushdown(task plannercore.AnalyzeCo
 	return &analyzeTask{taskType: colTask, colExec: e, job: job}
 }
 
+func (b *executorBuilder) buildAnalyzeVirtualColumns(task plannercore.AnalyzeColumnsTask, opts map[ast.AnalyzeOptionType]uint64, autoAnalyze string) *analyzeTask {
+	e := &AnalyzeVirtualColumnsExec{
+		ctx:           b.ctx,
+		tableID:       task.TableID,
+		dbName:        task.DBName,
+		tableName:     task.TableName,
+		partitionName: task.PartitionName,
+		colsInfo:      task.ColsInfo,
+		opts:          opts,
+		analyzeVer:    task.StatsVersion,
+	}
+	job := &statistics.AnalyzeJob{DBName: task.DBName, TableName: task.TableName, PartitionName: task.PartitionName, JobInfo: autoAnalyze + "analyze virtual columns"}
+	return &analyzeTask{taskType: virtualColTask, virtualColExec: e, job: job}
+}
+
 func (b *executorBuilder) buildAnalyzePKIncremental(task plannercore.AnalyzeColumnsTask, opts map[ast.AnalyzeOptionType]uint64) *analyzeTask {
 	h := domain.GetDomain(b.ctx).StatsHandle()
 	statsTbl := h.GetPartitionStats(&model.TableInfo{}, task.TableID.GetStatisticsID())
@@ -2215,12 +2308,28 @@ func (b *executorBuilder) buildAnalyzeFastIndex(e *AnalyzeExec, task plannercore
 	}
 }
 
+// analyzeTaskOpts returns the analyze options of a task, the number of samples is replaced if the task has its own
+// sample size derived from the sample rate.
+func analyzeTaskOpts(opts map[ast.AnalyzeOptionType]uint64, sampleSize uint64) map[ast.AnalyzeOptionType]uint64 {
+	if sampleSize == 0 {
+		return opts
+	}
+	taskOpts := make(map[ast.AnalyzeOptionType]uint64, len(opts))
+	for key, val := range opts {
+		taskOpts[key] = val
+	}
+	taskOpts[ast.AnalyzeOptNumSamples] = sampleSize
+	return taskOpts
+}
+
 func (b *executorBuilder) buildAnalyze(v *plannercore.Analyze) Executor {
 	e := &AnalyzeExec{
 		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
 		tasks:        make([]*analyzeTask, 0, len(v.ColTasks)+len(v.IdxTasks)),
 		wg:           &sync.WaitGroup{},
 		opts:         v.Opts,
+		tableOpts:    v.TableOpts,
+		savedOpts:    v.SavedOpts,
 	}
 	enableFastAnalyze := b.ctx.GetSessionVars().EnableFastAnalyze
 	autoAnalyze := ""
@@ -2228,36 +2337,63 @@ func (b *executorBuilder) buildAnalyze(v *plannercore.Analyze) Executor {
 		autoAnalyze = "auto "
 	}
 	for _, task := range v.ColTasks {
+		numTasks := len(e.tasks)
+		opts := analyzeTaskOpts(v.OptsOfTable(task.TableID.TableID), task.SampleSize)
 		if task.Incremental {
-			e.tasks = append(e.tasks, b.buildAnalyzePKIncremental(task, v.Opts))
+			e.tasks = append(e.tasks, b.buildAnalyzePKIncremental(task, opts))
 		} else {
 			if enableFastAnalyze {
-				b.buildAnalyzeFastColumn(e, task, v.Opts)
+				b.buildAnalyzeFastColumn(e, task, opts)
 			} else {
-				e.tasks = append(e.tasks, b.buildAnalyzeColumnsPushdown(task, v.Opts, autoAnalyze))
+				e.tasks = append(e.tasks, b.buildAnalyzeColumnsPushdown(task, opts, autoAnalyze))
 			}
 		}
 		if b.err != nil {
 			return nil
 		}
+		b.setAnalyzeJobsTotalRows(e.tasks[numTasks:], task.TblInfo, task.TableID)
+	}
+	for _, task := range v.VirtualColTasks {
+		e.tasks = append(e.tasks, b.buildAnalyzeVirtualColumns(task, analyzeTaskOpts(v.OptsOfTable(task.TableID.TableID), task.SampleSize), autoAnalyze))
 	}
 	for _, task := range v.IdxTasks {
+		numTasks := len(e.tasks)
+		opts := v.OptsOfTable(task.TableID.TableID)
 		if task.Incremental {
-			e.tasks = append(e.tasks, b.buildAnalyzeIndexIncremental(task, v.Opts))
+			e.tasks = append(e.tasks, b.buildAnalyzeIndexIncremental(task, opts))
 		} else {
 			if enableFastAnalyze {
-				b.buildAnalyzeFastIndex(e, task, v.Opts)
+				b.buildAnalyzeFastIndex(e, task, opts)
 			} else {
-				e.tasks = append(e.tasks, b.buildAnalyzeIndexPushdown(task, v.Opts, autoAnalyze))
+				e.tasks = append(e.tasks, b.buildAnalyzeIndexPushdown(task, opts, autoAnalyze))
 			}
 		}
 		if b.err != nil {
 			return nil
 		}
+		b.setAnalyzeJobsTotalRows(e.tasks[numTasks:], task.TblInfo, task.TableID)
 	}
 	return e
 }
 
+// setAnalyzeJobsTotalRows sets the number of rows to be processed by the analyze jobs, which is estimated by the row
+// count in the stats of the table or partition.
+func (b *executorBuilder) setAnalyzeJobsTotalRows(tasks []*analyzeTask, tblInfo *model.TableInfo, tableID plannercore.AnalyzeTableID) {
+	statsHandle := domain.GetDomain(b.ctx).StatsHandle()
+	if statsHandle == nil || tblInfo == nil {
+		return
+	}
+	statsTbl := statsHandle.GetPartitionStats(tblInfo, tableID.GetStatisticsID())
+	if statsTbl.Pseudo {
+		return
+	}
+	for _, task := range tasks {
+		if task.job != nil {
+			task.job.TotalRowCount = statsTbl.Count
+		}
+	}
+}
+
 func constructDistExec(sctx sessionctx.Context, plans []plannercore.PhysicalPlan) ([]*tipb.Executor, bool, error) {
 	streaming := true
 	executors := make([]*tipb.Executor, 0, len(plans))
@@ -3719,19 +3855,35 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) *WindowExec
 		resultColIdx++
 	}
 	var processor windowProcessor
-	if v.Frame == nil {
+	var peers *peerGroups
+	if v.Frame != nil && (v.Frame.Type == ast.Groups || v.Frame.Exclude == plannercore.FrameExcludeGroup || v.Frame.Exclude == plannercore.FrameExcludeTies) {
+		peers = newPeerGroups(b.ctx, orderByCols)
+	}
+	switch {
+	case v.Frame == nil:
 		processor = &aggWindowProcessor{
 			windowFuncs:    windowFuncs,
 			partialResults: partialResults,
 		}
-	} else if v.Frame.Type == ast.Rows {
+	case v.Frame.Type == ast.Rows:
 		processor = &rowFrameWindowProcessor{
 			windowFuncs:    windowFuncs,
 			partialResults: partialResults,
 			start:          v.Frame.Start,
 			end:            v.Frame.End,
+			exclude:        v.Frame.Exclude,
+			peers:          peers,
 		}
-	} else {
+	case v.Frame.Type == ast.Groups:
+		processor = &groupFrameWindowProcessor{
+			windowFuncs:    windowFuncs,
+			partialResults: partialResults,
+			start:          v.Frame.Start,
+			end:            v.Frame.End,
+			exclude:        v.Frame.Exclude,
+			peers:          peers,
+		}
+	default:
 		cmpResult := int64(-1)
 		if len(v.OrderBy) > 0 && v.OrderBy[0].Desc {
 			cmpResult = 1
@@ -3741,6 +3893,8 @@ func (b *executorBuilder) buildWindow(v *plannercore.PhysicalWindow) *WindowExec
 			partialResults:    partialResults,
 			start:             v.Frame.Start,
 			end:               v.Frame.End,
+			exclude:           v.Frame.Exclude,
+			peers:             peers,
 			orderByCols:       orderByCols,
 			expectedCmpResult: cmpResult,
 		}
@@ -4032,3 +4186,30 @@ func (b *executorBuilder) buildTableSample(v *plannercore.PhysicalTableSample) *
 	}
 	return e
 }
+
+func (b *executorBuilder) buildFullTextReader(v *plannercore.PhysicalFullTextReader) Executor {
+	startTS, err := b.getSnapshotTS()
+	if err != nil {
+		b.err = err
+		return nil
+	}
+	idx, ok := tables.NewIndex(v.TblInfo.ID, v.TblInfo, v.IndexInfo).(table.FulltextIndex)
+	if !ok {
+		b.err = errors.Errorf("index %s is not a FULLTEXT index", v.IndexInfo.Name.O)
+		return nil
+	}
+	e := &FullTextReaderExec{
+		BatchPointGetExec: &BatchPointGetExec{
+			baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+			tblInfo:      v.TblInfo,
+			rowDecoder:   NewRowDecoder(b.ctx, v.Schema(), v.TblInfo),
+			startTS:      startTS,
+			keepOrder:    true,
+			columns:      v.Columns,
+		},
+		index:  idx,
+		search: v.Search,
+	}
+	e.buildVirtualColumnInfo()
+	return e
+}
@@ -333,7 +333,16 @@ func GetStmtLabel(stmtNode ast.StmtNode) string {
 	case *ast.LoadDataStmt:
 		return "LoadData"
 	case *ast.RollbackStmt:
+		if x.SavepointName != "" {
+			return "RollbackToSavepoint"
+		}
 		return "RollBack"
+	case *ast.SavepointStmt:
+		return "Savepoint"
+	case *ast.ReleaseSavepointStmt:
+		return "ReleaseSavepoint"
+	case *ast.XAStmt:
+		return "XA"
 	case *ast.SelectStmt:
 		return "Select"
 	case *ast.SetStmt, *ast.SetPwdStmt:
@@ -20,16 +20,22 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/format"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/ddl/placement"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/admin"
 	"github.com/pingcap/tidb/util/chunk"
@@ -47,6 +53,8 @@ type DDLExec struct {
 	stmt ast.StmtNode
 	is   infoschema.InfoSchema
 	done bool
+	// selectCols are the names of the select columns of a `CREATE TABLE ... SELECT` statement.
+	selectCols []model.CIStr
 }
 
 // toErr converts the error to the ErrInfoSchemaChanged when the schema is outdated.
@@ -124,6 +132,12 @@ func (e *DDLExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 		err = e.executeDropSequence(x)
 	case *ast.AlterSequenceStmt:
 		err = e.executeAlterSequence(x)
+	case *ast.CreatePlacementPolicyStmt:
+		err = e.executeCreatePlacementPolicy(x)
+	case *ast.AlterPlacementPolicyStmt:
+		err = e.executeAlterPlacementPolicy(x)
+	case *ast.DropPlacementPolicyStmt:
+		err = e.executeDropPlacementPolicy(x)
 	}
 	if err != nil {
 		// If the owner return ErrTableNotExists error when running this DDL, it may be caused by schema changed,
@@ -138,7 +152,7 @@ func (e *DDLExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 
 	dom := domain.GetDomain(e.ctx)
 	// Update InfoSchema in TxnCtx, so it will pass schema check.
-	is := dom.InfoSchema()
+	is := infoschema.AttachLocalTemporaryTables(e.ctx.GetSessionVars(), dom.InfoSchema())
 	txnCtx := e.ctx.GetSessionVars().TxnCtx
 	txnCtx.InfoSchema = is
 	txnCtx.SchemaVersion = is.SchemaMetaVersion()
@@ -148,12 +162,22 @@ func (e *DDLExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 }
 
 func (e *DDLExec) executeTruncateTable(s *ast.TruncateTableStmt) error {
+	if localTables, ok := e.ctx.GetSessionVars().LocalTemporaryTables.(*infoschema.LocalTemporaryTables); ok {
+		if tbl, ok := localTables.TableByName(s.Table.Schema, s.Table.Name); ok {
+			return e.truncateLocalTemporaryTable(localTables, tbl)
+		}
+	}
 	ident := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
 	err := domain.GetDomain(e.ctx).DDL().TruncateTable(e.ctx, ident)
 	return err
 }
 
 func (e *DDLExec) executeRenameTable(s *ast.RenameTableStmt) error {
+	for _, tables := range s.TableToTables {
+		if err := e.checkNotLocalTemporaryTable(tables.OldTable, "RENAME TABLE"); err != nil {
+			return err
+		}
+	}
 	isAlterTable := false
 	var err error
 	if len(s.TableToTables) == 1 {
@@ -202,16 +226,125 @@ func (e *DDLExec) executeAlterDatabase(s *ast.AlterDatabaseStmt) error {
 }
 
 func (e *DDLExec) executeCreateTable(s *ast.CreateTableStmt) error {
+	if s.Select != nil {
+		return e.executeCreateTableAsSelect(s)
+	}
 	err := domain.GetDomain(e.ctx).DDL().CreateTable(e.ctx, s)
 	return err
 }
 
+// executeCreateTableAsSelect creates the table of a `CREATE TABLE ... SELECT` statement. The rows of the select
+// are inserted by the DDL job before the table becomes public, so the table is never seen half-filled, and it's
+// dropped by the job if the rows can't be inserted.
+func (e *DDLExec) executeCreateTableAsSelect(s *ast.CreateTableStmt) error {
+	dom := domain.GetDomain(e.ctx)
+	if s.IfNotExists {
+		is := infoschema.AttachLocalTemporaryTables(e.ctx.GetSessionVars(), dom.InfoSchema())
+		if is.TableExists(s.Table.Schema, s.Table.Name) {
+			// Like MySQL, nothing is inserted into an existing table, creating it only appends the warning.
+			return dom.DDL().CreateTable(e.ctx, s)
+		}
+	}
+	insertSQL, err := e.buildInsertForCreateTableAsSelect(s)
+	if err != nil {
+		return err
+	}
+	if s.IsTemporary {
+		return e.executeCreateLocalTemporaryTableAsSelect(s, insertSQL)
+	}
+	return dom.DDL().CreateTableAsSelect(e.ctx, s, insertSQL)
+}
+
+// executeCreateLocalTemporaryTableAsSelect creates the local temporary table and inserts the rows in the session.
+// The local temporary table is only visible to the session, so it's dropped if the rows can't be inserted.
+func (e *DDLExec) executeCreateLocalTemporaryTableAsSelect(s *ast.CreateTableStmt, insertSQL string) error {
+	if err := domain.GetDomain(e.ctx).DDL().CreateTable(e.ctx, s); err != nil {
+		return err
+	}
+	err := e.insertCreateTableAsSelect(insertSQL)
+	if err == nil {
+		return nil
+	}
+	if _, dropErr := e.dropLocalTemporaryTables([]*ast.TableName{s.Table}); dropErr != nil {
+		logutil.BgLogger().Error("[ddl] drop the local temporary table created by select failed",
+			zap.Stringer("table", s.Table.Name), zap.Error(dropErr))
+	}
+	return err
+}
+
+// buildInsertForCreateTableAsSelect builds the statement inserting the result of the select of a
+// `CREATE TABLE ... SELECT` statement. The select columns are matched with the table columns by name.
+func (e *DDLExec) buildInsertForCreateTableAsSelect(s *ast.CreateTableStmt) (string, error) {
+	var sql strings.Builder
+	switch s.OnDuplicate {
+	case ast.OnDuplicateKeyHandlingReplace:
+		sql.WriteString("REPLACE")
+	case ast.OnDuplicateKeyHandlingIgnore:
+		sql.WriteString("INSERT IGNORE")
+	default:
+		sql.WriteString("INSERT")
+	}
+	sqlexec.MustFormatSQL(&sql, " INTO %n.%n (", s.Table.Schema.O, s.Table.Name.O)
+	for i, col := range e.selectCols {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		sqlexec.MustFormatSQL(&sql, "%n", col.O)
+	}
+	sql.WriteString(") ")
+	// The table names in the select have been qualified with the current database by the preprocessor,
+	// so the statement can be executed by the DDL job.
+	if err := s.Select.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sql)); err != nil {
+		return "", errors.Trace(err)
+	}
+	return sql.String(), nil
+}
+
+// insertCreateTableAsSelect inserts the rows into the local temporary table created by select in one transaction.
+func (e *DDLExec) insertCreateTableAsSelect(insertSQL string) (err error) {
+	exec := e.ctx.(sqlexec.SQLExecutor)
+	ctx := context.Background()
+	// Start a new transaction to see the created table, `BEGIN` would reuse the current one.
+	if err = e.ctx.NewTxn(ctx); err != nil {
+		return err
+	}
+	if _, err = exec.ExecuteInternal(ctx, "BEGIN"); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if _, rollbackErr := exec.ExecuteInternal(ctx, "ROLLBACK"); rollbackErr != nil {
+				logutil.BgLogger().Error("rollback the insert of the table created by select failed", zap.Error(rollbackErr))
+			}
+			// The internal statements replace the statement context and end the transaction, restore them
+			// so that the error is reported as one raised after the DDL job ran.
+			e.ctx.GetSessionVars().StmtCtx.IsDDLJobInQueue = true
+			if txnErr := e.ctx.NewTxn(ctx); txnErr != nil {
+				logutil.BgLogger().Error("[ddl] start transaction failed", zap.Error(txnErr))
+			}
+		}
+	}()
+	if _, err = exec.ExecuteInternal(ctx, insertSQL); err != nil {
+		return err
+	}
+	affectedRows := e.ctx.GetSessionVars().StmtCtx.AffectedRows()
+	if _, err = exec.ExecuteInternal(ctx, "COMMIT"); err != nil {
+		return err
+	}
+	// The internal statements replace the statement context, report the inserted rows like MySQL.
+	e.ctx.GetSessionVars().StmtCtx.AddAffectedRows(affectedRows)
+	return nil
+}
+
 func (e *DDLExec) executeCreateView(s *ast.CreateViewStmt) error {
 	err := domain.GetDomain(e.ctx).DDL().CreateView(e.ctx, s)
 	return err
 }
 
 func (e *DDLExec) executeCreateIndex(s *ast.CreateIndexStmt) error {
+	if err := e.checkNotLocalTemporaryTable(s.Table, "CREATE INDEX"); err != nil {
+		return err
+	}
 	ident := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
 	err := domain.GetDomain(e.ctx).DDL().CreateIndex(e.ctx, ident, s.KeyType, model.NewCIStr(s.IndexName),
 		s.IndexPartSpecifications, s.IndexOption, s.IfNotExists)
@@ -277,7 +410,85 @@ const (
 )
 
 func (e *DDLExec) executeDropTable(s *ast.DropTableStmt) error {
-	return e.dropTableObject(s.Tables, tableObject, s.IfExists)
+	// Like MySQL, `DROP TABLE` drops the local temporary table first if it shadows a normal table.
+	remains, err := e.dropLocalTemporaryTables(s.Tables)
+	if err != nil {
+		return err
+	}
+	if !s.IsTemporary {
+		if len(remains) == 0 {
+			return nil
+		}
+		return e.dropTableObject(remains, tableObject, s.IfExists)
+	}
+	// `DROP TEMPORARY TABLE` never drops normal tables.
+	notExistTables := make([]string, 0, len(remains))
+	for _, tn := range remains {
+		notExistTables = append(notExistTables, ast.Ident{Schema: tn.Schema, Name: tn.Name}.String())
+	}
+	if len(notExistTables) > 0 && !s.IfExists {
+		return infoschema.ErrTableDropExists.GenWithStackByArgs(strings.Join(notExistTables, ","))
+	}
+	for _, table := range notExistTables {
+		e.ctx.GetSessionVars().StmtCtx.AppendNote(infoschema.ErrTableDropExists.GenWithStackByArgs(table))
+	}
+	return nil
+}
+
+// dropLocalTemporaryTables drops the local temporary tables in objects, and returns the other tables.
+func (e *DDLExec) dropLocalTemporaryTables(objects []*ast.TableName) ([]*ast.TableName, error) {
+	sessVars := e.ctx.GetSessionVars()
+	localTables, ok := sessVars.LocalTemporaryTables.(*infoschema.LocalTemporaryTables)
+	if !ok {
+		return objects, nil
+	}
+	remains := make([]*ast.TableName, 0, len(objects))
+	for _, tn := range objects {
+		tbl, ok := localTables.TableByName(tn.Schema, tn.Name)
+		if !ok {
+			remains = append(remains, tn)
+			continue
+		}
+		if err := e.clearLocalTemporaryTableData(tbl.Meta().ID); err != nil {
+			return nil, err
+		}
+		localTables.RemoveTable(tn.Schema, tn.Name)
+	}
+	return remains, nil
+}
+
+// truncateLocalTemporaryTable removes all the data of a local temporary table and resets its auto IDs.
+func (e *DDLExec) truncateLocalTemporaryTable(localTables *infoschema.LocalTemporaryTables, tbl table.Table) error {
+	tblInfo := tbl.Meta()
+	if err := e.clearLocalTemporaryTableData(tblInfo.ID); err != nil {
+		return err
+	}
+	newTbl, err := tables.TableFromMeta(autoid.NewAllocatorsFromTempTblInfo(tblInfo), tblInfo)
+	if err != nil {
+		return err
+	}
+	db, _ := localTables.SchemaByTable(tblInfo)
+	localTables.RemoveTable(db.Name, tblInfo.Name)
+	return localTables.AddTable(db, newTbl)
+}
+
+func (e *DDLExec) clearLocalTemporaryTableData(tableID int64) error {
+	data := e.ctx.GetSessionVars().TemporaryTableData
+	if data == nil {
+		return nil
+	}
+	tablePrefix := tablecodec.EncodeTablePrefix(tableID)
+	return data.DeleteRange(tablePrefix, tablePrefix.PrefixNext())
+}
+
+// checkNotLocalTemporaryTable returns an error if the DDL operates on a local temporary table.
+func (e *DDLExec) checkNotLocalTemporaryTable(tn *ast.TableName, op string) error {
+	if localTables, ok := e.ctx.GetSessionVars().LocalTemporaryTables.(*infoschema.LocalTemporaryTables); ok {
+		if _, ok := localTables.TableByName(tn.Schema, tn.Name); ok {
+			return ddl.ErrOptOnTemporaryTable.GenWithStackByArgs(op)
+		}
+	}
+	return nil
 }
 
 func (e *DDLExec) executeDropView(s *ast.DropTableStmt) error {
@@ -363,6 +574,9 @@ func (e *DDLExec) dropTableObject(objects []*ast.TableName, obt objectType, ifEx
 }
 
 func (e *DDLExec) executeDropIndex(s *ast.DropIndexStmt) error {
+	if err := e.checkNotLocalTemporaryTable(s.Table, "DROP INDEX"); err != nil {
+		return err
+	}
 	ti := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
 	err := domain.GetDomain(e.ctx).DDL().DropIndex(e.ctx, ti, model.NewCIStr(s.IndexName), s.IfExists)
 	if (infoschema.ErrDatabaseNotExists.Equal(err) || infoschema.ErrTableNotExists.Equal(err)) && s.IfExists {
@@ -372,6 +586,9 @@ func (e *DDLExec) executeDropIndex(s *ast.DropIndexStmt) error {
 }
 
 func (e *DDLExec) executeAlterTable(s *ast.AlterTableStmt) error {
+	if err := e.checkNotLocalTemporaryTable(s.Table, "ALTER TABLE"); err != nil {
+		return err
+	}
 	ti := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
 	err := domain.GetDomain(e.ctx).DDL().AlterTable(e.ctx, ti, s.Specs)
 	return err
@@ -626,3 +843,23 @@ func (e *DDLExec) executeCreateSequence(s *ast.CreateSequenceStmt) error {
 func (e *DDLExec) executeAlterSequence(s *ast.AlterSequenceStmt) error {
 	return domain.GetDomain(e.ctx).DDL().AlterSequence(e.ctx, s)
 }
+
+func (e *DDLExec) executeCreatePlacementPolicy(s *ast.CreatePlacementPolicyStmt) error {
+	policy, err := placement.NewPolicy(s.PolicyName.O, s.Options)
+	if err != nil {
+		return err
+	}
+	return domain.GetDomain(e.ctx).DDL().CreatePlacementPolicy(e.ctx, policy, s.IfNotExists)
+}
+
+func (e *DDLExec) executeAlterPlacementPolicy(s *ast.AlterPlacementPolicyStmt) error {
+	policy, err := placement.NewPolicy(s.PolicyName.O, s.Options)
+	if err != nil {
+		return err
+	}
+	return domain.GetDomain(e.ctx).DDL().AlterPlacementPolicy(e.ctx, policy)
+}
+
+func (e *DDLExec) executeDropPlacementPolicy(s *ast.DropPlacementPolicyStmt) error {
+	return domain.GetDomain(e.ctx).DDL().DropPlacementPolicy(e.ctx, s.PolicyName, s.IfExists)
+}
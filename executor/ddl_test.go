@@ -40,6 +40,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/testkit"
@@ -59,6 +60,137 @@ func (s *testSuite6) TestTruncateTable(c *C) {
 	result.Check(nil)
 }
 
+func (s *testSuite6) TestLocalTemporaryTable(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, tmp1")
+	tk.MustExec("create table t1 (id int primary key, v int)")
+	tk.MustExec("insert into t1 values (1, 1)")
+
+	// The local temporary table shadows the normal table with the same name.
+	tk.MustExec("create temporary table t1 (id int primary key, u varchar(10), unique key(u))")
+	tk.MustQuery("select * from t1").Check(testkit.Rows())
+	tk.MustExec("insert into t1 values (1, 'a'), (2, 'b')")
+	tk.MustQuery("select * from t1 order by id").Check(testkit.Rows("1 a", "2 b"))
+	tk.MustQuery("select * from t1 where id = 2").Check(testkit.Rows("2 b"))
+	tk.MustQuery("select * from t1 where id in (1, 2, 3) order by id").Check(testkit.Rows("1 a", "2 b"))
+	tk.MustQuery("select id from t1 use index(u) where u = 'b'").Check(testkit.Rows("2"))
+	_, err := tk.Exec("insert into t1 values (1, 'c')")
+	c.Assert(kv.ErrKeyExists.Equal(err), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("insert into t1 values (3, 'a')")
+	c.Assert(kv.ErrKeyExists.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustExec("update t1 set u = 'c' where id = 2")
+	tk.MustExec("delete from t1 where id = 1")
+	tk.MustQuery("select * from t1").Check(testkit.Rows("2 c"))
+	tk.MustQuery("select * from t1 where u = 'c'").Check(testkit.Rows("2 c"))
+
+	// The changes are rolled back with the transaction.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t1 values (3, 'd')")
+	tk.MustQuery("select * from t1 order by id").Check(testkit.Rows("2 c", "3 d"))
+	tk.MustExec("rollback")
+	tk.MustQuery("select * from t1").Check(testkit.Rows("2 c"))
+	tk.MustExec("begin pessimistic")
+	_, err = tk.Exec("insert into t1 values (2, 'e')")
+	c.Assert(kv.ErrKeyExists.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustExec("insert into t1 values (3, 'd')")
+	tk.MustQuery("select * from t1 where id = 3 for update").Check(testkit.Rows("3 d"))
+	tk.MustExec("commit")
+	tk.MustQuery("select * from t1 order by id").Check(testkit.Rows("2 c", "3 d"))
+
+	// The data is never written to the storage.
+	localTables := tk.Se.GetSessionVars().LocalTemporaryTables.(*infoschema.LocalTemporaryTables)
+	tmpTbl, ok := localTables.TableByName(model.NewCIStr("test"), model.NewCIStr("t1"))
+	c.Assert(ok, IsTrue)
+	txn, err := s.store.Begin()
+	c.Assert(err, IsNil)
+	tablePrefix := tablecodec.EncodeTablePrefix(tmpTbl.Meta().ID)
+	iter, err := txn.Iter(tablePrefix, tablePrefix.PrefixNext())
+	c.Assert(err, IsNil)
+	c.Assert(iter.Valid(), IsFalse)
+	iter.Close()
+	c.Assert(txn.Rollback(), IsNil)
+
+	// The local temporary table is invisible to other sessions, SHOW TABLES and information_schema.
+	tk2 := testkit.NewTestKit(c, s.store)
+	tk2.MustExec("use test")
+	tk2.MustQuery("select * from t1").Check(testkit.Rows("1 1"))
+	tk.MustQuery("show tables like 't1'").Check(testkit.Rows("t1"))
+	tk.MustQuery("select count(*) from information_schema.tables where table_schema = 'test' and table_name = 't1'").Check(testkit.Rows("1"))
+	tk.MustQuery("show create table t1").Check(testkit.Rows("t1 CREATE TEMPORARY TABLE `t1` (\n" +
+		"  `id` int(11) NOT NULL,\n" +
+		"  `u` varchar(10) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */,\n" +
+		"  UNIQUE KEY `u` (`u`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+
+	// Unsupported operations.
+	_, err = tk.Exec("alter table t1 add column c int")
+	c.Assert(ddl.ErrOptOnTemporaryTable.Equal(err), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("create index idx on t1(u)")
+	c.Assert(ddl.ErrOptOnTemporaryTable.Equal(err), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("create temporary table tmp1 (id int) partition by hash(id) partitions 2")
+	c.Assert(ddl.ErrPartitionNoTemporary.Equal(err), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("create temporary table t1 (id int)")
+	c.Assert(infoschema.ErrTableExists.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustExec("create temporary table if not exists t1 (id int)")
+
+	// The auto IDs are allocated in memory, and TRUNCATE TABLE resets them.
+	tk.MustExec("create temporary table tmp1 (id int auto_increment primary key, v int)")
+	tk.MustExec("insert into tmp1(v) values (1), (2)")
+	tk.MustQuery("select * from tmp1").Check(testkit.Rows("1 1", "2 2"))
+	tk.MustExec("truncate table tmp1")
+	tk.MustQuery("select * from tmp1").Check(testkit.Rows())
+	tk.MustExec("insert into tmp1(v) values (3)")
+	tk.MustQuery("select * from tmp1").Check(testkit.Rows("1 3"))
+
+	// DROP TEMPORARY TABLE never drops the normal table.
+	tk.MustExec("drop temporary table t1")
+	tk.MustQuery("select * from t1").Check(testkit.Rows("1 1"))
+	_, err = tk.Exec("drop temporary table t1")
+	c.Assert(infoschema.ErrTableDropExists.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustExec("drop temporary table if exists t1")
+	tk.MustExec("drop table tmp1")
+	_, err = tk.Exec("select * from tmp1")
+	c.Assert(infoschema.ErrTableNotExists.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustExec("drop table t1")
+}
+
+func (s *testSuite6) TestLocalTemporaryTableSpill(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists tmp1")
+	tk.MustExec("set @@tidb_tmp_table_mem_quota = 4096")
+	tk.MustExec("create temporary table tmp1 (id int primary key, v int, key(v))")
+	tk.MustExec("insert into tmp1 values (1, 1)")
+	for i := 0; i < 6; i++ {
+		tk.MustExec("insert into tmp1 select id + (select count(*) from tmp1), v + (select count(*) from tmp1) from tmp1")
+	}
+	data := tk.Se.GetSessionVars().TemporaryTableData
+	c.Assert(data.DiskUsage(), Greater, int64(0))
+	c.Assert(data.MemUsage(), LessEqual, int64(4096))
+
+	// The spilled data is read together with the data in memory.
+	tk.MustQuery("select count(*), sum(id), max(v) from tmp1").Check(testkit.Rows("64 2080 64"))
+	tk.MustQuery("select v from tmp1 where id = 33").Check(testkit.Rows("33"))
+	tk.MustQuery("select id from tmp1 use index(v) where v between 10 and 12").Check(testkit.Rows("10", "11", "12"))
+	tk.MustQuery("select id from tmp1 where id in (1, 64, 65)").Sort().Check(testkit.Rows("1", "64"))
+	tk.MustExec("update tmp1 set v = v + 100 where id <= 32")
+	tk.MustExec("delete from tmp1 where id > 48")
+	tk.MustQuery("select count(*), sum(v) from tmp1").Check(testkit.Rows("48 4376"))
+	tk.MustQuery("select count(*) from tmp1 use index(v) where v > 100").Check(testkit.Rows("32"))
+
+	// TRUNCATE TABLE drops the spilled data, and the data vanishes with the session.
+	tk.MustExec("truncate table tmp1")
+	tk.MustQuery("select count(*) from tmp1").Check(testkit.Rows("0"))
+	c.Assert(data.DiskUsage(), Equals, int64(0))
+	tk.MustExec("insert into tmp1 select 1, 1 union all select 2, 2")
+	tk.MustQuery("select * from tmp1").Check(testkit.Rows("1 1", "2 2"))
+	tk.Se.Close()
+	c.Assert(data.DiskUsage(), Equals, int64(0))
+	c.Assert(data.MemUsage(), Equals, int64(0))
+}
+
 // TestInTxnExecDDLFail tests the following case:
 //  1. Execute the SQL of "begin";
 //  2. A SQL that will fail to execute;
@@ -196,6 +328,59 @@ func (s *testSuite6) TestCreateTable(c *C) {
 	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Note|1051|Unknown table 'test.t2_if_exists'", "Note|1051|Unknown table 'test.t3_if_exists'"))
 }
 
+func (s *testSuite6) TestCreateTableAsSelect(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists src, t1, t2, t3, t4, t5")
+	tk.MustExec("create table src (id int not null primary key, name varchar(20), price decimal(10, 2) unsigned)")
+	tk.MustExec("insert into src values (1, 'a', 1.5), (2, 'b', 2.5), (3, 'b', null)")
+
+	// The columns are inferred from the select.
+	tk.MustExec("create table t1 select * from src where id < 3")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(2))
+	tk.MustQuery("select * from t1 order by id").Check(testkit.Rows("1 a 1.50", "2 b 2.50"))
+	tk.MustQuery("show create table t1").Check(testkit.Rows("t1 CREATE TABLE `t1` (\n" +
+		"  `id` int(11) NOT NULL,\n" +
+		"  `name` varchar(20) DEFAULT NULL,\n" +
+		"  `price` decimal(10,2) unsigned DEFAULT NULL\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+
+	// Explicitly defined columns come first and are filled by the select columns with the same name.
+	tk.MustExec("create table t2 (id int primary key, c int default 10) as select id, name, count(*) as cnt from src group by id, name")
+	tk.MustQuery("select * from t2 order by id").Check(testkit.Rows("1 10 a 1", "2 10 b 1", "3 10 b 1"))
+	tk.MustQuery("select column_name from information_schema.columns where table_schema = 'test' and table_name = 't2' order by ordinal_position").
+		Check(testkit.Rows("id", "c", "name", "cnt"))
+
+	// The table is dropped if the insert fails.
+	tk.MustGetErrCode("create table t3 (name varchar(20) primary key) select name from src", errno.ErrDupEntry)
+	tk.MustGetErrCode("select * from t3", errno.ErrNoSuchTable)
+	tk.MustGetErrCode("create table t3 select * from src_not_exists", errno.ErrNoSuchTable)
+	tk.MustGetErrCode("select * from t3", errno.ErrNoSuchTable)
+
+	// IGNORE and REPLACE handle the duplicated rows.
+	tk.MustExec("create table t3 (name varchar(20) primary key) ignore select name, id from src order by id")
+	tk.MustQuery("select * from t3 order by name").Check(testkit.Rows("a 1", "b 2"))
+	tk.MustExec("create table t4 (name varchar(20) primary key) replace select name, id from src order by id")
+	tk.MustQuery("select * from t4 order by name").Check(testkit.Rows("a 1", "b 3"))
+
+	// Nothing is inserted into an existing table.
+	tk.MustExec("create table if not exists t4 select * from src")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1050 Table 'test.t4' already exists"))
+	tk.MustQuery("select * from t4 order by name").Check(testkit.Rows("a 1", "b 3"))
+	tk.MustGetErrCode("create table t4 select * from src", errno.ErrTableExists)
+
+	// Expressions and NULL.
+	tk.MustExec("create table t5 select id + 1 as a, concat(name, 'x') as b, null as c from src where id = 1")
+	tk.MustQuery("select * from t5").Check(testkit.Rows("2 ax <nil>"))
+	tk.MustQuery("select data_type from information_schema.columns where table_schema = 'test' and table_name = 't5' order by ordinal_position").
+		Check(testkit.Rows("bigint", "varchar", "binary"))
+
+	// Local temporary tables.
+	tk.MustExec("create temporary table tmp1 select * from src where id = 1")
+	tk.MustQuery("select * from tmp1").Check(testkit.Rows("1 a 1.50"))
+	tk.MustExec("drop table if exists src, t1, t2, t3, t4, t5, tmp1")
+}
+
 func (s *testSuite6) TestCreateView(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -14,6 +14,7 @@
 package executor
 
 import (
+	parser_mysql "github.com/pingcap/parser/mysql"
 	mysql "github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/util/dbterror"
 )
@@ -30,24 +31,30 @@ var (
 	ErrUnsupportedPs        = dbterror.ClassExecutor.NewStd(mysql.ErrUnsupportedPs)
 	ErrSubqueryMoreThan1Row = dbterror.ClassExecutor.NewStd(mysql.ErrSubqueryNo1Row)
 
-	ErrCantCreateUserWithGrant       = dbterror.ClassExecutor.NewStd(mysql.ErrCantCreateUserWithGrant)
-	ErrPasswordNoMatch               = dbterror.ClassExecutor.NewStd(mysql.ErrPasswordNoMatch)
-	ErrCannotUser                    = dbterror.ClassExecutor.NewStd(mysql.ErrCannotUser)
-	ErrGrantRole                     = dbterror.ClassExecutor.NewStd(mysql.ErrGrantRole)
-	ErrPasswordFormat                = dbterror.ClassExecutor.NewStd(mysql.ErrPasswordFormat)
-	ErrCantChangeTxCharacteristics   = dbterror.ClassExecutor.NewStd(mysql.ErrCantChangeTxCharacteristics)
-	ErrPsManyParam                   = dbterror.ClassExecutor.NewStd(mysql.ErrPsManyParam)
-	ErrAdminCheckTable               = dbterror.ClassExecutor.NewStd(mysql.ErrAdminCheckTable)
-	ErrDBaccessDenied                = dbterror.ClassExecutor.NewStd(mysql.ErrDBaccessDenied)
-	ErrTableaccessDenied             = dbterror.ClassExecutor.NewStd(mysql.ErrTableaccessDenied)
-	ErrBadDB                         = dbterror.ClassExecutor.NewStd(mysql.ErrBadDB)
-	ErrWrongObject                   = dbterror.ClassExecutor.NewStd(mysql.ErrWrongObject)
-	ErrRoleNotGranted                = dbterror.ClassPrivilege.NewStd(mysql.ErrRoleNotGranted)
-	ErrDeadlock                      = dbterror.ClassExecutor.NewStd(mysql.ErrLockDeadlock)
-	ErrQueryInterrupted              = dbterror.ClassExecutor.NewStd(mysql.ErrQueryInterrupted)
-	ErrDynamicPrivilegeNotRegistered = dbterror.ClassExecutor.NewStd(mysql.ErrDynamicPrivilegeNotRegistered)
-	ErrIllegalPrivilegeLevel         = dbterror.ClassExecutor.NewStd(mysql.ErrIllegalPrivilegeLevel)
-	ErrInvalidSplitRegionRanges      = dbterror.ClassExecutor.NewStd(mysql.ErrInvalidSplitRegionRanges)
+	ErrCantCreateUserWithGrant        = dbterror.ClassExecutor.NewStd(mysql.ErrCantCreateUserWithGrant)
+	ErrPasswordNoMatch                = dbterror.ClassExecutor.NewStd(mysql.ErrPasswordNoMatch)
+	ErrCannotUser                     = dbterror.ClassExecutor.NewStd(mysql.ErrCannotUser)
+	ErrGrantRole                      = dbterror.ClassExecutor.NewStd(mysql.ErrGrantRole)
+	ErrPasswordFormat                 = dbterror.ClassExecutor.NewStd(mysql.ErrPasswordFormat)
+	ErrNotValidPassword               = dbterror.ClassExecutor.NewStd(mysql.ErrNotValidPassword)
+	ErrCredentialsContradictToHistory = dbterror.ClassExecutor.NewStd(mysql.ErrCredentialsContradictToHistory)
+	ErrCantChangeTxCharacteristics    = dbterror.ClassExecutor.NewStd(mysql.ErrCantChangeTxCharacteristics)
+	ErrPsManyParam                    = dbterror.ClassExecutor.NewStd(mysql.ErrPsManyParam)
+	ErrAdminCheckTable                = dbterror.ClassExecutor.NewStd(mysql.ErrAdminCheckTable)
+	ErrDBaccessDenied                 = dbterror.ClassExecutor.NewStd(mysql.ErrDBaccessDenied)
+	ErrTableaccessDenied              = dbterror.ClassExecutor.NewStd(mysql.ErrTableaccessDenied)
+	ErrBadDB                          = dbterror.ClassExecutor.NewStd(mysql.ErrBadDB)
+	ErrWrongObject                    = dbterror.ClassExecutor.NewStd(mysql.ErrWrongObject)
+	ErrRoleNotGranted                 = dbterror.ClassPrivilege.NewStd(mysql.ErrRoleNotGranted)
+	ErrDeadlock                       = dbterror.ClassExecutor.NewStdErr(mysql.ErrLockDeadlock, parser_mysql.Message("Deadlock found when trying to get lock; try restarting transaction, deadlock id: %d, wait chain digest: %s", nil))
+	ErrQueryInterrupted               = dbterror.ClassExecutor.NewStd(mysql.ErrQueryInterrupted)
+	ErrDynamicPrivilegeNotRegistered  = dbterror.ClassExecutor.NewStd(mysql.ErrDynamicPrivilegeNotRegistered)
+	ErrIllegalPrivilegeLevel          = dbterror.ClassExecutor.NewStd(mysql.ErrIllegalPrivilegeLevel)
+	ErrInvalidSplitRegionRanges       = dbterror.ClassExecutor.NewStd(mysql.ErrInvalidSplitRegionRanges)
+	ErrSavepointNotExists             = dbterror.ClassExecutor.NewStd(mysql.ErrSpDoesNotExist)
+	ErrPluginIsNotLoaded              = dbterror.ClassExecutor.NewStd(mysql.ErrPluginIsNotLoaded)
+	ErrSetPasswordAuthPlugin          = dbterror.ClassExecutor.NewStd(mysql.ErrSetPasswordAuthPlugin)
+	ErrRowPolicyViolation             = dbterror.ClassExecutor.NewStd(mysql.ErrRowPolicyViolation)
 
 	ErrBRIEBackupFailed  = dbterror.ClassExecutor.NewStd(mysql.ErrBRIEBackupFailed)
 	ErrBRIERestoreFailed = dbterror.ClassExecutor.NewStd(mysql.ErrBRIERestoreFailed)
@@ -287,8 +287,8 @@ func Next(ctx context.Context, e Executor, req *chunk.Chunk) error {
 	return err
 }
 
-// CancelDDLJobsExec represents a cancel DDL jobs executor.
-type CancelDDLJobsExec struct {
+// CommandDDLJobsExec is the general struct for the executors which cancel, pause or resume DDL jobs.
+type CommandDDLJobsExec struct {
 	baseExecutor
 
 	cursor int
@@ -296,8 +296,23 @@ type CancelDDLJobsExec struct {
 	errs   []error
 }
 
+// CancelDDLJobsExec represents a cancel DDL jobs executor.
+type CancelDDLJobsExec struct {
+	*CommandDDLJobsExec
+}
+
+// PauseDDLJobsExec represents a pause DDL jobs executor.
+type PauseDDLJobsExec struct {
+	*CommandDDLJobsExec
+}
+
+// ResumeDDLJobsExec represents a resume DDL jobs executor.
+type ResumeDDLJobsExec struct {
+	*CommandDDLJobsExec
+}
+
 // Next implements the Executor Next interface.
-func (e *CancelDDLJobsExec) Next(ctx context.Context, req *chunk.Chunk) error {
+func (e *CommandDDLJobsExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	req.GrowAndReset(e.maxChunkSize)
 	if e.cursor >= len(e.jobIDs) {
 		return nil
@@ -438,6 +453,7 @@ type DDLJobRetriever struct {
 	is             infoschema.InfoSchema
 	activeRoles    []*auth.RoleIdentity
 	cacheJobs      []*model.Job
+	m              *meta.Meta
 }
 
 func (e *DDLJobRetriever) initial(txn kv.Transaction) error {
@@ -452,10 +468,12 @@ func (e *DDLJobRetriever) initial(txn kv.Transaction) error {
 	}
 	e.runningJobs = jobs
 	e.cursor = 0
+	e.m = m
 	return nil
 }
 
-func (e *DDLJobRetriever) appendJobToChunk(req *chunk.Chunk, job *model.Job, checker privilege.Manager) {
+// appendJobToChunk appends the job to the chunk, it returns false if the job is filtered by the privilege checker.
+func (e *DDLJobRetriever) appendJobToChunk(req *chunk.Chunk, job *model.Job, checker privilege.Manager) bool {
 	schemaName := job.SchemaName
 	tableName := ""
 	finishTS := uint64(0)
@@ -481,7 +499,7 @@ func (e *DDLJobRetriever) appendJobToChunk(req *chunk.Chunk, job *model.Job, che
 
 	// Check the privilege.
 	if checker != nil && !checker.RequestVerification(e.activeRoles, strings.ToLower(schemaName), strings.ToLower(tableName), "", mysql.AllPrivMask) {
-		return
+		return false
 	}
 
 	req.AppendInt64(0, job.ID)
@@ -499,6 +517,40 @@ func (e *DDLJobRetriever) appendJobToChunk(req *chunk.Chunk, job *model.Job, che
 		req.AppendNull(9)
 	}
 	req.AppendString(10, job.State.String())
+	return true
+}
+
+// getJobProgress gets the reorg progress of the running job, it returns nil if the job has no progress.
+func (e *DDLJobRetriever) getJobProgress(job *model.Job) (*meta.DDLReorgProgress, error) {
+	if job.IsFinished() {
+		return nil, nil
+	}
+	return e.m.GetDDLReorgProgress(job)
+}
+
+// appendJobProgressToChunk appends the progress percentage, the row rate, the ETA and the backfill key range
+// of the job to the chunk from the column colIdx. They're NULL if the job has no progress.
+func appendJobProgressToChunk(req *chunk.Chunk, colIdx int, job *model.Job, progress *meta.DDLReorgProgress) {
+	if progress == nil {
+		for i := 0; i < 5; i++ {
+			req.AppendNull(colIdx + i)
+		}
+		return
+	}
+	percentage := float64(100)
+	if progress.TotalRows > 0 {
+		percentage = math.Min(float64(job.RowCount)*100/float64(progress.TotalRows), 100)
+	}
+	req.AppendFloat64(colIdx, math.Round(percentage*100)/100)
+	req.AppendFloat64(colIdx+1, math.Round(progress.RowRate*100)/100)
+	if remainRows := progress.TotalRows - job.RowCount; remainRows > 0 && progress.RowRate > 0 {
+		eta := time.Duration(float64(remainRows) / progress.RowRate * float64(time.Second))
+		req.AppendString(colIdx+2, eta.Round(time.Second).String())
+	} else {
+		req.AppendNull(colIdx + 2)
+	}
+	req.AppendString(colIdx+3, progress.StartKey)
+	req.AppendString(colIdx+4, progress.EndKey)
 }
 
 func ts2Time(timestamp uint64) types.Time {
@@ -596,7 +648,12 @@ func (e *ShowDDLJobsExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	if e.cursor < len(e.runningJobs) {
 		numCurBatch := mathutil.Min(req.Capacity(), len(e.runningJobs)-e.cursor)
 		for i := e.cursor; i < e.cursor+numCurBatch; i++ {
+			progress, err := e.getJobProgress(e.runningJobs[i])
+			if err != nil {
+				return err
+			}
 			e.appendJobToChunk(req, e.runningJobs[i], nil)
+			appendJobProgressToChunk(req, 11, e.runningJobs[i], progress)
 		}
 		e.cursor += numCurBatch
 		count += numCurBatch
@@ -614,6 +671,7 @@ func (e *ShowDDLJobsExec) Next(ctx context.Context, req *chunk.Chunk) error {
 		}
 		for _, job := range e.cacheJobs {
 			e.appendJobToChunk(req, job, nil)
+			appendJobProgressToChunk(req, 11, job, nil)
 		}
 		e.cursor += len(e.cacheJobs)
 	}
@@ -1208,6 +1266,11 @@ type SelectionExec struct {
 	inputRow    chunk.Row
 	childResult *chunk.Chunk
 
+	// compiled is the filters compiled once they turn out to be hot, see expression.CompileFilter.
+	compiled     *expression.CompiledFilter
+	filteredRows int
+	compileTried bool
+
 	memTracker *memory.Tracker
 }
 
@@ -1238,6 +1301,9 @@ func (e *SelectionExec) Close() error {
 	e.memTracker.Consume(-e.childResult.MemoryUsage())
 	e.childResult = nil
 	e.selected = nil
+	e.compiled = nil
+	e.filteredRows = 0
+	e.compileTried = false
 	return e.baseExecutor.Close()
 }
 
@@ -1269,7 +1335,12 @@ func (e *SelectionExec) Next(ctx context.Context, req *chunk.Chunk) error {
 		if e.childResult.NumRows() == 0 {
 			return nil
 		}
-		e.selected, err = expression.VectorizedFilter(e.ctx, e.filters, e.inputIter, e.selected)
+		if e.compiled != nil {
+			e.selected, err = e.compiled.Filter(e.inputIter, e.selected)
+		} else {
+			e.selected, err = expression.VectorizedFilter(e.ctx, e.filters, e.inputIter, e.selected)
+			e.tryCompileFilters()
+		}
 		if err != nil {
 			return err
 		}
@@ -1277,6 +1348,19 @@ func (e *SelectionExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	}
 }
 
+// tryCompileFilters compiles the filters once they have been evaluated on expression.CompileFilterHotRows rows.
+func (e *SelectionExec) tryCompileFilters() {
+	if e.compileTried || !e.ctx.GetSessionVars().EnableExprCodegen {
+		return
+	}
+	e.filteredRows += e.childResult.NumRows()
+	if e.filteredRows < expression.CompileFilterHotRows {
+		return
+	}
+	e.compileTried = true
+	e.compiled = expression.CompileFilter(e.ctx, e.filters)
+}
+
 // unBatchedNext filters input rows one by one and returns once an input row is selected.
 // For sql with "SETVAR" in filter and "GETVAR" in projection, for example: "SELECT @a FROM t WHERE (@a := 2) > 0",
 // we have to set batch size to 1 to do the evaluation of filter and projection.
@@ -1604,6 +1688,7 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 		action.SetLogHook(domain.GetDomain(ctx).ExpensiveQueryHandle().LogOnQueryExceedMemQuota)
 		sc.MemTracker.SetActionOnExceed(action)
 	}
+	sc.MemTracker.SetEscalationChain(vars.MemOOMActionChain)
 	if execStmt, ok := s.(*ast.ExecuteStmt); ok {
 		s, err = planner.GetPreparedStmt(execStmt, vars)
 		if err != nil {
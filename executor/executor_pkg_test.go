@@ -18,6 +18,7 @@ import (
 	"crypto/tls"
 	"runtime"
 	"strconv"
+	"strings"
 	"time"
 	"unsafe"
 
@@ -25,11 +26,14 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/auth"
+	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/executor/aggfuncs"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/meta"
 	plannerutil "github.com/pingcap/tidb/planner/util"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
@@ -37,6 +41,7 @@ import (
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tidb/util/ranger"
+	"github.com/pingcap/tidb/util/txninfo"
 )
 
 var _ = Suite(&testExecSuite{})
@@ -85,6 +90,10 @@ func (msm *mockSessionManager) Kill(cid uint64, query bool) {
 func (msm *mockSessionManager) KillAllConnections() {
 }
 
+func (msm *mockSessionManager) ShowTxnList() []*txninfo.TxnInfo {
+	return nil
+}
+
 func (msm *mockSessionManager) UpdateTLSConfig(cfg *tls.Config) {
 }
 
@@ -583,3 +592,80 @@ func getGrowing(m aggPartialResultMapper) bool {
 	value := *point
 	return value.oldbuckets != nil
 }
+
+func (s *testExecSuite) TestParseGoroutinesByModule(c *C) {
+	profile := `goroutine profile: total 6
+3 @ 0x43a 0x44b
+#	0x43a	runtime.gopark+0x1a	/usr/local/go/src/runtime/proc.go:337
+#	0x44b	github.com/pingcap/tidb/util/chunk.(*Chunk).Reset+0x2b	/tidb/util/chunk/chunk.go:30
+#	0x45c	github.com/pingcap/tidb/store/tikv.(*RegionCache).asyncCheckAndResolveLoop+0x3c	/tidb/store/tikv/region_cache.go:320
+
+2 @ 0x43a 0x46d
+# labels: {"name":"grpc"}
+#	0x43a	runtime.gopark+0x1a	/usr/local/go/src/runtime/proc.go:337
+#	0x46d	google.golang.org/grpc.(*addrConn).resetTransport+0x4d	/grpc/clientconn.go:1200
+
+1 @ 0x43a
+#	0x43a	runtime.gopark+0x1a	/usr/local/go/src/runtime/proc.go:337
+`
+	result, err := parseGoroutinesByModule(strings.NewReader(profile))
+	c.Assert(err, IsNil)
+	c.Assert(result, DeepEquals, map[string]int{
+		"store/tikv":             3,
+		"google.golang.org/grpc": 2,
+		"runtime":                1,
+	})
+}
+
+func (s *testExecSuite) TestStatusReportRows(c *C) {
+	root := memory.NewTracker(memory.LabelForSQLText, -1)
+	child := memory.NewTracker(memory.LabelForChunkList, -1)
+	child.AttachTo(root)
+	child.Consume(100)
+	sc := &stmtctx.StatementContext{MemTracker: root}
+	sm := &mockSessionManager{PS: []*util.ProcessInfo{{ID: 2, User: "test", StmtCtx: sc}, {ID: 1, User: "idle"}}}
+
+	report, err := BuildStatusReport(nil, sm)
+	c.Assert(err, IsNil)
+	c.Assert(report.Memory.Sessions, HasLen, 1)
+	c.Assert(report.Memory.Sessions[0].ConnID, Equals, uint64(2))
+	c.Assert(report.RegionCache, IsNil)
+
+	var memRows []string
+	for _, row := range report.Rows() {
+		if row[0].GetString() == StatusReportSectionMemory {
+			memRows = append(memRows, row[1].GetString()+" "+row[2].GetString())
+		}
+	}
+	c.Assert(memRows[2:], DeepEquals, []string{"conn_2/-1 100", "conn_2/-1/-7 100"})
+}
+
+func (s *testExecSuite) TestAppendJobProgressToChunk(c *C) {
+	fieldTypes := []*types.FieldType{
+		types.NewFieldType(mysql.TypeDouble),
+		types.NewFieldType(mysql.TypeDouble),
+		types.NewFieldType(mysql.TypeVarchar),
+		types.NewFieldType(mysql.TypeVarchar),
+		types.NewFieldType(mysql.TypeVarchar),
+	}
+	req := chunk.New(fieldTypes, 3, 3)
+	job := &model.Job{RowCount: 250}
+	appendJobProgressToChunk(req, 0, job, &meta.DDLReorgProgress{TotalRows: 1000, RowRate: 50, StartKey: "250", EndKey: "1000"})
+	appendJobProgressToChunk(req, 0, job, &meta.DDLReorgProgress{TotalRows: 100, RowRate: 0, StartKey: "250", EndKey: "1000"})
+	appendJobProgressToChunk(req, 0, job, nil)
+
+	row := req.GetRow(0)
+	c.Assert(row.GetFloat64(0), Equals, float64(25))
+	c.Assert(row.GetFloat64(1), Equals, float64(50))
+	c.Assert(row.GetString(2), Equals, "15s")
+	c.Assert(row.GetString(3), Equals, "250")
+	c.Assert(row.GetString(4), Equals, "1000")
+	// The estimated total rows may be less than the reorganized rows.
+	row = req.GetRow(1)
+	c.Assert(row.GetFloat64(0), Equals, float64(100))
+	c.Assert(row.IsNull(2), IsTrue)
+	row = req.GetRow(2)
+	for i := 0; i < 5; i++ {
+		c.Assert(row.IsNull(i), IsTrue)
+	}
+}
@@ -349,7 +349,18 @@ func (s *testSuiteP1) TestShow(c *C) {
 		"Trigger Tables To use triggers",
 		"Create tablespace Server Admin To create/alter/drop tablespaces",
 		"Update Tables To update existing rows",
-		"Usage Server Admin No privileges - allow connect only"))
+		"Usage Server Admin No privileges - allow connect only",
+		"BACKUP_ADMIN Server Admin ",
+		"BYPASSRLS Server Admin ",
+		"CONNECTION_ADMIN Server Admin ",
+		"PLACEMENT_ADMIN Server Admin ",
+		"RESOURCE_GROUP_ADMIN Server Admin ",
+		"RESTORE_ADMIN Server Admin ",
+		"RESTRICTED_TABLES_ADMIN Server Admin ",
+		"ROLE_ADMIN Server Admin ",
+		"SET_USER_ID Server Admin ",
+		"SYSTEM_VARIABLES_ADMIN Server Admin ",
+		"UNMASK Server Admin "))
 	c.Assert(len(tk.MustQuery("show table status").Rows()), Equals, 1)
 }
 
@@ -372,6 +383,10 @@ func (s *testSuite3) TestAdmin(c *C) {
 	c.Assert(row.GetString(0), Equals, "1")
 	c.Assert(row.GetString(1), Matches, "*DDL Job:1 not found")
 
+	// pause and resume DDL jobs test
+	tk.MustQuery("admin pause ddl jobs 1").Check(testkit.Rows("1 error: [admin:8224]DDL Job:1 not found"))
+	tk.MustQuery("admin resume ddl jobs 1, 2").Check(testkit.Rows("1 error: [admin:8224]DDL Job:1 not found", "2 error: [admin:8224]DDL Job:2 not found"))
+
 	// show ddl test;
 	r, err = tk.Exec("admin show ddl")
 	c.Assert(err, IsNil)
@@ -408,7 +423,7 @@ func (s *testSuite3) TestAdmin(c *C) {
 	err = r.Next(ctx, req)
 	c.Assert(err, IsNil)
 	row = req.GetRow(0)
-	c.Assert(row.Len(), Equals, 11)
+	c.Assert(row.Len(), Equals, 16)
 	txn, err = s.store.Begin()
 	c.Assert(err, IsNil)
 	historyJobs, err := admin.GetHistoryDDLJobs(txn, admin.DefNumHistoryJobs)
@@ -424,7 +439,7 @@ func (s *testSuite3) TestAdmin(c *C) {
 	err = r.Next(ctx, req)
 	c.Assert(err, IsNil)
 	row = req.GetRow(0)
-	c.Assert(row.Len(), Equals, 11)
+	c.Assert(row.Len(), Equals, 16)
 	c.Assert(row.GetInt64(0), Equals, historyJobs[0].ID)
 	c.Assert(err, IsNil)
 
@@ -3090,6 +3105,34 @@ func (s *testSerialSuite) TestTiDBLastTxnInfoCommitMode(c *C) {
 	c.Assert(rows[0][2], Equals, "true")
 }
 
+func (s *testSerialSuite) TestPipelinedAutoCommit(c *C) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.TiKVClient.AsyncCommit.SafeWindow = time.Second
+	})
+
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key, v int)")
+	tk.MustExec("insert into t values (1, 1)")
+	tk.MustExec("set @@tidb_enable_async_commit = 0")
+	tk.MustExec("set @@tidb_enable_1pc = 0")
+	tk.MustExec("set @@tidb_pipelined_autocommit = 1")
+	defer tk.MustExec("set @@tidb_pipelined_autocommit = default")
+
+	// The auto-commit statements don't bypass the disabled 1PC and async commit.
+	tk.MustExec("update t set v = v + 1 where a = 1")
+	tk.MustQuery("select json_extract(@@tidb_last_txn_info, '$.txn_commit_mode')").Check(testkit.Rows(`"2pc"`))
+	tk.MustQuery("select v from t where a = 1").Check(testkit.Rows("2"))
+
+	tk.MustExec("set @@tidb_enable_1pc = 1")
+	defer tk.MustExec("set @@tidb_enable_1pc = default")
+	tk.MustExec("update t set v = v + 1 where a = 1")
+	tk.MustQuery("select json_extract(@@tidb_last_txn_info, '$.txn_commit_mode')").Check(testkit.Rows(`"1pc"`))
+	tk.MustQuery("select v from t where a = 1").Check(testkit.Rows("3"))
+}
+
 func (s *testSuite) TestTiDBLastQueryInfo(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -5048,6 +5091,23 @@ func (s *testSplitTable) TestSplitRegion(c *C) {
 	// Test split region by syntax.
 	tk.MustExec(`split table t by (0),(1000),(1000000)`)
 
+	// Test split index region by the statistics of the index.
+	tk.MustExec("create table t2(a int, b int, index idx_a(a), index idx_b(b))")
+	// The index which isn't analyzed is only split at its start and end keys.
+	tk.MustQuery("split table t2 index idx_b between () and () regions 4").Check(testkit.Rows("2 1"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 The statistics of index `idx_b` are not available, analyze the table to split the index by its data"))
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t2 values (%d, %d)", i, i%10))
+	}
+	tk.MustExec("analyze table t2")
+	// The end key of idx_a has been split already.
+	tk.MustQuery("split table t2 index idx_a between () and () regions 4").Check(testkit.Rows("3 1"))
+	rows := tk.MustQuery("show table t2 index idx_a regions").Rows()
+	c.Assert(len(rows), Equals, 4)
+	// There are only 10 distinct values of idx_b, so it's split into 10 regions.
+	tk.MustQuery("split table t2 index idx_b between () and () regions 20").Check(testkit.Rows("9 1"))
+	tk.MustExec("drop table t2")
+
 	// Test split region twice to test for multiple batch split region requests.
 	tk.MustExec("create table t1(a int, b int)")
 	tk.MustQuery("split table t1 between(0) and (10000) regions 10;").Check(testkit.Rows("9 1"))
@@ -5599,6 +5659,23 @@ func (s *testSuite) TestSummaryFailedUpdate(c *C) {
 	tk.MustQuery("select stmt_type from information_schema.statements_summary where digest_text = 'update `t` set `t` . `a` = `t` . `a` - ? where `t` . `a` in ( select `a` from `t` where `a` < ? )'").Check(testkit.Rows("Update"))
 }
 
+func (s *testSuite) TestStmtSummaryStatsVersions(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int, index idx(a))")
+	tk.MustExec("create table t2(a int)")
+	tk.MustExec("insert into t1 values(1), (2), (3)")
+	tk.MustExec("analyze table t1")
+	c.Assert(tk.Se.Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil), IsTrue)
+	tk.MustExec("set @@tidb_enable_stmt_summary = 1")
+	defer tk.MustExec("set @@tidb_enable_stmt_summary = default")
+	tk.MustQuery("select * from t1 join t2 on t1.a = t2.a where t1.a > 1")
+	rows := tk.MustQuery("select stats_versions from information_schema.statements_summary where digest_text like 'select * from `t1` join `t2`%'").Rows()
+	c.Assert(rows, HasLen, 1)
+	c.Assert(rows[0][0], Matches, `t1:[1-9][0-9]*\[hist:[1-9][0-9]*\],t2:pseudo`)
+}
+
 func (s *testSuite) TestOOMPanicAction(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -7704,7 +7781,24 @@ func (s *testSuite) TestOOMActionPriority(c *C) {
 	tk.MustExec("insert into t4 values(1)")
 	tk.MustQuery("select * from t0 join t1 join t2 join t3 join t4 order by t0.a").Check(testkit.Rows("1 1 1 1 1"))
 	action := tk.Se.GetSessionVars().StmtCtx.MemTracker.GetFallbackForTest()
-	// check the first 5 actions is rate limit.
+	// check the spill actions are taken first by default.
+	c.Assert(action.GetPriority(), Equals, int64(memory.DefSpillPriority))
+	for action.GetPriority() == memory.DefSpillPriority {
+		action = action.GetFallback()
+	}
+	// check the next 5 actions is rate limit.
+	for i := 0; i < 5; i++ {
+		c.Assert(action.GetPriority(), Equals, int64(memory.DefRateLimitPriority))
+		action = action.GetFallback()
+	}
+	c.Assert(action.GetPriority(), Equals, int64(memory.DefLogPriority))
+	c.Assert(action.GetFallback(), IsNil)
+
+	// the escalation chain can be reordered.
+	tk.MustExec("set @@tidb_mem_oom_action_chain = 'rate_limit, SPILL'")
+	tk.MustQuery("select @@tidb_mem_oom_action_chain").Check(testkit.Rows("rate_limit,spill"))
+	tk.MustQuery("select * from t0 join t1 join t2 join t3 join t4 order by t0.a").Check(testkit.Rows("1 1 1 1 1"))
+	action = tk.Se.GetSessionVars().StmtCtx.MemTracker.GetFallbackForTest()
 	for i := 0; i < 5; i++ {
 		c.Assert(action.GetPriority(), Equals, int64(memory.DefRateLimitPriority))
 		action = action.GetFallback()
@@ -7714,6 +7808,51 @@ func (s *testSuite) TestOOMActionPriority(c *C) {
 		action = action.GetFallback()
 	}
 	c.Assert(action.GetPriority(), Equals, int64(memory.DefLogPriority))
+
+	// the actions not in the escalation chain are disabled.
+	tk.MustExec("set @@tidb_mem_oom_action_chain = 'rate_limit'")
+	tk.MustQuery("select * from t0 join t1 join t2 join t3 join t4 order by t0.a").Check(testkit.Rows("1 1 1 1 1"))
+	action = tk.Se.GetSessionVars().StmtCtx.MemTracker.GetFallbackForTest()
+	for i := 0; i < 5; i++ {
+		c.Assert(action.GetPriority(), Equals, int64(memory.DefRateLimitPriority))
+		action = action.GetFallback()
+	}
+	c.Assert(action.GetPriority(), Equals, int64(memory.DefLogPriority))
+	tk.MustExec("set @@tidb_mem_oom_action_chain = ''")
+	tk.MustQuery("select * from t0 join t1 join t2 join t3 join t4 order by t0.a").Check(testkit.Rows("1 1 1 1 1"))
+	action = tk.Se.GetSessionVars().StmtCtx.MemTracker.GetFallbackForTest()
+	c.Assert(action.GetPriority(), Equals, int64(memory.DefLogPriority))
+	c.Assert(action.GetFallback(), IsNil)
+
+	_, err := tk.Exec("set @@tidb_mem_oom_action_chain = 'spill,cancel'")
+	c.Assert(variable.ErrWrongValueForVar.Equal(err), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("set @@tidb_mem_oom_action_chain = 'spill,spill'")
+	c.Assert(variable.ErrWrongValueForVar.Equal(err), IsTrue, Commentf("err %v", err))
+}
+
+func (s *testSuite) TestOOMActionRecorded(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int)")
+	for i := 0; i < 20; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values(%d, %d)", i, i))
+	}
+	tk.MustExec("set @@tidb_mem_quota_query = 1")
+	defer tk.MustExec("set @@tidb_mem_quota_query = default")
+	tk.MustQuery("explain analyze select * from t order by a")
+	actions := tk.Se.GetSessionVars().StmtCtx.MemTracker.TriggeredActions()
+	c.Assert(actions, Matches, ".*log\\(1\\).*")
+	c.Assert(actions, Matches, ".*spill\\(1\\).*")
+	rows := tk.MustQuery("explain analyze select * from t order by a").Rows()
+	c.Assert(fmt.Sprintf("%v", rows[0][5]), Matches, ".*oom_actions: .*log\\(1\\).*")
+
+	tk.MustExec("set @@tidb_mem_oom_action_chain = ''")
+	tk.MustQuery("select * from t order by a")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.MemTracker.TriggeredActions(), Equals, "log(1)")
+	tk.MustExec("set @@tidb_mem_quota_query = default")
+	tk.MustQuery("select * from t order by a")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.MemTracker.TriggeredActions(), Equals, "")
 }
 
 func (s *testSuite) Test17780(c *C) {
@@ -7914,6 +8053,24 @@ func (s *testSerialSuite) TestStalenessTransaction(c *C) {
 			txnScope:    "local",
 			zone:        "sz",
 		},
+		{
+			name:        "TimestampBoundMaxStaleness",
+			preSQL:      "begin",
+			sql:         `START TRANSACTION READ ONLY WITH TIMESTAMP BOUND MAX STALENESS '00:00:20';`,
+			IsStaleness: true,
+			preSec:      20,
+			txnScope:    "local",
+			zone:        "sh",
+		},
+		{
+			name:             "TimestampBoundMinReadTimestamp",
+			preSQL:           "begin",
+			sql:              `START TRANSACTION READ ONLY WITH TIMESTAMP BOUND MIN READ TIMESTAMP '2020-09-06 00:00:00';`,
+			IsStaleness:      true,
+			expectPhysicalTS: 1599321600000,
+			txnScope:         "local",
+			zone:             "bj",
+		},
 		{
 			name:        "begin",
 			preSQL:      `START TRANSACTION READ ONLY WITH TIMESTAMP BOUND READ TIMESTAMP '2020-09-06 00:00:00';`,
@@ -8028,6 +8185,42 @@ func (s *testSuite) TestStalenessAndHistoryRead(c *C) {
 	tk.MustExec("commit")
 }
 
+func (s *testSuite) TestStaleRead(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key)")
+	tk.MustExec("insert into t values (1)")
+
+	// The auto-commit read-only statements read the stale data if @@tidb_read_staleness is set.
+	c.Assert(tk.ExecToErr("set @@tidb_read_staleness = 5"), NotNil)
+	tk.MustExec("set @@tidb_read_staleness = -5")
+	tk.MustQuery("select * from t").Check(testkit.Rows())
+	tk.MustQuery("select * from t union select * from t").Check(testkit.Rows())
+	// The statements in the explicit transactions and the locking reads are not affected.
+	tk.MustQuery("select * from t for update").Check(testkit.Rows("1"))
+	tk.MustExec("begin")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1"))
+	tk.MustExec("commit")
+	tk.MustExec("set @@tidb_read_staleness = 0")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1"))
+
+	_, err := tk.Exec(fmt.Sprintf("START TRANSACTION READ ONLY WITH TIMESTAMP BOUND READ TIMESTAMP '%v'", time.Now().Add(time.Hour).Format("2006-01-02 15:04:05")))
+	c.Assert(err, ErrorMatches, ".*cannot set read timestamp to a future time.*")
+
+	// The data before the GC safe point can't be read.
+	tk.MustExec(fmt.Sprintf(`INSERT INTO mysql.tidb VALUES ('tikv_gc_safe_point', '%[1]s', '') ON DUPLICATE KEY UPDATE variable_value = '%[1]s'`,
+		time.Now().Add(-time.Minute).Format("20060102-15:04:05 -0700")))
+	defer tk.MustExec("delete from mysql.tidb where variable_name = 'tikv_gc_safe_point'")
+	_, err = tk.Exec("START TRANSACTION READ ONLY WITH TIMESTAMP BOUND READ TIMESTAMP '2020-09-06 00:00:00'")
+	c.Assert(variable.ErrSnapshotTooOld.Equal(err), IsTrue, Commentf("%v", err))
+	tk.MustExec("set @@tidb_read_staleness = -120")
+	_, err = tk.Exec("select * from t")
+	c.Assert(variable.ErrSnapshotTooOld.Equal(err), IsTrue, Commentf("%v", err))
+	tk.MustExec("set @@tidb_read_staleness = -5")
+	tk.MustQuery("select * from t").Check(testkit.Rows())
+}
+
 func (s *testSuite) TestIssue22231(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -8262,3 +8455,91 @@ func (s *testSuite1) TestIssue24091(c *C) {
 	tk.MustQuery("select null div 0;").Check(testkit.Rows("<nil>"))
 	tk.MustQuery("select * from t;").Check(testkit.Rows("<nil>"))
 }
+
+func (s *testSerialSuite) TestReadOnly(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	c.Assert(tk.Se.Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil), IsTrue)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int)")
+	tk.MustExec("drop user if exists 'ro_user'@'%'")
+	tk.MustExec("create user 'ro_user'@'%'")
+	tk.MustExec("grant all privileges on test.* to 'ro_user'@'%'")
+	defer func() {
+		tk.MustExec("set global read_only = 0")
+		tk.MustExec("set global tidb_read_only_grace_period = 10")
+		tk.MustExec("drop user 'ro_user'@'%'")
+	}()
+	newUserTestKit := func() *testkit.TestKit {
+		tk := testkit.NewTestKitWithInit(c, s.store)
+		c.Assert(tk.Se.Auth(&auth.UserIdentity{Username: "ro_user", Hostname: "%"}, nil, nil), IsTrue)
+		return tk
+	}
+	isReadOnlyErr := func(err error, option string) {
+		c.Assert(terror.ErrorEqual(err, plannercore.ErrOptionPreventsStatement), IsTrue, Commentf("err %v", err))
+		c.Assert(err.Error(), Matches, ".*"+option+" option.*")
+	}
+	tk1, tk2 := newUserTestKit(), newUserTestKit()
+
+	// The transaction started before the server turns read-only can keep writing in the grace period.
+	tk2.MustExec("begin")
+	tk2.MustExec("insert into t values (1)")
+	tk.MustExec("set global tidb_read_only_grace_period = 3600")
+	tk.MustExec("set global read_only = 1")
+	tk.MustQuery("select @@global.read_only, @@global.super_read_only").Check(testkit.Rows("1 0"))
+
+	_, err := tk1.Exec("insert into t values (2)")
+	isReadOnlyErr(err, "--read-only")
+	_, err = tk1.Exec("create table t1 (a int)")
+	isReadOnlyErr(err, "--read-only")
+	tk1.MustExec("prepare stmt from 'insert into t values (?)'")
+	tk1.MustExec("set @a = 2")
+	_, err = tk1.Exec("execute stmt using @a")
+	isReadOnlyErr(err, "--read-only")
+	_, err = tk1.Exec("explain analyze insert into t values (2)")
+	isReadOnlyErr(err, "--read-only")
+	tk1.MustExec("explain insert into t values (2)")
+	tk1.MustQuery("select * from t").Check(testkit.Rows())
+	// The local temporary tables are writable.
+	tk1.MustExec("create temporary table tmp (a int)")
+	tk1.MustExec("insert into tmp values (1)")
+	tk1.MustQuery("select * from tmp").Check(testkit.Rows("1"))
+	tk1.MustExec("drop temporary table tmp")
+
+	tk2.MustExec("insert into t values (3)")
+	tk2.MustExec("commit")
+	// The users with the SUPER or CONNECTION_ADMIN privilege can write.
+	tk.MustExec("insert into t values (4)")
+	tk.MustExec("set tidb_enable_dynamic_privileges = 1")
+	tk.MustExec("grant connection_admin on *.* to 'ro_user'@'%'")
+	tk3 := newUserTestKit()
+	tk3.MustExec("insert into t values (5)")
+	tk3.MustExec("delete from t where a = 5")
+	tk.MustExec("revoke connection_admin on *.* from 'ro_user'@'%'")
+
+	// super_read_only implies read_only, and rejects the writes of all the users.
+	tk.MustExec("set global super_read_only = 1")
+	_, err = tk.Exec("insert into t values (5)")
+	isReadOnlyErr(err, "--super-read-only")
+	_, err = tk1.Exec("insert into t values (5)")
+	isReadOnlyErr(err, "--super-read-only")
+	tk.MustExec("set global read_only = 0")
+	tk.MustQuery("select @@global.read_only, @@global.super_read_only").Check(testkit.Rows("0 0"))
+	tk.MustExec("set global super_read_only = 1")
+	tk.MustQuery("select @@global.read_only, @@global.super_read_only").Check(testkit.Rows("1 1"))
+	tk.MustExec("set global super_read_only = 0")
+	tk.MustQuery("select @@global.read_only, @@global.super_read_only").Check(testkit.Rows("1 0"))
+
+	// The transaction can't commit its writes after the grace period.
+	tk.MustExec("set global read_only = 0")
+	tk2.MustExec("begin")
+	tk2.MustExec("insert into t values (6)")
+	tk.MustExec("set global tidb_read_only_grace_period = 0")
+	tk.MustExec("set global read_only = 1")
+	_, err = tk2.Exec("commit")
+	isReadOnlyErr(err, "--read-only")
+	tk2.MustExec("rollback")
+
+	tk.MustExec("set global read_only = 0")
+	tk1.MustExec("insert into t values (7)")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1", "3", "4", "7"))
+}
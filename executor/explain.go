@@ -20,6 +20,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/execdetails"
 )
 
 // ExplainExec represents an explain executor.
@@ -100,12 +101,52 @@ func (e *ExplainExec) generateExplainInfo(ctx context.Context) (rows [][]string,
 	if err = e.executeAnalyzeExec(ctx); err != nil {
 		return nil, err
 	}
+	e.registerOOMActionStats()
 	if err = e.explain.RenderResult(); err != nil {
 		return nil, err
 	}
 	return e.explain.Rows, nil
 }
 
+// registerOOMActionStats attaches the OOM actions triggered by the analyzed statement to the runtime stats of the
+// root executor, so they are shown in the execution info of EXPLAIN ANALYZE.
+func (e *ExplainExec) registerOOMActionStats() {
+	sc := e.ctx.GetSessionVars().StmtCtx
+	if e.analyzeExec == nil || sc.RuntimeStatsColl == nil || sc.MemTracker == nil {
+		return
+	}
+	if actions := sc.MemTracker.TriggeredActions(); actions != "" {
+		sc.RuntimeStatsColl.RegisterStats(e.analyzeExec.base().id, &oomActionRuntimeStats{actions: actions})
+	}
+}
+
+// oomActionRuntimeStats is the runtime stats of the OOM actions triggered by a statement.
+type oomActionRuntimeStats struct {
+	actions string
+}
+
+// String implements the RuntimeStats interface.
+func (e *oomActionRuntimeStats) String() string {
+	return "oom_actions: " + e.actions
+}
+
+// Merge implements the RuntimeStats interface.
+func (e *oomActionRuntimeStats) Merge(other execdetails.RuntimeStats) {
+	if tmp, ok := other.(*oomActionRuntimeStats); ok {
+		e.actions = tmp.actions
+	}
+}
+
+// Clone implements the RuntimeStats interface.
+func (e *oomActionRuntimeStats) Clone() execdetails.RuntimeStats {
+	return &oomActionRuntimeStats{actions: e.actions}
+}
+
+// Tp implements the RuntimeStats interface.
+func (e *oomActionRuntimeStats) Tp() int {
+	return execdetails.TpOOMActionRuntimeStats
+}
+
 // getAnalyzeExecToExecutedNoDelay gets the analyze DML executor to execute in handleNoDelay function.
 // For explain analyze insert/update/delete statement, the analyze executor should be executed in handleNoDelay
 // function and then commit transaction if needed.
@@ -31,6 +31,7 @@ import (
 	"github.com/pingcap/tidb/util/israce"
 	"github.com/pingcap/tidb/util/kvcache"
 	"github.com/pingcap/tidb/util/testkit"
+	"github.com/pingcap/tidb/util/txninfo"
 )
 
 // mockSessionManager is a mocked session manager which is used for test.
@@ -63,6 +64,10 @@ func (msm *mockSessionManager1) Kill(cid uint64, query bool) {
 func (msm *mockSessionManager1) KillAllConnections() {
 }
 
+func (msm *mockSessionManager1) ShowTxnList() []*txninfo.TxnInfo {
+	return nil
+}
+
 func (msm *mockSessionManager1) UpdateTLSConfig(cfg *tls.Config) {
 }
 
@@ -0,0 +1,91 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/fulltext"
+)
+
+// FullTextReaderExec reads the rows containing any word of the search string of MATCH ... AGAINST through
+// the FULLTEXT index. The handles of the rows are fetched from the index when it's opened, then the rows
+// are read by the handles like BatchPointGetExec. Both the index entries and the rows written in the
+// transaction are read, so it doesn't need a UnionScanExec.
+type FullTextReaderExec struct {
+	*BatchPointGetExec
+
+	index  table.FulltextIndex
+	search expression.Expression
+}
+
+// Open implements the Executor interface.
+func (e *FullTextReaderExec) Open(ctx context.Context) error {
+	if err := e.BatchPointGetExec.Open(ctx); err != nil {
+		return err
+	}
+	e.handles = e.handles[:0]
+	search, isNull, err := e.search.EvalString(e.ctx, chunk.Row{})
+	if err != nil || isNull {
+		return err
+	}
+	sc := e.ctx.GetSessionVars().StmtCtx
+	dedup := kv.NewHandleMap()
+	for _, word := range fulltext.NewQuery(search).Terms() {
+		handles, err := e.fetchHandles(sc, word)
+		if err != nil {
+			return err
+		}
+		for _, h := range handles {
+			if _, found := dedup.Get(h); found {
+				continue
+			}
+			dedup.Set(h, true)
+			e.handles = append(e.handles, h)
+		}
+	}
+	return nil
+}
+
+// fetchHandles fetches the handles of the rows containing the word, the entries in the mem buffer of
+// the transaction override the ones in the snapshot.
+func (e *FullTextReaderExec) fetchHandles(sc *stmtctx.StatementContext, word string) ([]kv.Handle, error) {
+	handles, _, err := e.index.FetchHandles(sc, e.snapshot, word)
+	if err != nil || !e.txn.Valid() {
+		return handles, err
+	}
+	added, deleted, err := e.index.FetchHandles(sc, e.txn.GetMemBuffer(), word)
+	if err != nil {
+		return nil, err
+	}
+	if len(deleted) > 0 {
+		deletedHandles := kv.NewHandleMap()
+		for _, h := range deleted {
+			deletedHandles.Set(h, true)
+		}
+		remained := handles[:0]
+		for _, h := range handles {
+			if _, found := deletedHandles.Get(h); !found {
+				remained = append(remained, h)
+			}
+		}
+		handles = remained
+	}
+	return append(handles, added...), nil
+}
@@ -16,6 +16,7 @@ package executor
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -29,34 +30,45 @@ import (
 	"github.com/cznic/mathutil"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/charset"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/ddl"
 	"github.com/pingcap/tidb/ddl/placement"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/domain/infosync"
 	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/masking"
 	"github.com/pingcap/tidb/meta/autoid"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/rowpolicy"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/store/helper"
+	"github.com/pingcap/tidb/store/tikv/oracle"
 	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/types"
 	binaryJson "github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/deadlockhistory"
+	"github.com/pingcap/tidb/util/fulltext"
+	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/pdapi"
 	"github.com/pingcap/tidb/util/set"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/stmtsummary"
 	"github.com/pingcap/tidb/util/stringutil"
+	"github.com/pingcap/tidb/util/txninfo"
+	"github.com/pingcap/tidb/util/txntimeout"
 	"go.etcd.io/etcd/clientv3"
 )
 
@@ -129,6 +141,8 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 			err = e.setDataForTiDBHotRegions(sctx)
 		case infoschema.TableConstraints:
 			e.setDataFromTableConstraints(sctx, dbs)
+		case infoschema.TableReferConst:
+			e.setDataFromReferConst(sctx, dbs)
 		case infoschema.TableSessionVar:
 			err = e.setDataFromSessionVar(sctx)
 		case infoschema.TableTiDBServersInfo:
@@ -148,6 +162,38 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 			infoschema.TableClientErrorsSummaryByUser,
 			infoschema.TableClientErrorsSummaryByHost:
 			err = e.setDataForClientErrorsSummary(sctx, e.table.Name.O)
+		case infoschema.TableTiDBStatusReport:
+			err = e.setDataForStatusReport(sctx)
+		case infoschema.TablePlacementPolicies:
+			err = e.setDataForPlacementPolicies(sctx)
+		case infoschema.TableStatsHealth:
+			e.setDataForStatsHealth(sctx, dbs)
+		case infoschema.TableTransactionTimeoutHistory:
+			e.setDataForTransactionTimeoutHistory(sctx)
+		case infoschema.TableDeadlocks:
+			err = e.setDataForDeadlocks(sctx)
+		case infoschema.TableTiDBTrx:
+			e.setDataForTiDBTrx(sctx)
+		case infoschema.ClusterTableTiDBTrx:
+			err = e.setDataForClusterTiDBTrx(sctx)
+		case infoschema.TableDataLockWaits:
+			e.setDataForDataLockWaits(sctx)
+		case infoschema.ClusterTableDataLockWaits:
+			err = e.setDataForClusterDataLockWaits(sctx)
+		case infoschema.TableMemoryUsage:
+			e.setDataForMemoryUsage(sctx)
+		case infoschema.ClusterTableMemoryUsage:
+			err = e.setDataForClusterMemoryUsage(sctx)
+		case infoschema.TableSessionResourceUsage:
+			e.setDataForSessionResourceUsage(sctx)
+		case infoschema.ClusterTableSessionResourceUsage:
+			err = e.setDataForClusterSessionResourceUsage(sctx)
+		case infoschema.TablePreparedPlanCache:
+			e.setDataForPreparedPlanCache(sctx)
+		case infoschema.TableRowPolicies:
+			e.setDataForRowPolicies(sctx)
+		case infoschema.TableMaskingRules:
+			e.setDataForMaskingRules(sctx)
 		}
 		if err != nil {
 			return nil, err
@@ -384,6 +430,10 @@ func (e *memtableRetriever) setDataForStatisticsInTable(schema *model.DBInfo, ta
 		if index.Unique {
 			nonUnique = "0"
 		}
+		indexType := "BTREE"
+		if fulltext.IsIndex(index) {
+			indexType = index.Tp.String()
+		}
 		for i, key := range index.Columns {
 			col := nameToCol[key.Name.L]
 			nullable := "YES"
@@ -419,7 +469,7 @@ func (e *memtableRetriever) setDataForStatisticsInTable(schema *model.DBInfo, ta
 				nil,                   // SUB_PART
 				nil,                   // PACKED
 				nullable,              // NULLABLE
-				"BTREE",               // INDEX_TYPE
+				indexType,             // INDEX_TYPE
 				"",                    // COMMENT
 				"",                    // INDEX_COMMENT
 				visible,               // IS_VISIBLE
@@ -587,7 +637,7 @@ func (e *hugeMemTableRetriever) dataForColumnsInTable(ctx context.Context, sctx
 		return
 	}
 	for i, col := range tbl.Columns {
-		if col.Hidden {
+		if tables.IsHiddenToSession(sctx.GetSessionVars(), col) {
 			continue
 		}
 		var charMaxLen, charOctLen, numericPrecision, numericScale, datetimePrecision interface{}
@@ -1020,8 +1070,14 @@ func (e *DDLJobsReaderExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	if e.cursor < len(e.runningJobs) {
 		num := mathutil.Min(req.Capacity(), len(e.runningJobs)-e.cursor)
 		for i := e.cursor; i < e.cursor+num; i++ {
-			e.appendJobToChunk(req, e.runningJobs[i], checker)
-			req.AppendString(11, e.runningJobs[i].Query)
+			progress, err := e.getJobProgress(e.runningJobs[i])
+			if err != nil {
+				return err
+			}
+			if e.appendJobToChunk(req, e.runningJobs[i], checker) {
+				req.AppendString(11, e.runningJobs[i].Query)
+				appendJobProgressToChunk(req, 12, e.runningJobs[i], progress)
+			}
 		}
 		e.cursor += num
 		count += num
@@ -1035,8 +1091,10 @@ func (e *DDLJobsReaderExec) Next(ctx context.Context, req *chunk.Chunk) error {
 			return err
 		}
 		for _, job := range e.cacheJobs {
-			e.appendJobToChunk(req, job, checker)
-			req.AppendString(11, job.Query)
+			if e.appendJobToChunk(req, job, checker) {
+				req.AppendString(11, job.Query)
+				appendJobProgressToChunk(req, 12, job, nil)
+			}
 		}
 		e.cursor += len(e.cacheJobs)
 	}
@@ -1269,12 +1327,12 @@ func keyColumnUsageInTable(schema *model.DBInfo, table *model.TableInfo) [][]typ
 		}
 	}
 	for _, fk := range table.ForeignKeys {
-		fkRefCol := ""
-		if len(fk.RefCols) > 0 {
-			fkRefCol = fk.RefCols[0].O
-		}
 		for i, key := range fk.Cols {
 			col := nameToCol[key.L]
+			fkRefCol := ""
+			if i < len(fk.RefCols) {
+				fkRefCol = fk.RefCols[i].O
+			}
 			record := types.MakeDatums(
 				infoschema.CatalogVal, // CONSTRAINT_CATALOG
 				schema.Name.O,         // CONSTRAINT_SCHEMA
@@ -1284,7 +1342,7 @@ func keyColumnUsageInTable(schema *model.DBInfo, table *model.TableInfo) [][]typ
 				table.Name.O,          // TABLE_NAME
 				col.Name.O,            // COLUMN_NAME
 				i+1,                   // ORDINAL_POSITION,
-				1,                     // POSITION_IN_UNIQUE_CONSTRAINT
+				i+1,                   // POSITION_IN_UNIQUE_CONSTRAINT
 				schema.Name.O,         // REFERENCED_TABLE_SCHEMA
 				fk.RefTable.O,         // REFERENCED_TABLE_NAME
 				fkRefCol,              // REFERENCED_COLUMN_NAME
@@ -1511,11 +1569,91 @@ func (e *memtableRetriever) setDataFromTableConstraints(ctx sessionctx.Context,
 				)
 				rows = append(rows, record)
 			}
+
+			for _, fk := range tbl.ForeignKeys {
+				record := types.MakeDatums(
+					infoschema.CatalogVal,     // CONSTRAINT_CATALOG
+					schema.Name.O,             // CONSTRAINT_SCHEMA
+					fk.Name.O,                 // CONSTRAINT_NAME
+					schema.Name.O,             // TABLE_SCHEMA
+					tbl.Name.O,                // TABLE_NAME
+					infoschema.ForeignKeyType, // CONSTRAINT_TYPE
+				)
+				rows = append(rows, record)
+			}
+		}
+	}
+	e.rows = rows
+}
+
+func (e *memtableRetriever) setDataFromReferConst(ctx sessionctx.Context, schemas []*model.DBInfo) {
+	checker := privilege.GetPrivilegeManager(ctx)
+	var rows [][]types.Datum
+	for _, schema := range schemas {
+		for _, tbl := range schema.Tables {
+			if checker != nil && !checker.RequestVerification(ctx.GetSessionVars().ActiveRoles, schema.Name.L, tbl.Name.L, "", mysql.AllPrivMask) {
+				continue
+			}
+			for _, fk := range tbl.ForeignKeys {
+				record := types.MakeDatums(
+					infoschema.CatalogVal,              // CONSTRAINT_CATALOG
+					schema.Name.O,                      // CONSTRAINT_SCHEMA
+					fk.Name.O,                          // CONSTRAINT_NAME
+					infoschema.CatalogVal,              // UNIQUE_CONSTRAINT_CATALOG
+					schema.Name.O,                      // UNIQUE_CONSTRAINT_SCHEMA
+					getFKReferredIndexName(schema, fk), // UNIQUE_CONSTRAINT_NAME
+					"NONE",                             // MATCH_OPTION
+					referOptionRule(fk.OnUpdate),       // UPDATE_RULE
+					referOptionRule(fk.OnDelete),       // DELETE_RULE
+					tbl.Name.O,                         // TABLE_NAME
+					fk.RefTable.O,                      // REFERENCED_TABLE_NAME
+				)
+				rows = append(rows, record)
+			}
 		}
 	}
 	e.rows = rows
 }
 
+// getFKReferredIndexName returns the name of the index in the referred table used by the foreign key,
+// or nil if the referred table or the index doesn't exist.
+func getFKReferredIndexName(schema *model.DBInfo, fk *model.FKInfo) interface{} {
+	for _, tbl := range schema.Tables {
+		if tbl.Name.L != fk.RefTable.L {
+			continue
+		}
+		if tbl.PKIsHandle && len(fk.RefCols) == 1 {
+			if pkCol := tbl.GetPkColInfo(); pkCol != nil && pkCol.Name.L == fk.RefCols[0].L {
+				return infoschema.PrimaryConstraint
+			}
+		}
+		for _, idx := range tbl.Indices {
+			if len(idx.Columns) < len(fk.RefCols) {
+				continue
+			}
+			matched := true
+			for i, col := range fk.RefCols {
+				if idx.Columns[i].Name.L != col.L {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				return idx.Name.O
+			}
+		}
+	}
+	return nil
+}
+
+// referOptionRule returns the rule name of the referential action, which is NO ACTION by default.
+func referOptionRule(opt int) string {
+	if ast.ReferOptionType(opt) == ast.ReferOptionNoOption {
+		return ast.ReferOptionNoAction.String()
+	}
+	return ast.ReferOptionType(opt).String()
+}
+
 // tableStorageStatsRetriever is used to read slow log data.
 type tableStorageStatsRetriever struct {
 	dummyCloser
@@ -1652,6 +1790,9 @@ func (e *memtableRetriever) setDataFromSessionVar(ctx sessionctx.Context) error
 	var err error
 	sessionVars := ctx.GetSessionVars()
 	for _, v := range variable.GetSysVars() {
+		if variable.IsSessionStatesVar(v.Name) {
+			continue
+		}
 		var value string
 		value, err = variable.GetSessionSystemVar(sessionVars, v.Name)
 		if err != nil {
@@ -1680,6 +1821,10 @@ func dataForAnalyzeStatusHelper(sctx sessionctx.Context) (rows [][]types.Datum)
 		} else {
 			endTime = types.NewTime(types.FromGoTime(job.EndTime), mysql.TypeDatetime, 0)
 		}
+		var progress interface{}
+		if p, ok := job.Progress(); ok {
+			progress = p
+		}
 		if checker == nil || checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, job.DBName, job.TableName, "", mysql.AllPrivMask) {
 			rows = append(rows, types.MakeDatums(
 				job.DBName,        // TABLE_SCHEMA
@@ -1690,6 +1835,7 @@ func dataForAnalyzeStatusHelper(sctx sessionctx.Context) (rows [][]types.Datum)
 				startTime,         // START_TIME
 				endTime,           // END_TIME
 				job.State,         // STATE
+				progress,          // PROGRESS
 			))
 		}
 		job.Unlock()
@@ -1702,6 +1848,495 @@ func (e *memtableRetriever) setDataForAnalyzeStatus(sctx sessionctx.Context) {
 	e.rows = dataForAnalyzeStatusHelper(sctx)
 }
 
+// setDataForStatsHealth gets the health of the stats of all the tables and partitions.
+func (e *memtableRetriever) setDataForStatsHealth(sctx sessionctx.Context, schemas []*model.DBInfo) {
+	h := domain.GetDomain(sctx).StatsHandle()
+	if h == nil {
+		return
+	}
+	checker := privilege.GetPrivilegeManager(sctx)
+	for _, schema := range schemas {
+		for _, tbl := range schema.Tables {
+			if tbl.IsView() || tbl.IsSequence() {
+				continue
+			}
+			if checker != nil && !checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, schema.Name.L, tbl.Name.L, "", mysql.AllPrivMask) {
+				continue
+			}
+			pi := tbl.GetPartitionInfo()
+			if pi == nil || sctx.GetSessionVars().UseDynamicPartitionPrune() {
+				partitionName := ""
+				if pi != nil {
+					partitionName = "global"
+				}
+				e.appendRowForStatsHealth(schema.Name.O, tbl, partitionName, h.GetTableStats(tbl))
+			}
+			if pi != nil {
+				for _, def := range pi.Definitions {
+					e.appendRowForStatsHealth(schema.Name.O, tbl, def.Name.O, h.GetPartitionStats(tbl, def.ID))
+				}
+			}
+		}
+	}
+}
+
+func (e *memtableRetriever) appendRowForStatsHealth(dbName string, tblInfo *model.TableInfo, partitionName string, statsTbl *statistics.Table) {
+	healthy, ok := statsTbl.GetStatsHealthy()
+	if !ok {
+		return
+	}
+	var lastAnalyzeTime interface{}
+	if version := statsTbl.LastAnalyzeVersion(); version > 0 {
+		lastAnalyzeTime = types.NewTime(types.FromGoTime(oracle.GetTimeFromTS(version)), mysql.TypeDatetime, 0)
+	}
+	missingColumnStats := statsTbl.MissingColumnStatsCount(tblInfo)
+	e.rows = append(e.rows, types.MakeDatums(
+		dbName,                 // TABLE_SCHEMA
+		tblInfo.Name.O,         // TABLE_NAME
+		partitionName,          // PARTITION_NAME
+		healthy,                // HEALTHY
+		statsTbl.ModifyRatio(), // MODIFY_RATIO
+		statsTbl.Count,         // ROW_COUNT
+		statsTbl.ModifyCount,   // MODIFY_COUNT
+		lastAnalyzeTime,        // LAST_ANALYZE_TIME
+		missingColumnStats,     // MISSING_COLUMN_STATS
+	))
+}
+
+// setDataForTransactionTimeoutHistory gets the transactions killed by tidb_idle_transaction_timeout or
+// tidb_max_transaction_duration on this tidb-server.
+func (e *memtableRetriever) setDataForTransactionTimeoutHistory(sctx sessionctx.Context) {
+	loginUser := sctx.GetSessionVars().User
+	var hasProcessPriv bool
+	if pm := privilege.GetPrivilegeManager(sctx); pm != nil {
+		hasProcessPriv = pm.RequestVerification(sctx.GetSessionVars().ActiveRoles, "", "", "", mysql.ProcessPriv)
+	}
+	events := txntimeout.GlobalHistory.Events()
+	rows := make([][]types.Datum, 0, len(events))
+	for _, event := range events {
+		// Only the users with the PROCESS privilege can see the transactions of the other users.
+		if !hasProcessPriv && loginUser != nil && event.User != loginUser.Username {
+			continue
+		}
+		startTime := types.NewTime(types.FromGoTime(event.StartTime), mysql.TypeDatetime, types.MaxFsp)
+		killTime := types.NewTime(types.FromGoTime(event.KillTime), mysql.TypeDatetime, types.MaxFsp)
+		duration := event.KillTime.Sub(event.StartTime).Seconds()
+		rows = append(rows, types.MakeDatums(
+			event.ConnID,  // ID
+			event.User,    // USER
+			event.Host,    // HOST
+			event.DB,      // DB
+			event.StartTS, // START_TS
+			startTime,     // TRX_START_TIME
+			killTime,      // KILL_TIME
+			duration,      // DURATION
+			event.Reason,  // REASON
+		))
+	}
+	e.rows = rows
+}
+
+// setDataForDeadlocks gets the recent deadlocks met by the transactions on this tidb-server, each row is an item of the
+// wait chain of a deadlock.
+func (e *memtableRetriever) setDataForDeadlocks(sctx sessionctx.Context) error {
+	// The deadlocks expose the statements of all sessions, so it requires the PROCESS privilege like processlist.
+	if pm := privilege.GetPrivilegeManager(sctx); pm != nil {
+		if !pm.RequestVerification(sctx.GetSessionVars().ActiveRoles, "", "", "", mysql.ProcessPriv) {
+			return plannercore.ErrSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+		}
+	}
+	var rows [][]types.Datum
+	for _, record := range deadlockhistory.GlobalDeadlockHistory.GetAll() {
+		occurTime := types.NewTime(types.FromGoTime(record.OccurTime), mysql.TypeDatetime, types.MaxFsp)
+		digest := record.Digest()
+		for _, item := range record.WaitChain {
+			var key, txnHoldingLock interface{}
+			if len(item.Key) > 0 {
+				key = strings.ToUpper(hex.EncodeToString(item.Key))
+			}
+			if item.TxnHoldingLock > 0 {
+				txnHoldingLock = item.TxnHoldingLock
+			}
+			rows = append(rows, types.MakeDatums(
+				record.ID,          // DEADLOCK_ID
+				occurTime,          // OCCUR_TIME
+				record.IsRetryable, // RETRYABLE
+				digest,             // WAIT_CHAIN_DIGEST
+				item.TryLockTxn,    // TRY_LOCK_TRX_ID
+				item.SQLDigest,     // CURRENT_SQL_DIGEST
+				item.SQLDigestText, // CURRENT_SQL_DIGEST_TEXT
+				key,                // KEY
+				txnHoldingLock,     // TRX_HOLDING_LOCK
+			))
+		}
+	}
+	e.rows = rows
+	return nil
+}
+
+// showTxnList returns the running transactions on this tidb-server which can be seen by the current user.
+func showTxnList(sctx sessionctx.Context) []*txninfo.TxnInfo {
+	sm := sctx.GetSessionManager()
+	if sm == nil {
+		return nil
+	}
+	loginUser := sctx.GetSessionVars().User
+	var hasProcessPriv bool
+	if pm := privilege.GetPrivilegeManager(sctx); pm != nil {
+		hasProcessPriv = pm.RequestVerification(sctx.GetSessionVars().ActiveRoles, "", "", "", mysql.ProcessPriv)
+	}
+	infos := sm.ShowTxnList()
+	visible := make([]*txninfo.TxnInfo, 0, len(infos))
+	for _, info := range infos {
+		// If you have the PROCESS privilege, you can see all transactions.
+		// Otherwise, you can see only your own transactions.
+		if !hasProcessPriv && loginUser != nil && info.Username != loginUser.Username {
+			continue
+		}
+		visible = append(visible, info)
+	}
+	return visible
+}
+
+func (e *memtableRetriever) setDataForTiDBTrx(sctx sessionctx.Context) {
+	infos := showTxnList(sctx)
+	rows := make([][]types.Datum, 0, len(infos))
+	for _, info := range infos {
+		startTime := types.NewTime(types.FromGoTime(oracle.GetTimeFromTS(info.StartTS)), mysql.TypeDatetime, types.MaxFsp)
+		var waitingStartTime interface{}
+		if info.State == txninfo.TxnLockWaiting {
+			waitingStartTime = types.NewTime(types.FromGoTime(info.BlockStartTime), mysql.TypeDatetime, types.MaxFsp)
+		}
+		rows = append(rows, types.MakeDatums(
+			info.StartTS,                            // ID
+			startTime,                               // START_TIME
+			info.CurrentSQLDigest,                   // CURRENT_SQL_DIGEST
+			info.CurrentSQLDigestText,               // CURRENT_SQL_DIGEST_TEXT
+			txninfo.TxnRunningStateStrs[info.State], // STATE
+			waitingStartTime,                        // WAITING_START_TIME
+			info.EntriesCount,                       // MEM_BUFFER_KEYS
+			info.EntriesSize,                        // MEM_BUFFER_BYTES
+			info.ConnectionID,                       // SESSION_ID
+			info.Username,                           // USER
+			info.CurrentDB,                          // DB
+		))
+	}
+	e.rows = rows
+}
+
+func (e *memtableRetriever) setDataForClusterTiDBTrx(sctx sessionctx.Context) error {
+	e.setDataForTiDBTrx(sctx)
+	rows, err := infoschema.AppendHostInfoToRows(e.rows)
+	if err != nil {
+		return err
+	}
+	e.rows = rows
+	return nil
+}
+
+// setDataForDataLockWaits gets the pessimistic lock waits of the transactions on this tidb-server. The lock waits are
+// reported when the lock requests return from TiKV, so a lock wait may be invisible in its first round.
+func (e *memtableRetriever) setDataForDataLockWaits(sctx sessionctx.Context) {
+	var rows [][]types.Datum
+	for _, info := range showTxnList(sctx) {
+		if info.State != txninfo.TxnLockWaiting {
+			continue
+		}
+		waitingStartTime := types.NewTime(types.FromGoTime(info.BlockStartTime), mysql.TypeDatetime, types.MaxFsp)
+		for _, wait := range info.LockWaits {
+			key := strings.ToUpper(hex.EncodeToString(wait.Key))
+			rows = append(rows, types.MakeDatums(
+				key,                       // KEY
+				info.StartTS,              // TRX_ID
+				wait.HoldingTxnID,         // CURRENT_HOLDING_TRX_ID
+				info.CurrentSQLDigest,     // SQL_DIGEST
+				info.CurrentSQLDigestText, // SQL_DIGEST_TEXT
+				waitingStartTime,          // WAITING_START_TIME
+			))
+		}
+	}
+	e.rows = rows
+}
+
+func (e *memtableRetriever) setDataForClusterDataLockWaits(sctx sessionctx.Context) error {
+	e.setDataForDataLockWaits(sctx)
+	rows, err := infoschema.AppendHostInfoToRows(e.rows)
+	if err != nil {
+		return err
+	}
+	e.rows = rows
+	return nil
+}
+
+// showProcessList returns the sessions on this tidb-server which can be seen by the current user, ordered by the
+// connection ID.
+func showProcessList(sctx sessionctx.Context) []*util.ProcessInfo {
+	sm := sctx.GetSessionManager()
+	if sm == nil {
+		return nil
+	}
+	loginUser := sctx.GetSessionVars().User
+	var hasProcessPriv bool
+	if pm := privilege.GetPrivilegeManager(sctx); pm != nil {
+		hasProcessPriv = pm.RequestVerification(sctx.GetSessionVars().ActiveRoles, "", "", "", mysql.ProcessPriv)
+	}
+	pl := sm.ShowProcessList()
+	infos := make([]*util.ProcessInfo, 0, len(pl))
+	for _, pi := range pl {
+		// If you have the PROCESS privilege, you can see all sessions.
+		// Otherwise, you can see only your own sessions.
+		if !hasProcessPriv && loginUser != nil && pi.User != loginUser.Username {
+			continue
+		}
+		infos = append(infos, pi)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ID < infos[j].ID
+	})
+	return infos
+}
+
+// planOperatorNames maps the IDs of the plans to their explain IDs, which are the labels of the executor trackers.
+func planOperatorNames(p interface{}) map[int]string {
+	names := make(map[int]string)
+	var walk func(p plannercore.Plan)
+	walk = func(p plannercore.Plan) {
+		names[p.ID()] = p.ExplainID().String()
+		switch x := p.(type) {
+		case plannercore.PhysicalPlan:
+			for _, child := range x.Children() {
+				walk(child)
+			}
+		case *plannercore.Insert:
+			if x.SelectPlan != nil {
+				walk(x.SelectPlan)
+			}
+		case *plannercore.Update:
+			if x.SelectPlan != nil {
+				walk(x.SelectPlan)
+			}
+		case *plannercore.Delete:
+			if x.SelectPlan != nil {
+				walk(x.SelectPlan)
+			}
+		}
+	}
+	if plan, ok := p.(plannercore.Plan); ok && plan != nil {
+		walk(plan)
+	}
+	return names
+}
+
+func appendTrackerRows(rows [][]types.Datum, connID uint64, trackerType string, snap *memory.TrackerSnapshot,
+	depth int, parentLabel interface{}, names map[int]string) [][]types.Datum {
+	label, ok := names[snap.Label]
+	if !ok {
+		label = memory.LabelName(snap.Label)
+	}
+	rows = append(rows, types.MakeDatums(
+		connID,             // ID
+		trackerType,        // TRACKER_TYPE
+		depth,              // DEPTH
+		label,              // LABEL
+		parentLabel,        // PARENT_LABEL
+		snap.BytesConsumed, // BYTES
+		snap.MaxConsumed,   // MAX_BYTES
+		snap.BytesLimit,    // BYTES_LIMIT
+	))
+	for _, child := range snap.Children {
+		rows = appendTrackerRows(rows, connID, trackerType, child, depth+1, label, names)
+	}
+	return rows
+}
+
+// setDataForMemoryUsage flattens the memory and disk tracker trees of the running statements, so that the operators
+// consuming the most memory or spilling the most data can be found. The idle sessions are skipped.
+func (e *memtableRetriever) setDataForMemoryUsage(sctx sessionctx.Context) {
+	var rows [][]types.Datum
+	for _, pi := range showProcessList(sctx) {
+		if pi.Command == mysql.ComSleep || pi.StmtCtx == nil {
+			continue
+		}
+		names := planOperatorNames(pi.Plan)
+		if pi.StmtCtx.MemTracker != nil {
+			rows = appendTrackerRows(rows, pi.ID, "MEMORY", pi.StmtCtx.MemTracker.Snapshot(), 0, nil, names)
+		}
+		if pi.StmtCtx.DiskTracker != nil {
+			rows = appendTrackerRows(rows, pi.ID, "DISK", pi.StmtCtx.DiskTracker.Snapshot(), 0, nil, names)
+		}
+	}
+	e.rows = rows
+}
+
+func (e *memtableRetriever) setDataForClusterMemoryUsage(sctx sessionctx.Context) error {
+	e.setDataForMemoryUsage(sctx)
+	rows, err := infoschema.AppendHostInfoToRows(e.rows)
+	if err != nil {
+		return err
+	}
+	e.rows = rows
+	return nil
+}
+
+// setDataForSessionResourceUsage shows the memory and disk usage of the sessions. The usage of the idle sessions are
+// the ones of their last statements.
+func (e *memtableRetriever) setDataForSessionResourceUsage(sctx sessionctx.Context) {
+	txnMemBuffer := make(map[uint64]uint64)
+	if sm := sctx.GetSessionManager(); sm != nil {
+		for _, info := range sm.ShowTxnList() {
+			txnMemBuffer[info.ConnectionID] = info.EntriesSize
+		}
+	}
+	pl := showProcessList(sctx)
+	rows := make([][]types.Datum, 0, len(pl))
+	for _, pi := range pl {
+		var memBytes, maxMemBytes, diskBytes, maxDiskBytes int64
+		memQuota := int64(-1)
+		if pi.StmtCtx != nil {
+			if pi.StmtCtx.MemTracker != nil {
+				memBytes, maxMemBytes = pi.StmtCtx.MemTracker.BytesConsumed(), pi.StmtCtx.MemTracker.MaxConsumed()
+				memQuota = pi.StmtCtx.MemTracker.GetBytesLimit()
+			}
+			if pi.StmtCtx.DiskTracker != nil {
+				diskBytes, maxDiskBytes = pi.StmtCtx.DiskTracker.BytesConsumed(), pi.StmtCtx.DiskTracker.MaxConsumed()
+			}
+		}
+		show := pi.ToRowForShow(true)
+		var digest interface{}
+		if pi.Digest != "" {
+			digest = pi.Digest
+		}
+		rows = append(rows, types.MakeDatums(
+			pi.ID,               // ID
+			pi.User,             // USER
+			show[2],             // HOST
+			show[3],             // DB
+			show[4],             // COMMAND
+			show[5],             // TIME
+			digest,              // DIGEST
+			memBytes,            // MEM_BYTES
+			maxMemBytes,         // MAX_MEM_BYTES
+			memQuota,            // MEM_QUOTA
+			diskBytes,           // DISK_BYTES
+			maxDiskBytes,        // MAX_DISK_BYTES
+			txnMemBuffer[pi.ID], // TXN_MEM_BUFFER_BYTES
+			show[7],             // INFO
+		))
+	}
+	e.rows = rows
+}
+
+func (e *memtableRetriever) setDataForClusterSessionResourceUsage(sctx sessionctx.Context) error {
+	e.setDataForSessionResourceUsage(sctx)
+	rows, err := infoschema.AppendHostInfoToRows(e.rows)
+	if err != nil {
+		return err
+	}
+	e.rows = rows
+	return nil
+}
+
+func (e *memtableRetriever) setDataForPreparedPlanCache(sctx sessionctx.Context) {
+	cache := sctx.PreparedPlanCache()
+	if cache == nil {
+		return
+	}
+	vars := sctx.GetSessionVars()
+	keys, values := cache.Keys(), cache.Values()
+	rows := make([][]types.Datum, 0, len(keys))
+	for i, key := range keys {
+		stmtID, schemaVersion, ok := plannercore.ParsePSTMTPlanCacheKey(key)
+		if !ok {
+			continue
+		}
+		cachedVals, ok := values[i].([]*plannercore.PSTMTPlanCacheValue)
+		if !ok {
+			continue
+		}
+		var sqlDigest, normalizedSQL, tableIDs interface{}
+		if preparedObj, ok := vars.PreparedStmts[stmtID].(*plannercore.CachedPrepareStmt); ok {
+			sqlDigest, normalizedSQL = preparedObj.SQLDigest, preparedObj.NormalizedSQL
+			if preparedObj.TableRevisions != nil {
+				ids := make([]int64, 0, len(preparedObj.TableRevisions))
+				for id := range preparedObj.TableRevisions {
+					ids = append(ids, id)
+				}
+				tableIDs = joinSortedIDs(ids)
+			}
+		}
+		for _, cachedVal := range cachedVals {
+			statsIDs := make([]int64, 0, len(cachedVal.UsedStats))
+			for id := range cachedVal.UsedStats {
+				statsIDs = append(statsIDs, id)
+			}
+			sort.Slice(statsIDs, func(i, j int) bool { return statsIDs[i] < statsIDs[j] })
+			usedStats := make([]string, 0, len(statsIDs))
+			for _, id := range statsIDs {
+				usedStats = append(usedStats, fmt.Sprintf("%d:%d", id, cachedVal.UsedStats[id].HistVersion))
+			}
+			rows = append(rows, types.MakeDatums(
+				uint64(stmtID),               // STMT_ID
+				schemaVersion,                // SCHEMA_VERSION
+				sqlDigest,                    // SQL_DIGEST
+				normalizedSQL,                // NORMALIZED_SQL
+				tableIDs,                     // TABLE_IDS
+				strings.Join(usedStats, ","), // USED_STATS
+				cachedVal.Bindings,           // BINDINGS
+			))
+		}
+	}
+	e.rows = rows
+}
+
+// setDataForRowPolicies sets the row policies of the tables the user has any privilege on.
+func (e *memtableRetriever) setDataForRowPolicies(sctx sessionctx.Context) {
+	checker := privilege.GetPrivilegeManager(sctx)
+	policies := rowpolicy.GlobalManager().Policies()
+	rows := make([][]types.Datum, 0, len(policies))
+	for _, policy := range policies {
+		if checker != nil && !checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, policy.DB, policy.Table, "", mysql.AllPrivMask) {
+			continue
+		}
+		rows = append(rows, types.MakeDatums(
+			policy.Name,  // POLICY_NAME
+			policy.DB,    // TABLE_SCHEMA
+			policy.Table, // TABLE_NAME
+			fmt.Sprintf("'%s'@'%s'", policy.User, policy.Host), // GRANTEE
+			policy.Using, // USING_EXPR
+		))
+	}
+	e.rows = rows
+}
+
+// setDataForMaskingRules sets the masking rules of the tables the user has any privilege on.
+func (e *memtableRetriever) setDataForMaskingRules(sctx sessionctx.Context) {
+	checker := privilege.GetPrivilegeManager(sctx)
+	rules := masking.GlobalManager().Rules()
+	rows := make([][]types.Datum, 0, len(rules))
+	for _, rule := range rules {
+		if checker != nil && !checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, rule.DB, rule.Table, "", mysql.AllPrivMask) {
+			continue
+		}
+		rows = append(rows, types.MakeDatums(
+			rule.DB,     // TABLE_SCHEMA
+			rule.Table,  // TABLE_NAME
+			rule.Column, // COLUMN_NAME
+			rule.Expr,   // MASK_EXPR
+		))
+	}
+	e.rows = rows
+}
+
+// joinSortedIDs sorts the ids and joins them with commas.
+func joinSortedIDs(ids []int64) string {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	strs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		strs = append(strs, strconv.FormatInt(id, 10))
+	}
+	return strings.Join(strs, ",")
+}
+
 // setDataForPseudoProfiling returns pseudo data for table profiling when system variable `profiling` is set to `ON`.
 func (e *memtableRetriever) setDataForPseudoProfiling(sctx sessionctx.Context) {
 	if v, ok := sctx.GetSessionVars().GetSystemVar("profiling"); ok && variable.TiDBOptOn(v) {
@@ -1853,6 +2488,28 @@ func (e *memtableRetriever) setDataForStatementsSummary(ctx sessionctx.Context,
 	return nil
 }
 
+func (e *memtableRetriever) setDataForPlacementPolicies(ctx sessionctx.Context) error {
+	policies, err := ddl.LoadPlacementPolicies(ctx, "")
+	if err != nil {
+		return err
+	}
+	rows := make([][]types.Datum, 0, len(policies))
+	for _, policy := range policies {
+		rows = append(rows, types.MakeDatums(
+			policy.Name,
+			policy.LeaderConstraints,
+			policy.Followers,
+			policy.FollowerConstraints,
+			policy.Voters,
+			policy.VoterConstraints,
+			policy.Learners,
+			policy.LearnerConstraints,
+		))
+	}
+	e.rows = rows
+	return nil
+}
+
 func (e *memtableRetriever) setDataForPlacementPolicy(ctx sessionctx.Context) error {
 	checker := privilege.GetPrivilegeManager(ctx)
 	is := infoschema.GetInfoSchema(ctx)
@@ -1907,6 +2564,21 @@ func (e *memtableRetriever) setDataForPlacementPolicy(ctx sessionctx.Context) er
 	return nil
 }
 
+func (e *memtableRetriever) setDataForStatusReport(ctx sessionctx.Context) error {
+	// The report exposes the memory usage of all sessions, so it requires the PROCESS privilege like processlist.
+	if pm := privilege.GetPrivilegeManager(ctx); pm != nil {
+		if !pm.RequestVerification(ctx.GetSessionVars().ActiveRoles, "", "", "", mysql.ProcessPriv) {
+			return plannercore.ErrSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+		}
+	}
+	report, err := BuildStatusReport(domain.GetDomain(ctx), ctx.GetSessionManager())
+	if err != nil {
+		return err
+	}
+	e.rows = report.Rows()
+	return nil
+}
+
 func (e *memtableRetriever) setDataForClientErrorsSummary(ctx sessionctx.Context, tableName string) error {
 	// Seeing client errors should require the PROCESS privilege, with the exception of errors for your own user.
 	// This is similar to information_schema.processlist, which is the closest comparison.
@@ -34,19 +34,25 @@ import (
 	"github.com/pingcap/tidb/domain/infosync"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/kv"
+	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/server"
 	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/statistics/handle"
 	"github.com/pingcap/tidb/store/helper"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/disk"
+	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/pdapi"
 	"github.com/pingcap/tidb/util/stringutil"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testleak"
 	"github.com/pingcap/tidb/util/testutil"
+	"github.com/pingcap/tidb/util/txninfo"
+	"github.com/pingcap/tidb/util/txntimeout"
 	"google.golang.org/grpc"
 )
 
@@ -510,6 +516,17 @@ func (s *testInfoschemaTableSuite) TestMetricTables(c *C) {
 func (s *testInfoschemaTableSuite) TestTableConstraintsTable(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustQuery("select * from information_schema.TABLE_CONSTRAINTS where TABLE_NAME='gc_delete_range';").Check(testkit.Rows("def mysql delete_range_index mysql gc_delete_range UNIQUE"))
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists fk_child, fk_parent")
+	tk.MustExec("create table fk_parent (a int, b int, primary key (a, b))")
+	tk.MustExec("create table fk_child (a int, b int, constraint fk foreign key (b, a) references fk_parent(a, b))")
+	tk.MustQuery("select * from information_schema.TABLE_CONSTRAINTS where TABLE_NAME='fk_child';").Check(testkit.Rows("def test fk test fk_child FOREIGN KEY"))
+	tk.MustQuery("select COLUMN_NAME, ORDINAL_POSITION, POSITION_IN_UNIQUE_CONSTRAINT, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME from information_schema.KEY_COLUMN_USAGE where TABLE_NAME='fk_child' and CONSTRAINT_NAME='fk';").Check(
+		testkit.Rows("b 1 1 fk_parent a", "a 2 2 fk_parent b"))
+	tk.MustQuery("select * from information_schema.REFERENTIAL_CONSTRAINTS where TABLE_NAME='fk_child';").Check(
+		testkit.Rows("def test fk def test PRIMARY NONE NO ACTION NO ACTION fk_child fk_parent"))
+	tk.MustExec("drop table fk_child, fk_parent")
 }
 
 func (s *testInfoschemaTableSuite) TestTableSessionVar(c *C) {
@@ -551,11 +568,201 @@ func (s *testInfoschemaTableSuite) TestForAnalyzeStatus(c *C) {
 	resultT1 := tk.MustQuery("select * from information_schema.analyze_status where TABLE_NAME='t1'").Sort()
 	c.Assert(len(resultT1.Rows()), Greater, 0)
 	for _, row := range resultT1.Rows() {
-		c.Assert(len(row), Equals, 8) // test length of row
+		c.Assert(len(row), Equals, 9) // test length of row
 		c.Assert(row[6], NotNil)      // test `End_time` field
 	}
 }
 
+func (s *testInfoschemaTableSerialSuite) TestForStatsHealth(c *C) {
+	s.dom.SetStatsUpdating(true)
+	tk := testkit.NewTestKit(c, s.store)
+	h := s.dom.StatsHandle()
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists stats_health_test")
+	tk.MustExec("create table stats_health_test (a int, b int, index idx(a))")
+	c.Assert(h.HandleDDLEvent(<-h.DDLEventCh()), IsNil)
+	tk.MustExec("insert into stats_health_test values (1,2),(3,4),(5,6),(7,8),(9,10),(11,12)")
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	tk.MustQuery("select * from information_schema.stats_health where table_name='stats_health_test'").Check(testkit.Rows())
+
+	tk.MustExec("analyze table stats_health_test")
+	tk.MustQuery("select healthy, modify_ratio, row_count, modify_count, missing_column_stats, last_analyze_time is not null from information_schema.stats_health where table_name='stats_health_test'").Check(
+		testkit.Rows("100 0 6 0 0 1"))
+
+	tk.MustExec("insert into stats_health_test values (13,14),(15,16)")
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	c.Assert(h.Update(s.dom.InfoSchema()), IsNil)
+	tk.MustExec("alter table stats_health_test add column c int")
+	c.Assert(h.HandleDDLEvent(<-h.DDLEventCh()), IsNil)
+	c.Assert(h.Update(s.dom.InfoSchema()), IsNil)
+	tk.MustQuery("select healthy, round(modify_ratio, 2), row_count, modify_count, missing_column_stats from information_schema.stats_health where table_name='stats_health_test'").Check(
+		testkit.Rows("75 0.25 8 2 1"))
+
+	// The user without privilege of the table can't see its stats health.
+	tk.MustExec("create user stats_health_tester")
+	tester := testkit.NewTestKit(c, s.store)
+	tester.MustExec("use information_schema")
+	c.Assert(tester.Se.Auth(&auth.UserIdentity{
+		Username: "stats_health_tester",
+		Hostname: "127.0.0.1",
+	}, nil, nil), IsTrue)
+	tester.MustQuery("select * from information_schema.stats_health where table_name='stats_health_test'").Check(testkit.Rows())
+}
+
+func (s *testInfoschemaTableSerialSuite) TestForTransactionTimeoutHistory(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("create user txn_timeout_tester")
+	tester := testkit.NewTestKitWithInit(c, s.store)
+	c.Assert(tester.Se.Auth(&auth.UserIdentity{
+		Username: "txn_timeout_tester",
+		Hostname: "127.0.0.1",
+	}, nil, nil), IsTrue)
+	tester.MustExec("set @@tidb_idle_transaction_timeout = 10, @@tidb_max_transaction_duration = 60")
+	tester.MustQuery("select @@tidb_idle_transaction_timeout, @@tidb_max_transaction_duration").Check(testkit.Rows("10 60"))
+	tester.MustExec("begin")
+	tester.MustQuery("select 1")
+
+	pi := tester.Se.ShowProcess()
+	c.Assert(pi.IdleTxnTimeout, Equals, 10*time.Second)
+	c.Assert(pi.MaxTxnDuration, Equals, time.Minute)
+	c.Assert(pi.TxnStartTime.IsZero(), IsFalse)
+	c.Assert(pi.IdleStartTime.IsZero(), IsFalse)
+	now := pi.IdleStartTime.Add(10 * time.Second)
+	c.Assert(txntimeout.Check(pi, now), Equals, txntimeout.ReasonIdle)
+	txntimeout.GlobalHistory.Push(txntimeout.NewEvent(pi, txntimeout.ReasonIdle, now))
+	tester.MustExec("rollback")
+
+	result := tk.MustQuery("select user, host, start_ts > 0, duration >= 10, reason from information_schema.transaction_timeout_history where id = ?", pi.ID)
+	result.Check(testkit.Rows("txn_timeout_tester 127.0.0.1 1 1 idle"))
+	tester.MustQuery("select count(*) from information_schema.transaction_timeout_history where id = ?", pi.ID).Check(testkit.Rows("1"))
+
+	// The user without the PROCESS privilege can only see the transactions of its own.
+	tk.MustExec("create user txn_timeout_tester2")
+	tester2 := testkit.NewTestKitWithInit(c, s.store)
+	c.Assert(tester2.Se.Auth(&auth.UserIdentity{
+		Username: "txn_timeout_tester2",
+		Hostname: "127.0.0.1",
+	}, nil, nil), IsTrue)
+	tester2.MustQuery("select count(*) from information_schema.transaction_timeout_history where id = ?", pi.ID).Check(testkit.Rows("0"))
+}
+
+func (s *testInfoschemaTableSerialSuite) TestTiDBTrxAndDataLockWaits(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("create user trx_tester")
+	blockStartTime := time.Date(2021, 4, 10, 12, 0, 0, 0, time.Local)
+	sm := &mockSessionManager{
+		processInfoMap: make(map[uint64]*util.ProcessInfo),
+		txnInfo: []*txninfo.TxnInfo{
+			{
+				StartTS:              424768545227014155,
+				CurrentSQLDigest:     "sql_digest_1",
+				CurrentSQLDigestText: "update `t` set `a` = ? where `id` = ?",
+				State:                txninfo.TxnRunning,
+				EntriesCount:         1,
+				EntriesSize:          19,
+				ConnectionID:         2,
+				Username:             "root",
+				CurrentDB:            "test",
+			},
+			{
+				StartTS:              424768545227014156,
+				CurrentSQLDigest:     "sql_digest_2",
+				CurrentSQLDigestText: "select * from `t` where `id` = ? for update",
+				State:                txninfo.TxnLockWaiting,
+				BlockStartTime:       blockStartTime,
+				LockWaits:            []txninfo.LockWait{{Key: []byte("key"), HoldingTxnID: 424768545227014155}},
+				ConnectionID:         3,
+				Username:             "trx_tester",
+				CurrentDB:            "test",
+			},
+		},
+	}
+	tk.Se.SetSessionManager(sm)
+	tk.MustQuery("select id, current_sql_digest, current_sql_digest_text, state, waiting_start_time, mem_buffer_keys, mem_buffer_bytes, session_id, user, db from information_schema.tidb_trx order by id").Check(
+		[][]interface{}{
+			{"424768545227014155", "sql_digest_1", "update `t` set `a` = ? where `id` = ?", "Running", "<nil>", "1", "19", "2", "root", "test"},
+			{"424768545227014156", "sql_digest_2", "select * from `t` where `id` = ? for update", "LockWaiting", "2021-04-10 12:00:00.000000", "0", "0", "3", "trx_tester", "test"},
+		})
+	tk.MustQuery("select * from information_schema.data_lock_waits").Check(
+		[][]interface{}{
+			{"6B6579", "424768545227014156", "424768545227014155", "sql_digest_2", "select * from `t` where `id` = ? for update", "2021-04-10 12:00:00.000000"},
+		})
+
+	// The user without the PROCESS privilege can only see the transactions of its own.
+	tester := testkit.NewTestKitWithInit(c, s.store)
+	tester.Se.SetSessionManager(sm)
+	c.Assert(tester.Se.Auth(&auth.UserIdentity{
+		Username: "trx_tester",
+		Hostname: "127.0.0.1",
+	}, nil, nil), IsTrue)
+	tester.MustQuery("select id from information_schema.tidb_trx").Check(testkit.Rows("424768545227014156"))
+	tester.MustQuery("select trx_id from information_schema.data_lock_waits").Check(testkit.Rows("424768545227014156"))
+}
+
+func (s *testInfoschemaTableSerialSuite) TestMemoryUsage(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("create user mu_tester")
+	tk.MustExec("drop table if exists mu_t")
+	tk.MustExec("create table mu_t (a int)")
+	tk.MustQuery("select * from mu_t order by a")
+	plan := tk.Se.ShowProcess().Plan.(plannercore.PhysicalPlan)
+	rootName := plan.ExplainID().String()
+
+	// The running statement spills the sort operator to disk.
+	sc := &stmtctx.StatementContext{
+		MemTracker:  memory.NewTracker(memory.LabelForSQLText, 1000),
+		DiskTracker: disk.NewTracker(memory.LabelForSQLText, -1),
+	}
+	opMemTracker := memory.NewTracker(plan.ID(), -1)
+	opMemTracker.AttachTo(sc.MemTracker)
+	opMemTracker.Consume(100)
+	rowContainerTracker := memory.NewTracker(memory.LabelForRowContainer, -1)
+	rowContainerTracker.AttachTo(opMemTracker)
+	rowContainerTracker.Consume(40)
+	opDiskTracker := disk.NewTracker(plan.ID(), -1)
+	opDiskTracker.AttachTo(sc.DiskTracker)
+	opDiskTracker.Consume(2048)
+	// The idle session keeps the usage of its last statement.
+	idleSC := &stmtctx.StatementContext{
+		MemTracker:  memory.NewTracker(memory.LabelForSQLText, -1),
+		DiskTracker: disk.NewTracker(memory.LabelForSQLText, -1),
+	}
+	idleSC.MemTracker.Consume(10)
+	idleSC.MemTracker.Consume(-10)
+
+	sm := &mockSessionManager{
+		processInfoMap: map[uint64]*util.ProcessInfo{
+			1: {ID: 1, User: "root", Host: "127.0.0.1", DB: "test", Command: mysql.ComQuery, Digest: "digest", Time: time.Now(),
+				Info: "select * from mu_t order by a", Plan: plan, StmtCtx: sc},
+			2: {ID: 2, User: "mu_tester", Host: "127.0.0.1", Command: mysql.ComSleep, Time: time.Now(), StmtCtx: idleSC},
+		},
+		txnInfo: []*txninfo.TxnInfo{{StartTS: 1, ConnectionID: 2, Username: "mu_tester", EntriesSize: 19}},
+	}
+	tk.Se.SetSessionManager(sm)
+	tk.MustQuery("select * from information_schema.memory_usage").Check(testkit.Rows(
+		"1 MEMORY 0 SQLText <nil> 140 140 1000",
+		"1 MEMORY 1 "+rootName+" SQLText 140 140 -1",
+		"1 MEMORY 2 RowContainer "+rootName+" 40 40 -1",
+		"1 DISK 0 SQLText <nil> 2048 2048 -1",
+		"1 DISK 1 "+rootName+" SQLText 2048 2048 -1",
+	))
+	tk.MustQuery("select id, user, command, digest, mem_bytes, max_mem_bytes, mem_quota, disk_bytes, max_disk_bytes, txn_mem_buffer_bytes, info from information_schema.session_resource_usage").Check(
+		[][]interface{}{
+			{"1", "root", "Query", "digest", "140", "140", "1000", "2048", "2048", "0", "select * from mu_t order by a"},
+			{"2", "mu_tester", "Sleep", "<nil>", "0", "10", "-1", "0", "0", "19", "<nil>"},
+		})
+
+	// The user without the PROCESS privilege can only see the sessions of its own.
+	tester := testkit.NewTestKitWithInit(c, s.store)
+	tester.Se.SetSessionManager(sm)
+	c.Assert(tester.Se.Auth(&auth.UserIdentity{
+		Username: "mu_tester",
+		Hostname: "127.0.0.1",
+	}, nil, nil), IsTrue)
+	tester.MustQuery("select * from information_schema.memory_usage").Check(testkit.Rows())
+	tester.MustQuery("select id from information_schema.session_resource_usage").Check(testkit.Rows("2"))
+}
+
 func (s *testInfoschemaTableSerialSuite) TestForServersInfo(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	result := tk.MustQuery("select * from information_schema.TIDB_SERVERS_INFO")
@@ -725,6 +932,7 @@ func (s *testInfoschemaClusterTableSuite) TearDownSuite(c *C) {
 
 type mockSessionManager struct {
 	processInfoMap map[uint64]*util.ProcessInfo
+	txnInfo        []*txninfo.TxnInfo
 	serverID       uint64
 }
 
@@ -732,6 +940,10 @@ func (sm *mockSessionManager) ShowProcessList() map[uint64]*util.ProcessInfo {
 	return sm.processInfoMap
 }
 
+func (sm *mockSessionManager) ShowTxnList() []*txninfo.TxnInfo {
+	return sm.txnInfo
+}
+
 func (sm *mockSessionManager) GetProcessInfo(id uint64) (*util.ProcessInfo, bool) {
 	rs, ok := sm.processInfoMap[id]
 	return rs, ok
@@ -362,6 +362,10 @@ func (e *InsertExec) initEvalBuffer4Dup() {
 // doDupRowUpdate updates the duplicate row.
 func (e *InsertExec) doDupRowUpdate(ctx context.Context, handle kv.Handle, oldRow []types.Datum, newRow []types.Datum,
 	cols []*expression.Assignment) error {
+	// The rows invisible to the user can't be updated.
+	if err := checkRowPolicy(e.ctx, e.rowPolicyCheck, e.Table, oldRow); err != nil {
+		return err
+	}
 	assignFlag := make([]bool, len(e.Table.WritableCols()))
 	// See http://dev.mysql.com/doc/refman/5.7/en/miscellaneous-functions.html#function_values
 	e.curInsertVals.SetDatums(newRow...)
@@ -389,6 +393,9 @@ func (e *InsertExec) doDupRowUpdate(ctx context.Context, handle kv.Handle, oldRo
 	}
 
 	newData := e.row4Update[:len(oldRow)]
+	if err := checkRowPolicy(e.ctx, e.rowPolicyCheck, e.Table, newData); err != nil {
+		return err
+	}
 	_, err := updateRecord(ctx, e.ctx, handle, oldRow, newData, assignFlag, e.Table, true, e.memTracker)
 	if err != nil {
 		return err
@@ -87,6 +87,9 @@ type InsertValues struct {
 
 	rowLen int
 
+	// rowPolicyCheck is the condition the inserted rows must satisfy by the row policies of the table.
+	rowPolicyCheck expression.Expression
+
 	stats *InsertRuntimeStat
 
 	// LoadData use two goroutines. One for generate batch data,
@@ -152,8 +155,8 @@ func (e *InsertValues) initInsertColumns() error {
 			return errors.Errorf("INSERT INTO %s: unknown column %s", e.Table.Meta().Name.O, missingColName)
 		}
 	} else {
-		// If e.Columns are empty, use all columns instead.
-		cols = tableCols
+		// If e.Columns are empty, use all visible columns instead.
+		cols = e.Table.VisibleCols()
 	}
 	for _, col := range cols {
 		if !col.IsGenerated() {
@@ -1113,6 +1116,9 @@ func (e *InsertValues) addRecord(ctx context.Context, row []types.Datum) error {
 }
 
 func (e *InsertValues) addRecordWithAutoIDHint(ctx context.Context, row []types.Datum, reserveAutoIDCount int) (err error) {
+	if err = checkRowPolicy(e.ctx, e.rowPolicyCheck, e.Table, row); err != nil {
+		return err
+	}
 	vars := e.ctx.GetSessionVars()
 	if !vars.ConstraintCheckInPlace {
 		vars.PresumeKeyNotExists = true
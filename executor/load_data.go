@@ -24,13 +24,16 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/charset"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/encoding"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
 	"go.uber.org/zap"
@@ -118,6 +121,8 @@ type LoadDataInfo struct {
 	ColumnAssignments  []*ast.Assignment
 	ColumnsAndUserVars []*ast.ColumnNameOrUserVar
 	FieldMappings      []*FieldMapping
+	// fieldDecoder decodes the fields if they are encoded in a character set incompatible with UTF-8.
+	fieldDecoder *encoding.Converter
 
 	commitTaskQueue chan CommitTask
 	StopCh          chan struct{}
@@ -130,6 +135,33 @@ type FieldMapping struct {
 	UserVar *ast.VariableExpr
 }
 
+// initFieldDecoder initializes the decoder of the fields, which are encoded in character_set_database.
+func (e *LoadDataInfo) initFieldDecoder() error {
+	cs, ok := e.Ctx.GetSessionVars().GetSystemVar(variable.CharsetDatabase)
+	if !ok || encoding.IsUTF8Compatible(cs) {
+		return nil
+	}
+	var err error
+	e.fieldDecoder, err = encoding.NewConverter(charset.CharsetBin, cs)
+	return err
+}
+
+// decodeField decodes the field to UTF-8, the invalid characters are handled according to the session
+// variable tidb_invalid_char_handling.
+func (e *LoadDataInfo) decodeField(str []byte) ([]byte, error) {
+	if e.fieldDecoder == nil {
+		return str, nil
+	}
+	res, warn, err := e.fieldDecoder.Convert(nil, str, e.Ctx.GetSessionVars().InvalidCharHandling)
+	if err != nil {
+		return nil, err
+	}
+	if warn != nil {
+		e.handleWarning(warn)
+	}
+	return res, nil
+}
+
 // initLoadColumns sets columns which the input fields loaded to.
 func (e *LoadDataInfo) initLoadColumns(columnNames []string) error {
 	var cols []*table.Column
@@ -682,9 +714,14 @@ func (e *LoadDataInfo) colsToRow(ctx context.Context, cols []field) []types.Datu
 			continue
 		}
 
+		str, err := e.decodeField(cols[i].str)
+		if err != nil {
+			e.handleWarning(err)
+			return nil
+		}
 		if e.FieldMappings[i].Column == nil {
 			sessionVars := e.Ctx.GetSessionVars()
-			sessionVars.SetUserVar(e.FieldMappings[i].UserVar.Name, string(cols[i].str), mysql.DefaultCollationName)
+			sessionVars.SetUserVar(e.FieldMappings[i].UserVar.Name, string(str), mysql.DefaultCollationName)
 			continue
 		}
 
@@ -695,7 +732,7 @@ func (e *LoadDataInfo) colsToRow(ctx context.Context, cols []field) []types.Datu
 			continue
 		}
 
-		row = append(row, types.NewDatum(string(cols[i].str)))
+		row = append(row, types.NewDatum(string(str)))
 	}
 	for i := 0; i < len(e.ColumnAssignments); i++ {
 		// eval expression of `SET` clause
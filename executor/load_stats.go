@@ -15,7 +15,6 @@ package executor
 
 import (
 	"context"
-	"encoding/json"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/domain"
@@ -75,11 +74,12 @@ func (e *LoadStatsExec) Open(ctx context.Context) error {
 	return nil
 }
 
-// Update updates the stats of the corresponding table according to the data.
+// Update updates the stats of the corresponding table according to the data, which is the JSON dumped by the stats
+// dump API and may be compressed by gzip.
 func (e *LoadStatsInfo) Update(data []byte) error {
-	jsonTbl := &handle.JSONTable{}
-	if err := json.Unmarshal(data, jsonTbl); err != nil {
-		return errors.Trace(err)
+	jsonTbl, err := handle.DecodeJSONTable(data)
+	if err != nil {
+		return err
 	}
 	do := domain.GetDomain(e.Ctx)
 	h := do.StatsHandle()
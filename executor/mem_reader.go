@@ -322,25 +322,45 @@ func iterTxnMemBuffer(ctx sessionctx.Context, kvRanges []kv.KeyRange, fn process
 	if err != nil {
 		return err
 	}
+	isTempTable := len(kvRanges) > 0 && ctx.GetSessionVars().IsLocalTemporaryTable(tablecodec.DecodeTableID(kvRanges[0].StartKey))
 	for _, rg := range kvRanges {
-		iter := txn.GetMemBuffer().SnapshotIter(rg.StartKey, rg.EndKey)
-		for ; iter.Valid(); err = iter.Next() {
-			if err != nil {
-				return err
-			}
-			// check whether the key was been deleted.
-			if len(iter.Value()) == 0 {
-				continue
-			}
-			err = fn(iter.Key(), iter.Value())
+		var iter kv.Iterator
+		if isTempTable {
+			// The committed data of local temporary tables is kept in the session too,
+			// txn.Iter reads it together with the mem buffer.
+			iter, err = txn.Iter(rg.StartKey, rg.EndKey)
 			if err != nil {
 				return err
 			}
+		} else {
+			iter = txn.GetMemBuffer().SnapshotIter(rg.StartKey, rg.EndKey)
+		}
+		err = iterKVs(iter, fn)
+		iter.Close()
+		if err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
+func iterKVs(iter kv.Iterator, fn processKVFunc) (err error) {
+	for ; iter.Valid(); err = iter.Next() {
+		if err != nil {
+			return err
+		}
+		// check whether the key was been deleted.
+		if len(iter.Value()) == 0 {
+			continue
+		}
+		err = fn(iter.Key(), iter.Value())
+		if err != nil {
+			return err
+		}
+	}
+	return err
+}
+
 func reverseDatumSlice(rows [][]types.Datum) {
 	for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
 		rows[i], rows[j] = rows[j], rows[i]
@@ -28,7 +28,9 @@ import (
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/metrics"
 	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/resourcegroup"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/store/tikv"
 	tikvstore "github.com/pingcap/tidb/store/tikv/kv"
@@ -187,6 +189,9 @@ func (e *PointGetExecutor) Next(ctx context.Context, req *chunk.Chunk) error {
 		return nil
 	}
 	e.done = true
+	if err := consumeReadRequestRU(ctx, e.ctx); err != nil {
+		return err
+	}
 
 	var tblID int64
 	var err error
@@ -375,6 +380,14 @@ func (e *PointGetExecutor) get(ctx context.Context, key kv.Key) ([]byte, error)
 		// fallthrough to snapshot get.
 	}
 
+	if e.ctx.GetSessionVars().IsLocalTemporaryTable(e.tblInfo.ID) {
+		// The committed data of local temporary tables is kept in the session instead of the storage.
+		if tempTables := infoschema.NewTemporaryTableRetriever(e.ctx.GetSessionVars()); tempTables != nil {
+			return tempTables.Get(ctx, key)
+		}
+		return nil, kv.ErrNotExist
+	}
+
 	lock := e.tblInfo.Lock
 	if lock != nil && (lock.Tp == model.TableLockRead || lock.Tp == model.TableLockReadOnly) {
 		if e.ctx.GetSessionVars().EnablePointGetCache {
@@ -605,3 +618,12 @@ func (e *runtimeStatsWithSnapshot) Merge(other execdetails.RuntimeStats) {
 func (e *runtimeStatsWithSnapshot) Tp() int {
 	return execdetails.TpRuntimeStatsWithSnapshot
 }
+
+// consumeReadRequestRU throttles the point get requests by the quota of the resource group of the session.
+func consumeReadRequestRU(ctx context.Context, sctx sessionctx.Context) error {
+	groupName := sctx.GetSessionVars().ResourceGroupName
+	if groupName == "" {
+		return nil
+	}
+	return resourcegroup.GlobalManager().Consume(ctx, groupName, metrics.LblRead, resourcegroup.ReadRequestRU)
+}
@@ -26,8 +26,10 @@ import (
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/masking"
 	"github.com/pingcap/tidb/planner"
 	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/rowpolicy"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
 	driver "github.com/pingcap/tidb/types/parser_driver"
@@ -216,11 +218,17 @@ func (e *PrepareExec) Next(ctx context.Context, req *chunk.Chunk) error {
 
 	normalized, digest := parser.NormalizeDigest(prepared.Stmt.Text())
 	preparedObj := &plannercore.CachedPrepareStmt{
-		PreparedAst:   prepared,
-		VisitInfos:    destBuilder.GetVisitInfo(),
-		NormalizedSQL: normalized,
-		SQLDigest:     digest,
-		ForUpdateRead: destBuilder.GetIsForUpdateRead(),
+		PreparedAst:        prepared,
+		VisitInfos:         destBuilder.GetVisitInfo(),
+		NormalizedSQL:      normalized,
+		SQLDigest:          digest,
+		ForUpdateRead:      destBuilder.GetIsForUpdateRead(),
+		StmtText:           e.sqlText,
+		StmtDB:             vars.CurrentDB,
+		TableRevisions:     plannercore.CollectTableRevisions(stmt),
+		PlanSchemaVersion:  prepared.SchemaVersion,
+		RowPolicyVersion:   rowpolicy.GlobalManager().Version(),
+		MaskingRuleVersion: masking.GlobalManager().Version(),
 	}
 	return vars.AddPreparedStmt(e.ID, preparedObj)
 }
@@ -297,11 +305,10 @@ func (e *DeallocateExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	if !ok {
 		return errors.Errorf("invalid CachedPrepareStmt type")
 	}
-	prepared := preparedObj.PreparedAst
 	delete(vars.PreparedStmtNameToID, e.Name)
 	if plannercore.PreparedPlanCacheEnabled() {
 		e.ctx.PreparedPlanCache().Delete(plannercore.NewPSTMTPlanCacheKey(
-			vars, id, prepared.SchemaVersion,
+			vars, id, preparedObj.PlanSchemaVersion,
 		))
 	}
 	vars.RemovePreparedStmt(id)
@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/testkit"
+	"github.com/pingcap/tidb/util/txninfo"
 )
 
 func (s *testSuite1) TestPreparedNameResolver(c *C) {
@@ -151,6 +152,7 @@ func (sm *mockSessionManager2) Kill(connectionID uint64, query bool) {
 	atomic.StoreUint32(&sm.se.GetSessionVars().Killed, 1)
 }
 func (sm *mockSessionManager2) KillAllConnections()             {}
+func (sm *mockSessionManager2) ShowTxnList() []*txninfo.TxnInfo { return nil }
 func (sm *mockSessionManager2) UpdateTLSConfig(cfg *tls.Config) {}
 func (sm *mockSessionManager2) ServerID() uint64 {
 	return 1
@@ -395,3 +397,64 @@ func (s *testPrepareSuite) TestPlanCacheWithDifferentVariableTypes(c *C) {
 		}
 	}
 }
+
+func (s *testSerialSuite) TestPlanCacheInvalidation(c *C) {
+	store, dom, err := newStoreWithBootstrap()
+	c.Assert(err, IsNil)
+	tk := testkit.NewTestKit(c, store)
+	defer func() {
+		dom.Close()
+		store.Close()
+	}()
+	orgEnable := plannercore.PreparedPlanCacheEnabled()
+	defer func() {
+		plannercore.SetPreparedPlanCache(orgEnable)
+	}()
+	plannercore.SetPreparedPlanCache(true)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("set @@tidb_enable_collect_execution_info=0")
+	tk.MustExec("create table t1(a int, b int, key(b))")
+	tk.MustExec("create table t2(a int)")
+	tk.MustExec("insert into t1 values(1, 1), (2, 2), (3, 3)")
+	tk.MustExec(`prepare stmt from "select a from t1 where b > ?"`)
+	tk.MustExec("set @v = 1")
+	tk.MustQuery("execute stmt using @v").Sort().Check(testkit.Rows("2", "3"))
+	tk.MustQuery("execute stmt using @v").Sort().Check(testkit.Rows("2", "3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustQuery("select stmt_id, table_ids != '', bindings from information_schema.prepared_plan_cache").Check(
+		testkit.Rows("1 1 "))
+
+	// The changes of the unrelated tables don't invalidate the cached plan.
+	tk.MustExec("alter table t2 add column b int")
+	tk.MustQuery("execute stmt using @v").Sort().Check(testkit.Rows("2", "3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The changes of the referenced tables invalidate the cached plan.
+	tk.MustExec("alter table t1 add column c int")
+	tk.MustQuery("execute stmt using @v").Sort().Check(testkit.Rows("2", "3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("execute stmt using @v").Sort().Check(testkit.Rows("2", "3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// Analyzing the referenced tables invalidates the cached plan.
+	tk.MustExec("analyze table t1")
+	tk.MustQuery("execute stmt using @v").Sort().Check(testkit.Rows("2", "3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("execute stmt using @v").Sort().Check(testkit.Rows("2", "3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustExec("analyze table t2")
+	tk.MustQuery("execute stmt using @v").Sort().Check(testkit.Rows("2", "3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// Creating the bindings of the statement invalidates the cached plan.
+	tk.MustExec("create session binding for select a from t1 where b > 1 using select a from t1 ignore index(b) where b > 1")
+	tk.MustQuery("execute stmt using @v").Sort().Check(testkit.Rows("2", "3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("execute stmt using @v").Sort().Check(testkit.Rows("2", "3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustQuery("select bindings != '' from information_schema.prepared_plan_cache").Check(testkit.Rows("1"))
+	tk.MustExec("drop session binding for select a from t1 where b > 1")
+	tk.MustQuery("execute stmt using @v").Sort().Check(testkit.Rows("2", "3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+}
@@ -74,6 +74,10 @@ func (e *ReplaceExec) removeRow(ctx context.Context, txn kv.Transaction, handle
 		}
 		return false, err
 	}
+	// The rows invisible to the user can't be replaced.
+	if err = checkRowPolicy(e.ctx, e.rowPolicyCheck, r.t, oldRow); err != nil {
+		return false, err
+	}
 
 	rowUnchanged, err := e.EqualDatumsAsBinary(e.ctx.GetSessionVars().StmtCtx, oldRow, newRow)
 	if err != nil {
@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/kvcache"
 	"github.com/pingcap/tidb/util/testkit"
+	"github.com/pingcap/tidb/util/txninfo"
 	dto "github.com/prometheus/client_model/go"
 )
 
@@ -812,6 +813,10 @@ func (msm *mockSessionManager1) Kill(cid uint64, query bool) {}
 
 func (msm *mockSessionManager1) KillAllConnections() {}
 
+func (msm *mockSessionManager1) ShowTxnList() []*txninfo.TxnInfo {
+	return nil
+}
+
 func (msm *mockSessionManager1) ServerID() uint64 {
 	return 1
 }
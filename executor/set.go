@@ -15,7 +15,9 @@ package executor
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/pingcap/errors"
@@ -25,7 +27,12 @@ import (
 	"github.com/pingcap/parser/terror"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/infoschema"
+	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/plugin"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/resourcegroup"
+	"github.com/pingcap/tidb/sessionctx/sessionstates"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
@@ -105,7 +112,7 @@ func (e *SetExecutor) Next(ctx context.Context, req *chunk.Chunk) error {
 		syns := e.getSynonyms(name)
 		// Set system variable
 		for _, n := range syns {
-			err := e.setSysVariable(n, v)
+			err := e.setSysVariable(ctx, n, v)
 			if err != nil {
 				return err
 			}
@@ -124,7 +131,7 @@ func (e *SetExecutor) getSynonyms(varName string) []string {
 	return synonyms
 }
 
-func (e *SetExecutor) setSysVariable(name string, v *expression.VarAssignment) error {
+func (e *SetExecutor) setSysVariable(ctx context.Context, name string, v *expression.VarAssignment) error {
 	sessionVars := e.ctx.GetSessionVars()
 	sysVar := variable.GetSysVar(name)
 	if sysVar == nil {
@@ -180,6 +187,9 @@ func (e *SetExecutor) setSysVariable(name string, v *expression.VarAssignment) e
 		if name == variable.TxnIsolationOneShot && sessionVars.InTxn() {
 			return errors.Trace(ErrCantChangeTxCharacteristics)
 		}
+		if name == variable.TiDBSessionStates {
+			return e.setSessionStates(ctx, value)
+		}
 		err = variable.SetSessionSystemVar(sessionVars, name, value)
 		if err != nil {
 			return err
@@ -235,6 +245,90 @@ func (e *SetExecutor) setSysVariable(name string, v *expression.VarAssignment) e
 	return nil
 }
 
+// setSessionStates restores the session states encoded by `select @@tidb_session_states`, which is used to migrate
+// the session from another tidb-server.
+func (e *SetExecutor) setSessionStates(ctx context.Context, value types.Datum) error {
+	if value.IsNull() {
+		return errors.New("the session states can't be NULL")
+	}
+	valStr, err := value.ToString()
+	if err != nil {
+		return err
+	}
+	var sessionStates sessionstates.SessionStates
+	if err = json.Unmarshal([]byte(valStr), &sessionStates); err != nil {
+		return errors.Annotate(err, "invalid session states")
+	}
+	sessionVars := e.ctx.GetSessionVars()
+	for id := range sessionStates.PreparedStmts {
+		if _, ok := sessionVars.PreparedStmts[id]; ok {
+			return errors.Errorf("the prepared statement %d already exists, the session states should be restored in a new session", id)
+		}
+	}
+	if err = sessionVars.DecodeSessionStates(&sessionStates); err != nil {
+		return err
+	}
+	// The system variables are restored as SET does, so that they are checked and take effect in the same way.
+	names := make([]string, 0, len(sessionStates.SystemVars))
+	for name := range sessionStates.SystemVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err = e.setSessionStatesVar(ctx, name, sessionStates.SystemVars[name]); err != nil {
+			return err
+		}
+	}
+	// Preparing the statements resets the statement context, so keep the one of the SET statement.
+	sc, currentDB := sessionVars.StmtCtx, sessionVars.CurrentDB
+	is := infoschema.GetInfoSchema(e.ctx)
+	for id, info := range sessionStates.PreparedStmts {
+		sessionVars.CurrentDB = info.StmtDB
+		prepareExec := NewPrepareExec(e.ctx, is, info.StmtText)
+		prepareExec.ID, prepareExec.name = id, info.Name
+		if err = prepareExec.Next(ctx, nil); err != nil {
+			sessionVars.StmtCtx, sessionVars.CurrentDB = sc, currentDB
+			return err
+		}
+	}
+	sessionVars.StmtCtx, sessionVars.CurrentDB = sc, currentDB
+	sessionVars.SetPreparedStmtID(sessionStates.PreparedStmtID)
+	// The current database is restored as USE does, which checks the privilege of the user.
+	if sessionStates.CurrentDB == "" {
+		sessionVars.CurrentDB = ""
+		return nil
+	}
+	useExec := &SimpleExec{baseExecutor: newBaseExecutor(e.ctx, nil, e.id), is: is}
+	return useExec.executeUse(&ast.UseStmt{DBName: sessionStates.CurrentDB})
+}
+
+// setSessionStatesVar restores a session variable in the session states.
+func (e *SetExecutor) setSessionStatesVar(ctx context.Context, name, value string) error {
+	if name == variable.TiDBSessionStates {
+		return nil
+	}
+	sessionVars := e.ctx.GetSessionVars()
+	// Joining a resource group needs the same privilege as SET.
+	if name == variable.TiDBResourceGroup {
+		if current, err := variable.GetSessionSystemVar(sessionVars, name); err == nil && current == value {
+			return nil
+		}
+		checker := privilege.GetPrivilegeManager(e.ctx)
+		if checker != nil && !checker.RequestDynamicVerification(sessionVars.ActiveRoles, resourcegroup.AdminPriv, false) {
+			return plannercore.ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or " + resourcegroup.AdminPriv)
+		}
+	}
+	assign := &expression.VarAssignment{
+		Name:     name,
+		IsSystem: true,
+		Expr: &expression.Constant{
+			Value:   types.NewStringDatum(value),
+			RetType: types.NewFieldType(mysql.TypeString),
+		},
+	}
+	return e.setSysVariable(ctx, name, assign)
+}
+
 func (e *SetExecutor) setCharset(cs, co string, isSetName bool) error {
 	var err error
 	if len(co) == 0 {
@@ -59,6 +59,7 @@ import (
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/format"
+	"github.com/pingcap/tidb/util/fulltext"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/hint"
 	"github.com/pingcap/tidb/util/set"
@@ -480,7 +481,11 @@ func (e *ShowExec) fetchShowColumns(ctx context.Context) error {
 	if e.Extended {
 		cols = tb.Cols()
 	} else {
-		cols = tb.VisibleCols()
+		for _, col := range tb.Cols() {
+			if !tables.IsHiddenToSession(e.ctx.GetSessionVars(), col.ColumnInfo) {
+				cols = append(cols, col)
+			}
+		}
 	}
 	if err := tryFillViewColumnType(ctx, e.ctx, e.is, e.DBName, tb.Meta()); err != nil {
 		return err
@@ -551,14 +556,16 @@ func (e *ShowExec) fetchShowIndex() error {
 		return e.tableAccessDenied("SELECT", tb.Meta().Name.O)
 	}
 
+	var pkCol *table.Column
 	if tb.Meta().PKIsHandle {
-		var pkCol *table.Column
 		for _, col := range tb.Cols() {
 			if mysql.HasPriKeyFlag(col.Flag) {
 				pkCol = col
 				break
 			}
 		}
+	}
+	if pkCol != nil && !tables.IsHiddenToSession(e.ctx.GetSessionVars(), pkCol.ColumnInfo) {
 		e.appendRow([]interface{}{
 			tb.Meta().Name.O, // Table
 			0,                // Non_unique
@@ -689,7 +696,7 @@ func (e *ShowExec) fetchShowVariables() (err error) {
 	// If it is a session only variable, use the default value defined in code,
 	//   otherwise, fetch the value from table `mysql.Global_Variables`.
 	for _, v := range variable.GetSysVars() {
-		if variable.FilterImplicitFeatureSwitch(v) {
+		if variable.FilterImplicitFeatureSwitch(v) || variable.IsSessionStatesVar(v.Name) {
 			continue
 		}
 		value, err = variable.GetSessionSystemVar(sessionVars, v.Name)
@@ -755,12 +762,18 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 	}
 
 	sqlMode := ctx.GetSessionVars().SQLMode
-	fmt.Fprintf(buf, "CREATE TABLE %s (\n", stringutil.Escape(tableInfo.Name.O, sqlMode))
+	if ctx.GetSessionVars().IsLocalTemporaryTable(tableInfo.ID) {
+		fmt.Fprintf(buf, "CREATE TEMPORARY TABLE %s (\n", stringutil.Escape(tableInfo.Name.O, sqlMode))
+	} else {
+		fmt.Fprintf(buf, "CREATE TABLE %s (\n", stringutil.Escape(tableInfo.Name.O, sqlMode))
+	}
 	var pkCol *model.ColumnInfo
 	var hasAutoIncID bool
 	needAddComma := false
+	var hasHiddenGIPK bool
 	for i, col := range tableInfo.Cols() {
-		if col.Hidden {
+		if tables.IsHiddenToSession(ctx.GetSessionVars(), col) {
+			hasHiddenGIPK = hasHiddenGIPK || tables.IsGeneratedInvisiblePK(col)
 			continue
 		}
 		if needAddComma {
@@ -874,6 +887,8 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 			buf.WriteString("  PRIMARY KEY ")
 		} else if idxInfo.Unique {
 			fmt.Fprintf(buf, "  UNIQUE KEY %s ", stringutil.Escape(idxInfo.Name.O, sqlMode))
+		} else if fulltext.IsIndex(idxInfo) {
+			fmt.Fprintf(buf, "  FULLTEXT KEY %s ", stringutil.Escape(idxInfo.Name.O, sqlMode))
 		} else {
 			fmt.Fprintf(buf, "  KEY %s ", stringutil.Escape(idxInfo.Name.O, sqlMode))
 		}
@@ -973,7 +988,8 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 			return errors.Trace(err)
 		}
 
-		if autoRandID > 1 {
+		// The AUTO_RANDOM_BASE belongs to the generated invisible primary key if it's hidden.
+		if autoRandID > 1 && !hasHiddenGIPK {
 			fmt.Fprintf(buf, " /*T![auto_rand_base] AUTO_RANDOM_BASE=%d */", autoRandID)
 		}
 	}
@@ -989,6 +1005,16 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 	if len(tableInfo.Comment) > 0 {
 		fmt.Fprintf(buf, " COMMENT='%s'", format.OutputFormat(tableInfo.Comment))
 	}
+
+	if ttlInfo := tableInfo.TTLInfo; ttlInfo != nil {
+		fmt.Fprintf(buf, " /*T![ttl] TTL=%s + INTERVAL %d %s */", stringutil.Escape(ttlInfo.ColumnName.O, sqlMode), ttlInfo.IntervalValue, ttlInfo.IntervalUnit)
+		enable := "ON"
+		if !ttlInfo.Enable {
+			enable = "OFF"
+		}
+		fmt.Fprintf(buf, " /*T![ttl] TTL_ENABLE='%s' */", enable)
+		fmt.Fprintf(buf, " /*T![ttl] TTL_JOB_WINDOW_START='%s' TTL_JOB_WINDOW_END='%s' */", ttlInfo.JobWindowStart, ttlInfo.JobWindowEnd)
+	}
 	// add partition info here.
 	appendPartitionInfo(tableInfo.Partition, buf)
 	return nil
@@ -1327,9 +1353,33 @@ func (e *ShowExec) fetchShowCreateUser() error {
 		}
 		require = privValue.RequireStr()
 	}
+	authPlugin, _ := checker.GetAuthPlugin(e.User.Username, e.User.Hostname)
+	var resourceOptions string
+	maxUserConns, maxConnsPerHour := checker.GetConnectionLimits(e.User.Username, e.User.Hostname)
+	if maxConnsPerHour > 0 {
+		resourceOptions += fmt.Sprintf(" MAX_CONNECTIONS_PER_HOUR %d", maxConnsPerHour)
+	}
+	if maxUserConns > 0 {
+		resourceOptions += fmt.Sprintf(" MAX_USER_CONNECTIONS %d", maxUserConns)
+	}
+	if resourceOptions != "" {
+		resourceOptions = " WITH" + resourceOptions
+	}
+	accountLock := "UNLOCK"
+	if locking, ok := checker.GetPasswordLocking(e.User.Username, e.User.Hostname); ok && locking.AccountLocked {
+		accountLock = "LOCK"
+	}
+	passwordExpire := "DEFAULT"
+	if expiration, ok := checker.GetPasswordExpiration(e.User.Username, e.User.Hostname); ok {
+		if expiration.Lifetime == 0 {
+			passwordExpire = "NEVER"
+		} else if expiration.Lifetime > 0 {
+			passwordExpire = fmt.Sprintf("INTERVAL %d DAY", expiration.Lifetime)
+		}
+	}
 	// FIXME: the returned string is not escaped safely
-	showStr := fmt.Sprintf("CREATE USER '%s'@'%s' IDENTIFIED WITH 'mysql_native_password' AS '%s' REQUIRE %s PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK",
-		e.User.Username, e.User.Hostname, checker.GetEncodedPassword(e.User.Username, e.User.Hostname), require)
+	showStr := fmt.Sprintf("CREATE USER '%s'@'%s' IDENTIFIED WITH '%s' AS '%s' REQUIRE %s%s PASSWORD EXPIRE %s ACCOUNT %s",
+		e.User.Username, e.User.Hostname, authPlugin, checker.GetEncodedPassword(e.User.Username, e.User.Hostname), require, resourceOptions, passwordExpire, accountLock)
 	e.appendRow([]interface{}{showStr})
 	return nil
 }
@@ -1403,6 +1453,9 @@ func (e *ShowExec) fetchShowPrivileges() error {
 	e.appendRow([]interface{}{"Create tablespace", "Server Admin", "To create/alter/drop tablespaces"})
 	e.appendRow([]interface{}{"Update", "Tables", "To update existing rows"})
 	e.appendRow([]interface{}{"Usage", "Server Admin", "No privileges - allow connect only"})
+	for _, priv := range privilege.DynamicPrivileges() {
+		e.appendRow([]interface{}{priv, "Server Admin", ""})
+	}
 	return nil
 }
 
@@ -104,6 +104,10 @@ func (e *ShowExec) appendTableForStatsExtended(dbName string, tbl *model.TableIn
 func (e *ShowExec) fetchShowStatsMeta() error {
 	do := domain.GetDomain(e.ctx)
 	h := do.StatsHandle()
+	locked, err := h.GetLockedTables()
+	if err != nil {
+		return err
+	}
 	dbs := do.InfoSchema().AllSchemas()
 	for _, db := range dbs {
 		for _, tbl := range db.Tables {
@@ -113,15 +117,15 @@ func (e *ShowExec) fetchShowStatsMeta() error {
 				if pi != nil {
 					partitionName = "global"
 				}
-				e.appendTableForStatsMeta(db.Name.O, tbl.Name.O, partitionName, h.GetTableStats(tbl))
+				e.appendTableForStatsMeta(db.Name.O, tbl.Name.O, partitionName, h.GetTableStats(tbl), locked)
 				if pi != nil {
 					for _, def := range pi.Definitions {
-						e.appendTableForStatsMeta(db.Name.O, tbl.Name.O, def.Name.O, h.GetPartitionStats(tbl, def.ID))
+						e.appendTableForStatsMeta(db.Name.O, tbl.Name.O, def.Name.O, h.GetPartitionStats(tbl, def.ID), locked)
 					}
 				}
 			} else {
 				for _, def := range pi.Definitions {
-					e.appendTableForStatsMeta(db.Name.O, tbl.Name.O, def.Name.O, h.GetPartitionStats(tbl, def.ID))
+					e.appendTableForStatsMeta(db.Name.O, tbl.Name.O, def.Name.O, h.GetPartitionStats(tbl, def.ID), locked)
 				}
 			}
 		}
@@ -129,10 +133,14 @@ func (e *ShowExec) fetchShowStatsMeta() error {
 	return nil
 }
 
-func (e *ShowExec) appendTableForStatsMeta(dbName, tblName, partitionName string, statsTbl *statistics.Table) {
+func (e *ShowExec) appendTableForStatsMeta(dbName, tblName, partitionName string, statsTbl *statistics.Table, locked map[int64]struct{}) {
 	if statsTbl.Pseudo {
 		return
 	}
+	isLocked := 0
+	if _, ok := locked[statsTbl.PhysicalID]; ok {
+		isLocked = 1
+	}
 	e.appendRow([]interface{}{
 		dbName,
 		tblName,
@@ -140,6 +148,7 @@ func (e *ShowExec) appendTableForStatsMeta(dbName, tblName, partitionName string
 		e.versionToTime(statsTbl.Version),
 		statsTbl.ModifyCount,
 		statsTbl.Count,
+		isLocked,
 	})
 }
 
@@ -426,15 +435,10 @@ func (e *ShowExec) fetchShowStatsHealthy() {
 }
 
 func (e *ShowExec) appendTableForStatsHealthy(dbName, tblName, partitionName string, statsTbl *statistics.Table) {
-	if statsTbl.Pseudo {
+	healthy, ok := statsTbl.GetStatsHealthy()
+	if !ok {
 		return
 	}
-	var healthy int64
-	if statsTbl.ModifyCount < statsTbl.Count {
-		healthy = int64((1.0 - float64(statsTbl.ModifyCount)/float64(statsTbl.Count)) * 100.0)
-	} else if statsTbl.ModifyCount == 0 {
-		healthy = 100
-	}
 	e.appendRow([]interface{}{
 		dbName,
 		tblName,
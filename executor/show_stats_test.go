@@ -227,6 +227,7 @@ func (s *testShowStatsSuite) TestShowAnalyzeStatus(c *C) {
 	c.Assert(result.Rows()[0][5], NotNil)
 	c.Assert(result.Rows()[0][6], NotNil)
 	c.Assert(result.Rows()[0][7], Equals, "finished")
+	c.Assert(result.Rows()[0][8], Equals, "100")
 
 	c.Assert(len(result.Rows()), Equals, 2)
 	c.Assert(result.Rows()[1][0], Equals, "test")
@@ -237,6 +238,7 @@ func (s *testShowStatsSuite) TestShowAnalyzeStatus(c *C) {
 	c.Assert(result.Rows()[1][5], NotNil)
 	c.Assert(result.Rows()[1][6], NotNil)
 	c.Assert(result.Rows()[1][7], Equals, "finished")
+	c.Assert(result.Rows()[1][8], Equals, "100")
 }
 
 func (s *testShowStatsSuite) TestShowStatusSnapshot(c *C) {
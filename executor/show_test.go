@@ -435,6 +435,23 @@ func (s *testSuite5) TestShowCreateUser(c *C) {
 	rows.Check(testkit.Rows("CREATE USER 'check_priv'@'127.0.0.1' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK"))
 }
 
+func (s *testSuite5) TestConnectionLimitsOfUser(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec(`CREATE USER 'test_conn_limits'@'%' IDENTIFIED BY 'root' WITH MAX_USER_CONNECTIONS 3 MAX_QUERIES_PER_HOUR 10`)
+	defer tk.MustExec(`DROP USER 'test_conn_limits'@'%'`)
+	tk.MustQuery("select max_connections, max_user_connections from mysql.user where user = 'test_conn_limits'").Check(testkit.Rows("0 3"))
+	tk.MustQuery("show create user 'test_conn_limits'@'%'").
+		Check(testkit.Rows(`CREATE USER 'test_conn_limits'@'%' IDENTIFIED WITH 'mysql_native_password' AS '*81F5E21E35407D884A6CD4A731AEBFB6AF209E1B' REQUIRE NONE WITH MAX_USER_CONNECTIONS 3 PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK`))
+
+	// The password is kept when only the resource options are altered.
+	tk.MustExec(`ALTER USER 'test_conn_limits'@'%' WITH MAX_CONNECTIONS_PER_HOUR 20`)
+	tk.MustQuery("show create user 'test_conn_limits'@'%'").
+		Check(testkit.Rows(`CREATE USER 'test_conn_limits'@'%' IDENTIFIED WITH 'mysql_native_password' AS '*81F5E21E35407D884A6CD4A731AEBFB6AF209E1B' REQUIRE NONE WITH MAX_CONNECTIONS_PER_HOUR 20 MAX_USER_CONNECTIONS 3 PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK`))
+	tk.MustExec(`ALTER USER 'test_conn_limits'@'%' IDENTIFIED BY '' WITH MAX_CONNECTIONS_PER_HOUR 0 MAX_USER_CONNECTIONS 0`)
+	tk.MustQuery("show create user 'test_conn_limits'@'%'").
+		Check(testkit.Rows(`CREATE USER 'test_conn_limits'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK`))
+}
+
 func (s *testSuite5) TestUnprivilegedShow(c *C) {
 
 	tk := testkit.NewTestKit(c, s.store)
@@ -834,12 +851,12 @@ func (s *testSuite5) TestShowCreateTable(c *C) {
 
 	// Test Foreign keys + ON DELETE / ON UPDATE
 	tk.MustExec(`DROP TABLE child`)
-	tk.MustExec(`CREATE TABLE child (id INT NOT NULL PRIMARY KEY auto_increment, parent_id INT NOT NULL, INDEX par_ind (parent_id), CONSTRAINT child_ibfk_1 FOREIGN KEY (parent_id) REFERENCES parent(id) ON DELETE SET NULL ON UPDATE CASCADE)`)
+	tk.MustExec(`CREATE TABLE child (id INT NOT NULL PRIMARY KEY auto_increment, parent_id INT, INDEX par_ind (parent_id), CONSTRAINT child_ibfk_1 FOREIGN KEY (parent_id) REFERENCES parent(id) ON DELETE SET NULL ON UPDATE CASCADE)`)
 	tk.MustQuery(`show create table child`).Check(testutil.RowsWithSep("|",
 		""+
 			"child CREATE TABLE `child` (\n"+
 			"  `id` int(11) NOT NULL AUTO_INCREMENT,\n"+
-			"  `parent_id` int(11) NOT NULL,\n"+
+			"  `parent_id` int(11) DEFAULT NULL,\n"+
 			"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */,\n"+
 			"  KEY `par_ind` (`parent_id`),\n"+
 			"  CONSTRAINT `child_ibfk_1` FOREIGN KEY (`parent_id`) REFERENCES `parent` (`id`) ON DELETE SET NULL ON UPDATE CASCADE\n"+
@@ -1102,9 +1119,9 @@ func (s *testSuite5) TestShowBuiltin(c *C) {
 	res := tk.MustQuery("show builtins;")
 	c.Assert(res, NotNil)
 	rows := res.Rows()
-	c.Assert(268, Equals, len(rows))
+	c.Assert(288, Equals, len(rows))
 	c.Assert("abs", Equals, rows[0][0].(string))
-	c.Assert("yearweek", Equals, rows[267][0].(string))
+	c.Assert("yearweek", Equals, rows[287][0].(string))
 }
 
 func (s *testSuite5) TestShowClusterConfig(c *C) {
@@ -1236,7 +1253,7 @@ func (s *testSuite5) TestShowVar(c *C) {
 	sessionVars := make([]string, 0, len(variable.GetSysVars()))
 	globalVars := make([]string, 0, len(variable.GetSysVars()))
 	for _, v := range variable.GetSysVars() {
-		if variable.FilterImplicitFeatureSwitch(v) {
+		if variable.FilterImplicitFeatureSwitch(v) || variable.IsSessionStatesVar(v.Name) {
 			continue
 		}
 
@@ -1271,6 +1288,10 @@ func (s *testSuite5) TestShowVar(c *C) {
 		res = tk.MustQuery("show global variables like '" + one + "'")
 		c.Check(res.Rows(), HasLen, 0)
 	}
+
+	// The variables used to migrate the session are not shown.
+	tk.MustQuery("show variables like 'tidb_session_%'").Check(testkit.Rows())
+	tk.MustQuery("select * from information_schema.session_variables where variable_name like 'tidb_session_%'").Check(testkit.Rows())
 }
 
 func (s *testSuite5) TestIssue19507(c *C) {
@@ -15,8 +15,15 @@ package executor
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -25,19 +32,24 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/auth"
+	"github.com/pingcap/parser/format"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/config"
-	"github.com/pingcap/tidb/distsql"
 	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/domain/infosync"
 	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
-	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/masking"
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/plugin"
 	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/privilege/privileges"
+	"github.com/pingcap/tidb/resourcegroup"
+	"github.com/pingcap/tidb/rowpolicy"
+	"github.com/pingcap/tidb/server/audit"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	tikvstore "github.com/pingcap/tidb/store/tikv/kv"
@@ -47,11 +59,10 @@ import (
 	driver "github.com/pingcap/tidb/types/parser_driver"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/gcutil"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/sqlexec"
-	"github.com/pingcap/tidb/util/timeutil"
-	"github.com/pingcap/tipb/go-tipb"
 	"go.uber.org/zap"
 )
 
@@ -63,7 +74,7 @@ var (
 // SimpleExec represents simple statement executor.
 // For statements do simple execution.
 // includes `UseStmt`, 'SetStmt`, `DoStmt`,
-// `BeginStmt`, `CommitStmt`, `RollbackStmt`.
+// `BeginStmt`, `CommitStmt`, `RollbackStmt`, `SavepointStmt`, `ReleaseSavepointStmt`.
 // TODO: list all simple statements.
 type SimpleExec struct {
 	baseExecutor
@@ -104,6 +115,7 @@ func (e *baseExecutor) releaseSysSession(ctx sessionctx.Context) {
 
 // Next implements the Executor Next interface.
 func (e *SimpleExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
+	req.Reset()
 	if e.done {
 		return nil
 	}
@@ -131,6 +143,26 @@ func (e *SimpleExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 		e.executeCommit(x)
 	case *ast.RollbackStmt:
 		err = e.executeRollback(x)
+	case *ast.SavepointStmt:
+		err = e.executeSavepoint(x)
+	case *ast.ReleaseSavepointStmt:
+		err = e.executeReleaseSavepoint(x)
+	case *ast.XAStmt:
+		err = e.executeXA(ctx, x, req)
+	case *ast.CreateResourceGroupStmt:
+		err = e.executeCreateResourceGroup(x)
+	case *ast.AlterResourceGroupStmt:
+		err = e.executeAlterResourceGroup(x)
+	case *ast.DropResourceGroupStmt:
+		err = e.executeDropResourceGroup(x)
+	case *ast.CreateRowPolicyStmt:
+		err = e.executeCreateRowPolicy(x)
+	case *ast.DropRowPolicyStmt:
+		err = e.executeDropRowPolicy(x)
+	case *ast.CreateMaskingRuleStmt:
+		err = e.executeCreateMaskingRule(x)
+	case *ast.DropMaskingRuleStmt:
+		err = e.executeDropMaskingRule(x)
 	case *ast.CreateUserStmt:
 		err = e.executeCreateUser(ctx, x)
 	case *ast.AlterUserStmt:
@@ -620,8 +652,9 @@ func (e *SimpleExec) executeBegin(ctx context.Context, s *ast.BeginStmt) error {
 func (e *SimpleExec) executeStartTransactionReadOnlyWithTimestampBound(ctx context.Context, s *ast.BeginStmt) error {
 	opt := sessionctx.StalenessTxnOption{}
 	opt.Mode = s.Bound.Mode
+	var readTS uint64
 	switch s.Bound.Mode {
-	case ast.TimestampBoundReadTimestamp:
+	case ast.TimestampBoundReadTimestamp, ast.TimestampBoundMinReadTimestamp:
 		// TODO: support funcCallExpr in future
 		v, ok := s.Bound.Timestamp.(*driver.ValueExpr)
 		if !ok {
@@ -635,9 +668,15 @@ func (e *SimpleExec) executeStartTransactionReadOnlyWithTimestampBound(ctx conte
 		if err != nil {
 			return err
 		}
+		if gt.After(time.Now()) {
+			return errors.New("cannot set read timestamp to a future time")
+		}
+		// The data at the min read timestamp is the stalest one allowed, which is most likely to be served by the
+		// closest replica.
 		startTS := oracle.ComposeTS(gt.Unix()*1000, 0)
 		opt.StartTS = startTS
-	case ast.TimestampBoundExactStaleness:
+		readTS = startTS
+	case ast.TimestampBoundExactStaleness, ast.TimestampBoundMaxStaleness:
 		// TODO: support funcCallExpr in future
 		v, ok := s.Bound.Timestamp.(*driver.ValueExpr)
 		if !ok {
@@ -647,7 +686,14 @@ func (e *SimpleExec) executeStartTransactionReadOnlyWithTimestampBound(ctx conte
 		if err != nil {
 			return err
 		}
+		// The max staleness is used as the exact staleness for the same reason as the min read timestamp.
 		opt.PrevSec = uint64(d.Seconds())
+		readTS = oracle.GoTimeToTS(time.Now().Add(-d.Duration))
+	}
+	if readTS > 0 {
+		if err := gcutil.ValidateStaleReadTS(e.ctx, readTS); err != nil {
+			return err
+		}
 	}
 	err := e.ctx.NewTxnWithStalenessOption(ctx, opt)
 	if err != nil {
@@ -750,6 +796,9 @@ func (e *SimpleExec) executeCommit(s *ast.CommitStmt) {
 }
 
 func (e *SimpleExec) executeRollback(s *ast.RollbackStmt) error {
+	if s.SavepointName != "" {
+		return e.executeRollbackToSavepoint(s)
+	}
 	sessVars := e.ctx.GetSessionVars()
 	logutil.BgLogger().Debug("execute rollback statement", zap.Uint64("conn", sessVars.ConnectionID))
 	sessVars.SetInTxn(false)
@@ -770,6 +819,90 @@ func (e *SimpleExec) executeRollback(s *ast.RollbackStmt) error {
 	return nil
 }
 
+// executeSavepoint sets a savepoint in the current transaction, the savepoint with the same name is replaced.
+// It does nothing outside a transaction, like MySQL.
+func (e *SimpleExec) executeSavepoint(s *ast.SavepointStmt) error {
+	sessVars := e.ctx.GetSessionVars()
+	if !sessVars.InTxn() {
+		return nil
+	}
+	if sessVars.BinlogClient != nil {
+		return errors.New("SAVEPOINT is not supported when binlog is enabled")
+	}
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	sessVars.TxnCtx.AddSavepoint(s.Name, txn.GetMemBuffer().Checkpoint())
+	return nil
+}
+
+// executeRollbackToSavepoint discards the modifications made after the savepoint, the savepoints set after it are
+// removed. The pessimistic locks acquired after the savepoint are kept until the transaction ends.
+func (e *SimpleExec) executeRollbackToSavepoint(s *ast.RollbackStmt) error {
+	sessVars := e.ctx.GetSessionVars()
+	if !sessVars.InTxn() {
+		return ErrSavepointNotExists.GenWithStackByArgs("SAVEPOINT", s.SavepointName)
+	}
+	record := sessVars.TxnCtx.RollbackToSavepoint(s.SavepointName)
+	if record == nil {
+		return ErrSavepointNotExists.GenWithStackByArgs("SAVEPOINT", s.SavepointName)
+	}
+	txn, err := e.ctx.Txn(false)
+	if err != nil {
+		return err
+	}
+	if txn.Valid() {
+		txn.RevertToMemCheckpoint(record.MemCheckpoint)
+	}
+	// The statement history doesn't contain the partial rollback, so the transaction can't be retried.
+	sessVars.TxnCtx.CouldRetry = false
+	return nil
+}
+
+func (e *SimpleExec) executeReleaseSavepoint(s *ast.ReleaseSavepointStmt) error {
+	sessVars := e.ctx.GetSessionVars()
+	if !sessVars.InTxn() || !sessVars.TxnCtx.ReleaseSavepoint(s.Name) {
+		return ErrSavepointNotExists.GenWithStackByArgs("SAVEPOINT", s.Name)
+	}
+	return nil
+}
+
+func (e *SimpleExec) executeXA(ctx context.Context, s *ast.XAStmt, req *chunk.Chunk) error {
+	xa, ok := e.ctx.(sqlexec.XATxnExecutor)
+	if !ok {
+		return errors.New("XA transactions are not supported by the session")
+	}
+	switch s.Tp {
+	case ast.XAStart:
+		return xa.XAStart(ctx, *s.XID)
+	case ast.XAEnd:
+		return xa.XAEnd(*s.XID)
+	case ast.XAPrepare:
+		return xa.XAPrepare(ctx, *s.XID)
+	case ast.XACommit:
+		return xa.XACommit(ctx, *s.XID, s.OnePhase)
+	case ast.XARollback:
+		return xa.XARollback(ctx, *s.XID)
+	case ast.XARecover:
+		xids, err := xa.XARecover(ctx)
+		if err != nil {
+			return err
+		}
+		for _, xid := range xids {
+			req.AppendInt64(0, xid.FormatID)
+			req.AppendInt64(1, int64(len(xid.GTRID)))
+			req.AppendInt64(2, int64(len(xid.BQUAL)))
+			if s.ConvertXID {
+				req.AppendString(3, "0x"+strings.ToUpper(hex.EncodeToString([]byte(xid.GTRID+xid.BQUAL))))
+			} else {
+				req.AppendString(3, xid.GTRID+xid.BQUAL)
+			}
+		}
+	}
+	return nil
+}
+
 func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStmt) error {
 	// Check `CREATE USER` privilege.
 	if !config.GetGlobalConfig().Security.SkipGrantTable {
@@ -796,15 +929,38 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 		return err
 	}
 
+	plugins, err := authPluginsOfUserSpecs(e.ctx, s.Specs)
+	if err != nil {
+		return err
+	}
+	defaultPlugin, err := defaultAuthPlugin(e.ctx)
+	if err != nil {
+		return err
+	}
+	limits := connectionLimitsOfResourceOptions(s.ResourceOptions)
+	accountLocked := "N"
+	if lock, _ := accountLockOfOptions(s.PasswordOrLockOptions); lock {
+		accountLocked = "Y"
+	}
+	expire, lifetime, _, err := passwordExpireOfOptions(s.PasswordOrLockOptions)
+	if err != nil {
+		return err
+	}
+	passwordExpired := "N"
+	if expire {
+		passwordExpired = "Y"
+	}
+
 	sql := new(strings.Builder)
 	if s.IsCreateRole {
-		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string, Account_locked) VALUES `, mysql.SystemDB, mysql.UserTable)
+		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string, plugin, Account_locked) VALUES `, mysql.SystemDB, mysql.UserTable)
 	} else {
-		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string) VALUES `, mysql.SystemDB, mysql.UserTable)
+		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string, plugin, max_connections, max_user_connections, Account_locked, password_expired, password_lifetime) VALUES `, mysql.SystemDB, mysql.UserTable)
 	}
 
 	users := make([]*auth.UserIdentity, 0, len(s.Specs))
-	for _, spec := range s.Specs {
+	passwords := make([]string, 0, len(s.Specs))
+	for i, spec := range s.Specs {
 		if len(users) > 0 {
 			sqlexec.MustFormatSQL(sql, ",")
 		}
@@ -824,16 +980,27 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 			e.ctx.GetSessionVars().StmtCtx.AppendNote(err)
 			continue
 		}
-		pwd, ok := spec.EncodedPassword()
+		plugin := plugins[i]
+		if plugin == "" {
+			plugin = defaultPlugin
+		}
+		if !s.IsCreateRole {
+			if err := validateUserPassword(e.ctx, spec, plugin); err != nil {
+				return err
+			}
+		}
+		pwd, ok := encodeUserPassword(spec, plugin)
 		if !ok {
 			return errors.Trace(ErrPasswordFormat)
 		}
 		if s.IsCreateRole {
-			sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?)`, spec.User.Hostname, spec.User.Username, pwd, "Y")
+			sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?, %?)`, spec.User.Hostname, spec.User.Username, pwd, plugin, "Y")
 		} else {
-			sqlexec.MustFormatSQL(sql, `(%?, %?, %?)`, spec.User.Hostname, spec.User.Username, pwd)
+			sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?, %?, %?, %?, %?, %?)`, spec.User.Hostname, spec.User.Username, pwd, plugin,
+				limits[ast.MaxConnectionsPerHour], limits[ast.MaxUserConnections], accountLocked, passwordExpired, lifetime)
 		}
 		users = append(users, spec.User)
+		passwords = append(passwords, pwd)
 	}
 	if len(users) == 0 {
 		return nil
@@ -876,10 +1043,72 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 	if _, err := sqlExecutor.ExecuteInternal(context.TODO(), "commit"); err != nil {
 		return errors.Trace(err)
 	}
+	if !s.IsCreateRole {
+		for i, user := range users {
+			if err := privileges.RecordPasswordHistory(e.ctx, user.Username, user.Hostname, passwords[i]); err != nil {
+				return err
+			}
+		}
+	}
 	domain.GetDomain(e.ctx).NotifyUpdatePrivilege(e.ctx)
 	return err
 }
 
+// accountLockOfOptions returns whether the options lock or unlock the accounts, the last ACCOUNT LOCK or ACCOUNT UNLOCK
+// wins. specified is false if neither of them is specified.
+func accountLockOfOptions(opts []*ast.PasswordOrLockOption) (lock bool, specified bool) {
+	for _, opt := range opts {
+		switch opt.Type {
+		case ast.Lock:
+			lock, specified = true, true
+		case ast.Unlock:
+			lock, specified = false, true
+		}
+	}
+	return lock, specified
+}
+
+// passwordExpireOfOptions returns the password expiration specified in the options, expire is true if the password
+// is expired manually by PASSWORD EXPIRE. lifetime is the password lifetime in days to be stored in mysql.user, nil
+// for PASSWORD EXPIRE DEFAULT and 0 for PASSWORD EXPIRE NEVER, lifetimeSpecified is false if none of them is
+// specified. Like the account lock, the last lifetime wins.
+func passwordExpireOfOptions(opts []*ast.PasswordOrLockOption) (expire bool, lifetime interface{}, lifetimeSpecified bool, err error) {
+	for _, opt := range opts {
+		switch opt.Type {
+		case ast.PasswordExpire:
+			expire = true
+		case ast.PasswordExpireDefault:
+			lifetime, lifetimeSpecified = nil, true
+		case ast.PasswordExpireNever:
+			lifetime, lifetimeSpecified = 0, true
+		case ast.PasswordExpireInterval:
+			if opt.Count <= 0 || opt.Count > math.MaxUint16 {
+				return false, nil, false, errors.Errorf("invalid PASSWORD EXPIRE INTERVAL %d DAY, it should be between 1 and %d", opt.Count, math.MaxUint16)
+			}
+			lifetime, lifetimeSpecified = opt.Count, true
+		}
+	}
+	return expire, lifetime, lifetimeSpecified, nil
+}
+
+// connectionLimitColumns are the columns of mysql.user which store the connection limits of the resource options.
+var connectionLimitColumns = map[int]string{
+	ast.MaxConnectionsPerHour: "max_connections",
+	ast.MaxUserConnections:    "max_user_connections",
+}
+
+// connectionLimitsOfResourceOptions returns the connection limits specified in the resource options, keyed by the
+// option types. MAX_QUERIES_PER_HOUR and MAX_UPDATES_PER_HOUR are ignored.
+func connectionLimitsOfResourceOptions(opts []*ast.ResourceOption) map[int]int64 {
+	limits := make(map[int]int64, len(opts))
+	for _, opt := range opts {
+		if _, ok := connectionLimitColumns[opt.Type]; ok {
+			limits[opt.Type] = opt.Count
+		}
+	}
+	return limits
+}
+
 func (e *SimpleExec) executeAlterUser(s *ast.AlterUserStmt) error {
 	if s.CurrentAuth != nil {
 		user := e.ctx.GetSessionVars().User
@@ -901,8 +1130,19 @@ func (e *SimpleExec) executeAlterUser(s *ast.AlterUserStmt) error {
 		return err
 	}
 
+	plugins, err := authPluginsOfUserSpecs(e.ctx, s.Specs)
+	if err != nil {
+		return err
+	}
+	limits := connectionLimitsOfResourceOptions(s.ResourceOptions)
+	lock, lockSpecified := accountLockOfOptions(s.PasswordOrLockOptions)
+	expire, lifetime, lifetimeSpecified, err := passwordExpireOfOptions(s.PasswordOrLockOptions)
+	if err != nil {
+		return err
+	}
+
 	failedUsers := make([]string, 0, len(s.Specs))
-	for _, spec := range s.Specs {
+	for i, spec := range s.Specs {
 		if spec.User.CurrentUser {
 			user := e.ctx.GetSessionVars().User
 			spec.User.Username = user.Username
@@ -918,22 +1158,99 @@ func (e *SimpleExec) executeAlterUser(s *ast.AlterUserStmt) error {
 			failedUsers = append(failedUsers, user)
 			continue
 		}
-		pwd, ok := spec.EncodedPassword()
-		if !ok {
-			return errors.Trace(ErrPasswordFormat)
-		}
 		exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
-		stmt, err := exec.ParseWithParams(context.TODO(), `UPDATE %n.%n SET authentication_string=%? WHERE Host=%? and User=%?;`, mysql.SystemDB, mysql.UserTable, pwd, spec.User.Hostname, spec.User.Username)
-		if err != nil {
-			return err
+		// Like MySQL, the password is kept if the statement doesn't specify it, e.g. ALTER USER ... WITH MAX_USER_CONNECTIONS 1.
+		if spec.AuthOpt != nil || (len(s.ResourceOptions) == 0 && !lockSpecified && !expire && !lifetimeSpecified && s.ResourceGroupNameOption == nil) {
+			plugin := plugins[i]
+			if plugin == "" {
+				if plugin, err = userAuthPlugin(e.ctx, spec.User.Username, spec.User.Hostname); err != nil {
+					return err
+				}
+			}
+			if err := validateUserPassword(e.ctx, spec, plugin); err != nil {
+				return err
+			}
+			if err := checkUserPasswordReuse(e.ctx, spec, plugin); err != nil {
+				return err
+			}
+			pwd, ok := encodeUserPassword(spec, plugin)
+			if !ok {
+				return errors.Trace(ErrPasswordFormat)
+			}
+			stmt, err := exec.ParseWithParams(context.TODO(), `UPDATE %n.%n SET authentication_string=%?, plugin=%?, password_expired='N', password_last_changed=NOW() WHERE Host=%? and User=%?;`, mysql.SystemDB, mysql.UserTable, pwd, plugin, spec.User.Hostname, spec.User.Username)
+			if err != nil {
+				return err
+			}
+			_, _, err = exec.ExecRestrictedStmt(context.TODO(), stmt)
+			if err == nil {
+				err = privileges.RecordPasswordHistory(e.ctx, spec.User.Username, spec.User.Hostname, pwd)
+			}
+			if err != nil {
+				failedUsers = append(failedUsers, spec.User.String())
+			}
 		}
-		_, _, err = exec.ExecRestrictedStmt(context.TODO(), stmt)
-		if err != nil {
-			failedUsers = append(failedUsers, spec.User.String())
+
+		if expire || lifetimeSpecified {
+			sql := new(strings.Builder)
+			sqlexec.MustFormatSQL(sql, `UPDATE %n.%n SET `, mysql.SystemDB, mysql.UserTable)
+			if expire {
+				sqlexec.MustFormatSQL(sql, `password_expired='Y'`)
+			}
+			if lifetimeSpecified {
+				if expire {
+					sqlexec.MustFormatSQL(sql, ", ")
+				}
+				sqlexec.MustFormatSQL(sql, `password_lifetime=%?`, lifetime)
+			}
+			sqlexec.MustFormatSQL(sql, ` WHERE Host=%? and User=%?;`, spec.User.Hostname, spec.User.Username)
+			stmt, err := exec.ParseWithParams(context.TODO(), sql.String())
+			if err != nil {
+				return err
+			}
+			_, _, err = exec.ExecRestrictedStmt(context.TODO(), stmt)
+			if err != nil {
+				failedUsers = append(failedUsers, spec.User.String())
+			}
+		}
+
+		if len(limits) > 0 {
+			sql := new(strings.Builder)
+			sqlexec.MustFormatSQL(sql, `UPDATE %n.%n SET `, mysql.SystemDB, mysql.UserTable)
+			first := true
+			for _, tp := range []int{ast.MaxConnectionsPerHour, ast.MaxUserConnections} {
+				if v, ok := limits[tp]; ok {
+					if !first {
+						sqlexec.MustFormatSQL(sql, ", ")
+					}
+					sqlexec.MustFormatSQL(sql, `%n=%?`, connectionLimitColumns[tp], v)
+					first = false
+				}
+			}
+			sqlexec.MustFormatSQL(sql, ` WHERE Host=%? and User=%?;`, spec.User.Hostname, spec.User.Username)
+			stmt, err := exec.ParseWithParams(context.TODO(), sql.String())
+			if err != nil {
+				return err
+			}
+			_, _, err = exec.ExecRestrictedStmt(context.TODO(), stmt)
+			if err != nil {
+				failedUsers = append(failedUsers, spec.User.String())
+			}
+		}
+
+		if lockSpecified {
+			if err := e.lockOrUnlockAccount(spec.User, lock); err != nil {
+				failedUsers = append(failedUsers, spec.User.String())
+			}
+		}
+
+		if s.ResourceGroupNameOption != nil {
+			if err := e.bindResourceGroup(spec.User, s.ResourceGroupNameOption.Value); err != nil {
+				return err
+			}
 		}
 
 		if len(privData) > 0 {
-			stmt, err = exec.ParseWithParams(context.TODO(), "INSERT INTO %n.%n (Host, User, Priv) VALUES (%?,%?,%?) ON DUPLICATE KEY UPDATE Priv = values(Priv)", mysql.SystemDB, mysql.GlobalPrivTable, spec.User.Hostname, spec.User.Username, string(hack.String(privData)))
+			stmt, err := exec.ParseWithParams(context.TODO(), "INSERT INTO %n.%n (Host, User, Priv) VALUES (%?,%?,%?) ON DUPLICATE KEY UPDATE Priv = values(Priv)", mysql.SystemDB, mysql.GlobalPrivTable, spec.User.Hostname, spec.User.Username, string(hack.String(privData)))
 			if err != nil {
 				return err
 			}
@@ -965,6 +1282,192 @@ func (e *SimpleExec) executeAlterUser(s *ast.AlterUserStmt) error {
 	return nil
 }
 
+// bindResourceGroup binds the user account to the resource group, the new connections of the account use the
+// resource group. The resourcegroup.DefaultGroupName unbinds the account.
+func (e *SimpleExec) bindResourceGroup(user *auth.UserIdentity, name string) error {
+	sysSession, err := e.getSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.releaseSysSession(sysSession)
+	manager := resourcegroup.GlobalManager()
+	if resourcegroup.NormalizeName(name) == resourcegroup.DefaultGroupName {
+		return manager.UnbindUser(sysSession, user.Username, user.Hostname)
+	}
+	return manager.BindUser(sysSession, user.Username, user.Hostname, name)
+}
+
+// resourceGroupOptions returns the RU_PER_SEC and the PRIORITY of the options, the RU_PER_SEC is -1 and the PRIORITY
+// is empty if they are not specified.
+func resourceGroupOptions(options []*ast.ResourceGroupOption) (ruPerSec int64, priority string, err error) {
+	ruPerSec = -1
+	for _, op := range options {
+		switch op.Tp {
+		case ast.ResourceRUPerSec:
+			if op.UintValue > math.MaxInt64 {
+				return 0, "", errors.Errorf("invalid RU_PER_SEC %d", op.UintValue)
+			}
+			ruPerSec = int64(op.UintValue)
+		case ast.ResourcePriority:
+			priority = op.StrValue
+		}
+	}
+	return ruPerSec, priority, nil
+}
+
+func (e *SimpleExec) executeCreateResourceGroup(s *ast.CreateResourceGroupStmt) error {
+	ruPerSec, priority, err := resourceGroupOptions(s.Options)
+	if err != nil {
+		return err
+	}
+	if ruPerSec < 0 {
+		// The group is unlimited if RU_PER_SEC isn't specified.
+		ruPerSec = 0
+	}
+	sysSession, err := e.getSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.releaseSysSession(sysSession)
+	return resourcegroup.GlobalManager().CreateGroup(sysSession, s.ResourceGroupName.O, ruPerSec, priority, s.IfNotExists)
+}
+
+func (e *SimpleExec) executeAlterResourceGroup(s *ast.AlterResourceGroupStmt) error {
+	ruPerSec, priority, err := resourceGroupOptions(s.Options)
+	if err != nil {
+		return err
+	}
+	sysSession, err := e.getSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.releaseSysSession(sysSession)
+	return resourcegroup.GlobalManager().AlterGroup(sysSession, s.ResourceGroupName.O, ruPerSec, priority)
+}
+
+func (e *SimpleExec) executeDropResourceGroup(s *ast.DropResourceGroupStmt) error {
+	sysSession, err := e.getSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.releaseSysSession(sysSession)
+	return resourcegroup.GlobalManager().DropGroup(sysSession, s.ResourceGroupName.O, s.IfExists)
+}
+
+// resolveTableName returns the database and the name of the table in the row policy and masking rule statements, the
+// table is in the current database if the statement doesn't specify its database.
+func (e *SimpleExec) resolveTableName(tn *ast.TableName) (db, table string, err error) {
+	db = tn.Schema.O
+	if db == "" {
+		db = e.ctx.GetSessionVars().CurrentDB
+	}
+	if db == "" {
+		return "", "", core.ErrNoDB
+	}
+	return db, tn.Name.O, nil
+}
+
+func (e *SimpleExec) executeCreateRowPolicy(s *ast.CreateRowPolicyStmt) error {
+	db, table, err := e.resolveTableName(s.Table)
+	if err != nil {
+		return err
+	}
+	user := s.User
+	if user.CurrentUser {
+		user = &auth.UserIdentity{Username: e.ctx.GetSessionVars().User.AuthUsername, Hostname: e.ctx.GetSessionVars().User.AuthHostname}
+	}
+	var sb strings.Builder
+	if err = s.Using.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+		return err
+	}
+	policy := rowpolicy.Policy{
+		Name:  s.PolicyName.O,
+		DB:    db,
+		Table: table,
+		User:  user.Username,
+		Host:  user.Hostname,
+		Using: sb.String(),
+	}
+	sysSession, err := e.getSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.releaseSysSession(sysSession)
+	return rowpolicy.GlobalManager().CreatePolicy(sysSession, e.is, policy, s.OrReplace)
+}
+
+func (e *SimpleExec) executeDropRowPolicy(s *ast.DropRowPolicyStmt) error {
+	db, table, err := e.resolveTableName(s.Table)
+	if err != nil {
+		return err
+	}
+	sysSession, err := e.getSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.releaseSysSession(sysSession)
+	return rowpolicy.GlobalManager().DropPolicy(sysSession, db, table, s.PolicyName.O, s.IfExists)
+}
+
+func (e *SimpleExec) executeCreateMaskingRule(s *ast.CreateMaskingRuleStmt) error {
+	db, table, err := e.resolveTableName(s.Table)
+	if err != nil {
+		return err
+	}
+	var sb strings.Builder
+	if err = s.Expr.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+		return err
+	}
+	rule := masking.Rule{
+		DB:     db,
+		Table:  table,
+		Column: s.Column.O,
+		Expr:   sb.String(),
+	}
+	sysSession, err := e.getSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.releaseSysSession(sysSession)
+	return masking.GlobalManager().CreateRule(sysSession, e.is, rule, s.OrReplace)
+}
+
+func (e *SimpleExec) executeDropMaskingRule(s *ast.DropMaskingRuleStmt) error {
+	db, table, err := e.resolveTableName(s.Table)
+	if err != nil {
+		return err
+	}
+	sysSession, err := e.getSysSession()
+	if err != nil {
+		return err
+	}
+	defer e.releaseSysSession(sysSession)
+	return masking.GlobalManager().DropRule(sysSession, db, table, s.Column.O, s.IfExists)
+}
+
+// lockOrUnlockAccount locks or unlocks the account by ACCOUNT LOCK or ACCOUNT UNLOCK. Unlocking an account also
+// unlocks it if it's locked by the failed logins, and resets its failed login count.
+func (e *SimpleExec) lockOrUnlockAccount(user *auth.UserIdentity, lock bool) error {
+	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
+	sql := `UPDATE %n.%n SET Account_locked='Y' WHERE Host=%? and User=%?;`
+	typ, reason := audit.TypeAccountLock, "ACCOUNT LOCK"
+	if !lock {
+		sql = `UPDATE %n.%n SET Account_locked='N', failed_login_count=0, auto_locked_time=NULL WHERE Host=%? and User=%?;`
+		typ, reason = audit.TypeAccountUnlock, "ACCOUNT UNLOCK"
+	}
+	stmt, err := exec.ParseWithParams(context.TODO(), sql, mysql.SystemDB, mysql.UserTable, user.Hostname, user.Username)
+	if err != nil {
+		return err
+	}
+	if _, _, err = exec.ExecRestrictedStmt(context.TODO(), stmt); err != nil {
+		return err
+	}
+	if audit.Enabled() {
+		audit.Log(audit.NewAccountEvent(typ, e.ctx.GetSessionVars().ConnectionID, user.Username, user.Hostname, reason))
+	}
+	return nil
+}
+
 func (e *SimpleExec) executeGrantRole(s *ast.GrantRoleStmt) error {
 	sessionVars := e.ctx.GetSessionVars()
 	for i, user := range s.Users {
@@ -1148,6 +1651,14 @@ func (e *SimpleExec) executeDropUser(s *ast.DropUserStmt) error {
 			break
 		}
 
+		// delete the previous passwords from mysql.password_history
+		sql.Reset()
+		sqlexec.MustFormatSQL(sql, `DELETE FROM %n.%n WHERE Host = %? and User = %?;`, mysql.SystemDB, "password_history", user.Hostname, user.Username)
+		if _, err = sqlExecutor.ExecuteInternal(context.TODO(), sql.String()); err != nil {
+			failedUsers = append(failedUsers, user.String())
+			break
+		}
+
 		//TODO: need delete columns_priv once we implement columns_priv functionality.
 	}
 
@@ -1206,14 +1717,36 @@ func (e *SimpleExec) executeSetPwd(s *ast.SetPwdStmt) error {
 		return errors.Trace(ErrPasswordNoMatch)
 	}
 
+	plugin, err := userAuthPlugin(e.ctx, u, h)
+	if err != nil {
+		return err
+	}
+	if privileges.IsLDAPAuthPlugin(plugin) {
+		return ErrSetPasswordAuthPlugin.GenWithStackByArgs()
+	}
+	if err := validatePassword(e.ctx, u, s.Password); err != nil {
+		return err
+	}
+	if err := checkPasswordReuse(e.ctx, u, h, s.Password); err != nil {
+		return err
+	}
+
 	// update mysql.user
+	pwd := encodePassword(s.Password, plugin)
 	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
-	stmt, err := exec.ParseWithParams(context.TODO(), `UPDATE %n.%n SET authentication_string=%? WHERE User=%? AND Host=%?;`, mysql.SystemDB, mysql.UserTable, auth.EncodePassword(s.Password), u, h)
+	stmt, err := exec.ParseWithParams(context.TODO(), `UPDATE %n.%n SET authentication_string=%?, password_expired='N', password_last_changed=NOW() WHERE User=%? AND Host=%?;`, mysql.SystemDB, mysql.UserTable, pwd, u, h)
 	if err != nil {
 		return err
 	}
 	_, _, err = exec.ExecRestrictedStmt(context.TODO(), stmt)
+	if err == nil {
+		err = privileges.RecordPasswordHistory(e.ctx, u, h, pwd)
+	}
 	domain.GetDomain(e.ctx).NotifyUpdatePrivilege(e.ctx)
+	// The session leaves the sandbox mode after the expired password is reset.
+	if user := e.ctx.GetSessionVars().User; err == nil && user != nil && user.AuthUsername == u && user.AuthHostname == h {
+		e.ctx.GetSessionVars().InSandBoxMode = false
+	}
 	return err
 }
 
@@ -1272,46 +1805,65 @@ func (e *SimpleExec) executeKillStmt(ctx context.Context, s *ast.KillStmt) error
 	return nil
 }
 
-func killRemoteConn(ctx context.Context, sctx sessionctx.Context, connID *util.GlobalConnID, query bool) error {
+// killRemoteConn kills the connection or the running query on the tidb-server identified by the serverID of the
+// connection, by the status API of that tidb-server. The current user is sent along and checked by the remote
+// tidb-server.
+func killRemoteConn(ctx context.Context, sctx sessionctx.Context, connID *util.GlobalConnID, query bool) (retErr error) {
 	if connID.ServerID == 0 {
 		return errors.New("Unexpected ZERO ServerID. Please file a bug to the TiDB Team")
 	}
 
-	killExec := &tipb.Executor{
-		Tp:   tipb.ExecType_TypeKill,
-		Kill: &tipb.Kill{ConnID: connID.ID(), Query: query},
+	serversInfo, err := infosync.GetAllServerInfo(ctx)
+	if err != nil {
+		return err
 	}
-
-	dagReq := &tipb.DAGRequest{}
-	dagReq.TimeZoneName, dagReq.TimeZoneOffset = timeutil.Zone(sctx.GetSessionVars().Location())
-	sc := sctx.GetSessionVars().StmtCtx
-	if sc.RuntimeStatsColl != nil {
-		collExec := true
-		dagReq.CollectExecutionSummaries = &collExec
+	var target *infosync.ServerInfo
+	for _, info := range serversInfo {
+		if info.JSONServerID == connID.ServerID {
+			target = info
+			break
+		}
+	}
+	if target == nil {
+		return errors.Errorf("no tidb-server with serverID %d is found", connID.ServerID)
 	}
-	dagReq.Flags = sc.PushDownFlags()
-	dagReq.Executors = []*tipb.Executor{killExec}
 
-	var builder distsql.RequestBuilder
-	kvReq, err := builder.
-		SetDAGRequest(dagReq).
-		SetFromSessionVars(sctx.GetSessionVars()).
-		SetStoreType(kv.TiDB).
-		SetTiDBServerID(connID.ServerID).
-		Build()
+	// The remote tidb-server checks whether the user can kill the connection.
+	user := sctx.GetSessionVars().User
+	if user == nil {
+		return errors.New("no user is found to kill the remote connection")
+	}
+	form := url.Values{}
+	form.Set("query", strconv.FormatBool(query))
+	form.Set("user", user.AuthUsername)
+	form.Set("host", user.AuthHostname)
+	statusAddr := net.JoinHostPort(target.IP, strconv.FormatUint(uint64(target.StatusPort), 10))
+	killURL := fmt.Sprintf("%s://%s/connections/%d/kill", util.InternalHTTPSchema(), statusAddr, connID.ID())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, killURL, strings.NewReader(form.Encode()))
 	if err != nil {
 		return err
 	}
-
-	resp := sctx.GetClient().Send(ctx, kvReq, sctx.GetSessionVars().KVVars, sctx.GetSessionVars().StmtCtx.MemTracker, false)
-	if resp == nil {
-		err := errors.New("client returns nil response")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := util.InternalHTTPClient().Do(req)
+	if err != nil {
 		return err
 	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		message, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		return errors.Errorf("request %s failed: %s", killURL, message)
+	}
 
 	logutil.BgLogger().Info("Killed remote connection", zap.Uint64("serverID", connID.ServerID),
 		zap.Uint64("connID", connID.ID()), zap.Bool("query", query))
-	return err
+	return nil
 }
 
 func (e *SimpleExec) executeFlush(s *ast.FlushStmt) error {
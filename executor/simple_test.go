@@ -164,6 +164,77 @@ func (s *testSuite3) TestTransaction(c *C) {
 	tk.MustQuery("select * from txn").Check(testkit.Rows("1", "2"))
 }
 
+func (s *testSuite3) TestSavepoint(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, v int, key(v))")
+	tk.MustExec("insert into t values (10, 10)")
+	tbl, err := domain.GetDomain(tk.Se).InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tid := tbl.Meta().ID
+
+	// The savepoints can't be used outside a transaction.
+	tk.MustExec("savepoint s1")
+	err = tk.ExecToErr("rollback to s1")
+	c.Assert(executor.ErrSavepointNotExists.Equal(err), IsTrue)
+	c.Assert(err.Error(), Matches, ".*SAVEPOINT s1 does not exist")
+
+	for _, mode := range []string{"optimistic", "pessimistic"} {
+		tk.MustExec("begin " + mode)
+		tk.MustExec("insert into t values (1, 1)")
+		tk.MustExec("savepoint s1")
+		tk.MustExec("insert into t values (2, 2)")
+		tk.MustExec("update t set v = 100 where id = 1")
+		tk.MustExec("delete from t where id = 10")
+		tk.MustExec("savepoint s2")
+		tk.MustExec("insert into t values (3, 3)")
+		c.Assert(tk.Se.GetSessionVars().TxnCtx.TableDeltaMap[tid].Delta, Equals, int64(2))
+
+		// The savepoint names are case-insensitive.
+		tk.MustExec("rollback to savepoint S1")
+		c.Assert(inTxn(tk.Se), IsTrue)
+		tk.MustQuery("select * from t").Check(testkit.Rows("1 1", "10 10"))
+		tk.MustQuery("select * from t use index(v) where v > 0").Check(testkit.Rows("1 1", "10 10"))
+		c.Assert(tk.Se.GetSessionVars().TxnCtx.TableDeltaMap[tid].Delta, Equals, int64(1))
+		// The savepoints after the rolled back one are removed.
+		err = tk.ExecToErr("rollback to s2")
+		c.Assert(executor.ErrSavepointNotExists.Equal(err), IsTrue)
+		// The savepoint is kept after rolling back to it.
+		tk.MustExec("insert into t values (4, 4)")
+		tk.MustExec("rollback to s1")
+		tk.MustExec("insert into t values (5, 5)")
+		tk.MustExec("commit")
+		tk.MustQuery("select * from t").Check(testkit.Rows("1 1", "5 5", "10 10"))
+		tk.MustExec("admin check table t")
+		tk.MustExec("delete from t where id < 10")
+	}
+
+	// Releasing a savepoint removes it and the savepoints after it.
+	tk.MustExec("begin")
+	tk.MustExec("savepoint s1")
+	tk.MustExec("savepoint s2")
+	tk.MustExec("release savepoint s1")
+	err = tk.ExecToErr("release savepoint s2")
+	c.Assert(executor.ErrSavepointNotExists.Equal(err), IsTrue)
+	tk.MustExec("rollback")
+
+	// The pessimistic locks acquired after the savepoint are kept.
+	tk.MustExec("begin pessimistic")
+	tk.MustExec("savepoint s1")
+	tk.MustExec("update t set v = 11 where id = 10")
+	tk.MustExec("rollback to savepoint s1")
+	tk.MustQuery("select * from t").Check(testkit.Rows("10 10"))
+	tk2 := testkit.NewTestKit(c, s.store)
+	tk2.MustExec("use test")
+	tk2.MustExec("set @@innodb_lock_wait_timeout = 1")
+	tk2.MustExec("begin pessimistic")
+	c.Assert(tk2.ExecToErr("update t set v = 12 where id = 10"), NotNil)
+	tk2.MustExec("rollback")
+	tk.MustExec("commit")
+	tk.MustQuery("select * from t").Check(testkit.Rows("10 10"))
+}
+
 func inTxn(ctx sessionctx.Context) bool {
 	return (ctx.GetSessionVars().Status & mysql.ServerStatusInTrans) > 0
 }
@@ -512,6 +583,142 @@ func (s *testSuite3) TestSetPwd(c *C) {
 
 }
 
+func (s *testSuite3) TestValidatePassword(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("CREATE USER 'vpuser'@'localhost' IDENTIFIED BY 'weak'")
+	defer tk.MustExec("DROP USER 'vpuser'@'localhost'")
+
+	// The validation is disabled by default.
+	tk.MustQuery("SELECT VALIDATE_PASSWORD_STRENGTH('Abcd1234!')").Check(testkit.Rows("0"))
+	tk.MustExec("SET GLOBAL validate_password_enable = ON")
+	defer tk.MustExec("SET GLOBAL validate_password_enable = DEFAULT")
+	tk.MustQuery("SELECT VALIDATE_PASSWORD_STRENGTH('abc'), VALIDATE_PASSWORD_STRENGTH('abcd'), VALIDATE_PASSWORD_STRENGTH('abcdefgh'), " +
+		"VALIDATE_PASSWORD_STRENGTH('Abcd1234!'), VALIDATE_PASSWORD_STRENGTH(NULL)").Check(testkit.Rows("0 25 50 100 <nil>"))
+
+	err := tk.ExecToErr("CREATE USER 'vpuser2'@'localhost' IDENTIFIED BY 'abcdefgh'")
+	c.Assert(terror.ErrorEqual(err, executor.ErrNotValidPassword), IsTrue, Commentf("err %v", err))
+	tk.MustQuery("SHOW WARNINGS").Check(testkit.Rows("Warning 1105 Require Password Uppercase Count: 1",
+		"Error 1819 Your password does not satisfy the current policy requirements"))
+	err = tk.ExecToErr("ALTER USER 'vpuser'@'localhost' IDENTIFIED BY 'Abcd123'")
+	c.Assert(terror.ErrorEqual(err, executor.ErrNotValidPassword), IsTrue, Commentf("err %v", err))
+	err = tk.ExecToErr("SET PASSWORD FOR 'vpuser'@'localhost' = 'Abcd1234'")
+	c.Assert(terror.ErrorEqual(err, executor.ErrNotValidPassword), IsTrue, Commentf("err %v", err))
+	tk.MustExec("SET PASSWORD FOR 'vpuser'@'localhost' = 'Abcd1234!'")
+	tk.MustExec("ALTER USER 'vpuser'@'localhost' IDENTIFIED BY 'Abcd1234#'")
+	// The hashed passwords and the roles aren't validated.
+	tk.MustExec("ALTER USER 'vpuser'@'localhost' IDENTIFIED BY PASSWORD '*6BB4837EB74329105EE4568DDA7DC67ED2CA2AD9'")
+	tk.MustExec("CREATE ROLE 'vprole'")
+	tk.MustExec("DROP ROLE 'vprole'")
+
+	// The low policy only requires the length.
+	tk.MustExec("SET GLOBAL validate_password_policy = LOW")
+	defer tk.MustExec("SET GLOBAL validate_password_policy = DEFAULT")
+	tk.MustExec("SET PASSWORD FOR 'vpuser'@'localhost' = 'abcdefgh'")
+	tk.MustExec("SET GLOBAL validate_password_length = 10")
+	defer tk.MustExec("SET GLOBAL validate_password_length = DEFAULT")
+	err = tk.ExecToErr("SET PASSWORD FOR 'vpuser'@'localhost' = 'abcdefghi'")
+	c.Assert(terror.ErrorEqual(err, executor.ErrNotValidPassword), IsTrue, Commentf("err %v", err))
+
+	// The password can't be the user name or its reverse.
+	tk.MustExec("SET GLOBAL validate_password_length = 6")
+	tk.MustExec("SET GLOBAL validate_password_check_user_name = ON")
+	defer tk.MustExec("SET GLOBAL validate_password_check_user_name = DEFAULT")
+	err = tk.ExecToErr("ALTER USER 'vpuser'@'localhost' IDENTIFIED BY 'RESUPV'")
+	c.Assert(terror.ErrorEqual(err, executor.ErrNotValidPassword), IsTrue, Commentf("err %v", err))
+	tk.MustExec("ALTER USER 'vpuser'@'localhost' IDENTIFIED BY 'vpuser1'")
+
+	// The strong policy checks the substrings against the dictionary.
+	tk.MustExec("SET GLOBAL validate_password_policy = STRONG")
+	tk.MustExec("SET GLOBAL validate_password_dictionary = 'secret;tidb'")
+	defer tk.MustExec("SET GLOBAL validate_password_dictionary = DEFAULT")
+	err = tk.ExecToErr("ALTER USER 'vpuser'@'localhost' IDENTIFIED BY 'My-TiDB-123'")
+	c.Assert(terror.ErrorEqual(err, executor.ErrNotValidPassword), IsTrue, Commentf("err %v", err))
+	tk.MustQuery("SELECT VALIDATE_PASSWORD_STRENGTH('My-TiDB-123'), VALIDATE_PASSWORD_STRENGTH('My-Pass-123')").Check(testkit.Rows("75 100"))
+	tk.MustExec("ALTER USER 'vpuser'@'localhost' IDENTIFIED BY 'My-Pass-123'")
+}
+
+func (s *testSuite3) TestPasswordHistory(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("CREATE USER 'phuser'@'localhost' IDENTIFIED BY 'pwd1'")
+	defer tk.MustExec("DROP USER IF EXISTS 'phuser'@'localhost'")
+	countSQL := "SELECT COUNT(*) FROM mysql.password_history WHERE User = 'phuser' AND Host = 'localhost'"
+	// The passwords aren't recorded by default.
+	tk.MustExec("SET PASSWORD FOR 'phuser'@'localhost' = 'pwd1'")
+	tk.MustQuery(countSQL).Check(testkit.Rows("0"))
+
+	// The last password_history passwords can't be reused.
+	tk.MustExec("SET GLOBAL password_history = 2")
+	defer tk.MustExec("SET GLOBAL password_history = DEFAULT")
+	tk.MustExec("SET PASSWORD FOR 'phuser'@'localhost' = 'pwd2'")
+	tk.MustExec("ALTER USER 'phuser'@'localhost' IDENTIFIED BY 'pwd3'")
+	tk.MustQuery(countSQL).Check(testkit.Rows("2"))
+	err := tk.ExecToErr("SET PASSWORD FOR 'phuser'@'localhost' = 'pwd2'")
+	c.Assert(terror.ErrorEqual(err, executor.ErrCredentialsContradictToHistory), IsTrue, Commentf("err %v", err))
+	err = tk.ExecToErr("ALTER USER 'phuser'@'localhost' IDENTIFIED BY 'pwd3'")
+	c.Assert(terror.ErrorEqual(err, executor.ErrCredentialsContradictToHistory), IsTrue, Commentf("err %v", err))
+	tk.MustExec("ALTER USER 'phuser'@'localhost' IDENTIFIED BY 'pwd1'")
+	tk.MustQuery(countSQL).Check(testkit.Rows("2"))
+	tk.MustExec("ALTER USER 'phuser'@'localhost' IDENTIFIED WITH 'caching_sha2_password' BY 'pwd4'")
+	err = tk.ExecToErr("ALTER USER 'phuser'@'localhost' IDENTIFIED WITH 'mysql_native_password' BY 'pwd4'")
+	c.Assert(terror.ErrorEqual(err, executor.ErrCredentialsContradictToHistory), IsTrue, Commentf("err %v", err))
+
+	// The passwords used within password_reuse_interval days can't be reused either.
+	tk.MustExec("SET GLOBAL password_history = 0")
+	tk.MustExec("SET GLOBAL password_reuse_interval = 1")
+	defer tk.MustExec("SET GLOBAL password_reuse_interval = DEFAULT")
+	tk.MustExec("SET PASSWORD FOR 'phuser'@'localhost' = 'pwd5'")
+	tk.MustQuery(countSQL).Check(testkit.Rows("3"))
+	err = tk.ExecToErr("SET PASSWORD FOR 'phuser'@'localhost' = 'pwd1'")
+	c.Assert(terror.ErrorEqual(err, executor.ErrCredentialsContradictToHistory), IsTrue, Commentf("err %v", err))
+	tk.MustExec("UPDATE mysql.password_history SET Password_timestamp = Password_timestamp - INTERVAL 2 DAY WHERE User = 'phuser'")
+	tk.MustExec("SET PASSWORD FOR 'phuser'@'localhost' = 'pwd1'")
+	tk.MustQuery(countSQL).Check(testkit.Rows("1"))
+
+	// The hashed passwords aren't checked, and the history is removed with the user.
+	tk.MustExec("ALTER USER 'phuser'@'localhost' IDENTIFIED WITH 'mysql_native_password' AS '*6BB4837EB74329105EE4568DDA7DC67ED2CA2AD9'")
+	tk.MustExec("ALTER USER 'phuser'@'localhost' IDENTIFIED WITH 'mysql_native_password' AS '*6BB4837EB74329105EE4568DDA7DC67ED2CA2AD9'")
+	tk.MustQuery(countSQL).Check(testkit.Rows("3"))
+	tk.MustExec("DROP USER 'phuser'@'localhost'")
+	tk.MustQuery(countSQL).Check(testkit.Rows("0"))
+}
+
+func (s *testSuite3) TestPasswordExpire(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("CREATE USER 'peuser'@'localhost' PASSWORD EXPIRE INTERVAL 30 DAY")
+	defer tk.MustExec("DROP USER IF EXISTS 'peuser'@'localhost'")
+	querySQL := "SELECT password_expired, password_lifetime FROM mysql.user WHERE User = 'peuser' AND Host = 'localhost'"
+	tk.MustQuery(querySQL).Check(testkit.Rows("N 30"))
+	tk.MustExec("ALTER USER 'peuser'@'localhost' PASSWORD EXPIRE NEVER")
+	tk.MustQuery(querySQL).Check(testkit.Rows("N 0"))
+	tk.MustExec("ALTER USER 'peuser'@'localhost' PASSWORD EXPIRE")
+	tk.MustQuery(querySQL).Check(testkit.Rows("Y 0"))
+	tk.MustExec("ALTER USER 'peuser'@'localhost' PASSWORD EXPIRE DEFAULT")
+	tk.MustQuery(querySQL).Check(testkit.Rows("Y <nil>"))
+	// Changing the password makes it unexpired.
+	tk.MustExec("SET PASSWORD FOR 'peuser'@'localhost' = 'pwd'")
+	tk.MustQuery(querySQL).Check(testkit.Rows("N <nil>"))
+	err := tk.ExecToErr("ALTER USER 'peuser'@'localhost' PASSWORD EXPIRE INTERVAL 0 DAY")
+	c.Assert(err, NotNil)
+}
+
+func (s *testSuite3) TestLDAPUser(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	// The authentication strings of the LDAP users aren't passwords, so they aren't validated.
+	tk.MustExec("SET GLOBAL validate_password_enable = ON")
+	defer tk.MustExec("SET GLOBAL validate_password_enable = DEFAULT")
+	tk.MustExec("CREATE USER 'ldapuser'@'localhost' IDENTIFIED WITH 'authentication_ldap_simple' AS 'uid=ldapuser,ou=People,dc=example,dc=com#dev=r1@localhost'")
+	defer tk.MustExec("DROP USER IF EXISTS 'ldapuser'@'localhost'")
+	tk.MustQuery("SELECT plugin, authentication_string FROM mysql.user WHERE User = 'ldapuser'").Check(testkit.Rows(
+		"authentication_ldap_simple uid=ldapuser,ou=People,dc=example,dc=com#dev=r1@localhost"))
+	tk.MustExec("ALTER USER 'ldapuser'@'localhost' IDENTIFIED WITH 'authentication_ldap_sasl' BY '+uid=ldapuser,ou=People'")
+	tk.MustQuery("SELECT plugin, authentication_string FROM mysql.user WHERE User = 'ldapuser'").Check(testkit.Rows(
+		"authentication_ldap_sasl +uid=ldapuser,ou=People"))
+	err := tk.ExecToErr("ALTER USER 'ldapuser'@'localhost' IDENTIFIED WITH 'authentication_ldap_sasl' AS '#dev'")
+	c.Assert(err, NotNil)
+	err = tk.ExecToErr("SET PASSWORD FOR 'ldapuser'@'localhost' = 'pwd'")
+	c.Assert(terror.ErrorEqual(err, executor.ErrSetPasswordAuthPlugin), IsTrue, Commentf("err %v", err))
+}
+
 func (s *testSuite3) TestKillStmt(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -16,6 +16,7 @@ package executor
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -316,6 +317,15 @@ func (e *slowQueryRetriever) getBatchLog(ctx context.Context, reader *bufio.Read
 				return [][]string{log}, err
 			}
 			line = string(hack.String(lineByte))
+			if isJSONSlowLog(line) {
+				lines, err := expandJSONSlowLog(line)
+				if err != nil {
+					// The line may be being written, skip it like the malformed text lines.
+					continue
+				}
+				log = append(log, lines...)
+				break
+			}
 			log = append(log, line)
 			if strings.HasSuffix(line, variable.SlowLogSQLSuffixStr) {
 				if strings.HasPrefix(line, "use") || strings.HasPrefix(line, variable.SlowLogRowPrefixStr) {
@@ -368,6 +378,17 @@ func (e *slowQueryRetriever) getBatchLogForReversedScan(ctx context.Context, rea
 			return nil, err
 		}
 		line = string(hack.String(lineByte))
+		if isJSONSlowLog(line) {
+			lines, err := expandJSONSlowLog(line)
+			if err != nil {
+				continue
+			}
+			logs = append(logs, lines)
+			if scanPreviousFile {
+				break
+			}
+			continue
+		}
 		if !hasStartFlag && strings.HasPrefix(line, variable.SlowLogStartPrefixStr) {
 			hasStartFlag = true
 		}
@@ -389,6 +410,50 @@ func (e *slowQueryRetriever) getBatchLogForReversedScan(ctx context.Context, rea
 	return decomposeToSlowLogTasks(logs, num), err
 }
 
+// isJSONSlowLog checks whether the line is an entry of the JSON slow log.
+func isJSONSlowLog(line string) bool {
+	return strings.HasPrefix(line, "{")
+}
+
+// expandJSONSlowLog expands an entry of the JSON slow log into the lines of the text format, so they're parsed in the
+// same way. The time is the first line and the statement is the last line.
+func expandJSONSlowLog(line string) ([]string, error) {
+	decoder := json.NewDecoder(strings.NewReader(line))
+	decoder.UseNumber()
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+	valueOf := func(key string) string {
+		switch v := fields[key].(type) {
+		case string:
+			return v
+		case json.Number:
+			return v.String()
+		case bool:
+			return strconv.FormatBool(v)
+		}
+		return ""
+	}
+	t, sql := valueOf(variable.SlowLogTimeStr), valueOf(variable.SlowLogQuerySQLStr)
+	if t == "" || !strings.HasSuffix(sql, variable.SlowLogSQLSuffixStr) {
+		return nil, errors.Errorf("invalid JSON slow log %s", line)
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		if key != variable.SlowLogTimeStr && key != variable.SlowLogQuerySQLStr {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	lines := make([]string, 0, len(keys)+2)
+	lines = append(lines, variable.SlowLogStartPrefixStr+t)
+	for _, key := range keys {
+		lines = append(lines, variable.SlowLogRowPrefixStr+key+variable.SlowLogSpaceMarkStr+valueOf(key))
+	}
+	return append(lines, sql), nil
+}
+
 func decomposeToSlowLogTasks(logs []slowLogBlock, num int) [][]string {
 	if len(logs) == 0 {
 		return nil
@@ -648,11 +713,13 @@ type slowQueryTuple struct {
 	succ                      bool
 	planFromCache             bool
 	planFromBinding           bool
+	isSampled                 bool
 	prepared                  bool
 	kvTotal                   float64
 	pdTotal                   float64
 	backoffTotal              float64
 	writeSQLRespTotal         float64
+	writeSQLRespBytes         uint64
 	plan                      string
 	planDigest                string
 	backoffDetail             string
@@ -808,6 +875,8 @@ func (st *slowQueryTuple) setFieldValue(tz *time.Location, field, value string,
 		st.planFromCache, err = strconv.ParseBool(value)
 	case variable.SlowLogPlanFromBinding:
 		st.planFromBinding, err = strconv.ParseBool(value)
+	case variable.SlowLogIsSampled:
+		st.isSampled, err = strconv.ParseBool(value)
 	case variable.SlowLogPlan:
 		st.plan = value
 	case variable.SlowLogPlanDigest:
@@ -824,6 +893,8 @@ func (st *slowQueryTuple) setFieldValue(tz *time.Location, field, value string,
 		st.backoffTotal, err = strconv.ParseFloat(value, 64)
 	case variable.SlowLogWriteSQLRespTotal:
 		st.writeSQLRespTotal, err = strconv.ParseFloat(value, 64)
+	case variable.SlowLogWriteSQLRespBytes:
+		st.writeSQLRespBytes, err = strconv.ParseUint(value, 10, 64)
 	case variable.SlowLogPrepared:
 		st.prepared, err = strconv.ParseBool(value)
 	case variable.SlowLogRewriteTimeStr:
@@ -906,6 +977,7 @@ func (st *slowQueryTuple) convertToDatumRow() []types.Datum {
 	record = append(record, types.NewFloat64Datum(st.pdTotal))
 	record = append(record, types.NewFloat64Datum(st.backoffTotal))
 	record = append(record, types.NewFloat64Datum(st.writeSQLRespTotal))
+	record = append(record, types.NewUintDatum(st.writeSQLRespBytes))
 	record = append(record, types.NewStringDatum(st.backoffDetail))
 	if st.prepared {
 		record = append(record, types.NewIntDatum(1))
@@ -931,6 +1003,11 @@ func (st *slowQueryTuple) convertToDatumRow() []types.Datum {
 	record = append(record, types.NewStringDatum(st.planDigest))
 	record = append(record, types.NewStringDatum(st.prevStmt))
 	record = append(record, types.NewStringDatum(st.sql))
+	if st.isSampled {
+		record = append(record, types.NewIntDatum(1))
+	} else {
+		record = append(record, types.NewIntDatum(0))
+	}
 	return record
 }
 
@@ -1010,6 +1087,10 @@ func (e *slowQueryRetriever) getAllFiles(ctx context.Context, sctx sessionctx.Co
 		if !strings.HasPrefix(path, prefix) {
 			return nil
 		}
+		// The compressed rotated files can't be read.
+		if strings.HasSuffix(path, ".gz") {
+			return nil
+		}
 		if isCtxDone(ctx) {
 			return ctx.Err()
 		}
@@ -1099,6 +1180,11 @@ func (e *slowQueryRetriever) getFileStartTime(ctx context.Context, file *os.File
 		if strings.HasPrefix(line, variable.SlowLogStartPrefixStr) {
 			return ParseTime(line[len(variable.SlowLogStartPrefixStr):])
 		}
+		if isJSONSlowLog(line) {
+			if lines, err := expandJSONSlowLog(line); err == nil {
+				return ParseTime(lines[0][len(variable.SlowLogStartPrefixStr):])
+			}
+		}
 		maxNum -= 1
 		if maxNum <= 0 {
 			break
@@ -1180,6 +1266,11 @@ func (e *slowQueryRetriever) getFileEndTime(ctx context.Context, file *os.File)
 			if strings.HasPrefix(lines[i], variable.SlowLogStartPrefixStr) {
 				return ParseTime(lines[i][len(variable.SlowLogStartPrefixStr):])
 			}
+			if isJSONSlowLog(lines[i]) {
+				if expanded, err := expandJSONSlowLog(lines[i]); err == nil {
+					return ParseTime(expanded[0][len(variable.SlowLogStartPrefixStr):])
+				}
+			}
 		}
 		tried += len(lines)
 		if tried >= maxLineNum {
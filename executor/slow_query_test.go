@@ -109,6 +109,7 @@ func (s *testExecSuite) TestParseSlowLogFile(c *C) {
 # Cop_backoff_rpcTiKV_total_times: 200 Cop_backoff_rpcTiKV_total_time: 0.2 Cop_backoff_rpcTiKV_max_time: 0.2 Cop_backoff_rpcTiKV_max_addr: 127.0.0.1 Cop_backoff_rpcTiKV_avg_time: 0.2 Cop_backoff_rpcTiKV_p90_time: 0.2
 # Mem_max: 70724
 # Disk_max: 65536
+# Write_sql_response_bytes: 1048576
 # Plan_from_cache: true
 # Plan_from_binding: true
 # Succ: false
@@ -136,10 +137,10 @@ select * from t;`
 	expectRecordString := `2019-04-28 15:24:04.309074,` +
 		`405888132465033227,root,localhost,0,57,0.12,0.216905,` +
 		`0,0,0,0,0,0,0,0,0,0,0,0,,0,0,0,0,0,0,0.38,0.021,0,0,0,1,637,0,10,10,10,10,100,,,1,42a1c8aae6f133e934d4bf0147491709a8812ea05ff8819ec522780fe657b772,t1:1,t2:2,` +
-		`0.1,0.2,0.03,127.0.0.1:20160,0.05,0.6,0.8,0.0.0.0:20160,70724,65536,0,0,0,0,` +
+		`0.1,0.2,0.03,127.0.0.1:20160,0.05,0.6,0.8,0.0.0.0:20160,70724,65536,0,0,0,0,1048576,` +
 		`Cop_backoff_regionMiss_total_times: 200 Cop_backoff_regionMiss_total_time: 0.2 Cop_backoff_regionMiss_max_time: 0.2 Cop_backoff_regionMiss_max_addr: 127.0.0.1 Cop_backoff_regionMiss_avg_time: 0.2 Cop_backoff_regionMiss_p90_time: 0.2 Cop_backoff_rpcPD_total_times: 200 Cop_backoff_rpcPD_total_time: 0.2 Cop_backoff_rpcPD_max_time: 0.2 Cop_backoff_rpcPD_max_addr: 127.0.0.1 Cop_backoff_rpcPD_avg_time: 0.2 Cop_backoff_rpcPD_p90_time: 0.2 Cop_backoff_rpcTiKV_total_times: 200 Cop_backoff_rpcTiKV_total_time: 0.2 Cop_backoff_rpcTiKV_max_time: 0.2 Cop_backoff_rpcTiKV_max_addr: 127.0.0.1 Cop_backoff_rpcTiKV_avg_time: 0.2 Cop_backoff_rpcTiKV_p90_time: 0.2,` +
 		`0,0,1,1,,60e9378c746d9a2be1c791047e008967cf252eb6de9167ad3aa6098fa2d523f4,` +
-		`update t set i = 1;,select * from t;`
+		`update t set i = 1;,select * from t;,0`
 	c.Assert(expectRecordString, Equals, recordString)
 
 	// Issue 20928
@@ -159,10 +160,10 @@ select * from t;`
 	expectRecordString = `2019-04-28 15:24:04.309074,` +
 		`405888132465033227,root,localhost,0,57,0.12,0.216905,` +
 		`0,0,0,0,0,0,0,0,0,0,0,0,,0,0,0,0,0,0,0.38,0.021,0,0,0,1,637,0,10,10,10,10,100,,,1,42a1c8aae6f133e934d4bf0147491709a8812ea05ff8819ec522780fe657b772,t1:1,t2:2,` +
-		`0.1,0.2,0.03,127.0.0.1:20160,0.05,0.6,0.8,0.0.0.0:20160,70724,65536,0,0,0,0,` +
+		`0.1,0.2,0.03,127.0.0.1:20160,0.05,0.6,0.8,0.0.0.0:20160,70724,65536,0,0,0,0,1048576,` +
 		`Cop_backoff_regionMiss_total_times: 200 Cop_backoff_regionMiss_total_time: 0.2 Cop_backoff_regionMiss_max_time: 0.2 Cop_backoff_regionMiss_max_addr: 127.0.0.1 Cop_backoff_regionMiss_avg_time: 0.2 Cop_backoff_regionMiss_p90_time: 0.2 Cop_backoff_rpcPD_total_times: 200 Cop_backoff_rpcPD_total_time: 0.2 Cop_backoff_rpcPD_max_time: 0.2 Cop_backoff_rpcPD_max_addr: 127.0.0.1 Cop_backoff_rpcPD_avg_time: 0.2 Cop_backoff_rpcPD_p90_time: 0.2 Cop_backoff_rpcTiKV_total_times: 200 Cop_backoff_rpcTiKV_total_time: 0.2 Cop_backoff_rpcTiKV_max_time: 0.2 Cop_backoff_rpcTiKV_max_addr: 127.0.0.1 Cop_backoff_rpcTiKV_avg_time: 0.2 Cop_backoff_rpcTiKV_p90_time: 0.2,` +
 		`0,0,1,1,,60e9378c746d9a2be1c791047e008967cf252eb6de9167ad3aa6098fa2d523f4,` +
-		`update t set i = 1;,select * from t;`
+		`update t set i = 1;,select * from t;,0`
 	c.Assert(expectRecordString, Equals, recordString)
 
 	// fix sql contain '# ' bug
@@ -242,6 +243,60 @@ select * from t;
 	c.Assert(err, IsNil)
 }
 
+func (s *testExecSuite) TestParseJSONSlowLog(c *C) {
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	c.Assert(err, IsNil)
+	ctx := mock.NewContext()
+	ctx.GetSessionVars().TimeZone = loc
+	textLog := `# Time: 2019-04-28T15:24:04.309074+08:00
+# Txn_start_ts: 405888132465033227
+# User@Host: root[root] @ localhost [127.0.0.1]
+# Query_time: 0.216905
+# Process_time: 0.021 Request_count: 1 Total_keys: 637
+# DB: test
+# Is_internal: true
+# Digest: 42a1c8aae6f133e934d4bf0147491709a8812ea05ff8819ec522780fe657b772
+# Succ: false
+select * from t where a = "1";
+`
+	jsonLog := `{"Time":"2019-04-28T15:24:04.309074+08:00","Txn_start_ts":405888132465033227,` +
+		`"User@Host":"root[root] @ localhost [127.0.0.1]","Query_time":0.216905,"Process_time":0.021,` +
+		`"Request_count":1,"Total_keys":637,"DB":"test","Is_internal":true,` +
+		`"Digest":"42a1c8aae6f133e934d4bf0147491709a8812ea05ff8819ec522780fe657b772","Succ":false,` +
+		`"Query":"select * from t where a = \"1\";"}
+`
+	expected, err := parseSlowLog(ctx, bufio.NewReader(bytes.NewBufferString(textLog)), 64)
+	c.Assert(err, IsNil)
+	c.Assert(expected, HasLen, 1)
+	rows, err := parseSlowLog(ctx, bufio.NewReader(bytes.NewBufferString(jsonLog+jsonLog)), 64)
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 2)
+	for _, row := range rows {
+		c.Assert(row, HasLen, len(expected[0]))
+		for i := range row {
+			c.Assert(row[i].GetValue(), DeepEquals, expected[0][i].GetValue())
+		}
+	}
+
+	// The JSON entries and the text entries can be mixed in a file after the format is changed.
+	rows, err = parseSlowLog(ctx, bufio.NewReader(bytes.NewBufferString(textLog+jsonLog)), 64)
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 2)
+
+	lines, err := expandJSONSlowLog(`{"Time":"2019-04-28T15:24:04.309074+08:00","Succ":true,"DB":"test","Query":"select 1;"}`)
+	c.Assert(err, IsNil)
+	c.Assert(lines, DeepEquals, []string{
+		"# Time: 2019-04-28T15:24:04.309074+08:00",
+		"# DB: test",
+		"# Succ: true",
+		"select 1;",
+	})
+	_, err = expandJSONSlowLog(`{"Time":"2019-04-28T15:24:04.309074+08:00"}`)
+	c.Assert(err, NotNil)
+	_, err = expandJSONSlowLog(`{"Time":`)
+	c.Assert(err, NotNil)
+}
+
 func (s *testExecSuite) TestSlowLogParseTime(c *C) {
 	t1Str := "2019-01-24T22:32:29.313255+08:00"
 	t2Str := "2019-01-24T22:32:29.313255"
@@ -448,7 +503,8 @@ select 7;`
 			c.Assert(err, IsNil)
 			c.Assert(len(rows), Equals, len(cas.querys), comment)
 			for i, row := range rows {
-				c.Assert(row[len(row)-1].GetString(), Equals, cas.querys[i], comment)
+				// The Query column is followed by the Is_sampled column.
+				c.Assert(row[len(row)-2].GetString(), Equals, cas.querys[i], comment)
 			}
 		}
 
@@ -22,12 +22,15 @@ import (
 	"time"
 
 	"github.com/cznic/mathutil"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/store/helper"
 	"github.com/pingcap/tidb/store/tikv"
 	"github.com/pingcap/tidb/table/tables"
@@ -50,6 +53,7 @@ type SplitIndexRegionExec struct {
 	upper          []types.Datum
 	num            int
 	valueLists     [][]types.Datum
+	splitByStats   bool
 	splitIdxKeys   [][]byte
 
 	done bool
@@ -121,6 +125,10 @@ func (e *SplitIndexRegionExec) getSplitIdxKeys() ([][]byte, error) {
 	if len(e.valueLists) > 0 {
 		return e.getSplitIdxKeysFromValueList()
 	}
+	// Split index regions by the sampled index keys.
+	if e.splitByStats {
+		return e.getSplitIdxKeysFromStats()
+	}
 
 	return e.getSplitIdxKeysFromBound()
 }
@@ -245,6 +253,63 @@ func (e *SplitIndexRegionExec) getSplitIdxPhysicalKeysFromBound(physicalID int64
 	return getValuesList(lowerIdxKey, upperIdxKey, e.num, keys), nil
 }
 
+func (e *SplitIndexRegionExec) getSplitIdxKeysFromStats() (keys [][]byte, err error) {
+	pi := e.tableInfo.GetPartitionInfo()
+	if pi == nil {
+		keys = make([][]byte, 0, e.num+1)
+		return e.getSplitIdxPhysicalKeysFromStats(e.tableInfo.ID, keys)
+	}
+
+	// Split for all table partitions.
+	if len(e.partitionNames) == 0 {
+		keys = make([][]byte, 0, (e.num+1)*len(pi.Definitions))
+		for _, p := range pi.Definitions {
+			keys, err = e.getSplitIdxPhysicalKeysFromStats(p.ID, keys)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return keys, nil
+	}
+
+	// Split for specified table partitions.
+	keys = make([][]byte, 0, (e.num+1)*len(e.partitionNames))
+	for _, name := range e.partitionNames {
+		pid, err := tables.FindPartitionByName(e.tableInfo, name.L)
+		if err != nil {
+			return nil, err
+		}
+		keys, err = e.getSplitIdxPhysicalKeysFromStats(pid, keys)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// getSplitIdxPhysicalKeysFromStats splits the index by the statistics of the index, the split keys are the quantiles of
+// the values in the histogram and the TopN, so every region gets about the same amount of index data. It splits fewer
+// regions if the index doesn't have enough distinct values, and only splits the start and end keys of the index if the
+// index isn't analyzed.
+func (e *SplitIndexRegionExec) getSplitIdxPhysicalKeysFromStats(physicalID int64, keys [][]byte) ([][]byte, error) {
+	keys = e.getSplitIdxPhysicalStartAndOtherIdxKeys(physicalID, keys)
+	var idxStats *statistics.Index
+	if h := domain.GetDomain(e.ctx).StatsHandle(); h != nil {
+		if statsTbl := h.GetPartitionStats(e.tableInfo, physicalID); !statsTbl.Pseudo {
+			idxStats = statsTbl.Indices[e.indexInfo.ID]
+		}
+	}
+	if idxStats == nil || idxStats.TotalRowCount() == 0 {
+		e.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("The statistics of index `%v` are not available, "+
+			"analyze the table to split the index by its data", e.indexInfo.Name))
+		return keys, nil
+	}
+	for _, value := range statistics.SplitValues(idxStats.SplitPoints(), e.num) {
+		keys = append(keys, tablecodec.EncodeIndexSeekKey(physicalID, e.indexInfo.ID, value))
+	}
+	return keys, nil
+}
+
 // getValuesList is used to get `num` values between lower and upper value.
 // To Simplify the explain, suppose lower and upper value type is int64, and lower=0, upper=100, num=10,
 // then calculate the step=(upper-lower)/num=10, then the function should return 0+10, 10+10, 20+10... all together 9 (num-1) values.
@@ -0,0 +1,293 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/store/tikv"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/kvcache"
+	"github.com/pingcap/tidb/util/memory"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Sections of the status report.
+const (
+	StatusReportSectionMemory      = "memory"
+	StatusReportSectionGoroutine   = "goroutine"
+	StatusReportSectionCache       = "cache"
+	StatusReportSectionRegionCache = "region_cache"
+	StatusReportSectionTxn         = "txn"
+)
+
+const tidbPackagePrefix = "github.com/pingcap/tidb/"
+
+// statusReportTxnMetrics are the metric families summarized into the txn section.
+var statusReportTxnMetrics = []string{
+	"tidb_session_transaction_duration_seconds",
+	"tidb_tikvclient_commit_txn_counter",
+	"tidb_tikvclient_async_commit_txn_counter",
+	"tidb_tikvclient_one_pc_txn_counter",
+}
+
+// StatusReport is a diagnostic snapshot of a TiDB instance which is collected for support bundles.
+type StatusReport struct {
+	Time        time.Time              `json:"time"`
+	Memory      StatusReportMemory     `json:"memory"`
+	Goroutines  map[string]int         `json:"goroutines"`
+	Caches      map[string]int64       `json:"caches"`
+	RegionCache *tikv.RegionCacheStats `json:"region_cache,omitempty"`
+	Txn         map[string]float64     `json:"txn"`
+}
+
+// StatusReportMemory is the memory section of StatusReport.
+type StatusReportMemory struct {
+	GlobalBytes int64                     `json:"global_bytes"`
+	DiskBytes   int64                     `json:"disk_bytes"`
+	Sessions    []*StatusReportSessionMem `json:"sessions,omitempty"`
+}
+
+// StatusReportSessionMem is the memory tracker tree of a running statement.
+type StatusReportSessionMem struct {
+	ConnID  uint64                  `json:"conn_id"`
+	User    string                  `json:"user"`
+	Tracker *memory.TrackerSnapshot `json:"tracker"`
+}
+
+// BuildStatusReport collects a StatusReport. dom and sm may be nil, in which case the related parts are skipped.
+func BuildStatusReport(dom *domain.Domain, sm util.SessionManager) (*StatusReport, error) {
+	report := &StatusReport{
+		Time:   time.Now(),
+		Caches: make(map[string]int64),
+	}
+	if GlobalMemoryUsageTracker != nil {
+		report.Memory.GlobalBytes = GlobalMemoryUsageTracker.BytesConsumed()
+	}
+	if GlobalDiskUsageTracker != nil {
+		report.Memory.DiskBytes = GlobalDiskUsageTracker.BytesConsumed()
+	}
+	if sm != nil {
+		for _, pi := range sm.ShowProcessList() {
+			if pi.StmtCtx == nil || pi.StmtCtx.MemTracker == nil {
+				continue
+			}
+			report.Memory.Sessions = append(report.Memory.Sessions, &StatusReportSessionMem{
+				ConnID:  pi.ID,
+				User:    pi.User,
+				Tracker: pi.StmtCtx.MemTracker.Snapshot(),
+			})
+		}
+		sort.Slice(report.Memory.Sessions, func(i, j int) bool {
+			return report.Memory.Sessions[i].ConnID < report.Memory.Sessions[j].ConnID
+		})
+	}
+
+	goroutines, err := goroutinesByModule()
+	if err != nil {
+		return nil, err
+	}
+	report.Goroutines = goroutines
+
+	report.Caches["plan_cache_bytes"] = kvcache.GlobalLRUMemUsageTracker.BytesConsumed()
+	if dom != nil {
+		if h := dom.StatsHandle(); h != nil {
+			report.Caches["stats_cache_bytes"] = h.GetMemConsumed()
+		}
+		if store, ok := dom.Store().(tikv.Storage); ok {
+			stats := store.GetRegionCache().Stats()
+			report.RegionCache = &stats
+		}
+	}
+
+	txn, err := txnCounters(prometheus.DefaultGatherer)
+	if err != nil {
+		return nil, err
+	}
+	report.Txn = txn
+	return report, nil
+}
+
+// Rows flattens the report into (SECTION, NAME, VALUE) rows.
+func (r *StatusReport) Rows() [][]types.Datum {
+	var rows [][]types.Datum
+	appendRow := func(section, name string, value interface{}) {
+		rows = append(rows, types.MakeDatums(section, name, fmt.Sprintf("%v", value)))
+	}
+	appendRow(StatusReportSectionMemory, "global_bytes", r.Memory.GlobalBytes)
+	appendRow(StatusReportSectionMemory, "disk_bytes", r.Memory.DiskBytes)
+	for _, s := range r.Memory.Sessions {
+		var walk func(path string, t *memory.TrackerSnapshot)
+		walk = func(path string, t *memory.TrackerSnapshot) {
+			path = path + "/" + strconv.Itoa(t.Label)
+			appendRow(StatusReportSectionMemory, path, t.BytesConsumed)
+			for _, child := range t.Children {
+				walk(path, child)
+			}
+		}
+		walk("conn_"+strconv.FormatUint(s.ConnID, 10), s.Tracker)
+	}
+	modules := make([]string, 0, len(r.Goroutines))
+	for name := range r.Goroutines {
+		modules = append(modules, name)
+	}
+	sort.Strings(modules)
+	for _, name := range modules {
+		appendRow(StatusReportSectionGoroutine, name, r.Goroutines[name])
+	}
+	caches := make([]string, 0, len(r.Caches))
+	for name := range r.Caches {
+		caches = append(caches, name)
+	}
+	sort.Strings(caches)
+	for _, name := range caches {
+		appendRow(StatusReportSectionCache, name, r.Caches[name])
+	}
+	if r.RegionCache != nil {
+		appendRow(StatusReportSectionRegionCache, "regions", r.RegionCache.Regions)
+		appendRow(StatusReportSectionRegionCache, "expired_regions", r.RegionCache.ExpiredRegions)
+		appendRow(StatusReportSectionRegionCache, "stores", r.RegionCache.Stores)
+	}
+	txn := make([]string, 0, len(r.Txn))
+	for name := range r.Txn {
+		txn = append(txn, name)
+	}
+	sort.Strings(txn)
+	for _, name := range txn {
+		appendRow(StatusReportSectionTxn, name, r.Txn[name])
+	}
+	return rows
+}
+
+func goroutinesByModule() (map[string]int, error) {
+	p := pprof.Lookup("goroutine")
+	if p == nil {
+		return nil, errors.New("cannot retrieve goroutine profile")
+	}
+	buffer := &bytes.Buffer{}
+	if err := p.WriteTo(buffer, 1); err != nil {
+		return nil, err
+	}
+	return parseGoroutinesByModule(buffer)
+}
+
+// parseGoroutinesByModule groups a goroutine profile written with debug=1 by module.
+// A goroutine belongs to the TiDB package of its outermost TiDB frame, or to the
+// package of its outermost frame if it never enters TiDB code.
+func parseGoroutinesByModule(reader io.Reader) (map[string]int, error) {
+	result := make(map[string]int)
+	var count int
+	var module, fallback string
+	flush := func() {
+		if count == 0 {
+			return
+		}
+		if module == "" {
+			module = fallback
+		}
+		result[module] += count
+		count, module, fallback = 0, "", ""
+	}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "#"):
+			fields := strings.Fields(line)
+			if count == 0 || len(fields) < 3 || !strings.HasPrefix(fields[1], "0x") {
+				continue
+			}
+			pkg := funcPackage(fields[2])
+			if strings.HasPrefix(pkg, tidbPackagePrefix) {
+				module = strings.TrimPrefix(pkg, tidbPackagePrefix)
+			}
+			fallback = pkg
+		case strings.Contains(line, " @ "):
+			flush()
+			n, err := strconv.Atoi(strings.TrimSpace(line[:strings.Index(line, " @ ")]))
+			if err != nil {
+				return nil, errors.Annotatef(err, "invalid goroutine profile record: %s", line)
+			}
+			count = n
+		}
+	}
+	flush()
+	return result, scanner.Err()
+}
+
+// funcPackage returns the package path of a symbolized function name like "pkg/path.(*T).f+0x10".
+func funcPackage(fn string) string {
+	if idx := strings.LastIndex(fn, "+0x"); idx >= 0 {
+		fn = fn[:idx]
+	}
+	dir, base := "", fn
+	if idx := strings.LastIndex(fn, "/"); idx >= 0 {
+		dir, base = fn[:idx+1], fn[idx+1:]
+	}
+	if idx := strings.Index(base, "."); idx >= 0 {
+		base = base[:idx]
+	}
+	return dir + base
+}
+
+func txnCounters(gatherer prometheus.Gatherer) (map[string]float64, error) {
+	families, err := gatherer.Gather()
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[string]struct{}, len(statusReportTxnMetrics))
+	for _, name := range statusReportTxnMetrics {
+		wanted[name] = struct{}{}
+	}
+	result := make(map[string]float64)
+	for _, family := range families {
+		if _, ok := wanted[family.GetName()]; !ok {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			name := family.GetName() + formatLabels(m.GetLabel())
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				result[name] = m.GetCounter().GetValue()
+			case dto.MetricType_HISTOGRAM:
+				result[name] = float64(m.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return result, nil
+}
+
+func formatLabels(labels []*dto.LabelPair) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(labels))
+	for _, l := range labels {
+		parts = append(parts, fmt.Sprintf("%s=%q", l.GetName(), l.GetValue()))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
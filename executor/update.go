@@ -63,6 +63,9 @@ type UpdateExec struct {
 	tableUpdatable []bool
 	changed        []bool
 	matches        []bool
+
+	// rowPolicyChecks are the conditions the updated rows of the tables must satisfy by their row policies.
+	rowPolicyChecks map[int64]expression.Expression
 }
 
 // prepare `handles`, `tableUpdatable`, `changed` to avoid re-computations.
@@ -184,6 +187,10 @@ func (e *UpdateExec) exec(ctx context.Context, schema *expression.Schema, row, n
 		newTableData := newData[content.Start:content.End]
 		flags := bAssignFlag[content.Start:content.End]
 
+		if err := checkRowPolicy(e.ctx, e.rowPolicyChecks[content.TblID], tbl, newTableData); err != nil {
+			return err
+		}
+
 		// Update row
 		changed, err1 := updateRecord(ctx, e.ctx, handle, oldData, newTableData, flags, tbl, false, e.memTracker)
 		if err1 == nil {
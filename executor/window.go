@@ -213,15 +213,20 @@ func (p *aggWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chu
 }
 
 func (p *aggWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
-	for remained > 0 {
-		for i, windowFunc := range p.windowFuncs {
-			// TODO: We can extend the agg func interface to avoid the `for` loop  here.
+	// Every window function appends its results to its own column, so the results can be appended function by function.
+	for i, windowFunc := range p.windowFuncs {
+		if batchFunc, ok := windowFunc.(aggfuncs.BatchWindowFunc); ok {
+			if err := batchFunc.AppendFinalResults2Chunk(ctx, p.partialResults[i], chk, remained); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		for j := 0; j < remained; j++ {
 			err := windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 			if err != nil {
 				return nil, err
 			}
 		}
-		remained--
 	}
 	return rows, nil
 }
@@ -237,7 +242,10 @@ type rowFrameWindowProcessor struct {
 	partialResults []aggfuncs.PartialResult
 	start          *core.FrameBound
 	end            *core.FrameBound
-	curRowIdx      uint64
+	exclude        core.FrameExclusion
+	// peers is only used for excluding the peer group of the current row.
+	peers     *peerGroups
+	curRowIdx uint64
 }
 
 func (p *rowFrameWindowProcessor) getStartOffset(numRows uint64) uint64 {
@@ -292,82 +300,20 @@ func (p *rowFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows
 
 func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
 	numRows := uint64(len(rows))
-	var (
-		err                      error
-		initializedSlidingWindow bool
-		start                    uint64
-		end                      uint64
-		lastStart                uint64
-		lastEnd                  uint64
-		shiftStart               uint64
-		shiftEnd                 uint64
-	)
-	slidingWindowAggFuncs := make([]aggfuncs.SlidingWindowAggFunc, len(p.windowFuncs))
-	for i, windowFunc := range p.windowFuncs {
-		if slidingWindowAggFunc, ok := windowFunc.(aggfuncs.SlidingWindowAggFunc); ok {
-			slidingWindowAggFuncs[i] = slidingWindowAggFunc
-		}
-	}
-	for ; remained > 0; lastStart, lastEnd = start, end {
-		start = p.getStartOffset(numRows)
-		end = p.getEndOffset(numRows)
+	nextFrame := func() (cur, start, end uint64, err error) {
+		cur, start, end = p.curRowIdx, p.getStartOffset(numRows), p.getEndOffset(numRows)
 		p.curRowIdx++
-		remained--
-		shiftStart = start - lastStart
-		shiftEnd = end - lastEnd
-		if start >= end {
-			for i, windowFunc := range p.windowFuncs {
-				slidingWindowAggFunc := slidingWindowAggFuncs[i]
-				if slidingWindowAggFunc != nil && initializedSlidingWindow {
-					err = slidingWindowAggFunc.Slide(ctx, rows, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
-					if err != nil {
-						return nil, err
-					}
-				}
-				err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
-				if err != nil {
-					return nil, err
-				}
-			}
-			continue
-		}
-
-		for i, windowFunc := range p.windowFuncs {
-			slidingWindowAggFunc := slidingWindowAggFuncs[i]
-			if slidingWindowAggFunc != nil && initializedSlidingWindow {
-				err = slidingWindowAggFunc.Slide(ctx, rows, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
-			} else {
-				// For MinMaxSlidingWindowAggFuncs, it needs the absolute value of each start of window, to compare
-				// whether elements inside deque are out of current window.
-				if minMaxSlidingWindowAggFunc, ok := windowFunc.(aggfuncs.MaxMinSlidingWindowAggFunc); ok {
-					// Store start inside MaxMinSlidingWindowAggFunc.windowInfo
-					minMaxSlidingWindowAggFunc.SetWindowStart(start)
-				}
-				_, err = windowFunc.UpdatePartialResult(ctx, rows[start:end], p.partialResults[i])
-			}
-			if err != nil {
-				return nil, err
-			}
-			err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
-			if err != nil {
-				return nil, err
-			}
-			if slidingWindowAggFunc == nil {
-				windowFunc.ResetPartialResult(p.partialResults[i])
-			}
-		}
-		if !initializedSlidingWindow {
-			initializedSlidingWindow = true
-		}
+		return cur, start, end, nil
 	}
-	for i, windowFunc := range p.windowFuncs {
-		windowFunc.ResetPartialResult(p.partialResults[i])
-	}
-	return rows, nil
+	err := appendFrameResults(ctx, p.windowFuncs, p.partialResults, rows, chk, remained, p.exclude, p.peers, nextFrame)
+	return rows, err
 }
 
 func (p *rowFrameWindowProcessor) resetPartialResult() {
 	p.curRowIdx = 0
+	if p.peers != nil {
+		p.peers.reset()
+	}
 }
 
 type rangeFrameWindowProcessor struct {
@@ -375,6 +321,8 @@ type rangeFrameWindowProcessor struct {
 	partialResults  []aggfuncs.PartialResult
 	start           *core.FrameBound
 	end             *core.FrameBound
+	exclude         core.FrameExclusion
+	peers           *peerGroups
 	curRowIdx       uint64
 	lastStartOffset uint64
 	lastEndOffset   uint64
@@ -436,6 +384,196 @@ func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows []
 }
 
 func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
+	nextFrame := func() (cur, start, end uint64, err error) {
+		start, err = p.getStartOffset(ctx, rows)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		end, err = p.getEndOffset(ctx, rows)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		cur = p.curRowIdx
+		p.curRowIdx++
+		return cur, start, end, nil
+	}
+	err := appendFrameResults(ctx, p.windowFuncs, p.partialResults, rows, chk, remained, p.exclude, p.peers, nextFrame)
+	return rows, err
+}
+
+func (p *rangeFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
+	return rows, nil
+}
+
+func (p *rangeFrameWindowProcessor) resetPartialResult() {
+	p.curRowIdx = 0
+	p.lastStartOffset = 0
+	p.lastEndOffset = 0
+	if p.peers != nil {
+		p.peers.reset()
+	}
+}
+
+// groupFrameWindowProcessor processes the windows with `GROUPS` frames, whose
+// bounds are counted in peer groups instead of rows.
+type groupFrameWindowProcessor struct {
+	windowFuncs    []aggfuncs.AggFunc
+	partialResults []aggfuncs.PartialResult
+	start          *core.FrameBound
+	end            *core.FrameBound
+	exclude        core.FrameExclusion
+	peers          *peerGroups
+	curRowIdx      uint64
+}
+
+func (p *groupFrameWindowProcessor) getStartOffset() uint64 {
+	if p.start.UnBounded {
+		return 0
+	}
+	group, numGroups := p.peers.groupIdx[p.curRowIdx], p.peers.numGroups()
+	switch p.start.Type {
+	case ast.Preceding:
+		if group >= p.start.Num {
+			return p.peers.starts[group-p.start.Num]
+		}
+		return 0
+	case ast.Following:
+		if p.start.Num >= numGroups-group {
+			return p.peers.starts[numGroups]
+		}
+		return p.peers.starts[group+p.start.Num]
+	case ast.CurrentRow:
+		return p.peers.starts[group]
+	}
+	// It will never reach here.
+	return 0
+}
+
+func (p *groupFrameWindowProcessor) getEndOffset() uint64 {
+	group, numGroups := p.peers.groupIdx[p.curRowIdx], p.peers.numGroups()
+	if p.end.UnBounded {
+		return p.peers.starts[numGroups]
+	}
+	switch p.end.Type {
+	case ast.Preceding:
+		if group >= p.end.Num {
+			return p.peers.starts[group-p.end.Num+1]
+		}
+		return 0
+	case ast.Following:
+		if p.end.Num >= numGroups-group-1 {
+			return p.peers.starts[numGroups]
+		}
+		return p.peers.starts[group+p.end.Num+1]
+	case ast.CurrentRow:
+		return p.peers.starts[group+1]
+	}
+	// It will never reach here.
+	return 0
+}
+
+func (p *groupFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
+	return rows, nil
+}
+
+func (p *groupFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
+	if err := p.peers.build(ctx, rows); err != nil {
+		return nil, err
+	}
+	nextFrame := func() (cur, start, end uint64, err error) {
+		cur, start, end = p.curRowIdx, p.getStartOffset(), p.getEndOffset()
+		p.curRowIdx++
+		return cur, start, end, nil
+	}
+	err := appendFrameResults(ctx, p.windowFuncs, p.partialResults, rows, chk, remained, p.exclude, p.peers, nextFrame)
+	return rows, err
+}
+
+func (p *groupFrameWindowProcessor) resetPartialResult() {
+	p.curRowIdx = 0
+	p.peers.reset()
+}
+
+// peerGroups splits the rows of a partition into peer groups, a peer group is
+// the consecutive rows which are equal on the ORDER BY items.
+type peerGroups struct {
+	orderByCols []*expression.Column
+	cmpFuncs    []expression.CompareFunc
+	// starts stores the offset of the first row of each peer group, followed
+	// by the number of rows in the partition.
+	starts []uint64
+	// groupIdx stores the index of the peer group each row belongs to.
+	groupIdx []uint64
+}
+
+func newPeerGroups(ctx sessionctx.Context, orderByCols []*expression.Column) *peerGroups {
+	cmpFuncs := make([]expression.CompareFunc, len(orderByCols))
+	for i, col := range orderByCols {
+		cmpFuncs[i] = expression.GetCmpFunction(ctx, col, col)
+	}
+	return &peerGroups{orderByCols: orderByCols, cmpFuncs: cmpFuncs}
+}
+
+// build splits the rows of a partition, it does nothing if the partition has been split.
+func (g *peerGroups) build(ctx sessionctx.Context, rows []chunk.Row) error {
+	if len(g.groupIdx) == len(rows) {
+		return nil
+	}
+	g.starts = append(g.starts[:0], 0)
+	g.groupIdx = g.groupIdx[:0]
+	for i := range rows {
+		if i > 0 {
+			isPeer, err := g.isPeer(ctx, rows[i-1], rows[i])
+			if err != nil {
+				return err
+			}
+			if !isPeer {
+				g.starts = append(g.starts, uint64(i))
+			}
+		}
+		g.groupIdx = append(g.groupIdx, uint64(len(g.starts)-1))
+	}
+	g.starts = append(g.starts, uint64(len(rows)))
+	return nil
+}
+
+func (g *peerGroups) isPeer(ctx sessionctx.Context, lhs, rhs chunk.Row) (bool, error) {
+	for i, col := range g.orderByCols {
+		res, _, err := g.cmpFuncs[i](ctx, col, col, lhs, rhs)
+		if err != nil || res != 0 {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (g *peerGroups) numGroups() uint64 {
+	return uint64(len(g.starts) - 1)
+}
+
+// bounds returns the offsets [start, end) of the peer group of the row.
+func (g *peerGroups) bounds(rowIdx uint64) (start, end uint64) {
+	group := g.groupIdx[rowIdx]
+	return g.starts[group], g.starts[group+1]
+}
+
+func (g *peerGroups) reset() {
+	g.starts = g.starts[:0]
+	g.groupIdx = g.groupIdx[:0]
+}
+
+// nextFrameFunc returns the offset of the next row to evaluate and the offsets [start, end) of its frame.
+type nextFrameFunc func() (cur, start, end uint64, err error)
+
+// appendFrameResults evaluates the window functions on the frames of the next `remained` rows
+// and appends the results to chk. The frames are got by nextFrame, and their bounds must never
+// decrease from one row to the next, which makes it possible to maintain the sliding window
+// aggregates incrementally.
+func appendFrameResults(ctx sessionctx.Context, windowFuncs []aggfuncs.AggFunc, partialResults []aggfuncs.PartialResult,
+	rows []chunk.Row, chk *chunk.Chunk, remained int, exclude core.FrameExclusion, peers *peerGroups, nextFrame nextFrameFunc) error {
+	if exclude != core.FrameExcludeNoOthers {
+		return appendExcludedFrameResults(ctx, windowFuncs, partialResults, rows, chk, remained, exclude, peers, nextFrame)
+	}
 	var (
 		err                      error
 		initializedSlidingWindow bool
@@ -446,79 +584,198 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 		shiftStart               uint64
 		shiftEnd                 uint64
 	)
-	slidingWindowAggFuncs := make([]aggfuncs.SlidingWindowAggFunc, len(p.windowFuncs))
-	for i, windowFunc := range p.windowFuncs {
+	slidingWindowAggFuncs := make([]aggfuncs.SlidingWindowAggFunc, len(windowFuncs))
+	for i, windowFunc := range windowFuncs {
 		if slidingWindowAggFunc, ok := windowFunc.(aggfuncs.SlidingWindowAggFunc); ok {
 			slidingWindowAggFuncs[i] = slidingWindowAggFunc
 		}
 	}
 	for ; remained > 0; lastStart, lastEnd = start, end {
-		start, err = p.getStartOffset(ctx, rows)
-		if err != nil {
-			return nil, err
-		}
-		end, err = p.getEndOffset(ctx, rows)
+		_, start, end, err = nextFrame()
 		if err != nil {
-			return nil, err
+			return err
 		}
-		p.curRowIdx++
 		remained--
 		shiftStart = start - lastStart
 		shiftEnd = end - lastEnd
 		if start >= end {
-			for i, windowFunc := range p.windowFuncs {
+			for i, windowFunc := range windowFuncs {
 				slidingWindowAggFunc := slidingWindowAggFuncs[i]
 				if slidingWindowAggFunc != nil && initializedSlidingWindow {
-					err = slidingWindowAggFunc.Slide(ctx, rows, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+					err = slidingWindowAggFunc.Slide(ctx, rows, lastStart, lastEnd, shiftStart, shiftEnd, partialResults[i])
 					if err != nil {
-						return nil, err
+						return err
 					}
 				}
-				err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
+				err = windowFunc.AppendFinalResult2Chunk(ctx, partialResults[i], chk)
 				if err != nil {
-					return nil, err
+					return err
 				}
 			}
 			continue
 		}
 
-		for i, windowFunc := range p.windowFuncs {
+		for i, windowFunc := range windowFuncs {
 			slidingWindowAggFunc := slidingWindowAggFuncs[i]
 			if slidingWindowAggFunc != nil && initializedSlidingWindow {
-				err = slidingWindowAggFunc.Slide(ctx, rows, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+				err = slidingWindowAggFunc.Slide(ctx, rows, lastStart, lastEnd, shiftStart, shiftEnd, partialResults[i])
 			} else {
+				// For MinMaxSlidingWindowAggFuncs, it needs the absolute value of each start of window, to compare
+				// whether elements inside deque are out of current window.
 				if minMaxSlidingWindowAggFunc, ok := windowFunc.(aggfuncs.MaxMinSlidingWindowAggFunc); ok {
+					// Store start inside MaxMinSlidingWindowAggFunc.windowInfo
 					minMaxSlidingWindowAggFunc.SetWindowStart(start)
 				}
-				_, err = windowFunc.UpdatePartialResult(ctx, rows[start:end], p.partialResults[i])
+				_, err = windowFunc.UpdatePartialResult(ctx, rows[start:end], partialResults[i])
 			}
 			if err != nil {
-				return nil, err
+				return err
 			}
-			err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
+			err = windowFunc.AppendFinalResult2Chunk(ctx, partialResults[i], chk)
 			if err != nil {
-				return nil, err
+				return err
 			}
 			if slidingWindowAggFunc == nil {
-				windowFunc.ResetPartialResult(p.partialResults[i])
+				windowFunc.ResetPartialResult(partialResults[i])
 			}
 		}
 		if !initializedSlidingWindow {
 			initializedSlidingWindow = true
 		}
 	}
-	for i, windowFunc := range p.windowFuncs {
-		windowFunc.ResetPartialResult(p.partialResults[i])
+	for i, windowFunc := range windowFuncs {
+		windowFunc.ResetPartialResult(partialResults[i])
 	}
-	return rows, nil
+	return nil
 }
 
-func (p *rangeFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
-	return rows, nil
+// appendExcludedFrameResults is like appendFrameResults, but removes the rows specified by
+// the EXCLUDE clause from the frames.
+// The excluded rows split a frame into a left segment and a right segment, and the bounds of
+// both segments never decrease from one row to the next either, so the sliding window
+// aggregates can still be maintained in O(n):
+//  1. For sum, count, avg and bit_xor, both segments slide in the same partial result, since
+//     they simply add the rows entering the segments and subtract the rows leaving them.
+//  2. For max and min, each segment slides in its own deque, and the partial results of the
+//     segments are merged to get the result of the frame.
+//
+// The other window functions are evaluated on the rows of the segments from scratch.
+func appendExcludedFrameResults(ctx sessionctx.Context, windowFuncs []aggfuncs.AggFunc, partialResults []aggfuncs.PartialResult,
+	rows []chunk.Row, chk *chunk.Chunk, remained int, exclude core.FrameExclusion, peers *peerGroups, nextFrame nextFrameFunc) error {
+	if exclude != core.FrameExcludeCurrentRow {
+		if err := peers.build(ctx, rows); err != nil {
+			return err
+		}
+	}
+	slidingWindowAggFuncs := make([]aggfuncs.SlidingWindowAggFunc, len(windowFuncs))
+	// rightResults stores the partial results of the right segments for max and min.
+	rightResults := make([]aggfuncs.PartialResult, len(windowFuncs))
+	for i, windowFunc := range windowFuncs {
+		if slidingWindowAggFunc, ok := windowFunc.(aggfuncs.SlidingWindowAggFunc); ok {
+			slidingWindowAggFuncs[i] = slidingWindowAggFunc
+			if _, ok := windowFunc.(aggfuncs.MaxMinSlidingWindowAggFunc); ok {
+				rightResults[i], _ = windowFunc.AllocPartialResult()
+			}
+		}
+	}
+	var leftStart, leftEnd, rightStart, rightEnd uint64
+	for ; remained > 0; remained-- {
+		cur, start, end, err := nextFrame()
+		if err != nil {
+			return err
+		}
+		excludeStart, excludeEnd := cur, cur+1
+		if exclude != core.FrameExcludeCurrentRow {
+			excludeStart, excludeEnd = peers.bounds(cur)
+		}
+		lastLeftStart, lastLeftEnd, lastRightStart, lastRightEnd := leftStart, leftEnd, rightStart, rightEnd
+		leftStart, leftEnd = start, mathutil.MaxUint64(start, mathutil.MinUint64(excludeStart, end))
+		rightStart = mathutil.MaxUint64(start, excludeEnd)
+		rightEnd = mathutil.MaxUint64(end, rightStart)
+		keepCurRow := exclude == core.FrameExcludeTies && start <= cur && cur < end
+
+		for i, windowFunc := range windowFuncs {
+			slidingWindowAggFunc := slidingWindowAggFuncs[i]
+			switch {
+			case slidingWindowAggFunc == nil:
+				segments := [][]chunk.Row{rows[leftStart:leftEnd], nil, rows[rightStart:rightEnd]}
+				if keepCurRow {
+					segments[1] = rows[cur : cur+1]
+				}
+				for _, segment := range segments {
+					if _, err = windowFunc.UpdatePartialResult(ctx, segment, partialResults[i]); err != nil {
+						return err
+					}
+				}
+				err = windowFunc.AppendFinalResult2Chunk(ctx, partialResults[i], chk)
+				windowFunc.ResetPartialResult(partialResults[i])
+			case rightResults[i] == nil:
+				err = slideFrameSegment(ctx, slidingWindowAggFunc, rows, lastLeftStart, lastLeftEnd, leftStart, leftEnd, partialResults[i])
+				if err != nil {
+					return err
+				}
+				err = slideFrameSegment(ctx, slidingWindowAggFunc, rows, lastRightStart, lastRightEnd, rightStart, rightEnd, partialResults[i])
+				if err != nil {
+					return err
+				}
+				if !keepCurRow {
+					err = windowFunc.AppendFinalResult2Chunk(ctx, partialResults[i], chk)
+					break
+				}
+				// Add the current row for the result, and subtract it after that.
+				if err = slidingWindowAggFunc.Slide(ctx, rows, cur, cur, 0, 1, partialResults[i]); err != nil {
+					return err
+				}
+				if err = windowFunc.AppendFinalResult2Chunk(ctx, partialResults[i], chk); err != nil {
+					return err
+				}
+				err = slidingWindowAggFunc.Slide(ctx, rows, cur, cur, 1, 0, partialResults[i])
+			default:
+				err = slideFrameSegment(ctx, slidingWindowAggFunc, rows, lastLeftStart, lastLeftEnd, leftStart, leftEnd, partialResults[i])
+				if err != nil {
+					return err
+				}
+				err = slideFrameSegment(ctx, slidingWindowAggFunc, rows, lastRightStart, lastRightEnd, rightStart, rightEnd, rightResults[i])
+				if err != nil {
+					return err
+				}
+				// The merged partial result may share the deque with the merged ones,
+				// so it is allocated for every row and never updated after merging.
+				frameResult, _ := windowFunc.AllocPartialResult()
+				if keepCurRow {
+					windowFunc.(aggfuncs.MaxMinSlidingWindowAggFunc).SetWindowStart(cur)
+					if _, err = windowFunc.UpdatePartialResult(ctx, rows[cur:cur+1], frameResult); err != nil {
+						return err
+					}
+				}
+				for _, pr := range []aggfuncs.PartialResult{partialResults[i], rightResults[i]} {
+					if _, err = windowFunc.MergePartialResult(ctx, pr, frameResult); err != nil {
+						return err
+					}
+				}
+				err = windowFunc.AppendFinalResult2Chunk(ctx, frameResult, chk)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for i, windowFunc := range windowFuncs {
+		windowFunc.ResetPartialResult(partialResults[i])
+		if rightResults[i] != nil {
+			windowFunc.ResetPartialResult(rightResults[i])
+		}
+	}
+	return nil
 }
 
-func (p *rangeFrameWindowProcessor) resetPartialResult() {
-	p.curRowIdx = 0
-	p.lastStartOffset = 0
-	p.lastEndOffset = 0
+// slideFrameSegment slides the window function from the rows [lastStart, lastEnd) to [start, end),
+// where lastStart <= start and lastEnd <= end.
+func slideFrameSegment(ctx sessionctx.Context, windowFunc aggfuncs.SlidingWindowAggFunc, rows []chunk.Row,
+	lastStart, lastEnd, start, end uint64, pr aggfuncs.PartialResult) error {
+	if start >= lastEnd {
+		// The segments do not overlap, so remove all the rows of the last one and add all the rows of the new one.
+		return windowFunc.Slide(ctx, rows, lastStart, start, lastEnd-lastStart, end-start, pr)
+	}
+	return windowFunc.Slide(ctx, rows, lastStart, lastEnd, start-lastStart, end-lastEnd, pr)
 }
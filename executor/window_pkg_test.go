@@ -0,0 +1,219 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"math/rand"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/executor/aggfuncs"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/expression/aggregation"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/mock"
+)
+
+// windowFrameTestCase describes a frame whose bounds are counted in rows for `Rows`, and in peer groups otherwise.
+type windowFrameTestCase struct {
+	tp         ast.FrameType
+	start, end plannercore.FrameBound
+}
+
+// contains checks whether the row at offset pos, which is counted in rows or peer groups, from the current row is in the frame.
+func (f windowFrameTestCase) contains(pos int64) bool {
+	check := func(bound plannercore.FrameBound, isStart bool) bool {
+		if bound.UnBounded {
+			return true
+		}
+		offset := int64(bound.Num)
+		switch bound.Type {
+		case ast.Preceding:
+			offset = -offset
+		case ast.CurrentRow:
+			offset = 0
+		}
+		if isStart {
+			return pos >= offset
+		}
+		return pos <= offset
+	}
+	return check(f.start, true) && check(f.end, false)
+}
+
+func (f windowFrameTestCase) build(ctx sessionctx.Context, orderByCol *expression.Column, exclude plannercore.FrameExclusion,
+	windowFuncs []aggfuncs.AggFunc, partialResults []aggfuncs.PartialResult) windowProcessor {
+	start, end := f.start, f.end
+	var peers *peerGroups
+	if f.tp == ast.Groups || exclude == plannercore.FrameExcludeGroup || exclude == plannercore.FrameExcludeTies {
+		peers = newPeerGroups(ctx, []*expression.Column{orderByCol})
+	}
+	switch f.tp {
+	case ast.Rows:
+		return &rowFrameWindowProcessor{windowFuncs: windowFuncs, partialResults: partialResults, start: &start, end: &end, exclude: exclude, peers: peers}
+	case ast.Groups:
+		return &groupFrameWindowProcessor{windowFuncs: windowFuncs, partialResults: partialResults, start: &start, end: &end, exclude: exclude, peers: peers}
+	}
+	for _, bound := range []*plannercore.FrameBound{&start, &end} {
+		if bound.Type == ast.CurrentRow {
+			bound.CalcFuncs = []expression.Expression{orderByCol}
+			bound.CmpFuncs = []expression.CompareFunc{expression.GetCmpFunction(ctx, orderByCol, orderByCol)}
+		}
+	}
+	return &rangeFrameWindowProcessor{windowFuncs: windowFuncs, partialResults: partialResults, start: &start, end: &end, exclude: exclude, peers: peers,
+		orderByCols: []*expression.Column{orderByCol}, expectedCmpResult: -1}
+}
+
+func (s *pkgTestSuite) TestWindowFrameExclusion(c *C) {
+	ctx := mock.NewContext()
+	fieldTypes := []*types.FieldType{
+		types.NewFieldType(mysql.TypeLonglong),
+		types.NewFieldType(mysql.TypeLonglong),
+		types.NewFieldType(mysql.TypeDouble),
+	}
+	orderByCol := &expression.Column{Index: 0, RetType: fieldTypes[0]}
+	intCol := &expression.Column{Index: 1, RetType: fieldTypes[1]}
+	realCol := &expression.Column{Index: 2, RetType: fieldTypes[2]}
+
+	// Generate 2 partitions sorted on the first column, whose values have a lot of duplicates.
+	partitions := make([][]chunk.Row, 2)
+	groups := make([][]int64, 2)
+	for i := range partitions {
+		numRows := 30 + rand.Intn(30)
+		input := chunk.New(fieldTypes, numRows, numRows)
+		key := int64(0)
+		for j := 0; j < numRows; j++ {
+			if rand.Intn(3) == 0 {
+				key += int64(rand.Intn(3)) + 1
+			}
+			input.AppendInt64(0, key)
+			if rand.Intn(8) == 0 {
+				input.AppendNull(1)
+				input.AppendNull(2)
+			} else {
+				input.AppendInt64(1, rand.Int63n(100))
+				input.AppendFloat64(2, float64(rand.Intn(100)))
+			}
+			partitions[i] = append(partitions[i], input.GetRow(j))
+			groups[i] = append(groups[i], key)
+		}
+	}
+
+	funcs := []struct {
+		name string
+		arg  *expression.Column
+	}{
+		{ast.AggFuncSum, realCol},
+		{ast.AggFuncAvg, realCol},
+		{ast.AggFuncCount, intCol},
+		{ast.AggFuncMax, realCol},
+		{ast.AggFuncMin, intCol},
+		{ast.AggFuncBitXor, intCol},
+		{ast.WindowFuncFirstValue, intCol},
+		{ast.WindowFuncLastValue, realCol},
+	}
+	frames := []windowFrameTestCase{
+		{ast.Rows, plannercore.FrameBound{Type: ast.Preceding, Num: 2}, plannercore.FrameBound{Type: ast.Following, Num: 1}},
+		{ast.Rows, plannercore.FrameBound{Type: ast.Preceding, UnBounded: true}, plannercore.FrameBound{Type: ast.CurrentRow}},
+		{ast.Rows, plannercore.FrameBound{Type: ast.Following, Num: 1}, plannercore.FrameBound{Type: ast.Following, Num: 3}},
+		{ast.Ranges, plannercore.FrameBound{Type: ast.Preceding, UnBounded: true}, plannercore.FrameBound{Type: ast.CurrentRow}},
+		{ast.Ranges, plannercore.FrameBound{Type: ast.CurrentRow}, plannercore.FrameBound{Type: ast.Following, UnBounded: true}},
+		{ast.Groups, plannercore.FrameBound{Type: ast.Preceding, Num: 1}, plannercore.FrameBound{Type: ast.Following, Num: 1}},
+		{ast.Groups, plannercore.FrameBound{Type: ast.Preceding, Num: 2}, plannercore.FrameBound{Type: ast.Preceding, Num: 1}},
+		{ast.Groups, plannercore.FrameBound{Type: ast.CurrentRow}, plannercore.FrameBound{Type: ast.Following, Num: 2}},
+		{ast.Groups, plannercore.FrameBound{Type: ast.Following, Num: 1}, plannercore.FrameBound{Type: ast.Following, UnBounded: true}},
+	}
+	exclusions := []plannercore.FrameExclusion{
+		plannercore.FrameExcludeNoOthers,
+		plannercore.FrameExcludeCurrentRow,
+		plannercore.FrameExcludeGroup,
+		plannercore.FrameExcludeTies,
+	}
+	for _, f := range funcs {
+		desc, err := aggregation.NewAggFuncDesc(ctx, f.name, []expression.Expression{f.arg}, false)
+		c.Assert(err, IsNil)
+		for _, frame := range frames {
+			for _, exclude := range exclusions {
+				comment := Commentf("func: %v, frame: %+v, exclude: %v", f.name, frame, exclude)
+				windowFunc := aggfuncs.BuildWindowFunctions(ctx, desc, 0, []*expression.Column{orderByCol})
+				partialResult, _ := windowFunc.AllocPartialResult()
+				processor := frame.build(ctx, orderByCol, exclude, []aggfuncs.AggFunc{windowFunc}, []aggfuncs.PartialResult{partialResult})
+				// The frames of a partition are evaluated in several batches, like the WindowExec does.
+				obtained := chunk.NewChunkWithCapacity([]*types.FieldType{desc.RetTp}, 1)
+				for _, rows := range partitions {
+					for remained := len(rows); remained > 0; {
+						batch := 1 + rand.Intn(remained)
+						_, err = processor.appendResult2Chunk(ctx, rows, obtained, batch)
+						c.Assert(err, IsNil, comment)
+						remained -= batch
+					}
+					processor.resetPartialResult()
+				}
+
+				// Evaluate the frames row by row with a fresh window function.
+				expected := chunk.NewChunkWithCapacity([]*types.FieldType{desc.RetTp}, 1)
+				for i, rows := range partitions {
+					for cur := range rows {
+						var frameRows []chunk.Row
+						for j := range rows {
+							pos := int64(j - cur)
+							if frame.tp != ast.Rows {
+								pos = groupDistance(groups[i], cur, j)
+							}
+							isPeer := groups[i][j] == groups[i][cur]
+							switch {
+							case !frame.contains(pos):
+							case exclude == plannercore.FrameExcludeCurrentRow && j == cur:
+							case exclude == plannercore.FrameExcludeGroup && isPeer:
+							case exclude == plannercore.FrameExcludeTies && isPeer && j != cur:
+							default:
+								frameRows = append(frameRows, rows[j])
+							}
+						}
+						bruteForce := aggfuncs.BuildWindowFunctions(ctx, desc, 0, []*expression.Column{orderByCol})
+						pr, _ := bruteForce.AllocPartialResult()
+						_, err = bruteForce.UpdatePartialResult(ctx, frameRows, pr)
+						c.Assert(err, IsNil)
+						c.Assert(bruteForce.AppendFinalResult2Chunk(ctx, pr, expected), IsNil)
+					}
+				}
+
+				c.Assert(obtained.NumRows(), Equals, expected.NumRows(), comment)
+				for i := 0; i < expected.NumRows(); i++ {
+					c.Assert(obtained.GetRow(i).GetDatum(0, desc.RetTp), DeepEquals, expected.GetRow(i).GetDatum(0, desc.RetTp), Commentf("row %d, func: %v, frame: %+v, exclude: %v", i, f.name, frame, exclude))
+				}
+			}
+		}
+	}
+}
+
+// groupDistance returns the number of peer groups from the row cur to the row j, the rows are sorted on groups.
+func groupDistance(groups []int64, cur, j int) int64 {
+	distance := int64(0)
+	for i := cur; i < j; i++ {
+		if groups[i] != groups[i+1] {
+			distance++
+		}
+	}
+	for i := cur; i > j; i-- {
+		if groups[i] != groups[i-1] {
+			distance--
+		}
+	}
+	return distance
+}
@@ -79,6 +79,19 @@ func doTestWindowFunctions(tk *testkit.TestKit) {
 	result = tk.MustQuery("select a, b, sum(a) over(order by b desc range between interval 1 day preceding and interval 2 day following) from t")
 	result.Check(testkit.Rows("5 2019-02-05 8", "3 2019-02-03 6", "2 2019-02-02 6", "1 2019-02-01 3", "<nil> <nil> <nil>"))
 
+	tk.MustExec("drop table t")
+	tk.MustExec("create table t(a int, b int)")
+	tk.MustExec("insert into t values (1,1),(1,2),(2,3),(3,4),(3,5),(5,6)")
+	result = tk.MustQuery("select a, b, sum(b) over(order by a groups between 1 preceding and current row) from t order by a, b")
+	result.Check(testkit.Rows("1 1 3", "1 2 3", "2 3 6", "3 4 12", "3 5 12", "5 6 15"))
+	result = tk.MustQuery("select a, b, count(b) over(order by a groups between 1 following and 2 following) from t order by a, b")
+	result.Check(testkit.Rows("1 1 3", "1 2 3", "2 3 3", "3 4 1", "3 5 1", "5 6 0"))
+	result = tk.MustQuery("select a, b, max(b) over(order by a desc groups current row) from t order by a, b")
+	result.Check(testkit.Rows("1 1 2", "1 2 2", "2 3 3", "3 4 5", "3 5 5", "5 6 6"))
+	result = tk.MustQuery("select a, b, sum(b) over(partition by a > 2 order by a groups between unbounded preceding and 1 preceding) from t order by a, b")
+	result.Check(testkit.Rows("1 1 <nil>", "1 2 <nil>", "2 3 3", "3 4 <nil>", "3 5 <nil>", "5 6 9"))
+	tk.MustGetErrMsg("select sum(b) over(order by a groups interval 1 day preceding) from t", "[planner:3596]Window '<unnamed window>': INTERVAL can only be used with RANGE frames.")
+
 	tk.MustExec("drop table t")
 	tk.MustExec("CREATE TABLE t (id INTEGER, sex CHAR(1))")
 	tk.MustExec("insert into t values (1, 'M'), (2, 'F'), (3, 'F'), (4, 'F'), (5, 'M'), (10, NULL), (11, NULL)")
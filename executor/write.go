@@ -32,6 +32,7 @@ import (
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/memory"
 )
@@ -259,3 +260,19 @@ func resetErrDataTooLong(colName string, rowIdx int, err error) error {
 	newErr := types.ErrDataTooLong.GenWithStack("Data too long for column '%v' at row %v", colName, rowIdx)
 	return newErr
 }
+
+// checkRowPolicy checks the row written to the table satisfies the row policies of the table, the check is nil if the
+// user isn't restricted by any policy.
+func checkRowPolicy(sctx sessionctx.Context, check expression.Expression, t table.Table, row []types.Datum) error {
+	if check == nil {
+		return nil
+	}
+	ok, _, err := expression.EvalBool(sctx, expression.CNFExprs{check}, chunk.MutRowFromDatums(row).ToRow())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrRowPolicyViolation.GenWithStackByArgs(t.Meta().Name.O)
+	}
+	return nil
+}
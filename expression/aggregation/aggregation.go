@@ -19,6 +19,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
@@ -172,13 +173,33 @@ func NeedCount(name string) bool {
 func NeedValue(name string) bool {
 	switch name {
 	case ast.AggFuncSum, ast.AggFuncAvg, ast.AggFuncFirstRow, ast.AggFuncMax, ast.AggFuncMin,
-		ast.AggFuncGroupConcat, ast.AggFuncBitOr, ast.AggFuncBitAnd, ast.AggFuncBitXor, ast.AggFuncApproxPercentile:
+		ast.AggFuncGroupConcat, ast.AggFuncBitOr, ast.AggFuncBitAnd, ast.AggFuncBitXor:
 		return true
 	default:
 		return false
 	}
 }
 
+// ApproxPercentileSplittable checks whether the partial result of approx_percentile can be
+// passed to the final phase as a serialized t-digest, which holds only numeric and duration values.
+func ApproxPercentileSplittable(aggFunc *AggFuncDesc) bool {
+	if aggFunc.Args[0].GetType().Tp == mysql.TypeBit {
+		return false
+	}
+	switch aggFunc.Args[0].GetType().EvalType() {
+	case types.ETInt, types.ETReal, types.ETDecimal, types.ETDuration:
+		return true
+	}
+	return false
+}
+
+// IsApproxPercentileState checks whether ft is the type of the serialized t-digest output by
+// the partial approx_percentile pushed down across union. It's distinguished from the string
+// values by the NotNull flag, which is always removed from the result type of approx_percentile.
+func IsApproxPercentileState(ft *types.FieldType) bool {
+	return ft.Tp == mysql.TypeString && mysql.HasNotNullFlag(ft.Flag)
+}
+
 // IsAllFirstRow checks whether functions in `aggFuncs` are all FirstRow.
 func IsAllFirstRow(aggFuncs []*AggFuncDesc) bool {
 	for _, fun := range aggFuncs {
@@ -194,6 +215,9 @@ func CheckAggPushDown(aggFunc *AggFuncDesc, storeType kv.StoreType) bool {
 	if len(aggFunc.OrderByItems) > 0 {
 		return false
 	}
+	// approx_percentile is only split into the partial and final phases in TiDB. The coprocessors can't
+	// compute it because tipb has neither an expression type for it nor a format for its t-digest.
+	// TODO: push it down to TiKV/TiFlash once tipb supports it.
 	if aggFunc.Name == ast.AggFuncApproxPercentile {
 		return false
 	}
@@ -211,7 +235,8 @@ func CheckAggPushDown(aggFunc *AggFuncDesc, storeType kv.StoreType) bool {
 // CheckAggPushFlash checks whether an agg function can be pushed to flash storage.
 func CheckAggPushFlash(aggFunc *AggFuncDesc) bool {
 	switch aggFunc.Name {
-	case ast.AggFuncSum, ast.AggFuncCount, ast.AggFuncMin, ast.AggFuncMax, ast.AggFuncAvg, ast.AggFuncFirstRow, ast.AggFuncApproxCountDistinct:
+	case ast.AggFuncSum, ast.AggFuncCount, ast.AggFuncMin, ast.AggFuncMax, ast.AggFuncAvg, ast.AggFuncFirstRow, ast.AggFuncApproxCountDistinct,
+		ast.AggFuncBitAnd, ast.AggFuncBitOr, ast.AggFuncBitXor:
 		return true
 	}
 	return false
@@ -273,7 +273,34 @@ func (a *baseFuncDesc) typeInfer4BitFuncs(ctx sessionctx.Context) {
 	a.RetTp.Flen = 21
 	types.SetBinChsClnFlag(a.RetTp)
 	a.RetTp.Flag |= mysql.UnsignedFlag | mysql.NotNullFlag
-	// TODO: a.Args[0] = expression.WrapWithCastAsInt(ctx, a.Args[0])
+	// Cast the argument here rather than in WrapCastForAggArgs, so the cast is also pushed down with the function.
+	a.Args[0] = wrapWithCastAsUnsigned(ctx, a.Args[0])
+}
+
+// wrapWithCastAsUnsigned converts `expr` to the 64-bit unsigned representation used by the bit functions if the
+// return type of expr is not type int. The non-negative values are cast as unsigned BIGINT, so the values beyond
+// the range of a signed BIGINT don't overflow, and the negative values are cast as signed BIGINT first, so they
+// keep their two's complement bits like the integers do.
+func wrapWithCastAsUnsigned(ctx sessionctx.Context, expr expression.Expression) expression.Expression {
+	if expr.GetType().EvalType() == types.ETInt {
+		return expr
+	}
+	unsignedTp := types.NewFieldType(mysql.TypeLonglong)
+	unsignedTp.Flen, unsignedTp.Decimal = expr.GetType().Flen, 0
+	types.SetBinChsClnFlag(unsignedTp)
+	signedTp := unsignedTp.Clone()
+	unsignedTp.Flag |= mysql.UnsignedFlag
+	zero := &expression.Constant{Value: types.NewIntDatum(0), RetType: types.NewFieldType(mysql.TypeLonglong)}
+	nonNegative, err := expression.NewFunction(ctx, ast.GE, types.NewFieldType(mysql.TypeLonglong), expr, zero)
+	if err != nil {
+		return expression.BuildCastFunction(ctx, expr, unsignedTp)
+	}
+	negative := expression.BuildCastFunction(ctx, expression.BuildCastFunction(ctx, expr, signedTp), unsignedTp)
+	ret, err := expression.NewFunction(ctx, ast.If, unsignedTp, nonNegative, expression.BuildCastFunction(ctx, expr, unsignedTp), negative)
+	if err != nil {
+		return expression.BuildCastFunction(ctx, expr, unsignedTp)
+	}
+	return ret
 }
 
 func (a *baseFuncDesc) typeInfer4JsonFuncs(ctx sessionctx.Context) {
@@ -379,6 +379,10 @@ func (b *baseBuiltinFunc) isChildrenVectorized() bool {
 func (b *baseBuiltinFunc) getRetTp() *types.FieldType {
 	switch b.tp.EvalType() {
 	case types.ETString:
+		// geometry values keep their own type regardless of the length.
+		if b.tp.Tp == mysql.TypeGeometry {
+			break
+		}
 		if b.tp.Flen >= mysql.MaxBlobWidth {
 			b.tp.Tp = mysql.TypeLongBlob
 		} else if b.tp.Flen >= 65536 {
@@ -787,6 +791,9 @@ var funcs = map[string]functionClass{
 	ast.ReleaseAllLocks: &releaseAllLocksFunctionClass{baseFunctionClass{ast.ReleaseAllLocks, 0, 0}},
 	ast.UUID:            &uuidFunctionClass{baseFunctionClass{ast.UUID, 0, 0}},
 	ast.UUIDShort:       &uuidShortFunctionClass{baseFunctionClass{ast.UUIDShort, 0, 0}},
+	ast.UUIDToBin:       &uuidToBinFunctionClass{baseFunctionClass{ast.UUIDToBin, 1, 2}},
+	ast.BinToUUID:       &binToUUIDFunctionClass{baseFunctionClass{ast.BinToUUID, 1, 2}},
+	isUUID:              &isUUIDFunctionClass{baseFunctionClass{isUUID, 1, 1}},
 	ast.VitessHash:      &vitessHashFunctionClass{baseFunctionClass{ast.VitessHash, 1, 1}},
 
 	// get_lock() and release_lock() are parsed but do nothing.
@@ -877,13 +884,38 @@ var funcs = map[string]functionClass{
 	ast.JSONLength:        &jsonLengthFunctionClass{baseFunctionClass{ast.JSONLength, 1, 2}},
 
 	// TiDB internal function.
-	ast.TiDBDecodeKey: &tidbDecodeKeyFunctionClass{baseFunctionClass{ast.TiDBDecodeKey, 1, 1}},
+	ast.TiDBDecodeKey:    &tidbDecodeKeyFunctionClass{baseFunctionClass{ast.TiDBDecodeKey, 1, 1}},
+	tidbDecodeIndexValue: &tidbDecodeIndexValueFunctionClass{baseFunctionClass{tidbDecodeIndexValue, 2, 2}},
+	tidbMVCCInfo:         &tidbMVCCInfoFunctionClass{baseFunctionClass{tidbMVCCInfo, 1, 1}},
 	// This function is used to show tidb-server version info.
 	ast.TiDBVersion:    &tidbVersionFunctionClass{baseFunctionClass{ast.TiDBVersion, 0, 0}},
 	ast.TiDBIsDDLOwner: &tidbIsDDLOwnerFunctionClass{baseFunctionClass{ast.TiDBIsDDLOwner, 0, 0}},
 	ast.TiDBParseTso:   &tidbParseTsoFunctionClass{baseFunctionClass{ast.TiDBParseTso, 1, 1}},
 	ast.TiDBDecodePlan: &tidbDecodePlanFunctionClass{baseFunctionClass{ast.TiDBDecodePlan, 1, 1}},
 
+	// spatial functions.
+	stGeomFromText:     &stGeomFromTextFunctionClass{baseFunctionClass{stGeomFromText, 1, 2}},
+	stGeometryFromText: &stGeomFromTextFunctionClass{baseFunctionClass{stGeometryFromText, 1, 2}},
+	stAsText:           &stAsTextFunctionClass{baseFunctionClass{stAsText, 1, 1}},
+	stAsWKT:            &stAsTextFunctionClass{baseFunctionClass{stAsWKT, 1, 1}},
+	stX:                &stCoordinateFunctionClass{baseFunctionClass{stX, 1, 1}},
+	stY:                &stCoordinateFunctionClass{baseFunctionClass{stY, 1, 1}},
+	stDistance:         &stDistanceFunctionClass{baseFunctionClass{stDistance, 2, 2}},
+	stContains:         &stContainsFunctionClass{baseFunctionClass{stContains, 2, 2}},
+
+	// string similarity functions.
+	ast.Soundex: &soundexFunctionClass{baseFunctionClass{ast.Soundex, 1, 1}},
+	levenshtein: &levenshteinFunctionClass{baseFunctionClass{levenshtein, 2, 2}},
+	jaroWinkler: &jaroWinklerFunctionClass{baseFunctionClass{jaroWinkler, 2, 2}},
+
+	// data masking functions.
+	maskInner: &maskInnerFunctionClass{baseFunctionClass{maskInner, 3, 4}},
+	maskPan:   &maskPanFunctionClass{baseFunctionClass{maskPan, 1, 1}},
+	maskEmail: &maskEmailFunctionClass{baseFunctionClass{maskEmail, 1, 1}},
+
+	// full-text search function, MATCH ... AGAINST is rewritten to it.
+	MatchAgainst: &matchAgainstFunctionClass{baseFunctionClass{MatchAgainst, 2, -1}},
+
 	// TiDB Sequence function.
 	ast.NextVal: &nextValFunctionClass{baseFunctionClass{ast.NextVal, 1, 1}},
 	ast.LastVal: &lastValFunctionClass{baseFunctionClass{ast.LastVal, 1, 1}},
@@ -18,6 +18,7 @@ import (
 	"strings"
 
 	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/charset"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/opcode"
 	"github.com/pingcap/parser/terror"
@@ -25,6 +26,7 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tipb/go-tipb"
 )
 
@@ -1343,6 +1345,7 @@ func (c *compareFunctionClass) refineArgs(ctx sessionctx.Context, args []Express
 	if ContainMutableConst(ctx, args) {
 		return args
 	}
+	args = c.refineArgsByUUID(ctx, args)
 	arg0Type, arg1Type := args[0].GetType(), args[1].GetType()
 	arg0IsInt := arg0Type.EvalType() == types.ETInt
 	arg1IsInt := arg1Type.EvalType() == types.ETInt
@@ -1432,6 +1435,65 @@ func (c *compareFunctionClass) refineArgs(ctx sessionctx.Context, args []Express
 	return c.refineArgsByUnsignedFlag(ctx, []Expression{finalArg0, finalArg1})
 }
 
+// refineArgsByUUID rewrites `BIN_TO_UUID(col[, swap_flag]) <cmp> constant` to `col <cmp> UUID_TO_BIN(constant[, swap_flag])`
+// for the (in)equality comparisons, so the ranges can be built on the binary column which stores the UUIDs. The
+// rewrite is skipped if the constant can't be returned by BIN_TO_UUID, because the comparison is always false then.
+func (c *compareFunctionClass) refineArgsByUUID(ctx sessionctx.Context, args []Expression) []Expression {
+	if c.op != opcode.EQ && c.op != opcode.NullEQ && c.op != opcode.NE {
+		return args
+	}
+	for i := 0; i < 2; i++ {
+		sf, ok := args[i].(*ScalarFunction)
+		if !ok || sf.FuncName.L != ast.BinToUUID {
+			continue
+		}
+		con, ok := args[1-i].(*Constant)
+		if !ok || con.DeferredExpr != nil || con.ParamMarker != nil {
+			return args
+		}
+		sfArgs := sf.GetArgs()
+		col, ok := sfArgs[0].(*Column)
+		if !ok || col.GetType().EvalType() != types.ETString || col.GetType().Collate != charset.CollationBin {
+			return args
+		}
+		swap := false
+		if len(sfArgs) == 2 {
+			flag, ok := sfArgs[1].(*Constant)
+			if !ok || flag.DeferredExpr != nil || flag.ParamMarker != nil {
+				return args
+			}
+			v, isNull, err := flag.EvalInt(ctx, chunk.Row{})
+			if err != nil {
+				return args
+			}
+			swap = !isNull && v != 0
+		}
+		str, isNull, err := con.EvalString(ctx, chunk.Row{})
+		if err != nil || isNull {
+			return args
+		}
+		bin, ok := parseUUID(str)
+		if !ok {
+			return args
+		}
+		// BIN_TO_UUID returns the lowercase UUIDs with dashes, the constant must equal it in the compared collation.
+		_, collation := DeriveCollationFromExprs(ctx, args...)
+		if formatted, err := binToUUID(string(bin), false); err != nil || collate.GetCollator(collation).Compare(str, formatted) != 0 {
+			return args
+		}
+		if swap {
+			bin = swapUUIDTime(bin)
+		}
+		tp := types.NewFieldType(mysql.TypeVarString)
+		tp.Flen = len(bin)
+		types.SetBinChsClnFlag(tp)
+		refined := make([]Expression, 2)
+		refined[i], refined[1-i] = col, &Constant{Value: types.NewBytesDatum(bin), RetType: tp}
+		return refined
+	}
+	return args
+}
+
 func (c *compareFunctionClass) refineArgsByUnsignedFlag(ctx sessionctx.Context, args []Expression) []Expression {
 	// Only handle int cases, cause MySQL declares that `UNSIGNED` is deprecated for FLOAT, DOUBLE and DECIMAL types,
 	// and support for it would be removed in a future version.
@@ -1499,6 +1561,7 @@ func (c *compareFunctionClass) getFunction(ctx sessionctx.Context, rawArgs []Exp
 	}
 	args := c.refineArgs(ctx, rawArgs)
 	cmpType := GetAccurateCmpType(args[0], args[1])
+	recordImplicitCasts(ctx, c.funcName, args, cmpType)
 	sig, err = c.generateCmpSigs(ctx, args, cmpType)
 	return sig, err
 }
@@ -0,0 +1,101 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/fulltext"
+)
+
+// MatchAgainst is the name of the function which MATCH (col, ...) AGAINST (expr) is rewritten to.
+// Its first argument is the search string and the others are the matched columns.
+const MatchAgainst = "match_against"
+
+var (
+	_ functionClass = &matchAgainstFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinMatchAgainstSig{}
+)
+
+type matchAgainstFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *matchAgainstFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	// Same as MySQL, the search string must be constant during the evaluation.
+	if _, ok := args[0].(*Constant); !ok {
+		return nil, errIncorrectArgs.GenWithStackByArgs("AGAINST")
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for range args {
+		argTps = append(argTps, types.ETString)
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETReal, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinMatchAgainstSig{baseBuiltinFunc: bf}
+	return sig, nil
+}
+
+type builtinMatchAgainstSig struct {
+	baseBuiltinFunc
+
+	// search and query memorize the last search string and its tokenized query.
+	search string
+	query  *fulltext.Query
+}
+
+func (b *builtinMatchAgainstSig) Clone() builtinFunc {
+	newSig := &builtinMatchAgainstSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinMatchAgainstSig) getQuery(search string) *fulltext.Query {
+	if b.query == nil || b.search != search {
+		b.search, b.query = search, fulltext.NewQuery(search)
+	}
+	return b.query
+}
+
+// evalReal evals a builtinMatchAgainstSig in natural language mode.
+// See https://dev.mysql.com/doc/refman/5.7/en/fulltext-natural-language.html
+func (b *builtinMatchAgainstSig) evalReal(row chunk.Row) (float64, bool, error) {
+	search, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if err != nil {
+		return 0, true, err
+	}
+	if isNull {
+		return 0, false, nil
+	}
+	docs := make([]string, 0, len(b.args)-1)
+	for _, arg := range b.args[1:] {
+		doc, isNull, err := arg.EvalString(b.ctx, row)
+		if err != nil {
+			return 0, true, err
+		}
+		if !isNull {
+			docs = append(docs, doc)
+		}
+	}
+	return b.getQuery(search).Relevance(docs...), false, nil
+}
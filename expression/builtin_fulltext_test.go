@@ -0,0 +1,48 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"math"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+func (s *testEvaluatorSuite) TestMatchAgainst(c *C) {
+	tbl := []struct {
+		args     []interface{}
+		expected float64
+	}{
+		{[]interface{}{"database", "TiDB is a distributed database"}, 1},
+		{[]interface{}{"database tidb", "TiDB is a distributed database", "another database"}, 2 + math.Log(2)},
+		{[]interface{}{"database", "no match", nil}, 0},
+		{[]interface{}{nil, "database"}, 0},
+		{[]interface{}{"the", "the stopword"}, 0},
+	}
+	for _, t := range tbl {
+		f, err := newFunctionForTest(s.ctx, MatchAgainst, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		c.Assert(f.GetType().Tp, Equals, mysql.TypeDouble)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewFloat64Datum(t.expected))
+	}
+
+	_, err := funcs[MatchAgainst].getFunction(s.ctx, []Expression{&Column{RetType: types.NewFieldType(mysql.TypeVarchar), Index: 0}, &Column{RetType: types.NewFieldType(mysql.TypeVarchar), Index: 1}})
+	c.Assert(errIncorrectArgs.Equal(err), IsTrue)
+}
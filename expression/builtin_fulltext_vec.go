@@ -0,0 +1,57 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (b *builtinMatchAgainstSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinMatchAgainstSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs := make([]*chunk.Column, len(b.args))
+	for i, arg := range b.args {
+		buf, err := b.bufAllocator.get(types.ETString, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(buf)
+		if err := arg.VecEvalString(b.ctx, input, buf); err != nil {
+			return err
+		}
+		bufs[i] = buf
+	}
+
+	result.ResizeFloat64(n, false)
+	f64s := result.Float64s()
+	docs := make([]string, 0, len(b.args)-1)
+	for i := 0; i < n; i++ {
+		if bufs[0].IsNull(i) {
+			f64s[i] = 0
+			continue
+		}
+		docs = docs[:0]
+		for _, buf := range bufs[1:] {
+			if !buf.IsNull(i) {
+				docs = append(docs, buf.GetString(i))
+			}
+		}
+		f64s[i] = b.getQuery(bufs[0].GetString(i)).Relevance(docs...)
+	}
+	return nil
+}
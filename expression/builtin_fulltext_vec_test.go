@@ -0,0 +1,49 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/types"
+)
+
+var vecBuiltinFulltextCases = map[string][]vecExprBenchCase{
+	MatchAgainst: {
+		{
+			retEvalType:   types.ETReal,
+			childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString},
+			geners: []dataGenerator{
+				nil,
+				newSelectStringGener([]string{"tidb database", "distributed sql database", "nothing", ""}),
+				newSelectStringGener([]string{"database", "sql", "TiDB"}),
+			},
+			constants: []*Constant{{Value: types.NewStringDatum("database tidb"), RetType: types.NewFieldType(mysql.TypeVarString)}},
+		},
+	},
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinFulltextEvalOneVec(c *C) {
+	testVectorizedEvalOneVec(c, vecBuiltinFulltextCases)
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinFulltextFunc(c *C) {
+	testVectorizedBuiltinFunc(c, vecBuiltinFulltextCases)
+}
+
+func BenchmarkVectorizedBuiltinFulltextFunc(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltinFulltextCases)
+}
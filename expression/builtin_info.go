@@ -71,6 +71,8 @@ var (
 	_ builtinFunc = &builtinTiDBVersionSig{}
 	_ builtinFunc = &builtinRowCountSig{}
 	_ builtinFunc = &builtinTiDBDecodeKeySig{}
+	_ builtinFunc = &builtinTiDBDecodeIndexValueSig{}
+	_ builtinFunc = &builtinTiDBMVCCInfoSig{}
 	_ builtinFunc = &builtinNextValSig{}
 	_ builtinFunc = &builtinLastValSig{}
 	_ builtinFunc = &builtinSetValSig{}
@@ -761,16 +763,116 @@ func (b *builtinTiDBDecodeKeySig) evalString(row chunk.Row) (string, bool, error
 	return decode(b.ctx, s), false, nil
 }
 
+// TiDB internal functions to decode the keys and values, they are not defined in the parser.
+const (
+	tidbDecodeIndexValue = "tidb_decode_index_value"
+	tidbMVCCInfo         = "tidb_mvcc_info"
+)
+
 // TiDBDecodeKeyFunctionKeyType is used to identify the decoder function in context.
 type TiDBDecodeKeyFunctionKeyType int
 
 // String() implements Stringer.
 func (k TiDBDecodeKeyFunctionKeyType) String() string {
+	switch k {
+	case TiDBDecodeIndexValueFunctionKey:
+		return tidbDecodeIndexValue
+	case TiDBMVCCInfoFunctionKey:
+		return tidbMVCCInfo
+	}
 	return "tidb_decode_key"
 }
 
-// TiDBDecodeKeyFunctionKey is used to identify the decoder function in context.
-const TiDBDecodeKeyFunctionKey TiDBDecodeKeyFunctionKeyType = 0
+const (
+	// TiDBDecodeKeyFunctionKey is used to identify the decoder function in context.
+	TiDBDecodeKeyFunctionKey TiDBDecodeKeyFunctionKeyType = iota
+	// TiDBDecodeIndexValueFunctionKey is used to identify the index value decoder function in context.
+	TiDBDecodeIndexValueFunctionKey
+	// TiDBMVCCInfoFunctionKey is used to identify the MVCC info reader function in context.
+	TiDBMVCCInfoFunctionKey
+)
+
+type tidbDecodeIndexValueFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbDecodeIndexValueFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTiDBDecodeIndexValueSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBDecodeIndexValueSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBDecodeIndexValueSig) Clone() builtinFunc {
+	newSig := &builtinTiDBDecodeIndexValueSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinTiDBDecodeIndexValueSig.
+func (b *builtinTiDBDecodeIndexValueSig) evalString(row chunk.Row) (string, bool, error) {
+	key, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	value, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	decode := func(ctx sessionctx.Context, key, value string) string { return value }
+	if fn := b.ctx.Value(TiDBDecodeIndexValueFunctionKey); fn != nil {
+		decode = fn.(func(ctx sessionctx.Context, key, value string) string)
+	}
+	return decode(b.ctx, key, value), false, nil
+}
+
+type tidbMVCCInfoFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *tidbMVCCInfoFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinTiDBMVCCInfoSig{bf}
+	return sig, nil
+}
+
+type builtinTiDBMVCCInfoSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTiDBMVCCInfoSig) Clone() builtinFunc {
+	newSig := &builtinTiDBMVCCInfoSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinTiDBMVCCInfoSig.
+func (b *builtinTiDBMVCCInfoSig) evalString(row chunk.Row) (string, bool, error) {
+	key, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	getInfo := func(ctx sessionctx.Context, key string) string { return key }
+	if fn := b.ctx.Value(TiDBMVCCInfoFunctionKey); fn != nil {
+		getInfo = fn.(func(ctx sessionctx.Context, key string) string)
+	}
+	return getInfo(b.ctx, key), false, nil
+}
 
 type tidbDecodePlanFunctionClass struct {
 	baseFunctionClass
@@ -842,28 +944,37 @@ func (b *builtinNextValSig) evalInt(row chunk.Row) (int64, bool, error) {
 	if isNull || err != nil {
 		return 0, isNull, err
 	}
-	db, seq := getSchemaAndSequence(sequenceName)
+	sequence, db, seq, err := b.getSequence(sequenceName)
+	if err != nil {
+		return 0, false, err
+	}
+	nextVal, err := sequence.GetSequenceNextVal(b.ctx, db, seq)
+	if err != nil {
+		return 0, false, err
+	}
+	// update the sequenceState.
+	b.ctx.GetSessionVars().SequenceState.UpdateState(sequence.GetSequenceID(), nextVal)
+	return nextVal, false, nil
+}
+
+// getSequence gets the sequence by name and checks the privilege of consuming it.
+func (b *builtinNextValSig) getSequence(sequenceName string) (sequence util.SequenceTable, db, seq string, err error) {
+	db, seq = getSchemaAndSequence(sequenceName)
 	if len(db) == 0 {
 		db = b.ctx.GetSessionVars().CurrentDB
 	}
 	// Check the tableName valid.
-	sequence, err := b.ctx.GetSessionVars().TxnCtx.InfoSchema.(util.SequenceSchema).SequenceByName(model.NewCIStr(db), model.NewCIStr(seq))
+	sequence, err = b.ctx.GetSessionVars().TxnCtx.InfoSchema.(util.SequenceSchema).SequenceByName(model.NewCIStr(db), model.NewCIStr(seq))
 	if err != nil {
-		return 0, false, err
+		return nil, "", "", err
 	}
 	// Do the privilege check.
 	checker := privilege.GetPrivilegeManager(b.ctx)
 	user := b.ctx.GetSessionVars().User
 	if checker != nil && !checker.RequestVerification(b.ctx.GetSessionVars().ActiveRoles, db, seq, "", mysql.InsertPriv) {
-		return 0, false, errSequenceAccessDenied.GenWithStackByArgs("INSERT", user.AuthUsername, user.AuthHostname, seq)
-	}
-	nextVal, err := sequence.GetSequenceNextVal(b.ctx, db, seq)
-	if err != nil {
-		return 0, false, err
+		return nil, "", "", errSequenceAccessDenied.GenWithStackByArgs("INSERT", user.AuthUsername, user.AuthHostname, seq)
 	}
-	// update the sequenceState.
-	b.ctx.GetSessionVars().SequenceState.UpdateState(sequence.GetSequenceID(), nextVal)
-	return nextVal, false, nil
+	return sequence, db, seq, nil
 }
 
 type lastValFunctionClass struct {
@@ -354,3 +354,45 @@ func (b *builtinTiDBDecodeKeySig) vecEvalString(input *chunk.Chunk, result *chun
 	}
 	return nil
 }
+
+func (b *builtinNextValSig) vectorized() bool {
+	return true
+}
+
+// vecEvalInt evals a builtinNextValSig, the values of a constant sequence are allocated in batch.
+func (b *builtinNextValSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ResizeInt64(n, false)
+	i64s := result.Int64s()
+	if _, ok := b.args[0].(*Constant); !ok || n == 0 {
+		for i := 0; i < n; i++ {
+			val, isNull, err := b.evalInt(input.GetRow(i))
+			if err != nil {
+				return err
+			}
+			result.SetNull(i, isNull)
+			i64s[i] = val
+		}
+		return nil
+	}
+	sequenceName, isNull, err := b.args[0].EvalString(b.ctx, chunk.Row{})
+	if err != nil {
+		return err
+	}
+	if isNull {
+		result.ResizeInt64(n, true)
+		return nil
+	}
+	sequence, db, seq, err := b.getSequence(sequenceName)
+	if err != nil {
+		return err
+	}
+	vals, err := sequence.GetSequenceNextVals(b.ctx, db, seq, n)
+	if err != nil {
+		return err
+	}
+	copy(i64s, vals)
+	// update the sequenceState.
+	b.ctx.GetSessionVars().SequenceState.UpdateState(sequence.GetSequenceID(), vals[n-1])
+	return nil
+}
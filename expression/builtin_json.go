@@ -752,7 +752,7 @@ func (b *builtinJSONContainsSig) evalInt(row chunk.Row) (res int64, isNull bool,
 		if err != nil {
 			return res, true, err
 		}
-		if pathExpr.ContainsAnyAsterisk() {
+		if pathExpr.CouldMatchMultipleValues() {
 			return res, true, json.ErrInvalidJSONPathWildcard
 		}
 		var exists bool
@@ -940,7 +940,7 @@ func (b *builtinJSONArrayAppendSig) appendJSONArray(res json.BinaryJSON, p strin
 	if err != nil {
 		return res, true, json.ErrInvalidJSONPath.GenWithStackByArgs(p)
 	}
-	if pathExpr.ContainsAnyAsterisk() {
+	if pathExpr.CouldMatchMultipleValues() {
 		return res, true, json.ErrInvalidJSONPathWildcard.GenWithStackByArgs(p)
 	}
 
@@ -1018,7 +1018,7 @@ func (b *builtinJSONArrayInsertSig) evalJSON(row chunk.Row) (res json.BinaryJSON
 		if err != nil {
 			return res, true, json.ErrInvalidJSONPath.GenWithStackByArgs(s)
 		}
-		if pathExpr.ContainsAnyAsterisk() {
+		if pathExpr.CouldMatchMultipleValues() {
 			return res, true, json.ErrInvalidJSONPathWildcard.GenWithStackByArgs(s)
 		}
 
@@ -1368,7 +1368,7 @@ func (b *builtinJSONKeys2ArgsSig) evalJSON(row chunk.Row) (res json.BinaryJSON,
 	if err != nil {
 		return res, true, err
 	}
-	if pathExpr.ContainsAnyAsterisk() {
+	if pathExpr.CouldMatchMultipleValues() {
 		return res, true, json.ErrInvalidJSONPathWildcard
 	}
 
@@ -1437,7 +1437,7 @@ func (b *builtinJSONLengthSig) evalInt(row chunk.Row) (res int64, isNull bool, e
 		if err != nil {
 			return res, true, err
 		}
-		if pathExpr.ContainsAnyAsterisk() {
+		if pathExpr.CouldMatchMultipleValues() {
 			return res, true, json.ErrInvalidJSONPathWildcard
 		}
 
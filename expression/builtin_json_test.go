@@ -117,6 +117,8 @@ func (s *testEvaluatorSuite) TestJSONExtract(c *C) {
 		{[]interface{}{nil, nil}, nil, true},
 		{[]interface{}{jstr, `$.a[0].aa[0].aaa`, `$.aaa`}, `[1, 2]`, true},
 		{[]interface{}{jstr, `$.a[0].aa[0].aaa`, `$InvalidPath`}, nil, false},
+		{[]interface{}{jstr, `$.a[last].aa[last].aaa`}, `1`, true},
+		{[]interface{}{`[1, 2, 3, 4]`, `$[last-2 to last]`}, `[2, 3, 4]`, true},
 	}
 	for _, t := range tbl {
 		args := types.MakeDatums(t.Input...)
@@ -158,6 +160,12 @@ func (s *testEvaluatorSuite) TestJSONSetInsertReplace(c *C) {
 		{funcs[ast.JSONSet], []interface{}{`{}`, `$.a`, 3, `$.b`, "3"}, `{"a": 3, "b": "3"}`, true, true},
 		{funcs[ast.JSONSet], []interface{}{`{}`, `$.a`, nil, `$.b`, "nil"}, `{"a": null, "b": "nil"}`, true, true},
 		{funcs[ast.JSONSet], []interface{}{`{}`, `$.a`, 3, `$.b`}, nil, false, false},
+		{funcs[ast.JSONSet], []interface{}{`[1, 2, 3]`, `$[last]`, 4}, `[1, 2, 4]`, true, true},
+		{funcs[ast.JSONSet], []interface{}{`{"a": 1, "b": {"a": 2}}`, `$**.a`, 0}, nil, true, false},
+		{funcs[ast.JSONSet], []interface{}{`[1, 2, 3]`, `$[*]`, 0}, nil, true, false},
+		{funcs[ast.JSONReplace], []interface{}{`{"a": 1}`, `$.*`, 0}, nil, true, false},
+		{funcs[ast.JSONReplace], []interface{}{`[1, 2, 3]`, `$[1 to last]`, 0}, nil, true, false},
+		{funcs[ast.JSONInsert], []interface{}{`{"a": 1}`, `$.*`, 0}, nil, true, false},
 		{funcs[ast.JSONSet], []interface{}{`{}`, `$InvalidPath`, 3}, nil, true, false},
 	}
 	var err error
@@ -323,9 +331,13 @@ func (s *testEvaluatorSuite) TestJSONRemove(c *C) {
 		Success  bool
 	}{
 		{[]interface{}{`{"a": [1, 2, {"aa": "xx"}]}`, "$"}, nil, false},
+
 		{[]interface{}{`{"a": [1, 2, {"aa": "xx"}]}`, "$.*"}, nil, false},
 		{[]interface{}{`{"a": [1, 2, {"aa": "xx"}]}`, "$[*]"}, nil, false},
 		{[]interface{}{`{"a": [1, 2, {"aa": "xx"}]}`, "$**.a"}, nil, false},
+		{[]interface{}{`{"a": [1, 2, {"aa": "xx"}]}`, "$.a[0 to 1]"}, nil, false},
+
+		{[]interface{}{`{"a": [1, 2, {"aa": "xx"}]}`, "$.a[last]"}, `{"a": [1, 2]}`, true},
 
 		{[]interface{}{nil, "$.a"}, nil, true},
 		{[]interface{}{`{"a": [1, 2, {"aa": "xx"}]}`, "$.a[2].aa"}, `{"a": [1, 2, {}]}`, true},
@@ -403,6 +415,7 @@ func (s *testEvaluatorSuite) TestJSONContains(c *C) {
 		{[]interface{}{`{"a": [1, 2, {"aa": "xx"}]}`, `1`, "$.*"}, nil, json.ErrInvalidJSONPathWildcard},
 		{[]interface{}{`{"a": [1, 2, {"aa": "xx"}]}`, `1`, "$[*]"}, nil, json.ErrInvalidJSONPathWildcard},
 		{[]interface{}{`{"a": [1, 2, {"aa": "xx"}]}`, `1`, "$**.a"}, nil, json.ErrInvalidJSONPathWildcard},
+		{[]interface{}{`{"a": [1, 2, {"aa": "xx"}]}`, `1`, "$.a[0 to 1]"}, nil, json.ErrInvalidJSONPathWildcard},
 		// Tests path expression does not identify a section of the target document
 		{[]interface{}{`{"a": [1, 2, {"aa": "xx"}]}`, `1`, "$.c"}, nil, nil},
 		{[]interface{}{`{"a": [1, 2, {"aa": "xx"}]}`, `1`, "$.a[3]"}, nil, nil},
@@ -548,6 +561,7 @@ func (s *testEvaluatorSuite) TestJSONLength(c *C) {
 		{[]interface{}{`{"a": [1, 2, {"aa": "xx"}]}`, "$.*"}, nil, false},
 		{[]interface{}{`{"a": [1, 2, {"aa": "xx"}]}`, "$[*]"}, nil, false},
 		{[]interface{}{`{"a": [1, 2, {"aa": "xx"}]}`, "$**.a"}, nil, false},
+		{[]interface{}{`{"a": [1, 2, {"aa": "xx"}]}`, "$.a[0 to 1]"}, nil, false},
 		// Tests path expression does not identify a section of the target document
 		{[]interface{}{`{"a": [1, 2, {"aa": "xx"}]}`, "$.c"}, nil, true},
 		{[]interface{}{`{"a": [1, 2, {"aa": "xx"}]}`, "$.a[3]"}, nil, true},
@@ -297,7 +297,7 @@ func (b *builtinJSONContainsSig) vecEvalInt(input *chunk.Chunk, result *chunk.Co
 			if err != nil {
 				return err
 			}
-			if pathExpr.ContainsAnyAsterisk() {
+			if pathExpr.CouldMatchMultipleValues() {
 				return json.ErrInvalidJSONPathWildcard
 			}
 
@@ -601,7 +601,7 @@ func (b *builtinJSONArrayInsertSig) vecEvalJSON(input *chunk.Chunk, result *chun
 			if err != nil {
 				return json.ErrInvalidJSONPath.GenWithStackByArgs(pathBufs[j].GetString(i))
 			}
-			if pathExpr.ContainsAnyAsterisk() {
+			if pathExpr.CouldMatchMultipleValues() {
 				return json.ErrInvalidJSONPathWildcard.GenWithStackByArgs(pathBufs[j].GetString(i))
 			}
 			if valueBufs[j].IsNull(i) {
@@ -657,7 +657,7 @@ func (b *builtinJSONKeys2ArgsSig) vecEvalJSON(input *chunk.Chunk, result *chunk.
 		if err != nil {
 			return err
 		}
-		if pathExpr.ContainsAnyAsterisk() {
+		if pathExpr.CouldMatchMultipleValues() {
 			return json.ErrInvalidJSONPathWildcard
 		}
 
@@ -726,7 +726,7 @@ func (b *builtinJSONLengthSig) vecEvalInt(input *chunk.Chunk, result *chunk.Colu
 			if err != nil {
 				return err
 			}
-			if pathExpr.ContainsAnyAsterisk() {
+			if pathExpr.CouldMatchMultipleValues() {
 				return json.ErrInvalidJSONPathWildcard
 			}
 
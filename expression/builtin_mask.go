@@ -0,0 +1,261 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// Data masking function names, they are not defined in the parser.
+const (
+	maskInner = "mask_inner"
+	maskPan   = "mask_pan"
+	maskEmail = "mask_email"
+)
+
+// defaultMaskChar is the character replacing the masked characters.
+const defaultMaskChar = "X"
+
+// panUnmaskedDigits is the number of the trailing digits kept by mask_pan.
+const panUnmaskedDigits = 4
+
+var (
+	_ functionClass = &maskInnerFunctionClass{}
+	_ functionClass = &maskPanFunctionClass{}
+	_ functionClass = &maskEmailFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinMaskInnerSig{}
+	_ builtinFunc = &builtinMaskPanSig{}
+	_ builtinFunc = &builtinMaskEmailSig{}
+)
+
+// maskUnits splits str into the units replaced by the mask character, the binary strings are
+// masked by bytes, the others by characters.
+func maskUnits(str string, collation string) []string {
+	units := make([]string, 0, len(str))
+	for len(str) > 0 {
+		size := 1
+		if collation != charset.CollationBin {
+			_, size = utf8.DecodeRuneInString(str)
+		}
+		units = append(units, str[:size])
+		str = str[size:]
+	}
+	return units
+}
+
+// maskInnerString replaces the units of str with maskChar, except the first margin1 ones and the
+// last margin2 ones. The string is returned unchanged if the margins cover it.
+func maskInnerString(str string, margin1, margin2 int64, maskChar string, collation string) string {
+	units := maskUnits(str, collation)
+	if margin1+margin2 >= int64(len(units)) {
+		return str
+	}
+	var sb strings.Builder
+	sb.Grow(len(str))
+	for i, unit := range units {
+		if int64(i) >= margin1 && int64(i) < int64(len(units))-margin2 {
+			unit = maskChar
+		}
+		sb.WriteString(unit)
+	}
+	return sb.String()
+}
+
+// maskPanString replaces the digits of the payment card number with 'X' except the last four
+// ones, the other characters like the separators are kept.
+func maskPanString(str string) string {
+	digits := 0
+	for i := 0; i < len(str); i++ {
+		if str[i] >= '0' && str[i] <= '9' {
+			digits++
+		}
+	}
+	if digits <= panUnmaskedDigits {
+		return str
+	}
+	masked := []byte(str)
+	for i := range masked {
+		if digits == panUnmaskedDigits {
+			break
+		}
+		if masked[i] >= '0' && masked[i] <= '9' {
+			masked[i] = defaultMaskChar[0]
+			digits--
+		}
+	}
+	return string(masked)
+}
+
+// maskEmailString replaces the local part of the email address with 'X' except its first unit,
+// and keeps the domain. The whole string is masked if it isn't an email address.
+func maskEmailString(str string, collation string) string {
+	at := strings.LastIndexByte(str, '@')
+	if at < 0 {
+		return maskInnerString(str, 0, 0, defaultMaskChar, collation)
+	}
+	return maskInnerString(str[:at], 1, 0, defaultMaskChar, collation) + str[at:]
+}
+
+// checkMaskArgs checks the margins and the mask character of mask_inner.
+func checkMaskArgs(margin1, margin2 int64, maskChar string, collation string) error {
+	if margin1 < 0 || margin2 < 0 {
+		return errIncorrectArgs.GenWithStack("The margins of %s can't be negative", maskInner)
+	}
+	if len(maskUnits(maskChar, collation)) != 1 {
+		return errIncorrectArgs.GenWithStack("The mask character of %s must be a single character", maskInner)
+	}
+	return nil
+}
+
+type maskInnerFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *maskInnerFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETInt, types.ETInt}
+	if len(args) == 4 {
+		argTps = append(argTps, types.ETString)
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	// Every masked unit is replaced by a single unit, so the length is kept.
+	bf.tp.Flen = args[0].GetType().Flen
+	sig := &builtinMaskInnerSig{bf}
+	return sig, nil
+}
+
+type builtinMaskInnerSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinMaskInnerSig) Clone() builtinFunc {
+	newSig := &builtinMaskInnerSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals MASK_INNER(str, margin1, margin2[, mask_char]), it masks the interior of str
+// and keeps margin1 characters at the start and margin2 characters at the end.
+func (b *builtinMaskInnerSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	margin1, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	margin2, isNull, err := b.args[2].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	maskChar := defaultMaskChar
+	if len(b.args) == 4 {
+		maskChar, isNull, err = b.args[3].EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return "", true, err
+		}
+	}
+	if err = checkMaskArgs(margin1, margin2, maskChar, b.collation); err != nil {
+		return "", true, err
+	}
+	return maskInnerString(str, margin1, margin2, maskChar, b.collation), false, nil
+}
+
+type maskPanFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *maskPanFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = args[0].GetType().Flen
+	sig := &builtinMaskPanSig{bf}
+	return sig, nil
+}
+
+type builtinMaskPanSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinMaskPanSig) Clone() builtinFunc {
+	newSig := &builtinMaskPanSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals MASK_PAN(str), it masks the payment card number except its last four digits.
+func (b *builtinMaskPanSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return maskPanString(str), false, nil
+}
+
+type maskEmailFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *maskEmailFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = args[0].GetType().Flen
+	sig := &builtinMaskEmailSig{bf}
+	return sig, nil
+}
+
+type builtinMaskEmailSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinMaskEmailSig) Clone() builtinFunc {
+	newSig := &builtinMaskEmailSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals MASK_EMAIL(str), it masks the local part of the email address except its
+// first character.
+func (b *builtinMaskEmailSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return maskEmailString(str, b.collation), false, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+func (s *testEvaluatorSuite) TestMaskInner(c *C) {
+	tbl := []struct {
+		args     []interface{}
+		expected interface{}
+		err      string
+	}{
+		{[]interface{}{"abcdef", 1, 2}, "aXXXef", ""},
+		{[]interface{}{"abcdef", 0, 0}, "XXXXXX", ""},
+		{[]interface{}{"abcdef", 3, 3}, "abcdef", ""},
+		{[]interface{}{"abcdef", 10, 0}, "abcdef", ""},
+		{[]interface{}{"数据库管理", 1, 1, "*"}, "数***理", ""},
+		{[]interface{}{"abcdef", 1, 1, "某"}, "a某某某某f", ""},
+		{[]interface{}{"", 0, 0}, "", ""},
+		{[]interface{}{nil, 1, 1}, nil, ""},
+		{[]interface{}{"abc", nil, 1}, nil, ""},
+		{[]interface{}{"abc", 1, 1, nil}, nil, ""},
+		{[]interface{}{"abc", -1, 1}, nil, ".*margins of mask_inner can't be negative"},
+		{[]interface{}{"abc", 0, 0, "**"}, nil, ".*must be a single character"},
+		{[]interface{}{"abc", 0, 0, ""}, nil, ".*must be a single character"},
+	}
+	for _, t := range tbl {
+		f, err := newFunctionForTest(s.ctx, maskInner, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		if t.err != "" {
+			c.Assert(err, ErrorMatches, t.err)
+			continue
+		}
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.expected), Commentf("mask_inner(%v)", t.args))
+	}
+}
+
+func (s *testEvaluatorSuite) TestMaskPanAndEmail(c *C) {
+	tbl := []struct {
+		arg   interface{}
+		pan   interface{}
+		email interface{}
+	}{
+		{"4111111111111111", "XXXXXXXXXXXX1111", "XXXXXXXXXXXXXXXX"},
+		{"4111-1111-1111-1234", "XXXX-XXXX-XXXX-1234", "XXXXXXXXXXXXXXXXXXX"},
+		{"alice@example.com", "alice@example.com", "aXXXX@example.com"},
+		{"a.b@c@example.com", "a.b@c@example.com", "aXXXX@example.com"},
+		{"张三@example.com", "张三@example.com", "张X@example.com"},
+		{"@example.com", "@example.com", "@example.com"},
+		{"1234", "1234", "XXXX"},
+		{"", "", ""},
+		{nil, nil, nil},
+	}
+	for _, t := range tbl {
+		args := s.primitiveValsToConstants([]interface{}{t.arg})
+		f, err := newFunctionForTest(s.ctx, maskPan, args...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.pan), Commentf("mask_pan(%v)", t.arg))
+
+		f, err = newFunctionForTest(s.ctx, maskEmail, args...)
+		c.Assert(err, IsNil)
+		d, err = f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.email), Commentf("mask_email(%v)", t.arg))
+	}
+}
+
+func (s *testEvaluatorSuite) TestMaskUnits(c *C) {
+	// The binary strings are masked by bytes.
+	c.Assert(maskInnerString("数据", 0, 0, "X", "binary"), Equals, "XXXXXX")
+	c.Assert(maskInnerString("数据", 0, 0, "X", "utf8mb4_bin"), Equals, "XX")
+	c.Assert(maskEmailString("数@a", "binary"), Equals, "\xe6XX@a")
+}
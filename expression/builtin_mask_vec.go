@@ -0,0 +1,129 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (b *builtinMaskInnerSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinMaskInnerSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	strBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(strBuf)
+	if err := b.args[0].VecEvalString(b.ctx, input, strBuf); err != nil {
+		return err
+	}
+	margin1Buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(margin1Buf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, margin1Buf); err != nil {
+		return err
+	}
+	margin2Buf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(margin2Buf)
+	if err := b.args[2].VecEvalInt(b.ctx, input, margin2Buf); err != nil {
+		return err
+	}
+	var maskCharBuf *chunk.Column
+	if len(b.args) == 4 {
+		maskCharBuf, err = b.bufAllocator.get(types.ETString, n)
+		if err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(maskCharBuf)
+		if err := b.args[3].VecEvalString(b.ctx, input, maskCharBuf); err != nil {
+			return err
+		}
+	}
+	margin1s, margin2s := margin1Buf.Int64s(), margin2Buf.Int64s()
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if strBuf.IsNull(i) || margin1Buf.IsNull(i) || margin2Buf.IsNull(i) || (maskCharBuf != nil && maskCharBuf.IsNull(i)) {
+			result.AppendNull()
+			continue
+		}
+		maskChar := defaultMaskChar
+		if maskCharBuf != nil {
+			maskChar = maskCharBuf.GetString(i)
+		}
+		if err := checkMaskArgs(margin1s[i], margin2s[i], maskChar, b.collation); err != nil {
+			return err
+		}
+		result.AppendString(maskInnerString(strBuf.GetString(i), margin1s[i], margin2s[i], maskChar, b.collation))
+	}
+	return nil
+}
+
+func (b *builtinMaskPanSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinMaskPanSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(maskPanString(buf.GetString(i)))
+	}
+	return nil
+}
+
+func (b *builtinMaskEmailSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinMaskEmailSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(maskEmailString(buf.GetString(i), b.collation))
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/types"
+)
+
+var vecBuiltinMaskCases = map[string][]vecExprBenchCase{
+	maskInner: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETInt}, geners: []dataGenerator{
+			nil, newRangeInt64Gener(0, 10), newRangeInt64Gener(0, 10),
+		}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETInt, types.ETString}, geners: []dataGenerator{
+			newSelectStringGener([]string{"4111111111111111", "数据库管理", "ab", ""}),
+			newRangeInt64Gener(0, 3), newRangeInt64Gener(0, 3),
+			newSelectStringGener([]string{"*", "#", "某"}),
+		}},
+	},
+	maskPan: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}, geners: []dataGenerator{
+			newSelectStringGener([]string{"4111111111111111", "4111-1111-1111-1111", "1234", ""}),
+		}},
+	},
+	maskEmail: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}, geners: []dataGenerator{
+			newSelectStringGener([]string{"alice@example.com", "a@b", "@example.com", "alice", ""}),
+		}},
+	},
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinMaskEvalOneVec(c *C) {
+	testVectorizedEvalOneVec(c, vecBuiltinMaskCases)
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinMaskFunc(c *C) {
+	testVectorizedBuiltinFunc(c, vecBuiltinMaskCases)
+}
+
+func BenchmarkVectorizedBuiltinMaskFunc(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltinMaskCases)
+}
@@ -15,6 +15,7 @@ package expression
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"math"
 	"net"
@@ -22,6 +23,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
@@ -31,6 +33,9 @@ import (
 	"github.com/pingcap/tipb/go-tipb"
 )
 
+// isUUID is the name of IS_UUID, which is not defined in the parser.
+const isUUID = "is_uuid"
+
 var (
 	_ functionClass = &sleepFunctionClass{}
 	_ functionClass = &lockFunctionClass{}
@@ -52,6 +57,9 @@ var (
 	_ functionClass = &releaseAllLocksFunctionClass{}
 	_ functionClass = &uuidFunctionClass{}
 	_ functionClass = &uuidShortFunctionClass{}
+	_ functionClass = &uuidToBinFunctionClass{}
+	_ functionClass = &binToUUIDFunctionClass{}
+	_ functionClass = &isUUIDFunctionClass{}
 	_ functionClass = &vitessHashFunctionClass{}
 )
 
@@ -75,6 +83,9 @@ var (
 	_ builtinFunc = &builtinIsIPv4MappedSig{}
 	_ builtinFunc = &builtinIsIPv6Sig{}
 	_ builtinFunc = &builtinUUIDSig{}
+	_ builtinFunc = &builtinUUIDToBinSig{}
+	_ builtinFunc = &builtinBinToUUIDSig{}
+	_ builtinFunc = &builtinIsUUIDSig{}
 	_ builtinFunc = &builtinVitessHashSig{}
 
 	_ builtinFunc = &builtinNameConstIntSig{}
@@ -1050,6 +1061,226 @@ func (c *uuidShortFunctionClass) getFunction(ctx sessionctx.Context, args []Expr
 	return nil, errFunctionNotExists.GenWithStackByArgs("FUNCTION", "UUID_SHORT")
 }
 
+// parseUUID parses the string UUID in the formats accepted by MySQL: 32 hexadecimal digits,
+// which are optionally grouped by dashes as 8-4-4-4-12 and then optionally enclosed in braces.
+func parseUUID(str string) ([]byte, bool) {
+	if len(str) == 38 && str[0] == '{' && str[37] == '}' {
+		str = str[1:37]
+	} else if len(str) != 36 && len(str) != 32 {
+		return nil, false
+	}
+	if len(str) == 36 {
+		if str[8] != '-' || str[13] != '-' || str[18] != '-' || str[23] != '-' {
+			return nil, false
+		}
+		str = str[:8] + str[9:13] + str[14:18] + str[19:23] + str[24:]
+	}
+	bin, err := hex.DecodeString(str)
+	if err != nil {
+		return nil, false
+	}
+	return bin, true
+}
+
+// swapUUIDTime swaps the time-low and time-high parts of a binary UUID, which puts the rapidly
+// varying part at the end, so the UUIDs generated by UUID() are stored in increasing order.
+func swapUUIDTime(bin []byte) []byte {
+	swapped := make([]byte, 0, 16)
+	swapped = append(swapped, bin[6:8]...)
+	swapped = append(swapped, bin[4:6]...)
+	swapped = append(swapped, bin[0:4]...)
+	return append(swapped, bin[8:]...)
+}
+
+// unswapUUIDTime reverts swapUUIDTime.
+func unswapUUIDTime(bin []byte) []byte {
+	unswapped := make([]byte, 0, 16)
+	unswapped = append(unswapped, bin[4:8]...)
+	unswapped = append(unswapped, bin[2:4]...)
+	unswapped = append(unswapped, bin[0:2]...)
+	return append(unswapped, bin[8:]...)
+}
+
+// uuidToBin converts the string UUID to its binary form, the time parts are swapped if swap is true.
+func uuidToBin(str string, swap bool) ([]byte, error) {
+	bin, ok := parseUUID(str)
+	if !ok {
+		return nil, types.ErrWrongValueForType.GenWithStackByArgs("string", str, ast.UUIDToBin)
+	}
+	if swap {
+		bin = swapUUIDTime(bin)
+	}
+	return bin, nil
+}
+
+// binToUUID converts the binary UUID to its string form, the time parts are swapped back if swap is true.
+func binToUUID(bin string, swap bool) (string, error) {
+	if len(bin) != 16 {
+		return "", types.ErrWrongValueForType.GenWithStackByArgs("string", bin, ast.BinToUUID)
+	}
+	b := []byte(bin)
+	if swap {
+		b = unswapUUIDTime(b)
+	}
+	id, err := uuid.FromBytes(b)
+	if err != nil {
+		return "", err
+	}
+	return id.String(), nil
+}
+
+// evalUUIDSwapFlag evaluates the optional swap flag of UUID_TO_BIN and BIN_TO_UUID, NULL means no swap.
+func evalUUIDSwapFlag(ctx sessionctx.Context, args []Expression, row chunk.Row) (bool, error) {
+	if len(args) < 2 {
+		return false, nil
+	}
+	flag, isNull, err := args[1].EvalInt(ctx, row)
+	if isNull || err != nil {
+		return false, err
+	}
+	return flag != 0, nil
+}
+
+type uuidToBinFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *uuidToBinFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString}
+	if len(args) == 2 {
+		argTps = append(argTps, types.ETInt)
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = 16
+	types.SetBinChsClnFlag(bf.tp)
+	sig := &builtinUUIDToBinSig{bf}
+	return sig, nil
+}
+
+type builtinUUIDToBinSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUUIDToBinSig) Clone() builtinFunc {
+	newSig := &builtinUUIDToBinSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals UUID_TO_BIN(string_uuid[, swap_flag]).
+// See https://dev.mysql.com/doc/refman/8.0/en/miscellaneous-functions.html#function_uuid-to-bin
+func (b *builtinUUIDToBinSig) evalString(row chunk.Row) (string, bool, error) {
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	swap, err := evalUUIDSwapFlag(b.ctx, b.args, row)
+	if err != nil {
+		return "", true, err
+	}
+	bin, err := uuidToBin(val, swap)
+	if err != nil {
+		return "", true, err
+	}
+	return string(bin), false, nil
+}
+
+type binToUUIDFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *binToUUIDFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString}
+	if len(args) == 2 {
+		argTps = append(argTps, types.ETInt)
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Charset, bf.tp.Collate = ctx.GetSessionVars().GetCharsetInfo()
+	bf.tp.Flen = 36
+	sig := &builtinBinToUUIDSig{bf}
+	return sig, nil
+}
+
+type builtinBinToUUIDSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinBinToUUIDSig) Clone() builtinFunc {
+	newSig := &builtinBinToUUIDSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals BIN_TO_UUID(binary_uuid[, swap_flag]).
+// See https://dev.mysql.com/doc/refman/8.0/en/miscellaneous-functions.html#function_bin-to-uuid
+func (b *builtinBinToUUIDSig) evalString(row chunk.Row) (string, bool, error) {
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	swap, err := evalUUIDSwapFlag(b.ctx, b.args, row)
+	if err != nil {
+		return "", true, err
+	}
+	str, err := binToUUID(val, swap)
+	if err != nil {
+		return "", true, err
+	}
+	return str, false, nil
+}
+
+type isUUIDFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *isUUIDFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = 1
+	sig := &builtinIsUUIDSig{bf}
+	return sig, nil
+}
+
+type builtinIsUUIDSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIsUUIDSig) Clone() builtinFunc {
+	newSig := &builtinIsUUIDSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals IS_UUID(string_uuid).
+// See https://dev.mysql.com/doc/refman/8.0/en/miscellaneous-functions.html#function_is-uuid
+func (b *builtinIsUUIDSig) evalInt(row chunk.Row) (int64, bool, error) {
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	if _, ok := parseUUID(val); ok {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
+
 type vitessHashFunctionClass struct {
 	baseFunctionClass
 }
@@ -115,6 +115,78 @@ func (s *testEvaluatorSuite) TestUUID(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (s *testEvaluatorSuite) TestUUIDToBinAndBinToUUID(c *C) {
+	tests := []struct {
+		uuid    interface{}
+		swap    interface{}
+		bin     interface{}
+		revUUID interface{}
+	}{
+		{"6ccd780c-baba-1026-9564-5b8c656024db", nil, "\x6c\xcd\x78\x0c\xba\xba\x10\x26\x95\x64\x5b\x8c\x65\x60\x24\xdb", "6ccd780c-baba-1026-9564-5b8c656024db"},
+		{"6ccd780c-baba-1026-9564-5b8c656024db", 0, "\x6c\xcd\x78\x0c\xba\xba\x10\x26\x95\x64\x5b\x8c\x65\x60\x24\xdb", "6ccd780c-baba-1026-9564-5b8c656024db"},
+		{"6ccd780c-baba-1026-9564-5b8c656024db", 1, "\x10\x26\xba\xba\x6c\xcd\x78\x0c\x95\x64\x5b\x8c\x65\x60\x24\xdb", "6ccd780c-baba-1026-9564-5b8c656024db"},
+		{"{6CCD780C-BABA-1026-9564-5B8C656024DB}", 1, "\x10\x26\xba\xba\x6c\xcd\x78\x0c\x95\x64\x5b\x8c\x65\x60\x24\xdb", "6ccd780c-baba-1026-9564-5b8c656024db"},
+		{"6ccd780cbaba102695645b8c656024db", nil, "\x6c\xcd\x78\x0c\xba\xba\x10\x26\x95\x64\x5b\x8c\x65\x60\x24\xdb", "6ccd780c-baba-1026-9564-5b8c656024db"},
+		{nil, 1, nil, nil},
+	}
+	for _, t := range tests {
+		args := []interface{}{t.uuid}
+		if t.swap != nil {
+			args = append(args, t.swap)
+		}
+		f, err := newFunctionForTest(s.ctx, ast.UUIDToBin, s.primitiveValsToConstants(args)...)
+		c.Assert(err, IsNil)
+		bin, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(bin, testutil.DatumEquals, types.NewDatum(t.bin))
+
+		args[0] = bin.GetValue()
+		f, err = newFunctionForTest(s.ctx, ast.BinToUUID, s.primitiveValsToConstants(args)...)
+		c.Assert(err, IsNil)
+		uuid, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(uuid, testutil.DatumEquals, types.NewDatum(t.revUUID))
+	}
+
+	for _, str := range []string{"", "6ccd780c-baba-1026-9564-5b8c656024d", "6ccd780c-baba-1026-9564-5b8c656024dx", "6ccd780cbaba-1026-9564-5b8c656024db1", "{6ccd780cbaba102695645b8c656024db}"} {
+		f, err := newFunctionForTest(s.ctx, ast.UUIDToBin, s.primitiveValsToConstants([]interface{}{str})...)
+		c.Assert(err, IsNil)
+		_, err = f.Eval(chunk.Row{})
+		c.Assert(types.ErrWrongValueForType.Equal(err), IsTrue, Commentf("%s", str))
+	}
+	for _, str := range []string{"", "\x01", "0123456789abcdef0"} {
+		f, err := newFunctionForTest(s.ctx, ast.BinToUUID, s.primitiveValsToConstants([]interface{}{str})...)
+		c.Assert(err, IsNil)
+		_, err = f.Eval(chunk.Row{})
+		c.Assert(types.ErrWrongValueForType.Equal(err), IsTrue, Commentf("%s", str))
+	}
+}
+
+func (s *testEvaluatorSuite) TestIsUUID(c *C) {
+	tests := []struct {
+		arg    interface{}
+		expect interface{}
+	}{
+		{"6ccd780c-baba-1026-9564-5b8c656024db", 1},
+		{"6CCD780C-BABA-1026-9564-5B8C656024DB", 1},
+		{"6ccd780cbaba102695645b8c656024db", 1},
+		{"{6ccd780c-baba-1026-9564-5b8c656024db}", 1},
+		{"{6ccd780cbaba102695645b8c656024db}", 0},
+		{"6ccd780c-baba-1026-95645b8c-656024db", 0},
+		{"6ccd780c-baba-1026-9564-5b8c656024dg", 0},
+		{"urn:uuid:6ccd780c-baba-1026-9564-5b8c656024db", 0},
+		{"", 0},
+		{nil, nil},
+	}
+	for _, t := range tests {
+		f, err := newFunctionForTest(s.ctx, isUUID, s.primitiveValsToConstants([]interface{}{t.arg})...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.expect))
+	}
+}
+
 func (s *testEvaluatorSuite) TestAnyValue(c *C) {
 	tbl := []struct {
 		arg interface{}
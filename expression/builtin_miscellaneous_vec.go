@@ -653,3 +653,124 @@ func (b *builtinVitessHashSig) vecEvalInt(input *chunk.Chunk, result *chunk.Colu
 
 	return nil
 }
+
+// vecEvalUUIDSwapFlags evaluates the optional swap flags of UUID_TO_BIN and BIN_TO_UUID,
+// it returns nil if the flag is omitted. The caller should put the returned buffer back.
+func vecEvalUUIDSwapFlags(b *baseBuiltinFunc, input *chunk.Chunk) (*chunk.Column, error) {
+	if len(b.args) < 2 {
+		return nil, nil
+	}
+	buf, err := b.bufAllocator.get(types.ETInt, input.NumRows())
+	if err != nil {
+		return nil, err
+	}
+	if err := b.args[1].VecEvalInt(b.ctx, input, buf); err != nil {
+		b.bufAllocator.put(buf)
+		return nil, err
+	}
+	return buf, nil
+}
+
+func (b *builtinUUIDToBinSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUUIDToBinSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	flags, err := vecEvalUUIDSwapFlags(&b.baseBuiltinFunc, input)
+	if err != nil {
+		return err
+	}
+	if flags != nil {
+		defer b.bufAllocator.put(flags)
+	}
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		swap := flags != nil && !flags.IsNull(i) && flags.GetInt64(i) != 0
+		bin, err := uuidToBin(buf.GetString(i), swap)
+		if err != nil {
+			return err
+		}
+		result.AppendBytes(bin)
+	}
+	return nil
+}
+
+func (b *builtinBinToUUIDSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinBinToUUIDSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	flags, err := vecEvalUUIDSwapFlags(&b.baseBuiltinFunc, input)
+	if err != nil {
+		return err
+	}
+	if flags != nil {
+		defer b.bufAllocator.put(flags)
+	}
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		swap := flags != nil && !flags.IsNull(i) && flags.GetInt64(i) != 0
+		str, err := binToUUID(buf.GetString(i), swap)
+		if err != nil {
+			return err
+		}
+		result.AppendString(str)
+	}
+	return nil
+}
+
+func (b *builtinIsUUIDSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinIsUUIDSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		if _, ok := parseUUID(buf.GetString(i)); ok {
+			i64s[i] = 1
+		} else {
+			i64s[i] = 0
+		}
+	}
+	return nil
+}
@@ -38,6 +38,23 @@ var vecBuiltinMiscellaneousCases = map[string][]vecExprBenchCase{
 		}},
 	},
 	ast.UUID: {},
+	ast.UUIDToBin: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}, geners: []dataGenerator{newRandHexStrGener(32, 33)}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt}, geners: []dataGenerator{newRandHexStrGener(32, 33), newRangeInt64Gener(0, 2)}},
+	},
+	ast.BinToUUID: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}, geners: []dataGenerator{newRandLenStrGener(16, 17)}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt}, geners: []dataGenerator{newRandLenStrGener(16, 17), newRangeInt64Gener(0, 2)}},
+	},
+	isUUID: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString}, geners: []dataGenerator{newSelectStringGener([]string{
+			"6ccd780c-baba-1026-9564-5b8c656024db",
+			"{6CCD780C-BABA-1026-9564-5B8C656024DB}",
+			"6ccd780cbaba102695645b8c656024db",
+			"6ccd780c-baba-1026-9564-5b8c656024d",
+		})}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString}},
+	},
 	ast.Inet6Ntoa: {
 		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}, geners: []dataGenerator{
 			newSelectStringGener(
@@ -0,0 +1,341 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/spatial"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// Spatial function names, they are not defined in the parser.
+const (
+	stGeomFromText     = "st_geomfromtext"
+	stGeometryFromText = "st_geometryfromtext"
+	stAsText           = "st_astext"
+	stAsWKT            = "st_aswkt"
+	stX                = "st_x"
+	stY                = "st_y"
+	stDistance         = "st_distance"
+	stContains         = "st_contains"
+)
+
+var (
+	_ functionClass = &stGeomFromTextFunctionClass{}
+	_ functionClass = &stAsTextFunctionClass{}
+	_ functionClass = &stCoordinateFunctionClass{}
+	_ functionClass = &stDistanceFunctionClass{}
+	_ functionClass = &stContainsFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinSTGeomFromTextSig{}
+	_ builtinFunc = &builtinSTAsTextSig{}
+	_ builtinFunc = &builtinSTXSig{}
+	_ builtinFunc = &builtinSTYSig{}
+	_ builtinFunc = &builtinSTDistanceSig{}
+	_ builtinFunc = &builtinSTContainsSig{}
+)
+
+// decodeGeometry decodes the geometry argument of the spatial function funcName.
+func decodeGeometry(funcName string, data string) (spatial.Geometry, error) {
+	g, err := spatial.Decode([]byte(data))
+	if err != nil {
+		return g, spatial.ErrGISInvalidData.GenWithStackByArgs(funcName)
+	}
+	return g, nil
+}
+
+// setGeometryFieldType sets the field type for the functions returning a geometry value.
+func setGeometryFieldType(tp *types.FieldType) {
+	tp.Tp = mysql.TypeGeometry
+	tp.Flen = mysql.MaxBlobWidth
+	types.SetBinChsClnFlag(tp)
+}
+
+type stGeomFromTextFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *stGeomFromTextFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString}
+	if len(args) == 2 {
+		argTps = append(argTps, types.ETInt)
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	setGeometryFieldType(bf.tp)
+	sig := &builtinSTGeomFromTextSig{bf}
+	return sig, nil
+}
+
+type builtinSTGeomFromTextSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSTGeomFromTextSig) Clone() builtinFunc {
+	newSig := &builtinSTGeomFromTextSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinSTGeomFromTextSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/gis-wkt-functions.html#function_st-geomfromtext
+func (b *builtinSTGeomFromTextSig) evalString(row chunk.Row) (string, bool, error) {
+	wkt, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	var srid int64
+	if len(b.args) == 2 {
+		srid, isNull, err = b.args[1].EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return "", true, err
+		}
+	}
+	return geomFromText(wkt, srid)
+}
+
+func geomFromText(wkt string, srid int64) (string, bool, error) {
+	if srid < 0 || srid > int64(^uint32(0)) {
+		return "", true, spatial.ErrGISInvalidData.GenWithStackByArgs(stGeomFromText)
+	}
+	g, err := spatial.ParseWKT(wkt, uint32(srid))
+	if err != nil {
+		return "", true, spatial.ErrGISInvalidData.GenWithStackByArgs(stGeomFromText)
+	}
+	return string(g.Encode()), false, nil
+}
+
+type stAsTextFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *stAsTextFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Charset, bf.tp.Collate = ctx.GetSessionVars().GetCharsetInfo()
+	bf.tp.Flen = mysql.MaxBlobWidth
+	sig := &builtinSTAsTextSig{bf}
+	return sig, nil
+}
+
+type builtinSTAsTextSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSTAsTextSig) Clone() builtinFunc {
+	newSig := &builtinSTAsTextSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinSTAsTextSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/gis-format-conversion-functions.html#function_st-astext
+func (b *builtinSTAsTextSig) evalString(row chunk.Row) (string, bool, error) {
+	data, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	g, err := decodeGeometry(stAsText, data)
+	if err != nil {
+		return "", true, err
+	}
+	return g.WKT(), false, nil
+}
+
+type stCoordinateFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *stCoordinateFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETReal, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	if c.funcName == stX {
+		return &builtinSTXSig{bf}, nil
+	}
+	return &builtinSTYSig{bf}, nil
+}
+
+// evalPoint evaluates the point argument of ST_X and ST_Y.
+func evalPoint(b *baseBuiltinFunc, funcName string, row chunk.Row) (spatial.Point, bool, error) {
+	data, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return spatial.Point{}, true, err
+	}
+	return decodePoint(funcName, data)
+}
+
+func decodePoint(funcName string, data string) (spatial.Point, bool, error) {
+	g, err := decodeGeometry(funcName, data)
+	if err != nil {
+		return spatial.Point{}, true, err
+	}
+	if g.Type != spatial.TypePoint {
+		return spatial.Point{}, true, spatial.ErrGISUnsupportedArgument.GenWithStackByArgs(funcName)
+	}
+	return g.Points[0], false, nil
+}
+
+type builtinSTXSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSTXSig) Clone() builtinFunc {
+	newSig := &builtinSTXSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinSTXSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/gis-point-property-functions.html#function_st-x
+func (b *builtinSTXSig) evalReal(row chunk.Row) (float64, bool, error) {
+	p, isNull, err := evalPoint(&b.baseBuiltinFunc, stX, row)
+	return p.X, isNull, err
+}
+
+type builtinSTYSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSTYSig) Clone() builtinFunc {
+	newSig := &builtinSTYSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinSTYSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/gis-point-property-functions.html#function_st-y
+func (b *builtinSTYSig) evalReal(row chunk.Row) (float64, bool, error) {
+	p, isNull, err := evalPoint(&b.baseBuiltinFunc, stY, row)
+	return p.Y, isNull, err
+}
+
+// evalGeometryPair evaluates the two geometry arguments of a binary spatial function.
+func evalGeometryPair(b *baseBuiltinFunc, funcName string, row chunk.Row) (g1, g2 spatial.Geometry, isNull bool, err error) {
+	data1, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return g1, g2, true, err
+	}
+	data2, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return g1, g2, true, err
+	}
+	if g1, err = decodeGeometry(funcName, data1); err != nil {
+		return g1, g2, true, err
+	}
+	g2, err = decodeGeometry(funcName, data2)
+	return g1, g2, err != nil, err
+}
+
+type stDistanceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *stDistanceFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETReal, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinSTDistanceSig{bf}
+	return sig, nil
+}
+
+type builtinSTDistanceSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSTDistanceSig) Clone() builtinFunc {
+	newSig := &builtinSTDistanceSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinSTDistanceSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/spatial-relation-functions-object-shapes.html#function_st-distance
+func (b *builtinSTDistanceSig) evalReal(row chunk.Row) (float64, bool, error) {
+	g1, g2, isNull, err := evalGeometryPair(&b.baseBuiltinFunc, stDistance, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	dist, err := spatial.Distance(g1, g2)
+	if err != nil {
+		return 0, true, err
+	}
+	return dist, false, nil
+}
+
+type stContainsFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *stContainsFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = 1
+	sig := &builtinSTContainsSig{bf}
+	return sig, nil
+}
+
+type builtinSTContainsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSTContainsSig) Clone() builtinFunc {
+	newSig := &builtinSTContainsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinSTContainsSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/spatial-relation-functions-object-shapes.html#function_st-contains
+func (b *builtinSTContainsSig) evalInt(row chunk.Row) (int64, bool, error) {
+	g1, g2, isNull, err := evalGeometryPair(&b.baseBuiltinFunc, stContains, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	contains, err := spatial.Contains(g1, g2)
+	if err != nil {
+		return 0, true, err
+	}
+	if contains {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/spatial"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+func (s *testEvaluatorSuite) geometryConstant(c *C, wkt string, srid uint32) Expression {
+	g, err := spatial.ParseWKT(wkt, srid)
+	c.Assert(err, IsNil)
+	return &Constant{Value: types.NewBytesDatum(g.Encode()), RetType: types.NewFieldType(mysql.TypeBlob)}
+}
+
+func (s *testEvaluatorSuite) TestSTGeomFromTextAndAsText(c *C) {
+	tbl := []struct {
+		args     []interface{}
+		expected interface{}
+		err      bool
+	}{
+		{[]interface{}{"POINT(1 2)"}, "POINT(1 2)", false},
+		{[]interface{}{"linestring(0 0, 1.5 1)", 4326}, "LINESTRING(0 0,1.5 1)", false},
+		{[]interface{}{"POLYGON((0 0,1 0,1 1,0 0))"}, "POLYGON((0 0,1 0,1 1,0 0))", false},
+		{[]interface{}{nil}, nil, false},
+		{[]interface{}{"POINT(1 2)", nil}, nil, false},
+		{[]interface{}{"POINT(1)"}, nil, true},
+		{[]interface{}{"POINT(1 2)", -1}, nil, true},
+	}
+	for _, t := range tbl {
+		geom, err := funcs[stGeomFromText].getFunction(s.ctx, s.primitiveValsToConstants(t.args))
+		c.Assert(err, IsNil)
+		c.Assert(geom.getRetTp().Tp, Equals, mysql.TypeGeometry)
+		f, err := newFunctionForTest(s.ctx, stAsText, &ScalarFunction{FuncName: model.NewCIStr(stGeomFromText), RetType: geom.getRetTp(), Function: geom})
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		if t.err {
+			c.Assert(spatial.ErrGISInvalidData.Equal(err), IsTrue)
+			continue
+		}
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.expected))
+	}
+
+	f, err := newFunctionForTest(s.ctx, stAsText, s.primitiveValsToConstants([]interface{}{"not a geometry"})...)
+	c.Assert(err, IsNil)
+	_, err = f.Eval(chunk.Row{})
+	c.Assert(spatial.ErrGISInvalidData.Equal(err), IsTrue)
+}
+
+func (s *testEvaluatorSuite) TestSTXY(c *C) {
+	for _, name := range []string{stX, stY} {
+		f, err := newFunctionForTest(s.ctx, name, s.geometryConstant(c, "POINT(1.5 -2)", 0))
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		if name == stX {
+			c.Assert(d.GetFloat64(), Equals, 1.5)
+		} else {
+			c.Assert(d.GetFloat64(), Equals, float64(-2))
+		}
+
+		f, err = newFunctionForTest(s.ctx, name, s.geometryConstant(c, "LINESTRING(0 0,1 1)", 0))
+		c.Assert(err, IsNil)
+		_, err = f.Eval(chunk.Row{})
+		c.Assert(spatial.ErrGISUnsupportedArgument.Equal(err), IsTrue)
+	}
+}
+
+func (s *testEvaluatorSuite) TestSTDistanceAndContains(c *C) {
+	square := "POLYGON((0 0,10 0,10 10,0 10,0 0))"
+	tbl := []struct {
+		g1, g2   string
+		distance float64
+		contains int64
+	}{
+		{"POINT(0 0)", "POINT(3 4)", 5, 0},
+		{square, "POINT(5 5)", 0, 1},
+		{square, "POINT(13 14)", 5, 0},
+		{square, "LINESTRING(1 1,9 9)", 0, 1},
+		{"LINESTRING(0 0,10 0)", "POINT(5 3)", 3, 0},
+	}
+	for _, t := range tbl {
+		f, err := newFunctionForTest(s.ctx, stDistance, s.geometryConstant(c, t.g1, 0), s.geometryConstant(c, t.g2, 0))
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.GetFloat64(), Equals, t.distance)
+
+		f, err = newFunctionForTest(s.ctx, stContains, s.geometryConstant(c, t.g1, 0), s.geometryConstant(c, t.g2, 0))
+		c.Assert(err, IsNil)
+		d, err = f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.GetInt64(), Equals, t.contains)
+	}
+
+	for _, name := range []string{stDistance, stContains} {
+		f, err := newFunctionForTest(s.ctx, name, s.geometryConstant(c, "POINT(0 0)", 0), s.geometryConstant(c, "POINT(0 0)", 4326))
+		c.Assert(err, IsNil)
+		_, err = f.Eval(chunk.Row{})
+		c.Assert(spatial.ErrGISDifferentSRIDs.Equal(err), IsTrue)
+
+		f, err = newFunctionForTest(s.ctx, name, s.geometryConstant(c, "POINT(0 0)", 0), &Constant{Value: types.NewDatum(nil), RetType: types.NewFieldType(mysql.TypeBlob)})
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.IsNull(), IsTrue)
+	}
+}
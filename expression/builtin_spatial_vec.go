@@ -0,0 +1,210 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/spatial"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (b *builtinSTGeomFromTextSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSTGeomFromTextSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	var sridBuf *chunk.Column
+	if len(b.args) == 2 {
+		if sridBuf, err = b.bufAllocator.get(types.ETInt, n); err != nil {
+			return err
+		}
+		defer b.bufAllocator.put(sridBuf)
+		if err := b.args[1].VecEvalInt(b.ctx, input, sridBuf); err != nil {
+			return err
+		}
+	}
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) || (sridBuf != nil && sridBuf.IsNull(i)) {
+			result.AppendNull()
+			continue
+		}
+		var srid int64
+		if sridBuf != nil {
+			srid = sridBuf.GetInt64(i)
+		}
+		res, _, err := geomFromText(buf.GetString(i), srid)
+		if err != nil {
+			return err
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+func (b *builtinSTAsTextSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSTAsTextSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		g, err := decodeGeometry(stAsText, buf.GetString(i))
+		if err != nil {
+			return err
+		}
+		result.AppendString(g.WKT())
+	}
+	return nil
+}
+
+// vecEvalCoordinate evaluates ST_X or ST_Y in a vectorized manner.
+func vecEvalCoordinate(b *baseBuiltinFunc, funcName string, input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	result.ResizeFloat64(n, false)
+	result.MergeNulls(buf)
+	f64s := result.Float64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		p, _, err := decodePoint(funcName, buf.GetString(i))
+		if err != nil {
+			return err
+		}
+		if funcName == stX {
+			f64s[i] = p.X
+		} else {
+			f64s[i] = p.Y
+		}
+	}
+	return nil
+}
+
+func (b *builtinSTXSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSTXSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalCoordinate(&b.baseBuiltinFunc, stX, input, result)
+}
+
+func (b *builtinSTYSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSTYSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalCoordinate(&b.baseBuiltinFunc, stY, input, result)
+}
+
+// vecEvalGeometryPair evaluates the two geometry arguments of a binary spatial function, and calls
+// fn on every row where neither of them is null.
+func vecEvalGeometryPair(b *baseBuiltinFunc, funcName string, input *chunk.Chunk, result *chunk.Column, fn func(i int, g1, g2 spatial.Geometry) error) error {
+	n := input.NumRows()
+	buf1, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf1)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf1); err != nil {
+		return err
+	}
+	buf2, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf2)
+	if err := b.args[1].VecEvalString(b.ctx, input, buf2); err != nil {
+		return err
+	}
+	result.MergeNulls(buf1, buf2)
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		g1, err := decodeGeometry(funcName, buf1.GetString(i))
+		if err != nil {
+			return err
+		}
+		g2, err := decodeGeometry(funcName, buf2.GetString(i))
+		if err != nil {
+			return err
+		}
+		if err := fn(i, g1, g2); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builtinSTDistanceSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSTDistanceSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	result.ResizeFloat64(input.NumRows(), false)
+	f64s := result.Float64s()
+	return vecEvalGeometryPair(&b.baseBuiltinFunc, stDistance, input, result, func(i int, g1, g2 spatial.Geometry) (err error) {
+		f64s[i], err = spatial.Distance(g1, g2)
+		return err
+	})
+}
+
+func (b *builtinSTContainsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSTContainsSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	result.ResizeInt64(input.NumRows(), false)
+	i64s := result.Int64s()
+	return vecEvalGeometryPair(&b.baseBuiltinFunc, stContains, input, result, func(i int, g1, g2 spatial.Geometry) error {
+		contains, err := spatial.Contains(g1, g2)
+		if contains {
+			i64s[i] = 1
+		} else {
+			i64s[i] = 0
+		}
+		return err
+	})
+}
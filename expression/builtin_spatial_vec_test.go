@@ -0,0 +1,69 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/spatial"
+)
+
+// pointGener generates encoded points with integer coordinates in [-10, 10).
+type pointGener struct {
+	randGen *defaultRandGen
+}
+
+func (g *pointGener) gen() interface{} {
+	if g.randGen.Float64() < 0.1 {
+		return nil
+	}
+	return string(spatial.NewPoint(0, float64(g.randGen.Intn(20)-10), float64(g.randGen.Intn(20)-10)).Encode())
+}
+
+var vecBuiltinSpatialCases = map[string][]vecExprBenchCase{
+	stGeomFromText: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}, geners: []dataGenerator{
+			newSelectStringGener([]string{"POINT(1 2)", "LINESTRING(0 0,1 1)", "POLYGON((0 0,1 0,1 1,0 0))"}),
+		}},
+	},
+	stAsText: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}, geners: []dataGenerator{&pointGener{newDefaultRandGen()}}},
+	},
+	stX: {
+		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETString}, geners: []dataGenerator{&pointGener{newDefaultRandGen()}}},
+	},
+	stY: {
+		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETString}, geners: []dataGenerator{&pointGener{newDefaultRandGen()}}},
+	},
+	stDistance: {
+		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETString, types.ETString}, geners: []dataGenerator{&pointGener{newDefaultRandGen()}, &pointGener{newDefaultRandGen()}}},
+	},
+	stContains: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString}, geners: []dataGenerator{&pointGener{newDefaultRandGen()}, &pointGener{newDefaultRandGen()}}},
+	},
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinSpatialEvalOneVec(c *C) {
+	testVectorizedEvalOneVec(c, vecBuiltinSpatialCases)
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinSpatialFunc(c *C) {
+	testVectorizedBuiltinFunc(c, vecBuiltinSpatialCases)
+}
+
+func BenchmarkVectorizedBuiltinSpatialFunc(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltinSpatialCases)
+}
@@ -36,11 +36,11 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/encoding"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tipb/go-tipb"
 	"go.uber.org/zap"
-	"golang.org/x/text/transform"
 )
 
 var (
@@ -1078,21 +1078,60 @@ func (c *convertFunctionClass) getFunction(ctx sessionctx.Context, args []Expres
 	}
 
 	bf.tp.Flen = mysql.MaxBlobWidth
-	sig := &builtinConvertSig{bf}
+	conv, err := encoding.NewConverter(convertSourceCharset(bf.args[0].GetType()), bf.tp.Charset)
+	if err != nil {
+		return nil, errUnknownCharacterSet.GenWithStackByArgs(transcodingName)
+	}
+	sig := &builtinConvertSig{bf, conv}
 	sig.setPbCode(tipb.ScalarFuncSig_Convert)
 	return sig, nil
 }
 
+// BuildConvertFunction wraps expr with CONVERT(expr USING cs) if expr is a string which isn't always
+// valid in cs, it's used by the casts with an explicit character set.
+func BuildConvertFunction(ctx sessionctx.Context, expr Expression, cs string) (Expression, error) {
+	tp := expr.GetType()
+	if !types.IsString(tp.Tp) || !encoding.NeedConvert(convertSourceCharset(tp), cs) {
+		return expr, nil
+	}
+	return NewFunction(ctx, ast.Convert, types.NewFieldType(mysql.TypeVarString), expr, DatumToConstant(types.NewStringDatum(cs), mysql.TypeVarString, 0))
+}
+
+// convertSourceCharset returns the character set which the argument of CONVERT is converted from.
+func convertSourceCharset(tp *types.FieldType) string {
+	if types.IsBinaryStr(tp) {
+		return charset.CharsetBin
+	}
+	if types.IsString(tp.Tp) && encoding.IsSupported(tp.Charset) {
+		return tp.Charset
+	}
+	return charset.CharsetUTF8MB4
+}
+
 type builtinConvertSig struct {
 	baseBuiltinFunc
+	conv *encoding.Converter
 }
 
 func (b *builtinConvertSig) Clone() builtinFunc {
-	newSig := &builtinConvertSig{}
+	newSig := &builtinConvertSig{conv: b.conv}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
+// convert converts str by the converter of b, the invalid characters are handled according to
+// the session variable tidb_invalid_char_handling.
+func (b *builtinConvertSig) convert(str string) (string, error) {
+	res, warn, err := b.conv.Convert(nil, hack.Slice(str), b.ctx.GetSessionVars().InvalidCharHandling)
+	if err != nil {
+		return "", err
+	}
+	if warn != nil {
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(warn)
+	}
+	return string(hack.String(res)), nil
+}
+
 // evalString evals CONVERT(expr USING transcoding_name).
 // Syntax CONVERT(expr, type) is parsed as cast expr so not handled here.
 // See https://dev.mysql.com/doc/refman/5.7/en/cast-functions.html#function_convert
@@ -1102,16 +1141,7 @@ func (b *builtinConvertSig) evalString(row chunk.Row) (string, bool, error) {
 		return "", true, err
 	}
 
-	// Since charset is already validated and set from getFunction(), there's no
-	// need to get charset from args again.
-	encoding, _ := charset.Lookup(b.tp.Charset)
-	// However, if `b.tp.Charset` is abnormally set to a wrong charset, we still
-	// return with error.
-	if encoding == nil {
-		return "", true, errUnknownCharacterSet.GenWithStackByArgs(b.tp.Charset)
-	}
-
-	target, _, err := transform.String(encoding.NewDecoder(), expr)
+	target, err := b.convert(expr)
 	return target, err != nil, err
 }
 
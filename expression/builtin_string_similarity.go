@@ -0,0 +1,359 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
+)
+
+// String similarity function names, levenshtein and jaro_winkler are not defined in the parser.
+const (
+	levenshtein = "levenshtein"
+	jaroWinkler = "jaro_winkler"
+)
+
+var (
+	_ functionClass = &soundexFunctionClass{}
+	_ functionClass = &levenshteinFunctionClass{}
+	_ functionClass = &jaroWinklerFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinSoundexSig{}
+	_ builtinFunc = &builtinLevenshteinSig{}
+	_ builtinFunc = &builtinJaroWinklerSig{}
+)
+
+// maxSimilarityUnits is the max number of units of the arguments of levenshtein and jaro_winkler,
+// their time complexity is the product of the lengths of the two arguments.
+const maxSimilarityUnits = 4096
+
+// similarityArgs splits str1 and str2 into units. If any of them is longer than
+// maxSimilarityUnits, it appends a warning and returns false, and the function returns NULL.
+func similarityArgs(ctx sessionctx.Context, funcName string, str1, str2 string, collation string) (units1, units2 []string, ok bool) {
+	for _, str := range []string{str1, str2} {
+		length := len(str)
+		if collation != charset.CollationBin {
+			length = utf8.RuneCountInString(str)
+		}
+		if length > maxSimilarityUnits {
+			ctx.GetSessionVars().StmtCtx.AppendWarning(errWarnSimilarityArgTooLong.GenWithStackByArgs(funcName, maxSimilarityUnits))
+			return nil, nil, false
+		}
+	}
+	return similarityUnits(str1, collation), similarityUnits(str2, collation), true
+}
+
+// similarityUnits splits str into the units compared by the similarity functions. The
+// binary strings are compared by bytes, the others by characters, which are replaced
+// by their collation keys unless the collation is binary, so `a` matches `A` under a
+// case-insensitive collation.
+func similarityUnits(str string, collation string) []string {
+	if collation == charset.CollationBin {
+		units := make([]string, len(str))
+		for i := range str {
+			units[i] = str[i : i+1]
+		}
+		return units
+	}
+	var collator collate.Collator
+	if !collate.IsBinCollation(collation) {
+		collator = collate.GetCollator(collation)
+	}
+	units := make([]string, 0, len(str))
+	for len(str) > 0 {
+		_, size := utf8.DecodeRuneInString(str)
+		unit := str[:size]
+		if collator != nil {
+			unit = string(collator.Key(unit))
+		}
+		units = append(units, unit)
+		str = str[size:]
+	}
+	return units
+}
+
+// levenshteinDistance returns the minimum number of single unit insertions, deletions
+// and substitutions changing a into b.
+func levenshteinDistance(a, b []string) int64 {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	// Only the previous row of the distance matrix is kept, it's indexed by b.
+	row := make([]int64, len(b)+1)
+	for j := range row {
+		row[j] = int64(j)
+	}
+	for i := range a {
+		prevDiag := row[0]
+		row[0] = int64(i + 1)
+		for j := range b {
+			cost := int64(1)
+			if a[i] == b[j] {
+				cost = 0
+			}
+			next := prevDiag + cost
+			if row[j]+1 < next {
+				next = row[j] + 1
+			}
+			if row[j+1]+1 < next {
+				next = row[j+1] + 1
+			}
+			prevDiag, row[j+1] = row[j+1], next
+		}
+	}
+	return row[len(b)]
+}
+
+// jaroWinklerSimilarity returns the Jaro-Winkler similarity of a and b in [0, 1], the
+// common prefix of at most 4 units increases the Jaro similarity with a scaling factor 0.1.
+// See https://en.wikipedia.org/wiki/Jaro%E2%80%93Winkler_distance.
+func jaroWinklerSimilarity(a, b []string) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	window := len(a)
+	if len(b) > window {
+		window = len(b)
+	}
+	window = window/2 - 1
+	if window < 0 {
+		window = 0
+	}
+	matchedA, matchedB := make([]bool, len(a)), make([]bool, len(b))
+	matches := 0
+	for i := range a {
+		lo, hi := i-window, i+window+1
+		if lo < 0 {
+			lo = 0
+		}
+		if hi > len(b) {
+			hi = len(b)
+		}
+		for j := lo; j < hi; j++ {
+			if !matchedB[j] && a[i] == b[j] {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+	// The transpositions are the matched units in different orders.
+	transpositions, j := 0, 0
+	for i := range a {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if a[i] != b[j] {
+			transpositions++
+		}
+		j++
+	}
+	m := float64(matches)
+	jaro := (m/float64(len(a)) + m/float64(len(b)) + (m-float64(transpositions/2))/m) / 3
+	prefix := 0
+	for prefix < 4 && prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// soundexCodes are the soundex digits of the letters A to Z, '0' means the letter is skipped.
+const soundexCodes = "01230120022455012623010202"
+
+func soundexCode(r rune) byte {
+	r = unicode.ToUpper(r)
+	if r < 'A' || r > 'Z' {
+		return '0'
+	}
+	return soundexCodes[r-'A']
+}
+
+// soundex returns the soundex string of str like MySQL: the characters which are not
+// letters are ignored, the letters out of A-Z are treated as vowels, and the result is
+// not truncated to 4 characters.
+func soundex(str string) string {
+	var sb strings.Builder
+	var last byte
+	for _, r := range str {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		code := soundexCode(r)
+		if sb.Len() == 0 {
+			sb.WriteRune(unicode.ToUpper(r))
+			last = code
+			continue
+		}
+		if code != '0' && code != last {
+			sb.WriteByte(code)
+			last = code
+		}
+	}
+	if sb.Len() == 0 {
+		return ""
+	}
+	for n := len([]rune(sb.String())); n < 4; n++ {
+		sb.WriteByte('0')
+	}
+	return sb.String()
+}
+
+type soundexFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *soundexFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = args[0].GetType().Flen
+	if bf.tp.Flen != types.UnspecifiedLength && bf.tp.Flen < 4 {
+		bf.tp.Flen = 4
+	}
+	sig := &builtinSoundexSig{bf}
+	return sig, nil
+}
+
+type builtinSoundexSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSoundexSig) Clone() builtinFunc {
+	newSig := &builtinSoundexSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals SOUNDEX(str).
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_soundex
+func (b *builtinSoundexSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	return soundex(str), false, nil
+}
+
+type levenshteinFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *levenshteinFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = mysql.MaxIntWidth
+	types.SetBinChsClnFlag(bf.tp)
+	sig := &builtinLevenshteinSig{bf}
+	return sig, nil
+}
+
+type builtinLevenshteinSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLevenshteinSig) Clone() builtinFunc {
+	newSig := &builtinLevenshteinSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals LEVENSHTEIN(str1, str2), the edit distance between two strings.
+func (b *builtinLevenshteinSig) evalInt(row chunk.Row) (int64, bool, error) {
+	str1, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	str2, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	units1, units2, ok := similarityArgs(b.ctx, levenshtein, str1, str2, b.collation)
+	if !ok {
+		return 0, true, nil
+	}
+	return levenshteinDistance(units1, units2), false, nil
+}
+
+type jaroWinklerFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jaroWinklerFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETReal, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen, bf.tp.Decimal = mysql.MaxRealWidth, types.UnspecifiedLength
+	types.SetBinChsClnFlag(bf.tp)
+	sig := &builtinJaroWinklerSig{bf}
+	return sig, nil
+}
+
+type builtinJaroWinklerSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJaroWinklerSig) Clone() builtinFunc {
+	newSig := &builtinJaroWinklerSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals JARO_WINKLER(str1, str2), the Jaro-Winkler similarity between two strings.
+func (b *builtinJaroWinklerSig) evalReal(row chunk.Row) (float64, bool, error) {
+	str1, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	str2, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	units1, units2, ok := similarityArgs(b.ctx, jaroWinkler, str1, str2, b.collation)
+	if !ok {
+		return 0, true, nil
+	}
+	return jaroWinklerSimilarity(units1, units2), false, nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"math"
+	"strings"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+func (s *testEvaluatorSuite) TestSoundex(c *C) {
+	tbl := []struct {
+		arg      interface{}
+		expected interface{}
+	}{
+		{"Hello", "H400"},
+		{"Quadratically", "Q36324"},
+		{"Robert", "R163"},
+		{"Rupert", "R163"},
+		// MySQL implements the original soundex, which discards the vowels before the duplicates.
+		{"Tymczak", "T520"},
+		{"  -- ashcraft", "A2613"},
+		{"ÉCOLE", "É240"},
+		{"123", ""},
+		{"", ""},
+		{nil, nil},
+	}
+	for _, t := range tbl {
+		f, err := newFunctionForTest(s.ctx, ast.Soundex, s.primitiveValsToConstants([]interface{}{t.arg})...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.expected), Commentf("soundex(%v)", t.arg))
+	}
+}
+
+func (s *testEvaluatorSuite) TestLevenshteinAndJaroWinkler(c *C) {
+	tbl := []struct {
+		str1, str2  interface{}
+		distance    interface{}
+		jaroWinkler interface{}
+	}{
+		{"kitten", "sitting", int64(3), 0.746},
+		{"MARTHA", "MARHTA", int64(2), 0.961},
+		{"DIXON", "DICKSONX", int64(4), 0.813},
+		{"abc", "abc", int64(0), 1.0},
+		{"", "", int64(0), 1.0},
+		{"abc", "", int64(3), 0.0},
+		{"数据库", "数据", int64(1), 0.911},
+		{"abc", nil, nil, nil},
+	}
+	for _, t := range tbl {
+		args := s.primitiveValsToConstants([]interface{}{t.str1, t.str2})
+		f, err := newFunctionForTest(s.ctx, levenshtein, args...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.distance), Commentf("levenshtein(%v, %v)", t.str1, t.str2))
+
+		f, err = newFunctionForTest(s.ctx, jaroWinkler, args...)
+		c.Assert(err, IsNil)
+		d, err = f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		if t.jaroWinkler == nil {
+			c.Assert(d.IsNull(), IsTrue)
+			continue
+		}
+		c.Assert(math.Abs(d.GetFloat64()-t.jaroWinkler.(float64)), Less, 0.001, Commentf("jaro_winkler(%v, %v) = %v", t.str1, t.str2, d.GetFloat64()))
+	}
+}
+
+func (s *testEvaluatorSuite) TestSimilarityUnits(c *C) {
+	// The characters are compared by the collation.
+	c.Assert(levenshteinDistance(similarityUnits("abc", "utf8mb4_bin"), similarityUnits("ABC", "utf8mb4_bin")), Equals, int64(3))
+	c.Assert(levenshteinDistance(similarityUnits("数据", "utf8mb4_bin"), similarityUnits("数", "utf8mb4_bin")), Equals, int64(1))
+	// The binary strings are compared by bytes.
+	c.Assert(levenshteinDistance(similarityUnits("数据", "binary"), similarityUnits("数", "binary")), Equals, int64(3))
+	// The invalid UTF-8 bytes are units on their own.
+	c.Assert(similarityUnits("a\xffb", "utf8mb4_bin"), DeepEquals, []string{"a", "\xff", "b"})
+}
+
+func (s *testEvaluatorSuite) TestSimilarityArgTooLong(c *C) {
+	// The length of a non-binary string is counted by characters.
+	maxLen := strings.Repeat("数", maxSimilarityUnits)
+	tooLong := strings.Repeat("a", maxSimilarityUnits+1)
+	sc := s.ctx.GetSessionVars().StmtCtx
+	for _, name := range []string{levenshtein, jaroWinkler} {
+		f, err := newFunctionForTest(s.ctx, name, s.primitiveValsToConstants([]interface{}{maxLen, "a"})...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.IsNull(), IsFalse)
+
+		warnCnt := sc.WarningCount()
+		f, err = newFunctionForTest(s.ctx, name, s.primitiveValsToConstants([]interface{}{"a", tooLong})...)
+		c.Assert(err, IsNil)
+		d, err = f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.IsNull(), IsTrue)
+		c.Assert(sc.WarningCount(), Equals, warnCnt+1)
+		c.Assert(errWarnSimilarityArgTooLong.Equal(sc.GetWarnings()[warnCnt].Err), IsTrue)
+	}
+}
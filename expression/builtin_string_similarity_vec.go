@@ -0,0 +1,122 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (b *builtinSoundexSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSoundexSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(soundex(buf.GetString(i)))
+	}
+	return nil
+}
+
+func (b *builtinLevenshteinSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLevenshteinSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	leftBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(leftBuf)
+	if err := b.args[0].VecEvalString(b.ctx, input, leftBuf); err != nil {
+		return err
+	}
+	rightBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(rightBuf)
+	if err := b.args[1].VecEvalString(b.ctx, input, rightBuf); err != nil {
+		return err
+	}
+	result.ResizeInt64(n, false)
+	result.MergeNulls(leftBuf, rightBuf)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		units1, units2, ok := similarityArgs(b.ctx, levenshtein, leftBuf.GetString(i), rightBuf.GetString(i), b.collation)
+		if !ok {
+			result.SetNull(i, true)
+			continue
+		}
+		i64s[i] = levenshteinDistance(units1, units2)
+	}
+	return nil
+}
+
+func (b *builtinJaroWinklerSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJaroWinklerSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	leftBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(leftBuf)
+	if err := b.args[0].VecEvalString(b.ctx, input, leftBuf); err != nil {
+		return err
+	}
+	rightBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(rightBuf)
+	if err := b.args[1].VecEvalString(b.ctx, input, rightBuf); err != nil {
+		return err
+	}
+	result.ResizeFloat64(n, false)
+	result.MergeNulls(leftBuf, rightBuf)
+	f64s := result.Float64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		units1, units2, ok := similarityArgs(b.ctx, jaroWinkler, leftBuf.GetString(i), rightBuf.GetString(i), b.collation)
+		if !ok {
+			result.SetNull(i, true)
+			continue
+		}
+		f64s[i] = jaroWinklerSimilarity(units1, units2)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/tidb/types"
+)
+
+var vecBuiltinStringSimilarityCases = map[string][]vecExprBenchCase{
+	ast.Soundex: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}, geners: []dataGenerator{
+			newSelectStringGener([]string{"Hello", "Quadratically", "Robert", "123", ""}),
+		}},
+	},
+	levenshtein: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString}, geners: []dataGenerator{
+			newSelectStringGener([]string{"kitten", "sitting", "数据库", ""}),
+			newSelectStringGener([]string{"kitten", "sitting", "数据库", ""}),
+		}},
+	},
+	jaroWinkler: {
+		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETString, types.ETString}},
+		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETString, types.ETString}, geners: []dataGenerator{
+			newSelectStringGener([]string{"MARTHA", "MARHTA", "DIXON", "DICKSONX", ""}),
+			newSelectStringGener([]string{"MARTHA", "MARHTA", "DIXON", "DICKSONX", ""}),
+		}},
+	},
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinStringSimilarityEvalOneVec(c *C) {
+	testVectorizedEvalOneVec(c, vecBuiltinStringSimilarityCases)
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinStringSimilarityFunc(c *C) {
+	testVectorizedBuiltinFunc(c, vecBuiltinStringSimilarityCases)
+}
+
+func BenchmarkVectorizedBuiltinStringSimilarityFunc(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltinStringSimilarityCases)
+}
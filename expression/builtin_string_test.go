@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/encoding"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tidb/util/testutil"
 )
@@ -802,15 +803,34 @@ func (s *testEvaluatorSuite) TestConvert(c *C) {
 		c.Assert(f, IsNil)
 	}
 
-	// Test wrong charset while evaluating.
-	fc := funcs[ast.Convert]
-	f, err := fc.getFunction(s.ctx, s.datumsToConstants(types.MakeDatums("haha", "utf8")))
-	c.Assert(err, IsNil)
-	c.Assert(f, NotNil)
-	wrongFunction := f.(*builtinConvertSig)
-	wrongFunction.tp.Charset = "wrongcharset"
-	_, err = evalBuiltinFunc(wrongFunction, chunk.Row{})
-	c.Assert(err.Error(), Equals, "[expression:1115]Unknown character set: 'wrongcharset'")
+	// Test invalid characters.
+	invalidTbl := []struct {
+		str    interface{}
+		cs     string
+		result string
+	}{
+		{types.NewBinaryLiteralFromUint(0x61ff62, -1), "utf8mb4", "a?b"},
+		{types.NewBinaryLiteralFromUint(0xf09f9880, -1), "utf8", "?"},
+		{"中文a", "ascii", "??a"},
+	}
+	sc := s.ctx.GetSessionVars().StmtCtx
+	for _, v := range invalidTbl {
+		fc := funcs[ast.Convert]
+		f, err := fc.getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(v.str, v.cs)))
+		c.Assert(err, IsNil)
+		s.ctx.GetSessionVars().InvalidCharHandling = encoding.InvalidCharReplace
+		sc.SetWarnings(nil)
+		r, err := evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(r.GetString(), Equals, v.result)
+		c.Assert(sc.WarningCount(), Equals, uint16(1))
+		c.Assert(encoding.ErrInvalidCharacterString.Equal(sc.GetWarnings()[0].Err), IsTrue)
+
+		s.ctx.GetSessionVars().InvalidCharHandling = encoding.InvalidCharError
+		_, err = evalBuiltinFunc(f, chunk.Row{})
+		c.Assert(encoding.ErrInvalidCharacterString.Equal(err), IsTrue)
+	}
+	s.ctx.GetSessionVars().InvalidCharHandling = encoding.InvalidCharReplace
 }
 
 func (s *testEvaluatorSuite) TestSubstringIndex(c *C) {
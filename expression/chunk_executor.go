@@ -33,25 +33,11 @@ func Vectorizable(exprs []Expression) bool {
 
 // checkSequenceFunction indicates whether the exprs can be evaluated as a vector.
 // When two or more of this three(nextval, lastval, setval) exists in exprs list and one of them is nextval, it should be eval row by row.
+// The sequence functions in the arguments are also counted, because nextval can be evaluated as a vector.
 func checkSequenceFunction(exprs []Expression) bool {
-	var (
-		nextval int
-		lastval int
-		setval  int
-	)
+	var nextval, lastval, setval int
 	for _, expr := range exprs {
-		scalaFunc, ok := expr.(*ScalarFunction)
-		if !ok {
-			continue
-		}
-		switch scalaFunc.FuncName.L {
-		case ast.NextVal:
-			nextval++
-		case ast.LastVal:
-			lastval++
-		case ast.SetVal:
-			setval++
-		}
+		countSequenceFunction(expr, &nextval, &lastval, &setval)
 	}
 	// case1: nextval && other sequence function.
 	// case2: more than one nextval.
@@ -61,6 +47,24 @@ func checkSequenceFunction(exprs []Expression) bool {
 	return true
 }
 
+func countSequenceFunction(expr Expression, nextval, lastval, setval *int) {
+	scalaFunc, ok := expr.(*ScalarFunction)
+	if !ok {
+		return
+	}
+	switch scalaFunc.FuncName.L {
+	case ast.NextVal:
+		*nextval++
+	case ast.LastVal:
+		*lastval++
+	case ast.SetVal:
+		*setval++
+	}
+	for _, arg := range scalaFunc.GetArgs() {
+		countSequenceFunction(arg, nextval, lastval, setval)
+	}
+}
+
 // HasGetSetVarFunc checks whether an expression contains SetVar/GetVar function.
 func HasGetSetVarFunc(expr Expression) bool {
 	scalaFunc, ok := expr.(*ScalarFunction)
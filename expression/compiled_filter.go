@@ -0,0 +1,499 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// CompileFilterHotRows is the number of rows a filter should be evaluated on
+// before it is considered hot and worth compiling by CompileFilter.
+var CompileFilterHotRows = 1 << 16
+
+// compiledInt is an expression fused into a closure which evaluates it as an int.
+type compiledInt func(row chunk.Row) (int64, bool, error)
+
+// compiledReal is an expression fused into a closure which evaluates it as a real.
+type compiledReal func(row chunk.Row) (float64, bool, error)
+
+// CompiledFilter is a list of filters fused into Go closures. Calling the closures
+// avoids the interface dispatches and the per-node bookkeeping of walking the
+// expression tree, which matters for simple filters evaluated on a lot of rows.
+type CompiledFilter struct {
+	filters []compiledInt
+}
+
+// CompileFilter fuses filters into closures. It returns nil if any of the filters
+// contains an expression which is not supported. The supported expressions are:
+//   - columns and constants of signed integer and real types;
+//   - +, - and * of signed integers and reals;
+//   - <, <=, >, >=, = and != of signed integers and reals;
+//   - AND, OR, NOT, IS NULL and IS TRUE.
+func CompileFilter(ctx sessionctx.Context, filters []Expression) *CompiledFilter {
+	f := &CompiledFilter{filters: make([]compiledInt, 0, len(filters))}
+	for _, filter := range filters {
+		fn, ok := compileInt(ctx, filter)
+		if !ok {
+			return nil
+		}
+		f.filters = append(f.filters, fn)
+	}
+	return f
+}
+
+// Filter applies the filters to the Chunk of iterator, it returns the same result as VectorizedFilter.
+func (f *CompiledFilter) Filter(iterator *chunk.Iterator4Chunk, selected []bool) ([]bool, error) {
+	input := iterator.GetChunk()
+	sel := input.Sel()
+	if sel != nil {
+		defer input.SetSel(sel)
+		input.SetSel(nil)
+	}
+	numRows := input.NumRows()
+	selected = selected[:0]
+	for i := 0; i < numRows; i++ {
+		selected = append(selected, sel == nil)
+	}
+	for _, i := range sel {
+		selected[i] = true
+	}
+	for _, filter := range f.filters {
+		for i := 0; i < numRows; i++ {
+			if !selected[i] {
+				continue
+			}
+			val, isNull, err := filter(input.GetRow(i))
+			if err != nil {
+				return nil, err
+			}
+			selected[i] = !isNull && val != 0
+		}
+	}
+	return selected, nil
+}
+
+func isSignedInt(expr Expression) bool {
+	tp := expr.GetType()
+	return tp.EvalType() == types.ETInt && !tp.Hybrid() && !mysql.HasUnsignedFlag(tp.Flag)
+}
+
+func compileInt(ctx sessionctx.Context, expr Expression) (compiledInt, bool) {
+	if !isSignedInt(expr) {
+		return nil, false
+	}
+	switch x := expr.(type) {
+	case *Column:
+		idx := x.Index
+		return func(row chunk.Row) (int64, bool, error) {
+			if row.IsNull(idx) {
+				return 0, true, nil
+			}
+			return row.GetInt64(idx), false, nil
+		}, true
+	case *Constant:
+		if x.DeferredExpr != nil || x.ParamMarker != nil {
+			return nil, false
+		}
+		val, isNull, err := x.EvalInt(ctx, chunk.Row{})
+		if err != nil {
+			return nil, false
+		}
+		return func(chunk.Row) (int64, bool, error) {
+			return val, isNull, nil
+		}, true
+	case *ScalarFunction:
+		return compileIntFunc(ctx, x)
+	}
+	return nil, false
+}
+
+func compileIntFunc(ctx sessionctx.Context, sf *ScalarFunction) (compiledInt, bool) {
+	args := sf.GetArgs()
+	switch sig := sf.Function.(type) {
+	case *builtinLTIntSig:
+		return compileIntCompare(ctx, args, resOfLT)
+	case *builtinLEIntSig:
+		return compileIntCompare(ctx, args, resOfLE)
+	case *builtinGTIntSig:
+		return compileIntCompare(ctx, args, resOfGT)
+	case *builtinGEIntSig:
+		return compileIntCompare(ctx, args, resOfGE)
+	case *builtinEQIntSig:
+		return compileIntCompare(ctx, args, resOfEQ)
+	case *builtinNEIntSig:
+		return compileIntCompare(ctx, args, resOfNE)
+	case *builtinLTRealSig:
+		return compileRealCompare(ctx, args, resOfLT)
+	case *builtinLERealSig:
+		return compileRealCompare(ctx, args, resOfLE)
+	case *builtinGTRealSig:
+		return compileRealCompare(ctx, args, resOfGT)
+	case *builtinGERealSig:
+		return compileRealCompare(ctx, args, resOfGE)
+	case *builtinEQRealSig:
+		return compileRealCompare(ctx, args, resOfEQ)
+	case *builtinNERealSig:
+		return compileRealCompare(ctx, args, resOfNE)
+	case *builtinLogicAndSig:
+		lhs, rhs, ok := compileIntArgs(ctx, args)
+		if !ok {
+			return nil, false
+		}
+		return func(row chunk.Row) (int64, bool, error) {
+			arg0, isNull0, err := lhs(row)
+			if err != nil || (!isNull0 && arg0 == 0) {
+				return 0, err != nil, err
+			}
+			arg1, isNull1, err := rhs(row)
+			if err != nil || (!isNull1 && arg1 == 0) {
+				return 0, err != nil, err
+			}
+			if isNull0 || isNull1 {
+				return 0, true, nil
+			}
+			return 1, false, nil
+		}, true
+	case *builtinLogicOrSig:
+		lhs, rhs, ok := compileIntArgs(ctx, args)
+		if !ok {
+			return nil, false
+		}
+		return func(row chunk.Row) (int64, bool, error) {
+			arg0, isNull0, err := lhs(row)
+			if err != nil {
+				return 0, true, err
+			}
+			if !isNull0 && arg0 != 0 {
+				return 1, false, nil
+			}
+			arg1, isNull1, err := rhs(row)
+			if err != nil {
+				return 0, true, err
+			}
+			if !isNull1 && arg1 != 0 {
+				return 1, false, nil
+			}
+			if isNull0 || isNull1 {
+				return 0, true, nil
+			}
+			return 0, false, nil
+		}, true
+	case *builtinUnaryNotIntSig:
+		arg, ok := compileInt(ctx, args[0])
+		if !ok {
+			return nil, false
+		}
+		return func(row chunk.Row) (int64, bool, error) {
+			val, isNull, err := arg(row)
+			if isNull || err != nil {
+				return 0, true, err
+			}
+			if val == 0 {
+				return 1, false, nil
+			}
+			return 0, false, nil
+		}, true
+	case *builtinIntIsNullSig:
+		arg, ok := compileInt(ctx, args[0])
+		if !ok {
+			return nil, false
+		}
+		return func(row chunk.Row) (int64, bool, error) {
+			_, isNull, err := arg(row)
+			return evalIsNull(isNull, err)
+		}, true
+	case *builtinRealIsNullSig:
+		arg, ok := compileReal(ctx, args[0])
+		if !ok {
+			return nil, false
+		}
+		return func(row chunk.Row) (int64, bool, error) {
+			_, isNull, err := arg(row)
+			return evalIsNull(isNull, err)
+		}, true
+	case *builtinIntIsTrueSig:
+		arg, ok := compileInt(ctx, args[0])
+		if !ok {
+			return nil, false
+		}
+		keepNull := sig.keepNull
+		return func(row chunk.Row) (int64, bool, error) {
+			val, isNull, err := arg(row)
+			if err != nil {
+				return 0, true, err
+			}
+			if keepNull && isNull {
+				return 0, true, nil
+			}
+			if isNull || val == 0 {
+				return 0, false, nil
+			}
+			return 1, false, nil
+		}, true
+	case *builtinRealIsTrueSig:
+		arg, ok := compileReal(ctx, args[0])
+		if !ok {
+			return nil, false
+		}
+		keepNull := sig.keepNull
+		return func(row chunk.Row) (int64, bool, error) {
+			val, isNull, err := arg(row)
+			if err != nil {
+				return 0, true, err
+			}
+			if keepNull && isNull {
+				return 0, true, nil
+			}
+			if isNull || val == 0 {
+				return 0, false, nil
+			}
+			return 1, false, nil
+		}, true
+	case *builtinArithmeticPlusIntSig:
+		lhs, rhs, ok := compileIntArgs(ctx, args)
+		if !ok {
+			return nil, false
+		}
+		errExpr := fmt.Sprintf("(%s + %s)", args[0].String(), args[1].String())
+		return func(row chunk.Row) (int64, bool, error) {
+			a, isNull, err := lhs(row)
+			if isNull || err != nil {
+				return 0, isNull, err
+			}
+			b, isNull, err := rhs(row)
+			if isNull || err != nil {
+				return 0, isNull, err
+			}
+			if (a > 0 && b > math.MaxInt64-a) || (a < 0 && b < math.MinInt64-a) {
+				return 0, true, types.ErrOverflow.GenWithStackByArgs("BIGINT", errExpr)
+			}
+			return a + b, false, nil
+		}, true
+	case *builtinArithmeticMinusIntSig:
+		lhs, rhs, ok := compileIntArgs(ctx, args)
+		if !ok {
+			return nil, false
+		}
+		errExpr := fmt.Sprintf("(%s - %s)", args[0].String(), args[1].String())
+		return func(row chunk.Row) (int64, bool, error) {
+			a, isNull, err := lhs(row)
+			if isNull || err != nil {
+				return 0, isNull, err
+			}
+			b, isNull, err := rhs(row)
+			if isNull || err != nil {
+				return 0, isNull, err
+			}
+			res := a - b
+			if (a > 0 && b < 0 && res < 0) || (a < 0 && b > 0 && res >= 0) {
+				return 0, true, types.ErrOverflow.GenWithStackByArgs("BIGINT", errExpr)
+			}
+			return res, false, nil
+		}, true
+	case *builtinArithmeticMultiplyIntSig:
+		lhs, rhs, ok := compileIntArgs(ctx, args)
+		if !ok {
+			return nil, false
+		}
+		errExpr := fmt.Sprintf("(%s * %s)", args[0].String(), args[1].String())
+		return func(row chunk.Row) (int64, bool, error) {
+			a, isNull, err := lhs(row)
+			if isNull || err != nil {
+				return 0, isNull, err
+			}
+			b, isNull, err := rhs(row)
+			if isNull || err != nil {
+				return 0, isNull, err
+			}
+			res := a * b
+			if (a != 0 && res/a != b) || (res == math.MinInt64 && a == -1) {
+				return 0, true, types.ErrOverflow.GenWithStackByArgs("BIGINT", errExpr)
+			}
+			return res, false, nil
+		}, true
+	}
+	return nil, false
+}
+
+func compileIntArgs(ctx sessionctx.Context, args []Expression) (lhs, rhs compiledInt, ok bool) {
+	if lhs, ok = compileInt(ctx, args[0]); !ok {
+		return nil, nil, false
+	}
+	if rhs, ok = compileInt(ctx, args[1]); !ok {
+		return nil, nil, false
+	}
+	return lhs, rhs, true
+}
+
+func compileIntCompare(ctx sessionctx.Context, args []Expression, resOf func(int64, bool, error) (int64, bool, error)) (compiledInt, bool) {
+	lhs, rhs, ok := compileIntArgs(ctx, args)
+	if !ok {
+		return nil, false
+	}
+	return func(row chunk.Row) (int64, bool, error) {
+		arg0, isNull0, err := lhs(row)
+		if err != nil {
+			return 0, true, err
+		}
+		arg1, isNull1, err := rhs(row)
+		if err != nil {
+			return 0, true, err
+		}
+		if isNull0 || isNull1 {
+			return resOf(compareNull(isNull0, isNull1), true, nil)
+		}
+		return resOf(int64(types.CompareInt64(arg0, arg1)), false, nil)
+	}, true
+}
+
+func compileRealCompare(ctx sessionctx.Context, args []Expression, resOf func(int64, bool, error) (int64, bool, error)) (compiledInt, bool) {
+	lhs, rhs, ok := compileRealArgs(ctx, args)
+	if !ok {
+		return nil, false
+	}
+	return func(row chunk.Row) (int64, bool, error) {
+		arg0, isNull0, err := lhs(row)
+		if err != nil {
+			return 0, true, err
+		}
+		arg1, isNull1, err := rhs(row)
+		if err != nil {
+			return 0, true, err
+		}
+		if isNull0 || isNull1 {
+			return resOf(compareNull(isNull0, isNull1), true, nil)
+		}
+		return resOf(int64(types.CompareFloat64(arg0, arg1)), false, nil)
+	}, true
+}
+
+func compileReal(ctx sessionctx.Context, expr Expression) (compiledReal, bool) {
+	if expr.GetType().EvalType() != types.ETReal {
+		return nil, false
+	}
+	switch x := expr.(type) {
+	case *Column:
+		idx := x.Index
+		if x.GetType().Tp == mysql.TypeFloat {
+			return func(row chunk.Row) (float64, bool, error) {
+				if row.IsNull(idx) {
+					return 0, true, nil
+				}
+				return float64(row.GetFloat32(idx)), false, nil
+			}, true
+		}
+		return func(row chunk.Row) (float64, bool, error) {
+			if row.IsNull(idx) {
+				return 0, true, nil
+			}
+			return row.GetFloat64(idx), false, nil
+		}, true
+	case *Constant:
+		if x.DeferredExpr != nil || x.ParamMarker != nil {
+			return nil, false
+		}
+		val, isNull, err := x.EvalReal(ctx, chunk.Row{})
+		if err != nil {
+			return nil, false
+		}
+		return func(chunk.Row) (float64, bool, error) {
+			return val, isNull, nil
+		}, true
+	case *ScalarFunction:
+		args := x.GetArgs()
+		switch x.Function.(type) {
+		case *builtinArithmeticPlusRealSig:
+			lhs, rhs, ok := compileRealArgs(ctx, args)
+			if !ok {
+				return nil, false
+			}
+			errExpr := fmt.Sprintf("(%s + %s)", args[0].String(), args[1].String())
+			return func(row chunk.Row) (float64, bool, error) {
+				a, isLHSNull, err := lhs(row)
+				if err != nil {
+					return 0, isLHSNull, err
+				}
+				b, isRHSNull, err := rhs(row)
+				if err != nil {
+					return 0, isRHSNull, err
+				}
+				if isLHSNull || isRHSNull {
+					return 0, true, nil
+				}
+				if (a > 0 && b > math.MaxFloat64-a) || (a < 0 && b < -math.MaxFloat64-a) {
+					return 0, true, types.ErrOverflow.GenWithStackByArgs("DOUBLE", errExpr)
+				}
+				return a + b, false, nil
+			}, true
+		case *builtinArithmeticMinusRealSig:
+			lhs, rhs, ok := compileRealArgs(ctx, args)
+			if !ok {
+				return nil, false
+			}
+			errExpr := fmt.Sprintf("(%s - %s)", args[0].String(), args[1].String())
+			return func(row chunk.Row) (float64, bool, error) {
+				a, isNull, err := lhs(row)
+				if isNull || err != nil {
+					return 0, isNull, err
+				}
+				b, isNull, err := rhs(row)
+				if isNull || err != nil {
+					return 0, isNull, err
+				}
+				if (a > 0 && -b > math.MaxFloat64-a) || (a < 0 && -b < -math.MaxFloat64-a) {
+					return 0, true, types.ErrOverflow.GenWithStackByArgs("DOUBLE", errExpr)
+				}
+				return a - b, false, nil
+			}, true
+		case *builtinArithmeticMultiplyRealSig:
+			lhs, rhs, ok := compileRealArgs(ctx, args)
+			if !ok {
+				return nil, false
+			}
+			errExpr := fmt.Sprintf("(%s * %s)", args[0].String(), args[1].String())
+			return func(row chunk.Row) (float64, bool, error) {
+				a, isNull, err := lhs(row)
+				if isNull || err != nil {
+					return 0, isNull, err
+				}
+				b, isNull, err := rhs(row)
+				if isNull || err != nil {
+					return 0, isNull, err
+				}
+				res := a * b
+				if math.IsInf(res, 0) {
+					return 0, true, types.ErrOverflow.GenWithStackByArgs("DOUBLE", errExpr)
+				}
+				return res, false, nil
+			}, true
+		}
+	}
+	return nil, false
+}
+
+func compileRealArgs(ctx sessionctx.Context, args []Expression) (lhs, rhs compiledReal, ok bool) {
+	if lhs, ok = compileReal(ctx, args[0]); !ok {
+		return nil, nil, false
+	}
+	if rhs, ok = compileReal(ctx, args[1]); !ok {
+		return nil, nil, false
+	}
+	return lhs, rhs, true
+}
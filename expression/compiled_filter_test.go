@@ -0,0 +1,204 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/mock"
+)
+
+var compiledFilterColTypes = []*types.FieldType{
+	types.NewFieldType(mysql.TypeLonglong),
+	types.NewFieldType(mysql.TypeLonglong),
+	types.NewFieldType(mysql.TypeDouble),
+	types.NewFieldType(mysql.TypeFloat),
+	types.NewFieldType(mysql.TypeVarchar),
+}
+
+// genCompiledFilterInput generates a chunk with the columns of compiledFilterColTypes.
+func genCompiledFilterInput(numRows int) *chunk.Chunk {
+	input := chunk.New(compiledFilterColTypes, numRows, numRows)
+	for i := 0; i < numRows; i++ {
+		for colIdx := 0; colIdx < 4; colIdx++ {
+			if rand.Intn(10) == 0 {
+				input.AppendNull(colIdx)
+				continue
+			}
+			switch colIdx {
+			case 0, 1:
+				input.AppendInt64(colIdx, rand.Int63n(20)-10)
+			case 2:
+				input.AppendFloat64(colIdx, rand.Float64()*20-10)
+			case 3:
+				input.AppendFloat32(colIdx, rand.Float32()*20-10)
+			}
+		}
+		input.AppendString(4, "a")
+	}
+	return input
+}
+
+func compiledFilterCol(idx int) *Column {
+	return &Column{Index: idx, RetType: compiledFilterColTypes[idx]}
+}
+
+func genCompiledFilters(ctx sessionctx.Context) [][]Expression {
+	intCon := func(v int64) Expression {
+		return &Constant{Value: types.NewIntDatum(v), RetType: types.NewFieldType(mysql.TypeLonglong)}
+	}
+	realCon := func(v float64) Expression {
+		return &Constant{Value: types.NewFloat64Datum(v), RetType: types.NewFieldType(mysql.TypeDouble)}
+	}
+	fn := func(name string, args ...Expression) Expression {
+		return NewFunctionInternal(ctx, name, types.NewFieldType(mysql.TypeLonglong), args...)
+	}
+	c0, c1, c2, c3 := compiledFilterCol(0), compiledFilterCol(1), compiledFilterCol(2), compiledFilterCol(3)
+	return [][]Expression{
+		{fn(ast.LT, c0, intCon(3))},
+		{fn(ast.GE, fn(ast.Plus, c0, c1), intCon(0)), fn(ast.LT, fn(ast.Minus, c2, realCon(1.5)), c3)},
+		{fn(ast.LogicOr, fn(ast.UnaryNot, fn(ast.IsNull, c0)), fn(ast.NE, c2, realCon(0)))},
+		{fn(ast.LogicAnd, c0, c2)},
+		{fn(ast.EQ, fn(ast.Minus, c1, intCon(3)), c0), fn(ast.GT, c2, fn(ast.Plus, c3, realCon(1)))},
+		{fn(ast.LE, c1, c0), fn(ast.IsNull, c3)},
+		{fn(ast.NE, fn(ast.Mul, c0, c1), intCon(0)), fn(ast.LE, fn(ast.Mul, c2, c3), realCon(10))},
+		{c0},
+	}
+}
+
+func (s *testEvaluatorSuite) TestCompiledFilter(c *C) {
+	ctx := mock.NewContext()
+	for _, filters := range genCompiledFilters(ctx) {
+		compiled := CompileFilter(ctx, filters)
+		c.Assert(compiled, NotNil, Commentf("%v", filters))
+		for round := 0; round < 8; round++ {
+			input := genCompiledFilterInput(1024)
+			it := chunk.NewIterator4Chunk(input)
+			expected, err := VectorizedFilter(ctx, filters, it, nil)
+			c.Assert(err, IsNil)
+			selected, err := compiled.Filter(it, nil)
+			c.Assert(err, IsNil)
+			c.Assert(selected, DeepEquals, expected)
+
+			input.SetSel(generateRandomSel())
+			it = chunk.NewIterator4Chunk(input)
+			expected, err = VectorizedFilter(ctx, filters, it, nil)
+			c.Assert(err, IsNil)
+			selected, err = compiled.Filter(it, selected)
+			c.Assert(err, IsNil)
+			c.Assert(selected, DeepEquals, expected)
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestCompiledFilterOverflow(c *C) {
+	ctx := mock.NewContext()
+	max := &Constant{Value: types.NewIntDatum(math.MaxInt64), RetType: types.NewFieldType(mysql.TypeLonglong)}
+	plus := NewFunctionInternal(ctx, ast.Plus, types.NewFieldType(mysql.TypeLonglong), compiledFilterCol(0), max)
+	filters := []Expression{NewFunctionInternal(ctx, ast.GT, types.NewFieldType(mysql.TypeLonglong), plus, compiledFilterCol(1))}
+	compiled := CompileFilter(ctx, filters)
+	c.Assert(compiled, NotNil)
+
+	input := chunk.New(compiledFilterColTypes, 1, 1)
+	input.AppendInt64(0, 1)
+	input.AppendInt64(1, 1)
+	input.AppendFloat64(2, 0)
+	input.AppendFloat32(3, 0)
+	input.AppendString(4, "a")
+	it := chunk.NewIterator4Chunk(input)
+	_, expected := VectorizedFilter(ctx, filters, it, nil)
+	c.Assert(types.ErrOverflow.Equal(expected), IsTrue)
+	_, err := compiled.Filter(it, nil)
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, expected.Error())
+}
+
+func (s *testEvaluatorSuite) TestCompiledFilterMulOverflow(c *C) {
+	ctx := mock.NewContext()
+	mul := NewFunctionInternal(ctx, ast.Mul, types.NewFieldType(mysql.TypeLonglong), compiledFilterCol(0), compiledFilterCol(1))
+	filters := []Expression{NewFunctionInternal(ctx, ast.GT, types.NewFieldType(mysql.TypeLonglong), mul, compiledFilterCol(1))}
+	compiled := CompileFilter(ctx, filters)
+	c.Assert(compiled, NotNil)
+
+	input := chunk.New(compiledFilterColTypes, 1, 1)
+	input.AppendInt64(0, math.MinInt64)
+	input.AppendInt64(1, -1)
+	input.AppendFloat64(2, 0)
+	input.AppendFloat32(3, 0)
+	input.AppendString(4, "a")
+	it := chunk.NewIterator4Chunk(input)
+	_, expected := VectorizedFilter(ctx, filters, it, nil)
+	c.Assert(types.ErrOverflow.Equal(expected), IsTrue)
+	_, err := compiled.Filter(it, nil)
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, expected.Error())
+}
+
+func (s *testEvaluatorSuite) TestCompileFilterUnsupported(c *C) {
+	ctx := mock.NewContext()
+	str := &Constant{Value: types.NewStringDatum("a"), RetType: types.NewFieldType(mysql.TypeVarchar)}
+	unsignedType := types.NewFieldType(mysql.TypeLonglong)
+	unsignedType.Flag |= mysql.UnsignedFlag
+	unsignedCol := &Column{Index: 0, RetType: unsignedType}
+	supported := NewFunctionInternal(ctx, ast.LT, types.NewFieldType(mysql.TypeLonglong), compiledFilterCol(0), compiledFilterCol(1))
+	for _, filter := range []Expression{
+		NewFunctionInternal(ctx, ast.EQ, types.NewFieldType(mysql.TypeLonglong), compiledFilterCol(4), str),
+		NewFunctionInternal(ctx, ast.LT, types.NewFieldType(mysql.TypeLonglong), unsignedCol, compiledFilterCol(1)),
+		// A real filter is evaluated as a bool, which is not supported.
+		compiledFilterCol(2),
+	} {
+		c.Assert(CompileFilter(ctx, []Expression{supported, filter}), IsNil, Commentf("%v", filter))
+	}
+}
+
+func BenchmarkCompiledFilter(b *testing.B) {
+	ctx := mock.NewContext()
+	input := genCompiledFilterInput(1024)
+	it := chunk.NewIterator4Chunk(input)
+	selected := make([]bool, 0, 1024)
+	nulls := make([]bool, 0, 1024)
+	for i, filters := range genCompiledFilters(ctx) {
+		compiled := CompileFilter(ctx, filters)
+		b.Run(fmt.Sprintf("Row-%d", i), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, _, err := rowBasedFilter(ctx, filters, it, selected, nulls); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("Vec-%d", i), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, _, err := vectorizedFilter(ctx, filters, it, selected, nulls); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("Compiled-%d", i), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := compiled.Filter(it, selected); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
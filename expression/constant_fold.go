@@ -65,10 +65,30 @@ func isNullHandler(expr *ScalarFunction) (Expression, bool) {
 	return expr, false
 }
 
+// foldDeferredBranches folds the control flow function whose branch to run depends on a deferred constant, e.g.
+// `IF(? > 0, a, b)` in a cached plan. The branch can't be chosen until the execution, so the function is folded
+// into a deferred constant if all of its arguments are constants, otherwise it's kept as is.
+func foldDeferredBranches(expr *ScalarFunction) (Expression, bool) {
+	for _, arg := range expr.GetArgs() {
+		if _, isConst := arg.(*Constant); !isConst {
+			return expr, true
+		}
+	}
+	value, err := expr.Eval(chunk.Row{})
+	if err != nil {
+		logutil.BgLogger().Debug("fold expression to constant", zap.String("expression", expr.ExplainInfo()), zap.Error(err))
+		return expr, true
+	}
+	return &Constant{Value: value, RetType: expr.RetType, DeferredExpr: expr}, true
+}
+
 func ifFoldHandler(expr *ScalarFunction) (Expression, bool) {
 	args := expr.GetArgs()
-	foldedArg0, _ := foldConstant(args[0])
+	foldedArg0, isDeferred := foldConstant(args[0])
 	if constArg, isConst := foldedArg0.(*Constant); isConst {
+		if isDeferred {
+			return foldDeferredBranches(expr)
+		}
 		arg0, isNull0, err := constArg.EvalInt(expr.Function.getCtx(), chunk.Row{})
 		if err != nil {
 			// Failed to fold this expr to a constant, print the DEBUG log and
@@ -90,13 +110,13 @@ func ifNullFoldHandler(expr *ScalarFunction) (Expression, bool) {
 	args := expr.GetArgs()
 	foldedArg0, isDeferred := foldConstant(args[0])
 	if constArg, isConst := foldedArg0.(*Constant); isConst {
-		// Only check constArg.Value here. Because deferred expression is
-		// evaluated to constArg.Value after foldConstant(args[0]), it's not
-		// needed to be checked.
+		if isDeferred {
+			return foldDeferredBranches(expr)
+		}
 		if constArg.Value.IsNull() {
 			return foldConstant(args[1])
 		}
-		return constArg, isDeferred
+		return constArg, false
 	}
 	// if the condition is not const, which branch is unknown to run, so directly return.
 	return expr, false
@@ -109,6 +129,9 @@ func caseWhenHandler(expr *ScalarFunction) (Expression, bool) {
 		expr.GetArgs()[i], isDeferred = foldConstant(args[i])
 		isDeferredConst = isDeferredConst || isDeferred
 		if _, isConst := expr.GetArgs()[i].(*Constant); isConst {
+			if isDeferred {
+				return foldDeferredBranches(expr)
+			}
 			// If the condition is const and true, and the previous conditions
 			// has no expr, then the folded execution body is returned, otherwise
 			// the arguments of the casewhen are folded and replaced.
@@ -158,21 +181,30 @@ func foldConstant(expr Expression) (Expression, bool) {
 		args := x.GetArgs()
 		sc := x.GetCtx().GetSessionVars().StmtCtx
 		argIsConst := make([]bool, len(args))
-		hasNullArg := false
+		hasNullArg, hasStaticNullArg := false, false
 		allConstArg := true
 		isDeferredConst := false
 		for i := 0; i < len(args); i++ {
 			switch x := args[i].(type) {
 			case *Constant:
-				isDeferredConst = isDeferredConst || x.DeferredExpr != nil || x.ParamMarker != nil
+				isDeferred := x.DeferredExpr != nil || x.ParamMarker != nil
+				isDeferredConst = isDeferredConst || isDeferred
 				argIsConst[i] = true
 				hasNullArg = hasNullArg || x.Value.IsNull()
+				hasStaticNullArg = hasStaticNullArg || (!isDeferred && x.Value.IsNull())
 			default:
 				allConstArg = false
 			}
 		}
+		if _, isStrict := nullStrictFunctions[x.FuncName.L]; allConstArg && isDeferredConst && hasStaticNullArg && isStrict {
+			// The result is NULL whatever the deferred arguments are, so it doesn't depend on them.
+			retType := x.RetType.Clone()
+			retType.Flag &= ^mysql.NotNullFlag
+			return &Constant{Value: types.NewDatum(nil), RetType: retType}, false
+		}
 		if !allConstArg {
-			if !hasNullArg || !sc.InNullRejectCheck || x.FuncName.L == ast.NullEQ {
+			// The result of the dummy function depends on the deferred arguments, we can't use it in a cached plan.
+			if !hasNullArg || !sc.InNullRejectCheck || x.FuncName.L == ast.NullEQ || isDeferredConst {
 				return expr, isDeferredConst
 			}
 			constArgs := make([]Expression, len(args))
@@ -0,0 +1,158 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/collate"
+)
+
+// commutativeFuncs stores the functions whose arguments can be reordered without changing the result.
+var commutativeFuncs = map[string]struct{}{
+	ast.EQ:       {},
+	ast.NE:       {},
+	ast.NullEQ:   {},
+	ast.Plus:     {},
+	ast.Mul:      {},
+	ast.LogicAnd: {},
+	ast.LogicOr:  {},
+	ast.LogicXor: {},
+	ast.And:      {},
+	ast.Or:       {},
+	ast.Xor:      {},
+}
+
+// a op b is equal to b symmetricFuncs[op] a
+var symmetricFuncs = map[string]string{
+	ast.LT: ast.GT,
+	ast.GT: ast.LT,
+	ast.LE: ast.GE,
+	ast.GE: ast.LE,
+}
+
+// NormalizeExpr returns the canonical text of the expression. Expressions which always
+// produce the same result for the same input rows are normalized to the same text, so:
+// 1. constants, including the parameters of the plan cache, are replaced by '?';
+// 2. the arguments of commutative functions are sorted, and nested AND/OR are flattened;
+// 3. collations are reduced to the ones which actually decide the result, e.g.
+// utf8_bin and utf8mb4_bin are the same, and all of them are ignored if the new
+// collations are not enabled.
+func NormalizeExpr(expr Expression) string {
+	switch x := expr.(type) {
+	case *Constant:
+		return "?"
+	case *CorrelatedColumn:
+		return "corr(" + normalizeColumn(&x.Column) + ")"
+	case *Column:
+		return normalizeColumn(x)
+	case *ScalarFunction:
+		return normalizeScalarFunc(x)
+	}
+	return expr.ExplainNormalizedInfo()
+}
+
+// ExprDigest returns the canonical text of the expression and its digest. The digest is
+// stable across statements and sessions, so it can be used as the key to dedup or cache
+// the expressions.
+func ExprDigest(expr Expression) (normalized, digest string) {
+	normalized = NormalizeExpr(expr)
+	return normalized, fmt.Sprintf("%x", sha256.Sum256([]byte(normalized)))
+}
+
+// ExprsDigest is same like ExprDigest, but it's used for a list of conditions in CNF, the
+// order of the conditions doesn't affect the result.
+func ExprsDigest(exprs []Expression) (normalized, digest string) {
+	items := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		items = append(items, NormalizeExpr(expr))
+	}
+	sort.Strings(items)
+	normalized = strings.Join(items, ", ")
+	return normalized, fmt.Sprintf("%x", sha256.Sum256([]byte(normalized)))
+}
+
+func normalizeColumn(col *Column) string {
+	if col.OrigName != "" {
+		return strings.ToLower(col.OrigName)
+	}
+	// The column is generated by the planner, the unique id is the only thing can identify it.
+	return fmt.Sprintf("#%d", col.UniqueID)
+}
+
+func normalizeScalarFunc(sf *ScalarFunction) string {
+	name := sf.FuncName.L
+	args := sf.GetArgs()
+	switch name {
+	case ast.LogicAnd:
+		args = SplitCNFItems(sf)
+	case ast.LogicOr:
+		args = SplitDNFItems(sf)
+	}
+	items := make([]string, 0, len(args))
+	for _, arg := range args {
+		items = append(items, NormalizeExpr(arg))
+	}
+	if _, ok := commutativeFuncs[name]; ok {
+		sort.Strings(items)
+	} else if symmetric, ok := symmetricFuncs[name]; ok && len(items) == 2 && items[0] > items[1] {
+		name = symmetric
+		items[0], items[1] = items[1], items[0]
+	}
+
+	var builder strings.Builder
+	builder.WriteString(name)
+	builder.WriteString("(")
+	builder.WriteString(strings.Join(items, ", "))
+	if name == ast.Cast {
+		builder.WriteString(", ")
+		builder.WriteString(sf.RetType.InfoSchemaStr())
+	}
+	builder.WriteString(")")
+	if coll := normalizeCollation(sf); coll != "" {
+		builder.WriteString(" collate ")
+		builder.WriteString(coll)
+	}
+	return builder.String()
+}
+
+// normalizeCollation returns the collation which decides the result of the function, it
+// returns an empty string if the collation doesn't matter.
+func normalizeCollation(sf *ScalarFunction) string {
+	if !collate.NewCollationEnabled() {
+		return ""
+	}
+	involveString := sf.RetType.EvalType() == types.ETString
+	for _, arg := range sf.GetArgs() {
+		involveString = involveString || arg.GetType().EvalType() == types.ETString
+	}
+	if !involveString {
+		return ""
+	}
+	_, coll := sf.CharsetAndCollation(nil)
+	switch {
+	case collate.IsBinCollation(coll):
+		return "bin"
+	case coll == "utf8_general_ci" || coll == "utf8mb4_general_ci":
+		return "general_ci"
+	case coll == "utf8_unicode_ci" || coll == "utf8mb4_unicode_ci":
+		return "unicode_ci"
+	}
+	return coll
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/collate"
+)
+
+var _ = SerialSuites(&testDigestSuite{})
+
+type testDigestSuite struct{}
+
+func newNamedColumn(id int, name string, tp *types.FieldType) *Column {
+	col := newColumnWithType(id, tp)
+	col.OrigName = name
+	return col
+}
+
+func newStringColumn(id int, name string, coll string) *Column {
+	tp := types.NewFieldType(mysql.TypeVarchar)
+	tp.Charset, tp.Collate = "utf8mb4", coll
+	return newNamedColumn(id, name, tp)
+}
+
+func (s *testDigestSuite) TestExprDigest(c *C) {
+	a := newNamedColumn(1, "test.t.a", types.NewFieldType(mysql.TypeLonglong))
+	b := newNamedColumn(2, "test.t.B", types.NewFieldType(mysql.TypeLonglong))
+	x := newFunction(ast.EQ, a, newLonglong(1))
+	y := newFunction(ast.GT, b, newLonglong(2))
+	z := newFunction(ast.IsNull, a)
+
+	tests := []struct {
+		lhs   Expression
+		rhs   Expression
+		equal bool
+	}{
+		{newFunction(ast.EQ, a, newLonglong(1)), newFunction(ast.EQ, newLonglong(2), a), true},
+		{newFunction(ast.LT, a, b), newFunction(ast.GT, b, a), true},
+		{newFunction(ast.LE, a, b), newFunction(ast.LT, a, b), false},
+		{newFunction(ast.Minus, a, b), newFunction(ast.Minus, b, a), false},
+		{newFunction(ast.Plus, a, b), newFunction(ast.Plus, b, a), true},
+		{
+			newFunction(ast.LogicAnd, newFunction(ast.LogicAnd, x, y), z),
+			newFunction(ast.LogicAnd, z, newFunction(ast.LogicAnd, y, x)),
+			true,
+		},
+		{newFunction(ast.LogicAnd, x, y), newFunction(ast.LogicOr, x, y), false},
+		{newFunction(ast.EQ, a, b), newFunction(ast.EQ, a, newNamedColumn(3, "test.t.b", types.NewFieldType(mysql.TypeLonglong))), true},
+	}
+	for i, tt := range tests {
+		lhs, lhsDigest := ExprDigest(tt.lhs)
+		rhs, rhsDigest := ExprDigest(tt.rhs)
+		c.Assert(lhsDigest == rhsDigest, Equals, tt.equal, Commentf("case %d: %s, %s", i, lhs, rhs))
+		c.Assert(lhs == rhs, Equals, tt.equal, Commentf("case %d: %s, %s", i, lhs, rhs))
+	}
+
+	normalized, _ := ExprDigest(newFunction(ast.GT, newLonglong(1), a))
+	c.Assert(normalized, Equals, "gt(?, test.t.a)")
+	normalized, _ = ExprDigest(newFunction(ast.LT, a, newLonglong(1)))
+	c.Assert(normalized, Equals, "gt(?, test.t.a)")
+
+	_, digest1 := ExprsDigest([]Expression{x, y, z})
+	_, digest2 := ExprsDigest([]Expression{z, y, x})
+	c.Assert(digest1, Equals, digest2)
+	_, digest3 := ExprsDigest([]Expression{x, y})
+	c.Assert(digest1, Not(Equals), digest3)
+}
+
+func (s *testDigestSuite) TestExprDigestCollation(c *C) {
+	eq := func(coll1, coll2 string) Expression {
+		return newFunction(ast.EQ, newStringColumn(1, "test.t.a", coll1), newStringColumn(2, "test.t.b", coll2))
+	}
+	digest := func(expr Expression) string {
+		_, d := ExprDigest(expr)
+		return d
+	}
+
+	c.Assert(digest(eq("utf8mb4_bin", "utf8mb4_bin")), Equals, digest(eq("utf8mb4_general_ci", "utf8mb4_general_ci")))
+
+	collate.SetNewCollationEnabledForTest(true)
+	defer collate.SetNewCollationEnabledForTest(false)
+	c.Assert(digest(eq("utf8mb4_bin", "utf8mb4_bin")), Equals, digest(eq("utf8_bin", "utf8_bin")))
+	c.Assert(digest(eq("utf8mb4_general_ci", "utf8mb4_general_ci")), Equals, digest(eq("utf8_general_ci", "utf8_general_ci")))
+	c.Assert(digest(eq("utf8mb4_bin", "utf8mb4_bin")), Not(Equals), digest(eq("utf8mb4_general_ci", "utf8mb4_general_ci")))
+	normalized, _ := ExprDigest(eq("utf8mb4_general_ci", "utf8mb4_general_ci"))
+	c.Assert(normalized, Equals, "eq(test.t.a, test.t.b) collate general_ci")
+
+	// The collation doesn't affect the functions which don't handle strings.
+	intEQ := newFunction(ast.EQ, newColumn(1), newLonglong(1))
+	normalized, _ = ExprDigest(intEQ)
+	c.Assert(normalized, Equals, "eq(#1, ?)")
+}
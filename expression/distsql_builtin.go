@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/encoding"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tipb/go-tipb"
 )
@@ -936,7 +937,11 @@ func getSignatureByPB(ctx sessionctx.Context, sigCode tipb.ScalarFuncSig, tp *ti
 	case tipb.ScalarFuncSig_ConcatWS:
 		f = &builtinConcatWSSig{base, maxAllowedPacket}
 	case tipb.ScalarFuncSig_Convert:
-		f = &builtinConvertSig{base}
+		conv, err := encoding.NewConverter(convertSourceCharset(args[0].GetType()), fieldTp.Charset)
+		if err != nil {
+			return nil, err
+		}
+		f = &builtinConvertSig{base, conv}
 	case tipb.ScalarFuncSig_Elt:
 		f = &builtinEltSig{base}
 	case tipb.ScalarFuncSig_ExportSet3Arg:
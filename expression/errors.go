@@ -47,6 +47,8 @@ var (
 	errTruncatedWrongValue           = dbterror.ClassExpression.NewStd(mysql.ErrTruncatedWrongValue)
 	errUnknownLocale                 = dbterror.ClassExpression.NewStd(mysql.ErrUnknownLocale)
 	errNonUniq                       = dbterror.ClassExpression.NewStd(mysql.ErrNonUniq)
+	errImplicitCast                  = dbterror.ClassExpression.NewStdErr(mysql.ErrUnknown, pmysql.Message("Implicit cast: %s", nil))
+	errWarnSimilarityArgTooLong      = dbterror.ClassExpression.NewStdErr(mysql.ErrUnknown, pmysql.Message("Arguments of %s() longer than %d characters are not supported, NULL is returned", nil))
 
 	// Sequence usage privilege check.
 	errSequenceAccessDenied      = dbterror.ClassExpression.NewStd(mysql.ErrTableaccessDenied)
@@ -781,11 +781,13 @@ func SplitDNFItems(onExpr Expression) []Expression {
 
 // EvaluateExprWithNull sets columns in schema as null and calculate the final result of the scalar function.
 // If the Expression is a non-constant value, it means the result is unknown.
+// The plan only depends on the mutable constants if they are left in the result, the ones folded away don't matter.
 func EvaluateExprWithNull(ctx sessionctx.Context, schema *Schema, expr Expression) Expression {
-	if ContainMutableConst(ctx, []Expression{expr}) {
+	result := evaluateExprWithNull(ctx, schema, expr)
+	if ContainMutableConst(ctx, []Expression{result}) {
 		ctx.GetSessionVars().StmtCtx.OptimDependOnMutableConst = true
 	}
-	return evaluateExprWithNull(ctx, schema, expr)
+	return result
 }
 
 func evaluateExprWithNull(ctx sessionctx.Context, schema *Schema, expr Expression) Expression {
@@ -48,6 +48,10 @@ var unFoldableFunctions = map[string]struct{}{
 	ast.NextVal:   {},
 	ast.LastVal:   {},
 	ast.SetVal:    {},
+	tidbMVCCInfo:  {},
+
+	// The result of VALIDATE_PASSWORD_STRENGTH depends on the password policy, which can be changed at any time.
+	ast.ValidatePasswordStrength: {},
 }
 
 // DisableFoldFunctions stores functions which prevent child scope functions from being constant folded.
@@ -178,6 +182,28 @@ var AllowedPartition4UnaryOpMap = map[opcode.Op]struct{}{
 	opcode.Minus: {},
 }
 
+// nullStrictFunctions stores functions which always return NULL if any of their arguments is NULL, so they can be
+// folded to NULL without evaluating the other arguments, which may be deferred to the execution.
+var nullStrictFunctions = map[string]struct{}{
+	ast.LT:         {},
+	ast.LE:         {},
+	ast.GT:         {},
+	ast.GE:         {},
+	ast.EQ:         {},
+	ast.NE:         {},
+	ast.Plus:       {},
+	ast.Minus:      {},
+	ast.Mul:        {},
+	ast.Div:        {},
+	ast.IntDiv:     {},
+	ast.Mod:        {},
+	ast.UnaryMinus: {},
+	ast.DateAdd:    {},
+	ast.DateSub:    {},
+	ast.AddDate:    {},
+	ast.SubDate:    {},
+}
+
 // inequalFunctions stores functions which cannot be propagated from column equal condition.
 var inequalFunctions = map[string]struct{}{
 	ast.IsNull: {},
@@ -0,0 +1,126 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"fmt"
+
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// recordImplicitCasts records the implicit casts of the compared non-constant arguments when
+// tidb_enable_implicit_cast_diagnostics is on. Such casts usually prevent the indexes on the arguments from being
+// used, so every cast is reported once per statement as a note and in the slow log.
+func recordImplicitCasts(ctx sessionctx.Context, funcName string, args []Expression, cmpType types.EvalType) {
+	vars := ctx.GetSessionVars()
+	if !vars.EnableImplicitCastDiagnostics || len(args) != 2 {
+		return
+	}
+	sc := vars.StmtCtx
+	for i, arg := range args {
+		if _, ok := arg.(*Constant); ok {
+			continue
+		}
+		event := implicitCastEvent(arg, cmpType)
+		if len(event) == 0 {
+			if con, ok := args[1-i].(*Constant); ok {
+				event = temporalTruncationEvent(sc, arg, con, cmpType)
+			}
+		}
+		if len(event) == 0 {
+			continue
+		}
+		event = fmt.Sprintf("%s in %s(%s, %s)", event, funcName, args[0], args[1])
+		if sc.AppendImplicitCast(event) {
+			sc.AppendNote(errImplicitCast.GenWithStackByArgs(event))
+		}
+	}
+}
+
+// implicitCastEvent describes the cast of arg if it's compared as a different kind of type, e.g. a string column
+// compared as a number.
+func implicitCastEvent(arg Expression, cmpType types.EvalType) string {
+	tp := arg.GetType()
+	if tp.Hybrid() {
+		return ""
+	}
+	from, to := evalTypeKind(tp.EvalType()), evalTypeKind(cmpType)
+	if from == to {
+		return ""
+	}
+	return fmt.Sprintf("%s %s (%s) is cast to %s", exprKind(arg), arg, tp.CompactStr(), to)
+}
+
+// temporalTruncationEvent describes the truncation of the constant if it's compared with a temporal argument whose
+// type can't hold the constant, e.g. a datetime constant with the time part compared with a date column.
+func temporalTruncationEvent(sc *stmtctx.StatementContext, arg Expression, con *Constant, cmpType types.EvalType) string {
+	tp := arg.GetType()
+	if !types.IsTypeTime(tp.Tp) || (cmpType != types.ETDatetime && cmpType != types.ETTimestamp) ||
+		con.ParamMarker != nil || con.DeferredExpr != nil {
+		return ""
+	}
+	val, err := con.Eval(chunk.Row{})
+	if err != nil || val.IsNull() {
+		return ""
+	}
+	// Convert the values with a separated statement context, the warnings are not a part of the statement.
+	convSc := &stmtctx.StatementContext{TimeZone: sc.TimeZone}
+	fullTp := types.NewFieldType(mysql.TypeDatetime)
+	fullTp.Decimal = int(types.MaxFsp)
+	full, err := val.ConvertTo(convSc, fullTp)
+	if err != nil {
+		return ""
+	}
+	casted, err := full.ConvertTo(convSc, tp)
+	if err != nil {
+		return ""
+	}
+	if cmp, err := full.CompareDatum(convSc, &casted); err != nil || cmp == 0 {
+		return ""
+	}
+	fullStr, err := full.ToString()
+	if err != nil {
+		return ""
+	}
+	castedStr, err := casted.ToString()
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("constant %s is truncated to %s for %s %s (%s)", fullStr, castedStr, exprKind(arg), arg, tp.CompactStr())
+}
+
+// evalTypeKind groups the eval types whose values can be compared without changing their representation.
+func evalTypeKind(et types.EvalType) string {
+	switch et {
+	case types.ETInt, types.ETReal, types.ETDecimal:
+		return "number"
+	case types.ETDatetime, types.ETTimestamp, types.ETDuration:
+		return "time"
+	case types.ETJson:
+		return "json"
+	default:
+		return "string"
+	}
+}
+
+func exprKind(expr Expression) string {
+	if _, ok := expr.(*Column); ok {
+		return "column"
+	}
+	return "expression"
+}
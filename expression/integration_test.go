@@ -293,6 +293,111 @@ func (s *testIntegrationSuite) TestMiscellaneousBuiltin(c *C) {
 	result.Check(testkit.Rows("1"))
 }
 
+func (s *testIntegrationSuite) TestUUIDBuiltin(c *C) {
+	defer s.cleanEnv(c)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustQuery("select hex(uuid_to_bin('6ccd780c-baba-1026-9564-5b8c656024db')), hex(uuid_to_bin('6ccd780c-baba-1026-9564-5b8c656024db', 1))").Check(testkit.Rows(
+		"6CCD780CBABA102695645B8C656024DB 1026BABA6CCD780C95645B8C656024DB"))
+	tk.MustQuery("select bin_to_uuid(unhex('6CCD780CBABA102695645B8C656024DB')), bin_to_uuid(unhex('1026BABA6CCD780C95645B8C656024DB'), 1)").Check(testkit.Rows(
+		"6ccd780c-baba-1026-9564-5b8c656024db 6ccd780c-baba-1026-9564-5b8c656024db"))
+	tk.MustQuery("select bin_to_uuid(uuid_to_bin('{6CCD780C-BABA-1026-9564-5B8C656024DB}', 1), 1), uuid_to_bin(null), bin_to_uuid(null, 1)").Check(testkit.Rows(
+		"6ccd780c-baba-1026-9564-5b8c656024db <nil> <nil>"))
+	tk.MustQuery("select is_uuid('6ccd780c-baba-1026-9564-5b8c656024db'), is_uuid('6ccd780cbaba102695645b8c656024db'), is_uuid('6ccd780c-baba-1026'), is_uuid(null)").Check(testkit.Rows(
+		"1 1 0 <nil>"))
+	tk.MustQuery("select is_uuid(uuid()), bin_to_uuid(uuid_to_bin(uuid(), 1), 1) regexp '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$'").Check(testkit.Rows("1 1"))
+	err := tk.QueryToErr("select uuid_to_bin('6ccd780c-baba-1026')")
+	c.Assert(err, ErrorMatches, ".*Incorrect string value: '6ccd780c-baba-1026' for function uuid_to_bin")
+	err = tk.QueryToErr("select bin_to_uuid('abc')")
+	c.Assert(err, ErrorMatches, ".*Incorrect string value: 'abc' for function bin_to_uuid")
+	tk.MustGetErrCode("select is_uuid()", mysql.ErrWrongParamcountToNativeFct)
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id binary(16) primary key, id2 varbinary(16), key(id2))")
+	tk.MustExec("insert into t values(uuid_to_bin('6ccd780c-baba-1026-9564-5b8c656024db', 1), uuid_to_bin('6ccd780c-baba-1026-9564-5b8c656024db'))")
+	tk.MustExec("insert into t values(uuid_to_bin('11111111-2222-3333-4444-555555555555', 1), uuid_to_bin('11111111-2222-3333-4444-555555555555'))")
+	tk.MustQuery("select bin_to_uuid(id, 1), bin_to_uuid(id2) from t order by id").Check(testkit.Rows(
+		"6ccd780c-baba-1026-9564-5b8c656024db 6ccd780c-baba-1026-9564-5b8c656024db",
+		"11111111-2222-3333-4444-555555555555 11111111-2222-3333-4444-555555555555"))
+	tk.MustQuery("select bin_to_uuid(id2) from t where bin_to_uuid(id, 1) = '6ccd780c-baba-1026-9564-5b8c656024db'").Check(testkit.Rows(
+		"6ccd780c-baba-1026-9564-5b8c656024db"))
+	tk.MustQuery("select bin_to_uuid(id, 1) from t where '11111111-2222-3333-4444-555555555555' = bin_to_uuid(id2)").Check(testkit.Rows(
+		"11111111-2222-3333-4444-555555555555"))
+	tk.MustQuery("select count(*) from t where bin_to_uuid(id2) != '11111111-2222-3333-4444-555555555555'").Check(testkit.Rows("1"))
+	// BIN_TO_UUID never returns the uppercase or undashed UUIDs, so these comparisons are always false.
+	tk.MustQuery("select count(*) from t where bin_to_uuid(id2) = '11111111-2222-3333-4444-55555555555A' or bin_to_uuid(id2) = '11111111222233334444555555555555'").Check(testkit.Rows("0"))
+}
+
+func (s *testIntegrationSuite) TestCharsetConversion(c *C) {
+	defer s.cleanEnv(c)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	// The binary strings are decoded in the target character set, the invalid bytes are replaced by '?'.
+	tk.MustQuery("select convert(0xe4b8ade69687 using utf8mb4), convert(0x61ff62 using utf8mb4), convert(0xf09f9880 using utf8), convert(0xff using latin1) = 0xff").Check(testkit.Rows(
+		"中文 a?b ? 1"))
+	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|",
+		"Warning|1300|Invalid utf8mb4 character string: 'FF'",
+		"Warning|1300|Invalid utf8 character string: 'F09F9880'"))
+	// The non-binary strings are checked to be representable in the target character set.
+	tk.MustQuery("select convert('中文a' using ascii), convert('a😀' using utf8), convert('a😀' using utf8mb4), convert('中文' using binary)").Check(testkit.Rows(
+		"??a a? a😀 中文"))
+	tk.MustQuery("select cast('中文a' as char character set ascii), cast(0xe4b8ad61ff as char character set utf8mb4), cast('a😀' as char)").Check(testkit.Rows(
+		"??a 中a? a😀"))
+	tk.MustQuery("select _utf8mb4 0xe4b8ad, _utf8mb4 0x61ff62, _ascii 0x61e4b8ad, _binary 0xe4b8ad").Check(testkit.Rows(
+		"中 a?b a? 中"))
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a varchar(10) charset utf8mb4, b varbinary(10))")
+	tk.MustExec("insert into t values('a😀', 0x61ff), ('中', 0xe4b8ad)")
+	tk.MustQuery("select convert(a using utf8), convert(a using ascii), convert(b using utf8mb4) from t order by b").Check(testkit.Rows(
+		"a? a? a?",
+		"中 ? 中"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(4))
+
+	tk.MustExec("set @@tidb_invalid_char_handling = 'ERROR'")
+	tk.MustQuery("select convert(a using utf8mb4), convert(b using utf8mb4) from t where b = 0xe4b8ad").Check(testkit.Rows("中 中"))
+	err := tk.QueryToErr("select convert(b using utf8mb4) from t")
+	c.Assert(err, ErrorMatches, ".*Invalid utf8mb4 character string: 'FF'")
+	err = tk.QueryToErr("select convert(a using ascii) from t")
+	c.Assert(err, ErrorMatches, ".*Invalid ascii character string: 'F09F9880'")
+	err = tk.QueryToErr("select convert(0xff using utf8mb4)")
+	c.Assert(err, ErrorMatches, ".*Invalid utf8mb4 character string: 'FF'")
+	err = tk.ExecToErr("select _utf8mb4 0xff")
+	c.Assert(err, ErrorMatches, ".*Invalid utf8mb4 character string: 'FF'")
+	tk.MustExec("set @@tidb_invalid_char_handling = default")
+	tk.MustGetErrCode("set @@tidb_invalid_char_handling = 'IGNORE'", mysql.ErrWrongValueForVar)
+}
+
+func (s *testIntegrationSuite) TestImplicitCastDiagnostics(c *C) {
+	defer s.cleanEnv(c)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a varchar(20), b int, d date, key(a), key(d))")
+	tk.MustExec("create table t2(a int, b varchar(20))")
+
+	// The diagnostics is disabled by default.
+	tk.MustQuery("select * from t1 where a = 1")
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+
+	tk.MustExec("set @@tidb_enable_implicit_cast_diagnostics = 1")
+	tk.MustQuery("select * from t1 where a = 1")
+	tk.MustQuery("show warnings").Check(testkit.Rows(
+		"Note 1105 Implicit cast: column test.t1.a (varchar(20)) is cast to number in eq(test.t1.a, 1)"))
+	tk.MustQuery("select * from t1 where b = '1'")
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+	tk.MustQuery("select * from t1, t2 where t1.a = t2.a and t1.b = t2.b")
+	tk.MustQuery("show warnings").Check(testkit.Rows(
+		"Note 1105 Implicit cast: column test.t1.a (varchar(20)) is cast to number in eq(test.t1.a, test.t2.a)",
+		"Note 1105 Implicit cast: column test.t2.b (varchar(20)) is cast to number in eq(test.t1.b, test.t2.b)"))
+	tk.MustQuery("select * from t1 where d = '2021-01-01 10:00:00'")
+	tk.MustQuery("show warnings").Check(testkit.Rows(
+		"Note 1105 Implicit cast: constant 2021-01-01 10:00:00.000000 is truncated to 2021-01-01 for column test.t1.d (date) in eq(test.t1.d, 2021-01-01 10:00:00)"))
+	tk.MustQuery("select * from t1 where d = '2021-01-01'")
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetImplicitCasts(), HasLen, 0)
+}
+
 func (s *testIntegrationSuite) TestConvertToBit(c *C) {
 	defer s.cleanEnv(c)
 	tk := testkit.NewTestKit(c, s.store)
@@ -3927,6 +4032,32 @@ func (s *testIntegrationSuite) TestAggregationBuiltinBitAnd(c *C) {
 	result.Check(testkit.Rows("7 7", "5 5", "3 3", "2 2", "<nil> 18446744073709551615"))
 }
 
+func (s *testIntegrationSuite) TestAggregationBuiltinBitFuncsUnsigned(c *C) {
+	defer s.cleanEnv(c)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t1;")
+	tk.MustExec("create table t(id int, u bigint unsigned, d decimal(30, 0), f double, s varchar(30))")
+	tk.MustExec("insert into t values(1, 18446744073709551615, 18446744073709551615, 18446744073709551615, '18446744073709551615')")
+	tk.MustExec("insert into t values(2, 9223372036854775808, 9223372036854775808, 9223372036854775808, '9223372036854775808')")
+	tk.MustExec("insert into t values(3, null, null, null, null)")
+	for _, col := range []string{"u", "d", "f", "s"} {
+		tk.MustQuery(fmt.Sprintf("select bit_and(%[1]s), bit_or(%[1]s), bit_xor(%[1]s) from t", col)).Check(
+			testkit.Rows("9223372036854775808 18446744073709551615 9223372036854775807"))
+	}
+	tk.MustQuery("select bit_and(d), bit_or(-1.0), bit_xor(-1) from t where id = 1").Check(
+		testkit.Rows("18446744073709551615 18446744073709551615 18446744073709551615"))
+
+	// The bit functions are decomposed across the union and the join.
+	tk.MustExec("set @@tidb_opt_agg_push_down = 1")
+	tk.MustQuery("select bit_and(u), bit_or(u), bit_xor(u) from (select u from t union all select u from t where id = 1) tt").Check(
+		testkit.Rows("9223372036854775808 18446744073709551615 9223372036854775808"))
+	tk.MustExec("create table t1(id int)")
+	tk.MustExec("insert into t1 values(1), (1), (2), (4)")
+	tk.MustQuery("select t1.id, bit_and(t.u), bit_or(t.d) from t1 left join t on t1.id = t.id group by t1.id order by t1.id").Check(
+		testkit.Rows("1 18446744073709551615 18446744073709551615", "2 9223372036854775808 9223372036854775808", "4 18446744073709551615 0"))
+}
+
 func (s *testIntegrationSuite) TestAggregationBuiltinGroupConcat(c *C) {
 	defer s.cleanEnv(c)
 	tk := testkit.NewTestKit(c, s.store)
@@ -4410,6 +4541,17 @@ func (s *testIntegrationSuite) TestFuncJSON(c *C) {
 	tk.MustExec(`update table_json set a=json_set(a,'$.a',json_object('a',1,'b',2)) where json_extract(a,'$.a[1]') = '2'`)
 	r = tk.MustQuery(`select json_extract(a, '$.a.a'), json_extract(a, '$.a.b') from table_json`)
 	r.Check(testkit.Rows("1 2", "<nil> <nil>"))
+	// The functions modifying the JSON can't take wildcards or array ranges, same as MySQL.
+	for _, sql := range []string{
+		`select json_set('{"a": 1}', '$.*', 2)`,
+		`select json_replace('[1, 2]', '$[0 to 1]', 2)`,
+		`select json_remove('{"a": {"b": 1}}', '$**.b')`,
+	} {
+		err := tk.QueryToErr(sql)
+		c.Assert(err, NotNil)
+		c.Assert(err.Error(), Equals, "[json:3149]In this situation, path expressions may not contain the * and ** tokens.")
+	}
+	tk.MustQuery(`select json_set('[1, 2]', '$[last]', 3), json_remove('[1, 2]', '$[last]')`).Check(testkit.Rows("[1, 3] [1]"))
 
 	r = tk.MustQuery(`select json_contains(NULL, '1'), json_contains('1', NULL), json_contains('1', '1', NULL)`)
 	r.Check(testkit.Rows("<nil> <nil> <nil>"))
@@ -4853,6 +4995,69 @@ func (s *testIntegrationSuite) TestTiDBInternalFunc(c *C) {
 	result.Check(testkit.Rows(rs))
 }
 
+func (s *testIntegrationSuite) TestTiDBDecodeIndexValueAndMVCCInfo(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	defer s.cleanEnv(c)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, tp")
+	tk.MustExec("create table t (a varchar(10), b int, c int, primary key (a, b) clustered, unique key uk(c), key k(c))")
+	tk.MustExec("insert into t values ('abc', 1, 100)")
+	tk.MustExec("create table tp (a int, b int, key k(b)) partition by hash(a) partitions 2")
+	tk.MustExec("insert into tp values (1, 10)")
+	is := domain.GetDomain(tk.Se).InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tblInfo := tbl.Meta()
+	ptbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("tp"))
+	c.Assert(err, IsNil)
+	ptblInfo := ptbl.Meta()
+	pid := ptblInfo.Partition.Definitions[1].ID
+
+	// getIndexKV gets the only index entry of the index from the storage.
+	getIndexKV := func(physicalID int64, idxInfo *model.IndexInfo) (string, string) {
+		txn, err := s.store.Begin()
+		c.Assert(err, IsNil)
+		defer func() { c.Assert(txn.Rollback(), IsNil) }()
+		prefix := tablecodec.EncodeTableIndexPrefix(physicalID, idxInfo.ID)
+		it, err := txn.Iter(prefix, prefix.PrefixNext())
+		c.Assert(err, IsNil)
+		defer it.Close()
+		c.Assert(it.Valid(), IsTrue)
+		return hex.EncodeToString(it.Key()), hex.EncodeToString(it.Value())
+	}
+
+	// The unique index stores the common handle in the value.
+	key, value := getIndexKV(tblInfo.ID, tblInfo.Indices[1])
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_index_value('%s', '%s')", key, value)).Check(testkit.Rows(
+		fmt.Sprintf(`{"handle":{"a":"abc","b":"1"},"index_id":%d,"index_vals":{"c":"100"},"table_id":%d}`, tblInfo.Indices[1].ID, tblInfo.ID)))
+	// The non-unique index stores the common handle in the key.
+	key, value = getIndexKV(tblInfo.ID, tblInfo.Indices[2])
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_index_value('%s', '%s')", key, value)).Check(testkit.Rows(
+		fmt.Sprintf(`{"handle":{"a":"abc","b":"1"},"index_id":%d,"index_vals":{"c":"100"},"table_id":%d}`, tblInfo.Indices[2].ID, tblInfo.ID)))
+
+	// The keys of the partitions are decoded with the partitioned table.
+	key, value = getIndexKV(pid, ptblInfo.Indices[0])
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_index_value('%s', '%s')", key, value)).Check(testkit.Rows(
+		fmt.Sprintf(`{"handle":1,"index_id":%d,"index_vals":{"b":"10"},"partition_id":%d,"table_id":%d}`, ptblInfo.Indices[0].ID, pid, ptblInfo.ID)))
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_key('%s')", key)).Check(testkit.Rows(
+		fmt.Sprintf(`{"index_id":%d,"index_vals":{"b":"10"},"partition_id":%d,"table_id":%d}`, ptblInfo.Indices[0].ID, pid, ptblInfo.ID)))
+	recordKey := hex.EncodeToString(tablecodec.EncodeRowKeyWithHandle(pid, kv.IntHandle(1)))
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_key('%s')", recordKey)).Check(testkit.Rows(
+		fmt.Sprintf(`{"_tidb_rowid":1,"partition_id":%d,"table_id":%d}`, pid, ptblInfo.ID)))
+
+	// Test invalid index key and value.
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_index_value('%s', 'zz')", key)).Check(testkit.Rows("zz"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 invalid index value: zz"))
+	tk.MustQuery(fmt.Sprintf("select tidb_decode_index_value('%s', '%s')", recordKey, value)).Check(testkit.Rows(value))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
+
+	// Test the MVCC info.
+	result := tk.MustQuery(fmt.Sprintf("select tidb_mvcc_info('%s')", recordKey)).Rows()[0][0].(string)
+	c.Assert(strings.HasPrefix(result, fmt.Sprintf(`{"key":"%s","mvcc":{"writes":[{`, strings.ToUpper(recordKey))), IsTrue, Commentf("%s", result))
+	tk.MustQuery("select tidb_mvcc_info('zz')").Check(testkit.Rows("zz"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 invalid key: zz"))
+}
+
 func newStoreWithBootstrap() (kv.Storage, *domain.Domain, error) {
 	store, err := mockstore.NewMockStore()
 	if err != nil {
@@ -9183,3 +9388,139 @@ func (s *testIntegrationSuite) TestRefineArgNullValues(c *C) {
 		"<nil>",
 	))
 }
+
+func (s *testIntegrationSuite) TestStringSimilarityBuiltin(c *C) {
+	defer s.cleanEnv(c)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int primary key, name varchar(20))")
+	tk.MustExec("insert into t values(1, 'Robert'), (2, 'Rupert'), (3, 'Rubin'), (4, null)")
+	tk.MustQuery("select id, soundex(name), levenshtein(name, 'Robert'), round(jaro_winkler(name, 'Robert'), 3) from t order by id").Check(testkit.Rows(
+		"1 R163 0 1",
+		"2 R163 2 0.8",
+		"3 R150 4 0.62",
+		"4 <nil> <nil> <nil>",
+	))
+	tk.MustQuery("select id from t where soundex(name) = soundex('Robbert') order by id").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select levenshtein('数据库', '数据'), levenshtein(binary '数据库', binary '数据'), soundex('Quadratically')").Check(testkit.Rows("1 3 Q36324"))
+}
+
+func (s *testIntegrationSuite) TestMaskBuiltin(c *C) {
+	defer s.cleanEnv(c)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int primary key, email varchar(64), card char(19))")
+	tk.MustExec("insert into t values(1, 'alice@example.com', '4111-1111-1111-1234'), (2, 'bob', '1234'), (3, null, null)")
+	tk.MustQuery("select id, mask_email(email), mask_pan(card), mask_inner(email, 2, 4), mask_inner(card, 0, 4, '*') from t order by id").Check(testkit.Rows(
+		"1 aXXXX@example.com XXXX-XXXX-XXXX-1234 alXXXXXXXXXXX.com ***************1234",
+		"2 XXX 1234 bob 1234",
+		"3 <nil> <nil> <nil> <nil>",
+	))
+	tk.MustQuery("select mask_inner('数据库管理', 1, 1, '某'), mask_inner(binary '数据', 1, 1)").Check(testkit.Rows("数某某某理 \xe6XXXX\xae"))
+	err := tk.QueryToErr("select mask_inner('abc', -1, 0)")
+	c.Assert(err, ErrorMatches, ".*The margins of mask_inner can't be negative")
+	err = tk.QueryToErr("select mask_inner(email, 0, 0, 'ab') from t")
+	c.Assert(err, ErrorMatches, ".*The mask character of mask_inner must be a single character")
+	tk.MustGetErrCode("select mask_pan('a', 'b')", mysql.ErrWrongParamcountToNativeFct)
+}
+
+func (s *testIntegrationSerialSuite) TestStringSimilarityCollation(c *C) {
+	collate.SetNewCollationEnabledForTest(true)
+	defer collate.SetNewCollationEnabledForTest(false)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustQuery("select levenshtein('Straße', 'STRASSE' collate utf8mb4_general_ci), levenshtein('abc', 'ABC' collate utf8mb4_general_ci), levenshtein('abc', 'ABC' collate utf8mb4_bin)").Check(testkit.Rows("1 0 3"))
+	tk.MustQuery("select jaro_winkler('résumé', 'RESUME' collate utf8mb4_unicode_ci), jaro_winkler('résumé', 'resume' collate utf8mb4_bin) < 1").Check(testkit.Rows("1 1"))
+}
+
+func (s *testIntegrationSuite) TestSpatialBuiltin(c *C) {
+	defer s.cleanEnv(c)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int primary key, g blob)")
+	tk.MustExec("insert into t values(1, st_geomfromtext('POINT(1 2)')), (2, st_geomfromtext('POINT(20 20)')), (3, null)")
+	tk.MustQuery("select id, st_astext(g), st_x(g), st_y(g) from t order by id").Check(testkit.Rows(
+		"1 POINT(1 2) 1 2",
+		"2 POINT(20 20) 20 20",
+		"3 <nil> <nil> <nil>",
+	))
+	tk.MustQuery("select id from t where st_contains(st_geomfromtext('POLYGON((0 0,10 0,10 10,0 10,0 0))'), g)").Check(testkit.Rows("1"))
+	tk.MustQuery("select id, st_distance(g, st_geomfromtext('POINT(4 6)')) from t order by id").Check(testkit.Rows(
+		"1 5",
+		"2 21.2602916254693",
+		"3 <nil>",
+	))
+	tk.MustQuery("select st_aswkt(st_geometryfromtext('LINESTRING(0 0, 1 1)', 4326))").Check(testkit.Rows("LINESTRING(0 0,1 1)"))
+	err := tk.QueryToErr("select st_geomfromtext('POINT(1)')")
+	c.Assert(err.Error(), Equals, "[types:3037]Invalid GIS data provided to function st_geomfromtext.")
+	err = tk.QueryToErr("select st_distance(st_geomfromtext('POINT(1 1)'), st_geomfromtext('POINT(1 1)', 4326))")
+	c.Assert(err.Error(), Equals, "[types:3033]Binary geometry function st_distance given two geometries of different srids: 0 and 4326, which should have been identical.")
+	// spatial functions are not pushed down to the storage layer.
+	tk.MustQuery("explain format = 'brief' select id from t where st_x(g) > 1").Check(testkit.Rows(
+		"Projection 8000.00 root  test.t.id",
+		"└─Selection 8000.00 root  gt(st_x(test.t.g), 1)",
+		"  └─TableReader 10000.00 root  data:TableFullScan",
+		"    └─TableFullScan 10000.00 cop[tikv] table:t keep order:false, stats:pseudo",
+	))
+}
+
+func (s *testIntegrationSuite) TestMatchAgainst(c *C) {
+	defer s.cleanEnv(c)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int primary key, title varchar(100), body text)")
+	tk.MustExec("insert into t values (1, 'TiDB Introduction', 'TiDB is a distributed SQL database'), " +
+		"(2, 'MySQL Tutorial', 'DBMS stands for database'), (3, 'Cooking', 'How to cook pasta'), (4, null, null)")
+	tk.MustQuery("select id from t where match(title, body) against('database') order by id").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select id, match(title, body) against('tidb database') from t order by id").Check(testkit.Rows(
+		"1 2.6931471805599454", "2 1", "3 0", "4 0"))
+	tk.MustQuery("select id from t where match(body) against('pasta')").Check(testkit.Rows("3"))
+
+	_, err := tk.Exec("select id from t where match(title) against('tidb' in boolean mode)")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[planner:1235]This version of TiDB doesn't yet support 'MATCH ... AGAINST in BOOLEAN MODE'")
+	_, err = tk.Exec("select id from t where match(title) against(body)")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[expression:1210]Incorrect arguments to AGAINST")
+}
+
+func (s *testIntegrationSuite) TestMatchAgainstFullTextIndex(c *C) {
+	defer s.cleanEnv(c)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int primary key, title varchar(100), body text, fulltext key ft(title, body))")
+	tk.MustExec("insert into t values (1, 'TiDB Introduction', 'TiDB is a distributed SQL database'), " +
+		"(2, 'MySQL Tutorial', 'DBMS stands for database'), (3, 'Cooking', 'How to cook pasta'), (4, null, null)")
+	tk.MustQuery("explain format = 'brief' select id from t where match(title, body) against('database') and id > 1").Check(testkit.Rows(
+		"Projection 2666.67 root  test.t.id",
+		"└─Selection 2666.67 root  match_against(\"database\", test.t.title, test.t.body)",
+		"  └─Selection 3333.33 root  gt(test.t.id, 1)",
+		"    └─FullTextReader 10000.00 root table:t, index:ft(title, body) search:\"database\"",
+	))
+	tk.MustQuery("select id from t where match(title, body) against('database') order by id").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select id from t where match(title, body) against('tidb pasta') order by id").Check(testkit.Rows("1", "3"))
+	// The FULLTEXT index is read only if its columns are exactly the matched ones.
+	tk.MustQuery("select id from t where match(body) against('pasta')").Check(testkit.Rows("3"))
+	tk.MustQuery("select id from t ignore index(ft) where match(title, body) against('pasta')").Check(testkit.Rows("3"))
+
+	tk.MustExec("update t set body = 'How to cook rice' where id = 3")
+	tk.MustExec("delete from t where id = 2")
+	tk.MustQuery("select id from t where match(title, body) against('database pasta rice') order by id").Check(testkit.Rows("1", "3"))
+
+	// The rows written in the transaction are read.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (5, 'Database Design', null)")
+	tk.MustExec("delete from t where id = 1")
+	tk.MustQuery("select id from t where match(title, body) against('database') order by id").Check(testkit.Rows("5"))
+	tk.MustExec("rollback")
+	tk.MustQuery("select id from t where match(title, body) against('database') order by id").Check(testkit.Rows("1"))
+
+	// The index added to a table with rows is backfilled.
+	tk.MustExec("alter table t add fulltext key ft_body(body)")
+	tk.MustQuery("select id from t where match(body) against('distributed rice') order by id").Check(testkit.Rows("1", "3"))
+	tk.MustExec("admin check table t")
+}
@@ -136,12 +136,17 @@ func (b *Builder) ApplyDiff(m *meta.Meta, diff *model.SchemaDiff) ([]int64, erro
 		}
 	}
 	if diff.AffectedOpts != nil {
+		var partitionIDs []int64
 		for _, opt := range diff.AffectedOpts {
 			switch diff.Type {
 			case model.ActionAlterTableAlterPartition:
 				partitionID := opt.TableID
 				// TODO: enhancement: If the leader Placement Policy isn't updated, maybe we can omit the diff.
-				return []int64{partitionID}, b.applyPlacementUpdate(placement.GroupID(partitionID))
+				partitionIDs = append(partitionIDs, partitionID)
+				if err := b.applyPlacementUpdate(placement.GroupID(partitionID)); err != nil {
+					return nil, errors.Trace(err)
+				}
+				continue
 			case model.ActionTruncateTablePartition:
 				// Reduce the impact on DML when executing partition DDL. eg.
 				// While session 1 performs the DML operation associated with partition 1,
@@ -185,6 +190,10 @@ func (b *Builder) ApplyDiff(m *meta.Meta, diff *model.SchemaDiff) ([]int64, erro
 			}
 			tblIDs = append(tblIDs, affectedIDs...)
 		}
+		if diff.Type == model.ActionAlterTableAlterPartition {
+			// Only the transactions on the partitions whose placement changed are affected.
+			return partitionIDs, nil
+		}
 	} else {
 		switch diff.Type {
 		case model.ActionAlterTableAlterPartition:
@@ -432,7 +441,10 @@ func (b *Builder) applyDropTable(dbInfo *model.DBInfo, tableID int64, affected [
 	}
 	if tableNames, ok := b.is.schemaMap[dbInfo.Name.L]; ok {
 		tblInfo := sortedTbls[idx].Meta()
-		delete(tableNames.tables, tblInfo.Name.L)
+		// The name may already be taken by another table renamed in the same diff.
+		if tbl, ok := tableNames.tables[tblInfo.Name.L]; ok && tbl.Meta().ID == tableID {
+			delete(tableNames.tables, tblInfo.Name.L)
+		}
 		affected = appendAffectedIDs(affected, tblInfo)
 	}
 	// Remove the table in sorted table slice.
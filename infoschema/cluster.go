@@ -34,6 +34,14 @@ const (
 	ClusterTableStatementsSummary = "CLUSTER_STATEMENTS_SUMMARY"
 	// ClusterTableStatementsSummaryHistory is the string constant of cluster statement summary history table.
 	ClusterTableStatementsSummaryHistory = "CLUSTER_STATEMENTS_SUMMARY_HISTORY"
+	// ClusterTableTiDBTrx is the string constant of cluster running transactions table.
+	ClusterTableTiDBTrx = "CLUSTER_TIDB_TRX"
+	// ClusterTableDataLockWaits is the string constant of cluster pessimistic lock waits table.
+	ClusterTableDataLockWaits = "CLUSTER_DATA_LOCK_WAITS"
+	// ClusterTableMemoryUsage is the string constant of cluster memory and disk trackers table.
+	ClusterTableMemoryUsage = "CLUSTER_MEMORY_USAGE"
+	// ClusterTableSessionResourceUsage is the string constant of cluster session memory and disk usage table.
+	ClusterTableSessionResourceUsage = "CLUSTER_SESSION_RESOURCE_USAGE"
 )
 
 // memTableToClusterTables means add memory table to cluster table.
@@ -42,6 +50,10 @@ var memTableToClusterTables = map[string]string{
 	TableProcesslist:              ClusterTableProcesslist,
 	TableStatementsSummary:        ClusterTableStatementsSummary,
 	TableStatementsSummaryHistory: ClusterTableStatementsSummaryHistory,
+	TableTiDBTrx:                  ClusterTableTiDBTrx,
+	TableDataLockWaits:            ClusterTableDataLockWaits,
+	TableMemoryUsage:              ClusterTableMemoryUsage,
+	TableSessionResourceUsage:     ClusterTableSessionResourceUsage,
 }
 
 func init() {
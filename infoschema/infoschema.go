@@ -272,6 +272,10 @@ func (is *infoSchema) SchemaTables(schema model.CIStr) (tables []table.Table) {
 		return
 	}
 	for _, tbl := range schemaTables.tables {
+		// The table created by `CREATE TABLE ... SELECT` is invisible until its rows are inserted.
+		if tbl.Meta().State == model.StateWriteReorganization {
+			continue
+		}
 		tables = append(tables, tbl)
 	}
 	return
@@ -45,6 +45,7 @@ var perfSchemaTables = []string{
 	tablePDProfileAllocs,
 	tablePDProfileBlock,
 	tablePDProfileGoroutines,
+	tableSessionConnectAttrs,
 }
 
 // tableGlobalStatus contains the column name definitions for table global_status, same as MySQL.
@@ -543,3 +544,11 @@ const tablePDProfileGoroutines = "CREATE TABLE IF NOT EXISTS " + tableNamePDProf
 	"ID INT(8) NOT NULL," +
 	"STATE VARCHAR(16) NOT NULL," +
 	"LOCATION VARCHAR(512) NOT NULL);"
+
+// tableSessionConnectAttrs contains the column name definitions for table session_connect_attrs, same as MySQL.
+const tableSessionConnectAttrs = "CREATE TABLE IF NOT EXISTS performance_schema." + tableNameSessionConnectAttrs + " (" +
+	"PROCESSLIST_ID bigint(20) unsigned NOT NULL," +
+	"ATTR_NAME varchar(32) NOT NULL," +
+	"ATTR_VALUE varchar(1024)," +
+	"ORDINAL_POSITION int(11)," +
+	"PRIMARY KEY (PROCESSLIST_ID, ATTR_NAME));"
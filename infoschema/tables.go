@@ -68,8 +68,9 @@ const (
 	// TablePartitions is the string constant of infoschema table.
 	TablePartitions = "PARTITIONS"
 	// TableKeyColumn is the string constant of KEY_COLUMN_USAGE.
-	TableKeyColumn  = "KEY_COLUMN_USAGE"
-	tableReferConst = "REFERENTIAL_CONSTRAINTS"
+	TableKeyColumn = "KEY_COLUMN_USAGE"
+	// TableReferConst is the string constant of REFERENTIAL_CONSTRAINTS.
+	TableReferConst = "REFERENTIAL_CONSTRAINTS"
 	// TableSessionVar is the string constant of SESSION_VARIABLES.
 	TableSessionVar = "SESSION_VARIABLES"
 	tablePlugins    = "PLUGINS"
@@ -161,6 +162,30 @@ const (
 	TableClientErrorsSummaryByUser = "CLIENT_ERRORS_SUMMARY_BY_USER"
 	// TableClientErrorsSummaryByHost is the string constant of client errors table.
 	TableClientErrorsSummaryByHost = "CLIENT_ERRORS_SUMMARY_BY_HOST"
+	// TableTiDBStatusReport is the string constant of the diagnostic status report table.
+	TableTiDBStatusReport = "TIDB_STATUS_REPORT"
+	// TablePlacementPolicies is the string constant of the placement policy objects table.
+	TablePlacementPolicies = "PLACEMENT_POLICIES"
+	// TableStatsHealth is the string constant of the stats health table.
+	TableStatsHealth = "STATS_HEALTH"
+	// TableTransactionTimeoutHistory is the string constant of the transaction timeout history table.
+	TableTransactionTimeoutHistory = "TRANSACTION_TIMEOUT_HISTORY"
+	// TableDeadlocks is the string constant of the deadlock history table.
+	TableDeadlocks = "DEADLOCKS"
+	// TableTiDBTrx is the string constant of the running transactions table.
+	TableTiDBTrx = "TIDB_TRX"
+	// TableDataLockWaits is the string constant of the pessimistic lock waits table.
+	TableDataLockWaits = "DATA_LOCK_WAITS"
+	// TableMemoryUsage is the string constant of the memory and disk trackers table of the running statements.
+	TableMemoryUsage = "MEMORY_USAGE"
+	// TableSessionResourceUsage is the string constant of the session memory and disk usage table.
+	TableSessionResourceUsage = "SESSION_RESOURCE_USAGE"
+	// TablePreparedPlanCache is the string constant of the prepared plan cache table of the current session.
+	TablePreparedPlanCache = "PREPARED_PLAN_CACHE"
+	// TableRowPolicies is the string constant of the row policies table.
+	TableRowPolicies = "ROW_POLICIES"
+	// TableMaskingRules is the string constant of the column masking rules table.
+	TableMaskingRules = "MASKING_RULES"
 )
 
 var tableIDMap = map[string]int64{
@@ -176,7 +201,7 @@ var tableIDMap = map[string]int64{
 	TableProfiling:                          autoid.InformationSchemaDBID + 10,
 	TablePartitions:                         autoid.InformationSchemaDBID + 11,
 	TableKeyColumn:                          autoid.InformationSchemaDBID + 12,
-	tableReferConst:                         autoid.InformationSchemaDBID + 13,
+	TableReferConst:                         autoid.InformationSchemaDBID + 13,
 	TableSessionVar:                         autoid.InformationSchemaDBID + 14,
 	tablePlugins:                            autoid.InformationSchemaDBID + 15,
 	TableConstraints:                        autoid.InformationSchemaDBID + 16,
@@ -233,6 +258,22 @@ var tableIDMap = map[string]int64{
 	TableClientErrorsSummaryGlobal:          autoid.InformationSchemaDBID + 67,
 	TableClientErrorsSummaryByUser:          autoid.InformationSchemaDBID + 68,
 	TableClientErrorsSummaryByHost:          autoid.InformationSchemaDBID + 69,
+	TableTiDBStatusReport:                   autoid.InformationSchemaDBID + 70,
+	TablePlacementPolicies:                  autoid.InformationSchemaDBID + 71,
+	TableStatsHealth:                        autoid.InformationSchemaDBID + 72,
+	TableTransactionTimeoutHistory:          autoid.InformationSchemaDBID + 73,
+	TableDeadlocks:                          autoid.InformationSchemaDBID + 74,
+	TableTiDBTrx:                            autoid.InformationSchemaDBID + 75,
+	ClusterTableTiDBTrx:                     autoid.InformationSchemaDBID + 76,
+	TableDataLockWaits:                      autoid.InformationSchemaDBID + 77,
+	ClusterTableDataLockWaits:               autoid.InformationSchemaDBID + 78,
+	TableMemoryUsage:                        autoid.InformationSchemaDBID + 79,
+	ClusterTableMemoryUsage:                 autoid.InformationSchemaDBID + 80,
+	TableSessionResourceUsage:               autoid.InformationSchemaDBID + 81,
+	ClusterTableSessionResourceUsage:        autoid.InformationSchemaDBID + 82,
+	TablePreparedPlanCache:                  autoid.InformationSchemaDBID + 83,
+	TableRowPolicies:                        autoid.InformationSchemaDBID + 84,
+	TableMaskingRules:                       autoid.InformationSchemaDBID + 85,
 }
 
 type columnInfo struct {
@@ -736,6 +777,9 @@ var tableProcesslistCols = []columnInfo{
 	{name: "MEM", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag},
 	{name: "DISK", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag},
 	{name: "TxnStart", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag, deflt: ""},
+	{name: "PROGRAM_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "CLIENT_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "CLIENT_VERSION", tp: mysql.TypeVarchar, size: 64},
 }
 
 var tableTiDBIndexesCols = []columnInfo{
@@ -813,6 +857,7 @@ var slowQueryCols = []columnInfo{
 	{name: variable.SlowLogPDTotal, tp: mysql.TypeDouble, size: 22},
 	{name: variable.SlowLogBackoffTotal, tp: mysql.TypeDouble, size: 22},
 	{name: variable.SlowLogWriteSQLRespTotal, tp: mysql.TypeDouble, size: 22},
+	{name: variable.SlowLogWriteSQLRespBytes, tp: mysql.TypeLonglong, size: 20, flag: mysql.UnsignedFlag},
 	{name: variable.SlowLogBackoffDetail, tp: mysql.TypeVarchar, size: 4096},
 	{name: variable.SlowLogPrepared, tp: mysql.TypeTiny, size: 1},
 	{name: variable.SlowLogSucc, tp: mysql.TypeTiny, size: 1},
@@ -822,6 +867,7 @@ var slowQueryCols = []columnInfo{
 	{name: variable.SlowLogPlanDigest, tp: mysql.TypeVarchar, size: 128},
 	{name: variable.SlowLogPrevStmt, tp: mysql.TypeLongBlob, size: types.UnspecifiedLength},
 	{name: variable.SlowLogQuerySQLStr, tp: mysql.TypeLongBlob, size: types.UnspecifiedLength},
+	{name: variable.SlowLogIsSampled, tp: mysql.TypeTiny, size: 1},
 }
 
 // TableTiDBHotRegionsCols is TiDB hot region mem table columns.
@@ -870,6 +916,7 @@ var tableAnalyzeStatusCols = []columnInfo{
 	{name: "START_TIME", tp: mysql.TypeDatetime},
 	{name: "END_TIME", tp: mysql.TypeDatetime},
 	{name: "STATE", tp: mysql.TypeVarchar, size: 64},
+	{name: "PROGRESS", tp: mysql.TypeDouble, size: 22},
 }
 
 // TableTiKVRegionStatusCols is TiKV region status mem table columns.
@@ -1093,6 +1140,11 @@ var tableDDLJobsCols = []columnInfo{
 	{name: "END_TIME", tp: mysql.TypeDatetime, size: 19},
 	{name: "STATE", tp: mysql.TypeVarchar, size: 64},
 	{name: "QUERY", tp: mysql.TypeVarchar, size: 64},
+	{name: "PROGRESS", tp: mysql.TypeDouble, size: 22},
+	{name: "ROW_RATE", tp: mysql.TypeDouble, size: 22},
+	{name: "ETA", tp: mysql.TypeVarchar, size: 64},
+	{name: "START_KEY", tp: mysql.TypeVarchar, size: 64},
+	{name: "END_KEY", tp: mysql.TypeVarchar, size: 64},
 }
 
 var tableSequencesCols = []columnInfo{
@@ -1187,6 +1239,8 @@ var tableStatementsSummaryCols = []columnInfo{
 	{name: "AVG_PD_TIME", tp: mysql.TypeLonglong, size: 22, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Average time of PD used"},
 	{name: "AVG_BACKOFF_TOTAL_TIME", tp: mysql.TypeLonglong, size: 22, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Average time of Backoff used"},
 	{name: "AVG_WRITE_SQL_RESP_TIME", tp: mysql.TypeLonglong, size: 22, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Average time of write sql resp used"},
+	{name: "AVG_WRITE_SQL_RESP_BYTES", tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Average bytes of the result sets written to the client"},
+	{name: "MAX_WRITE_SQL_RESP_BYTES", tp: mysql.TypeLonglong, size: 20, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Max bytes of the result sets written to the client"},
 	{name: "PREPARED", tp: mysql.TypeTiny, size: 1, flag: mysql.NotNullFlag, comment: "Whether prepared"},
 	{name: "AVG_AFFECTED_ROWS", tp: mysql.TypeDouble, size: 22, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Average number of rows affected"},
 	{name: "FIRST_SEEN", tp: mysql.TypeTimestamp, size: 26, flag: mysql.NotNullFlag, comment: "The time these statements are seen for the first time"},
@@ -1198,6 +1252,7 @@ var tableStatementsSummaryCols = []columnInfo{
 	{name: "PREV_SAMPLE_TEXT", tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "The previous statement before commit"},
 	{name: "PLAN_DIGEST", tp: mysql.TypeVarchar, size: 64, comment: "Digest of its execution plan"},
 	{name: "PLAN", tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "Sampled execution plan"},
+	{name: "STATS_VERSIONS", tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "The versions of the stats used to compile the last statement"},
 }
 
 var tableStorageStatsCols = []columnInfo{
@@ -1332,6 +1387,135 @@ var tableClientErrorsSummaryByHostCols = []columnInfo{
 	{name: "LAST_SEEN", tp: mysql.TypeTimestamp, size: 26},
 }
 
+var tableTiDBStatusReportCols = []columnInfo{
+	{name: "SECTION", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "NAME", tp: mysql.TypeVarchar, size: 512, flag: mysql.NotNullFlag},
+	{name: "VALUE", tp: mysql.TypeVarchar, size: 64},
+}
+
+var tablePlacementPoliciesCols = []columnInfo{
+	{name: "POLICY_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "LEADER_CONSTRAINTS", tp: mysql.TypeVarchar, size: 1024},
+	{name: "FOLLOWERS", tp: mysql.TypeLonglong, size: 64, flag: mysql.NotNullFlag | mysql.UnsignedFlag},
+	{name: "FOLLOWER_CONSTRAINTS", tp: mysql.TypeVarchar, size: 1024},
+	{name: "VOTERS", tp: mysql.TypeLonglong, size: 64, flag: mysql.NotNullFlag | mysql.UnsignedFlag},
+	{name: "VOTER_CONSTRAINTS", tp: mysql.TypeVarchar, size: 1024},
+	{name: "LEARNERS", tp: mysql.TypeLonglong, size: 64, flag: mysql.NotNullFlag | mysql.UnsignedFlag},
+	{name: "LEARNER_CONSTRAINTS", tp: mysql.TypeVarchar, size: 1024},
+}
+
+var tableStatsHealthCols = []columnInfo{
+	{name: "TABLE_SCHEMA", tp: mysql.TypeVarchar, size: 64},
+	{name: "TABLE_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "PARTITION_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "HEALTHY", tp: mysql.TypeLonglong, size: 21},
+	{name: "MODIFY_RATIO", tp: mysql.TypeDouble, size: 22},
+	{name: "ROW_COUNT", tp: mysql.TypeLonglong, size: 21},
+	{name: "MODIFY_COUNT", tp: mysql.TypeLonglong, size: 21},
+	{name: "LAST_ANALYZE_TIME", tp: mysql.TypeDatetime},
+	{name: "MISSING_COLUMN_STATS", tp: mysql.TypeLonglong, size: 21},
+}
+
+var tableTransactionTimeoutHistoryCols = []columnInfo{
+	{name: "ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag},
+	{name: "USER", tp: mysql.TypeVarchar, size: 16},
+	{name: "HOST", tp: mysql.TypeVarchar, size: 64},
+	{name: "DB", tp: mysql.TypeVarchar, size: 64},
+	{name: "START_TS", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag},
+	{name: "TRX_START_TIME", tp: mysql.TypeDatetime, size: 26, decimal: 6},
+	{name: "KILL_TIME", tp: mysql.TypeDatetime, size: 26, decimal: 6},
+	{name: "DURATION", tp: mysql.TypeDouble, size: 22, comment: "The lifetime of the transaction in seconds"},
+	{name: "REASON", tp: mysql.TypeVarchar, size: 16, comment: "idle or lifetime"},
+}
+
+var tableDeadlocksCols = []columnInfo{
+	{name: "DEADLOCK_ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag},
+	{name: "OCCUR_TIME", tp: mysql.TypeDatetime, size: 26, decimal: 6},
+	{name: "RETRYABLE", tp: mysql.TypeTiny, size: 1, flag: mysql.NotNullFlag},
+	{name: "WAIT_CHAIN_DIGEST", tp: mysql.TypeVarchar, size: 64},
+	{name: "TRY_LOCK_TRX_ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag},
+	{name: "CURRENT_SQL_DIGEST", tp: mysql.TypeVarchar, size: 64},
+	{name: "CURRENT_SQL_DIGEST_TEXT", tp: mysql.TypeBlob, size: types.UnspecifiedLength},
+	{name: "KEY", tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "The key in hex which the transaction tries to lock"},
+	{name: "TRX_HOLDING_LOCK", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag},
+}
+
+var tableTiDBTrxCols = []columnInfo{
+	{name: "ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Start ts of the transaction"},
+	{name: "START_TIME", tp: mysql.TypeDatetime, size: 26, decimal: 6, comment: "Start time of the transaction"},
+	{name: "CURRENT_SQL_DIGEST", tp: mysql.TypeVarchar, size: 64, comment: "Digest of the sql the transaction is currently running"},
+	{name: "CURRENT_SQL_DIGEST_TEXT", tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "The normalized sql the transaction is currently running"},
+	{name: "STATE", tp: mysql.TypeVarchar, size: 16, comment: "Current running state of the transaction"},
+	{name: "WAITING_START_TIME", tp: mysql.TypeDatetime, size: 26, decimal: 6, comment: "Current lock waiting's start time"},
+	{name: "MEM_BUFFER_KEYS", tp: mysql.TypeLonglong, size: 21, comment: "How many entries are in the membuffer"},
+	{name: "MEM_BUFFER_BYTES", tp: mysql.TypeLonglong, size: 21, comment: "How many bytes are in the membuffer"},
+	{name: "SESSION_ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag, comment: "Which session this transaction belongs to"},
+	{name: "USER", tp: mysql.TypeVarchar, size: 16, comment: "The user who open this session"},
+	{name: "DB", tp: mysql.TypeVarchar, size: 64, comment: "The schema this transaction works on"},
+}
+
+var tableDataLockWaitsCols = []columnInfo{
+	{name: "KEY", tp: mysql.TypeBlob, size: types.UnspecifiedLength, flag: mysql.NotNullFlag, comment: "The key that's being waiting on, in hex"},
+	{name: "TRX_ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Current transaction that's waiting for the lock"},
+	{name: "CURRENT_HOLDING_TRX_ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "The transaction that's holding the lock and blocks the current transaction"},
+	{name: "SQL_DIGEST", tp: mysql.TypeVarchar, size: 64, comment: "Digest of the SQL that's trying to acquire the lock"},
+	{name: "SQL_DIGEST_TEXT", tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "The normalized SQL that's trying to acquire the lock"},
+	{name: "WAITING_START_TIME", tp: mysql.TypeDatetime, size: 26, decimal: 6, comment: "Start time of acquiring the lock"},
+}
+
+var tableMemoryUsageCols = []columnInfo{
+	{name: "ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Connection ID of the session"},
+	{name: "TRACKER_TYPE", tp: mysql.TypeVarchar, size: 16, comment: "MEMORY or DISK"},
+	{name: "DEPTH", tp: mysql.TypeLonglong, size: 21, comment: "Depth of the tracker in the tracker tree, the statement tracker is 0"},
+	{name: "LABEL", tp: mysql.TypeVarchar, size: 128, comment: "The operator or the component tracked by the tracker"},
+	{name: "PARENT_LABEL", tp: mysql.TypeVarchar, size: 128, comment: "Label of the parent tracker"},
+	{name: "BYTES", tp: mysql.TypeLonglong, size: 21, comment: "Bytes currently consumed"},
+	{name: "MAX_BYTES", tp: mysql.TypeLonglong, size: 21, comment: "Max bytes consumed"},
+	{name: "BYTES_LIMIT", tp: mysql.TypeLonglong, size: 21, comment: "Quota of the tracker, -1 means no limit"},
+}
+
+var tableSessionResourceUsageCols = []columnInfo{
+	{name: "ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Connection ID of the session"},
+	{name: "USER", tp: mysql.TypeVarchar, size: 16, flag: mysql.NotNullFlag},
+	{name: "HOST", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "DB", tp: mysql.TypeVarchar, size: 64},
+	{name: "COMMAND", tp: mysql.TypeVarchar, size: 16, flag: mysql.NotNullFlag},
+	{name: "TIME", tp: mysql.TypeLong, size: 7, flag: mysql.NotNullFlag},
+	{name: "DIGEST", tp: mysql.TypeVarchar, size: 64},
+	{name: "MEM_BYTES", tp: mysql.TypeLonglong, size: 21, comment: "Memory consumed by the current or the last statement"},
+	{name: "MAX_MEM_BYTES", tp: mysql.TypeLonglong, size: 21, comment: "Max memory consumed by the current or the last statement"},
+	{name: "MEM_QUOTA", tp: mysql.TypeLonglong, size: 21, comment: "Memory quota of the statement, -1 means no limit"},
+	{name: "DISK_BYTES", tp: mysql.TypeLonglong, size: 21, comment: "Disk spilled by the current or the last statement"},
+	{name: "MAX_DISK_BYTES", tp: mysql.TypeLonglong, size: 21, comment: "Max disk spilled by the current or the last statement"},
+	{name: "TXN_MEM_BUFFER_BYTES", tp: mysql.TypeLonglong, size: 21, comment: "Bytes in the mem buffer of the current transaction"},
+	{name: "INFO", tp: mysql.TypeLongBlob, size: types.UnspecifiedLength},
+}
+
+var tablePreparedPlanCacheCols = []columnInfo{
+	{name: "STMT_ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "ID of the prepared statement"},
+	{name: "SCHEMA_VERSION", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag, comment: "Schema version of the tables referenced by the cached plan"},
+	{name: "SQL_DIGEST", tp: mysql.TypeVarchar, size: 64},
+	{name: "NORMALIZED_SQL", tp: mysql.TypeLongBlob, size: types.UnspecifiedLength},
+	{name: "TABLE_IDS", tp: mysql.TypeLongBlob, size: types.UnspecifiedLength, comment: "IDs of the tables referenced by the statement, empty if views are referenced"},
+	{name: "USED_STATS", tp: mysql.TypeLongBlob, size: types.UnspecifiedLength, comment: "Versions of the stats used by the cached plan, in the form of id:version"},
+	{name: "BINDINGS", tp: mysql.TypeLongBlob, size: types.UnspecifiedLength, comment: "Bindings used by the cached plan"},
+}
+
+var tableRowPoliciesCols = []columnInfo{
+	{name: "POLICY_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "TABLE_SCHEMA", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "TABLE_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "GRANTEE", tp: mysql.TypeVarchar, size: 81, flag: mysql.NotNullFlag},
+	{name: "USING_EXPR", tp: mysql.TypeLongBlob, size: types.UnspecifiedLength, comment: "The condition the rows visible to the grantee satisfy"},
+}
+
+var tableMaskingRulesCols = []columnInfo{
+	{name: "TABLE_SCHEMA", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "TABLE_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "COLUMN_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "MASK_EXPR", tp: mysql.TypeLongBlob, size: types.UnspecifiedLength, comment: "The expression replacing the column for the users without the UNMASK privilege"},
+}
+
 // GetShardingInfo returns a nil or description string for the sharding information of given TableInfo.
 // The returned description string may be:
 //  - "NOT_SHARDED": for tables that SHARD_ROW_ID_BITS is not specified.
@@ -1364,6 +1548,8 @@ const (
 	PrimaryConstraint = "PRIMARY"
 	// UniqueKeyType is the string constant of UNIQUE.
 	UniqueKeyType = "UNIQUE"
+	// ForeignKeyType is the string constant of FOREIGN KEY.
+	ForeignKeyType = "FOREIGN KEY"
 )
 
 // ServerInfo represents the basic server information of single cluster component
@@ -1648,7 +1834,7 @@ var tableNameToColumns = map[string][]columnInfo{
 	TableProfiling:                          profilingCols,
 	TablePartitions:                         partitionsCols,
 	TableKeyColumn:                          keyColumnUsageCols,
-	tableReferConst:                         referConstCols,
+	TableReferConst:                         referConstCols,
 	TableSessionVar:                         sessionVarCols,
 	tablePlugins:                            pluginsCols,
 	TableConstraints:                        tableConstraintsCols,
@@ -1701,6 +1887,18 @@ var tableNameToColumns = map[string][]columnInfo{
 	TableClientErrorsSummaryGlobal:          tableClientErrorsSummaryGlobalCols,
 	TableClientErrorsSummaryByUser:          tableClientErrorsSummaryByUserCols,
 	TableClientErrorsSummaryByHost:          tableClientErrorsSummaryByHostCols,
+	TableTiDBStatusReport:                   tableTiDBStatusReportCols,
+	TablePlacementPolicies:                  tablePlacementPoliciesCols,
+	TableStatsHealth:                        tableStatsHealthCols,
+	TableTransactionTimeoutHistory:          tableTransactionTimeoutHistoryCols,
+	TableDeadlocks:                          tableDeadlocksCols,
+	TableTiDBTrx:                            tableTiDBTrxCols,
+	TableDataLockWaits:                      tableDataLockWaitsCols,
+	TableMemoryUsage:                        tableMemoryUsageCols,
+	TableSessionResourceUsage:               tableSessionResourceUsageCols,
+	TablePreparedPlanCache:                  tablePreparedPlanCacheCols,
+	TableRowPolicies:                        tableRowPoliciesCols,
+	TableMaskingRules:                       tableMaskingRulesCols,
 }
 
 func createInfoSchemaTable(_ autoid.Allocators, meta *model.TableInfo) (table.Table, error) {
@@ -1742,7 +1940,6 @@ func (it *infoschemaTable) getRows(ctx sessionctx.Context, cols []*table.Column)
 	sort.Sort(SchemasSorter(dbs))
 	switch it.meta.Name.O {
 	case tableFiles:
-	case tableReferConst:
 	case tablePlugins, tableTriggers:
 	case tableRoutines:
 	// TODO: Fill the following tables.
@@ -14,6 +14,7 @@
 package infoschema_test
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"math"
@@ -48,9 +49,12 @@ import (
 	"github.com/pingcap/tidb/util/kvcache"
 	"github.com/pingcap/tidb/util/pdapi"
 	"github.com/pingcap/tidb/util/set"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/stmtsummary"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testleak"
 	"github.com/pingcap/tidb/util/testutil"
+	"github.com/pingcap/tidb/util/txninfo"
 	"google.golang.org/grpc"
 )
 
@@ -306,7 +310,10 @@ func (s *testTableSuite) TestInfoschemaFieldValue(c *C) {
 			"  `DIGEST` varchar(64) DEFAULT '',\n" +
 			"  `MEM` bigint(21) unsigned DEFAULT NULL,\n" +
 			"  `DISK` bigint(21) unsigned DEFAULT NULL,\n" +
-			"  `TxnStart` varchar(64) NOT NULL DEFAULT ''\n" +
+			"  `TxnStart` varchar(64) NOT NULL DEFAULT '',\n" +
+			"  `PROGRAM_NAME` varchar(64) DEFAULT NULL,\n" +
+			"  `CLIENT_NAME` varchar(64) DEFAULT NULL,\n" +
+			"  `CLIENT_VERSION` varchar(64) DEFAULT NULL\n" +
 			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
 	tk.MustQuery("show create table information_schema.cluster_log").Check(
 		testkit.Rows("" +
@@ -448,6 +455,10 @@ func (sm *mockSessionManager) Kill(connectionID uint64, query bool) {}
 
 func (sm *mockSessionManager) KillAllConnections() {}
 
+func (sm *mockSessionManager) ShowTxnList() []*txninfo.TxnInfo {
+	return nil
+}
+
 func (sm *mockSessionManager) UpdateTLSConfig(cfg *tls.Config) {}
 
 func (sm *mockSessionManager) ServerID() uint64 {
@@ -495,13 +506,24 @@ func (s *testTableSuite) TestSomeTables(c *C) {
 		State:   1,
 		Info:    "check port",
 		StmtCtx: tk.Se.GetSessionVars().StmtCtx,
+		ConnectAttrs: map[string]string{
+			"program_name":    "mysql",
+			"_client_name":    "libmysql",
+			"_client_version": "8.0.23",
+		},
 	}
 	tk.Se.SetSessionManager(sm)
 	tk.MustQuery("select * from information_schema.PROCESSLIST order by ID;").Sort().Check(
 		testkit.Rows(
-			fmt.Sprintf("1 user-1 localhost information_schema Quit 9223372036 %s %s abc1 0 0 ", "in transaction", "do something"),
-			fmt.Sprintf("2 user-2 localhost test Init DB 9223372036 %s %s abc2 0 0 ", "autocommit", strings.Repeat("x", 101)),
-			fmt.Sprintf("3 user-3 127.0.0.1:12345 test Init DB 9223372036 %s %s abc3 0 0 ", "in transaction", "check port"),
+			fmt.Sprintf("1 user-1 localhost information_schema Quit 9223372036 %s %s abc1 0 0  <nil> <nil> <nil>", "in transaction", "do something"),
+			fmt.Sprintf("2 user-2 localhost test Init DB 9223372036 %s %s abc2 0 0  <nil> <nil> <nil>", "autocommit", strings.Repeat("x", 101)),
+			fmt.Sprintf("3 user-3 127.0.0.1:12345 test Init DB 9223372036 %s %s abc3 0 0  mysql libmysql 8.0.23", "in transaction", "check port"),
+		))
+	tk.MustQuery("select * from performance_schema.session_connect_attrs").Check(
+		testkit.Rows(
+			"3 _client_name libmysql 0",
+			"3 _client_version 8.0.23 1",
+			"3 program_name mysql 2",
 		))
 	tk.MustQuery("SHOW PROCESSLIST;").Sort().Check(
 		testkit.Rows(
@@ -542,8 +564,8 @@ func (s *testTableSuite) TestSomeTables(c *C) {
 	tk.Se.GetSessionVars().TimeZone = time.UTC
 	tk.MustQuery("select * from information_schema.PROCESSLIST order by ID;").Check(
 		testkit.Rows(
-			fmt.Sprintf("1 user-1 localhost information_schema Quit 9223372036 %s %s abc1 0 0 ", "in transaction", "<nil>"),
-			fmt.Sprintf("2 user-2 localhost <nil> Init DB 9223372036 %s %s abc2 0 0 07-29 03:26:05.158(410090409861578752)", "autocommit", strings.Repeat("x", 101)),
+			fmt.Sprintf("1 user-1 localhost information_schema Quit 9223372036 %s %s abc1 0 0  <nil> <nil> <nil>", "in transaction", "<nil>"),
+			fmt.Sprintf("2 user-2 localhost <nil> Init DB 9223372036 %s %s abc2 0 0 07-29 03:26:05.158(410090409861578752) <nil> <nil> <nil>", "autocommit", strings.Repeat("x", 101)),
 		))
 	tk.MustQuery("SHOW PROCESSLIST;").Sort().Check(
 		testkit.Rows(
@@ -557,11 +579,11 @@ func (s *testTableSuite) TestSomeTables(c *C) {
 		))
 	tk.MustQuery("select * from information_schema.PROCESSLIST where db is null;").Check(
 		testkit.Rows(
-			fmt.Sprintf("2 user-2 localhost <nil> Init DB 9223372036 %s %s abc2 0 0 07-29 03:26:05.158(410090409861578752)", "autocommit", strings.Repeat("x", 101)),
+			fmt.Sprintf("2 user-2 localhost <nil> Init DB 9223372036 %s %s abc2 0 0 07-29 03:26:05.158(410090409861578752) <nil> <nil> <nil>", "autocommit", strings.Repeat("x", 101)),
 		))
 	tk.MustQuery("select * from information_schema.PROCESSLIST where Info is null;").Check(
 		testkit.Rows(
-			fmt.Sprintf("1 user-1 localhost information_schema Quit 9223372036 %s %s abc1 0 0 ", "in transaction", "<nil>"),
+			fmt.Sprintf("1 user-1 localhost information_schema Quit 9223372036 %s %s abc1 0 0  <nil> <nil> <nil>", "in transaction", "<nil>"),
 		))
 }
 
@@ -661,10 +683,10 @@ func (s *testTableSuite) TestSlowQuery(c *C) {
 	tk.MustExec("set time_zone = '+08:00';")
 	re := tk.MustQuery("select * from information_schema.slow_query")
 	re.Check(testutil.RowsWithSep("|",
-		"2019-02-12 19:33:56.571953|406315658548871171|root|localhost|6|57|0.12|4.895492|0.4|0.2|0.000000003|2|0.000000002|0.00000001|0.000000003|0.19|0.21|0.01|0|0.18|[txnLock]|0.03|0|15|480|1|8|0.3824278|0.161|0.101|0.092|1.71|1|100001|100000|100|10|10|10|100|test||0|42a1c8aae6f133e934d4bf0147491709a8812ea05ff8819ec522780fe657b772|t1:1,t2:2|0.1|0.2|0.03|127.0.0.1:20160|0.05|0.6|0.8|0.0.0.0:20160|70724|65536|0|0|0|0||0|1|1|0|abcd|60e9378c746d9a2be1c791047e008967cf252eb6de9167ad3aa6098fa2d523f4|update t set i = 2;|select * from t_slim;"))
+		"2019-02-12 19:33:56.571953|406315658548871171|root|localhost|6|57|0.12|4.895492|0.4|0.2|0.000000003|2|0.000000002|0.00000001|0.000000003|0.19|0.21|0.01|0|0.18|[txnLock]|0.03|0|15|480|1|8|0.3824278|0.161|0.101|0.092|1.71|1|100001|100000|100|10|10|10|100|test||0|42a1c8aae6f133e934d4bf0147491709a8812ea05ff8819ec522780fe657b772|t1:1,t2:2|0.1|0.2|0.03|127.0.0.1:20160|0.05|0.6|0.8|0.0.0.0:20160|70724|65536|0|0|0|0|0||0|1|1|0|abcd|60e9378c746d9a2be1c791047e008967cf252eb6de9167ad3aa6098fa2d523f4|update t set i = 2;|select * from t_slim;|0"))
 	tk.MustExec("set time_zone = '+00:00';")
 	re = tk.MustQuery("select * from information_schema.slow_query")
-	re.Check(testutil.RowsWithSep("|", "2019-02-12 11:33:56.571953|406315658548871171|root|localhost|6|57|0.12|4.895492|0.4|0.2|0.000000003|2|0.000000002|0.00000001|0.000000003|0.19|0.21|0.01|0|0.18|[txnLock]|0.03|0|15|480|1|8|0.3824278|0.161|0.101|0.092|1.71|1|100001|100000|100|10|10|10|100|test||0|42a1c8aae6f133e934d4bf0147491709a8812ea05ff8819ec522780fe657b772|t1:1,t2:2|0.1|0.2|0.03|127.0.0.1:20160|0.05|0.6|0.8|0.0.0.0:20160|70724|65536|0|0|0|0||0|1|1|0|abcd|60e9378c746d9a2be1c791047e008967cf252eb6de9167ad3aa6098fa2d523f4|update t set i = 2;|select * from t_slim;"))
+	re.Check(testutil.RowsWithSep("|", "2019-02-12 11:33:56.571953|406315658548871171|root|localhost|6|57|0.12|4.895492|0.4|0.2|0.000000003|2|0.000000002|0.00000001|0.000000003|0.19|0.21|0.01|0|0.18|[txnLock]|0.03|0|15|480|1|8|0.3824278|0.161|0.101|0.092|1.71|1|100001|100000|100|10|10|10|100|test||0|42a1c8aae6f133e934d4bf0147491709a8812ea05ff8819ec522780fe657b772|t1:1,t2:2|0.1|0.2|0.03|127.0.0.1:20160|0.05|0.6|0.8|0.0.0.0:20160|70724|65536|0|0|0|0|0||0|1|1|0|abcd|60e9378c746d9a2be1c791047e008967cf252eb6de9167ad3aa6098fa2d523f4|update t set i = 2;|select * from t_slim;|0"))
 
 	// Test for long query.
 	f, err := os.OpenFile(slowLogFileName, os.O_CREATE|os.O_WRONLY, 0644)
@@ -823,7 +845,7 @@ func (s *testClusterTableSuite) TestSelectClusterTable(c *C) {
 		tk.MustQuery("select count(*) from `CLUSTER_SLOW_QUERY`").Check(testkit.Rows("1"))
 		tk.MustQuery("select time from `CLUSTER_SLOW_QUERY` where time='2019-02-12 19:33:56.571953'").Check(testutil.RowsWithSep("|", "2019-02-12 19:33:56.571953"))
 		tk.MustQuery("select count(*) from `CLUSTER_PROCESSLIST`").Check(testkit.Rows("1"))
-		tk.MustQuery("select * from `CLUSTER_PROCESSLIST`").Check(testkit.Rows(fmt.Sprintf(":10080 1 root 127.0.0.1 <nil> Query 9223372036 %s <nil>  0 0 ", "")))
+		tk.MustQuery("select * from `CLUSTER_PROCESSLIST`").Check(testkit.Rows(fmt.Sprintf(":10080 1 root 127.0.0.1 <nil> Query 9223372036 %s <nil>  0 0  <nil> <nil> <nil>", "")))
 		tk.MustQuery("select query_time, conn_id from `CLUSTER_SLOW_QUERY` order by time limit 1").Check(testkit.Rows("4.895492 6"))
 		tk.MustQuery("select count(*) from `CLUSTER_SLOW_QUERY` group by digest").Check(testkit.Rows("1"))
 		tk.MustQuery("select digest, count(*) from `CLUSTER_SLOW_QUERY` group by digest").Check(testkit.Rows("42a1c8aae6f133e934d4bf0147491709a8812ea05ff8819ec522780fe657b772 1"))
@@ -874,7 +896,7 @@ select * from t3;
 	tk.MustQuery("select count(*) from `CLUSTER_SLOW_QUERY`").Check(testkit.Rows("4"))
 	tk.MustQuery("select count(*) from `SLOW_QUERY`").Check(testkit.Rows("4"))
 	tk.MustQuery("select count(*) from `CLUSTER_PROCESSLIST`").Check(testkit.Rows("1"))
-	tk.MustQuery("select * from `CLUSTER_PROCESSLIST`").Check(testkit.Rows(fmt.Sprintf(":10080 1 root 127.0.0.1 <nil> Query 9223372036 %s <nil>  0 0 ", "")))
+	tk.MustQuery("select * from `CLUSTER_PROCESSLIST`").Check(testkit.Rows(fmt.Sprintf(":10080 1 root 127.0.0.1 <nil> Query 9223372036 %s <nil>  0 0  <nil> <nil> <nil>", "")))
 	tk.MustExec("create user user1")
 	tk.MustExec("create user user2")
 	user1 := testkit.NewTestKit(c, s.store)
@@ -1246,6 +1268,54 @@ func (s *testClusterTableSuite) TestStmtSummaryHistoryTable(c *C) {
 	))
 }
 
+func (s *testClusterTableSuite) TestStmtSummaryPersistHistory(c *C) {
+	tk := s.newTestKitWithRoot(c)
+	// The columns of the persisted history are the same as the history table except the instance.
+	persistedCols := tk.MustQuery("desc mysql.statements_summary_history").Rows()
+	historyCols := tk.MustQuery("desc information_schema.statements_summary_history").Rows()
+	c.Assert(persistedCols, HasLen, len(historyCols)+1)
+	c.Assert(persistedCols[0][0], Equals, "instance")
+	for i, col := range historyCols {
+		c.Assert(persistedCols[i+1][0], Equals, strings.ToLower(col[0].(string)))
+	}
+
+	tk.MustExec("drop table if exists test_summary")
+	tk.MustExec("create table test_summary(a int, b varchar(10), key k(a))")
+	tk.MustExec("set global tidb_enable_stmt_summary = 1")
+	s.dom.GetGlobalVarsCache().Disable()
+	tk.MustExec("set global tidb_stmt_summary_refresh_interval = 1")
+	defer tk.MustExec("set global tidb_stmt_summary_refresh_interval = 1800")
+
+	tk = s.newTestKitWithRoot(c)
+	tk.MustExec("select b from test_summary where a = 1")
+	tk.MustExec("select b from test_summary where a = 2")
+	// Wait for the interval to end.
+	time.Sleep(1100 * time.Millisecond)
+
+	ctx := context.Background()
+	exec := tk.Se.(sqlexec.RestrictedSQLExecutor)
+	instance := "127.0.0.1:10080"
+	c.Assert(stmtsummary.PersistHistory(ctx, exec, instance), IsNil)
+	// The persisted history isn't persisted again.
+	c.Assert(stmtsummary.PersistHistory(ctx, exec, instance), IsNil)
+	sql := "select instance, exec_count, query_sample_text from mysql.statements_summary_history where digest_text like 'select `b` from `test_summary`%'"
+	tk.MustQuery(sql).Check(testkit.Rows("127.0.0.1:10080 2 select b from test_summary where a = 1"))
+
+	// The history is loaded after tidb-server restarts.
+	stmtsummary.StmtSummaryByDigestMap.Clear()
+	c.Assert(stmtsummary.LoadPersistedHistory(ctx, exec, instance), IsNil)
+	sql = "select exec_count, query_sample_text from information_schema.statements_summary_history where digest_text like 'select `b` from `test_summary`%'"
+	tk.MustQuery(sql).Check(testkit.Rows("2 select b from test_summary where a = 1"))
+	c.Assert(stmtsummary.LoadPersistedHistory(ctx, exec, "127.0.0.1:10081"), IsNil)
+	tk.MustQuery(sql).Check(testkit.Rows())
+
+	c.Assert(stmtsummary.DeleteExpiredHistory(ctx, exec, 1), IsNil)
+	tk.MustQuery("select count(*) from mysql.statements_summary_history where digest_text like 'select `b` from `test_summary`%'").Check(testkit.Rows("1"))
+	tk.MustExec("update mysql.statements_summary_history set summary_end_time = date_sub(summary_end_time, interval 2 day)")
+	c.Assert(stmtsummary.DeleteExpiredHistory(ctx, exec, 1), IsNil)
+	tk.MustQuery("select count(*) from mysql.statements_summary_history").Check(testkit.Rows("0"))
+}
+
 // Test statements_summary_history.
 func (s *testTableSuite) TestStmtSummaryInternalQuery(c *C) {
 	tk := s.newTestKitWithRoot(c)
@@ -1509,3 +1579,16 @@ func (s *testTableSuite) TestInfoschemaClientErrors(c *C) {
 	err = tk.ExecToErr("FLUSH CLIENT_ERRORS_SUMMARY")
 	c.Assert(err.Error(), Equals, "[planner:1227]Access denied; you need (at least one of) the RELOAD privilege(s) for this operation")
 }
+
+func (s *testTableSuite) TestInfoschemaStatusReport(c *C) {
+	tk := s.newTestKitWithRoot(c)
+	tk.MustQuery("SELECT count(*) > 0 FROM information_schema.tidb_status_report WHERE section = 'goroutine'").Check(testkit.Rows("1"))
+	tk.MustQuery("SELECT name FROM information_schema.tidb_status_report WHERE section = 'memory' AND name LIKE '%_bytes'").Check(
+		testkit.Rows("global_bytes", "disk_bytes"))
+	tk.MustQuery("SELECT count(*) FROM information_schema.tidb_status_report WHERE section = 'region_cache'").Check(testkit.Rows("3"))
+
+	tk.MustExec("CREATE USER 'statusreporttest'@'localhost'")
+	c.Assert(tk.Se.Auth(&auth.UserIdentity{Username: "statusreporttest", Hostname: "localhost"}, nil, nil), IsTrue)
+	err := tk.QueryToErr("SELECT * FROM information_schema.tidb_status_report")
+	c.Assert(err.Error(), Equals, "[planner:1227]Access denied; you need (at least one of) the PROCESS privilege(s) for this operation")
+}
@@ -0,0 +1,254 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package infoschema
+
+import (
+	"context"
+
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta/autoid"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/temptable"
+)
+
+// LocalTemporaryTables contains the local temporary tables of a session.
+// Local temporary tables are only visible to the session which creates them,
+// and they shadow the normal tables with the same names.
+type LocalTemporaryTables struct {
+	schemaMap map[string]map[string]table.Table
+	idx2table map[int64]table.Table
+	idx2db    map[int64]*model.DBInfo
+}
+
+// NewLocalTemporaryTables creates a new LocalTemporaryTables object.
+func NewLocalTemporaryTables() *LocalTemporaryTables {
+	return &LocalTemporaryTables{
+		schemaMap: make(map[string]map[string]table.Table),
+		idx2table: make(map[int64]table.Table),
+		idx2db:    make(map[int64]*model.DBInfo),
+	}
+}
+
+// TableByName gets the local temporary table by the schema and table name.
+func (l *LocalTemporaryTables) TableByName(schema, tbl model.CIStr) (table.Table, bool) {
+	if tbls, ok := l.schemaMap[schema.L]; ok {
+		t, ok := tbls[tbl.L]
+		return t, ok
+	}
+	return nil, false
+}
+
+// TableByID gets the local temporary table by the table ID.
+func (l *LocalTemporaryTables) TableByID(id int64) (table.Table, bool) {
+	t, ok := l.idx2table[id]
+	return t, ok
+}
+
+// IsTemporaryTable returns whether the table is a local temporary table.
+func (l *LocalTemporaryTables) IsTemporaryTable(tableID int64) bool {
+	_, ok := l.idx2table[tableID]
+	return ok
+}
+
+// SchemaByTable gets the schema of a local temporary table.
+func (l *LocalTemporaryTables) SchemaByTable(tblInfo *model.TableInfo) (*model.DBInfo, bool) {
+	if tblInfo == nil {
+		return nil, false
+	}
+	db, ok := l.idx2db[tblInfo.ID]
+	return db, ok
+}
+
+// AddTable adds a local temporary table into the schema.
+func (l *LocalTemporaryTables) AddTable(db *model.DBInfo, tbl table.Table) error {
+	tbls, ok := l.schemaMap[db.Name.L]
+	if !ok {
+		tbls = make(map[string]table.Table)
+		l.schemaMap[db.Name.L] = tbls
+	}
+	name := tbl.Meta().Name
+	if _, ok := tbls[name.L]; ok {
+		return ErrTableExists.GenWithStackByArgs(name)
+	}
+	tbls[name.L] = tbl
+	l.idx2table[tbl.Meta().ID] = tbl
+	l.idx2db[tbl.Meta().ID] = db
+	return nil
+}
+
+// RemoveTable removes a local temporary table, it returns false if the table doesn't exist.
+func (l *LocalTemporaryTables) RemoveTable(schema, tbl model.CIStr) bool {
+	tbls, ok := l.schemaMap[schema.L]
+	if !ok {
+		return false
+	}
+	t, ok := tbls[tbl.L]
+	if !ok {
+		return false
+	}
+	delete(tbls, tbl.L)
+	if len(tbls) == 0 {
+		delete(l.schemaMap, schema.L)
+	}
+	delete(l.idx2table, t.Meta().ID)
+	delete(l.idx2db, t.Meta().ID)
+	return true
+}
+
+// TableIDs returns the IDs of all the local temporary tables.
+func (l *LocalTemporaryTables) TableIDs() []int64 {
+	ids := make([]int64, 0, len(l.idx2table))
+	for id := range l.idx2table {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Count returns the number of local temporary tables.
+func (l *LocalTemporaryTables) Count() int {
+	return len(l.idx2table)
+}
+
+// TemporaryTableAttachedInfoSchema is an InfoSchema with the local temporary tables of a session attached.
+// The local temporary tables are invisible in SchemaTables, so they don't appear in SHOW TABLES
+// or information_schema.tables, which is the same as MySQL.
+type TemporaryTableAttachedInfoSchema struct {
+	InfoSchema
+	LocalTemporaryTables *LocalTemporaryTables
+}
+
+// AttachLocalTemporaryTables attaches the local temporary tables of the session to the InfoSchema.
+func AttachLocalTemporaryTables(vars *variable.SessionVars, is InfoSchema) InfoSchema {
+	localTables, ok := vars.LocalTemporaryTables.(*LocalTemporaryTables)
+	if !ok || localTables == nil {
+		return is
+	}
+	if attached, ok := is.(*TemporaryTableAttachedInfoSchema); ok {
+		is = attached.InfoSchema
+	}
+	return &TemporaryTableAttachedInfoSchema{
+		InfoSchema:           is,
+		LocalTemporaryTables: localTables,
+	}
+}
+
+// TableByName implements InfoSchema.TableByName.
+func (ts *TemporaryTableAttachedInfoSchema) TableByName(schema, tbl model.CIStr) (table.Table, error) {
+	if t, ok := ts.LocalTemporaryTables.TableByName(schema, tbl); ok {
+		return t, nil
+	}
+	return ts.InfoSchema.TableByName(schema, tbl)
+}
+
+// TableExists implements InfoSchema.TableExists.
+func (ts *TemporaryTableAttachedInfoSchema) TableExists(schema, tbl model.CIStr) bool {
+	if _, ok := ts.LocalTemporaryTables.TableByName(schema, tbl); ok {
+		return true
+	}
+	return ts.InfoSchema.TableExists(schema, tbl)
+}
+
+// TableByID implements InfoSchema.TableByID.
+func (ts *TemporaryTableAttachedInfoSchema) TableByID(id int64) (table.Table, bool) {
+	if t, ok := ts.LocalTemporaryTables.TableByID(id); ok {
+		return t, true
+	}
+	return ts.InfoSchema.TableByID(id)
+}
+
+// SchemaByTable implements InfoSchema.SchemaByTable.
+func (ts *TemporaryTableAttachedInfoSchema) SchemaByTable(tblInfo *model.TableInfo) (*model.DBInfo, bool) {
+	if db, ok := ts.LocalTemporaryTables.SchemaByTable(tblInfo); ok {
+		return db, true
+	}
+	return ts.InfoSchema.SchemaByTable(tblInfo)
+}
+
+// AllocByID implements InfoSchema.AllocByID.
+func (ts *TemporaryTableAttachedInfoSchema) AllocByID(id int64) (autoid.Allocators, bool) {
+	if t, ok := ts.LocalTemporaryTables.TableByID(id); ok {
+		return t.Allocators(nil), true
+	}
+	return ts.InfoSchema.AllocByID(id)
+}
+
+// TableIsView implements InfoSchema.TableIsView.
+func (ts *TemporaryTableAttachedInfoSchema) TableIsView(schema, tbl model.CIStr) bool {
+	if _, ok := ts.LocalTemporaryTables.TableByName(schema, tbl); ok {
+		return false
+	}
+	return ts.InfoSchema.TableIsView(schema, tbl)
+}
+
+// TableIsSequence implements InfoSchema.TableIsSequence.
+func (ts *TemporaryTableAttachedInfoSchema) TableIsSequence(schema, tbl model.CIStr) bool {
+	if _, ok := ts.LocalTemporaryTables.TableByName(schema, tbl); ok {
+		return false
+	}
+	return ts.InfoSchema.TableIsSequence(schema, tbl)
+}
+
+// NewTemporaryTableRetriever returns the kv.TemporaryTableRetriever which reads the committed data of
+// the local temporary tables of the session, it returns nil if the session has no local temporary table.
+func NewTemporaryTableRetriever(vars *variable.SessionVars) kv.TemporaryTableRetriever {
+	localTables, ok := vars.LocalTemporaryTables.(*LocalTemporaryTables)
+	if !ok || localTables == nil || vars.TemporaryTableData == nil {
+		return nil
+	}
+	return &temporaryTableRetriever{
+		Storage:     vars.TemporaryTableData,
+		localTables: localTables,
+	}
+}
+
+type temporaryTableRetriever struct {
+	*temptable.Storage
+	localTables *LocalTemporaryTables
+}
+
+// Get implements kv.Getter interface.
+func (r *temporaryTableRetriever) Get(ctx context.Context, k kv.Key) ([]byte, error) {
+	val, err := r.Storage.Get(ctx, k)
+	if err != nil {
+		return nil, err
+	}
+	// The key has been deleted.
+	if len(val) == 0 {
+		return nil, kv.ErrNotExist
+	}
+	return val, nil
+}
+
+// BatchGet implements kv.BatchGetter interface.
+func (r *temporaryTableRetriever) BatchGet(ctx context.Context, keys []kv.Key) (map[string][]byte, error) {
+	values := make(map[string][]byte, len(keys))
+	for _, k := range keys {
+		val, err := r.Get(ctx, k)
+		if kv.IsErrNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		values[string(k)] = val
+	}
+	return values, nil
+}
+
+// IsTemporaryTable implements kv.TemporaryTableRetriever interface.
+func (r *temporaryTableRetriever) IsTemporaryTable(tableID int64) bool {
+	return r.localTables.IsTemporaryTable(tableID)
+}
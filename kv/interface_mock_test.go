@@ -102,6 +102,10 @@ func (t *mockTxn) GetMemBuffer() MemBuffer {
 	return nil
 }
 
+func (t *mockTxn) RevertToMemCheckpoint(cp MemCheckpoint) {
+
+}
+
 func (t *mockTxn) GetSnapshot() Snapshot {
 	return nil
 }
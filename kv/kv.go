@@ -69,6 +69,16 @@ type Retriever interface {
 	IterReverse(k Key) (Iterator, error)
 }
 
+// TemporaryTableRetriever is used by a transaction to read the local temporary tables of its session.
+// The data of local temporary tables is kept in the memory of the session, so it's never read from
+// or committed to the storage.
+type TemporaryTableRetriever interface {
+	Retriever
+	BatchGetter
+	// IsTemporaryTable returns whether the table is a local temporary table.
+	IsTemporaryTable(tableID int64) bool
+}
+
 // Mutator is the interface wraps the basic Set and Delete methods.
 type Mutator interface {
 	// Set sets the value for key k as v into kv store.
@@ -94,6 +104,9 @@ type RetrieverMutator interface {
 	Mutator
 }
 
+// MemCheckpoint is the position of a MemBuffer returned by MemBuffer.Checkpoint, it's opaque to the users.
+type MemCheckpoint interface{}
+
 // MemBuffer is an in-memory kv collection, can be used to buffer write operations.
 type MemBuffer interface {
 	RetrieverMutator
@@ -124,6 +137,10 @@ type MemBuffer interface {
 	Cleanup(StagingHandle)
 	// InspectStage used to inspect the value updates in the given stage.
 	InspectStage(StagingHandle, func(Key, tikvstore.KeyFlags, []byte))
+	// Checkpoint returns the current position of the MemBuffer.
+	Checkpoint() MemCheckpoint
+	// RevertToCheckpoint discards all modifications after the checkpoint, the flags of the keys are kept.
+	RevertToCheckpoint(MemCheckpoint)
 
 	// SnapshotGetter returns a Getter for a snapshot of MemBuffer.
 	SnapshotGetter() Getter
@@ -171,6 +188,8 @@ type Transaction interface {
 	Valid() bool
 	// GetMemBuffer return the MemBuffer binding to this transaction.
 	GetMemBuffer() MemBuffer
+	// RevertToMemCheckpoint discards the modifications in the MemBuffer after the checkpoint.
+	RevertToMemCheckpoint(MemCheckpoint)
 	// GetSnapshot returns the Snapshot binding to this transaction.
 	GetSnapshot() Snapshot
 	// GetUnionStore returns the UnionStore binding to this transaction.
@@ -291,6 +310,8 @@ type Request struct {
 	IsStaleness bool
 	// MatchStoreLabels indicates the labels the store should be matched
 	MatchStoreLabels []*metapb.StoreLabel
+	// ResourceGroupName is the name of the resource group whose quota throttles the request.
+	ResourceGroupName string
 }
 
 // ResultSubset represents a result subset from a single storage unit.
@@ -406,6 +427,27 @@ type EtcdBackend interface {
 	StartGCWorker() error
 }
 
+// PreparedTransaction is the transaction prepared by committing it with the XAPrepare option.
+type PreparedTransaction interface {
+	// PreparedPrimary returns the primary key of the prepared transaction, it's nil if the transaction writes nothing.
+	PreparedPrimary() []byte
+	// CommitPrepared commits all the keys of the prepared transaction.
+	CommitPrepared(ctx context.Context) error
+	// RollbackPrepared rolls back all the keys of the prepared transaction.
+	RollbackPrepared(ctx context.Context) error
+}
+
+// PreparedTxnStorage is the storage which can finish the prepared transactions by their start ts and primary keys,
+// it's used when the tidb-server preparing them has restarted.
+type PreparedTxnStorage interface {
+	// CommitPreparedTxn commits the prepared transaction.
+	CommitPreparedTxn(ctx context.Context, primary []byte, startTS uint64) error
+	// RollbackPreparedTxn rolls back the prepared transaction.
+	RollbackPreparedTxn(ctx context.Context, primary []byte, startTS uint64) error
+	// KeepPreparedTxnAlive extends the TTL of the locks of the prepared transaction.
+	KeepPreparedTxnAlive(ctx context.Context, primary []byte, startTS uint64) error
+}
+
 // FnKeyCmp is the function for iterator the keys
 type FnKeyCmp func(key Key) bool
 
@@ -0,0 +1,132 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package masking
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/privilege"
+)
+
+const (
+	// TableName is the name of the system table storing the masking rules.
+	TableName = "masking_rules"
+	// LoadInterval is the interval of reloading the masking rules from the system table.
+	LoadInterval = 10 * time.Second
+	// UnmaskPriv is the dynamic privilege to read the original values of the masked columns.
+	UnmaskPriv = "UNMASK"
+)
+
+// Rule is a column masking rule. The queries of the users without the UnmaskPriv read the value of the Expr instead
+// of the column, as if the table were a view replacing the column with the Expr.
+type Rule struct {
+	DB     string `json:"db"`
+	Table  string `json:"table"`
+	Column string `json:"column"`
+	Expr   string `json:"expr"`
+}
+
+// Manager manages the masking rules.
+type Manager struct {
+	mu struct {
+		sync.RWMutex
+		// rules maps the "db.table" to the masking rules of the table, ordered by their columns.
+		rules map[string][]Rule
+		// version is increased every time the rules are changed.
+		version uint64
+	}
+}
+
+// NewManager creates a Manager without any masking rule.
+func NewManager() *Manager {
+	m := &Manager{}
+	m.mu.rules = make(map[string][]Rule)
+	return m
+}
+
+var globalManager = NewManager()
+
+func init() {
+	privilege.MustRegisterDynamicPrivilege(UnmaskPriv)
+}
+
+// GlobalManager returns the Manager of the tidb-server.
+func GlobalManager() *Manager {
+	return globalManager
+}
+
+func tableKey(db, table string) string {
+	return strings.ToLower(db) + "." + strings.ToLower(table)
+}
+
+// update replaces the masking rules, the version is only increased if they are changed.
+func (m *Manager) update(rules map[string][]Rule) {
+	for _, tableRules := range rules {
+		sort.Slice(tableRules, func(i, j int) bool { return tableRules[i].Column < tableRules[j].Column })
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if reflect.DeepEqual(m.mu.rules, rules) {
+		return
+	}
+	m.mu.rules = rules
+	m.mu.version++
+}
+
+// Version returns the version of the masking rules. The plans depending on the rules must be rebuilt after the
+// version is changed.
+func (m *Manager) Version() uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.mu.version
+}
+
+// HasRules returns whether the table has any masking rule.
+func (m *Manager) HasRules(db, table string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.mu.rules[tableKey(db, table)]) > 0
+}
+
+// TableRules returns the masking rules of the table ordered by their columns. The caller should check the UnmaskPriv
+// before applying them.
+func (m *Manager) TableRules(db, table string) []Rule {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]Rule{}, m.mu.rules[tableKey(db, table)]...)
+}
+
+// Rules returns all the masking rules ordered by their tables and columns.
+func (m *Manager) Rules() []Rule {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	rules := make([]Rule, 0, len(m.mu.rules))
+	for _, tableRules := range m.mu.rules {
+		rules = append(rules, tableRules...)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].DB != rules[j].DB {
+			return rules[i].DB < rules[j].DB
+		}
+		if rules[i].Table != rules[j].Table {
+			return rules[i].Table < rules[j].Table
+		}
+		return rules[i].Column < rules[j].Column
+	})
+	return rules
+}
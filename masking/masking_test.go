@@ -0,0 +1,115 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package masking_test
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/masking"
+	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testMaskingSuite{})
+
+type testMaskingSuite struct {
+	store kv.Storage
+	dom   *domain.Domain
+	se    session.Session
+}
+
+func (s *testMaskingSuite) SetUpSuite(c *C) {
+	store, err := mockstore.NewMockStore()
+	c.Assert(err, IsNil)
+	s.store = store
+	session.SetSchemaLease(0)
+	s.dom, err = session.BootstrapSession(s.store)
+	c.Assert(err, IsNil)
+	s.se, err = session.CreateSession4Test(s.store)
+	c.Assert(err, IsNil)
+}
+
+func (s *testMaskingSuite) TearDownSuite(c *C) {
+	s.se.Close()
+	s.dom.Close()
+	s.store.Close()
+}
+
+func (s *testMaskingSuite) TestCreateAndDrop(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t_mask (id int primary key, email varchar(64), card varchar(20))")
+	tk.MustExec("create table t_mask_clustered (id varchar(20) primary key clustered, v int)")
+	tk.MustExec("create view v_mask as select * from t_mask")
+	defer tk.MustExec("drop table t_mask, t_mask_clustered; drop view v_mask")
+
+	manager := masking.GlobalManager()
+	version := manager.Version()
+	rule := masking.Rule{DB: "Test", Table: "T_Mask", Column: "EMAIL", Expr: " mask_email(email) "}
+	c.Assert(manager.CreateRule(s.se, s.dom.InfoSchema(), rule, false), IsNil)
+	c.Assert(manager.Version(), Equals, version+1)
+	expected := masking.Rule{DB: "test", Table: "t_mask", Column: "email", Expr: "mask_email(email)"}
+	c.Assert(manager.TableRules("test", "t_mask"), DeepEquals, []masking.Rule{expected})
+	c.Assert(manager.HasRules("TEST", "T_mask"), IsTrue)
+	tk.MustQuery("select db, table_name, column_name, mask_expr from mysql.masking_rules").Check(
+		testkit.Rows("test t_mask email mask_email(email)"))
+
+	// Reloading the same rules doesn't change the version.
+	c.Assert(manager.Reload(s.se), IsNil)
+	c.Assert(manager.Version(), Equals, version+1)
+
+	// The rule of a masked column is only replaced by OR REPLACE.
+	rule = masking.Rule{DB: "test", Table: "t_mask", Column: "card", Expr: "mask_pan(card)"}
+	c.Assert(manager.CreateRule(s.se, s.dom.InfoSchema(), rule, false), IsNil)
+	rule.Expr = "mask_inner(card, 0, 4, '*')"
+	c.Assert(manager.CreateRule(s.se, s.dom.InfoSchema(), rule, false), ErrorMatches, "masking rule of test.t_mask.card already exists")
+	c.Assert(manager.CreateRule(s.se, s.dom.InfoSchema(), rule, true), IsNil)
+	c.Assert(manager.Rules(), DeepEquals, []masking.Rule{rule, expected})
+
+	c.Assert(manager.DropRule(s.se, "test", "t_mask", "Card", false), IsNil)
+	c.Assert(manager.DropRule(s.se, "test", "t_mask", "card", false), ErrorMatches, "masking rule of test.t_mask.card doesn't exist")
+	c.Assert(manager.DropRule(s.se, "test", "t_mask", "card", true), IsNil)
+	c.Assert(manager.DropRule(s.se, "test", "t_mask", "email", false), IsNil)
+	c.Assert(manager.HasRules("test", "t_mask"), IsFalse)
+	c.Assert(manager.Version(), Equals, version+5)
+
+	// The rules are created on the columns of the base tables, and the expressions must be valid over their columns.
+	for _, ca := range []struct {
+		rule masking.Rule
+		err  string
+	}{
+		{masking.Rule{DB: "test", Table: "t_none", Column: "email", Expr: "'x'"}, ".*doesn't exist"},
+		{masking.Rule{DB: "test", Table: "v_mask", Column: "email", Expr: "'x'"}, "masking rules can only be created on base tables"},
+		{masking.Rule{DB: "mysql", Table: "user", Column: "user", Expr: "'x'"}, "masking rules can only be created on base tables"},
+		{masking.Rule{DB: "test", Table: "t_mask", Column: "phone", Expr: "'x'"}, "column 'phone' doesn't exist in test.t_mask"},
+		{masking.Rule{DB: "test", Table: "t_mask", Column: "id", Expr: "0"}, "the clustered primary key column 'id' can't be masked"},
+		{masking.Rule{DB: "test", Table: "t_mask_clustered", Column: "id", Expr: "'x'"}, "the clustered primary key column 'id' can't be masked"},
+		{masking.Rule{DB: "test", Table: "t_mask", Column: "email", Expr: ""}, "invalid masking expression of column 'email'.*"},
+		{masking.Rule{DB: "test", Table: "t_mask", Column: "email", Expr: "mask_email(unknown)"}, ".*Unknown column.*"},
+		{masking.Rule{DB: "test", Table: "t_mask", Column: "email", Expr: "mask_email(t_mask.email)"}, ".*the column names can't be qualified"},
+		{masking.Rule{DB: "test", Table: "t_mask", Column: "email", Expr: "(select 1)"}, ".*can only reference the columns of the table"},
+		{masking.Rule{DB: "test", Table: "t_mask", Column: "email", Expr: "@a"}, ".*can only reference the columns of the table"},
+	} {
+		c.Assert(manager.CreateRule(s.se, s.dom.InfoSchema(), ca.rule, false), ErrorMatches, ca.err, Commentf("%v", ca.rule))
+	}
+	c.Assert(manager.Rules(), HasLen, 0)
+}
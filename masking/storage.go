@@ -0,0 +1,189 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package masking
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// CreateRule creates a masking rule of the column, orReplace replaces the expression of the rule if it exists. The
+// expression must be a valid expression over the columns of the table, and can't qualify the column names.
+func (m *Manager) CreateRule(sctx sessionctx.Context, is infoschema.InfoSchema, r Rule, orReplace bool) error {
+	r.DB, r.Table, r.Column = strings.ToLower(r.DB), strings.ToLower(r.Table), strings.ToLower(r.Column)
+	r.Expr = strings.TrimSpace(r.Expr)
+	tbl, err := is.TableByName(model.NewCIStr(r.DB), model.NewCIStr(r.Table))
+	if err != nil {
+		return err
+	}
+	if util.IsMemOrSysDB(r.DB) || tbl.Meta().IsView() || tbl.Meta().IsSequence() || tbl.Type().IsVirtualTable() {
+		return errors.Errorf("masking rules can only be created on base tables")
+	}
+	col := table.FindCol(tbl.Cols(), r.Column)
+	if col == nil {
+		return errors.Errorf("column '%s' doesn't exist in %s.%s", r.Column, r.DB, r.Table)
+	}
+	// The handle columns identify the rows locked or written by the statements, so they're kept.
+	if mysql.HasPriKeyFlag(col.Flag) && (tbl.Meta().PKIsHandle || tbl.Meta().IsCommonHandle) {
+		return errors.Errorf("the clustered primary key column '%s' can't be masked", r.Column)
+	}
+	if err = validateExpr(sctx, tbl.Meta(), r.Expr); err != nil {
+		return errors.Annotatef(err, "invalid masking expression of column '%s'", r.Column)
+	}
+	if !orReplace {
+		exists, err := ruleExists(sctx, r.DB, r.Table, r.Column)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return errors.Errorf("masking rule of %s.%s.%s already exists", r.DB, r.Table, r.Column)
+		}
+	}
+	err = execSQL(sctx, "REPLACE INTO %n.%n (db, table_name, column_name, mask_expr) VALUES (%?, %?, %?, %?)",
+		mysql.SystemDB, TableName, r.DB, r.Table, r.Column, r.Expr)
+	if err != nil {
+		return err
+	}
+	return m.Reload(sctx)
+}
+
+// DropRule drops the masking rule of the column, ifExists ignores the rule not existing.
+func (m *Manager) DropRule(sctx sessionctx.Context, db, table, column string, ifExists bool) error {
+	db, table, column = strings.ToLower(db), strings.ToLower(table), strings.ToLower(column)
+	exists, err := ruleExists(sctx, db, table, column)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if ifExists {
+			return nil
+		}
+		return errors.Errorf("masking rule of %s.%s.%s doesn't exist", db, table, column)
+	}
+	err = execSQL(sctx, "DELETE FROM %n.%n WHERE db = %? AND table_name = %? AND column_name = %?",
+		mysql.SystemDB, TableName, db, table, column)
+	if err != nil {
+		return err
+	}
+	return m.Reload(sctx)
+}
+
+func ruleExists(sctx sessionctx.Context, db, table, column string) (bool, error) {
+	rows, err := querySQL(sctx, "SELECT 1 FROM %n.%n WHERE db = %? AND table_name = %? AND column_name = %?",
+		mysql.SystemDB, TableName, db, table, column)
+	return len(rows) > 0, err
+}
+
+// Reload loads the masking rules from the system table.
+func (m *Manager) Reload(sctx sessionctx.Context) error {
+	rows, err := querySQL(sctx, "SELECT db, table_name, column_name, mask_expr FROM %n.%n", mysql.SystemDB, TableName)
+	if err != nil {
+		return err
+	}
+	rules := make(map[string][]Rule)
+	for _, row := range rows {
+		r := Rule{
+			DB:     row.GetString(0),
+			Table:  row.GetString(1),
+			Column: row.GetString(2),
+			Expr:   row.GetString(3),
+		}
+		key := tableKey(r.DB, r.Table)
+		rules[key] = append(rules[key], r)
+	}
+	m.update(rules)
+	return nil
+}
+
+// ParseExpr parses the expression of a masking rule.
+func ParseExpr(expr string) (ast.ExprNode, error) {
+	stmt, err := parser.New().ParseOneStmt("SELECT "+expr, "", "")
+	if err != nil {
+		return nil, err
+	}
+	sel, ok := stmt.(*ast.SelectStmt)
+	if !ok || len(sel.Fields.Fields) != 1 || sel.Fields.Fields[0].Expr == nil || sel.From != nil || sel.Where != nil ||
+		sel.GroupBy != nil || sel.Having != nil || sel.OrderBy != nil || sel.Limit != nil {
+		return nil, errors.Errorf("'%s' is not an expression", expr)
+	}
+	return sel.Fields.Fields[0].Expr, nil
+}
+
+func validateExpr(sctx sessionctx.Context, tblInfo *model.TableInfo, expr string) error {
+	node, err := ParseExpr(expr)
+	if err != nil {
+		return err
+	}
+	checker := &exprChecker{}
+	node.Accept(checker)
+	if checker.err != nil {
+		return checker.err
+	}
+	_, err = expression.RewriteSimpleExprWithTableInfo(sctx, tblInfo, node)
+	return err
+}
+
+// exprChecker checks the masking expression only references the columns of the table by their names, so it can be
+// applied to the table under any alias.
+type exprChecker struct {
+	err error
+}
+
+// Enter implements the ast.Visitor interface.
+func (c *exprChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.ColumnName:
+		if x.Table.L != "" || x.Schema.L != "" {
+			c.err = errors.Errorf("the column names can't be qualified")
+		}
+	case *ast.SubqueryExpr, *ast.ExistsSubqueryExpr, *ast.AggregateFuncExpr, *ast.WindowFuncExpr, *ast.VariableExpr,
+		ast.ParamMarkerExpr, *ast.DefaultExpr, *ast.ValuesExpr:
+		c.err = errors.Errorf("the expression can only reference the columns of the table")
+	}
+	return in, c.err != nil
+}
+
+// Leave implements the ast.Visitor interface.
+func (c *exprChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, c.err == nil
+}
+
+func querySQL(sctx sessionctx.Context, sql string, args ...interface{}) ([]chunk.Row, error) {
+	ctx := context.Background()
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(ctx, sql, args...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+	return rows, errors.Trace(err)
+}
+
+func execSQL(sctx sessionctx.Context, sql string, args ...interface{}) error {
+	_, err := sctx.(sqlexec.SQLExecutor).ExecuteInternal(context.Background(), sql, args...)
+	return errors.Trace(err)
+}
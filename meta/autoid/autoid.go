@@ -130,6 +130,10 @@ type Allocator interface {
 	// cycle option.
 	AllocSeqCache(sequenceID int64) (min int64, max int64, round int64, err error)
 
+	// AllocSeqBatch is like AllocSeqCache, but the returned range covers at least n sequence values unless the sequence
+	// runs out or reaches the cycle boundary. It's used to allocate the values of a bulk insert in one request.
+	AllocSeqBatch(sequenceID int64, n int64) (min int64, max int64, round int64, err error)
+
 	// Rebase rebases the autoID base for table with tableID and the new base value.
 	// If allocIDs is true, it will allocate some IDs and save to the cache.
 	// If allocIDs is false, it will not allocate IDs.
@@ -509,12 +513,17 @@ func (alloc *allocator) Alloc(ctx context.Context, tableID int64, n uint64, incr
 }
 
 func (alloc *allocator) AllocSeqCache(tableID int64) (int64, int64, int64, error) {
+	return alloc.AllocSeqBatch(tableID, 1)
+}
+
+// AllocSeqBatch implements autoid.Allocator AllocSeqBatch interface.
+func (alloc *allocator) AllocSeqBatch(tableID int64, n int64) (int64, int64, int64, error) {
 	if tableID == 0 {
 		return 0, 0, 0, errInvalidTableID.GenWithStackByArgs("Invalid tableID")
 	}
 	alloc.mu.Lock()
 	defer alloc.mu.Unlock()
-	return alloc.alloc4Sequence(tableID)
+	return alloc.alloc4Sequence(tableID, n)
 }
 
 func validIncrementAndOffset(increment, offset int64) bool {
@@ -812,7 +821,8 @@ func (alloc *allocator) alloc4Unsigned(ctx context.Context, tableID int64, n uin
 // 3: sequence allocation may have negative growth.
 // 4: sequence allocation batch length can be dissatisfied.
 // 5: sequence batch allocation will be consumed immediately.
-func (alloc *allocator) alloc4Sequence(tableID int64) (min int64, max int64, round int64, err error) {
+// The batch covers max(n, cache size) values, so a nocache sequence allocates exactly n values in one request.
+func (alloc *allocator) alloc4Sequence(tableID int64, n int64) (min int64, max int64, round int64, err error) {
 	increment := alloc.sequence.Increment
 	offset := alloc.sequence.Start
 	minValue := alloc.sequence.MinValue
@@ -821,6 +831,14 @@ func (alloc *allocator) alloc4Sequence(tableID int64) (min int64, max int64, rou
 	if !alloc.sequence.Cache {
 		cacheSize = 1
 	}
+	if n > cacheSize {
+		// To ensure that size * increment will never overflows, it's the same as the limit of the cache value.
+		maxIncrement := increment
+		if maxIncrement < 0 {
+			maxIncrement = -maxIncrement
+		}
+		cacheSize = mathutil.MinInt64(n, (math.MaxInt64-maxIncrement)/maxIncrement-1)
+	}
 
 	var newBase, newEnd int64
 	startTime := time.Now()
@@ -777,6 +777,20 @@ func (*testSuite) TestSequenceAutoid(c *C) {
 	_, ok = autoid.SeekToFirstSequenceValue(base, seq.Increment, offset, base, end)
 	// the cache is already empty.
 	c.Assert(ok, Equals, false)
+
+	// allocate a batch bigger than the cache size, it covers the 5 values -4, -2, 0, 2, 4.
+	base, end, round, err = alloc.AllocSeqBatch(1, 5)
+	c.Assert(err, IsNil)
+	c.Assert(base, Equals, int64(-6))
+	c.Assert(end, Equals, int64(4))
+	c.Assert(round, Equals, int64(1))
+
+	// the batch is cut by the max value, the rest values are allocated in the next cycle.
+	base, end, round, err = alloc.AllocSeqBatch(1, 5)
+	c.Assert(err, IsNil)
+	c.Assert(base, Equals, int64(4))
+	c.Assert(end, Equals, int64(10))
+	c.Assert(round, Equals, int64(1))
 }
 
 func (*testSuite) TestConcurrentAllocSequence(c *C) {
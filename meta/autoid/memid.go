@@ -0,0 +1,154 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoid
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/pingcap/parser/model"
+)
+
+// NewAllocatorsFromTempTblInfo creates the in-memory allocators of a temporary table.
+// A temporary table is private to its session, so its IDs never need to be persisted.
+func NewAllocatorsFromTempTblInfo(tblInfo *model.TableInfo) Allocators {
+	hasRowID := !tblInfo.PKIsHandle && !tblInfo.IsCommonHandle
+	hasAutoIncID := tblInfo.GetAutoIncrementColInfo() != nil
+	if hasRowID || hasAutoIncID {
+		alloc := newInMemoryAllocator(tblInfo.IsAutoIncColUnsigned(), RowIDAllocType)
+		if tblInfo.AutoIncID > 1 {
+			alloc.base = tblInfo.AutoIncID - 1
+		}
+		return NewAllocators(alloc)
+	}
+	return nil
+}
+
+// inMemoryAllocator is an Allocator which keeps its base in memory only.
+type inMemoryAllocator struct {
+	mu         sync.Mutex
+	base       int64
+	isUnsigned bool
+	allocType  AllocatorType
+}
+
+// newInMemoryAllocator returns an Allocator which never accesses the storage.
+func newInMemoryAllocator(isUnsigned bool, allocType AllocatorType) *inMemoryAllocator {
+	return &inMemoryAllocator{
+		isUnsigned: isUnsigned,
+		allocType:  allocType,
+	}
+}
+
+// Base implements autoid.Allocator Base interface.
+func (alloc *inMemoryAllocator) Base() int64 {
+	alloc.mu.Lock()
+	defer alloc.mu.Unlock()
+	return alloc.base
+}
+
+// End implements autoid.Allocator End interface.
+func (alloc *inMemoryAllocator) End() int64 {
+	alloc.mu.Lock()
+	defer alloc.mu.Unlock()
+	return alloc.base
+}
+
+// GetType implements autoid.Allocator GetType interface.
+func (alloc *inMemoryAllocator) GetType() AllocatorType {
+	return alloc.allocType
+}
+
+// NextGlobalAutoID implements autoid.Allocator NextGlobalAutoID interface.
+func (alloc *inMemoryAllocator) NextGlobalAutoID(tableID int64) (int64, error) {
+	alloc.mu.Lock()
+	defer alloc.mu.Unlock()
+	return alloc.base + 1, nil
+}
+
+// Alloc implements autoid.Allocator Alloc interface.
+func (alloc *inMemoryAllocator) Alloc(ctx context.Context, tableID int64, n uint64, increment, offset int64) (int64, int64, error) {
+	if n == 0 {
+		return 0, 0, nil
+	}
+	if !validIncrementAndOffset(increment, offset) {
+		return 0, 0, errInvalidIncrementAndOffset.GenWithStackByArgs(increment, offset)
+	}
+	alloc.mu.Lock()
+	defer alloc.mu.Unlock()
+	if alloc.isUnsigned {
+		return alloc.alloc4Unsigned(n, increment, offset)
+	}
+	return alloc.alloc4Signed(n, increment, offset)
+}
+
+// Rebase implements autoid.Allocator Rebase interface.
+func (alloc *inMemoryAllocator) Rebase(tableID, requiredBase int64, allocIDs bool) error {
+	alloc.mu.Lock()
+	defer alloc.mu.Unlock()
+	if alloc.isUnsigned {
+		if uint64(requiredBase) > uint64(alloc.base) {
+			alloc.base = requiredBase
+		}
+	} else if requiredBase > alloc.base {
+		alloc.base = requiredBase
+	}
+	return nil
+}
+
+func (alloc *inMemoryAllocator) alloc4Signed(n uint64, increment, offset int64) (int64, int64, error) {
+	// Check offset rebase if necessary.
+	if offset-1 > alloc.base {
+		alloc.base = offset - 1
+	}
+	n1 := CalcNeededBatchSize(alloc.base, int64(n), increment, offset, alloc.isUnsigned)
+	// Condition alloc.base+n1 > alloc.end will overflow when alloc.base + n1 > MaxInt64. So need this.
+	if math.MaxInt64-alloc.base <= n1 {
+		return 0, 0, ErrAutoincReadFailed
+	}
+	min := alloc.base
+	alloc.base += n1
+	return min, alloc.base, nil
+}
+
+func (alloc *inMemoryAllocator) alloc4Unsigned(n uint64, increment, offset int64) (int64, int64, error) {
+	// Check offset rebase if necessary.
+	if uint64(offset)-1 > uint64(alloc.base) {
+		alloc.base = int64(uint64(offset) - 1)
+	}
+	n1 := CalcNeededBatchSize(alloc.base, int64(n), increment, offset, alloc.isUnsigned)
+	// Condition alloc.base+n1 > alloc.end will overflow when alloc.base + n1 > MaxInt64. So need this.
+	if math.MaxUint64-uint64(alloc.base) <= uint64(n1) {
+		return 0, 0, ErrAutoincReadFailed
+	}
+	min := alloc.base
+	alloc.base = int64(uint64(alloc.base) + uint64(n1))
+	return min, alloc.base, nil
+}
+
+// AllocSeqCache implements autoid.Allocator AllocSeqCache interface.
+func (alloc *inMemoryAllocator) AllocSeqCache(tableID int64) (int64, int64, int64, error) {
+	return 0, 0, 0, ErrInvalidAllocatorType.GenWithStackByArgs()
+}
+
+// AllocSeqBatch implements autoid.Allocator AllocSeqBatch interface.
+func (alloc *inMemoryAllocator) AllocSeqBatch(tableID int64, n int64) (int64, int64, int64, error) {
+	return 0, 0, 0, ErrInvalidAllocatorType.GenWithStackByArgs()
+}
+
+// RebaseSeq implements autoid.Allocator RebaseSeq interface.
+func (alloc *inMemoryAllocator) RebaseSeq(tableID, requiredBase int64) (int64, bool, error) {
+	return 0, false, ErrInvalidAllocatorType.GenWithStackByArgs()
+}
@@ -573,27 +573,51 @@ func (m *Meta) GetTable(dbID int64, tableID int64) (*model.TableInfo, error) {
 //	DDLJobList: list jobs
 //	DDLJobHistory: hash
 //	DDLJobReorg: hash
+//	DDLEvent: hash
 //
 // for multi DDL workers, only one can become the owner
 // to operate DDL jobs, and dispatch them to MR Jobs.
 
 var (
-	mDDLJobListKey    = []byte("DDLJobList")
-	mDDLJobAddIdxList = []byte("DDLJobAddIdxList")
-	mDDLJobHistoryKey = []byte("DDLJobHistory")
-	mDDLJobReorgKey   = []byte("DDLJobReorg")
+	mDDLJobListKey      = []byte("DDLJobList")
+	mDDLJobAddIdxList   = []byte("DDLJobAddIdxList")
+	mDDLJobTableListKey = []byte("DDLJobTableList")
+	mDDLJobHistoryKey   = []byte("DDLJobHistory")
+	mDDLJobReorgKey     = []byte("DDLJobReorg")
+	mDDLEventKey        = []byte("DDLEvent")
 )
 
 // JobListKeyType is a key type of the DDL job queue.
 type JobListKeyType []byte
 
 var (
-	// DefaultJobListKey keeps all actions of DDL jobs except "add index".
+	// DefaultJobListKey keeps all actions of DDL jobs except "add index" and the ones in the table job queues.
 	DefaultJobListKey JobListKeyType = mDDLJobListKey
 	// AddIndexJobListKey only keeps the action of adding index.
 	AddIndexJobListKey JobListKeyType = mDDLJobAddIdxList
 )
 
+// TableJobListKeyCnt is the number of the table job queues.
+const TableJobListKeyCnt = 4
+
+// TableJobListKey returns the key of the idx-th table job queue.
+// The table job queues keep the jobs which only change one table, the jobs of a table are always in the same queue.
+func TableJobListKey(idx int) JobListKeyType {
+	key := make([]byte, 0, len(mDDLJobTableListKey)+2)
+	key = append(key, mDDLJobTableListKey...)
+	return strconv.AppendInt(key, int64(idx), 10)
+}
+
+// AllJobListKeys returns the keys of all the DDL job queues.
+func AllJobListKeys() []JobListKeyType {
+	keys := make([]JobListKeyType, 0, 2+TableJobListKeyCnt)
+	keys = append(keys, DefaultJobListKey, AddIndexJobListKey)
+	for i := 0; i < TableJobListKeyCnt; i++ {
+		keys = append(keys, TableJobListKey(i))
+	}
+	return keys
+}
+
 func (m *Meta) enQueueDDLJob(key []byte, job *model.Job) error {
 	b, err := job.Encode(true)
 	if err == nil {
@@ -764,6 +788,13 @@ func (m *Meta) reorgJobEndHandle(id int64, element *Element) []byte {
 	return b
 }
 
+func (m *Meta) reorgJobProgress(id int64) []byte {
+	b := make([]byte, 0, 17)
+	b = append(b, m.jobIDKey(id)...)
+	b = append(b, "_progress"...)
+	return b
+}
+
 func (m *Meta) reorgJobPhysicalTableID(id int64, element *Element) []byte {
 	b := make([]byte, 8, 25)
 	binary.BigEndian.PutUint64(b, uint64(id))
@@ -900,6 +931,60 @@ func (s *jobsSorter) Less(i, j int) bool {
 	return s.jobs[i].ID < s.jobs[j].ID
 }
 
+// DDLEvent is the notification of a committed DDL job, the events are ordered by the schema version.
+type DDLEvent struct {
+	SchemaVersion int64            `json:"schema_version"`
+	JobID         int64            `json:"job_id"`
+	Type          model.ActionType `json:"type"`
+	TypeName      string           `json:"type_name"`
+	SchemaID      int64            `json:"schema_id"`
+	TableID       int64            `json:"table_id"`
+	SchemaName    string           `json:"schema_name"`
+	TableName     string           `json:"table_name"`
+	Query         string           `json:"query"`
+	FinishedTS    uint64           `json:"finished_ts"`
+}
+
+// AddDDLEvent adds the event of a committed DDL job.
+func (m *Meta) AddDDLEvent(event *DDLEvent) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return m.txn.HSet(mDDLEventKey, m.jobIDKey(event.SchemaVersion), value)
+}
+
+// GetDDLEvents gets at most limit DDL events whose schema versions are greater than startVersion in ascending order.
+// All the events after startVersion are returned if limit isn't positive.
+func (m *Meta) GetDDLEvents(startVersion int64, limit int) ([]*DDLEvent, error) {
+	iter, err := structure.NewHashReverseIter(m.txn, mDDLEventKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer iter.Close()
+	var events []*DDLEvent
+	for iter.Valid() {
+		event := &DDLEvent{}
+		if err = json.Unmarshal(iter.Value(), event); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if event.SchemaVersion <= startVersion {
+			break
+		}
+		events = append(events, event)
+		if err = iter.Next(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+	if limit > 0 && len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
 // GetBootstrapVersion returns the version of the server which bootstrap the store.
 // If the store is not bootstraped, the version will be zero.
 func (m *Meta) GetBootstrapVersion() (int64, error) {
@@ -921,6 +1006,8 @@ var (
 	ColumnElementKey ElementKeyType = []byte("_col_")
 	// IndexElementKey is the key for index element.
 	IndexElementKey ElementKeyType = []byte("_idx_")
+	// PartitionElementKey is the key for partition element.
+	PartitionElementKey ElementKeyType = []byte("_prt_")
 )
 
 const elementKeyLen = 5
@@ -961,6 +1048,8 @@ func DecodeElement(b []byte) (*Element, error) {
 		tp = IndexElementKey
 	case string(ColumnElementKey):
 		tp = ColumnElementKey
+	case string(PartitionElementKey):
+		tp = PartitionElementKey
 	default:
 		return nil, errors.Errorf("invalid encoded element key prefix %q", prefix)
 	}
@@ -1018,6 +1107,9 @@ func (m *Meta) RemoveReorgElement(job *model.Job) error {
 
 // RemoveDDLReorgHandle removes the job reorganization related handles.
 func (m *Meta) RemoveDDLReorgHandle(job *model.Job, elements []*Element) error {
+	if err := m.txn.HDel(mDDLJobReorgKey, m.reorgJobProgress(job.ID)); err != nil {
+		return errors.Trace(err)
+	}
 	if len(elements) == 0 {
 		return nil
 	}
@@ -1042,6 +1134,37 @@ func (m *Meta) RemoveDDLReorgHandle(job *model.Job, elements []*Element) error {
 	return nil
 }
 
+// DDLReorgProgress is the progress of a reorganization job, it's updated periodically while the job is running.
+type DDLReorgProgress struct {
+	// TotalRows is the estimated number of the rows to reorganize, it's from the statistics.
+	TotalRows int64 `json:"total_rows"`
+	// RowRate is the number of the reorganized rows per second since the last update.
+	RowRate float64 `json:"row_rate"`
+	// StartKey and EndKey are the decoded key range being backfilled.
+	StartKey string `json:"start_key"`
+	EndKey   string `json:"end_key"`
+}
+
+// UpdateDDLReorgProgress saves the progress of the reorganization job.
+func (m *Meta) UpdateDDLReorgProgress(job *model.Job, progress *DDLReorgProgress) error {
+	value, err := json.Marshal(progress)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return m.txn.HSet(mDDLJobReorgKey, m.reorgJobProgress(job.ID), value)
+}
+
+// GetDDLReorgProgress gets the progress of the reorganization job, it returns nil if the progress isn't saved.
+func (m *Meta) GetDDLReorgProgress(job *model.Job) (*DDLReorgProgress, error) {
+	value, err := m.txn.HGet(mDDLJobReorgKey, m.reorgJobProgress(job.ID))
+	if err != nil || value == nil {
+		return nil, errors.Trace(err)
+	}
+	progress := &DDLReorgProgress{}
+	err = json.Unmarshal(value, progress)
+	return progress, errors.Trace(err)
+}
+
 // GetDDLReorgHandle gets the latest processed DDL reorganize position.
 func (m *Meta) GetDDLReorgHandle(job *model.Job) (element *Element, startKey, endKey kv.Key, physicalTableID int64, err error) {
 	elementBytes, err := m.txn.HGet(mDDLJobReorgKey, m.reorgJobCurrentElement(job.ID))
@@ -327,6 +327,7 @@ func (s *testSuite) TestElement(c *C) {
 	checkElement(key, errors.Errorf(`invalid encoded element key prefix "_col\x00"`))
 	checkElement(meta.IndexElementKey, nil)
 	checkElement(meta.ColumnElementKey, nil)
+	checkElement(meta.PartitionElementKey, nil)
 	key = []byte("inexistent")
 	checkElement(key, errors.Errorf("invalid encoded element key prefix %q", key[:5]))
 
@@ -399,8 +400,21 @@ func (s *testSuite) TestDDL(c *C) {
 	c.Assert(j, DeepEquals, kv.Key(endHandle.Encoded()))
 	c.Assert(k, Equals, int64(3))
 
+	progress, err := t.GetDDLReorgProgress(job)
+	c.Assert(err, IsNil)
+	c.Assert(progress, IsNil)
+	expectedProgress := &meta.DDLReorgProgress{TotalRows: 100, RowRate: 12.5, StartKey: "1", EndKey: "100"}
+	err = t.UpdateDDLReorgProgress(job, expectedProgress)
+	c.Assert(err, IsNil)
+	progress, err = t.GetDDLReorgProgress(job)
+	c.Assert(err, IsNil)
+	c.Assert(progress, DeepEquals, expectedProgress)
+
 	err = t.RemoveDDLReorgHandle(job, []*meta.Element{element, element1})
 	c.Assert(err, IsNil)
+	progress, err = t.GetDDLReorgProgress(job)
+	c.Assert(err, IsNil)
+	c.Assert(progress, IsNil)
 	e, i, j, k, err = t.GetDDLReorgHandle(job)
 	c.Assert(meta.ErrDDLReorgElementNotExist.Equal(err), IsTrue)
 	c.Assert(e, IsNil)
@@ -463,6 +477,30 @@ func (s *testSuite) TestDDL(c *C) {
 	c.Assert(historyJobs[0].ID == 1234, IsTrue)
 	c.Assert(historyJobs[1].ID == 123, IsTrue)
 
+	// Test for the DDL events.
+	events, err := t.GetDDLEvents(0, 0)
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 0)
+	for _, ver := range []int64{12, 10, 11} {
+		err = t.AddDDLEvent(&meta.DDLEvent{SchemaVersion: ver, JobID: ver + 100, Type: model.ActionAddColumn, Query: "alter table t add column c int"})
+		c.Assert(err, IsNil)
+	}
+	events, err = t.GetDDLEvents(0, 0)
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 3)
+	for i, event := range events {
+		c.Assert(event.SchemaVersion, Equals, int64(10+i))
+		c.Assert(event.JobID, Equals, int64(110+i))
+		c.Assert(event.Type, Equals, model.ActionAddColumn)
+	}
+	events, err = t.GetDDLEvents(10, 1)
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 1)
+	c.Assert(events[0].SchemaVersion, Equals, int64(11))
+	events, err = t.GetDDLEvents(12, 0)
+	c.Assert(err, IsNil)
+	c.Assert(events, HasLen, 0)
+
 	// Test GetAllDDLJobsInQueue.
 	err = t.EnQueueDDLJob(job)
 	c.Assert(err, IsNil)
@@ -498,6 +536,19 @@ func (s *testSuite) TestDDL(c *C) {
 	expectJobs = []*model.Job{job}
 	c.Assert(jobs, DeepEquals, expectJobs)
 
+	// Test for the table job queues.
+	c.Assert(meta.AllJobListKeys(), HasLen, 2+meta.TableJobListKeyCnt)
+	c.Assert(meta.TableJobListKey(0), Not(DeepEquals), meta.TableJobListKey(1))
+	m = meta.NewMeta(txn1, meta.TableJobListKey(1))
+	err = m.EnQueueDDLJob(job1)
+	c.Assert(err, IsNil)
+	l, err = m.DDLJobQueueLen(meta.TableJobListKey(0))
+	c.Assert(err, IsNil)
+	c.Assert(l, Equals, int64(0))
+	jobs, err = m.GetAllDDLJobsInQueue()
+	c.Assert(err, IsNil)
+	c.Assert(jobs, DeepEquals, []*model.Job{job1})
+
 	err = txn1.Commit(context.Background())
 	c.Assert(err, IsNil)
 
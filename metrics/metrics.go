@@ -95,6 +95,10 @@ func RegisterMetrics() {
 	prometheus.MustRegister(HandleJobHistogram)
 	prometheus.MustRegister(SignificantFeedbackCounter)
 	prometheus.MustRegister(FastAnalyzeHistogram)
+	prometheus.MustRegister(StatsCacheLRUCounter)
+	prometheus.MustRegister(StatsHistogramLoadDuration)
+	prometheus.MustRegister(StatsHealthyGauge)
+	prometheus.MustRegister(StatsMissingColumnStatsGauge)
 	prometheus.MustRegister(JobsGauge)
 	prometheus.MustRegister(KeepAliveCounter)
 	prometheus.MustRegister(LoadPrivilegeCounter)
@@ -105,6 +109,7 @@ func RegisterMetrics() {
 	prometheus.MustRegister(OwnerHandleSyncerHistogram)
 	prometheus.MustRegister(PanicCounter)
 	prometheus.MustRegister(PlanCacheCounter)
+	prometheus.MustRegister(PlanCacheInvalidationCounter)
 	prometheus.MustRegister(PseudoEstimation)
 	prometheus.MustRegister(PacketIOHistogram)
 	prometheus.MustRegister(QueryDurationHistogram)
@@ -140,6 +145,8 @@ func RegisterMetrics() {
 	prometheus.MustRegister(TotalQueryProcHistogram)
 	prometheus.MustRegister(TotalCopProcHistogram)
 	prometheus.MustRegister(TotalCopWaitHistogram)
+	prometheus.MustRegister(SampledQueryCounter)
+	prometheus.MustRegister(SlowQueryDroppedCounter)
 	prometheus.MustRegister(HandleSchemaValidate)
 	prometheus.MustRegister(MaxProcs)
 	prometheus.MustRegister(GOGC)
@@ -148,8 +155,21 @@ func RegisterMetrics() {
 	prometheus.MustRegister(TokenGauge)
 	prometheus.MustRegister(ConfigStatus)
 	prometheus.MustRegister(TiFlashQueryTotalCounter)
+	prometheus.MustRegister(WorkerPoolQueueLength)
+	prometheus.MustRegister(WorkerPoolQueueDuration)
+	prometheus.MustRegister(WorkerPoolRejectedCounter)
+	prometheus.MustRegister(CursorGauge)
+	prometheus.MustRegister(CursorUsageGauge)
+	prometheus.MustRegister(ConnRejectedCounter)
+	prometheus.MustRegister(StmtResponseBytesHistogram)
 	prometheus.MustRegister(SmallTxnWriteDuration)
 	prometheus.MustRegister(TxnWriteThroughput)
+	prometheus.MustRegister(TTLJobCounter)
+	prometheus.MustRegister(TTLDeletedRowsCounter)
+	prometheus.MustRegister(TTLDeleteDuration)
+	prometheus.MustRegister(ResourceGroupRUCounter)
+	prometheus.MustRegister(ResourceGroupThrottledDuration)
+	prometheus.MustRegister(ResourceGroupQuotaGauge)
 
 	tikvmetrics.InitMetrics(TiDB, TiKVClient)
 	tikvmetrics.RegisterMetrics()
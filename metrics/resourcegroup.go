@@ -0,0 +1,56 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics for the resource groups.
+var (
+	// ResourceGroupRUCounter records the request units consumed by the resource groups.
+	ResourceGroupRUCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "resource_group",
+			Name:      "consumed_ru_total",
+			Help:      "Counter of request units consumed by the resource groups.",
+		}, []string{LblName, LblType})
+
+	// ResourceGroupThrottledDuration records the time the requests of the resource groups wait for the quota.
+	ResourceGroupThrottledDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "tidb",
+			Subsystem: "resource_group",
+			Name:      "throttled_duration_seconds",
+			Help:      "Bucketed histogram of the time (s) the throttled requests wait for the quota of their resource group.",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 2, 20), // 1ms ~ 524s
+		}, []string{LblName})
+
+	// ResourceGroupQuotaGauge records the RU_PER_SEC quota of the resource groups.
+	ResourceGroupQuotaGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb",
+			Subsystem: "resource_group",
+			Name:      "ru_per_sec",
+			Help:      "The request units per second quota of the resource groups.",
+		}, []string{LblName})
+)
+
+// Label constants of the resource group metrics.
+const (
+	LblName  = "name"
+	LblRead  = "read"
+	LblWrite = "write"
+)
@@ -128,6 +128,14 @@ var (
 			Help:      "Counter of query using plan cache.",
 		}, []string{LblType})
 
+	PlanCacheInvalidationCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "plan_cache_invalidation_total",
+			Help:      "Counter of the cached plans invalidated by the changes of the schema, the stats or the bindings.",
+		}, []string{LblType})
+
 	HandShakeErrorCounter = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Namespace: "tidb",
@@ -170,6 +178,21 @@ var (
 			Help:      "Bucketed histogram of all cop waiting time (s) of of slow queries.",
 			Buckets:   prometheus.ExponentialBuckets(0.001, 2, 28), // 1ms ~ 1.5days
 		}, []string{LblSQLType})
+	SampledQueryCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "slow_query_sampled_total",
+			Help:      "Counter of queries below the slow threshold that are logged by sampling.",
+		})
+
+	SlowQueryDroppedCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "slow_query_dropped_total",
+			Help:      "Counter of queries above the slow threshold that are not logged by sampling.",
+		})
 
 	MaxProcs = prometheus.NewGauge(
 		prometheus.GaugeOpts{
@@ -228,6 +251,84 @@ var (
 			Name:      "tiflash_query_total",
 			Help:      "Counter of TiFlash queries.",
 		}, []string{LblResult})
+
+	WorkerPoolQueueLength = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "worker_pool_queue_length",
+			Help:      "Number of the requests queued in the worker pool.",
+		}, []string{LblPriority})
+
+	WorkerPoolQueueDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "worker_pool_queue_duration_seconds",
+			Help:      "Bucketed histogram of the time (s) the requests wait in the queue of the worker pool.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 20), // 0.5ms ~ 262s
+		}, []string{LblPriority})
+
+	WorkerPoolRejectedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "worker_pool_rejected_total",
+			Help:      "Counter of the requests rejected by the worker pool.",
+		}, []string{LblPriority, LblReason})
+
+	CursorGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "open_cursors",
+			Help:      "Number of the open server-side cursors.",
+		})
+
+	CursorUsageGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "cursor_usage_bytes",
+			Help:      "Bytes of the rows kept by the open server-side cursors in memory and on disk.",
+		}, []string{LblType})
+
+	ConnRejectedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "connection_rejected_total",
+			Help:      "Counter of the connections rejected by the connection limits.",
+		}, []string{LblType})
+
+	StmtResponseBytesHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "statement_response_bytes",
+			Help:      "Bucketed histogram of the bytes of the result sets written to the client by each statement.",
+			Buckets:   prometheus.ExponentialBuckets(64, 4, 16), // 64Bytes ~ 64GB
+		}, []string{LblSQLType})
+)
+
+// Label constants of the worker pool.
+const (
+	LblPriority = "priority"
+	LblReason   = "reason"
+)
+
+// Label constants of the cursor usage.
+const (
+	LblMemory = "memory"
+	LblDisk   = "disk"
+)
+
+// Label constants of the connection limits.
+const (
+	LblMaxServerConnections  = "max_server_connections"
+	LblMaxConnectionsPerIP   = "max_connections_per_ip"
+	LblMaxUserConnections    = "max_user_connections"
+	LblMaxConnectionsPerHour = "max_connections_per_hour"
 )
 
 // ExecuteErrorToLabel converts an execute error to label.
@@ -93,4 +93,37 @@ var (
 			Help:      "Bucketed histogram of some stats in fast analyze.",
 			Buckets:   prometheus.ExponentialBuckets(1, 2, 16),
 		}, []string{LblSQLType, LblType})
+
+	StatsCacheLRUCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "statistics",
+			Name:      "stats_cache_lru_op_total",
+			Help:      "Counter of hit, miss and evict of the stats cache.",
+		}, []string{LblType})
+
+	StatsHistogramLoadDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "tidb",
+			Subsystem: "statistics",
+			Name:      "histogram_load_duration_seconds",
+			Help:      "Bucketed histogram of processing time (s) of loading the needed histograms of columns.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 20), // 0.5ms ~ 262s
+		})
+
+	StatsHealthyGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb",
+			Subsystem: "statistics",
+			Name:      "stats_healthy",
+			Help:      "Gauge of the number of the tables grouped by the healthy of their stats.",
+		}, []string{LblType})
+
+	StatsMissingColumnStatsGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "tidb",
+			Subsystem: "statistics",
+			Name:      "missing_column_stats",
+			Help:      "Gauge of the number of the tables which have columns without stats.",
+		})
 )
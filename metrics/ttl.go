@@ -0,0 +1,49 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics for the TTL jobs.
+var (
+	// TTLJobCounter records the counter of TTL jobs.
+	TTLJobCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "ttl",
+			Name:      "jobs_total",
+			Help:      "Counter of TTL jobs.",
+		}, []string{LblResult})
+
+	// TTLDeletedRowsCounter records the counter of rows deleted by TTL jobs.
+	TTLDeletedRowsCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "ttl",
+			Name:      "deleted_rows_total",
+			Help:      "Counter of rows deleted by TTL jobs.",
+		})
+
+	// TTLDeleteDuration records the duration of deleting a batch of expired rows.
+	TTLDeleteDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "tidb",
+			Subsystem: "ttl",
+			Name:      "delete_duration_seconds",
+			Help:      "Bucketed histogram of processing time (s) of deleting a batch of expired rows.",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 2, 20), // 1ms ~ 524s
+		})
+)
@@ -0,0 +1,1323 @@
+// Code generated by goyacc DO NOT EDIT.
+
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import __yyfmt__ "fmt"
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+)
+
+type yyhintSymType struct {
+	yys         int
+	offset      int
+	ident       string
+	number      uint64
+	hint        *ast.TableOptimizerHint
+	hints       []*ast.TableOptimizerHint
+	table       ast.HintTable
+	modelIdents []model.CIStr
+}
+
+type yyhintXError struct {
+	state, xsym int
+}
+
+const (
+	yyhintDefault             = 57414
+	yyhintEOFCode             = 57344
+	yyhintErrCode             = 57345
+	hintAggToCop              = 57376
+	hintBCJoin                = 57389
+	hintBCJoinPreferLocal     = 57390
+	hintBKA                   = 57354
+	hintBNL                   = 57356
+	hintDupsWeedOut           = 57410
+	hintFalse                 = 57406
+	hintFirstMatch            = 57411
+	hintGB                    = 57409
+	hintHashAgg               = 57378
+	hintHashJoin              = 57358
+	hintIdentifier            = 57347
+	hintIgnoreIndex           = 57379
+	hintIgnorePlanCache       = 57377
+	hintIndexMerge            = 57362
+	hintInlHashJoin           = 57380
+	hintInlJoin               = 57381
+	hintInlMergeJoin          = 57382
+	hintIntLit                = 57346
+	hintJoinFixedOrder        = 57350
+	hintJoinOrder             = 57351
+	hintJoinPrefix            = 57352
+	hintJoinSuffix            = 57353
+	hintLimitToCop            = 57400
+	hintLooseScan             = 57412
+	hintMB                    = 57408
+	hintMRR                   = 57364
+	hintMaterialization       = 57413
+	hintMaxExecutionTime      = 57372
+	hintMemoryQuota           = 57383
+	hintMerge                 = 57360
+	hintNoBKA                 = 57355
+	hintNoBNL                 = 57357
+	hintNoHashJoin            = 57359
+	hintNoICP                 = 57366
+	hintNoIndexMerge          = 57363
+	hintNoMRR                 = 57365
+	hintNoMerge               = 57361
+	hintNoRangeOptimization   = 57367
+	hintNoSemijoin            = 57371
+	hintNoSkipScan            = 57369
+	hintNoSwapJoinInputs      = 57384
+	hintNthPlan               = 57399
+	hintOLAP                  = 57401
+	hintOLTP                  = 57402
+	hintPartition             = 57403
+	hintQBName                = 57375
+	hintQueryType             = 57385
+	hintReadConsistentReplica = 57386
+	hintReadFromStorage       = 57387
+	hintResourceGroup         = 57374
+	hintSMJoin                = 57388
+	hintSemijoin              = 57370
+	hintSetVar                = 57373
+	hintSingleAtIdentifier    = 57348
+	hintSkipScan              = 57368
+	hintStreamAgg             = 57391
+	hintStringLit             = 57349
+	hintSwapJoinInputs        = 57392
+	hintTiFlash               = 57405
+	hintTiKV                  = 57404
+	hintTimeRange             = 57397
+	hintTrue                  = 57407
+	hintUseCascades           = 57398
+	hintUseIndex              = 57394
+	hintUseIndexMerge         = 57393
+	hintUsePlanCache          = 57395
+	hintUseToja               = 57396
+
+	yyhintMaxDepth = 200
+	yyhintTabOfs   = -170
+)
+
+var (
+	yyhintXLAT = map[int]int{
+		41:    0,   // ')' (129x)
+		57376: 1,   // hintAggToCop (121x)
+		57389: 2,   // hintBCJoin (121x)
+		57390: 3,   // hintBCJoinPreferLocal (121x)
+		57354: 4,   // hintBKA (121x)
+		57356: 5,   // hintBNL (121x)
+		57378: 6,   // hintHashAgg (121x)
+		57358: 7,   // hintHashJoin (121x)
+		57379: 8,   // hintIgnoreIndex (121x)
+		57377: 9,   // hintIgnorePlanCache (121x)
+		57362: 10,  // hintIndexMerge (121x)
+		57380: 11,  // hintInlHashJoin (121x)
+		57381: 12,  // hintInlJoin (121x)
+		57382: 13,  // hintInlMergeJoin (121x)
+		57350: 14,  // hintJoinFixedOrder (121x)
+		57351: 15,  // hintJoinOrder (121x)
+		57352: 16,  // hintJoinPrefix (121x)
+		57353: 17,  // hintJoinSuffix (121x)
+		57400: 18,  // hintLimitToCop (121x)
+		57372: 19,  // hintMaxExecutionTime (121x)
+		57383: 20,  // hintMemoryQuota (121x)
+		57360: 21,  // hintMerge (121x)
+		57364: 22,  // hintMRR (121x)
+		57355: 23,  // hintNoBKA (121x)
+		57357: 24,  // hintNoBNL (121x)
+		57359: 25,  // hintNoHashJoin (121x)
+		57366: 26,  // hintNoICP (121x)
+		57363: 27,  // hintNoIndexMerge (121x)
+		57361: 28,  // hintNoMerge (121x)
+		57365: 29,  // hintNoMRR (121x)
+		57367: 30,  // hintNoRangeOptimization (121x)
+		57371: 31,  // hintNoSemijoin (121x)
+		57369: 32,  // hintNoSkipScan (121x)
+		57384: 33,  // hintNoSwapJoinInputs (121x)
+		57399: 34,  // hintNthPlan (121x)
+		57375: 35,  // hintQBName (121x)
+		57385: 36,  // hintQueryType (121x)
+		57386: 37,  // hintReadConsistentReplica (121x)
+		57387: 38,  // hintReadFromStorage (121x)
+		57374: 39,  // hintResourceGroup (121x)
+		57370: 40,  // hintSemijoin (121x)
+		57373: 41,  // hintSetVar (121x)
+		57368: 42,  // hintSkipScan (121x)
+		57388: 43,  // hintSMJoin (121x)
+		57391: 44,  // hintStreamAgg (121x)
+		57392: 45,  // hintSwapJoinInputs (121x)
+		57397: 46,  // hintTimeRange (121x)
+		57398: 47,  // hintUseCascades (121x)
+		57394: 48,  // hintUseIndex (121x)
+		57393: 49,  // hintUseIndexMerge (121x)
+		57395: 50,  // hintUsePlanCache (121x)
+		57396: 51,  // hintUseToja (121x)
+		44:    52,  // ',' (119x)
+		57410: 53,  // hintDupsWeedOut (99x)
+		57411: 54,  // hintFirstMatch (99x)
+		57412: 55,  // hintLooseScan (99x)
+		57413: 56,  // hintMaterialization (99x)
+		57405: 57,  // hintTiFlash (99x)
+		57404: 58,  // hintTiKV (99x)
+		57406: 59,  // hintFalse (98x)
+		57401: 60,  // hintOLAP (98x)
+		57402: 61,  // hintOLTP (98x)
+		57407: 62,  // hintTrue (98x)
+		57409: 63,  // hintGB (97x)
+		57408: 64,  // hintMB (97x)
+		57347: 65,  // hintIdentifier (96x)
+		57348: 66,  // hintSingleAtIdentifier (81x)
+		93:    67,  // ']' (75x)
+		57403: 68,  // hintPartition (69x)
+		46:    69,  // '.' (65x)
+		61:    70,  // '=' (65x)
+		40:    71,  // '(' (60x)
+		57344: 72,  // $end (24x)
+		57434: 73,  // QueryBlockOpt (17x)
+		57426: 74,  // Identifier (13x)
+		57346: 75,  // hintIntLit (8x)
+		57349: 76,  // hintStringLit (5x)
+		57416: 77,  // CommaOpt (4x)
+		57422: 78,  // HintTable (4x)
+		57423: 79,  // HintTableList (4x)
+		91:    80,  // '[' (3x)
+		57415: 81,  // BooleanHintName (2x)
+		57417: 82,  // HintIndexList (2x)
+		57419: 83,  // HintStorageType (2x)
+		57420: 84,  // HintStorageTypeAndTable (2x)
+		57424: 85,  // HintTableListOpt (2x)
+		57429: 86,  // JoinOrderOptimizerHintName (2x)
+		57430: 87,  // NullaryHintName (2x)
+		57433: 88,  // PartitionListOpt (2x)
+		57436: 89,  // StorageOptimizerHintOpt (2x)
+		57437: 90,  // SubqueryOptimizerHintName (2x)
+		57440: 91,  // SubqueryStrategy (2x)
+		57441: 92,  // SupportedIndexLevelOptimizerHintName (2x)
+		57442: 93,  // SupportedTableLevelOptimizerHintName (2x)
+		57443: 94,  // TableOptimizerHintOpt (2x)
+		57445: 95,  // UnsupportedIndexLevelOptimizerHintName (2x)
+		57446: 96,  // UnsupportedTableLevelOptimizerHintName (2x)
+		57418: 97,  // HintQueryType (1x)
+		57421: 98,  // HintStorageTypeAndTableList (1x)
+		57425: 99,  // HintTrueOrFalse (1x)
+		57427: 100, // IndexNameList (1x)
+		57428: 101, // IndexNameListOpt (1x)
+		57431: 102, // OptimizerHintList (1x)
+		57432: 103, // PartitionList (1x)
+		57435: 104, // Start (1x)
+		57438: 105, // SubqueryStrategies (1x)
+		57439: 106, // SubqueryStrategiesOpt (1x)
+		57444: 107, // UnitOfBytes (1x)
+		57447: 108, // Value (1x)
+		57414: 109, // $default (0x)
+		57345: 110, // error (0x)
+	}
+
+	yyhintSymNames = []string{
+		"')'",
+		"hintAggToCop",
+		"hintBCJoin",
+		"hintBCJoinPreferLocal",
+		"hintBKA",
+		"hintBNL",
+		"hintHashAgg",
+		"hintHashJoin",
+		"hintIgnoreIndex",
+		"hintIgnorePlanCache",
+		"hintIndexMerge",
+		"hintInlHashJoin",
+		"hintInlJoin",
+		"hintInlMergeJoin",
+		"hintJoinFixedOrder",
+		"hintJoinOrder",
+		"hintJoinPrefix",
+		"hintJoinSuffix",
+		"hintLimitToCop",
+		"hintMaxExecutionTime",
+		"hintMemoryQuota",
+		"hintMerge",
+		"hintMRR",
+		"hintNoBKA",
+		"hintNoBNL",
+		"hintNoHashJoin",
+		"hintNoICP",
+		"hintNoIndexMerge",
+		"hintNoMerge",
+		"hintNoMRR",
+		"hintNoRangeOptimization",
+		"hintNoSemijoin",
+		"hintNoSkipScan",
+		"hintNoSwapJoinInputs",
+		"hintNthPlan",
+		"hintQBName",
+		"hintQueryType",
+		"hintReadConsistentReplica",
+		"hintReadFromStorage",
+		"hintResourceGroup",
+		"hintSemijoin",
+		"hintSetVar",
+		"hintSkipScan",
+		"hintSMJoin",
+		"hintStreamAgg",
+		"hintSwapJoinInputs",
+		"hintTimeRange",
+		"hintUseCascades",
+		"hintUseIndex",
+		"hintUseIndexMerge",
+		"hintUsePlanCache",
+		"hintUseToja",
+		"','",
+		"hintDupsWeedOut",
+		"hintFirstMatch",
+		"hintLooseScan",
+		"hintMaterialization",
+		"hintTiFlash",
+		"hintTiKV",
+		"hintFalse",
+		"hintOLAP",
+		"hintOLTP",
+		"hintTrue",
+		"hintGB",
+		"hintMB",
+		"hintIdentifier",
+		"hintSingleAtIdentifier",
+		"']'",
+		"hintPartition",
+		"'.'",
+		"'='",
+		"'('",
+		"$end",
+		"QueryBlockOpt",
+		"Identifier",
+		"hintIntLit",
+		"hintStringLit",
+		"CommaOpt",
+		"HintTable",
+		"HintTableList",
+		"'['",
+		"BooleanHintName",
+		"HintIndexList",
+		"HintStorageType",
+		"HintStorageTypeAndTable",
+		"HintTableListOpt",
+		"JoinOrderOptimizerHintName",
+		"NullaryHintName",
+		"PartitionListOpt",
+		"StorageOptimizerHintOpt",
+		"SubqueryOptimizerHintName",
+		"SubqueryStrategy",
+		"SupportedIndexLevelOptimizerHintName",
+		"SupportedTableLevelOptimizerHintName",
+		"TableOptimizerHintOpt",
+		"UnsupportedIndexLevelOptimizerHintName",
+		"UnsupportedTableLevelOptimizerHintName",
+		"HintQueryType",
+		"HintStorageTypeAndTableList",
+		"HintTrueOrFalse",
+		"IndexNameList",
+		"IndexNameListOpt",
+		"OptimizerHintList",
+		"PartitionList",
+		"Start",
+		"SubqueryStrategies",
+		"SubqueryStrategiesOpt",
+		"UnitOfBytes",
+		"Value",
+		"$default",
+		"error",
+	}
+
+	yyhintReductions = []struct{ xsym, components int }{
+		{0, 1},
+		{104, 1},
+		{102, 1},
+		{102, 3},
+		{102, 1},
+		{102, 3},
+		{94, 4},
+		{94, 4},
+		{94, 4},
+		{94, 4},
+		{94, 4},
+		{94, 4},
+		{94, 5},
+		{94, 5},
+		{94, 5},
+		{94, 6},
+		{94, 4},
+		{94, 4},
+		{94, 6},
+		{94, 6},
+		{94, 5},
+		{94, 4},
+		{94, 5},
+		{89, 5},
+		{98, 1},
+		{98, 3},
+		{84, 4},
+		{73, 0},
+		{73, 1},
+		{77, 0},
+		{77, 1},
+		{88, 0},
+		{88, 4},
+		{103, 1},
+		{103, 3},
+		{85, 1},
+		{85, 1},
+		{79, 2},
+		{79, 3},
+		{78, 3},
+		{78, 5},
+		{82, 4},
+		{101, 0},
+		{101, 1},
+		{100, 1},
+		{100, 3},
+		{106, 0},
+		{106, 1},
+		{105, 1},
+		{105, 3},
+		{108, 1},
+		{108, 1},
+		{108, 1},
+		{107, 1},
+		{107, 1},
+		{99, 1},
+		{99, 1},
+		{86, 1},
+		{86, 1},
+		{86, 1},
+		{96, 1},
+		{96, 1},
+		{96, 1},
+		{96, 1},
+		{96, 1},
+		{96, 1},
+		{96, 1},
+		{93, 1},
+		{93, 1},
+		{93, 1},
+		{93, 1},
+		{93, 1},
+		{93, 1},
+		{93, 1},
+		{93, 1},
+		{93, 1},
+		{95, 1},
+		{95, 1},
+		{95, 1},
+		{95, 1},
+		{95, 1},
+		{95, 1},
+		{95, 1},
+		{92, 1},
+		{92, 1},
+		{92, 1},
+		{90, 1},
+		{90, 1},
+		{91, 1},
+		{91, 1},
+		{91, 1},
+		{91, 1},
+		{81, 1},
+		{81, 1},
+		{87, 1},
+		{87, 1},
+		{87, 1},
+		{87, 1},
+		{87, 1},
+		{87, 1},
+		{87, 1},
+		{87, 1},
+		{97, 1},
+		{97, 1},
+		{83, 1},
+		{83, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+		{74, 1},
+	}
+
+	yyhintXErrors = map[yyhintXError]string{}
+
+	yyhintParseTab = [253][]uint16{
+		// 0
+		{1: 229, 204, 205, 196, 198, 227, 211, 220, 233, 212, 207, 206, 210, 175, 193, 194, 195, 230, 182, 187, 201, 213, 197, 199, 200, 215, 231, 202, 214, 216, 223, 218, 209, 183, 186, 191, 232, 192, 185, 222, 184, 217, 203, 228, 208, 188, 225, 219, 221, 226, 224, 81: 189, 86: 176, 190, 89: 174, 181, 92: 180, 178, 173, 179, 177, 102: 172, 104: 171},
+		{72: 170},
+		{1: 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 320, 72: 169, 77: 420},
+		{1: 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 168, 72: 168},
+		{1: 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 166, 72: 166},
+		// 5
+		{71: 417},
+		{71: 414},
+		{71: 411},
+		{71: 406},
+		{71: 403},
+		// 10
+		{71: 392},
+		{71: 380},
+		{71: 376},
+		{71: 372},
+		{71: 364},
+		// 15
+		{71: 361},
+		{71: 358},
+		{71: 351},
+		{71: 346},
+		{71: 340},
+		// 20
+		{71: 337},
+		{71: 331},
+		{71: 234},
+		{71: 113},
+		{71: 112},
+		// 25
+		{71: 111},
+		{71: 110},
+		{71: 109},
+		{71: 108},
+		{71: 107},
+		// 30
+		{71: 106},
+		{71: 105},
+		{71: 104},
+		{71: 103},
+		{71: 102},
+		// 35
+		{71: 101},
+		{71: 100},
+		{71: 99},
+		{71: 98},
+		{71: 97},
+		// 40
+		{71: 96},
+		{71: 95},
+		{71: 94},
+		{71: 93},
+		{71: 92},
+		// 45
+		{71: 91},
+		{71: 90},
+		{71: 89},
+		{71: 88},
+		{71: 87},
+		// 50
+		{71: 86},
+		{71: 85},
+		{71: 84},
+		{71: 83},
+		{71: 78},
+		// 55
+		{71: 77},
+		{71: 76},
+		{71: 75},
+		{71: 74},
+		{71: 73},
+		// 60
+		{71: 72},
+		{71: 71},
+		{71: 70},
+		{71: 69},
+		{57: 143, 143, 66: 236, 73: 235},
+		// 65
+		{57: 241, 240, 83: 239, 238, 98: 237},
+		{142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 67: 142, 142, 75: 142},
+		{328, 52: 329},
+		{146, 52: 146},
+		{80: 242},
+		// 70
+		{80: 66},
+		{80: 65},
+		{1: 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 53: 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 236, 73: 244, 79: 243},
+		{52: 326, 67: 325},
+		{1: 274, 288, 289, 252, 254, 277, 256, 278, 276, 260, 279, 280, 281, 248, 249, 250, 251, 275, 270, 282, 258, 262, 253, 255, 257, 264, 261, 259, 263, 265, 269, 267, 283, 298, 273, 284, 285, 286, 272, 268, 271, 266, 287, 290, 291, 296, 297, 293, 292, 294, 295, 53: 307, 308, 309, 310, 302, 301, 303, 299, 300, 304, 306, 305, 247, 74: 246, 78: 245},
+		// 75
+		{133, 52: 133, 67: 133},
+		{143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 236, 143, 143, 312, 73: 311},
+		{64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64, 64},
+		{63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63, 63},
+		{62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62, 62},
+		// 80
+		{61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61, 61},
+		{60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60},
+		{59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59},
+		{58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58},
+		{57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57},
+		// 85
+		{56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56},
+		{55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55},
+		{54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54},
+		{53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53},
+		{52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52},
+		// 90
+		{51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51},
+		{50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50},
+		{49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49},
+		{48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48},
+		{47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47},
+		// 95
+		{46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46},
+		{45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45},
+		{44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44},
+		{43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43},
+		{42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42},
+		// 100
+		{41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41},
+		{40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40},
+		{39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39},
+		{38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38},
+		{37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37},
+		// 105
+		{36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36},
+		{35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35},
+		{34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34},
+		{33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33},
+		{32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32},
+		// 110
+		{31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31},
+		{30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+		{29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29},
+		{28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+		{27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27},
+		// 115
+		{26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26},
+		{25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25},
+		{24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24},
+		{23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23},
+		{22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22},
+		// 120
+		{21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21},
+		{20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20},
+		{19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19},
+		{18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18},
+		{17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17},
+		// 125
+		{16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16},
+		{15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15},
+		{14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14},
+		{13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13},
+		{12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12},
+		// 130
+		{11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11},
+		{10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10},
+		{9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9},
+		{8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8},
+		{7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7},
+		// 135
+		{6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6},
+		{5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5},
+		{4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4},
+		{3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3},
+		{2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2},
+		// 140
+		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+		{139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 67: 139, 315, 88: 324},
+		{1: 274, 288, 289, 252, 254, 277, 256, 278, 276, 260, 279, 280, 281, 248, 249, 250, 251, 275, 270, 282, 258, 262, 253, 255, 257, 264, 261, 259, 263, 265, 269, 267, 283, 298, 273, 284, 285, 286, 272, 268, 271, 266, 287, 290, 291, 296, 297, 293, 292, 294, 295, 53: 307, 308, 309, 310, 302, 301, 303, 299, 300, 304, 306, 305, 247, 74: 313},
+		{143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 236, 143, 143, 73: 314},
+		{139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 67: 139, 315, 88: 316},
+		// 145
+		{71: 317},
+		{130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 67: 130},
+		{1: 274, 288, 289, 252, 254, 277, 256, 278, 276, 260, 279, 280, 281, 248, 249, 250, 251, 275, 270, 282, 258, 262, 253, 255, 257, 264, 261, 259, 263, 265, 269, 267, 283, 298, 273, 284, 285, 286, 272, 268, 271, 266, 287, 290, 291, 296, 297, 293, 292, 294, 295, 53: 307, 308, 309, 310, 302, 301, 303, 299, 300, 304, 306, 305, 247, 74: 319, 103: 318},
+		{321, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 320, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 77: 322},
+		{137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137},
+		// 150
+		{140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 53: 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 76: 140},
+		{138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 67: 138},
+		{1: 274, 288, 289, 252, 254, 277, 256, 278, 276, 260, 279, 280, 281, 248, 249, 250, 251, 275, 270, 282, 258, 262, 253, 255, 257, 264, 261, 259, 263, 265, 269, 267, 283, 298, 273, 284, 285, 286, 272, 268, 271, 266, 287, 290, 291, 296, 297, 293, 292, 294, 295, 53: 307, 308, 309, 310, 302, 301, 303, 299, 300, 304, 306, 305, 247, 74: 323},
+		{136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136},
+		{131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 67: 131},
+		// 155
+		{144, 52: 144},
+		{1: 274, 288, 289, 252, 254, 277, 256, 278, 276, 260, 279, 280, 281, 248, 249, 250, 251, 275, 270, 282, 258, 262, 253, 255, 257, 264, 261, 259, 263, 265, 269, 267, 283, 298, 273, 284, 285, 286, 272, 268, 271, 266, 287, 290, 291, 296, 297, 293, 292, 294, 295, 53: 307, 308, 309, 310, 302, 301, 303, 299, 300, 304, 306, 305, 247, 74: 246, 78: 327},
+		{132, 52: 132, 67: 132},
+		{1: 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 72: 147},
+		{57: 241, 240, 83: 239, 330},
+		// 160
+		{145, 52: 145},
+		{60: 143, 143, 66: 236, 73: 332},
+		{60: 334, 335, 97: 333},
+		{336},
+		{68},
+		// 165
+		{67},
+		{1: 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 72: 148},
+		{143, 66: 236, 73: 338},
+		{339},
+		{1: 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 72: 149},
+		// 170
+		{59: 143, 62: 143, 66: 236, 73: 341},
+		{59: 344, 62: 343, 99: 342},
+		{345},
+		{115},
+		{114},
+		// 175
+		{1: 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 72: 150},
+		{76: 347},
+		{52: 320, 76: 141, 348},
+		{76: 349},
+		{350},
+		// 180
+		{1: 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 72: 151},
+		{66: 236, 73: 352, 75: 143},
+		{75: 353},
+		{63: 356, 355, 107: 354},
+		{357},
+		// 185
+		{117},
+		{116},
+		{1: 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 72: 152},
+		{1: 274, 288, 289, 252, 254, 277, 256, 278, 276, 260, 279, 280, 281, 248, 249, 250, 251, 275, 270, 282, 258, 262, 253, 255, 257, 264, 261, 259, 263, 265, 269, 267, 283, 298, 273, 284, 285, 286, 272, 268, 271, 266, 287, 290, 291, 296, 297, 293, 292, 294, 295, 53: 307, 308, 309, 310, 302, 301, 303, 299, 300, 304, 306, 305, 247, 74: 359},
+		{360},
+		// 190
+		{1: 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 72: 153},
+		{1: 274, 288, 289, 252, 254, 277, 256, 278, 276, 260, 279, 280, 281, 248, 249, 250, 251, 275, 270, 282, 258, 262, 253, 255, 257, 264, 261, 259, 263, 265, 269, 267, 283, 298, 273, 284, 285, 286, 272, 268, 271, 266, 287, 290, 291, 296, 297, 293, 292, 294, 295, 53: 307, 308, 309, 310, 302, 301, 303, 299, 300, 304, 306, 305, 247, 74: 362},
+		{363},
+		{1: 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 72: 154},
+		{1: 274, 288, 289, 252, 254, 277, 256, 278, 276, 260, 279, 280, 281, 248, 249, 250, 251, 275, 270, 282, 258, 262, 253, 255, 257, 264, 261, 259, 263, 265, 269, 267, 283, 298, 273, 284, 285, 286, 272, 268, 271, 266, 287, 290, 291, 296, 297, 293, 292, 294, 295, 53: 307, 308, 309, 310, 302, 301, 303, 299, 300, 304, 306, 305, 247, 74: 365},
+		// 195
+		{70: 366},
+		{1: 274, 288, 289, 252, 254, 277, 256, 278, 276, 260, 279, 280, 281, 248, 249, 250, 251, 275, 270, 282, 258, 262, 253, 255, 257, 264, 261, 259, 263, 265, 269, 267, 283, 298, 273, 284, 285, 286, 272, 268, 271, 266, 287, 290, 291, 296, 297, 293, 292, 294, 295, 53: 307, 308, 309, 310, 302, 301, 303, 299, 300, 304, 306, 305, 247, 74: 369, 370, 368, 108: 367},
+		{371},
+		{120},
+		{119},
+		// 200
+		{118},
+		{1: 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 72: 155},
+		{66: 236, 73: 373, 75: 143},
+		{75: 374},
+		{375},
+		// 205
+		{1: 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 72: 156},
+		{66: 236, 73: 377, 75: 143},
+		{75: 378},
+		{379},
+		{1: 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 157, 72: 157},
+		// 210
+		{143, 53: 143, 143, 143, 143, 66: 236, 73: 381},
+		{124, 53: 385, 386, 387, 388, 91: 384, 105: 383, 382},
+		{391},
+		{123, 52: 389},
+		{122, 52: 122},
+		// 215
+		{82, 52: 82},
+		{81, 52: 81},
+		{80, 52: 80},
+		{79, 52: 79},
+		{53: 385, 386, 387, 388, 91: 390},
+		// 220
+		{121, 52: 121},
+		{1: 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 158, 72: 158},
+		{1: 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 53: 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 236, 73: 394, 82: 393},
+		{402},
+		{1: 274, 288, 289, 252, 254, 277, 256, 278, 276, 260, 279, 280, 281, 248, 249, 250, 251, 275, 270, 282, 258, 262, 253, 255, 257, 264, 261, 259, 263, 265, 269, 267, 283, 298, 273, 284, 285, 286, 272, 268, 271, 266, 287, 290, 291, 296, 297, 293, 292, 294, 295, 53: 307, 308, 309, 310, 302, 301, 303, 299, 300, 304, 306, 305, 247, 74: 246, 78: 395},
+		// 225
+		{141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 320, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 77: 396},
+		{128, 274, 288, 289, 252, 254, 277, 256, 278, 276, 260, 279, 280, 281, 248, 249, 250, 251, 275, 270, 282, 258, 262, 253, 255, 257, 264, 261, 259, 263, 265, 269, 267, 283, 298, 273, 284, 285, 286, 272, 268, 271, 266, 287, 290, 291, 296, 297, 293, 292, 294, 295, 53: 307, 308, 309, 310, 302, 301, 303, 299, 300, 304, 306, 305, 247, 74: 399, 100: 398, 397},
+		{129},
+		{127, 52: 400},
+		{126, 52: 126},
+		// 230
+		{1: 274, 288, 289, 252, 254, 277, 256, 278, 276, 260, 279, 280, 281, 248, 249, 250, 251, 275, 270, 282, 258, 262, 253, 255, 257, 264, 261, 259, 263, 265, 269, 267, 283, 298, 273, 284, 285, 286, 272, 268, 271, 266, 287, 290, 291, 296, 297, 293, 292, 294, 295, 53: 307, 308, 309, 310, 302, 301, 303, 299, 300, 304, 306, 305, 247, 74: 401},
+		{125, 52: 125},
+		{1: 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 159, 72: 159},
+		{1: 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 53: 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 236, 73: 394, 82: 404},
+		{405},
+		// 235
+		{1: 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 160, 72: 160},
+		{143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 53: 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 236, 73: 409, 79: 408, 85: 407},
+		{410},
+		{135, 52: 326},
+		{134, 274, 288, 289, 252, 254, 277, 256, 278, 276, 260, 279, 280, 281, 248, 249, 250, 251, 275, 270, 282, 258, 262, 253, 255, 257, 264, 261, 259, 263, 265, 269, 267, 283, 298, 273, 284, 285, 286, 272, 268, 271, 266, 287, 290, 291, 296, 297, 293, 292, 294, 295, 53: 307, 308, 309, 310, 302, 301, 303, 299, 300, 304, 306, 305, 247, 74: 246, 78: 245},
+		// 240
+		{1: 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 161, 72: 161},
+		{143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 53: 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 236, 73: 409, 79: 408, 85: 412},
+		{413},
+		{1: 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 162, 72: 162},
+		{1: 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 53: 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 236, 73: 244, 79: 415},
+		// 245
+		{416, 52: 326},
+		{1: 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 163, 72: 163},
+		{143, 66: 236, 73: 418},
+		{419},
+		{1: 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 164, 72: 164},
+		// 250
+		{1: 229, 204, 205, 196, 198, 227, 211, 220, 233, 212, 207, 206, 210, 175, 193, 194, 195, 230, 182, 187, 201, 213, 197, 199, 200, 215, 231, 202, 214, 216, 223, 218, 209, 183, 186, 191, 232, 192, 185, 222, 184, 217, 203, 228, 208, 188, 225, 219, 221, 226, 224, 81: 189, 86: 176, 190, 89: 422, 181, 92: 180, 178, 421, 179, 177},
+		{1: 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 167, 72: 167},
+		{1: 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 165, 72: 165},
+	}
+)
+
+var yyhintDebug = 0
+
+type yyhintLexer interface {
+	Lex(lval *yyhintSymType) int
+	Errorf(format string, a ...interface{}) error
+	AppendError(err error)
+	Errors() (warns []error, errs []error)
+}
+
+type yyhintLexerEx interface {
+	yyhintLexer
+	Reduced(rule, state int, lval *yyhintSymType) bool
+}
+
+func yyhintSymName(c int) (s string) {
+	x, ok := yyhintXLAT[c]
+	if ok {
+		return yyhintSymNames[x]
+	}
+
+	return __yyfmt__.Sprintf("%d", c)
+}
+
+func yyhintlex1(yylex yyhintLexer, lval *yyhintSymType) (n int) {
+	n = yylex.Lex(lval)
+	if n <= 0 {
+		n = yyhintEOFCode
+	}
+	if yyhintDebug >= 3 {
+		__yyfmt__.Printf("\nlex %s(%#x %d), lval: %+v\n", yyhintSymName(n), n, n, lval)
+	}
+	return n
+}
+
+func yyhintParse(yylex yyhintLexer, parser *hintParser) int {
+	const yyError = 110
+
+	yyEx, _ := yylex.(yyhintLexerEx)
+	var yyn int
+	parser.yylval = yyhintSymType{}
+	yyS := parser.cache
+
+	Nerrs := 0   /* number of errors */
+	Errflag := 0 /* error recovery flag */
+	yyerrok := func() {
+		if yyhintDebug >= 2 {
+			__yyfmt__.Printf("yyerrok()\n")
+		}
+		Errflag = 0
+	}
+	_ = yyerrok
+	yystate := 0
+	yychar := -1
+	var yyxchar int
+	var yyshift int
+	yyp := -1
+	goto yystack
+
+ret0:
+	return 0
+
+ret1:
+	return 1
+
+yystack:
+	/* put a state and value onto the stack */
+	yyp++
+	if yyp+1 >= len(yyS) {
+		nyys := make([]yyhintSymType, len(yyS)*2)
+		copy(nyys, yyS)
+		yyS = nyys
+		parser.cache = yyS
+	}
+	parser.yyVAL = &yyS[yyp+1]
+	yyS[yyp].yys = yystate
+
+yynewstate:
+	if yychar < 0 {
+		yychar = yyhintlex1(yylex, &parser.yylval)
+		var ok bool
+		if yyxchar, ok = yyhintXLAT[yychar]; !ok {
+			yyxchar = len(yyhintSymNames) // > tab width
+		}
+	}
+	if yyhintDebug >= 4 {
+		var a []int
+		for _, v := range yyS[:yyp+1] {
+			a = append(a, v.yys)
+		}
+		__yyfmt__.Printf("state stack %v\n", a)
+	}
+	row := yyhintParseTab[yystate]
+	yyn = 0
+	if yyxchar < len(row) {
+		if yyn = int(row[yyxchar]); yyn != 0 {
+			yyn += yyhintTabOfs
+		}
+	}
+	switch {
+	case yyn > 0: // shift
+		yychar = -1
+		*parser.yyVAL = parser.yylval
+		yystate = yyn
+		yyshift = yyn
+		if yyhintDebug >= 2 {
+			__yyfmt__.Printf("shift, and goto state %d\n", yystate)
+		}
+		if Errflag > 0 {
+			Errflag--
+		}
+		goto yystack
+	case yyn < 0: // reduce
+	case yystate == 1: // accept
+		if yyhintDebug >= 2 {
+			__yyfmt__.Println("accept")
+		}
+		goto ret0
+	}
+
+	if yyn == 0 {
+		/* error ... attempt to resume parsing */
+		switch Errflag {
+		case 0: /* brand new error */
+			if yyhintDebug >= 1 {
+				__yyfmt__.Printf("no action for %s in state %d\n", yyhintSymName(yychar), yystate)
+			}
+			msg, ok := yyhintXErrors[yyhintXError{yystate, yyxchar}]
+			if !ok {
+				msg, ok = yyhintXErrors[yyhintXError{yystate, -1}]
+			}
+			if !ok && yyshift != 0 {
+				msg, ok = yyhintXErrors[yyhintXError{yyshift, yyxchar}]
+			}
+			if !ok {
+				msg, ok = yyhintXErrors[yyhintXError{yyshift, -1}]
+			}
+			if !ok || msg == "" {
+				msg = "syntax error"
+			}
+			// ignore goyacc error message
+			yylex.AppendError(yylex.Errorf(""))
+			Nerrs++
+			fallthrough
+
+		case 1, 2: /* incompletely recovered error ... try again */
+			Errflag = 3
+
+			/* find a state where "error" is a legal shift action */
+			for yyp >= 0 {
+				row := yyhintParseTab[yyS[yyp].yys]
+				if yyError < len(row) {
+					yyn = int(row[yyError]) + yyhintTabOfs
+					if yyn > 0 { // hit
+						if yyhintDebug >= 2 {
+							__yyfmt__.Printf("error recovery found error shift in state %d\n", yyS[yyp].yys)
+						}
+						yystate = yyn /* simulate a shift of "error" */
+						goto yystack
+					}
+				}
+
+				/* the current p has no shift on "error", pop stack */
+				if yyhintDebug >= 2 {
+					__yyfmt__.Printf("error recovery pops state %d\n", yyS[yyp].yys)
+				}
+				yyp--
+			}
+			/* there is no state on the stack with an error shift ... abort */
+			if yyhintDebug >= 2 {
+				__yyfmt__.Printf("error recovery failed\n")
+			}
+			goto ret1
+
+		case 3: /* no shift yet; clobber input char */
+			if yyhintDebug >= 2 {
+				__yyfmt__.Printf("error recovery discards %s\n", yyhintSymName(yychar))
+			}
+			if yychar == yyhintEOFCode {
+				goto ret1
+			}
+
+			yychar = -1
+			goto yynewstate /* try again in the same state */
+		}
+	}
+
+	r := -yyn
+	x0 := yyhintReductions[r]
+	x, n := x0.xsym, x0.components
+	yypt := yyp
+	_ = yypt // guard against "declared and not used"
+
+	yyp -= n
+	if yyp+1 >= len(yyS) {
+		nyys := make([]yyhintSymType, len(yyS)*2)
+		copy(nyys, yyS)
+		yyS = nyys
+		parser.cache = yyS
+	}
+	parser.yyVAL = &yyS[yyp+1]
+
+	/* consult goto table to find next state */
+	exState := yystate
+	yystate = int(yyhintParseTab[yyS[yyp].yys][x]) + yyhintTabOfs
+	/* reduction by production r */
+	if yyhintDebug >= 2 {
+		__yyfmt__.Printf("reduce using rule %v (%s), and goto state %d\n", r, yyhintSymNames[x], yystate)
+	}
+
+	switch r {
+	case 1:
+		{
+			parser.result = yyS[yypt-0].hints
+		}
+	case 2:
+		{
+			if yyS[yypt-0].hint != nil {
+				parser.yyVAL.hints = []*ast.TableOptimizerHint{yyS[yypt-0].hint}
+			}
+		}
+	case 3:
+		{
+			if yyS[yypt-0].hint != nil {
+				parser.yyVAL.hints = append(yyS[yypt-2].hints, yyS[yypt-0].hint)
+			} else {
+				parser.yyVAL.hints = yyS[yypt-2].hints
+			}
+		}
+	case 4:
+		{
+			parser.yyVAL.hints = yyS[yypt-0].hints
+		}
+	case 5:
+		{
+			parser.yyVAL.hints = append(yyS[yypt-2].hints, yyS[yypt-0].hints...)
+		}
+	case 6:
+		{
+			parser.warnUnsupportedHint(yyS[yypt-3].ident)
+			parser.yyVAL.hint = nil
+		}
+	case 7:
+		{
+			parser.warnUnsupportedHint(yyS[yypt-3].ident)
+			parser.yyVAL.hint = nil
+		}
+	case 8:
+		{
+			parser.warnUnsupportedHint(yyS[yypt-3].ident)
+			parser.yyVAL.hint = nil
+		}
+	case 9:
+		{
+			h := yyS[yypt-1].hint
+			h.HintName = model.NewCIStr(yyS[yypt-3].ident)
+			parser.yyVAL.hint = h
+		}
+	case 10:
+		{
+			parser.warnUnsupportedHint(yyS[yypt-3].ident)
+			parser.yyVAL.hint = nil
+		}
+	case 11:
+		{
+			h := yyS[yypt-1].hint
+			h.HintName = model.NewCIStr(yyS[yypt-3].ident)
+			parser.yyVAL.hint = h
+		}
+	case 12:
+		{
+			parser.warnUnsupportedHint(yyS[yypt-4].ident)
+			parser.yyVAL.hint = nil
+		}
+	case 13:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{
+				HintName: model.NewCIStr(yyS[yypt-4].ident),
+				QBName:   model.NewCIStr(yyS[yypt-2].ident),
+				HintData: yyS[yypt-1].number,
+			}
+		}
+	case 14:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{
+				HintName: model.NewCIStr(yyS[yypt-4].ident),
+				QBName:   model.NewCIStr(yyS[yypt-2].ident),
+				HintData: int64(yyS[yypt-1].number),
+			}
+		}
+	case 15:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{
+				HintName: model.NewCIStr(yyS[yypt-5].ident),
+				HintData: ast.HintSetVar{
+					VarName: yyS[yypt-3].ident,
+					Value:   yyS[yypt-1].ident,
+				},
+			}
+		}
+	case 16:
+		{
+			parser.warnUnsupportedHint(yyS[yypt-3].ident)
+			parser.yyVAL.hint = nil
+		}
+	case 17:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{
+				HintName: model.NewCIStr(yyS[yypt-3].ident),
+				QBName:   model.NewCIStr(yyS[yypt-1].ident),
+			}
+		}
+	case 18:
+		{
+			maxValue := uint64(math.MaxInt64) / yyS[yypt-1].number
+			if yyS[yypt-2].number <= maxValue {
+				parser.yyVAL.hint = &ast.TableOptimizerHint{
+					HintName: model.NewCIStr(yyS[yypt-5].ident),
+					HintData: int64(yyS[yypt-2].number * yyS[yypt-1].number),
+					QBName:   model.NewCIStr(yyS[yypt-3].ident),
+				}
+			} else {
+				yylex.AppendError(ErrWarnMemoryQuotaOverflow.GenWithStackByArgs(math.MaxInt64))
+				parser.lastErrorAsWarn()
+				parser.yyVAL.hint = nil
+			}
+		}
+	case 19:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{
+				HintName: model.NewCIStr(yyS[yypt-5].ident),
+				HintData: ast.HintTimeRange{
+					From: yyS[yypt-3].ident,
+					To:   yyS[yypt-1].ident,
+				},
+			}
+		}
+	case 20:
+		{
+			h := yyS[yypt-1].hint
+			h.HintName = model.NewCIStr(yyS[yypt-4].ident)
+			h.QBName = model.NewCIStr(yyS[yypt-2].ident)
+			parser.yyVAL.hint = h
+		}
+	case 21:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{
+				HintName: model.NewCIStr(yyS[yypt-3].ident),
+				QBName:   model.NewCIStr(yyS[yypt-1].ident),
+			}
+		}
+	case 22:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{
+				HintName: model.NewCIStr(yyS[yypt-4].ident),
+				QBName:   model.NewCIStr(yyS[yypt-2].ident),
+				HintData: model.NewCIStr(yyS[yypt-1].ident),
+			}
+		}
+	case 23:
+		{
+			hs := yyS[yypt-1].hints
+			name := model.NewCIStr(yyS[yypt-4].ident)
+			qb := model.NewCIStr(yyS[yypt-2].ident)
+			for _, h := range hs {
+				h.HintName = name
+				h.QBName = qb
+			}
+			parser.yyVAL.hints = hs
+		}
+	case 24:
+		{
+			parser.yyVAL.hints = []*ast.TableOptimizerHint{yyS[yypt-0].hint}
+		}
+	case 25:
+		{
+			parser.yyVAL.hints = append(yyS[yypt-2].hints, yyS[yypt-0].hint)
+		}
+	case 26:
+		{
+			h := yyS[yypt-1].hint
+			h.HintData = model.NewCIStr(yyS[yypt-3].ident)
+			parser.yyVAL.hint = h
+		}
+	case 27:
+		{
+			parser.yyVAL.ident = ""
+		}
+	case 31:
+		{
+			parser.yyVAL.modelIdents = nil
+		}
+	case 32:
+		{
+			parser.yyVAL.modelIdents = yyS[yypt-1].modelIdents
+		}
+	case 33:
+		{
+			parser.yyVAL.modelIdents = []model.CIStr{model.NewCIStr(yyS[yypt-0].ident)}
+		}
+	case 34:
+		{
+			parser.yyVAL.modelIdents = append(yyS[yypt-2].modelIdents, model.NewCIStr(yyS[yypt-0].ident))
+		}
+	case 36:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{
+				QBName: model.NewCIStr(yyS[yypt-0].ident),
+			}
+		}
+	case 37:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{
+				Tables: []ast.HintTable{yyS[yypt-0].table},
+				QBName: model.NewCIStr(yyS[yypt-1].ident),
+			}
+		}
+	case 38:
+		{
+			h := yyS[yypt-2].hint
+			h.Tables = append(h.Tables, yyS[yypt-0].table)
+			parser.yyVAL.hint = h
+		}
+	case 39:
+		{
+			parser.yyVAL.table = ast.HintTable{
+				TableName:     model.NewCIStr(yyS[yypt-2].ident),
+				QBName:        model.NewCIStr(yyS[yypt-1].ident),
+				PartitionList: yyS[yypt-0].modelIdents,
+			}
+		}
+	case 40:
+		{
+			parser.yyVAL.table = ast.HintTable{
+				DBName:        model.NewCIStr(yyS[yypt-4].ident),
+				TableName:     model.NewCIStr(yyS[yypt-2].ident),
+				QBName:        model.NewCIStr(yyS[yypt-1].ident),
+				PartitionList: yyS[yypt-0].modelIdents,
+			}
+		}
+	case 41:
+		{
+			h := yyS[yypt-0].hint
+			h.Tables = []ast.HintTable{yyS[yypt-2].table}
+			h.QBName = model.NewCIStr(yyS[yypt-3].ident)
+			parser.yyVAL.hint = h
+		}
+	case 42:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{}
+		}
+	case 44:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{
+				Indexes: []model.CIStr{model.NewCIStr(yyS[yypt-0].ident)},
+			}
+		}
+	case 45:
+		{
+			h := yyS[yypt-2].hint
+			h.Indexes = append(h.Indexes, model.NewCIStr(yyS[yypt-0].ident))
+			parser.yyVAL.hint = h
+		}
+	case 52:
+		{
+			parser.yyVAL.ident = strconv.FormatUint(yyS[yypt-0].number, 10)
+		}
+	case 53:
+		{
+			parser.yyVAL.number = 1024 * 1024
+		}
+	case 54:
+		{
+			parser.yyVAL.number = 1024 * 1024 * 1024
+		}
+	case 55:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{HintData: true}
+		}
+	case 56:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{HintData: false}
+		}
+
+	}
+
+	yyhintSetOffset(parser.yyVAL, parser.yyVAL.offset)
+
+	if yyEx != nil && yyEx.Reduced(r, exState, parser.yyVAL) {
+		return -1
+	}
+	goto yystack /* stack new state and value */
+}
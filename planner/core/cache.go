@@ -15,13 +15,22 @@ package core
 
 import (
 	"math"
+	"sort"
+	"strings"
 	"sync/atomic"
 	"time"
 
 	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/bindinfo"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/masking"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/rowpolicy"
+	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/codec"
@@ -71,6 +80,9 @@ type pstmtPlanCacheKey struct {
 	timezoneOffset       int
 	isolationReadEngines map[kv.StoreType]struct{}
 	selectLimit          uint64
+	useInvisibleIndexes  bool
+	// stmtVars are the variables set by the SET_VAR hints, in the form of name=value and sorted.
+	stmtVars []string
 
 	hash []byte
 }
@@ -101,6 +113,12 @@ func (key *pstmtPlanCacheKey) Hash() []byte {
 			key.hash = append(key.hash, kv.TiFlash.Name()...)
 		}
 		key.hash = codec.EncodeInt(key.hash, int64(key.selectLimit))
+		if key.useInvisibleIndexes {
+			key.hash = append(key.hash, 1)
+		}
+		for _, stmtVar := range key.stmtVars {
+			key.hash = codec.EncodeCompactBytes(key.hash, hack.Slice(stmtVar))
+		}
 	}
 	return key.hash
 }
@@ -121,6 +139,16 @@ func SetPstmtIDSchemaVersion(key kvcache.Key, pstmtID uint32, schemaVersion int6
 	psStmtKey.hash = psStmtKey.hash[:0]
 }
 
+// ParsePSTMTPlanCacheKey returns the pstmtID and the schemaVersion of the cacheKey, ok is false if the key isn't
+// created by NewPSTMTPlanCacheKey.
+func ParsePSTMTPlanCacheKey(key kvcache.Key) (pstmtID uint32, schemaVersion int64, ok bool) {
+	psStmtKey, ok := key.(*pstmtPlanCacheKey)
+	if !ok {
+		return 0, 0, false
+	}
+	return psStmtKey.pstmtID, psStmtKey.schemaVersion, true
+}
+
 // NewPSTMTPlanCacheKey creates a new pstmtPlanCacheKey object.
 func NewPSTMTPlanCacheKey(sessionVars *variable.SessionVars, pstmtID uint32, schemaVersion int64) kvcache.Key {
 	timezoneOffset := 0
@@ -136,10 +164,15 @@ func NewPSTMTPlanCacheKey(sessionVars *variable.SessionVars, pstmtID uint32, sch
 		timezoneOffset:       timezoneOffset,
 		isolationReadEngines: make(map[kv.StoreType]struct{}),
 		selectLimit:          sessionVars.SelectLimit,
+		useInvisibleIndexes:  sessionVars.UseInvisibleIndexes(),
 	}
 	for k, v := range sessionVars.IsolationReadEngines {
 		key.isolationReadEngines[k] = v
 	}
+	for name, val := range sessionVars.GetStmtVars() {
+		key.stmtVars = append(key.stmtVars, name+"="+val)
+	}
+	sort.Strings(key.stmtVars)
 	return key
 }
 
@@ -171,6 +204,101 @@ type PSTMTPlanCacheValue struct {
 	OutPutNames       []*types.FieldName
 	TblInfo2UnionScan map[*model.TableInfo]bool
 	UserVarTypes      FieldSlice
+	// UsedStats are the versions of the analyzed histograms of the tables or the partitions used to build the plan,
+	// the plan is invalidated when any of them is analyzed again.
+	UsedStats map[int64]PlanCacheStats
+	// BindSQLDigest and BindNormalizedSQL identify the bindings of the statement, and Bindings are the bindings
+	// used to build the plan. The plan is invalidated when the bindings of the statement are changed.
+	BindSQLDigest     string
+	BindNormalizedSQL string
+	Bindings          string
+}
+
+// PlanCacheStats is the stats of a table or a partition used by a cached plan.
+type PlanCacheStats struct {
+	TblInfo     *model.TableInfo
+	HistVersion uint64
+}
+
+// The reasons why the cached plans are invalidated.
+const (
+	planCacheInvalidatedBySchema  = "schema"
+	planCacheInvalidatedByStats   = "stats"
+	planCacheInvalidatedByBinding = "binding"
+)
+
+// setDependencies records the stats and the bindings used to build the plan.
+func (v *PSTMTPlanCacheValue) setDependencies(sctx sessionctx.Context) {
+	sc := sctx.GetSessionVars().StmtCtx
+	usedStats := sc.UsedStats()
+	v.UsedStats = make(map[int64]PlanCacheStats, len(usedStats))
+	for id, info := range usedStats {
+		v.UsedStats[id] = PlanCacheStats{TblInfo: info.TblInfo, HistVersion: info.HistVersion}
+	}
+	v.BindSQLDigest, v.BindNormalizedSQL = sc.BindSQLDigest, sc.BindNormalizedSQL
+	v.Bindings = usingBindings(sctx, v.BindSQLDigest, v.BindNormalizedSQL)
+}
+
+// invalidReason returns why the cached plan can't be used anymore, it's empty if the plan is still valid.
+func (v *PSTMTPlanCacheValue) invalidReason(sctx sessionctx.Context) string {
+	if do := domain.GetDomain(sctx); do != nil && do.StatsHandle() != nil {
+		for id, stats := range v.UsedStats {
+			statsTbl := do.StatsHandle().GetPartitionStats(stats.TblInfo, id)
+			var histVersion uint64
+			if !usePseudoStats(statsTbl) {
+				histVersion = statsTbl.LastAnalyzeVersion()
+			}
+			if histVersion != stats.HistVersion {
+				return planCacheInvalidatedByStats
+			}
+		}
+	}
+	if usingBindings(sctx, v.BindSQLDigest, v.BindNormalizedSQL) != v.Bindings {
+		return planCacheInvalidatedByBinding
+	}
+	return ""
+}
+
+// usingBindings returns the bindings of the statement which can be used by the optimizer, in the form of
+// `bind_sql@update_time` separated by semicolons.
+func usingBindings(sctx sessionctx.Context, digest, normalizedSQL string) string {
+	vars := sctx.GetSessionVars()
+	if normalizedSQL == "" || !(vars.UsePlanBaselines || vars.EvolvePlanBaselines) {
+		return ""
+	}
+	bindRecord, _ := GetBindRecord(sctx, digest, normalizedSQL)
+	if bindRecord == nil {
+		return ""
+	}
+	bindings := make([]string, 0, len(bindRecord.Bindings))
+	for _, binding := range bindRecord.Bindings {
+		if binding.Status == bindinfo.Using {
+			bindings = append(bindings, binding.BindSQL+"@"+binding.UpdateTime.String())
+		}
+	}
+	return strings.Join(bindings, ";")
+}
+
+// GetBindRecord returns the bindings of the statement identified by the digest and the normalized SQL, and the scope
+// of them. The session bindings take precedence over the global ones.
+func GetBindRecord(sctx sessionctx.Context, digest, normalizedSQL string) (*bindinfo.BindRecord, string) {
+	// When the domain is initializing, the bind will be nil.
+	sessionHandle, ok := sctx.Value(bindinfo.SessionBindInfoKeyType).(*bindinfo.SessionHandle)
+	if !ok {
+		return nil, ""
+	}
+	bindRecord := sessionHandle.GetBindRecord(normalizedSQL, "")
+	if bindRecord != nil {
+		if bindRecord.HasUsingBinding() {
+			return bindRecord, metrics.ScopeSession
+		}
+		return nil, ""
+	}
+	globalHandle := domain.GetDomain(sctx).BindHandle()
+	if globalHandle == nil {
+		return nil, ""
+	}
+	return globalHandle.GetBindRecord(digest, normalizedSQL, ""), metrics.ScopeGlobal
 }
 
 // NewPSTMTPlanCacheValue creates a SQLCacheValue.
@@ -202,4 +330,91 @@ type CachedPrepareStmt struct {
 	SQLDigest      string
 	PlanDigest     string
 	ForUpdateRead  bool
+	// StmtText and StmtDB are the text and the current database when the statement is prepared,
+	// which are used to prepare the statement again when the session is migrated.
+	StmtText string
+	StmtDB   string
+	// TableRevisions are the UpdateTS of the tables referenced by the statement, it's nil if the statement
+	// references views, whose plans are invalidated by any schema change.
+	TableRevisions map[int64]uint64
+	// PlanSchemaVersion is the schema version in the plan cache keys of the statement. Unlike the SchemaVersion of
+	// the PreparedAst, it's only changed when the tables referenced by the statement are changed, so the cached
+	// plans survive the changes of the other tables.
+	PlanSchemaVersion int64
+	// RowPolicyVersion is the version of the row policies when the statement is preprocessed. The row policies are
+	// treated as a part of the schema, the cached plans are invalidated when any of them is changed.
+	RowPolicyVersion uint64
+	// MaskingRuleVersion is the version of the masking rules when the statement is preprocessed, the cached plans are
+	// invalidated when any of them is changed.
+	MaskingRuleVersion uint64
+}
+
+// CheckSchemaVersion checks whether the tables referenced by the statement are changed in the infoschema. If they
+// are not, the schema version of the statement is updated to the infoschema's, and the plans cached for it are kept.
+func (s *CachedPrepareStmt) CheckSchemaVersion(is infoschema.InfoSchema) (changed bool) {
+	prepared := s.PreparedAst
+	if s.RowPolicyVersion != rowpolicy.GlobalManager().Version() || s.MaskingRuleVersion != masking.GlobalManager().Version() {
+		return true
+	}
+	if prepared.SchemaVersion == is.SchemaMetaVersion() {
+		return false
+	}
+	if s.TableRevisions == nil {
+		return true
+	}
+	for id, revision := range s.TableRevisions {
+		tbl, ok := is.TableByID(id)
+		if !ok || tbl.Meta().UpdateTS != revision {
+			return true
+		}
+	}
+	prepared.SchemaVersion = is.SchemaMetaVersion()
+	return false
+}
+
+// ResetSchemaVersion updates the schema version and the referenced tables of the statement after it's
+// preprocessed again, the plans cached before are not used anymore.
+func (s *CachedPrepareStmt) ResetSchemaVersion(is infoschema.InfoSchema) {
+	s.PreparedAst.SchemaVersion = is.SchemaMetaVersion()
+	s.TableRevisions = CollectTableRevisions(s.PreparedAst.Stmt)
+	s.PlanSchemaVersion = s.PreparedAst.SchemaVersion
+	s.RowPolicyVersion = rowpolicy.GlobalManager().Version()
+	s.MaskingRuleVersion = masking.GlobalManager().Version()
+}
+
+// CollectTableRevisions collects the UpdateTS of the tables referenced by the preprocessed statement, it returns
+// nil if the statement references views.
+func CollectTableRevisions(stmt ast.StmtNode) map[int64]uint64 {
+	c := &tableRevisionCollector{revisions: make(map[int64]uint64)}
+	stmt.Accept(c)
+	if c.hasView {
+		return nil
+	}
+	return c.revisions
+}
+
+type tableRevisionCollector struct {
+	revisions map[int64]uint64
+	hasView   bool
+}
+
+// Enter implements the ast.Visitor interface.
+func (c *tableRevisionCollector) Enter(in ast.Node) (ast.Node, bool) {
+	if tn, ok := in.(*ast.TableName); ok && tn.TableInfo != nil {
+		if tn.TableInfo.IsView() {
+			c.hasView = true
+		}
+		c.revisions[tn.TableInfo.ID] = tn.TableInfo.UpdateTS
+	}
+	return in, c.hasView
+}
+
+// Leave implements the ast.Visitor interface.
+func (c *tableRevisionCollector) Leave(in ast.Node) (ast.Node, bool) {
+	return in, !c.hasView
+}
+
+// EncodePreparedStmt implements the sessionstates.PreparedStmtEncoder interface.
+func (s *CachedPrepareStmt) EncodePreparedStmt() (stmtText, stmtDB string) {
+	return s.StmtText, s.StmtDB
 }
@@ -40,4 +40,11 @@ func (s *testCacheSuite) TestCacheKey(c *C) {
 	defer testleak.AfterTest(c)()
 	key := NewPSTMTPlanCacheKey(s.ctx.GetSessionVars(), 1, 1)
 	c.Assert(key.Hash(), DeepEquals, []byte{0x74, 0x65, 0x73, 0x74, 0x80, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x80, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1, 0x80, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1, 0x80, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x80, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x74, 0x69, 0x64, 0x62, 0x74, 0x69, 0x6b, 0x76, 0x74, 0x69, 0x66, 0x6c, 0x61, 0x73, 0x68, 0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+
+	// The values set by the SET_VAR hints should be a part of the key.
+	sessVars := s.ctx.GetSessionVars()
+	c.Assert(sessVars.SetStmtVar("tidb_executor_concurrency", "1"), IsNil)
+	defer sessVars.ClearStmtVars()
+	hintedKey := NewPSTMTPlanCacheKey(sessVars, 1, 1)
+	c.Assert(hintedKey.Hash(), Not(DeepEquals), key.Hash())
 }
@@ -997,6 +997,42 @@ func (s *testAnalyzeSuite) TestLimitIndexEstimation(c *C) {
 	}
 }
 
+func (s *testAnalyzeSuite) TestLimitIndexEstimationWithDependentFilters(c *C) {
+	defer testleak.AfterTest(c)()
+	store, dom, err := newStoreWithBootstrap()
+	c.Assert(err, IsNil)
+	tk := testkit.NewTestKit(c, store)
+	defer func() {
+		dom.Close()
+		store.Close()
+	}()
+
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int, b int, c int, key idx_a(a), key idx_b(b))")
+	tk.MustExec("set session tidb_enable_extended_stats = on")
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	// Values in column a are from 1 to 1000, values in column b are from 1000 to 1, these 2 columns are strictly
+	// correlated in reverse order, and column c is independent of them.
+	values := make([]string, 0, 1000)
+	for i := 1; i <= 1000; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d, %d)", i, 1001-i, i%2))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+	tk.MustExec("alter table t add stats_extended s1 correlation(a,b)")
+	tk.MustExec("analyze table t")
+	c.Assert(dom.StatsHandle().Update(dom.InfoSchema()), IsNil)
+
+	// The rows matching `a >= 990` are the first ones in the order of b, so the ordered scan of idx_b only reads a few rows,
+	// and the filter on c is estimated by the stats of c.
+	rows := tk.MustQuery("explain format = 'brief' select * from t where a >= 990 and c = 0 order by b limit 1").Rows()
+	plan := make([]string, 0, len(rows))
+	for _, row := range rows {
+		plan = append(plan, fmt.Sprintf("%v", row))
+	}
+	c.Assert(strings.Join(plan, "\n"), Matches, "(?s).*IndexFullScan.*index:idx_b\\(b\\) keep order:true.*")
+	tk.MustQuery("select * from t where a >= 990 and c = 0 order by b limit 1").Check(testkit.Rows("1000 1 0"))
+}
+
 func (s *testAnalyzeSuite) TestBatchPointGetTablePartition(c *C) {
 	store, dom, err := newStoreWithBootstrap()
 	c.Assert(err, IsNil)
@@ -0,0 +1,117 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"sort"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/util"
+)
+
+// tableColumn is a column of a table.
+type tableColumn struct {
+	tableID  int64
+	columnID int64
+}
+
+// predicateColumnsCollector collects the columns of the tables used by the predicates, joins or group-by of the
+// logical plan, which need the stats to estimate the row counts.
+type predicateColumnsCollector struct {
+	// colMap maps the unique ID of the column in the plan to the column of the table it is read from.
+	colMap map[int64]tableColumn
+	// predicateCols maps the table ID to the IDs of its predicate columns.
+	predicateCols map[int64]map[int64]struct{}
+}
+
+func (c *predicateColumnsCollector) addPredicateCols(exprs []expression.Expression) {
+	for _, col := range expression.ExtractColumnsFromExpressions(nil, exprs, nil) {
+		tblCol, ok := c.colMap[col.UniqueID]
+		if !ok {
+			continue
+		}
+		cols, ok := c.predicateCols[tblCol.tableID]
+		if !ok {
+			cols = make(map[int64]struct{})
+			c.predicateCols[tblCol.tableID] = cols
+		}
+		cols[tblCol.columnID] = struct{}{}
+	}
+}
+
+func (c *predicateColumnsCollector) addJoinCols(join *LogicalJoin) {
+	exprs := make([]expression.Expression, 0, len(join.EqualConditions)+len(join.LeftConditions)+len(join.RightConditions)+len(join.OtherConditions))
+	for _, cond := range join.EqualConditions {
+		exprs = append(exprs, cond)
+	}
+	exprs = append(exprs, join.LeftConditions...)
+	exprs = append(exprs, join.RightConditions...)
+	exprs = append(exprs, join.OtherConditions...)
+	c.addPredicateCols(exprs)
+}
+
+func (c *predicateColumnsCollector) collect(lp LogicalPlan) {
+	for _, child := range lp.Children() {
+		c.collect(child)
+	}
+	switch x := lp.(type) {
+	case *DataSource:
+		if util.IsMemOrSysDB(x.DBName.L) {
+			return
+		}
+		for i, col := range x.Schema().Columns {
+			// The extra handle column isn't a column of the table.
+			if i < len(x.Columns) && x.Columns[i].ID > 0 {
+				c.colMap[col.UniqueID] = tableColumn{tableID: x.tableInfo.ID, columnID: x.Columns[i].ID}
+			}
+		}
+		c.addPredicateCols(x.pushedDownConds)
+	case *LogicalProjection:
+		// The columns projected directly are the same columns of the tables.
+		for i, expr := range x.Exprs {
+			if col, ok := expr.(*expression.Column); ok {
+				if tblCol, ok := c.colMap[col.UniqueID]; ok {
+					c.colMap[x.Schema().Columns[i].UniqueID] = tblCol
+				}
+			}
+		}
+	case *LogicalSelection:
+		c.addPredicateCols(x.Conditions)
+	case *LogicalJoin:
+		c.addJoinCols(x)
+	case *LogicalApply:
+		c.addJoinCols(&x.LogicalJoin)
+	case *LogicalAggregation:
+		c.addPredicateCols(x.GroupByItems)
+	}
+}
+
+// collectPredicateColumns returns the predicate columns of each table used by the logical plan.
+func collectPredicateColumns(lp LogicalPlan) map[int64][]int64 {
+	c := &predicateColumnsCollector{
+		colMap:        make(map[int64]tableColumn),
+		predicateCols: make(map[int64]map[int64]struct{}),
+	}
+	c.collect(lp)
+	result := make(map[int64][]int64, len(c.predicateCols))
+	for tableID, cols := range c.predicateCols {
+		colIDs := make([]int64, 0, len(cols))
+		for colID := range cols {
+			colIDs = append(colIDs, colID)
+		}
+		sort.Slice(colIDs, func(i, j int) bool { return colIDs[i] < colIDs[j] })
+		result[tableID] = colIDs
+	}
+	return result
+}
@@ -123,6 +123,20 @@ type CancelDDLJobs struct {
 	JobIDs []int64
 }
 
+// PauseDDLJobs represents a pause DDL jobs plan.
+type PauseDDLJobs struct {
+	baseSchemaProducer
+
+	JobIDs []int64
+}
+
+// ResumeDDLJobs represents a resume DDL jobs plan.
+type ResumeDDLJobs struct {
+	baseSchemaProducer
+
+	JobIDs []int64
+}
+
 // ReloadExprPushdownBlacklist reloads the data from expr_pushdown_blacklist table.
 type ReloadExprPushdownBlacklist struct {
 	baseSchemaProducer
@@ -256,9 +270,9 @@ func (e *Execute) OptimizePreparedPlan(ctx context.Context, sctx sessionctx.Cont
 		}
 	}
 
-	if prepared.SchemaVersion != is.SchemaMetaVersion() {
+	if preparedObj.CheckSchemaVersion(is) {
 		// In order to avoid some correctness issues, we have to clear the
-		// cached plan once the schema version is changed.
+		// cached plan once the tables referenced by the statement are changed.
 		// Cached plan in prepared struct does NOT have a "cache key" with
 		// schema version like prepared plan cache key
 		prepared.CachedPlan = nil
@@ -269,7 +283,11 @@ func (e *Execute) OptimizePreparedPlan(ctx context.Context, sctx sessionctx.Cont
 		if err != nil {
 			return ErrSchemaChanged.GenWithStack("Schema change caused error: %s", err.Error())
 		}
-		prepared.SchemaVersion = is.SchemaMetaVersion()
+		if prepared.UseCache {
+			sctx.PreparedPlanCache().Delete(NewPSTMTPlanCacheKey(sctx.GetSessionVars(), e.ExecID, preparedObj.PlanSchemaVersion))
+		}
+		preparedObj.ResetSchemaVersion(is)
+		metrics.PlanCacheInvalidationCounter.WithLabelValues(planCacheInvalidatedBySchema).Inc()
 	}
 	err := e.getPhysicalPlan(ctx, sctx, is, preparedObj)
 	if err != nil {
@@ -303,7 +321,7 @@ func (e *Execute) getPhysicalPlan(ctx context.Context, sctx sessionctx.Context,
 	stmtCtx.UseCache = prepared.UseCache
 	var cacheKey kvcache.Key
 	if prepared.UseCache {
-		cacheKey = NewPSTMTPlanCacheKey(sctx.GetSessionVars(), e.ExecID, prepared.SchemaVersion)
+		cacheKey = NewPSTMTPlanCacheKey(sctx.GetSessionVars(), e.ExecID, preparedStmt.PlanSchemaVersion)
 	}
 	tps := make([]*types.FieldType, len(e.UsingVars))
 	for i, param := range e.UsingVars {
@@ -359,6 +377,13 @@ func (e *Execute) getPhysicalPlan(ctx context.Context, sctx sessionctx.Context,
 						break
 					}
 				}
+				if planValid {
+					if reason := cachedVal.invalidReason(sctx); reason != "" {
+						planValid = false
+						sctx.PreparedPlanCache().Delete(cacheKey)
+						metrics.PlanCacheInvalidationCounter.WithLabelValues(reason).Inc()
+					}
+				}
 				if planValid {
 					err := e.rebuildRange(cachedVal.Plan)
 					if err != nil {
@@ -397,14 +422,15 @@ REBUILD:
 	e.names = names
 	e.Plan = p
 	_, isTableDual := p.(*PhysicalTableDual)
-	if !isTableDual && prepared.UseCache && !stmtCtx.OptimDependOnMutableConst {
+	if !isTableDual && prepared.UseCache && !stmtCtx.OptimDependOnMutableConst && !stmtCtx.DependOnRowPolicy && !stmtCtx.DependOnMaskingRule {
 		// rebuild key to exclude kv.TiFlash when stmt is not read only
 		if _, isolationReadContainTiFlash := sessVars.IsolationReadEngines[kv.TiFlash]; isolationReadContainTiFlash && !IsReadOnly(stmt, sessVars) {
 			delete(sessVars.IsolationReadEngines, kv.TiFlash)
-			cacheKey = NewPSTMTPlanCacheKey(sctx.GetSessionVars(), e.ExecID, prepared.SchemaVersion)
+			cacheKey = NewPSTMTPlanCacheKey(sctx.GetSessionVars(), e.ExecID, preparedStmt.PlanSchemaVersion)
 			sessVars.IsolationReadEngines[kv.TiFlash] = struct{}{}
 		}
 		cached := NewPSTMTPlanCacheValue(p, names, stmtCtx.TblInfo2UnionScan, tps)
+		cached.setDependencies(sctx)
 		preparedStmt.NormalizedPlan, preparedStmt.PlanDigest = NormalizePlan(p)
 		stmtCtx.SetPlanDigest(preparedStmt.NormalizedPlan, preparedStmt.PlanDigest)
 		if cacheVals, exists := sctx.PreparedPlanCache().Get(cacheKey); exists {
@@ -432,7 +458,7 @@ REBUILD:
 // short paths for these executions, currently "point select" and "point update"
 func (e *Execute) tryCachePointPlan(ctx context.Context, sctx sessionctx.Context,
 	preparedStmt *CachedPrepareStmt, is infoschema.InfoSchema, p Plan) error {
-	if sctx.GetSessionVars().StmtCtx.OptimDependOnMutableConst {
+	if sc := sctx.GetSessionVars().StmtCtx; sc.OptimDependOnMutableConst || sc.DependOnRowPolicy || sc.DependOnMaskingRule {
 		return nil
 	}
 	var (
@@ -767,6 +793,10 @@ type Insert struct {
 	AllAssignmentsAreConstant bool
 
 	RowLen int
+
+	// RowPolicyCheck is the condition the written rows must satisfy by the row policies of the table, it's nil if
+	// the user isn't restricted by any policy.
+	RowPolicyCheck expression.Expression
 }
 
 // Update represents Update plan.
@@ -787,6 +817,9 @@ type Update struct {
 	// e.g. update t partition(p0) set a = 1;
 	PartitionedTable []table.PartitionedTable
 
+	// RowPolicyChecks are the conditions the updated rows of the tables must satisfy by their row policies.
+	RowPolicyChecks map[int64]expression.Expression
+
 	tblID2Table map[int64]table.Table
 }
 
@@ -848,6 +881,8 @@ type analyzeInfo struct {
 	TableID       AnalyzeTableID
 	Incremental   bool
 	StatsVersion  int
+	// SampleSize is the number of rows sampled to build the column stats, 0 means the SAMPLES option is used.
+	SampleSize uint64
 }
 
 // AnalyzeColumnsTask is used for analyze columns.
@@ -872,7 +907,22 @@ type Analyze struct {
 
 	ColTasks []AnalyzeColumnsTask
 	IdxTasks []AnalyzeIndexTask
-	Opts     map[ast.AnalyzeOptionType]uint64
+	// VirtualColTasks analyze the virtual generated columns, whose values are evaluated in TiDB.
+	VirtualColTasks []AnalyzeColumnsTask
+	Opts            map[ast.AnalyzeOptionType]uint64
+	// TableOpts are the options of each table, in which the BUCKETS and TOPN options not specified by the statement
+	// are replaced by the ones saved for the table.
+	TableOpts map[int64]map[ast.AnalyzeOptionType]uint64
+	// SavedOpts are the BUCKETS and TOPN options specified by the statement, which are saved for the analyzed tables.
+	SavedOpts map[ast.AnalyzeOptionType]uint64
+}
+
+// OptsOfTable returns the analyze options of the table.
+func (p *Analyze) OptsOfTable(tableID int64) map[ast.AnalyzeOptionType]uint64 {
+	if opts, ok := p.TableOpts[tableID]; ok {
+		return opts
+	}
+	return p.Opts
 }
 
 // LoadData represents a loaddata plan.
@@ -892,6 +942,9 @@ type LoadData struct {
 	ColumnsAndUserVars []*ast.ColumnNameOrUserVar
 
 	GenCols InsertGeneratedColumns
+
+	// RowPolicyCheck is the condition the loaded rows must satisfy by the row policies of the table.
+	RowPolicyCheck expression.Expression
 }
 
 // LoadStats represents a load stats plan.
@@ -923,6 +976,9 @@ type SplitRegion struct {
 	Upper          []types.Datum
 	Num            int
 	ValueLists     [][]types.Datum
+	// SplitByStats indicates the index regions are split by the statistics of the index
+	// instead of the lower and upper values.
+	SplitByStats bool
 }
 
 // SplitRegionStatus represents a split regions status plan.
@@ -938,6 +994,8 @@ type DDL struct {
 	baseSchemaProducer
 
 	Statement ast.DDLNode
+	// SelectCols are the names of the select columns of a `CREATE TABLE ... SELECT` statement.
+	SelectCols []model.CIStr
 }
 
 // SelectInto represents a select-into plan.
@@ -76,6 +76,7 @@ var (
 	errTooBigPrecision                 = dbterror.ClassExpression.NewStd(mysql.ErrTooBigPrecision)
 	ErrDBaccessDenied                  = dbterror.ClassOptimizer.NewStd(mysql.ErrDBaccessDenied)
 	ErrTableaccessDenied               = dbterror.ClassOptimizer.NewStd(mysql.ErrTableaccessDenied)
+	ErrColumnaccessDenied              = dbterror.ClassOptimizer.NewStd(mysql.ErrColumnaccessDenied)
 	ErrSpecificAccessDenied            = dbterror.ClassOptimizer.NewStd(mysql.ErrSpecificAccessDenied)
 	ErrViewNoExplain                   = dbterror.ClassOptimizer.NewStd(mysql.ErrViewNoExplain)
 	ErrWrongValueCountOnRow            = dbterror.ClassOptimizer.NewStd(mysql.ErrWrongValueCountOnRow)
@@ -92,4 +93,6 @@ var (
 	ErrAccessDenied        = dbterror.ClassOptimizer.NewStdErr(mysql.ErrAccessDenied, mysql.MySQLErrName[mysql.ErrAccessDeniedNoPassword])
 	ErrBadNull             = dbterror.ClassOptimizer.NewStd(mysql.ErrBadNull)
 	ErrNotSupportedWithSem = dbterror.ClassOptimizer.NewStd(mysql.ErrNotSupportedWithSem)
+	// ErrOptionPreventsStatement is returned when the statement writes on a read-only server.
+	ErrOptionPreventsStatement = dbterror.ClassOptimizer.NewStd(mysql.ErrOptionPreventsStatement)
 )
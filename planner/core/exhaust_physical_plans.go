@@ -2086,6 +2086,8 @@ func disableAggPushDownToCop(p LogicalPlan) {
 }
 
 func (p *LogicalWindow) exhaustPhysicalPlans(prop *property.PhysicalProperty) ([]PhysicalPlan, bool) {
+	// TODO: push the window functions, e.g. ntile, percent_rank and cume_dist, down to TiFlash in MPP mode.
+	// The tipb protocol has no window executor yet, so the window is always executed by TiDB for now.
 	if prop.IsFlashProp() {
 		return nil, true
 	}
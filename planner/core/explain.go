@@ -744,15 +744,22 @@ func (p *PhysicalWindow) ExplainInfo() string {
 		if !isFirst {
 			buffer.WriteString(" ")
 		}
-		if p.Frame.Type == ast.Rows {
+		switch p.Frame.Type {
+		case ast.Rows:
 			buffer.WriteString("rows")
-		} else {
+		case ast.Groups:
+			buffer.WriteString("groups")
+		default:
 			buffer.WriteString("range")
 		}
 		buffer.WriteString(" between ")
 		p.formatFrameBound(buffer, p.Frame.Start)
 		buffer.WriteString(" and ")
 		p.formatFrameBound(buffer, p.Frame.End)
+		if p.Frame.Exclude != FrameExcludeNoOthers {
+			buffer.WriteString(" ")
+			buffer.WriteString(p.Frame.Exclude.String())
+		}
 	}
 	buffer.WriteString(")")
 	return buffer.String()
@@ -985,6 +992,42 @@ func (p *TiKVSingleGather) ExplainInfo() string {
 	return buffer.String()
 }
 
+// ExplainInfo implements Plan interface.
+func (p *PhysicalFullTextReader) ExplainInfo() string {
+	return p.AccessObject(false) + ", " + p.OperatorInfo(false)
+}
+
+// ExplainNormalizedInfo implements Plan interface.
+func (p *PhysicalFullTextReader) ExplainNormalizedInfo() string {
+	return p.AccessObject(true) + ", " + p.OperatorInfo(true)
+}
+
+// AccessObject implements dataAccesser interface.
+func (p *PhysicalFullTextReader) AccessObject(_ bool) string {
+	buffer := bytes.NewBufferString("")
+	tblName := p.TblInfo.Name.O
+	if p.TableAsName != nil && p.TableAsName.O != "" {
+		tblName = p.TableAsName.O
+	}
+	fmt.Fprintf(buffer, "table:%s, index:%s(", tblName, p.IndexInfo.Name.O)
+	for i, idxCol := range p.IndexInfo.Columns {
+		if i > 0 {
+			buffer.WriteString(", ")
+		}
+		buffer.WriteString(idxCol.Name.O)
+	}
+	buffer.WriteString(")")
+	return buffer.String()
+}
+
+// OperatorInfo implements dataAccesser interface.
+func (p *PhysicalFullTextReader) OperatorInfo(normalized bool) string {
+	if normalized {
+		return "search:" + p.Search.ExplainNormalizedInfo()
+	}
+	return "search:" + p.Search.ExplainInfo()
+}
+
 // MetricTableTimeFormat is the time format for metric table explain and format.
 const MetricTableTimeFormat = "2006-01-02 15:04:05.999"
 
@@ -31,8 +31,10 @@ import (
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/expression/aggregation"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/store/helper"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
@@ -41,6 +43,7 @@ import (
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/encoding"
 	"github.com/pingcap/tidb/util/hint"
 	"github.com/pingcap/tidb/util/stringutil"
 )
@@ -153,6 +156,8 @@ func (b *PlanBuilder) getExpressionRewriter(ctx context.Context, p LogicalPlan)
 	if len(b.rewriterPool) < b.rewriterCounter {
 		rewriter = &expressionRewriter{p: p, b: b, sctx: b.ctx, ctx: ctx}
 		rewriter.sctx.SetValue(expression.TiDBDecodeKeyFunctionKey, decodeKeyFromString)
+		rewriter.sctx.SetValue(expression.TiDBDecodeIndexValueFunctionKey, decodeIndexValueFromString)
+		rewriter.sctx.SetValue(expression.TiDBMVCCInfoFunctionKey, mvccInfoFromString)
 		b.rewriterPool = append(b.rewriterPool, rewriter)
 		return
 	}
@@ -1026,6 +1031,9 @@ func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok
 		}
 		v.Datum.SetValue(v.Datum.GetValue(), retType)
 		value := &expression.Constant{Value: v.Datum, RetType: retType}
+		if er.err = er.convertIntroducedLiteral(&value.Value, retType); er.err != nil {
+			return retNode, false
+		}
 		er.ctxStackAppend(value, types.EmptyName)
 	case *driver.ParamMarkerExpr:
 		var value expression.Expression
@@ -1080,6 +1088,12 @@ func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok
 
 		if v.Tp.EvalType() == types.ETString {
 			arg.SetCoercibility(expression.CoercibilityImplicit)
+			if v.ExplicitCharSet {
+				arg, er.err = expression.BuildConvertFunction(er.sctx, arg, v.Tp.Charset)
+				if er.err != nil {
+					return retNode, false
+				}
+			}
 		}
 
 		er.ctxStack[len(er.ctxStack)-1] = expression.BuildCastFunction(er.sctx, arg, v.Tp)
@@ -1098,6 +1112,8 @@ func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok
 		er.positionToScalarFunc(v)
 	case *ast.IsNullExpr:
 		er.isNullToExpression(v)
+	case *ast.MatchAgainst:
+		er.matchAgainstToScalarFunc(v)
 	case *ast.IsTruthExpr:
 		er.isTrueToScalarFunc(v)
 	case *ast.DefaultExpr:
@@ -1158,6 +1174,29 @@ func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok
 }
 
 // newFunction chooses which expression.NewFunctionImpl() will be used.
+// convertIntroducedLiteral decodes the hexadecimal or bit-value literal with a character set introducer,
+// like _utf8mb4 0xE4B8AD, in the character set. The invalid characters are handled according to the
+// session variable tidb_invalid_char_handling.
+func (er *expressionRewriter) convertIntroducedLiteral(d *types.Datum, tp *types.FieldType) error {
+	if d.Kind() != types.KindBinaryLiteral || !encoding.NeedConvert(charset.CharsetBin, tp.Charset) {
+		return nil
+	}
+	conv, err := encoding.NewConverter(charset.CharsetBin, tp.Charset)
+	if err != nil {
+		return err
+	}
+	sessVars := er.sctx.GetSessionVars()
+	res, warn, err := conv.Convert(nil, d.GetBytes(), sessVars.InvalidCharHandling)
+	if err != nil {
+		return err
+	}
+	if warn != nil {
+		sessVars.StmtCtx.AppendWarning(warn)
+	}
+	d.SetBinaryLiteral(res)
+	return nil
+}
+
 func (er *expressionRewriter) newFunction(funcName string, retType *types.FieldType, args ...expression.Expression) (expression.Expression, error) {
 	if er.disableFoldCounter > 0 {
 		return expression.NewFunctionBase(er.sctx, funcName, retType, args...)
@@ -1323,6 +1362,32 @@ func (er *expressionRewriter) isNullToExpression(v *ast.IsNullExpr) {
 	er.ctxStackAppend(function, types.EmptyName)
 }
 
+// matchAgainstToScalarFunc rewrites MATCH (col, ...) AGAINST (expr) to a full-text search function.
+// Only the natural language mode is supported now.
+func (er *expressionRewriter) matchAgainstToScalarFunc(v *ast.MatchAgainst) {
+	if v.Modifier.IsBooleanMode() {
+		er.err = ErrNotSupportedYet.GenWithStackByArgs("MATCH ... AGAINST in BOOLEAN MODE")
+		return
+	}
+	if v.Modifier.WithQueryExpansion() {
+		er.err = ErrNotSupportedYet.GenWithStackByArgs("MATCH ... AGAINST WITH QUERY EXPANSION")
+		return
+	}
+	stkLen := len(er.ctxStack)
+	argsLen := len(v.ColumnNames) + 1
+	args := make([]expression.Expression, 0, argsLen)
+	// The search string is pushed after the columns, but it is the first argument of the function.
+	args = append(args, er.ctxStack[stkLen-1])
+	args = append(args, er.ctxStack[stkLen-argsLen:stkLen-1]...)
+	function, err := er.newFunction(expression.MatchAgainst, types.NewFieldType(mysql.TypeDouble), args...)
+	if err != nil {
+		er.err = err
+		return
+	}
+	er.ctxStackPop(argsLen)
+	er.ctxStackAppend(function, types.EmptyName)
+}
+
 func (er *expressionRewriter) positionToScalarFunc(v *ast.PositionExpr) {
 	pos := v.N
 	str := strconv.Itoa(pos)
@@ -1695,6 +1760,12 @@ func (er *expressionRewriter) funcCallToExpression(v *ast.FuncCallExpr) {
 		return
 	}
 
+	// The MVCC info contains the raw data of any table, so it requires the SUPER privilege.
+	if v.FnName.L == expression.TiDBMVCCInfoFunctionKey.String() && er.b != nil {
+		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER")
+		er.b.visitInfo = appendVisitInfo(er.b.visitInfo, mysql.SuperPriv, "", "", "", err)
+	}
+
 	var function expression.Expression
 	er.ctxStackPop(len(v.Args))
 	if _, ok := expression.DeferredFunctions[v.FnName.L]; er.useCache() && ok {
@@ -1740,6 +1811,7 @@ func (er *expressionRewriter) toColumn(v *ast.ColumnName) {
 			er.err = ErrUnknownColumn.GenWithStackByArgs(v.Name, clauseMsg[er.b.curClause])
 			return
 		}
+		er.b.visitColumn(column)
 		er.ctxStackAppend(column, er.names[idx])
 		return
 	}
@@ -1748,6 +1820,7 @@ func (er *expressionRewriter) toColumn(v *ast.ColumnName) {
 		idx, err = expression.FindFieldName(outerName, v)
 		if idx >= 0 {
 			column := outerSchema.Columns[idx]
+			er.b.visitColumn(column)
 			er.ctxStackAppend(&expression.CorrelatedColumn{Column: *column, Data: new(types.Datum)}, outerName[idx])
 			return
 		}
@@ -1765,6 +1838,7 @@ func (er *expressionRewriter) toColumn(v *ast.ColumnName) {
 		er.err = err
 		return
 	} else if col != nil {
+		er.b.visitColumn(col)
 		er.ctxStackAppend(col, name)
 		return
 	}
@@ -1890,11 +1964,7 @@ func decodeKeyFromString(ctx sessionctx.Context, s string) string {
 		ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("invalid record/index key: %X", key))
 		return s
 	}
-	// Auto decode byte if needed.
-	_, bs, err := codec.DecodeBytes(key, nil)
-	if err == nil {
-		key = bs
-	}
+	key = autoDecodeBytesKey(key)
 	tableID := tablecodec.DecodeTableID(key)
 	if tableID == 0 {
 		ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("invalid record/index key: %X", key))
@@ -1905,163 +1975,324 @@ func decodeKeyFromString(ctx sessionctx.Context, s string) string {
 		ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("domain not found when decoding record/index key: %X", key))
 		return s
 	}
-	tbl, _ := dm.InfoSchema().TableByID(tableID)
+	tbl, partitionID := tableByPhysicalID(dm.InfoSchema(), tableID)
 	loc := ctx.GetSessionVars().Location()
+	var ret map[string]interface{}
 	if tablecodec.IsRecordKey(key) {
-		ret, err := decodeRecordKey(key, tableID, tbl, loc)
-		if err != nil {
-			ctx.GetSessionVars().StmtCtx.AppendWarning(err)
-			return s
-		}
-		return ret
+		ret, err = decodeRecordKey(key, tableID, tbl, loc)
 	} else if tablecodec.IsIndexKey(key) {
-		ret, err := decodeIndexKey(key, tableID, tbl, loc)
-		if err != nil {
-			ctx.GetSessionVars().StmtCtx.AppendWarning(err)
-			return s
-		}
-		return ret
+		ret, err = decodeIndexKey(key, tableID, tbl, loc)
+	} else {
+		err = errors.Errorf("invalid record/index key: %X", key)
+	}
+	if err == nil {
+		s, err = marshalDecodedKey(ret, tbl, partitionID)
+	}
+	if err != nil {
+		ctx.GetSessionVars().StmtCtx.AppendWarning(err)
 	}
-	ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("invalid record/index key: %X", key))
 	return s
 }
 
-func decodeRecordKey(key []byte, tableID int64, tbl table.Table, loc *time.Location) (string, error) {
-	_, handle, err := tablecodec.DecodeRecordKey(key)
+// autoDecodeBytesKey decodes the key if it's encoded as the memcomparable bytes, which is the format of
+// the keys in the TiKV logs.
+func autoDecodeBytesKey(key []byte) []byte {
+	if _, bs, err := codec.DecodeBytes(key, nil); err == nil {
+		return bs
+	}
+	return key
+}
+
+// tableByPhysicalID finds the table by the table ID in the key. The ID may be the ID of a partition, then the
+// partitioned table and the partition ID are returned.
+func tableByPhysicalID(is infoschema.InfoSchema, physicalID int64) (table.Table, int64) {
+	if tbl, ok := is.TableByID(physicalID); ok {
+		return tbl, 0
+	}
+	if tbl, _, def := is.FindTableByPartitionID(physicalID); tbl != nil && def != nil {
+		return tbl, physicalID
+	}
+	return nil, 0
+}
+
+// marshalDecodedKey marshals the decoded key or value to JSON. The partition ID is recorded separately from
+// the ID of the partitioned table.
+func marshalDecodedKey(ret map[string]interface{}, tbl table.Table, partitionID int64) (string, error) {
+	if partitionID != 0 {
+		ret["table_id"] = tbl.Meta().ID
+		ret["partition_id"] = partitionID
+	}
+	retStr, err := json.Marshal(ret)
 	if err != nil {
 		return "", errors.Trace(err)
 	}
+	return string(retStr), nil
+}
+
+func decodeRecordKey(key []byte, tableID int64, tbl table.Table, loc *time.Location) (map[string]interface{}, error) {
+	_, handle, err := tablecodec.DecodeRecordKey(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	if handle.IsInt() {
 		ret := make(map[string]interface{})
 		ret["table_id"] = strconv.FormatInt(tableID, 10)
 		ret["_tidb_rowid"] = handle.IntValue()
-		retStr, err := json.Marshal(ret)
-		if err != nil {
-			return "", errors.Trace(err)
-		}
-		return string(retStr), nil
+		return ret, nil
 	}
 	if tbl != nil {
-		tblInfo := tbl.Meta()
-		idxInfo := tables.FindPrimaryIndex(tblInfo)
-		if idxInfo == nil {
-			return "", errors.Trace(errors.Errorf("primary key not found when decoding record key: %X", key))
-		}
-		cols := make(map[int64]*types.FieldType, len(tblInfo.Columns))
-		for _, col := range tblInfo.Columns {
-			cols[col.ID] = &col.FieldType
-		}
-		handleColIDs := make([]int64, 0, len(idxInfo.Columns))
-		for _, col := range idxInfo.Columns {
-			handleColIDs = append(handleColIDs, tblInfo.Columns[col.Offset].ID)
-		}
-
-		if len(handleColIDs) != handle.NumCols() {
-			return "", errors.Trace(errors.Errorf("primary key length not match handle columns number in key"))
-		}
-		datumMap, err := tablecodec.DecodeHandleToDatumMap(handle, handleColIDs, cols, loc, nil)
+		handleRet, err := decodeCommonHandle(handle, tbl.Meta(), loc)
 		if err != nil {
-			return "", errors.Trace(err)
+			return nil, errors.Trace(errors.Errorf("%s when decoding record key: %X", err.Error(), key))
 		}
 		ret := make(map[string]interface{})
 		ret["table_id"] = tableID
-		handleRet := make(map[string]interface{})
-		for colID, dt := range datumMap {
-			dtStr, err := datumToJSONObject(&dt)
-			if err != nil {
-				return "", errors.Trace(err)
-			}
-			found := false
-			for _, colInfo := range tblInfo.Columns {
-				if colInfo.ID == colID {
-					found = true
-					handleRet[colInfo.Name.L] = dtStr
-					break
-				}
-			}
-			if !found {
-				return "", errors.Trace(errors.Errorf("column not found when decoding record key: %X", key))
-			}
-		}
 		ret["handle"] = handleRet
-		retStr, err := json.Marshal(ret)
-		if err != nil {
-			return "", errors.Trace(err)
-		}
-		return string(retStr), nil
+		return ret, nil
 	}
 	ret := make(map[string]interface{})
 	ret["table_id"] = tableID
 	ret["handle"] = handle.String()
-	retStr, err := json.Marshal(ret)
+	return ret, nil
+}
+
+// decodeCommonHandle decodes the common handle to the values of the primary key columns.
+func decodeCommonHandle(handle kv.Handle, tblInfo *model.TableInfo, loc *time.Location) (map[string]interface{}, error) {
+	idxInfo := tables.FindPrimaryIndex(tblInfo)
+	if idxInfo == nil {
+		return nil, errors.New("primary key not found")
+	}
+	cols := make(map[int64]*types.FieldType, len(tblInfo.Columns))
+	for _, col := range tblInfo.Columns {
+		cols[col.ID] = &col.FieldType
+	}
+	handleColIDs := make([]int64, 0, len(idxInfo.Columns))
+	for _, col := range idxInfo.Columns {
+		handleColIDs = append(handleColIDs, tblInfo.Columns[col.Offset].ID)
+	}
+
+	if len(handleColIDs) != handle.NumCols() {
+		return nil, errors.New("primary key length not match handle columns number in key")
+	}
+	datumMap, err := tablecodec.DecodeHandleToDatumMap(handle, handleColIDs, cols, loc, nil)
 	if err != nil {
-		return "", errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
-	return string(retStr), nil
+	handleRet := make(map[string]interface{})
+	for colID, dt := range datumMap {
+		dtStr, err := datumToJSONObject(&dt)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		found := false
+		for _, colInfo := range tblInfo.Columns {
+			if colInfo.ID == colID {
+				found = true
+				handleRet[colInfo.Name.L] = dtStr
+				break
+			}
+		}
+		if !found {
+			return nil, errors.New("column not found")
+		}
+	}
+	return handleRet, nil
 }
 
-func decodeIndexKey(key []byte, tableID int64, tbl table.Table, loc *time.Location) (string, error) {
+func decodeIndexKey(key []byte, tableID int64, tbl table.Table, loc *time.Location) (map[string]interface{}, error) {
 	if tbl != nil {
 		_, indexID, _, err := tablecodec.DecodeKeyHead(key)
 		if err != nil {
-			return "", errors.Trace(errors.Errorf("invalid record/index key: %X", key))
+			return nil, errors.Trace(errors.Errorf("invalid record/index key: %X", key))
 		}
 		tblInfo := tbl.Meta()
-		var targetIndex *model.IndexInfo
-		for _, idx := range tblInfo.Indices {
-			if idx.ID == indexID {
-				targetIndex = idx
-				break
-			}
-		}
+		targetIndex := findIndexByID(tblInfo, indexID)
 		if targetIndex == nil {
-			return "", errors.Trace(errors.Errorf("index not found when decoding index key: %X", key))
+			return nil, errors.Trace(errors.Errorf("index not found when decoding index key: %X", key))
 		}
 		colInfos := tables.BuildRowcodecColInfoForIndexColumns(targetIndex, tblInfo)
-		tps := tables.BuildFieldTypesForIndexColumns(targetIndex, tblInfo)
 		values, err := tablecodec.DecodeIndexKV(key, []byte{0}, len(colInfos), tablecodec.HandleNotNeeded, colInfos)
 		if err != nil {
-			return "", errors.Trace(err)
+			return nil, errors.Trace(err)
 		}
-		ds := make([]types.Datum, 0, len(colInfos))
-		for i := 0; i < len(colInfos); i++ {
-			d, err := tablecodec.DecodeColumnValue(values[i], tps[i], loc)
-			if err != nil {
-				return "", errors.Trace(err)
-			}
-			ds = append(ds, d)
+		idxValMap, err := decodeIndexColumnValues(values, targetIndex, tblInfo, loc)
+		if err != nil {
+			return nil, errors.Trace(err)
 		}
 		ret := make(map[string]interface{})
 		ret["table_id"] = tableID
 		ret["index_id"] = indexID
-		idxValMap := make(map[string]interface{}, len(targetIndex.Columns))
-		for i := 0; i < len(targetIndex.Columns); i++ {
-			dtStr, err := datumToJSONObject(&ds[i])
-			if err != nil {
-				return "", errors.Trace(err)
-			}
-			idxValMap[targetIndex.Columns[i].Name.L] = dtStr
-		}
 		ret["index_vals"] = idxValMap
-		retStr, err := json.Marshal(ret)
-		if err != nil {
-			return "", errors.Trace(err)
-		}
-		return string(retStr), nil
+		return ret, nil
 	}
 	_, indexID, indexValues, err := tablecodec.DecodeIndexKey(key)
 	if err != nil {
-		return "", errors.Trace(errors.Errorf("invalid index key: %X", key))
+		return nil, errors.Trace(errors.Errorf("invalid index key: %X", key))
 	}
 	ret := make(map[string]interface{})
 	ret["table_id"] = tableID
 	ret["index_id"] = indexID
 	ret["index_vals"] = strings.Join(indexValues, ", ")
+	return ret, nil
+}
+
+func findIndexByID(tblInfo *model.TableInfo, indexID int64) *model.IndexInfo {
+	for _, idx := range tblInfo.Indices {
+		if idx.ID == indexID {
+			return idx
+		}
+	}
+	return nil
+}
+
+// decodeIndexColumnValues decodes the values of the index columns, the values may have more columns than the index,
+// e.g. the handle columns, they are ignored.
+func decodeIndexColumnValues(values [][]byte, idxInfo *model.IndexInfo, tblInfo *model.TableInfo, loc *time.Location) (map[string]interface{}, error) {
+	tps := tables.BuildFieldTypesForIndexColumns(idxInfo, tblInfo)
+	idxValMap := make(map[string]interface{}, len(idxInfo.Columns))
+	for i := 0; i < len(idxInfo.Columns); i++ {
+		d, err := tablecodec.DecodeColumnValue(values[i], tps[i], loc)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		dtStr, err := datumToJSONObject(&d)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		idxValMap[idxInfo.Columns[i].Name.L] = dtStr
+	}
+	return idxValMap, nil
+}
+
+func decodeIndexValueFromString(ctx sessionctx.Context, keyStr, valueStr string) string {
+	key, err := hex.DecodeString(keyStr)
+	if err != nil {
+		ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("invalid index key: %s", keyStr))
+		return valueStr
+	}
+	value, err := hex.DecodeString(valueStr)
+	if err != nil || len(value) == 0 {
+		ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("invalid index value: %s", valueStr))
+		return valueStr
+	}
+	key = autoDecodeBytesKey(key)
+	tableID := tablecodec.DecodeTableID(key)
+	if tableID == 0 || !tablecodec.IsIndexKey(key) {
+		ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("invalid index key: %X", key))
+		return valueStr
+	}
+	dm := domain.GetDomain(ctx)
+	if dm == nil {
+		ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("domain not found when decoding index value: %X", value))
+		return valueStr
+	}
+	tbl, partitionID := tableByPhysicalID(dm.InfoSchema(), tableID)
+	ret, err := decodeIndexValue(key, value, tableID, tbl, ctx.GetSessionVars().Location())
+	if err == nil {
+		valueStr, err = marshalDecodedKey(ret, tbl, partitionID)
+	}
+	if err != nil {
+		ctx.GetSessionVars().StmtCtx.AppendWarning(err)
+	}
+	return valueStr
+}
+
+// decodeIndexValue decodes the index key and value to the index column values and the handle of the row. If the
+// index is a global index, the partition ID of the row is also decoded from the value.
+func decodeIndexValue(key, value []byte, tableID int64, tbl table.Table, loc *time.Location) (map[string]interface{}, error) {
+	_, indexID, _, err := tablecodec.DecodeKeyHead(key)
+	if err != nil {
+		return nil, errors.Trace(errors.Errorf("invalid index key: %X", key))
+	}
+	ret := make(map[string]interface{})
+	ret["table_id"] = tableID
+	ret["index_id"] = indexID
+	if tbl == nil {
+		_, _, indexValues, err := tablecodec.DecodeIndexKey(key)
+		if err != nil {
+			return nil, errors.Trace(errors.Errorf("invalid index key: %X", key))
+		}
+		ret["index_vals"] = strings.Join(indexValues, ", ")
+		return ret, nil
+	}
+	tblInfo := tbl.Meta()
+	idxInfo := findIndexByID(tblInfo, indexID)
+	if idxInfo == nil {
+		return nil, errors.Trace(errors.Errorf("index not found when decoding index value: %X", value))
+	}
+	colInfos := tables.BuildRowcodecColInfoForIndexColumns(idxInfo, tblInfo)
+	hdStatus := tablecodec.HandleDefault
+	if !tblInfo.IsCommonHandle && tblInfo.PKIsHandle && mysql.HasUnsignedFlag(tblInfo.GetPkColInfo().Flag) {
+		hdStatus = tablecodec.HandleIsUnsigned
+	}
+	values, err := tablecodec.DecodeIndexKV(key, value, len(colInfos), hdStatus, colInfos)
+	if err != nil {
+		return nil, errors.Trace(errors.Errorf("invalid index value: %X", value))
+	}
+	ret["index_vals"], err = decodeIndexColumnValues(values, idxInfo, tblInfo, loc)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	handle, err := tablecodec.DecodeIndexHandle(key, value, len(colInfos))
+	if err != nil {
+		return nil, errors.Trace(errors.Errorf("invalid index value: %X", value))
+	}
+	if handle.IsInt() {
+		ret["handle"] = handle.IntValue()
+	} else {
+		ret["handle"], err = decodeCommonHandle(handle, tblInfo, loc)
+		if err != nil {
+			return nil, errors.Trace(errors.Errorf("%s when decoding index value: %X", err.Error(), value))
+		}
+	}
+	if len(value) > tablecodec.MaxOldEncodeValueLen {
+		segs := tablecodec.SplitIndexValue(value)
+		if value[1] == tablecodec.IndexVersionFlag {
+			segs = tablecodec.SplitIndexValueForClusteredIndexVersion1(value)
+		}
+		if len(segs.PartitionID) > 0 {
+			_, pid, err := codec.DecodeInt(segs.PartitionID)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ret["row_partition_id"] = pid
+		}
+	}
+	return ret, nil
+}
+
+func mvccInfoFromString(ctx sessionctx.Context, s string) string {
+	key, err := hex.DecodeString(s)
+	if err != nil || len(key) == 0 {
+		ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("invalid key: %s", s))
+		return s
+	}
+	key = autoDecodeBytesKey(key)
+	store, ok := ctx.GetStore().(helper.Storage)
+	if !ok {
+		ctx.GetSessionVars().StmtCtx.AppendWarning(errors.New("the MVCC info is only available in TiKV"))
+		return s
+	}
+	resp, err := helper.NewHelper(store).GetMvccByEncodedKey(key)
+	if err == nil && resp.RegionError != nil {
+		err = errors.Errorf("region error: %s", resp.RegionError.String())
+	}
+	if err == nil && len(resp.Error) > 0 {
+		err = errors.New(resp.Error)
+	}
+	if err != nil {
+		ctx.GetSessionVars().StmtCtx.AppendWarning(err)
+		return s
+	}
+	ret := make(map[string]interface{})
+	ret["key"] = strings.ToUpper(hex.EncodeToString(key))
+	ret["mvcc"] = resp.Info
 	retStr, err := json.Marshal(ret)
 	if err != nil {
-		return "", errors.Trace(err)
+		ctx.GetSessionVars().StmtCtx.AppendWarning(err)
+		return s
 	}
-	return string(retStr), nil
+	return string(retStr)
 }
 
 func datumToJSONObject(d *types.Datum) (interface{}, error) {
@@ -30,6 +30,7 @@ import (
 	tidbutil "github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/fulltext"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/ranger"
 	"github.com/pingcap/tidb/util/set"
@@ -406,6 +407,87 @@ func (ds *DataSource) tryToGetDualTask() (task, error) {
 	return nil, nil
 }
 
+// tryToGetFullTextTask reads the rows through the FULLTEXT index if a condition is a MATCH ... AGAINST search
+// on the columns of the index. Same as MySQL, the FULLTEXT index is always used when it can be, so it returns
+// invalidTask for the properties the reader can't satisfy, and nil if the index can't be used.
+func (ds *DataSource) tryToGetFullTextTask(prop *property.PhysicalProperty) task {
+	// The rows read for update are locked by the scans.
+	if ds.isForUpdateRead || ds.SampleInfo != nil {
+		return nil
+	}
+	if _, ok := ds.ctx.GetSessionVars().GetIsolationReadEngines()[kv.TiKV]; !ok {
+		return nil
+	}
+	// MATCH ... AGAINST can't be pushed down, it's kept in allConds and evaluated by the Selection above.
+	for _, cond := range ds.allConds {
+		sf, ok := cond.(*expression.ScalarFunction)
+		if !ok || sf.FuncName.L != expression.MatchAgainst {
+			continue
+		}
+		idx := ds.findFullTextIndex(sf.GetArgs()[1:])
+		if idx == nil {
+			continue
+		}
+		if !prop.IsEmpty() || prop.TaskTp != property.RootTaskType {
+			return invalidTask
+		}
+		reader := PhysicalFullTextReader{
+			TblInfo:     ds.tableInfo,
+			IndexInfo:   idx,
+			Columns:     ds.Columns,
+			TableAsName: ds.TableAsName,
+			Search:      sf.GetArgs()[0],
+		}.Init(ds.ctx, ds.tableStats, ds.blockOffset)
+		reader.SetSchema(ds.schema)
+		sessVars := ds.ctx.GetSessionVars()
+		rowCount := reader.stats.RowCount
+		rowSize := ds.TblColHists.GetTableAvgRowSize(ds.ctx, ds.TblCols, kv.TiKV, true)
+		cost := rowCount*(rowSize*sessVars.NetworkFactor+sessVars.SeekFactor)/float64(sessVars.DistSQLScanConcurrency()) +
+			rowCount*sessVars.CPUFactor
+		if len(ds.pushedDownConds) == 0 {
+			return &rootTask{p: reader, cst: cost}
+		}
+		sel := PhysicalSelection{
+			Conditions: ds.pushedDownConds,
+		}.Init(ds.ctx, ds.stats, ds.blockOffset)
+		sel.SetChildren(reader)
+		return &rootTask{p: sel, cst: cost}
+	}
+	return nil
+}
+
+// findFullTextIndex finds the public FULLTEXT index whose columns are exactly the columns.
+func (ds *DataSource) findFullTextIndex(args []expression.Expression) *model.IndexInfo {
+	colIDs := make(map[int64]struct{}, len(args))
+	for _, arg := range args {
+		col, ok := arg.(*expression.Column)
+		if !ok {
+			return nil
+		}
+		colIDs[col.ID] = struct{}{}
+	}
+	useInvisibleIndexes := ds.ctx.GetSessionVars().UseInvisibleIndexes()
+	for _, idx := range ds.tableInfo.Indices {
+		if !fulltext.IsIndex(idx) || idx.State != model.StatePublic || (idx.Invisible && !useInvisibleIndexes) {
+			continue
+		}
+		if len(idx.Columns) != len(colIDs) {
+			continue
+		}
+		matched := true
+		for _, idxCol := range idx.Columns {
+			if _, ok := colIDs[ds.tableInfo.Columns[idxCol.Offset].ID]; !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return idx
+		}
+	}
+	return nil
+}
+
 // candidatePath is used to maintain required info for skyline pruning.
 type candidatePath struct {
 	path         *util.AccessPath
@@ -665,6 +747,14 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 		return t, 1, err
 	}
 
+	if t = ds.tryToGetFullTextTask(prop); t != nil {
+		if !t.invalid() {
+			planCounter.Dec(1)
+			return t, 1, nil
+		}
+		return t, 0, nil
+	}
+
 	t = invalidTask
 	candidates := ds.skylinePruning(prop)
 
@@ -1412,12 +1502,42 @@ func (ds *DataSource) crossEstimateRowCount(path *util.AccessPath, conds []expre
 	}
 	scanCount := rangeCount + expectedCnt - count
 	if len(remained) > 0 {
-		scanCount = scanCount / SelectionFactor
+		selectivity := ds.crossEstimateRemainedSelectivity(remained, col)
+		if selectivity <= 0 {
+			return path.CountAfterAccess, true, 0
+		}
+		scanCount = scanCount / selectivity
 	}
 	scanCount = math.Min(scanCount, path.CountAfterAccess)
 	return scanCount, true, 0
 }
 
+// crossEstimateRemainedSelectivity estimates the selectivity of the filters which cannot be converted to the ranges of
+// the correlated column. The filters on the other columns are estimated by their stats, since the rows matching them
+// are distributed independently of the order of the scan.
+func (ds *DataSource) crossEstimateRemainedSelectivity(remained []expression.Expression, col *expression.Column) float64 {
+	otherConds := make([]expression.Expression, 0, len(remained))
+	selectivity := 1.0
+	for _, cond := range remained {
+		cols := expression.ExtractColumns(cond)
+		if len(cols) == 1 && cols[0].UniqueID == col.UniqueID {
+			continue
+		}
+		otherConds = append(otherConds, cond)
+	}
+	if len(otherConds) < len(remained) {
+		selectivity = SelectionFactor
+	}
+	if len(otherConds) == 0 {
+		return selectivity
+	}
+	otherSelectivity, _, err := ds.tableStats.HistColl.Selectivity(ds.ctx, otherConds, nil)
+	if err != nil {
+		otherSelectivity = SelectionFactor
+	}
+	return selectivity * otherSelectivity
+}
+
 // crossEstimateIndexRowCount estimates row count of index scan using histogram of another column which is in TableFilters/IndexFilters
 // and has high order correlation with the first index column. For example, if the query is like:
 // `select * from tbl where a = 1 order by b limit 1`
@@ -1436,8 +1556,10 @@ func (ds *DataSource) crossEstimateIndexRowCount(path *util.AccessPath, expected
 	return ds.crossEstimateRowCount(path, filters, col, corr, expectedCnt, desc)
 }
 
-// getMostCorrCol4Index checks if column in the condition is correlated enough with the first index column. If the condition
-// contains multiple columns, return nil and get the max correlation, which would be used in the heuristic estimation.
+// getMostCorrCol4Index checks if column in the condition is correlated enough with the first index column by the
+// correlation extended stats. If the condition contains multiple columns, the most correlated one is returned and the
+// filters on the other columns are estimated as the dependent filters. If no column is correlated enough, return nil
+// and get the max correlation, which would be used in the heuristic estimation.
 func getMostCorrCol4Index(path *util.AccessPath, histColl *statistics.Table, threshold float64) (*expression.Column, float64) {
 	if histColl.ExtendedStats == nil || len(histColl.ExtendedStats.Stats) == 0 {
 		return nil, 0
@@ -1458,6 +1580,9 @@ func getMostCorrCol4Index(path *util.AccessPath, histColl *statistics.Table, thr
 		colSet.Insert(col.UniqueID)
 		curCorr := float64(0)
 		for _, item := range histColl.ExtendedStats.Stats {
+			if item.Tp != ast.StatsTypeCorrelation {
+				continue
+			}
 			if (col.ID == item.ColIDs[0] && path.FullIdxCols[0].ID == item.ColIDs[1]) ||
 				(col.ID == item.ColIDs[1] && path.FullIdxCols[0].ID == item.ColIDs[0]) {
 				curCorr = item.ScalarVals
@@ -1469,7 +1594,7 @@ func getMostCorrCol4Index(path *util.AccessPath, histColl *statistics.Table, thr
 			corr = curCorr
 		}
 	}
-	if len(colSet) == 1 && math.Abs(corr) >= threshold {
+	if math.Abs(corr) >= threshold {
 		return corrCol, corr
 	}
 	return nil, corr
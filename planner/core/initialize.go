@@ -445,6 +445,13 @@ func (p PhysicalTableSample) Init(ctx sessionctx.Context, offset int) *PhysicalT
 	return &p
 }
 
+// Init initializes PhysicalFullTextReader.
+func (p PhysicalFullTextReader) Init(ctx sessionctx.Context, stats *property.StatsInfo, offset int) *PhysicalFullTextReader {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, plancodec.TypeFullTextReader, &p, offset)
+	p.stats = stats
+	return &p
+}
+
 // Init initializes PhysicalIndexReader.
 func (p PhysicalIndexReader) Init(ctx sessionctx.Context, offset int) *PhysicalIndexReader {
 	p.basePhysicalPlan = newBasePhysicalPlan(ctx, plancodec.TypeIndexReader, &p, offset)
@@ -876,6 +876,13 @@ func (s *testIntegrationSerialSuite) TestAggPushDownEngine(c *C) {
 		"  └─HashAgg_6 1.00 batchCop[tiflash]  funcs:approx_count_distinct(test.t.a)->Column#4",
 		"    └─TableFullScan_10 10000.00 batchCop[tiflash] table:t keep order:false, stats:pseudo"))
 
+	tk.MustQuery("desc select bit_and(a), bit_or(a), bit_xor(a) from t").Check(testkit.Rows(
+		"HashAgg_15 1.00 root  funcs:bit_and(Column#9)->Column#3, funcs:bit_or(Column#10)->Column#4, funcs:bit_xor(Column#11)->Column#5",
+		"└─TableReader_17 1.00 root  data:ExchangeSender_16",
+		"  └─ExchangeSender_16 1.00 batchCop[tiflash]  ExchangeType: PassThrough",
+		"    └─HashAgg_7 1.00 batchCop[tiflash]  funcs:bit_and(test.t.a)->Column#9, funcs:bit_or(test.t.a)->Column#10, funcs:bit_xor(test.t.a)->Column#11",
+		"      └─TableFullScan_14 10000.00 batchCop[tiflash] table:t keep order:false, stats:pseudo"))
+
 	tk.MustExec("set @@session.tidb_isolation_read_engines = 'tikv'")
 
 	tk.MustQuery("desc select approx_count_distinct(a) from t").Check(testkit.Rows(
@@ -1327,6 +1334,20 @@ func (s *testIntegrationSuite) TestInvisibleIndex(c *C) {
 	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 1)
 	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings()[0].Err.Error(), Equals, errStr)
 
+	// Optimizer can use invisible indexes if use_invisible_indexes is on, and the index is maintained by DML.
+	tk.MustExec("insert into t values (3,4)")
+	c.Check(tk.MustUseIndex("select /*+ SET_VAR(optimizer_switch='use_invisible_indexes=on') */ a from t where a > 1", "i_a"), IsTrue)
+	tk.MustQuery("select /*+ SET_VAR(optimizer_switch='use_invisible_indexes=on') */ a from t use index(i_a) where a > 1").Check(testkit.Rows("3"))
+	tk.MustQuery("select /*+ SET_VAR(optimizer_switch='use_invisible_indexes=on') */ b from t where a = 3").Check(testkit.Rows("4"))
+	c.Check(tk.MustUseIndex("select a from t where a > 1", "i_a"), IsFalse)
+	tk.MustExec("set @@optimizer_switch = 'use_invisible_indexes=on'")
+	tk.MustQuery("select @@optimizer_switch like '%use_invisible_indexes=on%'").Check(testkit.Rows("1"))
+	c.Check(tk.MustUseIndex("select a from t where a > 1", "i_a"), IsTrue)
+	tk.MustQuery("select b from t use index(i_a) where a = 1").Check(testkit.Rows("2"))
+	tk.MustExec("set @@optimizer_switch = 'use_invisible_indexes=off'")
+	c.Check(tk.MustUseIndex("select a from t where a > 1", "i_a"), IsFalse)
+	tk.MustGetErrMsg("select * from t use index(i_a)", errStr)
+
 	tk.MustExec("admin check table t")
 	tk.MustExec("admin check index t i_a")
 }
@@ -1452,6 +1473,36 @@ func (s *testIntegrationSuite) TestApproxCountDistinctInPartitionTable(c *C) {
 	tk.MustQuery("select approx_count_distinct(a), b from t group by b order by b desc").Check(testkit.Rows("1 2", "3 1"))
 }
 
+func (s *testIntegrationSuite) TestApproxPercentileInPartitionTable(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int(11), b int, c time, d datetime) partition by range (a) (partition p0 values less than (3), partition p1 values less than maxvalue);")
+	tk.MustExec("insert into t values(1, 1, '00:00:01', '2021-01-01'), (2, 1, '00:00:02', '2021-01-02'), (3, 1, '00:00:03', '2021-01-03'), (4, 2, '00:00:04', '2021-01-04'), (5, 2, '00:00:05', '2021-01-05')")
+	tk.MustExec(`set @@tidb_partition_prune_mode='` + string(variable.Static) + `'`)
+	// The partial approx_percentile on each partition outputs the serialized t-digest.
+	tk.MustQuery("explain format = 'brief' select approx_percentile(a, 50), b from t group by b order by b desc").Check(testkit.Rows("Sort 16000.00 root  test.t.b:desc",
+		"└─HashAgg 16000.00 root  group by:test.t.b, funcs:approx_percentile(Column#7, 50)->Column#6, funcs:firstrow(Column#8)->test.t.b",
+		"  └─PartitionUnion 16000.00 root  ",
+		"    ├─HashAgg 8000.00 root  group by:test.t.b, funcs:approx_percentile(test.t.a, 50)->Column#7, funcs:firstrow(test.t.b)->Column#8, funcs:firstrow(test.t.b)->test.t.b",
+		"    │ └─TableReader 10000.00 root  data:TableFullScan",
+		"    │   └─TableFullScan 10000.00 cop[tikv] table:t, partition:p0 keep order:false, stats:pseudo",
+		"    └─HashAgg 8000.00 root  group by:test.t.b, funcs:approx_percentile(test.t.a, 50)->Column#7, funcs:firstrow(test.t.b)->Column#8, funcs:firstrow(test.t.b)->test.t.b",
+		"      └─TableReader 10000.00 root  data:TableFullScan",
+		"        └─TableFullScan 10000.00 cop[tikv] table:t, partition:p1 keep order:false, stats:pseudo"))
+	tk.MustQuery("select approx_percentile(a, 50), b from t group by b order by b desc").Check(testkit.Rows("4 2", "2 1"))
+	tk.MustQuery("select approx_percentile(a, 100), approx_percentile(c, 50) from t").Check(testkit.Rows("5 00:00:03"))
+	// The time values can't be summarized by t-digest, so the aggregation is not pushed down.
+	tk.MustQuery("explain format = 'brief' select approx_percentile(d, 50) from t").Check(testkit.Rows("HashAgg 1.00 root  funcs:approx_percentile(test.t.d, 50)->Column#6",
+		"└─PartitionUnion 20000.00 root  ",
+		"  ├─TableReader 10000.00 root  data:TableFullScan",
+		"  │ └─TableFullScan 10000.00 cop[tikv] table:t, partition:p0 keep order:false, stats:pseudo",
+		"  └─TableReader 10000.00 root  data:TableFullScan",
+		"    └─TableFullScan 10000.00 cop[tikv] table:t, partition:p1 keep order:false, stats:pseudo"))
+	tk.MustQuery("select approx_percentile(d, 50) from t").Check(testkit.Rows("2021-01-03 00:00:00"))
+}
+
 func (s *testIntegrationSuite) TestApproxPercentile(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 
@@ -3526,3 +3577,71 @@ func (s *testIntegrationSuite) TestIssue23839(c *C) {
 		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin AUTO_INCREMENT=2000001")
 	tk.Exec("explain SELECT OUTR . col2 AS X FROM (SELECT INNR . col1 as col1, SUM( INNR . col2 ) as col2 FROM (SELECT INNR . `col_int_not_null` + 1 as col1, INNR . `pk` as col2 FROM BB AS INNR) AS INNR GROUP BY col1) AS OUTR2 INNER JOIN (SELECT INNR . col1 as col1, MAX( INNR . col2 ) as col2 FROM (SELECT INNR . `col_int_not_null` + 1 as col1, INNR . `pk` as col2 FROM BB AS INNR) AS INNR GROUP BY col1) AS OUTR ON OUTR2.col1 = OUTR.col1 GROUP BY OUTR . col1, OUTR2 . col1 HAVING X <> 'b'")
 }
+
+func (s *testIntegrationSuite) TestBinToUUIDPredicate(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id binary(16) primary key, id2 varbinary(16), a varchar(36), key(id2))")
+	// The equality comparisons on BIN_TO_UUID(col) are rewritten to the ones on col.
+	tk.MustQuery("explain format = 'brief' select * from t where bin_to_uuid(id, 1) = '6ccd780c-baba-1026-9564-5b8c656024db'").Check(testkit.Rows(
+		"Point_Get 1.00 root table:t, index:PRIMARY(id) "))
+	tk.MustQuery("explain format = 'brief' select * from t where '6ccd780c-baba-1026-9564-5b8c656024db' = bin_to_uuid(id2)").Check(testkit.Rows(
+		"IndexLookUp 10.00 root  ",
+		"├─IndexRangeScan(Build) 10.00 cop[tikv] table:t, index:id2(id2) range:[0x6CCD780CBABA102695645B8C656024DB,0x6CCD780CBABA102695645B8C656024DB], keep order:false, stats:pseudo",
+		"└─TableRowIDScan(Probe) 10.00 cop[tikv] table:t keep order:false, stats:pseudo"))
+	tk.MustQuery("explain format = 'brief' select * from t where bin_to_uuid(id2, 0) <=> '6ccd780c-baba-1026-9564-5b8c656024db'").Check(testkit.Rows(
+		"IndexLookUp 10.00 root  ",
+		"├─IndexRangeScan(Build) 10.00 cop[tikv] table:t, index:id2(id2) range:[0x6CCD780CBABA102695645B8C656024DB,0x6CCD780CBABA102695645B8C656024DB], keep order:false, stats:pseudo",
+		"└─TableRowIDScan(Probe) 10.00 cop[tikv] table:t keep order:false, stats:pseudo"))
+	// BIN_TO_UUID returns the lowercase UUIDs, the orders of the string and binary UUIDs differ when the flag is set,
+	// and the argument must be a binary string, so these are not rewritten.
+	tk.MustQuery("explain format = 'brief' select * from t where bin_to_uuid(id2) = '6CCD780C-BABA-1026-9564-5B8C656024DB'").Check(testkit.Rows(
+		"Selection 8000.00 root  eq(bin_to_uuid(test.t.id2), \"6CCD780C-BABA-1026-9564-5B8C656024DB\")",
+		"└─TableReader 10000.00 root  data:TableFullScan",
+		"  └─TableFullScan 10000.00 cop[tikv] table:t keep order:false, stats:pseudo"))
+	tk.MustQuery("explain format = 'brief' select * from t where bin_to_uuid(id, 1) > '6ccd780c-baba-1026-9564-5b8c656024db'").Check(testkit.Rows(
+		"Selection 8000.00 root  gt(bin_to_uuid(test.t.id, 1), \"6ccd780c-baba-1026-9564-5b8c656024db\")",
+		"└─TableReader 10000.00 root  data:TableFullScan",
+		"  └─TableFullScan 10000.00 cop[tikv] table:t keep order:false, stats:pseudo"))
+	tk.MustQuery("explain format = 'brief' select * from t where bin_to_uuid(a) = '6ccd780c-baba-1026-9564-5b8c656024db'").Check(testkit.Rows(
+		"Selection 8000.00 root  eq(bin_to_uuid(test.t.a), \"6ccd780c-baba-1026-9564-5b8c656024db\")",
+		"└─TableReader 10000.00 root  data:TableFullScan",
+		"  └─TableFullScan 10000.00 cop[tikv] table:t keep order:false, stats:pseudo"))
+}
+
+func (s *testIntegrationSuite) TestLateralDerivedTable(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("create table t2(a int, b int)")
+	tk.MustExec("insert into t1 values(1, 10), (2, 20), (3, 30)")
+	tk.MustExec("insert into t2 values(1, 1), (1, 2), (2, 3), (4, 4)")
+
+	tk.MustQuery("select t1.a, dt.m from t1 join lateral (select max(t2.b) m from t2 where t2.a = t1.a) dt order by t1.a").Check(testkit.Rows("1 2", "2 3", "3 <nil>"))
+	tk.MustQuery("select t1.a, dt.b from t1 left join lateral (select t2.b from t2 where t2.a = t1.a order by t2.b desc limit 1) dt on true order by t1.a").Check(testkit.Rows("1 2", "2 3", "3 <nil>"))
+	tk.MustQuery("select t1.a, dt.c from t1 join lateral (select t1.b + 1 c) dt on dt.c > 15 order by t1.a").Check(testkit.Rows("2 21", "3 31"))
+	tk.MustQuery("select t1.a, dt.b from t1 join lateral (select t2.b from t2) dt on dt.b = t1.a order by t1.a").Check(testkit.Rows("1 1", "2 2", "3 3"))
+
+	// The Apply is decorrelated into a join if possible.
+	sql := "select t1.a, dt.b from t1, lateral (select t2.b from t2 where t2.a = t1.a) dt order by t1.a, dt.b"
+	tk.MustQuery(sql).Check(testkit.Rows("1 1", "1 2", "2 3"))
+	tk.MustQuery("explain format = 'brief' " + sql).Check(testkit.Rows(
+		"Sort 12487.50 root  test.t1.a, test.t2.b",
+		"└─HashJoin 12487.50 root  inner join, equal:[eq(test.t1.a, test.t2.a)]",
+		"  ├─TableReader(Build) 9990.00 root  data:Selection",
+		"  │ └─Selection 9990.00 cop[tikv]  not(isnull(test.t2.a))",
+		"  │   └─TableFullScan 10000.00 cop[tikv] table:t2 keep order:false, stats:pseudo",
+		"  └─TableReader(Probe) 9990.00 root  data:Selection",
+		"    └─Selection 9990.00 cop[tikv]  not(isnull(test.t1.a))",
+		"      └─TableFullScan 10000.00 cop[tikv] table:t1 keep order:false, stats:pseudo"))
+
+	// A derived table without LATERAL and the right side of a RIGHT JOIN can't refer to the preceding tables.
+	err := tk.ExecToErr("select * from t1 join (select t2.b from t2 where t2.a = t1.a) dt")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[planner:1054]Unknown column 't1.a' in 'where clause'")
+	err = tk.ExecToErr("select * from t1 right join lateral (select t2.b from t2 where t2.a = t1.a) dt on true")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[planner:1054]Unknown column 't1.a' in 'where clause'")
+}
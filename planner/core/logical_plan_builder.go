@@ -43,6 +43,7 @@ import (
 	"github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
@@ -665,7 +666,21 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		return nil, err
 	}
 
+	// A lateral derived table can refer to the columns of the left side, it's built
+	// like a correlated subquery and joined by LogicalApply, which is turned back into
+	// a join by the decorrelation rule if possible. The right side of a RIGHT JOIN can't
+	// refer to the left side, the same as MySQL.
+	isLateral := false
+	if ts, ok := joinNode.Right.(*ast.TableSource); ok && ts.Lateral && joinNode.Tp != ast.RightJoin {
+		isLateral = true
+		b.outerSchemas = append(b.outerSchemas, leftPlan.Schema().Clone())
+		b.outerNames = append(b.outerNames, leftPlan.OutputNames())
+	}
 	rightPlan, err := b.buildResultSetNode(ctx, joinNode.Right)
+	if isLateral {
+		b.outerSchemas = b.outerSchemas[0 : len(b.outerSchemas)-1]
+		b.outerNames = b.outerNames[0 : len(b.outerNames)-1]
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -675,6 +690,13 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 	b.handleHelper.mergeAndPush(handleMap1, handleMap2)
 
 	joinPlan := LogicalJoin{StraightJoin: joinNode.StraightJoin || b.inStraightJoin}.Init(b.ctx, b.getSelectOffset())
+	// result is the plan of the join, it's different from joinPlan only for the lateral derived tables.
+	var result LogicalPlan = joinPlan
+	if isLateral {
+		b.optFlag = b.optFlag | flagBuildKeyInfo | flagDecorrelate
+		ap := LogicalApply{LogicalJoin: LogicalJoin{StraightJoin: joinPlan.StraightJoin}}.Init(b.ctx, b.getSelectOffset())
+		joinPlan, result = &ap.LogicalJoin, ap
+	}
 	joinPlan.SetChildren(leftPlan, rightPlan)
 	joinPlan.SetSchema(expression.MergeSchema(leftPlan.Schema(), rightPlan.Schema()))
 	joinPlan.names = make([]*types.FieldName, leftPlan.Schema().Len()+rightPlan.Schema().Len())
@@ -739,11 +761,11 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		}
 	} else if joinNode.On != nil {
 		b.curClause = onClause
-		onExpr, newPlan, err := b.rewrite(ctx, joinNode.On.Expr, joinPlan, nil, false)
+		onExpr, newPlan, err := b.rewrite(ctx, joinNode.On.Expr, result, nil, false)
 		if err != nil {
 			return nil, err
 		}
-		if newPlan != joinPlan {
+		if newPlan != result {
 			return nil, errors.New("ON condition doesn't support subqueries yet")
 		}
 		onCondition := expression.SplitCNFItems(onExpr)
@@ -751,7 +773,7 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		// possible decorrelate optimizations. The ON clause is actually treated as a WHERE clause now.
 		if joinPlan.JoinType == InnerJoin {
 			sel := LogicalSelection{Conditions: onCondition}.Init(b.ctx, b.getSelectOffset())
-			sel.SetChildren(joinPlan)
+			sel.SetChildren(result)
 			return sel, nil
 		}
 		joinPlan.AttachOnConds(onCondition)
@@ -761,7 +783,7 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		joinPlan.cartesianJoin = true
 	}
 
-	return joinPlan, nil
+	return result, nil
 }
 
 // buildUsingClause eliminate the redundant columns and ordering columns based
@@ -3540,8 +3562,10 @@ func getStatsTable(ctx sessionctx.Context, tblInfo *model.TableInfo, pid int64)
 	var statsTbl *statistics.Table
 	if pid == tblInfo.ID || ctx.GetSessionVars().UseDynamicPartitionPrune() {
 		statsTbl = statsHandle.GetTableStats(tblInfo)
+		recordUsedStats(ctx, tblInfo, tblInfo.ID, statsTbl)
 	} else {
 		statsTbl = statsHandle.GetPartitionStats(tblInfo, pid)
+		recordUsedStats(ctx, tblInfo, pid, statsTbl)
 	}
 
 	// 2. table row count from statistics is zero.
@@ -3560,6 +3584,26 @@ func getStatsTable(ctx sessionctx.Context, tblInfo *model.TableInfo, pid int64)
 	return statsTbl
 }
 
+// recordUsedStats records the versions of the stats used by the optimizer into the statement context, they are shown
+// in the slow log and the statements summary to find out whether a plan change is caused by the change of the stats.
+func recordUsedStats(ctx sessionctx.Context, tblInfo *model.TableInfo, physicalID int64, statsTbl *statistics.Table) {
+	info := stmtctx.UsedStatsInfo{Name: tblInfo.Name.O, TblInfo: tblInfo}
+	if physicalID != tblInfo.ID {
+		info.Name += "." + tblInfo.GetPartitionInfo().GetNameByID(physicalID)
+	}
+	if !usePseudoStats(statsTbl) {
+		info.Version = statsTbl.Version
+		info.HistVersion = statsTbl.LastAnalyzeVersion()
+	}
+	ctx.GetSessionVars().StmtCtx.RecordUsedStats(physicalID, info)
+}
+
+// usePseudoStats checks whether the pseudo stats are used instead of the stats, which happens if the table is empty
+// or the stats is outdated.
+func usePseudoStats(statsTbl *statistics.Table) bool {
+	return statsTbl.Pseudo || statsTbl.Count == 0 || statsTbl.IsOutdated()
+}
+
 func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, asName *model.CIStr) (LogicalPlan, error) {
 	dbName := tn.Schema
 	sessionVars := b.ctx.GetSessionVars()
@@ -3729,13 +3773,14 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 	names := make([]*types.FieldName, 0, len(columns))
 	for i, col := range columns {
 		ds.Columns = append(ds.Columns, col.ToInfo())
+		hidden := tables.IsHiddenToSession(sessionVars, col.ColumnInfo)
 		names = append(names, &types.FieldName{
 			DBName:            dbName,
 			TblName:           tableInfo.Name,
 			ColName:           col.Name,
 			OrigTblName:       tableInfo.Name,
 			OrigColName:       col.Name,
-			Hidden:            col.Hidden,
+			Hidden:            hidden,
 			NotExplicitUsable: col.State != model.StatePublic,
 		})
 		newCol := &expression.Column{
@@ -3743,7 +3788,7 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 			ID:       col.ID,
 			RetType:  col.FieldType.Clone(),
 			OrigName: names[i].String(),
-			IsHidden: col.Hidden,
+			IsHidden: hidden,
 		}
 		if col.IsPKHandleColumn(tableInfo) {
 			handleCols = &IntHandleCols{col: newCol}
@@ -3817,8 +3862,16 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 			}
 		}
 	}
+	// The columns referenced by the generated columns above aren't read by the user, so they're registered after.
+	for i, col := range columns {
+		b.setColumnOrigin(ds.TblCols[i], dbName.L, tableInfo.Name.L, col.Name.L)
+	}
 
-	return result, nil
+	result, err = b.buildRowPolicySelection(ctx, result, ds, dbName.L)
+	if err != nil {
+		return nil, err
+	}
+	return b.buildMaskingProjection(ctx, result, ds, dbName.L)
 }
 
 func (b *PlanBuilder) timeRangeForSummaryTable() QueryTimeRange {
@@ -3962,8 +4015,8 @@ func (b *PlanBuilder) BuildDataSourceFromView(ctx context.Context, dbName model.
 	if err != nil {
 		return nil, err
 	}
-	originalVisitInfo := b.visitInfo
-	b.visitInfo = make([]visitInfo, 0)
+	originalVisitInfo, originalColumnVisitInfo := b.visitInfo, b.columnVisitInfo
+	b.visitInfo, b.columnVisitInfo = make([]visitInfo, 0), nil
 	selectLogicalPlan, err := b.Build(ctx, selectNode)
 	if err != nil {
 		if terror.ErrorNotEqual(err, ErrViewRecursive) &&
@@ -3976,15 +4029,23 @@ func (b *PlanBuilder) BuildDataSourceFromView(ctx context.Context, dbName model.
 
 	if tableInfo.View.Security == model.SecurityDefiner {
 		if pm := privilege.GetPrivilegeManager(b.ctx); pm != nil {
-			for _, v := range b.visitInfo {
-				if !pm.RequestVerificationWithUser(v.db, v.table, v.column, v.privilege, tableInfo.View.Definer) {
-					return nil, ErrViewInvalid.GenWithStackByArgs(dbName.O, tableInfo.Name.O)
-				}
+			err = checkPrivilege(b.GetVisitInfo(), func(v *visitInfo) bool {
+				return pm.RequestVerificationWithUser(v.db, v.table, v.column, v.privilege, tableInfo.View.Definer)
+			})
+			if err != nil {
+				return nil, ErrViewInvalid.GenWithStackByArgs(dbName.O, tableInfo.Name.O)
 			}
 		}
 		b.visitInfo = b.visitInfo[:0]
+		// The columns may be referenced again out of the view.
+		for _, v := range b.columnVisitInfo {
+			v.err = nil
+			delete(b.visitedColumns, v)
+		}
+		b.columnVisitInfo = nil
 	}
 	b.visitInfo = append(originalVisitInfo, b.visitInfo...)
+	b.columnVisitInfo = append(originalColumnVisitInfo, b.columnVisitInfo...)
 
 	if b.ctx.GetSessionVars().StmtCtx.InExplainStmt {
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.ShowViewPriv, dbName.L, tableInfo.Name.L, "", ErrViewNoExplain)
@@ -4039,6 +4100,10 @@ func (b *PlanBuilder) buildProjUponView(ctx context.Context, dbName model.CIStr,
 		})
 		projExprs = append(projExprs, cols[i])
 	}
+	// The columns of the view are checked instead of the underlying ones, which are checked when the view is built.
+	for i, col := range projSchema.Columns {
+		b.setColumnOrigin(col, dbName.L, tableInfo.Name.L, columnInfo[i].Name.L)
+	}
 	projUponView := LogicalProjection{Exprs: projExprs}.Init(b.ctx, b.getSelectOffset())
 	projUponView.names = projNames
 	projUponView.SetChildren(selectLogicalPlan.(LogicalPlan))
@@ -4340,9 +4405,28 @@ func (b *PlanBuilder) buildUpdate(ctx context.Context, update *ast.UpdateStmt) (
 		tblID2table[id], _ = b.is.TableByID(id)
 	}
 	updt.TblColPosInfos, err = buildColumns2Handle(updt.OutputNames(), tblID2Handle, tblID2table, true)
+	if err != nil {
+		return nil, err
+	}
 	updt.PartitionedTable = b.partitionedTable
 	updt.tblID2Table = tblID2table
-	return updt, err
+	for id, tbl := range tblID2table {
+		dbInfo, ok := b.is.SchemaByTable(tbl.Meta())
+		if !ok {
+			continue
+		}
+		check, err := buildRowPolicyCheck(b.ctx, dbInfo.Name.L, tbl.Meta())
+		if err != nil {
+			return nil, err
+		}
+		if check != nil {
+			if updt.RowPolicyChecks == nil {
+				updt.RowPolicyChecks = make(map[int64]expression.Expression)
+			}
+			updt.RowPolicyChecks[id] = check
+		}
+	}
+	return updt, nil
 }
 
 type tblUpdateInfo struct {
@@ -4513,7 +4597,9 @@ func (b *PlanBuilder) buildUpdateLists(ctx context.Context, tableList []*ast.Tab
 					return expr
 				}
 			}
+			b.ignoreColumnVisit = true
 			newExpr, np, err = b.rewriteWithPreprocess(ctx, assign.Expr, p, nil, nil, false, rewritePreprocess)
+			b.ignoreColumnVisit = false
 			if err != nil {
 				return nil, nil, false, err
 			}
@@ -4545,6 +4631,11 @@ func (b *PlanBuilder) buildUpdateLists(ctx context.Context, tableList []*ast.Tab
 			dbName = b.ctx.GetSessionVars().CurrentDB
 		}
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.UpdatePriv, dbName, name.OrigTblName.L, "", nil)
+		if i < len(list) {
+			// The SELECT privilege required by UPDATE is granted by the one on the updated columns too.
+			b.appendColumnVisitInfo(mysql.SelectPriv, dbName, name.OrigTblName.L, name.OrigColName.L)
+			b.appendColumnVisitInfo(mysql.UpdatePriv, dbName, name.OrigTblName.L, name.OrigColName.L)
+		}
 	}
 	return newList, p, allAssignmentsAreConstant, nil
 }
@@ -4922,7 +5013,7 @@ func (b *PlanBuilder) buildByItemsForWindow(
 }
 
 // buildWindowFunctionFrameBound builds the bounds of window function frames.
-// For type `Rows`, the bound expr must be an unsigned integer.
+// For type `Rows` and `Groups`, the bound expr must be an unsigned integer.
 // For type `Range`, the bound expr must be temporal or numeric types.
 func (b *PlanBuilder) buildWindowFunctionFrameBound(ctx context.Context, spec *ast.WindowSpec, orderByItems []property.SortItem, boundClause *ast.FrameBound) (*FrameBound, error) {
 	frameType := spec.Frame.Type
@@ -4931,7 +5022,7 @@ func (b *PlanBuilder) buildWindowFunctionFrameBound(ctx context.Context, spec *a
 		return bound, nil
 	}
 
-	if frameType == ast.Rows {
+	if frameType == ast.Rows || frameType == ast.Groups {
 		if bound.Type == ast.CurrentRow {
 			return bound, nil
 		}
@@ -5223,9 +5314,6 @@ func (b *PlanBuilder) checkOriginWindowSpec(spec *ast.WindowSpec, orderByItems [
 	if spec.Frame == nil {
 		return nil
 	}
-	if spec.Frame.Type == ast.Groups {
-		return ErrNotSupportedYet.GenWithStackByArgs("GROUPS")
-	}
 	start, end := spec.Frame.Extent.Start, spec.Frame.Extent.End
 	if start.Type == ast.Following && start.UnBounded {
 		return ErrWindowFrameStartIllegal.GenWithStackByArgs(getWindowName(spec.Name.O))
@@ -5257,7 +5345,7 @@ func (b *PlanBuilder) checkOriginWindowFrameBound(bound *ast.FrameBound, spec *a
 	}
 
 	frameType := spec.Frame.Type
-	if frameType == ast.Rows {
+	if frameType == ast.Rows || frameType == ast.Groups {
 		if bound.Unit != ast.TimeUnitInvalid {
 			return ErrWindowRowsIntervalUse.GenWithStackByArgs(getWindowName(spec.Name.O))
 		}
@@ -5554,6 +5642,40 @@ func appendVisitInfo(vi []visitInfo, priv mysql.PrivilegeType, db, tbl, col stri
 	})
 }
 
+// appendColumnVisitInfo appends the privilege on the column to columnVisitInfo, see CheckPrivilege.
+func (b *PlanBuilder) appendColumnVisitInfo(priv mysql.PrivilegeType, db, tbl, col string) {
+	vi := visitInfo{privilege: priv, db: db, table: tbl, column: col}
+	if _, ok := b.visitedColumns[vi]; ok {
+		return
+	}
+	if b.visitedColumns == nil {
+		b.visitedColumns = make(map[visitInfo]struct{})
+	}
+	b.visitedColumns[vi] = struct{}{}
+	if user := b.ctx.GetSessionVars().User; user != nil {
+		cmd := strings.ToUpper(mysql.Priv2Str[priv])
+		vi.err = ErrColumnaccessDenied.FastGenByArgs(cmd, user.AuthUsername, user.AuthHostname, col, tbl)
+	}
+	b.columnVisitInfo = append(b.columnVisitInfo, vi)
+}
+
+// visitColumn appends the SELECT privilege on the column if it's read from a table or view.
+func (b *PlanBuilder) visitColumn(col *expression.Column) {
+	if b.ignoreColumnVisit {
+		return
+	}
+	if origin, ok := b.columnOrigins[col.UniqueID]; ok {
+		b.appendColumnVisitInfo(mysql.SelectPriv, origin.db, origin.table, origin.column)
+	}
+}
+
+func (b *PlanBuilder) setColumnOrigin(col *expression.Column, db, tbl, colName string) {
+	if b.columnOrigins == nil {
+		b.columnOrigins = make(map[int64]columnOrigin)
+	}
+	b.columnOrigins[col.UniqueID] = columnOrigin{db: db, table: tbl, column: colName}
+}
+
 func getInnerFromParenthesesAndUnaryPlus(expr ast.ExprNode) ast.ExprNode {
 	if pexpr, ok := expr.(*ast.ParenthesesExpr); ok {
 		return getInnerFromParenthesesAndUnaryPlus(pexpr.Expr)
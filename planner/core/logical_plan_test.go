@@ -1169,7 +1169,7 @@ func (s *testPlanSuite) TestVisitInfo(c *C) {
 		{
 			sql: "RESTORE DATABASE test FROM 'local:///tmp/a'",
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "BACKUP_ADMIN", false},
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "RESTORE_ADMIN", false},
 			},
 		},
 		{
@@ -1181,7 +1181,25 @@ func (s *testPlanSuite) TestVisitInfo(c *C) {
 		{
 			sql: "SHOW RESTORES",
 			ans: []visitInfo{
-				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "BACKUP_ADMIN", false},
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "RESTORE_ADMIN", false},
+			},
+		},
+		{
+			sql: "ALTER INSTANCE RELOAD TLS",
+			ans: []visitInfo{
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "CONNECTION_ADMIN", false},
+			},
+		},
+		{
+			sql: "SET GLOBAL tidb_resource_group = 'rg1'",
+			ans: []visitInfo{
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "SYSTEM_VARIABLES_ADMIN", false},
+			},
+		},
+		{
+			sql: "SET tidb_resource_group = 'rg1'",
+			ans: []visitInfo{
+				{mysql.ExtendedPriv, "", "", "", ErrSpecificAccessDenied, false, "RESOURCE_GROUP_ADMIN", false},
 			},
 		},
 		{
@@ -1493,6 +1511,10 @@ func (s *testPlanSuite) doTestWindowFunction(c *C, input, output []string) {
 			output[i] = ToString(p)
 		})
 		c.Assert(ToString(p), Equals, output[i], comment)
+		// The parser can not restore the GROUPS frames yet.
+		if strings.Contains(output[i], "over(groups ") {
+			continue
+		}
 
 		var sb strings.Builder
 		// After restore, the result should be the same.
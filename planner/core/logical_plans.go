@@ -1032,9 +1032,42 @@ type LogicalLock struct {
 
 // WindowFrame represents a window function frame.
 type WindowFrame struct {
-	Type  ast.FrameType
-	Start *FrameBound
-	End   *FrameBound
+	Type    ast.FrameType
+	Start   *FrameBound
+	End     *FrameBound
+	Exclude FrameExclusion
+}
+
+// FrameExclusion is the EXCLUDE option of a window frame, which removes the
+// current row or its peers, i.e. the rows equal to it on the ORDER BY items,
+// from the frame.
+// The parser does not support the EXCLUDE clause yet, so the frames built from
+// SQL are always FrameExcludeNoOthers.
+type FrameExclusion int
+
+// Window function frame exclusions.
+const (
+	// FrameExcludeNoOthers keeps all the rows of the frame.
+	FrameExcludeNoOthers FrameExclusion = iota
+	// FrameExcludeCurrentRow removes the current row from the frame.
+	FrameExcludeCurrentRow
+	// FrameExcludeGroup removes the current row and its peers from the frame.
+	FrameExcludeGroup
+	// FrameExcludeTies removes the peers of the current row from the frame, but keeps the current row.
+	FrameExcludeTies
+)
+
+// String implements the fmt.Stringer interface.
+func (e FrameExclusion) String() string {
+	switch e {
+	case FrameExcludeCurrentRow:
+		return "exclude current row"
+	case FrameExcludeGroup:
+		return "exclude group"
+	case FrameExcludeTies:
+		return "exclude ties"
+	}
+	return "exclude no others"
 }
 
 // FrameBound is the boundary of a frame.
@@ -0,0 +1,101 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/masking"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/sessionctx"
+)
+
+// maskingRulesOf returns the masking rules of the table applied to the current user. The users with the UNMASK
+// privilege and the internal sessions without users read the original values.
+func maskingRulesOf(sctx sessionctx.Context, db, table string) []masking.Rule {
+	manager := masking.GlobalManager()
+	if !manager.HasRules(db, table) {
+		return nil
+	}
+	vars := sctx.GetSessionVars()
+	vars.StmtCtx.DependOnMaskingRule = true
+	if vars.User == nil {
+		return nil
+	}
+	pm := privilege.GetPrivilegeManager(sctx)
+	if pm == nil || pm.RequestDynamicVerification(vars.ActiveRoles, masking.UnmaskPriv, false) {
+		return nil
+	}
+	return manager.TableRules(db, table)
+}
+
+// buildMaskingProjection replaces the masked columns of the data source with their masking expressions, so the rest
+// of the query, including its filters and joins, only sees the masked values. The UPDATE and DELETE statements
+// would write the masked values back or reveal the original ones by the affected rows, so they can't read the tables
+// with masking rules.
+func (b *PlanBuilder) buildMaskingProjection(ctx context.Context, p LogicalPlan, ds *DataSource, db string) (LogicalPlan, error) {
+	rules := maskingRulesOf(b.ctx, db, ds.tableInfo.Name.L)
+	if len(rules) == 0 {
+		return p, nil
+	}
+	if b.inUpdateStmt || b.inDeleteStmt {
+		return nil, ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or " + masking.UnmaskPriv)
+	}
+	// The columns referenced by the masking expressions aren't read by the user.
+	ignoreColumnVisit := b.ignoreColumnVisit
+	b.ignoreColumnVisit = true
+	defer func() {
+		b.ignoreColumnVisit = ignoreColumnVisit
+	}()
+	names := p.OutputNames()
+	exprs := make([]expression.Expression, 0, p.Schema().Len())
+	cols := make([]*expression.Column, 0, p.Schema().Len())
+	for i, col := range p.Schema().Columns {
+		var rule *masking.Rule
+		for j := range rules {
+			if rules[j].Column == names[i].ColName.L {
+				rule = &rules[j]
+				break
+			}
+		}
+		if rule == nil {
+			exprs = append(exprs, col)
+			cols = append(cols, col)
+			continue
+		}
+		node, err := masking.ParseExpr(rule.Expr)
+		if err != nil {
+			return nil, err
+		}
+		expr, _, err := b.rewrite(ctx, node, p, nil, true)
+		if err != nil {
+			return nil, err
+		}
+		maskedCol := &expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  expr.GetType(),
+			OrigName: col.OrigName,
+		}
+		// Reading the masked column still requires the privileges on the column.
+		b.setColumnOrigin(maskedCol, db, ds.tableInfo.Name.L, rule.Column)
+		exprs = append(exprs, expr)
+		cols = append(cols, maskedCol)
+	}
+	proj := LogicalProjection{Exprs: exprs}.Init(b.ctx, b.getSelectOffset())
+	proj.SetChildren(p)
+	proj.SetSchema(expression.NewSchema(cols...))
+	proj.names = names
+	return proj, nil
+}
@@ -98,24 +98,89 @@ func BuildLogicalPlan(ctx context.Context, sctx sessionctx.Context, node ast.Nod
 
 // CheckPrivilege checks the privilege for a user.
 func CheckPrivilege(activeRoles []*auth.RoleIdentity, pm privilege.Manager, vs []visitInfo) error {
-	for _, v := range vs {
+	return checkPrivilege(vs, func(v *visitInfo) bool {
 		if v.privilege == mysql.ExtendedPriv {
-			if !pm.RequestDynamicVerification(activeRoles, v.dynamicPriv, v.dynamicWithGrant) {
-				if v.err == nil {
-					return ErrPrivilegeCheckFail
-				}
-				return v.err
+			return pm.RequestDynamicVerification(activeRoles, v.dynamicPriv, v.dynamicWithGrant)
+		}
+		return pm.RequestVerification(activeRoles, v.db, v.table, v.column, v.privilege)
+	})
+}
+
+// tablePriv is a privilege on a table.
+type tablePriv struct {
+	privilege mysql.PrivilegeType
+	db        string
+	table     string
+}
+
+// checkPrivilege checks the visitInfo by verify. If the privilege on a table is missing, it's still granted if the
+// privileges on all the columns of the table referenced by the statement are granted. So it's denied if no column
+// is referenced, like SELECT COUNT(*).
+func checkPrivilege(vs []visitInfo, verify func(v *visitInfo) bool) error {
+	var referenced map[tablePriv]bool
+	for i := range vs {
+		if vs[i].column != "" {
+			if referenced == nil {
+				referenced = make(map[tablePriv]bool)
 			}
-		} else if !pm.RequestVerification(activeRoles, v.db, v.table, v.column, v.privilege) {
-			if v.err == nil {
-				return ErrPrivilegeCheckFail
+			referenced[tablePriv{vs[i].privilege, vs[i].db, vs[i].table}] = true
+		}
+	}
+	// missing is the privileges on the tables which are checked by the columns.
+	var missing map[tablePriv]*visitInfo
+	for i := range vs {
+		v := &vs[i]
+		if v.column != "" || verify(v) {
+			continue
+		}
+		key := tablePriv{v.privilege, v.db, v.table}
+		if !referenced[key] {
+			return visitInfoError(v)
+		}
+		if missing == nil {
+			missing = make(map[tablePriv]*visitInfo)
+		}
+		if _, ok := missing[key]; !ok {
+			missing[key] = v
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	// The error of the table is returned if no privilege on the referenced columns is granted.
+	granted := make(map[tablePriv]bool)
+	denied := make(map[tablePriv]*visitInfo)
+	for i := range vs {
+		v := &vs[i]
+		key := tablePriv{v.privilege, v.db, v.table}
+		if v.column == "" || missing[key] == nil {
+			continue
+		}
+		if verify(v) {
+			granted[key] = true
+		} else if _, ok := denied[key]; !ok {
+			denied[key] = v
+		}
+	}
+	for i := range vs {
+		key := tablePriv{vs[i].privilege, vs[i].db, vs[i].table}
+		if v, ok := denied[key]; ok && missing[key] == &vs[i] {
+			if granted[key] {
+				return visitInfoError(v)
 			}
-			return v.err
+			return visitInfoError(missing[key])
 		}
 	}
 	return nil
 }
 
+func visitInfoError(v *visitInfo) error {
+	if v.err == nil {
+		return ErrPrivilegeCheckFail
+	}
+	return v.err
+}
+
 // CheckTableLock checks the table lock.
 func CheckTableLock(ctx sessionctx.Context, is infoschema.InfoSchema, vs []visitInfo) error {
 	if !config.TableLockEnabled() {
@@ -123,6 +188,10 @@ func CheckTableLock(ctx sessionctx.Context, is infoschema.InfoSchema, vs []visit
 	}
 	checker := lock.NewChecker(ctx, is)
 	for i := range vs {
+		// The tables of the columns are checked already.
+		if vs[i].column != "" {
+			continue
+		}
 		err := checker.CheckTableLock(vs[i].db, vs[i].table, vs[i].privilege, vs[i].alterWritable)
 		if err != nil {
 			return err
@@ -137,6 +206,11 @@ func DoOptimize(ctx context.Context, sctx sessionctx.Context, flag uint64, logic
 	if flag&flagPrunColumns > 0 && flag-flagPrunColumns > flagPrunColumns {
 		flag |= flagPrunColumnsAgain
 	}
+	if sctx.GetSessionVars().EnableColumnTracking && !sctx.GetSessionVars().InRestrictedSQL {
+		for tableID, colIDs := range collectPredicateColumns(logic) {
+			sctx.StorePredicateColumns(tableID, colIDs)
+		}
+	}
 	logic, err := logicalOptimize(ctx, flag, logic)
 	if err != nil {
 		return nil, 0, err
@@ -63,6 +63,7 @@ var (
 	_ PhysicalPlan = &PhysicalShuffleReceiverStub{}
 	_ PhysicalPlan = &BatchPointGetPlan{}
 	_ PhysicalPlan = &PhysicalTableSample{}
+	_ PhysicalPlan = &PhysicalFullTextReader{}
 )
 
 // PhysicalTableReader is the table reader in tidb.
@@ -1343,6 +1344,20 @@ type PhysicalTableSample struct {
 	Desc            bool
 }
 
+// PhysicalFullTextReader reads the rows containing any word of the search string of MATCH ... AGAINST
+// through the FULLTEXT index. The MATCH ... AGAINST condition is still evaluated on the rows by the
+// parent Selection.
+type PhysicalFullTextReader struct {
+	physicalSchemaProducer
+
+	TblInfo     *model.TableInfo
+	IndexInfo   *model.IndexInfo
+	Columns     []*model.ColumnInfo
+	TableAsName *model.CIStr
+	// Search is the search string of MATCH ... AGAINST, which is a constant.
+	Search expression.Expression
+}
+
 // TableSampleInfo contains the information for PhysicalTableSample.
 type TableSampleInfo struct {
 	AstNode    *ast.TableSample
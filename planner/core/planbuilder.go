@@ -18,6 +18,7 @@ import (
 	"context"
 	"encoding/binary"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -34,8 +35,12 @@ import (
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/masking"
 	"github.com/pingcap/tidb/planner/property"
 	"github.com/pingcap/tidb/planner/util"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/resourcegroup"
+	"github.com/pingcap/tidb/rowpolicy"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
@@ -47,6 +52,7 @@ import (
 	util2 "github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/execdetails"
+	"github.com/pingcap/tidb/util/fulltext"
 	"github.com/pingcap/tidb/util/hint"
 	"github.com/pingcap/tidb/util/logutil"
 	utilparser "github.com/pingcap/tidb/util/parser"
@@ -70,6 +76,13 @@ type visitInfo struct {
 	dynamicWithGrant bool
 }
 
+// columnOrigin is the column of a table or view which a column of the plan is read from.
+type columnOrigin struct {
+	db     string
+	table  string
+	column string
+}
+
 type indexNestedLoopJoinTables struct {
 	inljTables  []hintTableInfo
 	inlhjTables []hintTableInfo
@@ -420,6 +433,15 @@ type PlanBuilder struct {
 	// visitInfo is used for privilege check.
 	visitInfo     []visitInfo
 	tableHintInfo []tableHintInfo
+	// columnVisitInfo is the privileges on the columns referenced by the statement, they're checked only if the
+	// privilege on the table is missing.
+	columnVisitInfo []visitInfo
+	visitedColumns  map[visitInfo]struct{}
+	// columnOrigins maps the UniqueID of the columns of the tables and views to the columns, so the privileges on
+	// the columns can be checked when they're referenced.
+	columnOrigins map[int64]columnOrigin
+	// ignoreColumnVisit is true when the expressions not written by the user, like generated columns, are rewritten.
+	ignoreColumnVisit bool
 	// optFlag indicates the flags of the optimizer rules.
 	optFlag uint64
 	// capFlag indicates the capability flags.
@@ -519,9 +541,15 @@ func (hch *handleColHelper) tailMap() map[int64][]HandleCols {
 	return hch.id2HandleMapStack[hch.stackTail-1]
 }
 
-// GetVisitInfo gets the visitInfo of the PlanBuilder.
+// GetVisitInfo gets the visitInfo of the PlanBuilder, the privileges on the columns are placed after the ones
+// on the tables.
 func (b *PlanBuilder) GetVisitInfo() []visitInfo {
-	return b.visitInfo
+	if len(b.columnVisitInfo) == 0 {
+		return b.visitInfo
+	}
+	vs := make([]visitInfo, 0, len(b.visitInfo)+len(b.columnVisitInfo))
+	vs = append(vs, b.visitInfo...)
+	return append(vs, b.columnVisitInfo...)
 }
 
 // GetIsForUpdateRead gets if the PlanBuilder use forUpdateRead
@@ -644,7 +672,10 @@ func (b *PlanBuilder) Build(ctx context.Context, node ast.Node) (Plan, error) {
 	case *ast.BinlogStmt, *ast.FlushStmt, *ast.UseStmt, *ast.BRIEStmt,
 		*ast.BeginStmt, *ast.CommitStmt, *ast.RollbackStmt, *ast.CreateUserStmt, *ast.SetPwdStmt, *ast.AlterInstanceStmt,
 		*ast.GrantStmt, *ast.DropUserStmt, *ast.AlterUserStmt, *ast.RevokeStmt, *ast.KillStmt, *ast.DropStatsStmt,
-		*ast.GrantRoleStmt, *ast.RevokeRoleStmt, *ast.SetRoleStmt, *ast.SetDefaultRoleStmt, *ast.ShutdownStmt:
+		*ast.GrantRoleStmt, *ast.RevokeRoleStmt, *ast.SetRoleStmt, *ast.SetDefaultRoleStmt, *ast.ShutdownStmt,
+		*ast.SavepointStmt, *ast.ReleaseSavepointStmt, *ast.CreateResourceGroupStmt, *ast.AlterResourceGroupStmt,
+		*ast.DropResourceGroupStmt, *ast.XAStmt, *ast.CreateRowPolicyStmt, *ast.DropRowPolicyStmt,
+		*ast.CreateMaskingRuleStmt, *ast.DropMaskingRuleStmt:
 		return b.buildSimple(node.(ast.StmtNode))
 	case ast.DDLNode:
 		return b.buildDDL(ctx, x)
@@ -723,7 +754,10 @@ func (b *PlanBuilder) buildSet(ctx context.Context, v *ast.SetStmt) (Plan, error
 	for _, vars := range v.Variables {
 		if vars.IsGlobal {
 			err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or SYSTEM_VARIABLES_ADMIN")
-			b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "SYSTEM_VARIABLES_ADMIN", false, err)
+			b.visitInfo = appendDynamicVisitInfo(b.visitInfo, privilege.SystemVariablesAdmin, false, err)
+		} else if vars.IsSystem && strings.EqualFold(vars.Name, variable.TiDBResourceGroup) {
+			err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or " + resourcegroup.AdminPriv)
+			b.visitInfo = appendDynamicVisitInfo(b.visitInfo, resourcegroup.AdminPriv, false, err)
 		}
 		assign := &expression.VarAssignment{
 			Name:     vars.Name,
@@ -946,7 +980,7 @@ func getPossibleAccessPaths(ctx sessionctx.Context, tableHints *tableHintInfo, i
 		publicPaths = append(publicPaths, genTiFlashPath(tblInfo, false))
 		publicPaths = append(publicPaths, genTiFlashPath(tblInfo, true))
 	}
-	optimizerUseInvisibleIndexes := ctx.GetSessionVars().OptimizerUseInvisibleIndexes
+	optimizerUseInvisibleIndexes := ctx.GetSessionVars().UseInvisibleIndexes()
 
 	check = check && ctx.GetSessionVars().ConnectionID > 0
 	var latestIndexes map[int64]*model.IndexInfo
@@ -961,6 +995,10 @@ func getPossibleAccessPaths(ctx sessionctx.Context, tableHints *tableHintInfo, i
 			if tblInfo.IsCommonHandle && index.Primary {
 				continue
 			}
+			// The FULLTEXT index is only read by MATCH ... AGAINST, see DataSource.tryToGetFullTextTask.
+			if fulltext.IsIndex(index) {
+				continue
+			}
 			if check && latestIndexes == nil {
 				latestIndexes, check, err = getLatestIndexInfo(ctx, tblInfo.ID, 0)
 				if err != nil {
@@ -1023,6 +1061,10 @@ func getPossibleAccessPaths(ctx sessionctx.Context, tableHints *tableHintInfo, i
 		for _, idxName := range hint.IndexNames {
 			path := getPathByIndexName(publicPaths, idxName, tblInfo)
 			if path == nil {
+				// Same as MySQL, the index hints are silently ignored for the FULLTEXT index.
+				if idx := tblInfo.FindIndexByName(idxName.L); idx != nil && fulltext.IsIndex(idx) {
+					continue
+				}
 				err := ErrKeyDoesNotExist.GenWithStackByArgs(idxName, tblInfo.Name)
 				// if hint is from comment-style sql hints, we should throw a warning instead of error.
 				if i < indexHintsLen {
@@ -1177,6 +1219,14 @@ func (b *PlanBuilder) buildAdmin(ctx context.Context, as *ast.AdminStmt) (Plan,
 		p := &CancelDDLJobs{JobIDs: as.JobIDs}
 		p.setSchemaAndNames(buildCancelDDLJobsFields())
 		ret = p
+	case ast.AdminPauseDDLJobs:
+		p := &PauseDDLJobs{JobIDs: as.JobIDs}
+		p.setSchemaAndNames(buildCancelDDLJobsFields())
+		ret = p
+	case ast.AdminResumeDDLJobs:
+		p := &ResumeDDLJobs{JobIDs: as.JobIDs}
+		p.setSchemaAndNames(buildCancelDDLJobsFields())
+		ret = p
 	case ast.AdminCheckIndexRange:
 		schema, names, err := b.buildCheckIndexSchema(as.Tables[0], as.Index)
 		if err != nil {
@@ -1408,6 +1458,10 @@ func (b *PlanBuilder) buildPhysicalIndexLookUpReaders(ctx context.Context, dbNam
 			// Skip checking clustered index.
 			continue
 		}
+		if fulltext.IsIndex(idxInfo) {
+			// Skip checking FULLTEXT index, whose entries are the words of the columns.
+			continue
+		}
 		if idxInfo.State != model.StatePublic {
 			logutil.Logger(ctx).Info("build physical index lookup reader, the index isn't public",
 				zap.String("index", idxInfo.Name.O),
@@ -1491,6 +1545,9 @@ func (b *PlanBuilder) buildAdminCheckTable(ctx context.Context, as *ast.AdminStm
 		if idx.Meta().State != model.StatePublic {
 			return nil, errors.Errorf("index %s state %s isn't public", as.Index, idx.Meta().State)
 		}
+		if fulltext.IsIndex(idx.Meta()) {
+			return nil, errors.Errorf("index %s is a FULLTEXT index, which can't be checked", as.Index)
+		}
 		p.CheckIndex = true
 		readerPlans, indexInfos, err = b.buildPhysicalIndexLookUpReaders(ctx, tblName.Schema, tbl, []table.Index{idx})
 	} else {
@@ -1547,6 +1604,17 @@ func (b *PlanBuilder) buildCheckIndexSchema(tn *ast.TableName, indexName string)
 	return schema, names, nil
 }
 
+// getVirtualColsInfo returns the info of the public virtual generated columns. The hidden columns of the expression
+// indexes are excluded, since their stats are collected by the indexes.
+func getVirtualColsInfo(tblInfo *model.TableInfo) (colsInfo []*model.ColumnInfo) {
+	for _, col := range tblInfo.Columns {
+		if col.IsGenerated() && !col.GeneratedStored && !col.Hidden && col.State == model.StatePublic {
+			colsInfo = append(colsInfo, col)
+		}
+	}
+	return
+}
+
 // getColsInfo returns the info of index columns, normal columns and primary key.
 func getColsInfo(tn *ast.TableName) (indicesInfo []*model.IndexInfo, colsInfo []*model.ColumnInfo) {
 	tbl := tn.TableInfo
@@ -1561,7 +1629,8 @@ func getColsInfo(tn *ast.TableName) (indicesInfo []*model.IndexInfo, colsInfo []
 		colsInfo = append(colsInfo, col)
 	}
 	for _, idx := range tn.TableInfo.Indices {
-		if idx.State == model.StatePublic {
+		// The statistics of the FULLTEXT index, whose entries are the words, are not collected.
+		if idx.State == model.StatePublic && !fulltext.IsIndex(idx) {
 			indicesInfo = append(indicesInfo, idx)
 		}
 	}
@@ -1638,6 +1707,81 @@ func GetPhysicalIDsAndPartitionNames(tblInfo *model.TableInfo, partitionNames []
 	return ids, names, nil
 }
 
+// filterLockedPhysicalIDs removes the physical tables whose stats are locked from the analyzed ones unless
+// tidb_force_analyze_locked_stats is on, a warning is appended for each of the skipped tables.
+func (b *PlanBuilder) filterLockedPhysicalIDs(tbl *ast.TableName, physicalIDs []int64, names []string) ([]int64, []string, error) {
+	statsHandle := domain.GetDomain(b.ctx).StatsHandle()
+	if statsHandle == nil || b.ctx.GetSessionVars().ForceAnalyzeLockedStats {
+		return physicalIDs, names, nil
+	}
+	locked, err := statsHandle.GetLockedTables(physicalIDs...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(locked) == 0 {
+		return physicalIDs, names, nil
+	}
+	filteredIDs := make([]int64, 0, len(physicalIDs))
+	filteredNames := make([]string, 0, len(names))
+	for i, id := range physicalIDs {
+		if _, ok := locked[id]; !ok {
+			filteredIDs = append(filteredIDs, id)
+			filteredNames = append(filteredNames, names[i])
+			continue
+		}
+		if names[i] == "" {
+			b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("skip analyze table %s.%s because its stats are locked", tbl.Schema.O, tbl.Name.O))
+		} else {
+			b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("skip analyze partition %s of table %s.%s because its stats are locked", names[i], tbl.Schema.O, tbl.Name.O))
+		}
+	}
+	return filteredIDs, filteredNames, nil
+}
+
+// getAnalyzeNeededColumns returns the columns whose stats are collected by the analyze of the table, which are the
+// predicate columns and the index columns when tidb_analyze_predicate_columns is on or it's the auto analyze. Nil is
+// returned if all the columns are needed, including the case that no predicate column of the table is recorded.
+func (b *PlanBuilder) getAnalyzeNeededColumns(tblInfo *model.TableInfo) (map[int64]struct{}, error) {
+	sessVars := b.ctx.GetSessionVars()
+	if !sessVars.AnalyzePredicateColumns && !sessVars.InRestrictedSQL {
+		return nil, nil
+	}
+	statsHandle := domain.GetDomain(b.ctx).StatsHandle()
+	if statsHandle == nil {
+		return nil, nil
+	}
+	colIDs, err := statsHandle.GetPredicateColumns(tblInfo.ID)
+	if err != nil || len(colIDs) == 0 {
+		return nil, err
+	}
+	neededCols := make(map[int64]struct{}, len(colIDs))
+	for _, id := range colIDs {
+		neededCols[id] = struct{}{}
+	}
+	for _, idx := range tblInfo.Indices {
+		if idx.State != model.StatePublic {
+			continue
+		}
+		for _, idxCol := range idx.Columns {
+			neededCols[tblInfo.Columns[idxCol.Offset].ID] = struct{}{}
+		}
+	}
+	return neededCols, nil
+}
+
+func filterAnalyzeColumns(colsInfo []*model.ColumnInfo, neededCols map[int64]struct{}) []*model.ColumnInfo {
+	if neededCols == nil {
+		return colsInfo
+	}
+	filtered := make([]*model.ColumnInfo, 0, len(colsInfo))
+	for _, col := range colsInfo {
+		if _, ok := neededCols[col.ID]; ok {
+			filtered = append(filtered, col)
+		}
+	}
+	return filtered
+}
+
 func (b *PlanBuilder) buildAnalyzeTable(as *ast.AnalyzeTableStmt, opts map[ast.AnalyzeOptionType]uint64, version int) (Plan, error) {
 	p := &Analyze{Opts: opts}
 	for _, tbl := range as.TableNames {
@@ -1652,6 +1796,15 @@ func (b *PlanBuilder) buildAnalyzeTable(as *ast.AnalyzeTableStmt, opts map[ast.A
 		if err != nil {
 			return nil, err
 		}
+		neededCols, err := b.getAnalyzeNeededColumns(tbl.TableInfo)
+		if err != nil {
+			return nil, err
+		}
+		colInfo = filterAnalyzeColumns(colInfo, neededCols)
+		physicalIDs, names, err = b.filterLockedPhysicalIDs(tbl, physicalIDs, names)
+		if err != nil {
+			return nil, err
+		}
 		var commonHandleInfo *model.IndexInfo
 		// If we want to analyze this table with analyze version 2 but the existing stats is version 1 and stats feedback is enabled,
 		// we will switch back to analyze version 1.
@@ -1703,6 +1856,7 @@ func (b *PlanBuilder) buildAnalyzeTable(as *ast.AnalyzeTableStmt, opts map[ast.A
 					TableID:       AnalyzeTableID{TableID: tbl.TableInfo.ID, PartitionID: id},
 					Incremental:   as.Incremental,
 					StatsVersion:  version,
+					SampleSize:    b.getAnalyzeSampleSize(as, tbl.TableInfo, physicalIDs[i]),
 				}
 				p.ColTasks = append(p.ColTasks, AnalyzeColumnsTask{
 					HandleCols:       handleCols,
@@ -1713,6 +1867,29 @@ func (b *PlanBuilder) buildAnalyzeTable(as *ast.AnalyzeTableStmt, opts map[ast.A
 				})
 			}
 		}
+		// The virtual columns are not stored in TiKV, so their values are evaluated in TiDB during analyze, which
+		// doesn't support the incremental analyze and the fast analyze.
+		virtualColsInfo := filterAnalyzeColumns(getVirtualColsInfo(tbl.TableInfo), neededCols)
+		if len(virtualColsInfo) > 0 && !as.Incremental && !b.ctx.GetSessionVars().EnableFastAnalyze {
+			for i, id := range physicalIDs {
+				if id == tbl.TableInfo.ID {
+					id = -1
+				}
+				info := analyzeInfo{
+					DBName:        tbl.Schema.O,
+					TableName:     tbl.Name.O,
+					PartitionName: names[i],
+					TableID:       AnalyzeTableID{TableID: tbl.TableInfo.ID, PartitionID: id},
+					StatsVersion:  version,
+					SampleSize:    b.getAnalyzeSampleSize(as, tbl.TableInfo, physicalIDs[i]),
+				}
+				p.VirtualColTasks = append(p.VirtualColTasks, AnalyzeColumnsTask{
+					ColsInfo:    virtualColsInfo,
+					analyzeInfo: info,
+					TblInfo:     tbl.TableInfo,
+				})
+			}
+		}
 	}
 	return p, nil
 }
@@ -1724,6 +1901,10 @@ func (b *PlanBuilder) buildAnalyzeIndex(as *ast.AnalyzeTableStmt, opts map[ast.A
 	if err != nil {
 		return nil, err
 	}
+	physicalIDs, names, err = b.filterLockedPhysicalIDs(as.TableNames[0], physicalIDs, names)
+	if err != nil {
+		return nil, err
+	}
 	statsHandle := domain.GetDomain(b.ctx).StatsHandle()
 	if statsHandle == nil {
 		return nil, errors.Errorf("statistics hasn't been initialized, please try again later")
@@ -1750,6 +1931,7 @@ func (b *PlanBuilder) buildAnalyzeIndex(as *ast.AnalyzeTableStmt, opts map[ast.A
 						PartitionName: names[i], TableID: AnalyzeTableID{TableID: tblInfo.ID, PartitionID: id},
 						Incremental:  as.Incremental,
 						StatsVersion: version,
+						SampleSize:   b.getAnalyzeSampleSize(as, tblInfo, physicalIDs[i]),
 					}
 					p.ColTasks = append(p.ColTasks, AnalyzeColumnsTask{HandleCols: handleCols, analyzeInfo: info, TblInfo: tblInfo})
 				}
@@ -1757,7 +1939,7 @@ func (b *PlanBuilder) buildAnalyzeIndex(as *ast.AnalyzeTableStmt, opts map[ast.A
 			}
 		}
 		idx := tblInfo.FindIndexByName(idxName.L)
-		if idx == nil || idx.State != model.StatePublic {
+		if idx == nil || idx.State != model.StatePublic || fulltext.IsIndex(idx) {
 			return nil, ErrAnalyzeMissIndex.GenWithStackByArgs(idxName.O, tblInfo.Name.O)
 		}
 		for i, id := range physicalIDs {
@@ -1785,6 +1967,10 @@ func (b *PlanBuilder) buildAnalyzeAllIndex(as *ast.AnalyzeTableStmt, opts map[as
 	if err != nil {
 		return nil, err
 	}
+	physicalIDs, names, err = b.filterLockedPhysicalIDs(as.TableNames[0], physicalIDs, names)
+	if err != nil {
+		return nil, err
+	}
 	statsHandle := domain.GetDomain(b.ctx).StatsHandle()
 	if statsHandle == nil {
 		return nil, errors.Errorf("statistics hasn't been initialized, please try again later")
@@ -1797,7 +1983,7 @@ func (b *PlanBuilder) buildAnalyzeAllIndex(as *ast.AnalyzeTableStmt, opts map[as
 		b.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("The analyze version from the session is not compatible with the existing statistics of the table. Use the existing version instead"))
 	}
 	for _, idx := range tblInfo.Indices {
-		if idx.State == model.StatePublic {
+		if idx.State == model.StatePublic && !fulltext.IsIndex(idx) {
 			for i, id := range physicalIDs {
 				if id == tblInfo.ID {
 					id = -1
@@ -1827,6 +2013,7 @@ func (b *PlanBuilder) buildAnalyzeAllIndex(as *ast.AnalyzeTableStmt, opts map[as
 				TableID:       AnalyzeTableID{TableID: tblInfo.ID, PartitionID: id},
 				Incremental:   as.Incremental,
 				StatsVersion:  version,
+				SampleSize:    b.getAnalyzeSampleSize(as, tblInfo, physicalIDs[i]),
 			}
 			p.ColTasks = append(p.ColTasks, AnalyzeColumnsTask{HandleCols: handleCols, analyzeInfo: info, TblInfo: tblInfo})
 		}
@@ -1853,6 +2040,41 @@ var analyzeOptionDefault = map[ast.AnalyzeOptionType]uint64{
 	ast.AnalyzeOptNumSamples:    10000,
 }
 
+// getAnalyzeSampleSize returns the number of rows sampled to build the column stats of the physical table when
+// tidb_analyze_sample_rate is set, 0 means the SAMPLES option is used. The samples are collected by the reservoir
+// sampling in the coprocessor, so each row is picked with the same probability. As for the error bound, a
+// uniform sample of r rows is enough to build an equi-depth histogram of k buckets whose bucket sizes are within the
+// relative error f with the probability 1-γ if r >= 4k*ln(2N/γ)/f^2, where N is the row count. The NDV and the null
+// count are collected on all the rows, so they are not affected by the sample rate.
+func (b *PlanBuilder) getAnalyzeSampleSize(as *ast.AnalyzeTableStmt, tblInfo *model.TableInfo, physicalID int64) uint64 {
+	rate := b.ctx.GetSessionVars().AnalyzeSampleRate
+	if rate <= 0 {
+		return 0
+	}
+	// The SAMPLES option specified explicitly takes precedence over the sample rate.
+	for _, opt := range as.AnalyzeOpts {
+		if opt.Type == ast.AnalyzeOptNumSamples {
+			return 0
+		}
+	}
+	count := int64(statistics.PseudoRowCount)
+	if statsHandle := domain.GetDomain(b.ctx).StatsHandle(); statsHandle != nil {
+		statsTbl := statsHandle.GetTableStats(tblInfo)
+		if physicalID != tblInfo.ID {
+			statsTbl = statsHandle.GetPartitionStats(tblInfo, physicalID)
+		}
+		count = statsTbl.Count
+	}
+	sampleSize := uint64(math.Ceil(rate * float64(count)))
+	if sampleSize == 0 {
+		sampleSize = 1
+	}
+	if sampleSize > analyzeOptionLimit[ast.AnalyzeOptNumSamples] {
+		sampleSize = analyzeOptionLimit[ast.AnalyzeOptNumSamples]
+	}
+	return sampleSize
+}
+
 func handleAnalyzeOptions(opts []ast.AnalyzeOpt) (map[ast.AnalyzeOptionType]uint64, error) {
 	optMap := make(map[ast.AnalyzeOptionType]uint64, len(analyzeOptionDefault))
 	for key, val := range analyzeOptionDefault {
@@ -1899,13 +2121,70 @@ func (b *PlanBuilder) buildAnalyze(as *ast.AnalyzeTableStmt) (Plan, error) {
 	if err != nil {
 		return nil, err
 	}
+	var p Plan
 	if as.IndexFlag {
 		if len(as.IndexNames) == 0 {
-			return b.buildAnalyzeAllIndex(as, opts, statsVersion)
+			p, err = b.buildAnalyzeAllIndex(as, opts, statsVersion)
+		} else {
+			p, err = b.buildAnalyzeIndex(as, opts, statsVersion)
 		}
-		return b.buildAnalyzeIndex(as, opts, statsVersion)
+	} else {
+		p, err = b.buildAnalyzeTable(as, opts, statsVersion)
 	}
-	return b.buildAnalyzeTable(as, opts, statsVersion)
+	if err != nil {
+		return nil, err
+	}
+	analyze := p.(*Analyze)
+	analyze.TableOpts, analyze.SavedOpts, err = b.getTableAnalyzeOptions(as, opts)
+	if err != nil {
+		return nil, err
+	}
+	return analyze, nil
+}
+
+// persistedAnalyzeOptions are the analyze options saved for the tables once they are specified explicitly.
+var persistedAnalyzeOptions = []ast.AnalyzeOptionType{ast.AnalyzeOptNumBuckets, ast.AnalyzeOptNumTopN}
+
+// getTableAnalyzeOptions returns the analyze options of each table, in which the BUCKETS and TOPN options not specified
+// by the statement are replaced by the ones saved for the table, so that the later analyze, including the auto analyze,
+// keeps building the stats as the table is analyzed last time. The BUCKETS and TOPN options specified by the statement
+// are returned as well to be saved for the tables.
+func (b *PlanBuilder) getTableAnalyzeOptions(as *ast.AnalyzeTableStmt, opts map[ast.AnalyzeOptionType]uint64) (map[int64]map[ast.AnalyzeOptionType]uint64, map[ast.AnalyzeOptionType]uint64, error) {
+	savedOpts := make(map[ast.AnalyzeOptionType]uint64, len(persistedAnalyzeOptions))
+	for _, opt := range as.AnalyzeOpts {
+		if opt.Type == ast.AnalyzeOptNumBuckets || opt.Type == ast.AnalyzeOptNumTopN {
+			savedOpts[opt.Type] = opt.Value
+		}
+	}
+	if !b.ctx.GetSessionVars().PersistAnalyzeOptions {
+		return nil, nil, nil
+	}
+	statsHandle := domain.GetDomain(b.ctx).StatsHandle()
+	if statsHandle == nil || len(savedOpts) == len(persistedAnalyzeOptions) {
+		return nil, savedOpts, nil
+	}
+	tableOpts := make(map[int64]map[ast.AnalyzeOptionType]uint64, len(as.TableNames))
+	for _, tbl := range as.TableNames {
+		persisted, err := statsHandle.GetAnalyzeOptions(tbl.TableInfo.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(persisted) == 0 {
+			continue
+		}
+		tblOpts := make(map[ast.AnalyzeOptionType]uint64, len(opts))
+		for key, val := range opts {
+			tblOpts[key] = val
+		}
+		for _, tp := range persistedAnalyzeOptions {
+			val, ok := persisted[tp]
+			if _, specified := savedOpts[tp]; ok && !specified && val <= analyzeOptionLimit[tp] {
+				tblOpts[tp] = val
+			}
+		}
+		tableOpts[tbl.TableInfo.ID] = tblOpts
+	}
+	return tableOpts, savedOpts, nil
 }
 
 func buildShowNextRowID() (*expression.Schema, types.NameSlice) {
@@ -1944,7 +2223,7 @@ func buildCleanupIndexFields() (*expression.Schema, types.NameSlice) {
 }
 
 func buildShowDDLJobsFields() (*expression.Schema, types.NameSlice) {
-	schema := newColumnsWithNames(11)
+	schema := newColumnsWithNames(16)
 	schema.Append(buildColumnWithName("", "JOB_ID", mysql.TypeLonglong, 4))
 	schema.Append(buildColumnWithName("", "DB_NAME", mysql.TypeVarchar, 64))
 	schema.Append(buildColumnWithName("", "TABLE_NAME", mysql.TypeVarchar, 64))
@@ -1956,6 +2235,11 @@ func buildShowDDLJobsFields() (*expression.Schema, types.NameSlice) {
 	schema.Append(buildColumnWithName("", "START_TIME", mysql.TypeDatetime, 19))
 	schema.Append(buildColumnWithName("", "END_TIME", mysql.TypeDatetime, 19))
 	schema.Append(buildColumnWithName("", "STATE", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "PROGRESS", mysql.TypeDouble, 22))
+	schema.Append(buildColumnWithName("", "ROW_RATE", mysql.TypeDouble, 22))
+	schema.Append(buildColumnWithName("", "ETA", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "START_KEY", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "END_KEY", mysql.TypeVarchar, 64))
 	return schema.col2Schema(), schema.names
 }
 
@@ -2032,6 +2316,17 @@ func buildBRIESchema() (*expression.Schema, types.NameSlice) {
 	return schema.col2Schema(), schema.names
 }
 
+func buildXARecoverSchema() (*expression.Schema, types.NameSlice) {
+	longlongSize, _ := mysql.GetDefaultFieldLengthAndDecimal(mysql.TypeLonglong)
+
+	schema := newColumnsWithNames(4)
+	schema.Append(buildColumnWithName("", "formatID", mysql.TypeLonglong, longlongSize))
+	schema.Append(buildColumnWithName("", "gtrid_length", mysql.TypeLonglong, longlongSize))
+	schema.Append(buildColumnWithName("", "bqual_length", mysql.TypeLonglong, longlongSize))
+	schema.Append(buildColumnWithName("", "data", mysql.TypeVarchar, 258))
+	return schema.col2Schema(), schema.names
+}
+
 func buildShowTelemetrySchema() (*expression.Schema, types.NameSlice) {
 	schema := newColumnsWithNames(1)
 	schema.Append(buildColumnWithName("", "TRACKING_ID", mysql.TypeVarchar, 64))
@@ -2140,9 +2435,12 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (Plan,
 	case ast.ShowCreateView:
 		err := ErrSpecificAccessDenied.GenWithStackByArgs("SHOW VIEW")
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.ShowViewPriv, show.Table.Schema.L, show.Table.Name.L, "", err)
-	case ast.ShowBackups, ast.ShowRestores:
+	case ast.ShowBackups:
 		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or BACKUP_ADMIN")
-		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "BACKUP_ADMIN", false, err)
+		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, privilege.BackupAdmin, false, err)
+	case ast.ShowRestores:
+		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or RESTORE_ADMIN")
+		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, privilege.RestoreAdmin, false, err)
 	case ast.ShowTableNextRowId:
 		p := &ShowNextRowID{TableName: show.Table}
 		p.setSchemaAndNames(buildShowNextRowID())
@@ -2216,11 +2514,24 @@ func (b *PlanBuilder) buildSimple(node ast.StmtNode) (Plan, error) {
 		err := ErrSpecificAccessDenied.GenWithStackByArgs("RELOAD")
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.ReloadPriv, "", "", "", err)
 	case *ast.AlterInstanceStmt:
-		err := ErrSpecificAccessDenied.GenWithStack("SUPER")
-		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "", "", "", err)
+		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or CONNECTION_ADMIN")
+		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, privilege.ConnectionAdmin, false, err)
 	case *ast.AlterUserStmt:
 		err := ErrSpecificAccessDenied.GenWithStackByArgs("CREATE USER")
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.CreateUserPriv, "", "", "", err)
+		if raw.ResourceGroupNameOption != nil {
+			err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or " + resourcegroup.AdminPriv)
+			b.visitInfo = appendDynamicVisitInfo(b.visitInfo, resourcegroup.AdminPriv, false, err)
+		}
+	case *ast.CreateResourceGroupStmt, *ast.AlterResourceGroupStmt, *ast.DropResourceGroupStmt:
+		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or " + resourcegroup.AdminPriv)
+		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, resourcegroup.AdminPriv, false, err)
+	case *ast.CreateRowPolicyStmt, *ast.DropRowPolicyStmt:
+		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or " + rowpolicy.BypassPriv)
+		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, rowpolicy.BypassPriv, false, err)
+	case *ast.CreateMaskingRuleStmt, *ast.DropMaskingRuleStmt:
+		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or " + masking.UnmaskPriv)
+		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, masking.UnmaskPriv, false, err)
 	case *ast.GrantStmt:
 		if b.ctx.GetSessionVars().CurrentDB == "" && raw.Level.DBName == "" {
 			if raw.Level.Level == ast.GrantLevelTable {
@@ -2230,15 +2541,24 @@ func (b *PlanBuilder) buildSimple(node ast.StmtNode) (Plan, error) {
 		b.visitInfo = collectVisitInfoFromGrantStmt(b.ctx, b.visitInfo, raw)
 	case *ast.BRIEStmt:
 		p.setSchemaAndNames(buildBRIESchema())
-		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or BACKUP_ADMIN")
-		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "BACKUP_ADMIN", false, err)
+		if raw.Kind == ast.BRIEKindRestore {
+			err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or RESTORE_ADMIN")
+			b.visitInfo = appendDynamicVisitInfo(b.visitInfo, privilege.RestoreAdmin, false, err)
+		} else {
+			err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or BACKUP_ADMIN")
+			b.visitInfo = appendDynamicVisitInfo(b.visitInfo, privilege.BackupAdmin, false, err)
+		}
+	case *ast.XAStmt:
+		if raw.Tp == ast.XARecover {
+			p.setSchemaAndNames(buildXARecoverSchema())
+		}
 	case *ast.GrantRoleStmt, *ast.RevokeRoleStmt:
 		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or ROLE_ADMIN")
-		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "ROLE_ADMIN", false, err)
+		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, privilege.RoleAdmin, false, err)
 	case *ast.RevokeStmt:
 		b.visitInfo = collectVisitInfoFromRevokeStmt(b.ctx, b.visitInfo, raw)
 	case *ast.KillStmt:
-		// If you have the SUPER privilege, you can kill all threads and statements.
+		// If you have the SUPER or CONNECTION_ADMIN privilege, you can kill all threads and statements.
 		// Otherwise, you can kill only your own threads and statements.
 		sm := b.ctx.GetSessionManager()
 		if sm != nil {
@@ -2246,7 +2566,7 @@ func (b *PlanBuilder) buildSimple(node ast.StmtNode) (Plan, error) {
 				loginUser := b.ctx.GetSessionVars().User
 				if pi.User != loginUser.Username {
 					err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or CONNECTION_ADMIN")
-					b.visitInfo = appendDynamicVisitInfo(b.visitInfo, "CONNECTION_ADMIN", false, err)
+					b.visitInfo = appendDynamicVisitInfo(b.visitInfo, privilege.ConnectionAdmin, false, err)
 				}
 			}
 		}
@@ -2374,6 +2694,10 @@ func (b *PlanBuilder) findDefaultValue(cols []*table.Column, name *ast.ColumnNam
 // resolveGeneratedColumns resolves generated columns with their generation
 // expressions respectively. onDups indicates which columns are in on-duplicate list.
 func (b *PlanBuilder) resolveGeneratedColumns(ctx context.Context, columns []*table.Column, onDups map[string]struct{}, mockPlan LogicalPlan) (igc InsertGeneratedColumns, err error) {
+	b.ignoreColumnVisit = true
+	defer func() {
+		b.ignoreColumnVisit = false
+	}()
 	for _, column := range columns {
 		if !column.IsGenerated() {
 			continue
@@ -2490,6 +2814,11 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 		}
 		b.visitInfo = appendVisitInfo(b.visitInfo, extraPriv, tn.DBInfo.Name.L, tableInfo.Name.L, "", authErr)
 	}
+	b.visitInsertColumns(insert, tn.DBInfo.Name.L, insertPlan.Table)
+	insertPlan.RowPolicyCheck, err = buildRowPolicyCheck(b.ctx, tn.DBInfo.Name.L, tableInfo)
+	if err != nil {
+		return nil, err
+	}
 
 	mockTablePlan := LogicalTableDual{}.Init(b.ctx, b.getSelectOffset())
 	mockTablePlan.SetSchema(insertPlan.tableSchema)
@@ -2548,6 +2877,29 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 	return insertPlan, err
 }
 
+// visitInsertColumns appends the INSERT privilege on the inserted columns and the UPDATE privilege on the columns
+// updated by ON DUPLICATE KEY UPDATE.
+func (b *PlanBuilder) visitInsertColumns(insert *ast.InsertStmt, db string, tbl table.Table) {
+	tblName := tbl.Meta().Name.L
+	switch {
+	case len(insert.Setlist) > 0:
+		for _, assign := range insert.Setlist {
+			b.appendColumnVisitInfo(mysql.InsertPriv, db, tblName, assign.Column.Name.L)
+		}
+	case len(insert.Columns) > 0:
+		for _, col := range insert.Columns {
+			b.appendColumnVisitInfo(mysql.InsertPriv, db, tblName, col.Name.L)
+		}
+	default:
+		for _, col := range tbl.VisibleCols() {
+			b.appendColumnVisitInfo(mysql.InsertPriv, db, tblName, col.Name.L)
+		}
+	}
+	for _, assign := range insert.OnDuplicate {
+		b.appendColumnVisitInfo(mysql.UpdatePriv, db, tblName, assign.Column.Name.L)
+	}
+}
+
 func (p *Insert) resolveOnDuplicate(onDup []*ast.Assignment, tblInfo *model.TableInfo, yield func(ast.ExprNode) (expression.Expression, error)) (map[string]struct{}, error) {
 	onDupColSet := make(map[string]struct{}, len(onDup))
 	colMap := make(map[string]*table.Column, len(p.Table.Cols()))
@@ -2951,6 +3303,10 @@ func (b *PlanBuilder) buildLoadData(ctx context.Context, ld *ast.LoadDataStmt) (
 	if err != nil {
 		return nil, err
 	}
+	p.RowPolicyCheck, err = buildRowPolicyCheck(b.ctx, p.Table.Schema.L, tableInfo)
+	if err != nil {
+		return nil, err
+	}
 	return p, nil
 }
 
@@ -3028,16 +3384,22 @@ func (b *PlanBuilder) buildSplitIndexRegion(node *ast.SplitRegionStmt) (Plan, er
 		}
 		return b.convertValue2ColumnType(valuesItem, mockTablePlan, indexInfo, tblInfo)
 	}
-	lowerValues, err := checkLowerUpperValue(node.SplitOpt.Lower, "lower")
-	if err != nil {
-		return nil, err
-	}
-	upperValues, err := checkLowerUpperValue(node.SplitOpt.Upper, "upper")
-	if err != nil {
-		return nil, err
+	if len(node.SplitOpt.Lower) == 0 && len(node.SplitOpt.Upper) == 0 {
+		// Split index regions by the distribution of the existing index data,
+		// such as `split table t index idx between () and () regions 10`.
+		p.SplitByStats = true
+	} else {
+		lowerValues, err := checkLowerUpperValue(node.SplitOpt.Lower, "lower")
+		if err != nil {
+			return nil, err
+		}
+		upperValues, err := checkLowerUpperValue(node.SplitOpt.Upper, "upper")
+		if err != nil {
+			return nil, err
+		}
+		p.Lower = lowerValues
+		p.Upper = upperValues
 	}
-	p.Lower = lowerValues
-	p.Upper = upperValues
 
 	maxSplitRegionNum := int64(config.GetGlobalConfig().SplitRegionMaxNum)
 	if node.SplitOpt.Num > maxSplitRegionNum {
@@ -3250,6 +3612,9 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (Plan, err
 				}
 				b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DropPriv, v.Table.Schema.L,
 					v.Table.Name.L, "", authErr)
+			} else if spec.Tp == ast.AlterTablePlacementPolicy {
+				err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or PLACEMENT_ADMIN")
+				b.visitInfo = appendDynamicVisitInfo(b.visitInfo, privilege.PlacementAdmin, false, err)
 			} else if spec.Tp == ast.AlterTableWriteable {
 				b.visitInfo[0].alterWritable = true
 			} else if spec.Tp == ast.AlterTableAddStatistics {
@@ -3311,6 +3676,20 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (Plan, err
 			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, v.ReferTable.Schema.L,
 				v.ReferTable.Name.L, "", authErr)
 		}
+		if v.Select != nil {
+			if b.ctx.GetSessionVars().User != nil {
+				authErr = ErrTableaccessDenied.GenWithStackByArgs("INSERT", b.ctx.GetSessionVars().User.AuthUsername,
+					b.ctx.GetSessionVars().User.AuthHostname, v.Table.Name.L)
+			}
+			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, v.Table.Schema.L,
+				v.Table.Name.L, "", authErr)
+			// The privileges of the select are collected while building it.
+			stmt, selectCols, err := b.buildCreateTableAsSelect(ctx, v)
+			if err != nil {
+				return nil, err
+			}
+			return &DDL{Statement: stmt, SelectCols: selectCols}, nil
+		}
 	case *ast.CreateViewStmt:
 		b.capFlag |= canExpandAST | renameView
 		b.renamingViewName = v.ViewName.Schema.L + "." + v.ViewName.Name.L
@@ -3344,9 +3723,8 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (Plan, err
 			v.Definer = b.ctx.GetSessionVars().User
 		}
 		if b.ctx.GetSessionVars().User != nil && v.Definer.String() != b.ctx.GetSessionVars().User.String() {
-			err = ErrSpecificAccessDenied.GenWithStackByArgs("SUPER")
-			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "",
-				"", "", err)
+			err = ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or SET_USER_ID")
+			b.visitInfo = appendDynamicVisitInfo(b.visitInfo, privilege.SetUserID, false, err)
 		}
 	case *ast.CreateSequenceStmt:
 		if b.ctx.GetSessionVars().User != nil {
@@ -3422,6 +3800,9 @@ func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (Plan, err
 		}
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, v.TableToTables[0].NewTable.Schema.L,
 			v.TableToTables[0].NewTable.Name.L, "", authErr)
+	case *ast.CreatePlacementPolicyStmt, *ast.AlterPlacementPolicyStmt, *ast.DropPlacementPolicyStmt:
+		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER or PLACEMENT_ADMIN")
+		b.visitInfo = appendDynamicVisitInfo(b.visitInfo, privilege.PlacementAdmin, false, err)
 	case *ast.RecoverTableStmt, *ast.FlashBackTableStmt:
 		// Recover table command can only be executed by administrator.
 		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "", "", "", nil)
@@ -3707,8 +4088,8 @@ func buildShowSchema(s *ast.ShowStmt, isView bool, isSequence bool) (schema *exp
 		names = []string{"NodeID", "Address", "State", "Max_Commit_Ts", "Update_Time"}
 		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeLonglong, mysql.TypeVarchar}
 	case ast.ShowStatsMeta:
-		names = []string{"Db_name", "Table_name", "Partition_name", "Update_time", "Modify_count", "Row_count"}
-		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeDatetime, mysql.TypeLonglong, mysql.TypeLonglong}
+		names = []string{"Db_name", "Table_name", "Partition_name", "Update_time", "Modify_count", "Row_count", "Is_locked"}
+		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeDatetime, mysql.TypeLonglong, mysql.TypeLonglong, mysql.TypeTiny}
 	case ast.ShowStatsExtended:
 		names = []string{"Db_name", "Table_name", "Stats_name", "Column_names", "Stats_type", "Stats_val", "Last_update_version"}
 		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeLonglong}
@@ -3740,8 +4121,8 @@ func buildShowSchema(s *ast.ShowStmt, isView bool, isSequence bool) (schema *exp
 		names = []string{"Original_sql", "Bind_sql", "Default_db", "Status", "Create_time", "Update_time", "Charset", "Collation", "Source"}
 		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeDatetime, mysql.TypeDatetime, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar}
 	case ast.ShowAnalyzeStatus:
-		names = []string{"Table_schema", "Table_name", "Partition_name", "Job_info", "Processed_rows", "Start_time", "End_time", "State"}
-		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeLonglong, mysql.TypeDatetime, mysql.TypeDatetime, mysql.TypeVarchar}
+		names = []string{"Table_schema", "Table_name", "Partition_name", "Job_info", "Processed_rows", "Start_time", "End_time", "State", "Progress"}
+		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeLonglong, mysql.TypeDatetime, mysql.TypeDatetime, mysql.TypeVarchar, mysql.TypeDouble}
 	case ast.ShowBuiltins:
 		names = []string{"Supported_builtin_functions"}
 		ftypes = []byte{mysql.TypeVarchar}
@@ -3779,6 +4160,73 @@ func buildChecksumTableSchema() (*expression.Schema, []*types.FieldName) {
 	return schema.col2Schema(), schema.names
 }
 
+// buildCreateTableAsSelect builds the select of a `CREATE TABLE ... SELECT` statement and returns a copy
+// of the statement, the columns inferred from the output of the select are appended to the definition of
+// the new table. A select column that has the same name as an explicitly defined column fills that column
+// instead. It also returns the names of the select columns, in the order they should be inserted.
+func (b *PlanBuilder) buildCreateTableAsSelect(ctx context.Context, stmt *ast.CreateTableStmt) (*ast.CreateTableStmt, []model.CIStr, error) {
+	plan, err := b.Build(ctx, stmt.Select)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The statement may be executed again, e.g. by a prepared statement, so don't change the columns of it.
+	newStmt := *stmt
+	newStmt.Cols = make([]*ast.ColumnDef, len(stmt.Cols), len(stmt.Cols)+len(plan.OutputNames()))
+	copy(newStmt.Cols, stmt.Cols)
+	defined := make(map[string]struct{}, len(stmt.Cols))
+	for _, col := range stmt.Cols {
+		defined[col.Name.Name.L] = struct{}{}
+	}
+	schema := plan.Schema()
+	names := plan.OutputNames()
+	selectCols := make([]model.CIStr, 0, len(names))
+	for i, name := range names {
+		selectCols = append(selectCols, name.ColName)
+		if _, ok := defined[name.ColName.L]; ok {
+			continue
+		}
+		defined[name.ColName.L] = struct{}{}
+		newStmt.Cols = append(newStmt.Cols, buildColumnDefFromSelect(name.ColName, schema.Columns[i].RetType))
+	}
+	return &newStmt, selectCols, nil
+}
+
+// buildColumnDefFromSelect infers the definition of a column created by `CREATE TABLE ... SELECT`
+// from the type of the select column.
+func buildColumnDefFromSelect(name model.CIStr, retType *types.FieldType) *ast.ColumnDef {
+	tp := retType.Clone()
+	// Only the flags describing the value are inherited, keys and auto-increment are not.
+	tp.Flag &= mysql.UnsignedFlag | mysql.BinaryFlag | mysql.ZerofillFlag
+	switch tp.Tp {
+	case mysql.TypeNull:
+		tp.Tp, tp.Flen, tp.Decimal = mysql.TypeString, 0, 0
+		tp.Charset, tp.Collate = charset.CharsetBin, charset.CollationBin
+		tp.Flag |= mysql.BinaryFlag
+	case mysql.TypeVarString, mysql.TypeVarchar:
+		tp.Tp = mysql.TypeVarchar
+		maxLen := 1
+		if cs, err := charset.GetCharsetDesc(tp.Charset); err == nil {
+			maxLen = cs.Maxlen
+		}
+		if tp.Flen*maxLen > mysql.MaxFieldVarCharLength {
+			// Too long for a varchar, fall back to a blob type like MySQL does.
+			tp.Tp = mysql.TypeMediumBlob
+			if tp.Flen*maxLen > mysql.MaxBlobWidth {
+				tp.Tp = mysql.TypeLongBlob
+			}
+			tp.Flen = types.UnspecifiedLength
+		}
+	}
+	colDef := &ast.ColumnDef{
+		Name: &ast.ColumnName{Name: name},
+		Tp:   tp,
+	}
+	if mysql.HasNotNullFlag(retType.Flag) {
+		colDef.Options = append(colDef.Options, &ast.ColumnOption{Tp: ast.ColumnOptionNotNull})
+	}
+	return colDef
+}
+
 // adjustOverlongViewColname adjusts the overlong outputNames of a view to
 // `new_exp_$off` where `$off` is the offset of the output column, $off starts from 1.
 // There is still some MySQL compatible problems.
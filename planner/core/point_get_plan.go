@@ -593,7 +593,7 @@ func newBatchPointGetPlan(
 		}
 	}
 	for _, idxInfo := range tbl.Indices {
-		if !idxInfo.Unique || idxInfo.State != model.StatePublic || idxInfo.Invisible ||
+		if !idxInfo.Unique || idxInfo.State != model.StatePublic || (idxInfo.Invisible && !ctx.GetSessionVars().UseInvisibleIndexes()) ||
 			!indexIsAvailableByHints(idxInfo, indexHints) {
 			continue
 		}
@@ -718,7 +718,7 @@ func tryWhereIn2BatchPointGet(ctx sessionctx.Context, selStmt *ast.SelectStmt) *
 	}
 
 	for _, col := range tbl.Columns {
-		if col.IsGenerated() || col.State != model.StatePublic {
+		if col.IsGenerated() || col.State != model.StatePublic || tables.IsHiddenToSession(ctx.GetSessionVars(), col) {
 			return nil
 		}
 	}
@@ -821,6 +821,10 @@ func tryPointGetPlan(ctx sessionctx.Context, selStmt *ast.SelectStmt, check bool
 		if col.State != model.StatePublic {
 			return nil
 		}
+		// Do not handle the hidden columns, they're unknown to the fields.
+		if tables.IsHiddenToSession(ctx.GetSessionVars(), col) {
+			return nil
+		}
 	}
 	schema, names := buildSchemaFromFields(tblName.Schema, tbl, tblAlias, selStmt.Fields.Fields)
 	if schema == nil {
@@ -877,7 +881,7 @@ func tryPointGetPlan(ctx sessionctx.Context, selStmt *ast.SelectStmt, check bool
 	var err error
 
 	for _, idxInfo := range tbl.Indices {
-		if !idxInfo.Unique || idxInfo.State != model.StatePublic || idxInfo.Invisible ||
+		if !idxInfo.Unique || idxInfo.State != model.StatePublic || (idxInfo.Invisible && !ctx.GetSessionVars().UseInvisibleIndexes()) ||
 			!indexIsAvailableByHints(idxInfo, tblName.IndexHints) {
 			continue
 		}
@@ -989,6 +993,14 @@ func newPointGetPlan(ctx sessionctx.Context, dbName string, schema *expression.S
 }
 
 func checkFastPlanPrivilege(ctx sessionctx.Context, dbName, tableName string, checkTypes ...mysql.PrivilegeType) error {
+	// The fast plans don't apply the row policies, so the restricted users fall back to the normal plans.
+	if len(rowPoliciesOf(ctx, dbName, tableName)) > 0 {
+		return errors.New("row policies apply")
+	}
+	// Nor do they apply the masking rules.
+	if len(maskingRulesOf(ctx, dbName, tableName)) > 0 {
+		return errors.New("masking rules apply")
+	}
 	pm := privilege.GetPrivilegeManager(ctx)
 	var visitInfos []visitInfo
 	for _, checkType := range checkTypes {
@@ -249,6 +249,61 @@ func (s *testPrepareSerialSuite) TestPrepareCacheNow(c *C) {
 	c.Assert(rs[0][3].(string), Equals, rs[0][8].(string))
 }
 
+func (s *testPrepareSerialSuite) TestPrepareCacheFoldDeferredExpr(c *C) {
+	defer testleak.AfterTest(c)()
+	store, dom, err := newStoreWithBootstrap()
+	c.Assert(err, IsNil)
+	tk := testkit.NewTestKit(c, store)
+	orgEnable := core.PreparedPlanCacheEnabled()
+	defer func() {
+		dom.Close()
+		err = store.Close()
+		c.Assert(err, IsNil)
+		core.SetPreparedPlanCache(orgEnable)
+	}()
+	core.SetPreparedPlanCache(true)
+	tk.Se, err = session.CreateSession4TestWithOpt(store, &session.Opt{
+		PreparedPlanCache: kvcache.NewSimpleLRUCache(100, 0.1, math.MaxUint64),
+	})
+	c.Assert(err, IsNil)
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1 (id int primary key, c datetime, key(c))")
+	tk.MustExec("create table t2 (id int primary key, c datetime, key(c))")
+	tk.MustExec("insert into t1 values (1, now() - interval 3 day), (2, now() - interval 10 day)")
+	tk.MustExec("insert into t2 values (1, now() - interval 3 day)")
+
+	// The branch of IF and CASE WHEN over the parameters is chosen at execution.
+	tk.MustExec("prepare stmt from 'select id from t1 where c < if(? > 0, now(), \\'2000-01-01\\') order by id'")
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("1", "2"))
+	tk.MustExec("set @a = -1")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows())
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustExec("prepare stmt from 'select id from t1 where c < case when ? > 0 then now() else \\'2000-01-01\\' end order by id'")
+	tk.MustExec("set @a = -1")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows())
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The null-rejected conditions over the parameters don't prevent the outer join plans from being cached.
+	tk.MustExec("prepare stmt from 'select t1.id from t1 left join t2 on t1.id = t2.id where t2.c < date_sub(now(), interval ? day)'")
+	tk.MustExec("set @a = 5")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows())
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("1"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	// The plan isn't cached if whether the condition is null-rejected depends on the parameters.
+	tk.MustExec("prepare stmt from 'select t1.id from t1 left join t2 on t1.id = t2.id where ifnull(t2.c, now()) < date_sub(now(), interval ? day) order by t1.id'")
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("1"))
+	tk.MustExec("set @a = -1")
+	tk.MustQuery("execute stmt using @a").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+}
+
 func (s *testPrepareSerialSuite) TestPrepareOverMaxPreparedStmtCount(c *C) {
 	defer testleak.AfterTest(c)()
 	store, dom, err := newStoreWithBootstrap()
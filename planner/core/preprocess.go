@@ -563,11 +563,6 @@ func (p *preprocessor) checkCreateTableGrammar(stmt *ast.CreateTableStmt) {
 		p.err = ddl.ErrWrongTableName.GenWithStackByArgs(tName)
 		return
 	}
-	enableNoopFuncs := p.ctx.GetSessionVars().EnableNoopFuncs
-	if stmt.IsTemporary && !enableNoopFuncs {
-		p.err = expression.ErrFunctionsNoopImpl.GenWithStackByArgs("CREATE TEMPORARY TABLE")
-		return
-	}
 	countPrimaryKey := 0
 	for _, colDef := range stmt.Cols {
 		if err := checkColumn(colDef); err != nil {
@@ -613,11 +608,7 @@ func (p *preprocessor) checkCreateTableGrammar(stmt *ast.CreateTableStmt) {
 	if p.err = checkUnsupportedTableOptions(stmt.Options); p.err != nil {
 		return
 	}
-	if stmt.Select != nil {
-		// FIXME: a temp error noticing 'not implemented' (issue 4754)
-		p.err = errors.New("'CREATE TABLE ... SELECT' is not implemented yet")
-		return
-	} else if len(stmt.Cols) == 0 && stmt.ReferTable == nil {
+	if len(stmt.Cols) == 0 && stmt.ReferTable == nil && stmt.Select == nil {
 		p.err = ddl.ErrTableMustHaveColumns
 		return
 	}
@@ -675,11 +666,6 @@ func (p *preprocessor) checkDropSequenceGrammar(stmt *ast.DropSequenceStmt) {
 
 func (p *preprocessor) checkDropTableGrammar(stmt *ast.DropTableStmt) {
 	p.checkDropTableNames(stmt.Tables)
-	enableNoopFuncs := p.ctx.GetSessionVars().EnableNoopFuncs
-	if stmt.IsTemporary && !enableNoopFuncs {
-		p.err = expression.ErrFunctionsNoopImpl.GenWithStackByArgs("DROP TEMPORARY TABLE")
-		return
-	}
 }
 
 func (p *preprocessor) checkDropTableNames(tables []*ast.TableName) {
@@ -1139,6 +1125,10 @@ func (p *preprocessor) handleTableName(tn *ast.TableName) {
 	}
 
 	table, err := p.is.TableByName(tn.Schema, tn.Name)
+	if err == nil && table.Meta().State == model.StateWriteReorganization && !p.ctx.GetSessionVars().InRestrictedSQL {
+		// The table created by `CREATE TABLE ... SELECT` is invisible until its rows are inserted by the DDL job.
+		err = infoschema.ErrTableNotExists.GenWithStackByArgs(tn.Schema, tn.Name)
+	}
 	if err != nil {
 		// We should never leak that the table doesn't exist (i.e. attach ErrTableNotExists)
 		// unless we know that the user has permissions to it, should it exist.
@@ -0,0 +1,116 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+
+	"github.com/pingcap/parser/auth"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/rowpolicy"
+	"github.com/pingcap/tidb/sessionctx"
+)
+
+// rowPoliciesOf returns the row policies of the table applied to the current user, including the policies of all
+// its granted roles. The users with the BYPASSRLS privilege and the internal sessions without users aren't restricted
+// by any policy.
+func rowPoliciesOf(sctx sessionctx.Context, db, table string) []rowpolicy.Policy {
+	manager := rowpolicy.GlobalManager()
+	if !manager.HasPolicies(db, table) {
+		return nil
+	}
+	vars := sctx.GetSessionVars()
+	vars.StmtCtx.DependOnRowPolicy = true
+	if vars.User == nil {
+		return nil
+	}
+	pm := privilege.GetPrivilegeManager(sctx)
+	if pm == nil || pm.RequestDynamicVerification(vars.ActiveRoles, rowpolicy.BypassPriv, false) {
+		return nil
+	}
+	return manager.PoliciesFor(db, table, vars.User, grantedRoles(pm, vars.User))
+}
+
+// grantedRoles returns all the roles granted to the user, directly or through other roles, active or not.
+func grantedRoles(pm privilege.Manager, user *auth.UserIdentity) []*auth.RoleIdentity {
+	var roles []*auth.RoleIdentity
+	visited := make(map[string]struct{})
+	queue := pm.GetAllRoles(user.AuthUsername, user.AuthHostname)
+	for len(queue) > 0 {
+		role := queue[0]
+		queue = queue[1:]
+		if _, ok := visited[role.String()]; ok {
+			continue
+		}
+		visited[role.String()] = struct{}{}
+		roles = append(roles, role)
+		queue = append(queue, pm.GetAllRoles(role.Username, role.Hostname)...)
+	}
+	return roles
+}
+
+// buildRowPolicySelection filters the rows read from the data source by the row policies applied to the current
+// user, a row is visible if it satisfies any of the policies.
+func (b *PlanBuilder) buildRowPolicySelection(ctx context.Context, p LogicalPlan, ds *DataSource, db string) (LogicalPlan, error) {
+	policies := rowPoliciesOf(b.ctx, db, ds.tableInfo.Name.L)
+	if len(policies) == 0 {
+		return p, nil
+	}
+	// The columns referenced by the policies aren't read by the user.
+	ignoreColumnVisit := b.ignoreColumnVisit
+	b.ignoreColumnVisit = true
+	defer func() {
+		b.ignoreColumnVisit = ignoreColumnVisit
+	}()
+	conds := make([]expression.Expression, 0, len(policies))
+	for _, policy := range policies {
+		expr, err := rowpolicy.ParseUsing(policy.Using)
+		if err != nil {
+			return nil, err
+		}
+		cond, _, err := b.rewrite(ctx, expr, ds, nil, true)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+	}
+	cond := expression.ComposeDNFCondition(b.ctx, conds...)
+	sel := LogicalSelection{Conditions: expression.SplitCNFItems(cond)}.Init(b.ctx, b.getSelectOffset())
+	sel.SetChildren(p)
+	return sel, nil
+}
+
+// buildRowPolicyCheck builds the condition the rows written to the table by the current user must satisfy, the
+// columns of the condition are indexed by their offsets in the table. It returns nil if the user isn't restricted.
+func buildRowPolicyCheck(sctx sessionctx.Context, db string, tblInfo *model.TableInfo) (expression.Expression, error) {
+	policies := rowPoliciesOf(sctx, db, tblInfo.Name.L)
+	if len(policies) == 0 {
+		return nil, nil
+	}
+	conds := make([]expression.Expression, 0, len(policies))
+	for _, policy := range policies {
+		expr, err := rowpolicy.ParseUsing(policy.Using)
+		if err != nil {
+			return nil, err
+		}
+		cond, err := expression.RewriteSimpleExprWithTableInfo(sctx, tblInfo, expr)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+	}
+	return expression.ComposeDNFCondition(sctx, conds...), nil
+}
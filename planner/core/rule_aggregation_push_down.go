@@ -41,7 +41,8 @@ func (a *aggregationPushDownSolver) isDecomposableWithJoin(fun *aggregation.AggF
 	case ast.AggFuncAvg, ast.AggFuncGroupConcat, ast.AggFuncVarPop, ast.AggFuncJsonObjectAgg, ast.AggFuncStddevPop, ast.AggFuncVarSamp, ast.AggFuncStddevSamp, ast.AggFuncApproxPercentile:
 		// TODO: Support avg push down.
 		return false
-	case ast.AggFuncMax, ast.AggFuncMin, ast.AggFuncFirstRow:
+	case ast.AggFuncMax, ast.AggFuncMin, ast.AggFuncFirstRow, ast.AggFuncBitAnd, ast.AggFuncBitOr:
+		// These functions are idempotent, so the duplicated rows produced by the join don't change the result.
 		return true
 	case ast.AggFuncSum, ast.AggFuncCount:
 		return !fun.HasDistinct
@@ -55,12 +56,18 @@ func (a *aggregationPushDownSolver) isDecomposableWithUnion(fun *aggregation.Agg
 		return false
 	}
 	switch fun.Name {
-	case ast.AggFuncGroupConcat, ast.AggFuncVarPop, ast.AggFuncJsonObjectAgg, ast.AggFuncApproxPercentile:
+	case ast.AggFuncGroupConcat, ast.AggFuncVarPop, ast.AggFuncJsonObjectAgg:
 		return false
+	case ast.AggFuncApproxPercentile:
+		return !fun.HasDistinct && aggregation.ApproxPercentileSplittable(fun)
 	case ast.AggFuncMax, ast.AggFuncMin, ast.AggFuncFirstRow:
 		return true
 	case ast.AggFuncSum, ast.AggFuncCount, ast.AggFuncAvg, ast.AggFuncApproxCountDistinct:
 		return true
+	case ast.AggFuncBitAnd, ast.AggFuncBitOr:
+		return true
+	case ast.AggFuncBitXor:
+		return !fun.HasDistinct
 	default:
 		return false
 	}
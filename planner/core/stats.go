@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/planner/property"
 	"github.com/pingcap/tidb/planner/util"
+	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/logutil"
@@ -237,7 +238,8 @@ func (ds *DataSource) initStats(colGroups [][]*expression.Column) {
 }
 
 func (ds *DataSource) deriveStatsByFilter(conds expression.CNFExprs, filledPaths []*util.AccessPath) *property.StatsInfo {
-	selectivity, nodes, err := ds.tableStats.HistColl.Selectivity(ds.ctx, conds, filledPaths)
+	estConds, coll := ds.substituteVirtualColumns4Stats(conds)
+	selectivity, nodes, err := coll.Selectivity(ds.ctx, estConds, filledPaths)
 	if err != nil {
 		logutil.BgLogger().Debug("something wrong happened, use the default selectivity", zap.Error(err))
 		selectivity = SelectionFactor
@@ -249,6 +251,89 @@ func (ds *DataSource) deriveStatsByFilter(conds expression.CNFExprs, filledPaths
 	return stats
 }
 
+// substituteVirtualColumns4Stats replaces the expressions of the analyzed virtual generated columns in the
+// conditions with these columns, and returns the new conditions along with the HistColl containing the stats of
+// these columns. So the selectivity of the predicates over the same expressions can be estimated by the stats of
+// the virtual columns. The new conditions are only used for the estimation, the plan is not changed.
+func (ds *DataSource) substituteVirtualColumns4Stats(conds expression.CNFExprs) (expression.CNFExprs, *statistics.HistColl) {
+	coll := ds.tableStats.HistColl
+	if ds.TblColHists == nil || len(conds) == 0 {
+		return conds, coll
+	}
+	sc := ds.ctx.GetSessionVars().StmtCtx
+	var virtualCols []*expression.Column
+	for _, col := range ds.TblCols {
+		if col.VirtualExpr == nil || !col.GetType().Equal(col.VirtualExpr.GetType()) {
+			continue
+		}
+		if colHist, ok := ds.TblColHists.Columns[col.UniqueID]; ok && !colHist.IsInvalid(sc, false) {
+			virtualCols = append(virtualCols, col)
+		}
+	}
+	if len(virtualCols) == 0 {
+		return conds, coll
+	}
+	newConds := make(expression.CNFExprs, 0, len(conds))
+	var newColl *statistics.HistColl
+	for _, cond := range conds {
+		newCond, col := substituteVirtualColumn4Stats(ds.ctx, cond, virtualCols)
+		if col != nil {
+			if newColl == nil {
+				newColl = &statistics.HistColl{}
+				*newColl = *coll
+				newColl.Columns = make(map[int64]*statistics.Column, len(coll.Columns)+len(virtualCols))
+				for id, colHist := range coll.Columns {
+					newColl.Columns[id] = colHist
+				}
+			}
+			newColl.Columns[col.UniqueID] = ds.TblColHists.Columns[col.UniqueID]
+		}
+		newConds = append(newConds, newCond)
+	}
+	if newColl == nil {
+		return conds, coll
+	}
+	return newConds, newColl
+}
+
+// substituteVirtualColumn4Stats replaces the expression compared with constants in the condition with the virtual
+// column of the same expression, it returns the substituted column or nil if nothing is replaced.
+func substituteVirtualColumn4Stats(sctx sessionctx.Context, cond expression.Expression, cols []*expression.Column) (expression.Expression, *expression.Column) {
+	sf, ok := cond.(*expression.ScalarFunction)
+	if !ok {
+		return cond, nil
+	}
+	sc := sctx.GetSessionVars().StmtCtx
+	args := sf.GetArgs()
+	var idx int
+	switch sf.FuncName.L {
+	case ast.EQ, ast.NE, ast.LT, ast.LE, ast.GT, ast.GE:
+		if args[1].ConstItem(sc) {
+			idx = 0
+		} else if args[0].ConstItem(sc) {
+			idx = 1
+		} else {
+			return cond, nil
+		}
+	case ast.In:
+		for _, arg := range args[1:] {
+			if !arg.ConstItem(sc) {
+				return cond, nil
+			}
+		}
+	default:
+		return cond, nil
+	}
+	for _, col := range cols {
+		if args[idx].Equal(sctx, col.VirtualExpr) {
+			newCond := sf.Clone().(*expression.ScalarFunction)
+			newCond.GetArgs()[idx] = col
+			return newCond, col
+		}
+	}
+	return cond, nil
+}
+
 // DeriveStats implement LogicalPlan DeriveStats interface.
 func (ds *DataSource) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema, colGroups [][]*expression.Column) (*property.StatsInfo, error) {
 	if ds.stats != nil && len(colGroups) == 0 {
@@ -601,11 +686,45 @@ func (p *LogicalSelection) DeriveStats(childStats []*property.StatsInfo, selfSch
 	if p.stats != nil {
 		return p.stats, nil
 	}
-	p.stats = childStats[0].Scale(SelectionFactor)
+	p.stats = childStats[0].Scale(p.selectivity4VirtualColumns())
 	p.stats.GroupNDVs = nil
 	return p.stats, nil
 }
 
+// selectivity4VirtualColumns estimates the selectivity of the conditions over the data source by the stats of the
+// analyzed virtual generated columns, since these conditions can't be pushed down to the data source. It returns
+// the default selection factor if no stats of the virtual columns can be used.
+func (p *LogicalSelection) selectivity4VirtualColumns() float64 {
+	// The children are not set when the stats are derived by the cascades planner.
+	if len(p.children) == 0 {
+		return SelectionFactor
+	}
+	ds, ok := p.children[0].(*DataSource)
+	if !ok || ds.tableStats == nil {
+		return SelectionFactor
+	}
+	conds, coll := ds.substituteVirtualColumns4Stats(p.Conditions)
+	sc := p.ctx.GetSessionVars().StmtCtx
+	hasVirtualColStats := false
+	for _, col := range expression.ExtractColumnsFromExpressions(nil, conds, nil) {
+		if col.VirtualExpr == nil {
+			continue
+		}
+		if colHist, ok := coll.Columns[col.UniqueID]; ok && !colHist.IsInvalid(sc, false) {
+			hasVirtualColStats = true
+			break
+		}
+	}
+	if !hasVirtualColStats {
+		return SelectionFactor
+	}
+	selectivity, _, err := coll.Selectivity(p.ctx, conds, nil)
+	if err != nil {
+		return SelectionFactor
+	}
+	return selectivity
+}
+
 // DeriveStats implement LogicalPlan DeriveStats interface.
 func (p *LogicalUnionAll) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema, _ [][]*expression.Column) (*property.StatsInfo, error) {
 	if p.stats != nil {
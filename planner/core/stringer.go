@@ -249,6 +249,13 @@ func toString(in Plan, strs []string, idxs []int) ([]string, []int) {
 			}
 			children = append(children, fmt.Sprintf("Table(%s)", strings.Join(colNames, ", ")))
 		}
+		for _, col := range x.VirtualColTasks {
+			colNames := make([]string, 0, len(col.ColsInfo))
+			for _, c := range col.ColsInfo {
+				colNames = append(colNames, c.Name.O)
+			}
+			children = append(children, fmt.Sprintf("Virtual(%s)", strings.Join(colNames, ", ")))
+		}
 		str = str + strings.Join(children, ",") + "}"
 	case *Update:
 		str = fmt.Sprintf("%s->Update", ToString(x.SelectPlan))
@@ -1492,7 +1492,7 @@ func BuildFinalModeAggregation(
 					partialCursor++
 				}
 			}
-			if finalAggFunc.Name == ast.AggFuncApproxCountDistinct {
+			if finalAggFunc.Name == ast.AggFuncApproxCountDistinct || finalAggFunc.Name == ast.AggFuncApproxPercentile {
 				ft := types.NewFieldType(mysql.TypeString)
 				ft.Charset, ft.Collate = charset.CharsetBin, charset.CollationBin
 				ft.Flag |= mysql.NotNullFlag
@@ -1526,6 +1526,13 @@ func BuildFinalModeAggregation(
 				approxCountDistinctAgg.Name = ast.AggFuncApproxCountDistinct
 				approxCountDistinctAgg.RetTp = partial.Schema.Columns[partialCursor-1].GetType()
 				partial.AggFuncs = append(partial.AggFuncs, &approxCountDistinctAgg)
+			} else if aggFunc.Name == ast.AggFuncApproxPercentile {
+				// The partial approx_percentile outputs the serialized t-digest, and the final one
+				// still needs the percent argument.
+				approxPercentileAgg := *aggFunc
+				approxPercentileAgg.RetTp = partial.Schema.Columns[partialCursor-1].GetType()
+				partial.AggFuncs = append(partial.AggFuncs, &approxPercentileAgg)
+				args = append(args, aggFunc.Args[1])
 			} else {
 				partial.AggFuncs = append(partial.AggFuncs, aggFunc)
 			}
@@ -1728,7 +1735,7 @@ func computePartialCursorOffset(name string) int {
 	if aggregation.NeedValue(name) {
 		offset++
 	}
-	if name == ast.AggFuncApproxCountDistinct {
+	if name == ast.AggFuncApproxCountDistinct || name == ast.AggFuncApproxPercentile {
 		offset++
 	}
 	return offset
@@ -295,6 +295,10 @@ func extractStringFromStringSet(set set.StringSet) string {
 }
 
 func tableHasDirtyContent(ctx sessionctx.Context, tableInfo *model.TableInfo) bool {
+	// The data of local temporary tables is always in memory, so it's read like dirty content.
+	if ctx.GetSessionVars().IsLocalTemporaryTable(tableInfo.ID) {
+		return true
+	}
 	pi := tableInfo.GetPartitionInfo()
 	if pi == nil {
 		return ctx.HasDirtyContent(tableInfo.ID)
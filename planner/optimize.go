@@ -90,6 +90,13 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 		}()
 	}
 
+	if err := checkReadOnly(sctx, node); err != nil {
+		return nil, nil, err
+	}
+
+	if execStmt, ok := node.(*ast.ExecuteStmt); ok {
+		setVarsForExecuteStmt(sctx, execStmt)
+	}
 	tableHints := hint.ExtractTableHintsFromStmtNode(node, sctx)
 	stmtHints, warns := handleStmtHints(tableHints)
 	sessVars.StmtCtx.StmtHints = stmtHints
@@ -126,6 +133,12 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 	if err != nil {
 		return nil, nil, err
 	}
+	if stmtNode, ok := node.(ast.StmtNode); ok && sessVars.StmtCtx.UseCache && sctx.Value(bindinfo.SessionBindInfoKeyType) != nil {
+		// Record the statement, so the cached plan is invalidated when the bindings of the statement are changed.
+		if _, normalizedSQL, hash, err := extractSelectAndNormalizeDigest(stmtNode, sessVars.CurrentDB); err == nil && normalizedSQL != "" {
+			sessVars.StmtCtx.BindSQLDigest, sessVars.StmtCtx.BindNormalizedSQL = hash, normalizedSQL
+		}
+	}
 	if !(sessVars.UsePlanBaselines || sessVars.EvolvePlanBaselines) {
 		return bestPlan, names, nil
 	}
@@ -350,20 +363,8 @@ func getBindRecord(ctx sessionctx.Context, stmt ast.StmtNode) (*bindinfo.BindRec
 	if err != nil || stmtNode == nil {
 		return nil, "", err
 	}
-	sessionHandle := ctx.Value(bindinfo.SessionBindInfoKeyType).(*bindinfo.SessionHandle)
-	bindRecord := sessionHandle.GetBindRecord(normalizedSQL, "")
-	if bindRecord != nil {
-		if bindRecord.HasUsingBinding() {
-			return bindRecord, metrics.ScopeSession, nil
-		}
-		return nil, "", nil
-	}
-	globalHandle := domain.GetDomain(ctx).BindHandle()
-	if globalHandle == nil {
-		return nil, "", nil
-	}
-	bindRecord = globalHandle.GetBindRecord(hash, normalizedSQL, "")
-	return bindRecord, metrics.ScopeGlobal, nil
+	bindRecord, scope := plannercore.GetBindRecord(ctx, hash, normalizedSQL)
+	return bindRecord, scope, nil
 }
 
 func handleInvalidBindRecord(ctx context.Context, sctx sessionctx.Context, level string, bindRecord bindinfo.BindRecord) {
@@ -416,6 +417,7 @@ func OptimizeExecStmt(ctx context.Context, sctx sessionctx.Context,
 	execAst *ast.ExecuteStmt, is infoschema.InfoSchema) (plannercore.Plan, error) {
 	defer trace.StartRegion(ctx, "Optimize").End()
 	var err error
+	setVarsForExecuteStmt(sctx, execAst)
 	builder, _ := plannercore.NewPlanBuilder(sctx, is, nil)
 	p, err := builder.Build(ctx, execAst)
 	if err != nil {
@@ -429,6 +431,21 @@ func OptimizeExecStmt(ctx context.Context, sctx sessionctx.Context,
 	return nil, err
 }
 
+// setVarsForExecuteStmt applies the SET_VAR hints of the prepared statement before looking up the plan cache, so that
+// the cache key and the execution of the cached plan see the variables set by the hints. The warnings of the hints are
+// reported when the plan is built.
+func setVarsForExecuteStmt(sctx sessionctx.Context, execStmt *ast.ExecuteStmt) {
+	sessVars := sctx.GetSessionVars()
+	stmt, err := GetPreparedStmt(execStmt, sessVars)
+	if err != nil {
+		return
+	}
+	stmtHints, _ := handleStmtHints(hint.ExtractTableHintsFromStmtNode(stmt, nil))
+	for name, val := range stmtHints.SetVars {
+		_ = variable.SetStmtVar(sessVars, name, val)
+	}
+}
+
 func handleStmtHints(hints []*ast.TableOptimizerHint) (stmtHints stmtctx.StmtHints, warns []error) {
 	if len(hints) == 0 {
 		return
@@ -0,0 +1,130 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package planner
+
+import (
+	"time"
+
+	"github.com/pingcap/parser/ast"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+)
+
+// checkReadOnly rejects the statement if it writes when the server is read-only. The users with the SUPER or
+// CONNECTION_ADMIN privilege can still write unless the server is super read-only, and the transactions started
+// before the server turns read-only can keep writing and committing in the grace period.
+func checkReadOnly(sctx sessionctx.Context, node ast.Node) error {
+	sessVars := sctx.GetSessionVars()
+	if sessVars.InRestrictedSQL {
+		return nil
+	}
+	// The statement explained is optimized again, it has been checked with the EXPLAIN statement.
+	if _, ok := node.(*ast.ExplainStmt); !ok && sessVars.StmtCtx.InExplainStmt {
+		return nil
+	}
+	state := variable.GetReadOnlyState()
+	if !state.ReadOnly {
+		return nil
+	}
+	if _, ok := node.(*ast.CommitStmt); ok {
+		if !hasUncommittedWrites(sctx) {
+			return nil
+		}
+	} else if !isWriteStmt(node, sessVars) {
+		return nil
+	}
+	if sessVars.InTxn() && state.InGracePeriod(sessVars.TxnCtx.CreateTime, time.Now()) {
+		return nil
+	}
+	if state.SuperReadOnly {
+		return plannercore.ErrOptionPreventsStatement.GenWithStackByArgs("--super-read-only")
+	}
+	if pm := privilege.GetPrivilegeManager(sctx); pm != nil && !pm.RequestDynamicVerification(sessVars.ActiveRoles, privilege.ConnectionAdmin, false) {
+		return plannercore.ErrOptionPreventsStatement.GenWithStackByArgs("--read-only")
+	}
+	return nil
+}
+
+func hasUncommittedWrites(sctx sessionctx.Context) bool {
+	txn, err := sctx.Txn(false)
+	return err == nil && txn.Valid() && !txn.IsReadOnly()
+}
+
+// isWriteStmt checks whether the statement writes the data or the metadata. Like MySQL, writing the local
+// temporary tables is not counted.
+func isWriteStmt(node ast.Node, vars *variable.SessionVars) bool {
+	switch x := node.(type) {
+	case *ast.ExecuteStmt:
+		stmt, err := GetPreparedStmt(x, vars)
+		return err == nil && isWriteStmt(stmt, vars)
+	case *ast.ExplainStmt:
+		return x.Analyze && isWriteStmt(x.Stmt, vars)
+	case *ast.InsertStmt:
+		return !onlyLocalTemporaryTables(x.Table, vars)
+	case *ast.UpdateStmt:
+		return !onlyLocalTemporaryTables(x.TableRefs, vars)
+	case *ast.DeleteStmt:
+		return !onlyLocalTemporaryTables(x.TableRefs, vars)
+	case *ast.LoadDataStmt:
+		return !onlyLocalTemporaryTables(x.Table, vars)
+	case *ast.CreateTableStmt:
+		return !x.IsTemporary
+	case *ast.DropTableStmt:
+		return !x.IsTemporary && !x.IsView && !onlyLocalTemporaryTables(x, vars)
+	case *ast.TruncateTableStmt:
+		return !onlyLocalTemporaryTables(x.Table, vars)
+	case ast.DDLNode:
+		return true
+	case *ast.GrantStmt, *ast.GrantRoleStmt, *ast.RevokeStmt, *ast.RevokeRoleStmt, *ast.CreateUserStmt,
+		*ast.AlterUserStmt, *ast.DropUserStmt, *ast.SetPwdStmt, *ast.SetDefaultRoleStmt:
+		return true
+	case *ast.CreateBindingStmt:
+		return x.GlobalScope
+	case *ast.DropBindingStmt:
+		return x.GlobalScope
+	}
+	return false
+}
+
+// onlyLocalTemporaryTables checks whether all the tables in the node are the local temporary tables.
+func onlyLocalTemporaryTables(node ast.Node, vars *variable.SessionVars) bool {
+	if node == nil {
+		return false
+	}
+	collector := &tableNameCollector{vars: vars, onlyLocalTemporary: true}
+	node.Accept(collector)
+	return collector.found && collector.onlyLocalTemporary
+}
+
+type tableNameCollector struct {
+	vars               *variable.SessionVars
+	found              bool
+	onlyLocalTemporary bool
+}
+
+func (c *tableNameCollector) Enter(in ast.Node) (ast.Node, bool) {
+	if tn, ok := in.(*ast.TableName); ok {
+		c.found = true
+		if tn.TableInfo == nil || !c.vars.IsLocalTemporaryTable(tn.TableInfo.ID) {
+			c.onlyLocalTemporary = false
+		}
+	}
+	return in, !c.onlyLocalTemporary
+}
+
+func (c *tableNameCollector) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
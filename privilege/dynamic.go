@@ -0,0 +1,112 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privilege
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/errors"
+)
+
+// The built-in DYNAMIC privileges. The SUPER privilege satisfies all of them unless they are restricted by SEM.
+const (
+	// BackupAdmin is required to back up the data and to show the backup tasks.
+	BackupAdmin = "BACKUP_ADMIN"
+	// RestoreAdmin is required to restore the data and to show the restore tasks.
+	RestoreAdmin = "RESTORE_ADMIN"
+	// SystemVariablesAdmin is required to set the global system variables.
+	SystemVariablesAdmin = "SYSTEM_VARIABLES_ADMIN"
+	// RoleAdmin is required to grant and revoke the roles.
+	RoleAdmin = "ROLE_ADMIN"
+	// ConnectionAdmin is required to kill the connections of the other users, to write when the server is
+	// read-only and to reload the TLS certificates by ALTER INSTANCE. The users with it skip init_connect.
+	ConnectionAdmin = "CONNECTION_ADMIN"
+	// RestrictedTablesAdmin is required to access the tables hidden by SEM.
+	RestrictedTablesAdmin = "RESTRICTED_TABLES_ADMIN"
+	// SetUserID is required to create the views whose definer isn't the current user.
+	SetUserID = "SET_USER_ID"
+	// PlacementAdmin is required to create, alter and drop the placement policies and to set the placement
+	// policies of the tables.
+	PlacementAdmin = "PLACEMENT_ADMIN"
+)
+
+// maxDynamicPrivilegeLength is the length of the PRIV column of mysql.global_grants.
+const maxDynamicPrivilegeLength = 32
+
+var dynamicPrivileges = struct {
+	sync.RWMutex
+	names map[string]struct{}
+}{
+	names: map[string]struct{}{
+		BackupAdmin:           {},
+		RestoreAdmin:          {},
+		SystemVariablesAdmin:  {},
+		RoleAdmin:             {},
+		ConnectionAdmin:       {},
+		RestrictedTablesAdmin: {},
+		SetUserID:             {},
+		PlacementAdmin:        {},
+	},
+}
+
+// RegisterDynamicPrivilege declares a DYNAMIC privilege, so it can be granted and revoked. It's called by the
+// features and the plugins on initialization. The name is case-insensitive, and consists of letters, digits and
+// underscores.
+func RegisterDynamicPrivilege(name string) error {
+	name = strings.ToUpper(name)
+	if name == "" || len(name) > maxDynamicPrivilegeLength {
+		return errors.Errorf("invalid privilege name %q, the length should be in [1, %d]", name, maxDynamicPrivilegeLength)
+	}
+	for _, c := range name {
+		if !(c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			return errors.Errorf("invalid privilege name %q, only letters, digits and underscores are allowed", name)
+		}
+	}
+	dynamicPrivileges.Lock()
+	defer dynamicPrivileges.Unlock()
+	if _, ok := dynamicPrivileges.names[name]; ok {
+		return errors.Errorf("privilege %s is already registered", name)
+	}
+	dynamicPrivileges.names[name] = struct{}{}
+	return nil
+}
+
+// MustRegisterDynamicPrivilege is like RegisterDynamicPrivilege but panics if the privilege can't be registered.
+func MustRegisterDynamicPrivilege(name string) {
+	if err := RegisterDynamicPrivilege(name); err != nil {
+		panic(err)
+	}
+}
+
+// IsDynamicPrivilege returns whether the DYNAMIC privilege is built-in or registered.
+func IsDynamicPrivilege(name string) bool {
+	dynamicPrivileges.RLock()
+	defer dynamicPrivileges.RUnlock()
+	_, ok := dynamicPrivileges.names[strings.ToUpper(name)]
+	return ok
+}
+
+// DynamicPrivileges returns the names of all the DYNAMIC privileges in order.
+func DynamicPrivileges() []string {
+	dynamicPrivileges.RLock()
+	names := make([]string, 0, len(dynamicPrivileges.names))
+	for name := range dynamicPrivileges.names {
+		names = append(names, name)
+	}
+	dynamicPrivileges.RUnlock()
+	sort.Strings(names)
+	return names
+}
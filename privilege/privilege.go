@@ -15,10 +15,12 @@ package privilege
 
 import (
 	"crypto/tls"
+	"time"
 
 	"github.com/pingcap/parser/auth"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 )
 
@@ -50,12 +52,25 @@ type Manager interface {
 	// Dynamic privileges are only assignable globally, and have their own grantable attribute.
 	RequestDynamicVerification(activeRoles []*auth.RoleIdentity, privName string, grantable bool) bool
 
-	// ConnectionVerification verifies user privilege for connection.
-	ConnectionVerification(user, host string, auth, salt []byte, tlsState *tls.ConnectionState) (string, string, bool)
+	// ConnectionVerification verifies user privilege for connection. The authentication plugins which verify the users
+	// by the external services use the auth context, which can be nil for the other plugins.
+	ConnectionVerification(user, host string, auth, salt []byte, tlsState *tls.ConnectionState, authCtx *AuthContext) (string, string, bool)
 
 	// GetAuthWithoutVerification uses to get auth name without verification.
 	GetAuthWithoutVerification(user, host string) (string, string, bool)
 
+	// GetAuthPlugin gets the authentication plugin of the user, the bool is false if the user doesn't exist.
+	GetAuthPlugin(user, host string) (string, bool)
+
+	// GetConnectionLimits gets MAX_USER_CONNECTIONS and MAX_CONNECTIONS_PER_HOUR of the user, 0 means unlimited.
+	GetConnectionLimits(user, host string) (maxUserConnections, maxConnectionsPerHour uint64)
+
+	// GetPasswordLocking gets the state of the account locking of the user, the bool is false if the user doesn't exist.
+	GetPasswordLocking(user, host string) (PasswordLocking, bool)
+
+	// GetPasswordExpiration gets the password expiration of the user, the bool is false if the user doesn't exist.
+	GetPasswordExpiration(user, host string) (PasswordExpiration, bool)
+
 	// DBIsVisible returns true is the database is visible to current user.
 	DBIsVisible(activeRole []*auth.RoleIdentity, db string) bool
 
@@ -79,6 +94,85 @@ type Manager interface {
 	IsDynamicPrivilege(privNameInUpper string) bool
 }
 
+// PasswordLockTimeUnbounded is the PASSWORD_LOCK_TIME of the accounts which are locked until they're unlocked by
+// ALTER USER ... ACCOUNT UNLOCK.
+const PasswordLockTimeUnbounded = -1
+
+// PasswordLocking is the state of the account locking of a user. The account is temporarily locked after
+// FailedLoginAttempts consecutive failed logins for PasswordLockTime days, the locking is disabled if either of them
+// is 0.
+type PasswordLocking struct {
+	AccountLocked       bool
+	FailedLoginAttempts int64
+	PasswordLockTime    int64
+	FailedLoginCount    int64
+	// AutoLockedTime is the time the account is locked by the failed logins, it's zero if the account isn't locked.
+	AutoLockedTime time.Time
+}
+
+// Enabled returns whether the failed logins of the account are tracked.
+func (l *PasswordLocking) Enabled() bool {
+	return l.FailedLoginAttempts > 0 && l.PasswordLockTime != 0
+}
+
+// AutoLocked returns whether the account is still locked by the failed logins at the time.
+func (l *PasswordLocking) AutoLocked(now time.Time) bool {
+	if l.AutoLockedTime.IsZero() {
+		return false
+	}
+	if l.PasswordLockTime == PasswordLockTimeUnbounded {
+		return true
+	}
+	return now.Before(l.AutoLockedTime.Add(time.Duration(l.PasswordLockTime) * 24 * time.Hour))
+}
+
+// PasswordLifetimeDefault is the password lifetime of the accounts whose passwords expire after the global
+// default_password_lifetime days.
+const PasswordLifetimeDefault = -1
+
+// PasswordExpiration is the password expiration of a user. The password expires if it's expired manually by
+// ALTER USER ... PASSWORD EXPIRE, or it isn't changed for Lifetime days, it never expires by time if Lifetime is 0.
+type PasswordExpiration struct {
+	Expired     bool
+	LastChanged time.Time
+	Lifetime    int64
+}
+
+// IsExpired returns whether the password has expired at the time, defaultLifetime is the default_password_lifetime.
+func (e *PasswordExpiration) IsExpired(now time.Time, defaultLifetime int64) bool {
+	if e.Expired {
+		return true
+	}
+	lifetime := e.Lifetime
+	if lifetime == PasswordLifetimeDefault {
+		lifetime = defaultLifetime
+	}
+	if lifetime <= 0 || e.LastChanged.IsZero() {
+		return false
+	}
+	return !now.Before(e.LastChanged.Add(time.Duration(lifetime) * 24 * time.Hour))
+}
+
+// AuthConn is the connection of the client being authenticated, the authentication plugins use it to exchange more
+// data with the client, e.g. the SASL messages of authentication_ldap_sasl.
+type AuthConn interface {
+	// WriteAuthMoreData sends the data of the authentication plugin to the client.
+	WriteAuthMoreData(data []byte) error
+	// ReadPacket reads the next response of the client.
+	ReadPacket() ([]byte, error)
+}
+
+// AuthContext is the context of the authentication plugins which verify the users by the external services, e.g. the
+// LDAP plugins.
+type AuthContext struct {
+	// GlobalVars loads the configuration of the plugins from the global system variables.
+	GlobalVars variable.GlobalVarAccessor
+	// Conn is nil if the plugins can't exchange more data with the client.
+	Conn AuthConn
+	// Roles are set by the plugins to the roles activated for the session, e.g. the roles mapped from the LDAP groups.
+	Roles []*auth.RoleIdentity
+}
+
 const key keyType = 0
 
 // BindPrivilegeManager binds Manager to context.
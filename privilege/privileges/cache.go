@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/parser/auth"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
@@ -63,7 +64,11 @@ const (
 	References_priv,Alter_priv,Execute_priv,Index_priv,Create_view_priv,Show_view_priv,
 	Create_role_priv,Drop_role_priv,Create_tmp_table_priv,Lock_tables_priv,Create_routine_priv,
 	Alter_routine_priv,Event_priv,Shutdown_priv,Reload_priv,File_priv,Config_priv,Repl_client_priv,Repl_slave_priv,
-	account_locked FROM mysql.user`
+	account_locked,plugin,max_connections,max_user_connections%s FROM mysql.user`
+	// sqlUserPasswordColumns are the columns of the account locking and the password expiration, they may be absent
+	// if mysql.user is synchronized from MySQL.
+	sqlUserPasswordColumns = `,failed_login_attempts,password_lock_time,failed_login_count,auto_locked_time,
+	password_expired,password_last_changed,password_lifetime`
 	sqlLoadGlobalGrantsTable = `SELECT HIGH_PRIORITY Host,User,Priv,With_Grant_Option FROM mysql.global_grants`
 )
 
@@ -94,8 +99,17 @@ type UserRecord struct {
 	baseRecord
 
 	AuthenticationString string
-	Privileges           mysql.PrivilegeType
-	AccountLocked        bool // A role record when this field is true
+	// AuthPlugin is the authentication plugin of the user, empty means mysql_native_password.
+	AuthPlugin    string
+	Privileges    mysql.PrivilegeType
+	AccountLocked bool // A role record when this field is true
+	// MaxConnectionsPerHour and MaxUserConnections are the connection limits of the user, 0 means unlimited.
+	MaxConnectionsPerHour uint64
+	MaxUserConnections    uint64
+	// PasswordLocking is the state of the account locking by the failed logins, its AccountLocked isn't set.
+	PasswordLocking privilege.PasswordLocking
+	// PasswordExpiration is the password expiration of the user.
+	PasswordExpiration privilege.PasswordExpiration
 }
 
 // NewUserRecord return a UserRecord, only use for unit test.
@@ -377,6 +391,16 @@ func noSuchTable(err error) bool {
 	return false
 }
 
+func noSuchColumn(err error) bool {
+	e1 := errors.Cause(err)
+	if e2, ok := e1.(*terror.Error); ok {
+		if terror.ErrCode(e2.Code()) == terror.ErrCode(mysql.ErrBadField) {
+			return true
+		}
+	}
+	return false
+}
+
 // LoadRoleGraph loads the mysql.role_edges table from database.
 func (p *MySQLPrivilege) LoadRoleGraph(ctx sessionctx.Context) error {
 	p.RoleGraph = make(map[string]roleGraphEdgesTable)
@@ -389,7 +413,12 @@ func (p *MySQLPrivilege) LoadRoleGraph(ctx sessionctx.Context) error {
 
 // LoadUserTable loads the mysql.user table from database.
 func (p *MySQLPrivilege) LoadUserTable(ctx sessionctx.Context) error {
-	err := p.loadTable(ctx, sqlLoadUserTable, p.decodeUserTableRow)
+	err := p.loadTable(ctx, fmt.Sprintf(sqlLoadUserTable, sqlUserPasswordColumns), p.decodeUserTableRow)
+	if err != nil && noSuchColumn(err) {
+		logutil.BgLogger().Warn("the password columns of mysql.user missing")
+		p.User = p.User[:0]
+		err = p.loadTable(ctx, fmt.Sprintf(sqlLoadUserTable, ""), p.decodeUserTableRow)
+	}
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -632,6 +661,42 @@ func (p *MySQLPrivilege) decodeUserTableRow(row chunk.Row, fs []*ast.ResultField
 			if row.GetEnum(i).String() == "Y" {
 				value.AccountLocked = true
 			}
+		case f.ColumnAsName.L == "plugin":
+			value.AuthPlugin = row.GetString(i)
+		case f.ColumnAsName.L == "max_connections":
+			value.MaxConnectionsPerHour = row.GetUint64(i)
+		case f.ColumnAsName.L == "max_user_connections":
+			value.MaxUserConnections = row.GetUint64(i)
+		case f.ColumnAsName.L == "failed_login_attempts":
+			value.PasswordLocking.FailedLoginAttempts = int64(row.GetUint64(i))
+		case f.ColumnAsName.L == "password_lock_time":
+			value.PasswordLocking.PasswordLockTime = row.GetInt64(i)
+		case f.ColumnAsName.L == "failed_login_count":
+			value.PasswordLocking.FailedLoginCount = int64(row.GetUint64(i))
+		case f.ColumnAsName.L == "auto_locked_time":
+			if !row.IsNull(i) {
+				t, err := row.GetTime(i).GoTime(time.Local)
+				if err != nil {
+					return err
+				}
+				value.PasswordLocking.AutoLockedTime = t
+			}
+		case f.ColumnAsName.L == "password_expired":
+			value.PasswordExpiration.Expired = row.GetEnum(i).String() == "Y"
+		case f.ColumnAsName.L == "password_last_changed":
+			if !row.IsNull(i) {
+				t, err := row.GetTime(i).GoTime(time.Local)
+				if err != nil {
+					return err
+				}
+				value.PasswordExpiration.LastChanged = t
+			}
+		case f.ColumnAsName.L == "password_lifetime":
+			if row.IsNull(i) {
+				value.PasswordExpiration.Lifetime = privilege.PasswordLifetimeDefault
+			} else {
+				value.PasswordExpiration.Lifetime = int64(row.GetUint64(i))
+			}
 		case f.Column.Tp == mysql.TypeEnum:
 			if row.GetEnum(i).String() != "Y" {
 				continue
@@ -1513,3 +1578,13 @@ func (h *Handle) Update(ctx sessionctx.Context) error {
 	h.priv.Store(&priv)
 	return nil
 }
+
+// RequestDynamicVerificationWithUser checks whether the user has the DYNAMIC privilege, directly or by any of its
+// granted roles. It is used for the requests forwarded by the other tidb-servers, which do not carry the active roles.
+func (h *Handle) RequestDynamicVerificationWithUser(user, host, privName string) bool {
+	if SkipWithGrant {
+		return true
+	}
+	mysqlPriv := h.Get()
+	return mysqlPriv.RequestDynamicVerification(mysqlPriv.getAllRoles(user, host), user, host, privName, false)
+}
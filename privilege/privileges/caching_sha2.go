@@ -0,0 +1,233 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileges
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+const (
+	// The authentication string of caching_sha2_password is the same as MySQL:
+	// $A$<rounds/1000 in 3 hex digits>$<20 bytes salt><43 bytes sha256crypt digest>
+	sha2PasswordPrefix        = "$A$"
+	sha2PasswordSaltLen       = 20
+	sha2PasswordDigestLen     = 43
+	sha2PasswordRoundsPerUnit = 1000
+	sha2PasswordDefaultRounds = 5000
+	sha2PasswordLen           = len(sha2PasswordPrefix) + 3 + 1 + sha2PasswordSaltLen + sha2PasswordDigestLen
+
+	cryptAlphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// EncodeCachingSha2Password encodes the password into the authentication string of caching_sha2_password.
+// An empty password is encoded into an empty string.
+func EncodeCachingSha2Password(pwd string) string {
+	if len(pwd) == 0 {
+		return ""
+	}
+	salt := make([]byte, sha2PasswordSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		panic(err)
+	}
+	for i := range salt {
+		salt[i] = cryptAlphabet[int(salt[i])%len(cryptAlphabet)]
+	}
+	return encodeCachingSha2Password([]byte(pwd), salt, sha2PasswordDefaultRounds)
+}
+
+func encodeCachingSha2Password(pwd, salt []byte, rounds int) string {
+	return fmt.Sprintf("%s%03X$%s%s", sha2PasswordPrefix, rounds/sha2PasswordRoundsPerUnit, salt, sha256Crypt(pwd, salt, rounds))
+}
+
+// IsValidCachingSha2Password checks whether the string is a valid authentication string of caching_sha2_password.
+func IsValidCachingSha2Password(authString string) bool {
+	_, _, _, ok := decodeCachingSha2Password(authString)
+	return ok || len(authString) == 0
+}
+
+func decodeCachingSha2Password(authString string) (rounds int, salt, digest []byte, ok bool) {
+	if len(authString) != sha2PasswordLen || authString[:len(sha2PasswordPrefix)] != sha2PasswordPrefix {
+		return 0, nil, nil, false
+	}
+	s := authString[len(sha2PasswordPrefix):]
+	if s[3] != '$' {
+		return 0, nil, nil, false
+	}
+	units, err := strconv.ParseUint(s[:3], 16, 32)
+	if err != nil || units == 0 {
+		return 0, nil, nil, false
+	}
+	s = s[4:]
+	return int(units) * sha2PasswordRoundsPerUnit, []byte(s[:sha2PasswordSaltLen]), []byte(s[sha2PasswordSaltLen:]), true
+}
+
+// checkCachingSha2Password checks the cleartext password against the authentication string of caching_sha2_password.
+func checkCachingSha2Password(pwd []byte, authString string) bool {
+	rounds, salt, digest, ok := decodeCachingSha2Password(authString)
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare(sha256Crypt(pwd, salt, rounds), digest) == 1
+}
+
+// sha256Crypt implements the SHA-256 variant of the SHA-crypt by Ulrich Drepper, it returns the encoded digest.
+func sha256Crypt(pwd, salt []byte, rounds int) []byte {
+	h := sha256.New()
+	h.Write(pwd)
+	h.Write(salt)
+	h.Write(pwd)
+	b := h.Sum(nil)
+
+	h.Reset()
+	h.Write(pwd)
+	h.Write(salt)
+	cnt := len(pwd)
+	for ; cnt > sha256.Size; cnt -= sha256.Size {
+		h.Write(b)
+	}
+	h.Write(b[:cnt])
+	for cnt = len(pwd); cnt > 0; cnt >>= 1 {
+		if cnt&1 != 0 {
+			h.Write(b)
+		} else {
+			h.Write(pwd)
+		}
+	}
+	a := h.Sum(nil)
+
+	h.Reset()
+	for i := 0; i < len(pwd); i++ {
+		h.Write(pwd)
+	}
+	p := repeatDigest(h.Sum(nil), len(pwd))
+
+	h.Reset()
+	for i := 0; i < 16+int(a[0]); i++ {
+		h.Write(salt)
+	}
+	s := repeatDigest(h.Sum(nil), len(salt))
+
+	for i := 0; i < rounds; i++ {
+		h.Reset()
+		if i&1 != 0 {
+			h.Write(p)
+		} else {
+			h.Write(a)
+		}
+		if i%3 != 0 {
+			h.Write(s)
+		}
+		if i%7 != 0 {
+			h.Write(p)
+		}
+		if i&1 != 0 {
+			h.Write(a)
+		} else {
+			h.Write(p)
+		}
+		a = h.Sum(a[:0])
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(sha2PasswordDigestLen)
+	for i := 0; i < 10; i++ {
+		// The bytes are permuted as (0, 10, 20), (21, 1, 11), (12, 22, 2), ...
+		b2, b1, b0 := a[(i*21)%30], a[(i*21+10)%30], a[(i*21+20)%30]
+		writeCryptBase64(&buf, uint(b2)<<16|uint(b1)<<8|uint(b0), 4)
+	}
+	writeCryptBase64(&buf, uint(a[31])<<8|uint(a[30]), 3)
+	return buf.Bytes()
+}
+
+func repeatDigest(digest []byte, n int) []byte {
+	result := make([]byte, 0, n)
+	for ; n > len(digest); n -= len(digest) {
+		result = append(result, digest...)
+	}
+	return append(result, digest[:n]...)
+}
+
+func writeCryptBase64(buf *bytes.Buffer, w uint, n int) {
+	for ; n > 0; n-- {
+		buf.WriteByte(cryptAlphabet[w&0x3f])
+		w >>= 6
+	}
+}
+
+// sha2PasswordCache caches SHA256(SHA256(password)) of the users authenticated by caching_sha2_password,
+// so the later connections can be authenticated by the scramble without sending the password.
+type sha2PasswordCache struct {
+	sync.RWMutex
+	entries map[string]sha2PasswordCacheEntry
+}
+
+type sha2PasswordCacheEntry struct {
+	// authString is used to invalidate the entry after the password is changed.
+	authString string
+	digest     []byte
+}
+
+var globalSha2PasswordCache = &sha2PasswordCache{entries: make(map[string]sha2PasswordCacheEntry)}
+
+func sha2PasswordCacheKey(user, host string) string {
+	return user + "@" + host
+}
+
+func (c *sha2PasswordCache) add(user, host, authString string, pwd []byte) {
+	digest := sha256.Sum256(pwd)
+	digest = sha256.Sum256(digest[:])
+	c.Lock()
+	c.entries[sha2PasswordCacheKey(user, host)] = sha2PasswordCacheEntry{authString: authString, digest: digest[:]}
+	c.Unlock()
+}
+
+// check checks the scramble sent by the fast authentication, which is
+// SHA256(password) XOR SHA256(SHA256(SHA256(password)), nonce).
+func (c *sha2PasswordCache) check(user, host, authString string, scramble, nonce []byte) bool {
+	c.RLock()
+	entry, ok := c.entries[sha2PasswordCacheKey(user, host)]
+	c.RUnlock()
+	if !ok || entry.authString != authString || len(scramble) != sha256.Size {
+		return false
+	}
+	h := sha256.New()
+	h.Write(entry.digest)
+	h.Write(nonce)
+	stage1 := h.Sum(nil)
+	for i := range stage1 {
+		stage1[i] ^= scramble[i]
+	}
+	stage2 := sha256.Sum256(stage1)
+	return subtle.ConstantTimeCompare(stage2[:], entry.digest) == 1
+}
+
+// checkCachingSha2Authentication checks the authentication data of a caching_sha2_password user. The authentication
+// is the scramble of the fast authentication if the salt is not empty, otherwise it's the cleartext password sent by
+// the full authentication, and the user is cached for the fast authentication after it succeeds.
+func checkCachingSha2Authentication(record *UserRecord, authentication, salt []byte) bool {
+	if len(salt) > 0 {
+		return globalSha2PasswordCache.check(record.User, record.Host, record.AuthenticationString, authentication, salt)
+	}
+	if !checkCachingSha2Password(authentication, record.AuthenticationString) {
+		return false
+	}
+	globalSha2PasswordCache.add(record.User, record.Host, record.AuthenticationString, authentication)
+	return true
+}
@@ -0,0 +1,79 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileges
+
+import (
+	"crypto/sha256"
+	"strings"
+
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testCachingSha2Suite{})
+
+type testCachingSha2Suite struct{}
+
+func (s *testCachingSha2Suite) TestSha256Crypt(c *C) {
+	// The results are the same as crypt(3) of glibc.
+	c.Assert(string(sha256Crypt([]byte("Hello world!"), []byte("saltstring"), 5000)), Equals, "5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5")
+	c.Assert(string(sha256Crypt([]byte("Hello world!"), []byte("saltstringsaltst"), 10000)), Equals, "3xv.VbSHBb41AL9AvLeujZkZRBAwqFMz2.opqey6IcA")
+	pwd := []byte("a very much longer text to encrypt.  This one even stretches over morethan one line.")
+	c.Assert(string(sha256Crypt(pwd, []byte("anotherlongsalts"), 1400)), Equals, "Rx.j8H.h8HjEDGomFU8bDkXm3XIUnzyxf12oP84Bnq1")
+}
+
+func (s *testCachingSha2Suite) TestEncodeCachingSha2Password(c *C) {
+	c.Assert(EncodeCachingSha2Password(""), Equals, "")
+	authString := EncodeCachingSha2Password("abc")
+	c.Assert(authString, HasLen, sha2PasswordLen)
+	c.Assert(strings.HasPrefix(authString, "$A$005$"), IsTrue)
+	c.Assert(IsValidCachingSha2Password(authString), IsTrue)
+	c.Assert(IsValidCachingSha2Password(""), IsTrue)
+	c.Assert(IsValidCachingSha2Password("*0D3CED9BEC10A777AEC23CCC353A8C08A633045E"), IsFalse)
+	c.Assert(IsValidCachingSha2Password("$A$000$"+authString[7:]), IsFalse)
+	c.Assert(checkCachingSha2Password([]byte("abc"), authString), IsTrue)
+	c.Assert(checkCachingSha2Password([]byte("abd"), authString), IsFalse)
+	// The salt is random.
+	c.Assert(EncodeCachingSha2Password("abc"), Not(Equals), authString)
+
+	salt := []byte(strings.Repeat("s", sha2PasswordSaltLen))
+	c.Assert(encodeCachingSha2Password([]byte("abc"), salt, 10000), Equals, "$A$00A$"+string(salt)+string(sha256Crypt([]byte("abc"), salt, 10000)))
+}
+
+func (s *testCachingSha2Suite) TestSha2PasswordCache(c *C) {
+	cache := &sha2PasswordCache{entries: make(map[string]sha2PasswordCacheEntry)}
+	nonce := []byte("01234567890123456789")
+	scramble := cachingSha2Scramble([]byte("abc"), nonce)
+	c.Assert(cache.check("u", "%", "auth1", scramble, nonce), IsFalse)
+	cache.add("u", "%", "auth1", []byte("abc"))
+	c.Assert(cache.check("u", "%", "auth1", scramble, nonce), IsTrue)
+	c.Assert(cache.check("u", "localhost", "auth1", scramble, nonce), IsFalse)
+	c.Assert(cache.check("u", "%", "auth1", cachingSha2Scramble([]byte("abd"), nonce), nonce), IsFalse)
+	c.Assert(cache.check("u", "%", "auth1", scramble, []byte("98765432109876543210")), IsFalse)
+	// The entry is invalidated after the password is changed.
+	c.Assert(cache.check("u", "%", "auth2", scramble, nonce), IsFalse)
+}
+
+// cachingSha2Scramble computes the scramble of the fast authentication like the clients.
+func cachingSha2Scramble(pwd, nonce []byte) []byte {
+	stage1 := sha256.Sum256(pwd)
+	stage2 := sha256.Sum256(stage1[:])
+	h := sha256.New()
+	h.Write(stage2[:])
+	h.Write(nonce)
+	scramble := h.Sum(nil)
+	for i := range scramble {
+		scramble[i] ^= stage1[i]
+	}
+	return scramble
+}
@@ -0,0 +1,514 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileges
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/auth"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/ldap"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+const (
+	// AuthLDAPSimple is the authentication plugin which verifies the cleartext passwords by the simple binds to the
+	// LDAP server.
+	AuthLDAPSimple = "authentication_ldap_simple"
+	// AuthLDAPSASL is the authentication plugin which relays the SASL messages between the client and the LDAP server.
+	AuthLDAPSASL = "authentication_ldap_sasl"
+	// AuthLDAPSASLClient is the client-side plugin of authentication_ldap_sasl.
+	AuthLDAPSASLClient = "authentication_ldap_sasl_client"
+
+	ldapTimeout     = 10 * time.Second
+	ldapsScheme     = "ldaps://"
+	maxLDAPSASLStep = 16
+	// ldapBindCacheSweepSize is the size of the bind cache from which the expired entries are swept on insertion.
+	ldapBindCacheSweepSize = 1024
+)
+
+// IsLDAPAuthPlugin returns whether the users of the plugin are authenticated by LDAP, the authentication strings of
+// the users are their LDAP DNs and group mappings instead of the password hashes.
+func IsLDAPAuthPlugin(plugin string) bool {
+	return plugin == AuthLDAPSimple || plugin == AuthLDAPSASL
+}
+
+// ldapSysVars is the names of the system variables configuring an LDAP plugin, the empty ones don't apply to the
+// plugin.
+type ldapSysVars struct {
+	serverHost        string
+	serverPort        string
+	tls               string
+	caPath            string
+	bindBaseDN        string
+	bindRootDN        string
+	bindRootPwd       string
+	userSearchAttr    string
+	groupSearchAttr   string
+	groupSearchFilter string
+	bindCacheTTL      string
+	authMethod        string
+}
+
+var ldapSimpleSysVars = &ldapSysVars{
+	serverHost:        variable.AuthenticationLDAPSimpleServerHost,
+	serverPort:        variable.AuthenticationLDAPSimpleServerPort,
+	tls:               variable.AuthenticationLDAPSimpleTLS,
+	caPath:            variable.AuthenticationLDAPSimpleCAPath,
+	bindBaseDN:        variable.AuthenticationLDAPSimpleBindBaseDN,
+	bindRootDN:        variable.AuthenticationLDAPSimpleBindRootDN,
+	bindRootPwd:       variable.AuthenticationLDAPSimpleBindRootPwd,
+	userSearchAttr:    variable.AuthenticationLDAPSimpleUserSearchAttr,
+	groupSearchAttr:   variable.AuthenticationLDAPSimpleGroupSearchAttr,
+	groupSearchFilter: variable.AuthenticationLDAPSimpleGroupSearchFilter,
+	bindCacheTTL:      variable.AuthenticationLDAPSimpleBindCacheTTL,
+}
+
+var ldapSASLSysVars = &ldapSysVars{
+	serverHost:        variable.AuthenticationLDAPSASLServerHost,
+	serverPort:        variable.AuthenticationLDAPSASLServerPort,
+	tls:               variable.AuthenticationLDAPSASLTLS,
+	caPath:            variable.AuthenticationLDAPSASLCAPath,
+	bindBaseDN:        variable.AuthenticationLDAPSASLBindBaseDN,
+	bindRootDN:        variable.AuthenticationLDAPSASLBindRootDN,
+	bindRootPwd:       variable.AuthenticationLDAPSASLBindRootPwd,
+	userSearchAttr:    variable.AuthenticationLDAPSASLUserSearchAttr,
+	groupSearchAttr:   variable.AuthenticationLDAPSASLGroupSearchAttr,
+	groupSearchFilter: variable.AuthenticationLDAPSASLGroupSearchFilter,
+	authMethod:        variable.AuthenticationLDAPSASLAuthMethodName,
+}
+
+// ldapConfig is the configuration of an LDAP plugin loaded from its system variables.
+type ldapConfig struct {
+	serverHost        string
+	serverPort        string
+	useTLS            bool
+	caPath            string
+	bindBaseDN        string
+	bindRootDN        string
+	bindRootPwd       string
+	userSearchAttr    string
+	groupSearchAttr   string
+	groupSearchFilter string
+	bindCacheTTL      time.Duration
+	authMethod        string
+}
+
+func loadLDAPConfig(accessor variable.GlobalVarAccessor, vars *ldapSysVars) (*ldapConfig, error) {
+	cfg := &ldapConfig{}
+	var useTLS, bindCacheTTL string
+	for _, v := range []struct {
+		name  string
+		value *string
+	}{
+		{vars.serverHost, &cfg.serverHost},
+		{vars.serverPort, &cfg.serverPort},
+		{vars.tls, &useTLS},
+		{vars.caPath, &cfg.caPath},
+		{vars.bindBaseDN, &cfg.bindBaseDN},
+		{vars.bindRootDN, &cfg.bindRootDN},
+		{vars.bindRootPwd, &cfg.bindRootPwd},
+		{vars.userSearchAttr, &cfg.userSearchAttr},
+		{vars.groupSearchAttr, &cfg.groupSearchAttr},
+		{vars.groupSearchFilter, &cfg.groupSearchFilter},
+		{vars.bindCacheTTL, &bindCacheTTL},
+		{vars.authMethod, &cfg.authMethod},
+	} {
+		if v.name == "" {
+			continue
+		}
+		val, err := accessor.GetGlobalSysVar(v.name)
+		if err != nil {
+			return nil, err
+		}
+		*v.value = val
+	}
+	if cfg.serverHost == "" {
+		return nil, errors.Errorf("%s isn't set", vars.serverHost)
+	}
+	cfg.useTLS = variable.TiDBOptOn(useTLS)
+	if bindCacheTTL != "" {
+		seconds, err := strconv.ParseInt(bindCacheTTL, 10, 64)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		cfg.bindCacheTTL = time.Duration(seconds) * time.Second
+	}
+	return cfg, nil
+}
+
+// dial connects to the LDAP server. The connection is secured by LDAPS if the server host starts with ldaps://, or by
+// StartTLS if the TLS of the plugin is ON.
+func (cfg *ldapConfig) dial() (*ldap.Conn, error) {
+	host := cfg.serverHost
+	ldaps := strings.HasPrefix(strings.ToLower(host), ldapsScheme)
+	if ldaps {
+		host = host[len(ldapsScheme):]
+	}
+	var tlsConfig *tls.Config
+	if ldaps || cfg.useTLS {
+		tlsConfig = &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+		if cfg.caPath != "" {
+			pem, err := ioutil.ReadFile(cfg.caPath)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			tlsConfig.RootCAs = x509.NewCertPool()
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, errors.Errorf("no certificate is found in %s", cfg.caPath)
+			}
+		}
+	}
+	addr := net.JoinHostPort(host, cfg.serverPort)
+	if ldaps {
+		return ldap.Dial(addr, tlsConfig, ldapTimeout)
+	}
+	conn, err := ldap.Dial(addr, nil, ldapTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		if err = conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// bindRoot binds the connection as the root DN to search the users and the groups, the bind is anonymous if the root
+// DN isn't set.
+func (cfg *ldapConfig) bindRoot(conn *ldap.Conn) error {
+	return conn.Bind(cfg.bindRootDN, cfg.bindRootPwd)
+}
+
+// searchUserDN searches the DN of the user whose user search attribute is the user name.
+func (cfg *ldapConfig) searchUserDN(conn *ldap.Conn, user string) (string, error) {
+	entries, err := conn.Search(&ldap.SearchRequest{
+		BaseDN: cfg.bindBaseDN,
+		Scope:  ldap.ScopeWholeSubtree,
+		Filter: fmt.Sprintf("(%s=%s)", cfg.userSearchAttr, ldap.EscapeFilter(user)),
+		// 1.1 means no attributes, only the DNs are returned.
+		Attributes: []string{"1.1"},
+		SizeLimit:  2,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(entries) != 1 {
+		return "", errors.Errorf("%d LDAP entries are found for the user %s", len(entries), user)
+	}
+	return entries[0].DN, nil
+}
+
+// mapGroupRoles returns the roles mapped from the groups of the user, which are searched by the group search filter
+// as the root DN. {UA} and {UD} in the filter are replaced by the user name and the user DN.
+func (cfg *ldapConfig) mapGroupRoles(conn *ldap.Conn, authString *ldapAuthString, user, dn string) ([]*auth.RoleIdentity, error) {
+	if len(authString.groupRoles) == 0 {
+		return nil, nil
+	}
+	if err := cfg.bindRoot(conn); err != nil {
+		return nil, err
+	}
+	filter := strings.NewReplacer("{UA}", ldap.EscapeFilter(user), "{UD}", ldap.EscapeFilter(dn)).Replace(cfg.groupSearchFilter)
+	entries, err := conn.Search(&ldap.SearchRequest{
+		BaseDN:     cfg.bindBaseDN,
+		Scope:      ldap.ScopeWholeSubtree,
+		Filter:     filter,
+		Attributes: []string{cfg.groupSearchAttr},
+	})
+	if err != nil {
+		return nil, err
+	}
+	groups := make(map[string]struct{})
+	for _, entry := range entries {
+		for _, group := range entry.Values(cfg.groupSearchAttr) {
+			groups[strings.ToLower(group)] = struct{}{}
+		}
+	}
+	var roles []*auth.RoleIdentity
+	for _, groupRole := range authString.groupRoles {
+		if _, ok := groups[strings.ToLower(groupRole.group)]; ok {
+			roles = append(roles, groupRole.role)
+		}
+	}
+	return roles, nil
+}
+
+// fingerprint identifies the configuration the binds are verified by, so the cached binds are invalidated once the
+// configuration changes.
+func (cfg *ldapConfig) fingerprint() string {
+	return strings.Join([]string{cfg.serverHost, cfg.serverPort, strconv.FormatBool(cfg.useTLS), cfg.caPath, cfg.bindBaseDN,
+		cfg.bindRootDN, cfg.userSearchAttr, cfg.groupSearchAttr, cfg.groupSearchFilter}, "\x00")
+}
+
+// ldapAuthString is the parsed authentication string of the LDAP users, which is `[[+]DN][#group=role[,...]]`. The
+// DN of the user is searched by the user name if it's empty, and it's relative to the bind base DN if it starts with
+// '+'. The LDAP groups of the user are mapped to the roles activated for the session, a role is `name[@host]`.
+type ldapAuthString struct {
+	dn         string
+	groupRoles []ldapGroupRole
+}
+
+type ldapGroupRole struct {
+	group string
+	role  *auth.RoleIdentity
+}
+
+func parseLDAPAuthString(s, baseDN string) (*ldapAuthString, error) {
+	authString := &ldapAuthString{}
+	dn, mapping := s, ""
+	if i := strings.IndexByte(s, '#'); i >= 0 {
+		dn, mapping = s[:i], s[i+1:]
+	}
+	authString.dn = strings.TrimSpace(dn)
+	if strings.HasPrefix(authString.dn, "+") {
+		authString.dn = authString.dn[1:]
+		if baseDN != "" {
+			authString.dn += "," + baseDN
+		}
+	}
+	if strings.TrimSpace(mapping) == "" {
+		return authString, nil
+	}
+	for _, item := range strings.Split(mapping, ",") {
+		eq := strings.IndexByte(item, '=')
+		if eq < 0 {
+			return nil, errors.Errorf("invalid LDAP group mapping %q, it should be group=role", item)
+		}
+		group, role := strings.TrimSpace(item[:eq]), strings.TrimSpace(item[eq+1:])
+		host := "%"
+		if at := strings.LastIndexByte(role, '@'); at >= 0 {
+			role, host = role[:at], strings.Trim(role[at+1:], "'`\"")
+		}
+		role = strings.Trim(role, "'`\"")
+		if group == "" || role == "" {
+			return nil, errors.Errorf("invalid LDAP group mapping %q, it should be group=role", item)
+		}
+		authString.groupRoles = append(authString.groupRoles, ldapGroupRole{group: group, role: &auth.RoleIdentity{Username: role, Hostname: host}})
+	}
+	return authString, nil
+}
+
+// ValidateLDAPAuthString checks the authentication string of the LDAP users.
+func ValidateLDAPAuthString(s string) error {
+	_, err := parseLDAPAuthString(s, "")
+	return err
+}
+
+// ldapBindCache caches the successful simple binds, the logins with the same password in the TTL don't access the
+// LDAP server. The passwords are kept as the HMAC digests with a random key generated on startup.
+type ldapBindCache struct {
+	sync.Mutex
+	key     []byte
+	entries map[string]ldapBindCacheEntry
+}
+
+type ldapBindCacheEntry struct {
+	digest []byte
+	roles  []*auth.RoleIdentity
+	expire time.Time
+}
+
+var ldapSimpleBindCache = newLDAPBindCache()
+
+func newLDAPBindCache() *ldapBindCache {
+	key := make([]byte, sha256.Size)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	return &ldapBindCache{key: key, entries: make(map[string]ldapBindCacheEntry)}
+}
+
+func (c *ldapBindCache) digest(password []byte) []byte {
+	h := hmac.New(sha256.New, c.key)
+	h.Write(password)
+	return h.Sum(nil)
+}
+
+func (c *ldapBindCache) get(key string, password []byte, now time.Time) ([]*auth.RoleIdentity, bool) {
+	digest := c.digest(password)
+	c.Lock()
+	defer c.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !now.Before(entry.expire) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	if !hmac.Equal(entry.digest, digest) {
+		return nil, false
+	}
+	return entry.roles, true
+}
+
+func (c *ldapBindCache) put(key string, password []byte, roles []*auth.RoleIdentity, expire time.Time) {
+	digest := c.digest(password)
+	c.Lock()
+	defer c.Unlock()
+	if len(c.entries) >= ldapBindCacheSweepSize {
+		now := time.Now()
+		for k, entry := range c.entries {
+			if !now.Before(entry.expire) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	c.entries[key] = ldapBindCacheEntry{digest: digest, roles: roles, expire: expire}
+}
+
+// authLDAPSimple verifies the cleartext password of the user by the simple bind as the DN of the user, it returns
+// the roles mapped from the LDAP groups of the user.
+func authLDAPSimple(authCtx *privilege.AuthContext, user, host, authStr string, password []byte) ([]*auth.RoleIdentity, error) {
+	// The simple bind with an empty password is an unauthenticated bind, which always succeeds.
+	if len(password) == 0 {
+		return nil, errors.New("the password is empty")
+	}
+	cfg, err := loadLDAPConfig(authCtx.GlobalVars, ldapSimpleSysVars)
+	if err != nil {
+		return nil, err
+	}
+	authString, err := parseLDAPAuthString(authStr, cfg.bindBaseDN)
+	if err != nil {
+		return nil, err
+	}
+	cacheKey := strings.Join([]string{cfg.fingerprint(), user, host, authStr}, "\x00")
+	if cfg.bindCacheTTL > 0 {
+		if roles, ok := ldapSimpleBindCache.get(cacheKey, password, time.Now()); ok {
+			return roles, nil
+		}
+	}
+	conn, err := cfg.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	dn := authString.dn
+	if dn == "" {
+		if err = cfg.bindRoot(conn); err != nil {
+			return nil, err
+		}
+		if dn, err = cfg.searchUserDN(conn, user); err != nil {
+			return nil, err
+		}
+	}
+	if err = conn.Bind(dn, string(password)); err != nil {
+		return nil, err
+	}
+	roles, err := cfg.mapGroupRoles(conn, authString, user, dn)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.bindCacheTTL > 0 {
+		ldapSimpleBindCache.put(cacheKey, password, roles, time.Now().Add(cfg.bindCacheTTL))
+	}
+	return roles, nil
+}
+
+// authLDAPSASL relays the SASL messages between the client and the LDAP server until the SASL bind is done, the
+// first message is the response of the client to the auth switch request. It returns the roles mapped from the LDAP
+// groups of the user. The SASL binds aren't cached since TiDB doesn't know the passwords.
+func authLDAPSASL(authCtx *privilege.AuthContext, user, authStr string, initial []byte) ([]*auth.RoleIdentity, error) {
+	if authCtx.Conn == nil {
+		return nil, errors.New("the SASL messages can't be exchanged with the client")
+	}
+	cfg, err := loadLDAPConfig(authCtx.GlobalVars, ldapSASLSysVars)
+	if err != nil {
+		return nil, err
+	}
+	authString, err := parseLDAPAuthString(authStr, cfg.bindBaseDN)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := cfg.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	credentials := initial
+	for step := 0; ; step++ {
+		if step >= maxLDAPSASLStep {
+			return nil, errors.Errorf("the SASL bind isn't done in %d steps", maxLDAPSASLStep)
+		}
+		serverCredentials, done, err := conn.SASLBind(cfg.authMethod, credentials)
+		if err != nil {
+			return nil, err
+		}
+		// The final message of the server is sent as well, e.g. the server signature of SCRAM.
+		if len(serverCredentials) > 0 || !done {
+			if err = authCtx.Conn.WriteAuthMoreData(serverCredentials); err != nil {
+				return nil, err
+			}
+		}
+		if done {
+			break
+		}
+		if credentials, err = authCtx.Conn.ReadPacket(); err != nil {
+			return nil, err
+		}
+	}
+	if len(authString.groupRoles) == 0 {
+		return nil, nil
+	}
+	dn := authString.dn
+	if dn == "" {
+		if err = cfg.bindRoot(conn); err != nil {
+			return nil, err
+		}
+		if dn, err = cfg.searchUserDN(conn, user); err != nil {
+			return nil, err
+		}
+	}
+	return cfg.mapGroupRoles(conn, authString, user, dn)
+}
+
+// verifyLDAPAuthentication verifies the user of the LDAP plugins, the mapped roles are set to the auth context.
+func verifyLDAPAuthentication(record *UserRecord, user, host string, authentication []byte, authCtx *privilege.AuthContext) bool {
+	if authCtx == nil || authCtx.GlobalVars == nil {
+		logutil.BgLogger().Error("the LDAP authentication needs the auth context", zap.String("user", user), zap.String("host", host))
+		return false
+	}
+	var (
+		roles []*auth.RoleIdentity
+		err   error
+	)
+	if record.AuthPlugin == AuthLDAPSimple {
+		roles, err = authLDAPSimple(authCtx, user, host, record.AuthenticationString, authentication)
+	} else {
+		roles, err = authLDAPSASL(authCtx, user, record.AuthenticationString, authentication)
+	}
+	if err != nil {
+		logutil.BgLogger().Warn("the LDAP authentication failed", zap.String("user", user), zap.String("host", host),
+			zap.String("plugin", record.AuthPlugin), zap.Error(err))
+		return false
+	}
+	authCtx.Roles = roles
+	return true
+}
@@ -0,0 +1,189 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileges
+
+import (
+	"strconv"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/auth"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+)
+
+const (
+	// PasswordReuseDefault is the PASSWORD HISTORY or PASSWORD REUSE INTERVAL of the user accounts which follow the
+	// global password_history or password_reuse_interval.
+	PasswordReuseDefault = -1
+	// MaxPasswordReuse is the maximum PASSWORD HISTORY and PASSWORD REUSE INTERVAL of the user accounts.
+	MaxPasswordReuse = 65535
+
+	passwordHistoryTable = "password_history"
+)
+
+// PasswordReuse is the password reuse policy of a user account stored in mysql.user.
+type PasswordReuse struct {
+	User          string `json:"user"`
+	Host          string `json:"host"`
+	History       int64  `json:"password_history"`
+	ReuseInterval int64  `json:"password_reuse_interval"`
+}
+
+// SetPasswordReuse sets PASSWORD HISTORY and PASSWORD REUSE INTERVAL of the user account, PasswordReuseDefault makes
+// it follow the global system variable. The password history is pruned the next time the password is changed.
+func SetPasswordReuse(sctx sessionctx.Context, user, host string, history, interval int64) error {
+	if history < PasswordReuseDefault || history > MaxPasswordReuse {
+		return errors.Errorf("invalid PASSWORD HISTORY %d, it should be between 0 and %d or DEFAULT", history, MaxPasswordReuse)
+	}
+	if interval < PasswordReuseDefault || interval > MaxPasswordReuse {
+		return errors.Errorf("invalid PASSWORD REUSE INTERVAL %d, it should be between 0 and %d or DEFAULT", interval, MaxPasswordReuse)
+	}
+	rows, err := querySQL(sctx, "SELECT 1 FROM %n.%n WHERE User=%? AND Host=%?", mysql.SystemDB, mysql.UserTable, user, host)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return errors.Errorf("user '%s'@'%s' doesn't exist", user, host)
+	}
+	_, err = querySQL(sctx, "UPDATE %n.%n SET Password_reuse_history=%?, Password_reuse_time=%? WHERE User=%? AND Host=%?",
+		mysql.SystemDB, mysql.UserTable, nullIfDefault(history), nullIfDefault(interval), user, host)
+	return err
+}
+
+func nullIfDefault(n int64) interface{} {
+	if n == PasswordReuseDefault {
+		return nil
+	}
+	return n
+}
+
+// PasswordReuses returns the password reuse policies of the accounts which don't follow the global ones.
+func PasswordReuses(sctx sessionctx.Context) ([]PasswordReuse, error) {
+	rows, err := querySQL(sctx, `SELECT User, Host, Password_reuse_history, Password_reuse_time FROM %n.%n
+		WHERE Password_reuse_history IS NOT NULL OR Password_reuse_time IS NOT NULL ORDER BY User, Host`, mysql.SystemDB, mysql.UserTable)
+	if err != nil {
+		return nil, err
+	}
+	reuses := make([]PasswordReuse, 0, len(rows))
+	for _, row := range rows {
+		reuse := PasswordReuse{User: row.GetString(0), Host: row.GetString(1), History: PasswordReuseDefault, ReuseInterval: PasswordReuseDefault}
+		if !row.IsNull(2) {
+			reuse.History = int64(row.GetUint64(2))
+		}
+		if !row.IsNull(3) {
+			reuse.ReuseInterval = int64(row.GetUint64(3))
+		}
+		reuses = append(reuses, reuse)
+	}
+	return reuses, nil
+}
+
+// passwordReuse returns the effective PASSWORD HISTORY and PASSWORD REUSE INTERVAL of the user account, the passwords
+// of the LDAP users are managed by the LDAP server so they're never restricted.
+func passwordReuse(sctx sessionctx.Context, user, host string) (history, interval int64, err error) {
+	rows, err := querySQL(sctx, "SELECT Password_reuse_history, Password_reuse_time, plugin FROM %n.%n WHERE User=%? AND Host=%?",
+		mysql.SystemDB, mysql.UserTable, user, host)
+	if err != nil || len(rows) == 0 || IsLDAPAuthPlugin(rows[0].GetString(2)) {
+		return 0, 0, err
+	}
+	if rows[0].IsNull(0) {
+		history, err = globalUintVar(sctx, variable.PasswordHistory)
+	} else {
+		history = int64(rows[0].GetUint64(0))
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	if rows[0].IsNull(1) {
+		interval, err = globalUintVar(sctx, variable.PasswordReuseInterval)
+	} else {
+		interval = int64(rows[0].GetUint64(1))
+	}
+	return history, interval, err
+}
+
+func globalUintVar(sctx sessionctx.Context, name string) (int64, error) {
+	val, err := sctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(name)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseInt(val, 10, 64)
+	return n, errors.Trace(err)
+}
+
+// CheckPasswordReuse checks whether the cleartext password can be used as the new password of the user account, a
+// password can't be reused if it's one of the last PASSWORD HISTORY passwords or it's used within PASSWORD REUSE
+// INTERVAL days.
+func CheckPasswordReuse(sctx sessionctx.Context, user, host, pwd string) (bool, error) {
+	history, interval, err := passwordReuse(sctx, user, host)
+	if err != nil || (history == 0 && interval == 0) {
+		return true, err
+	}
+	rows, err := querySQL(sctx, `SELECT Password, Password_timestamp >= NOW(6) - INTERVAL %? DAY FROM %n.%n
+		WHERE User=%? AND Host=%? ORDER BY Password_timestamp DESC`, interval, mysql.SystemDB, passwordHistoryTable, user, host)
+	if err != nil {
+		return false, err
+	}
+	for i, row := range rows {
+		if int64(i) >= history && row.GetInt64(1) == 0 {
+			break
+		}
+		if passwordMatches(pwd, row.GetString(0)) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// passwordMatches checks the cleartext password against the authentication string of mysql_native_password or
+// caching_sha2_password.
+func passwordMatches(pwd, authString string) bool {
+	if _, _, _, ok := decodeCachingSha2Password(authString); ok {
+		return checkCachingSha2Password([]byte(pwd), authString)
+	}
+	return auth.EncodePassword(pwd) == authString
+}
+
+// RecordPasswordHistory records the new authentication string of the user account in mysql.password_history if the
+// password reuse policy is enabled, and removes the passwords which are out of the policy.
+func RecordPasswordHistory(sctx sessionctx.Context, user, host, authString string) error {
+	history, interval, err := passwordReuse(sctx, user, host)
+	if err != nil {
+		return err
+	}
+	if history == 0 && interval == 0 {
+		return DeletePasswordHistory(sctx, user, host)
+	}
+	_, err = querySQL(sctx, "INSERT INTO %n.%n (Host, User, Password) VALUES (%?, %?, %?)",
+		mysql.SystemDB, passwordHistoryTable, host, user, authString)
+	if err != nil {
+		return err
+	}
+	rows, err := querySQL(sctx, "SELECT Password_timestamp FROM %n.%n WHERE User=%? AND Host=%? ORDER BY Password_timestamp DESC LIMIT %?, 1",
+		mysql.SystemDB, passwordHistoryTable, user, host, history)
+	if err != nil || len(rows) == 0 {
+		return err
+	}
+	// The passwords older than the last PASSWORD HISTORY ones are kept only if they're used within the interval.
+	_, err = querySQL(sctx, "DELETE FROM %n.%n WHERE User=%? AND Host=%? AND Password_timestamp <= %? AND Password_timestamp < NOW(6) - INTERVAL %? DAY",
+		mysql.SystemDB, passwordHistoryTable, user, host, rows[0].GetTime(0).String(), interval)
+	return err
+}
+
+// DeletePasswordHistory deletes the password history of the user account.
+func DeletePasswordHistory(sctx sessionctx.Context, user, host string) error {
+	_, err := querySQL(sctx, "DELETE FROM %n.%n WHERE User=%? AND Host=%?", mysql.SystemDB, passwordHistoryTable, user, host)
+	return err
+}
@@ -0,0 +1,127 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privileges
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+const (
+	// MaxFailedLoginAttempts is the maximum FAILED_LOGIN_ATTEMPTS of the user accounts.
+	MaxFailedLoginAttempts = 32767
+	// MaxPasswordLockTime is the maximum PASSWORD_LOCK_TIME of the user accounts in days.
+	MaxPasswordLockTime = 32767
+)
+
+// AccountLocking is the account locking options and state of a user account stored in mysql.user.
+type AccountLocking struct {
+	User                string `json:"user"`
+	Host                string `json:"host"`
+	FailedLoginAttempts int64  `json:"failed_login_attempts"`
+	PasswordLockTime    int64  `json:"password_lock_time"`
+	FailedLoginCount    int64  `json:"failed_login_count"`
+	AutoLockedTime      string `json:"auto_locked_time,omitempty"`
+}
+
+// SetPasswordLocking sets FAILED_LOGIN_ATTEMPTS and PASSWORD_LOCK_TIME of the user account, the lock time is in days
+// or privilege.PasswordLockTimeUnbounded. The failed login count is reset, but the account locked by the failed logins
+// is kept locked. The callers should notify the privilege update.
+func SetPasswordLocking(sctx sessionctx.Context, user, host string, attempts, lockTime int64) error {
+	if attempts < 0 || attempts > MaxFailedLoginAttempts {
+		return errors.Errorf("invalid FAILED_LOGIN_ATTEMPTS %d, it should be between 0 and %d", attempts, MaxFailedLoginAttempts)
+	}
+	if lockTime < privilege.PasswordLockTimeUnbounded || lockTime > MaxPasswordLockTime {
+		return errors.Errorf("invalid PASSWORD_LOCK_TIME %d, it should be between 0 and %d or UNBOUNDED", lockTime, MaxPasswordLockTime)
+	}
+	rows, err := querySQL(sctx, "SELECT 1 FROM %n.%n WHERE User=%? AND Host=%?", mysql.SystemDB, mysql.UserTable, user, host)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return errors.Errorf("user '%s'@'%s' doesn't exist", user, host)
+	}
+	_, err = querySQL(sctx, "UPDATE %n.%n SET failed_login_attempts=%?, password_lock_time=%?, failed_login_count=0 WHERE User=%? AND Host=%?",
+		mysql.SystemDB, mysql.UserTable, attempts, lockTime, user, host)
+	return err
+}
+
+// AccountLockings returns the account locking of the accounts whose failed logins are tracked or which are locked by
+// the failed logins.
+func AccountLockings(sctx sessionctx.Context) ([]AccountLocking, error) {
+	rows, err := querySQL(sctx, `SELECT User, Host, failed_login_attempts, password_lock_time, failed_login_count, auto_locked_time
+		FROM %n.%n WHERE failed_login_attempts > 0 OR auto_locked_time IS NOT NULL ORDER BY User, Host`, mysql.SystemDB, mysql.UserTable)
+	if err != nil {
+		return nil, err
+	}
+	lockings := make([]AccountLocking, 0, len(rows))
+	for _, row := range rows {
+		locking := AccountLocking{
+			User:                row.GetString(0),
+			Host:                row.GetString(1),
+			FailedLoginAttempts: int64(row.GetUint64(2)),
+			PasswordLockTime:    row.GetInt64(3),
+			FailedLoginCount:    int64(row.GetUint64(4)),
+		}
+		if !row.IsNull(5) {
+			locking.AutoLockedTime = row.GetTime(5).String()
+		}
+		lockings = append(lockings, locking)
+	}
+	return lockings, nil
+}
+
+// RecordFailedLogin increases the failed login count of the user account, and locks the account if the count reaches
+// the FAILED_LOGIN_ATTEMPTS. It returns whether the account is locked, the callers should notify the privilege update
+// if it is.
+func RecordFailedLogin(sctx sessionctx.Context, user, host string, attempts int64) (bool, error) {
+	_, err := querySQL(sctx, "UPDATE %n.%n SET failed_login_count=failed_login_count+1 WHERE User=%? AND Host=%? AND auto_locked_time IS NULL",
+		mysql.SystemDB, mysql.UserTable, user, host)
+	if err != nil {
+		return false, err
+	}
+	rows, err := querySQL(sctx, "SELECT failed_login_count FROM %n.%n WHERE User=%? AND Host=%? AND auto_locked_time IS NULL",
+		mysql.SystemDB, mysql.UserTable, user, host)
+	if err != nil || len(rows) == 0 || int64(rows[0].GetUint64(0)) < attempts {
+		return false, err
+	}
+	_, err = querySQL(sctx, "UPDATE %n.%n SET failed_login_count=0, auto_locked_time=NOW() WHERE User=%? AND Host=%?",
+		mysql.SystemDB, mysql.UserTable, user, host)
+	return err == nil, err
+}
+
+// ResetFailedLogins resets the failed login count of the user account, and unlocks the account if it's locked by the
+// failed logins. The callers should notify the privilege update.
+func ResetFailedLogins(sctx sessionctx.Context, user, host string) error {
+	_, err := querySQL(sctx, "UPDATE %n.%n SET failed_login_count=0, auto_locked_time=NULL WHERE User=%? AND Host=%? AND (failed_login_count > 0 OR auto_locked_time IS NOT NULL)",
+		mysql.SystemDB, mysql.UserTable, user, host)
+	return err
+}
+
+func querySQL(sctx sessionctx.Context, sql string, args ...interface{}) ([]chunk.Row, error) {
+	ctx := context.Background()
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(ctx, sql, args...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+	return rows, errors.Trace(err)
+}
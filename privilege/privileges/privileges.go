@@ -16,10 +16,9 @@ package privileges
 import (
 	"crypto/tls"
 	"crypto/x509"
-	"errors"
 	"fmt"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/pingcap/parser/auth"
 	"github.com/pingcap/parser/mysql"
@@ -38,14 +37,6 @@ import (
 var SkipWithGrant = false
 
 var _ privilege.Manager = (*UserPrivileges)(nil)
-var dynamicPrivs = []string{
-	"BACKUP_ADMIN",
-	"SYSTEM_VARIABLES_ADMIN",
-	"ROLE_ADMIN",
-	"CONNECTION_ADMIN",
-	"RESTRICTED_TABLES_ADMIN",
-}
-var dynamicPrivLock sync.Mutex
 
 // UserPrivileges implements privilege.Manager interface.
 // This is used to check privilege for the current user.
@@ -84,7 +75,7 @@ func (p *UserPrivileges) RequestVerification(activeRoles []*auth.RoleIdentity, d
 	tblLowerName := strings.ToLower(table)
 	// If SEM is enabled and the user does not have the RESTRICTED_TABLES_ADMIN privilege
 	// There are some hard rules which overwrite system tables and schemas as read-only at most.
-	if sem.IsEnabled() && !p.RequestDynamicVerification(activeRoles, "RESTRICTED_TABLES_ADMIN", false) {
+	if sem.IsEnabled() && !p.RequestDynamicVerification(activeRoles, privilege.RestrictedTablesAdmin, false) {
 		if sem.IsInvisibleTable(dbLowerName, tblLowerName) {
 			return false
 		}
@@ -152,6 +143,16 @@ func (p *UserPrivileges) GetEncodedPassword(user, host string) string {
 		return ""
 	}
 	pwd := record.AuthenticationString
+	if IsLDAPAuthPlugin(record.AuthPlugin) {
+		return pwd
+	}
+	if record.AuthPlugin == mysql.AuthCachingSha2Password {
+		if !IsValidCachingSha2Password(pwd) {
+			logutil.BgLogger().Error("user password from system DB not like caching_sha2_password", zap.String("user", user))
+			return ""
+		}
+		return pwd
+	}
 	if len(pwd) != 0 && len(pwd) != mysql.PWDHashLen+1 {
 		logutil.BgLogger().Error("user password from system DB not like sha1sum", zap.String("user", user))
 		return ""
@@ -159,6 +160,64 @@ func (p *UserPrivileges) GetEncodedPassword(user, host string) string {
 	return pwd
 }
 
+// GetAuthPlugin implements the Manager interface.
+func (p *UserPrivileges) GetAuthPlugin(user, host string) (string, bool) {
+	if SkipWithGrant {
+		return mysql.AuthNativePassword, true
+	}
+	mysqlPriv := p.Handle.Get()
+	record := mysqlPriv.connectionVerification(user, host)
+	if record == nil {
+		return "", false
+	}
+	if record.AuthPlugin == "" {
+		return mysql.AuthNativePassword, true
+	}
+	return record.AuthPlugin, true
+}
+
+// GetConnectionLimits implements the Manager interface.
+func (p *UserPrivileges) GetConnectionLimits(user, host string) (maxUserConnections, maxConnectionsPerHour uint64) {
+	if SkipWithGrant {
+		return 0, 0
+	}
+	mysqlPriv := p.Handle.Get()
+	record := mysqlPriv.connectionVerification(user, host)
+	if record == nil {
+		return 0, 0
+	}
+	return record.MaxUserConnections, record.MaxConnectionsPerHour
+}
+
+// GetPasswordLocking implements the Manager interface.
+func (p *UserPrivileges) GetPasswordLocking(user, host string) (privilege.PasswordLocking, bool) {
+	if SkipWithGrant {
+		return privilege.PasswordLocking{}, false
+	}
+	mysqlPriv := p.Handle.Get()
+	record := mysqlPriv.connectionVerification(user, host)
+	if record == nil {
+		return privilege.PasswordLocking{}, false
+	}
+	locking := record.PasswordLocking
+	locking.AccountLocked = record.AccountLocked
+	return locking, true
+}
+
+// GetPasswordExpiration implements the Manager interface.
+func (p *UserPrivileges) GetPasswordExpiration(user, host string) (privilege.PasswordExpiration, bool) {
+	if SkipWithGrant {
+		return privilege.PasswordExpiration{}, false
+	}
+	mysqlPriv := p.Handle.Get()
+	record := mysqlPriv.connectionVerification(user, host)
+	// The passwords of the LDAP users are managed by the LDAP server.
+	if record == nil || IsLDAPAuthPlugin(record.AuthPlugin) {
+		return privilege.PasswordExpiration{}, false
+	}
+	return record.PasswordExpiration, true
+}
+
 // GetAuthWithoutVerification implements the Manager interface.
 func (p *UserPrivileges) GetAuthWithoutVerification(user, host string) (u string, h string, success bool) {
 	if SkipWithGrant {
@@ -185,7 +244,7 @@ func (p *UserPrivileges) GetAuthWithoutVerification(user, host string) (u string
 }
 
 // ConnectionVerification implements the Manager interface.
-func (p *UserPrivileges) ConnectionVerification(user, host string, authentication, salt []byte, tlsState *tls.ConnectionState) (u string, h string, success bool) {
+func (p *UserPrivileges) ConnectionVerification(user, host string, authentication, salt []byte, tlsState *tls.ConnectionState, authCtx *privilege.AuthContext) (u string, h string, success bool) {
 	if SkipWithGrant {
 		p.user = user
 		p.host = host
@@ -222,13 +281,23 @@ func (p *UserPrivileges) ConnectionVerification(user, host string, authenticatio
 		success = false
 		return
 	}
+	if record.PasswordLocking.AutoLocked(time.Now()) {
+		logutil.BgLogger().Error("try to login an account locked by the failed logins",
+			zap.String("user", user), zap.String("host", host))
+		return
+	}
 
-	pwd := record.AuthenticationString
-	if len(pwd) != 0 && len(pwd) != mysql.PWDHashLen+1 {
-		logutil.BgLogger().Error("user password from system DB not like sha1sum", zap.String("user", user))
+	if IsLDAPAuthPlugin(record.AuthPlugin) {
+		if !verifyLDAPAuthentication(record, user, host, authentication, authCtx) {
+			return
+		}
+		p.user = user
+		p.host = h
+		success = true
 		return
 	}
 
+	pwd := record.AuthenticationString
 	// empty password
 	if len(pwd) == 0 && len(authentication) == 0 {
 		p.user = user
@@ -241,6 +310,21 @@ func (p *UserPrivileges) ConnectionVerification(user, host string, authenticatio
 		return
 	}
 
+	if record.AuthPlugin == mysql.AuthCachingSha2Password {
+		if !checkCachingSha2Authentication(record, authentication, salt) {
+			return
+		}
+		p.user = user
+		p.host = h
+		success = true
+		return
+	}
+
+	if len(pwd) != mysql.PWDHashLen+1 {
+		logutil.BgLogger().Error("user password from system DB not like sha1sum", zap.String("user", user))
+		return
+	}
+
 	hpwd, err := auth.DecodePassword(pwd)
 	if err != nil {
 		logutil.BgLogger().Error("decode password string failed", zap.Error(err))
@@ -257,14 +341,6 @@ func (p *UserPrivileges) ConnectionVerification(user, host string, authenticatio
 	return
 }
 
-type checkResult int
-
-const (
-	notCheck checkResult = iota
-	pass
-	fail
-)
-
 func (p *UserPrivileges) checkSSL(priv *globalPrivRecord, tlsState *tls.ConnectionState) bool {
 	if priv.Broken {
 		logutil.BgLogger().Info("ssl check failure, due to broken global_priv record",
@@ -310,58 +386,52 @@ func (p *UserPrivileges) checkSSL(priv *globalPrivRecord, tlsState *tls.Connecti
 				zap.String("require", priv.Priv.SSLCipher), zap.String("given", util.TLSCipher2String(tlsState.CipherSuite)))
 			return false
 		}
-		var (
-			hasCert      = false
-			matchIssuer  checkResult
-			matchSubject checkResult
-			matchSAN     checkResult
-		)
+		// A certificate is required only if ISSUER, SUBJECT or SAN is specified, CIPHER alone only restricts the
+		// cipher of the connection.
+		if len(priv.Priv.X509Issuer) == 0 && len(priv.Priv.X509Subject) == 0 && len(priv.Priv.SANs) == 0 {
+			return true
+		}
+		hasCert := false
 		for _, chain := range tlsState.VerifiedChains {
 			if len(chain) == 0 {
 				continue
 			}
-			cert := chain[0]
-			if len(priv.Priv.X509Issuer) > 0 {
-				given := util.X509NameOnline(cert.Issuer)
-				if priv.Priv.X509Issuer == given {
-					matchIssuer = pass
-				} else if matchIssuer == notCheck {
-					matchIssuer = fail
-					logutil.BgLogger().Info("ssl check failure for issuer", zap.String("user", priv.User), zap.String("host", priv.Host),
-						zap.String("require", priv.Priv.X509Issuer), zap.String("given", given))
-				}
-			}
-			if len(priv.Priv.X509Subject) > 0 {
-				given := util.X509NameOnline(cert.Subject)
-				if priv.Priv.X509Subject == given {
-					matchSubject = pass
-				} else if matchSubject == notCheck {
-					matchSubject = fail
-					logutil.BgLogger().Info("ssl check failure for subject", zap.String("user", priv.User), zap.String("host", priv.Host),
-						zap.String("require", priv.Priv.X509Subject), zap.String("given", given))
-				}
-			}
-			if len(priv.Priv.SANs) > 0 {
-				matchOne := checkCertSAN(priv, cert, priv.Priv.SANs)
-				if matchOne {
-					matchSAN = pass
-				} else if matchSAN == notCheck {
-					matchSAN = fail
-				}
-			}
 			hasCert = true
+			if checkCert(priv, chain[0]) {
+				return true
+			}
 		}
-		checkResult := hasCert && matchIssuer != fail && matchSubject != fail && matchSAN != fail
-		if !checkResult && !hasCert {
+		if !hasCert {
 			logutil.BgLogger().Info("ssl check failure, require issuer/subject/SAN but no verified cert",
 				zap.String("user", priv.User), zap.String("host", priv.Host))
 		}
-		return checkResult
+		return false
 	default:
 		panic(fmt.Sprintf("support ssl_type: %d", priv.Priv.SSLType))
 	}
 }
 
+// checkCert checks whether the certificate meets all the required ISSUER, SUBJECT and SAN.
+func checkCert(priv *globalPrivRecord, cert *x509.Certificate) bool {
+	if len(priv.Priv.X509Issuer) > 0 {
+		given := util.X509NameOnline(cert.Issuer)
+		if priv.Priv.X509Issuer != given {
+			logutil.BgLogger().Info("ssl check failure for issuer", zap.String("user", priv.User), zap.String("host", priv.Host),
+				zap.String("require", priv.Priv.X509Issuer), zap.String("given", given))
+			return false
+		}
+	}
+	if len(priv.Priv.X509Subject) > 0 {
+		given := util.X509NameOnline(cert.Subject)
+		if priv.Priv.X509Subject != given {
+			logutil.BgLogger().Info("ssl check failure for subject", zap.String("user", priv.User), zap.String("host", priv.Host),
+				zap.String("require", priv.Priv.X509Subject), zap.String("given", given))
+			return false
+		}
+	}
+	return len(priv.Priv.SANs) == 0 || checkCertSAN(priv, cert, priv.Priv.SANs)
+}
+
 func checkCertSAN(priv *globalPrivRecord, cert *x509.Certificate, sans map[util.SANType][]string) (r bool) {
 	r = true
 	for typ, requireOr := range sans {
@@ -380,6 +450,8 @@ func checkCertSAN(priv *globalPrivRecord, cert *x509.Certificate, sans map[util.
 			for _, ip := range cert.IPAddresses {
 				given = append(given, ip.String())
 			}
+		case util.EmailAddr:
+			given = cert.EmailAddresses
 		default:
 			unsupported = true
 		}
@@ -398,7 +470,7 @@ func checkCertSAN(priv *globalPrivRecord, cert *x509.Certificate, sans map[util.
 			}
 		}
 		if !givenMatchOne {
-			logutil.BgLogger().Info("ssl check failure for subject", zap.String("user", priv.User), zap.String("host", priv.Host),
+			logutil.BgLogger().Info("ssl check failure for SAN", zap.String("user", priv.User), zap.String("host", priv.Host),
 				zap.String("require", priv.Priv.SAN), zap.Strings("given", given), zap.String("type", string(typ)))
 			r = false
 			return
@@ -414,7 +486,7 @@ func (p *UserPrivileges) DBIsVisible(activeRoles []*auth.RoleIdentity, db string
 	}
 	// If SEM is enabled, respect hard rules about certain schemas being invisible
 	// Before checking if the user has permissions granted to them.
-	if sem.IsEnabled() && !p.RequestDynamicVerification(activeRoles, "RESTRICTED_TABLES_ADMIN", false) {
+	if sem.IsEnabled() && !p.RequestDynamicVerification(activeRoles, privilege.RestrictedTablesAdmin, false) {
 		if sem.IsInvisibleSchema(db) {
 			return false
 		}
@@ -513,23 +585,10 @@ func (p *UserPrivileges) GetAllRoles(user, host string) []*auth.RoleIdentity {
 
 // IsDynamicPrivilege returns true if the DYNAMIC privilege is built-in or has been registered by a plugin
 func (p *UserPrivileges) IsDynamicPrivilege(privNameInUpper string) bool {
-	for _, priv := range dynamicPrivs {
-		if privNameInUpper == priv {
-			return true
-		}
-	}
-	return false
+	return privilege.IsDynamicPrivilege(privNameInUpper)
 }
 
 // RegisterDynamicPrivilege is used by plugins to add new privileges to TiDB
 func RegisterDynamicPrivilege(privNameInUpper string) error {
-	dynamicPrivLock.Lock()
-	defer dynamicPrivLock.Unlock()
-	for _, priv := range dynamicPrivs {
-		if privNameInUpper == priv {
-			return errors.New("privilege is already registered")
-		}
-	}
-	dynamicPrivs = append(dynamicPrivs, privNameInUpper)
-	return nil
+	return privilege.RegisterDynamicPrivilege(privNameInUpper)
 }
@@ -16,20 +16,25 @@ package privileges_test
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"fmt"
+	"io"
+	"net"
 	"net/url"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/parser/auth"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
 	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/privilege"
@@ -38,6 +43,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/ldap"
 	"github.com/pingcap/tidb/util/sem"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testleak"
@@ -424,6 +430,203 @@ func (s *testPrivilegeSuite) TestShowColumnGrants(c *C) {
 	c.Assert(strings.Join(gs, " "), Equals, "GRANT USAGE ON *.* TO 'column'@'%' GRANT Select(a), Insert(c), Update(a, b) ON test.column_table TO 'column'@'%'")
 }
 
+func (s *testPrivilegeSuite) TestColumnPrivilege(c *C) {
+	rootSe := newSession(c, s.store, s.dbName)
+	mustExec(c, rootSe, `USE test`)
+	mustExec(c, rootSe, `CREATE USER 'coluser'@'localhost'`)
+	mustExec(c, rootSe, `CREATE TABLE col_priv (a int, b int, c int)`)
+	mustExec(c, rootSe, `CREATE TABLE col_priv2 (a int)`)
+	mustExec(c, rootSe, `INSERT INTO col_priv VALUES (1, 2, 3)`)
+	mustExec(c, rootSe, `GRANT SELECT(a, b), UPDATE(b), INSERT(a, b) ON test.col_priv TO 'coluser'@'localhost'`)
+	mustExec(c, rootSe, `CREATE DEFINER = 'root'@'%' SQL SECURITY DEFINER VIEW col_priv_view AS SELECT a, c FROM col_priv`)
+	mustExec(c, rootSe, `GRANT SELECT(a) ON test.col_priv_view TO 'coluser'@'localhost'`)
+
+	se := newSession(c, s.store, s.dbName)
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "coluser", Hostname: "localhost"}, nil, nil), IsTrue)
+	mustExec(c, se, `USE test`)
+	mustExec(c, se, `SELECT a, b FROM col_priv WHERE a = 1 ORDER BY b`)
+	mustExec(c, se, `SELECT count(a) FROM col_priv t1 JOIN col_priv t2 USING (a)`)
+	mustExec(c, se, `SELECT a FROM (SELECT a, b FROM col_priv) t WHERE b > 0`)
+	mustExec(c, se, `UPDATE col_priv SET b = a + 1 WHERE a = 1`)
+	mustExec(c, se, `INSERT INTO col_priv (a, b) VALUES (4, 5)`)
+	mustExec(c, se, `INSERT INTO col_priv SET a = 6`)
+	mustExec(c, se, `SELECT a FROM col_priv_view`)
+
+	for _, sql := range []string{
+		`SELECT * FROM col_priv`,
+		`SELECT a FROM col_priv WHERE c = 3`,
+		`UPDATE col_priv SET b = c`,
+		`INSERT INTO col_priv (a, c) VALUES (1, 2)`,
+		`INSERT INTO col_priv VALUES (1, 2, 3)`,
+	} {
+		_, err := se.ExecuteInternal(context.Background(), sql)
+		c.Assert(terror.ErrorEqual(err, core.ErrColumnaccessDenied), IsTrue, Commentf("%s: %v", sql, err))
+	}
+	// The table privilege is required if no column is referenced, or no privilege on the referenced columns is
+	// granted.
+	for _, sql := range []string{
+		`SELECT count(*) FROM col_priv`,
+		`SELECT c FROM col_priv`,
+		`SELECT a FROM (SELECT c AS a FROM col_priv) t`,
+		`SELECT a FROM col_priv2`,
+		`SELECT c FROM col_priv_view`,
+		`INSERT INTO col_priv (a) VALUES (1) ON DUPLICATE KEY UPDATE a = 2`,
+		`DELETE FROM col_priv WHERE a = 1`,
+	} {
+		_, err := se.ExecuteInternal(context.Background(), sql)
+		c.Assert(terror.ErrorEqual(err, core.ErrTableaccessDenied), IsTrue, Commentf("%s: %v", sql, err))
+	}
+	_, err := se.ExecuteInternal(context.Background(), `UPDATE col_priv SET a = 1`)
+	c.Assert(terror.ErrorEqual(err, core.ErrPrivilegeCheckFail), IsTrue)
+
+	// The privileges on the columns are revoked.
+	mustExec(c, rootSe, `REVOKE SELECT(b) ON test.col_priv FROM 'coluser'@'localhost'`)
+	_, err = se.ExecuteInternal(context.Background(), `SELECT a, b FROM col_priv`)
+	c.Assert(terror.ErrorEqual(err, core.ErrColumnaccessDenied), IsTrue)
+	mustExec(c, se, `SELECT a FROM col_priv`)
+}
+
+func (s *testPrivilegeSuite) TestRowPolicy(c *C) {
+	rootSe := newSession(c, s.store, s.dbName)
+	mustExec(c, rootSe, `USE test`)
+	mustExec(c, rootSe, `CREATE USER 'rlsuser'@'localhost', 'rlsbypass'@'localhost'`)
+	mustExec(c, rootSe, `CREATE ROLE 'rlsrole'@'%'`)
+	mustExec(c, rootSe, `CREATE TABLE row_policy (id int PRIMARY KEY, tenant int, v int)`)
+	mustExec(c, rootSe, `INSERT INTO row_policy VALUES (1, 1, 10), (2, 2, 20), (3, 3, 30)`)
+	mustExec(c, rootSe, `GRANT SELECT, INSERT, UPDATE, DELETE ON test.row_policy TO 'rlsuser'@'localhost', 'rlsbypass'@'localhost'`)
+	mustExec(c, rootSe, "SET tidb_enable_dynamic_privileges=1")
+	mustExec(c, rootSe, `GRANT BYPASSRLS ON *.* TO 'rlsbypass'@'localhost'`)
+	mustExec(c, rootSe, `GRANT 'rlsrole'@'%' TO 'rlsuser'@'localhost'`)
+
+	se := newSession(c, s.store, s.dbName)
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "rlsuser", Hostname: "localhost"}, nil, nil), IsTrue)
+	tk := testkit.NewTestKitWithSession(c, s.store, se)
+	tk.MustExec(`USE test`)
+	// The cached point plan is invalidated after the policy is created.
+	tk.MustExec(`PREPARE stmt FROM 'SELECT v FROM row_policy WHERE id = ?'`)
+	tk.MustExec(`SET @id = 2`)
+	tk.MustQuery(`EXECUTE stmt USING @id`).Check(testkit.Rows("20"))
+
+	// The row policies are managed by the users with the SUPER or BYPASSRLS privilege.
+	_, err := se.ExecuteInternal(context.Background(), `CREATE ROW POLICY p1 ON row_policy TO 'rlsuser'@'localhost' USING (tenant = 1)`)
+	c.Assert(err, ErrorMatches, ".*SUPER or BYPASSRLS privilege.*")
+	mustExec(c, rootSe, `CREATE ROW POLICY p1 ON row_policy TO 'rlsuser'@'localhost' USING (tenant = 1)`)
+	defer func() {
+		mustExec(c, rootSe, `DROP ROW POLICY IF EXISTS p1 ON test.row_policy`)
+		mustExec(c, rootSe, `DROP ROW POLICY IF EXISTS p2 ON test.row_policy`)
+	}()
+	_, err = rootSe.ExecuteInternal(context.Background(), `CREATE ROW POLICY p1 ON row_policy TO 'rlsuser'@'localhost' USING (tenant = 1)`)
+	c.Assert(err, ErrorMatches, "row policy 'p1' on test.row_policy already exists")
+	tk.MustQuery(`EXECUTE stmt USING @id`).Check(testkit.Rows())
+	tk.MustQuery(`SELECT id FROM row_policy ORDER BY id`).Check(testkit.Rows("1"))
+	tk.MustQuery(`SELECT v FROM row_policy WHERE id = 2`).Check(testkit.Rows())
+	tk.MustQuery(`SELECT count(*) FROM row_policy t1 JOIN row_policy t2 ON t1.id < t2.id`).Check(testkit.Rows("0"))
+
+	// The rows invisible to the user can't be updated or deleted, and the written rows must satisfy the policy.
+	tk.MustExec(`UPDATE row_policy SET v = v + 1`)
+	tk.MustExec(`DELETE FROM row_policy WHERE id = 3`)
+	tk.MustExec(`INSERT INTO row_policy VALUES (4, 1, 40)`)
+	for _, sql := range []string{
+		`INSERT INTO row_policy VALUES (5, 2, 50)`,
+		`UPDATE row_policy SET tenant = 2 WHERE id = 1`,
+		`INSERT INTO row_policy VALUES (2, 1, 0) ON DUPLICATE KEY UPDATE v = 0`,
+		`REPLACE INTO row_policy VALUES (3, 1, 0)`,
+	} {
+		_, err := se.ExecuteInternal(context.Background(), sql)
+		c.Assert(terror.ErrorEqual(err, executor.ErrRowPolicyViolation), IsTrue, Commentf("%s: %v", sql, err))
+	}
+	tk.MustQuery(`SELECT id, tenant, v FROM row_policy ORDER BY id`).Check(testkit.Rows("1 1 11", "4 1 40"))
+
+	// The users with the BYPASSRLS privilege see all the rows, and can manage the policies.
+	bypassSe := newSession(c, s.store, s.dbName)
+	c.Assert(bypassSe.Auth(&auth.UserIdentity{Username: "rlsbypass", Hostname: "localhost"}, nil, nil), IsTrue)
+	mustExec(c, bypassSe, `CREATE ROW POLICY p2 ON test.row_policy TO 'rlsrole' USING (tenant = 3)`)
+
+	// The policies granted to all the roles of the user are applied, whether the roles are active or not, and a row is
+	// visible if it satisfies any of them.
+	tk.MustQuery(`SELECT id FROM row_policy ORDER BY id`).Check(testkit.Rows("1", "3", "4"))
+	tk.MustExec(`SET ROLE 'rlsrole'@'%'`)
+	tk.MustQuery(`SELECT id FROM row_policy ORDER BY id`).Check(testkit.Rows("1", "3", "4"))
+	tk.MustExec(`SET ROLE NONE`)
+	tk.MustQuery(`SELECT id FROM row_policy ORDER BY id`).Check(testkit.Rows("1", "3", "4"))
+	mustExec(c, rootSe, `CREATE OR REPLACE ROW POLICY p1 ON test.row_policy TO 'rlsrole' USING (tenant = 1)`)
+	tk.MustQuery(`SELECT id FROM row_policy ORDER BY id`).Check(testkit.Rows("1", "3", "4"))
+	tk.MustQuery(`SELECT policy_name, grantee, using_expr FROM information_schema.row_policies WHERE table_name = 'row_policy'`).Check(
+		testkit.Rows("p1 'rlsrole'@'%' `tenant`=1", "p2 'rlsrole'@'%' `tenant`=3"))
+	mustExec(c, bypassSe, `DROP ROW POLICY p2 ON test.row_policy`)
+	tk.MustQuery(`SELECT id FROM row_policy ORDER BY id`).Check(testkit.Rows("1", "4"))
+
+	tk = testkit.NewTestKitWithSession(c, s.store, bypassSe)
+	tk.MustQuery(`SELECT id FROM test.row_policy ORDER BY id`).Check(testkit.Rows("1", "2", "3", "4"))
+}
+
+func (s *testPrivilegeSuite) TestMaskingRule(c *C) {
+	rootSe := newSession(c, s.store, s.dbName)
+	mustExec(c, rootSe, `USE test`)
+	mustExec(c, rootSe, `CREATE USER 'maskuser'@'localhost', 'unmaskuser'@'localhost'`)
+	mustExec(c, rootSe, `CREATE TABLE masking (id int PRIMARY KEY, email varchar(64), card varchar(20), v int)`)
+	mustExec(c, rootSe, `INSERT INTO masking VALUES (1, 'alice@example.com', '4111111111111111', 10), (2, 'bob@example.com', '5500000000000004', 20)`)
+	mustExec(c, rootSe, `CREATE TABLE masking_copy (id int, email varchar(64))`)
+	mustExec(c, rootSe, `GRANT SELECT, INSERT, UPDATE, DELETE ON test.* TO 'maskuser'@'localhost', 'unmaskuser'@'localhost'`)
+	mustExec(c, rootSe, "SET tidb_enable_dynamic_privileges=1")
+	mustExec(c, rootSe, `GRANT UNMASK ON *.* TO 'unmaskuser'@'localhost'`)
+
+	se := newSession(c, s.store, s.dbName)
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "maskuser", Hostname: "localhost"}, nil, nil), IsTrue)
+	tk := testkit.NewTestKitWithSession(c, s.store, se)
+	tk.MustExec(`USE test`)
+	// The cached point plan is invalidated after the rule is created.
+	tk.MustExec(`PREPARE stmt FROM 'SELECT email FROM masking WHERE id = ?'`)
+	tk.MustExec(`SET @id = 1`)
+	tk.MustQuery(`EXECUTE stmt USING @id`).Check(testkit.Rows("alice@example.com"))
+
+	// The masking rules are managed by the users with the SUPER or UNMASK privilege.
+	_, err := se.ExecuteInternal(context.Background(), `CREATE MASKING RULE ON masking (email) AS (mask_email(email))`)
+	c.Assert(err, ErrorMatches, ".*SUPER or UNMASK privilege.*")
+	_, err = se.ExecuteInternal(context.Background(), `DROP MASKING RULE IF EXISTS ON masking (email)`)
+	c.Assert(err, ErrorMatches, ".*SUPER or UNMASK privilege.*")
+	unmaskSe := newSession(c, s.store, s.dbName)
+	c.Assert(unmaskSe.Auth(&auth.UserIdentity{Username: "unmaskuser", Hostname: "localhost"}, nil, nil), IsTrue)
+	mustExec(c, rootSe, `CREATE MASKING RULE ON masking (email) AS (mask_email(email))`)
+	mustExec(c, unmaskSe, `CREATE MASKING RULE ON test.masking (card) AS (mask_inner(card, 0, 4))`)
+	mustExec(c, unmaskSe, `CREATE OR REPLACE MASKING RULE ON test.masking (card) AS (mask_pan(card))`)
+	defer func() {
+		mustExec(c, rootSe, `DROP MASKING RULE IF EXISTS ON test.masking (email)`)
+		mustExec(c, rootSe, `DROP MASKING RULE IF EXISTS ON test.masking (card)`)
+	}()
+	_, err = rootSe.ExecuteInternal(context.Background(), `CREATE MASKING RULE ON masking (email) AS ('')`)
+	c.Assert(err, ErrorMatches, "masking rule of test.masking.email already exists")
+	tk.MustQuery(`EXECUTE stmt USING @id`).Check(testkit.Rows("aXXXX@example.com"))
+	tk.MustQuery(`SELECT * FROM masking ORDER BY id`).Check(testkit.Rows(
+		"1 aXXXX@example.com XXXXXXXXXXXX1111 10", "2 bXX@example.com XXXXXXXXXXXX0004 20"))
+	tk.MustQuery(`SELECT t.email FROM masking AS t WHERE id = 2`).Check(testkit.Rows("bXX@example.com"))
+	// The filters and the joins only see the masked values.
+	tk.MustQuery(`SELECT id FROM masking WHERE email = 'alice@example.com'`).Check(testkit.Rows())
+	tk.MustQuery(`SELECT id FROM masking WHERE card LIKE '%1111'`).Check(testkit.Rows("1"))
+	tk.MustQuery(`SELECT count(*) FROM masking t1 JOIN masking t2 ON t1.email = t2.email`).Check(testkit.Rows("2"))
+	tk.MustQuery(`SELECT id, email FROM masking WHERE id IN (SELECT id FROM masking WHERE v > 10)`).Check(testkit.Rows("2 bXX@example.com"))
+	tk.MustQuery(`SELECT id FROM masking WHERE id = 1 FOR UPDATE`).Check(testkit.Rows("1"))
+
+	// The copied rows are masked, and the tables with masking rules can't be updated or deleted.
+	tk.MustExec(`INSERT INTO masking_copy SELECT id, email FROM masking`)
+	tk.MustQuery(`SELECT email FROM masking_copy ORDER BY id`).Check(testkit.Rows("aXXXX@example.com", "bXX@example.com"))
+	for _, sql := range []string{
+		`UPDATE masking SET v = v + 1`,
+		`DELETE FROM masking WHERE email LIKE 'a%'`,
+		`UPDATE masking_copy SET email = (SELECT email FROM masking WHERE id = 1)`,
+	} {
+		_, err := se.ExecuteInternal(context.Background(), sql)
+		c.Assert(err, ErrorMatches, ".*you need \\(at least one of\\) the SUPER or UNMASK privilege\\(s\\).*", Commentf("%s", sql))
+	}
+	tk.MustQuery(`SELECT table_name, column_name, mask_expr FROM information_schema.masking_rules WHERE table_name = 'masking'`).Check(
+		testkit.Rows("masking card MASK_PAN(`card`)", "masking email MASK_EMAIL(`email`)"))
+
+	// The users with the UNMASK privilege read the original values.
+	tk = testkit.NewTestKitWithSession(c, s.store, unmaskSe)
+	tk.MustQuery(`SELECT email, card FROM test.masking WHERE id = 1`).Check(testkit.Rows("alice@example.com 4111111111111111"))
+	tk.MustExec(`UPDATE test.masking SET v = v + 1 WHERE id = 1`)
+}
+
 func (s *testPrivilegeSuite) TestDropTablePriv(c *C) {
 	se := newSession(c, s.store, s.dbName)
 	ctx, _ := se.(sessionctx.Context)
@@ -732,6 +935,53 @@ func (s *testPrivilegeSuite) TestCheckCertBasedAuth(c *C) {
 
 }
 
+func (s *testPrivilegeSuite) TestCheckCertBasedAuthOnSameCert(c *C) {
+	se := newSession(c, s.store, s.dbName)
+	mustExec(c, se, `CREATE USER 'c1_cipher_only'@'localhost' require cipher 'TLS_AES_128_GCM_SHA256'`)
+	mustExec(c, se, `CREATE USER 'c2_email_san'@'localhost' require san 'EMAIL:tester1@pingcap.com'`)
+	mustExec(c, se, `CREATE USER 'c3_issuer_subject'@'localhost' require issuer '/C=US/CN=TiDB admin' subject '/C=ZH/CN=tester1'`)
+	mustExec(c, se, "flush privileges")
+	defer func() {
+		c.Assert(se.Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil), IsTrue)
+		mustExec(c, se, "drop user 'c1_cipher_only'@'localhost'")
+		mustExec(c, se, "drop user 'c2_email_san'@'localhost'")
+		mustExec(c, se, "drop user 'c3_issuer_subject'@'localhost'")
+	}()
+
+	// REQUIRE CIPHER doesn't require a certificate.
+	se.GetSessionVars().TLSConnectionState = &tls.ConnectionState{CipherSuite: tls.TLS_AES_128_GCM_SHA256}
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "c1_cipher_only", Hostname: "localhost"}, nil, nil), IsTrue)
+	se.GetSessionVars().TLSConnectionState = &tls.ConnectionState{CipherSuite: tls.TLS_AES_256_GCM_SHA384}
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "c1_cipher_only", Hostname: "localhost"}, nil, nil), IsFalse)
+
+	issuer := pkix.Name{Names: []pkix.AttributeTypeAndValue{
+		util.MockPkixAttribute(util.Country, "US"),
+		util.MockPkixAttribute(util.CommonName, "TiDB admin"),
+	}}
+	subject := pkix.Name{Names: []pkix.AttributeTypeAndValue{
+		util.MockPkixAttribute(util.Country, "ZH"),
+		util.MockPkixAttribute(util.CommonName, "tester1"),
+	}}
+	se.GetSessionVars().TLSConnectionState = connectionState(issuer, subject, tls.TLS_AES_128_GCM_SHA256, func(cert *x509.Certificate) {
+		cert.EmailAddresses = []string{"tester1@pingcap.com"}
+	})
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "c2_email_san", Hostname: "localhost"}, nil, nil), IsTrue)
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "c3_issuer_subject", Hostname: "localhost"}, nil, nil), IsTrue)
+	se.GetSessionVars().TLSConnectionState = connectionState(issuer, subject, tls.TLS_AES_128_GCM_SHA256, func(cert *x509.Certificate) {
+		cert.EmailAddresses = []string{"tester2@pingcap.com"}
+	})
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "c2_email_san", Hostname: "localhost"}, nil, nil), IsFalse)
+
+	// The issuer and the subject should be met by the same certificate.
+	se.GetSessionVars().TLSConnectionState = &tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{
+			{{Issuer: issuer, Subject: issuer}},
+			{{Issuer: subject, Subject: subject}},
+		},
+	}
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "c3_issuer_subject", Hostname: "localhost"}, nil, nil), IsFalse)
+}
+
 func connectionState(issuer, subject pkix.Name, cipher uint16, opt ...func(c *x509.Certificate)) *tls.ConnectionState {
 	cert := &x509.Certificate{Issuer: issuer, Subject: subject}
 	for _, o := range opt {
@@ -783,6 +1033,322 @@ func (s *testPrivilegeSuite) TestCheckAuthenticate(c *C) {
 	mustExec(c, se1, "drop user 'r3@example.com'@'localhost'")
 }
 
+func (s *testPrivilegeSuite) TestCachingSha2PasswordAuthenticate(c *C) {
+	rootSe := newSession(c, s.store, s.dbName)
+	se := newSession(c, s.store, s.dbName)
+	mustExec(c, rootSe, `CREATE USER 'sha2u1'@'localhost' IDENTIFIED WITH 'caching_sha2_password' BY 'abc', sha2u2@localhost IDENTIFIED WITH caching_sha2_password`)
+	mustExec(c, rootSe, `CREATE USER sha2u3@localhost IDENTIFIED BY 'abc'`)
+	pc := privilege.GetPrivilegeManager(se)
+	plugin, ok := pc.GetAuthPlugin("sha2u1", "localhost")
+	c.Assert(ok, IsTrue)
+	c.Assert(plugin, Equals, mysql.AuthCachingSha2Password)
+	plugin, _ = pc.GetAuthPlugin("sha2u3", "localhost")
+	c.Assert(plugin, Equals, mysql.AuthNativePassword)
+	_, ok = pc.GetAuthPlugin("sha2u4", "localhost")
+	c.Assert(ok, IsFalse)
+	c.Assert(strings.HasPrefix(pc.GetEncodedPassword("sha2u1", "localhost"), "$A$005$"), IsTrue)
+	c.Assert(pc.GetEncodedPassword("sha2u2", "localhost"), Equals, "")
+	c.Assert(se.AuthPluginForUser(&auth.UserIdentity{Username: "sha2u1", Hostname: "localhost"}), Equals, mysql.AuthCachingSha2Password)
+
+	nonce := []byte("01234567890123456789")
+	user := &auth.UserIdentity{Username: "sha2u1", Hostname: "localhost"}
+	// The fast authentication fails before the full authentication succeeds.
+	c.Assert(se.Auth(user, cachingSha2Scramble([]byte("abc"), nonce), nonce), IsFalse)
+	c.Assert(se.Auth(user, []byte("abd"), nil), IsFalse)
+	c.Assert(se.Auth(user, nil, nil), IsFalse)
+	c.Assert(se.Auth(user, []byte("abc"), nil), IsTrue)
+	c.Assert(se.Auth(user, cachingSha2Scramble([]byte("abc"), nonce), nonce), IsTrue)
+	c.Assert(se.Auth(user, cachingSha2Scramble([]byte("abd"), nonce), nonce), IsFalse)
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "sha2u2", Hostname: "localhost"}, nil, nil), IsTrue)
+
+	// The cached password is invalidated after it's changed.
+	mustExec(c, rootSe, `ALTER USER sha2u1@localhost IDENTIFIED BY 'def'`)
+	c.Assert(se.Auth(user, cachingSha2Scramble([]byte("abc"), nonce), nonce), IsFalse)
+	c.Assert(se.Auth(user, []byte("def"), nil), IsTrue)
+	mustExec(c, rootSe, `SET PASSWORD FOR sha2u1@localhost = 'ghi'`)
+	c.Assert(se.Auth(user, []byte("ghi"), nil), IsTrue)
+
+	// Switch the plugins by ALTER USER.
+	mustExec(c, rootSe, `ALTER USER sha2u1@localhost IDENTIFIED WITH mysql_native_password BY 'abc', sha2u3@localhost IDENTIFIED WITH caching_sha2_password BY 'abc'`)
+	salt := []byte{85, 92, 45, 22, 58, 79, 107, 6, 122, 125, 58, 80, 12, 90, 103, 32, 90, 10, 74, 82}
+	authentication := []byte{24, 180, 183, 225, 166, 6, 81, 102, 70, 248, 199, 143, 91, 204, 169, 9, 161, 171, 203, 33}
+	c.Assert(se.Auth(user, authentication, salt), IsTrue)
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "sha2u3", Hostname: "localhost"}, []byte("abc"), nil), IsTrue)
+	mustExec(c, rootSe, `ALTER USER sha2u3@localhost IDENTIFIED WITH caching_sha2_password AS '`+pc.GetEncodedPassword("sha2u3", "localhost")+`'`)
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "sha2u3", Hostname: "localhost"}, []byte("abc"), nil), IsTrue)
+
+	_, err := rootSe.ExecuteInternal(context.Background(), `ALTER USER sha2u3@localhost IDENTIFIED WITH caching_sha2_password AS '*0D3CED9BEC10A777AEC23CCC353A8C08A633045E'`)
+	c.Assert(err, NotNil)
+	_, err = rootSe.ExecuteInternal(context.Background(), `CREATE USER sha2u4@localhost IDENTIFIED WITH sha256_password BY 'abc'`)
+	c.Assert(terror.ErrorEqual(err, executor.ErrPluginIsNotLoaded), IsTrue, Commentf("%v", err))
+
+	// The default plugin is used if it's not specified.
+	mustExec(c, rootSe, `SET GLOBAL default_authentication_plugin = 'caching_sha2_password'`)
+	mustExec(c, rootSe, `CREATE USER sha2u4@localhost IDENTIFIED BY 'abc' /* IDENTIFIED WITH mysql_native_password */`)
+	mustExec(c, rootSe, `SET GLOBAL default_authentication_plugin = DEFAULT`)
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "sha2u4", Hostname: "localhost"}, []byte("abc"), nil), IsTrue)
+	tk := testkit.NewTestKitWithSession(c, s.store, rootSe)
+	tk.MustQuery(`SELECT user, plugin FROM mysql.user WHERE user LIKE 'sha2u%' ORDER BY user`).Check(testkit.Rows(
+		"sha2u1 mysql_native_password", "sha2u2 caching_sha2_password", "sha2u3 caching_sha2_password", "sha2u4 caching_sha2_password"))
+	c.Assert(tk.MustQuery(`SHOW CREATE USER sha2u4@localhost`).Rows()[0][0], Matches, `CREATE USER 'sha2u4'@'localhost' IDENTIFIED WITH 'caching_sha2_password' AS '\$A\$005\$.*`)
+	mustExec(c, rootSe, `DROP USER sha2u1@localhost, sha2u2@localhost, sha2u3@localhost, sha2u4@localhost`)
+}
+
+func (s *testPrivilegeSuite) TestAccountLocking(c *C) {
+	rootSe := newSession(c, s.store, s.dbName)
+	se := newSession(c, s.store, s.dbName)
+	tk := testkit.NewTestKitWithSession(c, s.store, rootSe)
+	mustExec(c, rootSe, `CREATE USER locku1@localhost IDENTIFIED WITH caching_sha2_password BY 'abc'`)
+	mustExec(c, rootSe, `CREATE USER locku2@localhost IDENTIFIED WITH caching_sha2_password BY 'abc' ACCOUNT LOCK`)
+	user := &auth.UserIdentity{Username: "locku1", Hostname: "localhost"}
+	pc := privilege.GetPrivilegeManager(se)
+
+	// ACCOUNT LOCK and ACCOUNT UNLOCK keep the password.
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "locku2", Hostname: "localhost"}, []byte("abc"), nil), IsFalse)
+	mustExec(c, rootSe, `ALTER USER locku1@localhost ACCOUNT LOCK`)
+	c.Assert(tk.MustQuery(`SHOW CREATE USER locku1@localhost`).Rows()[0][0], Matches, `.* ACCOUNT LOCK`)
+	c.Assert(se.Auth(user, []byte("abc"), nil), IsFalse)
+	mustExec(c, rootSe, `ALTER USER locku1@localhost ACCOUNT UNLOCK`)
+	c.Assert(tk.MustQuery(`SHOW CREATE USER locku1@localhost`).Rows()[0][0], Matches, `.* ACCOUNT UNLOCK`)
+	c.Assert(se.Auth(user, []byte("abc"), nil), IsTrue)
+
+	// The failed logins aren't tracked by default.
+	for i := 0; i < 5; i++ {
+		c.Assert(se.Auth(user, []byte("abd"), nil), IsFalse)
+	}
+	c.Assert(se.Auth(user, []byte("abc"), nil), IsTrue)
+
+	c.Assert(privileges.SetPasswordLocking(rootSe, "locku1", "localhost", 3, 1), IsNil)
+	mustExec(c, rootSe, `FLUSH PRIVILEGES`)
+	// A successful login resets the failed login count.
+	c.Assert(se.Auth(user, []byte("abd"), nil), IsFalse)
+	c.Assert(se.Auth(user, []byte("abd"), nil), IsFalse)
+	c.Assert(se.Auth(user, []byte("abc"), nil), IsTrue)
+	for i := 0; i < 3; i++ {
+		c.Assert(se.Auth(user, []byte("abd"), nil), IsFalse)
+	}
+	c.Assert(se.Auth(user, []byte("abc"), nil), IsFalse)
+	locking, ok := pc.GetPasswordLocking("locku1", "localhost")
+	c.Assert(ok, IsTrue)
+	c.Assert(locking.AccountLocked, IsFalse)
+	c.Assert(locking.AutoLocked(time.Now()), IsTrue)
+	c.Assert(locking.AutoLocked(time.Now().Add(25*time.Hour)), IsFalse)
+	lockings, err := privileges.AccountLockings(rootSe)
+	c.Assert(err, IsNil)
+	c.Assert(lockings, HasLen, 1)
+	c.Assert(lockings[0].FailedLoginAttempts, Equals, int64(3))
+	c.Assert(lockings[0].PasswordLockTime, Equals, int64(1))
+	c.Assert(lockings[0].AutoLockedTime, Not(Equals), "")
+
+	// The account is unlocked by the next login after the lock time expires.
+	mustExec(c, rootSe, `UPDATE mysql.user SET auto_locked_time = NOW() - INTERVAL 2 DAY WHERE User = 'locku1'`)
+	mustExec(c, rootSe, `FLUSH PRIVILEGES`)
+	c.Assert(se.Auth(user, []byte("abc"), nil), IsTrue)
+	locking, _ = pc.GetPasswordLocking("locku1", "localhost")
+	c.Assert(locking.AutoLockedTime.IsZero(), IsTrue)
+
+	// ACCOUNT UNLOCK unlocks the account locked by the failed logins.
+	c.Assert(privileges.SetPasswordLocking(rootSe, "locku1", "localhost", 1, privilege.PasswordLockTimeUnbounded), IsNil)
+	mustExec(c, rootSe, `FLUSH PRIVILEGES`)
+	c.Assert(se.Auth(user, []byte("abd"), nil), IsFalse)
+	locking, _ = pc.GetPasswordLocking("locku1", "localhost")
+	c.Assert(locking.AutoLocked(time.Now().AddDate(100, 0, 0)), IsTrue)
+	c.Assert(se.Auth(user, []byte("abc"), nil), IsFalse)
+	mustExec(c, rootSe, `ALTER USER locku1@localhost ACCOUNT UNLOCK`)
+	c.Assert(se.Auth(user, []byte("abc"), nil), IsTrue)
+
+	c.Assert(privileges.SetPasswordLocking(rootSe, "locku1", "localhost", privileges.MaxFailedLoginAttempts+1, 1), NotNil)
+	c.Assert(privileges.SetPasswordLocking(rootSe, "locku1", "localhost", 3, -2), NotNil)
+	c.Assert(privileges.SetPasswordLocking(rootSe, "locku3", "localhost", 3, 1), NotNil)
+	mustExec(c, rootSe, `DROP USER locku1@localhost, locku2@localhost`)
+}
+
+func (s *testPrivilegeSuite) TestPasswordExpiration(c *C) {
+	rootSe := newSession(c, s.store, s.dbName)
+	se := newSession(c, s.store, s.dbName)
+	rootTk := testkit.NewTestKitWithSession(c, s.store, rootSe)
+	tk := testkit.NewTestKitWithSession(c, s.store, se)
+	mustExec(c, rootSe, `CREATE USER expireu1@localhost IDENTIFIED WITH caching_sha2_password BY 'abc' PASSWORD EXPIRE INTERVAL 5 DAY`)
+	user := &auth.UserIdentity{Username: "expireu1", Hostname: "localhost"}
+	pc := privilege.GetPrivilegeManager(se)
+	c.Assert(rootTk.MustQuery(`SHOW CREATE USER expireu1@localhost`).Rows()[0][0], Matches, `.* PASSWORD EXPIRE INTERVAL 5 DAY .*`)
+	expiration, ok := pc.GetPasswordExpiration("expireu1", "localhost")
+	c.Assert(ok, IsTrue)
+	c.Assert(expiration.Lifetime, Equals, int64(5))
+	c.Assert(expiration.IsExpired(time.Now(), 0), IsFalse)
+	c.Assert(expiration.IsExpired(time.Now().AddDate(0, 0, 6), 0), IsTrue)
+	c.Assert(se.Auth(user, []byte("abc"), nil), IsTrue)
+	tk.MustQuery(`SELECT 1`).Check(testkit.Rows("1"))
+
+	// The sessions of the expired accounts can only change the password.
+	mustExec(c, rootSe, `ALTER USER expireu1@localhost PASSWORD EXPIRE`)
+	c.Assert(se.Auth(user, []byte("abc"), nil), IsTrue)
+	_, err := tk.Exec(`SELECT 1`)
+	c.Assert(terror.ErrorEqual(err, session.ErrMustChangePassword), IsTrue, Commentf("err %v", err))
+	tk.MustExec(`SET PASSWORD = 'abcd'`)
+	tk.MustQuery(`SELECT 1`).Check(testkit.Rows("1"))
+	c.Assert(se.Auth(user, []byte("abcd"), nil), IsTrue)
+	tk.MustQuery(`SELECT 1`).Check(testkit.Rows("1"))
+
+	// The passwords expire after the lifetime of the account or default_password_lifetime.
+	mustExec(c, rootSe, `ALTER USER expireu1@localhost PASSWORD EXPIRE DEFAULT`)
+	c.Assert(rootTk.MustQuery(`SHOW CREATE USER expireu1@localhost`).Rows()[0][0], Matches, `.* PASSWORD EXPIRE DEFAULT .*`)
+	mustExec(c, rootSe, `UPDATE mysql.user SET password_last_changed = NOW() - INTERVAL 10 DAY WHERE User = 'expireu1'`)
+	mustExec(c, rootSe, `FLUSH PRIVILEGES`)
+	c.Assert(se.Auth(user, []byte("abcd"), nil), IsTrue)
+	tk.MustQuery(`SELECT 1`).Check(testkit.Rows("1"))
+	rootTk.MustExec(`SET GLOBAL default_password_lifetime = 7`)
+	defer rootTk.MustExec(`SET GLOBAL default_password_lifetime = DEFAULT`)
+	c.Assert(se.Auth(user, []byte("abcd"), nil), IsTrue)
+	_, err = tk.Exec(`SELECT 1`)
+	c.Assert(terror.ErrorEqual(err, session.ErrMustChangePassword), IsTrue, Commentf("err %v", err))
+	mustExec(c, rootSe, `ALTER USER expireu1@localhost PASSWORD EXPIRE NEVER`)
+	c.Assert(rootTk.MustQuery(`SHOW CREATE USER expireu1@localhost`).Rows()[0][0], Matches, `.* PASSWORD EXPIRE NEVER .*`)
+	c.Assert(se.Auth(user, []byte("abcd"), nil), IsTrue)
+	tk.MustQuery(`SELECT 1`).Check(testkit.Rows("1"))
+	mustExec(c, rootSe, `DROP USER expireu1@localhost`)
+}
+
+func (s *testPrivilegeSuite) TestPasswordReuse(c *C) {
+	rootSe := newSession(c, s.store, s.dbName)
+	tk := testkit.NewTestKitWithSession(c, s.store, rootSe)
+	mustExec(c, rootSe, `CREATE USER reuseu1@localhost IDENTIFIED BY 'abc'`)
+	tk.MustExec(`SET GLOBAL password_history = 1`)
+	defer tk.MustExec(`SET GLOBAL password_history = DEFAULT`)
+
+	// The policy of the account overrides the global one.
+	c.Assert(privileges.SetPasswordReuse(rootSe, "reuseu1", "localhost", 0, privileges.PasswordReuseDefault), IsNil)
+	tk.MustExec(`SET PASSWORD FOR reuseu1@localhost = 'abcd'`)
+	tk.MustExec(`SET PASSWORD FOR reuseu1@localhost = 'abcd'`)
+	tk.MustQuery(`SELECT COUNT(*) FROM mysql.password_history WHERE User = 'reuseu1'`).Check(testkit.Rows("0"))
+	c.Assert(privileges.SetPasswordReuse(rootSe, "reuseu1", "localhost", 3, privileges.PasswordReuseDefault), IsNil)
+	reuses, err := privileges.PasswordReuses(rootSe)
+	c.Assert(err, IsNil)
+	c.Assert(reuses, DeepEquals, []privileges.PasswordReuse{{User: "reuseu1", Host: "localhost", History: 3, ReuseInterval: privileges.PasswordReuseDefault}})
+	tk.MustExec(`SET PASSWORD FOR reuseu1@localhost = 'abc1'`)
+	tk.MustExec(`SET PASSWORD FOR reuseu1@localhost = 'abc2'`)
+	ok, err := privileges.CheckPasswordReuse(rootSe, "reuseu1", "localhost", "abc1")
+	c.Assert(err, IsNil)
+	c.Assert(ok, IsFalse)
+	ok, err = privileges.CheckPasswordReuse(rootSe, "reuseu1", "localhost", "abcd")
+	c.Assert(err, IsNil)
+	c.Assert(ok, IsTrue)
+
+	c.Assert(privileges.SetPasswordReuse(rootSe, "reuseu1", "localhost", privileges.PasswordReuseDefault, privileges.PasswordReuseDefault), IsNil)
+	reuses, err = privileges.PasswordReuses(rootSe)
+	c.Assert(err, IsNil)
+	c.Assert(reuses, HasLen, 0)
+	c.Assert(privileges.SetPasswordReuse(rootSe, "reuseu1", "localhost", privileges.MaxPasswordReuse+1, 0), NotNil)
+	c.Assert(privileges.SetPasswordReuse(rootSe, "reuseu1", "localhost", 0, -2), NotNil)
+	c.Assert(privileges.SetPasswordReuse(rootSe, "reuseu2", "localhost", 0, 0), NotNil)
+	mustExec(c, rootSe, `DROP USER reuseu1@localhost`)
+}
+
+type mockAuthConn struct {
+	written [][]byte
+	packets [][]byte
+}
+
+func (c *mockAuthConn) WriteAuthMoreData(data []byte) error {
+	c.written = append(c.written, data)
+	return nil
+}
+
+func (c *mockAuthConn) ReadPacket() ([]byte, error) {
+	if len(c.packets) == 0 {
+		return nil, io.EOF
+	}
+	data := c.packets[0]
+	c.packets = c.packets[1:]
+	return data, nil
+}
+
+func (s *testPrivilegeSuite) TestLDAPAuthentication(c *C) {
+	server, err := ldap.NewMockServer([]*ldap.Entry{
+		{DN: "uid=alice,ou=People,dc=example,dc=com", Attributes: []*ldap.Attribute{
+			{Name: "uid", Values: []string{"alice"}},
+			{Name: "userPassword", Values: []string{"secret"}},
+		}},
+		{DN: "uid=bob,ou=People,dc=example,dc=com", Attributes: []*ldap.Attribute{
+			{Name: "uid", Values: []string{"bob"}},
+			{Name: "userPassword", Values: []string{"bobpwd"}},
+		}},
+		{DN: "cn=dev,ou=Groups,dc=example,dc=com", Attributes: []*ldap.Attribute{
+			{Name: "objectClass", Values: []string{"posixGroup"}},
+			{Name: "cn", Values: []string{"dev"}},
+			{Name: "memberUid", Values: []string{"alice", "bob"}},
+		}},
+	}, nil)
+	c.Assert(err, IsNil)
+	defer server.Close()
+	host, port, err := net.SplitHostPort(server.Addr())
+	c.Assert(err, IsNil)
+
+	rootSe := newSession(c, s.store, s.dbName)
+	se := newSession(c, s.store, s.dbName)
+	tk := testkit.NewTestKitWithSession(c, s.store, rootSe)
+	for _, prefix := range []string{"authentication_ldap_simple", "authentication_ldap_sasl"} {
+		tk.MustExec(fmt.Sprintf(`SET GLOBAL %s_server_host = '%s'`, prefix, host))
+		tk.MustExec(fmt.Sprintf(`SET GLOBAL %s_server_port = %s`, prefix, port))
+		tk.MustExec(fmt.Sprintf(`SET GLOBAL %s_bind_base_dn = 'dc=example,dc=com'`, prefix))
+		defer tk.MustExec(fmt.Sprintf(`SET GLOBAL %s_server_host = DEFAULT`, prefix))
+		defer tk.MustExec(fmt.Sprintf(`SET GLOBAL %s_server_port = DEFAULT`, prefix))
+		defer tk.MustExec(fmt.Sprintf(`SET GLOBAL %s_bind_base_dn = DEFAULT`, prefix))
+	}
+	mustExec(c, rootSe, `CREATE ROLE ldap_dev, ldap_ops`)
+	mustExec(c, rootSe, `CREATE USER alice@localhost IDENTIFIED WITH authentication_ldap_simple AS 'uid=alice,ou=People,dc=example,dc=com#dev=ldap_dev,ops=ldap_ops'`)
+	mustExec(c, rootSe, `CREATE USER bob@localhost IDENTIFIED WITH authentication_ldap_simple`)
+	alice := &auth.UserIdentity{Username: "alice", Hostname: "localhost"}
+	bob := &auth.UserIdentity{Username: "bob", Hostname: "localhost"}
+	pc := privilege.GetPrivilegeManager(se)
+	plugin, ok := pc.GetAuthPlugin("alice", "localhost")
+	c.Assert(ok, IsTrue)
+	c.Assert(plugin, Equals, privileges.AuthLDAPSimple)
+
+	// The roles are mapped from the LDAP groups of the user.
+	c.Assert(se.Auth(alice, []byte("secret"), nil), IsTrue)
+	c.Assert(se.GetSessionVars().ActiveRoles, DeepEquals, []*auth.RoleIdentity{{Username: "ldap_dev", Hostname: "%"}})
+	c.Assert(se.Auth(alice, []byte("wrong"), nil), IsFalse)
+	c.Assert(se.Auth(alice, nil, nil), IsFalse)
+	// The DN is searched by the user name if it's not specified.
+	c.Assert(se.Auth(bob, []byte("bobpwd"), nil), IsTrue)
+	c.Assert(se.GetSessionVars().ActiveRoles, HasLen, 0)
+	c.Assert(se.Auth(bob, []byte("secret"), nil), IsFalse)
+
+	// The binds are cached in the TTL.
+	binds := server.Binds()
+	c.Assert(se.Auth(alice, []byte("secret"), nil), IsTrue)
+	c.Assert(server.Binds(), Equals, binds+2)
+	tk.MustExec(`SET GLOBAL authentication_ldap_simple_bind_cache_ttl = 60`)
+	defer tk.MustExec(`SET GLOBAL authentication_ldap_simple_bind_cache_ttl = DEFAULT`)
+	c.Assert(se.Auth(alice, []byte("secret"), nil), IsTrue)
+	binds = server.Binds()
+	c.Assert(se.Auth(alice, []byte("secret"), nil), IsTrue)
+	c.Assert(se.GetSessionVars().ActiveRoles, DeepEquals, []*auth.RoleIdentity{{Username: "ldap_dev", Hostname: "%"}})
+	c.Assert(server.Binds(), Equals, binds)
+	c.Assert(se.Auth(alice, []byte("wrong"), nil), IsFalse)
+	c.Assert(server.Binds(), Equals, binds+1)
+
+	// The SASL messages are relayed between the client and the LDAP server.
+	mustExec(c, rootSe, `CREATE USER bob@127.0.0.1 IDENTIFIED WITH authentication_ldap_sasl AS '+uid=bob,ou=People#dev=ldap_ops'`)
+	mustExec(c, rootSe, fmt.Sprintf(`REPLACE INTO mysql.global_variables VALUES ('authentication_ldap_sasl_auth_method_name', '%s')`, ldap.MockSASLMechanism))
+	defer tk.MustExec(`SET GLOBAL authentication_ldap_sasl_auth_method_name = DEFAULT`)
+	bobSASL := &auth.UserIdentity{Username: "bob", Hostname: "127.0.0.1"}
+	conn := &mockAuthConn{packets: [][]byte{[]byte("bobpwd")}}
+	c.Assert(se.AuthWithConn(bobSASL, []byte("bob"), nil, conn), IsTrue)
+	c.Assert(conn.written, DeepEquals, [][]byte{[]byte("password?")})
+	c.Assert(se.GetSessionVars().ActiveRoles, DeepEquals, []*auth.RoleIdentity{{Username: "ldap_ops", Hostname: "%"}})
+	conn = &mockAuthConn{packets: [][]byte{[]byte("secret")}}
+	c.Assert(se.AuthWithConn(bobSASL, []byte("bob"), nil, conn), IsFalse)
+	// The SASL binds need the connection to the client.
+	c.Assert(se.Auth(bobSASL, []byte("bob"), nil), IsFalse)
+
+	// The LDAP users have no passwords to expire.
+	tk.MustExec(`ALTER USER alice@localhost PASSWORD EXPIRE`)
+	c.Assert(se.Auth(alice, []byte("secret"), nil), IsTrue)
+	c.Assert(se.GetSessionVars().InSandBoxMode, IsFalse)
+}
+
 func (s *testPrivilegeSuite) TestUseDB(c *C) {
 
 	se := newSession(c, s.store, s.dbName)
@@ -859,6 +1425,20 @@ func (s *testPrivilegeSuite) TestSetGlobal(c *C) {
 	c.Assert(terror.ErrorEqual(err, core.ErrSpecificAccessDenied), IsTrue)
 }
 
+func (s *testPrivilegeSuite) TestMVCCInfo(c *C) {
+	se := newSession(c, s.store, s.dbName)
+	mustExec(c, se, `CREATE USER mvccinfo_a@localhost`)
+	mustExec(c, se, `CREATE USER mvccinfo_b@localhost`)
+	mustExec(c, se, `GRANT SUPER ON *.* to mvccinfo_a@localhost`)
+
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "mvccinfo_a", Hostname: "localhost"}, nil, nil), IsTrue)
+	mustExec(c, se, `select tidb_mvcc_info('7480000000000000015f728000000000000001')`)
+
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "mvccinfo_b", Hostname: "localhost"}, nil, nil), IsTrue)
+	_, err := se.ExecuteInternal(context.Background(), `select tidb_mvcc_info('7480000000000000015f728000000000000001')`)
+	c.Assert(terror.ErrorEqual(err, core.ErrSpecificAccessDenied), IsTrue)
+}
+
 func (s *testPrivilegeSuite) TestCreateDropUser(c *C) {
 	se := newSession(c, s.store, s.dbName)
 	mustExec(c, se, `CREATE USER tcd1, tcd2`)
@@ -1044,6 +1624,10 @@ func (s *testPrivilegeSuite) TestAdminCommand(c *C) {
 	c.Assert(strings.Contains(err.Error(), "privilege check fail"), IsTrue)
 	_, err = se.ExecuteInternal(context.Background(), "ADMIN CHECK TABLE t")
 	c.Assert(strings.Contains(err.Error(), "privilege check fail"), IsTrue)
+	_, err = se.ExecuteInternal(context.Background(), "ADMIN PAUSE DDL JOBS 1")
+	c.Assert(strings.Contains(err.Error(), "privilege check fail"), IsTrue)
+	_, err = se.ExecuteInternal(context.Background(), "ADMIN RESUME DDL JOBS 1")
+	c.Assert(strings.Contains(err.Error(), "privilege check fail"), IsTrue)
 
 	c.Assert(se.Auth(&auth.UserIdentity{Username: "root", Hostname: "localhost"}, nil, nil), IsTrue)
 	_, err = se.ExecuteInternal(context.Background(), "ADMIN SHOW DDL JOBS")
@@ -1310,6 +1894,69 @@ func (s *testPrivilegeSuite) TestDynamicGrantOption(c *C) {
 	mustExec(c, se2, "GRANT SYSTEM_VARIABLES_ADMIN ON *.* TO varuser3")
 }
 
+func (s *testPrivilegeSuite) TestRegisterDynamicPrivilege(c *C) {
+	c.Assert(privileges.RegisterDynamicPrivilege("ACME_feature_ADMIN"), IsNil)
+	c.Assert(privilege.IsDynamicPrivilege("ACME_FEATURE_ADMIN"), IsTrue)
+	c.Assert(privileges.RegisterDynamicPrivilege("acme_feature_admin"), ErrorMatches, ".*already registered")
+	c.Assert(privilege.RegisterDynamicPrivilege("BACKUP_ADMIN"), ErrorMatches, ".*already registered")
+	c.Assert(privilege.RegisterDynamicPrivilege(""), NotNil)
+	c.Assert(privilege.RegisterDynamicPrivilege("ACME ADMIN"), NotNil)
+	c.Assert(privilege.RegisterDynamicPrivilege(strings.Repeat("A", 33)), NotNil)
+	c.Assert(strings.Join(privilege.DynamicPrivileges(), ","), Matches, "ACME_FEATURE_ADMIN,BACKUP_ADMIN,.*")
+
+	rootSe := newSession(c, s.store, s.dbName)
+	mustExec(c, rootSe, "CREATE USER acmeuser")
+	mustExec(c, rootSe, "SET tidb_enable_dynamic_privileges=1")
+	mustExec(c, rootSe, "GRANT acme_feature_admin ON *.* TO acmeuser")
+	_, err := rootSe.ExecuteInternal(context.Background(), "GRANT ACME_UNKNOWN_ADMIN ON *.* TO acmeuser")
+	c.Assert(err, NotNil)
+	se := newSession(c, s.store, s.dbName)
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "acmeuser", Hostname: "%"}, nil, nil), IsTrue)
+	pc := privilege.GetPrivilegeManager(se)
+	c.Assert(pc.RequestDynamicVerification(nil, "ACME_FEATURE_ADMIN", false), IsTrue)
+	c.Assert(pc.RequestDynamicVerification(nil, privilege.BackupAdmin, false), IsFalse)
+}
+
+func (s *testPrivilegeSuite) TestSuperDecomposition(c *C) {
+	rootSe := newSession(c, s.store, s.dbName)
+	mustExec(c, rootSe, "CREATE USER granularuser")
+	mustExec(c, rootSe, "GRANT CREATE VIEW, SELECT ON test.* TO granularuser")
+	mustExec(c, rootSe, "SET tidb_enable_dynamic_privileges=1")
+	mustExec(c, rootSe, "CREATE TABLE IF NOT EXISTS test.decomposition (a int)")
+	se := newSession(c, s.store, s.dbName)
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "granularuser", Hostname: "%"}, nil, nil), IsTrue)
+
+	// SET_USER_ID is required to create the views of the other definers.
+	mustExec(c, se, "CREATE VIEW test.decomposition_v1 AS SELECT a FROM test.decomposition")
+	_, err := se.ExecuteInternal(context.Background(), "CREATE DEFINER = 'root'@'%' VIEW test.decomposition_v2 AS SELECT a FROM test.decomposition")
+	c.Assert(err.Error(), Equals, "[planner:1227]Access denied; you need (at least one of) the SUPER or SET_USER_ID privilege(s) for this operation")
+	mustExec(c, rootSe, "GRANT SET_USER_ID ON *.* TO granularuser")
+	mustExec(c, se, "CREATE DEFINER = 'root'@'%' VIEW test.decomposition_v2 AS SELECT a FROM test.decomposition")
+
+	// RESOURCE_GROUP_ADMIN is required to switch the resource group of the session.
+	_, err = se.ExecuteInternal(context.Background(), "SET tidb_resource_group = 'rg1'")
+	c.Assert(err.Error(), Equals, "[planner:1227]Access denied; you need (at least one of) the SUPER or RESOURCE_GROUP_ADMIN privilege(s) for this operation")
+	mustExec(c, rootSe, "GRANT RESOURCE_GROUP_ADMIN ON *.* TO granularuser")
+	mustExec(c, se, "SET tidb_resource_group = 'rg1'")
+	c.Assert(se.GetSessionVars().ResourceGroupName, Equals, "rg1")
+
+	// PLACEMENT_ADMIN is required to manage the placement policies and to set them on the tables.
+	_, err = se.ExecuteInternal(context.Background(), "CREATE PLACEMENT POLICY decomposition_p FOLLOWERS=1")
+	c.Assert(err.Error(), Equals, "[planner:1227]Access denied; you need (at least one of) the SUPER or PLACEMENT_ADMIN privilege(s) for this operation")
+	mustExec(c, rootSe, "GRANT ALTER ON test.* TO granularuser")
+	_, err = se.ExecuteInternal(context.Background(), "ALTER TABLE test.decomposition PLACEMENT POLICY = decomposition_p")
+	c.Assert(err.Error(), Equals, "[planner:1227]Access denied; you need (at least one of) the SUPER or PLACEMENT_ADMIN privilege(s) for this operation")
+	mustExec(c, rootSe, "GRANT PLACEMENT_ADMIN ON *.* TO granularuser")
+	mustExec(c, se, "CREATE PLACEMENT POLICY decomposition_p FOLLOWERS=1")
+	mustExec(c, se, "ALTER TABLE test.decomposition PLACEMENT POLICY = decomposition_p")
+	mustExec(c, se, "ALTER TABLE test.decomposition PLACEMENT POLICY = DEFAULT")
+	mustExec(c, se, "DROP PLACEMENT POLICY decomposition_p")
+
+	// CONNECTION_ADMIN is required to reload the TLS certificates.
+	_, err = se.ExecuteInternal(context.Background(), "ALTER INSTANCE RELOAD TLS")
+	c.Assert(err.Error(), Equals, "[planner:1227]Access denied; you need (at least one of) the SUPER or CONNECTION_ADMIN privilege(s) for this operation")
+}
+
 func (s *testPrivilegeSuite) TestSecurityEnhancedModeRestrictedTables(c *C) {
 	// This provides an integration test of the tests in util/security/security_test.go
 	cloudAdminSe := newSession(c, s.store, s.dbName)
@@ -1341,3 +1988,17 @@ func (s *testPrivilegeSuite) TestSecurityEnhancedModeRestrictedTables(c *C) {
 	mustExec(c, cloudAdminSe, "CREATE TABLE mysql.abcd (a int)")
 
 }
+
+// cachingSha2Scramble computes the scramble of the fast authentication of caching_sha2_password like the clients.
+func cachingSha2Scramble(pwd, nonce []byte) []byte {
+	stage1 := sha256.Sum256(pwd)
+	stage2 := sha256.Sum256(stage1[:])
+	h := sha256.New()
+	h.Write(stage2[:])
+	h.Write(nonce)
+	scramble := h.Sum(nil)
+	for i := range scramble {
+		scramble[i] ^= stage1[i]
+	}
+	return scramble
+}
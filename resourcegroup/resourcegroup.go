@@ -0,0 +1,279 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcegroup
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/privilege"
+)
+
+const (
+	// GroupTableName is the name of the system table storing the resource groups.
+	GroupTableName = "resource_groups"
+	// UserTableName is the name of the system table storing the resource groups bound to the users.
+	UserTableName = "resource_group_users"
+	// LoadInterval is the interval of reloading the resource groups from the system tables.
+	LoadInterval = 10 * time.Second
+	// AdminPriv is the dynamic privilege to manage the resource groups and switch the resource group of the session.
+	AdminPriv = "RESOURCE_GROUP_ADMIN"
+	// DefaultGroupName is the reserved name to unbind the user accounts from their resource groups.
+	DefaultGroupName = "default"
+)
+
+// The request unit (RU) model. A read request costs ReadRequestRU plus 1 RU for every ReadBytesPerRU bytes read,
+// a write costs WriteRequestRU plus 1 RU for every WriteBytesPerRU bytes written.
+const (
+	ReadRequestRU   = 0.25
+	ReadBytesPerRU  = 64 * 1024
+	WriteRequestRU  = 1
+	WriteBytesPerRU = 1024
+)
+
+// ReadBytesRU returns the request units of reading the bytes, excluding the ReadRequestRU of the request. The read
+// requests consume ReadRequestRU before they are sent, and are charged for the bytes after the responses arrive.
+func ReadBytesRU(bytes int) float64 {
+	return float64(bytes) / ReadBytesPerRU
+}
+
+// WriteRU returns the request units of a write request which writes the bytes.
+func WriteRU(bytes int) float64 {
+	return WriteRequestRU + float64(bytes)/WriteBytesPerRU
+}
+
+// tokenBucket is a token bucket which is refilled at rate tokens per second and holds at most burst tokens. The tokens
+// can be taken into debt, then the following takers wait until the debt is paid off.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, now time.Time) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: rate, tokens: rate, last: now}
+}
+
+func (b *tokenBucket) refillLocked(now time.Time) {
+	if now.After(b.last) {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+}
+
+// take takes n tokens from the bucket and returns how long the taker should wait for the tokens.
+func (b *tokenBucket) take(now time.Time, n float64) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refillLocked(now)
+	b.tokens -= n
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) setRate(now time.Time, rate float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refillLocked(now)
+	b.rate, b.burst = rate, rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+// The priorities of the resource groups. The statements of the groups with higher priorities are scheduled first when
+// the worker pool of the server is busy.
+const (
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+)
+
+// Group is a resource group. The requests of the sessions bound to the group are throttled when the group consumes
+// more than RUPerSec request units per second. RUPerSec 0 means the group is unlimited.
+type Group struct {
+	Name     string `json:"name"`
+	RUPerSec int64  `json:"ru_per_sec"`
+	Priority string `json:"priority"`
+
+	bucket *tokenBucket
+}
+
+// groupSetting is the setting of a resource group stored in the system table.
+type groupSetting struct {
+	ruPerSec int64
+	priority string
+}
+
+// Manager manages the resource groups and throttles the requests of them.
+type Manager struct {
+	mu struct {
+		sync.RWMutex
+		groups map[string]*Group
+		// users maps the "user@host" to its resource group binding.
+		users map[string]UserBinding
+	}
+}
+
+// NewManager creates a Manager without any resource group.
+func NewManager() *Manager {
+	m := &Manager{}
+	m.mu.groups = make(map[string]*Group)
+	m.mu.users = make(map[string]UserBinding)
+	return m
+}
+
+var globalManager = NewManager()
+
+func init() {
+	privilege.MustRegisterDynamicPrivilege(AdminPriv)
+}
+
+// GlobalManager returns the Manager of the tidb-server.
+func GlobalManager() *Manager {
+	return globalManager
+}
+
+func userKey(user, host string) string {
+	return user + "@" + strings.ToLower(host)
+}
+
+// NormalizeName returns the normalized name of a resource group, the names of resource groups are case-insensitive.
+func NormalizeName(name string) string {
+	return strings.ToLower(name)
+}
+
+// update replaces the resource groups and the user bindings. The buckets of the existing groups are kept, so that
+// reloading the groups doesn't reset their consumption.
+func (m *Manager) update(groups map[string]groupSetting, users map[string]UserBinding) {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for name := range m.mu.groups {
+		if _, ok := groups[name]; !ok {
+			delete(m.mu.groups, name)
+			metrics.ResourceGroupQuotaGauge.DeleteLabelValues(name)
+		}
+	}
+	for name, setting := range groups {
+		ruPerSec := setting.ruPerSec
+		group, ok := m.mu.groups[name]
+		if ok && group.RUPerSec == ruPerSec && group.Priority == setting.priority {
+			continue
+		}
+		newGroup := &Group{Name: name, RUPerSec: ruPerSec, Priority: setting.priority}
+		if ruPerSec > 0 {
+			if ok && group.bucket != nil {
+				group.bucket.setRate(now, float64(ruPerSec))
+				newGroup.bucket = group.bucket
+			} else {
+				newGroup.bucket = newTokenBucket(float64(ruPerSec), now)
+			}
+		}
+		m.mu.groups[name] = newGroup
+		metrics.ResourceGroupQuotaGauge.WithLabelValues(name).Set(float64(ruPerSec))
+	}
+	m.mu.users = users
+}
+
+// GetGroup returns the resource group of the name, or nil if it doesn't exist.
+func (m *Manager) GetGroup(name string) *Group {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.mu.groups[NormalizeName(name)]
+}
+
+// Groups returns all the resource groups ordered by their names.
+func (m *Manager) Groups() []*Group {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	groups := make([]*Group, 0, len(m.mu.groups))
+	for _, group := range m.mu.groups {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups
+}
+
+// PriorityOfGroup returns the priority of the resource group, the sessions not in any resource group have the medium
+// priority.
+func (m *Manager) PriorityOfGroup(name string) string {
+	if name == "" {
+		return PriorityMedium
+	}
+	if group := m.GetGroup(name); group != nil {
+		return group.Priority
+	}
+	return PriorityMedium
+}
+
+// GroupOfUser returns the name of the resource group bound to the user account, or "" if there isn't one.
+func (m *Manager) GroupOfUser(user, host string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.mu.users[userKey(user, host)].ResourceGroup
+}
+
+// Consume consumes the request units of a request of the resource group. It blocks until the group has enough quota
+// for the request or the ctx is done. The tp is the type of the request, which is metrics.LblRead or metrics.LblWrite.
+func (m *Manager) Consume(ctx context.Context, name, tp string, ru float64) error {
+	wait := m.charge(name, tp, ru)
+	if wait <= 0 {
+		return nil
+	}
+	name = NormalizeName(name)
+	start := time.Now()
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	metrics.ResourceGroupThrottledDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+	return nil
+}
+
+// Charge charges the request units which have been consumed by the resource group without waiting, for example,
+// the bytes read by a finished request. The following requests of the group are throttled if the group runs out of
+// its quota.
+func (m *Manager) Charge(name, tp string, ru float64) {
+	m.charge(name, tp, ru)
+}
+
+func (m *Manager) charge(name, tp string, ru float64) time.Duration {
+	if name == "" {
+		return 0
+	}
+	group := m.GetGroup(name)
+	if group == nil {
+		return 0
+	}
+	metrics.ResourceGroupRUCounter.WithLabelValues(group.Name, tp).Add(ru)
+	if group.bucket == nil {
+		return 0
+	}
+	return group.bucket.take(time.Now(), ru)
+}
@@ -0,0 +1,232 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcegroup_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/auth"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/resourcegroup"
+	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testResourceGroupSuite{})
+
+type testResourceGroupSuite struct {
+	store kv.Storage
+	dom   *domain.Domain
+	se    session.Session
+}
+
+func (s *testResourceGroupSuite) SetUpSuite(c *C) {
+	store, err := mockstore.NewMockStore()
+	c.Assert(err, IsNil)
+	s.store = store
+	session.SetSchemaLease(0)
+	s.dom, err = session.BootstrapSession(s.store)
+	c.Assert(err, IsNil)
+	s.se, err = session.CreateSession4Test(s.store)
+	c.Assert(err, IsNil)
+}
+
+func (s *testResourceGroupSuite) TearDownSuite(c *C) {
+	s.se.Close()
+	s.dom.Close()
+	s.store.Close()
+}
+
+func (s *testResourceGroupSuite) TestConsume(c *C) {
+	manager := resourcegroup.GlobalManager()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("create resource group RG_Consume ru_per_sec = 10")
+	defer tk.MustExec("drop resource group rg_consume")
+	group := manager.GetGroup("rg_consume")
+	c.Assert(group, NotNil)
+	c.Assert(group.Name, Equals, "rg_consume")
+	c.Assert(group.RUPerSec, Equals, int64(10))
+
+	// The bucket is full at first, so the requests within the burst don't wait.
+	ctx := context.Background()
+	c.Assert(manager.Consume(ctx, "rg_consume", metrics.LblRead, 10), IsNil)
+	// The quota is used up, the next request waits about 0.5s.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	c.Assert(manager.Consume(timeoutCtx, "rg_consume", metrics.LblRead, 5), Equals, context.DeadlineExceeded)
+	cancel()
+
+	// Raising the quota refills the bucket faster.
+	tk.MustExec("alter resource group rg_consume ru_per_sec = 1000000")
+	c.Assert(manager.GetGroup("rg_consume").RUPerSec, Equals, int64(1000000))
+	timeoutCtx, cancel = context.WithTimeout(ctx, time.Second)
+	c.Assert(manager.Consume(timeoutCtx, "rg_consume", metrics.LblWrite, 5), IsNil)
+	cancel()
+
+	// The unlimited groups and the groups which don't exist never throttle the requests.
+	tk.MustExec("alter resource group rg_consume ru_per_sec = 0")
+	c.Assert(manager.Consume(ctx, "rg_consume", metrics.LblRead, 1e9), IsNil)
+	c.Assert(manager.Consume(ctx, "rg_not_exists", metrics.LblRead, 1e9), IsNil)
+
+	tk.MustGetErrMsg("create resource group rg_consume ru_per_sec = 10", "resource group 'rg_consume' already exists")
+	tk.MustExec("create resource group if not exists rg_consume ru_per_sec = 10")
+	c.Assert(manager.GetGroup("rg_consume").RUPerSec, Equals, int64(0))
+	tk.MustGetErrMsg("create resource group `default` ru_per_sec = 10", "invalid resource group name 'default'")
+	tk.MustGetErrMsg("alter resource group rg_not_exists ru_per_sec = 10", "resource group 'rg_not_exists' doesn't exist")
+	tk.MustGetErrMsg("drop resource group rg_not_exists", "resource group 'rg_not_exists' doesn't exist")
+	tk.MustExec("drop resource group if exists rg_not_exists")
+	c.Assert(manager.CreateGroup(s.se, "", 10, "", false), ErrorMatches, "invalid resource group name ''")
+	c.Assert(manager.CreateGroup(s.se, "rg_consume", -1, "", false), ErrorMatches, "invalid RU_PER_SEC -1")
+}
+
+func (s *testResourceGroupSuite) TestPriority(c *C) {
+	manager := resourcegroup.GlobalManager()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("create resource group rg_priority ru_per_sec = 0")
+	defer tk.MustExec("drop resource group rg_priority")
+	c.Assert(manager.GetGroup("rg_priority").Priority, Equals, resourcegroup.PriorityMedium)
+	tk.MustExec("alter resource group rg_priority ru_per_sec = 10, priority = HIGH")
+	c.Assert(manager.GetGroup("rg_priority").Priority, Equals, resourcegroup.PriorityHigh)
+	// The priority is kept if it's not specified.
+	tk.MustExec("alter resource group rg_priority ru_per_sec = 20")
+	group := manager.GetGroup("rg_priority")
+	c.Assert(group.RUPerSec, Equals, int64(20))
+	c.Assert(group.Priority, Equals, resourcegroup.PriorityHigh)
+	// The quota is kept if it's not specified.
+	tk.MustExec("alter resource group rg_priority priority = low")
+	group = manager.GetGroup("rg_priority")
+	c.Assert(group.RUPerSec, Equals, int64(20))
+	c.Assert(group.Priority, Equals, resourcegroup.PriorityLow)
+	c.Assert(manager.PriorityOfGroup("rg_priority"), Equals, resourcegroup.PriorityLow)
+	c.Assert(manager.PriorityOfGroup("rg_not_exists"), Equals, resourcegroup.PriorityMedium)
+	c.Assert(manager.PriorityOfGroup(""), Equals, resourcegroup.PriorityMedium)
+
+	tk.MustGetErrMsg("alter resource group rg_priority priority = urgent", "invalid PRIORITY 'urgent'")
+	tk.MustGetErrMsg("create resource group rg_urgent priority = urgent", "invalid PRIORITY 'urgent'")
+}
+
+func (s *testResourceGroupSuite) TestBindUser(c *C) {
+	manager := resourcegroup.GlobalManager()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("create user 'rg_user'@'%' identified by 'pwd'")
+	defer tk.MustExec("drop user 'rg_user'@'%'")
+
+	tk.MustGetErrMsg("alter user 'rg_user'@'%' resource group rg_bind", "resource group 'rg_bind' doesn't exist")
+	tk.MustExec("create resource group rg_bind ru_per_sec = 100")
+	authString := tk.MustQuery("select authentication_string from mysql.user where user = 'rg_user'").Rows()[0][0]
+	tk.MustExec("alter user 'rg_user'@'%' resource group RG_Bind")
+	// Binding the group keeps the password.
+	tk.MustQuery("select authentication_string from mysql.user where user = 'rg_user'").Check(testkit.Rows(authString.(string)))
+	c.Assert(manager.GroupOfUser("rg_user", "%"), Equals, "rg_bind")
+	c.Assert(manager.UserBindings("rg_bind"), DeepEquals, []resourcegroup.UserBinding{{User: "rg_user", Host: "%", ResourceGroup: "rg_bind"}})
+	tk.MustQuery("select user, host, resource_group from mysql.resource_group_users").Check(testkit.Rows("rg_user % rg_bind"))
+
+	// The new sessions of the user join the resource group.
+	tk.MustExec("alter user 'rg_user'@'%' identified by ''")
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	c.Assert(tk1.Se.Auth(&auth.UserIdentity{Username: "rg_user", Hostname: "localhost"}, nil, nil), IsTrue)
+	tk1.MustQuery("select @@tidb_resource_group").Check(testkit.Rows("rg_bind"))
+	c.Assert(tk1.Se.GetSessionVars().ResourceGroupName, Equals, "rg_bind")
+	// Switching the resource group requires RESOURCE_GROUP_ADMIN.
+	_, err := tk1.Exec("set @@tidb_resource_group = 'RG_Other'")
+	c.Assert(err, ErrorMatches, ".*SUPER or RESOURCE_GROUP_ADMIN.*")
+	tk.MustExec("set tidb_enable_dynamic_privileges = 1")
+	tk.MustExec("grant resource_group_admin on *.* to 'rg_user'@'%'")
+	tk1.MustExec("set @@tidb_resource_group = 'RG_Other'")
+	c.Assert(tk1.Se.GetSessionVars().ResourceGroupName, Equals, "rg_other")
+
+	tk.MustExec("alter user 'rg_user'@'%' resource group `default`")
+	c.Assert(manager.GroupOfUser("rg_user", "%"), Equals, "")
+	tk.MustExec("alter user 'rg_user'@'%' resource group rg_bind")
+
+	// Dropping the group unbinds its users.
+	tk.MustExec("drop resource group rg_bind")
+	c.Assert(manager.GetGroup("rg_bind"), IsNil)
+	c.Assert(manager.GroupOfUser("rg_user", "%"), Equals, "")
+	tk.MustQuery("select count(*) from mysql.resource_group_users").Check(testkit.Rows("0"))
+}
+
+func (s *testResourceGroupSuite) TestPrivilege(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("create user 'rg_admin'@'%', 'rg_other'@'%'")
+	defer tk.MustExec("drop user 'rg_admin'@'%', 'rg_other'@'%'")
+	tk.MustExec("grant create user on *.* to 'rg_admin'@'%'")
+
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	c.Assert(tk1.Se.Auth(&auth.UserIdentity{Username: "rg_admin", Hostname: "localhost"}, nil, nil), IsTrue)
+	for _, sql := range []string{
+		"create resource group rg_priv ru_per_sec = 10",
+		"alter resource group rg_priv ru_per_sec = 10",
+		"drop resource group rg_priv",
+		"alter user 'rg_other'@'%' resource group rg_priv",
+	} {
+		_, err := tk1.Exec(sql)
+		c.Assert(err, ErrorMatches, ".*SUPER or RESOURCE_GROUP_ADMIN.*", Commentf(sql))
+	}
+
+	tk.MustExec("set tidb_enable_dynamic_privileges = 1")
+	tk.MustExec("grant resource_group_admin on *.* to 'rg_admin'@'%'")
+	tk1.MustExec("create resource group rg_priv ru_per_sec = 10")
+	tk1.MustExec("alter resource group rg_priv ru_per_sec = 20")
+	tk1.MustExec("alter user 'rg_other'@'%' resource group rg_priv")
+	tk1.MustExec("drop resource group rg_priv")
+}
+
+func (s *testResourceGroupSuite) TestThrottleStatements(c *C) {
+	manager := resourcegroup.GlobalManager()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("create resource group rg_throttle ru_per_sec = 10")
+	defer tk.MustExec("drop resource group rg_throttle")
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t_throttle")
+	tk.MustExec("create table t_throttle (id int primary key, v int)")
+	tk.MustExec("insert into t_throttle values (1, 1), (2, 2)")
+	tk.MustExec("set @@tidb_resource_group = 'rg_throttle'")
+	c.Assert(tk.Se.GetSessionVars().ResourceGroupName, Equals, "rg_throttle")
+
+	for _, ca := range []struct {
+		sql      string
+		expected []string
+	}{
+		{"select * from t_throttle", []string{"1 1", "2 2"}},
+		{"select * from t_throttle where id = 1", []string{"1 1"}},
+		{"select * from t_throttle where id in (1, 2)", []string{"1 1", "2 2"}},
+		{"insert into t_throttle values (3, 3)", nil},
+	} {
+		// Use up the burst and run the group into a debt of at least 0.3s.
+		manager.Charge("rg_throttle", metrics.LblRead, 13)
+		start := time.Now()
+		if ca.expected != nil {
+			tk.MustQuery(ca.sql).Check(testkit.Rows(ca.expected...))
+		} else {
+			tk.MustExec(ca.sql)
+		}
+		c.Assert(time.Since(start) >= 250*time.Millisecond, IsTrue, Commentf("%s", ca.sql))
+	}
+
+	// The statements of the sessions out of the group are not throttled.
+	tk.MustExec("set @@tidb_resource_group = ''")
+	manager.Charge("rg_throttle", metrics.LblRead, 1000)
+	tk.MustQuery("select * from t_throttle").Check(testkit.Rows("1 1", "2 2", "3 3"))
+	tk.MustExec("insert into t_throttle values (4, 4)")
+}
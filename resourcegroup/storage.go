@@ -0,0 +1,224 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcegroup
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+const maxNameLength = 64
+
+// UserBinding is a user account bound to a resource group.
+type UserBinding struct {
+	User          string `json:"user"`
+	Host          string `json:"host"`
+	ResourceGroup string `json:"resource_group"`
+}
+
+// CreateGroup creates a resource group, the empty priority means the medium priority. It does nothing if the group
+// exists and ifNotExists is true.
+func (m *Manager) CreateGroup(sctx sessionctx.Context, name string, ruPerSec int64, priority string, ifNotExists bool) error {
+	name = NormalizeName(name)
+	if name == "" || name == DefaultGroupName || len(name) > maxNameLength {
+		return errors.Errorf("invalid resource group name '%s'", name)
+	}
+	if ruPerSec < 0 {
+		return errors.Errorf("invalid RU_PER_SEC %d", ruPerSec)
+	}
+	if priority == "" {
+		priority = PriorityMedium
+	}
+	if err := checkPriority(priority); err != nil {
+		return err
+	}
+	exists, err := groupExists(sctx, name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if ifNotExists {
+			return nil
+		}
+		return errors.Errorf("resource group '%s' already exists", name)
+	}
+	err = execSQL(sctx, "INSERT INTO %n.%n (name, ru_per_sec, priority) VALUES (%?, %?, %?)",
+		mysql.SystemDB, GroupTableName, name, ruPerSec, strings.ToLower(priority))
+	if err != nil {
+		return err
+	}
+	return m.Reload(sctx)
+}
+
+// AlterGroup changes the quota and the priority of a resource group. The negative ruPerSec keeps the quota, and the
+// empty priority keeps the priority.
+func (m *Manager) AlterGroup(sctx sessionctx.Context, name string, ruPerSec int64, priority string) error {
+	name = NormalizeName(name)
+	rows, err := querySQL(sctx, "SELECT ru_per_sec, priority FROM %n.%n WHERE name = %?", mysql.SystemDB, GroupTableName, name)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return errors.Errorf("resource group '%s' doesn't exist", name)
+	}
+	if ruPerSec < 0 {
+		ruPerSec = rows[0].GetInt64(0)
+	}
+	if priority == "" {
+		priority = rows[0].GetString(1)
+	}
+	if err = checkPriority(priority); err != nil {
+		return err
+	}
+	err = execSQL(sctx, "UPDATE %n.%n SET ru_per_sec = %?, priority = %? WHERE name = %?",
+		mysql.SystemDB, GroupTableName, ruPerSec, strings.ToLower(priority), name)
+	if err != nil {
+		return err
+	}
+	return m.Reload(sctx)
+}
+
+// DropGroup drops a resource group and unbinds the user accounts bound to it. It does nothing if the group doesn't
+// exist and ifExists is true.
+func (m *Manager) DropGroup(sctx sessionctx.Context, name string, ifExists bool) error {
+	name = NormalizeName(name)
+	exists, err := groupExists(sctx, name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if ifExists {
+			return nil
+		}
+		return errors.Errorf("resource group '%s' doesn't exist", name)
+	}
+	if err = execSQL(sctx, "BEGIN PESSIMISTIC"); err != nil {
+		return err
+	}
+	err = execSQL(sctx, "DELETE FROM %n.%n WHERE name = %?", mysql.SystemDB, GroupTableName, name)
+	if err == nil {
+		err = execSQL(sctx, "DELETE FROM %n.%n WHERE resource_group = %?", mysql.SystemDB, UserTableName, name)
+	}
+	if err == nil {
+		err = execSQL(sctx, "COMMIT")
+	}
+	if err != nil {
+		_ = execSQL(sctx, "ROLLBACK")
+		return err
+	}
+	return m.Reload(sctx)
+}
+
+func checkPriority(priority string) error {
+	switch strings.ToLower(priority) {
+	case PriorityLow, PriorityMedium, PriorityHigh:
+		return nil
+	}
+	return errors.Errorf("invalid PRIORITY '%s'", priority)
+}
+
+func groupExists(sctx sessionctx.Context, name string) (bool, error) {
+	rows, err := querySQL(sctx, "SELECT 1 FROM %n.%n WHERE name = %?", mysql.SystemDB, GroupTableName, name)
+	return len(rows) > 0, err
+}
+
+// BindUser binds a user account to a resource group, the new connections of the account use the resource group.
+func (m *Manager) BindUser(sctx sessionctx.Context, user, host, name string) error {
+	name = NormalizeName(name)
+	exists, err := groupExists(sctx, name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.Errorf("resource group '%s' doesn't exist", name)
+	}
+	err = execSQL(sctx, "REPLACE INTO %n.%n (user, host, resource_group) VALUES (%?, %?, %?)",
+		mysql.SystemDB, UserTableName, user, host, name)
+	if err != nil {
+		return err
+	}
+	return m.Reload(sctx)
+}
+
+// UnbindUser unbinds a user account from its resource group.
+func (m *Manager) UnbindUser(sctx sessionctx.Context, user, host string) error {
+	err := execSQL(sctx, "DELETE FROM %n.%n WHERE user = %? AND host = %?", mysql.SystemDB, UserTableName, user, host)
+	if err != nil {
+		return err
+	}
+	return m.Reload(sctx)
+}
+
+// UserBindings returns the user accounts bound to the resource group.
+func (m *Manager) UserBindings(name string) []UserBinding {
+	name = NormalizeName(name)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	bindings := make([]UserBinding, 0)
+	for _, binding := range m.mu.users {
+		if binding.ResourceGroup == name {
+			bindings = append(bindings, binding)
+		}
+	}
+	sort.Slice(bindings, func(i, j int) bool {
+		return bindings[i].User < bindings[j].User || (bindings[i].User == bindings[j].User && bindings[i].Host < bindings[j].Host)
+	})
+	return bindings
+}
+
+// Reload loads the resource groups and the user bindings from the system tables.
+func (m *Manager) Reload(sctx sessionctx.Context) error {
+	rows, err := querySQL(sctx, "SELECT name, ru_per_sec, priority FROM %n.%n", mysql.SystemDB, GroupTableName)
+	if err != nil {
+		return err
+	}
+	groups := make(map[string]groupSetting, len(rows))
+	for _, row := range rows {
+		groups[NormalizeName(row.GetString(0))] = groupSetting{ruPerSec: row.GetInt64(1), priority: row.GetString(2)}
+	}
+	rows, err = querySQL(sctx, "SELECT user, host, resource_group FROM %n.%n", mysql.SystemDB, UserTableName)
+	if err != nil {
+		return err
+	}
+	users := make(map[string]UserBinding, len(rows))
+	for _, row := range rows {
+		binding := UserBinding{User: row.GetString(0), Host: row.GetString(1), ResourceGroup: NormalizeName(row.GetString(2))}
+		users[userKey(binding.User, binding.Host)] = binding
+	}
+	m.update(groups, users)
+	return nil
+}
+
+func querySQL(sctx sessionctx.Context, sql string, args ...interface{}) ([]chunk.Row, error) {
+	ctx := context.Background()
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(ctx, sql, args...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+	return rows, errors.Trace(err)
+}
+
+func execSQL(sctx sessionctx.Context, sql string, args ...interface{}) error {
+	_, err := sctx.(sqlexec.SQLExecutor).ExecuteInternal(context.Background(), sql, args...)
+	return errors.Trace(err)
+}
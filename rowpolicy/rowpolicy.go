@@ -0,0 +1,162 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rowpolicy
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/parser/auth"
+	"github.com/pingcap/tidb/privilege"
+)
+
+const (
+	// TableName is the name of the system table storing the row policies.
+	TableName = "row_policies"
+	// LoadInterval is the interval of reloading the row policies from the system table.
+	LoadInterval = 10 * time.Second
+	// BypassPriv is the dynamic privilege to bypass all the row policies.
+	BypassPriv = "BYPASSRLS"
+)
+
+// Policy is a row policy. The queries of the grantee on the table only see the rows satisfying the Using expression,
+// and the rows written by the grantee must satisfy it too. A user or role granted several policies of a table sees the
+// rows satisfying any of them.
+type Policy struct {
+	Name  string `json:"name"`
+	DB    string `json:"db"`
+	Table string `json:"table"`
+	User  string `json:"user"`
+	Host  string `json:"host"`
+	Using string `json:"using"`
+}
+
+// Manager manages the row policies.
+type Manager struct {
+	mu struct {
+		sync.RWMutex
+		// policies maps the "db.table" to the policies of the table, ordered by their names.
+		policies map[string][]Policy
+		// version is increased every time the policies are changed.
+		version uint64
+	}
+}
+
+// NewManager creates a Manager without any row policy.
+func NewManager() *Manager {
+	m := &Manager{}
+	m.mu.policies = make(map[string][]Policy)
+	return m
+}
+
+var globalManager = NewManager()
+
+func init() {
+	privilege.MustRegisterDynamicPrivilege(BypassPriv)
+}
+
+// GlobalManager returns the Manager of the tidb-server.
+func GlobalManager() *Manager {
+	return globalManager
+}
+
+func tableKey(db, table string) string {
+	return strings.ToLower(db) + "." + strings.ToLower(table)
+}
+
+// update replaces the row policies, the version is only increased if they are changed.
+func (m *Manager) update(policies map[string][]Policy) {
+	for _, tablePolicies := range policies {
+		sort.Slice(tablePolicies, func(i, j int) bool { return tablePolicies[i].Name < tablePolicies[j].Name })
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if reflect.DeepEqual(m.mu.policies, policies) {
+		return
+	}
+	m.mu.policies = policies
+	m.mu.version++
+}
+
+// Version returns the version of the row policies. The plans depending on the policies must be rebuilt after the
+// version is changed.
+func (m *Manager) Version() uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.mu.version
+}
+
+// HasPolicies returns whether the table has any row policy.
+func (m *Manager) HasPolicies(db, table string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.mu.policies[tableKey(db, table)]) > 0
+}
+
+// TablePolicies returns the row policies of the table ordered by their names.
+func (m *Manager) TablePolicies(db, table string) []Policy {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]Policy{}, m.mu.policies[tableKey(db, table)]...)
+}
+
+// Policies returns all the row policies ordered by their tables and names.
+func (m *Manager) Policies() []Policy {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	policies := make([]Policy, 0, len(m.mu.policies))
+	for _, tablePolicies := range m.mu.policies {
+		policies = append(policies, tablePolicies...)
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		if policies[i].DB != policies[j].DB {
+			return policies[i].DB < policies[j].DB
+		}
+		if policies[i].Table != policies[j].Table {
+			return policies[i].Table < policies[j].Table
+		}
+		return policies[i].Name < policies[j].Name
+	})
+	return policies
+}
+
+// PoliciesFor returns the row policies of the table granted to the user account or any of the roles. The roles
+// should be all the roles granted to the user, active or not, otherwise the user could escape the policies of its
+// roles by SET ROLE NONE. The caller should check the BypassPriv before applying them.
+func (m *Manager) PoliciesFor(db, table string, user *auth.UserIdentity, roles []*auth.RoleIdentity) []Policy {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var policies []Policy
+	for _, policy := range m.mu.policies[tableKey(db, table)] {
+		if policy.grantedTo(user, roles) {
+			policies = append(policies, policy)
+		}
+	}
+	return policies
+}
+
+func (p *Policy) grantedTo(user *auth.UserIdentity, roles []*auth.RoleIdentity) bool {
+	if user != nil && p.User == user.AuthUsername && strings.EqualFold(p.Host, user.AuthHostname) {
+		return true
+	}
+	for _, role := range roles {
+		if p.User == role.Username && strings.EqualFold(p.Host, role.Hostname) {
+			return true
+		}
+	}
+	return false
+}
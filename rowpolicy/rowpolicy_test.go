@@ -0,0 +1,125 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rowpolicy_test
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/auth"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/rowpolicy"
+	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testRowPolicySuite{})
+
+type testRowPolicySuite struct {
+	store kv.Storage
+	dom   *domain.Domain
+	se    session.Session
+}
+
+func (s *testRowPolicySuite) SetUpSuite(c *C) {
+	store, err := mockstore.NewMockStore()
+	c.Assert(err, IsNil)
+	s.store = store
+	session.SetSchemaLease(0)
+	s.dom, err = session.BootstrapSession(s.store)
+	c.Assert(err, IsNil)
+	s.se, err = session.CreateSession4Test(s.store)
+	c.Assert(err, IsNil)
+}
+
+func (s *testRowPolicySuite) TearDownSuite(c *C) {
+	s.se.Close()
+	s.dom.Close()
+	s.store.Close()
+}
+
+func (s *testRowPolicySuite) TestCreateAndDrop(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t_rp (id int primary key, tenant int)")
+	tk.MustExec("create view v_rp as select * from t_rp")
+	defer tk.MustExec("drop table t_rp; drop view v_rp")
+
+	manager := rowpolicy.GlobalManager()
+	version := manager.Version()
+	policy := rowpolicy.Policy{Name: "P_Tenant", DB: "Test", Table: "T_RP", User: "u1", Using: "tenant = 1"}
+	c.Assert(manager.CreatePolicy(s.se, s.dom.InfoSchema(), policy, false), IsNil)
+	c.Assert(manager.Version(), Equals, version+1)
+	expected := rowpolicy.Policy{Name: "p_tenant", DB: "test", Table: "t_rp", User: "u1", Host: "%", Using: "tenant = 1"}
+	c.Assert(manager.TablePolicies("test", "t_rp"), DeepEquals, []rowpolicy.Policy{expected})
+	c.Assert(manager.HasPolicies("TEST", "T_rp"), IsTrue)
+	tk.MustQuery("select name, db, table_name, user, host, using_expr from mysql.row_policies").Check(
+		testkit.Rows("p_tenant test t_rp u1 % tenant = 1"))
+
+	// Reloading the same policies doesn't change the version.
+	c.Assert(manager.Reload(s.se), IsNil)
+	c.Assert(manager.Version(), Equals, version+1)
+
+	// The existing policy is only replaced by OR REPLACE.
+	policy.Using = "tenant = 2"
+	c.Assert(manager.CreatePolicy(s.se, s.dom.InfoSchema(), policy, false), ErrorMatches, "row policy 'p_tenant' on test.t_rp already exists")
+	c.Assert(manager.CreatePolicy(s.se, s.dom.InfoSchema(), policy, true), IsNil)
+	c.Assert(manager.TablePolicies("test", "t_rp")[0].Using, Equals, "tenant = 2")
+	policy.Using = "tenant = 1"
+	c.Assert(manager.CreatePolicy(s.se, s.dom.InfoSchema(), policy, true), IsNil)
+	c.Assert(manager.Version(), Equals, version+3)
+
+	// The policies are granted to the user accounts or the roles.
+	policy = rowpolicy.Policy{Name: "p_role", DB: "test", Table: "t_rp", User: "r1", Host: "%", Using: "tenant in (2, 3)"}
+	c.Assert(manager.CreatePolicy(s.se, s.dom.InfoSchema(), policy, false), IsNil)
+	c.Assert(manager.Policies(), HasLen, 2)
+	user := &auth.UserIdentity{Username: "u1", Hostname: "localhost", AuthUsername: "u1", AuthHostname: "%"}
+	c.Assert(manager.PoliciesFor("test", "t_rp", user, nil), DeepEquals, []rowpolicy.Policy{expected})
+	roles := []*auth.RoleIdentity{{Username: "r1", Hostname: "%"}}
+	c.Assert(manager.PoliciesFor("test", "t_rp", user, roles), HasLen, 2)
+	c.Assert(manager.PoliciesFor("test", "t_rp", &auth.UserIdentity{AuthUsername: "u2", AuthHostname: "%"}, nil), HasLen, 0)
+
+	c.Assert(manager.DropPolicy(s.se, "test", "t_rp", "P_Role", false), IsNil)
+	c.Assert(manager.DropPolicy(s.se, "test", "t_rp", "p_role", false), ErrorMatches, "row policy 'p_role' on test.t_rp doesn't exist")
+	c.Assert(manager.DropPolicy(s.se, "test", "t_rp", "p_role", true), IsNil)
+	c.Assert(manager.DropPolicy(s.se, "test", "t_rp", "p_tenant", false), IsNil)
+	c.Assert(manager.HasPolicies("test", "t_rp"), IsFalse)
+	c.Assert(manager.Version(), Equals, version+6)
+
+	// The policies are created on the base tables, and the expressions must be valid over their columns.
+	for _, ca := range []struct {
+		policy rowpolicy.Policy
+		err    string
+	}{
+		{rowpolicy.Policy{Name: "", DB: "test", Table: "t_rp", User: "u1", Using: "tenant = 1"}, "invalid row policy name ''"},
+		{rowpolicy.Policy{Name: "p", DB: "test", Table: "t_rp", Using: "tenant = 1"}, "the grantee of row policy 'p' is not specified"},
+		{rowpolicy.Policy{Name: "p", DB: "test", Table: "t_none", User: "u1", Using: "tenant = 1"}, ".*doesn't exist"},
+		{rowpolicy.Policy{Name: "p", DB: "test", Table: "v_rp", User: "u1", Using: "tenant = 1"}, "row policies can only be created on base tables"},
+		{rowpolicy.Policy{Name: "p", DB: "mysql", Table: "user", User: "u1", Using: "1"}, "row policies can only be created on base tables"},
+		{rowpolicy.Policy{Name: "p", DB: "test", Table: "t_rp", User: "u1", Using: ""}, "invalid USING expression of row policy .p.*"},
+		{rowpolicy.Policy{Name: "p", DB: "test", Table: "t_rp", User: "u1", Using: "unknown = 1"}, ".*Unknown column.*"},
+		{rowpolicy.Policy{Name: "p", DB: "test", Table: "t_rp", User: "u1", Using: "t_rp.tenant = 1"}, ".*the column names can't be qualified"},
+		{rowpolicy.Policy{Name: "p", DB: "test", Table: "t_rp", User: "u1", Using: "tenant in (select 1)"}, ".*can only reference the columns of the table"},
+		{rowpolicy.Policy{Name: "p", DB: "test", Table: "t_rp", User: "u1", Using: "tenant = @a"}, ".*can only reference the columns of the table"},
+	} {
+		c.Assert(manager.CreatePolicy(s.se, s.dom.InfoSchema(), ca.policy, false), ErrorMatches, ca.err, Commentf("%v", ca.policy))
+	}
+	c.Assert(manager.Policies(), HasLen, 0)
+}
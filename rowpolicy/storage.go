@@ -0,0 +1,193 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rowpolicy
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+const maxNameLength = 64
+
+// CreatePolicy creates a row policy, orReplace replaces the grantee and the expression of the policy if it exists.
+// The expression must be a valid expression over the columns of the table, and can't qualify the column names.
+func (m *Manager) CreatePolicy(sctx sessionctx.Context, is infoschema.InfoSchema, p Policy, orReplace bool) error {
+	p.Name, p.DB, p.Table = strings.ToLower(p.Name), strings.ToLower(p.DB), strings.ToLower(p.Table)
+	p.Using = strings.TrimSpace(p.Using)
+	if p.Name == "" || len(p.Name) > maxNameLength {
+		return errors.Errorf("invalid row policy name '%s'", p.Name)
+	}
+	if p.User == "" {
+		return errors.Errorf("the grantee of row policy '%s' is not specified", p.Name)
+	}
+	if p.Host == "" {
+		p.Host = "%"
+	}
+	tbl, err := is.TableByName(model.NewCIStr(p.DB), model.NewCIStr(p.Table))
+	if err != nil {
+		return err
+	}
+	if util.IsMemOrSysDB(p.DB) || tbl.Meta().IsView() || tbl.Meta().IsSequence() || tbl.Type().IsVirtualTable() {
+		return errors.Errorf("row policies can only be created on base tables")
+	}
+	if err = validateUsing(sctx, tbl.Meta(), p.Using); err != nil {
+		return errors.Annotatef(err, "invalid USING expression of row policy '%s'", p.Name)
+	}
+	if !orReplace {
+		exists, err := policyExists(sctx, p.DB, p.Table, p.Name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return errors.Errorf("row policy '%s' on %s.%s already exists", p.Name, p.DB, p.Table)
+		}
+	}
+	err = execSQL(sctx, "REPLACE INTO %n.%n (name, db, table_name, user, host, using_expr) VALUES (%?, %?, %?, %?, %?, %?)",
+		mysql.SystemDB, TableName, p.Name, p.DB, p.Table, p.User, p.Host, p.Using)
+	if err != nil {
+		return err
+	}
+	return m.Reload(sctx)
+}
+
+// DropPolicy drops a row policy of the table, ifExists ignores the policy not existing.
+func (m *Manager) DropPolicy(sctx sessionctx.Context, db, table, name string, ifExists bool) error {
+	db, table, name = strings.ToLower(db), strings.ToLower(table), strings.ToLower(name)
+	exists, err := policyExists(sctx, db, table, name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if ifExists {
+			return nil
+		}
+		return errors.Errorf("row policy '%s' on %s.%s doesn't exist", name, db, table)
+	}
+	err = execSQL(sctx, "DELETE FROM %n.%n WHERE db = %? AND table_name = %? AND name = %?",
+		mysql.SystemDB, TableName, db, table, name)
+	if err != nil {
+		return err
+	}
+	return m.Reload(sctx)
+}
+
+func policyExists(sctx sessionctx.Context, db, table, name string) (bool, error) {
+	rows, err := querySQL(sctx, "SELECT 1 FROM %n.%n WHERE db = %? AND table_name = %? AND name = %?",
+		mysql.SystemDB, TableName, db, table, name)
+	return len(rows) > 0, err
+}
+
+// Reload loads the row policies from the system table.
+func (m *Manager) Reload(sctx sessionctx.Context) error {
+	rows, err := querySQL(sctx, "SELECT name, db, table_name, user, host, using_expr FROM %n.%n", mysql.SystemDB, TableName)
+	if err != nil {
+		return err
+	}
+	policies := make(map[string][]Policy)
+	for _, row := range rows {
+		p := Policy{
+			Name:  row.GetString(0),
+			DB:    row.GetString(1),
+			Table: row.GetString(2),
+			User:  row.GetString(3),
+			Host:  row.GetString(4),
+			Using: row.GetString(5),
+		}
+		key := tableKey(p.DB, p.Table)
+		policies[key] = append(policies[key], p)
+	}
+	m.update(policies)
+	return nil
+}
+
+// ParseUsing parses the USING expression of a row policy.
+func ParseUsing(using string) (ast.ExprNode, error) {
+	stmt, err := parser.New().ParseOneStmt("SELECT "+using, "", "")
+	if err != nil {
+		return nil, err
+	}
+	sel, ok := stmt.(*ast.SelectStmt)
+	if !ok || len(sel.Fields.Fields) != 1 || sel.Fields.Fields[0].Expr == nil || sel.From != nil || sel.Where != nil ||
+		sel.GroupBy != nil || sel.Having != nil || sel.OrderBy != nil || sel.Limit != nil {
+		return nil, errors.Errorf("'%s' is not an expression", using)
+	}
+	return sel.Fields.Fields[0].Expr, nil
+}
+
+func validateUsing(sctx sessionctx.Context, tblInfo *model.TableInfo, using string) error {
+	expr, err := ParseUsing(using)
+	if err != nil {
+		return err
+	}
+	checker := &usingChecker{}
+	expr.Accept(checker)
+	if checker.err != nil {
+		return checker.err
+	}
+	_, err = expression.RewriteSimpleExprWithTableInfo(sctx, tblInfo, expr)
+	return err
+}
+
+// usingChecker checks the USING expression only references the columns of the table by their names, so it can be
+// applied to the table under any alias.
+type usingChecker struct {
+	err error
+}
+
+// Enter implements the ast.Visitor interface.
+func (c *usingChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.ColumnName:
+		if x.Table.L != "" || x.Schema.L != "" {
+			c.err = errors.Errorf("the column names can't be qualified")
+		}
+	case *ast.SubqueryExpr, *ast.ExistsSubqueryExpr, *ast.AggregateFuncExpr, *ast.WindowFuncExpr, *ast.VariableExpr,
+		ast.ParamMarkerExpr, *ast.DefaultExpr, *ast.ValuesExpr:
+		c.err = errors.Errorf("the expression can only reference the columns of the table")
+	}
+	return in, c.err != nil
+}
+
+// Leave implements the ast.Visitor interface.
+func (c *usingChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, c.err == nil
+}
+
+func querySQL(sctx sessionctx.Context, sql string, args ...interface{}) ([]chunk.Row, error) {
+	ctx := context.Background()
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(ctx, sql, args...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+	return rows, errors.Trace(err)
+}
+
+func execSQL(sctx sessionctx.Context, sql string, args ...interface{}) error {
+	_, err := sctx.(sqlexec.SQLExecutor).ExecuteInternal(context.Background(), sql, args...)
+	return errors.Trace(err)
+}
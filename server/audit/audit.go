@@ -0,0 +1,200 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// Sink writes the audit events to the destination, e.g. a file, syslog or kafka.
+// Write is called concurrently by the connections.
+type Sink interface {
+	Write(e *Event) error
+	Close() error
+}
+
+// SinkFactory creates a sink by the audit config.
+type SinkFactory func(cfg *config.Audit) (Sink, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]SinkFactory{
+		FileSinkName: newFileSink,
+	}
+)
+
+// RegisterSink registers a sink factory, the sink is used if its name is in the sinks of the audit config.
+// It's usually called by the init function of a plugin package, so the enterprise sinks can be attached.
+func RegisterSink(name string, factory SinkFactory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	if _, ok := factories[name]; ok {
+		panic("audit sink " + name + " is already registered")
+	}
+	factories[name] = factory
+}
+
+// RegisteredSinks returns the names of the registered sinks.
+func RegisteredSinks() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type namedSink struct {
+	name string
+	Sink
+}
+
+type logger struct {
+	classes map[EventClass]struct{}
+	filters []*filter
+	sinks   []namedSink
+}
+
+var (
+	enabled uint32
+	mu      sync.RWMutex
+	current *logger
+)
+
+// Setup sets up the audit log by the config, the previous sinks are closed.
+func Setup(cfg *config.Audit) error {
+	var l *logger
+	if cfg.Enable {
+		var err error
+		if l, err = newLogger(cfg); err != nil {
+			return err
+		}
+	}
+	mu.Lock()
+	prev := current
+	current = l
+	if l != nil {
+		atomic.StoreUint32(&enabled, 1)
+	} else {
+		atomic.StoreUint32(&enabled, 0)
+	}
+	mu.Unlock()
+	prev.close()
+	return nil
+}
+
+// Close closes the sinks and disables the audit log.
+func Close() {
+	mu.Lock()
+	prev := current
+	current = nil
+	atomic.StoreUint32(&enabled, 0)
+	mu.Unlock()
+	prev.close()
+}
+
+// Enabled returns whether the audit log is enabled, the callers check it before building the events.
+func Enabled() bool {
+	return atomic.LoadUint32(&enabled) == 1
+}
+
+// Log writes the event to the sinks if it's selected by the event classes and the filters.
+func Log(e *Event) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if current == nil || !current.accept(e) {
+		return
+	}
+	for _, s := range current.sinks {
+		if err := s.Write(e); err != nil {
+			logutil.BgLogger().Warn("write audit event failed", zap.String("sink", s.name), zap.Error(err))
+		}
+	}
+}
+
+func newLogger(cfg *config.Audit) (*logger, error) {
+	l := &logger{classes: make(map[EventClass]struct{}, len(cfg.EventClasses))}
+	for _, name := range cfg.EventClasses {
+		class, err := parseEventClass(name)
+		if err != nil {
+			return nil, err
+		}
+		l.classes[class] = struct{}{}
+	}
+	for i := range cfg.Filters {
+		f, err := newFilter(&cfg.Filters[i])
+		if err != nil {
+			return nil, err
+		}
+		l.filters = append(l.filters, f)
+	}
+	for _, name := range cfg.Sinks {
+		factoriesMu.RLock()
+		factory, ok := factories[name]
+		factoriesMu.RUnlock()
+		if !ok {
+			l.close()
+			return nil, errors.Errorf("unknown audit sink %s, the registered sinks are %v", name, RegisteredSinks())
+		}
+		s, err := factory(cfg)
+		if err != nil {
+			l.close()
+			return nil, errors.Annotatef(err, "create audit sink %s", name)
+		}
+		l.sinks = append(l.sinks, namedSink{name: name, Sink: s})
+	}
+	return l, nil
+}
+
+// accept checks whether the event is audited. An event is audited if it matches any filter without exclude, or there
+// is no such filter, and it doesn't match any filter with exclude.
+func (l *logger) accept(e *Event) bool {
+	if _, ok := l.classes[e.Class]; !ok {
+		return false
+	}
+	included, hasInclude := false, false
+	for _, f := range l.filters {
+		if f.exclude {
+			if f.match(e) {
+				return false
+			}
+			continue
+		}
+		hasInclude = true
+		if !included {
+			included = f.match(e)
+		}
+	}
+	return included || !hasInclude
+}
+
+func (l *logger) close() {
+	if l == nil {
+		return
+	}
+	for _, s := range l.sinks {
+		if err := s.Close(); err != nil {
+			logutil.BgLogger().Warn("close audit sink failed", zap.String("sink", s.name), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser"
+	"github.com/pingcap/tidb/config"
+	_ "github.com/pingcap/tidb/types/parser_driver"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = SerialSuites(&testAuditSuite{})
+
+type testAuditSuite struct{}
+
+type memorySink struct {
+	sync.Mutex
+	events []*Event
+	closed bool
+}
+
+func (s *memorySink) Write(e *Event) error {
+	s.Lock()
+	defer s.Unlock()
+	s.events = append(s.events, e)
+	return nil
+}
+
+func (s *memorySink) Close() error {
+	s.Lock()
+	defer s.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *testAuditSuite) TestStmtClass(c *C) {
+	tests := []struct {
+		sql   string
+		class EventClass
+		typ   string
+	}{
+		{"select * from t", ClassQuery, "Select"},
+		{"insert into t values (1)", ClassQuery, "Insert"},
+		{"set @a = 1", ClassQuery, "Set"},
+		{"create table t (a int)", ClassDDL, "CreateTable"},
+		{"drop database test", ClassDDL, "DropDatabase"},
+		{"truncate table t", ClassDDL, "TruncateTable"},
+		{"create user u", ClassPrivilege, "CreateUser"},
+		{"drop user u", ClassPrivilege, "DropUser"},
+		{"grant select on test.* to u", ClassPrivilege, "Grant"},
+		{"revoke select on test.* from u", ClassPrivilege, "Revoke"},
+		{"grant r to u", ClassPrivilege, "GrantRole"},
+		{"set password for u = 'abc'", ClassPrivilege, "SetPwd"},
+	}
+	p := parser.New()
+	for _, t := range tests {
+		stmt, err := p.ParseOneStmt(t.sql, "", "")
+		c.Assert(err, IsNil)
+		c.Assert(StmtClass(stmt), Equals, t.class, Commentf("%s", t.sql))
+		c.Assert(StmtType(stmt), Equals, t.typ, Commentf("%s", t.sql))
+	}
+}
+
+func (s *testAuditSuite) TestFilter(c *C) {
+	selectT := &Event{Class: ClassQuery, User: "app_1", DB: "test", Tables: []Table{{DB: "test", Table: "t1"}}}
+	selectS := &Event{Class: ClassQuery, User: "root", DB: "test", Tables: []Table{{DB: "mysql", Table: "user"}}}
+	createDB := &Event{Class: ClassDDL, User: "root", Tables: []Table{{DB: "Test"}}}
+	connect := &Event{Class: ClassConnection, User: "app_1", DB: "test"}
+
+	tests := []struct {
+		cfg     config.AuditFilter
+		matched []bool
+	}{
+		{config.AuditFilter{}, []bool{true, true, true, true}},
+		{config.AuditFilter{Classes: []string{"query", "DDL"}}, []bool{true, true, true, false}},
+		{config.AuditFilter{Users: []string{"app_*"}}, []bool{true, false, false, true}},
+		{config.AuditFilter{Databases: []string{"test"}}, []bool{true, false, true, true}},
+		{config.AuditFilter{Databases: []string{"my*"}}, []bool{false, true, false, false}},
+		{config.AuditFilter{Tables: []string{"test.t?"}}, []bool{true, false, false, false}},
+		{config.AuditFilter{Tables: []string{"*.*"}}, []bool{true, true, false, false}},
+		{config.AuditFilter{Users: []string{"root"}, Tables: []string{"mysql.*"}}, []bool{false, true, false, false}},
+	}
+	for i, t := range tests {
+		f, err := newFilter(&t.cfg)
+		c.Assert(err, IsNil)
+		for j, e := range []*Event{selectT, selectS, createDB, connect} {
+			c.Assert(f.match(e), Equals, t.matched[j], Commentf("filter %d, event %d", i, j))
+		}
+	}
+
+	_, err := newFilter(&config.AuditFilter{Classes: []string{"dml"}})
+	c.Assert(err, ErrorMatches, "unknown audit event class dml")
+	_, err = newFilter(&config.AuditFilter{Users: []string{"["}})
+	c.Assert(err, NotNil)
+}
+
+func (s *testAuditSuite) TestLog(c *C) {
+	sink := &memorySink{}
+	RegisterSink("memory", func(cfg *config.Audit) (Sink, error) {
+		return sink, nil
+	})
+	c.Assert(func() {
+		RegisterSink("memory", nil)
+	}, PanicMatches, "audit sink memory is already registered")
+	c.Assert(RegisteredSinks(), DeepEquals, []string{"file", "memory"})
+
+	cfg := config.Audit{
+		Enable:       true,
+		EventClasses: []string{"connection", "query", "ddl"},
+		Sinks:        []string{"memory"},
+		Filters: []config.AuditFilter{
+			{Users: []string{"app_*", "root"}},
+			{Classes: []string{"query"}, Users: []string{"root"}, Exclude: true},
+		},
+	}
+	c.Assert(Setup(&cfg), IsNil)
+	c.Assert(Enabled(), IsTrue)
+
+	events := []*Event{
+		{Class: ClassConnection, User: "app_1"},
+		{Class: ClassQuery, User: "app_1"},
+		{Class: ClassPrivilege, User: "app_1"},
+		{Class: ClassQuery, User: "root"},
+		{Class: ClassDDL, User: "root"},
+		{Class: ClassQuery, User: "u"},
+	}
+	for _, e := range events {
+		Log(e)
+	}
+	c.Assert(sink.events, DeepEquals, []*Event{events[0], events[1], events[4]})
+
+	// Only the exclude filters.
+	cfg.Filters = cfg.Filters[1:]
+	c.Assert(Setup(&cfg), IsNil)
+	c.Assert(sink.closed, IsTrue)
+	sink.events = nil
+	for _, e := range events {
+		Log(e)
+	}
+	c.Assert(sink.events, DeepEquals, []*Event{events[0], events[1], events[4], events[5]})
+
+	Close()
+	c.Assert(Enabled(), IsFalse)
+	sink.events = nil
+	Log(events[0])
+	c.Assert(sink.events, HasLen, 0)
+
+	cfg.Sinks = []string{"unknown"}
+	c.Assert(Setup(&cfg), ErrorMatches, "unknown audit sink unknown.*")
+	c.Assert(Enabled(), IsFalse)
+	cfg.Sinks = []string{"memory"}
+	cfg.EventClasses = []string{"dml"}
+	c.Assert(Setup(&cfg), ErrorMatches, "unknown audit event class dml")
+	cfg.Enable = false
+	c.Assert(Setup(&cfg), IsNil)
+	c.Assert(Enabled(), IsFalse)
+}
+
+func (s *testAuditSuite) TestFileSink(c *C) {
+	dir, err := ioutil.TempDir("", "audit")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	cfg := config.Audit{
+		Enable:       true,
+		EventClasses: []string{"connection", "query"},
+		Sinks:        []string{FileSinkName},
+	}
+	cfg.File.Filename = dir
+	c.Assert(Setup(&cfg), ErrorMatches, ".*can't use directory as audit log file name")
+	cfg.File.Filename = filepath.Join(dir, "audit.log")
+	c.Assert(Setup(&cfg), IsNil)
+	e1 := NewConnEvent(TypeConnect, 1, "root", "127.0.0.1", "test", nil)
+	e2 := &Event{Time: e1.Time, Class: ClassQuery, Type: "Select", ConnectionID: 1, User: "root", Host: "127.0.0.1",
+		Tables: []Table{{DB: "test", Table: "t"}}, SQL: "select * from t", CostTime: 0.5, Succ: true}
+	Log(e1)
+	Log(e2)
+	Close()
+
+	f, err := os.Open(cfg.File.Filename)
+	c.Assert(err, IsNil)
+	defer f.Close()
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		c.Assert(json.Unmarshal(scanner.Bytes(), &line), IsNil)
+		lines = append(lines, line)
+	}
+	c.Assert(scanner.Err(), IsNil)
+	c.Assert(lines, HasLen, 2)
+	c.Assert(lines[0]["class"], Equals, "connection")
+	c.Assert(lines[0]["type"], Equals, "Connect")
+	c.Assert(lines[0]["db"], Equals, "test")
+	c.Assert(lines[0]["succ"], Equals, true)
+	_, ok := lines[0]["sql"]
+	c.Assert(ok, IsFalse)
+	c.Assert(lines[1]["sql"], Equals, "select * from t")
+	c.Assert(lines[1]["cost_time"], Equals, 0.5)
+	c.Assert(lines[1]["tables"], DeepEquals, []interface{}{map[string]interface{}{"db": "test", "table": "t"}})
+}
@@ -0,0 +1,182 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/tidb/sessionctx/variable"
+)
+
+// EventClass is the class of the audit events.
+type EventClass string
+
+// The event classes.
+const (
+	ClassConnection EventClass = "connection"
+	ClassQuery      EventClass = "query"
+	ClassDDL        EventClass = "ddl"
+	ClassPrivilege  EventClass = "privilege"
+)
+
+func parseEventClass(name string) (EventClass, error) {
+	switch class := EventClass(strings.ToLower(name)); class {
+	case ClassConnection, ClassQuery, ClassDDL, ClassPrivilege:
+		return class, nil
+	}
+	return "", errors.Errorf("unknown audit event class %s", name)
+}
+
+// The types of the connection events, the type of a statement event is the statement type, e.g. Select or CreateUser.
+const (
+	TypeConnect    = "Connect"
+	TypeDisconnect = "Disconnect"
+	TypeReject     = "Reject"
+	TypeChangeUser = "ChangeUser"
+)
+
+// The types of the account events, which are in the privilege class.
+const (
+	TypeAccountLock   = "AccountLock"
+	TypeAccountUnlock = "AccountUnlock"
+)
+
+// Event is an audit event, it's written to the sinks as a JSON object. The host is the address of the client, the
+// proxy is the address of the PROXY protocol frontend if the client connects through it.
+type Event struct {
+	Time         time.Time  `json:"time"`
+	Class        EventClass `json:"class"`
+	Type         string     `json:"type"`
+	ConnectionID uint64     `json:"connection_id"`
+	User         string     `json:"user"`
+	Host         string     `json:"host"`
+	Proxy        string     `json:"proxy,omitempty"`
+	DB           string     `json:"db,omitempty"`
+	Tables       []Table    `json:"tables,omitempty"`
+	SQL          string     `json:"sql,omitempty"`
+	// CostTime is the execution time of the statement or the lifetime of the connection in seconds.
+	CostTime     float64 `json:"cost_time,omitempty"`
+	AffectedRows uint64  `json:"affected_rows,omitempty"`
+	Succ         bool    `json:"succ"`
+	Error        string  `json:"error,omitempty"`
+	// Attrs are the connection attributes sent by the client, they're only set in the connection events.
+	Attrs map[string]string `json:"attrs,omitempty"`
+	// Reason explains why the account is locked or unlocked, it's only set in the account events.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Table is a table accessed by the statement, the table name is empty if the statement accesses the database.
+type Table struct {
+	DB    string `json:"db"`
+	Table string `json:"table,omitempty"`
+}
+
+// NewConnEvent creates a connection event.
+func NewConnEvent(typ string, connID uint64, user, host, db string, err error) *Event {
+	e := &Event{
+		Time:         time.Now(),
+		Class:        ClassConnection,
+		Type:         typ,
+		ConnectionID: connID,
+		User:         user,
+		Host:         host,
+		DB:           db,
+		Succ:         err == nil,
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	return e
+}
+
+// NewAccountEvent creates the event of locking or unlocking a user account, the user and host are of the account
+// rather than the connection. The reason explains why the account is locked or unlocked.
+func NewAccountEvent(typ string, connID uint64, user, host, reason string) *Event {
+	return &Event{
+		Time:         time.Now(),
+		Class:        ClassPrivilege,
+		Type:         typ,
+		ConnectionID: connID,
+		User:         user,
+		Host:         host,
+		Succ:         true,
+		Reason:       reason,
+	}
+}
+
+// NewStmtEvent creates the event of the statement which is just executed by the session, err is the error of the
+// execution. The prepared statement is passed instead of the EXECUTE statement.
+func NewStmtEvent(connID uint64, vars *variable.SessionVars, stmt ast.StmtNode, err error) *Event {
+	sc := vars.StmtCtx
+	e := &Event{
+		Time:         time.Now(),
+		Class:        StmtClass(stmt),
+		Type:         StmtType(stmt),
+		ConnectionID: connID,
+		DB:           vars.CurrentDB,
+		SQL:          stmtText(vars, stmt),
+		CostTime:     time.Since(vars.StartTime).Seconds(),
+		AffectedRows: sc.AffectedRows(),
+		Succ:         err == nil,
+	}
+	if vars.User != nil {
+		e.User, e.Host = vars.User.Username, vars.User.Hostname
+	}
+	for _, tbl := range sc.Tables {
+		if tbl.DB != "" {
+			e.Tables = append(e.Tables, Table{DB: tbl.DB, Table: tbl.Table})
+		}
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	return e
+}
+
+// stmtText returns the statement text like the slow log, the passwords are hidden.
+func stmtText(vars *variable.SessionVars, stmt ast.StmtNode) string {
+	if ss, ok := stmt.(ast.SensitiveStmtNode); ok {
+		return ss.SecureText()
+	}
+	if vars.EnableRedactLog {
+		sql, _ := vars.StmtCtx.SQLDigest()
+		return sql
+	}
+	return vars.StmtCtx.OriginalSQL + vars.PreparedParams.String()
+}
+
+// StmtClass returns the event class of the statement.
+func StmtClass(stmt ast.StmtNode) EventClass {
+	switch stmt.(type) {
+	case *ast.GrantStmt, *ast.RevokeStmt, *ast.GrantRoleStmt, *ast.RevokeRoleStmt, *ast.SetDefaultRoleStmt,
+		*ast.CreateUserStmt, *ast.AlterUserStmt, *ast.DropUserStmt, *ast.SetPwdStmt:
+		return ClassPrivilege
+	case ast.DDLNode:
+		return ClassDDL
+	}
+	return ClassQuery
+}
+
+// StmtType returns the type of the statement, which is the name of the AST node without the suffix Stmt.
+func StmtType(stmt ast.StmtNode) string {
+	t := reflect.TypeOf(stmt)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return strings.TrimSuffix(t.Name(), "Stmt")
+}
@@ -0,0 +1,76 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/util/logutil"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// FileSinkName is the name of the built-in sink which writes the events into a file.
+const FileSinkName = "file"
+
+// fileSink writes the events into the file as JSON lines, the file is rotated by the size.
+type fileSink struct {
+	mu  sync.Mutex
+	out *lumberjack.Logger
+}
+
+func newFileSink(cfg *config.Audit) (Sink, error) {
+	fileCfg := cfg.File
+	if fileCfg.Filename == "" {
+		return nil, errors.New("the filename of the audit log is empty")
+	}
+	if st, err := os.Stat(fileCfg.Filename); err == nil && st.IsDir() {
+		return nil, errors.New("can't use directory as audit log file name")
+	}
+	if fileCfg.MaxSize == 0 {
+		fileCfg.MaxSize = logutil.DefaultLogMaxSize
+	}
+	return &fileSink{
+		out: &lumberjack.Logger{
+			Filename:   fileCfg.Filename,
+			MaxSize:    fileCfg.MaxSize,
+			MaxBackups: fileCfg.MaxBackups,
+			MaxAge:     fileCfg.MaxDays,
+			LocalTime:  true,
+		},
+	}, nil
+}
+
+// Write implements Sink interface.
+func (s *fileSink) Write(e *Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	data = append(data, '\n')
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.out.Write(data)
+	return errors.Trace(err)
+}
+
+// Close implements Sink interface.
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return errors.Trace(s.out.Close())
+}
@@ -0,0 +1,120 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"path"
+
+	"github.com/pingcap/errors"
+	tablefilter "github.com/pingcap/tidb-tools/pkg/table-filter"
+	"github.com/pingcap/tidb/config"
+)
+
+// filter is a compiled config.AuditFilter, the nil or empty fields match all the events.
+type filter struct {
+	classes map[EventClass]struct{}
+	users   []string
+	dbs     tablefilter.Filter
+	tables  tablefilter.Filter
+	exclude bool
+}
+
+func newFilter(cfg *config.AuditFilter) (*filter, error) {
+	f := &filter{users: cfg.Users, exclude: cfg.Exclude}
+	if len(cfg.Classes) > 0 {
+		f.classes = make(map[EventClass]struct{}, len(cfg.Classes))
+		for _, name := range cfg.Classes {
+			class, err := parseEventClass(name)
+			if err != nil {
+				return nil, err
+			}
+			f.classes[class] = struct{}{}
+		}
+	}
+	for _, pattern := range cfg.Users {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.Annotatef(err, "invalid audit user pattern %s", pattern)
+		}
+	}
+	if len(cfg.Databases) > 0 {
+		// The database patterns are converted to the table patterns which match all the tables in the databases.
+		patterns := make([]string, 0, len(cfg.Databases))
+		for _, pattern := range cfg.Databases {
+			patterns = append(patterns, pattern+".*")
+		}
+		dbs, err := tablefilter.Parse(patterns)
+		if err != nil {
+			return nil, errors.Annotate(err, "invalid audit database pattern")
+		}
+		f.dbs = tablefilter.CaseInsensitive(dbs)
+	}
+	if len(cfg.Tables) > 0 {
+		tables, err := tablefilter.Parse(cfg.Tables)
+		if err != nil {
+			return nil, errors.Annotate(err, "invalid audit table pattern")
+		}
+		f.tables = tablefilter.CaseInsensitive(tables)
+	}
+	return f, nil
+}
+
+// match checks whether the event matches all the fields of the filter. The databases of an event are the databases
+// of the accessed tables, or the current database if no table is accessed.
+func (f *filter) match(e *Event) bool {
+	if f.classes != nil {
+		if _, ok := f.classes[e.Class]; !ok {
+			return false
+		}
+	}
+	if len(f.users) > 0 && !f.matchUser(e.User) {
+		return false
+	}
+	if f.dbs != nil {
+		matched := false
+		if len(e.Tables) == 0 {
+			matched = e.DB != "" && f.dbs.MatchSchema(e.DB)
+		}
+		for _, tbl := range e.Tables {
+			if f.dbs.MatchSchema(tbl.DB) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if f.tables != nil {
+		matched := false
+		for _, tbl := range e.Tables {
+			if tbl.Table != "" && f.tables.MatchTable(tbl.DB, tbl.Table) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *filter) matchUser(user string) bool {
+	for _, pattern := range f.users {
+		if ok, _ := path.Match(pattern, user); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,258 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/auth"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/privilege/privileges"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// The packets of caching_sha2_password, see
+// https://dev.mysql.com/doc/dev/mysql-server/latest/page_caching_sha2_authentication_exchanges.html
+const (
+	authMoreData = 0x01
+
+	cachingSha2RequestPublicKey = 0x02
+	cachingSha2FastAuthSuccess  = 0x03
+	cachingSha2PerformFullAuth  = 0x04
+
+	cachingSha2KeyBits = 2048
+)
+
+// authClearPassword is the client plugin sending the cleartext password, which is required by
+// authentication_ldap_simple to bind the LDAP server.
+const authClearPassword = "mysql_clear_password"
+
+// clientCanHandleExpiredPasswords is the capability of the clients which can handle the sandbox mode of the expired
+// passwords, the other clients are disconnected if their passwords have expired and disconnect_on_expired_password is
+// ON.
+const clientCanHandleExpiredPasswords uint32 = 1 << 22
+
+// authenticate verifies the auth data of the user by the authentication plugin of the user. The client is asked to
+// switch to the plugin of the user if it speaks another one.
+func (cc *clientConn) authenticate(ctx context.Context, user *auth.UserIdentity, authData []byte, authPlugin, hasPassword string) (err error) {
+	userPlugin := cc.ctx.AuthPluginForUser(user)
+	if userPlugin == mysql.AuthCachingSha2Password {
+		if authPlugin != mysql.AuthCachingSha2Password {
+			if authData, err = cc.authSwitchRequest(ctx, mysql.AuthCachingSha2Password); err != nil {
+				logutil.Logger(ctx).Warn("attempt to send auth switch request packet failed", zap.Error(err))
+				return err
+			}
+		}
+		return cc.authCachingSha2Password(ctx, user, authData, hasPassword)
+	}
+	switch userPlugin {
+	case privileges.AuthLDAPSimple:
+		return cc.authLDAPSimple(ctx, user, authData, authPlugin, hasPassword)
+	case privileges.AuthLDAPSASL:
+		return cc.authLDAPSASL(ctx, user, hasPassword)
+	}
+	// switching from other methods should work, but not tested
+	if authPlugin != "" && authPlugin != mysql.AuthNativePassword {
+		if authData, err = cc.authSwitchRequest(ctx, mysql.AuthNativePassword); err != nil {
+			logutil.Logger(ctx).Warn("attempt to send auth switch request packet failed", zap.Error(err))
+			return err
+		}
+	}
+	if !cc.ctx.Auth(user, authData, cc.salt) {
+		return errAccessDenied.FastGenByArgs(cc.user, user.Hostname, hasPassword)
+	}
+	return nil
+}
+
+// authCachingSha2Password authenticates the user of caching_sha2_password. The scramble is verified by the cached
+// password digest first, which is the fast authentication. If it fails, the client is asked to send the password,
+// which is encrypted by the RSA public key of the server unless the connection is secure, and the password digest is
+// cached after it's verified, which is the full authentication.
+func (cc *clientConn) authCachingSha2Password(ctx context.Context, user *auth.UserIdentity, scramble []byte, hasPassword string) error {
+	// The scramble of the empty password is empty or a single '\0'.
+	if len(scramble) == 0 || (len(scramble) == 1 && scramble[0] == 0) {
+		if !cc.ctx.Auth(user, nil, nil) {
+			return errAccessDenied.FastGenByArgs(cc.user, user.Hostname, hasPassword)
+		}
+		return nil
+	}
+	if cc.ctx.Auth(user, scramble, cc.salt) {
+		return cc.writeAuthMoreData(ctx, []byte{cachingSha2FastAuthSuccess}, false)
+	}
+
+	if err := cc.writeAuthMoreData(ctx, []byte{cachingSha2PerformFullAuth}, true); err != nil {
+		return err
+	}
+	data, err := cc.readPacket()
+	if err != nil {
+		return err
+	}
+	var password []byte
+	if cc.tlsConn != nil || cc.server.isUnixSocket() {
+		password = data
+	} else {
+		if len(data) == 1 && data[0] == cachingSha2RequestPublicKey {
+			key, err := cc.server.cachingSha2Key()
+			if err != nil {
+				return err
+			}
+			publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if err = cc.writeAuthMoreData(ctx, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}), true); err != nil {
+				return err
+			}
+			if data, err = cc.readPacket(); err != nil {
+				return err
+			}
+		}
+		if password, err = cc.decryptCachingSha2Password(data); err != nil {
+			logutil.Logger(ctx).Warn("decrypt the password of caching_sha2_password failed", zap.Error(err))
+			return errAccessDenied.FastGenByArgs(cc.user, user.Hostname, hasPassword)
+		}
+	}
+	// The password is terminated by '\0'.
+	password = bytes.TrimSuffix(password, []byte{0})
+	if !cc.ctx.Auth(user, password, nil) {
+		return errAccessDenied.FastGenByArgs(cc.user, user.Hostname, hasPassword)
+	}
+	return nil
+}
+
+// authLDAPSimple authenticates the user of authentication_ldap_simple, the client is asked to send the cleartext
+// password, which is used to bind the LDAP server.
+func (cc *clientConn) authLDAPSimple(ctx context.Context, user *auth.UserIdentity, authData []byte, authPlugin, hasPassword string) (err error) {
+	if authPlugin != authClearPassword {
+		if authData, err = cc.authSwitchRequest(ctx, authClearPassword); err != nil {
+			logutil.Logger(ctx).Warn("attempt to send auth switch request packet failed", zap.Error(err))
+			return err
+		}
+	}
+	// The password is terminated by '\0'.
+	password := bytes.TrimSuffix(authData, []byte{0})
+	if !cc.ctx.Auth(user, password, nil) {
+		return errAccessDenied.FastGenByArgs(cc.user, user.Hostname, hasPassword)
+	}
+	return nil
+}
+
+// authLDAPSASL authenticates the user of authentication_ldap_sasl. The client is asked to switch to
+// authentication_ldap_sasl_client with the SASL mechanism as the plugin data, then the SASL messages are relayed
+// between the client and the LDAP server until the bind is done.
+func (cc *clientConn) authLDAPSASL(ctx context.Context, user *auth.UserIdentity, hasPassword string) error {
+	mechanism, err := cc.ctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.AuthenticationLDAPSASLAuthMethodName)
+	if err != nil {
+		return err
+	}
+	initial, err := cc.authSwitchRequestWithData(ctx, privileges.AuthLDAPSASLClient, []byte(mechanism))
+	if err != nil {
+		logutil.Logger(ctx).Warn("attempt to send auth switch request packet failed", zap.Error(err))
+		return err
+	}
+	if !cc.ctx.AuthWithConn(user, initial, nil, &authConn{ctx: ctx, cc: cc}) {
+		return errAccessDenied.FastGenByArgs(cc.user, user.Hostname, hasPassword)
+	}
+	return nil
+}
+
+// authConn exchanges the auth data with the client by the AuthMoreData packets during the authentication.
+type authConn struct {
+	ctx context.Context
+	cc  *clientConn
+}
+
+// WriteAuthMoreData implements the privilege.AuthConn interface.
+func (c *authConn) WriteAuthMoreData(data []byte) error {
+	return c.cc.writeAuthMoreData(c.ctx, data, true)
+}
+
+// ReadPacket implements the privilege.AuthConn interface.
+func (c *authConn) ReadPacket() ([]byte, error) {
+	return c.cc.readPacket()
+}
+
+// decryptCachingSha2Password decrypts the password encrypted by RSA-OAEP, the plaintext is the password XOR the salt.
+func (cc *clientConn) decryptCachingSha2Password(ciphertext []byte) ([]byte, error) {
+	key, err := cc.server.cachingSha2Key()
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := rsa.DecryptOAEP(sha1.New(), rand.Reader, key, ciphertext, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for i := range plaintext {
+		plaintext[i] ^= cc.salt[i%len(cc.salt)]
+	}
+	return plaintext, nil
+}
+
+func (cc *clientConn) writeAuthMoreData(ctx context.Context, payload []byte, flush bool) error {
+	data := cc.alloc.AllocWithLen(4, 4+1+len(payload))
+	data = append(data, authMoreData)
+	data = append(data, payload...)
+	if err := cc.writePacket(data); err != nil {
+		return err
+	}
+	if flush {
+		return cc.flush(ctx)
+	}
+	return nil
+}
+
+// cachingSha2Key returns the RSA key to exchange the password of caching_sha2_password, a key is generated on the
+// first use if it's not configured.
+func (s *Server) cachingSha2Key() (*rsa.PrivateKey, error) {
+	s.sha2KeyOnce.Do(func() {
+		if s.sha2Key == nil {
+			s.sha2Key, s.sha2KeyErr = rsa.GenerateKey(rand.Reader, cachingSha2KeyBits)
+		}
+	})
+	return s.sha2Key, s.sha2KeyErr
+}
+
+// loadCachingSha2Key loads the RSA private key in PEM format, it can be either PKCS #1 or PKCS #8.
+func loadCachingSha2Key(path string) (*rsa.PrivateKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.Errorf("no PEM data is found in %s", path)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.Errorf("the private key in %s is not an RSA key", path)
+	}
+	return rsaKey, nil
+}
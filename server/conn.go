@@ -60,6 +60,7 @@ import (
 	"github.com/pingcap/parser"
 	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/auth"
+	"github.com/pingcap/parser/charset"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
 	"github.com/pingcap/tidb/config"
@@ -72,8 +73,12 @@ import (
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/plugin"
 	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/resourcegroup"
+	"github.com/pingcap/tidb/server/audit"
+	"github.com/pingcap/tidb/server/workerpool"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/sessionstates"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	tikvstore "github.com/pingcap/tidb/store/tikv/kv"
@@ -81,6 +86,7 @@ import (
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/util/arena"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/encoding"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
@@ -177,13 +183,18 @@ type clientConn struct {
 	alloc        arena.Allocator   // an memory allocator for reducing memory allocation.
 	lastPacket   []byte            // latest sql query string, currently used for logging error.
 	ctx          *TiDBContext      // an interface to execute sql statements.
-	attrs        map[string]string // attributes parsed from client handshake response, not used for now.
+	attrs        map[string]string // attributes parsed from client handshake response.
 	peerHost     string            // peer host
 	peerPort     string            // peer port
+	proxyAddr    string            // address of the PROXY protocol frontend, empty if the client connects directly.
 	status       int32             // dispatching/reading/shutdown/waitshutdown
 	lastCode     uint16            // last error code
 	collation    uint8             // collation used by client, may be different from the collation used by database.
+	zstdLevel    int               // compression level of zstd negotiated in the handshake.
 	lastActive   time.Time
+	// limitedIP and limitedAccount are the client IP and the account which the connection is counted in, see connLimiter.
+	limitedIP      string
+	limitedAccount string
 
 	// mu is used for cancelling the execution of current transaction.
 	mu struct {
@@ -199,18 +210,27 @@ func (cc *clientConn) String() string {
 	)
 }
 
+// authTiDBSessionToken is the authentication plugin used by the proxies to migrate sessions between tidb-servers.
+// The auth data is the token got from `select @@tidb_session_token` on the original tidb-server.
+const authTiDBSessionToken = "tidb_session_token"
+
 // authSwitchRequest is used when the client asked to speak something
-// other than mysql_native_password. The server is allowed to ask
-// the client to switch, so lets ask for mysql_native_password
+// other than the authentication plugin of the user. The server is allowed to ask
+// the client to switch, so lets ask for the plugin of the user
 // https://dev.mysql.com/doc/internals/en/connection-phase-packets.html#packet-Protocol::AuthSwitchRequest
-func (cc *clientConn) authSwitchRequest(ctx context.Context) ([]byte, error) {
-	enclen := 1 + len(mysql.AuthNativePassword) + 1 + len(cc.salt) + 1
+func (cc *clientConn) authSwitchRequest(ctx context.Context, authPlugin string) ([]byte, error) {
+	return cc.authSwitchRequestWithData(ctx, authPlugin, append(cc.salt[:len(cc.salt):len(cc.salt)], 0))
+}
+
+// authSwitchRequestWithData asks the client to switch to the authentication plugin with the plugin data, which is
+// the salt for the most plugins.
+func (cc *clientConn) authSwitchRequestWithData(ctx context.Context, authPlugin string, pluginData []byte) ([]byte, error) {
+	enclen := 1 + len(authPlugin) + 1 + len(pluginData)
 	data := cc.alloc.AllocWithLen(4, enclen)
 	data = append(data, mysql.AuthSwitchRequest) // switch request
-	data = append(data, []byte(mysql.AuthNativePassword)...)
+	data = append(data, []byte(authPlugin)...)
 	data = append(data, byte(0x00)) // requires null
-	data = append(data, cc.salt...)
-	data = append(data, 0)
+	data = append(data, pluginData...)
 	err := cc.writePacket(data)
 	if err != nil {
 		logutil.Logger(ctx).Debug("write response to client failed", zap.Error(err))
@@ -274,7 +294,7 @@ func (cc *clientConn) handshake(ctx context.Context) error {
 	}
 
 	err := cc.writePacket(data)
-	cc.pkt.sequence = 0
+	cc.pkt.resetSequence()
 	if err != nil {
 		err = errors.SuspendStack(err)
 		logutil.Logger(ctx).Debug("write response to client failed", zap.Error(err))
@@ -287,6 +307,13 @@ func (cc *clientConn) handshake(ctx context.Context) error {
 		logutil.Logger(ctx).Debug("flush response to client failed", zap.Error(err))
 		return err
 	}
+
+	// The packets are compressed after the handshake.
+	if cc.capability&mysql.ClientCompress > 0 {
+		cc.pkt.setCompression(compressionZlib, 0)
+	} else if cc.capability&clientZstdCompressionAlgorithm > 0 {
+		cc.pkt.setCompression(compressionZstd, cc.zstdLevel)
+	}
 	return err
 }
 
@@ -300,6 +327,7 @@ func (cc *clientConn) Close() error {
 
 func closeConn(cc *clientConn, connections int) error {
 	metrics.ConnGauge.Set(float64(connections))
+	cc.server.connLimiter.release(cc)
 	err := cc.bufReadConn.Close()
 	terror.Log(err)
 	if cc.ctx != nil {
@@ -330,7 +358,8 @@ func (cc *clientConn) writeInitialHandshake(ctx context.Context) error {
 	// filler [00]
 	data = append(data, 0)
 	// capability flag lower 2 bytes, using default capability here
-	data = append(data, byte(cc.server.capability), byte(cc.server.capability>>8))
+	capability := cc.server.getCapability()
+	data = append(data, byte(capability), byte(capability>>8))
 	// charset
 	if cc.collation == 0 {
 		cc.collation = uint8(mysql.DefaultCollationID)
@@ -340,7 +369,7 @@ func (cc *clientConn) writeInitialHandshake(ctx context.Context) error {
 	data = dumpUint16(data, mysql.ServerStatusAutocommit)
 	// below 13 byte may not be used
 	// capability flag upper 2 bytes, using default capability here
-	data = append(data, byte(cc.server.capability>>16), byte(cc.server.capability>>24))
+	data = append(data, byte(capability>>16), byte(capability>>24))
 	// length of auth-plugin-data
 	data = append(data, byte(len(cc.salt)+1))
 	// reserved 10 [00]
@@ -394,6 +423,7 @@ type handshakeResponse41 struct {
 	Auth       []byte
 	AuthPlugin string
 	Attrs      map[string]string
+	ZstdLevel  int
 }
 
 // parseOldHandshakeResponseHeader parses the old version handshake header HandshakeResponse320
@@ -536,12 +566,20 @@ func parseHandshakeResponseBody(ctx context.Context, packet *handshakeResponse41
 		if num, null, off := parseLengthEncodedInt(data[offset:]); !null {
 			offset += off
 			row := data[offset : offset+int(num)]
+			offset += int(num)
 			attrs, err := parseAttrs(row)
 			if err != nil {
 				logutil.Logger(ctx).Warn("parse attrs failed", zap.Error(err))
-				return nil
+			} else {
+				packet.Attrs = attrs
 			}
-			packet.Attrs = attrs
+		}
+	}
+
+	packet.ZstdLevel = defaultZstdLevel
+	if packet.Capability&clientZstdCompressionAlgorithm > 0 && len(data[offset:]) > 0 {
+		if level := int(data[offset]); level >= 1 && level <= 22 {
+			packet.ZstdLevel = level
 		}
 	}
 
@@ -644,21 +682,14 @@ func (cc *clientConn) readOptionalSSLRequestAndHandshakeResponse(ctx context.Con
 		return err
 	}
 
-	// switching from other methods should work, but not tested
-	if resp.AuthPlugin == mysql.AuthCachingSha2Password {
-		resp.Auth, err = cc.authSwitchRequest(ctx)
-		if err != nil {
-			logutil.Logger(ctx).Warn("attempt to send auth switch request packet failed", zap.Error(err))
-			return err
-		}
-	}
-	cc.capability = resp.Capability & cc.server.capability
+	cc.capability = resp.Capability & cc.server.getCapability()
 	cc.user = resp.User
 	cc.dbname = resp.DBName
 	cc.collation = resp.Collation
 	cc.attrs = resp.Attrs
+	cc.zstdLevel = resp.ZstdLevel
 
-	err = cc.openSessionAndDoAuth(resp.Auth)
+	err = cc.openSessionAndDoAuth(resp.Auth, resp.AuthPlugin)
 	if err != nil {
 		logutil.Logger(ctx).Warn("open new session failure", zap.Error(err))
 	}
@@ -679,35 +710,71 @@ func (cc *clientConn) SessionStatusToString() string {
 	)
 }
 
-func (cc *clientConn) openSessionAndDoAuth(authData []byte) error {
+func (cc *clientConn) openSessionAndDoAuth(authData []byte, authPlugin string) error {
+	hasPassword := "YES"
+	if len(authData) == 0 {
+		hasPassword = "NO"
+	}
+	userIdentity, port, err := cc.openSession(hasPassword)
+	if err != nil {
+		return err
+	}
+	if authPlugin == authTiDBSessionToken {
+		// The session is migrated from another tidb-server, it's authenticated by the session token instead of the password.
+		if err = sessionstates.ValidateSessionToken(authData, cc.user, userIdentity.Hostname); err != nil {
+			logutil.BgLogger().Warn("verify session token failed", zap.String("username", cc.user), zap.Error(err))
+			return errAccessDenied.FastGenByArgs(cc.user, userIdentity.Hostname, hasPassword)
+		}
+		if !cc.ctx.AuthWithoutVerification(userIdentity) {
+			return errAccessDenied.FastGenByArgs(cc.user, userIdentity.Hostname, hasPassword)
+		}
+	} else if err = cc.authenticate(context.Background(), userIdentity, authData, authPlugin, hasPassword); err != nil {
+		return err
+	}
+	return cc.onAuthenticated(port)
+}
+
+// openSession opens the session of the connection and returns the identity of the user to be authenticated.
+func (cc *clientConn) openSession(hasPassword string) (userIdentity *auth.UserIdentity, port string, err error) {
 	var tlsStatePtr *tls.ConnectionState
 	if cc.tlsConn != nil {
 		tlsState := cc.tlsConn.ConnectionState()
 		tlsStatePtr = &tlsState
 	}
-	var err error
 	cc.ctx, err = cc.server.driver.OpenCtx(cc.connectionID, cc.capability, cc.collation, cc.dbname, tlsStatePtr)
 	if err != nil {
-		return err
+		return nil, "", err
 	}
 
 	if err = cc.server.checkConnectionCount(); err != nil {
-		return err
-	}
-	hasPassword := "YES"
-	if len(authData) == 0 {
-		hasPassword = "NO"
+		return nil, "", err
 	}
 	host, port, err := cc.PeerHost(hasPassword)
 	if err != nil {
-		return err
+		return nil, "", err
 	}
-	if !cc.ctx.Auth(&auth.UserIdentity{Username: cc.user, Hostname: host}, authData, cc.salt) {
-		return errAccessDenied.FastGenByArgs(cc.user, host, hasPassword)
+	if err = cc.server.connLimiter.acquireIP(cc, host, cc.server.cfg.MaxConnectionsPerIP); err != nil {
+		return nil, "", err
 	}
+	return &auth.UserIdentity{Username: cc.user, Hostname: host}, port, nil
+}
+
+// onAuthenticated prepares the session after the user is authenticated.
+func (cc *clientConn) onAuthenticated(port string) error {
 	cc.ctx.SetPort(port)
+	cc.ctx.GetSessionVars().ConnectAttrs = cc.attrs
+	if err := cc.checkAccountConnLimits(); err != nil {
+		return err
+	}
+	if cc.ctx.GetSessionVars().InSandBoxMode {
+		if cc.capability&clientCanHandleExpiredPasswords == 0 && variable.TiDBOptOn(variable.GetSysVar(variable.DisconnectOnExpiredPassword).Value) {
+			return errMustChangePasswordLogin.GenWithStackByArgs()
+		}
+		// Nothing but SET PASSWORD can be executed in the sandbox mode, so the default database is ignored.
+		cc.dbname = ""
+	}
 	if cc.dbname != "" {
-		err = cc.useDB(context.Background(), cc.dbname)
+		err := cc.useDB(context.Background(), cc.dbname)
 		if err != nil {
 			return err
 		}
@@ -739,12 +806,15 @@ func (cc *clientConn) PeerHost(hasPassword string) (host, port string, err error
 // skipInitConnect follows MySQL's rules of when init-connect should be skipped.
 // In 5.7 it is any user with SUPER privilege, but in 8.0 it is:
 // - SUPER or the CONNECTION_ADMIN dynamic privilege.
-// - (additional exception) users with expired passwords (not yet supported)
+// - (additional exception) users with expired passwords
 // In TiDB CONNECTION_ADMIN is satisfied by SUPER, so we only need to check once.
 func (cc *clientConn) skipInitConnect() bool {
+	if cc.ctx.GetSessionVars().InSandBoxMode {
+		return true
+	}
 	checker := privilege.GetPrivilegeManager(cc.ctx.Session)
 	activeRoles := cc.ctx.GetSessionVars().ActiveRoles
-	return checker != nil && checker.RequestDynamicVerification(activeRoles, "CONNECTION_ADMIN", false)
+	return checker != nil && checker.RequestDynamicVerification(activeRoles, privilege.ConnectionAdmin, false)
 }
 
 // initConnect runs the initConnect SQL statement if it has been specified.
@@ -858,7 +928,7 @@ func (cc *clientConn) Run(ctx context.Context) {
 		}
 
 		startTime := time.Now()
-		if err = cc.dispatch(ctx, data); err != nil {
+		if err = cc.dispatchInPool(ctx, data); err != nil {
 			if terror.ErrorEqual(err, io.EOF) {
 				cc.addMetrics(data[0], startTime, nil)
 				disconnectNormal.Inc()
@@ -887,7 +957,7 @@ func (cc *clientConn) Run(ctx context.Context) {
 			terror.Log(err1)
 		}
 		cc.addMetrics(data[0], startTime, err)
-		cc.pkt.sequence = 0
+		cc.pkt.resetSequence()
 	}
 }
 
@@ -996,6 +1066,41 @@ func (cc *clientConn) addMetrics(cmd byte, startTime time.Time, err error) {
 	}
 }
 
+// dispatchInPool dispatches the request in the worker pool of the server if it's enabled. Only the commands which
+// execute statements are queued, and the statements in transactions are dispatched directly, otherwise they may
+// wait for the workers blocked by their locks.
+func (cc *clientConn) dispatchInPool(ctx context.Context, data []byte) error {
+	if cc.server == nil || cc.server.workerPool == nil || cc.ctx == nil {
+		return cc.dispatch(ctx, data)
+	}
+	switch data[0] {
+	case mysql.ComQuery, mysql.ComStmtPrepare, mysql.ComStmtExecute, mysql.ComStmtFetch:
+	default:
+		return cc.dispatch(ctx, data)
+	}
+	sessVars := cc.ctx.GetSessionVars()
+	if sessVars.InTxn() {
+		return cc.dispatch(ctx, data)
+	}
+	priority := workerpool.PriorityMedium
+	switch resourcegroup.GlobalManager().PriorityOfGroup(sessVars.ResourceGroupName) {
+	case resourcegroup.PriorityLow:
+		priority = workerpool.PriorityLow
+	case resourcegroup.PriorityHigh:
+		priority = workerpool.PriorityHigh
+	}
+	err := cc.server.workerPool.Execute(priority, func() error {
+		return cc.dispatch(ctx, data)
+	})
+	switch err {
+	case workerpool.ErrQueueFull, workerpool.ErrQueueTimeout, workerpool.ErrPoolClosed:
+		// The request isn't dispatched, keep it for the log of the error.
+		cc.lastPacket = data
+		return errServerBusy.GenWithStackByArgs(err.Error())
+	}
+	return err
+}
+
 // dispatch handles client request based on command which is the first byte of the data.
 // It also gets a token from server which is used to limit the concurrently handling clients.
 // The most frequently used command is ComQuery.
@@ -1100,7 +1205,11 @@ func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
 			data = data[:len(data)-1]
 			dataStr = string(hack.String(data))
 		}
-		return cc.handleQuery(ctx, dataStr)
+		sql, err := cc.decodeQuery(data)
+		if err != nil {
+			return err
+		}
+		return cc.handleQuery(ctx, sql)
 	case mysql.ComFieldList:
 		return cc.handleFieldList(ctx, dataStr)
 	// ComCreateDB, ComDropDB
@@ -1121,7 +1230,11 @@ func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
 		return cc.handleChangeUser(ctx, data)
 	// ComBinlogDump, ComTableDump, ComConnectOut, ComRegisterSlave
 	case mysql.ComStmtPrepare:
-		return cc.handleStmtPrepare(ctx, dataStr)
+		sql, err := cc.decodeQuery(data)
+		if err != nil {
+			return err
+		}
+		return cc.handleStmtPrepare(ctx, sql)
 	case mysql.ComStmtExecute:
 		return cc.handleStmtExecute(ctx, data)
 	case mysql.ComStmtSendLongData:
@@ -1225,25 +1338,23 @@ func (cc *clientConn) writeOkWith(ctx context.Context, msg string, affectedRows,
 	return cc.flush(ctx)
 }
 
-func (cc *clientConn) writeError(ctx context.Context, e error) error {
-	var (
-		m  *mysql.SQLError
-		te *terror.Error
-		ok bool
-	)
+// toSQLError converts the error to the error sent to the clients.
+func toSQLError(e error) *mysql.SQLError {
 	originErr := errors.Cause(e)
-	if te, ok = originErr.(*terror.Error); ok {
-		m = terror.ToSQLError(te)
-	} else {
-		e := errors.Cause(originErr)
-		switch y := e.(type) {
-		case *terror.Error:
-			m = terror.ToSQLError(y)
-		default:
-			m = mysql.NewErrf(mysql.ErrUnknown, "%s", nil, e.Error())
-		}
+	if te, ok := originErr.(*terror.Error); ok {
+		return terror.ToSQLError(te)
 	}
+	e = errors.Cause(originErr)
+	switch y := e.(type) {
+	case *terror.Error:
+		return terror.ToSQLError(y)
+	default:
+		return mysql.NewErrf(mysql.ErrUnknown, "%s", nil, e.Error())
+	}
+}
 
+func (cc *clientConn) writeError(ctx context.Context, e error) error {
+	m := toSQLError(e)
 	cc.lastCode = m.Code
 	defer errno.IncrementError(m.Code, cc.user, cc.peerHost)
 	data := cc.alloc.AllocWithLen(4, 16+len(m.Message))
@@ -1324,7 +1435,9 @@ func processStream(ctx context.Context, cc *clientConn, loadDataInfo *executor.L
 	var err error
 	var shouldBreak bool
 	var prevData, curData []byte
+	src := newLocalInfileSource(cc, loadDataInfo.Ctx.GetSessionVars().LoadDataLocalCompression)
 	defer func() {
+		src.Close()
 		r := recover()
 		if r != nil {
 			logutil.Logger(ctx).Error("process routine panicked",
@@ -1339,15 +1452,16 @@ func processStream(ctx context.Context, cc *clientConn, loadDataInfo *executor.L
 		wg.Done()
 	}()
 	for {
-		curData, err = cc.readPacket()
+		curData, err = src.next()
+		loadDataInfo.Drained = src.drained()
 		if err != nil {
 			if terror.ErrorNotEqual(err, io.EOF) {
-				logutil.Logger(ctx).Error("read packet failed", zap.Error(err))
+				logutil.Logger(ctx).Error("read local infile failed", zap.Error(err))
 				break
 			}
+			err = nil
 		}
 		if len(curData) == 0 {
-			loadDataInfo.Drained = true
 			shouldBreak = true
 			if len(prevData) == 0 {
 				break
@@ -1505,6 +1619,27 @@ func (cc *clientConn) handleIndexAdvise(ctx context.Context, indexAdviseInfo *ex
 	return nil
 }
 
+// decodeQuery decodes the query text sent by the client in character_set_client. The text is kept
+// as it is if the character set is compatible with UTF-8, it's checked by the parser then.
+func (cc *clientConn) decodeQuery(data []byte) (string, error) {
+	vars := cc.ctx.GetSessionVars()
+	cs, ok := vars.GetSystemVar(variable.CharacterSetClient)
+	if !ok || encoding.IsUTF8Compatible(cs) {
+		return string(hack.String(data)), nil
+	}
+	conv, err := encoding.NewConverter(charset.CharsetBin, cs)
+	if err != nil {
+		return "", err
+	}
+	// The invalid characters are replaced silently unless tidb_invalid_char_handling is ERROR, since the
+	// warnings of the statement haven't been initialized yet.
+	sql, _, err := conv.Convert(nil, data, vars.InvalidCharHandling)
+	if err != nil {
+		return "", err
+	}
+	return string(hack.String(sql)), nil
+}
+
 // handleQuery executes the sql query string and writes result set or result ok to the client.
 // As the execution time of this function represents the performance of TiDB, we do time log and metrics here.
 // There is a special query `load data` that does not return result, which is handled differently.
@@ -1690,7 +1825,11 @@ func (cc *clientConn) prefetchPointPlanKeys(ctx context.Context, stmts []ast.Stm
 
 // The first return value indicates whether the call of handleStmt has no side effect and can be retried.
 // Currently the first return value is used to fallback to TiKV when TiFlash is down.
-func (cc *clientConn) handleStmt(ctx context.Context, stmt ast.StmtNode, warns []stmtctx.SQLWarn, lastStmt bool) (bool, error) {
+func (cc *clientConn) handleStmt(ctx context.Context, stmt ast.StmtNode, warns []stmtctx.SQLWarn, lastStmt bool) (_ bool, err error) {
+	// The result set is closed before the statement is audited.
+	defer func() {
+		cc.auditStmt(stmt, err)
+	}()
 	ctx = context.WithValue(ctx, execdetails.StmtExecDetailKey, &execdetails.StmtExecDetails{})
 	ctx = context.WithValue(ctx, util.ExecDetailsKey, &util.ExecDetails{})
 	reg := trace.StartRegion(ctx, "ExecuteStmt")
@@ -1720,7 +1859,7 @@ func (cc *clientConn) handleStmt(ctx context.Context, stmt ast.StmtNode, warns [
 			return false, executor.ErrQueryInterrupted
 		}
 
-		retryable, err := cc.writeResultset(ctx, rs, false, status, 0)
+		retryable, err := cc.writeResultset(ctx, rs, nil, status, 0)
 		if err != nil {
 			return retryable, err
 		}
@@ -1739,6 +1878,14 @@ func (cc *clientConn) handleStmt(ctx context.Context, stmt ast.StmtNode, warns [
 	return false, nil
 }
 
+// auditStmt writes the event of the statement just executed to the audit log.
+func (cc *clientConn) auditStmt(stmt ast.StmtNode, err error) {
+	if !audit.Enabled() || cc.ctx == nil {
+		return
+	}
+	audit.Log(audit.NewStmtEvent(cc.connectionID, cc.ctx.GetSessionVars(), stmt, err))
+}
+
 func (cc *clientConn) handleQuerySpecial(ctx context.Context, status uint16) (bool, error) {
 	handled := false
 	loadDataInfo := cc.ctx.Value(executor.LoadDataVarKey)
@@ -1799,13 +1946,13 @@ func (cc *clientConn) handleFieldList(ctx context.Context, sql string) (err erro
 }
 
 // writeResultset writes data into a resultset and uses rs.Next to get row data back.
-// If binary is true, the data would be encoded in BINARY format.
+// If stmt isn't nil, the resultset is of the prepared statement and the data would be encoded in BINARY format.
 // serverStatus, a flag bit represents server information.
 // fetchSize, the desired number of rows to be fetched each time when client uses cursor.
 // retryable indicates whether the call of writeResultset has no side effect and can be retried to correct error. The call
 // has side effect in cursor mode or once data has been sent to client. Currently retryable is used to fallback to TiKV when
 // TiFlash is down.
-func (cc *clientConn) writeResultset(ctx context.Context, rs ResultSet, binary bool, serverStatus uint16, fetchSize int) (retryable bool, runErr error) {
+func (cc *clientConn) writeResultset(ctx context.Context, rs ResultSet, stmt PreparedStatement, serverStatus uint16, fetchSize int) (retryable bool, runErr error) {
 	defer func() {
 		// close ResultSet when cursor doesn't exist
 		r := recover()
@@ -1823,11 +1970,13 @@ func (cc *clientConn) writeResultset(ctx context.Context, rs ResultSet, binary b
 		logutil.Logger(ctx).Error("write query result panic", zap.Stringer("lastSQL", getLastStmtInConn{cc}), zap.String("stack", string(buf)))
 	}()
 	var err error
+	written := cc.bytesWritten()
 	if mysql.HasCursorExistsFlag(serverStatus) {
 		err = cc.writeChunksWithFetchSize(ctx, rs, serverStatus, fetchSize)
 	} else {
-		retryable, err = cc.writeChunks(ctx, rs, binary, serverStatus)
+		retryable, err = cc.writeChunks(ctx, rs, stmt, serverStatus)
 	}
+	cc.recordRespBytes(ctx, cc.bytesWritten()-written)
 	if err != nil {
 		return retryable, err
 	}
@@ -1835,29 +1984,93 @@ func (cc *clientConn) writeResultset(ctx context.Context, rs ResultSet, binary b
 	return false, cc.flush(ctx)
 }
 
-func (cc *clientConn) writeColumnInfo(columns []*ColumnInfo, serverStatus uint16) error {
+// bytesWritten returns the number of bytes written to the client by the connection.
+func (cc *clientConn) bytesWritten() uint64 {
+	if cc.pkt == nil {
+		return 0
+	}
+	return cc.pkt.bytesWritten
+}
+
+// recordRespBytes accounts the bytes of a result set written to the client in the details of the statement, which
+// are shown in the slow log and the statement summary, and in the metrics.
+func (cc *clientConn) recordRespBytes(ctx context.Context, n uint64) {
+	if stmtDetail, ok := ctx.Value(execdetails.StmtExecDetailKey).(*execdetails.StmtExecDetails); ok {
+		stmtDetail.WriteSQLRespBytes += n
+	}
+	sqlType := cc.ctx.GetSessionVars().StmtCtx.StmtType
+	if sqlType == "" {
+		sqlType = metrics.LblGeneral
+	}
+	metrics.StmtResponseBytesHistogram.WithLabelValues(sqlType).Observe(float64(n))
+}
+
+const (
+	// clientOptionalResultsetMetadata is the capability to skip the column definitions of the result sets, which is
+	// added in MySQL 8.0.3. The column count is followed by a flag telling whether the definitions follow.
+	clientOptionalResultsetMetadata uint32 = 1 << 25
+
+	resultsetMetadataNone byte = 0
+	resultsetMetadataFull byte = 1
+)
+
+// sendResultsetMetadata checks whether the column definitions should be sent. If the client supports
+// CLIENT_OPTIONAL_RESULTSET_METADATA, they're skipped if resultset_metadata is NONE, and the definitions of the
+// prepared statement are skipped if they're the same as the ones sent in the response of the preparation, which the
+// client always holds.
+func (cc *clientConn) sendResultsetMetadata(columns []*ColumnInfo, stmt PreparedStatement) bool {
+	if cc.capability&clientOptionalResultsetMetadata == 0 {
+		return true
+	}
+	if cc.ctx.GetSessionVars().SkipResultsetMetadata {
+		return false
+	}
+	if stmt == nil {
+		return true
+	}
+	var dumped []byte
+	for _, column := range columns {
+		dumped = column.Dump(dumped)
+	}
+	return !bytes.Equal(dumped, stmt.GetSentColumns())
+}
+
+// writeColumnInfo writes the column count and the column definitions, stmt is the prepared statement of the result
+// set, nil if it's a text result set.
+func (cc *clientConn) writeColumnInfo(columns []*ColumnInfo, stmt PreparedStatement, serverStatus uint16) error {
+	sendMetadata := cc.sendResultsetMetadata(columns, stmt)
 	data := cc.alloc.AllocWithLen(4, 1024)
 	data = dumpLengthEncodedInt(data, uint64(len(columns)))
+	if cc.capability&clientOptionalResultsetMetadata > 0 {
+		if sendMetadata {
+			data = append(data, resultsetMetadataFull)
+		} else {
+			data = append(data, resultsetMetadataNone)
+		}
+	}
 	if err := cc.writePacket(data); err != nil {
 		return err
 	}
-	for _, v := range columns {
-		data = data[0:4]
-		data = v.Dump(data)
-		if err := cc.writePacket(data); err != nil {
-			return err
+	if sendMetadata {
+		for _, v := range columns {
+			data = data[0:4]
+			data = v.Dump(data)
+			if err := cc.writePacket(data); err != nil {
+				return err
+			}
 		}
 	}
 	return cc.writeEOF(serverStatus)
 }
 
 // writeChunks writes data from a Chunk, which filled data by a ResultSet, into a connection.
-// binary specifies the way to dump data. It throws any error while dumping data.
+// The data is dumped in BINARY format if stmt isn't nil. It throws any error while dumping data.
 // serverStatus, a flag bit represents server information
 // The first return value indicates whether error occurs at the first call of ResultSet.Next.
-func (cc *clientConn) writeChunks(ctx context.Context, rs ResultSet, binary bool, serverStatus uint16) (bool, error) {
+func (cc *clientConn) writeChunks(ctx context.Context, rs ResultSet, stmt PreparedStatement, serverStatus uint16) (bool, error) {
 	data := cc.alloc.AllocWithLen(4, 1024)
 	req := rs.NewChunk()
+	encoder := newResultEncoder(cc.ctx.GetSessionVars())
 	gotColumnInfo := false
 	firstNext := true
 	var stmtDetail *execdetails.StmtExecDetails
@@ -1887,7 +2100,7 @@ func (cc *clientConn) writeChunks(ctx context.Context, rs ResultSet, binary bool
 			// We need to call Next before we get columns.
 			// Otherwise, we will get incorrect columns info.
 			columns := rs.Columns()
-			err = cc.writeColumnInfo(columns, serverStatus)
+			err = cc.writeColumnInfo(columns, stmt, serverStatus)
 			if err != nil {
 				return false, err
 			}
@@ -1901,10 +2114,10 @@ func (cc *clientConn) writeChunks(ctx context.Context, rs ResultSet, binary bool
 		start := time.Now()
 		for i := 0; i < rowCount; i++ {
 			data = data[0:4]
-			if binary {
-				data, err = dumpBinaryRow(data, rs.Columns(), req.GetRow(i))
+			if stmt != nil {
+				data, err = dumpBinaryRow(data, rs.Columns(), req.GetRow(i), encoder)
 			} else {
-				data, err = dumpTextRow(data, rs.Columns(), req.GetRow(i))
+				data, err = dumpTextRow(data, rs.Columns(), req.GetRow(i), encoder)
 			}
 			if err != nil {
 				reg.End()
@@ -1976,10 +2189,11 @@ func (cc *clientConn) writeChunksWithFetchSize(ctx context.Context, rs ResultSet
 		stmtDetail = stmtDetailRaw.(*execdetails.StmtExecDetails)
 	}
 	start := time.Now()
+	encoder := newResultEncoder(cc.ctx.GetSessionVars())
 	var err error
 	for _, row := range curRows {
 		data = data[0:4]
-		data, err = dumpBinaryRow(data, rs.Columns(), row)
+		data, err = dumpBinaryRow(data, rs.Columns(), row, encoder)
 		if err != nil {
 			return err
 		}
@@ -1990,9 +2204,6 @@ func (cc *clientConn) writeChunksWithFetchSize(ctx context.Context, rs ResultSet
 	if stmtDetail != nil {
 		stmtDetail.WriteSQLRespDuration += time.Since(start)
 	}
-	if cl, ok := rs.(fetchNotifier); ok {
-		cl.OnFetchReturned()
-	}
 	return cc.writeEOF(serverStatus)
 }
 
@@ -2030,14 +2241,22 @@ func (cc *clientConn) handleChangeUser(ctx context.Context, data []byte) error {
 	}
 	pass := data[:passLen]
 	data = data[passLen:]
-	dbName, _ := parseNullTermString(data)
+	dbName, data := parseNullTermString(data)
 	cc.dbname = string(hack.String(dbName))
+	authPlugin := mysql.AuthNativePassword
+	// The database is followed by the charset and the authentication plugin of the auth data.
+	if cc.capability&mysql.ClientPluginAuth > 0 && len(data) > 2 {
+		if plugin, _ := parseNullTermString(data[2:]); len(plugin) > 0 {
+			authPlugin = string(plugin)
+		}
+	}
 
 	err := cc.ctx.Close()
 	if err != nil {
 		logutil.Logger(ctx).Debug("close old context failed", zap.Error(err))
 	}
-	err = cc.openSessionAndDoAuth(pass)
+	err = cc.openSessionAndDoAuth(pass, authPlugin)
+	cc.auditConnEvent(audit.TypeChangeUser, 0, err)
 	if err != nil {
 		return err
 	}
@@ -2045,6 +2264,14 @@ func (cc *clientConn) handleChangeUser(ctx context.Context, data []byte) error {
 }
 
 func (cc *clientConn) handleResetConnection(ctx context.Context) error {
+	if err := cc.resetSession(ctx); err != nil {
+		return err
+	}
+	return cc.handleCommonConnectionReset(ctx)
+}
+
+// resetSession replaces the session with a new one of the same user and the current database.
+func (cc *clientConn) resetSession(ctx context.Context) error {
 	user := cc.ctx.GetSessionVars().User
 	err := cc.ctx.Close()
 	if err != nil {
@@ -2069,8 +2296,7 @@ func (cc *clientConn) handleResetConnection(ctx context.Context) error {
 		}
 	}
 	cc.ctx.SetSessionManager(cc.server)
-
-	return cc.handleCommonConnectionReset(ctx)
+	return nil
 }
 
 func (cc *clientConn) handleCommonConnectionReset(ctx context.Context) error {
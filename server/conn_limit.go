@@ -0,0 +1,129 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// connLimiter counts the connections of the client IPs and the accounts, and rejects the new connections exceeding
+// max-connections-per-ip, MAX_USER_CONNECTIONS or MAX_CONNECTIONS_PER_HOUR. The zero value is ready to use.
+type connLimiter struct {
+	sync.Mutex
+	ips      map[string]uint32
+	accounts map[string]*accountConns
+}
+
+// accountConns is the connection statistics of an account, which is the matched record of mysql.user.
+type accountConns struct {
+	active    uint64
+	hourStart time.Time
+	hourCount uint64
+}
+
+// acquireIP counts the connection of the client IP, it fails if the IP already has max connections. The connection
+// is counted once, even if the session is reopened by COM_CHANGE_USER.
+func (l *connLimiter) acquireIP(cc *clientConn, ip string, max uint32) error {
+	if max == 0 || cc.server.isUnixSocket() {
+		return nil
+	}
+	l.Lock()
+	defer l.Unlock()
+	if cc.limitedIP != "" {
+		return nil
+	}
+	if l.ips[ip] >= max {
+		metrics.ConnRejectedCounter.WithLabelValues(metrics.LblMaxConnectionsPerIP).Inc()
+		logutil.BgLogger().Warn("too many connections from the host", zap.String("host", ip), zap.Uint32("max connections", max))
+		return errTooManyConnsFromHost.FastGenByArgs(ip)
+	}
+	if l.ips == nil {
+		l.ips = make(map[string]uint32)
+	}
+	l.ips[ip]++
+	cc.limitedIP = ip
+	return nil
+}
+
+// acquireAccount counts the connection of the account, it fails if the account already has MAX_USER_CONNECTIONS
+// connections or has connected MAX_CONNECTIONS_PER_HOUR times in the current hour.
+func (l *connLimiter) acquireAccount(cc *clientConn, account, user string, maxUserConns, maxConnsPerHour uint64, now time.Time) error {
+	l.Lock()
+	defer l.Unlock()
+	l.releaseAccount(cc)
+	if l.accounts == nil {
+		l.accounts = make(map[string]*accountConns)
+	}
+	conns, ok := l.accounts[account]
+	if !ok {
+		conns = &accountConns{hourStart: now}
+		l.accounts[account] = conns
+	}
+	if now.Sub(conns.hourStart) >= time.Hour {
+		conns.hourStart, conns.hourCount = now, 0
+	}
+	if maxUserConns > 0 && conns.active >= maxUserConns {
+		metrics.ConnRejectedCounter.WithLabelValues(metrics.LblMaxUserConnections).Inc()
+		return errTooManyUserConnections.FastGenByArgs(user)
+	}
+	if maxConnsPerHour > 0 && conns.hourCount >= maxConnsPerHour {
+		metrics.ConnRejectedCounter.WithLabelValues(metrics.LblMaxConnectionsPerHour).Inc()
+		return errUserLimitReached.FastGenByArgs(user, "max_connections_per_hour", maxConnsPerHour)
+	}
+	conns.active++
+	conns.hourCount++
+	cc.limitedAccount = account
+	return nil
+}
+
+// release uncounts the connection from its client IP and account.
+func (l *connLimiter) release(cc *clientConn) {
+	l.Lock()
+	defer l.Unlock()
+	if cc.limitedIP != "" {
+		if l.ips[cc.limitedIP]--; l.ips[cc.limitedIP] == 0 {
+			delete(l.ips, cc.limitedIP)
+		}
+		cc.limitedIP = ""
+	}
+	l.releaseAccount(cc)
+}
+
+func (l *connLimiter) releaseAccount(cc *clientConn) {
+	if cc.limitedAccount == "" {
+		return
+	}
+	if conns, ok := l.accounts[cc.limitedAccount]; ok && conns.active > 0 {
+		conns.active--
+	}
+	cc.limitedAccount = ""
+}
+
+// checkAccountConnLimits checks the connection limits of the account authenticated by the connection.
+func (cc *clientConn) checkAccountConnLimits() error {
+	user := cc.ctx.GetSessionVars().User
+	pm := privilege.GetPrivilegeManager(cc.ctx.Session)
+	if user == nil || pm == nil {
+		return nil
+	}
+	maxUserConns, maxConnsPerHour := pm.GetConnectionLimits(user.AuthUsername, user.AuthHostname)
+	account := user.AuthUsername + "@" + user.AuthHostname
+	return cc.server.connLimiter.acquireAccount(cc, account, user.AuthUsername, maxUserConns, maxConnsPerHour, time.Now())
+}
@@ -49,6 +49,7 @@ import (
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/metrics"
 	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/server/audit"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	tikvstore "github.com/pingcap/tidb/store/tikv/kv"
 	"github.com/pingcap/tidb/store/tikv/util"
@@ -76,18 +77,29 @@ func (cc *clientConn) handleStmtPrepare(ctx context.Context, sql string) error {
 	data = append(data, 0)
 	// warning count
 	data = append(data, 0, 0) // TODO support warning count
+	sendMetadata := true
+	if cc.capability&clientOptionalResultsetMetadata > 0 {
+		sendMetadata = !cc.ctx.GetSessionVars().SkipResultsetMetadata
+		if sendMetadata {
+			data = append(data, resultsetMetadataFull)
+		} else {
+			data = append(data, resultsetMetadataNone)
+		}
+	}
 
 	if err := cc.writePacket(data); err != nil {
 		return err
 	}
 
 	if len(params) > 0 {
-		for i := 0; i < len(params); i++ {
-			data = data[0:4]
-			data = params[i].Dump(data)
+		if sendMetadata {
+			for i := 0; i < len(params); i++ {
+				data = data[0:4]
+				data = params[i].Dump(data)
 
-			if err := cc.writePacket(data); err != nil {
-				return err
+				if err := cc.writePacket(data); err != nil {
+					return err
+				}
 			}
 		}
 
@@ -97,13 +109,19 @@ func (cc *clientConn) handleStmtPrepare(ctx context.Context, sql string) error {
 	}
 
 	if len(columns) > 0 {
-		for i := 0; i < len(columns); i++ {
-			data = data[0:4]
-			data = columns[i].Dump(data)
-
-			if err := cc.writePacket(data); err != nil {
-				return err
+		if sendMetadata {
+			var sentColumns []byte
+			for i := 0; i < len(columns); i++ {
+				data = data[0:4]
+				data = columns[i].Dump(data)
+				sentColumns = append(sentColumns, data[4:]...)
+
+				if err := cc.writePacket(data); err != nil {
+					return err
+				}
 			}
+			// The client holds the definitions, they're sent again on execution only if they're changed.
+			stmt.StoreSentColumns(sentColumns)
 		}
 
 		if err := cc.writeEOF(0); err != nil {
@@ -210,9 +228,20 @@ func (cc *clientConn) handleStmtExecute(ctx context.Context, data []byte) (err e
 		// We append warning after the retry because `ResetContextOfStmt` may be called during the retry, which clears warnings.
 		cc.ctx.GetSessionVars().StmtCtx.AppendError(prevErr)
 	}
+	cc.auditPreparedStmt(uint32(stmt.ID()), err)
 	return err
 }
 
+// auditPreparedStmt writes the event of the prepared statement just executed to the audit log.
+func (cc *clientConn) auditPreparedStmt(stmtID uint32, err error) {
+	if !audit.Enabled() {
+		return
+	}
+	if prepared, ok := cc.ctx.GetSessionVars().PreparedStmts[stmtID].(*plannercore.CachedPrepareStmt); ok {
+		cc.auditStmt(prepared.PreparedAst.Stmt, err)
+	}
+}
+
 // The first return value indicates whether the call of executePreparedStmtAndWriteResult has no side effect and can be retried.
 // Currently the first return value is used to fallback to TiKV when TiFlash is down.
 func (cc *clientConn) executePreparedStmtAndWriteResult(ctx context.Context, stmt PreparedStatement, args []types.Datum, useCursor bool) (bool, error) {
@@ -228,19 +257,22 @@ func (cc *clientConn) executePreparedStmtAndWriteResult(ctx context.Context, stm
 	// we should hold the ResultSet in PreparedStatement for next stmt_fetch, and only send back ColumnInfo.
 	// Tell the client cursor exists in server by setting proper serverStatus.
 	if useCursor {
-		stmt.StoreResultSet(rs)
-		err = cc.writeColumnInfo(rs.Columns(), mysql.ServerStatusCursorExists)
+		// The rows are read into the cursor now, so the executor isn't kept running between the fetches. Nothing is
+		// sent to the client if it fails, so it can be retried.
+		crs, err := newCursorResultSet(ctx, cc.ctx.GetSessionVars(), rs)
 		if err != nil {
-			return false, err
+			return true, errors.Annotate(err, cc.preparedStmt2String(uint32(stmt.ID())))
 		}
-		if cl, ok := rs.(fetchNotifier); ok {
-			cl.OnFetchReturned()
+		stmt.StoreResultSet(crs)
+		err = cc.writeColumnInfo(crs.Columns(), stmt, mysql.ServerStatusCursorExists)
+		if err != nil {
+			return false, err
 		}
 		// explicitly flush columnInfo to client.
 		return false, cc.flush(ctx)
 	}
 	defer terror.Call(rs.Close)
-	retryable, err := cc.writeResultset(ctx, rs, true, 0, 0)
+	retryable, err := cc.writeResultset(ctx, rs, stmt, 0, 0)
 	if err != nil {
 		return retryable, errors.Annotate(err, cc.preparedStmt2String(uint32(stmt.ID())))
 	}
@@ -276,7 +308,7 @@ func (cc *clientConn) handleStmtFetch(ctx context.Context, data []byte) (err err
 			strconv.FormatUint(uint64(stmtID), 10), "stmt_fetch_rs"), cc.preparedStmt2String(stmtID))
 	}
 
-	_, err = cc.writeResultset(ctx, rs, true, mysql.ServerStatusCursorExists, int(fetchSize))
+	_, err = cc.writeResultset(ctx, rs, stmt, mysql.ServerStatusCursorExists, int(fetchSize))
 	if err != nil {
 		return errors.Annotate(err, cc.preparedStmt2String(stmtID))
 	}
@@ -18,19 +18,28 @@ import (
 	"bytes"
 	"context"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/sessionstates"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/store/mockstore/unistore"
@@ -39,6 +48,7 @@ import (
 	"github.com/pingcap/tidb/util/arena"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testleak"
+	dto "github.com/prometheus/client_model/go"
 )
 
 type ConnTestSuite struct {
@@ -748,6 +758,58 @@ func testGetTableByName(c *C, ctx sessionctx.Context, db, table string) table.Ta
 	return tbl
 }
 
+func (ts *ConnTestSuite) TestCursorSpillToDisk(c *C) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.OOMUseTmpStorage = true
+		conf.OOMAction = config.OOMActionLog
+	})
+	cc := &clientConn{
+		alloc: arena.NewAllocator(1024),
+		pkt: &packetIO{
+			bufWriter: bufio.NewWriter(bytes.NewBuffer(nil)),
+		},
+	}
+	tk := testkit.NewTestKitWithInit(c, ts.store)
+	cc.ctx = &TiDBContext{Session: tk.Se, stmts: make(map[int]*TiDBStatement)}
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b varchar(255))")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values(%d, repeat('a', 255))", i))
+	}
+	tk.MustExec("insert into t select a + 100, b from t")
+	tk.MustExec("insert into t select a + 200, b from t")
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+	tk.MustExec("set @@tidb_mem_quota_query = 4096")
+
+	openCursors := func() float64 {
+		m := &dto.Metric{}
+		c.Assert(metrics.CursorGauge.Write(m), IsNil)
+		return m.GetGauge().GetValue()
+	}
+	cursorsBefore := openCursors()
+	ctx := context.Background()
+	c.Assert(cc.handleStmtPrepare(ctx, "select * from t"), IsNil)
+	c.Assert(cc.handleStmtExecute(ctx, []byte{0x1, 0x0, 0x0, 0x0, 0x1, 0x1, 0x0, 0x0, 0x0}), IsNil)
+	crs := cc.ctx.GetStatement(1).GetResultSet().(*cursorResultSet)
+	c.Assert(crs.rowContainer.NumRow(), Equals, 400)
+	c.Assert(openCursors(), Equals, cursorsBefore+1)
+	// The rows exceed the quota of a query, so they're spilled to disk in the background.
+	for i := 0; i < 100 && !crs.rowContainer.AlreadySpilledSafeForTest(); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Assert(crs.rowContainer.AlreadySpilledSafeForTest(), IsTrue)
+
+	// The rows are fetched from disk, and the cursor is closed after all of them are fetched.
+	for i := 0; i < 4; i++ {
+		c.Assert(cc.handleStmtFetch(ctx, []byte{0x1, 0x0, 0x0, 0x0, 0x64, 0x0, 0x0, 0x0}), IsNil)
+		c.Assert(atomic.LoadInt32(&crs.closed), Equals, int32(0))
+	}
+	c.Assert(cc.handleStmtFetch(ctx, []byte{0x1, 0x0, 0x0, 0x0, 0x64, 0x0, 0x0, 0x0}), IsNil)
+	c.Assert(atomic.LoadInt32(&crs.closed), Equals, int32(1))
+	c.Assert(openCursors(), Equals, cursorsBefore)
+}
+
 func (ts *ConnTestSuite) TestTiFlashFallback(c *C) {
 	cc := &clientConn{
 		alloc: arena.NewAllocator(1024),
@@ -777,9 +839,9 @@ func (ts *ConnTestSuite) TestTiFlashFallback(c *C) {
 	c.Assert(cc.handleStmtPrepare(ctx, "select sum(a) from t"), IsNil)
 	c.Assert(cc.handleStmtExecute(ctx, []byte{0x1, 0x0, 0x0, 0x0, 0x0, 0x1, 0x0, 0x0, 0x0}), IsNil)
 	tk.MustQuery("show warnings").Check(testkit.Rows("Error 9012 TiFlash server timeout"))
-	// test COM_STMT_FETCH (cursor mode)
+	// test COM_STMT_FETCH (cursor mode), the rows are read on execution so it falls back to TiKV too
 	c.Assert(cc.handleStmtExecute(ctx, []byte{0x1, 0x0, 0x0, 0x0, 0x1, 0x1, 0x0, 0x0, 0x0}), IsNil)
-	c.Assert(cc.handleStmtFetch(ctx, []byte{0x1, 0x0, 0x0, 0x0, 0x1, 0x0, 0x0, 0x0}), NotNil)
+	c.Assert(cc.handleStmtFetch(ctx, []byte{0x1, 0x0, 0x0, 0x0, 0x1, 0x0, 0x0, 0x0}), IsNil)
 	tk.MustExec("set @@tidb_allow_fallback_to_tikv=''")
 	c.Assert(cc.handleStmtExecute(ctx, []byte{0x1, 0x0, 0x0, 0x0, 0x0, 0x1, 0x0, 0x0, 0x0}), NotNil)
 	c.Assert(failpoint.Disable("github.com/pingcap/tidb/store/mockstore/unistore/BatchCopRpcErrtiflash0"), IsNil)
@@ -838,3 +900,258 @@ func testFallbackWork(c *C, tk *testkit.TestKit, cc *clientConn, sql string) {
 	c.Assert(cc.handleQuery(ctx, sql), IsNil)
 	tk.MustQuery("show warnings").Check(testkit.Rows("Error 9012 TiFlash server timeout"))
 }
+
+func (ts *ConnTestSuite) TestConnectAttrs(c *C) {
+	cfg := newTestConfig()
+	cfg.Port, cfg.Status.StatusPort = 0, 0
+	cfg.Status.ReportStatus = false
+	server, err := NewServer(cfg, NewTiDBDriver(ts.store))
+	c.Assert(err, IsNil)
+	defer server.Close()
+	cc := &clientConn{
+		connectionID: 1,
+		server:       server,
+		user:         "root",
+		peerHost:     "localhost",
+		collation:    mysql.DefaultCollationID,
+		alloc:        arena.NewAllocator(512),
+		attrs: map[string]string{
+			"program_name":    "mysql",
+			"_client_name":    "libmysql",
+			"_client_version": "8.0.23",
+		},
+	}
+	c.Assert(cc.openSessionAndDoAuth(nil, mysql.AuthNativePassword), IsNil)
+	defer cc.ctx.Close()
+	server.rwlock.Lock()
+	server.clients[cc.connectionID] = cc
+	server.rwlock.Unlock()
+	c.Assert(cc.ctx.GetSessionVars().ConnectAttrs, DeepEquals, cc.attrs)
+
+	tk := testkit.NewTestKit(c, ts.store)
+	tk.Se = cc.ctx.Session
+	tk.MustQuery("select program_name, client_name, client_version from information_schema.processlist where id = connection_id()").
+		Check(testkit.Rows("mysql libmysql 8.0.23"))
+	tk.MustQuery("select attr_name, attr_value, ordinal_position from performance_schema.session_connect_attrs where processlist_id = connection_id()").
+		Check(testkit.Rows("_client_name libmysql 0", "_client_version 8.0.23 1", "program_name mysql 2"))
+}
+
+func (ts *ConnTestSuite) TestConnectionLimits(c *C) {
+	tk := testkit.NewTestKitWithInit(c, ts.store)
+	tk.MustExec("create user 'conn_limit_user'@'%' with max_user_connections 1 max_connections_per_hour 2")
+	defer tk.MustExec("drop user 'conn_limit_user'@'%'")
+
+	cfg := newTestConfig()
+	cfg.Port, cfg.Status.StatusPort = 0, 0
+	cfg.Status.ReportStatus = false
+	cfg.MaxConnectionsPerIP = 2
+	server, err := NewServer(cfg, NewTiDBDriver(ts.store))
+	c.Assert(err, IsNil)
+	defer server.Close()
+	connID := uint64(0)
+	connect := func(user, host string) (*clientConn, error) {
+		connID++
+		cc := &clientConn{
+			connectionID: connID,
+			server:       server,
+			user:         user,
+			peerHost:     host,
+			collation:    mysql.DefaultCollationID,
+			alloc:        arena.NewAllocator(512),
+			bufReadConn:  newBufferedReadConn(&bytesConn{}),
+		}
+		return cc, cc.openSessionAndDoAuth(nil, mysql.AuthNativePassword)
+	}
+
+	// MAX_USER_CONNECTIONS limits the active connections of the user.
+	cc1, err := connect("conn_limit_user", "10.0.0.1")
+	c.Assert(err, IsNil)
+	cc2, err := connect("conn_limit_user", "10.0.0.2")
+	c.Assert(err, ErrorMatches, ".*User conn_limit_user already has more than 'maxUserConnections' active connections")
+	c.Assert(cc2.Close(), IsNil)
+	c.Assert(cc1.Close(), IsNil)
+
+	// MAX_CONNECTIONS_PER_HOUR limits the connections of the user in an hour.
+	cc1, err = connect("conn_limit_user", "10.0.0.1")
+	c.Assert(err, IsNil)
+	c.Assert(cc1.Close(), IsNil)
+	cc1, err = connect("conn_limit_user", "10.0.0.1")
+	c.Assert(err, ErrorMatches, ".*User 'conn_limit_user' has exceeded the 'max_connections_per_hour' resource \\(current value: 2\\)")
+	c.Assert(cc1.Close(), IsNil)
+	server.connLimiter.accounts["conn_limit_user@%"].hourStart = time.Now().Add(-time.Hour)
+	cc1, err = connect("conn_limit_user", "10.0.0.1")
+	c.Assert(err, IsNil)
+	c.Assert(cc1.Close(), IsNil)
+
+	// max-connections-per-ip limits the connections from the same IP.
+	cc1, err = connect("root", "10.0.0.1")
+	c.Assert(err, IsNil)
+	cc2, err = connect("root", "10.0.0.1")
+	c.Assert(err, IsNil)
+	cc3, err := connect("root", "10.0.0.1")
+	c.Assert(err, ErrorMatches, ".*Host '10.0.0.1' already has more than 'max-connections-per-ip' active connections")
+	c.Assert(cc3.Close(), IsNil)
+	cc3, err = connect("root", "10.0.0.2")
+	c.Assert(err, IsNil)
+	c.Assert(cc3.Close(), IsNil)
+	c.Assert(cc2.Close(), IsNil)
+	cc2, err = connect("root", "10.0.0.1")
+	c.Assert(err, IsNil)
+	c.Assert(cc2.Close(), IsNil)
+	c.Assert(cc1.Close(), IsNil)
+	c.Assert(server.connLimiter.ips, HasLen, 0)
+}
+
+func (ts *ConnTestSuite) TestAuthSessionToken(c *C) {
+	defer config.RestoreFunc()()
+	dir, err := ioutil.TempDir("", "session-token")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+	keyPath := filepath.Join(dir, "key")
+	c.Assert(ioutil.WriteFile(keyPath, []byte("secret"), 0600), IsNil)
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Security.SessionTokenSigningKey = keyPath
+	})
+
+	tk := testkit.NewTestKitWithInit(c, ts.store)
+	tk.MustExec("drop user if exists 'token_user'@'%'")
+	tk.MustExec("create user 'token_user'@'%' identified by 'pwd'")
+	defer tk.MustExec("drop user 'token_user'@'%'")
+
+	cfg := newTestConfig()
+	cfg.Port, cfg.Status.StatusPort = 0, 0
+	cfg.Status.ReportStatus = false
+	server, err := NewServer(cfg, NewTiDBDriver(ts.store))
+	c.Assert(err, IsNil)
+	defer server.Close()
+	cc := &clientConn{
+		connectionID: 1,
+		salt:         []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F, 0x10, 0x11, 0x12, 0x13, 0x14},
+		server:       server,
+		user:         "token_user",
+		peerHost:     "localhost",
+		collation:    mysql.DefaultCollationID,
+		alloc:        arena.NewAllocator(512),
+	}
+
+	// The token of another user or another host is denied.
+	for _, identity := range [][2]string{{"root", "localhost"}, {"token_user", "192.168.0.1"}} {
+		token, err := sessionstates.CreateSessionToken(identity[0], identity[1])
+		c.Assert(err, IsNil)
+		tokenBytes, err := json.Marshal(token)
+		c.Assert(err, IsNil)
+		err = cc.openSessionAndDoAuth(tokenBytes, authTiDBSessionToken)
+		c.Assert(err, ErrorMatches, ".*Access denied.*")
+		c.Assert(cc.ctx.Close(), IsNil)
+	}
+
+	token, err := sessionstates.CreateSessionToken("token_user", "localhost")
+	c.Assert(err, IsNil)
+	tokenBytes, err := json.Marshal(token)
+	c.Assert(err, IsNil)
+	// The token can't be used as the password.
+	err = cc.openSessionAndDoAuth(tokenBytes, mysql.AuthNativePassword)
+	c.Assert(err, ErrorMatches, ".*Access denied.*")
+	c.Assert(cc.ctx.Close(), IsNil)
+	c.Assert(cc.openSessionAndDoAuth(tokenBytes, authTiDBSessionToken), IsNil)
+	c.Assert(cc.ctx.GetSessionVars().User.Username, Equals, "token_user")
+
+	// The migrated session can get a new token for the next migration.
+	tk1 := testkit.NewTestKit(c, ts.store)
+	tk1.Se = cc.ctx.Session
+	newToken := tk1.MustQuery("select @@tidb_session_token").Rows()[0][0].(string)
+	c.Assert(sessionstates.ValidateSessionToken([]byte(newToken), "token_user", "localhost"), IsNil)
+
+	// COM_CHANGE_USER can switch to the migrated user by the token.
+	var outBuffer bytes.Buffer
+	cc.pkt = &packetIO{bufWriter: bufio.NewWriter(&outBuffer)}
+	cc.capability = mysql.ClientProtocol41 | mysql.ClientPluginAuth
+	cc.user = "root"
+	c.Assert(cc.openSessionAndDoAuth(nil, mysql.AuthNativePassword), IsNil)
+	data := append([]byte("token_user"), 0, byte(len(newToken)))
+	data = append(data, newToken...)
+	data = append(data, 0, mysql.DefaultCollationID, 0)
+	data = append(data, authTiDBSessionToken...)
+	data = append(data, 0)
+	c.Assert(cc.handleChangeUser(context.Background(), data), IsNil)
+	c.Assert(cc.ctx.GetSessionVars().User.Username, Equals, "token_user")
+	c.Assert(cc.ctx.Close(), IsNil)
+}
+
+func (ts *ConnTestSuite) TestOptionalResultsetMetadata(c *C) {
+	var outBuffer bytes.Buffer
+	cc := &clientConn{
+		connectionID: 1,
+		pkt: &packetIO{
+			bufWriter: bufio.NewWriter(&outBuffer),
+		},
+		alloc:      arena.NewAllocator(1024),
+		capability: mysql.ClientProtocol41 | clientOptionalResultsetMetadata,
+	}
+	tk := testkit.NewTestKitWithInit(c, ts.store)
+	cc.ctx = &TiDBContext{Session: tk.Se, stmts: make(map[int]*TiDBStatement)}
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b varchar(10))")
+	tk.MustExec("insert into t values (1, 'a')")
+	ctx := context.Background()
+
+	// readPackets splits the output into the payloads of the packets.
+	readPackets := func() [][]byte {
+		c.Assert(cc.flush(ctx), IsNil)
+		var packets [][]byte
+		data := outBuffer.Bytes()
+		for len(data) > 0 {
+			length := int(uint32(data[0]) | uint32(data[1])<<8 | uint32(data[2])<<16)
+			packets = append(packets, data[4:4+length])
+			data = data[4+length:]
+		}
+		outBuffer.Reset()
+		return packets
+	}
+	isEOF := func(packet []byte) bool {
+		return len(packet) == 5 && packet[0] == mysql.EOFHeader
+	}
+
+	// The text result set: the column count with the flag, 2 definitions, EOF, 1 row and EOF.
+	c.Assert(cc.handleQuery(ctx, "select * from t"), IsNil)
+	packets := readPackets()
+	c.Assert(packets, HasLen, 6)
+	c.Assert(packets[0], DeepEquals, []byte{2, resultsetMetadataFull})
+	c.Assert(isEOF(packets[3]), IsTrue)
+
+	// The response of the preparation ends with the flag.
+	c.Assert(cc.handleStmtPrepare(ctx, "select a from t where b = ?"), IsNil)
+	packets = readPackets()
+	c.Assert(packets, HasLen, 5)
+	c.Assert(packets[0][len(packets[0])-1], Equals, resultsetMetadataFull)
+	stmtID := packets[0][1:5]
+
+	// The definitions are the same as the ones sent in the response of the preparation, they're skipped.
+	execute := append(append([]byte(nil), stmtID...), 0, 1, 0, 0, 0, 0, 1, mysql.TypeVarchar, 0, 1, 'a')
+	c.Assert(cc.handleStmtExecute(ctx, execute), IsNil)
+	packets = readPackets()
+	c.Assert(packets, HasLen, 4)
+	c.Assert(packets[0], DeepEquals, []byte{1, resultsetMetadataNone})
+	c.Assert(isEOF(packets[1]), IsTrue)
+	c.Assert(isEOF(packets[3]), IsTrue)
+
+	// The definitions are skipped if resultset_metadata is NONE.
+	tk.MustExec("set @@resultset_metadata = 'none'")
+	c.Assert(cc.handleQuery(ctx, "select * from t"), IsNil)
+	packets = readPackets()
+	c.Assert(packets, HasLen, 4)
+	c.Assert(packets[0], DeepEquals, []byte{2, resultsetMetadataNone})
+	c.Assert(cc.handleStmtPrepare(ctx, "select b from t"), IsNil)
+	packets = readPackets()
+	c.Assert(packets, HasLen, 2)
+	c.Assert(packets[0][len(packets[0])-1], Equals, resultsetMetadataNone)
+	c.Assert(isEOF(packets[1]), IsTrue)
+	tk.MustExec("set @@resultset_metadata = 'full'")
+
+	// The client without the capability always receives the definitions.
+	cc.capability = mysql.ClientProtocol41
+	c.Assert(cc.handleStmtExecute(ctx, execute), IsNil)
+	packets = readPackets()
+	c.Assert(packets, HasLen, 5)
+	c.Assert(packets[0], DeepEquals, []byte{1})
+}
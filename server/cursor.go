@@ -0,0 +1,166 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/disk"
+	"github.com/pingcap/tidb/util/memory"
+)
+
+// cursorResultSet is the ResultSet of a server-side cursor. All the rows are read from the executor when the statement
+// is executed and kept in a RowContainer, which spills to disk when the rows exceed the memory quota of a query. So the
+// executor and the transaction are released immediately, and fetching a large result doesn't exhaust the memory.
+type cursorResultSet struct {
+	columns      []*ColumnInfo
+	fieldTypes   []*types.FieldType
+	maxChunkSize int
+	rowContainer *chunk.RowContainer
+	memTracker   *memory.Tracker
+	diskTracker  *disk.Tracker
+	// chkIdx is the index of the next chunk read from rowContainer.
+	chkIdx int
+	rows   []chunk.Row
+	closed int32
+
+	// memUsage and diskUsage are the bytes of the rows counted in metrics.CursorUsageGauge.
+	memUsage  int64
+	diskUsage int64
+}
+
+// newCursorResultSet reads all the rows of rs into a cursorResultSet, rs is closed when it returns.
+func newCursorResultSet(ctx context.Context, vars *variable.SessionVars, rs ResultSet) (_ *cursorResultSet, err error) {
+	defer terror.Call(rs.Close)
+	fieldTypes := rs.FieldTypes()
+	crs := &cursorResultSet{
+		columns:      rs.Columns(),
+		fieldTypes:   fieldTypes,
+		maxChunkSize: vars.MaxChunkSize,
+		rowContainer: chunk.NewRowContainer(fieldTypes, vars.MaxChunkSize),
+		memTracker:   memory.NewTracker(memory.LabelForCursorFetch, vars.MemQuotaQuery),
+		diskTracker:  disk.NewTracker(memory.LabelForCursorFetch, -1),
+	}
+	crs.memTracker.AttachToGlobalTracker(executor.GlobalMemoryUsageTracker)
+	crs.memTracker.SetActionOnExceed(&memory.LogOnExceed{ConnID: vars.ConnectionID})
+	crs.memTracker.SetEscalationChain(vars.MemOOMActionChain)
+	crs.rowContainer.GetMemTracker().AttachTo(crs.memTracker)
+	crs.rowContainer.GetMemTracker().SetLabel(memory.LabelForCursorFetch)
+	if config.GetGlobalConfig().OOMUseTmpStorage {
+		crs.memTracker.FallbackOldAndSetNewAction(crs.rowContainer.ActionSpill())
+		if executor.GlobalDiskUsageTracker != nil {
+			crs.diskTracker.AttachToGlobalTracker(executor.GlobalDiskUsageTracker)
+		}
+	}
+	crs.rowContainer.GetDiskTracker().AttachTo(crs.diskTracker)
+	crs.rowContainer.GetDiskTracker().SetLabel(memory.LabelForCursorFetch)
+	metrics.CursorGauge.Inc()
+	defer func() {
+		if err != nil {
+			terror.Call(crs.Close)
+		}
+	}()
+
+	for {
+		// The chunk is kept by rowContainer, so a new one is allocated each time.
+		chk := rs.NewChunk()
+		if err = rs.Next(ctx, chk); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if chk.NumRows() == 0 {
+			break
+		}
+		if err = crs.rowContainer.Add(chk); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	crs.updateUsage()
+	return crs, nil
+}
+
+// Columns implements ResultSet Columns method.
+func (crs *cursorResultSet) Columns() []*ColumnInfo {
+	return crs.columns
+}
+
+// FieldTypes implements ResultSet FieldTypes method.
+func (crs *cursorResultSet) FieldTypes() []*types.FieldType {
+	return crs.fieldTypes
+}
+
+// NewChunk implements ResultSet NewChunk method.
+func (crs *cursorResultSet) NewChunk() *chunk.Chunk {
+	return chunk.New(crs.fieldTypes, crs.maxChunkSize, crs.maxChunkSize)
+}
+
+// Next implements ResultSet Next method, it reads the next chunk kept in the RowContainer.
+func (crs *cursorResultSet) Next(_ context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if crs.chkIdx >= crs.rowContainer.NumChunks() {
+		return nil
+	}
+	chk, err := crs.rowContainer.GetChunk(crs.chkIdx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	crs.chkIdx++
+	req.Append(chk, 0, chk.NumRows())
+	crs.updateUsage()
+	return nil
+}
+
+// StoreFetchedRows implements ResultSet StoreFetchedRows method.
+func (crs *cursorResultSet) StoreFetchedRows(rows []chunk.Row) {
+	crs.rows = rows
+}
+
+// GetFetchedRows implements ResultSet GetFetchedRows method.
+func (crs *cursorResultSet) GetFetchedRows() []chunk.Row {
+	if crs.rows == nil {
+		crs.rows = make([]chunk.Row, 0, 1024)
+	}
+	return crs.rows
+}
+
+// Close implements ResultSet Close method, it releases the rows in memory and on disk.
+func (crs *cursorResultSet) Close() error {
+	if !atomic.CompareAndSwapInt32(&crs.closed, 0, 1) {
+		return nil
+	}
+	err := crs.rowContainer.Close()
+	crs.rows = nil
+	crs.updateUsage()
+	crs.memTracker.DetachFromGlobalTracker()
+	crs.diskTracker.DetachFromGlobalTracker()
+	metrics.CursorGauge.Dec()
+	return err
+}
+
+// updateUsage updates metrics.CursorUsageGauge by the change of the usage of the RowContainer, the rows may be spilled
+// to disk in the background.
+func (crs *cursorResultSet) updateUsage() {
+	memUsage, diskUsage := crs.memTracker.BytesConsumed(), crs.diskTracker.BytesConsumed()
+	metrics.CursorUsageGauge.WithLabelValues(metrics.LblMemory).Add(float64(memUsage - crs.memUsage))
+	metrics.CursorUsageGauge.WithLabelValues(metrics.LblDisk).Add(float64(diskUsage - crs.diskUsage))
+	crs.memUsage, crs.diskUsage = memUsage, diskUsage
+}
@@ -56,6 +56,13 @@ type PreparedStatement interface {
 	// GetResultSet gets ResultSet associated this statement
 	GetResultSet() ResultSet
 
+	// StoreSentColumns stores the dumped column definitions sent in the response of the preparation, they're not
+	// sent again on execution if they're unchanged and the client supports CLIENT_OPTIONAL_RESULTSET_METADATA.
+	StoreSentColumns(columns []byte)
+
+	// GetSentColumns gets the dumped column definitions sent in the response of the preparation.
+	GetSentColumns() []byte
+
 	// Reset removes all bound parameters.
 	Reset()
 
@@ -66,16 +73,10 @@ type PreparedStatement interface {
 // ResultSet is the result set of an query.
 type ResultSet interface {
 	Columns() []*ColumnInfo
+	FieldTypes() []*types.FieldType
 	NewChunk() *chunk.Chunk
 	Next(context.Context, *chunk.Chunk) error
 	StoreFetchedRows(rows []chunk.Row)
 	GetFetchedRows() []chunk.Row
 	Close() error
 }
-
-// fetchNotifier represents notifier will be called in COM_FETCH.
-type fetchNotifier interface {
-	// OnFetchReturned be called when COM_FETCH returns.
-	// it will be used in server-side cursor.
-	OnFetchReturned()
-}
@@ -61,6 +61,8 @@ type TiDBStatement struct {
 	ctx         *TiDBContext
 	rs          ResultSet
 	sql         string
+	// sentColumns is the column definitions sent in the response of the preparation.
+	sentColumns []byte
 }
 
 // ID implements PreparedStatement ID method.
@@ -135,6 +137,16 @@ func (ts *TiDBStatement) GetResultSet() ResultSet {
 	return ts.rs
 }
 
+// StoreSentColumns implements PreparedStatement StoreSentColumns method.
+func (ts *TiDBStatement) StoreSentColumns(columns []byte) {
+	ts.sentColumns = columns
+}
+
+// GetSentColumns implements PreparedStatement GetSentColumns method.
+func (ts *TiDBStatement) GetSentColumns() []byte {
+	return ts.sentColumns
+}
+
 // Reset implements PreparedStatement Reset method.
 func (ts *TiDBStatement) Reset() {
 	for i := range ts.boundParams {
@@ -164,7 +176,7 @@ func (ts *TiDBStatement) Close() error {
 				return errors.Errorf("invalid CachedPrepareStmt type")
 			}
 			ts.ctx.PreparedPlanCache().Delete(core.NewPSTMTPlanCacheKey(
-				ts.ctx.GetSessionVars(), ts.id, preparedObj.PreparedAst.SchemaVersion))
+				ts.ctx.GetSessionVars(), ts.id, preparedObj.PlanSchemaVersion))
 		}
 		ts.ctx.GetSessionVars().RemovePreparedStmt(ts.id)
 	}
@@ -296,6 +308,15 @@ type tidbResultSet struct {
 	preparedStmt *core.CachedPrepareStmt
 }
 
+func (trs *tidbResultSet) FieldTypes() []*types.FieldType {
+	fields := trs.recordSet.Fields()
+	fieldTypes := make([]*types.FieldType, 0, len(fields))
+	for _, field := range fields {
+		fieldTypes = append(fieldTypes, &field.Column.FieldType)
+	}
+	return fieldTypes
+}
+
 func (trs *tidbResultSet) NewChunk() *chunk.Chunk {
 	return trs.recordSet.NewChunk()
 }
@@ -324,13 +345,6 @@ func (trs *tidbResultSet) Close() error {
 	return err
 }
 
-// OnFetchReturned implements fetchNotifier#OnFetchReturned
-func (trs *tidbResultSet) OnFetchReturned() {
-	if cl, ok := trs.recordSet.(fetchNotifier); ok {
-		cl.OnFetchReturned()
-	}
-}
-
 func (trs *tidbResultSet) Columns() []*ColumnInfo {
 	if trs.columns != nil {
 		return trs.columns
@@ -0,0 +1,207 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"runtime"
+	rpprof "runtime/pprof"
+	"strconv"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/util/admin"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/printer"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+	"go.uber.org/zap"
+)
+
+const (
+	// defDebugZipProfileSeconds and maxDebugZipProfileSeconds are the default and max seconds of the CPU profile.
+	defDebugZipProfileSeconds = 10
+	maxDebugZipProfileSeconds = 120
+	// debugZipSlowLogSize is the max bytes of the slow log collected, the latest ones are collected.
+	debugZipSlowLogSize = 8 << 20
+)
+
+// debugZipHandler is the handler of /debug/zip, which collects the diagnostics of the server into a zip file for
+// support tickets. The CPU is profiled for the seconds in the `seconds` parameter, 10 by default and 120 at most.
+type debugZipHandler struct {
+	*tikvHandlerTool
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h debugZipHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sec, err := strconv.ParseInt(r.FormValue("seconds"), 10, 64)
+	if sec <= 0 || err != nil {
+		sec = defDebugZipProfileSeconds
+	}
+	if sec > maxDebugZipProfileSeconds {
+		sec = maxDebugZipProfileSeconds
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="tidb_debug"`+time.Now().Format("20060102150405")+".zip"))
+
+	items := []struct {
+		name  string
+		write func(w io.Writer) error
+	}{
+		// dump goroutine/heap/mutex
+		{name: "goroutine", write: writeProfile("goroutine", 2, false)},
+		{name: "heap", write: writeProfile("heap", 0, true)},
+		{name: "mutex", write: writeProfile("mutex", 0, false)},
+		{name: "profile", write: func(w io.Writer) error {
+			if err := rpprof.StartCPUProfile(w); err != nil {
+				return errors.Errorf("could not enable CPU profiling: %s", err)
+			}
+			sleepWithCtx(r.Context(), time.Duration(sec)*time.Second)
+			rpprof.StopCPUProfile()
+			return nil
+		}},
+		{name: "config", write: func(w io.Writer) error {
+			return writeJSON(w, config.GetGlobalConfig())
+		}},
+		{name: "version", write: func(w io.Writer) error {
+			_, err := w.Write([]byte(printer.GetTiDBInfo()))
+			return err
+		}},
+		{name: "metrics", write: writeMetrics},
+		{name: "slow_query.log", write: writeSlowLog},
+		{name: "schema_version", write: h.writeSchemaVersion},
+		{name: "ddl_jobs", write: h.writeDDLJobs},
+	}
+	zw := zip.NewWriter(w)
+	for _, item := range items {
+		fw, err := zw.Create(item.name)
+		if err != nil {
+			serveError(w, http.StatusInternalServerError, fmt.Sprintf("Create zipped %s fail: %v", item.name, err))
+			return
+		}
+		// The zip is still useful without some of the items, so the error is written into the item instead.
+		if err = item.write(fw); err != nil {
+			logutil.BgLogger().Warn("collect the diagnostics failed", zap.String("item", item.name), zap.Error(err))
+			_, err = fmt.Fprintf(fw, "\ncollect %s fail: %v\n", item.name, err)
+			if err != nil {
+				return
+			}
+		}
+	}
+	if err := zw.Close(); err != nil {
+		logutil.BgLogger().Warn("close the zip of the diagnostics failed", zap.Error(err))
+	}
+}
+
+func writeProfile(name string, debug int, gc bool) func(w io.Writer) error {
+	return func(w io.Writer) error {
+		p := rpprof.Lookup(name)
+		if p == nil {
+			return errors.Errorf("unknown profile %s", name)
+		}
+		if gc {
+			runtime.GC()
+		}
+		return p.WriteTo(w, debug)
+	}
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	js, err := json.MarshalIndent(v, "", " ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = w.Write(js)
+	return err
+}
+
+// writeMetrics writes the snapshot of the metrics in the Prometheus text format.
+func writeMetrics(w io.Writer) error {
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, family := range families {
+		if _, err = expfmt.MetricFamilyToText(w, family); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// writeSlowLog writes the latest debugZipSlowLogSize bytes of the slow log file.
+func writeSlowLog(w io.Writer) error {
+	f, err := os.Open(config.GetGlobalConfig().Log.SlowQueryFile)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if offset := info.Size() - debugZipSlowLogSize; offset > 0 {
+		if _, err = f.Seek(offset, io.SeekStart); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	_, err = io.Copy(w, io.LimitReader(f, debugZipSlowLogSize))
+	return errors.Trace(err)
+}
+
+// writeSchemaVersion writes the version of the schema loaded by the server and the latest version in the storage,
+// the server lags behind if they're different.
+func (h debugZipHandler) writeSchemaVersion(w io.Writer) error {
+	is, err := h.schema()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	txn, err := h.Store.Begin()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		_ = txn.Rollback()
+	}()
+	globalVersion, err := meta.NewMeta(txn).GetSchemaVersion()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return writeJSON(w, map[string]int64{
+		"schema_version":        is.SchemaMetaVersion(),
+		"global_schema_version": globalVersion,
+	})
+}
+
+// writeDDLJobs writes the DDL jobs in the queue.
+func (h debugZipHandler) writeDDLJobs(w io.Writer) error {
+	txn, err := h.Store.Begin()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		_ = txn.Rollback()
+	}()
+	jobs, err := admin.GetDDLJobs(txn)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return writeJSON(w, jobs)
+}
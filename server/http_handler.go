@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"net"
 	"net/http"
 	"net/url"
 	"runtime"
@@ -34,15 +35,23 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/kvrpcpb"
 	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/terror"
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/ddl"
+	ddlutil "github.com/pingcap/tidb/ddl/util"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/domain/infosync"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/masking"
 	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/privilege/privileges"
+	"github.com/pingcap/tidb/resourcegroup"
+	"github.com/pingcap/tidb/rowpolicy"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/binloginfo"
@@ -55,6 +64,7 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/ttl"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/admin"
@@ -81,6 +91,9 @@ const (
 	pColumnLen  = "colLen"
 	pRowBin     = "rowBin"
 	pSnapshot   = "snapshot"
+	pPolicyName = "policy"
+	pGroupName  = "group"
+	pConnID     = "connID"
 )
 
 // For query string
@@ -89,6 +102,23 @@ const (
 	qLimit     = "limit"
 	qOperation = "op"
 	qSeconds   = "seconds"
+
+	qStartVersion = "start_version"
+	qTimeout      = "timeout"
+
+	qPartition = "partition"
+
+	qCompress = "compress"
+
+	qUser = "user"
+	qHost = "host"
+
+	qFailedLoginAttempts   = "failed_login_attempts"
+	qPasswordLockTime      = "password_lock_time"
+	qPasswordHistory       = "password_history"
+	qPasswordReuseInterval = "password_reuse_interval"
+
+	qQuery = "query"
 )
 
 const (
@@ -102,6 +132,12 @@ func writeError(w http.ResponseWriter, err error) {
 	terror.Log(errors.Trace(err))
 }
 
+func writeForbidden(w http.ResponseWriter, err error) {
+	w.WriteHeader(http.StatusForbidden)
+	_, err = w.Write([]byte(err.Error()))
+	terror.Log(errors.Trace(err))
+}
+
 func writeData(w http.ResponseWriter, data interface{}) {
 	js, err := json.MarshalIndent(data, "", " ")
 	if err != nil {
@@ -435,6 +471,48 @@ type ddlResignOwnerHandler struct {
 	store kv.Storage
 }
 
+// ddlEventsHandler is the handler for long polling the events of the committed ddl jobs.
+type ddlEventsHandler struct {
+	store kv.Storage
+}
+
+// ttlHandler is the handler for the TTL attributes of a table.
+type ttlHandler struct {
+	*tikvHandlerTool
+}
+
+// placementPolicyHandler is the handler for the placement policies.
+type placementPolicyHandler struct {
+	store kv.Storage
+}
+
+// resourceGroupHandler is the handler for the resource groups.
+type resourceGroupHandler struct{}
+
+// resourceGroupUsersHandler is the handler for the user accounts bound to a resource group.
+type resourceGroupUsersHandler struct{}
+
+// rowPolicyHandler is the handler for the row policies.
+type rowPolicyHandler struct{}
+
+// passwordLockingHandler is the handler for the account locking after the consecutive failed logins.
+type passwordLockingHandler struct {
+	store kv.Storage
+}
+
+// passwordReuseHandler is the handler for the password reuse policies of the user accounts.
+type passwordReuseHandler struct {
+	store kv.Storage
+}
+
+// maskingRuleHandler is the handler for the column masking rules.
+type maskingRuleHandler struct{}
+
+// repairIndexHandler is the handler for rebuilding an index from the table records.
+type repairIndexHandler struct {
+	store kv.Storage
+}
+
 type serverInfoHandler struct {
 	*tikvHandlerTool
 }
@@ -447,6 +525,18 @@ type profileHandler struct {
 	*tikvHandlerTool
 }
 
+// statusReportHandler is the handler for downloading the diagnostic status report.
+type statusReportHandler struct {
+	*tikvHandlerTool
+	sm util.SessionManager
+}
+
+// connKillHandler is the handler for killing a connection or its running query on this tidb-server.
+type connKillHandler struct {
+	*tikvHandlerTool
+	s *Server
+}
+
 // valueHandler is the handler for get value.
 type valueHandler struct {
 }
@@ -1185,6 +1275,316 @@ func (h ddlResignOwnerHandler) ServeHTTP(w http.ResponseWriter, req *http.Reques
 	writeData(w, "success!")
 }
 
+const (
+	defDDLEventsTimeout = 30 * time.Second
+	maxDDLEventsTimeout = 5 * time.Minute
+)
+
+// ServeHTTP handles request of subscribing ddl events, it waits until there are events after the start version or it times out.
+func (h ddlEventsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var startVersion int64
+	if str := req.FormValue(qStartVersion); len(str) > 0 {
+		var err error
+		startVersion, err = strconv.ParseInt(str, 10, 64)
+		if err != nil {
+			writeError(w, errors.Errorf("invalid start_version: %s", str))
+			return
+		}
+	}
+	limit := 0
+	if str := req.FormValue(qLimit); len(str) > 0 {
+		var err error
+		limit, err = strconv.Atoi(str)
+		if err != nil || limit < 1 {
+			writeError(w, errors.Errorf("invalid limit: %s", str))
+			return
+		}
+	}
+	timeout := defDDLEventsTimeout
+	if str := req.FormValue(qTimeout); len(str) > 0 {
+		seconds, err := strconv.Atoi(str)
+		if err != nil || seconds < 0 {
+			writeError(w, errors.Errorf("invalid timeout: %s", str))
+			return
+		}
+		timeout = time.Duration(seconds) * time.Second
+		if timeout > maxDDLEventsTimeout {
+			timeout = maxDDLEventsTimeout
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	defer cancel()
+	events, err := ddlutil.NewSubscriber(h.store, startVersion).Next(ctx, limit)
+	if err != nil && ctx.Err() == nil {
+		writeError(w, err)
+		return
+	}
+	if events == nil {
+		events = []*meta.DDLEvent{}
+	}
+	writeData(w, events)
+}
+
+// ttlResponse is the response of the TTL attributes of a table.
+type ttlResponse struct {
+	TTLInfo *model.TTLInfo   `json:"ttl_info"`
+	LastJob *ttl.TableStatus `json:"last_job"`
+}
+
+// ServeHTTP handles request of the TTL attributes of a table.
+func (h ttlHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, errors.Errorf("This api only support GET method, the TTL attributes are managed by CREATE TABLE and ALTER TABLE."))
+		return
+	}
+	params := mux.Vars(req)
+	schema, err := h.schema()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	tbl, err := schema.TableByName(model.NewCIStr(params[pDBName]), model.NewCIStr(params[pTableName]))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if tbl.Meta().TTLInfo == nil {
+		writeError(w, errors.Errorf("table '%s' is not a TTL table", tbl.Meta().Name.O))
+		return
+	}
+	se, err := session.CreateSession(h.Store)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer se.Close()
+
+	status, err := ttl.LoadTableStatus(se, tbl.Meta().ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeData(w, ttlResponse{TTLInfo: tbl.Meta().TTLInfo, LastJob: status})
+}
+
+// ServeHTTP handles request of the placement policies.
+func (h placementPolicyHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, errors.Errorf("This api only support GET method, the placement policies are managed by CREATE, ALTER and DROP PLACEMENT POLICY."))
+		return
+	}
+	se, err := session.CreateSession(h.store)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer se.Close()
+
+	name, ok := mux.Vars(req)[pPolicyName]
+	policies, err := ddl.LoadPlacementPolicies(se, name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if !ok {
+		writeData(w, policies)
+		return
+	}
+	if len(policies) == 0 {
+		writeError(w, ddl.ErrPlacementPolicyNotExists.GenWithStackByArgs(name))
+		return
+	}
+	writeData(w, policies[0])
+}
+
+// ServeHTTP handles request of the resource groups.
+func (h resourceGroupHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, errors.Errorf("This api only support GET method, the resource groups are managed by CREATE, ALTER and DROP RESOURCE GROUP."))
+		return
+	}
+	manager := resourcegroup.GlobalManager()
+	name, ok := mux.Vars(req)[pGroupName]
+	if !ok {
+		writeData(w, manager.Groups())
+		return
+	}
+	group := manager.GetGroup(name)
+	if group == nil {
+		writeError(w, errors.Errorf("resource group '%s' doesn't exist", name))
+		return
+	}
+	writeData(w, group)
+}
+
+// ServeHTTP handles request of the row policies.
+func (h rowPolicyHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, errors.Errorf("This api only support GET method, the row policies are managed by CREATE and DROP ROW POLICY."))
+		return
+	}
+	manager := rowpolicy.GlobalManager()
+	params := mux.Vars(req)
+	table, ok := params[pTableName]
+	if !ok {
+		writeData(w, manager.Policies())
+		return
+	}
+	writeData(w, manager.TablePolicies(params[pDBName], table))
+}
+
+// ServeHTTP handles request of the column masking rules.
+func (h maskingRuleHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, errors.Errorf("This api only support GET method, the masking rules are managed by CREATE and DROP MASKING RULE."))
+		return
+	}
+	manager := masking.GlobalManager()
+	params := mux.Vars(req)
+	table, ok := params[pTableName]
+	if !ok {
+		writeData(w, manager.Rules())
+		return
+	}
+	writeData(w, manager.TableRules(params[pDBName], table))
+}
+
+// ServeHTTP handles request of the user accounts bound to a resource group.
+func (h resourceGroupUsersHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		writeError(w, errors.Errorf("This api only support GET method, the user accounts are bound by ALTER USER ... RESOURCE GROUP."))
+		return
+	}
+	writeData(w, resourcegroup.GlobalManager().UserBindings(mux.Vars(req)[pGroupName]))
+}
+
+// ServeHTTP handles request of the account locking after the consecutive failed logins.
+func (h passwordLockingHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	se, err := session.CreateSession(h.store)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer se.Close()
+
+	switch req.Method {
+	case http.MethodGet:
+		lockings, err := privileges.AccountLockings(se)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeData(w, lockings)
+		return
+	case http.MethodPost:
+	default:
+		writeError(w, errors.Errorf("This api only support GET and POST method."))
+		return
+	}
+
+	user, host := req.FormValue(qUser), req.FormValue(qHost)
+	if user == "" {
+		writeError(w, errors.Errorf("user is not specified"))
+		return
+	}
+	if host == "" {
+		host = "%"
+	}
+	attempts, err := strconv.ParseInt(req.FormValue(qFailedLoginAttempts), 10, 64)
+	if err != nil {
+		writeError(w, errors.Errorf("invalid %s: %s", qFailedLoginAttempts, req.FormValue(qFailedLoginAttempts)))
+		return
+	}
+	var lockTime int64 = privilege.PasswordLockTimeUnbounded
+	if !strings.EqualFold(req.FormValue(qPasswordLockTime), "unbounded") {
+		lockTime, err = strconv.ParseInt(req.FormValue(qPasswordLockTime), 10, 64)
+		if err != nil || lockTime < 0 {
+			writeError(w, errors.Errorf("invalid %s: %s", qPasswordLockTime, req.FormValue(qPasswordLockTime)))
+			return
+		}
+	}
+	if err = privileges.SetPasswordLocking(se, user, host, attempts, lockTime); err != nil {
+		writeError(w, err)
+		return
+	}
+	domain.GetDomain(se).NotifyUpdatePrivilege(se)
+	writeData(w, "success!")
+}
+
+// ServeHTTP handles request of the password reuse policies.
+func (h passwordReuseHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	se, err := session.CreateSession(h.store)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer se.Close()
+
+	switch req.Method {
+	case http.MethodGet:
+		reuses, err := privileges.PasswordReuses(se)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeData(w, reuses)
+		return
+	case http.MethodPost:
+	default:
+		writeError(w, errors.Errorf("This api only support GET and POST method."))
+		return
+	}
+
+	user, host := req.FormValue(qUser), req.FormValue(qHost)
+	if user == "" {
+		writeError(w, errors.Errorf("user is not specified"))
+		return
+	}
+	if host == "" {
+		host = "%"
+	}
+	values := make([]int64, 0, 2)
+	for _, name := range []string{qPasswordHistory, qPasswordReuseInterval} {
+		var n int64 = privileges.PasswordReuseDefault
+		if val := req.FormValue(name); val != "" && !strings.EqualFold(val, "default") {
+			n, err = strconv.ParseInt(val, 10, 64)
+			if err != nil || n < 0 {
+				writeError(w, errors.Errorf("invalid %s: %s", name, val))
+				return
+			}
+		}
+		values = append(values, n)
+	}
+	if err = privileges.SetPasswordReuse(se, user, host, values[0], values[1]); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeData(w, "success!")
+}
+
+func (h repairIndexHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeError(w, errors.Errorf("This api only support POST method."))
+		return
+	}
+	params := mux.Vars(req)
+	se, err := session.CreateSession(h.store)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	defer se.Close()
+
+	ident := ast.Ident{Schema: model.NewCIStr(params[pDBName]), Name: model.NewCIStr(params[pTableName])}
+	err = domain.GetDomain(se).DDL().RepairIndex(se, ident, model.NewCIStr(params[pIndexName]))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeData(w, "success!")
+}
+
 func (h tableHandler) getPDAddr() ([]string, error) {
 	etcd, ok := h.Store.(kv.EtcdBackend)
 	if !ok {
@@ -1753,6 +2153,101 @@ func (h serverInfoHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	writeData(w, info)
 }
 
+// ServeHTTP handles request of the diagnostic status report.
+func (h statusReportHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	do, err := session.GetDomain(h.Store.(kv.Storage))
+	if err != nil {
+		writeError(w, errors.New("create session error"))
+		log.Error(err)
+		return
+	}
+	report, err := executor.BuildStatusReport(do, h.sm)
+	if err != nil {
+		writeError(w, err)
+		log.Error(err)
+		return
+	}
+	writeData(w, report)
+}
+
+// ServeHTTP handles request of killing a connection. It is used by the KILL statements executed on other tidb-servers
+// when the global kill is enabled.
+func (h connKillHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeError(w, errors.Errorf("This api only support POST method."))
+		return
+	}
+	if err := checkKillPeer(req); err != nil {
+		writeForbidden(w, err)
+		return
+	}
+	connID, err := strconv.ParseUint(mux.Vars(req)[pConnID], 10, 64)
+	if err != nil {
+		writeError(w, errors.Errorf("invalid connection ID: %s", mux.Vars(req)[pConnID]))
+		return
+	}
+	query := false
+	if str := req.FormValue(qQuery); len(str) > 0 {
+		if query, err = strconv.ParseBool(str); err != nil {
+			writeError(w, errors.Errorf("invalid value for %s: %s", qQuery, str))
+			return
+		}
+	}
+	// The user issuing the KILL is mandatory, the name can be empty for the anonymous user but the host can't.
+	if _, ok := req.PostForm[qUser]; !ok || len(req.PostFormValue(qHost)) == 0 {
+		writeError(w, errors.Errorf("the %s and %s of the user issuing the KILL are required", qUser, qHost))
+		return
+	}
+	user, host := req.PostFormValue(qUser), req.PostFormValue(qHost)
+	h.s.rwlock.RLock()
+	conn, ok := h.s.clients[connID]
+	h.s.rwlock.RUnlock()
+	if !ok {
+		writeError(w, errors.Errorf("connection %d is not found", connID))
+		return
+	}
+	// If the user has no CONNECTION_ADMIN privilege, it can kill only its own connections.
+	if owner := conn.ctx.GetSessionVars().User; owner == nil || owner.AuthUsername != user || owner.AuthHostname != host {
+		do, err := session.GetDomain(h.Store.(kv.Storage))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if !do.PrivilegeHandle().RequestDynamicVerificationWithUser(user, host, privilege.ConnectionAdmin) {
+			writeForbidden(w, errors.Errorf("connection %d is not owned by user '%s'@'%s'", connID, user, host))
+			return
+		}
+	}
+	logutil.BgLogger().Info("kill connection by the request from remote tidb-server", zap.Uint64("connID", connID),
+		zap.Bool("query", query), zap.String("user", user), zap.String("host", host),
+		zap.String("remoteAddr", req.RemoteAddr))
+	h.s.Kill(connID, query)
+	writeData(w, "success!")
+}
+
+// checkKillPeer authenticates the tidb-server forwarding a KILL. The request must carry a client certificate verified
+// by the cluster TLS config, or come from the address of a registered tidb-server.
+func checkKillPeer(req *http.Request) error {
+	if req.TLS != nil && len(req.TLS.VerifiedChains) > 0 {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return errors.Errorf("invalid remote address: %s", req.RemoteAddr)
+	}
+	remoteIP := net.ParseIP(host)
+	serversInfo, err := infosync.GetAllServerInfo(req.Context())
+	if err != nil {
+		return err
+	}
+	for _, info := range serversInfo {
+		if ip := net.ParseIP(info.IP); remoteIP != nil && ip != nil && ip.Equal(remoteIP) {
+			return nil
+		}
+	}
+	return errors.Errorf("%s is not a registered tidb-server", req.RemoteAddr)
+}
+
 // clusterServerInfo is used to report cluster servers info when do http request.
 type clusterServerInfo struct {
 	ServersNum                   int                             `json:"servers_num,omitempty"`
@@ -14,7 +14,9 @@
 package server
 
 import (
+	"archive/zip"
 	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
@@ -43,6 +45,7 @@ import (
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/ddl"
 	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/session"
@@ -1135,6 +1138,47 @@ func (ts *HTTPHandlerTestSuite) TestAllHistory(c *C) {
 	c.Assert(jobs, DeepEquals, data)
 }
 
+func (ts *HTTPHandlerTestSuite) TestDDLEvents(c *C) {
+	ts.startServer(c)
+	ts.prepareData(c)
+	defer ts.stopServer(c)
+
+	resp, err := ts.fetchStatus("/ddl/events?timeout=0")
+	c.Assert(err, IsNil)
+	var events []*meta.DDLEvent
+	err = json.NewDecoder(resp.Body).Decode(&events)
+	c.Assert(err, IsNil)
+	c.Assert(resp.Body.Close(), IsNil)
+	c.Assert(len(events), Greater, 0)
+	for i := 1; i < len(events); i++ {
+		c.Assert(events[i].SchemaVersion, Greater, events[i-1].SchemaVersion)
+	}
+
+	last := events[len(events)-1]
+	resp, err = ts.fetchStatus(fmt.Sprintf("/ddl/events?start_version=%d&limit=1", last.SchemaVersion-1))
+	c.Assert(err, IsNil)
+	events = nil
+	err = json.NewDecoder(resp.Body).Decode(&events)
+	c.Assert(err, IsNil)
+	c.Assert(resp.Body.Close(), IsNil)
+	c.Assert(events, HasLen, 1)
+	c.Assert(events[0], DeepEquals, last)
+
+	// It times out and returns no events when there isn't any new ddl job.
+	resp, err = ts.fetchStatus(fmt.Sprintf("/ddl/events?start_version=%d&timeout=1", last.SchemaVersion))
+	c.Assert(err, IsNil)
+	events = nil
+	err = json.NewDecoder(resp.Body).Decode(&events)
+	c.Assert(err, IsNil)
+	c.Assert(resp.Body.Close(), IsNil)
+	c.Assert(events, HasLen, 0)
+
+	resp, err = ts.fetchStatus("/ddl/events?limit=0")
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+	c.Assert(resp.Body.Close(), IsNil)
+}
+
 func (ts *HTTPHandlerTestSuite) TestPostSettings(c *C) {
 	ts.startServer(c)
 	ts.prepareData(c)
@@ -1270,6 +1314,154 @@ func (ts *HTTPHandlerTestSuite) TestServerInfo(c *C) {
 	c.Assert(info.ID, Equals, ddl.GetID())
 }
 
+func (ts *HTTPHandlerTestSuite) TestStatusReport(c *C) {
+	ts.startServer(c)
+	defer ts.stopServer(c)
+	resp, err := ts.fetchStatus("/status/report")
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	decoder := json.NewDecoder(resp.Body)
+
+	report := executor.StatusReport{}
+	err = decoder.Decode(&report)
+	c.Assert(err, IsNil)
+	c.Assert(report.Goroutines, Not(HasLen), 0)
+	c.Assert(report.RegionCache, NotNil)
+	_, ok := report.Caches["plan_cache_bytes"]
+	c.Assert(ok, IsTrue)
+}
+
+func (ts *HTTPHandlerTestSuite) TestHealthzAndReadyz(c *C) {
+	ts.startServer(c)
+	defer ts.stopServer(c)
+	resp, err := ts.fetchStatus("/healthz")
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	c.Assert(resp.Body.Close(), IsNil)
+
+	readyz := func(code int) readiness {
+		resp, err := ts.fetchStatus("/readyz")
+		c.Assert(err, IsNil)
+		defer resp.Body.Close()
+		c.Assert(resp.StatusCode, Equals, code)
+		var r readiness
+		c.Assert(json.NewDecoder(resp.Body).Decode(&r), IsNil)
+		return r
+	}
+	// The server isn't ready before the domain is set.
+	r := readyz(http.StatusServiceUnavailable)
+	c.Assert(r.Ready, IsFalse)
+	c.Assert(r.Checks, HasLen, 4)
+	c.Assert(r.Checks[0].OK, IsTrue)
+	c.Assert(r.Checks[1].Name, Equals, "schema")
+	c.Assert(r.Checks[1].OK, IsFalse)
+
+	ts.server.SetDomain(ts.domain)
+	r = readyz(http.StatusOK)
+	c.Assert(r.Ready, IsTrue)
+	for _, check := range r.Checks {
+		c.Assert(check.OK, IsTrue, Commentf("%s: %s", check.Name, check.Error))
+	}
+
+	// The server isn't ready if the schema is out of date.
+	ts.domain.SchemaValidator.Stop()
+	r = readyz(http.StatusServiceUnavailable)
+	c.Assert(r.Checks[1].OK, IsFalse)
+	c.Assert(r.Checks[1].Error, Equals, "the schema is out of date")
+	ts.domain.SchemaValidator.Restart()
+
+	// The server isn't ready while shutting down, but it's still alive.
+	atomic.StoreInt32(&ts.server.inShutdownMode, 1)
+	r = readyz(http.StatusServiceUnavailable)
+	c.Assert(r.Checks[0].OK, IsFalse)
+	resp, err = ts.fetchStatus("/healthz")
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	c.Assert(resp.Body.Close(), IsNil)
+}
+
+func (ts *HTTPHandlerTestSerialSuite) TestKillConnection(c *C) {
+	ts.startServer(c)
+	defer ts.stopServer(c)
+	db, err := sql.Open("mysql", ts.getDSN())
+	c.Assert(err, IsNil, Commentf("Error connecting"))
+	defer db.Close()
+	conn, err := db.Conn(context.Background())
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	var connID uint64
+	c.Assert(conn.QueryRowContext(context.Background(), "select connection_id()").Scan(&connID), IsNil)
+
+	// The request must come from a registered tidb-server.
+	resp, err := ts.formStatus(fmt.Sprintf("/connections/%d/kill", connID), url.Values{"user": {"root"}, "host": {"%"}})
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusForbidden)
+	c.Assert(resp.Body.Close(), IsNil)
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.AdvertiseAddress = "127.0.0.1"
+	})
+
+	resp, err = ts.formStatus("/connections/abc/kill", nil)
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+	c.Assert(resp.Body.Close(), IsNil)
+	resp, err = ts.fetchStatus(fmt.Sprintf("/connections/%d/kill", connID))
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+	c.Assert(resp.Body.Close(), IsNil)
+	resp, err = ts.formStatus(fmt.Sprintf("/connections/%d/kill", connID+1000), url.Values{"user": {"root"}, "host": {"%"}})
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+	c.Assert(resp.Body.Close(), IsNil)
+	// The user and host are mandatory.
+	resp, err = ts.formStatus(fmt.Sprintf("/connections/%d/kill", connID), nil)
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+	c.Assert(resp.Body.Close(), IsNil)
+	resp, err = ts.formStatus(fmt.Sprintf("/connections/%d/kill", connID), url.Values{"user": {"root"}})
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+	c.Assert(resp.Body.Close(), IsNil)
+
+	// The other users need the SUPER or CONNECTION_ADMIN privilege, which can be granted by a role.
+	_, err = db.Exec("create user 'kill_user'@'%', 'kill_role'")
+	c.Assert(err, IsNil)
+	resp, err = ts.formStatus(fmt.Sprintf("/connections/%d/kill", connID), url.Values{"query": {"true"}, "user": {"kill_user"}, "host": {"%"}})
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusForbidden)
+	c.Assert(resp.Body.Close(), IsNil)
+	_, err = db.Exec("grant SUPER on *.* to 'kill_role'")
+	c.Assert(err, IsNil)
+	_, err = db.Exec("grant 'kill_role' to 'kill_user'@'%'")
+	c.Assert(err, IsNil)
+	resp, err = ts.formStatus(fmt.Sprintf("/connections/%d/kill", connID), url.Values{"query": {"true"}, "user": {"kill_user"}, "host": {"%"}})
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	c.Assert(resp.Body.Close(), IsNil)
+
+	// Killing the query keeps the connection.
+	resp, err = ts.formStatus(fmt.Sprintf("/connections/%d/kill", connID), url.Values{"query": {"true"}, "user": {"root"}, "host": {"%"}})
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	c.Assert(resp.Body.Close(), IsNil)
+	_, ok := ts.server.GetProcessInfo(connID)
+	c.Assert(ok, IsTrue)
+
+	resp, err = ts.formStatus(fmt.Sprintf("/connections/%d/kill", connID), url.Values{"user": {"root"}, "host": {"%"}})
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	c.Assert(resp.Body.Close(), IsNil)
+	for i := 0; i < 50; i++ {
+		if _, ok = ts.server.GetProcessInfo(connID); !ok {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	c.Assert(ok, IsFalse)
+}
+
 func (ts *HTTPHandlerTestSerialSuite) TestAllServerInfo(c *C) {
 	ts.startServer(c)
 	defer ts.stopServer(c)
@@ -1318,10 +1510,32 @@ func (ts *HTTPHandlerTestSuite) TestDebugZip(c *C) {
 	resp, err := ts.fetchStatus("/debug/zip?seconds=1")
 	c.Assert(err, IsNil)
 	c.Assert(resp.StatusCode, Equals, http.StatusOK)
-	b, err := httputil.DumpResponse(resp, true)
+	b, err := ioutil.ReadAll(resp.Body)
 	c.Assert(err, IsNil)
-	c.Assert(len(b), Greater, 0)
 	c.Assert(resp.Body.Close(), IsNil)
+
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	c.Assert(err, IsNil)
+	files := make(map[string]string, len(zr.File))
+	for _, f := range zr.File {
+		r, err := f.Open()
+		c.Assert(err, IsNil)
+		content, err := ioutil.ReadAll(r)
+		c.Assert(err, IsNil)
+		c.Assert(r.Close(), IsNil)
+		files[f.Name] = string(content)
+	}
+	for _, name := range []string{"goroutine", "heap", "mutex", "profile", "config", "version", "metrics", "slow_query.log", "schema_version", "ddl_jobs"} {
+		c.Assert(files, HasKey, name)
+	}
+	c.Assert(files["metrics"], Matches, "(?s).*go_goroutines.*")
+	var versions map[string]int64
+	c.Assert(json.Unmarshal([]byte(files["schema_version"]), &versions), IsNil)
+	c.Assert(versions["schema_version"], Greater, int64(0))
+	c.Assert(versions["global_schema_version"], Equals, versions["schema_version"])
+	var jobs []interface{}
+	c.Assert(json.Unmarshal([]byte(files["ddl_jobs"]), &jobs), IsNil)
+	c.Assert(jobs, HasLen, 0)
 }
 
 func (ts *HTTPHandlerTestSuite) TestCheckCN(c *C) {
@@ -14,7 +14,6 @@
 package server
 
 import (
-	"archive/zip"
 	"bytes"
 	"context"
 	"crypto/tls"
@@ -26,8 +25,6 @@ import (
 	"net/http"
 	"net/http/pprof"
 	"net/url"
-	"runtime"
-	rpprof "runtime/pprof"
 	"strconv"
 	"strings"
 	"time"
@@ -40,9 +37,9 @@ import (
 	"github.com/pingcap/parser/terror"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/store/tikv/oracle"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/logutil"
-	"github.com/pingcap/tidb/util/printer"
 	"github.com/pingcap/tidb/util/versioninfo"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/soheilhy/cmux"
@@ -109,12 +106,16 @@ func (s *Server) startHTTPServer() {
 	router := mux.NewRouter()
 
 	router.HandleFunc("/status", s.handleStatus).Name("Status")
+	router.HandleFunc("/healthz", s.handleHealthz).Name("Healthz")
+	router.HandleFunc("/readyz", s.handleReadyz).Name("Readyz")
 	// HTTP path for prometheus.
 	router.Handle("/metrics", promhttp.Handler()).Name("Metrics")
 
 	// HTTP path for dump statistics.
 	router.Handle("/stats/dump/{db}/{table}", s.newStatsHandler()).Name("StatsDump")
 	router.Handle("/stats/dump/{db}/{table}/{snapshot}", s.newStatsHistoryHandler()).Name("StatsHistoryDump")
+	// HTTP path for lock and unlock statistics.
+	router.Handle("/stats/lock/{db}/{table}", s.newStatsLockHandler()).Name("StatsLock")
 
 	tikvHandlerTool := s.newTikvHandlerTool()
 	router.Handle("/settings", settingsHandler{tikvHandlerTool}).Name("Settings")
@@ -126,6 +127,20 @@ func (s *Server) startHTTPServer() {
 	router.Handle("/tables/{colID}/{colTp}/{colFlag}/{colLen}", valueHandler{})
 	router.Handle("/ddl/history", ddlHistoryJobHandler{tikvHandlerTool}).Name("DDL_History")
 	router.Handle("/ddl/owner/resign", ddlResignOwnerHandler{tikvHandlerTool.Store.(kv.Storage)}).Name("DDL_Owner_Resign")
+	router.Handle("/ddl/events", ddlEventsHandler{tikvHandlerTool.Store.(kv.Storage)}).Name("DDL_Events")
+	router.Handle("/tables/{db}/{table}/ttl", ttlHandler{tikvHandlerTool}).Name("TTL")
+	router.Handle("/tables/{db}/{table}/indexes/{index}/repair", repairIndexHandler{tikvHandlerTool.Store.(kv.Storage)}).Name("Repair_Index")
+	router.Handle("/placement-policies", placementPolicyHandler{tikvHandlerTool.Store.(kv.Storage)}).Name("Placement_Policies")
+	router.Handle("/placement-policies/{policy}", placementPolicyHandler{tikvHandlerTool.Store.(kv.Storage)})
+	router.Handle("/resource-groups", resourceGroupHandler{}).Name("Resource_Groups")
+	router.Handle("/resource-groups/{group}", resourceGroupHandler{})
+	router.Handle("/resource-groups/{group}/users", resourceGroupUsersHandler{})
+	router.Handle("/password-locking", passwordLockingHandler{tikvHandlerTool.Store.(kv.Storage)}).Name("Password_Locking")
+	router.Handle("/password-reuse", passwordReuseHandler{tikvHandlerTool.Store.(kv.Storage)}).Name("Password_Reuse")
+	router.Handle("/row-policies", rowPolicyHandler{}).Name("Row_Policies")
+	router.Handle("/tables/{db}/{table}/row-policies", rowPolicyHandler{})
+	router.Handle("/masking-rules", maskingRuleHandler{}).Name("Masking_Rules")
+	router.Handle("/tables/{db}/{table}/masking-rules", maskingRuleHandler{})
 
 	// HTTP path for get the TiDB config
 	router.Handle("/config", fn.Wrap(func() (*config.Config, error) {
@@ -135,6 +150,9 @@ func (s *Server) startHTTPServer() {
 	// HTTP path for get server info.
 	router.Handle("/info", serverInfoHandler{tikvHandlerTool}).Name("Info")
 	router.Handle("/info/all", allServerInfoHandler{tikvHandlerTool}).Name("InfoALL")
+	router.Handle("/status/report", statusReportHandler{tikvHandlerTool, s}).Name("StatusReport")
+	// HTTP path for killing a connection on this tidb-server, used by the global kill.
+	router.Handle("/connections/{connID}/kill", connKillHandler{tikvHandlerTool, s}).Name("KillConnection")
 	// HTTP path for get db and table info that is related to the tableID.
 	router.Handle("/db-table/{tableID}", dbTableHandler{tikvHandlerTool})
 	// HTTP path for get table tiflash replica info.
@@ -211,83 +229,7 @@ func (s *Server) startHTTPServer() {
 		}
 	})
 
-	serverMux.HandleFunc("/debug/zip", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="tidb_debug"`+time.Now().Format("20060102150405")+".zip"))
-
-		// dump goroutine/heap/mutex
-		items := []struct {
-			name   string
-			gc     int
-			debug  int
-			second int
-		}{
-			{name: "goroutine", debug: 2},
-			{name: "heap", gc: 1},
-			{name: "mutex"},
-		}
-		zw := zip.NewWriter(w)
-		for _, item := range items {
-			p := rpprof.Lookup(item.name)
-			if p == nil {
-				serveError(w, http.StatusNotFound, "Unknown profile")
-				return
-			}
-			if item.gc > 0 {
-				runtime.GC()
-			}
-			fw, err := zw.Create(item.name)
-			if err != nil {
-				serveError(w, http.StatusInternalServerError, fmt.Sprintf("Create zipped %s fail: %v", item.name, err))
-				return
-			}
-			err = p.WriteTo(fw, item.debug)
-			terror.Log(err)
-		}
-
-		// dump profile
-		fw, err := zw.Create("profile")
-		if err != nil {
-			serveError(w, http.StatusInternalServerError, fmt.Sprintf("Create zipped %s fail: %v", "profile", err))
-			return
-		}
-		if err := rpprof.StartCPUProfile(fw); err != nil {
-			serveError(w, http.StatusInternalServerError,
-				fmt.Sprintf("Could not enable CPU profiling: %s", err))
-			return
-		}
-		sec, err := strconv.ParseInt(r.FormValue("seconds"), 10, 64)
-		if sec <= 0 || err != nil {
-			sec = 10
-		}
-		sleepWithCtx(r.Context(), time.Duration(sec)*time.Second)
-		rpprof.StopCPUProfile()
-
-		// dump config
-		fw, err = zw.Create("config")
-		if err != nil {
-			serveError(w, http.StatusInternalServerError, fmt.Sprintf("Create zipped %s fail: %v", "config", err))
-			return
-		}
-		js, err := json.MarshalIndent(config.GetGlobalConfig(), "", " ")
-		if err != nil {
-			serveError(w, http.StatusInternalServerError, fmt.Sprintf("get config info fail%v", err))
-			return
-		}
-		_, err = fw.Write(js)
-		terror.Log(err)
-
-		// dump version
-		fw, err = zw.Create("version")
-		if err != nil {
-			serveError(w, http.StatusInternalServerError, fmt.Sprintf("Create zipped %s fail: %v", "version", err))
-			return
-		}
-		_, err = fw.Write([]byte(printer.GetTiDBInfo()))
-		terror.Log(err)
-
-		err = zw.Close()
-		terror.Log(err)
-	})
+	serverMux.Handle("/debug/zip", debugZipHandler{tikvHandlerTool})
 	fetcher := sqlInfoFetcher{store: tikvHandlerTool.Store}
 	serverMux.HandleFunc("/debug/sub-optimal-plan", fetcher.zipInfoForSQL)
 
@@ -395,7 +337,7 @@ func (s *Server) handleStatus(w http.ResponseWriter, req *http.Request) {
 	// If the server is in the process of shutting down, return a non-200 status.
 	// It is important not to return status{} as acquiring the s.ConnectionCount()
 	// acquires a lock that may already be held by the shutdown process.
-	if s.inShutdownMode {
+	if s.isShuttingDown() {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -413,3 +355,101 @@ func (s *Server) handleStatus(w http.ResponseWriter, req *http.Request) {
 	_, err = w.Write(js)
 	terror.Log(errors.Trace(err))
 }
+
+// readyzTimeout is the timeout of getting a timestamp from PD for the readiness check.
+const readyzTimeout = 3 * time.Second
+
+// readinessCheck is the result of a check of the readiness.
+type readinessCheck struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// readiness tells whether the server is ready to serve requests.
+type readiness struct {
+	Ready  bool             `json:"ready"`
+	Checks []readinessCheck `json:"checks"`
+}
+
+func (r *readiness) add(name string, err error) {
+	check := readinessCheck{Name: name, OK: err == nil}
+	if err != nil {
+		check.Error = err.Error()
+		r.Ready = false
+	}
+	r.Checks = append(r.Checks, check)
+}
+
+// handleHealthz reports whether the server is alive. Unlike /readyz, it doesn't depend on PD or the schema, so
+// the server isn't restarted when the cluster is unavailable.
+func (s *Server) handleHealthz(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, err := w.Write([]byte(`{"status":"ok"}`))
+	terror.Log(errors.Trace(err))
+}
+
+// handleReadyz reports whether the server is ready to serve requests, it returns 503 if the server is shutting
+// down, the schema isn't loaded or is out of date, PD can't be reached, or the GC safe point can't be checked.
+func (s *Server) handleReadyz(w http.ResponseWriter, req *http.Request) {
+	ctx, cancel := context.WithTimeout(req.Context(), readyzTimeout)
+	defer cancel()
+	r := s.checkReadiness(ctx)
+	js, err := json.Marshal(r)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		logutil.BgLogger().Error("encode json failed", zap.Error(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if !r.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_, err = w.Write(js)
+	terror.Log(errors.Trace(err))
+}
+
+func (s *Server) checkReadiness(ctx context.Context) *readiness {
+	r := &readiness{Ready: true}
+	var err error
+	if s.isShuttingDown() {
+		err = errors.New("the server is shutting down")
+	}
+	r.add("shutdown", err)
+
+	dom := s.dom
+	if dom == nil {
+		err = errors.New("the domain isn't initialized")
+		r.add("schema", err)
+		r.add("pd", err)
+		r.add("gc_safe_point", err)
+		return r
+	}
+	switch {
+	case dom.InfoSchema() == nil:
+		err = errors.New("the schema isn't loaded")
+	case !dom.SchemaValidator.IsStarted():
+		err = errors.New("the schema is out of date")
+	default:
+		err = nil
+	}
+	r.add("schema", err)
+
+	var ts uint64
+	if dom.IsLostConnectionToPD() {
+		err = errors.New("lost connection to PD")
+	} else {
+		ts, err = dom.Store().GetOracle().GetTimestamp(ctx, &oracle.Option{TxnScope: oracle.GlobalTxnScope})
+	}
+	r.add("pd", err)
+
+	if err == nil {
+		// The safe point is cached by the store, the check fails if the cache isn't refreshed from PD in time,
+		// because the reads may fall behind the safe point.
+		if checker, ok := dom.Store().(interface{ CheckVisibility(uint64) error }); ok {
+			err = checker.CheckVisibility(ts)
+		}
+	}
+	r.add("gc_safe_point", err)
+	return r
+}
@@ -0,0 +1,195 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/sessionctx/variable"
+)
+
+// localInfileReadSize is the size of the chunks the decompressed file of LOAD DATA LOCAL INFILE is read in.
+const localInfileReadSize = 64 * 1024
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// localInfileReader reads the file uploaded by LOAD DATA LOCAL INFILE from the packets sent by the client, the
+// file ends with an empty packet.
+type localInfileReader struct {
+	cc *clientConn
+	// peeked is the packet read for detecting the compression, it's returned by the next nextPacket.
+	peeked  []byte
+	buf     []byte
+	drained bool
+}
+
+// nextPacket returns the next non-empty packet of the file, it returns io.EOF after the empty packet is read.
+func (r *localInfileReader) nextPacket() ([]byte, error) {
+	if r.peeked != nil {
+		data := r.peeked
+		r.peeked = nil
+		return data, nil
+	}
+	if r.drained {
+		return nil, io.EOF
+	}
+	data, err := r.cc.readPacket()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		r.drained = true
+		return nil, io.EOF
+	}
+	return data, nil
+}
+
+// Read implements the io.Reader interface.
+func (r *localInfileReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		data, err := r.nextPacket()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = data
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// drain discards the packets left until the empty packet.
+func (r *localInfileReader) drain() error {
+	r.peeked, r.buf = nil, nil
+	for {
+		if _, err := r.nextPacket(); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// localInfileSource streams the content of the file uploaded by LOAD DATA LOCAL INFILE, the file is decompressed
+// on the fly if it's compressed, so the file is never buffered in memory as a whole.
+type localInfileSource struct {
+	r           *localInfileReader
+	compression string
+	inited      bool
+	decoder     io.ReadCloser
+	eof         bool
+}
+
+func newLocalInfileSource(cc *clientConn, compression string) *localInfileSource {
+	return &localInfileSource{r: &localInfileReader{cc: cc}, compression: compression}
+}
+
+// drained returns whether the empty packet ending the file has been read.
+func (s *localInfileSource) drained() bool {
+	return s.r.drained
+}
+
+func (s *localInfileSource) init() error {
+	s.inited = true
+	compression := s.compression
+	if compression == variable.LoadDataCompressionAuto {
+		data, err := s.r.nextPacket()
+		if err != nil {
+			return err
+		}
+		s.r.peeked = data
+		switch {
+		case bytes.HasPrefix(data, gzipMagic):
+			compression = variable.LoadDataCompressionGzip
+		case bytes.HasPrefix(data, zstdMagic):
+			compression = variable.LoadDataCompressionZstd
+		default:
+			compression = variable.LoadDataCompressionNone
+		}
+		s.compression = compression
+	}
+	switch compression {
+	case variable.LoadDataCompressionGzip:
+		decoder, err := gzip.NewReader(s.r)
+		if err != nil {
+			return err
+		}
+		s.decoder = decoder
+	case variable.LoadDataCompressionZstd:
+		decoder, err := zstd.NewReader(s.r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		s.decoder = decoder.IOReadCloser()
+	}
+	return nil
+}
+
+// next returns the next chunk of the file, it returns io.EOF at the end of the file. The chunks are never reused,
+// because the rows parsed from a chunk may refer to it.
+func (s *localInfileSource) next() ([]byte, error) {
+	if !s.inited {
+		if err := s.init(); err != nil {
+			return nil, err
+		}
+	}
+	if s.decoder == nil {
+		return s.r.nextPacket()
+	}
+	if s.eof {
+		return nil, io.EOF
+	}
+	buf := make([]byte, localInfileReadSize)
+	var n int
+	var err error
+	// Unlike io.ReadFull, a truncated file is reported by the io.ErrUnexpectedEOF of the decoder.
+	for n < len(buf) && err == nil {
+		var m int
+		m, err = s.decoder.Read(buf[n:])
+		n += m
+	}
+	switch err {
+	case nil:
+		return buf, nil
+	case io.EOF:
+		s.eof = true
+		// The client always sends the file until the empty packet, even if there is garbage after the
+		// compressed data.
+		if err = s.r.drain(); err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			return nil, io.EOF
+		}
+		return buf[:n], nil
+	default:
+		return nil, errors.Annotatef(err, "decompress the %s file", s.compression)
+	}
+}
+
+// Close releases the decoder.
+func (s *localInfileSource) Close() {
+	if s.decoder != nil {
+		terror.Log(s.decoder.Close())
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlx
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+)
+
+// The namespaces of StmtExecute, the statements of the admin namespaces are the admin commands.
+const (
+	NamespaceSQL     = "sql"
+	NamespaceMysqlx  = "mysqlx"
+	NamespaceXPlugin = "xplugin"
+)
+
+// CommandArgs gets the arguments of an admin command. The arguments are either the fields of an object, which is
+// the only argument, or the scalars in order.
+type CommandArgs struct {
+	cmd  string
+	args []*Any
+	obj  *Any
+	pos  int
+	err  error
+	used int
+}
+
+// NewCommandArgs creates the CommandArgs of the command.
+func NewCommandArgs(cmd string, args []*Any) *CommandArgs {
+	a := &CommandArgs{cmd: cmd, args: args}
+	if len(args) == 1 && args[0].Type == AnyObject {
+		a.obj = args[0]
+	}
+	return a
+}
+
+// Value gets an argument of any type, the optional argument is nil if it's missing.
+func (a *CommandArgs) Value(name string, optional bool) *Any {
+	if a.err != nil {
+		return nil
+	}
+	var v *Any
+	if a.obj != nil {
+		if v = a.obj.Field(name); v != nil {
+			a.used++
+		}
+	} else if a.pos < len(a.args) {
+		v = a.args[a.pos]
+		a.pos++
+	}
+	if v == nil && !optional {
+		a.err = ErrCmdNumArguments.GenByArgs(a.pos+1, len(a.args))
+		if a.obj != nil {
+			a.err = ErrCmdArgumentType.GenByArgs(name, a.cmd)
+		}
+	}
+	return v
+}
+
+// String gets a string argument, the optional argument is empty if it's missing.
+func (a *CommandArgs) String(name string, optional bool) string {
+	v := a.Value(name, optional)
+	if v == nil {
+		return ""
+	}
+	if v.Type != AnyScalar || (v.Scalar.Type != ScalarString && v.Scalar.Type != ScalarOctets) {
+		a.err = ErrCmdArgumentType.GenByArgs(name, a.cmd)
+		return ""
+	}
+	return string(v.Scalar.Bytes)
+}
+
+// UInt gets an unsigned integer argument, the optional argument is 0 if it's missing.
+func (a *CommandArgs) UInt(name string, optional bool) uint64 {
+	v := a.Value(name, optional)
+	if v == nil {
+		return 0
+	}
+	switch {
+	case v.Type != AnyScalar:
+	case v.Scalar.Type == ScalarUInt:
+		return v.Scalar.UInt
+	case v.Scalar.Type == ScalarSInt && v.Scalar.SInt >= 0:
+		return uint64(v.Scalar.SInt)
+	}
+	a.err = ErrCmdArgumentType.GenByArgs(name, a.cmd)
+	return 0
+}
+
+// Strings gets a list of strings, which is an array argument of an object, or the rest of the scalars in order.
+func (a *CommandArgs) Strings(name string, optional bool) []string {
+	var values []*Any
+	if a.obj != nil {
+		v := a.Value(name, optional)
+		if v == nil {
+			return nil
+		}
+		if v.Type != AnyArray {
+			a.err = ErrCmdArgumentType.GenByArgs(name, a.cmd)
+			return nil
+		}
+		values = v.Array
+	} else {
+		values = a.args[a.pos:]
+		a.pos = len(a.args)
+		if len(values) == 0 && !optional && a.err == nil {
+			a.err = ErrCmdNumArguments.GenByArgs(a.pos+1, len(a.args))
+		}
+	}
+	strs := make([]string, 0, len(values))
+	for _, v := range values {
+		if v.Type != AnyScalar || (v.Scalar.Type != ScalarString && v.Scalar.Type != ScalarOctets) {
+			if a.err == nil {
+				a.err = ErrCmdArgumentType.GenByArgs(name, a.cmd)
+			}
+			return nil
+		}
+		strs = append(strs, string(v.Scalar.Bytes))
+	}
+	return strs
+}
+
+// End checks there is no unknown argument, it returns the first error of getting the arguments.
+func (a *CommandArgs) End() error {
+	if a.err != nil {
+		return a.err
+	}
+	if a.obj != nil && a.used < len(a.obj.Object) {
+		return ErrCmdArgumentType.GenByArgs("unknown", a.cmd)
+	}
+	if a.obj == nil && a.pos < len(a.args) {
+		return ErrCmdNumArguments.GenByArgs(a.pos, len(a.args))
+	}
+	return nil
+}
+
+// CreateCollectionSQL returns the statement creating a collection. The documents are stored in the JSON column and
+// the primary key is their _id.
+func CreateCollectionSQL(schema, name string, ifNotExists bool) string {
+	g := &generator{}
+	g.write("CREATE TABLE ")
+	if ifNotExists {
+		g.write("IF NOT EXISTS ")
+	}
+	if schema != "" {
+		g.write("%n.", schema)
+	}
+	g.write("%n (%n JSON,%n VARBINARY(32) GENERATED ALWAYS AS (JSON_UNQUOTE(JSON_EXTRACT(%n,'$._id'))) STORED NOT NULL,PRIMARY KEY(%n))",
+		name, docColumn, idColumn, docColumn, idColumn)
+	return g.String()
+}
+
+// DropCollectionSQL returns the statement dropping a collection.
+func DropCollectionSQL(schema, name string) string {
+	return sqlFormat("DROP TABLE %n.%n", schema, name)
+}
+
+const idColumn = "_id"
+
+// IsCollection checks whether the table is a collection, which has only the columns of the documents and _id.
+func IsCollection(tbl *model.TableInfo) bool {
+	if len(tbl.Columns) != 2 {
+		return false
+	}
+	var hasDoc, hasID bool
+	for _, col := range tbl.Columns {
+		switch col.Name.L {
+		case docColumn:
+			hasDoc = col.Tp == mysql.TypeJSON
+		case idColumn:
+			hasID = col.IsGenerated()
+		}
+	}
+	return hasDoc && hasID
+}
+
+// DocumentIDGenerator generates the _id of the documents. Like MySQL, an id is 28 hex digits of a prefix, the
+// start time and a serial number, so the ids are unique and increasing on a server.
+type DocumentIDGenerator struct {
+	prefix uint16
+	start  uint64
+	serial uint64
+}
+
+// NewDocumentIDGenerator creates a DocumentIDGenerator, the prefix distinguishes the ids of the servers.
+func NewDocumentIDGenerator(prefix uint16) *DocumentIDGenerator {
+	return &DocumentIDGenerator{prefix: prefix, start: uint64(time.Now().Unix())}
+}
+
+// Next returns the next id.
+func (g *DocumentIDGenerator) Next() string {
+	return fmt.Sprintf("%04x%012x%012x", g.prefix, g.start, atomic.AddUint64(&g.serial, 1))
+}
@@ -0,0 +1,882 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlx
+
+import (
+	"github.com/pingcap/tidb/types/json"
+)
+
+// DataModel is the data model of the CRUD messages.
+type DataModel uint64
+
+// The data models. The collections are tables with a JSON column of the documents, the operations on the
+// documents are translated to the JSON functions on the column.
+const (
+	DataModelDocument DataModel = 1
+	DataModelTable    DataModel = 2
+)
+
+// Collection is Mysqlx.Crud.Collection, it's either a collection or a table.
+type Collection struct {
+	Name   string
+	Schema string
+}
+
+// Projection is Mysqlx.Crud.Projection.
+type Projection struct {
+	Source *Expr
+	Alias  string
+}
+
+// Column is Mysqlx.Crud.Column.
+type Column struct {
+	Name         string
+	Alias        string
+	DocumentPath []*DocumentPathItem
+}
+
+// Order is Mysqlx.Crud.Order.
+type Order struct {
+	Expr *Expr
+	Desc bool
+}
+
+// Limit is either Mysqlx.Crud.Limit or Mysqlx.Crud.LimitExpr.
+type Limit struct {
+	RowCount     uint64
+	Offset       uint64
+	RowCountExpr *Expr
+	OffsetExpr   *Expr
+}
+
+// The row locking of the Find messages.
+const (
+	LockingShared    uint64 = 1
+	LockingExclusive uint64 = 2
+)
+
+// Find is Mysqlx.Crud.Find.
+type Find struct {
+	Collection       Collection
+	DataModel        DataModel
+	Projection       []*Projection
+	Criteria         *Expr
+	Limit            *Limit
+	Order            []*Order
+	Grouping         []*Expr
+	GroupingCriteria *Expr
+	Args             []*Any
+	Locking          uint64
+}
+
+// Insert is Mysqlx.Crud.Insert.
+type Insert struct {
+	Collection Collection
+	DataModel  DataModel
+	Projection []*Column
+	Rows       [][]*Expr
+	Args       []*Any
+	Upsert     bool
+}
+
+// UpdateType is the type of an UpdateOperation.
+type UpdateType uint64
+
+// The types of the update operations.
+const (
+	UpdateSet         UpdateType = 1
+	UpdateItemRemove  UpdateType = 2
+	UpdateItemSet     UpdateType = 3
+	UpdateItemReplace UpdateType = 4
+	UpdateItemMerge   UpdateType = 5
+	UpdateArrayInsert UpdateType = 6
+	UpdateArrayAppend UpdateType = 7
+	UpdateMergePatch  UpdateType = 8
+)
+
+// UpdateOperation is Mysqlx.Crud.UpdateOperation.
+type UpdateOperation struct {
+	Source    *ColumnIdentifier
+	Operation UpdateType
+	Value     *Expr
+}
+
+// Update is Mysqlx.Crud.Update.
+type Update struct {
+	Collection Collection
+	DataModel  DataModel
+	Criteria   *Expr
+	Limit      *Limit
+	Order      []*Order
+	Operations []*UpdateOperation
+	Args       []*Any
+}
+
+// Delete is Mysqlx.Crud.Delete.
+type Delete struct {
+	Collection Collection
+	DataModel  DataModel
+	Criteria   *Expr
+	Limit      *Limit
+	Order      []*Order
+	Args       []*Any
+}
+
+func decodeCollection(data []byte) (Collection, error) {
+	var c Collection
+	d := decoder{data: data}
+	for d.next() {
+		switch d.field {
+		case 1:
+			c.Name = d.string()
+		case 2:
+			c.Schema = d.string()
+		default:
+			d.skip()
+		}
+	}
+	return c, d.err
+}
+
+func decodeProjection(data []byte) (*Projection, error) {
+	p := &Projection{}
+	d := decoder{data: data}
+	for d.next() {
+		switch d.field {
+		case 1:
+			d.message(func(data []byte) (err error) {
+				p.Source, err = decodeExpr(data)
+				return err
+			})
+		case 2:
+			p.Alias = d.string()
+		default:
+			d.skip()
+		}
+	}
+	return p, d.err
+}
+
+func decodeColumn(data []byte) (*Column, error) {
+	c := &Column{}
+	d := decoder{data: data}
+	for d.next() {
+		switch d.field {
+		case 1:
+			c.Name = d.string()
+		case 2:
+			c.Alias = d.string()
+		case 3:
+			d.message(func(data []byte) error {
+				item, err := decodeDocumentPathItem(data)
+				c.DocumentPath = append(c.DocumentPath, item)
+				return err
+			})
+		default:
+			d.skip()
+		}
+	}
+	return c, d.err
+}
+
+func decodeOrder(data []byte) (*Order, error) {
+	o := &Order{}
+	d := decoder{data: data}
+	for d.next() {
+		switch d.field {
+		case 1:
+			d.message(func(data []byte) (err error) {
+				o.Expr, err = decodeExpr(data)
+				return err
+			})
+		case 2:
+			o.Desc = d.varint() == 2
+		default:
+			d.skip()
+		}
+	}
+	return o, d.err
+}
+
+// decodeLimit decodes Mysqlx.Crud.Limit if isExpr is false, otherwise Mysqlx.Crud.LimitExpr.
+func decodeLimit(data []byte, isExpr bool) (*Limit, error) {
+	l := &Limit{}
+	d := decoder{data: data}
+	for d.next() {
+		switch {
+		case d.field == 1 && !isExpr:
+			l.RowCount = d.varint()
+		case d.field == 2 && !isExpr:
+			l.Offset = d.varint()
+		case d.field == 1:
+			d.message(func(data []byte) (err error) {
+				l.RowCountExpr, err = decodeExpr(data)
+				return err
+			})
+		case d.field == 2:
+			d.message(func(data []byte) (err error) {
+				l.OffsetExpr, err = decodeExpr(data)
+				return err
+			})
+		default:
+			d.skip()
+		}
+	}
+	return l, d.err
+}
+
+func (d *decoder) collection(c *Collection) {
+	d.message(func(data []byte) (err error) {
+		*c, err = decodeCollection(data)
+		return err
+	})
+}
+
+func (d *decoder) expr(e **Expr) {
+	d.message(func(data []byte) (err error) {
+		*e, err = decodeExpr(data)
+		return err
+	})
+}
+
+func (d *decoder) arg(args *[]*Any) {
+	d.message(func(data []byte) error {
+		a, err := decodeAny(data)
+		if err == nil && a.Type != AnyScalar {
+			err = ErrBadMessage.GenByArgs("the arguments must be scalars")
+		}
+		*args = append(*args, a)
+		return err
+	})
+}
+
+func (d *decoder) order(orders *[]*Order) {
+	d.message(func(data []byte) error {
+		o, err := decodeOrder(data)
+		*orders = append(*orders, o)
+		return err
+	})
+}
+
+func (d *decoder) limit(l **Limit, isExpr bool) {
+	d.message(func(data []byte) (err error) {
+		if *l != nil {
+			return ErrBadMessage.GenByArgs("both limit and limit_expr are set")
+		}
+		*l, err = decodeLimit(data, isExpr)
+		return err
+	})
+}
+
+// DecodeFind decodes Mysqlx.Crud.Find.
+func DecodeFind(data []byte) (*Find, error) {
+	m := &Find{}
+	d := decoder{data: data}
+	for d.next() {
+		switch d.field {
+		case 2:
+			d.collection(&m.Collection)
+		case 3:
+			m.DataModel = DataModel(d.varint())
+		case 4:
+			d.message(func(data []byte) error {
+				p, err := decodeProjection(data)
+				m.Projection = append(m.Projection, p)
+				return err
+			})
+		case 5:
+			d.expr(&m.Criteria)
+		case 6:
+			d.limit(&m.Limit, false)
+		case 7:
+			d.order(&m.Order)
+		case 8:
+			d.message(func(data []byte) error {
+				e, err := decodeExpr(data)
+				m.Grouping = append(m.Grouping, e)
+				return err
+			})
+		case 9:
+			d.expr(&m.GroupingCriteria)
+		case 11:
+			d.arg(&m.Args)
+		case 12:
+			m.Locking = d.varint()
+		case 14:
+			d.limit(&m.Limit, true)
+		default:
+			d.skip()
+		}
+	}
+	return m, d.err
+}
+
+// DecodeInsert decodes Mysqlx.Crud.Insert.
+func DecodeInsert(data []byte) (*Insert, error) {
+	m := &Insert{}
+	d := decoder{data: data}
+	for d.next() {
+		switch d.field {
+		case 1:
+			d.collection(&m.Collection)
+		case 2:
+			m.DataModel = DataModel(d.varint())
+		case 3:
+			d.message(func(data []byte) error {
+				c, err := decodeColumn(data)
+				m.Projection = append(m.Projection, c)
+				return err
+			})
+		case 4:
+			d.message(func(data []byte) error {
+				row, err := decodeExprList(data, 1)
+				m.Rows = append(m.Rows, row)
+				return err
+			})
+		case 5:
+			d.arg(&m.Args)
+		case 6:
+			m.Upsert = d.boolean()
+		default:
+			d.skip()
+		}
+	}
+	return m, d.err
+}
+
+// DecodeUpdate decodes Mysqlx.Crud.Update.
+func DecodeUpdate(data []byte) (*Update, error) {
+	m := &Update{}
+	d := decoder{data: data}
+	for d.next() {
+		switch d.field {
+		case 2:
+			d.collection(&m.Collection)
+		case 3:
+			m.DataModel = DataModel(d.varint())
+		case 4:
+			d.expr(&m.Criteria)
+		case 5:
+			d.limit(&m.Limit, false)
+		case 6:
+			d.order(&m.Order)
+		case 7:
+			d.message(func(data []byte) error {
+				op := &UpdateOperation{}
+				d := decoder{data: data}
+				for d.next() {
+					switch d.field {
+					case 1:
+						d.message(func(data []byte) (err error) {
+							op.Source, err = decodeColumnIdentifier(data)
+							return err
+						})
+					case 2:
+						op.Operation = UpdateType(d.varint())
+					case 3:
+						d.expr(&op.Value)
+					default:
+						d.skip()
+					}
+				}
+				m.Operations = append(m.Operations, op)
+				return d.err
+			})
+		case 8:
+			d.arg(&m.Args)
+		case 9:
+			d.limit(&m.Limit, true)
+		default:
+			d.skip()
+		}
+	}
+	return m, d.err
+}
+
+// DecodeDelete decodes Mysqlx.Crud.Delete.
+func DecodeDelete(data []byte) (*Delete, error) {
+	m := &Delete{}
+	d := decoder{data: data}
+	for d.next() {
+		switch d.field {
+		case 1:
+			d.collection(&m.Collection)
+		case 2:
+			m.DataModel = DataModel(d.varint())
+		case 3:
+			d.expr(&m.Criteria)
+		case 4:
+			d.limit(&m.Limit, false)
+		case 5:
+			d.order(&m.Order)
+		case 6:
+			d.arg(&m.Args)
+		case 7:
+			d.limit(&m.Limit, true)
+		default:
+			d.skip()
+		}
+	}
+	return m, d.err
+}
+
+func newGenerator(dataModel DataModel, args []*Any) (*generator, error) {
+	if dataModel != DataModelDocument && dataModel != DataModelTable {
+		return nil, ErrBadMessage.GenByArgs("invalid data model")
+	}
+	return &generator{documentMode: dataModel == DataModelDocument, args: args}, nil
+}
+
+func (g *generator) collection(c Collection) error {
+	if c.Name == "" {
+		return ErrInvalidCollection.GenByArgs("name")
+	}
+	if c.Schema != "" {
+		g.write("%n.", c.Schema)
+	}
+	g.write("%n", c.Name)
+	return nil
+}
+
+func (g *generator) where(criteria *Expr) error {
+	if criteria == nil {
+		return nil
+	}
+	g.write(" WHERE ")
+	return g.expr(criteria)
+}
+
+func (g *generator) orderBy(orders []*Order) error {
+	for i, o := range orders {
+		if i == 0 {
+			g.write(" ORDER BY ")
+		} else {
+			g.write(",")
+		}
+		if err := g.expr(o.Expr); err != nil {
+			return err
+		}
+		if o.Desc {
+			g.write(" DESC")
+		}
+	}
+	return nil
+}
+
+// limit generates the LIMIT clause, the offset is only allowed if allowOffset is true.
+func (g *generator) limit(l *Limit, allowOffset bool) error {
+	if l == nil {
+		return nil
+	}
+	rowCount, offset := l.RowCount, l.Offset
+	var err error
+	if l.RowCountExpr != nil {
+		if rowCount, err = g.uintValue(l.RowCountExpr); err != nil {
+			return err
+		}
+	}
+	if l.OffsetExpr != nil {
+		if offset, err = g.uintValue(l.OffsetExpr); err != nil {
+			return err
+		}
+	}
+	if offset != 0 && !allowOffset {
+		return ErrBadMessage.GenByArgs("invalid position of the limit offset")
+	}
+	g.write(" LIMIT %?", rowCount)
+	if offset != 0 {
+		g.write(" OFFSET %?", offset)
+	}
+	return nil
+}
+
+func (g *generator) uintValue(e *Expr) (uint64, error) {
+	s := literalScalar(e, g.args)
+	switch {
+	case s == nil:
+	case s.Type == ScalarUInt:
+		return s.UInt, nil
+	case s.Type == ScalarSInt && s.SInt >= 0:
+		return uint64(s.SInt), nil
+	}
+	return 0, ErrExprBadValue.GenByArgs("the limit must be a non-negative integer")
+}
+
+// SQL translates the Find message into a SELECT statement.
+func (m *Find) SQL() (string, error) {
+	g, err := newGenerator(m.DataModel, m.Args)
+	if err != nil {
+		return "", err
+	}
+	g.write("SELECT ")
+	if err = g.projection(m.Projection); err != nil {
+		return "", err
+	}
+	g.write(" FROM ")
+	if err = g.collection(m.Collection); err != nil {
+		return "", err
+	}
+	if err = g.where(m.Criteria); err != nil {
+		return "", err
+	}
+	if len(m.Grouping) > 0 {
+		g.write(" GROUP BY ")
+		if err = g.exprList(m.Grouping); err != nil {
+			return "", err
+		}
+	}
+	if m.GroupingCriteria != nil {
+		g.write(" HAVING ")
+		if err = g.expr(m.GroupingCriteria); err != nil {
+			return "", err
+		}
+	}
+	if err = g.orderBy(m.Order); err != nil {
+		return "", err
+	}
+	if err = g.limit(m.Limit, true); err != nil {
+		return "", err
+	}
+	switch m.Locking {
+	case LockingShared:
+		g.write(" LOCK IN SHARE MODE")
+	case LockingExclusive:
+		g.write(" FOR UPDATE")
+	}
+	return g.String(), nil
+}
+
+// projection generates the fields of the SELECT statement. In the document mode, the projection builds a new
+// document whose members are the aliases.
+func (g *generator) projection(projection []*Projection) error {
+	if len(projection) == 0 {
+		if g.documentMode {
+			g.write("%n", docColumn)
+		} else {
+			g.write("*")
+		}
+		return nil
+	}
+	if !g.documentMode {
+		for i, p := range projection {
+			if i > 0 {
+				g.write(",")
+			}
+			if err := g.expr(p.Source); err != nil {
+				return err
+			}
+			if p.Alias != "" {
+				g.write(" AS %n", p.Alias)
+			}
+		}
+		return nil
+	}
+	g.write("JSON_OBJECT(")
+	for i, p := range projection {
+		alias := p.Alias
+		if alias == "" && p.Source != nil && p.Source.Type == ExprIdent && p.Source.Identifier != nil {
+			path := p.Source.Identifier.DocumentPath
+			if len(path) > 0 && path[len(path)-1].Type == PathMember {
+				alias = path[len(path)-1].Value
+			}
+		}
+		if alias == "" {
+			return ErrBadProjection.GenByArgs()
+		}
+		if i > 0 {
+			g.write(",")
+		}
+		g.write("%?,", alias)
+		if err := g.expr(p.Source); err != nil {
+			return err
+		}
+	}
+	g.write(") AS %n", docColumn)
+	return nil
+}
+
+// SQL translates the Insert message into an INSERT statement. The documents without _id are assigned the ids
+// generated by genID, which are returned.
+func (m *Insert) SQL(genID func() string) (sql string, ids []string, err error) {
+	g, err := newGenerator(m.DataModel, m.Args)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(m.Rows) == 0 {
+		return "", nil, ErrBadMessage.GenByArgs("missing row data for insert")
+	}
+	g.write("INSERT INTO ")
+	if err = g.collection(m.Collection); err != nil {
+		return "", nil, err
+	}
+	if g.documentMode {
+		if len(m.Projection) > 0 {
+			return "", nil, ErrBadMessage.GenByArgs("invalid projection for document operation")
+		}
+		g.write(" (%n) VALUES ", docColumn)
+		for i, row := range m.Rows {
+			if len(row) != 1 {
+				return "", nil, ErrBadInsertData.GenByArgs("one document is expected in a row")
+			}
+			if i > 0 {
+				g.write(",")
+			}
+			g.write("(")
+			id, err := g.document(row[0], genID)
+			if err != nil {
+				return "", nil, err
+			}
+			g.write(")")
+			if id != "" {
+				ids = append(ids, id)
+			}
+		}
+		if m.Upsert {
+			g.write(" ON DUPLICATE KEY UPDATE %n = VALUES(%n)", docColumn, docColumn)
+		}
+		return g.String(), ids, nil
+	}
+	if m.Upsert {
+		return "", nil, ErrBadMessage.GenByArgs("unable to update on duplicate key for the table data model")
+	}
+	if len(m.Projection) > 0 {
+		g.write(" (")
+		for i, c := range m.Projection {
+			if c.Name == "" || len(c.DocumentPath) > 0 {
+				return "", nil, ErrBadProjection.GenByArgs()
+			}
+			if i > 0 {
+				g.write(",")
+			}
+			g.write("%n", c.Name)
+		}
+		g.write(")")
+	}
+	g.write(" VALUES ")
+	for i, row := range m.Rows {
+		if len(m.Projection) > 0 && len(row) != len(m.Projection) {
+			return "", nil, ErrBadInsertData.GenByArgs("the number of the fields doesn't match the projection")
+		}
+		if i > 0 {
+			g.write(",")
+		}
+		g.write("(")
+		if err = g.exprList(row); err != nil {
+			return "", nil, err
+		}
+		g.write(")")
+	}
+	return g.String(), nil, nil
+}
+
+// document generates a document to insert. If the document may not have an _id, it's inserted by JSON_INSERT,
+// which keeps the existing _id, and the id is returned.
+func (g *generator) document(e *Expr, genID func() string) (string, error) {
+	switch e.Type {
+	case ExprObject:
+		for _, fld := range e.Object {
+			if fld.Key == "_id" {
+				return "", g.expr(e)
+			}
+		}
+	case ExprLiteral, ExprPlaceholder:
+		s := literalScalar(e, g.args)
+		if s == nil {
+			return "", g.expr(e)
+		}
+		if s.Type != ScalarOctets && s.Type != ScalarString {
+			return "", ErrBadInsertData.GenByArgs("the document must be an object")
+		}
+		doc, err := json.ParseBinaryFromString(string(s.Bytes))
+		if err != nil || doc.TypeCode != json.TypeCodeObject {
+			return "", ErrBadInsertData.GenByArgs("the document must be an object")
+		}
+		if _, ok := doc.Extract([]json.PathExpression{idPath}); ok {
+			g.write("CAST(%? AS JSON)", string(s.Bytes))
+			return "", nil
+		}
+		id := genID()
+		g.write("JSON_INSERT(CAST(%? AS JSON),'$._id',%?)", string(s.Bytes), id)
+		return id, nil
+	}
+	id := genID()
+	g.write("JSON_INSERT(")
+	if err := g.expr(e); err != nil {
+		return "", err
+	}
+	g.write(",'$._id',%?)", id)
+	return id, nil
+}
+
+var idPath = func() json.PathExpression {
+	path, err := json.ParseJSONPathExpr("$._id")
+	if err != nil {
+		panic(err)
+	}
+	return path
+}()
+
+// SQL translates the Update message into an UPDATE statement.
+func (m *Update) SQL() (string, error) {
+	g, err := newGenerator(m.DataModel, m.Args)
+	if err != nil {
+		return "", err
+	}
+	if len(m.Operations) == 0 {
+		return "", ErrBadUpdateData.GenByArgs("missing update operations")
+	}
+	g.write("UPDATE ")
+	if err = g.collection(m.Collection); err != nil {
+		return "", err
+	}
+	// The operations on the same column are chained, the later ones are applied to the results of the former.
+	var columns []string
+	values := make(map[string]string)
+	for _, op := range m.Operations {
+		if op.Source == nil {
+			return "", ErrBadUpdateData.GenByArgs("missing source")
+		}
+		column := op.Source.Name
+		if g.documentMode {
+			if column != "" || op.Source.TableName != "" || op.Source.SchemaName != "" {
+				return "", ErrBadUpdateData.GenByArgs("the source of a document can't have a column")
+			}
+			column = docColumn
+		} else if column == "" {
+			return "", ErrBadUpdateData.GenByArgs("missing column")
+		}
+		value, ok := values[column]
+		if !ok {
+			value = sqlFormat("%n", column)
+			columns = append(columns, column)
+		}
+		if values[column], err = g.updateValue(value, op); err != nil {
+			return "", err
+		}
+	}
+	for i, column := range columns {
+		if i == 0 {
+			g.write(" SET ")
+		} else {
+			g.write(",")
+		}
+		g.write("%n=", column)
+		g.raw(values[column])
+	}
+	if err = g.where(m.Criteria); err != nil {
+		return "", err
+	}
+	if err = g.orderBy(m.Order); err != nil {
+		return "", err
+	}
+	if err = g.limit(m.Limit, false); err != nil {
+		return "", err
+	}
+	return g.String(), nil
+}
+
+// updateValue returns the new value of the column after the operation.
+func (g *generator) updateValue(value string, op *UpdateOperation) (string, error) {
+	var path string
+	if len(op.Source.DocumentPath) > 0 {
+		var err error
+		if path, err = DocumentPath(op.Source.DocumentPath); err != nil {
+			return "", err
+		}
+	}
+	sub := &generator{documentMode: g.documentMode, args: g.args}
+	valueSQL := func(asJSON bool) (string, error) {
+		var err error
+		if asJSON {
+			err = sub.jsonExpr(op.Value)
+		} else {
+			err = sub.expr(op.Value)
+		}
+		return sub.String(), err
+	}
+	if op.Value == nil && op.Operation != UpdateItemRemove {
+		return "", ErrBadUpdateData.GenByArgs("missing value")
+	}
+	switch op.Operation {
+	case UpdateSet:
+		if g.documentMode || path != "" {
+			return "", ErrBadUpdateData.GenByArgs("invalid type of update operation for document")
+		}
+		return valueSQL(false)
+	case UpdateMergePatch:
+		// JSON_MERGE_PATCH isn't supported by TiDB yet.
+		return "", ErrBadUpdateData.GenByArgs("merge patch is not supported")
+	case UpdateItemMerge:
+		if path != "" {
+			return "", ErrBadUpdateData.GenByArgs("the merge operations can't have a document path")
+		}
+		v, err := valueSQL(true)
+		if err != nil {
+			return "", err
+		}
+		merged := "JSON_MERGE_PRESERVE(" + value + "," + v + ")"
+		if g.documentMode {
+			// The _id of the documents can't be changed.
+			return "JSON_SET(" + merged + ",'$._id',JSON_EXTRACT(" + value + ",'$._id'))", nil
+		}
+		return merged, nil
+	case UpdateItemRemove, UpdateItemSet, UpdateItemReplace, UpdateArrayInsert, UpdateArrayAppend:
+		if path == "" || path == "$" {
+			return "", ErrBadDocumentPath.GenByArgs("$")
+		}
+		if g.documentMode && path == "$._id" {
+			return "", ErrBadUpdateData.GenByArgs("forbidden update operation on '$._id' member")
+		}
+		if op.Operation == UpdateArrayInsert && op.Source.DocumentPath[len(op.Source.DocumentPath)-1].Type != PathArrayIndex {
+			return "", ErrBadUpdateData.GenByArgs("the path of array insert must end with an array index")
+		}
+		if op.Operation == UpdateItemRemove {
+			return "JSON_REMOVE(" + value + "," + sqlFormat("%?", path) + ")", nil
+		}
+		v, err := valueSQL(false)
+		if err != nil {
+			return "", err
+		}
+		fn := map[UpdateType]string{
+			UpdateItemSet:     "JSON_SET",
+			UpdateItemReplace: "JSON_REPLACE",
+			UpdateArrayInsert: "JSON_ARRAY_INSERT",
+			UpdateArrayAppend: "JSON_ARRAY_APPEND",
+		}[op.Operation]
+		return fn + "(" + value + "," + sqlFormat("%?", path) + "," + v + ")", nil
+	}
+	return "", ErrBadUpdateData.GenByArgs("invalid type of update operation")
+}
+
+// SQL translates the Delete message into a DELETE statement.
+func (m *Delete) SQL() (string, error) {
+	g, err := newGenerator(m.DataModel, m.Args)
+	if err != nil {
+		return "", err
+	}
+	g.write("DELETE FROM ")
+	if err = g.collection(m.Collection); err != nil {
+		return "", err
+	}
+	if err = g.where(m.Criteria); err != nil {
+		return "", err
+	}
+	if err = g.orderBy(m.Order); err != nil {
+		return "", err
+	}
+	if err = g.limit(m.Limit, false); err != nil {
+		return "", err
+	}
+	return g.String(), nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlx
+
+import (
+	"fmt"
+
+	"github.com/pingcap/errors"
+)
+
+// Error is the error sent to the clients by the Error message.
+type Error struct {
+	Code     uint16
+	SQLState string
+	Message  string
+	// Fatal means the connection is closed after the error is sent.
+	Fatal bool
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("[mysqlx:%d]%s", e.Code, e.Message)
+}
+
+// ErrorClass is a kind of Error with the same code and message format.
+type ErrorClass struct {
+	code     uint16
+	sqlState string
+	format   string
+}
+
+// GenByArgs generates an Error of this class with the arguments of the message format.
+func (c ErrorClass) GenByArgs(args ...interface{}) *Error {
+	return &Error{Code: c.code, SQLState: c.sqlState, Message: fmt.Sprintf(c.format, args...)}
+}
+
+// Equal checks whether err is of this class.
+func (c ErrorClass) Equal(err error) bool {
+	e, ok := errors.Cause(err).(*Error)
+	return ok && e.Code == c.code
+}
+
+// The errors of the X Protocol, the codes are the same as MySQL.
+var (
+	ErrUnexpectedMessage     = ErrorClass{1047, "08S01", "Unexpected message received: %d"}
+	ErrPacketTooLarge        = ErrorClass{1153, "08S01", "Got a packet bigger than 'mysqlx_max_allowed_packet' bytes"}
+	ErrNotSupportedAuthMode  = ErrorClass{1251, "08004", "Invalid authentication method %s"}
+	ErrBadMessage            = ErrorClass{5000, "HY000", "Invalid message: %s"}
+	ErrCapabilitiesPrepare   = ErrorClass{5001, "HY000", "Capability prepare failed for '%s'"}
+	ErrCapabilityNotFound    = ErrorClass{5002, "HY000", "Capability '%s' doesn't exist"}
+	ErrCmdNumArguments       = ErrorClass{5015, "HY000", "Invalid number of arguments, expected %d but got %d"}
+	ErrCmdArgumentType       = ErrorClass{5016, "HY000", "Invalid type for argument '%s' to %s"}
+	ErrBadUpdateData         = ErrorClass{5050, "HY000", "Invalid data for update operation on document collection table: %s"}
+	ErrBadProjection         = ErrorClass{5114, "HY000", "Invalid projection target name"}
+	ErrBadInsertData         = ErrorClass{5115, "HY000", "Invalid document: %s"}
+	ErrExprBadOperator       = ErrorClass{5150, "HY000", "Invalid operator %s"}
+	ErrExprBadNumArgs        = ErrorClass{5151, "HY000", "Invalid number of arguments for operator %s"}
+	ErrExprMissingArg        = ErrorClass{5152, "HY000", "Invalid value for placeholder %d"}
+	ErrExprBadTypeValue      = ErrorClass{5153, "HY000", "Invalid value type %d"}
+	ErrExprBadValue          = ErrorClass{5154, "HY000", "Invalid value: %s"}
+	ErrInvalidCollection     = ErrorClass{5156, "HY000", "Invalid collection %s"}
+	ErrInvalidAdminCommand   = ErrorClass{5157, "HY000", "Invalid mysqlx command %s"}
+	ErrExpectNotOpen         = ErrorClass{5158, "HY000", "Expect block currently not open"}
+	ErrExpectNoErrorFailed   = ErrorClass{5159, "HY000", "Expectation failed: no_error"}
+	ErrExpectBadCondition    = ErrorClass{5160, "HY000", "Unknown condition key %d"}
+	ErrInvalidNamespace      = ErrorClass{5162, "HY000", "Unknown namespace %s"}
+	ErrBadDocumentPath       = ErrorClass{5163, "HY000", "Invalid document path %s"}
+	ErrUnsupportedExpression = ErrorClass{5164, "HY000", "Unsupported expression: %s"}
+)
@@ -0,0 +1,773 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlx
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// ExprType is the type of an Expr.
+type ExprType uint64
+
+// The types of the expressions.
+const (
+	ExprIdent       ExprType = 1
+	ExprLiteral     ExprType = 2
+	ExprVariable    ExprType = 3
+	ExprFuncCall    ExprType = 4
+	ExprOperator    ExprType = 5
+	ExprPlaceholder ExprType = 6
+	ExprObject      ExprType = 7
+	ExprArray       ExprType = 8
+)
+
+// Expr is Mysqlx.Expr.Expr.
+type Expr struct {
+	Type         ExprType
+	Identifier   *ColumnIdentifier
+	Variable     string
+	Literal      *Scalar
+	FunctionCall *FunctionCall
+	Operator     *Operator
+	Position     uint32
+	Object       []*ExprObjectField
+	Array        []*Expr
+}
+
+// ExprObjectField is a field of an object expression.
+type ExprObjectField struct {
+	Key   string
+	Value *Expr
+}
+
+// ColumnIdentifier is Mysqlx.Expr.ColumnIdentifier.
+type ColumnIdentifier struct {
+	DocumentPath []*DocumentPathItem
+	Name         string
+	TableName    string
+	SchemaName   string
+}
+
+// DocumentPathItemType is the type of a DocumentPathItem.
+type DocumentPathItemType uint64
+
+// The types of the document path items.
+const (
+	PathMember             DocumentPathItemType = 1
+	PathMemberAsterisk     DocumentPathItemType = 2
+	PathArrayIndex         DocumentPathItemType = 3
+	PathArrayIndexAsterisk DocumentPathItemType = 4
+	PathDoubleAsterisk     DocumentPathItemType = 5
+)
+
+// DocumentPathItem is Mysqlx.Expr.DocumentPathItem.
+type DocumentPathItem struct {
+	Type  DocumentPathItemType
+	Value string
+	Index uint32
+}
+
+// FunctionCall is Mysqlx.Expr.FunctionCall.
+type FunctionCall struct {
+	Name   string
+	Schema string
+	Params []*Expr
+}
+
+// Operator is Mysqlx.Expr.Operator.
+type Operator struct {
+	Name   string
+	Params []*Expr
+}
+
+func decodeExpr(data []byte) (*Expr, error) {
+	e := &Expr{}
+	d := decoder{data: data}
+	for d.next() {
+		switch d.field {
+		case 1:
+			e.Type = ExprType(d.varint())
+		case 2:
+			d.message(func(data []byte) (err error) {
+				e.Identifier, err = decodeColumnIdentifier(data)
+				return err
+			})
+		case 3:
+			e.Variable = d.string()
+		case 4:
+			d.message(func(data []byte) (err error) {
+				e.Literal, err = decodeScalar(data)
+				return err
+			})
+		case 5:
+			d.message(func(data []byte) (err error) {
+				e.FunctionCall, err = decodeFunctionCall(data)
+				return err
+			})
+		case 6:
+			d.message(func(data []byte) (err error) {
+				e.Operator, err = decodeOperator(data)
+				return err
+			})
+		case 7:
+			e.Position = uint32(d.varint())
+		case 8:
+			d.message(func(data []byte) error {
+				d := decoder{data: data}
+				for d.next() {
+					if d.field != 1 {
+						d.skip()
+						continue
+					}
+					d.message(func(data []byte) error {
+						fld := &ExprObjectField{}
+						d := decoder{data: data}
+						for d.next() {
+							switch d.field {
+							case 1:
+								fld.Key = d.string()
+							case 2:
+								d.message(func(data []byte) (err error) {
+									fld.Value, err = decodeExpr(data)
+									return err
+								})
+							default:
+								d.skip()
+							}
+						}
+						e.Object = append(e.Object, fld)
+						return d.err
+					})
+				}
+				return d.err
+			})
+		case 9:
+			d.message(func(data []byte) error {
+				var err error
+				e.Array, err = decodeExprList(data, 1)
+				return err
+			})
+		default:
+			d.skip()
+		}
+	}
+	return e, d.err
+}
+
+// decodeExprList decodes the expressions of the repeated field.
+func decodeExprList(data []byte, field int) ([]*Expr, error) {
+	var exprs []*Expr
+	d := decoder{data: data}
+	for d.next() {
+		if d.field != field {
+			d.skip()
+			continue
+		}
+		d.message(func(data []byte) error {
+			e, err := decodeExpr(data)
+			exprs = append(exprs, e)
+			return err
+		})
+	}
+	return exprs, d.err
+}
+
+func decodeColumnIdentifier(data []byte) (*ColumnIdentifier, error) {
+	id := &ColumnIdentifier{}
+	d := decoder{data: data}
+	for d.next() {
+		switch d.field {
+		case 1:
+			d.message(func(data []byte) error {
+				item, err := decodeDocumentPathItem(data)
+				id.DocumentPath = append(id.DocumentPath, item)
+				return err
+			})
+		case 2:
+			id.Name = d.string()
+		case 3:
+			id.TableName = d.string()
+		case 4:
+			id.SchemaName = d.string()
+		default:
+			d.skip()
+		}
+	}
+	return id, d.err
+}
+
+func decodeDocumentPathItem(data []byte) (*DocumentPathItem, error) {
+	item := &DocumentPathItem{}
+	d := decoder{data: data}
+	for d.next() {
+		switch d.field {
+		case 1:
+			item.Type = DocumentPathItemType(d.varint())
+		case 2:
+			item.Value = d.string()
+		case 3:
+			item.Index = uint32(d.varint())
+		default:
+			d.skip()
+		}
+	}
+	return item, d.err
+}
+
+func decodeFunctionCall(data []byte) (*FunctionCall, error) {
+	f := &FunctionCall{}
+	d := decoder{data: data}
+	for d.next() {
+		switch d.field {
+		case 1:
+			d.message(func(data []byte) error {
+				d := decoder{data: data}
+				for d.next() {
+					switch d.field {
+					case 1:
+						f.Name = d.string()
+					case 2:
+						f.Schema = d.string()
+					default:
+						d.skip()
+					}
+				}
+				return d.err
+			})
+		case 2:
+			d.message(func(data []byte) error {
+				e, err := decodeExpr(data)
+				f.Params = append(f.Params, e)
+				return err
+			})
+		default:
+			d.skip()
+		}
+	}
+	return f, d.err
+}
+
+func decodeOperator(data []byte) (*Operator, error) {
+	op := &Operator{}
+	d := decoder{data: data}
+	for d.next() {
+		switch d.field {
+		case 1:
+			op.Name = d.string()
+		case 2:
+			d.message(func(data []byte) error {
+				e, err := decodeExpr(data)
+				op.Params = append(op.Params, e)
+				return err
+			})
+		default:
+			d.skip()
+		}
+	}
+	return op, d.err
+}
+
+// docColumn is the column storing the documents of the collections.
+const docColumn = "doc"
+
+// The binary operators which are written infix.
+var infixOperators = map[string]string{
+	"==":         "=",
+	"!=":         "!=",
+	"<>":         "<>",
+	"<":          "<",
+	"<=":         "<=",
+	">":          ">",
+	">=":         ">=",
+	"&&":         "AND",
+	"||":         "OR",
+	"xor":        "XOR",
+	"+":          "+",
+	"-":          "-",
+	"*":          "*",
+	"/":          "/",
+	"div":        "DIV",
+	"%":          "%",
+	"<<":         "<<",
+	">>":         ">>",
+	"&":          "&",
+	"|":          "|",
+	"^":          "^",
+	"regexp":     "REGEXP",
+	"not_regexp": "NOT REGEXP",
+}
+
+// The unary operators which are written prefix.
+var prefixOperators = map[string]string{
+	"!":          "!",
+	"not":        "NOT ",
+	"~":          "~",
+	"sign_plus":  "+",
+	"sign_minus": "-",
+}
+
+var (
+	identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	castTypeRegexp   = regexp.MustCompile(`^(?i)(BINARY|CHAR|DATE|DATETIME|TIME|JSON|DECIMAL|SIGNED|UNSIGNED|SIGNED INTEGER|UNSIGNED INTEGER|BINARY\(\d+\)|CHAR\(\d+\)|DECIMAL\(\d+\)|DECIMAL\(\d+,\d+\))$`)
+	intervalUnits    = map[string]struct{}{
+		"MICROSECOND": {}, "SECOND": {}, "MINUTE": {}, "HOUR": {}, "DAY": {}, "WEEK": {}, "MONTH": {},
+		"QUARTER": {}, "YEAR": {}, "SECOND_MICROSECOND": {}, "MINUTE_MICROSECOND": {}, "MINUTE_SECOND": {},
+		"HOUR_MICROSECOND": {}, "HOUR_SECOND": {}, "HOUR_MINUTE": {}, "DAY_MICROSECOND": {}, "DAY_SECOND": {},
+		"DAY_MINUTE": {}, "DAY_HOUR": {}, "YEAR_MONTH": {},
+	}
+)
+
+// generator generates the SQL of the expressions. In the document mode, the identifiers without names refer to
+// the members of the documents.
+type generator struct {
+	sb           strings.Builder
+	documentMode bool
+	args         []*Any
+}
+
+func (g *generator) write(sql string, args ...interface{}) {
+	sqlexec.MustFormatSQL(&g.sb, sql, args...)
+}
+
+// raw writes the SQL which has been formatted.
+func (g *generator) raw(sql string) {
+	g.sb.WriteString(sql)
+}
+
+func sqlFormat(sql string, args ...interface{}) string {
+	return sqlexec.MustEscapeSQL(sql, args...)
+}
+
+func (g *generator) String() string {
+	return g.sb.String()
+}
+
+func (g *generator) expr(e *Expr) error {
+	if e == nil {
+		return ErrBadMessage.GenByArgs("missing expression")
+	}
+	switch e.Type {
+	case ExprIdent:
+		if e.Identifier == nil {
+			return ErrBadMessage.GenByArgs("missing identifier")
+		}
+		return g.columnIdentifier(e.Identifier)
+	case ExprLiteral:
+		if e.Literal == nil {
+			return ErrBadMessage.GenByArgs("missing literal")
+		}
+		return g.scalar(e.Literal)
+	case ExprVariable:
+		return ErrUnsupportedExpression.GenByArgs("variable")
+	case ExprFuncCall:
+		if e.FunctionCall == nil {
+			return ErrBadMessage.GenByArgs("missing function call")
+		}
+		return g.functionCall(e.FunctionCall)
+	case ExprOperator:
+		if e.Operator == nil {
+			return ErrBadMessage.GenByArgs("missing operator")
+		}
+		return g.operator(e.Operator)
+	case ExprPlaceholder:
+		if int(e.Position) >= len(g.args) {
+			return ErrExprMissingArg.GenByArgs(e.Position)
+		}
+		arg := g.args[e.Position]
+		if arg.Type != AnyScalar {
+			return ErrExprBadTypeValue.GenByArgs(arg.Type)
+		}
+		return g.scalar(arg.Scalar)
+	case ExprObject:
+		g.write("JSON_OBJECT(")
+		for i, fld := range e.Object {
+			if i > 0 {
+				g.write(",")
+			}
+			g.write("%?,", fld.Key)
+			if err := g.expr(fld.Value); err != nil {
+				return err
+			}
+		}
+		g.write(")")
+		return nil
+	case ExprArray:
+		g.write("JSON_ARRAY(")
+		if err := g.exprList(e.Array); err != nil {
+			return err
+		}
+		g.write(")")
+		return nil
+	}
+	return ErrExprBadTypeValue.GenByArgs(e.Type)
+}
+
+func (g *generator) exprList(exprs []*Expr) error {
+	for i, e := range exprs {
+		if i > 0 {
+			g.write(",")
+		}
+		if err := g.expr(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *generator) scalar(s *Scalar) error {
+	switch s.Type {
+	case ScalarSInt:
+		g.write("%?", s.SInt)
+	case ScalarUInt:
+		g.write("%?", s.UInt)
+	case ScalarNull:
+		g.write("NULL")
+	case ScalarOctets:
+		if s.ContentType == ContentTypeJSON {
+			g.write("CAST(%? AS JSON)", string(s.Bytes))
+		} else {
+			g.write("%?", string(s.Bytes))
+		}
+	case ScalarDouble:
+		if math.IsNaN(s.Double) || math.IsInf(s.Double, 0) {
+			return ErrExprBadValue.GenByArgs(strconv.FormatFloat(s.Double, 'g', -1, 64))
+		}
+		g.write("%?", s.Double)
+	case ScalarFloat:
+		if math.IsNaN(float64(s.Float)) || math.IsInf(float64(s.Float), 0) {
+			return ErrExprBadValue.GenByArgs(strconv.FormatFloat(float64(s.Float), 'g', -1, 32))
+		}
+		g.write("%?", s.Float)
+	case ScalarBool:
+		if s.Bool {
+			g.write("TRUE")
+		} else {
+			g.write("FALSE")
+		}
+	case ScalarString:
+		g.write("%?", string(s.Bytes))
+	default:
+		return ErrExprBadTypeValue.GenByArgs(s.Type)
+	}
+	return nil
+}
+
+func (g *generator) columnIdentifier(id *ColumnIdentifier) error {
+	if id.Name == "" && !g.documentMode {
+		return ErrBadDocumentPath.GenByArgs("without a column in the table mode")
+	}
+	if len(id.DocumentPath) > 0 {
+		g.write("JSON_EXTRACT(")
+	}
+	if id.SchemaName != "" {
+		g.write("%n.", id.SchemaName)
+	}
+	if id.TableName != "" {
+		g.write("%n.", id.TableName)
+	}
+	if id.Name != "" {
+		g.write("%n", id.Name)
+	} else {
+		g.write("%n", docColumn)
+	}
+	if len(id.DocumentPath) > 0 {
+		path, err := DocumentPath(id.DocumentPath)
+		if err != nil {
+			return err
+		}
+		g.write(",%?)", path)
+	}
+	return nil
+}
+
+// DocumentPath returns the JSON path of the document path items.
+func DocumentPath(items []*DocumentPathItem) (string, error) {
+	var sb strings.Builder
+	sb.WriteByte('$')
+	for i, item := range items {
+		switch item.Type {
+		case PathMember:
+			sb.WriteByte('.')
+			if identifierRegexp.MatchString(item.Value) {
+				sb.WriteString(item.Value)
+			} else {
+				sb.WriteString(strconv.Quote(item.Value))
+			}
+		case PathMemberAsterisk:
+			sb.WriteString(".*")
+		case PathArrayIndex:
+			fmt.Fprintf(&sb, "[%d]", item.Index)
+		case PathArrayIndexAsterisk:
+			sb.WriteString("[*]")
+		case PathDoubleAsterisk:
+			if i == len(items)-1 {
+				return "", ErrBadDocumentPath.GenByArgs(sb.String() + "**")
+			}
+			sb.WriteString("**")
+		default:
+			return "", ErrBadDocumentPath.GenByArgs(sb.String())
+		}
+	}
+	return sb.String(), nil
+}
+
+func (g *generator) functionCall(f *FunctionCall) error {
+	if !identifierRegexp.MatchString(f.Name) {
+		return ErrExprBadValue.GenByArgs("invalid function name " + f.Name)
+	}
+	if f.Schema != "" {
+		g.write("%n.%n(", f.Schema, f.Name)
+	} else {
+		g.raw(f.Name + "(")
+	}
+	if err := g.exprList(f.Params); err != nil {
+		return err
+	}
+	g.write(")")
+	return nil
+}
+
+func (g *generator) operator(op *Operator) error {
+	params := op.Params
+	checkParams := func(n ...int) error {
+		for _, num := range n {
+			if len(params) == num {
+				return nil
+			}
+		}
+		return ErrExprBadNumArgs.GenByArgs(op.Name)
+	}
+	if sqlOp, ok := infixOperators[op.Name]; ok {
+		if op.Name == "*" && len(params) == 0 {
+			g.write("*")
+			return nil
+		}
+		if err := checkParams(2); err != nil {
+			return err
+		}
+		return g.infix(sqlOp, params[0], params[1])
+	}
+	if sqlOp, ok := prefixOperators[op.Name]; ok {
+		if err := checkParams(1); err != nil {
+			return err
+		}
+		g.raw("(" + sqlOp)
+		if err := g.expr(params[0]); err != nil {
+			return err
+		}
+		g.write(")")
+		return nil
+	}
+	switch op.Name {
+	case "is", "is_not":
+		if err := checkParams(2); err != nil {
+			return err
+		}
+		rhs := params[1]
+		if rhs.Type != ExprLiteral || rhs.Literal == nil ||
+			(rhs.Literal.Type != ScalarNull && rhs.Literal.Type != ScalarBool) {
+			return ErrExprBadValue.GenByArgs("IS expression requires NULL, TRUE or FALSE")
+		}
+		sqlOp := "IS"
+		if op.Name == "is_not" {
+			sqlOp = "IS NOT"
+		}
+		return g.infix(sqlOp, params[0], rhs)
+	case "like", "not_like":
+		if err := checkParams(2, 3); err != nil {
+			return err
+		}
+		g.write("(")
+		if err := g.expr(params[0]); err != nil {
+			return err
+		}
+		if op.Name == "like" {
+			g.write(" LIKE ")
+		} else {
+			g.write(" NOT LIKE ")
+		}
+		if err := g.expr(params[1]); err != nil {
+			return err
+		}
+		if len(params) == 3 {
+			g.write(" ESCAPE ")
+			if err := g.expr(params[2]); err != nil {
+				return err
+			}
+		}
+		g.write(")")
+		return nil
+	case "in", "not_in":
+		if len(params) < 2 {
+			return ErrExprBadNumArgs.GenByArgs(op.Name)
+		}
+		g.write("(")
+		if err := g.expr(params[0]); err != nil {
+			return err
+		}
+		if op.Name == "in" {
+			g.write(" IN (")
+		} else {
+			g.write(" NOT IN (")
+		}
+		if err := g.exprList(params[1:]); err != nil {
+			return err
+		}
+		g.write("))")
+		return nil
+	case "cont_in", "not_cont_in":
+		if err := checkParams(2); err != nil {
+			return err
+		}
+		if op.Name == "not_cont_in" {
+			g.write("NOT ")
+		}
+		g.write("JSON_CONTAINS(")
+		if err := g.jsonExpr(params[1]); err != nil {
+			return err
+		}
+		g.write(",")
+		if err := g.jsonExpr(params[0]); err != nil {
+			return err
+		}
+		g.write(")")
+		return nil
+	case "between", "between_not", "not_between":
+		if err := checkParams(3); err != nil {
+			return err
+		}
+		g.write("(")
+		if err := g.expr(params[0]); err != nil {
+			return err
+		}
+		if op.Name == "between" {
+			g.write(" BETWEEN ")
+		} else {
+			g.write(" NOT BETWEEN ")
+		}
+		if err := g.expr(params[1]); err != nil {
+			return err
+		}
+		g.write(" AND ")
+		if err := g.expr(params[2]); err != nil {
+			return err
+		}
+		g.write(")")
+		return nil
+	case "cast":
+		if err := checkParams(2); err != nil {
+			return err
+		}
+		tp, ok := literalString(params[1])
+		if !ok || !castTypeRegexp.MatchString(tp) {
+			return ErrExprBadValue.GenByArgs("invalid cast type")
+		}
+		g.write("CAST(")
+		if err := g.expr(params[0]); err != nil {
+			return err
+		}
+		g.raw(" AS " + strings.ToUpper(tp) + ")")
+		return nil
+	case "date_add", "date_sub":
+		if err := checkParams(3); err != nil {
+			return err
+		}
+		unit, ok := literalString(params[2])
+		unit = strings.ToUpper(unit)
+		if _, valid := intervalUnits[unit]; !ok || !valid {
+			return ErrExprBadValue.GenByArgs("invalid interval unit")
+		}
+		g.raw(strings.ToUpper(op.Name) + "(")
+		if err := g.expr(params[0]); err != nil {
+			return err
+		}
+		g.write(",INTERVAL ")
+		if err := g.expr(params[1]); err != nil {
+			return err
+		}
+		g.raw(" " + unit + ")")
+		return nil
+	case "default":
+		if err := checkParams(0); err != nil {
+			return err
+		}
+		g.write("DEFAULT")
+		return nil
+	}
+	return ErrExprBadOperator.GenByArgs(op.Name)
+}
+
+func (g *generator) infix(sqlOp string, lhs, rhs *Expr) error {
+	g.write("(")
+	if err := g.expr(lhs); err != nil {
+		return err
+	}
+	g.raw(" " + sqlOp + " ")
+	if err := g.expr(rhs); err != nil {
+		return err
+	}
+	g.write(")")
+	return nil
+}
+
+// jsonExpr generates the expression as a JSON value, the strings are quoted and the other scalars are casted.
+func (g *generator) jsonExpr(e *Expr) error {
+	switch {
+	case e.Type == ExprObject || e.Type == ExprArray:
+		return g.expr(e)
+	case e.Type == ExprIdent && e.Identifier != nil && (e.Identifier.Name == "" || len(e.Identifier.DocumentPath) > 0):
+		return g.expr(e)
+	}
+	if s := literalScalar(e, g.args); s != nil {
+		if s.Type == ScalarString || (s.Type == ScalarOctets && s.ContentType != ContentTypeJSON) {
+			g.write("JSON_QUOTE(%?)", string(s.Bytes))
+			return nil
+		}
+		if s.Type == ScalarOctets {
+			return g.scalar(s)
+		}
+	}
+	g.write("CAST(")
+	if err := g.expr(e); err != nil {
+		return err
+	}
+	g.write(" AS JSON)")
+	return nil
+}
+
+// literalScalar returns the scalar of the literal or the placeholder, it returns nil for the other expressions.
+func literalScalar(e *Expr, args []*Any) *Scalar {
+	switch e.Type {
+	case ExprLiteral:
+		return e.Literal
+	case ExprPlaceholder:
+		if int(e.Position) < len(args) && args[e.Position].Type == AnyScalar {
+			return args[e.Position].Scalar
+		}
+	}
+	return nil
+}
+
+func literalString(e *Expr) (string, bool) {
+	if e.Type != ExprLiteral || e.Literal == nil || (e.Literal.Type != ScalarOctets && e.Literal.Type != ScalarString) {
+		return "", false
+	}
+	return string(e.Literal.Bytes), true
+}
@@ -0,0 +1,683 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mysqlx implements the messages of the MySQL X Protocol, and translates the CRUD messages into SQL.
+// The messages are protobuf messages defined by mysqlx*.proto of MySQL, they're encoded and decoded by hand
+// since only a few of them are needed.
+package mysqlx
+
+// The types of the messages sent by the clients.
+const (
+	ClientCapabilitiesGet      byte = 1
+	ClientCapabilitiesSet      byte = 2
+	ClientClose                byte = 3
+	ClientAuthenticateStart    byte = 4
+	ClientAuthenticateContinue byte = 5
+	ClientSessionReset         byte = 6
+	ClientSessionClose         byte = 7
+	ClientStmtExecute          byte = 12
+	ClientCrudFind             byte = 17
+	ClientCrudInsert           byte = 18
+	ClientCrudUpdate           byte = 19
+	ClientCrudDelete           byte = 20
+	ClientExpectOpen           byte = 24
+	ClientExpectClose          byte = 25
+)
+
+// The types of the messages sent by the server.
+const (
+	ServerOk                   byte = 0
+	ServerError                byte = 1
+	ServerCapabilities         byte = 2
+	ServerAuthenticateContinue byte = 3
+	ServerAuthenticateOk       byte = 4
+	ServerNotice               byte = 11
+	ServerColumnMetaData       byte = 12
+	ServerRow                  byte = 13
+	ServerFetchDone            byte = 14
+	ServerStmtExecuteOk        byte = 17
+)
+
+// ScalarType is the type of a Scalar.
+type ScalarType uint64
+
+// The types of the scalars.
+const (
+	ScalarSInt   ScalarType = 1
+	ScalarUInt   ScalarType = 2
+	ScalarNull   ScalarType = 3
+	ScalarOctets ScalarType = 4
+	ScalarDouble ScalarType = 5
+	ScalarFloat  ScalarType = 6
+	ScalarBool   ScalarType = 7
+	ScalarString ScalarType = 8
+)
+
+// The content types of the octets and the bytes columns.
+const (
+	ContentTypeGeometry uint32 = 1
+	ContentTypeJSON     uint32 = 2
+	ContentTypeXML      uint32 = 3
+)
+
+// Scalar is Mysqlx.Datatypes.Scalar.
+type Scalar struct {
+	Type ScalarType
+	SInt int64
+	UInt uint64
+	// Bytes is the value of the octets and the strings.
+	Bytes       []byte
+	ContentType uint32
+	Double      float64
+	Float       float32
+	Bool        bool
+}
+
+// NewUIntScalar creates an unsigned integer Scalar.
+func NewUIntScalar(v uint64) *Scalar {
+	return &Scalar{Type: ScalarUInt, UInt: v}
+}
+
+// NewStringScalar creates a string Scalar.
+func NewStringScalar(v string) *Scalar {
+	return &Scalar{Type: ScalarString, Bytes: []byte(v)}
+}
+
+// NewOctetsScalar creates an octets Scalar.
+func NewOctetsScalar(v string) *Scalar {
+	return &Scalar{Type: ScalarOctets, Bytes: []byte(v)}
+}
+
+func decodeScalar(data []byte) (*Scalar, error) {
+	s := &Scalar{}
+	d := decoder{data: data}
+	for d.next() {
+		switch d.field {
+		case 1:
+			s.Type = ScalarType(d.varint())
+		case 2:
+			s.SInt = d.sint()
+		case 3:
+			s.UInt = d.varint()
+		case 5:
+			d.message(func(data []byte) error {
+				d := decoder{data: data}
+				for d.next() {
+					switch d.field {
+					case 1:
+						s.Bytes = d.bytes()
+					case 2:
+						s.ContentType = uint32(d.varint())
+					default:
+						d.skip()
+					}
+				}
+				return d.err
+			})
+		case 6:
+			s.Double = d.double()
+		case 7:
+			s.Float = d.float()
+		case 8:
+			s.Bool = d.boolean()
+		case 9:
+			d.message(func(data []byte) error {
+				d := decoder{data: data}
+				for d.next() {
+					if d.field == 1 {
+						s.Bytes = d.bytes()
+					} else {
+						d.skip()
+					}
+				}
+				return d.err
+			})
+		default:
+			d.skip()
+		}
+	}
+	return s, d.err
+}
+
+func (s *Scalar) encode(e *encoder) {
+	e.varint(1, uint64(s.Type))
+	switch s.Type {
+	case ScalarSInt:
+		e.sint(2, s.SInt)
+	case ScalarUInt:
+		e.varint(3, s.UInt)
+	case ScalarOctets:
+		e.message(5, func(e *encoder) {
+			e.bytes(1, s.Bytes)
+			if s.ContentType != 0 {
+				e.varint(2, uint64(s.ContentType))
+			}
+		})
+	case ScalarDouble:
+		e.double(6, s.Double)
+	case ScalarFloat:
+		e.float(7, s.Float)
+	case ScalarBool:
+		e.boolean(8, s.Bool)
+	case ScalarString:
+		e.message(9, func(e *encoder) {
+			e.bytes(1, s.Bytes)
+		})
+	}
+}
+
+// AnyType is the type of an Any.
+type AnyType uint64
+
+// The types of the Any values.
+const (
+	AnyScalar AnyType = 1
+	AnyObject AnyType = 2
+	AnyArray  AnyType = 3
+)
+
+// Any is Mysqlx.Datatypes.Any.
+type Any struct {
+	Type   AnyType
+	Scalar *Scalar
+	Object []*ObjectField
+	Array  []*Any
+}
+
+// ObjectField is a field of an object of Any.
+type ObjectField struct {
+	Key   string
+	Value *Any
+}
+
+// NewScalarAny creates an Any of the scalar.
+func NewScalarAny(s *Scalar) *Any {
+	return &Any{Type: AnyScalar, Scalar: s}
+}
+
+// Field returns the value of the field of an object, it returns nil if the field doesn't exist.
+func (a *Any) Field(key string) *Any {
+	for _, fld := range a.Object {
+		if fld.Key == key {
+			return fld.Value
+		}
+	}
+	return nil
+}
+
+func decodeAny(data []byte) (*Any, error) {
+	a := &Any{}
+	d := decoder{data: data}
+	for d.next() {
+		switch d.field {
+		case 1:
+			a.Type = AnyType(d.varint())
+		case 2:
+			d.message(func(data []byte) (err error) {
+				a.Scalar, err = decodeScalar(data)
+				return err
+			})
+		case 3:
+			d.message(func(data []byte) error {
+				d := decoder{data: data}
+				for d.next() {
+					if d.field != 1 {
+						d.skip()
+						continue
+					}
+					d.message(func(data []byte) error {
+						fld := &ObjectField{}
+						d := decoder{data: data}
+						for d.next() {
+							switch d.field {
+							case 1:
+								fld.Key = d.string()
+							case 2:
+								d.message(func(data []byte) (err error) {
+									fld.Value, err = decodeAny(data)
+									return err
+								})
+							default:
+								d.skip()
+							}
+						}
+						a.Object = append(a.Object, fld)
+						return d.err
+					})
+				}
+				return d.err
+			})
+		case 4:
+			d.message(func(data []byte) error {
+				d := decoder{data: data}
+				for d.next() {
+					if d.field != 1 {
+						d.skip()
+						continue
+					}
+					d.message(func(data []byte) error {
+						v, err := decodeAny(data)
+						a.Array = append(a.Array, v)
+						return err
+					})
+				}
+				return d.err
+			})
+		default:
+			d.skip()
+		}
+	}
+	if d.err == nil && ((a.Type == AnyScalar && a.Scalar == nil) || a.Type < AnyScalar || a.Type > AnyArray) {
+		d.err = ErrBadMessage.GenByArgs("invalid Any")
+	}
+	return a, d.err
+}
+
+func (a *Any) encode(e *encoder) {
+	e.varint(1, uint64(a.Type))
+	switch a.Type {
+	case AnyScalar:
+		e.message(2, a.Scalar.encode)
+	case AnyObject:
+		e.message(3, func(e *encoder) {
+			for _, fld := range a.Object {
+				fld := fld
+				e.message(1, func(e *encoder) {
+					e.string(1, fld.Key)
+					e.message(2, fld.Value.encode)
+				})
+			}
+		})
+	case AnyArray:
+		e.message(4, func(e *encoder) {
+			for _, v := range a.Array {
+				e.message(1, v.encode)
+			}
+		})
+	}
+}
+
+// Capability is Mysqlx.Connection.Capability.
+type Capability struct {
+	Name  string
+	Value *Any
+}
+
+// DecodeCapabilitiesSet decodes Mysqlx.Connection.CapabilitiesSet.
+func DecodeCapabilitiesSet(data []byte) ([]*Capability, error) {
+	var caps []*Capability
+	d := decoder{data: data}
+	for d.next() {
+		if d.field != 1 {
+			d.skip()
+			continue
+		}
+		d.message(func(data []byte) error {
+			d := decoder{data: data}
+			for d.next() {
+				if d.field != 1 {
+					d.skip()
+					continue
+				}
+				d.message(func(data []byte) error {
+					c := &Capability{}
+					d := decoder{data: data}
+					for d.next() {
+						switch d.field {
+						case 1:
+							c.Name = d.string()
+						case 2:
+							d.message(func(data []byte) (err error) {
+								c.Value, err = decodeAny(data)
+								return err
+							})
+						default:
+							d.skip()
+						}
+					}
+					caps = append(caps, c)
+					return d.err
+				})
+			}
+			return d.err
+		})
+	}
+	return caps, d.err
+}
+
+// EncodeCapabilities encodes Mysqlx.Connection.Capabilities.
+func EncodeCapabilities(caps []*Capability) []byte {
+	var e encoder
+	for _, c := range caps {
+		c := c
+		e.message(1, func(e *encoder) {
+			e.string(1, c.Name)
+			e.message(2, c.Value.encode)
+		})
+	}
+	return e.buf
+}
+
+// AuthenticateStart is Mysqlx.Session.AuthenticateStart.
+type AuthenticateStart struct {
+	MechName        string
+	AuthData        []byte
+	InitialResponse []byte
+}
+
+// DecodeAuthenticateStart decodes Mysqlx.Session.AuthenticateStart.
+func DecodeAuthenticateStart(data []byte) (*AuthenticateStart, error) {
+	m := &AuthenticateStart{}
+	d := decoder{data: data}
+	for d.next() {
+		switch d.field {
+		case 1:
+			m.MechName = d.string()
+		case 2:
+			m.AuthData = d.bytes()
+		case 3:
+			m.InitialResponse = d.bytes()
+		default:
+			d.skip()
+		}
+	}
+	return m, d.err
+}
+
+// DecodeAuthenticateContinue decodes Mysqlx.Session.AuthenticateContinue and returns the auth data.
+func DecodeAuthenticateContinue(data []byte) ([]byte, error) {
+	var authData []byte
+	d := decoder{data: data}
+	for d.next() {
+		if d.field == 1 {
+			authData = d.bytes()
+		} else {
+			d.skip()
+		}
+	}
+	return authData, d.err
+}
+
+// EncodeAuthData encodes Mysqlx.Session.AuthenticateContinue or Mysqlx.Session.AuthenticateOk.
+func EncodeAuthData(authData []byte) []byte {
+	var e encoder
+	if authData != nil {
+		e.bytes(1, authData)
+	}
+	return e.buf
+}
+
+// DecodeSessionReset decodes Mysqlx.Session.Reset and returns whether the session is kept open.
+func DecodeSessionReset(data []byte) (keepOpen bool, err error) {
+	d := decoder{data: data}
+	for d.next() {
+		if d.field == 1 {
+			keepOpen = d.boolean()
+		} else {
+			d.skip()
+		}
+	}
+	return keepOpen, d.err
+}
+
+// StmtExecute is Mysqlx.Sql.StmtExecute.
+type StmtExecute struct {
+	Namespace       string
+	Stmt            []byte
+	Args            []*Any
+	CompactMetadata bool
+}
+
+// DecodeStmtExecute decodes Mysqlx.Sql.StmtExecute.
+func DecodeStmtExecute(data []byte) (*StmtExecute, error) {
+	m := &StmtExecute{Namespace: "sql"}
+	d := decoder{data: data}
+	for d.next() {
+		switch d.field {
+		case 1:
+			m.Stmt = d.bytes()
+		case 2:
+			d.message(func(data []byte) error {
+				v, err := decodeAny(data)
+				m.Args = append(m.Args, v)
+				return err
+			})
+		case 3:
+			m.Namespace = d.string()
+		case 4:
+			m.CompactMetadata = d.boolean()
+		default:
+			d.skip()
+		}
+	}
+	return m, d.err
+}
+
+// The condition keys of the expectations.
+const (
+	ExpectNoError        uint32 = 1
+	ExpectFieldExist     uint32 = 2
+	ExpectDocIDGenerated uint32 = 3
+)
+
+// The operations of the expectation blocks and the conditions.
+const (
+	ExpectCtxCopyPrev uint64 = 0
+	ExpectCtxEmpty    uint64 = 1
+	ExpectOpSet       uint64 = 0
+	ExpectOpUnset     uint64 = 1
+)
+
+// ExpectCondition is Mysqlx.Expect.Open.Condition.
+type ExpectCondition struct {
+	Key   uint32
+	Value []byte
+	Op    uint64
+}
+
+// ExpectOpen is Mysqlx.Expect.Open.
+type ExpectOpen struct {
+	Op    uint64
+	Conds []*ExpectCondition
+}
+
+// DecodeExpectOpen decodes Mysqlx.Expect.Open.
+func DecodeExpectOpen(data []byte) (*ExpectOpen, error) {
+	m := &ExpectOpen{}
+	d := decoder{data: data}
+	for d.next() {
+		switch d.field {
+		case 1:
+			m.Op = d.varint()
+		case 2:
+			d.message(func(data []byte) error {
+				c := &ExpectCondition{}
+				d := decoder{data: data}
+				for d.next() {
+					switch d.field {
+					case 1:
+						c.Key = uint32(d.varint())
+					case 2:
+						c.Value = d.bytes()
+					case 3:
+						c.Op = d.varint()
+					default:
+						d.skip()
+					}
+				}
+				m.Conds = append(m.Conds, c)
+				return d.err
+			})
+		default:
+			d.skip()
+		}
+	}
+	return m, d.err
+}
+
+// EncodeOk encodes Mysqlx.Ok.
+func EncodeOk(msg string) []byte {
+	var e encoder
+	if msg != "" {
+		e.string(1, msg)
+	}
+	return e.buf
+}
+
+// EncodeError encodes Mysqlx.Error.
+func EncodeError(err *Error) []byte {
+	var e encoder
+	if err.Fatal {
+		e.varint(1, 1)
+	}
+	e.varint(2, uint64(err.Code))
+	e.string(3, err.Message)
+	e.string(4, err.SQLState)
+	return e.buf
+}
+
+// The types of the notices.
+const (
+	NoticeWarning             uint32 = 1
+	NoticeSessionStateChanged uint32 = 3
+)
+
+// The scopes of the notices.
+const (
+	NoticeScopeGlobal uint64 = 1
+	NoticeScopeLocal  uint64 = 2
+)
+
+// The levels of the warning notices.
+const (
+	WarningNote    uint64 = 1
+	WarningWarning uint64 = 2
+	WarningError   uint64 = 3
+)
+
+// The parameters of the session state changed notices.
+const (
+	StateCurrentSchema        uint64 = 1
+	StateGeneratedInsertID    uint64 = 3
+	StateRowsAffected         uint64 = 4
+	StateProducedMessage      uint64 = 10
+	StateClientIDAssigned     uint64 = 11
+	StateGeneratedDocumentIDs uint64 = 12
+)
+
+func encodeNotice(typ uint32, scope uint64, payload []byte) []byte {
+	var e encoder
+	e.varint(1, uint64(typ))
+	e.varint(2, scope)
+	e.bytes(3, payload)
+	return e.buf
+}
+
+// EncodeWarningNotice encodes Mysqlx.Notice.Frame of Mysqlx.Notice.Warning.
+func EncodeWarningNotice(level uint64, code uint32, msg string) []byte {
+	var e encoder
+	e.varint(1, level)
+	e.varint(2, uint64(code))
+	e.string(3, msg)
+	return encodeNotice(NoticeWarning, NoticeScopeLocal, e.buf)
+}
+
+// EncodeSessionStateChangedNotice encodes Mysqlx.Notice.Frame of Mysqlx.Notice.SessionStateChanged.
+func EncodeSessionStateChangedNotice(param uint64, values ...*Scalar) []byte {
+	var e encoder
+	e.varint(1, param)
+	for _, v := range values {
+		e.message(2, v.encode)
+	}
+	return encodeNotice(NoticeSessionStateChanged, NoticeScopeLocal, e.buf)
+}
+
+// FieldType is the type of a column of Mysqlx.Resultset.ColumnMetaData.
+type FieldType uint64
+
+// The types of the columns.
+const (
+	FieldSInt     FieldType = 1
+	FieldUInt     FieldType = 2
+	FieldDouble   FieldType = 5
+	FieldFloat    FieldType = 6
+	FieldBytes    FieldType = 7
+	FieldTime     FieldType = 10
+	FieldDatetime FieldType = 12
+	FieldSet      FieldType = 15
+	FieldEnum     FieldType = 16
+	FieldBit      FieldType = 17
+	FieldDecimal  FieldType = 18
+)
+
+// The flags of the columns.
+const (
+	// FlagUnsigned is for the double, float and decimal columns.
+	FlagUnsigned uint32 = 0x0001
+	// FlagTimestamp is for the datetime columns.
+	FlagTimestamp     uint32 = 0x0001
+	FlagNotNull       uint32 = 0x0010
+	FlagPrimaryKey    uint32 = 0x0020
+	FlagUniqueKey     uint32 = 0x0040
+	FlagMultipleKey   uint32 = 0x0080
+	FlagAutoIncrement uint32 = 0x0100
+)
+
+// ColumnMetaData is Mysqlx.Resultset.ColumnMetaData.
+type ColumnMetaData struct {
+	Type             FieldType
+	Name             string
+	OriginalName     string
+	Table            string
+	OriginalTable    string
+	Schema           string
+	Catalog          string
+	Collation        uint64
+	FractionalDigits uint32
+	Length           uint32
+	Flags            uint32
+	ContentType      uint32
+}
+
+// Encode encodes Mysqlx.Resultset.ColumnMetaData, only the type is encoded if compact is true.
+func (c *ColumnMetaData) Encode(compact bool) []byte {
+	var e encoder
+	e.varint(1, uint64(c.Type))
+	if !compact {
+		e.string(2, c.Name)
+		e.string(3, c.OriginalName)
+		e.string(4, c.Table)
+		e.string(5, c.OriginalTable)
+		e.string(6, c.Schema)
+		e.string(7, c.Catalog)
+	}
+	if c.Collation != 0 {
+		e.varint(8, c.Collation)
+	}
+	e.varint(9, uint64(c.FractionalDigits))
+	e.varint(10, uint64(c.Length))
+	e.varint(11, uint64(c.Flags))
+	if c.ContentType != 0 {
+		e.varint(12, uint64(c.ContentType))
+	}
+	return e.buf
+}
+
+// EncodeRow encodes Mysqlx.Resultset.Row, a nil field means NULL.
+func EncodeRow(fields [][]byte) []byte {
+	var e encoder
+	for _, f := range fields {
+		e.bytes(1, f)
+	}
+	return e.buf
+}
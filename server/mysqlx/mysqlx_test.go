@@ -0,0 +1,393 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlx
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/types"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testMysqlxSuite{})
+
+type testMysqlxSuite struct{}
+
+func member(names ...string) *Expr {
+	id := &ColumnIdentifier{}
+	for _, name := range names {
+		id.DocumentPath = append(id.DocumentPath, &DocumentPathItem{Type: PathMember, Value: name})
+	}
+	return &Expr{Type: ExprIdent, Identifier: id}
+}
+
+func column(name string) *Expr {
+	return &Expr{Type: ExprIdent, Identifier: &ColumnIdentifier{Name: name}}
+}
+
+func literal(v interface{}) *Expr {
+	s := &Scalar{}
+	switch x := v.(type) {
+	case nil:
+		s.Type = ScalarNull
+	case int:
+		s.Type, s.SInt = ScalarSInt, int64(x)
+	case uint:
+		s.Type, s.UInt = ScalarUInt, uint64(x)
+	case float64:
+		s.Type, s.Double = ScalarDouble, x
+	case bool:
+		s.Type, s.Bool = ScalarBool, x
+	case string:
+		s.Type, s.Bytes = ScalarString, []byte(x)
+	}
+	return &Expr{Type: ExprLiteral, Literal: s}
+}
+
+func operator(name string, params ...*Expr) *Expr {
+	return &Expr{Type: ExprOperator, Operator: &Operator{Name: name, Params: params}}
+}
+
+func (s *testMysqlxSuite) TestMessage(c *C) {
+	var buf bytes.Buffer
+	c.Assert(WriteMessage(&buf, ServerOk, EncodeOk("bye!")), IsNil)
+	c.Assert(buf.Bytes()[:5], DeepEquals, []byte{7, 0, 0, 0, ServerOk})
+	typ, payload, err := ReadMessage(&buf)
+	c.Assert(err, IsNil)
+	c.Assert(typ, Equals, ServerOk)
+	c.Assert(payload, DeepEquals, []byte{0x0a, 4, 'b', 'y', 'e', '!'})
+
+	_, _, err = ReadMessage(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff, 1}))
+	c.Assert(ErrPacketTooLarge.Equal(err), IsTrue)
+	_, _, err = ReadMessage(bytes.NewReader([]byte{0, 0, 0, 0}))
+	c.Assert(ErrBadMessage.Equal(err), IsTrue)
+
+	// The scalars and the Any values round trip.
+	any := &Any{Type: AnyObject, Object: []*ObjectField{
+		{Key: "a", Value: NewScalarAny(&Scalar{Type: ScalarSInt, SInt: -3})},
+		{Key: "b", Value: &Any{Type: AnyArray, Array: []*Any{
+			NewScalarAny(NewStringScalar("x")),
+			NewScalarAny(&Scalar{Type: ScalarDouble, Double: 1.5}),
+		}}},
+	}}
+	var e encoder
+	any.encode(&e)
+	decoded, err := decodeAny(e.buf)
+	c.Assert(err, IsNil)
+	c.Assert(decoded, DeepEquals, any)
+
+	_, err = decodeAny([]byte{0x08})
+	c.Assert(ErrBadMessage.Equal(err), IsTrue)
+}
+
+func (s *testMysqlxSuite) TestDecodeFind(c *C) {
+	var e encoder
+	e.message(2, func(e *encoder) {
+		e.string(1, "c")
+		e.string(2, "test")
+	})
+	e.varint(3, uint64(DataModelDocument))
+	e.message(5, func(e *encoder) {
+		e.varint(1, uint64(ExprOperator))
+		e.message(6, func(e *encoder) {
+			e.string(1, "==")
+			e.message(2, func(e *encoder) {
+				e.varint(1, uint64(ExprIdent))
+				e.message(2, func(e *encoder) {
+					e.message(1, func(e *encoder) {
+						e.varint(1, uint64(PathMember))
+						e.string(2, "name")
+					})
+				})
+			})
+			e.message(2, func(e *encoder) {
+				e.varint(1, uint64(ExprPlaceholder))
+				e.varint(7, 0)
+			})
+		})
+	})
+	e.message(6, func(e *encoder) {
+		e.varint(1, 10)
+	})
+	e.message(11, NewScalarAny(NewStringScalar("foo")).encode)
+	// The unknown fields are skipped.
+	e.varint(100, 1)
+
+	find, err := DecodeFind(e.buf)
+	c.Assert(err, IsNil)
+	sql, err := find.SQL()
+	c.Assert(err, IsNil)
+	c.Assert(sql, Equals, "SELECT `doc` FROM `test`.`c` WHERE (JSON_EXTRACT(`doc`,'$.name') = 'foo') LIMIT 10")
+
+	_, err = DecodeFind(e.buf[:len(e.buf)-4])
+	c.Assert(ErrBadMessage.Equal(err), IsTrue)
+}
+
+func (s *testMysqlxSuite) TestExprSQL(c *C) {
+	args := []*Any{NewScalarAny(NewStringScalar("it's")), NewScalarAny(NewUIntScalar(2))}
+	cases := []struct {
+		expr     *Expr
+		document bool
+		sql      string
+	}{
+		{member("a", "b c"), true, "JSON_EXTRACT(`doc`,'$.a.\\\"b c\\\"')"},
+		{column("a"), false, "`a`"},
+		{&Expr{Type: ExprIdent, Identifier: &ColumnIdentifier{Name: "j", TableName: "t", DocumentPath: []*DocumentPathItem{
+			{Type: PathArrayIndex, Index: 1}, {Type: PathMemberAsterisk}}}}, false, "JSON_EXTRACT(`t`.`j`,'$[1].*')"},
+		{literal(nil), false, "NULL"},
+		{literal(-1), false, "-1"},
+		{literal(1.5), false, "1.5"},
+		{literal(true), false, "TRUE"},
+		{literal("a'b"), false, "'a\\'b'"},
+		{&Expr{Type: ExprLiteral, Literal: &Scalar{Type: ScalarOctets, Bytes: []byte(`{"a":1}`), ContentType: ContentTypeJSON}}, false, `CAST('{\"a\":1}' AS JSON)`},
+		{&Expr{Type: ExprPlaceholder, Position: 0}, false, "'it\\'s'"},
+		{operator("&&", operator(">", column("a"), literal(1)), operator("!", column("b"))), false, "((`a` > 1) AND (!`b`))"},
+		{operator("in", member("a"), literal(1), literal(2)), true, "(JSON_EXTRACT(`doc`,'$.a') IN (1,2))"},
+		{operator("like", column("a"), literal("%x"), literal("!")), false, "(`a` LIKE '%x' ESCAPE '!')"},
+		{operator("between_not", column("a"), literal(1), literal(2)), false, "(`a` NOT BETWEEN 1 AND 2)"},
+		{operator("is_not", column("a"), literal(nil)), false, "(`a` IS NOT NULL)"},
+		{operator("cont_in", literal("x"), member("tags")), true, "JSON_CONTAINS(JSON_EXTRACT(`doc`,'$.tags'),JSON_QUOTE('x'))"},
+		{operator("cont_in", literal(1), column("a")), false, "JSON_CONTAINS(CAST(`a` AS JSON),CAST(1 AS JSON))"},
+		{operator("cast", column("a"), literal("unsigned integer")), false, "CAST(`a` AS UNSIGNED INTEGER)"},
+		{operator("date_add", column("a"), literal(1), literal("day")), false, "DATE_ADD(`a`,INTERVAL 1 DAY)"},
+		{operator("*"), false, "*"},
+		{&Expr{Type: ExprFuncCall, FunctionCall: &FunctionCall{Name: "concat", Params: []*Expr{column("a"), literal("%?")}}}, false, "concat(`a`,'%?')"},
+		{&Expr{Type: ExprObject, Object: []*ExprObjectField{{Key: "k", Value: member("a")}}}, true, "JSON_OBJECT('k',JSON_EXTRACT(`doc`,'$.a'))"},
+		{&Expr{Type: ExprArray, Array: []*Expr{literal(1), column("a")}}, false, "JSON_ARRAY(1,`a`)"},
+	}
+	for _, ca := range cases {
+		g := &generator{documentMode: ca.document, args: args}
+		c.Assert(g.expr(ca.expr), IsNil, Commentf("%s", ca.sql))
+		c.Assert(g.String(), Equals, ca.sql)
+	}
+
+	errCases := []struct {
+		expr *Expr
+		err  ErrorClass
+	}{
+		{member("a"), ErrBadDocumentPath},
+		{&Expr{Type: ExprPlaceholder, Position: 2}, ErrExprMissingArg},
+		{&Expr{Type: ExprVariable, Variable: "a"}, ErrUnsupportedExpression},
+		{operator("foo", literal(1)), ErrExprBadOperator},
+		{operator("==", literal(1)), ErrExprBadNumArgs},
+		{operator("is", column("a"), literal(1)), ErrExprBadValue},
+		{operator("cast", column("a"), literal("int; drop table t")), ErrExprBadValue},
+		{operator("date_add", column("a"), literal(1), literal("days")), ErrExprBadValue},
+		{&Expr{Type: ExprFuncCall, FunctionCall: &FunctionCall{Name: "sleep(1);"}}, ErrExprBadValue},
+		{&Expr{Type: ExprIdent, Identifier: &ColumnIdentifier{DocumentPath: []*DocumentPathItem{{Type: PathDoubleAsterisk}}}}, ErrBadDocumentPath},
+	}
+	for _, ca := range errCases {
+		g := &generator{args: args}
+		c.Assert(ca.err.Equal(g.expr(ca.expr)), IsTrue, Commentf("%v", ca.expr))
+	}
+}
+
+func (s *testMysqlxSuite) TestCrudSQL(c *C) {
+	find := &Find{
+		Collection: Collection{Name: "c"},
+		DataModel:  DataModelDocument,
+		Projection: []*Projection{{Source: member("name")}, {Source: operator("+", member("age"), literal(1)), Alias: "next"}},
+		Criteria:   operator(">", member("age"), &Expr{Type: ExprPlaceholder}),
+		Order:      []*Order{{Expr: member("age"), Desc: true}},
+		Limit:      &Limit{RowCountExpr: literal(uint(2)), OffsetExpr: literal(uint(1))},
+		Args:       []*Any{NewScalarAny(NewUIntScalar(18))},
+		Locking:    LockingExclusive,
+	}
+	sql, err := find.SQL()
+	c.Assert(err, IsNil)
+	c.Assert(sql, Equals, "SELECT JSON_OBJECT('name',JSON_EXTRACT(`doc`,'$.name'),'next',(JSON_EXTRACT(`doc`,'$.age') + 1)) AS `doc` "+
+		"FROM `c` WHERE (JSON_EXTRACT(`doc`,'$.age') > 18) ORDER BY JSON_EXTRACT(`doc`,'$.age') DESC LIMIT 2 OFFSET 1 FOR UPDATE")
+	find.Projection = []*Projection{{Source: literal(1)}}
+	_, err = find.SQL()
+	c.Assert(ErrBadProjection.Equal(err), IsTrue)
+
+	find = &Find{
+		Collection:       Collection{Name: "t", Schema: "test"},
+		DataModel:        DataModelTable,
+		Projection:       []*Projection{{Source: column("a"), Alias: "x"}},
+		Grouping:         []*Expr{column("a")},
+		GroupingCriteria: operator(">", column("a"), literal(1)),
+	}
+	sql, err = find.SQL()
+	c.Assert(err, IsNil)
+	c.Assert(sql, Equals, "SELECT `a` AS `x` FROM `test`.`t` GROUP BY `a` HAVING (`a` > 1)")
+
+	ids := []string{"id1", "id2"}
+	genID := func() string {
+		id := ids[0]
+		ids = ids[1:]
+		return id
+	}
+	insert := &Insert{
+		Collection: Collection{Name: "c"},
+		DataModel:  DataModelDocument,
+		Rows: [][]*Expr{
+			{&Expr{Type: ExprObject, Object: []*ExprObjectField{{Key: "_id", Value: literal("1")}}}},
+			{&Expr{Type: ExprObject, Object: []*ExprObjectField{{Key: "a", Value: literal(1)}}}},
+			{&Expr{Type: ExprPlaceholder, Position: 0}},
+			{&Expr{Type: ExprPlaceholder, Position: 1}},
+		},
+		Args: []*Any{
+			NewScalarAny(&Scalar{Type: ScalarOctets, Bytes: []byte(`{"_id":"2"}`), ContentType: ContentTypeJSON}),
+			NewScalarAny(NewStringScalar(`{"b":2}`)),
+		},
+		Upsert: true,
+	}
+	sql, generated, err := insert.SQL(genID)
+	c.Assert(err, IsNil)
+	c.Assert(generated, DeepEquals, []string{"id1", "id2"})
+	c.Assert(sql, Equals, "INSERT INTO `c` (`doc`) VALUES (JSON_OBJECT('_id','1')),(JSON_INSERT(JSON_OBJECT('a',1),'$._id','id1')),"+
+		`(CAST('{\"_id\":\"2\"}' AS JSON)),(JSON_INSERT(CAST('{\"b\":2}' AS JSON),'$._id','id2')) ON DUPLICATE KEY UPDATE `+"`doc` = VALUES(`doc`)")
+	insert.Rows = [][]*Expr{{literal("[1]")}}
+	_, _, err = insert.SQL(genID)
+	c.Assert(ErrBadInsertData.Equal(err), IsTrue)
+
+	insert = &Insert{
+		Collection: Collection{Name: "t"},
+		DataModel:  DataModelTable,
+		Projection: []*Column{{Name: "a"}, {Name: "b"}},
+		Rows:       [][]*Expr{{literal(1), literal("x")}, {literal(2), literal(nil)}},
+	}
+	sql, generated, err = insert.SQL(genID)
+	c.Assert(err, IsNil)
+	c.Assert(generated, IsNil)
+	c.Assert(sql, Equals, "INSERT INTO `t` (`a`,`b`) VALUES (1,'x'),(2,NULL)")
+	insert.Rows = [][]*Expr{{literal(1)}}
+	_, _, err = insert.SQL(genID)
+	c.Assert(ErrBadInsertData.Equal(err), IsTrue)
+
+	update := &Update{
+		Collection: Collection{Name: "c"},
+		DataModel:  DataModelDocument,
+		Criteria:   operator("==", member("_id"), literal("1")),
+		Limit:      &Limit{RowCount: 1},
+		Operations: []*UpdateOperation{
+			{Source: member("a").Identifier, Operation: UpdateItemSet, Value: literal(1)},
+			{Source: member("b").Identifier, Operation: UpdateItemRemove},
+			{Source: &ColumnIdentifier{}, Operation: UpdateItemMerge, Value: &Expr{Type: ExprObject, Object: []*ExprObjectField{{Key: "c", Value: literal(nil)}}}},
+		},
+	}
+	sql, err = update.SQL()
+	c.Assert(err, IsNil)
+	c.Assert(sql, Equals, "UPDATE `c` SET `doc`=JSON_SET(JSON_MERGE_PRESERVE(JSON_REMOVE(JSON_SET(`doc`,'$.a',1),'$.b'),JSON_OBJECT('c',NULL)),"+
+		"'$._id',JSON_EXTRACT(JSON_REMOVE(JSON_SET(`doc`,'$.a',1),'$.b'),'$._id')) WHERE (JSON_EXTRACT(`doc`,'$._id') = '1') LIMIT 1")
+	update.Operations = []*UpdateOperation{{Source: member("_id").Identifier, Operation: UpdateItemSet, Value: literal(1)}}
+	_, err = update.SQL()
+	c.Assert(ErrBadUpdateData.Equal(err), IsTrue)
+	update.Operations = []*UpdateOperation{{Source: member("a").Identifier, Operation: UpdateSet, Value: literal(1)}}
+	_, err = update.SQL()
+	c.Assert(ErrBadUpdateData.Equal(err), IsTrue)
+	update.Operations = []*UpdateOperation{{Source: &ColumnIdentifier{}, Operation: UpdateMergePatch, Value: literal(1)}}
+	_, err = update.SQL()
+	c.Assert(ErrBadUpdateData.Equal(err), IsTrue)
+
+	update = &Update{
+		Collection: Collection{Name: "t"},
+		DataModel:  DataModelTable,
+		Operations: []*UpdateOperation{
+			{Source: &ColumnIdentifier{Name: "a"}, Operation: UpdateSet, Value: operator("+", column("a"), literal(1))},
+			{Source: &ColumnIdentifier{Name: "j", DocumentPath: []*DocumentPathItem{{Type: PathMember, Value: "x"}}}, Operation: UpdateArrayAppend, Value: literal(2)},
+		},
+		Limit: &Limit{RowCount: 1, Offset: 1},
+	}
+	_, err = update.SQL()
+	c.Assert(ErrBadMessage.Equal(err), IsTrue)
+	update.Limit = nil
+	sql, err = update.SQL()
+	c.Assert(err, IsNil)
+	c.Assert(sql, Equals, "UPDATE `t` SET `a`=(`a` + 1),`j`=JSON_ARRAY_APPEND(`j`,'$.x',2)")
+
+	del := &Delete{
+		Collection: Collection{Name: "c", Schema: "test"},
+		DataModel:  DataModelDocument,
+		Criteria:   operator("cont_in", literal(1), member("a")),
+		Order:      []*Order{{Expr: member("b")}},
+		Limit:      &Limit{RowCount: 3},
+	}
+	sql, err = del.SQL()
+	c.Assert(err, IsNil)
+	c.Assert(sql, Equals, "DELETE FROM `test`.`c` WHERE JSON_CONTAINS(JSON_EXTRACT(`doc`,'$.a'),CAST(1 AS JSON)) ORDER BY JSON_EXTRACT(`doc`,'$.b') LIMIT 3")
+	del.DataModel = 0
+	_, err = del.SQL()
+	c.Assert(ErrBadMessage.Equal(err), IsTrue)
+}
+
+func (s *testMysqlxSuite) TestCommandArgs(c *C) {
+	args := NewCommandArgs("create_collection", []*Any{{Type: AnyObject, Object: []*ObjectField{
+		{Key: "schema", Value: NewScalarAny(NewStringScalar("test"))},
+		{Key: "name", Value: NewScalarAny(NewStringScalar("c"))},
+	}}})
+	c.Assert(args.String("schema", false), Equals, "test")
+	c.Assert(args.String("name", false), Equals, "c")
+	c.Assert(args.Value("options", true), IsNil)
+	c.Assert(args.End(), IsNil)
+
+	args = NewCommandArgs("kill_client", []*Any{NewScalarAny(NewStringScalar("1"))})
+	args.UInt("id", false)
+	c.Assert(ErrCmdArgumentType.Equal(args.End()), IsTrue)
+
+	args = NewCommandArgs("enable_notices", []*Any{NewScalarAny(NewStringScalar("warnings")), NewScalarAny(NewStringScalar("rows_affected"))})
+	c.Assert(args.Strings("notice", false), DeepEquals, []string{"warnings", "rows_affected"})
+	c.Assert(args.End(), IsNil)
+
+	args = NewCommandArgs("drop_collection", nil)
+	args.String("schema", false)
+	c.Assert(ErrCmdNumArguments.Equal(args.End()), IsTrue)
+
+	c.Assert(CreateCollectionSQL("test", "c", true), Equals, "CREATE TABLE IF NOT EXISTS `test`.`c` (`doc` JSON,`_id` VARBINARY(32) "+
+		"GENERATED ALWAYS AS (JSON_UNQUOTE(JSON_EXTRACT(`doc`,'$._id'))) STORED NOT NULL,PRIMARY KEY(`_id`))")
+
+	g := NewDocumentIDGenerator(1)
+	id1, id2 := g.Next(), g.Next()
+	c.Assert(id1, HasLen, 28)
+	c.Assert(id1 < id2, IsTrue)
+}
+
+func (s *testMysqlxSuite) TestEncodeFields(c *C) {
+	c.Assert(EncodeSIntField(-2), DeepEquals, []byte{3})
+	c.Assert(EncodeUIntField(300), DeepEquals, []byte{0xac, 0x02})
+	c.Assert(EncodeBytesField(nil), DeepEquals, []byte{0})
+
+	for _, ca := range []struct {
+		dec string
+		bin []byte
+	}{
+		{"1.5", []byte{1, 0x15, 0xc0}},
+		{"-12.34", []byte{2, 0x12, 0x34, 0xd0}},
+		{"123", []byte{0, 0x12, 0x3c}},
+		{"0", []byte{0, 0x0c}},
+	} {
+		d := new(types.MyDecimal)
+		c.Assert(d.FromString([]byte(ca.dec)), IsNil)
+		c.Assert(EncodeDecimalField(d), DeepEquals, ca.bin, Commentf("%s", ca.dec))
+	}
+
+	t := types.NewTime(types.FromDate(2021, 3, 4, 5, 6, 7, 8), mysql.TypeDatetime, 6)
+	c.Assert(EncodeDatetimeField(t), DeepEquals, []byte{0xe5, 0x0f, 3, 4, 5, 6, 7, 8})
+	t = types.NewTime(types.FromDate(2021, 3, 4, 0, 0, 0, 0), mysql.TypeDate, 0)
+	c.Assert(EncodeDatetimeField(t), DeepEquals, []byte{0xe5, 0x0f, 3, 4})
+
+	d := types.Duration{Duration: -(25*time.Hour + 2*time.Minute + 3*time.Second)}
+	c.Assert(EncodeTimeField(d), DeepEquals, []byte{1, 25, 2, 3})
+
+	c.Assert(EncodeSetField(types.Set{}), DeepEquals, []byte{1})
+	c.Assert(EncodeSetField(types.Set{Name: "a,bc"}), DeepEquals, []byte{1, 'a', 2, 'b', 'c'})
+}
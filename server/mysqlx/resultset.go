@@ -0,0 +1,133 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlx
+
+import (
+	"encoding/binary"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/types"
+)
+
+// The fields of Mysqlx.Resultset.Row are encoded by the types of the columns, a NULL is an empty field.
+
+// EncodeSIntField encodes a field of a SINT column.
+func EncodeSIntField(v int64) []byte {
+	return appendUvarint(nil, zigzag(v))
+}
+
+// EncodeUIntField encodes a field of an UINT or a BIT column.
+func EncodeUIntField(v uint64) []byte {
+	return appendUvarint(nil, v)
+}
+
+// EncodeDoubleField encodes a field of a DOUBLE column.
+func EncodeDoubleField(v float64) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, math.Float64bits(v))
+	return b
+}
+
+// EncodeFloatField encodes a field of a FLOAT column.
+func EncodeFloatField(v float32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, math.Float32bits(v))
+	return b
+}
+
+// EncodeBytesField encodes a field of a BYTES or an ENUM column, the value is followed by a '\0' to distinguish
+// the empty value from NULL.
+func EncodeBytesField(v []byte) []byte {
+	b := make([]byte, 0, len(v)+1)
+	b = append(b, v...)
+	return append(b, 0)
+}
+
+// EncodeDatetimeField encodes a field of a DATETIME column, the time part is omitted for the dates.
+func EncodeDatetimeField(t types.Time) []byte {
+	b := appendUvarint(nil, uint64(t.Year()))
+	b = appendUvarint(b, uint64(t.Month()))
+	b = appendUvarint(b, uint64(t.Day()))
+	if t.Type() == mysql.TypeDate {
+		return b
+	}
+	b = appendUvarint(b, uint64(t.Hour()))
+	b = appendUvarint(b, uint64(t.Minute()))
+	b = appendUvarint(b, uint64(t.Second()))
+	if us := t.Microsecond(); us != 0 {
+		b = appendUvarint(b, uint64(us))
+	}
+	return b
+}
+
+// EncodeTimeField encodes a field of a TIME column.
+func EncodeTimeField(d types.Duration) []byte {
+	b := []byte{0}
+	dur := d.Duration
+	if dur < 0 {
+		b[0] = 1
+		dur = -dur
+	}
+	b = appendUvarint(b, uint64(dur/time.Hour))
+	b = appendUvarint(b, uint64(dur%time.Hour/time.Minute))
+	b = appendUvarint(b, uint64(dur%time.Minute/time.Second))
+	if us := dur % time.Second / time.Microsecond; us != 0 {
+		b = appendUvarint(b, uint64(us))
+	}
+	return b
+}
+
+// EncodeDecimalField encodes a field of a DECIMAL column. The first byte is the scale, it's followed by the BCD
+// digits and a sign nibble, 0xc for the positive numbers and 0xd for the negative ones.
+func EncodeDecimalField(d *types.MyDecimal) []byte {
+	str := d.String()
+	sign := byte(0xc)
+	if strings.HasPrefix(str, "-") {
+		sign = 0xd
+		str = str[1:]
+	}
+	var scale int
+	if i := strings.IndexByte(str, '.'); i >= 0 {
+		scale = len(str) - i - 1
+		str = str[:i] + str[i+1:]
+	}
+	b := make([]byte, 1, len(str)/2+2)
+	b[0] = byte(scale)
+	for i := 0; i+1 < len(str); i += 2 {
+		b = append(b, (str[i]-'0')<<4|(str[i+1]-'0'))
+	}
+	if len(str)%2 == 1 {
+		b = append(b, (str[len(str)-1]-'0')<<4|sign)
+	} else {
+		b = append(b, sign<<4)
+	}
+	return b
+}
+
+// EncodeSetField encodes a field of a SET column, the elements are prefixed by their lengths. The empty set is
+// encoded as 0x01 since an empty field is NULL.
+func EncodeSetField(set types.Set) []byte {
+	if set.Name == "" {
+		return []byte{1}
+	}
+	var b []byte
+	for _, elem := range strings.Split(set.Name, ",") {
+		b = appendUvarint(b, uint64(len(elem)))
+		b = append(b, elem...)
+	}
+	return b
+}
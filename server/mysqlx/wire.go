@@ -0,0 +1,291 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysqlx
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/pingcap/errors"
+)
+
+// The wire types of the protobuf fields.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// MaxMessageSize is the max size of a message sent by the clients, it's the default mysqlx_max_allowed_packet of MySQL.
+const MaxMessageSize = 64 << 20
+
+// ReadMessage reads a message from r. A message is framed by a 4 bytes little-endian length, which counts the type
+// byte and the payload, and a type byte.
+func ReadMessage(r io.Reader) (typ byte, payload []byte, err error) {
+	var header [5]byte
+	if _, err = io.ReadFull(r, header[:4]); err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+	size := binary.LittleEndian.Uint32(header[:4])
+	if size == 0 {
+		return 0, nil, errors.Trace(ErrBadMessage.GenByArgs("empty message"))
+	}
+	if size > MaxMessageSize {
+		return 0, nil, errors.Trace(ErrPacketTooLarge.GenByArgs())
+	}
+	payload = make([]byte, size)
+	if _, err = io.ReadFull(r, payload); err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+	return payload[0], payload[1:], nil
+}
+
+// WriteMessage writes a message framed like ReadMessage to w.
+func WriteMessage(w io.Writer, typ byte, payload []byte) error {
+	var header [5]byte
+	binary.LittleEndian.PutUint32(header[:4], uint32(len(payload)+1))
+	header[4] = typ
+	if _, err := w.Write(header[:]); err != nil {
+		return errors.Trace(err)
+	}
+	_, err := w.Write(payload)
+	return errors.Trace(err)
+}
+
+// encoder encodes the fields of a protobuf message.
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) tag(field, wire int) {
+	e.buf = appendUvarint(e.buf, uint64(field)<<3|uint64(wire))
+}
+
+func (e *encoder) varint(field int, v uint64) {
+	e.tag(field, wireVarint)
+	e.buf = appendUvarint(e.buf, v)
+}
+
+func (e *encoder) sint(field int, v int64) {
+	e.varint(field, zigzag(v))
+}
+
+func (e *encoder) boolean(field int, v bool) {
+	var u uint64
+	if v {
+		u = 1
+	}
+	e.varint(field, u)
+}
+
+func (e *encoder) bytes(field int, v []byte) {
+	e.tag(field, wireBytes)
+	e.buf = appendUvarint(e.buf, uint64(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func (e *encoder) string(field int, v string) {
+	e.tag(field, wireBytes)
+	e.buf = appendUvarint(e.buf, uint64(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func (e *encoder) double(field int, v float64) {
+	e.tag(field, wireFixed64)
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], math.Float64bits(v))
+	e.buf = append(e.buf, b[:]...)
+}
+
+func (e *encoder) float(field int, v float32) {
+	e.tag(field, wireFixed32)
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], math.Float32bits(v))
+	e.buf = append(e.buf, b[:]...)
+}
+
+// message encodes a nested message.
+func (e *encoder) message(field int, encode func(e *encoder)) {
+	var sub encoder
+	encode(&sub)
+	e.bytes(field, sub.buf)
+}
+
+// decoder decodes the fields of a protobuf message, the usage is:
+//
+//	d := decoder{data: data}
+//	for d.next() {
+//		switch d.field {
+//		case 1:
+//			m.Name = d.string()
+//		default:
+//			d.skip()
+//		}
+//	}
+//	return d.err
+type decoder struct {
+	data  []byte
+	field int
+	wire  int
+	err   error
+}
+
+func (d *decoder) fail(format string, args ...interface{}) {
+	if d.err == nil {
+		d.err = ErrBadMessage.GenByArgs(fmt.Sprintf(format, args...))
+	}
+}
+
+func (d *decoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.fail("invalid varint")
+		d.data = nil
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+// next moves to the next field, it returns false if all the fields are decoded or there is an error.
+func (d *decoder) next() bool {
+	if d.err != nil || len(d.data) == 0 {
+		return false
+	}
+	tag := d.uvarint()
+	d.field, d.wire = int(tag>>3), int(tag&7)
+	return d.err == nil
+}
+
+func (d *decoder) expect(wire int) bool {
+	if d.wire != wire {
+		d.fail("unexpected wire type %d of field %d", d.wire, d.field)
+		return false
+	}
+	return d.err == nil
+}
+
+func (d *decoder) varint() uint64 {
+	if !d.expect(wireVarint) {
+		return 0
+	}
+	return d.uvarint()
+}
+
+func (d *decoder) sint() int64 {
+	return unzigzag(d.varint())
+}
+
+func (d *decoder) boolean() bool {
+	return d.varint() != 0
+}
+
+func (d *decoder) bytes() []byte {
+	if !d.expect(wireBytes) {
+		return nil
+	}
+	size := d.uvarint()
+	if d.err != nil {
+		return nil
+	}
+	if size > uint64(len(d.data)) {
+		d.fail("truncated field %d", d.field)
+		return nil
+	}
+	v := d.data[:size]
+	d.data = d.data[size:]
+	return v
+}
+
+func (d *decoder) string() string {
+	return string(d.bytes())
+}
+
+func (d *decoder) fixed64() uint64 {
+	if !d.expect(wireFixed64) {
+		return 0
+	}
+	if len(d.data) < 8 {
+		d.fail("truncated field %d", d.field)
+		return 0
+	}
+	v := binary.LittleEndian.Uint64(d.data)
+	d.data = d.data[8:]
+	return v
+}
+
+func (d *decoder) fixed32() uint32 {
+	if !d.expect(wireFixed32) {
+		return 0
+	}
+	if len(d.data) < 4 {
+		d.fail("truncated field %d", d.field)
+		return 0
+	}
+	v := binary.LittleEndian.Uint32(d.data)
+	d.data = d.data[4:]
+	return v
+}
+
+func (d *decoder) double() float64 {
+	return math.Float64frombits(d.fixed64())
+}
+
+func (d *decoder) float() float32 {
+	return math.Float32frombits(d.fixed32())
+}
+
+// message decodes a nested message with decode.
+func (d *decoder) message(decode func(data []byte) error) {
+	data := d.bytes()
+	if d.err != nil {
+		return
+	}
+	if err := decode(data); err != nil && d.err == nil {
+		d.err = err
+	}
+}
+
+// skip skips the current field.
+func (d *decoder) skip() {
+	switch d.wire {
+	case wireVarint:
+		d.uvarint()
+	case wireFixed64:
+		d.fixed64()
+	case wireBytes:
+		d.bytes()
+	case wireFixed32:
+		d.fixed32()
+	default:
+		d.fail("unsupported wire type %d of field %d", d.wire, d.field)
+	}
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	return append(buf, b[:n]...)
+}
+
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+func unzigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
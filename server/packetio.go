@@ -36,9 +36,13 @@ package server
 
 import (
 	"bufio"
+	"bytes"
+	"compress/zlib"
 	"io"
+	"sync"
 	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
@@ -58,6 +62,10 @@ type packetIO struct {
 	bufWriter   *bufio.Writer
 	sequence    uint8
 	readTimeout time.Duration
+	// compressed reads and writes the compressed packets, it's nil if the compressed protocol isn't used.
+	compressed *compressedIO
+	// bytesWritten is the number of bytes of the packets written, they're counted before compressed.
+	bytesWritten uint64
 }
 
 func newPacketIO(bufReadConn *bufferedReadConn) *packetIO {
@@ -75,6 +83,33 @@ func (p *packetIO) setReadTimeout(timeout time.Duration) {
 	p.readTimeout = timeout
 }
 
+// setCompression enables the compressed protocol, the following packets are compressed by the algorithm.
+func (p *packetIO) setCompression(algorithm compressionAlgorithm, zstdLevel int) {
+	p.compressed = &compressedIO{p: p, algorithm: algorithm, zstdLevel: zstd.EncoderLevelFromZstd(zstdLevel)}
+}
+
+// resetSequence resets the sequences at the beginning of a command.
+func (p *packetIO) resetSequence() {
+	p.sequence = 0
+	if p.compressed != nil {
+		p.compressed.sequence = 0
+	}
+}
+
+func (p *packetIO) reader() io.Reader {
+	if p.compressed != nil {
+		return p.compressed
+	}
+	return p.bufReadConn
+}
+
+func (p *packetIO) writer() io.Writer {
+	if p.compressed != nil {
+		return p.compressed
+	}
+	return p.bufWriter
+}
+
 func (p *packetIO) readOnePacket() ([]byte, error) {
 	var header [4]byte
 	if p.readTimeout > 0 {
@@ -82,17 +117,22 @@ func (p *packetIO) readOnePacket() ([]byte, error) {
 			return nil, err
 		}
 	}
-	if _, err := io.ReadFull(p.bufReadConn, header[:]); err != nil {
+	if _, err := io.ReadFull(p.reader(), header[:]); err != nil {
 		return nil, errors.Trace(err)
 	}
 
 	sequence := header[3]
-	if sequence != p.sequence {
-		return nil, errInvalidSequence.GenWithStack("invalid sequence %d != %d", sequence, p.sequence)
+	if p.compressed != nil {
+		// Like MySQL, the sequences of the packets in the compressed packets are not checked, the sequence
+		// follows the compressed packets.
+		p.sequence = p.compressed.sequence
+	} else {
+		if sequence != p.sequence {
+			return nil, errInvalidSequence.GenWithStack("invalid sequence %d != %d", sequence, p.sequence)
+		}
+		p.sequence++
 	}
 
-	p.sequence++
-
 	length := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
 
 	data := make([]byte, length)
@@ -101,7 +141,7 @@ func (p *packetIO) readOnePacket() ([]byte, error) {
 			return nil, err
 		}
 	}
-	if _, err := io.ReadFull(p.bufReadConn, data); err != nil {
+	if _, err := io.ReadFull(p.reader(), data); err != nil {
 		return nil, errors.Trace(err)
 	}
 	return data, nil
@@ -153,12 +193,13 @@ func (p *packetIO) writePacket(data []byte) error {
 
 		data[3] = p.sequence
 
-		if n, err := p.bufWriter.Write(data[:4+mysql.MaxPayloadLen]); err != nil {
+		if n, err := p.writer().Write(data[:4+mysql.MaxPayloadLen]); err != nil {
 			return errors.Trace(mysql.ErrBadConn)
 		} else if n != (4 + mysql.MaxPayloadLen) {
 			return errors.Trace(mysql.ErrBadConn)
 		} else {
 			p.sequence++
+			p.bytesWritten += uint64(n)
 			length -= mysql.MaxPayloadLen
 			data = data[mysql.MaxPayloadLen:]
 		}
@@ -169,21 +210,231 @@ func (p *packetIO) writePacket(data []byte) error {
 	data[2] = byte(length >> 16)
 	data[3] = p.sequence
 
-	if n, err := p.bufWriter.Write(data); err != nil {
+	if n, err := p.writer().Write(data); err != nil {
 		terror.Log(errors.Trace(err))
 		return errors.Trace(mysql.ErrBadConn)
 	} else if n != len(data) {
 		return errors.Trace(mysql.ErrBadConn)
 	} else {
 		p.sequence++
+		p.bytesWritten += uint64(n)
 		return nil
 	}
 }
 
 func (p *packetIO) flush() error {
+	if p.compressed != nil {
+		if err := p.compressed.writeCompressedPackets(); err != nil {
+			return errors.Trace(err)
+		}
+		// Like MySQL, the sequence of the packets is synchronized with the compressed packets.
+		p.sequence = p.compressed.sequence
+	}
 	err := p.bufWriter.Flush()
 	if err != nil {
 		return errors.Trace(err)
 	}
 	return err
 }
+
+// compressionAlgorithm is the algorithm of the compressed protocol.
+type compressionAlgorithm int
+
+const (
+	compressionZlib compressionAlgorithm = iota + 1
+	compressionZstd
+)
+
+const (
+	// clientZstdCompressionAlgorithm is the capability of the compressed protocol using zstd, which is added in
+	// MySQL 8.0.18. The handshake response ends with the compression level if it's set.
+	clientZstdCompressionAlgorithm uint32 = 1 << 26
+	// defaultZstdLevel is the default compression level of zstd, it's used if the level is not in [1, 22].
+	defaultZstdLevel = 3
+	// compressedHeaderSize is the size of the header of a compressed packet, which is the 3-byte length of the
+	// payload, the sequence and the 3-byte length of the payload before compression. The length before
+	// compression is 0 if the payload isn't compressed.
+	compressedHeaderSize = 7
+	// minCompressLength is the minimum length of the data to compress, the shorter data is sent as it is.
+	minCompressLength = 50
+)
+
+var (
+	zstdDecoderOnce sync.Once
+	zstdDecoder     *zstd.Decoder
+	zstdEncodersMu  sync.Mutex
+	zstdEncoders    = make(map[zstd.EncoderLevel]*zstd.Encoder)
+)
+
+// getZstdDecoder returns the decoder shared by the connections, which decodes the data concurrently.
+func getZstdDecoder() *zstd.Decoder {
+	zstdDecoderOnce.Do(func() {
+		zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(mysql.MaxPayloadLen))
+	})
+	return zstdDecoder
+}
+
+// getZstdEncoder returns the encoder of the level shared by the connections, which encodes the data concurrently.
+func getZstdEncoder(level zstd.EncoderLevel) *zstd.Encoder {
+	zstdEncodersMu.Lock()
+	defer zstdEncodersMu.Unlock()
+	encoder, ok := zstdEncoders[level]
+	if !ok {
+		encoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(level))
+		zstdEncoders[level] = encoder
+	}
+	return encoder
+}
+
+// compressedIO reads and writes the compressed packets. The stream of the packets is split into the compressed
+// packets, so a packet may be in several compressed packets and a compressed packet may have several packets.
+type compressedIO struct {
+	p          *packetIO
+	algorithm  compressionAlgorithm
+	zstdLevel  zstd.EncoderLevel
+	sequence   uint8
+	readBuf    []byte
+	writeBuf   []byte
+	zlibReader io.ReadCloser
+	zlibWriter *zlib.Writer
+}
+
+// Read reads the data of the packets, it reads the next compressed packet if the data of the last one is consumed.
+func (c *compressedIO) Read(b []byte) (int, error) {
+	if len(c.readBuf) == 0 {
+		if err := c.readCompressedPacket(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(b, c.readBuf)
+	c.readBuf = c.readBuf[n:]
+	return n, nil
+}
+
+func (c *compressedIO) readCompressedPacket() error {
+	var header [compressedHeaderSize]byte
+	if _, err := io.ReadFull(c.p.bufReadConn, header[:]); err != nil {
+		return errors.Trace(err)
+	}
+	sequence := header[3]
+	if sequence != c.sequence {
+		return errInvalidSequence.GenWithStack("invalid compressed sequence %d != %d", sequence, c.sequence)
+	}
+	c.sequence++
+
+	length := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
+	uncompressedLength := int(uint32(header[4]) | uint32(header[5])<<8 | uint32(header[6])<<16)
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.p.bufReadConn, payload); err != nil {
+		return errors.Trace(err)
+	}
+	if uncompressedLength == 0 {
+		c.readBuf = payload
+		return nil
+	}
+
+	data := make([]byte, uncompressedLength)
+	var err error
+	switch c.algorithm {
+	case compressionZlib:
+		if c.zlibReader == nil {
+			c.zlibReader, err = zlib.NewReader(bytes.NewReader(payload))
+		} else {
+			err = c.zlibReader.(zlib.Resetter).Reset(bytes.NewReader(payload), nil)
+		}
+		if err == nil {
+			_, err = io.ReadFull(c.zlibReader, data)
+		}
+	case compressionZstd:
+		data, err = getZstdDecoder().DecodeAll(payload, data[:0])
+		if err == nil && len(data) != uncompressedLength {
+			err = io.ErrUnexpectedEOF
+		}
+	}
+	if err != nil {
+		terror.Log(errors.Trace(err))
+		return errors.Trace(mysql.ErrMalformPacket)
+	}
+	c.readBuf = data
+	return nil
+}
+
+// Write buffers the data of the packets, the compressed packets are written if enough data is buffered.
+func (c *compressedIO) Write(b []byte) (int, error) {
+	c.writeBuf = append(c.writeBuf, b...)
+	if len(c.writeBuf) >= defaultWriterSize {
+		if err := c.writeCompressedPackets(); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// writeCompressedPackets compresses the buffered data and writes the compressed packets to the buffered writer.
+func (c *compressedIO) writeCompressedPackets() error {
+	data := c.writeBuf
+	for len(data) > 0 {
+		n := len(data)
+		if n > mysql.MaxPayloadLen {
+			n = mysql.MaxPayloadLen
+		}
+		if err := c.writeCompressedPacket(data[:n]); err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+	if cap(c.writeBuf) > 4*defaultWriterSize {
+		c.writeBuf = nil
+	} else {
+		c.writeBuf = c.writeBuf[:0]
+	}
+	return nil
+}
+
+func (c *compressedIO) writeCompressedPacket(data []byte) error {
+	payload, uncompressedLength := data, 0
+	if len(data) >= minCompressLength {
+		var compressed []byte
+		switch c.algorithm {
+		case compressionZlib:
+			var buf bytes.Buffer
+			if c.zlibWriter == nil {
+				c.zlibWriter = zlib.NewWriter(&buf)
+			} else {
+				c.zlibWriter.Reset(&buf)
+			}
+			if _, err := c.zlibWriter.Write(data); err != nil {
+				return errors.Trace(err)
+			}
+			if err := c.zlibWriter.Close(); err != nil {
+				return errors.Trace(err)
+			}
+			compressed = buf.Bytes()
+		case compressionZstd:
+			compressed = getZstdEncoder(c.zstdLevel).EncodeAll(data, nil)
+		}
+		// The data is sent as it is if it can't be compressed.
+		if len(compressed) < len(data) {
+			payload, uncompressedLength = compressed, len(data)
+		}
+	}
+
+	var header [compressedHeaderSize]byte
+	header[0] = byte(len(payload))
+	header[1] = byte(len(payload) >> 8)
+	header[2] = byte(len(payload) >> 16)
+	header[3] = c.sequence
+	header[4] = byte(uncompressedLength)
+	header[5] = byte(uncompressedLength >> 8)
+	header[6] = byte(uncompressedLength >> 16)
+	if _, err := c.p.bufWriter.Write(header[:]); err != nil {
+		terror.Log(errors.Trace(err))
+		return errors.Trace(mysql.ErrBadConn)
+	}
+	if _, err := c.p.bufWriter.Write(payload); err != nil {
+		terror.Log(errors.Trace(err))
+		return errors.Trace(mysql.ErrBadConn)
+	}
+	c.sequence++
+	return nil
+}
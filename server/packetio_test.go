@@ -89,6 +89,54 @@ func (s *PacketIOTestSuite) TestRead(c *C) {
 	c.Assert(bytes[mysql.MaxPayloadLen], DeepEquals, byte(0x0a))
 }
 
+func (s *PacketIOTestSuite) TestCompressed(c *C) {
+	for _, algorithm := range []compressionAlgorithm{compressionZlib, compressionZstd} {
+		var outBuffer bytes.Buffer
+		pkt := &packetIO{bufWriter: bufio.NewWriter(&outBuffer)}
+		pkt.setCompression(algorithm, defaultZstdLevel)
+
+		// The short data is not compressed.
+		err := pkt.writePacket([]byte{0x00, 0x00, 0x00, 0x00, 0x01, 0x02, 0x03})
+		c.Assert(err, IsNil)
+		err = pkt.flush()
+		c.Assert(err, IsNil)
+		c.Assert(outBuffer.Bytes(), DeepEquals, []byte{0x07, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x02, 0x03})
+		c.Assert(pkt.sequence, Equals, uint8(1))
+		c.Assert(pkt.compressed.sequence, Equals, uint8(1))
+
+		middle := bytes.Repeat([]byte("tidb"), 1000)
+		err = pkt.writePacket(append([]byte{0x00, 0x00, 0x00, 0x00}, middle...))
+		c.Assert(err, IsNil)
+		large := bytes.Repeat([]byte{0x01, 0x02, 0x03}, mysql.MaxPayloadLen/3+1)
+		err = pkt.writePacket(append([]byte{0x00, 0x00, 0x00, 0x00}, large...))
+		c.Assert(err, IsNil)
+		err = pkt.flush()
+		c.Assert(err, IsNil)
+		c.Assert(outBuffer.Len() < len(large)/10, IsTrue)
+
+		pkt = newPacketIO(newBufferedReadConn(&bytesConn{outBuffer}))
+		pkt.setCompression(algorithm, defaultZstdLevel)
+		data, err := pkt.readPacket()
+		c.Assert(err, IsNil)
+		c.Assert(data, DeepEquals, []byte{0x01, 0x02, 0x03})
+		data, err = pkt.readPacket()
+		c.Assert(err, IsNil)
+		c.Assert(data, DeepEquals, middle)
+		data, err = pkt.readPacket()
+		c.Assert(err, IsNil)
+		c.Assert(data, DeepEquals, large)
+	}
+
+	// The sequences of the compressed packets are checked.
+	var inBuffer bytes.Buffer
+	_, err := inBuffer.Write([]byte{0x05, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01})
+	c.Assert(err, IsNil)
+	pkt := newPacketIO(newBufferedReadConn(&bytesConn{inBuffer}))
+	pkt.setCompression(compressionZlib, 0)
+	_, err = pkt.readPacket()
+	c.Assert(errInvalidSequence.Equal(err), IsTrue)
+}
+
 type bytesConn struct {
 	b bytes.Buffer
 }
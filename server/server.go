@@ -30,6 +30,7 @@ package server
 
 import (
 	"context"
+	"crypto/rsa"
 	"crypto/tls"
 	"flag"
 	"fmt"
@@ -43,11 +44,12 @@ import (
 	_ "net/http/pprof"
 	"os"
 	"os/user"
+	"runtime"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	"github.com/blacktear23/go-proxyprotocol"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
@@ -56,14 +58,19 @@ import (
 	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/plugin"
+	"github.com/pingcap/tidb/server/audit"
+	"github.com/pingcap/tidb/server/mysqlx"
+	"github.com/pingcap/tidb/server/workerpool"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/store/tikv/oracle"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/dbterror"
 	"github.com/pingcap/tidb/util/fastrand"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/proxyprotocol"
 	"github.com/pingcap/tidb/util/sys/linux"
 	"github.com/pingcap/tidb/util/timeutil"
+	"github.com/pingcap/tidb/util/txninfo"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 )
@@ -100,6 +107,12 @@ var (
 	errSecureTransportRequired = dbterror.ClassServer.NewStd(errno.ErrSecureTransportRequired)
 	errMultiStatementDisabled  = dbterror.ClassServer.NewStd(errno.ErrMultiStatementDisabled)
 	errNewAbortingConnection   = dbterror.ClassServer.NewStd(errno.ErrNewAbortingConnection)
+	errServerShutdown          = dbterror.ClassServer.NewStd(errno.ErrServerShutdown)
+	errServerBusy              = dbterror.ClassServer.NewStd(errno.ErrServerBusy)
+	errTooManyUserConnections  = dbterror.ClassServer.NewStd(errno.ErrTooManyUserConnections)
+	errUserLimitReached        = dbterror.ClassServer.NewStd(errno.ErrUserLimitReached)
+	errTooManyConnsFromHost    = dbterror.ClassServer.NewStd(errno.ErrTooManyConnectionsFromHost)
+	errMustChangePasswordLogin = dbterror.ClassServer.NewStd(errno.ErrMustChangePasswordLogin)
 )
 
 // DefaultCapability is the capability of the server when it is created using the default configuration.
@@ -108,7 +121,9 @@ const defaultCapability = mysql.ClientLongPassword | mysql.ClientLongFlag |
 	mysql.ClientConnectWithDB | mysql.ClientProtocol41 |
 	mysql.ClientTransactions | mysql.ClientSecureConnection | mysql.ClientFoundRows |
 	mysql.ClientMultiStatements | mysql.ClientMultiResults | mysql.ClientLocalFiles |
-	mysql.ClientConnectAtts | mysql.ClientPluginAuth | mysql.ClientInteractive
+	mysql.ClientConnectAtts | mysql.ClientPluginAuth | mysql.ClientInteractive |
+	mysql.ClientCompress | clientZstdCompressionAlgorithm | clientOptionalResultsetMetadata |
+	clientCanHandleExpiredPasswords
 
 // Server is the MySQL protocol server
 type Server struct {
@@ -117,18 +132,31 @@ type Server struct {
 	driver            IDriver
 	listener          net.Listener
 	socket            net.Listener
+	xListener         net.Listener
 	rwlock            sync.RWMutex
 	concurrentLimiter *TokenLimiter
 	clients           map[uint64]*clientConn
 	capability        uint32
 	dom               *domain.Domain
 	globalConnID      util.GlobalConnID
+	docIDGenerator    *mysqlx.DocumentIDGenerator
+
+	// sha2Key is the RSA key to exchange the password of caching_sha2_password.
+	sha2Key     *rsa.PrivateKey
+	sha2KeyErr  error
+	sha2KeyOnce sync.Once
 
 	statusAddr     string
 	statusListener net.Listener
 	statusServer   *http.Server
 	grpcServer     *grpc.Server
-	inShutdownMode bool
+	// inShutdownMode is set when the server starts draining, the health check fails and the new connections are rejected.
+	inShutdownMode int32
+	// workerPool executes the queries of the connections if it's enabled, nil otherwise.
+	workerPool *workerpool.Pool
+	// sslReloadExit stops sslReloadLoop, it's nil if the certificates aren't reloaded automatically.
+	sslReloadExit chan struct{}
+	connLimiter   connLimiter
 }
 
 // ConnectionCount gets current connection count.
@@ -170,7 +198,12 @@ func (s *Server) InitGlobalConnID(serverIDGetter func() uint64) {
 // It allocates a connection ID and random salt data for authentication.
 func (s *Server) newConn(conn net.Conn) *clientConn {
 	cc := newClientConn(s)
-	if tcpConn, ok := conn.(*net.TCPConn); ok {
+	rawConn := conn
+	if ppConn, ok := conn.(*proxyprotocol.Conn); ok {
+		cc.proxyAddr = ppConn.ProxyAddr().String()
+		rawConn = ppConn.Conn
+	}
+	if tcpConn, ok := rawConn.(*net.TCPConn); ok {
 		if err := tcpConn.SetKeepAlive(s.cfg.Performance.TCPKeepAlive); err != nil {
 			logutil.BgLogger().Error("failed to set tcp keep alive option", zap.Error(err))
 		}
@@ -229,6 +262,7 @@ func NewServer(cfg *config.Config, driver IDriver) (*Server, error) {
 		concurrentLimiter: NewTokenLimiter(cfg.TokenLimit),
 		clients:           make(map[uint64]*clientConn),
 		globalConnID:      util.GlobalConnID{ServerID: 0, Is64bits: true},
+		docIDGenerator:    mysqlx.NewDocumentIDGenerator(uint16(fastrand.Uint32())),
 	}
 	setTxnScope()
 	tlsConfig, err := util.LoadTLSCertificates(s.cfg.Security.SSLCA, s.cfg.Security.SSLKey, s.cfg.Security.SSLCert)
@@ -242,12 +276,28 @@ func NewServer(cfg *config.Config, driver IDriver) (*Server, error) {
 	} else if cfg.Security.RequireSecureTransport {
 		return nil, errSecureTransportRequired.FastGenByArgs()
 	}
+	if path := cfg.Security.CachingSha2PasswordPrivateKey; path != "" {
+		if s.sha2Key, err = loadCachingSha2Key(path); err != nil {
+			return nil, err
+		}
+	}
 
 	setSystemTimeZoneVariable()
 
+	if poolCfg := cfg.WorkerPool; poolCfg.Enable {
+		size := int(poolCfg.Size)
+		if size == 0 {
+			size = 4 * runtime.GOMAXPROCS(0)
+		}
+		s.workerPool = workerpool.New(size, int(poolCfg.MaxQueueSize), time.Duration(poolCfg.MaxQueueTime)*time.Millisecond)
+		logutil.BgLogger().Info("worker pool is enabled", zap.Int("size", size))
+	}
+
 	s.capability = defaultCapability
-	if s.tlsConfig != nil {
-		s.capability |= mysql.ClientSSL
+	if interval := cfg.Security.SSLReloadInterval; interval > 0 && cfg.Security.SSLCert != "" && cfg.Security.SSLKey != "" {
+		s.sslReloadExit = make(chan struct{})
+		version := sslFilesVersion(cfg.Security.SSLCA, cfg.Security.SSLKey, cfg.Security.SSLCert)
+		go s.sslReloadLoop(time.Duration(interval)*time.Second, version, s.sslReloadExit)
 	}
 
 	if s.cfg.Host != "" && (s.cfg.Port != 0 || runInGoTest) {
@@ -276,15 +326,24 @@ func NewServer(cfg *config.Config, driver IDriver) (*Server, error) {
 		err = errors.New("Server not configured to listen on either -socket or -host and -port")
 	}
 
-	if cfg.ProxyProtocol.Networks != "" {
-		pplistener, errProxy := proxyprotocol.NewListener(s.listener, cfg.ProxyProtocol.Networks,
-			int(cfg.ProxyProtocol.HeaderTimeout))
-		if errProxy != nil {
-			logutil.BgLogger().Error("ProxyProtocol networks parameter invalid")
-			return nil, errors.Trace(errProxy)
+	if cfg.ProxyProtocol.Networks != "" && err == nil {
+		if s.listener, err = wrapProxyProtocolListener(s.listener, cfg); err != nil {
+			return nil, err
 		}
 		logutil.BgLogger().Info("server is running MySQL protocol (through PROXY protocol)", zap.String("host", s.cfg.Host))
-		s.listener = pplistener
+	}
+
+	if cfg.XProtocol.Enable && s.cfg.Host != "" && err == nil {
+		addr := fmt.Sprintf("%s:%d", s.cfg.Host, s.cfg.XProtocol.Port)
+		if s.xListener, err = net.Listen("tcp", addr); err == nil {
+			logutil.BgLogger().Info("server is running MySQL X protocol", zap.String("addr", addr))
+			if runInGoTest && s.cfg.XProtocol.Port == 0 {
+				s.cfg.XProtocol.Port = uint(s.xListener.Addr().(*net.TCPAddr).Port)
+			}
+			if cfg.ProxyProtocol.Networks != "" {
+				s.xListener, err = wrapProxyProtocolListener(s.xListener, cfg)
+			}
+		}
 	}
 
 	if s.cfg.Status.ReportStatus && err == nil {
@@ -299,6 +358,19 @@ func NewServer(cfg *config.Config, driver IDriver) (*Server, error) {
 	return s, nil
 }
 
+// wrapProxyProtocolListener reads the PROXY protocol headers of the connections from the configured networks, so the
+// remote addresses of the connections are the addresses of the clients rather than the proxies.
+func wrapProxyProtocolListener(listener net.Listener, cfg *config.Config) (net.Listener, error) {
+	timeout := time.Duration(cfg.ProxyProtocol.HeaderTimeout) * time.Second
+	pplistener, err := proxyprotocol.NewListener(listener, cfg.ProxyProtocol.Networks, timeout)
+	if err != nil {
+		logutil.BgLogger().Error("ProxyProtocol networks parameter invalid")
+		terror.Log(listener.Close())
+		return nil, errors.Trace(err)
+	}
+	return pplistener, nil
+}
+
 func setSSLVariable(ca, key, cert string) {
 	variable.SetSysVar("have_openssl", "YES")
 	variable.SetSysVar("have_ssl", "YES")
@@ -332,6 +404,9 @@ func (s *Server) Run() error {
 	if s.cfg.Status.ReportStatus {
 		s.startStatusHTTP()
 	}
+	if s.xListener != nil {
+		go s.runXProtocol(s.xListener)
+	}
 	for {
 		conn, err := s.listener.Accept()
 		if err != nil {
@@ -385,26 +460,28 @@ func (s *Server) Run() error {
 	}
 }
 
+// startShutdown puts the server into the drain mode. The health check reports unhealthy, the new connections are
+// rejected, and the existing connections are drained in graceful-wait-before-shutdown.
 func (s *Server) startShutdown() {
-	s.rwlock.RLock()
 	logutil.BgLogger().Info("setting tidb-server to report unhealthy (shutting-down)")
-	s.inShutdownMode = true
-	s.rwlock.RUnlock()
+	atomic.StoreInt32(&s.inShutdownMode, 1)
+	s.closeListeners()
 	// give the load balancer a chance to receive a few unhealthy health reports
-	// before acquiring the s.rwlock and blocking connections.
+	// before closing the status server.
 	waitTime := time.Duration(s.cfg.GracefulWaitBeforeShutdown) * time.Second
 	if waitTime > 0 {
-		logutil.BgLogger().Info("waiting for stray connections before starting shutdown process", zap.Duration("waitTime", waitTime))
-		time.Sleep(waitTime)
+		logutil.BgLogger().Info("draining connections before starting shutdown process", zap.Duration("waitTime", waitTime))
+		s.drainConnections(waitTime)
 	}
 }
 
-// Close closes the server.
-func (s *Server) Close() {
-	s.startShutdown()
-	s.rwlock.Lock() // prevent new connections
-	defer s.rwlock.Unlock()
+func (s *Server) isShuttingDown() bool {
+	return atomic.LoadInt32(&s.inShutdownMode) == 1
+}
 
+func (s *Server) closeListeners() {
+	s.rwlock.Lock()
+	defer s.rwlock.Unlock()
 	if s.listener != nil {
 		err := s.listener.Close()
 		terror.Log(errors.Trace(err))
@@ -415,6 +492,37 @@ func (s *Server) Close() {
 		terror.Log(errors.Trace(err))
 		s.socket = nil
 	}
+	if s.xListener != nil {
+		err := s.xListener.Close()
+		terror.Log(errors.Trace(err))
+		s.xListener = nil
+	}
+}
+
+// drainConnections closes the idle connections in the wait time, the connections in transactions are closed after
+// the transactions finish. The remaining connections are cancelled when the wait time elapses.
+func (s *Server) drainConnections(waitTime time.Duration) {
+	timer := time.NewTimer(waitTime)
+	defer timer.Stop()
+	for {
+		s.kickIdleConnection()
+		select {
+		case <-timer.C:
+			if s.ConnectionCount() > 0 {
+				s.cancelConnections(cancelConnectionsTimeout)
+			}
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// Close closes the server.
+func (s *Server) Close() {
+	s.startShutdown()
+	s.rwlock.Lock()
+	defer s.rwlock.Unlock()
+
 	if s.statusServer != nil {
 		err := s.statusServer.Close()
 		terror.Log(errors.Trace(err))
@@ -424,6 +532,13 @@ func (s *Server) Close() {
 		s.grpcServer.Stop()
 		s.grpcServer = nil
 	}
+	if s.workerPool != nil {
+		s.workerPool.Close()
+	}
+	if s.sslReloadExit != nil {
+		close(s.sslReloadExit)
+		s.sslReloadExit = nil
+	}
 	metrics.ServerEventCounter.WithLabelValues(metrics.EventClose).Inc()
 }
 
@@ -431,6 +546,9 @@ func (s *Server) Close() {
 func (s *Server) onConn(conn *clientConn) {
 	ctx := logutil.WithConnID(context.Background(), conn.connectionID)
 	if err := conn.handshake(ctx); err != nil {
+		if terror.ErrorNotEqual(err, io.EOF) {
+			conn.auditConnEvent(audit.TypeReject, 0, err)
+		}
 		if plugin.IsEnable(plugin.Audit) && conn.ctx != nil {
 			conn.ctx.GetSessionVars().ConnectionInfo = conn.connectInfo()
 			err = plugin.ForeachPlugin(plugin.Audit, func(p *plugin.Plugin) error {
@@ -476,9 +594,11 @@ func (s *Server) onConn(conn *clientConn) {
 	if err != nil {
 		return
 	}
+	conn.auditConnEvent(audit.TypeConnect, 0, nil)
 
 	connectedTime := time.Now()
 	conn.Run(ctx)
+	conn.auditConnEvent(audit.TypeDisconnect, time.Since(connectedTime), nil)
 
 	err = plugin.ForeachPlugin(plugin.Audit, func(p *plugin.Plugin) error {
 		// Audit plugin may be disabled before a conn is created, leading no connectionInfo in sessionVars.
@@ -500,6 +620,18 @@ func (s *Server) onConn(conn *clientConn) {
 	}
 }
 
+// auditConnEvent writes the connection event to the audit log, d is the lifetime of the disconnected connection.
+func (cc *clientConn) auditConnEvent(typ string, d time.Duration, err error) {
+	if !audit.Enabled() {
+		return
+	}
+	e := audit.NewConnEvent(typ, cc.connectionID, cc.user, cc.peerHost, cc.dbname, err)
+	e.Proxy = cc.proxyAddr
+	e.CostTime = d.Seconds()
+	e.Attrs = cc.attrs
+	audit.Log(e)
+}
+
 func (cc *clientConn) connectInfo() *variable.ConnectionInfo {
 	connType := "Socket"
 	if cc.server.isUnixSocket() {
@@ -518,6 +650,7 @@ func (cc *clientConn) connectInfo() *variable.ConnectionInfo {
 		User:              cc.user,
 		ServerOSLoginUser: osUser,
 		OSVersion:         osVersion,
+		ClientVersion:     cc.attrs["_client_version"],
 		ServerVersion:     mysql.TiDBReleaseVersion,
 		SSLVersion:        "v1.2.0", // for current go version
 		PID:               serverPID,
@@ -527,6 +660,10 @@ func (cc *clientConn) connectInfo() *variable.ConnectionInfo {
 }
 
 func (s *Server) checkConnectionCount() error {
+	// The server is draining, the new connections may be accepted before the listener is closed.
+	if s.isShuttingDown() {
+		return errServerShutdown
+	}
 	// When the value of MaxServerConnections is 0, the number of connections is unlimited.
 	if int(s.cfg.MaxServerConnections) == 0 {
 		return nil
@@ -537,6 +674,7 @@ func (s *Server) checkConnectionCount() error {
 	s.rwlock.RUnlock()
 
 	if conns >= int(s.cfg.MaxServerConnections) {
+		metrics.ConnRejectedCounter.WithLabelValues(metrics.LblMaxServerConnections).Inc()
 		logutil.BgLogger().Error("too many connections",
 			zap.Uint32("max connections", s.cfg.MaxServerConnections), zap.Error(errConCount))
 		return errConCount
@@ -557,6 +695,19 @@ func (s *Server) ShowProcessList() map[uint64]*util.ProcessInfo {
 	return rs
 }
 
+// ShowTxnList implements the SessionManager interface.
+func (s *Server) ShowTxnList() []*txninfo.TxnInfo {
+	s.rwlock.RLock()
+	defer s.rwlock.RUnlock()
+	rs := make([]*txninfo.TxnInfo, 0, len(s.clients))
+	for _, client := range s.clients {
+		if info := client.ctx.TxnInfo(); info != nil {
+			rs = append(rs, info)
+		}
+	}
+	return rs
+}
+
 // GetProcessInfo implements the SessionManager interface.
 func (s *Server) GetProcessInfo(id uint64) (*util.ProcessInfo, bool) {
 	s.rwlock.RLock()
@@ -584,6 +735,11 @@ func (s *Server) Kill(connectionID uint64, query bool) {
 		// Mark the client connection status as WaitShutdown, when clientConn.Run detect
 		// this, it will end the dispatch loop and exit.
 		atomic.StoreInt32(&conn.status, connStatusWaitShutdown)
+		// Wake up the connection blocked in reading the next command, so that an idle connection exits immediately
+		// and rolls back its transaction.
+		if conn.bufReadConn != nil {
+			terror.Log(conn.bufReadConn.SetReadDeadline(time.Now()))
+		}
 	}
 	killConn(conn)
 }
@@ -597,6 +753,61 @@ func (s *Server) getTLSConfig() *tls.Config {
 	return (*tls.Config)(atomic.LoadPointer(&s.tlsConfig))
 }
 
+// getCapability returns the capability of the server. ClientSSL is set only if TLS is enabled at the moment, because
+// it can be enabled or disabled by reloading the certificates.
+func (s *Server) getCapability() uint32 {
+	if s.getTLSConfig() != nil {
+		return s.capability | mysql.ClientSSL
+	}
+	return s.capability &^ mysql.ClientSSL
+}
+
+// sslFilesVersion returns the modification time and size of the certificate files, it changes when any of them is
+// rewritten.
+func sslFilesVersion(paths ...string) string {
+	var b strings.Builder
+	for _, path := range paths {
+		if path != "" {
+			if info, err := os.Stat(path); err == nil {
+				fmt.Fprintf(&b, "%d/%d", info.ModTime().UnixNano(), info.Size())
+			}
+		}
+		b.WriteByte(';')
+	}
+	return b.String()
+}
+
+// sslReloadLoop reloads the certificates of the MySQL protocol when their files are rewritten, so they can be rotated
+// without restarting. The new certificates are only used by the new connections. The old ones are kept if the new
+// ones fail to load, e.g. the key is rewritten but the certificate isn't yet, and they're loaded again when the files
+// change next time. version is the version of the files when the certificates in use are loaded.
+func (s *Server) sslReloadLoop(interval time.Duration, version string, exit chan struct{}) {
+	sec := s.cfg.Security
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-exit:
+			return
+		case <-ticker.C:
+		}
+		newVersion := sslFilesVersion(sec.SSLCA, sec.SSLKey, sec.SSLCert)
+		if newVersion == version {
+			continue
+		}
+		version = newVersion
+		tlsConfig, err := util.LoadTLSCertificates(sec.SSLCA, sec.SSLKey, sec.SSLCert)
+		if err != nil || tlsConfig == nil {
+			logutil.BgLogger().Warn("reload the rewritten certificates failed, keep using the old ones", zap.Error(err))
+			continue
+		}
+		setSSLVariable(sec.SSLCA, sec.SSLKey, sec.SSLCert)
+		s.UpdateTLSConfig(tlsConfig)
+		logutil.BgLogger().Info("the rewritten certificates are reloaded",
+			zap.String("ssl-ca", sec.SSLCA), zap.String("ssl-cert", sec.SSLCert), zap.String("ssl-key", sec.SSLKey))
+	}
+}
+
 func killConn(conn *clientConn) {
 	sessVars := conn.ctx.GetSessionVars()
 	atomic.StoreUint32(&sessVars.Killed, 1)
@@ -623,9 +834,14 @@ func (s *Server) KillAllConnections() {
 	}
 }
 
-var gracefulCloseConnectionsTimeout = 15 * time.Second
+var (
+	gracefulCloseConnectionsTimeout = 15 * time.Second
+	// cancelConnectionsTimeout is how long the cancelled connections are waited to roll back and exit.
+	cancelConnectionsTimeout = 5 * time.Second
+)
 
-// TryGracefulDown will try to gracefully close all connection first with timeout. if timeout, will close all connection directly.
+// TryGracefulDown will try to gracefully close all connection first with timeout. if timeout, the remaining
+// connections are cancelled.
 func (s *Server) TryGracefulDown() {
 	ctx, cancel := context.WithTimeout(context.Background(), gracefulCloseConnectionsTimeout)
 	defer cancel()
@@ -635,12 +851,41 @@ func (s *Server) TryGracefulDown() {
 	}()
 	select {
 	case <-ctx.Done():
-		s.KillAllConnections()
+		s.cancelConnections(cancelConnectionsTimeout)
 	case <-done:
 		return
 	}
 }
 
+// cancelConnections cancels the remaining connections. The idle connections are closed, the running statements
+// are killed so their connections can roll back the transactions and exit by themselves. The connections which
+// don't exit in the timeout are closed directly.
+func (s *Server) cancelConnections(timeout time.Duration) {
+	logutil.BgLogger().Info("[server] cancel the remaining connections.", zap.Int("conn count", s.ConnectionCount()))
+	var idleConns []*clientConn
+	s.rwlock.RLock()
+	for _, cc := range s.clients {
+		if atomic.CompareAndSwapInt32(&cc.status, connStatusReading, connStatusShutdown) {
+			idleConns = append(idleConns, cc)
+			continue
+		}
+		atomic.StoreInt32(&cc.status, connStatusWaitShutdown)
+		killConn(cc)
+	}
+	s.rwlock.RUnlock()
+	for _, cc := range idleConns {
+		terror.Log(cc.Close())
+	}
+
+	for deadline := time.Now().Add(timeout); time.Now().Before(deadline); {
+		if s.ConnectionCount() == 0 {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	s.KillAllConnections()
+}
+
 // GracefulDown waits all clients to close.
 func (s *Server) GracefulDown(ctx context.Context, done chan struct{}) {
 	logutil.Logger(ctx).Info("[server] graceful shutdown.")
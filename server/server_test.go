@@ -15,6 +15,7 @@ package server
 
 import (
 	"bytes"
+	"compress/gzip"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -29,10 +30,12 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/go-sql-driver/mysql"
+	"github.com/klauspost/compress/zstd"
 	. "github.com/pingcap/check"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
@@ -41,6 +44,7 @@ import (
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/server/audit"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/versioninfo"
 	"go.uber.org/zap"
@@ -640,6 +644,73 @@ func (cli *testServerClient) runTestLoadDataAutoRandomWithSpecialTerm(c *C) {
 	})
 }
 
+func (cli *testServerClient) runTestLoadDataCompressed(c *C) {
+	var content bytes.Buffer
+	sum := 0
+	for i := 0; i < 50000; i++ {
+		sum += i
+		content.WriteString(fmt.Sprintf("%d,row%d\n", i, i))
+	}
+	var gzipContent, zstdContent bytes.Buffer
+	gw := gzip.NewWriter(&gzipContent)
+	_, err := gw.Write(content.Bytes())
+	c.Assert(err, IsNil)
+	c.Assert(gw.Close(), IsNil)
+	zw, err := zstd.NewWriter(&zstdContent)
+	c.Assert(err, IsNil)
+	_, err = zw.Write(content.Bytes())
+	c.Assert(err, IsNil)
+	c.Assert(zw.Close(), IsNil)
+
+	files := map[string][]byte{
+		"/tmp/load_data_compressed.csv":     content.Bytes(),
+		"/tmp/load_data_compressed.csv.gz":  gzipContent.Bytes(),
+		"/tmp/load_data_compressed.csv.zst": zstdContent.Bytes(),
+		// The file is truncated in the middle of the compressed data.
+		"/tmp/load_data_truncated.csv.gz": gzipContent.Bytes()[:gzipContent.Len()/2],
+	}
+	for path, data := range files {
+		c.Assert(ioutil.WriteFile(path, data, 0600), IsNil)
+		defer func(path string) {
+			_ = os.Remove(path)
+		}(path)
+	}
+
+	cli.runTestsOnNewDB(c, func(config *mysql.Config) {
+		config.AllowAllFiles = true
+	}, "load_data_compressed", func(dbt *DBTest) {
+		dbt.mustExec("create table t(a int primary key, b varchar(20))")
+		for _, ca := range []struct {
+			compression string
+			path        string
+		}{
+			{"none", "/tmp/load_data_compressed.csv"},
+			{"gzip", "/tmp/load_data_compressed.csv.gz"},
+			{"zstd", "/tmp/load_data_compressed.csv.zst"},
+			{"auto", "/tmp/load_data_compressed.csv"},
+			{"auto", "/tmp/load_data_compressed.csv.gz"},
+			{"auto", "/tmp/load_data_compressed.csv.zst"},
+		} {
+			dbt.mustExec("truncate table t")
+			dbt.mustExec("set @@tidb_load_data_local_compression = " + ca.compression)
+			dbt.mustExec(fmt.Sprintf("load data local infile %q into table t fields terminated by ','", ca.path))
+			rows := dbt.mustQuery("select count(*), sum(a), sum(b = concat('row', a)) from t")
+			cli.checkRows(c, rows, fmt.Sprintf("50000 %d 50000", sum))
+		}
+
+		// The corrupted files fail, and the connection is still usable after the left packets are drained.
+		dbt.mustExec("truncate table t")
+		dbt.mustExec("set @@tidb_load_data_local_compression = gzip")
+		_, err := dbt.db.Exec(fmt.Sprintf("load data local infile %q into table t fields terminated by ','", "/tmp/load_data_compressed.csv"))
+		c.Assert(err, NotNil)
+		dbt.mustExec("set @@tidb_load_data_local_compression = auto")
+		_, err = dbt.db.Exec(fmt.Sprintf("load data local infile %q into table t fields terminated by ','", "/tmp/load_data_truncated.csv.gz"))
+		c.Assert(err, NotNil)
+		rows := dbt.mustQuery("select count(*) from t where b != concat('row', a)")
+		cli.checkRows(c, rows, "0")
+	})
+}
+
 func (cli *testServerClient) runTestLoadDataForListPartition(c *C) {
 	path := "/tmp/load_data_list_partition.csv"
 	defer func() {
@@ -1606,6 +1677,149 @@ func (cli *testServerClient) runTestAuth(c *C) {
 	})
 }
 
+func (cli *testServerClient) runTestCachingSha2Password(c *C) {
+	cli.runTests(c, nil, func(dbt *DBTest) {
+		dbt.mustExec(`CREATE USER 'sha2test'@'%' IDENTIFIED WITH caching_sha2_password BY '123', 'sha2test2'@'%' IDENTIFIED WITH caching_sha2_password`)
+		dbt.mustExec(`GRANT ALL on test.* to 'sha2test', 'sha2test2'`)
+	})
+	checkCurrentUser := func(user string) func(dbt *DBTest) {
+		return func(dbt *DBTest) {
+			rows := dbt.mustQuery("SELECT current_user()")
+			dbt.Assert(rows.Next(), IsTrue)
+			var currentUser string
+			dbt.Assert(rows.Scan(&currentUser), IsNil)
+			dbt.Assert(currentUser, Equals, user)
+			dbt.Assert(rows.Close(), IsNil)
+		}
+	}
+	// The first connection is authenticated by the RSA exchange, and the second one is by the cached password.
+	for i := 0; i < 2; i++ {
+		cli.runTests(c, func(config *mysql.Config) {
+			config.User = "sha2test"
+			config.Passwd = "123"
+		}, checkCurrentUser("sha2test@%"))
+	}
+	cli.runTests(c, func(config *mysql.Config) {
+		config.User = "sha2test2"
+	}, checkCurrentUser("sha2test2@%"))
+
+	db, err := sql.Open("mysql", cli.getDSN(func(config *mysql.Config) {
+		config.User = "sha2test"
+		config.Passwd = "456"
+	}))
+	c.Assert(err, IsNil)
+	err = db.Ping()
+	c.Assert(err, NotNil, Commentf("Wrong password should be failed"))
+	c.Assert(db.Close(), IsNil)
+}
+
+type auditEventSink struct {
+	sync.Mutex
+	events []*audit.Event
+}
+
+func (s *auditEventSink) Write(e *audit.Event) error {
+	s.Lock()
+	defer s.Unlock()
+	s.events = append(s.events, e)
+	return nil
+}
+
+func (s *auditEventSink) Close() error {
+	return nil
+}
+
+func (s *auditEventSink) Events() []*audit.Event {
+	s.Lock()
+	defer s.Unlock()
+	return append([]*audit.Event(nil), s.events...)
+}
+
+func (cli *testServerClient) runTestAuditLog(c *C) {
+	sink := &auditEventSink{}
+	audit.RegisterSink("test", func(*config.Audit) (audit.Sink, error) {
+		return sink, nil
+	})
+	cfg := config.NewConfig().Audit
+	cfg.Enable = true
+	cfg.Sinks = []string{"test"}
+	// The other tests run in parallel, so only the events of the audit user are checked.
+	cfg.Filters = []config.AuditFilter{
+		{Users: []string{"audituser"}},
+		{Classes: []string{"query"}, Databases: []string{"information_schema"}, Exclude: true},
+	}
+	c.Assert(audit.Setup(&cfg), IsNil)
+	defer audit.Close()
+
+	cli.runTests(c, nil, func(dbt *DBTest) {
+		dbt.mustExec("CREATE USER 'audituser'@'%' IDENTIFIED BY '123'")
+		dbt.mustExec("GRANT ALL on test.* to 'audituser'")
+	})
+	cli.runTests(c, func(config *mysql.Config) {
+		config.User = "audituser"
+		config.Passwd = "123"
+		config.DBName = "test"
+	}, func(dbt *DBTest) {
+		dbt.mustExec("CREATE TABLE audit_t (a int)")
+		dbt.mustExec("INSERT INTO audit_t VALUES (1), (2)")
+		dbt.mustQueryRows("SELECT * FROM audit_t WHERE a = ?", 1)
+		dbt.mustQueryRows("SELECT table_name FROM information_schema.tables")
+		_, err := dbt.db.Exec("SET PASSWORD FOR 'root'@'%' = 'abc'")
+		dbt.Assert(err, NotNil)
+	})
+	db, err := sql.Open("mysql", cli.getDSN(func(config *mysql.Config) {
+		config.User = "audituser"
+		config.Passwd = "456"
+	}))
+	c.Assert(err, IsNil)
+	c.Assert(db.Ping(), NotNil)
+	c.Assert(db.Close(), IsNil)
+
+	// The disconnection is detected by the server asynchronously.
+	var events []*audit.Event
+	for i := 0; i < 50; i++ {
+		if events = sink.Events(); len(events) >= 9 {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	c.Assert(events, HasLen, 9)
+	type eventSummary struct {
+		Class audit.EventClass
+		Type  string
+		SQL   string
+		Succ  bool
+	}
+	summaries := make([]eventSummary, 0, len(events))
+	for _, e := range events {
+		c.Assert(e.User, Equals, "audituser")
+		summaries = append(summaries, eventSummary{Class: e.Class, Type: e.Type, SQL: e.SQL, Succ: e.Succ})
+	}
+	// The table test is dropped by runTests.
+	c.Assert(summaries[:7], DeepEquals, []eventSummary{
+		{audit.ClassConnection, audit.TypeConnect, "", true},
+		{audit.ClassDDL, "DropTable", "DROP TABLE IF EXISTS test", true},
+		{audit.ClassDDL, "CreateTable", "CREATE TABLE audit_t (a int)", true},
+		{audit.ClassQuery, "Insert", "INSERT INTO audit_t VALUES (1), (2)", true},
+		{audit.ClassQuery, "Select", "SELECT * FROM audit_t WHERE a = ? [arguments: 1]", true},
+		{audit.ClassPrivilege, "SetPwd", "set password for user root@%", false},
+		{audit.ClassDDL, "DropTable", "DROP TABLE IF EXISTS test", true},
+	})
+	c.Assert(events[2].Tables, DeepEquals, []audit.Table{{DB: "test", Table: "audit_t"}})
+	c.Assert(events[3].AffectedRows, Equals, uint64(2))
+	c.Assert(events[4].Tables, DeepEquals, []audit.Table{{DB: "test", Table: "audit_t"}})
+	// The disconnection and the rejected connection are not ordered.
+	disconnect, reject := events[7], events[8]
+	if disconnect.Type != audit.TypeDisconnect {
+		disconnect, reject = reject, disconnect
+	}
+	c.Assert(disconnect.Type, Equals, audit.TypeDisconnect)
+	c.Assert(disconnect.ConnectionID, Equals, events[0].ConnectionID)
+	c.Assert(reject.Type, Equals, audit.TypeReject)
+	c.Assert(reject.Succ, IsFalse)
+	c.Assert(reject.Error, Matches, ".*Access denied.*")
+}
+
 func (cli *testServerClient) runTestIssue3662(c *C) {
 	db, err := sql.Open("mysql", cli.getDSN(func(config *mysql.Config) {
 		config.DBName = "non_existing_schema"
@@ -15,13 +15,18 @@ package server
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/statistics/handle"
 	"github.com/pingcap/tidb/store/tikv/oracle"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/gcutil"
@@ -60,7 +65,7 @@ func (sh StatsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		if err != nil {
 			writeError(w, err)
 		} else {
-			writeData(w, js)
+			writeStatsData(w, req, js)
 		}
 	}
 }
@@ -125,6 +130,77 @@ func (sh StatsHistoryHandler) ServeHTTP(w http.ResponseWriter, req *http.Request
 	if err != nil {
 		writeError(w, err)
 	} else {
+		writeStatsData(w, req, js)
+	}
+}
+
+// writeStatsData writes the dumped statistics, which are compressed by gzip if the `compress` parameter is true.
+func writeStatsData(w http.ResponseWriter, req *http.Request, js *handle.JSONTable) {
+	if compress, err := strconv.ParseBool(req.FormValue(qCompress)); err != nil || !compress {
 		writeData(w, js)
+		return
+	}
+	data, err := handle.CompressJSONTable(js)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set(headerContentType, "application/gzip")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(data)
+	terror.Log(errors.Trace(err))
+}
+
+// StatsLockHandler is the handler for locking and unlocking statistics.
+type StatsLockHandler struct {
+	do *domain.Domain
+}
+
+func (s *Server) newStatsLockHandler() *StatsLockHandler {
+	store, ok := s.driver.(*TiDBDriver)
+	if !ok {
+		panic("Illegal driver")
+	}
+
+	do, err := session.GetDomain(store.store)
+	if err != nil {
+		panic("Failed to get domain")
+	}
+	return &StatsLockHandler{do}
+}
+
+func (sh StatsLockHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	params := mux.Vars(req)
+	tbl, err := sh.do.InfoSchema().TableByName(model.NewCIStr(params[pDBName]), model.NewCIStr(params[pTableName]))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	var partitionNames []model.CIStr
+	if partitions := req.FormValue(qPartition); partitions != "" {
+		for _, name := range strings.Split(partitions, ",") {
+			partitionNames = append(partitionNames, model.NewCIStr(strings.TrimSpace(name)))
+		}
+	}
+	physicalIDs, err := handle.GetLockStatsPhysicalIDs(tbl.Meta(), partitionNames)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	h := sh.do.StatsHandle()
+	switch req.Method {
+	case http.MethodPost:
+		err = h.LockTableStats(physicalIDs)
+	case http.MethodDelete:
+		err = h.UnlockTableStats(physicalIDs)
+	default:
+		err = errors.Errorf("This api only support POST and DELETE method.")
+	}
+	if err != nil {
+		writeError(w, err)
+		return
 	}
+	writeData(w, "success!")
 }
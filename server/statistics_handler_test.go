@@ -144,6 +144,26 @@ func (ds *testDumpStatsSuite) TestDumpStatsAPI(c *C) {
 	_, err = fp1.Write(js)
 	c.Assert(err, IsNil)
 	ds.checkData(c, path1)
+
+	// test dump compressed stats
+	path2 := "/tmp/stats_history.json.gz"
+	fp2, err := os.Create(path2)
+	c.Assert(err, IsNil)
+	c.Assert(fp2, NotNil)
+	defer func() {
+		c.Assert(fp2.Close(), IsNil)
+		c.Assert(os.Remove(path2), IsNil)
+	}()
+
+	resp2, err := ds.fetchStatus("/stats/dump/tidb/test/" + snapshot + "?compress=true")
+	c.Assert(err, IsNil)
+	defer resp2.Body.Close()
+	c.Assert(resp2.Header.Get("Content-Type"), Equals, "application/gzip")
+	js, err = ioutil.ReadAll(resp2.Body)
+	c.Assert(err, IsNil)
+	_, err = fp2.Write(js)
+	c.Assert(err, IsNil)
+	ds.checkData(c, path2)
 }
 
 func (ds *testDumpStatsSuite) prepareData(c *C) {
@@ -248,7 +268,7 @@ func (ds *testDumpStatsSuite) checkData(c *C, path string) {
 	var dbName, tableName string
 	var modifyCount, count int64
 	var other interface{}
-	err = rows.Scan(&dbName, &tableName, &other, &other, &modifyCount, &count)
+	err = rows.Scan(&dbName, &tableName, &other, &other, &modifyCount, &count, &other)
 	dbt.Check(err, IsNil)
 	dbt.Check(dbName, Equals, "tidb")
 	dbt.Check(tableName, Equals, "test")
@@ -15,18 +15,23 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"database/sql"
 	"encoding/pem"
+	"fmt"
 	"io/ioutil"
 	"math/big"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -150,6 +155,38 @@ func (ts *tidbTestSerialSuite) TestConfigDefaultValue(c *C) {
 	})
 }
 
+func (ts *tidbTestSerialSuite) TestWriteSQLRespBytes(c *C) {
+	ts.runTestsOnNewDB(c, nil, "write_sql_resp_bytes", func(dbt *DBTest) {
+		defer func() {
+			dbt.mustExec("set tidb_slow_log_threshold=300;")
+			dbt.mustExec("set @@global.tidb_enable_stmt_summary=0")
+		}()
+		dbt.mustExec("set tidb_slow_log_threshold=0;")
+		dbt.mustExec("set @@global.tidb_enable_stmt_summary=1")
+		dbt.mustExec("create table t_resp (a varchar(100))")
+		values := strings.Repeat(fmt.Sprintf("('%s'),", strings.Repeat("a", 100)), 100)
+		dbt.mustExec("insert into t_resp values " + values[:len(values)-1])
+
+		rows := dbt.mustQuery("select a from t_resp")
+		for rows.Next() {
+		}
+		c.Assert(rows.Close(), IsNil)
+		// Each row is a packet of the 4-byte header, the 1-byte length and the 100-byte value.
+		minBytes := 100 * (4 + 1 + 100)
+		var respBytes int
+		rows = dbt.mustQuery("select max_write_sql_resp_bytes from information_schema.statements_summary where digest_text = 'select `a` from `t_resp`'")
+		c.Assert(rows.Next(), IsTrue)
+		c.Assert(rows.Scan(&respBytes), IsNil)
+		c.Assert(rows.Close(), IsNil)
+		c.Assert(respBytes, Greater, minBytes)
+		rows = dbt.mustQuery("select write_sql_response_bytes from information_schema.slow_query where query = 'select a from t_resp;' order by time desc limit 1")
+		c.Assert(rows.Next(), IsTrue)
+		c.Assert(rows.Scan(&respBytes), IsNil)
+		c.Assert(rows.Close(), IsNil)
+		c.Assert(respBytes, Greater, minBytes)
+	})
+}
+
 // this test will change `kv.TxnTotalSizeLimit` which may affect other test suites,
 // so we must make it running in serial.
 func (ts *tidbTestSerialSuite) TestLoadData(c *C) {
@@ -175,6 +212,10 @@ func (ts *tidbTestSerialSuite) TestLoadDataAutoRandomWithSpecialTerm(c *C) {
 	ts.runTestLoadDataAutoRandomWithSpecialTerm(c)
 }
 
+func (ts *tidbTestSerialSuite) TestLoadDataCompressed(c *C) {
+	ts.runTestLoadDataCompressed(c)
+}
+
 func (ts *tidbTestSerialSuite) TestExplainFor(c *C) {
 	ts.runTestExplainForConn(c)
 }
@@ -199,6 +240,58 @@ func (ts *tidbTestSuite) TestAuth(c *C) {
 	ts.runTestIssue3682(c)
 }
 
+func (ts *tidbTestSuite) TestCachingSha2Password(c *C) {
+	c.Parallel()
+	ts.runTestCachingSha2Password(c)
+}
+
+func (ts *tidbTestSuite) TestAuditLog(c *C) {
+	ts.runTestAuditLog(c)
+}
+
+func (ts *tidbTestSuite) TestProxyProtocol(c *C) {
+	cli := newTestServerClient()
+	cfg := newTestConfig()
+	cfg.Port = cli.port
+	cfg.Status.ReportStatus = false
+	cfg.ProxyProtocol.Networks = "127.0.0.0/8"
+	cfg.ProxyProtocol.HeaderTimeout = 5
+	server, err := NewServer(cfg, ts.tidbdrv)
+	c.Assert(err, IsNil)
+	cli.port = getPortFromTCPAddr(server.listener.Addr())
+	go func() {
+		err := server.Run()
+		c.Assert(err, IsNil)
+	}()
+	time.Sleep(time.Millisecond * 100)
+	defer server.Close()
+
+	// The frontend sends a v2 header before the handshake.
+	mysql.RegisterDial("proxy-v2", func(addr string) (net.Conn, error) {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			return nil, err
+		}
+		header := []byte{0x0D, 0x0A, 0x0D, 0x0A, 0x00, 0x0D, 0x0A, 0x51, 0x55, 0x49, 0x54, 0x0A, 0x21, 0x11, 0, 12,
+			192, 168, 1, 1, 10, 0, 0, 1, 0xC8, 0x22, 0x0F, 0xA0}
+		if _, err = conn.Write(header); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return conn, nil
+	})
+	cli.runTests(c, func(config *mysql.Config) {
+		config.Net = "proxy-v2"
+	}, func(dbt *DBTest) {
+		rows := dbt.mustQuery("select host from information_schema.processlist where id = connection_id()")
+		c.Assert(rows.Next(), IsTrue)
+		var host string
+		c.Assert(rows.Scan(&host), IsNil)
+		c.Assert(host, Equals, "192.168.1.1:51234")
+		c.Assert(rows.Close(), IsNil)
+	})
+}
+
 func (ts *tidbTestSuite) TestIssues(c *C) {
 	c.Parallel()
 	ts.runTestIssue3662(c)
@@ -361,6 +454,59 @@ func (ts *tidbTestSuite) TestMultiStatements(c *C) {
 	ts.runTestMultiStatements(c)
 }
 
+func (ts *tidbTestSuite) TestCompressedProtocol(c *C) {
+	for _, capability := range []uint32{tmysql.ClientCompress, clientZstdCompressionAlgorithm} {
+		conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", ts.port))
+		c.Assert(err, IsNil)
+		pkt := newPacketIO(newBufferedReadConn(conn))
+		// Skip the initial handshake.
+		_, err = pkt.readPacket()
+		c.Assert(err, IsNil)
+
+		capability |= tmysql.ClientProtocol41 | tmysql.ClientSecureConnection | tmysql.ClientPluginAuth | tmysql.ClientLongPassword
+		resp := make([]byte, 4, 64)
+		resp = dumpUint32(resp, capability)
+		resp = dumpUint32(resp, tmysql.MaxPayloadLen)
+		resp = append(resp, tmysql.DefaultCollationID)
+		resp = append(resp, make([]byte, 23)...)
+		resp = append(resp, "root\x00"...)
+		resp = append(resp, 0)
+		resp = append(resp, tmysql.AuthNativePassword+"\x00"...)
+		if capability&clientZstdCompressionAlgorithm > 0 {
+			resp = append(resp, 7)
+		}
+		c.Assert(pkt.writePacket(resp), IsNil)
+		c.Assert(pkt.flush(), IsNil)
+		data, err := pkt.readPacket()
+		c.Assert(err, IsNil)
+		c.Assert(data[0], Equals, byte(tmysql.OKHeader))
+
+		// The following packets are compressed.
+		pkt.setCompression(compressionZlib, defaultZstdLevel)
+		if capability&clientZstdCompressionAlgorithm > 0 {
+			pkt.setCompression(compressionZstd, 7)
+		}
+		for i := 0; i < 2; i++ {
+			pkt.resetSequence()
+			query := append([]byte{0, 0, 0, 0, tmysql.ComQuery}, "select repeat('a', 1000)"...)
+			c.Assert(pkt.writePacket(query), IsNil)
+			c.Assert(pkt.flush(), IsNil)
+			// The column count, the column, EOF, the row and EOF.
+			var packets [][]byte
+			for len(packets) < 5 {
+				data, err = pkt.readPacket()
+				c.Assert(err, IsNil)
+				packets = append(packets, data)
+			}
+			c.Assert(packets[0], DeepEquals, []byte{1})
+			c.Assert(packets[2][0], Equals, byte(tmysql.EOFHeader))
+			c.Assert(packets[3], DeepEquals, append([]byte{0xfc, 0xe8, 0x03}, bytes.Repeat([]byte{'a'}, 1000)...))
+			c.Assert(packets[4][0], Equals, byte(tmysql.EOFHeader))
+		}
+		c.Assert(conn.Close(), IsNil)
+	}
+}
+
 func (ts *tidbTestSuite) TestSocketForwarding(c *C) {
 	cli := newTestServerClient()
 	cfg := newTestConfig()
@@ -756,6 +902,57 @@ func (ts *tidbTestSerialSuite) TestReloadTLS(c *C) {
 	server.Close()
 }
 
+func (ts *tidbTestSerialSuite) TestSSLReloadLoop(c *C) {
+	defer func() {
+		os.Remove("/tmp/ca-key-autoreload.pem")
+		os.Remove("/tmp/ca-cert-autoreload.pem")
+		os.Remove("/tmp/server-key-autoreload.pem")
+		os.Remove("/tmp/server-cert-autoreload.pem")
+	}()
+	cfg := newTestConfig()
+	cfg.Security = config.Security{
+		SSLCA:   "/tmp/ca-cert-autoreload.pem",
+		SSLCert: "/tmp/server-cert-autoreload.pem",
+		SSLKey:  "/tmp/server-key-autoreload.pem",
+	}
+	server := &Server{cfg: cfg, capability: defaultCapability}
+	exit := make(chan struct{})
+	defer close(exit)
+	go server.sslReloadLoop(10*time.Millisecond, sslFilesVersion(cfg.Security.SSLCA, cfg.Security.SSLKey, cfg.Security.SSLCert), exit)
+	c.Assert(server.getCapability()&tmysql.ClientSSL, Equals, uint32(0))
+
+	// TLS is enabled after the certificates are written.
+	caCert, caKey, err := generateCert(0, "TiDB CA", nil, nil, "/tmp/ca-key-autoreload.pem", "/tmp/ca-cert-autoreload.pem")
+	c.Assert(err, IsNil)
+	_, _, err = generateCert(1, "tidb-server", caCert, caKey, "/tmp/server-key-autoreload.pem", "/tmp/server-cert-autoreload.pem")
+	c.Assert(err, IsNil)
+	waitTLSConfig := func(check func(*tls.Config) bool) {
+		for i := 0; i < 100 && !check(server.getTLSConfig()); i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		c.Assert(check(server.getTLSConfig()), IsTrue)
+	}
+	waitTLSConfig(func(tlsCfg *tls.Config) bool { return tlsCfg != nil })
+	c.Assert(server.getCapability()&tmysql.ClientSSL, Equals, uint32(tmysql.ClientSSL))
+	oldTLSCfg := server.getTLSConfig()
+
+	// The certificate is replaced after it's rewritten.
+	_, _, err = generateCert(1, "tidb-server", caCert, caKey, "/tmp/server-key-autoreload.pem", "/tmp/server-cert-autoreload.pem", func(c *x509.Certificate) {
+		c.NotAfter = time.Now().Add(1 * time.Hour).UTC()
+	})
+	c.Assert(err, IsNil)
+	waitTLSConfig(func(tlsCfg *tls.Config) bool { return tlsCfg != oldTLSCfg })
+	cert, err := x509.ParseCertificate(server.getTLSConfig().Certificates[0].Certificate[0])
+	c.Assert(err, IsNil)
+	c.Assert(cert.NotAfter.Before(time.Now().Add(2*time.Hour)), IsTrue)
+
+	// The old certificate is kept if the new one is broken.
+	oldTLSCfg = server.getTLSConfig()
+	c.Assert(ioutil.WriteFile("/tmp/server-cert-autoreload.pem", []byte("broken"), 0600), IsNil)
+	time.Sleep(100 * time.Millisecond)
+	c.Assert(server.getTLSConfig(), Equals, oldTLSCfg)
+}
+
 func (ts *tidbTestSerialSuite) TestErrorNoRollback(c *C) {
 	// Generate valid TLS certificates.
 	caCert, caKey, err := generateCert(0, "TiDB CA", nil, nil, "/tmp/ca-key-rollback.pem", "/tmp/ca-cert-rollback.pem")
@@ -1091,6 +1288,158 @@ func (ts *tidbTestSuite) TestGracefulShutdown(c *C) {
 	c.Assert(err, ErrorMatches, ".*connect: connection refused")
 }
 
+func (ts *tidbTestSuite) TestGracefulShutdownDrain(c *C) {
+	cli := newTestServerClient()
+	cfg := newTestConfig()
+	cfg.GracefulWaitBeforeShutdown = 2
+	cfg.Port = 0
+	cfg.Status.StatusPort = 0
+	cfg.Status.ReportStatus = true
+	server, err := NewServer(cfg, ts.tidbdrv)
+	c.Assert(err, IsNil)
+	cli.port = getPortFromTCPAddr(server.listener.Addr())
+	cli.statusPort = getPortFromTCPAddr(server.statusListener.Addr())
+	go func() {
+		err := server.Run()
+		c.Assert(err, IsNil)
+	}()
+	time.Sleep(time.Millisecond * 100)
+
+	db, err := sql.Open("mysql", cli.getDSN())
+	c.Assert(err, IsNil)
+	defer db.Close()
+	ctx := context.Background()
+	_, err = db.Exec("create table if not exists drain (a int)")
+	c.Assert(err, IsNil)
+	conns := make([]*sql.Conn, 4)
+	for i := range conns {
+		conns[i], err = db.Conn(ctx)
+		c.Assert(err, IsNil)
+		defer conns[i].Close()
+	}
+	// conns[0] is idle, conns[1] commits its transaction in the drain time, conns[2] never commits its transaction,
+	// and conns[3] is running a statement when the drain time elapses.
+	for _, conn := range conns[1:] {
+		_, err = conn.ExecContext(ctx, "begin")
+		c.Assert(err, IsNil)
+		_, err = conn.ExecContext(ctx, "insert into drain values (1)")
+		c.Assert(err, IsNil)
+	}
+	sleepDone := make(chan error, 1)
+
+	go server.Close()
+	time.Sleep(time.Millisecond * 500)
+
+	// The health check fails and the new connections are refused.
+	resp, err := cli.fetchStatus("/status")
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusInternalServerError)
+	c.Assert(resp.Body.Close(), IsNil)
+	newDB, err := sql.Open("mysql", cli.getDSN())
+	c.Assert(err, IsNil)
+	c.Assert(newDB.Ping(), NotNil)
+	c.Assert(newDB.Close(), IsNil)
+
+	// The idle connection is closed, the transactions can still be committed.
+	_, err = conns[0].ExecContext(ctx, "select 1")
+	c.Assert(err, NotNil)
+	_, err = conns[1].ExecContext(ctx, "insert into drain values (2)")
+	c.Assert(err, IsNil)
+	_, err = conns[1].ExecContext(ctx, "commit")
+	c.Assert(err, IsNil)
+	go func() {
+		_, err := conns[3].ExecContext(ctx, "select sleep(10)")
+		sleepDone <- err
+	}()
+
+	// The stragglers are cancelled and their transactions are rolled back.
+	select {
+	case <-sleepDone:
+	case <-time.After(5 * time.Second):
+		c.Fatal("the running statement is not cancelled")
+	}
+	time.Sleep(time.Millisecond * 500)
+	for _, conn := range conns[2:] {
+		_, err = conn.ExecContext(ctx, "commit")
+		c.Assert(err, NotNil)
+	}
+	c.Assert(server.ConnectionCount(), Equals, 0)
+	_, err = cli.fetchStatus("/status")
+	c.Assert(err, ErrorMatches, ".*connect: connection refused")
+
+	se, err := session.CreateSession4Test(ts.store)
+	c.Assert(err, IsNil)
+	defer se.Close()
+	rs, err := se.Execute(ctx, "select a from test.drain order by a")
+	c.Assert(err, IsNil)
+	rows, err := session.GetRows4Test(ctx, se, rs[0])
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 2)
+	c.Assert(rows[0].GetInt64(0), Equals, int64(1))
+	c.Assert(rows[1].GetInt64(0), Equals, int64(2))
+	_, err = se.Execute(ctx, "drop table test.drain")
+	c.Assert(err, IsNil)
+}
+
+func (ts *tidbTestSuite) TestWorkerPool(c *C) {
+	cli := newTestServerClient()
+	cfg := newTestConfig()
+	cfg.Port = 0
+	cfg.Status.ReportStatus = false
+	cfg.WorkerPool = config.WorkerPool{Enable: true, Size: 1, MaxQueueSize: 1}
+	server, err := NewServer(cfg, ts.tidbdrv)
+	c.Assert(err, IsNil)
+	cli.port = getPortFromTCPAddr(server.listener.Addr())
+	go func() {
+		err := server.Run()
+		c.Assert(err, IsNil)
+	}()
+	defer server.Close()
+	time.Sleep(time.Millisecond * 100)
+
+	db, err := sql.Open("mysql", cli.getDSN())
+	c.Assert(err, IsNil)
+	defer db.Close()
+	ctx := context.Background()
+	conns := make([]*sql.Conn, 4)
+	for i := range conns {
+		conns[i], err = db.Conn(ctx)
+		c.Assert(err, IsNil)
+		defer conns[i].Close()
+	}
+	// conns[3] is in a transaction, its statements aren't queued.
+	_, err = conns[3].ExecContext(ctx, "begin")
+	c.Assert(err, IsNil)
+
+	// conns[0] occupies the only worker and conns[1] waits in the queue.
+	sleepDone := make(chan error, 1)
+	go func() {
+		_, err := conns[0].ExecContext(ctx, "select sleep(1)")
+		sleepDone <- err
+	}()
+	time.Sleep(time.Millisecond * 200)
+	queuedDone := make(chan error, 1)
+	go func() {
+		_, err := conns[1].ExecContext(ctx, "select 1")
+		queuedDone <- err
+	}()
+	time.Sleep(time.Millisecond * 200)
+	c.Assert(server.workerPool.Queued(), Equals, 1)
+
+	// The queue is full, the statement is rejected and the connection can be used later.
+	_, err = conns[2].ExecContext(ctx, "select 1")
+	c.Assert(err, ErrorMatches, ".*8243.*Server is busy: the queue of the worker pool is full")
+	_, err = conns[3].ExecContext(ctx, "select 1")
+	c.Assert(err, IsNil)
+	_, err = conns[3].ExecContext(ctx, "commit")
+	c.Assert(err, IsNil)
+
+	c.Assert(<-sleepDone, IsNil)
+	c.Assert(<-queuedDone, IsNil)
+	_, err = conns[2].ExecContext(ctx, "select 1")
+	c.Assert(err, IsNil)
+}
+
 func (ts *tidbTestSerialSuite) TestDefaultCharacterAndCollation(c *C) {
 	// issue #21194
 	collate.SetNewCollationEnabledForTest(true)
@@ -43,10 +43,13 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/pingcap/parser/charset"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/encoding"
 	"github.com/pingcap/tidb/util/hack"
 )
 
@@ -230,7 +233,53 @@ func dumpBinaryDateTime(data []byte, t types.Time) []byte {
 	return data
 }
 
-func dumpBinaryRow(buffer []byte, columns []*ColumnInfo, row chunk.Row) ([]byte, error) {
+// resultEncoder encodes the non-binary strings in the results in character_set_results.
+type resultEncoder struct {
+	conv     *encoding.Converter
+	handling encoding.InvalidCharHandling
+	buf      []byte
+}
+
+// newResultEncoder returns nil if the strings are sent as they are, which is the case for all the
+// character sets compatible with UTF-8.
+func newResultEncoder(vars *variable.SessionVars) *resultEncoder {
+	cs, ok := vars.GetSystemVar(variable.CharacterSetResults)
+	if !ok || encoding.IsUTF8Compatible(cs) {
+		return nil
+	}
+	conv, err := encoding.NewConverter(cs, charset.CharsetBin)
+	if err != nil {
+		return nil
+	}
+	return &resultEncoder{conv: conv, handling: vars.InvalidCharHandling}
+}
+
+// encode encodes str of the column, the binary strings are kept as they are. The characters which
+// can't be represented are replaced by '?' silently unless tidb_invalid_char_handling is ERROR, since
+// the warnings can't be reported after the results are sent.
+func (e *resultEncoder) encode(col *ColumnInfo, str []byte) ([]byte, error) {
+	if e == nil || col.Charset == mysql.BinaryDefaultCollationID {
+		return str, nil
+	}
+	res, _, err := e.conv.Convert(e.buf[:0], str, e.handling)
+	if err != nil {
+		return nil, err
+	}
+	e.buf = res
+	return res, nil
+}
+
+// dumpLengthEncodedString encodes str of the column and dumps it.
+func (e *resultEncoder) dumpLengthEncodedString(buffer []byte, col *ColumnInfo, str []byte) ([]byte, error) {
+	str, err := e.encode(col, str)
+	if err != nil {
+		return nil, err
+	}
+	return dumpLengthEncodedString(buffer, str), nil
+}
+
+func dumpBinaryRow(buffer []byte, columns []*ColumnInfo, row chunk.Row, e *resultEncoder) ([]byte, error) {
+	var err error
 	buffer = append(buffer, mysql.OKHeader)
 	nullBitmapOff := len(buffer)
 	numBytes4Null := (len(columns) + 7 + 2) / 8
@@ -260,26 +309,30 @@ func dumpBinaryRow(buffer []byte, columns []*ColumnInfo, row chunk.Row) ([]byte,
 		case mysql.TypeNewDecimal:
 			buffer = dumpLengthEncodedString(buffer, hack.Slice(row.GetMyDecimal(i).String()))
 		case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar, mysql.TypeBit,
-			mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
-			buffer = dumpLengthEncodedString(buffer, row.GetBytes(i))
+			mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob, mysql.TypeGeometry:
+			buffer, err = e.dumpLengthEncodedString(buffer, columns[i], row.GetBytes(i))
 		case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
 			buffer = dumpBinaryDateTime(buffer, row.GetTime(i))
 		case mysql.TypeDuration:
 			buffer = append(buffer, dumpBinaryTime(row.GetDuration(i, 0).Duration)...)
 		case mysql.TypeEnum:
-			buffer = dumpLengthEncodedString(buffer, hack.Slice(row.GetEnum(i).String()))
+			buffer, err = e.dumpLengthEncodedString(buffer, columns[i], hack.Slice(row.GetEnum(i).String()))
 		case mysql.TypeSet:
-			buffer = dumpLengthEncodedString(buffer, hack.Slice(row.GetSet(i).String()))
+			buffer, err = e.dumpLengthEncodedString(buffer, columns[i], hack.Slice(row.GetSet(i).String()))
 		case mysql.TypeJSON:
 			buffer = dumpLengthEncodedString(buffer, hack.Slice(row.GetJSON(i).String()))
 		default:
 			return nil, errInvalidType.GenWithStack("invalid type %v", columns[i].Type)
 		}
+		if err != nil {
+			return nil, err
+		}
 	}
 	return buffer, nil
 }
 
-func dumpTextRow(buffer []byte, columns []*ColumnInfo, row chunk.Row) ([]byte, error) {
+func dumpTextRow(buffer []byte, columns []*ColumnInfo, row chunk.Row, e *resultEncoder) ([]byte, error) {
+	var err error
 	tmp := make([]byte, 0, 20)
 	for i, col := range columns {
 		if row.IsNull(i) {
@@ -323,22 +376,25 @@ func dumpTextRow(buffer []byte, columns []*ColumnInfo, row chunk.Row) ([]byte, e
 		case mysql.TypeNewDecimal:
 			buffer = dumpLengthEncodedString(buffer, hack.Slice(row.GetMyDecimal(i).String()))
 		case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar, mysql.TypeBit,
-			mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
-			buffer = dumpLengthEncodedString(buffer, row.GetBytes(i))
+			mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob, mysql.TypeGeometry:
+			buffer, err = e.dumpLengthEncodedString(buffer, columns[i], row.GetBytes(i))
 		case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
 			buffer = dumpLengthEncodedString(buffer, hack.Slice(row.GetTime(i).String()))
 		case mysql.TypeDuration:
 			dur := row.GetDuration(i, int(col.Decimal))
 			buffer = dumpLengthEncodedString(buffer, hack.Slice(dur.String()))
 		case mysql.TypeEnum:
-			buffer = dumpLengthEncodedString(buffer, hack.Slice(row.GetEnum(i).String()))
+			buffer, err = e.dumpLengthEncodedString(buffer, columns[i], hack.Slice(row.GetEnum(i).String()))
 		case mysql.TypeSet:
-			buffer = dumpLengthEncodedString(buffer, hack.Slice(row.GetSet(i).String()))
+			buffer, err = e.dumpLengthEncodedString(buffer, columns[i], hack.Slice(row.GetSet(i).String()))
 		case mysql.TypeJSON:
 			buffer = dumpLengthEncodedString(buffer, hack.Slice(row.GetJSON(i).String()))
 		default:
 			return nil, errInvalidType.GenWithStack("invalid type %v", columns[i].Type)
 		}
+		if err != nil {
+			return nil, err
+		}
 	}
 	return buffer, nil
 }
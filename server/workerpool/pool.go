@@ -0,0 +1,245 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workerpool
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// Priority is the priority of a task, the queued tasks of higher priorities are executed first.
+type Priority int
+
+// The priorities of the tasks.
+const (
+	PriorityLow Priority = iota
+	PriorityMedium
+	PriorityHigh
+	numPriorities
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityMedium:
+		return "medium"
+	case PriorityHigh:
+		return "high"
+	}
+	return "unknown"
+}
+
+var (
+	// ErrQueueFull is returned if the queue is full when the task is submitted, or the queued task is shed for a
+	// task of a higher priority.
+	ErrQueueFull = errors.New("the queue of the worker pool is full")
+	// ErrQueueTimeout is returned if the task isn't executed in the max queue time.
+	ErrQueueTimeout = errors.New("the request waits too long in the queue of the worker pool")
+	// ErrPoolClosed is returned if the pool is closed before the task is executed.
+	ErrPoolClosed = errors.New("the worker pool is closed")
+)
+
+type task struct {
+	fn       func() error
+	priority Priority
+	enqueued time.Time
+	// elem is the element of the task in its queue, nil if the task isn't queued.
+	elem *list.Element
+	done chan result
+}
+
+type result struct {
+	err        error
+	panicked   bool
+	panicValue interface{}
+}
+
+// Pool runs the tasks in a fixed number of workers. The tasks wait in the queues of their priorities when all the
+// workers are busy, and they are shed when the queues are full or they wait too long.
+type Pool struct {
+	maxQueueSize int
+	maxQueueTime time.Duration
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queues [numPriorities]*list.List
+	queued int
+	closed bool
+}
+
+// New creates a pool with size workers. At most maxQueueSize tasks can be queued, and the tasks are rejected if they
+// wait longer than maxQueueTime, 0 means no limit.
+func New(size, maxQueueSize int, maxQueueTime time.Duration) *Pool {
+	p := &Pool{
+		maxQueueSize: maxQueueSize,
+		maxQueueTime: maxQueueTime,
+	}
+	p.cond = sync.NewCond(&p.mu)
+	for i := range p.queues {
+		p.queues[i] = list.New()
+	}
+	for i := 0; i < size; i++ {
+		go p.worker()
+	}
+	return p
+}
+
+// Execute runs fn in a worker and returns its error, or returns an error without running fn if the task is rejected.
+// The panic of fn is re-panicked in the caller.
+func (p *Pool) Execute(priority Priority, fn func() error) error {
+	t := &task{fn: fn, priority: priority, enqueued: time.Now(), done: make(chan result, 1)}
+	if err := p.enqueue(t); err != nil {
+		return err
+	}
+	var timeout <-chan time.Time
+	if p.maxQueueTime > 0 {
+		timer := time.NewTimer(p.maxQueueTime)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	var r result
+	select {
+	case r = <-t.done:
+	case <-timeout:
+		if p.cancel(t) {
+			return ErrQueueTimeout
+		}
+		// The task has been taken by a worker or shed.
+		r = <-t.done
+	}
+	if r.panicked {
+		panic(r.panicValue)
+	}
+	return r.err
+}
+
+func (p *Pool) enqueue(t *task) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+	if p.maxQueueSize > 0 && p.queued >= p.maxQueueSize {
+		// Shed the newest task of the lowest priority which is lower than the new task.
+		var victim *task
+		for i := PriorityLow; i < t.priority; i++ {
+			if back := p.queues[i].Back(); back != nil {
+				victim = back.Value.(*task)
+				break
+			}
+		}
+		if victim == nil {
+			metrics.WorkerPoolRejectedCounter.WithLabelValues(t.priority.String(), "queue_full").Inc()
+			return ErrQueueFull
+		}
+		p.removeLocked(victim)
+		metrics.WorkerPoolRejectedCounter.WithLabelValues(victim.priority.String(), "queue_full").Inc()
+		victim.done <- result{err: ErrQueueFull}
+	}
+	t.elem = p.queues[t.priority].PushBack(t)
+	p.queued++
+	metrics.WorkerPoolQueueLength.WithLabelValues(t.priority.String()).Inc()
+	p.cond.Signal()
+	return nil
+}
+
+// cancel removes the task from its queue, it returns false if the task isn't queued.
+func (p *Pool) cancel(t *task) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if t.elem == nil {
+		return false
+	}
+	p.removeLocked(t)
+	metrics.WorkerPoolRejectedCounter.WithLabelValues(t.priority.String(), "queue_timeout").Inc()
+	return true
+}
+
+func (p *Pool) removeLocked(t *task) {
+	p.queues[t.priority].Remove(t.elem)
+	t.elem = nil
+	p.queued--
+	metrics.WorkerPoolQueueLength.WithLabelValues(t.priority.String()).Dec()
+}
+
+// next takes the oldest task of the highest priority, it returns nil if the pool is closed.
+func (p *Pool) next() *task {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for p.queued == 0 && !p.closed {
+		p.cond.Wait()
+	}
+	for i := numPriorities - 1; i >= PriorityLow; i-- {
+		if front := p.queues[i].Front(); front != nil {
+			t := front.Value.(*task)
+			p.removeLocked(t)
+			return t
+		}
+	}
+	return nil
+}
+
+func (p *Pool) worker() {
+	for {
+		t := p.next()
+		if t == nil {
+			return
+		}
+		metrics.WorkerPoolQueueDuration.WithLabelValues(t.priority.String()).Observe(time.Since(t.enqueued).Seconds())
+		t.done <- run(t.fn)
+	}
+}
+
+func run(fn func() error) (r result) {
+	defer func() {
+		if v := recover(); v != nil {
+			logutil.BgLogger().Error("worker pool task panicked", zap.Reflect("r", v), zap.Stack("stack"))
+			r = result{panicked: true, panicValue: v}
+		}
+	}()
+	return result{err: fn()}
+}
+
+// Queued returns the number of the queued tasks.
+func (p *Pool) Queued() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.queued
+}
+
+// Close stops the workers after the running tasks finish, the queued tasks are rejected.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.closed = true
+	for i := range p.queues {
+		for e := p.queues[i].Front(); e != nil; e = p.queues[i].Front() {
+			t := e.Value.(*task)
+			p.removeLocked(t)
+			metrics.WorkerPoolRejectedCounter.WithLabelValues(t.priority.String(), "closed").Inc()
+			t.done <- result{err: ErrPoolClosed}
+		}
+	}
+	p.cond.Broadcast()
+}
@@ -0,0 +1,155 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workerpool
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/pingcap/check"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testPoolSuite{})
+
+type testPoolSuite struct{}
+
+// block occupies the only worker of the pool until the returned channel is closed.
+func block(c *C, p *Pool) chan struct{} {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go func() {
+		err := p.Execute(PriorityHigh, func() error {
+			close(started)
+			<-release
+			return nil
+		})
+		c.Check(err, IsNil)
+	}()
+	<-started
+	return release
+}
+
+func waitQueued(c *C, p *Pool, n int) {
+	for i := 0; i < 100 && p.Queued() != n; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Assert(p.Queued(), Equals, n)
+}
+
+func (s *testPoolSuite) TestExecute(c *C) {
+	p := New(2, 0, 0)
+	defer p.Close()
+	errTest := errors.New("test")
+	c.Assert(p.Execute(PriorityMedium, func() error { return nil }), IsNil)
+	c.Assert(p.Execute(PriorityLow, func() error { return errTest }), Equals, errTest)
+	c.Assert(func() {
+		_ = p.Execute(PriorityHigh, func() error { panic("boom") })
+	}, PanicMatches, "boom")
+	// The worker survives the panic.
+	c.Assert(p.Execute(PriorityHigh, func() error { return nil }), IsNil)
+}
+
+func (s *testPoolSuite) TestPriority(c *C) {
+	p := New(1, 0, 0)
+	defer p.Close()
+	release := block(c, p)
+
+	var mu sync.Mutex
+	var order []Priority
+	var wg sync.WaitGroup
+	for i, priority := range []Priority{PriorityLow, PriorityMedium, PriorityHigh, PriorityLow} {
+		priority := priority
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := p.Execute(priority, func() error {
+				mu.Lock()
+				order = append(order, priority)
+				mu.Unlock()
+				return nil
+			})
+			c.Check(err, IsNil)
+		}()
+		waitQueued(c, p, i+1)
+	}
+	close(release)
+	wg.Wait()
+	c.Assert(order, DeepEquals, []Priority{PriorityHigh, PriorityMedium, PriorityLow, PriorityLow})
+}
+
+func (s *testPoolSuite) TestShed(c *C) {
+	p := New(1, 2, 0)
+	defer p.Close()
+	release := block(c, p)
+
+	errs := make([]chan error, 2)
+	for i, priority := range []Priority{PriorityLow, PriorityMedium} {
+		errs[i] = make(chan error, 1)
+		priority, ch := priority, errs[i]
+		go func() {
+			ch <- p.Execute(priority, func() error { return nil })
+		}()
+		waitQueued(c, p, i+1)
+	}
+	// The queue is full, the task of the same priority is rejected.
+	c.Assert(p.Execute(PriorityLow, func() error { return nil }), Equals, ErrQueueFull)
+	// The low priority task is shed for the high priority task.
+	highErr := make(chan error, 1)
+	go func() {
+		highErr <- p.Execute(PriorityHigh, func() error { return nil })
+	}()
+	c.Assert(<-errs[0], Equals, ErrQueueFull)
+	waitQueued(c, p, 2)
+	close(release)
+	c.Assert(<-errs[1], IsNil)
+	c.Assert(<-highErr, IsNil)
+}
+
+func (s *testPoolSuite) TestQueueTimeout(c *C) {
+	p := New(1, 0, 50*time.Millisecond)
+	defer p.Close()
+	release := block(c, p)
+	start := time.Now()
+	c.Assert(p.Execute(PriorityMedium, func() error { return nil }), Equals, ErrQueueTimeout)
+	c.Assert(time.Since(start), GreaterEqual, 50*time.Millisecond)
+	c.Assert(p.Queued(), Equals, 0)
+	close(release)
+
+	// The running time of the task isn't limited.
+	c.Assert(p.Execute(PriorityMedium, func() error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	}), IsNil)
+}
+
+func (s *testPoolSuite) TestClose(c *C) {
+	p := New(1, 0, 0)
+	release := block(c, p)
+	queuedErr := make(chan error, 1)
+	go func() {
+		queuedErr <- p.Execute(PriorityMedium, func() error { return nil })
+	}()
+	waitQueued(c, p, 1)
+	p.Close()
+	c.Assert(<-queuedErr, Equals, ErrPoolClosed)
+	c.Assert(p.Execute(PriorityMedium, func() error { return nil }), Equals, ErrPoolClosed)
+	close(release)
+	p.Close()
+}
@@ -0,0 +1,1156 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/privilege/privileges"
+	"github.com/pingcap/tidb/server/audit"
+	"github.com/pingcap/tidb/server/mysqlx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/store/tikv/util"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/execdetails"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/proxyprotocol"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/stringutil"
+	"go.uber.org/zap"
+)
+
+// The authentication mechanisms of the X Protocol.
+const (
+	xAuthMySQL41 = "MYSQL41"
+	xAuthPlain   = "PLAIN"
+)
+
+// xMaxAuthAttempts is the number of the authentication attempts allowed on a connection, the connectors may try
+// several mechanisms before the one supported.
+const xMaxAuthAttempts = 3
+
+// xConn is a client connection of the MySQL X Protocol. It shares the session, the authentication and the
+// registration in the server with the MySQL protocol, so the connections can be listed and killed as usual.
+type xConn struct {
+	*clientConn
+	bufWriter *bufio.Writer
+	// warningsNotice indicates whether the warnings of the statements are sent by the notices.
+	warningsNotice bool
+	// expects are the open expectation blocks, the last one is the innermost.
+	expects []*xExpectBlock
+}
+
+// xExpectBlock is an expectation block opened by Mysqlx.Expect.Open. If it expects no error, the messages in the
+// block fail once a message fails.
+type xExpectBlock struct {
+	noError bool
+	failed  bool
+}
+
+func newXConn(cc *clientConn) *xConn {
+	return &xConn{
+		clientConn:     cc,
+		bufWriter:      bufio.NewWriterSize(cc.bufReadConn, defaultWriterSize),
+		warningsNotice: true,
+	}
+}
+
+func (xc *xConn) writeMessage(typ byte, payload []byte) error {
+	return mysqlx.WriteMessage(xc.bufWriter, typ, payload)
+}
+
+func (xc *xConn) flush() error {
+	return errors.Trace(xc.bufWriter.Flush())
+}
+
+// writeError writes the error, the errors of TiDB are converted like the MySQL protocol.
+func (xc *xConn) writeError(e error) error {
+	xerr, ok := errors.Cause(e).(*mysqlx.Error)
+	if !ok {
+		m := toSQLError(e)
+		xerr = &mysqlx.Error{Code: m.Code, SQLState: m.State, Message: m.Message}
+	}
+	xc.lastCode = xerr.Code
+	errno.IncrementError(xerr.Code, xc.user, xc.peerHost)
+	if err := xc.writeMessage(mysqlx.ServerError, mysqlx.EncodeError(xerr)); err != nil {
+		return err
+	}
+	return xc.flush()
+}
+
+func (xc *xConn) readMessage(ctx context.Context) (byte, []byte, error) {
+	var timeout time.Duration
+	if xc.ctx != nil {
+		timeout = time.Duration(xc.getSessionVarsWaitTimeout(ctx)) * time.Second
+	}
+	deadline := time.Time{}
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	if err := xc.bufReadConn.SetReadDeadline(deadline); err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+	return mysqlx.ReadMessage(xc.bufReadConn)
+}
+
+// handshake negotiates the capabilities and authenticates the client.
+func (xc *xConn) handshake(ctx context.Context) error {
+	authAttempts := 0
+	for {
+		typ, payload, err := xc.readMessage(ctx)
+		if err != nil {
+			return err
+		}
+		switch typ {
+		case mysqlx.ClientCapabilitiesGet:
+			err = xc.writeCapabilities()
+		case mysqlx.ClientCapabilitiesSet:
+			err = xc.handleCapabilitiesSet(payload)
+		case mysqlx.ClientClose:
+			if err = xc.writeMessage(mysqlx.ServerOk, mysqlx.EncodeOk("bye!")); err == nil {
+				err = xc.flush()
+			}
+			if err == nil {
+				err = io.EOF
+			}
+			return err
+		case mysqlx.ClientAuthenticateStart:
+			authAttempts++
+			err = xc.authenticate(ctx, payload)
+			if err == nil {
+				return xc.afterAuthenticated(ctx)
+			}
+			if authAttempts >= xMaxAuthAttempts {
+				terror.Log(xc.writeError(err))
+				return err
+			}
+		default:
+			err = mysqlx.ErrUnexpectedMessage.GenByArgs(typ)
+			terror.Log(xc.writeError(err))
+			return err
+		}
+		if err != nil {
+			if _, ok := errors.Cause(err).(*mysqlx.Error); !ok && !isAuthError(err) {
+				return err
+			}
+			if err = xc.writeError(err); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func isAuthError(err error) bool {
+	return errAccessDenied.Equal(err) || errConCount.Equal(err) || errSecureTransportRequired.Equal(err) ||
+		errServerShutdown.Equal(err)
+}
+
+func (xc *xConn) capabilities() []*mysqlx.Capability {
+	mechanisms := []*mysqlx.Any{mysqlx.NewScalarAny(mysqlx.NewStringScalar(xAuthMySQL41))}
+	if xc.tlsConn != nil {
+		mechanisms = append(mechanisms, mysqlx.NewScalarAny(mysqlx.NewStringScalar(xAuthPlain)))
+	}
+	caps := []*mysqlx.Capability{
+		{Name: "authentication.mechanisms", Value: &mysqlx.Any{Type: mysqlx.AnyArray, Array: mechanisms}},
+		{Name: "doc.formats", Value: mysqlx.NewScalarAny(mysqlx.NewStringScalar("text"))},
+		{Name: "node_type", Value: mysqlx.NewScalarAny(mysqlx.NewStringScalar("mysql"))},
+		{Name: "client.pwd_expire_ok", Value: mysqlx.NewScalarAny(&mysqlx.Scalar{Type: mysqlx.ScalarBool})},
+	}
+	if xc.server.getTLSConfig() != nil {
+		caps = append(caps, &mysqlx.Capability{
+			Name:  "tls",
+			Value: mysqlx.NewScalarAny(&mysqlx.Scalar{Type: mysqlx.ScalarBool, Bool: xc.tlsConn != nil}),
+		})
+	}
+	return caps
+}
+
+func (xc *xConn) writeCapabilities() error {
+	if err := xc.writeMessage(mysqlx.ServerCapabilities, mysqlx.EncodeCapabilities(xc.capabilities())); err != nil {
+		return err
+	}
+	return xc.flush()
+}
+
+// handleCapabilitiesSet sets the capabilities, the connection is upgraded to TLS after Ok is sent if tls is set.
+func (xc *xConn) handleCapabilitiesSet(payload []byte) error {
+	caps, err := mysqlx.DecodeCapabilitiesSet(payload)
+	if err != nil {
+		return err
+	}
+	upgradeToTLS := false
+	for _, c := range caps {
+		switch c.Name {
+		case "tls":
+			if c.Value == nil || c.Value.Type != mysqlx.AnyScalar || c.Value.Scalar.Type != mysqlx.ScalarBool ||
+				xc.server.getTLSConfig() == nil || xc.tlsConn != nil {
+				return mysqlx.ErrCapabilitiesPrepare.GenByArgs(c.Name)
+			}
+			upgradeToTLS = c.Value.Scalar.Bool
+		case "session_connect_attrs":
+			if c.Value == nil || c.Value.Type != mysqlx.AnyObject {
+				return mysqlx.ErrCapabilitiesPrepare.GenByArgs(c.Name)
+			}
+			attrs := make(map[string]string, len(c.Value.Object))
+			for _, fld := range c.Value.Object {
+				if fld.Value.Type == mysqlx.AnyScalar {
+					attrs[fld.Key] = string(fld.Value.Scalar.Bytes)
+				}
+			}
+			xc.attrs = attrs
+		case "client.pwd_expire_ok", "client.interactive":
+			// The client capabilities which don't affect the server.
+		default:
+			return mysqlx.ErrCapabilityNotFound.GenByArgs(c.Name)
+		}
+	}
+	if err = xc.writeMessage(mysqlx.ServerOk, mysqlx.EncodeOk("")); err != nil {
+		return err
+	}
+	if err = xc.flush(); err != nil {
+		return err
+	}
+	if upgradeToTLS {
+		if err = xc.upgradeToTLS(xc.server.getTLSConfig()); err != nil {
+			return err
+		}
+		xc.bufWriter = bufio.NewWriterSize(xc.bufReadConn, defaultWriterSize)
+	}
+	return nil
+}
+
+// authenticate authenticates the client by MYSQL41, which is the challenge-response of mysql_native_password, or
+// PLAIN, which sends the password in the TLS connections.
+func (xc *xConn) authenticate(ctx context.Context, payload []byte) error {
+	start, err := mysqlx.DecodeAuthenticateStart(payload)
+	if err != nil {
+		return err
+	}
+	if xc.tlsConn == nil && config.GetGlobalConfig().Security.RequireSecureTransport {
+		return errSecureTransportRequired.FastGenByArgs()
+	}
+	var authData []byte
+	switch start.MechName {
+	case xAuthMySQL41:
+		if err = xc.writeMessage(mysqlx.ServerAuthenticateContinue, mysqlx.EncodeAuthData(xc.salt)); err != nil {
+			return err
+		}
+		if err = xc.flush(); err != nil {
+			return err
+		}
+		typ, payload, err := xc.readMessage(ctx)
+		if err != nil {
+			return err
+		}
+		if typ != mysqlx.ClientAuthenticateContinue {
+			return mysqlx.ErrUnexpectedMessage.GenByArgs(typ)
+		}
+		data, err := mysqlx.DecodeAuthenticateContinue(payload)
+		if err != nil {
+			return err
+		}
+		// The response is schema\0user\0*hex(scramble), the scramble is empty if the password is empty.
+		fields := bytes.SplitN(data, []byte{0}, 3)
+		if len(fields) != 3 {
+			return mysqlx.ErrBadMessage.GenByArgs("invalid authentication data")
+		}
+		xc.dbname, xc.user = string(fields[0]), string(fields[1])
+		if scramble := fields[2]; len(scramble) > 0 {
+			if scramble[0] != '*' {
+				return mysqlx.ErrBadMessage.GenByArgs("invalid authentication data")
+			}
+			if authData, err = hex.DecodeString(string(scramble[1:])); err != nil {
+				return mysqlx.ErrBadMessage.GenByArgs("invalid authentication data")
+			}
+		}
+	case xAuthPlain:
+		if xc.tlsConn == nil {
+			return mysqlx.ErrNotSupportedAuthMode.GenByArgs(start.MechName)
+		}
+		// The response is schema\0user\0password.
+		fields := bytes.SplitN(start.AuthData, []byte{0}, 3)
+		if len(fields) != 3 {
+			return mysqlx.ErrBadMessage.GenByArgs("invalid authentication data")
+		}
+		xc.dbname, xc.user = string(fields[0]), string(fields[1])
+		authData = fields[2]
+	default:
+		return mysqlx.ErrNotSupportedAuthMode.GenByArgs(start.MechName)
+	}
+	if err = xc.openSessionAndVerify(start.MechName, authData); err != nil {
+		if xc.ctx != nil {
+			terror.Log(xc.ctx.Close())
+			xc.ctx = nil
+		}
+		logutil.Logger(ctx).Warn("open new session failure", zap.Error(err))
+	}
+	return err
+}
+
+// openSessionAndVerify opens the session and verifies the auth data of the mechanism. The users of
+// caching_sha2_password and authentication_ldap_simple can only be authenticated by PLAIN, which sends the cleartext
+// password.
+func (xc *xConn) openSessionAndVerify(mechanism string, authData []byte) error {
+	hasPassword := "YES"
+	if len(authData) == 0 {
+		hasPassword = "NO"
+	}
+	userIdentity, port, err := xc.openSession(hasPassword)
+	if err != nil {
+		return err
+	}
+	var ok bool
+	switch plugin := xc.ctx.AuthPluginForUser(userIdentity); {
+	case mechanism == xAuthPlain && (plugin == mysql.AuthCachingSha2Password || plugin == privileges.AuthLDAPSimple):
+		ok = xc.ctx.Auth(userIdentity, authData, nil)
+	case mechanism == xAuthPlain:
+		if len(authData) > 0 {
+			authData = scramblePassword(xc.salt, authData)
+		}
+		ok = xc.ctx.Auth(userIdentity, authData, xc.salt)
+	case plugin == mysql.AuthNativePassword:
+		ok = xc.ctx.Auth(userIdentity, authData, xc.salt)
+	}
+	if !ok {
+		return errAccessDenied.FastGenByArgs(xc.user, userIdentity.Hostname, hasPassword)
+	}
+	return xc.onAuthenticated(port)
+}
+
+// scramblePassword computes the response of mysql_native_password, which is SHA1(password) XOR
+// SHA1(salt + SHA1(SHA1(password))).
+func scramblePassword(salt, password []byte) []byte {
+	stage1 := sha1.Sum(password)
+	stage2 := sha1.Sum(stage1[:])
+	h := sha1.New()
+	h.Write(salt)
+	h.Write(stage2[:])
+	scramble := h.Sum(nil)
+	for i := range scramble {
+		scramble[i] ^= stage1[i]
+	}
+	return scramble
+}
+
+func (xc *xConn) afterAuthenticated(ctx context.Context) error {
+	if err := xc.initConnect(ctx); err != nil {
+		logutil.Logger(ctx).Warn("init_connect failed", zap.Error(err))
+		initErr := errNewAbortingConnection.FastGenByArgs(xc.connectionID, "unconnected", xc.user, xc.peerHost, "init_connect command failed")
+		terror.Log(xc.writeError(initErr))
+		return initErr
+	}
+	notice := mysqlx.EncodeSessionStateChangedNotice(mysqlx.StateClientIDAssigned, mysqlx.NewUIntScalar(xc.connectionID))
+	if err := xc.writeMessage(mysqlx.ServerNotice, notice); err != nil {
+		return err
+	}
+	if err := xc.writeMessage(mysqlx.ServerAuthenticateOk, mysqlx.EncodeAuthData(nil)); err != nil {
+		return err
+	}
+	return xc.flush()
+}
+
+// Run reads the messages and dispatches them like clientConn.Run.
+func (xc *xConn) Run(ctx context.Context) {
+	const size = 4096
+	defer func() {
+		r := recover()
+		if r != nil {
+			buf := make([]byte, size)
+			stackSize := runtime.Stack(buf, false)
+			buf = buf[:stackSize]
+			logutil.Logger(ctx).Error("connection running loop panic",
+				zap.Stringer("lastSQL", getLastStmtInConn{xc.clientConn}),
+				zap.String("err", fmt.Sprintf("%v", r)),
+				zap.String("stack", string(buf)),
+			)
+			terror.Log(xc.writeError(errors.New(fmt.Sprintf("%v", r))))
+			metrics.PanicCounter.WithLabelValues(metrics.LabelSession).Inc()
+		}
+		if atomic.LoadInt32(&xc.status) != connStatusShutdown {
+			terror.Log(xc.Close())
+		}
+	}()
+	for {
+		if !atomic.CompareAndSwapInt32(&xc.status, connStatusDispatching, connStatusReading) {
+			return
+		}
+		xc.alloc.Reset()
+		typ, payload, err := xc.readMessage(ctx)
+		if err != nil {
+			if terror.ErrorNotEqual(err, io.EOF) {
+				if netErr, isNetErr := errors.Cause(err).(net.Error); isNetErr && netErr.Timeout() {
+					logutil.Logger(ctx).Info("read message timeout, close this connection", zap.Error(err))
+				} else if !strings.Contains(errors.ErrorStack(err), "use of closed network connection") {
+					logutil.Logger(ctx).Warn("read message failed, close this connection",
+						zap.Error(errors.SuspendStack(err)))
+				}
+			}
+			disconnectByClientWithError.Inc()
+			return
+		}
+		if !atomic.CompareAndSwapInt32(&xc.status, connStatusReading, connStatusDispatching) {
+			return
+		}
+		if err = xc.dispatch(ctx, typ, payload); err != nil {
+			if terror.ErrorEqual(err, io.EOF) {
+				disconnectNormal.Inc()
+				return
+			} else if terror.ErrResultUndetermined.Equal(err) {
+				logutil.Logger(ctx).Error("result undetermined, close this connection", zap.Error(err))
+				disconnectErrorUndetermined.Inc()
+				return
+			}
+			logutil.Logger(ctx).Info("command dispatched failed",
+				zap.String("connInfo", xc.String()),
+				zap.Uint8("message", typ),
+				zap.Stringer("sql", getLastStmtInConn{xc.clientConn}),
+				zap.String("err", errStrForLog(err, xc.ctx.GetSessionVars().EnableRedactLog)),
+			)
+			if len(xc.expects) > 0 {
+				xc.expects[len(xc.expects)-1].failed = true
+			}
+			if err1 := xc.writeError(err); err1 != nil {
+				terror.Log(err1)
+				return
+			}
+		}
+	}
+}
+
+func (xc *xConn) dispatch(ctx context.Context, typ byte, payload []byte) error {
+	defer func() {
+		// reset killed for each request
+		atomic.StoreUint32(&xc.ctx.GetSessionVars().Killed, 0)
+	}()
+	var cancelFunc context.CancelFunc
+	ctx, cancelFunc = context.WithCancel(ctx)
+	defer cancelFunc()
+	xc.mu.Lock()
+	xc.mu.cancelFunc = cancelFunc
+	xc.mu.Unlock()
+
+	token := xc.server.getToken()
+	defer func() {
+		xc.server.releaseToken(token)
+		xc.lastActive = time.Now()
+	}()
+
+	switch typ {
+	case mysqlx.ClientExpectOpen:
+		return xc.handleExpectOpen(payload)
+	case mysqlx.ClientExpectClose:
+		return xc.handleExpectClose()
+	}
+	if n := len(xc.expects); n > 0 && xc.expects[n-1].noError && xc.expects[n-1].failed {
+		return mysqlx.ErrExpectNoErrorFailed.GenByArgs()
+	}
+	switch typ {
+	case mysqlx.ClientCapabilitiesGet:
+		return xc.writeCapabilities()
+	case mysqlx.ClientClose, mysqlx.ClientSessionClose:
+		if err := xc.writeMessage(mysqlx.ServerOk, mysqlx.EncodeOk("bye!")); err != nil {
+			return err
+		}
+		if err := xc.flush(); err != nil {
+			return err
+		}
+		return io.EOF
+	case mysqlx.ClientSessionReset:
+		if _, err := mysqlx.DecodeSessionReset(payload); err != nil {
+			return err
+		}
+		if err := xc.resetSession(ctx); err != nil {
+			return err
+		}
+		xc.warningsNotice = true
+		return xc.writeOk()
+	case mysqlx.ClientStmtExecute:
+		msg, err := mysqlx.DecodeStmtExecute(payload)
+		if err != nil {
+			return err
+		}
+		return xc.handleStmtExecute(ctx, msg)
+	case mysqlx.ClientCrudFind:
+		msg, err := mysqlx.DecodeFind(payload)
+		if err != nil {
+			return err
+		}
+		sql, err := msg.SQL()
+		if err != nil {
+			return err
+		}
+		return xc.executeSQL(ctx, sql, false, nil)
+	case mysqlx.ClientCrudInsert:
+		msg, err := mysqlx.DecodeInsert(payload)
+		if err != nil {
+			return err
+		}
+		sql, ids, err := msg.SQL(xc.server.docIDGenerator.Next)
+		if err != nil {
+			return err
+		}
+		return xc.executeSQL(ctx, sql, false, ids)
+	case mysqlx.ClientCrudUpdate:
+		msg, err := mysqlx.DecodeUpdate(payload)
+		if err != nil {
+			return err
+		}
+		sql, err := msg.SQL()
+		if err != nil {
+			return err
+		}
+		return xc.executeSQL(ctx, sql, false, nil)
+	case mysqlx.ClientCrudDelete:
+		msg, err := mysqlx.DecodeDelete(payload)
+		if err != nil {
+			return err
+		}
+		sql, err := msg.SQL()
+		if err != nil {
+			return err
+		}
+		return xc.executeSQL(ctx, sql, false, nil)
+	}
+	return mysqlx.ErrUnexpectedMessage.GenByArgs(typ)
+}
+
+func (xc *xConn) writeOk() error {
+	if err := xc.writeMessage(mysqlx.ServerOk, mysqlx.EncodeOk("")); err != nil {
+		return err
+	}
+	return xc.flush()
+}
+
+func (xc *xConn) handleExpectOpen(payload []byte) error {
+	msg, err := mysqlx.DecodeExpectOpen(payload)
+	if err != nil {
+		return err
+	}
+	block := &xExpectBlock{}
+	if n := len(xc.expects); n > 0 && msg.Op == mysqlx.ExpectCtxCopyPrev {
+		*block = *xc.expects[n-1]
+	}
+	for _, cond := range msg.Conds {
+		switch cond.Key {
+		case mysqlx.ExpectNoError:
+			block.noError = cond.Op == mysqlx.ExpectOpSet
+		case mysqlx.ExpectFieldExist, mysqlx.ExpectDocIDGenerated:
+			// All the fields are supported and the document ids are generated by the server.
+		default:
+			return mysqlx.ErrExpectBadCondition.GenByArgs(cond.Key)
+		}
+	}
+	xc.expects = append(xc.expects, block)
+	if block.noError && block.failed {
+		return mysqlx.ErrExpectNoErrorFailed.GenByArgs()
+	}
+	return xc.writeOk()
+}
+
+func (xc *xConn) handleExpectClose() error {
+	n := len(xc.expects)
+	if n == 0 {
+		return mysqlx.ErrExpectNotOpen.GenByArgs()
+	}
+	block := xc.expects[n-1]
+	xc.expects = xc.expects[:n-1]
+	if block.noError && block.failed {
+		return mysqlx.ErrExpectNoErrorFailed.GenByArgs()
+	}
+	return xc.writeOk()
+}
+
+func (xc *xConn) handleStmtExecute(ctx context.Context, msg *mysqlx.StmtExecute) error {
+	switch msg.Namespace {
+	case mysqlx.NamespaceSQL:
+		if len(msg.Args) == 0 {
+			return xc.executeSQL(ctx, string(msg.Stmt), msg.CompactMetadata, nil)
+		}
+		return xc.executePrepared(ctx, string(msg.Stmt), msg.Args, msg.CompactMetadata)
+	case mysqlx.NamespaceMysqlx, mysqlx.NamespaceXPlugin:
+		return xc.handleAdminCommand(ctx, string(msg.Stmt), mysqlx.NewCommandArgs(string(msg.Stmt), msg.Args))
+	}
+	return mysqlx.ErrInvalidNamespace.GenByArgs(msg.Namespace)
+}
+
+// executeSQL executes a statement and writes the result, ids are the document ids generated for the statement.
+func (xc *xConn) executeSQL(ctx context.Context, sql string, compact bool, ids []string) error {
+	xc.lastPacket = append(xc.lastPacket[:0], mysql.ComQuery)
+	xc.lastPacket = append(xc.lastPacket, sql...)
+	stmts, err := xc.ctx.Parse(ctx, sql)
+	if err != nil {
+		return err
+	}
+	if len(stmts) > 1 {
+		return mysqlx.ErrBadMessage.GenByArgs("multiple statements are not allowed")
+	}
+	if len(stmts) == 0 {
+		return xc.writeStmtExecuteOk(ids)
+	}
+	ctx = context.WithValue(ctx, execdetails.StmtExecDetailKey, &execdetails.StmtExecDetails{})
+	ctx = context.WithValue(ctx, util.ExecDetailsKey, &util.ExecDetails{})
+	rs, err := xc.ctx.ExecuteStmt(ctx, stmts[0])
+	err = xc.writeResult(ctx, rs, err, compact, ids)
+	xc.auditStmt(stmts[0], err)
+	return err
+}
+
+// executePrepared executes a statement with the arguments bound to its placeholders.
+func (xc *xConn) executePrepared(ctx context.Context, sql string, args []*mysqlx.Any, compact bool) error {
+	xc.lastPacket = append(xc.lastPacket[:0], mysql.ComQuery)
+	xc.lastPacket = append(xc.lastPacket, sql...)
+	stmt, _, _, err := xc.ctx.Prepare(sql)
+	if err != nil {
+		return err
+	}
+	defer terror.Call(stmt.Close)
+	if stmt.NumParams() != len(args) {
+		return mysqlx.ErrCmdNumArguments.GenByArgs(stmt.NumParams(), len(args))
+	}
+	datums := make([]types.Datum, len(args))
+	for i, arg := range args {
+		if datums[i], err = xScalarToDatum(arg.Scalar); err != nil {
+			return err
+		}
+	}
+	ctx = context.WithValue(ctx, execdetails.StmtExecDetailKey, &execdetails.StmtExecDetails{})
+	ctx = context.WithValue(ctx, util.ExecDetailsKey, &util.ExecDetails{})
+	rs, err := stmt.Execute(ctx, datums)
+	err = xc.writeResult(ctx, rs, err, compact, nil)
+	xc.auditPreparedStmt(uint32(stmt.ID()), err)
+	return err
+}
+
+func xScalarToDatum(s *mysqlx.Scalar) (types.Datum, error) {
+	var d types.Datum
+	switch s.Type {
+	case mysqlx.ScalarSInt:
+		d.SetInt64(s.SInt)
+	case mysqlx.ScalarUInt:
+		d.SetUint64(s.UInt)
+	case mysqlx.ScalarNull:
+	case mysqlx.ScalarOctets, mysqlx.ScalarString:
+		d.SetString(string(s.Bytes), mysql.DefaultCollationName)
+	case mysqlx.ScalarDouble:
+		d.SetFloat64(s.Double)
+	case mysqlx.ScalarFloat:
+		d.SetFloat32(s.Float)
+	case mysqlx.ScalarBool:
+		if s.Bool {
+			d.SetInt64(1)
+		} else {
+			d.SetInt64(0)
+		}
+	default:
+		return d, mysqlx.ErrExprBadTypeValue.GenByArgs(s.Type)
+	}
+	return d, nil
+}
+
+func (xc *xConn) writeResult(ctx context.Context, rs ResultSet, err error, compact bool, ids []string) error {
+	if rs != nil {
+		defer terror.Call(rs.Close)
+	}
+	if err != nil {
+		return err
+	}
+	if rs != nil {
+		if atomic.LoadInt32(&xc.status) == connStatusShutdown {
+			return executor.ErrQueryInterrupted
+		}
+		if err = xc.writeResultset(ctx, rs, compact); err != nil {
+			return err
+		}
+	} else if xc.ctx.Value(executor.LoadDataVarKey) != nil {
+		xc.ctx.SetValue(executor.LoadDataVarKey, nil)
+		return mysqlx.ErrUnsupportedExpression.GenByArgs("LOAD DATA LOCAL")
+	}
+	if err = xc.writeNotices(rs == nil, ids); err != nil {
+		return err
+	}
+	return xc.writeStmtExecuteOk(nil)
+}
+
+// writeNotices writes the warnings and the state changed by the statement.
+func (xc *xConn) writeNotices(withRowsAffected bool, ids []string) error {
+	if xc.warningsNotice {
+		for _, w := range xc.ctx.GetWarnings() {
+			level := mysqlx.WarningWarning
+			switch w.Level {
+			case stmtctx.WarnLevelNote:
+				level = mysqlx.WarningNote
+			case stmtctx.WarnLevelError:
+				level = mysqlx.WarningError
+			}
+			m := toSQLError(w.Err)
+			if err := xc.writeMessage(mysqlx.ServerNotice, mysqlx.EncodeWarningNotice(level, uint32(m.Code), m.Message)); err != nil {
+				return err
+			}
+		}
+	}
+	var notices [][]byte
+	if withRowsAffected {
+		notices = append(notices, mysqlx.EncodeSessionStateChangedNotice(mysqlx.StateRowsAffected, mysqlx.NewUIntScalar(xc.ctx.AffectedRows())))
+	}
+	if id := xc.ctx.LastInsertID(); id > 0 {
+		notices = append(notices, mysqlx.EncodeSessionStateChangedNotice(mysqlx.StateGeneratedInsertID, mysqlx.NewUIntScalar(id)))
+	}
+	if msg := xc.ctx.LastMessage(); msg != "" {
+		notices = append(notices, mysqlx.EncodeSessionStateChangedNotice(mysqlx.StateProducedMessage, mysqlx.NewStringScalar(msg)))
+	}
+	if len(ids) > 0 {
+		values := make([]*mysqlx.Scalar, 0, len(ids))
+		for _, id := range ids {
+			values = append(values, mysqlx.NewOctetsScalar(id))
+		}
+		notices = append(notices, mysqlx.EncodeSessionStateChangedNotice(mysqlx.StateGeneratedDocumentIDs, values...))
+	}
+	for _, notice := range notices {
+		if err := xc.writeMessage(mysqlx.ServerNotice, notice); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (xc *xConn) writeStmtExecuteOk(ids []string) error {
+	if len(ids) > 0 {
+		if err := xc.writeNotices(false, ids); err != nil {
+			return err
+		}
+	}
+	if err := xc.writeMessage(mysqlx.ServerStmtExecuteOk, nil); err != nil {
+		return err
+	}
+	return xc.flush()
+}
+
+// writeResultset writes the column metadata, the rows and FetchDone.
+func (xc *xConn) writeResultset(ctx context.Context, rs ResultSet, compact bool) error {
+	req := rs.NewChunk()
+	var columns []*ColumnInfo
+	for {
+		if err := rs.Next(ctx, req); err != nil {
+			return err
+		}
+		if columns == nil {
+			// We need to call Next before we get columns.
+			columns = rs.Columns()
+			if err := xc.writeColumnMetaData(columns, compact); err != nil {
+				return err
+			}
+		}
+		if req.NumRows() == 0 {
+			break
+		}
+		for i := 0; i < req.NumRows(); i++ {
+			fields, err := xRowFields(req.GetRow(i), columns)
+			if err != nil {
+				return err
+			}
+			if err = xc.writeMessage(mysqlx.ServerRow, mysqlx.EncodeRow(fields)); err != nil {
+				return err
+			}
+		}
+	}
+	return xc.writeMessage(mysqlx.ServerFetchDone, nil)
+}
+
+func (xc *xConn) writeColumnMetaData(columns []*ColumnInfo, compact bool) error {
+	for _, col := range columns {
+		if err := xc.writeMessage(mysqlx.ServerColumnMetaData, xColumnMetaData(col).Encode(compact)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func xColumnMetaData(col *ColumnInfo) *mysqlx.ColumnMetaData {
+	md := &mysqlx.ColumnMetaData{
+		Name:          col.Name,
+		OriginalName:  col.OrgName,
+		Table:         col.Table,
+		OriginalTable: col.OrgTable,
+		Schema:        col.Schema,
+		Catalog:       "def",
+		Length:        col.ColumnLength,
+	}
+	if col.Decimal != mysql.NotFixedDec {
+		md.FractionalDigits = uint32(col.Decimal)
+	}
+	flag := uint(col.Flag)
+	switch col.Type {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+		md.Type = mysqlx.FieldSInt
+		if mysql.HasUnsignedFlag(flag) {
+			md.Type = mysqlx.FieldUInt
+		}
+	case mysql.TypeYear:
+		md.Type = mysqlx.FieldUInt
+	case mysql.TypeFloat:
+		md.Type = mysqlx.FieldFloat
+	case mysql.TypeDouble:
+		md.Type = mysqlx.FieldDouble
+	case mysql.TypeNewDecimal:
+		md.Type = mysqlx.FieldDecimal
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		md.Type = mysqlx.FieldDatetime
+		if col.Type == mysql.TypeTimestamp {
+			md.Flags |= mysqlx.FlagTimestamp
+		}
+	case mysql.TypeDuration:
+		md.Type = mysqlx.FieldTime
+	case mysql.TypeEnum:
+		md.Type = mysqlx.FieldEnum
+		md.Collation = uint64(col.Charset)
+	case mysql.TypeSet:
+		md.Type = mysqlx.FieldSet
+		md.Collation = uint64(col.Charset)
+	case mysql.TypeBit:
+		md.Type = mysqlx.FieldBit
+	case mysql.TypeJSON:
+		md.Type = mysqlx.FieldBytes
+		md.ContentType = mysqlx.ContentTypeJSON
+		md.Collation = mysql.DefaultCollationID
+	case mysql.TypeGeometry:
+		md.Type = mysqlx.FieldBytes
+		md.ContentType = mysqlx.ContentTypeGeometry
+		md.Collation = uint64(col.Charset)
+	default:
+		md.Type = mysqlx.FieldBytes
+		md.Collation = uint64(col.Charset)
+	}
+	if (md.Type == mysqlx.FieldFloat || md.Type == mysqlx.FieldDouble || md.Type == mysqlx.FieldDecimal) && mysql.HasUnsignedFlag(flag) {
+		md.Flags |= mysqlx.FlagUnsigned
+	}
+	if mysql.HasNotNullFlag(flag) {
+		md.Flags |= mysqlx.FlagNotNull
+	}
+	if mysql.HasPriKeyFlag(flag) {
+		md.Flags |= mysqlx.FlagPrimaryKey
+	}
+	if mysql.HasUniKeyFlag(flag) {
+		md.Flags |= mysqlx.FlagUniqueKey
+	}
+	if mysql.HasMultipleKeyFlag(flag) {
+		md.Flags |= mysqlx.FlagMultipleKey
+	}
+	if mysql.HasAutoIncrementFlag(flag) {
+		md.Flags |= mysqlx.FlagAutoIncrement
+	}
+	return md
+}
+
+// xRowFields encodes the fields of a row by the types of the columns.
+func xRowFields(row chunk.Row, columns []*ColumnInfo) ([][]byte, error) {
+	fields := make([][]byte, len(columns))
+	for i, col := range columns {
+		if row.IsNull(i) {
+			continue
+		}
+		switch col.Type {
+		case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+			if mysql.HasUnsignedFlag(uint(col.Flag)) {
+				fields[i] = mysqlx.EncodeUIntField(row.GetUint64(i))
+			} else {
+				fields[i] = mysqlx.EncodeSIntField(row.GetInt64(i))
+			}
+		case mysql.TypeYear:
+			fields[i] = mysqlx.EncodeUIntField(uint64(row.GetInt64(i)))
+		case mysql.TypeFloat:
+			fields[i] = mysqlx.EncodeFloatField(row.GetFloat32(i))
+		case mysql.TypeDouble:
+			fields[i] = mysqlx.EncodeDoubleField(row.GetFloat64(i))
+		case mysql.TypeNewDecimal:
+			fields[i] = mysqlx.EncodeDecimalField(row.GetMyDecimal(i))
+		case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+			fields[i] = mysqlx.EncodeDatetimeField(row.GetTime(i))
+		case mysql.TypeDuration:
+			fields[i] = mysqlx.EncodeTimeField(row.GetDuration(i, int(col.Decimal)))
+		case mysql.TypeEnum:
+			fields[i] = mysqlx.EncodeBytesField([]byte(row.GetEnum(i).String()))
+		case mysql.TypeSet:
+			fields[i] = mysqlx.EncodeSetField(row.GetSet(i))
+		case mysql.TypeBit:
+			var v uint64
+			for _, b := range row.GetBytes(i) {
+				v = v<<8 | uint64(b)
+			}
+			fields[i] = mysqlx.EncodeUIntField(v)
+		case mysql.TypeJSON:
+			fields[i] = mysqlx.EncodeBytesField([]byte(row.GetJSON(i).String()))
+		case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar, mysql.TypeTinyBlob, mysql.TypeMediumBlob,
+			mysql.TypeLongBlob, mysql.TypeBlob, mysql.TypeGeometry:
+			fields[i] = mysqlx.EncodeBytesField(row.GetBytes(i))
+		default:
+			return nil, errInvalidType.GenWithStack("invalid type %v", col.Type)
+		}
+	}
+	return fields, nil
+}
+
+// handleAdminCommand handles the admin commands of the mysqlx namespace.
+func (xc *xConn) handleAdminCommand(ctx context.Context, cmd string, args *mysqlx.CommandArgs) error {
+	switch cmd {
+	case "ping":
+		if err := args.End(); err != nil {
+			return err
+		}
+		return xc.writeStmtExecuteOk(nil)
+	case "kill_client":
+		id := args.UInt("id", false)
+		if err := args.End(); err != nil {
+			return err
+		}
+		return xc.executeSQL(ctx, sqlexec.MustEscapeSQL("KILL CONNECTION %?", id), false, nil)
+	case "create_collection", "ensure_collection":
+		schema, name := args.String("schema", true), args.String("name", false)
+		args.Value("options", true)
+		if err := args.End(); err != nil {
+			return err
+		}
+		return xc.createCollection(ctx, schema, name, cmd == "ensure_collection")
+	case "drop_collection":
+		schema, name := args.String("schema", true), args.String("name", false)
+		if err := args.End(); err != nil {
+			return err
+		}
+		if schema == "" {
+			schema = xc.ctx.CurrentDB()
+		}
+		return xc.executeSQL(ctx, mysqlx.DropCollectionSQL(schema, name), false, nil)
+	case "list_objects":
+		schema, pattern := args.String("schema", true), args.String("pattern", true)
+		if err := args.End(); err != nil {
+			return err
+		}
+		return xc.listObjects(schema, pattern)
+	case "enable_notices", "disable_notices":
+		notices := args.Strings("notice", false)
+		if err := args.End(); err != nil {
+			return err
+		}
+		for _, notice := range notices {
+			switch notice {
+			case "warnings":
+				xc.warningsNotice = cmd == "enable_notices"
+			case "account_expired", "generated_insert_id", "rows_affected", "produced_message":
+				// The notices which are always sent.
+			default:
+				return mysqlx.ErrCmdArgumentType.GenByArgs(notice, cmd)
+			}
+		}
+		return xc.writeStmtExecuteOk(nil)
+	case "list_notices":
+		if err := args.End(); err != nil {
+			return err
+		}
+		return xc.listNotices()
+	}
+	return mysqlx.ErrInvalidAdminCommand.GenByArgs(cmd)
+}
+
+func (xc *xConn) createCollection(ctx context.Context, schema, name string, ensure bool) error {
+	if schema == "" {
+		schema = xc.ctx.CurrentDB()
+	}
+	if schema == "" {
+		return core.ErrNoDB
+	}
+	if name == "" {
+		return mysqlx.ErrInvalidCollection.GenByArgs("name")
+	}
+	if err := xc.executeSQLWithoutResult(ctx, mysqlx.CreateCollectionSQL(schema, name, ensure)); err != nil {
+		return err
+	}
+	if ensure {
+		tbl, err := xc.infoSchema().TableByName(model.NewCIStr(schema), model.NewCIStr(name))
+		if err != nil {
+			return err
+		}
+		if !mysqlx.IsCollection(tbl.Meta()) {
+			return mysqlx.ErrInvalidCollection.GenByArgs(name)
+		}
+	}
+	return xc.writeStmtExecuteOk(nil)
+}
+
+// executeSQLWithoutResult executes a statement without writing the result.
+func (xc *xConn) executeSQLWithoutResult(ctx context.Context, sql string) error {
+	stmts, err := xc.ctx.Parse(ctx, sql)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range stmts {
+		rs, err := xc.ctx.ExecuteStmt(ctx, stmt)
+		if rs != nil {
+			terror.Call(rs.Close)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (xc *xConn) infoSchema() infoschema.InfoSchema {
+	return domain.GetDomain(xc.ctx).InfoSchema()
+}
+
+// listObjects lists the tables, the views and the collections of the schema which the user has privileges on.
+func (xc *xConn) listObjects(schema, pattern string) error {
+	if schema == "" {
+		schema = xc.ctx.CurrentDB()
+	}
+	if schema == "" {
+		return core.ErrNoDB
+	}
+	is := xc.infoSchema()
+	dbName := model.NewCIStr(schema)
+	if _, ok := is.SchemaByName(dbName); !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(schema)
+	}
+	var patChars []rune
+	var patTypes []byte
+	if pattern != "" {
+		patChars, patTypes = stringutil.CompilePattern(pattern, '\\')
+	}
+	checker := privilege.GetPrivilegeManager(xc.ctx.Session)
+	activeRoles := xc.ctx.GetSessionVars().ActiveRoles
+	var rows [][][]byte
+	for _, tbl := range is.SchemaTables(dbName) {
+		tblInfo := tbl.Meta()
+		if pattern != "" && !stringutil.DoMatch(tblInfo.Name.O, patChars, patTypes) {
+			continue
+		}
+		if checker != nil && !checker.RequestVerification(activeRoles, dbName.L, tblInfo.Name.L, "", mysql.AllPrivMask) {
+			continue
+		}
+		typ := "TABLE"
+		if tblInfo.IsView() {
+			typ = "VIEW"
+		} else if mysqlx.IsCollection(tblInfo) {
+			typ = "COLLECTION"
+		}
+		rows = append(rows, [][]byte{mysqlx.EncodeBytesField([]byte(tblInfo.Name.O)), mysqlx.EncodeBytesField([]byte(typ))})
+	}
+	return xc.writeRows([]string{"name", "type"}, rows)
+}
+
+func (xc *xConn) listNotices() error {
+	enabled := int64(0)
+	if xc.warningsNotice {
+		enabled = 1
+	}
+	rows := [][][]byte{
+		{mysqlx.EncodeBytesField([]byte("warnings")), mysqlx.EncodeSIntField(enabled)},
+	}
+	for _, notice := range []string{"account_expired", "generated_insert_id", "rows_affected", "produced_message"} {
+		rows = append(rows, [][]byte{mysqlx.EncodeBytesField([]byte(notice)), mysqlx.EncodeSIntField(1)})
+	}
+	return xc.writeRows([]string{"notice", "enabled"}, rows)
+}
+
+// writeRows writes a result set built by the server, the columns are bytes except "enabled".
+func (xc *xConn) writeRows(names []string, rows [][][]byte) error {
+	for _, name := range names {
+		md := &mysqlx.ColumnMetaData{Type: mysqlx.FieldBytes, Name: name, OriginalName: name, Catalog: "def", Collation: mysql.DefaultCollationID}
+		if name == "enabled" {
+			md = &mysqlx.ColumnMetaData{Type: mysqlx.FieldSInt, Name: name, OriginalName: name, Catalog: "def"}
+		}
+		if err := xc.writeMessage(mysqlx.ServerColumnMetaData, md.Encode(false)); err != nil {
+			return err
+		}
+	}
+	for _, row := range rows {
+		if err := xc.writeMessage(mysqlx.ServerRow, mysqlx.EncodeRow(row)); err != nil {
+			return err
+		}
+	}
+	if err := xc.writeMessage(mysqlx.ServerFetchDone, nil); err != nil {
+		return err
+	}
+	return xc.writeStmtExecuteOk(nil)
+}
+
+// runXProtocol accepts the connections of the MySQL X Protocol.
+func (s *Server) runXProtocol(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if opErr, ok := err.(*net.OpError); ok && opErr.Err.Error() == "use of closed network connection" {
+				return
+			}
+			if proxyprotocol.IsProxyProtocolError(err) {
+				logutil.BgLogger().Error("PROXY protocol failed", zap.Error(err))
+				continue
+			}
+			logutil.BgLogger().Error("accept X protocol connection failed", zap.Error(err))
+			return
+		}
+		if s.dom != nil && s.dom.IsLostConnectionToPD() {
+			logutil.BgLogger().Warn("reject connection due to lost connection to PD")
+			terror.Log(conn.Close())
+			continue
+		}
+		go s.onXConn(newXConn(s.newConn(conn)))
+	}
+}
+
+// onXConn runs in its own goroutine, handles the messages from this connection.
+func (s *Server) onXConn(xc *xConn) {
+	ctx := logutil.WithConnID(context.Background(), xc.connectionID)
+	if err := xc.handshake(ctx); err != nil {
+		if terror.ErrorNotEqual(err, io.EOF) {
+			xc.auditConnEvent(audit.TypeReject, 0, err)
+			metrics.HandShakeErrorCounter.Inc()
+		}
+		terror.Log(errors.Trace(xc.Close()))
+		return
+	}
+	logutil.Logger(ctx).Debug("new X protocol connection", zap.String("remoteAddr", xc.bufReadConn.RemoteAddr().String()))
+	s.rwlock.Lock()
+	s.clients[xc.connectionID] = xc.clientConn
+	connections := len(s.clients)
+	s.rwlock.Unlock()
+	metrics.ConnGauge.Set(float64(connections))
+	xc.auditConnEvent(audit.TypeConnect, 0, nil)
+	connectedTime := time.Now()
+	xc.Run(ctx)
+	xc.auditConnEvent(audit.TypeDisconnect, time.Since(connectedTime), nil)
+}
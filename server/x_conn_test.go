@@ -0,0 +1,155 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/server/mysqlx"
+)
+
+// xMessage builds the protobuf messages sent by the test clients.
+type xMessage []byte
+
+func (m xMessage) varint(field int, v uint64) xMessage {
+	m = appendTestUvarint(m, uint64(field<<3))
+	return appendTestUvarint(m, v)
+}
+
+func (m xMessage) bytes(field int, v []byte) xMessage {
+	m = appendTestUvarint(m, uint64(field<<3|2))
+	m = appendTestUvarint(m, uint64(len(v)))
+	return append(m, v...)
+}
+
+func appendTestUvarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+func xStringAny(s string) xMessage {
+	scalar := xMessage(nil).varint(1, uint64(mysqlx.ScalarString)).bytes(9, xMessage(nil).bytes(1, []byte(s)))
+	return xMessage(nil).varint(1, uint64(mysqlx.AnyScalar)).bytes(2, scalar)
+}
+
+type xTestClient struct {
+	c    *C
+	conn net.Conn
+}
+
+func (cli *xTestClient) send(typ byte, payload xMessage) {
+	cli.c.Assert(mysqlx.WriteMessage(cli.conn, typ, payload), IsNil)
+}
+
+func (cli *xTestClient) recv() (byte, []byte) {
+	typ, payload, err := mysqlx.ReadMessage(cli.conn)
+	cli.c.Assert(err, IsNil)
+	return typ, payload
+}
+
+// recvUntil receives the messages until the one of typ or an Error, the types of the received messages are returned.
+func (cli *xTestClient) recvUntil(typ byte) (types []byte, rows [][]byte) {
+	for {
+		t, payload := cli.recv()
+		types = append(types, t)
+		if t == mysqlx.ServerRow {
+			rows = append(rows, payload)
+		}
+		if t == typ || t == mysqlx.ServerError {
+			return
+		}
+	}
+}
+
+func (ts *tidbTestSuite) TestXProtocol(c *C) {
+	cfg := newTestConfig()
+	cfg.Port = 0
+	cfg.Status.ReportStatus = false
+	cfg.XProtocol.Enable = true
+	cfg.XProtocol.Port = 0
+	server, err := NewServer(cfg, ts.tidbdrv)
+	c.Assert(err, IsNil)
+	go func() {
+		err := server.Run()
+		c.Assert(err, IsNil)
+	}()
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", fmt.Sprintf("127.0.0.1:%d", getPortFromTCPAddr(server.xListener.Addr())))
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	cli := &xTestClient{c: c, conn: conn}
+
+	// PLAIN is only allowed by the TLS connections.
+	cli.send(mysqlx.ClientAuthenticateStart, xMessage(nil).bytes(1, []byte("PLAIN")).bytes(2, []byte("test\x00root\x00")))
+	typ, _ := cli.recv()
+	c.Assert(typ, Equals, mysqlx.ServerError)
+
+	cli.send(mysqlx.ClientAuthenticateStart, xMessage(nil).bytes(1, []byte("MYSQL41")))
+	typ, salt := cli.recv()
+	c.Assert(typ, Equals, mysqlx.ServerAuthenticateContinue)
+	c.Assert(len(salt) > 2, IsTrue)
+	cli.send(mysqlx.ClientAuthenticateContinue, xMessage(nil).bytes(1, []byte("test\x00root\x00")))
+	types, _ := cli.recvUntil(mysqlx.ServerAuthenticateOk)
+	c.Assert(types, DeepEquals, []byte{mysqlx.ServerNotice, mysqlx.ServerAuthenticateOk})
+
+	// The SQL statements.
+	cli.send(mysqlx.ClientStmtExecute, xMessage(nil).bytes(1, []byte("select 1 + 1")))
+	types, rows := cli.recvUntil(mysqlx.ServerStmtExecuteOk)
+	c.Assert(types, DeepEquals, []byte{mysqlx.ServerColumnMetaData, mysqlx.ServerRow, mysqlx.ServerFetchDone, mysqlx.ServerStmtExecuteOk})
+	c.Assert(rows, DeepEquals, [][]byte{{0x0a, 1, 4}})
+	cli.send(mysqlx.ClientStmtExecute, xMessage(nil).bytes(1, []byte("select 1; select 2")))
+	types, _ = cli.recvUntil(mysqlx.ServerStmtExecuteOk)
+	c.Assert(types[len(types)-1], Equals, mysqlx.ServerError)
+
+	// The collections.
+	cli.send(mysqlx.ClientStmtExecute, xMessage(nil).bytes(1, []byte("create_collection")).
+		bytes(2, xStringAny("test")).bytes(2, xStringAny("xcoll")).bytes(3, []byte("mysqlx")))
+	types, _ = cli.recvUntil(mysqlx.ServerStmtExecuteOk)
+	c.Assert(types[len(types)-1], Equals, mysqlx.ServerStmtExecuteOk)
+
+	collection := xMessage(nil).bytes(1, []byte("xcoll")).bytes(2, []byte("test"))
+	literal := xMessage(nil).varint(1, uint64(mysqlx.ExprLiteral)).bytes(4, xMessage(nil).varint(1, uint64(mysqlx.ScalarSInt)).varint(2, 4))
+	doc := xMessage(nil).varint(1, uint64(mysqlx.ExprObject)).bytes(8, xMessage(nil).bytes(1, xMessage(nil).bytes(1, []byte("a")).bytes(2, literal)))
+	cli.send(mysqlx.ClientCrudInsert, xMessage(nil).bytes(1, collection).varint(2, uint64(mysqlx.DataModelDocument)).bytes(4, xMessage(nil).bytes(1, doc)))
+	types, _ = cli.recvUntil(mysqlx.ServerStmtExecuteOk)
+	c.Assert(types[len(types)-1], Equals, mysqlx.ServerStmtExecuteOk)
+
+	cli.send(mysqlx.ClientCrudFind, xMessage(nil).bytes(2, collection).varint(3, uint64(mysqlx.DataModelDocument)))
+	types, rows = cli.recvUntil(mysqlx.ServerStmtExecuteOk)
+	c.Assert(types[len(types)-1], Equals, mysqlx.ServerStmtExecuteOk)
+	c.Assert(rows, HasLen, 1)
+	c.Assert(strings.Contains(string(rows[0]), `"a": 2`), IsTrue, Commentf("%q", rows[0]))
+	c.Assert(strings.Contains(string(rows[0]), `"_id": "`), IsTrue, Commentf("%q", rows[0]))
+
+	cli.send(mysqlx.ClientCrudDelete, xMessage(nil).bytes(1, collection).varint(2, uint64(mysqlx.DataModelDocument)))
+	types, _ = cli.recvUntil(mysqlx.ServerStmtExecuteOk)
+	c.Assert(types[len(types)-1], Equals, mysqlx.ServerStmtExecuteOk)
+
+	cli.send(mysqlx.ClientStmtExecute, xMessage(nil).bytes(1, []byte("ping")).bytes(3, []byte("mysqlx")))
+	types, _ = cli.recvUntil(mysqlx.ServerStmtExecuteOk)
+	c.Assert(types, DeepEquals, []byte{mysqlx.ServerStmtExecuteOk})
+
+	cli.send(mysqlx.ClientClose, nil)
+	typ, _ = cli.recv()
+	c.Assert(typ, Equals, mysqlx.ServerOk)
+	_, _, err = mysqlx.ReadMessage(conn)
+	c.Assert(err, NotNil)
+}
@@ -88,6 +88,18 @@ const (
 		Create_Tablespace_Priv  ENUM('N','Y') NOT NULL DEFAULT 'N',
 		Repl_slave_priv	    	ENUM('N','Y') NOT NULL DEFAULT 'N',
 		Repl_client_priv		ENUM('N','Y') NOT NULL DEFAULT 'N',
+		plugin					CHAR(64) NOT NULL DEFAULT 'mysql_native_password',
+		max_connections			INT UNSIGNED NOT NULL DEFAULT 0,
+		max_user_connections	INT UNSIGNED NOT NULL DEFAULT 0,
+		failed_login_attempts	INT UNSIGNED NOT NULL DEFAULT 0,
+		password_lock_time		INT NOT NULL DEFAULT 0,
+		failed_login_count		INT UNSIGNED NOT NULL DEFAULT 0,
+		auto_locked_time		TIMESTAMP NULL DEFAULT NULL,
+		password_expired		ENUM('N','Y') NOT NULL DEFAULT 'N',
+		password_last_changed	TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP,
+		password_lifetime		SMALLINT UNSIGNED NULL DEFAULT NULL,
+		Password_reuse_history	SMALLINT UNSIGNED NULL DEFAULT NULL,
+		Password_reuse_time		SMALLINT UNSIGNED NULL DEFAULT NULL,
 		PRIMARY KEY (Host, User));`
 	// CreateGlobalPrivTable is the SQL statement creates Global scope privilege table in system db.
 	CreateGlobalPrivTable = "CREATE TABLE IF NOT EXISTS mysql.global_priv (" +
@@ -294,6 +306,215 @@ const (
 		INDEX tbl(table_id, is_index, hist_id)
 	);`
 
+	// CreateStatsGlobalMergeTable stores the versions of the partition-level stats merged into the global-stats.
+	CreateStatsGlobalMergeTable = `CREATE TABLE IF NOT EXISTS mysql.stats_global_merge (
+		table_id 		BIGINT(64) NOT NULL,
+		partition_id 	BIGINT(64) NOT NULL,
+		version 		BIGINT(64) UNSIGNED NOT NULL DEFAULT 0,
+		PRIMARY KEY(table_id, partition_id)
+	);`
+
+	// CreateStatsTableLockedTable stores the tables and partitions whose stats are locked.
+	CreateStatsTableLockedTable = `CREATE TABLE IF NOT EXISTS mysql.stats_table_locked (
+		table_id 	BIGINT(64) NOT NULL,
+		version 	BIGINT(64) UNSIGNED NOT NULL DEFAULT 0,
+		PRIMARY KEY(table_id)
+	);`
+
+	// CreateAnalyzeOptionsTable stores the analyze options specified explicitly for the tables, which are reused by the
+	// later analyze of the tables.
+	CreateAnalyzeOptionsTable = `CREATE TABLE IF NOT EXISTS mysql.analyze_options (
+		table_id 	BIGINT(64) NOT NULL,
+		buckets 	BIGINT(64),
+		topn 		BIGINT(64),
+		PRIMARY KEY(table_id)
+	);`
+
+	// CreateColumnStatsUsageTable stores the columns used by the predicates, joins or group-by of the queries.
+	CreateColumnStatsUsageTable = `CREATE TABLE IF NOT EXISTS mysql.column_stats_usage (
+		table_id 		BIGINT(64) NOT NULL,
+		column_id 		BIGINT(64) NOT NULL,
+		last_used_at 	TIMESTAMP,
+		PRIMARY KEY(table_id, column_id)
+	);`
+
+	// CreateAnalyzeJobsTable stores the analyze jobs and their progress.
+	CreateAnalyzeJobsTable = `CREATE TABLE IF NOT EXISTS mysql.analyze_jobs (
+		id 				BIGINT(64) UNSIGNED NOT NULL AUTO_INCREMENT,
+		update_time 	TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+		table_schema 	CHAR(64) NOT NULL DEFAULT '',
+		table_name 		CHAR(64) NOT NULL DEFAULT '',
+		partition_name 	CHAR(64) NOT NULL DEFAULT '',
+		job_info 		TEXT NOT NULL,
+		processed_rows 	BIGINT(64) UNSIGNED NOT NULL DEFAULT 0,
+		total_rows 		BIGINT(64) UNSIGNED NOT NULL DEFAULT 0,
+		start_time 		TIMESTAMP NULL DEFAULT NULL,
+		end_time 		TIMESTAMP NULL DEFAULT NULL,
+		state 			ENUM('pending', 'running', 'finished', 'failed', 'interrupted', 'resumed') NOT NULL,
+		instance 		VARCHAR(512) NOT NULL,
+		PRIMARY KEY (id),
+		KEY (update_time),
+		KEY (state, instance)
+	);`
+
+	// CreateResourceGroupsTable stores the resource groups, their RU_PER_SEC quotas and priorities.
+	CreateResourceGroupsTable = `CREATE TABLE IF NOT EXISTS mysql.resource_groups (
+		name 		VARCHAR(64) NOT NULL PRIMARY KEY,
+		ru_per_sec 	BIGINT(64) NOT NULL DEFAULT 0,
+		priority 	VARCHAR(8) NOT NULL DEFAULT 'medium'
+	);`
+
+	// CreateResourceGroupUsersTable stores the resource groups bound to the user accounts.
+	CreateResourceGroupUsersTable = `CREATE TABLE IF NOT EXISTS mysql.resource_group_users (
+		user 			CHAR(32) NOT NULL DEFAULT '',
+		host 			CHAR(255) NOT NULL DEFAULT '',
+		resource_group 	VARCHAR(64) NOT NULL,
+		PRIMARY KEY (user, host)
+	);`
+
+	// CreateStmtSummaryHistoryTable stores the history of the statement summaries of all the tidb-servers, so it
+	// survives the restarts. The columns after instance are the same as information_schema.statements_summary_history.
+	CreateStmtSummaryHistoryTable = `CREATE TABLE IF NOT EXISTS mysql.statements_summary_history (
+		instance VARCHAR(64) NOT NULL,
+		summary_begin_time TIMESTAMP(6) NOT NULL,
+		summary_end_time TIMESTAMP(6) NOT NULL,
+		stmt_type VARCHAR(64) NOT NULL,
+		schema_name VARCHAR(64) NOT NULL,
+		digest VARCHAR(64) NOT NULL,
+		digest_text TEXT NOT NULL,
+		table_names TEXT,
+		index_names TEXT,
+		sample_user VARCHAR(64),
+		exec_count BIGINT UNSIGNED NOT NULL,
+		sum_errors INT UNSIGNED NOT NULL,
+		sum_warnings INT UNSIGNED NOT NULL,
+		sum_latency BIGINT UNSIGNED NOT NULL,
+		max_latency BIGINT UNSIGNED NOT NULL,
+		min_latency BIGINT UNSIGNED NOT NULL,
+		avg_latency BIGINT UNSIGNED NOT NULL,
+		avg_parse_latency BIGINT UNSIGNED NOT NULL,
+		max_parse_latency BIGINT UNSIGNED NOT NULL,
+		avg_compile_latency BIGINT UNSIGNED NOT NULL,
+		max_compile_latency BIGINT UNSIGNED NOT NULL,
+		sum_cop_task_num BIGINT UNSIGNED NOT NULL,
+		max_cop_process_time BIGINT UNSIGNED NOT NULL,
+		max_cop_process_address VARCHAR(256),
+		max_cop_wait_time BIGINT UNSIGNED NOT NULL,
+		max_cop_wait_address VARCHAR(256),
+		avg_process_time BIGINT UNSIGNED NOT NULL,
+		max_process_time BIGINT UNSIGNED NOT NULL,
+		avg_wait_time BIGINT UNSIGNED NOT NULL,
+		max_wait_time BIGINT UNSIGNED NOT NULL,
+		avg_backoff_time BIGINT UNSIGNED NOT NULL,
+		max_backoff_time BIGINT UNSIGNED NOT NULL,
+		avg_total_keys BIGINT UNSIGNED NOT NULL,
+		max_total_keys BIGINT UNSIGNED NOT NULL,
+		avg_processed_keys BIGINT UNSIGNED NOT NULL,
+		max_processed_keys BIGINT UNSIGNED NOT NULL,
+		avg_rocksdb_delete_skipped_count DOUBLE UNSIGNED NOT NULL,
+		max_rocksdb_delete_skipped_count INT UNSIGNED NOT NULL,
+		avg_rocksdb_key_skipped_count DOUBLE UNSIGNED NOT NULL,
+		max_rocksdb_key_skipped_count INT UNSIGNED NOT NULL,
+		avg_rocksdb_block_cache_hit_count DOUBLE UNSIGNED NOT NULL,
+		max_rocksdb_block_cache_hit_count INT UNSIGNED NOT NULL,
+		avg_rocksdb_block_read_count DOUBLE UNSIGNED NOT NULL,
+		max_rocksdb_block_read_count INT UNSIGNED NOT NULL,
+		avg_rocksdb_block_read_byte DOUBLE UNSIGNED NOT NULL,
+		max_rocksdb_block_read_byte INT UNSIGNED NOT NULL,
+		avg_prewrite_time BIGINT UNSIGNED NOT NULL,
+		max_prewrite_time BIGINT UNSIGNED NOT NULL,
+		avg_commit_time BIGINT UNSIGNED NOT NULL,
+		max_commit_time BIGINT UNSIGNED NOT NULL,
+		avg_get_commit_ts_time BIGINT UNSIGNED NOT NULL,
+		max_get_commit_ts_time BIGINT UNSIGNED NOT NULL,
+		avg_commit_backoff_time BIGINT UNSIGNED NOT NULL,
+		max_commit_backoff_time BIGINT UNSIGNED NOT NULL,
+		avg_resolve_lock_time BIGINT UNSIGNED NOT NULL,
+		max_resolve_lock_time BIGINT UNSIGNED NOT NULL,
+		avg_local_latch_wait_time BIGINT UNSIGNED NOT NULL,
+		max_local_latch_wait_time BIGINT UNSIGNED NOT NULL,
+		avg_write_keys DOUBLE UNSIGNED NOT NULL,
+		max_write_keys BIGINT UNSIGNED NOT NULL,
+		avg_write_size DOUBLE UNSIGNED NOT NULL,
+		max_write_size BIGINT UNSIGNED NOT NULL,
+		avg_prewrite_regions DOUBLE UNSIGNED NOT NULL,
+		max_prewrite_regions INT UNSIGNED NOT NULL,
+		avg_txn_retry DOUBLE UNSIGNED NOT NULL,
+		max_txn_retry INT UNSIGNED NOT NULL,
+		sum_exec_retry BIGINT UNSIGNED NOT NULL,
+		sum_exec_retry_time BIGINT UNSIGNED NOT NULL,
+		sum_backoff_times BIGINT UNSIGNED NOT NULL,
+		backoff_types VARCHAR(1024),
+		avg_mem BIGINT UNSIGNED NOT NULL,
+		max_mem BIGINT UNSIGNED NOT NULL,
+		avg_disk BIGINT UNSIGNED NOT NULL,
+		max_disk BIGINT UNSIGNED NOT NULL,
+		avg_kv_time BIGINT UNSIGNED NOT NULL,
+		avg_pd_time BIGINT UNSIGNED NOT NULL,
+		avg_backoff_total_time BIGINT UNSIGNED NOT NULL,
+		avg_write_sql_resp_time BIGINT UNSIGNED NOT NULL,
+		avg_write_sql_resp_bytes BIGINT UNSIGNED NOT NULL DEFAULT 0,
+		max_write_sql_resp_bytes BIGINT UNSIGNED NOT NULL DEFAULT 0,
+		prepared TINYINT(1) NOT NULL,
+		avg_affected_rows DOUBLE UNSIGNED NOT NULL,
+		first_seen TIMESTAMP(6) NOT NULL,
+		last_seen TIMESTAMP(6) NOT NULL,
+		plan_in_cache TINYINT(1) NOT NULL,
+		plan_cache_hits BIGINT NOT NULL,
+		plan_in_binding TINYINT(1) NOT NULL,
+		query_sample_text TEXT,
+		prev_sample_text TEXT,
+		plan_digest VARCHAR(64),
+		plan TEXT,
+		stats_versions TEXT,
+		KEY (instance, summary_begin_time),
+		KEY (summary_end_time)
+	);`
+
+	// CreateRowPoliciesTable stores the row-level security policies, the predicates appended to the queries of the
+	// grantees on the tables.
+	CreateRowPoliciesTable = `CREATE TABLE IF NOT EXISTS mysql.row_policies (
+		name 		VARCHAR(64) NOT NULL,
+		db 			VARCHAR(64) NOT NULL,
+		table_name 	VARCHAR(64) NOT NULL,
+		user 		CHAR(32) NOT NULL DEFAULT '',
+		host 		CHAR(255) NOT NULL DEFAULT '',
+		using_expr 	TEXT NOT NULL,
+		PRIMARY KEY (db, table_name, name)
+	);`
+
+	// CreatePasswordHistoryTable stores the previous passwords of the users for the password reuse policy.
+	CreatePasswordHistoryTable = `CREATE TABLE IF NOT EXISTS mysql.password_history (
+		Host 				CHAR(255) NOT NULL DEFAULT '',
+		User 				CHAR(32) NOT NULL DEFAULT '',
+		Password_timestamp 	TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		Password 			TEXT,
+		PRIMARY KEY (Host, User, Password_timestamp)
+	);`
+
+	// CreateMaskingRulesTable stores the column masking rules, the expressions replacing the columns in the queries of
+	// the users without the UNMASK privilege.
+	CreateMaskingRulesTable = `CREATE TABLE IF NOT EXISTS mysql.masking_rules (
+		db 			VARCHAR(64) NOT NULL,
+		table_name 	VARCHAR(64) NOT NULL,
+		column_name VARCHAR(64) NOT NULL,
+		mask_expr 	TEXT NOT NULL,
+		PRIMARY KEY (db, table_name, column_name)
+	);`
+
+	// CreateXATransactionsTable stores the prepared XA transaction branches, so they can be committed or rolled back
+	// after the tidb-server restarts.
+	CreateXATransactionsTable = `CREATE TABLE IF NOT EXISTS mysql.xa_transactions (
+		format_id 		BIGINT(64) NOT NULL,
+		gtrid 			VARBINARY(64) NOT NULL,
+		bqual 			VARBINARY(64) NOT NULL,
+		start_ts 		BIGINT(64) UNSIGNED NOT NULL,
+		primary_key 	BLOB,
+		instance 		VARCHAR(512) NOT NULL,
+		prepare_time 	TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (format_id, gtrid, bqual)
+	);`
+
 	// CreateExprPushdownBlacklist stores the expressions which are not allowed to be pushed down.
 	CreateExprPushdownBlacklist = `CREATE TABLE IF NOT EXISTS mysql.expr_pushdown_blacklist (
 		name 		CHAR(100) NOT NULL,
@@ -337,6 +558,34 @@ const (
 		WITH_GRANT_OPTION enum('N','Y') NOT NULL DEFAULT 'N',
 		PRIMARY KEY (USER,HOST,PRIV)
 	  );`
+
+	// CreateTTLTable stores the status of the TTL jobs, the TTL attributes are in the table meta.
+	CreateTTLTable = `CREATE TABLE IF NOT EXISTS mysql.tidb_ttl_table (
+		table_id BIGINT(64) NOT NULL PRIMARY KEY,
+		last_job_start_time TIMESTAMP NULL DEFAULT NULL,
+		last_job_finish_time TIMESTAMP NULL DEFAULT NULL,
+		last_job_deleted_rows BIGINT(64) NOT NULL DEFAULT 0,
+		last_job_status VARCHAR(16) NOT NULL DEFAULT '',
+		last_job_error TEXT
+	);`
+	// CreatePlacementPolicyTable stores the placement policy objects.
+	CreatePlacementPolicyTable = `CREATE TABLE IF NOT EXISTS mysql.tidb_placement_policy (
+		name VARCHAR(64) NOT NULL PRIMARY KEY,
+		leader_constraints TEXT,
+		followers BIGINT(64) UNSIGNED NOT NULL DEFAULT 0,
+		follower_constraints TEXT,
+		voters BIGINT(64) UNSIGNED NOT NULL DEFAULT 0,
+		voter_constraints TEXT,
+		learners BIGINT(64) UNSIGNED NOT NULL DEFAULT 0,
+		learner_constraints TEXT,
+		create_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);`
+	// CreatePlacementPolicyObjectTable stores the tables and the partitions using the placement policies.
+	CreatePlacementPolicyObjectTable = `CREATE TABLE IF NOT EXISTS mysql.tidb_placement_policy_object (
+		object_id BIGINT(64) NOT NULL PRIMARY KEY,
+		policy_name VARCHAR(64) NOT NULL,
+		INDEX idx_policy_name (policy_name)
+	);`
 )
 
 // bootstrap initiates system DB for a store.
@@ -489,11 +738,50 @@ const (
 	version68 = 68
 	// version69 adds mysql.global_grants for DYNAMIC privileges
 	version69 = 69
+	// version70 adds mysql.tidb_ttl_table for TTL tables.
+	version70 = 70
+	// version71 adds mysql.tidb_placement_policy and mysql.tidb_placement_policy_object for placement policy objects.
+	version71 = 71
+	// version72 adds mysql.stats_global_merge to track the partition-level stats merged into global-stats.
+	version72 = 72
+	// version73 adds mysql.stats_table_locked to record the tables whose stats are locked.
+	version73 = 73
+	// version74 adds mysql.analyze_options to persist the analyze options of the tables.
+	version74 = 74
+	// version75 adds mysql.column_stats_usage to record the predicate columns.
+	version75 = 75
+	// version76 adds mysql.analyze_jobs to persist the progress of the analyze jobs.
+	version76 = 76
+	// version77 adds mysql.resource_groups and mysql.resource_group_users for the resource groups.
+	version77 = 77
+	// version78 adds the column plugin to mysql.user for the authentication plugins.
+	version78 = 78
+	// version79 adds the column priority to mysql.resource_groups for the priority scheduling of the worker pool.
+	version79 = 79
+	// version80 adds mysql.statements_summary_history to persist the history of the statement summaries.
+	version80 = 80
+	// version81 adds the columns max_connections and max_user_connections to mysql.user for the connection limits.
+	version81 = 81
+	// version82 adds the columns avg_write_sql_resp_bytes and max_write_sql_resp_bytes to mysql.statements_summary_history.
+	version82 = 82
+	// version83 adds mysql.row_policies for the row-level security policies.
+	version83 = 83
+	// version84 adds the columns failed_login_attempts, password_lock_time, failed_login_count and auto_locked_time to
+	// mysql.user for locking the accounts after the consecutive failed logins.
+	version84 = 84
+	// version85 adds the password expiration and reuse columns to mysql.user, and adds mysql.password_history.
+	version85 = 85
+	// version86 adds mysql.masking_rules for the column masking rules.
+	version86 = 86
+	// version87 drops the TTL attribute columns of mysql.tidb_ttl_table, the attributes are in the table meta.
+	version87 = 87
+	// version88 adds mysql.xa_transactions for the prepared XA transactions.
+	version88 = 88
 )
 
 // currentBootstrapVersion is defined as a variable, so we can modify its value for testing.
 // please make sure this is the largest version
-var currentBootstrapVersion int64 = version69
+var currentBootstrapVersion int64 = version88
 
 var (
 	bootstrapVersion = []func(Session, int64){
@@ -566,6 +854,25 @@ var (
 		upgradeToVer67,
 		upgradeToVer68,
 		upgradeToVer69,
+		upgradeToVer70,
+		upgradeToVer71,
+		upgradeToVer72,
+		upgradeToVer73,
+		upgradeToVer74,
+		upgradeToVer75,
+		upgradeToVer76,
+		upgradeToVer77,
+		upgradeToVer78,
+		upgradeToVer79,
+		upgradeToVer80,
+		upgradeToVer81,
+		upgradeToVer82,
+		upgradeToVer83,
+		upgradeToVer84,
+		upgradeToVer85,
+		upgradeToVer86,
+		upgradeToVer87,
+		upgradeToVer88,
 	}
 )
 
@@ -1489,6 +1796,155 @@ func upgradeToVer69(s Session, ver int64) {
 	doReentrantDDL(s, CreateGlobalGrantsTable)
 }
 
+func upgradeToVer70(s Session, ver int64) {
+	if ver >= version70 {
+		return
+	}
+	doReentrantDDL(s, CreateTTLTable)
+}
+
+func upgradeToVer71(s Session, ver int64) {
+	if ver >= version71 {
+		return
+	}
+	doReentrantDDL(s, CreatePlacementPolicyTable)
+	doReentrantDDL(s, CreatePlacementPolicyObjectTable)
+}
+
+func upgradeToVer72(s Session, ver int64) {
+	if ver >= version72 {
+		return
+	}
+	doReentrantDDL(s, CreateStatsGlobalMergeTable)
+}
+
+func upgradeToVer73(s Session, ver int64) {
+	if ver >= version73 {
+		return
+	}
+	doReentrantDDL(s, CreateStatsTableLockedTable)
+}
+
+func upgradeToVer74(s Session, ver int64) {
+	if ver >= version74 {
+		return
+	}
+	doReentrantDDL(s, CreateAnalyzeOptionsTable)
+}
+
+func upgradeToVer75(s Session, ver int64) {
+	if ver >= version75 {
+		return
+	}
+	doReentrantDDL(s, CreateColumnStatsUsageTable)
+}
+
+func upgradeToVer76(s Session, ver int64) {
+	if ver >= version76 {
+		return
+	}
+	doReentrantDDL(s, CreateAnalyzeJobsTable)
+}
+
+func upgradeToVer77(s Session, ver int64) {
+	if ver >= version77 {
+		return
+	}
+	doReentrantDDL(s, CreateResourceGroupsTable)
+	doReentrantDDL(s, CreateResourceGroupUsersTable)
+}
+
+func upgradeToVer78(s Session, ver int64) {
+	if ver >= version78 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `plugin` CHAR(64) NOT NULL DEFAULT 'mysql_native_password'", infoschema.ErrColumnExists)
+}
+
+func upgradeToVer79(s Session, ver int64) {
+	if ver >= version79 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.resource_groups ADD COLUMN `priority` VARCHAR(8) NOT NULL DEFAULT 'medium'", infoschema.ErrColumnExists)
+}
+
+func upgradeToVer80(s Session, ver int64) {
+	if ver >= version80 {
+		return
+	}
+	doReentrantDDL(s, CreateStmtSummaryHistoryTable)
+}
+
+func upgradeToVer81(s Session, ver int64) {
+	if ver >= version81 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `max_connections` INT UNSIGNED NOT NULL DEFAULT 0", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `max_user_connections` INT UNSIGNED NOT NULL DEFAULT 0", infoschema.ErrColumnExists)
+}
+
+func upgradeToVer82(s Session, ver int64) {
+	if ver >= version82 {
+		return
+	}
+	// The columns are positioned as information_schema.statements_summary_history, because the summaries are persisted
+	// and loaded by position.
+	doReentrantDDL(s, "ALTER TABLE mysql.statements_summary_history ADD COLUMN `avg_write_sql_resp_bytes` BIGINT UNSIGNED NOT NULL DEFAULT 0 AFTER `avg_write_sql_resp_time`", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.statements_summary_history ADD COLUMN `max_write_sql_resp_bytes` BIGINT UNSIGNED NOT NULL DEFAULT 0 AFTER `avg_write_sql_resp_bytes`", infoschema.ErrColumnExists)
+}
+
+func upgradeToVer83(s Session, ver int64) {
+	if ver >= version83 {
+		return
+	}
+	doReentrantDDL(s, CreateRowPoliciesTable)
+}
+
+func upgradeToVer84(s Session, ver int64) {
+	if ver >= version84 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `failed_login_attempts` INT UNSIGNED NOT NULL DEFAULT 0", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `password_lock_time` INT NOT NULL DEFAULT 0", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `failed_login_count` INT UNSIGNED NOT NULL DEFAULT 0", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `auto_locked_time` TIMESTAMP NULL DEFAULT NULL", infoschema.ErrColumnExists)
+}
+
+func upgradeToVer85(s Session, ver int64) {
+	if ver >= version85 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `password_expired` ENUM('N','Y') NOT NULL DEFAULT 'N'", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `password_last_changed` TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `password_lifetime` SMALLINT UNSIGNED NULL DEFAULT NULL", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_reuse_history` SMALLINT UNSIGNED NULL DEFAULT NULL", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_reuse_time` SMALLINT UNSIGNED NULL DEFAULT NULL", infoschema.ErrColumnExists)
+	doReentrantDDL(s, CreatePasswordHistoryTable)
+}
+
+func upgradeToVer86(s Session, ver int64) {
+	if ver >= version86 {
+		return
+	}
+	doReentrantDDL(s, CreateMaskingRulesTable)
+}
+
+func upgradeToVer87(s Session, ver int64) {
+	if ver >= version87 {
+		return
+	}
+	for _, col := range []string{"time_column", "interval_value", "interval_unit", "job_window_start", "job_window_end", "enable"} {
+		doReentrantDDL(s, "ALTER TABLE mysql.tidb_ttl_table DROP COLUMN "+col, ddl.ErrCantDropFieldOrKey)
+	}
+}
+
+func upgradeToVer88(s Session, ver int64) {
+	if ver >= version88 {
+		return
+	}
+	doReentrantDDL(s, CreateXATransactionsTable)
+}
+
 func writeOOMAction(s Session) {
 	comment := "oom-action is `log` by default in v3.0.x, `cancel` by default in v4.0.11+"
 	mustExecute(s, `INSERT HIGH_PRIORITY INTO %n.%n VALUES (%?, %?, %?) ON DUPLICATE KEY UPDATE VARIABLE_VALUE= %?`,
@@ -1567,6 +2023,34 @@ func doDDLWorks(s Session) {
 	mustExecute(s, CreateStatsFMSketchTable)
 	// Create global_grants
 	mustExecute(s, CreateGlobalGrantsTable)
+	// Create tidb_ttl_table.
+	mustExecute(s, CreateTTLTable)
+	// Create placement policy tables.
+	mustExecute(s, CreatePlacementPolicyTable)
+	mustExecute(s, CreatePlacementPolicyObjectTable)
+	// Create stats_global_merge table.
+	mustExecute(s, CreateStatsGlobalMergeTable)
+	// Create stats_table_locked table.
+	mustExecute(s, CreateStatsTableLockedTable)
+	// Create analyze_options table.
+	mustExecute(s, CreateAnalyzeOptionsTable)
+	// Create column_stats_usage table.
+	mustExecute(s, CreateColumnStatsUsageTable)
+	// Create analyze_jobs table.
+	mustExecute(s, CreateAnalyzeJobsTable)
+	// Create resource_groups and resource_group_users tables.
+	mustExecute(s, CreateResourceGroupsTable)
+	mustExecute(s, CreateResourceGroupUsersTable)
+	// Create statements_summary_history table.
+	mustExecute(s, CreateStmtSummaryHistoryTable)
+	// Create row_policies table.
+	mustExecute(s, CreateRowPoliciesTable)
+	// Create password_history table.
+	mustExecute(s, CreatePasswordHistoryTable)
+	// Create masking_rules table.
+	mustExecute(s, CreateMaskingRulesTable)
+	// Create xa_transactions table.
+	mustExecute(s, CreateXATransactionsTable)
 }
 
 // doDMLWorks executes DML statements in bootstrap stage.
@@ -1577,7 +2061,7 @@ func doDMLWorks(s Session) {
 
 	// Insert a default user with empty password.
 	mustExecute(s, `INSERT HIGH_PRIORITY INTO mysql.user VALUES
-		("%", "root", "", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y")`)
+		("%", "root", "", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "mysql_native_password", 0, 0, 0, 0, 0, NULL, "N", CURRENT_TIMESTAMP(), NULL, NULL, NULL)`)
 
 	// Init global system variables table.
 	values := make([]string, 0, len(variable.GetSysVars()))
@@ -37,7 +37,9 @@ import (
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/deadlockhistory"
 	"github.com/pingcap/tidb/util/testkit"
+	"github.com/pingcap/tidb/util/txninfo"
 )
 
 var _ = SerialSuites(&testPessimisticSuite{})
@@ -201,6 +203,16 @@ func (s *testPessimisticSuite) TestDeadlock(c *C) {
 	e, ok := errors.Cause(err).(*terror.Error)
 	c.Assert(ok, IsTrue)
 	c.Assert(int(e.Code()), Equals, mysql.ErrLockDeadlock)
+
+	// The deadlock is recorded in the deadlock history, and the error tells the wait chain digest.
+	records := deadlockhistory.GlobalDeadlockHistory.GetAll()
+	c.Assert(len(records), Greater, 0)
+	record := records[len(records)-1]
+	c.Assert(record.IsRetryable, IsFalse)
+	c.Assert(err.Error(), Matches, fmt.Sprintf(".*deadlock id: %d, wait chain digest: %s", record.ID, record.Digest()))
+	tk.MustExec("rollback")
+	tk.MustQuery("select retryable, wait_chain_digest, current_sql_digest_text, `key` is not null, trx_holding_lock > 0 from information_schema.deadlocks where deadlock_id = ?", record.ID).Check(
+		[][]interface{}{{"0", record.Digest(), "update `deadlock` set `v` = `v` + ? where `k` = ?", "1", "1"}})
 }
 
 func (s *testPessimisticSuite) TestSingleStatementRollback(c *C) {
@@ -1378,6 +1390,34 @@ func (s *testPessimisticSuite) TestRCSubQuery(c *C) {
 	tk.MustExec("rollback")
 }
 
+func (s *testPessimisticSuite) TestRCRetryStmtBeforeReturningRows(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, v int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+	tk.MustExec("set transaction isolation level read committed")
+	tk.MustExec("begin pessimistic")
+	tk.MustQuery("select v from t where id = 1").Check(testkit.Rows("1"))
+
+	tk2 := testkit.NewTestKitWithInit(c, s.store)
+	tk2.MustExec("update t set v = v + 10")
+
+	// The write conflict met before returning any row is retried with a new for-update ts.
+	c.Assert(failpoint.Enable("github.com/pingcap/tidb/executor/recordSetWriteConflict", "1*return(true)"), IsNil)
+	tk.MustQuery("select v from t order by id").Check(testkit.Rows("11", "12"))
+	c.Assert(failpoint.Disable("github.com/pingcap/tidb/executor/recordSetWriteConflict"), IsNil)
+	tk.MustExec("commit")
+
+	// The statement is not retried in the repeatable read transactions.
+	tk.MustExec("set transaction isolation level repeatable read")
+	tk.MustExec("begin pessimistic")
+	c.Assert(failpoint.Enable("github.com/pingcap/tidb/executor/recordSetWriteConflict", "1*return(true)"), IsNil)
+	err := tk.QueryToErr("select v from t order by id")
+	c.Assert(failpoint.Disable("github.com/pingcap/tidb/executor/recordSetWriteConflict"), IsNil)
+	c.Assert(kv.ErrWriteConflict.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustExec("rollback")
+}
+
 func (s *testPessimisticSuite) TestRCIndexMerge(c *C) {
 	tk := testkit.NewTestKitWithInit(c, s.store)
 	tk.MustExec("drop table if exists t")
@@ -2566,3 +2606,53 @@ func (s *testPessimisticSuite) TestAsyncCommitCalTSFail(c *C) {
 	tk2.MustExec("update tk set c2 = c2 + 1")
 	tk2.MustExec("commit")
 }
+
+func (s *testPessimisticSuite) TestTxnInfoWithLockWait(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists txn_info")
+	tk.MustExec("create table txn_info (k int primary key, v int)")
+	tk.MustExec("insert into txn_info values (1, 1)")
+	c.Assert(tk.Se.TxnInfo(), IsNil)
+
+	tk.MustExec("begin pessimistic")
+	tk.MustExec("update txn_info set v = v + 1 where k = 1")
+	info := tk.Se.TxnInfo()
+	c.Assert(info, NotNil)
+	c.Assert(info.StartTS, Equals, tk.Se.GetSessionVars().TxnCtx.StartTS)
+	c.Assert(info.State, Equals, txninfo.TxnRunning)
+	c.Assert(info.CurrentSQLDigestText, Equals, "update `txn_info` set `v` = `v` + ? where `k` = ?")
+	c.Assert(info.EntriesCount, Greater, uint64(0))
+	c.Assert(info.EntriesSize, Greater, uint64(0))
+
+	tk1.MustExec("set innodb_lock_wait_timeout = 50")
+	tk1.MustExec("begin pessimistic")
+	doneCh := make(chan struct{})
+	go func() {
+		tk1.MustExec("update txn_info set v = v + 1 where k = 1")
+		close(doneCh)
+	}()
+	// The holder of the lock is reported after the lock request returns from TiKV, which waits for at most 1s.
+	var info1 *txninfo.TxnInfo
+	for i := 0; i < 30; i++ {
+		time.Sleep(100 * time.Millisecond)
+		info1 = tk1.Se.TxnInfo()
+		if info1 != nil && len(info1.LockWaits) > 0 {
+			break
+		}
+	}
+	c.Assert(info1, NotNil)
+	c.Assert(info1.State, Equals, txninfo.TxnLockWaiting)
+	c.Assert(info1.BlockStartTime.IsZero(), IsFalse)
+	c.Assert(info1.LockWaits, HasLen, 1)
+	c.Assert(info1.LockWaits[0].HoldingTxnID, Equals, info.StartTS)
+
+	tk.MustExec("commit")
+	c.Assert(tk.Se.TxnInfo(), IsNil)
+	<-doneCh
+	info1 = tk1.Se.TxnInfo()
+	c.Assert(info1.State, Equals, txninfo.TxnRunning)
+	c.Assert(info1.LockWaits, HasLen, 0)
+	tk1.MustExec("rollback")
+	tk.MustQuery("select v from txn_info").Check(testkit.Rows("2"))
+}
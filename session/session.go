@@ -58,6 +58,8 @@ import (
 	"github.com/pingcap/tidb/plugin"
 	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/privilege/privileges"
+	"github.com/pingcap/tidb/resourcegroup"
+	"github.com/pingcap/tidb/server/audit"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/binloginfo"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
@@ -68,6 +70,7 @@ import (
 	tikvstore "github.com/pingcap/tidb/store/tikv/kv"
 	"github.com/pingcap/tidb/store/tikv/oracle"
 	tikvutil "github.com/pingcap/tidb/store/tikv/util"
+	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/telemetry"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
@@ -75,11 +78,13 @@ import (
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/dbterror"
 	"github.com/pingcap/tidb/util/execdetails"
+	"github.com/pingcap/tidb/util/gcutil"
 	"github.com/pingcap/tidb/util/kvcache"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/sli"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/timeutil"
+	"github.com/pingcap/tidb/util/txninfo"
 	"github.com/pingcap/tipb/go-binlog"
 	"go.uber.org/zap"
 )
@@ -142,8 +147,15 @@ type Session interface {
 	SetSessionManager(util.SessionManager)
 	Close()
 	Auth(user *auth.UserIdentity, auth []byte, salt []byte) bool
+	// AuthWithConn is Auth with the connection of the client, which is used by the authentication plugins exchanging
+	// more data with the client, e.g. authentication_ldap_sasl.
+	AuthWithConn(user *auth.UserIdentity, auth []byte, salt []byte, conn privilege.AuthConn) bool
 	AuthWithoutVerification(user *auth.UserIdentity) bool
+	// AuthPluginForUser returns the authentication plugin of the user, which decides how the user is authenticated.
+	AuthPluginForUser(user *auth.UserIdentity) string
 	ShowProcess() *util.ProcessInfo
+	// TxnInfo returns the information of the running transaction, it returns nil if there is no transaction.
+	TxnInfo() *txninfo.TxnInfo
 	// PrepareTxnCtx is exported for test.
 	PrepareTxnCtx(context.Context)
 	// FieldList returns fields list of a table.
@@ -214,6 +226,12 @@ type session struct {
 
 	// indexUsageCollector collects index usage information.
 	idxUsageCollector *handle.SessionIndexUsageCollector
+
+	// xa is the XA transaction branch of the session.
+	xa struct {
+		xid   ast.XID
+		state xaState
+	}
 }
 
 // AddTableLock adds table lock to the session lock map.
@@ -288,21 +306,20 @@ func (s *session) cleanRetryInfo() {
 
 	planCacheEnabled := plannercore.PreparedPlanCacheEnabled()
 	var cacheKey kvcache.Key
-	var preparedAst *ast.Prepared
+	var preparedObj *plannercore.CachedPrepareStmt
 	if planCacheEnabled {
 		firstStmtID := retryInfo.DroppedPreparedStmtIDs[0]
 		if preparedPointer, ok := s.sessionVars.PreparedStmts[firstStmtID]; ok {
-			preparedObj, ok := preparedPointer.(*plannercore.CachedPrepareStmt)
+			preparedObj, ok = preparedPointer.(*plannercore.CachedPrepareStmt)
 			if ok {
-				preparedAst = preparedObj.PreparedAst
-				cacheKey = plannercore.NewPSTMTPlanCacheKey(s.sessionVars, firstStmtID, preparedAst.SchemaVersion)
+				cacheKey = plannercore.NewPSTMTPlanCacheKey(s.sessionVars, firstStmtID, preparedObj.PlanSchemaVersion)
 			}
 		}
 	}
 	for i, stmtID := range retryInfo.DroppedPreparedStmtIDs {
 		if planCacheEnabled {
-			if i > 0 && preparedAst != nil {
-				plannercore.SetPstmtIDSchemaVersion(cacheKey, stmtID, preparedAst.SchemaVersion, s.sessionVars.IsolationReadEngines)
+			if i > 0 && preparedObj != nil {
+				plannercore.SetPstmtIDSchemaVersion(cacheKey, stmtID, preparedObj.PlanSchemaVersion, s.sessionVars.IsolationReadEngines)
 			}
 			s.PreparedPlanCache().Delete(cacheKey)
 		}
@@ -392,6 +409,13 @@ func (s *session) StoreQueryFeedback(feedback interface{}) {
 	}
 }
 
+// StorePredicateColumns stores the predicate columns in statsCollector.
+func (s *session) StorePredicateColumns(tableID int64, colIDs []int64) {
+	if s.statsCollector != nil {
+		s.statsCollector.UpdatePredicateColumns(tableID, colIDs)
+	}
+}
+
 // StoreIndexUsage stores index usage information in idxUsageCollector.
 func (s *session) StoreIndexUsage(tblID int64, idxID int64, rowsSelected int64) {
 	if s.idxUsageCollector == nil {
@@ -480,6 +504,14 @@ func (s *session) doCommit(ctx context.Context) error {
 		}
 	}
 
+	// Throttle the commit by the quota of the resource group of the session.
+	if groupName := s.sessionVars.ResourceGroupName; groupName != "" {
+		err = resourcegroup.GlobalManager().Consume(ctx, groupName, metrics.LblWrite, resourcegroup.WriteRU(s.txn.Size()))
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	// Get the related table or partition IDs.
 	relatedPhysicalTables := s.GetSessionVars().TxnCtx.TableDeltaMap
 	physicalTableIDs := make([]int64, 0, len(relatedPhysicalTables))
@@ -495,6 +527,9 @@ func (s *session) doCommit(ctx context.Context) error {
 	}
 	s.txn.SetOption(tikvstore.EnableAsyncCommit, s.GetSessionVars().EnableAsyncCommit)
 	s.txn.SetOption(tikvstore.Enable1PC, s.GetSessionVars().Enable1PC)
+	if !s.GetSessionVars().TxnCtx.IsExplicit && s.GetSessionVars().PipelinedAutoCommit {
+		s.txn.SetOption(tikvstore.PipelinedCommit, true)
+	}
 	// priority of the sysvar is lower than `start transaction with causal consistency only`
 	if s.txn.GetOption(tikvstore.GuaranteeLinearizability) == nil {
 		// We needn't ask the TiKV client to guarantee linearizability for auto-commit transactions
@@ -506,7 +541,57 @@ func (s *session) doCommit(ctx context.Context) error {
 			s.GetSessionVars().TxnCtx.IsExplicit && s.GetSessionVars().GuaranteeLinearizability)
 	}
 
-	return s.txn.Commit(tikvutil.SetSessionID(ctx, s.GetSessionVars().ConnectionID))
+	return s.commitTxnWithTemporaryData(tikvutil.SetSessionID(ctx, s.GetSessionVars().ConnectionID), &s.txn)
+}
+
+// commitTxnWithTemporaryData commits the transaction, and moves the changes of the local temporary tables
+// into the session after the transaction is committed successfully.
+func (s *session) commitTxnWithTemporaryData(ctx context.Context, txn kv.Transaction) error {
+	sessVars := s.sessionVars
+	localTables, ok := sessVars.LocalTemporaryTables.(*infoschema.LocalTemporaryTables)
+	if !ok || localTables.Count() == 0 || sessVars.TemporaryTableData == nil {
+		return txn.Commit(ctx)
+	}
+
+	type tempTableKV struct {
+		key   kv.Key
+		value []byte
+	}
+	var tempKVs []tempTableKV
+	memBuffer := txn.GetMemBuffer()
+	for _, tableID := range localTables.TableIDs() {
+		tablePrefix := tablecodec.EncodeTablePrefix(tableID)
+		iter, err := memBuffer.Iter(tablePrefix, tablePrefix.PrefixNext())
+		if err != nil {
+			return err
+		}
+		for ; iter.Valid(); err = iter.Next() {
+			if err != nil {
+				iter.Close()
+				return err
+			}
+			tempKVs = append(tempKVs, tempTableKV{key: iter.Key().Clone(), value: append([]byte(nil), iter.Value()...)})
+		}
+		iter.Close()
+	}
+
+	if err := txn.Commit(ctx); err != nil {
+		return err
+	}
+
+	for _, pair := range tempKVs {
+		var err error
+		// An empty value means the key is deleted.
+		if len(pair.value) == 0 {
+			err = sessVars.TemporaryTableData.Delete(pair.key)
+		} else {
+			err = sessVars.TemporaryTableData.Set(pair.key, pair.value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // errIsNoisy is used to filter DUPLCATE KEY errors.
@@ -582,7 +667,8 @@ func (s *session) doCommitWithRetry(ctx context.Context) error {
 	mapper := s.GetSessionVars().TxnCtx.TableDeltaMap
 	if s.statsCollector != nil && mapper != nil {
 		for _, item := range mapper {
-			if item.TableID > 0 {
+			// The statistics of local temporary tables are not maintained.
+			if item.TableID > 0 && !s.sessionVars.IsLocalTemporaryTable(item.TableID) {
 				s.statsCollector.Update(item.TableID, item.Delta, item.Count, &item.ColSize)
 			}
 		}
@@ -701,7 +787,7 @@ func (s *session) checkTxnAborted(stmt sqlexec.Statement) error {
 	if _, ok := stmt.(*executor.ExecStmt).StmtNode.(*ast.CommitStmt); ok {
 		return nil
 	}
-	if _, ok := stmt.(*executor.ExecStmt).StmtNode.(*ast.RollbackStmt); ok {
+	if rollback, ok := stmt.(*executor.ExecStmt).StmtNode.(*ast.RollbackStmt); ok && rollback.SavepointName == "" {
 		return nil
 	}
 	return err
@@ -866,6 +952,11 @@ func createSessionWithDomainFunc(store kv.Storage) func(*domain.Domain) (pools.R
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
+		// The select of `CREATE TABLE ... SELECT` is executed by the DDL workers, functions like CONCAT need it.
+		err = variable.SetSessionSystemVar(se.sessionVars, variable.MaxAllowedPacket, types.NewStringDatum("67108864"))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
 		se.sessionVars.CommonGlobalLoaded = true
 		se.sessionVars.InRestrictedSQL = true
 		return se, nil
@@ -1127,6 +1218,15 @@ func (s *session) SetProcessInfo(sql string, t time.Time, command byte, maxExecu
 		StatsInfo:        plannercore.GetStatsInfo,
 		MaxExecutionTime: maxExecutionTime,
 		RedactSQL:        s.sessionVars.EnableRedactLog,
+		IdleTxnTimeout:   s.sessionVars.IdleTxnTimeout,
+		MaxTxnDuration:   s.sessionVars.MaxTxnDuration,
+		ConnectAttrs:     s.sessionVars.ConnectAttrs,
+	}
+	if s.sessionVars.InTxn() {
+		pi.TxnStartTime = s.sessionVars.TxnCtx.CreateTime
+	}
+	if command == mysql.ComSleep {
+		pi.IdleStartTime = time.Now()
 	}
 	oldPi := s.ShowProcess()
 	if p == nil {
@@ -1396,6 +1496,12 @@ func (s *session) ExecuteStmt(ctx context.Context, stmtNode ast.StmtNode) (sqlex
 		ctx = opentracing.ContextWithSpan(ctx, span1)
 	}
 
+	if err := s.checkXAStmt(stmtNode); err != nil {
+		return nil, err
+	}
+	if err := s.checkSandBoxMode(stmtNode); err != nil {
+		return nil, err
+	}
 	s.PrepareTxnCtx(ctx)
 	err := s.loadCommonGlobalVariablesIfNeeded()
 	if err != nil {
@@ -1408,6 +1514,9 @@ func (s *session) ExecuteStmt(ctx context.Context, stmtNode ast.StmtNode) (sqlex
 	if err := executor.ResetContextOfStmt(s, stmtNode); err != nil {
 		return nil, err
 	}
+	if err := s.prepareStaleReadTxn(ctx, stmtNode); err != nil {
+		return nil, err
+	}
 	if err := s.validateStatementReadOnlyInStaleness(stmtNode); err != nil {
 		return nil, err
 	}
@@ -1421,6 +1530,8 @@ func (s *session) ExecuteStmt(ctx context.Context, stmtNode ast.StmtNode) (sqlex
 	stmt, err := compiler.Compile(ctx, stmtNode)
 	if err != nil {
 		s.rollbackOnError(ctx)
+		// The statement is not executed, so restore the variables set by the SET_VAR hints here.
+		s.sessionVars.ClearStmtVars()
 
 		// Only print log message when this SQL is from the user.
 		// Mute the warning for internal SQLs.
@@ -1442,6 +1553,7 @@ func (s *session) ExecuteStmt(ctx context.Context, stmtNode ast.StmtNode) (sqlex
 	logStmt(stmt, s.sessionVars)
 	recordSet, err := runStmt(ctx, s, stmt)
 	if err != nil {
+		s.sessionVars.ClearStmtVars()
 		if !kv.ErrKeyExists.Equal(err) {
 			logutil.Logger(ctx).Warn("run statement failed",
 				zap.Int64("schemaVersion", s.sessionVars.TxnCtx.SchemaVersion),
@@ -1463,6 +1575,32 @@ func (s *session) ExecuteStmt(ctx context.Context, stmtNode ast.StmtNode) (sqlex
 	return recordSet, nil
 }
 
+// prepareStaleReadTxn starts a staleness transaction for the auto-commit read-only statement if @@tidb_read_staleness
+// is set, so the statement can be served by the closest replica.
+func (s *session) prepareStaleReadTxn(ctx context.Context, stmtNode ast.StmtNode) error {
+	vars := s.GetSessionVars()
+	if vars.ReadStaleness == 0 || vars.InTxn() || !vars.IsAutocommit() || vars.SnapshotTS != 0 || s.isInternal() || s.txn.validOrPending() {
+		return nil
+	}
+	switch x := stmtNode.(type) {
+	case *ast.SelectStmt:
+		if x.LockInfo != nil && x.LockInfo.LockType != ast.SelectLockNone {
+			return nil
+		}
+	case *ast.SetOprStmt:
+	default:
+		return nil
+	}
+	staleness := -vars.ReadStaleness
+	if err := gcutil.ValidateStaleReadTS(s, oracle.GoTimeToTS(time.Now().Add(-staleness))); err != nil {
+		return err
+	}
+	return s.NewTxnWithStalenessOption(ctx, sessionctx.StalenessTxnOption{
+		Mode:    ast.TimestampBoundExactStaleness,
+		PrevSec: uint64(staleness.Seconds()),
+	})
+}
+
 func (s *session) validateStatementReadOnlyInStaleness(stmtNode ast.StmtNode) error {
 	vars := s.GetSessionVars()
 	if !vars.TxnCtx.IsStaleness {
@@ -1524,6 +1662,9 @@ func runStmt(ctx context.Context, se *session, s sqlexec.Statement) (rs sqlexec.
 	}
 
 	sessVars := se.sessionVars
+	if !se.isInternal() {
+		se.txn.onStmtStart(sessVars.StmtCtx.SQLDigest())
+	}
 
 	// Record diagnostic information for DML statements
 	if _, ok := s.(*executor.ExecStmt).StmtNode.(ast.DMLNode); ok {
@@ -1604,9 +1745,13 @@ func (s *session) rollbackOnError(ctx context.Context) {
 
 // PrepareStmt is used for executing prepare statement in binary protocol
 func (s *session) PrepareStmt(sql string) (stmtID uint32, paramCount int, fields []*ast.ResultField, err error) {
+	if s.sessionVars.InSandBoxMode {
+		err = ErrMustChangePassword.GenWithStackByArgs()
+		return
+	}
 	if s.sessionVars.TxnCtx.InfoSchema == nil {
 		// We don't need to create a transaction for prepare statement, just get information schema will do.
-		s.sessionVars.TxnCtx.InfoSchema = domain.GetDomain(s).InfoSchema()
+		s.sessionVars.TxnCtx.InfoSchema = infoschema.AttachLocalTemporaryTables(s.sessionVars, domain.GetDomain(s).InfoSchema())
 	}
 	err = s.loadCommonGlobalVariablesIfNeeded()
 	if err != nil {
@@ -1739,9 +1884,9 @@ func (s *session) IsCachedExecOk(ctx context.Context, preparedStmt *plannercore.
 	if !s.GetSessionVars().IsAutocommit() {
 		return false, nil
 	}
-	// check schema version
+	// check the tables referenced by the statement are not changed
 	is := infoschema.GetInfoSchema(s)
-	if prepared.SchemaVersion != is.SchemaMetaVersion() {
+	if preparedStmt.CheckSchemaVersion(is) {
 		prepared.CachedPlan = nil
 		return false, nil
 	}
@@ -1786,10 +1931,18 @@ func (s *session) ExecutePreparedStmt(ctx context.Context, stmtID uint32, args [
 	if err != nil {
 		return nil, err
 	}
+	var rs sqlexec.RecordSet
 	if ok {
-		return s.cachedPlanExec(ctx, stmtID, preparedStmt, args)
+		rs, err = s.cachedPlanExec(ctx, stmtID, preparedStmt, args)
+	} else {
+		rs, err = s.preparedStmtExec(ctx, stmtID, preparedStmt, args)
+	}
+	if err != nil && rs == nil {
+		// The variables set by the SET_VAR hints are restored when the statement finishes, but the statement may
+		// fail before it's executed.
+		s.sessionVars.ClearStmtVars()
 	}
-	return s.preparedStmtExec(ctx, stmtID, preparedStmt, args)
+	return rs, err
 }
 
 func (s *session) DropPreparedStmt(stmtID uint32) error {
@@ -1834,10 +1987,18 @@ func (s *session) Txn(active bool) (kv.Transaction, error) {
 		if s.sessionVars.GetReplicaRead().IsFollowerRead() {
 			s.txn.SetOption(tikvstore.ReplicaRead, tikvstore.ReplicaReadFollower)
 		}
+		s.setTemporaryTableOption()
 	}
 	return &s.txn, nil
 }
 
+// setTemporaryTableOption makes the transaction read and write the local temporary tables in the session.
+func (s *session) setTemporaryTableOption() {
+	if tempTables := infoschema.NewTemporaryTableRetriever(s.sessionVars); tempTables != nil {
+		s.txn.SetOption(tikvstore.TemporaryTables, tempTables)
+	}
+}
+
 // isTxnRetryable (if returns true) means the transaction could retry.
 // If the transaction is in pessimistic mode, do not retry.
 // If the session is already in transaction, enable retry or internal SQL could retry.
@@ -1899,7 +2060,8 @@ func (s *session) NewTxn(ctx context.Context) error {
 		txn.SetOption(tikvstore.ReplicaRead, tikvstore.ReplicaReadFollower)
 	}
 	s.txn.changeInvalidToValid(txn)
-	is := domain.GetDomain(s).InfoSchema()
+	s.setTemporaryTableOption()
+	is := infoschema.AttachLocalTemporaryTables(s.sessionVars, domain.GetDomain(s).InfoSchema())
 	s.sessionVars.TxnCtx = &variable.TransactionContext{
 		InfoSchema:    is,
 		SchemaVersion: is.SchemaMetaVersion(),
@@ -1960,8 +2122,14 @@ func (s *session) Close() {
 	}
 	ctx := context.WithValue(context.TODO(), inCloseSession{}, struct{}{})
 	s.RollbackTxn(ctx)
+	// The XA transaction branch not prepared is rolled back above, the prepared one is detached from the session.
+	s.endXA()
 	if s.sessionVars != nil {
 		s.sessionVars.WithdrawAllPreparedStmt()
+		// The data of the local temporary tables vanishes with the session.
+		if s.sessionVars.TemporaryTableData != nil {
+			s.sessionVars.TemporaryTableData.Close()
+		}
 	}
 }
 
@@ -1971,22 +2139,32 @@ func (s *session) GetSessionVars() *variable.SessionVars {
 }
 
 func (s *session) Auth(user *auth.UserIdentity, authentication []byte, salt []byte) bool {
+	return s.AuthWithConn(user, authentication, salt, nil)
+}
+
+// AuthWithConn implements the Session interface.
+func (s *session) AuthWithConn(user *auth.UserIdentity, authentication []byte, salt []byte, conn privilege.AuthConn) bool {
 	pm := privilege.GetPrivilegeManager(s)
+	authCtx := &privilege.AuthContext{GlobalVars: s.sessionVars.GlobalVarsAccessor, Conn: conn}
 
 	// Check IP or localhost.
 	var success bool
-	user.AuthUsername, user.AuthHostname, success = pm.ConnectionVerification(user.Username, user.Hostname, authentication, salt, s.sessionVars.TLSConnectionState)
+	user.AuthUsername, user.AuthHostname, success = pm.ConnectionVerification(user.Username, user.Hostname, authentication, salt, s.sessionVars.TLSConnectionState, authCtx)
 	if success {
 		s.sessionVars.User = user
-		s.sessionVars.ActiveRoles = pm.GetDefaultRoles(user.AuthUsername, user.AuthHostname)
+		s.sessionVars.ActiveRoles = append(pm.GetDefaultRoles(user.AuthUsername, user.AuthHostname), authCtx.Roles...)
+		s.bindResourceGroup(user.AuthUsername, user.AuthHostname)
+		s.resetFailedLogins(pm, user.AuthUsername, user.AuthHostname)
+		s.checkPasswordExpired(pm, user.AuthUsername, user.AuthHostname)
 		return true
 	} else if user.Hostname == variable.DefHostname {
+		s.trackFailedLogin(pm, user.AuthUsername, user.AuthHostname)
 		return false
 	}
 
 	// Check Hostname.
 	for _, addr := range getHostByIP(user.Hostname) {
-		u, h, success := pm.ConnectionVerification(user.Username, addr, authentication, salt, s.sessionVars.TLSConnectionState)
+		u, h, success := pm.ConnectionVerification(user.Username, addr, authentication, salt, s.sessionVars.TLSConnectionState, authCtx)
 		if success {
 			s.sessionVars.User = &auth.UserIdentity{
 				Username:     user.Username,
@@ -1994,13 +2172,131 @@ func (s *session) Auth(user *auth.UserIdentity, authentication []byte, salt []by
 				AuthUsername: u,
 				AuthHostname: h,
 			}
-			s.sessionVars.ActiveRoles = pm.GetDefaultRoles(u, h)
+			s.sessionVars.ActiveRoles = append(pm.GetDefaultRoles(u, h), authCtx.Roles...)
+			s.bindResourceGroup(u, h)
+			s.resetFailedLogins(pm, u, h)
+			s.checkPasswordExpired(pm, u, h)
 			return true
 		}
 	}
+	s.trackFailedLogin(pm, user.AuthUsername, user.AuthHostname)
 	return false
 }
 
+// AuthPluginForUser implements the Session interface.
+func (s *session) AuthPluginForUser(user *auth.UserIdentity) string {
+	pm := privilege.GetPrivilegeManager(s)
+	if plugin, ok := pm.GetAuthPlugin(user.Username, user.Hostname); ok {
+		return plugin
+	}
+	if user.Hostname != variable.DefHostname {
+		for _, addr := range getHostByIP(user.Hostname) {
+			if plugin, ok := pm.GetAuthPlugin(user.Username, addr); ok {
+				return plugin
+			}
+		}
+	}
+	return mysql.AuthNativePassword
+}
+
+// bindResourceGroup binds the session to the resource group of the authenticated user account.
+func (s *session) bindResourceGroup(user, host string) {
+	if group := resourcegroup.GlobalManager().GroupOfUser(user, host); group != "" {
+		terror.Log(s.sessionVars.SetSystemVar(variable.TiDBResourceGroup, group))
+	}
+}
+
+// trackFailedLogin records the failed login of the user account if its failed logins are tracked, the account is
+// locked after FAILED_LOGIN_ATTEMPTS consecutive failed logins.
+func (s *session) trackFailedLogin(pm privilege.Manager, user, host string) {
+	if user == "" {
+		return
+	}
+	locking, ok := pm.GetPasswordLocking(user, host)
+	if !ok || !locking.Enabled() || locking.AccountLocked || locking.AutoLocked(time.Now()) {
+		return
+	}
+	if !locking.AutoLockedTime.IsZero() {
+		// The lock is expired, the failed logins are counted from scratch.
+		s.unlockAccount(user, host, "the PASSWORD_LOCK_TIME expired")
+	}
+	locked, err := privileges.RecordFailedLogin(s, user, host, locking.FailedLoginAttempts)
+	if err != nil {
+		logutil.BgLogger().Warn("record the failed login failed", zap.String("user", user), zap.String("host", host), zap.Error(err))
+		return
+	}
+	if locked {
+		reason := fmt.Sprintf("%d consecutive failed logins", locking.FailedLoginAttempts)
+		logutil.BgLogger().Warn("the account is locked", zap.String("user", user), zap.String("host", host),
+			zap.String("reason", reason), zap.Int64("passwordLockTime", locking.PasswordLockTime))
+		if audit.Enabled() {
+			audit.Log(audit.NewAccountEvent(audit.TypeAccountLock, s.sessionVars.ConnectionID, user, host, reason))
+		}
+		domain.GetDomain(s).NotifyUpdatePrivilege(s)
+	}
+}
+
+// resetFailedLogins resets the failed login count of the user account after it logs in successfully.
+func (s *session) resetFailedLogins(pm privilege.Manager, user, host string) {
+	locking, ok := pm.GetPasswordLocking(user, host)
+	if !ok {
+		return
+	}
+	if !locking.AutoLockedTime.IsZero() {
+		s.unlockAccount(user, host, "the PASSWORD_LOCK_TIME expired")
+		domain.GetDomain(s).NotifyUpdatePrivilege(s)
+		return
+	}
+	// The failed login count in the privilege cache may be stale, so it's reset if the failed logins are tracked.
+	if locking.Enabled() {
+		if err := privileges.ResetFailedLogins(s, user, host); err != nil {
+			logutil.BgLogger().Warn("reset the failed logins failed", zap.String("user", user), zap.String("host", host), zap.Error(err))
+		}
+	}
+}
+
+// unlockAccount unlocks the account locked by the failed logins.
+func (s *session) unlockAccount(user, host, reason string) {
+	if err := privileges.ResetFailedLogins(s, user, host); err != nil {
+		logutil.BgLogger().Warn("unlock the account failed", zap.String("user", user), zap.String("host", host), zap.Error(err))
+		return
+	}
+	logutil.BgLogger().Info("the account is unlocked", zap.String("user", user), zap.String("host", host), zap.String("reason", reason))
+	if audit.Enabled() {
+		audit.Log(audit.NewAccountEvent(audit.TypeAccountUnlock, s.sessionVars.ConnectionID, user, host, reason))
+	}
+}
+
+// checkPasswordExpired puts the session into the sandbox mode if the password of the user account has expired.
+func (s *session) checkPasswordExpired(pm privilege.Manager, user, host string) {
+	expiration, ok := pm.GetPasswordExpiration(user, host)
+	if !ok {
+		return
+	}
+	var defaultLifetime int64
+	if val, err := s.sessionVars.GlobalVarsAccessor.GetGlobalSysVar(variable.DefaultPasswordLifetime); err == nil {
+		defaultLifetime, _ = strconv.ParseInt(val, 10, 64)
+	} else {
+		logutil.BgLogger().Warn("get default_password_lifetime failed", zap.Error(err))
+	}
+	s.sessionVars.InSandBoxMode = expiration.IsExpired(time.Now(), defaultLifetime)
+	if s.sessionVars.InSandBoxMode {
+		logutil.BgLogger().Info("the password has expired, the session is in the sandbox mode", zap.String("user", user), zap.String("host", host))
+	}
+}
+
+// checkSandBoxMode checks whether the statement can be executed in the sandbox mode, only SET PASSWORD is allowed
+// until the expired password is reset.
+func (s *session) checkSandBoxMode(stmtNode ast.StmtNode) error {
+	if !s.sessionVars.InSandBoxMode || s.sessionVars.InRestrictedSQL {
+		return nil
+	}
+	if _, ok := stmtNode.(*ast.SetPwdStmt); ok {
+		return nil
+	}
+	return ErrMustChangePassword.GenWithStackByArgs()
+}
+
 // AuthWithoutVerification is required by the ResetConnection RPC
 func (s *session) AuthWithoutVerification(user *auth.UserIdentity) bool {
 	pm := privilege.GetPrivilegeManager(s)
@@ -2011,6 +2307,7 @@ func (s *session) AuthWithoutVerification(user *auth.UserIdentity) bool {
 	if success {
 		s.sessionVars.User = user
 		s.sessionVars.ActiveRoles = pm.GetDefaultRoles(user.AuthUsername, user.AuthHostname)
+		s.bindResourceGroup(user.AuthUsername, user.AuthHostname)
 		return true
 	} else if user.Hostname == variable.DefHostname {
 		return false
@@ -2027,6 +2324,7 @@ func (s *session) AuthWithoutVerification(user *auth.UserIdentity) bool {
 				AuthHostname: h,
 			}
 			s.sessionVars.ActiveRoles = pm.GetDefaultRoles(u, h)
+			s.bindResourceGroup(u, h)
 			return true
 		}
 	}
@@ -2291,6 +2589,58 @@ func BootstrapSession(store kv.Storage) (*domain.Domain, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	se6, err := createSession(store)
+	if err != nil {
+		return nil, err
+	}
+	dom.TTLJobLoop(se6)
+
+	se7, err := createSession(store)
+	if err != nil {
+		return nil, err
+	}
+	err = dom.ResourceGroupLoop(se7)
+	if err != nil {
+		return nil, err
+	}
+
+	se8, err := createSession(store)
+	if err != nil {
+		return nil, err
+	}
+	dom.PreparedXAKeepAliveLoop(se8)
+
+	se9, err := createSession(store)
+	if err != nil {
+		return nil, err
+	}
+	dom.ReadOnlyLoop(se9)
+
+	se10, err := createSession(store)
+	if err != nil {
+		return nil, err
+	}
+	dom.StmtSummaryPersistLoop(se10)
+
+	se11, err := createSession(store)
+	if err != nil {
+		return nil, err
+	}
+	err = dom.RowPolicyLoop(se11)
+	if err != nil {
+		return nil, err
+	}
+
+	se12, err := createSession(store)
+	if err != nil {
+		return nil, err
+	}
+	err = dom.MaskingRuleLoop(se12)
+	if err != nil {
+		return nil, err
+	}
+
 	if raw, ok := store.(kv.EtcdBackend); ok {
 		err = raw.StartGCWorker()
 		if err != nil {
@@ -2468,6 +2818,7 @@ var builtinGlobalVariable = []string{
 	variable.WindowingUseHighPrecision,
 	variable.SQLSelectLimit,
 	variable.DefaultWeekFormat,
+	variable.OptimizerSwitch,
 
 	/* TiDB specific global variables: */
 	variable.TiDBSkipASCIICheck,
@@ -2491,6 +2842,8 @@ var builtinGlobalVariable = []string{
 	variable.TiDBDDLReorgWorkerCount,
 	variable.TiDBDDLReorgBatchSize,
 	variable.TiDBDDLErrorCountLimit,
+	variable.TiDBDDLEnableFastReorg,
+	variable.TiDBEnableConcurrentDDL,
 	variable.TiDBOptInSubqToJoinAndAgg,
 	variable.TiDBOptPreferRangeScan,
 	variable.TiDBOptCorrelationThreshold,
@@ -2513,6 +2866,7 @@ var builtinGlobalVariable = []string{
 	variable.TiDBEnableStrictDoubleTypeCheck,
 	variable.TiDBEnableTablePartition,
 	variable.TiDBEnableVectorizedExpression,
+	variable.TiDBEnableExprCodegen,
 	variable.TiDBEnableFastAnalyze,
 	variable.TiDBExpensiveQueryTimeThreshold,
 	variable.TiDBEnableNoopFuncs,
@@ -2539,6 +2893,8 @@ var builtinGlobalVariable = []string{
 	variable.TiDBStoreLimit,
 	variable.TiDBAllowAutoRandExplicitInsert,
 	variable.TiDBEnableClusteredIndex,
+	variable.TiDBEnableGeneratedInvisiblePK,
+	variable.TiDBShowGeneratedInvisiblePK,
 	variable.TiDBPartitionPruneMode,
 	variable.TiDBRedactLog,
 	variable.TiDBEnableTelemetry,
@@ -2552,9 +2908,11 @@ var builtinGlobalVariable = []string{
 	variable.TiDBEnableParallelApply,
 	variable.TiDBMemoryUsageAlarmRatio,
 	variable.TiDBEnableRateLimitAction,
+	variable.TiDBMemOOMActionChain,
 	variable.TiDBEnableAsyncCommit,
 	variable.TiDBEnable1PC,
 	variable.TiDBGuaranteeLinearizability,
+	variable.TiDBPipelinedAutoCommit,
 	variable.TiDBAnalyzeVersion,
 	variable.TiDBEnableIndexMergeJoin,
 	variable.TiDBTrackAggregateMemoryUsage,
@@ -2562,6 +2920,8 @@ var builtinGlobalVariable = []string{
 	variable.TiDBEnableExchangePartition,
 	variable.TiDBAllowFallbackToTiKV,
 	variable.TiDBEnableDynamicPrivileges,
+	variable.TiDBTmpTableMemQuota,
+	variable.TiDBLoadDataLocalCompression,
 }
 
 // loadCommonGlobalVariablesIfNeeded loads and applies commonly used global variables for the session.
@@ -2634,7 +2994,7 @@ func (s *session) PrepareTxnCtx(ctx context.Context) {
 		return
 	}
 
-	is := domain.GetDomain(s).InfoSchema()
+	is := infoschema.AttachLocalTemporaryTables(s.sessionVars, domain.GetDomain(s).InfoSchema())
 	s.sessionVars.TxnCtx = &variable.TransactionContext{
 		InfoSchema:    is,
 		SchemaVersion: is.SchemaMetaVersion(),
@@ -2694,6 +3054,7 @@ func (s *session) InitTxnWithStartTS(startTS uint64) error {
 	}
 	txn.SetVars(s.sessionVars.KVVars)
 	s.txn.changeInvalidToValid(txn)
+	s.setTemporaryTableOption()
 	err = s.loadCommonGlobalVariablesIfNeeded()
 	if err != nil {
 		return err
@@ -2720,12 +3081,12 @@ func (s *session) NewTxnWithStalenessOption(ctx context.Context, option sessionc
 	var err error
 	txnScope := s.GetSessionVars().CheckAndGetTxnScope()
 	switch option.Mode {
-	case ast.TimestampBoundReadTimestamp:
+	case ast.TimestampBoundReadTimestamp, ast.TimestampBoundMinReadTimestamp:
 		txn, err = s.store.BeginWithOption(kv.TransactionOption{}.SetTxnScope(txnScope).SetStartTs(option.StartTS))
 		if err != nil {
 			return err
 		}
-	case ast.TimestampBoundExactStaleness:
+	case ast.TimestampBoundExactStaleness, ast.TimestampBoundMaxStaleness:
 		txn, err = s.store.BeginWithOption(kv.TransactionOption{}.SetTxnScope(txnScope).SetPrevSec(option.PrevSec))
 		if err != nil {
 			return err
@@ -2738,7 +3099,8 @@ func (s *session) NewTxnWithStalenessOption(ctx context.Context, option sessionc
 	txn.SetOption(tikvstore.IsStalenessReadOnly, true)
 	txn.SetOption(tikvstore.TxnScope, txnScope)
 	s.txn.changeInvalidToValid(txn)
-	is := domain.GetDomain(s).InfoSchema()
+	s.setTemporaryTableOption()
+	is := infoschema.AttachLocalTemporaryTables(s.sessionVars, domain.GetDomain(s).InfoSchema())
 	s.sessionVars.TxnCtx = &variable.TransactionContext{
 		InfoSchema:    is,
 		SchemaVersion: is.SchemaMetaVersion(),
@@ -2765,6 +3127,21 @@ func (s *session) ShowProcess() *util.ProcessInfo {
 	return pi
 }
 
+func (s *session) TxnInfo() *txninfo.TxnInfo {
+	info := s.txn.TxnInfo()
+	if info == nil {
+		return nil
+	}
+	// Get the connection information from the process info, because the session variables are not safe to read
+	// from the other goroutines.
+	if pi := s.ShowProcess(); pi != nil {
+		info.ConnectionID = pi.ID
+		info.Username = pi.User
+		info.CurrentDB = pi.DB
+	}
+	return info
+}
+
 // logStmt logs some crucial SQL including: CREATE USER/GRANT PRIVILEGE/CHANGE PASSWORD/DDL etc and normal SQL
 // if variable.ProcessGeneralLog is set.
 func logStmt(execStmt *executor.ExecStmt, vars *variable.SessionVars) {
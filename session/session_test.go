@@ -3633,6 +3633,36 @@ func (s *testSessionSuite3) TestSetVarHint(c *C) {
 	tk.MustExec("SELECT /*+ SET_VAR(group_concat_max_len = 1024) SET_VAR(group_concat_max_len = 2048) */ 1;")
 	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 1)
 	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings()[0].Err.Error(), Equals, "[planner:3126]Hint SET_VAR(group_concat_max_len=2048) is ignored as conflicting/duplicated.")
+
+	// The hinted value should take effect on the session variables during the statement.
+	tk.MustExec("set @@tidb_executor_concurrency = 5")
+	tk.MustQuery("SELECT /*+ SET_VAR(tidb_executor_concurrency=1) */ @@tidb_executor_concurrency;").Check(testkit.Rows("1"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 0)
+	c.Assert(tk.Se.GetSessionVars().ExecutorConcurrency, Equals, 5)
+	tk.MustQuery("SELECT @@tidb_executor_concurrency;").Check(testkit.Rows("5"))
+
+	tk.MustExec("SELECT /*+ SET_VAR(tidb_opt_agg_push_down=1) SET_VAR(tidb_mem_quota_query=1024) */ 1;")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 0)
+	c.Assert(tk.Se.GetSessionVars().AllowAggPushDown, IsFalse)
+	c.Assert(tk.Se.GetSessionVars().MemQuotaQuery, Equals, int64(config.GetGlobalConfig().MemQuotaQuery))
+
+	tk.MustExec("SELECT /*+ SET_VAR(tidb_snapshot = '2020-01-01 00:00:00') */ 1;")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings(), HasLen, 1)
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.GetWarnings()[0].Err.Error(), Equals, "[planner:3637]Variable 'tidb_snapshot' cannot be set using SET_VAR hint.")
+	c.Assert(tk.Se.GetSessionVars().SnapshotTS, Equals, uint64(0))
+
+	// The hinted value should be restored even if the statement fails.
+	_, err := tk.Exec("SELECT /*+ SET_VAR(tidb_executor_concurrency=1) */ * from t_not_exists;")
+	c.Assert(err, NotNil)
+	c.Assert(tk.Se.GetSessionVars().ExecutorConcurrency, Equals, 5)
+
+	// The SET_VAR hints in the prepared statements should take effect on every execution.
+	tk.MustExec("prepare stmt from 'SELECT /*+ SET_VAR(tidb_executor_concurrency=2) */ @@tidb_executor_concurrency'")
+	tk.MustQuery("execute stmt").Check(testkit.Rows("2"))
+	c.Assert(tk.Se.GetSessionVars().ExecutorConcurrency, Equals, 5)
+	tk.MustQuery("execute stmt").Check(testkit.Rows("2"))
+	c.Assert(tk.Se.GetSessionVars().ExecutorConcurrency, Equals, 5)
+	tk.MustExec("deallocate prepare stmt")
 }
 
 // TestDeprecateSlowLogMasking should be in serial suite because it changes a global variable.
@@ -4243,3 +4273,160 @@ func (s *testSessionSerialSuite) TestParseWithParams(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(sb.String(), Equals, "SELECT 3")
 }
+
+func (s *testSessionSuite3) TestSessionStates(c *C) {
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	tk1.MustExec("drop table if exists session_states_t")
+	tk1.MustExec("create table session_states_t (a int primary key, b varchar(10))")
+	tk1.MustExec("insert into session_states_t values (1, 'a'), (2, 'b')")
+	tk1.MustExec("set @i = 1, @s = 'abc', @d = 1.5, @t = timestamp('2021-01-01 12:00:00.123'), @j = json_object('a', 1), @n = null")
+	tk1.MustExec("set @@session.tidb_opt_agg_push_down = 1, @@session.sql_mode = 'ANSI_QUOTES'")
+	tk1.MustExec("prepare s1 from 'select b from session_states_t where a = ?'")
+	stmtID, _, _, err := tk1.Se.PrepareStmt("select b from session_states_t where a = 2")
+	c.Assert(err, IsNil)
+	tk1.MustQuery("select * from session_states_t")
+	states := tk1.MustQuery("select @@tidb_session_states").Rows()[0][0].(string)
+
+	// The session states can't be encoded or decoded in a transaction.
+	tk1.MustExec("begin")
+	_, err = tk1.Exec("select @@tidb_session_states")
+	c.Assert(err, ErrorMatches, ".*in a transaction.*")
+	tk1.MustExec("rollback")
+
+	tk2 := testkit.NewTestKitWithInit(c, s.store)
+	tk2.MustExec("use mysql")
+	tk2.MustExec(sqlexec.MustEscapeSQL("set @@tidb_session_states = %?", states))
+	tk2.MustQuery("select found_rows()").Check(testkit.Rows("2"))
+	tk2.MustQuery("select database()").Check(testkit.Rows("test"))
+	tk2.MustQuery("select @i, @s, @d, @t, @j, @n").Check(testkit.Rows(`1 abc 1.5 2021-01-01 12:00:00.123 {"a": 1} <nil>`))
+	tk2.MustQuery("select @i + 1, @d + 1").Check(testkit.Rows("2 2.5"))
+	tk2.MustQuery("select @@tidb_opt_agg_push_down, @@sql_mode").Check(testkit.Rows("1 ANSI_QUOTES"))
+	tk2.MustExec("set @a = 1")
+	tk2.MustQuery("execute s1 using @a").Check(testkit.Rows("a"))
+	rs, err := tk2.Se.ExecutePreparedStmt(context.Background(), stmtID, nil)
+	c.Assert(err, IsNil)
+	tk2.ResultSetToResult(rs, Commentf("execute the restored binary prepared statement")).Check(testkit.Rows("b"))
+	// The new prepared statements don't conflict with the restored ones.
+	newStmtID, _, _, err := tk2.Se.PrepareStmt("select 1")
+	c.Assert(newStmtID > stmtID, IsTrue)
+	c.Assert(err, IsNil)
+
+	_, err = tk2.Exec("set @@tidb_session_states = 'invalid'")
+	c.Assert(err, ErrorMatches, ".*invalid session states.*")
+	// The prepared statements conflict with the existing ones.
+	_, err = tk2.Exec(sqlexec.MustEscapeSQL("set @@tidb_session_states = %?", states))
+	c.Assert(err, ErrorMatches, ".*already exists.*")
+}
+
+func (s *testSessionSuite3) TestSessionStatesUseAndSet(c *C) {
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	tk1.MustExec("create database if not exists session_states_db")
+	tk1.MustExec("create user 'session_states_user'@'%'")
+	defer tk1.MustExec("drop user 'session_states_user'@'%'")
+	tk1.MustExec("use session_states_db")
+	states := tk1.MustQuery("select @@tidb_session_states").Rows()[0][0].(string)
+
+	// The current database is restored by USE, which checks the privilege.
+	tk2 := testkit.NewTestKitWithInit(c, s.store)
+	c.Assert(tk2.Se.Auth(&auth.UserIdentity{Username: "session_states_user", Hostname: "%"}, nil, nil), IsTrue)
+	_, err := tk2.Exec(sqlexec.MustEscapeSQL("set @@tidb_session_states = %?", states))
+	c.Assert(err, ErrorMatches, ".*Access denied for user 'session_states_user'@'%' to database 'session_states_db'.*")
+
+	// tidb_snapshot is restored by SET, which loads the snapshot info schema.
+	// For mocktikv, safe point is not initialized, we manually insert it for snapshot to use.
+	tk1.MustExec(`insert into mysql.tidb values ('tikv_gc_safe_point', '20060102-15:04:05 -0700', '')
+		on duplicate key update variable_value = '20060102-15:04:05 -0700'`)
+	tk1.MustExec("create table session_states_t1 (a int)")
+	time.Sleep(time.Millisecond)
+	snapshotTime := time.Now()
+	time.Sleep(time.Millisecond)
+	tk1.MustExec("create table session_states_t2 (a int)")
+	tk1.MustExec("set @@tidb_snapshot = '" + snapshotTime.Format("2006-01-02 15:04:05.999999") + "'")
+	states = tk1.MustQuery("select @@tidb_session_states").Rows()[0][0].(string)
+	tk1.MustExec("set @@tidb_snapshot = ''")
+
+	tk3 := testkit.NewTestKitWithInit(c, s.store)
+	tk3.MustExec(sqlexec.MustEscapeSQL("set @@tidb_session_states = %?", states))
+	tk3.MustQuery("select database()").Check(testkit.Rows("session_states_db"))
+	c.Assert(tk3.Se.GetSessionVars().SnapshotTS, Greater, uint64(0))
+	tk3.MustQuery("select * from session_states_t1").Check(testkit.Rows())
+	_, err = tk3.Exec("select * from session_states_t2")
+	c.Assert(err, ErrorMatches, ".*doesn't exist.*")
+	tk3.MustExec("set @@tidb_snapshot = ''")
+	tk3.MustQuery("select * from session_states_t2").Check(testkit.Rows())
+}
+
+func (s *testSessionSuite3) TestXATransaction(c *C) {
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	tk2 := testkit.NewTestKitWithInit(c, s.store)
+	tk1.MustExec("drop table if exists xa_t")
+	tk1.MustExec("create table xa_t (a int primary key, b int)")
+
+	// Prepare the branch in a session and commit it in another session.
+	tk1.MustExec("xa start 'xa_gtrid', 'b1'")
+	_, err := tk2.Exec("xa start 'xa_gtrid', 'b1'")
+	c.Assert(session.ErrXaerDupid.Equal(err), IsTrue)
+	tk1.MustExec("insert into xa_t values (1, 1), (2, 2)")
+	_, err = tk1.Exec("commit")
+	c.Assert(session.ErrXaerRmfail.Equal(err), IsTrue)
+	_, err = tk1.Exec("create table xa_t1 (a int)")
+	c.Assert(session.ErrXaerRmfail.Equal(err), IsTrue)
+	_, err = tk1.Exec("xa prepare 'xa_gtrid', 'b1'")
+	c.Assert(session.ErrXaerRmfail.Equal(err), IsTrue)
+	_, err = tk1.Exec("xa end 'other'")
+	c.Assert(session.ErrXaerNota.Equal(err), IsTrue)
+	tk1.MustExec("xa end 'xa_gtrid', 'b1'")
+	_, err = tk1.Exec("select * from xa_t")
+	c.Assert(session.ErrXaerRmfail.Equal(err), IsTrue)
+	tk1.MustExec("xa prepare 'xa_gtrid', 'b1'")
+	tk2.MustQuery("xa recover").Check(testkit.Rows("1 8 2 xa_gtridb1"))
+	tk2.MustQuery("xa recover convert xid").Check(testkit.Rows("1 8 2 0x78615F67747269646231"))
+	_, err = tk1.Exec("xa commit 'other', '', 2")
+	c.Assert(session.ErrXaerNota.Equal(err), IsTrue)
+	tk2.MustExec("xa commit 'xa_gtrid', 'b1'")
+	tk2.MustQuery("select * from xa_t").Check(testkit.Rows("1 1", "2 2"))
+	_, err = tk2.Exec("xa commit 'xa_gtrid', 'b1'")
+	c.Assert(session.ErrXaerNota.Equal(err), IsTrue)
+	tk2.MustQuery("xa recover").Check(testkit.Rows())
+	// The prepared branch is detached from the session preparing it.
+	tk1.MustQuery("select * from xa_t").Check(testkit.Rows("1 1", "2 2"))
+
+	// Roll back the prepared branch in the session preparing it.
+	tk1.MustExec("xa begin 'xa_gtrid'")
+	tk1.MustExec("update xa_t set b = 10 where a = 1")
+	tk1.MustExec("xa end 'xa_gtrid'")
+	tk1.MustExec("xa prepare 'xa_gtrid'")
+	tk1.MustExec("xa rollback 'xa_gtrid'")
+	tk1.MustQuery("select * from xa_t").Check(testkit.Rows("1 1", "2 2"))
+
+	// Commit or roll back the ended branch directly.
+	tk1.MustExec("xa start 'xa_gtrid'")
+	tk1.MustExec("delete from xa_t where a = 2")
+	tk1.MustExec("xa end 'xa_gtrid'")
+	tk1.MustExec("xa commit 'xa_gtrid' one phase")
+	tk1.MustQuery("select * from xa_t").Check(testkit.Rows("1 1"))
+	tk1.MustExec("xa start 'xa_gtrid'")
+	tk1.MustExec("insert into xa_t values (3, 3)")
+	_, err = tk1.Exec("xa rollback 'xa_gtrid'")
+	c.Assert(session.ErrXaerRmfail.Equal(err), IsTrue)
+	tk1.MustExec("xa end 'xa_gtrid'")
+	tk1.MustExec("xa rollback 'xa_gtrid'")
+	tk1.MustQuery("select * from xa_t").Check(testkit.Rows("1 1"))
+
+	// The read-only branch is prepared without keys.
+	tk1.MustExec("xa start 'xa_gtrid'")
+	tk1.MustQuery("select * from xa_t").Check(testkit.Rows("1 1"))
+	tk1.MustExec("xa end 'xa_gtrid'")
+	tk1.MustExec("xa prepare 'xa_gtrid'")
+	tk1.MustExec("xa commit 'xa_gtrid'")
+
+	// The invalid XIDs and the XA transactions in the normal transactions are rejected.
+	_, err = tk1.Exec("xa start ''")
+	c.Assert(session.ErrXaerInval.Equal(err), IsTrue)
+	_, err = tk1.Exec(fmt.Sprintf("xa start '%s'", strings.Repeat("a", 65)))
+	c.Assert(session.ErrXaerInval.Equal(err), IsTrue)
+	tk1.MustExec("begin")
+	_, err = tk1.Exec("xa start 'xa_gtrid'")
+	c.Assert(session.ErrXaerOutside.Equal(err), IsTrue)
+	tk1.MustExec("rollback")
+}
@@ -353,4 +353,10 @@ func ResultSetToStringSlice(ctx context.Context, s Session, rs sqlexec.RecordSet
 // Session errors.
 var (
 	ErrForUpdateCantRetry = dbterror.ClassSession.NewStd(errno.ErrForUpdateCantRetry)
+	ErrXaerNota           = dbterror.ClassSession.NewStd(errno.ErrXaerNota)
+	ErrXaerInval          = dbterror.ClassSession.NewStd(errno.ErrXaerInval)
+	ErrXaerRmfail         = dbterror.ClassSession.NewStd(errno.ErrXaerRmfail)
+	ErrXaerOutside        = dbterror.ClassSession.NewStd(errno.ErrXaerOutside)
+	ErrXaerDupid          = dbterror.ClassSession.NewStd(errno.ErrXaerDupid)
+	ErrMustChangePassword = dbterror.ClassSession.NewStd(errno.ErrMustChangePassword)
 )
@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -240,3 +241,35 @@ func (s *testMainSuite) TestIndexUsageSyncLease(c *C) {
 	err = store.Close()
 	c.Assert(err, IsNil)
 }
+
+func (s *testMainSuite) TestPreparedXAAfterRestart(c *C) {
+	ctx := context.Background()
+	se, err := createSession(s.store)
+	c.Assert(err, IsNil)
+	mustExecSQL(c, se, "create table test.xa_restart (a int primary key)")
+	for i, commit := range []bool{true, false} {
+		xid := ast.XID{FormatID: 1, GTRID: "xa_restart", BQUAL: strconv.Itoa(i)}
+		mustExecSQL(c, se, fmt.Sprintf("xa start 'xa_restart', '%d'", i))
+		mustExecSQL(c, se, fmt.Sprintf("insert into test.xa_restart values (%d)", i))
+		mustExecSQL(c, se, fmt.Sprintf("xa end 'xa_restart', '%d'", i))
+		mustExecSQL(c, se, fmt.Sprintf("xa prepare 'xa_restart', '%d'", i))
+		// Forget the prepared transaction to simulate the tidb-server restarts, it's finished by its primary key.
+		xaBranches.Lock()
+		c.Assert(xaBranches.prepared, HasKey, xid)
+		delete(xaBranches.prepared, xid)
+		xaBranches.Unlock()
+		if commit {
+			mustExecSQL(c, se, fmt.Sprintf("xa commit 'xa_restart', '%d'", i))
+		} else {
+			mustExecSQL(c, se, fmt.Sprintf("xa rollback 'xa_restart', '%d'", i))
+		}
+	}
+	r := mustExecSQL(c, se, "select * from test.xa_restart")
+	c.Assert(r, NotNil)
+	req := r.NewChunk()
+	c.Assert(r.Next(ctx, req), IsNil)
+	c.Assert(req.NumRows(), Equals, 1)
+	c.Assert(req.GetRow(0).GetInt64(0), Equals, int64(0))
+	c.Assert(r.Close(), IsNil)
+	mustExecSQL(c, se, "drop table test.xa_restart")
+}
@@ -19,7 +19,9 @@ import (
 	"fmt"
 	"runtime/trace"
 	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/errors"
@@ -35,6 +37,7 @@ import (
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/sli"
+	"github.com/pingcap/tidb/util/txninfo"
 	"github.com/pingcap/tipb/go-binlog"
 	"go.uber.org/zap"
 )
@@ -55,6 +58,91 @@ type TxnState struct {
 	stagingHandle kv.StagingHandle
 	mutations     map[int64]*binlog.TableMutation
 	writeSLI      sli.TxnWriteThroughputSLI
+
+	// mu protects the information of the transaction, which is read by the other goroutines.
+	mu struct {
+		sync.Mutex
+		txnInfo txninfo.TxnInfo
+	}
+}
+
+// TxnInfo returns the information of the transaction, it returns nil if the transaction is not valid.
+func (txn *TxnState) TxnInfo() *txninfo.TxnInfo {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	if txn.mu.txnInfo.StartTS == 0 {
+		return nil
+	}
+	info := txn.mu.txnInfo
+	info.LockWaits = append([]txninfo.LockWait(nil), info.LockWaits...)
+	return &info
+}
+
+func (txn *TxnState) onTxnStart(startTS uint64) {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	txn.mu.txnInfo.StartTS = startTS
+	txn.mu.txnInfo.State = txninfo.TxnRunning
+	txn.mu.txnInfo.EntriesCount, txn.mu.txnInfo.EntriesSize = 0, 0
+}
+
+func (txn *TxnState) onTxnEnd() {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	txn.mu.txnInfo.StartTS = 0
+}
+
+// onStmtStart records the statement which is being executed in the transaction. The statement is recorded even if
+// the transaction is not valid, because the statement may activate the transaction.
+func (txn *TxnState) onStmtStart(digestText, digest string) {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	txn.mu.txnInfo.CurrentSQLDigest = digest
+	txn.mu.txnInfo.CurrentSQLDigestText = digestText
+}
+
+func (txn *TxnState) updateState(state txninfo.TxnRunningState) {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	txn.mu.txnInfo.State = state
+	if state == txninfo.TxnLockWaiting {
+		txn.mu.txnInfo.BlockStartTime = time.Now()
+	} else {
+		txn.mu.txnInfo.BlockStartTime = time.Time{}
+		txn.mu.txnInfo.LockWaits = nil
+	}
+}
+
+func (txn *TxnState) updateEntries(count, size int) {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	txn.mu.txnInfo.EntriesCount = uint64(count)
+	txn.mu.txnInfo.EntriesSize = uint64(size)
+}
+
+func (txn *TxnState) onLockWait(key []byte, holdingTxnID uint64) {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	for i := range txn.mu.txnInfo.LockWaits {
+		if bytes.Equal(txn.mu.txnInfo.LockWaits[i].Key, key) {
+			txn.mu.txnInfo.LockWaits[i].HoldingTxnID = holdingTxnID
+			return
+		}
+	}
+	txn.mu.txnInfo.LockWaits = append(txn.mu.txnInfo.LockWaits, txninfo.LockWait{
+		Key:          append([]byte(nil), key...),
+		HoldingTxnID: holdingTxnID,
+	})
+}
+
+// LockKeys overrides the Transaction interface, the transaction is in the lock waiting state until it returns.
+func (txn *TxnState) LockKeys(ctx context.Context, lockCtx *kv.LockCtx, keys ...kv.Key) error {
+	txn.updateState(txninfo.TxnLockWaiting)
+	defer txn.updateState(txninfo.TxnRunning)
+	if lockCtx.OnLockWait == nil {
+		lockCtx.OnLockWait = txn.onLockWait
+	}
+	return txn.Transaction.LockKeys(ctx, lockCtx, keys...)
 }
 
 // GetTableInfo returns the cached index name.
@@ -95,6 +183,7 @@ func (txn *TxnState) flushStmtBuf() {
 	buf := txn.Transaction.GetMemBuffer()
 	buf.Release(txn.stagingHandle)
 	txn.initCnt = buf.Len()
+	txn.updateEntries(buf.Len(), txn.Transaction.Size())
 }
 
 func (txn *TxnState) cleanupStmtBuf() {
@@ -161,6 +250,7 @@ func (txn *TxnState) changeInvalidToValid(kvTxn kv.Transaction) {
 	txn.Transaction = kvTxn
 	txn.initStmtBuf()
 	txn.txnFuture = nil
+	txn.onTxnStart(kvTxn.StartTS())
 }
 
 func (txn *TxnState) changeInvalidToPending(future *txnFuture) {
@@ -184,6 +274,7 @@ func (txn *TxnState) changePendingToValid(ctx context.Context) error {
 	}
 	txn.Transaction = t
 	txn.initStmtBuf()
+	txn.onTxnStart(t.StartTS())
 	return nil
 }
 
@@ -194,6 +285,7 @@ func (txn *TxnState) changeToInvalid() {
 	txn.stagingHandle = kv.InvalidStagingHandle
 	txn.Transaction = nil
 	txn.txnFuture = nil
+	txn.onTxnEnd()
 }
 
 var hasMockAutoIncIDRetry = int64(0)
@@ -225,6 +317,7 @@ func ResetMockAutoRandIDRetryCount(failTimes int64) {
 // Commit overrides the Transaction interface.
 func (txn *TxnState) Commit(ctx context.Context) error {
 	defer txn.reset()
+	txn.updateState(txninfo.TxnCommitting)
 	if len(txn.mutations) != 0 || txn.countHint() != 0 {
 		logutil.BgLogger().Error("the code should never run here",
 			zap.String("TxnState", txn.GoString()),
@@ -258,9 +351,17 @@ func (txn *TxnState) Commit(ctx context.Context) error {
 	return txn.Transaction.Commit(ctx)
 }
 
+// RevertToMemCheckpoint overrides the Transaction interface.
+func (txn *TxnState) RevertToMemCheckpoint(cp kv.MemCheckpoint) {
+	txn.flushStmtBuf()
+	txn.Transaction.RevertToMemCheckpoint(cp)
+	txn.cleanup()
+}
+
 // Rollback overrides the Transaction interface.
 func (txn *TxnState) Rollback() error {
 	defer txn.reset()
+	txn.updateState(txninfo.TxnRollingBack)
 	return txn.Transaction.Rollback()
 }
 
@@ -0,0 +1,337 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/kv"
+	tikvstore "github.com/pingcap/tidb/store/tikv/kv"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+func validateXID(xid ast.XID) error {
+	if len(xid.GTRID) == 0 || len(xid.GTRID) > 64 || len(xid.BQUAL) > 64 {
+		return ErrXaerInval.GenWithStackByArgs()
+	}
+	return nil
+}
+
+// xaState is the state of the XA transaction branch of a session.
+type xaState int
+
+const (
+	xaStateNone xaState = iota
+	xaStateActive
+	xaStateIdle
+)
+
+// String implements the fmt.Stringer interface.
+func (s xaState) String() string {
+	switch s {
+	case xaStateActive:
+		return "ACTIVE"
+	case xaStateIdle:
+		return "IDLE"
+	}
+	return "NON-EXISTING"
+}
+
+// xaBranches records the XA transaction branches started or prepared on this tidb-server.
+var xaBranches = struct {
+	sync.Mutex
+	// active is the branches started but not prepared yet, it's used to reject the duplicated XIDs.
+	active map[ast.XID]struct{}
+	// prepared is the branches prepared on this tidb-server, they are committed or rolled back with all of their
+	// keys. The branches prepared before the tidb-server restarts are finished by their primary keys instead.
+	prepared map[ast.XID]kv.PreparedTransaction
+}{
+	active:   make(map[ast.XID]struct{}),
+	prepared: make(map[ast.XID]kv.PreparedTransaction),
+}
+
+// XAStart implements the sqlexec.XATxnExecutor interface. It starts an XA transaction branch with the xid in the
+// session, the statements executed after it belong to the branch until XAEnd is called.
+func (s *session) XAStart(ctx context.Context, xid ast.XID) error {
+	if s.xa.state != xaStateNone {
+		return ErrXaerRmfail.GenWithStackByArgs(s.xa.state.String())
+	}
+	if s.sessionVars.InTxn() {
+		return ErrXaerOutside.GenWithStackByArgs()
+	}
+	if s.sessionVars.BinlogClient != nil {
+		return errors.New("XA transactions are not supported when binlog is enabled")
+	}
+	if err := validateXID(xid); err != nil {
+		return err
+	}
+	xaBranches.Lock()
+	_, ok := xaBranches.active[xid]
+	if !ok {
+		xaBranches.active[xid] = struct{}{}
+	}
+	xaBranches.Unlock()
+	if ok {
+		return ErrXaerDupid.GenWithStackByArgs()
+	}
+	if err := s.beginXA(ctx, xid); err != nil {
+		releaseActiveXA(xid)
+		return err
+	}
+	s.xa.xid, s.xa.state = xid, xaStateActive
+	return nil
+}
+
+func (s *session) beginXA(ctx context.Context, xid ast.XID) error {
+	rows, err := s.execXARegistrySQL(ctx, "select 1 from mysql.xa_transactions where format_id = %? and gtrid = %? and bqual = %?",
+		xid.FormatID, xid.GTRID, xid.BQUAL)
+	if err != nil {
+		return err
+	}
+	if len(rows) > 0 {
+		return ErrXaerDupid.GenWithStackByArgs()
+	}
+	if err = s.NewTxn(ctx); err != nil {
+		return err
+	}
+	s.sessionVars.SetInTxn(true)
+	if s.sessionVars.TxnMode == ast.Pessimistic {
+		s.sessionVars.TxnCtx.IsPessimistic = true
+	}
+	txn, err := s.Txn(true)
+	if err != nil {
+		return err
+	}
+	if s.sessionVars.TxnCtx.IsPessimistic {
+		txn.SetOption(tikvstore.Pessimistic, true)
+	}
+	// The branch is never retried, because the statement history isn't kept after it's prepared.
+	s.sessionVars.TxnCtx.CouldRetry = false
+	return nil
+}
+
+// XAEnd implements the sqlexec.XATxnExecutor interface. It ends the statements of the XA transaction branch, the
+// branch can only be prepared, committed in one phase or rolled back after it.
+func (s *session) XAEnd(xid ast.XID) error {
+	if err := s.checkXAState(xid, xaStateActive); err != nil {
+		return err
+	}
+	s.xa.state = xaStateIdle
+	return nil
+}
+
+// XAPrepare implements the sqlexec.XATxnExecutor interface. It prewrites the transaction and records the branch in
+// mysql.xa_transactions, so the branch can be committed or rolled back by any session, even after the tidb-server
+// restarts. The prepared branch is detached from the session, like xa_detach_on_prepare of MySQL.
+func (s *session) XAPrepare(ctx context.Context, xid ast.XID) error {
+	if err := s.checkXAState(xid, xaStateIdle); err != nil {
+		return err
+	}
+	var (
+		prepared kv.PreparedTransaction
+		startTS  uint64
+	)
+	if s.txn.Valid() {
+		startTS = s.txn.StartTS()
+		if !s.txn.IsReadOnly() {
+			var ok bool
+			if prepared, ok = s.txn.Transaction.(kv.PreparedTransaction); !ok {
+				s.RollbackTxn(ctx)
+				s.endXA()
+				return errors.New("XA transactions are not supported by the storage")
+			}
+			s.txn.SetOption(tikvstore.XAPrepare, true)
+		}
+	}
+	if err := s.CommitTxn(ctx); err != nil {
+		s.endXA()
+		return err
+	}
+	var primary []byte
+	if prepared != nil {
+		primary = prepared.PreparedPrimary()
+	}
+	cfg := config.GetGlobalConfig()
+	instance := net.JoinHostPort(cfg.AdvertiseAddress, strconv.Itoa(int(cfg.Port)))
+	_, err := s.execXARegistrySQL(ctx, "insert into mysql.xa_transactions (format_id, gtrid, bqual, start_ts, primary_key, instance) values (%?, %?, %?, %?, %?, %?)",
+		xid.FormatID, xid.GTRID, xid.BQUAL, startTS, primary, instance)
+	if err != nil {
+		if primary != nil {
+			if err1 := prepared.RollbackPrepared(ctx); err1 != nil {
+				logutil.Logger(ctx).Warn("rollback the XA transaction failed", zap.Stringer("xid", xid), zap.Error(err1))
+			}
+		}
+		s.endXA()
+		return err
+	}
+	xaBranches.Lock()
+	if primary != nil {
+		xaBranches.prepared[xid] = prepared
+	}
+	xaBranches.Unlock()
+	s.endXA()
+	return nil
+}
+
+// XACommit implements the sqlexec.XATxnExecutor interface. It commits the prepared XA transaction branch, which can
+// be prepared by any session. The branch ended but not prepared in the session is committed directly if onePhase is
+// true.
+func (s *session) XACommit(ctx context.Context, xid ast.XID, onePhase bool) error {
+	if onePhase {
+		if err := s.checkXAState(xid, xaStateIdle); err != nil {
+			return err
+		}
+		defer s.endXA()
+		return s.CommitTxn(ctx)
+	}
+	return s.finishPreparedXA(ctx, xid, true)
+}
+
+// XARollback implements the sqlexec.XATxnExecutor interface. It rolls back the XA transaction branch, which is
+// either ended in the session or prepared by any session.
+func (s *session) XARollback(ctx context.Context, xid ast.XID) error {
+	if s.xa.state != xaStateNone {
+		if err := s.checkXAState(xid, xaStateIdle); err != nil {
+			return err
+		}
+		s.RollbackTxn(ctx)
+		s.endXA()
+		return nil
+	}
+	return s.finishPreparedXA(ctx, xid, false)
+}
+
+// XARecover implements the sqlexec.XATxnExecutor interface. It returns the XIDs of the prepared XA transaction
+// branches.
+func (s *session) XARecover(ctx context.Context) ([]ast.XID, error) {
+	rows, err := s.execXARegistrySQL(ctx, "select format_id, gtrid, bqual from mysql.xa_transactions order by prepare_time, format_id, gtrid, bqual")
+	if err != nil {
+		return nil, err
+	}
+	xids := make([]ast.XID, 0, len(rows))
+	for _, row := range rows {
+		xids = append(xids, ast.XID{FormatID: row.GetInt64(0), GTRID: string(row.GetBytes(1)), BQUAL: string(row.GetBytes(2))})
+	}
+	return xids, nil
+}
+
+func (s *session) finishPreparedXA(ctx context.Context, xid ast.XID, commit bool) error {
+	if s.xa.state != xaStateNone {
+		return ErrXaerRmfail.GenWithStackByArgs(s.xa.state.String())
+	}
+	rows, err := s.execXARegistrySQL(ctx, "select start_ts, primary_key from mysql.xa_transactions where format_id = %? and gtrid = %? and bqual = %?",
+		xid.FormatID, xid.GTRID, xid.BQUAL)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return ErrXaerNota.GenWithStackByArgs()
+	}
+	startTS, primary := rows[0].GetUint64(0), rows[0].GetBytes(1)
+
+	xaBranches.Lock()
+	prepared := xaBranches.prepared[xid]
+	delete(xaBranches.prepared, xid)
+	xaBranches.Unlock()
+	switch {
+	case prepared != nil && commit:
+		err = prepared.CommitPrepared(ctx)
+	case prepared != nil:
+		err = prepared.RollbackPrepared(ctx)
+	case !rows[0].IsNull(1):
+		store, ok := s.store.(kv.PreparedTxnStorage)
+		if !ok {
+			return errors.New("XA transactions are not supported by the storage")
+		}
+		if commit {
+			err = store.CommitPreparedTxn(ctx, primary, startTS)
+		} else {
+			err = store.RollbackPreparedTxn(ctx, primary, startTS)
+		}
+	}
+	if err != nil {
+		if prepared != nil {
+			xaBranches.Lock()
+			xaBranches.prepared[xid] = prepared
+			xaBranches.Unlock()
+		}
+		return err
+	}
+	_, err = s.execXARegistrySQL(ctx, "delete from mysql.xa_transactions where format_id = %? and gtrid = %? and bqual = %?",
+		xid.FormatID, xid.GTRID, xid.BQUAL)
+	return err
+}
+
+// checkXAState checks the XA transaction branch of the session is the xid in the state.
+func (s *session) checkXAState(xid ast.XID, state xaState) error {
+	if s.xa.state != state {
+		return ErrXaerRmfail.GenWithStackByArgs(s.xa.state.String())
+	}
+	if s.xa.xid != xid {
+		return ErrXaerNota.GenWithStackByArgs()
+	}
+	return nil
+}
+
+// checkXAStmt checks the statement can be executed in the XA transaction branch of the session.
+func (s *session) checkXAStmt(stmtNode ast.StmtNode) error {
+	if s.sessionVars.InRestrictedSQL {
+		return nil
+	}
+	// The XA statements check the state by themselves.
+	if _, ok := stmtNode.(*ast.XAStmt); ok {
+		return nil
+	}
+	switch s.xa.state {
+	case xaStateIdle:
+		return ErrXaerRmfail.GenWithStackByArgs(s.xa.state.String())
+	case xaStateActive:
+		switch stmtNode.(type) {
+		case *ast.BeginStmt, *ast.CommitStmt, *ast.RollbackStmt, ast.DDLNode:
+			return ErrXaerRmfail.GenWithStackByArgs(s.xa.state.String())
+		}
+	}
+	return nil
+}
+
+// endXA detaches the XA transaction branch from the session.
+func (s *session) endXA() {
+	if s.xa.state != xaStateNone {
+		releaseActiveXA(s.xa.xid)
+	}
+	s.xa.xid, s.xa.state = ast.XID{}, xaStateNone
+}
+
+func releaseActiveXA(xid ast.XID) {
+	xaBranches.Lock()
+	delete(xaBranches.active, xid)
+	xaBranches.Unlock()
+}
+
+func (s *session) execXARegistrySQL(ctx context.Context, sql string, args ...interface{}) ([]chunk.Row, error) {
+	stmt, err := s.ParseWithParams(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	rows, _, err := s.ExecRestrictedStmt(ctx, stmt)
+	return rows, err
+}
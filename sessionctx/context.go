@@ -85,6 +85,9 @@ type Context interface {
 	// StoreQueryFeedback stores the query feedback.
 	StoreQueryFeedback(feedback interface{})
 
+	// StorePredicateColumns stores the columns of the table used by the predicates, joins or group-by.
+	StorePredicateColumns(tableID int64, colIDs []int64)
+
 	// HasDirtyContent checks whether there's dirty update on the given table.
 	HasDirtyContent(tid int64) bool
 
@@ -0,0 +1,54 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessionstates
+
+import (
+	"github.com/pingcap/tidb/types"
+)
+
+// PreparedStmtInfo contains the information about prepared statements, both text and binary protocols.
+type PreparedStmtInfo struct {
+	Name     string `json:"name,omitempty"`
+	StmtText string `json:"text"`
+	StmtDB   string `json:"db,omitempty"`
+}
+
+// UserVar is the encoded user variable. The value is kept in the string form and parsed again by its kind when it's
+// restored, because the datum codec doesn't keep the kind of string, time and duration values.
+type UserVar struct {
+	Kind      byte   `json:"kind"`
+	Type      byte   `json:"type,omitempty"`
+	Fsp       int8   `json:"fsp,omitempty"`
+	Collation string `json:"collation,omitempty"`
+	Value     string `json:"value,omitempty"`
+}
+
+// PreparedStmtEncoder is implemented by the cached prepared statements, so that the session states can be encoded
+// without depending on the planner.
+type PreparedStmtEncoder interface {
+	// EncodePreparedStmt returns the text and the current database when the statement is prepared.
+	EncodePreparedStmt() (stmtText, stmtDB string)
+}
+
+// SessionStates contains all the states in the session that should be migrated when the session
+// is migrated to another server. It is shown by `select @@tidb_session_states` and restored by `set @@tidb_session_states`.
+type SessionStates struct {
+	UserVars       map[string]*UserVar          `json:"user-var-values,omitempty"`
+	UserVarTypes   map[string]*types.FieldType  `json:"user-var-types,omitempty"`
+	SystemVars     map[string]string            `json:"sys-vars,omitempty"`
+	PreparedStmts  map[uint32]*PreparedStmtInfo `json:"prepared-stmts,omitempty"`
+	PreparedStmtID uint32                       `json:"prepared-stmt-id,omitempty"`
+	CurrentDB      string                       `json:"current-db,omitempty"`
+	LastFoundRows  uint64                       `json:"last-found-rows,omitempty"`
+}
@@ -0,0 +1,108 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessionstates
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/config"
+)
+
+// TokenLifetime is how long a session token is valid after it is signed. The token is only used to
+// authenticate the migrated session, so it's supposed to be used immediately after it's fetched.
+// It's a variable so that it can be changed in tests.
+var TokenLifetime = time.Minute
+
+// SessionToken represents the token used to authenticate a migrated session on another tidb-server
+// without the password. The signature is an HMAC of the other fields, so all the tidb-servers in the
+// cluster must share the same signing key. The token is bound to the host of the client, so it can't
+// be used to log in from another host even if the user is allowed to.
+type SessionToken struct {
+	Username   string    `json:"username"`
+	Hostname   string    `json:"hostname"`
+	SignTime   time.Time `json:"sign-time"`
+	ExpireTime time.Time `json:"expire-time"`
+	Signature  []byte    `json:"signature,omitempty"`
+}
+
+// loadSigningKey reads the signing key from the file configured by security.session-token-signing-key.
+// The file is read every time so that the key can be rotated without restarting the tidb-server.
+func loadSigningKey() ([]byte, error) {
+	path := config.GetGlobalConfig().Security.SessionTokenSigningKey
+	if path == "" {
+		return nil, errors.New("session token is disabled because security.session-token-signing-key is not set")
+	}
+	key, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	key = []byte(strings.TrimSpace(string(key)))
+	if len(key) == 0 {
+		return nil, errors.Errorf("the session token signing key file %s is empty", path)
+	}
+	return key, nil
+}
+
+func (t *SessionToken) sign(key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%s\n%s\n%d\n%d", t.Username, t.Hostname, t.SignTime.UnixNano(), t.ExpireTime.UnixNano())
+	return mac.Sum(nil)
+}
+
+// CreateSessionToken creates a signed token for the user connected from the host.
+func CreateSessionToken(username, hostname string) (*SessionToken, error) {
+	key, err := loadSigningKey()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	token := &SessionToken{
+		Username:   username,
+		Hostname:   hostname,
+		SignTime:   now,
+		ExpireTime: now.Add(TokenLifetime),
+	}
+	token.Signature = token.sign(key)
+	return token, nil
+}
+
+// ValidateSessionToken checks whether the token is signed by the cluster, not expired and belongs to the user
+// connected from the host.
+func ValidateSessionToken(tokenBytes []byte, username, hostname string) error {
+	var token SessionToken
+	if err := json.Unmarshal(tokenBytes, &token); err != nil {
+		return errors.Annotate(err, "invalid session token")
+	}
+	key, err := loadSigningKey()
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal(token.sign(key), token.Signature) {
+		return errors.New("the signature of the session token is invalid")
+	}
+	if time.Now().After(token.ExpireTime) {
+		return errors.New("the session token is expired")
+	}
+	if token.Username != username || token.Hostname != hostname {
+		return errors.Errorf("the session token does not belong to user %s@%s", username, hostname)
+	}
+	return nil
+}
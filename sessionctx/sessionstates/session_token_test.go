@@ -0,0 +1,87 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessionstates
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/config"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testSessionTokenSuite{})
+
+type testSessionTokenSuite struct{}
+
+func (s *testSessionTokenSuite) TestSessionToken(c *C) {
+	defer config.RestoreFunc()()
+	dir, err := ioutil.TempDir("", "session-token")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	// The token is disabled without the signing key.
+	_, err = CreateSessionToken("root", "127.0.0.1")
+	c.Assert(err, ErrorMatches, ".*session token is disabled.*")
+
+	keyPath := filepath.Join(dir, "key")
+	c.Assert(ioutil.WriteFile(keyPath, []byte("secret\n"), 0600), IsNil)
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Security.SessionTokenSigningKey = keyPath
+	})
+	token, err := CreateSessionToken("root", "127.0.0.1")
+	c.Assert(err, IsNil)
+	tokenBytes, err := json.Marshal(token)
+	c.Assert(err, IsNil)
+	c.Assert(ValidateSessionToken(tokenBytes, "root", "127.0.0.1"), IsNil)
+	c.Assert(ValidateSessionToken(tokenBytes, "u1", "127.0.0.1"), ErrorMatches, ".*does not belong to user u1@127.0.0.1.*")
+	c.Assert(ValidateSessionToken(tokenBytes, "root", "192.168.0.1"), ErrorMatches, ".*does not belong to user root@192.168.0.1.*")
+	c.Assert(ValidateSessionToken([]byte("invalid"), "root", "127.0.0.1"), ErrorMatches, ".*invalid session token.*")
+
+	// The token is tampered.
+	tampered := *token
+	tampered.ExpireTime = tampered.ExpireTime.Add(time.Hour)
+	tamperedBytes, err := json.Marshal(&tampered)
+	c.Assert(err, IsNil)
+	c.Assert(ValidateSessionToken(tamperedBytes, "root", "127.0.0.1"), ErrorMatches, ".*signature.*invalid.*")
+	tampered = *token
+	tampered.Hostname = "192.168.0.1"
+	tamperedBytes, err = json.Marshal(&tampered)
+	c.Assert(err, IsNil)
+	c.Assert(ValidateSessionToken(tamperedBytes, "root", "192.168.0.1"), ErrorMatches, ".*signature.*invalid.*")
+
+	// The token is signed by another key.
+	c.Assert(ioutil.WriteFile(keyPath, []byte("another"), 0600), IsNil)
+	c.Assert(ValidateSessionToken(tokenBytes, "root", "127.0.0.1"), ErrorMatches, ".*signature.*invalid.*")
+
+	// The token is expired.
+	lifetime := TokenLifetime
+	TokenLifetime = -time.Second
+	defer func() {
+		TokenLifetime = lifetime
+	}()
+	token, err = CreateSessionToken("root", "127.0.0.1")
+	c.Assert(err, IsNil)
+	tokenBytes, err = json.Marshal(token)
+	c.Assert(err, IsNil)
+	c.Assert(ValidateSessionToken(tokenBytes, "root", "127.0.0.1"), ErrorMatches, ".*expired.*")
+}
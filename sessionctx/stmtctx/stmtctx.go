@@ -17,6 +17,7 @@ import (
 	"math"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -117,6 +118,10 @@ type StatementContext struct {
 		histogramsNotLoad bool
 		execDetails       execdetails.ExecDetails
 		allExecDetails    []*execdetails.ExecDetails
+		// implicitCasts records the implicit casts found when building the statement, in the order they are found.
+		implicitCasts []string
+		// usedStats records the versions of the stats of the tables used when compiling the statement.
+		usedStats map[int64]UsedStatsInfo
 	}
 	// PrevAffectedRows is the affected-rows value(DDL is 0, DML is the number of affected rows).
 	PrevAffectedRows int64
@@ -164,6 +169,16 @@ type StatementContext struct {
 	TblInfo2UnionScan     map[*model.TableInfo]bool
 	TaskID                uint64 // unique ID for an execution of a statement
 	TaskMapBakTS          uint64 // counter for
+	// BindSQLDigest and BindNormalizedSQL identify the bindings of the statement, they are only set when the plan
+	// of the statement may be cached.
+	BindSQLDigest     string
+	BindNormalizedSQL string
+	// DependOnRowPolicy is true if the statement reads or writes a table with row policies, its plan depends on the
+	// user and the active roles of the session, so it isn't cached.
+	DependOnRowPolicy bool
+	// DependOnMaskingRule is true if the statement reads a table with masking rules, its plan depends on whether the
+	// user has the UNMASK privilege, so it isn't cached.
+	DependOnMaskingRule bool
 }
 
 // StmtHints are SessionVars related sql hints.
@@ -460,6 +475,80 @@ func (sc *StatementContext) AppendError(warn error) {
 	sc.mu.Unlock()
 }
 
+// AppendImplicitCast records an implicit cast event of the statement. It returns false if the same event
+// has been recorded already.
+func (sc *StatementContext) AppendImplicitCast(event string) bool {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	for _, e := range sc.mu.implicitCasts {
+		if e == event {
+			return false
+		}
+	}
+	sc.mu.implicitCasts = append(sc.mu.implicitCasts, event)
+	return true
+}
+
+// GetImplicitCasts gets the implicit cast events recorded for the statement.
+func (sc *StatementContext) GetImplicitCasts() []string {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.mu.implicitCasts
+}
+
+// UsedStatsInfo is the versions of the stats of a table or a partition used when compiling a statement.
+type UsedStatsInfo struct {
+	// Name is the name of the table, or `table.partition` for a partition.
+	Name    string
+	TblInfo *model.TableInfo
+	// Version is the version of the stats meta, 0 means the pseudo stats are used.
+	Version uint64
+	// HistVersion is the version of the latest analyzed histograms.
+	HistVersion uint64
+}
+
+func (info UsedStatsInfo) String() string {
+	if info.Version == 0 {
+		return info.Name + ":pseudo"
+	}
+	return info.Name + ":" + strconv.FormatUint(info.Version, 10) + "[hist:" + strconv.FormatUint(info.HistVersion, 10) + "]"
+}
+
+// RecordUsedStats records the versions of the stats of the table or the partition used by the optimizer.
+func (sc *StatementContext) RecordUsedStats(physicalID int64, info UsedStatsInfo) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.mu.usedStats == nil {
+		sc.mu.usedStats = make(map[int64]UsedStatsInfo)
+	}
+	sc.mu.usedStats[physicalID] = info
+}
+
+// UsedStats returns the versions of the stats used when compiling the statement, keyed by the physical IDs.
+func (sc *StatementContext) UsedStats() map[int64]UsedStatsInfo {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	usedStats := make(map[int64]UsedStatsInfo, len(sc.mu.usedStats))
+	for id, info := range sc.mu.usedStats {
+		usedStats[id] = info
+	}
+	return usedStats
+}
+
+// UsedStatsString returns the versions of the stats used when compiling the statement, which are sorted by the names
+// of the tables, e.g. `t1:424373937491312641[hist:424373936114532353],t2:pseudo`. It returns an empty string if
+// no stats are used, e.g. the plan is fetched from the plan cache.
+func (sc *StatementContext) UsedStatsString() string {
+	sc.mu.Lock()
+	infos := make([]string, 0, len(sc.mu.usedStats))
+	for _, info := range sc.mu.usedStats {
+		infos = append(infos, info.String())
+	}
+	sc.mu.Unlock()
+	sort.Strings(infos)
+	return strings.Join(infos, ",")
+}
+
 // SetHistogramsNotLoad sets histogramsNotLoad.
 func (sc *StatementContext) SetHistogramsNotLoad() {
 	sc.mu.Lock()
@@ -511,6 +600,7 @@ func (sc *StatementContext) ResetForRetry() {
 	sc.mu.warnings = nil
 	sc.mu.execDetails = execdetails.ExecDetails{}
 	sc.mu.allExecDetails = make([]*execdetails.ExecDetails, 0, 4)
+	sc.mu.usedStats = nil
 	sc.mu.Unlock()
 	sc.MaxRowID = 0
 	sc.BaseRowID = 0
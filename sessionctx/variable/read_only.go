@@ -0,0 +1,67 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+import (
+	"sync"
+	"time"
+)
+
+// ReadOnlyState is the read-only state of the tidb-server, it's synced from the global variables
+// read_only, super_read_only and tidb_read_only_grace_period.
+type ReadOnlyState struct {
+	// ReadOnly rejects the writes of the users without the SUPER privilege.
+	ReadOnly bool
+	// SuperReadOnly rejects the writes of all the users.
+	SuperReadOnly bool
+	// Since is the time when the server turns read-only, or turns super read-only from read-only.
+	Since time.Time
+	// GracePeriod is how long the transactions started before Since can keep writing and committing.
+	GracePeriod time.Duration
+}
+
+// InGracePeriod checks whether a transaction started at txnStart can still write at now.
+func (s ReadOnlyState) InGracePeriod(txnStart, now time.Time) bool {
+	return txnStart.Before(s.Since) && now.Before(s.Since.Add(s.GracePeriod))
+}
+
+var readOnlyState = struct {
+	sync.RWMutex
+	ReadOnlyState
+}{ReadOnlyState: ReadOnlyState{GracePeriod: DefTiDBReadOnlyGracePeriod * time.Second}}
+
+// GetReadOnlyState returns the read-only state of the tidb-server.
+func GetReadOnlyState() ReadOnlyState {
+	readOnlyState.RLock()
+	defer readOnlyState.RUnlock()
+	return readOnlyState.ReadOnlyState
+}
+
+// SetReadOnly sets read_only and super_read_only, the super_read_only implies the read_only.
+func SetReadOnly(readOnly, superReadOnly bool) {
+	readOnly = readOnly || superReadOnly
+	readOnlyState.Lock()
+	defer readOnlyState.Unlock()
+	if (readOnly && !readOnlyState.ReadOnly) || (superReadOnly && !readOnlyState.SuperReadOnly) {
+		readOnlyState.Since = time.Now()
+	}
+	readOnlyState.ReadOnly, readOnlyState.SuperReadOnly = readOnly, superReadOnly
+}
+
+// SetReadOnlyGracePeriod sets the grace period of the in-flight transactions when the server turns read-only.
+func SetReadOnlyGracePeriod(gracePeriod time.Duration) {
+	readOnlyState.Lock()
+	defer readOnlyState.Unlock()
+	readOnlyState.GracePeriod = gracePeriod
+}
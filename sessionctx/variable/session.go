@@ -17,10 +17,12 @@ import (
 	"bytes"
 	"crypto/tls"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"math"
 	"math/rand"
 	"net"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -43,9 +45,11 @@ import (
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	tikvstore "github.com/pingcap/tidb/store/tikv/kv"
 	"github.com/pingcap/tidb/store/tikv/oracle"
+	"github.com/pingcap/tidb/table/temptable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/encoding"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/rowcodec"
@@ -53,6 +57,7 @@ import (
 	"github.com/pingcap/tidb/util/timeutil"
 	"github.com/twmb/murmur3"
 	atomic2 "go.uber.org/atomic"
+	"go.uber.org/zap"
 )
 
 // PreparedStmtCount is exported for test.
@@ -171,6 +176,8 @@ type TransactionContext struct {
 	ForUpdate  uint32
 	// TxnScope indicates the value of txn_scope
 	TxnScope string
+	// Savepoints are the savepoints set in the transaction, in the order they are set.
+	Savepoints []SavepointRecord
 
 	// TableDeltaMap lock to prevent potential data race
 	tdmLock sync.Mutex
@@ -260,6 +267,94 @@ func (tc *TransactionContext) SetPessimisticLockCache(key kv.Key, val []byte) {
 	tc.pessimisticLockCache[string(key)] = val
 }
 
+// SavepointRecord is the state of the transaction recorded by a savepoint.
+type SavepointRecord struct {
+	// Name is the name of the savepoint in lower case.
+	Name string
+	// MemCheckpoint is the checkpoint of the membuffer of the transaction.
+	MemCheckpoint kv.MemCheckpoint
+
+	tableDeltaMap        map[int64]TableDelta
+	pessimisticLockCache map[string][]byte
+}
+
+// AddSavepoint records the current state of the transaction as a savepoint. The savepoint with the same name is
+// replaced.
+func (tc *TransactionContext) AddSavepoint(name string, memCheckpoint kv.MemCheckpoint) {
+	name = strings.ToLower(name)
+	tc.ReleaseSavepoint(name)
+	record := SavepointRecord{Name: name, MemCheckpoint: memCheckpoint}
+	tc.tdmLock.Lock()
+	record.tableDeltaMap = copyTableDeltaMap(tc.TableDeltaMap)
+	tc.tdmLock.Unlock()
+	if tc.pessimisticLockCache != nil {
+		record.pessimisticLockCache = make(map[string][]byte, len(tc.pessimisticLockCache))
+		for key, val := range tc.pessimisticLockCache {
+			record.pessimisticLockCache[key] = val
+		}
+	}
+	tc.Savepoints = append(tc.Savepoints, record)
+}
+
+// RollbackToSavepoint restores the state of the transaction recorded by the savepoint, the savepoints set after it
+// are removed. The caller should revert the membuffer to the MemCheckpoint of the returned record. It returns nil if
+// the savepoint doesn't exist.
+func (tc *TransactionContext) RollbackToSavepoint(name string) *SavepointRecord {
+	idx := tc.findSavepoint(name)
+	if idx < 0 {
+		return nil
+	}
+	tc.Savepoints = tc.Savepoints[:idx+1]
+	record := &tc.Savepoints[idx]
+	tc.tdmLock.Lock()
+	tc.TableDeltaMap = copyTableDeltaMap(record.tableDeltaMap)
+	tc.tdmLock.Unlock()
+	tc.pessimisticLockCache = nil
+	for key, val := range record.pessimisticLockCache {
+		tc.SetPessimisticLockCache(kv.Key(key), val)
+	}
+	return record
+}
+
+// ReleaseSavepoint removes the savepoint and the savepoints set after it. It returns false if the savepoint doesn't
+// exist.
+func (tc *TransactionContext) ReleaseSavepoint(name string) bool {
+	idx := tc.findSavepoint(name)
+	if idx < 0 {
+		return false
+	}
+	tc.Savepoints = tc.Savepoints[:idx]
+	return true
+}
+
+func (tc *TransactionContext) findSavepoint(name string) int {
+	name = strings.ToLower(name)
+	for i := len(tc.Savepoints) - 1; i >= 0; i-- {
+		if tc.Savepoints[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func copyTableDeltaMap(m map[int64]TableDelta) map[int64]TableDelta {
+	if m == nil {
+		return nil
+	}
+	res := make(map[int64]TableDelta, len(m))
+	for id, item := range m {
+		if item.ColSize != nil {
+			colSize := make(map[int64]int64, len(item.ColSize))
+			for key, val := range item.ColSize {
+				colSize[key] = val
+			}
+			item.ColSize = colSize
+		}
+		res[id] = item
+	}
+	return res
+}
+
 // Cleanup clears up transaction info that no longer use.
 func (tc *TransactionContext) Cleanup() {
 	// tc.InfoSchema = nil; we cannot do it now, because some operation like handleFieldList depend on this.
@@ -269,6 +364,7 @@ func (tc *TransactionContext) Cleanup() {
 	tc.TableDeltaMap = nil
 	tc.tdmLock.Unlock()
 	tc.pessimisticLockCache = nil
+	tc.Savepoints = nil
 	tc.IsStaleness = false
 }
 
@@ -380,8 +476,8 @@ type SessionVars struct {
 	UserVarTypes map[string]*types.FieldType
 	// systems variables, don't modify it directly, use GetSystemVar/SetSystemVar method.
 	systems map[string]string
-	// stmtVars variables are temporarily set by SET_VAR hint
-	// It only take effect for the duration of a single statement
+	// stmtVars are the original values of the variables temporarily set by SET_VAR hint,
+	// they are restored when the statement ends.
 	stmtVars map[string]string
 	// SysWarningCount is the system variable "warning_count", because it is on the hot path, so we extract it from the systems
 	SysWarningCount int
@@ -438,6 +534,10 @@ type SessionVars struct {
 	// User is the user identity with which the session login.
 	User *auth.UserIdentity
 
+	// InSandBoxMode indicates the password of the user has expired, the session can only execute SET PASSWORD until
+	// the password is reset.
+	InSandBoxMode bool
+
 	// Port is the port of the connected socket
 	Port string
 
@@ -464,6 +564,13 @@ type SessionVars struct {
 	// version, we load an old version schema for query.
 	SnapshotInfoschema interface{}
 
+	// LocalTemporaryTables is *infoschema.LocalTemporaryTables, use interface to avoid circle dependency.
+	// It's nil if the session has never created a local temporary table.
+	LocalTemporaryTables interface{}
+
+	// TemporaryTableData keeps the committed data of the local temporary tables.
+	TemporaryTableData *temptable.Storage
+
 	// BinlogClient is used to write binlog.
 	BinlogClient *pumpcli.PumpsClient
 
@@ -596,6 +703,15 @@ type SessionVars struct {
 	// EnableVectorizedExpression  enables the vectorized expression evaluation.
 	EnableVectorizedExpression bool
 
+	// EnableExprCodegen enables compiling hot filters into fused closures.
+	EnableExprCodegen bool
+
+	// InvalidCharHandling is how the character set conversions handle the invalid characters.
+	InvalidCharHandling encoding.InvalidCharHandling
+
+	// EnableImplicitCastDiagnostics enables recording the implicit casts of the compared columns.
+	EnableImplicitCastDiagnostics bool
+
 	// DDLReorgPriority is the operation priority of adding indices.
 	DDLReorgPriority int
 
@@ -666,6 +782,10 @@ type SessionVars struct {
 	// ConnectionInfo indicates current connection info used by current session, only be lazy assigned by plugin.
 	ConnectionInfo *ConnectionInfo
 
+	// ConnectAttrs are the connection attributes sent by the client in the handshake, such as program_name and
+	// _client_version.
+	ConnectAttrs map[string]string
+
 	// use noop funcs or not
 	EnableNoopFuncs bool
 
@@ -769,6 +889,13 @@ type SessionVars struct {
 	// EnableClusteredIndex indicates whether to enable clustered index when creating a new table.
 	EnableClusteredIndex ClusteredIndexDefMode
 
+	// EnableGeneratedInvisiblePK indicates whether to add an invisible auto_random primary key
+	// to the tables created without a primary key.
+	EnableGeneratedInvisiblePK bool
+
+	// ShowGeneratedInvisiblePK indicates whether the generated invisible primary key is visible to users.
+	ShowGeneratedInvisiblePK bool
+
 	// PresumeKeyNotExists indicates lazy existence checking is enabled.
 	PresumeKeyNotExists bool
 
@@ -796,9 +923,37 @@ type SessionVars struct {
 	// TxnScope indicates the scope of the transactions. It should be `global` or equal to `dc-location` in configuration.
 	TxnScope oracle.TxnScope
 
+	// ReadStaleness indicates how stale the data read by the auto-commit read-only statements can be, 0 means the
+	// stale read is disabled.
+	ReadStaleness time.Duration
+
+	// IdleTxnTimeout is the timeout of the idle explicit transactions, 0 means no timeout.
+	IdleTxnTimeout time.Duration
+
+	// MaxTxnDuration is the max lifetime of the explicit transactions, 0 means no limit.
+	MaxTxnDuration time.Duration
+
+	// LoadDataLocalCompression is the compression of the files uploaded by LOAD DATA LOCAL INFILE.
+	LoadDataLocalCompression string
+
+	// PreSplitIndexRegions is the number of regions the index created by CREATE INDEX is pre-split into.
+	PreSplitIndexRegions int
+
+	// SkipResultsetMetadata indicates whether the column definitions of the result sets are skipped, it only takes
+	// effect if the client supports CLIENT_OPTIONAL_RESULTSET_METADATA.
+	SkipResultsetMetadata bool
+
+	// ResourceGroupName is the name of the resource group the session is bound to, the requests of the session are
+	// throttled by the quota of the resource group. It's empty if the session isn't bound to any resource group.
+	ResourceGroupName string
+
 	// EnabledRateLimitAction indicates whether enabled ratelimit action during coprocessor
 	EnabledRateLimitAction bool
 
+	// MemOOMActionChain is the OOM actions taken in order before the final action when the memory quota of a
+	// statement is exceeded.
+	MemOOMActionChain []string
+
 	// EnableAsyncCommit indicates whether to enable the async commit feature.
 	EnableAsyncCommit bool
 
@@ -808,9 +963,29 @@ type SessionVars struct {
 	// GuaranteeLinearizability indicates whether to guarantee linearizability
 	GuaranteeLinearizability bool
 
+	// PipelinedAutoCommit indicates whether to acknowledge the auto-commit statements once their keys are prewritten.
+	PipelinedAutoCommit bool
+
 	// AnalyzeVersion indicates how TiDB collect and use analyzed statistics.
 	AnalyzeVersion int
 
+	// AnalyzeSampleRate indicates the rate of rows sampled by analyze, 0 means the number of samples is fixed.
+	AnalyzeSampleRate float64
+
+	// ForceAnalyzeLockedStats indicates whether the manual analyze replaces the locked stats of the tables.
+	ForceAnalyzeLockedStats bool
+
+	// PersistAnalyzeOptions indicates whether the BUCKETS and TOPN options of analyze are saved for the tables and
+	// reused by the later analyze.
+	PersistAnalyzeOptions bool
+
+	// EnableColumnTracking indicates whether the columns used by the predicates, joins or group-by of the queries are
+	// recorded.
+	EnableColumnTracking bool
+
+	// AnalyzePredicateColumns indicates whether the manual analyze only collects the stats of the predicate columns.
+	AnalyzePredicateColumns bool
+
 	// EnableIndexMergeJoin indicates whether to enable index merge join.
 	EnableIndexMergeJoin bool
 
@@ -977,6 +1152,7 @@ func NewSessionVars() *SessionVars {
 		ConcurrencyFactor:           DefOptConcurrencyFactor,
 		EnableRadixJoin:             false,
 		EnableVectorizedExpression:  DefEnableVectorizedExpression,
+		EnableExprCodegen:           DefEnableExprCodegen,
 		L2CacheSize:                 cpuid.CPU.Cache.L2,
 		CommandValue:                uint32(mysql.ComSleep),
 		TiDBOptJoinReorderThreshold: DefTiDBOptJoinReorderThreshold,
@@ -1003,6 +1179,8 @@ func NewSessionVars() *SessionVars {
 		SelectLimit:                 math.MaxUint64,
 		AllowAutoRandExplicitInsert: DefTiDBAllowAutoRandExplicitInsert,
 		EnableClusteredIndex:        DefTiDBEnableClusteredIndex,
+		EnableGeneratedInvisiblePK:  DefTiDBEnableGeneratedInvisiblePK,
+		ShowGeneratedInvisiblePK:    DefTiDBShowGeneratedInvisiblePK,
 		EnableParallelApply:         DefTiDBEnableParallelApply,
 		ShardAllocateStep:           DefTiDBShardAllocateStep,
 		EnableChangeColumnType:      DefTiDBChangeColumnType,
@@ -1013,12 +1191,20 @@ func NewSessionVars() *SessionVars {
 		PartitionPruneMode:          *atomic2.NewString(DefTiDBPartitionPruneMode),
 		TxnScope:                    oracle.GetTxnScope(),
 		EnabledRateLimitAction:      DefTiDBEnableRateLimitAction,
+		MemOOMActionChain:           strings.Split(DefTiDBMemOOMActionChain, ","),
 		EnableAsyncCommit:           DefTiDBEnableAsyncCommit,
 		Enable1PC:                   DefTiDBEnable1PC,
 		GuaranteeLinearizability:    DefTiDBGuaranteeLinearizability,
+		PipelinedAutoCommit:         DefTiDBPipelinedAutoCommit,
 		AnalyzeVersion:              DefTiDBAnalyzeVersion,
+		AnalyzeSampleRate:           DefTiDBAnalyzeSampleRate,
+		ForceAnalyzeLockedStats:     DefTiDBForceAnalyzeLockedStats,
+		PersistAnalyzeOptions:       DefTiDBPersistAnalyzeOptions,
+		EnableColumnTracking:        DefTiDBEnableColumnTracking,
+		AnalyzePredicateColumns:     DefTiDBAnalyzePredicateColumns,
 		EnableIndexMergeJoin:        DefTiDBEnableIndexMergeJoin,
 		AllowFallbackToTiKV:         make(map[kv.StoreType]struct{}),
+		LoadDataLocalCompression:    DefTiDBLoadDataLocalCompression,
 	}
 	vars.KVVars = kv.NewVariables(&vars.Killed)
 	vars.Concurrency = Concurrency{
@@ -1038,6 +1224,7 @@ func NewSessionVars() *SessionVars {
 	vars.MemQuota = MemQuota{
 		MemQuotaQuery:      config.GetGlobalConfig().MemQuotaQuery,
 		MemQuotaApplyCache: DefTiDBMemQuotaApplyCache,
+		TmpTableMemQuota:   DefTiDBTmpTableMemQuota,
 
 		// The variables below do not take any effect anymore, it's remaining for compatibility.
 		// TODO: remove them in v4.1
@@ -1295,9 +1482,6 @@ func (s *SessionVars) GetSystemVar(name string) (string, bool) {
 	if name == TiDBSlowLogMasking {
 		name = TiDBRedactLog
 	}
-	if val, ok := s.stmtVars[name]; ok {
-		return val, ok
-	}
 	val, ok := s.systems[name]
 	return val, ok
 }
@@ -1356,14 +1540,47 @@ func (s *SessionVars) WithdrawAllPreparedStmt() {
 	metrics.PreparedStmtGauge.Set(float64(afterMinus))
 }
 
-// SetStmtVar sets the value of a system variable temporarily
+// UseInvisibleIndexes checks whether the optimizer can use the invisible indexes. It can be turned on for
+// a statement by the hint /*+ SET_VAR(optimizer_switch='use_invisible_indexes=on') */.
+func (s *SessionVars) UseInvisibleIndexes() bool {
+	return s.OptimizerUseInvisibleIndexes
+}
+
+// SetStmtVar sets the value of a system variable for the current statement, the original value is restored by
+// ClearStmtVars.
 func (s *SessionVars) SetStmtVar(name string, val string) error {
-	s.stmtVars[name] = val
-	return nil
+	if _, ok := s.stmtVars[name]; !ok {
+		origin, err := GetSessionSystemVar(s, name)
+		if err != nil {
+			return err
+		}
+		s.stmtVars[name] = origin
+	}
+	return s.SetSystemVar(name, val)
 }
 
-// ClearStmtVars clear temporarily system variables.
+// GetStmtVars returns the values of the system variables set by the SET_VAR hint of the current statement.
+func (s *SessionVars) GetStmtVars() map[string]string {
+	if len(s.stmtVars) == 0 {
+		return nil
+	}
+	vars := make(map[string]string, len(s.stmtVars))
+	for name := range s.stmtVars {
+		vars[name] = s.systems[name]
+	}
+	return vars
+}
+
+// ClearStmtVars restores the system variables set by the SET_VAR hint of the current statement.
 func (s *SessionVars) ClearStmtVars() {
+	if len(s.stmtVars) == 0 {
+		return
+	}
+	for name, origin := range s.stmtVars {
+		if err := s.SetSystemVar(name, origin); err != nil {
+			logutil.BgLogger().Warn("restore the variable set by SET_VAR hint failed", zap.String("variable", name), zap.Error(err))
+		}
+	}
 	s.stmtVars = make(map[string]string)
 }
 
@@ -1404,6 +1621,10 @@ func (s *SessionVars) SetSystemVar(name string, val string) error {
 		}
 		cht, coll, err := charset.GetCharsetInfo(val)
 		if err != nil {
+			if (name == CharacterSetClient || name == CharacterSetResults) && encoding.IsSupported(val) {
+				s.systems[name] = strings.ToLower(val)
+				return nil
+			}
 			logutil.BgLogger().Warn(err.Error())
 			cht, coll = charset.GetDefaultCharsetAndCollate()
 		}
@@ -1464,6 +1685,14 @@ func (s *SessionVars) GetPrevStmtDigest() string {
 	return s.prevStmtDigest
 }
 
+// IsLocalTemporaryTable returns whether the table is a local temporary table of the session.
+func (s *SessionVars) IsLocalTemporaryTable(tableID int64) bool {
+	if tables, ok := s.LocalTemporaryTables.(interface{ IsTemporaryTable(int64) bool }); ok {
+		return tables.IsTemporaryTable(tableID)
+	}
+	return false
+}
+
 // LazyCheckKeyNotExists returns if we can lazy check key not exists.
 func (s *SessionVars) LazyCheckKeyNotExists() bool {
 	return s.PresumeKeyNotExists || (s.TxnCtx.IsPessimistic && !s.StmtCtx.DupKeyAsWarning)
@@ -1478,6 +1707,8 @@ func SetLocalSystemVar(name string, val string) {
 		SetDDLReorgBatchSize(int32(tidbOptPositiveInt32(val, DefTiDBDDLReorgBatchSize)))
 	case TiDBDDLErrorCountLimit:
 		SetDDLErrorCountLimit(tidbOptInt64(val, DefTiDBDDLErrorCountLimit))
+	case TiDBDDLEnableFastReorg:
+		SetDDLEnableFastReorg(TiDBOptOn(val))
 	case TiDBRowFormatVersion:
 		SetDDLReorgRowFormat(tidbOptInt64(val, DefTiDBRowFormatV2))
 	}
@@ -1495,12 +1726,6 @@ const (
 	MaxExecutionTime     = "max_execution_time"
 )
 
-// these variables are useless for TiDB, but still need to validate their values for some compatible issues.
-// TODO: some more variables need to be added here.
-const (
-	serverReadOnly = "read_only"
-)
-
 var (
 	// TxIsolationNames are the valid values of the variable "tx_isolation" or "transaction_isolation".
 	TxIsolationNames = map[string]struct{}{
@@ -1723,6 +1948,8 @@ type MemQuota struct {
 	MemQuotaQuery int64
 	// MemQuotaApplyCache defines the memory capacity for apply cache.
 	MemQuotaApplyCache int64
+	// TmpTableMemQuota is the memory quota of the local temporary tables, the data exceeding it is spilled to the disk.
+	TmpTableMemQuota int64
 
 	// The variables below do not take any effect anymore, it's remaining for compatibility.
 	// TODO: remove them in v4.1
@@ -1830,6 +2057,8 @@ const (
 	SlowLogMemMax = "Mem_max"
 	// SlowLogDiskMax is the nax number bytes of disk used in this statement.
 	SlowLogDiskMax = "Disk_max"
+	// SlowLogOOMActions is the OOM actions triggered by this statement in order.
+	SlowLogOOMActions = "OOM_actions"
 	// SlowLogPrepared is used to indicate whether this sql execute in prepare.
 	SlowLogPrepared = "Prepared"
 	// SlowLogPlanFromCache is used to indicate whether this plan is from plan cache.
@@ -1840,6 +2069,12 @@ const (
 	SlowLogHasMoreResults = "Has_more_results"
 	// SlowLogSucc is used to indicate whether this sql execute successfully.
 	SlowLogSucc = "Succ"
+	// SlowLogIsSampled is used to indicate whether this sql is logged by sampling rather than by the slow threshold.
+	SlowLogIsSampled = "Is_sampled"
+	// SlowLogSampleRateStr is the rate by which the query above the slow threshold is sampled.
+	SlowLogSampleRateStr = "Sample_rate"
+	// SlowLogImplicitCasts is used to record the implicit casts of the compared columns in this sql.
+	SlowLogImplicitCasts = "Implicit_casts"
 	// SlowLogPrevStmt is used to show the previous executed statement.
 	SlowLogPrevStmt = "Prev_stmt"
 	// SlowLogPlan is used to record the query plan.
@@ -1860,6 +2095,8 @@ const (
 	SlowLogBackoffTotal = "Backoff_total"
 	// SlowLogWriteSQLRespTotal is the total time used to write response to client.
 	SlowLogWriteSQLRespTotal = "Write_sql_response_total"
+	// SlowLogWriteSQLRespBytes is the number of bytes of the result sets written to the client.
+	SlowLogWriteSQLRespBytes = "Write_sql_response_bytes"
 	// SlowLogExecRetryCount is the execution retry count.
 	SlowLogExecRetryCount = "Exec_retry_count"
 	// SlowLogExecRetryTime is the execution retry time.
@@ -1881,15 +2118,20 @@ type SlowQueryLogItems struct {
 	TimeWaitTS        time.Duration
 	IndexNames        string
 	StatsInfos        map[string]uint64
+	UsedStats         string
 	CopTasks          *stmtctx.CopTasksDetails
 	ExecDetail        execdetails.ExecDetails
 	MemMax            int64
 	DiskMax           int64
+	OOMActions        string
 	Succ              bool
 	Prepared          bool
 	PlanFromCache     bool
 	PlanFromBinding   bool
 	HasMoreResults    bool
+	Sampled           bool
+	SampleRate        float64
+	ImplicitCasts     []string
 	PrevStmt          string
 	Plan              string
 	PlanDigest        string
@@ -1898,6 +2140,7 @@ type SlowQueryLogItems struct {
 	PDTotal           time.Duration
 	BackoffTotal      time.Duration
 	WriteSQLRespTotal time.Duration
+	WriteSQLRespBytes uint64
 	ExecRetryCount    uint
 	ExecRetryTime     time.Duration
 }
@@ -1924,62 +2167,66 @@ type SlowQueryLogItems struct {
 // # Prev_stmt: begin;
 // select * from t_slim;
 func (s *SessionVars) SlowLogFormat(logItems *SlowQueryLogItems) string {
-	var buf bytes.Buffer
+	return s.formatSlowLog(logItems, false)
+}
 
-	writeSlowLogItem(&buf, SlowLogTxnStartTSStr, strconv.FormatUint(logItems.TxnTS, 10))
+// SlowLogJSONFormat formats the slow log as a JSON object, the fields are the same as the ones of SlowLogFormat, and
+// the statement is the field Query. The numbers and the booleans are not quoted.
+func (s *SessionVars) SlowLogJSONFormat(logItems *SlowQueryLogItems) string {
+	return s.formatSlowLog(logItems, true)
+}
+
+func (s *SessionVars) formatSlowLog(logItems *SlowQueryLogItems, json bool) string {
+	w := &slowLogWriter{json: json}
+
+	w.writeItems(SlowLogTxnStartTSStr, strconv.FormatUint(logItems.TxnTS, 10))
 	if s.User != nil {
 		hostAddress := s.User.Hostname
 		if s.ConnectionInfo != nil {
 			hostAddress = s.ConnectionInfo.ClientIP
 		}
-		writeSlowLogItem(&buf, SlowLogUserAndHostStr, fmt.Sprintf("%s[%s] @ %s [%s]", s.User.Username, s.User.Username, s.User.Hostname, hostAddress))
+		w.writeItems(SlowLogUserAndHostStr, fmt.Sprintf("%s[%s] @ %s [%s]", s.User.Username, s.User.Username, s.User.Hostname, hostAddress))
 	}
 	if s.ConnectionID != 0 {
-		writeSlowLogItem(&buf, SlowLogConnIDStr, strconv.FormatUint(s.ConnectionID, 10))
+		w.writeItems(SlowLogConnIDStr, strconv.FormatUint(s.ConnectionID, 10))
 	}
 	if logItems.ExecRetryCount > 0 {
-		buf.WriteString(SlowLogRowPrefixStr)
-		buf.WriteString(SlowLogExecRetryTime)
-		buf.WriteString(SlowLogSpaceMarkStr)
-		buf.WriteString(strconv.FormatFloat(logItems.ExecRetryTime.Seconds(), 'f', -1, 64))
-		buf.WriteString(" ")
-		buf.WriteString(SlowLogExecRetryCount)
-		buf.WriteString(SlowLogSpaceMarkStr)
-		buf.WriteString(strconv.Itoa(int(logItems.ExecRetryCount)))
-		buf.WriteString("\n")
-	}
-	writeSlowLogItem(&buf, SlowLogQueryTimeStr, strconv.FormatFloat(logItems.TimeTotal.Seconds(), 'f', -1, 64))
-	writeSlowLogItem(&buf, SlowLogParseTimeStr, strconv.FormatFloat(logItems.TimeParse.Seconds(), 'f', -1, 64))
-	writeSlowLogItem(&buf, SlowLogCompileTimeStr, strconv.FormatFloat(logItems.TimeCompile.Seconds(), 'f', -1, 64))
-
-	buf.WriteString(SlowLogRowPrefixStr + fmt.Sprintf("%v%v%v", SlowLogRewriteTimeStr,
-		SlowLogSpaceMarkStr, strconv.FormatFloat(logItems.RewriteInfo.DurationRewrite.Seconds(), 'f', -1, 64)))
+		w.writeItems(SlowLogExecRetryTime, strconv.FormatFloat(logItems.ExecRetryTime.Seconds(), 'f', -1, 64),
+			SlowLogExecRetryCount, strconv.Itoa(int(logItems.ExecRetryCount)))
+	}
+	w.writeItems(SlowLogQueryTimeStr, strconv.FormatFloat(logItems.TimeTotal.Seconds(), 'f', -1, 64))
+	w.writeItems(SlowLogParseTimeStr, strconv.FormatFloat(logItems.TimeParse.Seconds(), 'f', -1, 64))
+	w.writeItems(SlowLogCompileTimeStr, strconv.FormatFloat(logItems.TimeCompile.Seconds(), 'f', -1, 64))
+
+	rewriteItems := []string{SlowLogRewriteTimeStr, strconv.FormatFloat(logItems.RewriteInfo.DurationRewrite.Seconds(), 'f', -1, 64)}
 	if logItems.RewriteInfo.PreprocessSubQueries > 0 {
-		buf.WriteString(fmt.Sprintf(" %v%v%v %v%v%v", SlowLogPreprocSubQueriesStr, SlowLogSpaceMarkStr, logItems.RewriteInfo.PreprocessSubQueries,
-			SlowLogPreProcSubQueryTimeStr, SlowLogSpaceMarkStr, strconv.FormatFloat(logItems.RewriteInfo.DurationPreprocessSubQuery.Seconds(), 'f', -1, 64)))
+		rewriteItems = append(rewriteItems, SlowLogPreprocSubQueriesStr, strconv.Itoa(logItems.RewriteInfo.PreprocessSubQueries),
+			SlowLogPreProcSubQueryTimeStr, strconv.FormatFloat(logItems.RewriteInfo.DurationPreprocessSubQuery.Seconds(), 'f', -1, 64))
 	}
-	buf.WriteString("\n")
+	w.writeItems(rewriteItems...)
 
-	writeSlowLogItem(&buf, SlowLogOptimizeTimeStr, strconv.FormatFloat(logItems.TimeOptimize.Seconds(), 'f', -1, 64))
-	writeSlowLogItem(&buf, SlowLogWaitTSTimeStr, strconv.FormatFloat(logItems.TimeWaitTS.Seconds(), 'f', -1, 64))
+	w.writeItems(SlowLogOptimizeTimeStr, strconv.FormatFloat(logItems.TimeOptimize.Seconds(), 'f', -1, 64))
+	w.writeItems(SlowLogWaitTSTimeStr, strconv.FormatFloat(logItems.TimeWaitTS.Seconds(), 'f', -1, 64))
 
 	if execDetailStr := logItems.ExecDetail.String(); len(execDetailStr) > 0 {
-		buf.WriteString(SlowLogRowPrefixStr + execDetailStr + "\n")
+		w.writeJoinedItems(execDetailStr)
 	}
 
 	if len(s.CurrentDB) > 0 {
-		writeSlowLogItem(&buf, SlowLogDBStr, s.CurrentDB)
+		w.writeItems(SlowLogDBStr, s.CurrentDB)
 	}
 	if len(logItems.IndexNames) > 0 {
-		writeSlowLogItem(&buf, SlowLogIndexNamesStr, logItems.IndexNames)
+		w.writeItems(SlowLogIndexNamesStr, logItems.IndexNames)
 	}
 
-	writeSlowLogItem(&buf, SlowLogIsInternalStr, strconv.FormatBool(s.InRestrictedSQL))
+	w.writeItems(SlowLogIsInternalStr, strconv.FormatBool(s.InRestrictedSQL))
 	if len(logItems.Digest) > 0 {
-		writeSlowLogItem(&buf, SlowLogDigestStr, logItems.Digest)
+		w.writeItems(SlowLogDigestStr, logItems.Digest)
 	}
-	if len(logItems.StatsInfos) > 0 {
-		buf.WriteString(SlowLogRowPrefixStr + SlowLogStatsInfoStr + SlowLogSpaceMarkStr)
+	if len(logItems.UsedStats) > 0 {
+		w.writeItems(SlowLogStatsInfoStr, logItems.UsedStats)
+	} else if len(logItems.StatsInfos) > 0 {
+		var stats strings.Builder
 		firstComma := false
 		vStr := ""
 		for k, v := range logItems.StatsInfos {
@@ -1990,16 +2237,16 @@ func (s *SessionVars) SlowLogFormat(logItems *SlowQueryLogItems) string {
 
 			}
 			if firstComma {
-				buf.WriteString("," + k + ":" + vStr)
+				stats.WriteString("," + k + ":" + vStr)
 			} else {
-				buf.WriteString(k + ":" + vStr)
+				stats.WriteString(k + ":" + vStr)
 				firstComma = true
 			}
 		}
-		buf.WriteString("\n")
+		w.writeItems(SlowLogStatsInfoStr, stats.String())
 	}
 	if logItems.CopTasks != nil {
-		writeSlowLogItem(&buf, SlowLogNumCopTasksStr, strconv.FormatInt(int64(logItems.CopTasks.NumCopTasks), 10))
+		w.writeItems(SlowLogNumCopTasksStr, strconv.FormatInt(int64(logItems.CopTasks.NumCopTasks), 10))
 		if logItems.CopTasks.NumCopTasks > 0 {
 			// make the result stable
 			backoffs := make([]string, 0, 3)
@@ -2009,86 +2256,171 @@ func (s *SessionVars) SlowLogFormat(logItems *SlowQueryLogItems) string {
 			sort.Strings(backoffs)
 
 			if logItems.CopTasks.NumCopTasks == 1 {
-				buf.WriteString(SlowLogRowPrefixStr + fmt.Sprintf("%v%v%v %v%v%v",
-					SlowLogCopProcAvg, SlowLogSpaceMarkStr, logItems.CopTasks.AvgProcessTime.Seconds(),
-					SlowLogCopProcAddr, SlowLogSpaceMarkStr, logItems.CopTasks.MaxProcessAddress) + "\n")
-				buf.WriteString(SlowLogRowPrefixStr + fmt.Sprintf("%v%v%v %v%v%v",
-					SlowLogCopWaitAvg, SlowLogSpaceMarkStr, logItems.CopTasks.AvgWaitTime.Seconds(),
-					SlowLogCopWaitAddr, SlowLogSpaceMarkStr, logItems.CopTasks.MaxWaitAddress) + "\n")
+				w.writeItems(SlowLogCopProcAvg, fmt.Sprint(logItems.CopTasks.AvgProcessTime.Seconds()),
+					SlowLogCopProcAddr, logItems.CopTasks.MaxProcessAddress)
+				w.writeItems(SlowLogCopWaitAvg, fmt.Sprint(logItems.CopTasks.AvgWaitTime.Seconds()),
+					SlowLogCopWaitAddr, logItems.CopTasks.MaxWaitAddress)
 				for _, backoff := range backoffs {
 					backoffPrefix := SlowLogCopBackoffPrefix + backoff + "_"
-					buf.WriteString(SlowLogRowPrefixStr + fmt.Sprintf("%v%v%v %v%v%v\n",
-						backoffPrefix+"total_times", SlowLogSpaceMarkStr, logItems.CopTasks.TotBackoffTimes[backoff],
-						backoffPrefix+"total_time", SlowLogSpaceMarkStr, logItems.CopTasks.TotBackoffTime[backoff].Seconds(),
-					))
+					w.writeItems(backoffPrefix+"total_times", fmt.Sprint(logItems.CopTasks.TotBackoffTimes[backoff]),
+						backoffPrefix+"total_time", fmt.Sprint(logItems.CopTasks.TotBackoffTime[backoff].Seconds()))
 				}
 			} else {
-				buf.WriteString(SlowLogRowPrefixStr + fmt.Sprintf("%v%v%v %v%v%v %v%v%v %v%v%v",
-					SlowLogCopProcAvg, SlowLogSpaceMarkStr, logItems.CopTasks.AvgProcessTime.Seconds(),
-					SlowLogCopProcP90, SlowLogSpaceMarkStr, logItems.CopTasks.P90ProcessTime.Seconds(),
-					SlowLogCopProcMax, SlowLogSpaceMarkStr, logItems.CopTasks.MaxProcessTime.Seconds(),
-					SlowLogCopProcAddr, SlowLogSpaceMarkStr, logItems.CopTasks.MaxProcessAddress) + "\n")
-				buf.WriteString(SlowLogRowPrefixStr + fmt.Sprintf("%v%v%v %v%v%v %v%v%v %v%v%v",
-					SlowLogCopWaitAvg, SlowLogSpaceMarkStr, logItems.CopTasks.AvgWaitTime.Seconds(),
-					SlowLogCopWaitP90, SlowLogSpaceMarkStr, logItems.CopTasks.P90WaitTime.Seconds(),
-					SlowLogCopWaitMax, SlowLogSpaceMarkStr, logItems.CopTasks.MaxWaitTime.Seconds(),
-					SlowLogCopWaitAddr, SlowLogSpaceMarkStr, logItems.CopTasks.MaxWaitAddress) + "\n")
+				w.writeItems(SlowLogCopProcAvg, fmt.Sprint(logItems.CopTasks.AvgProcessTime.Seconds()),
+					SlowLogCopProcP90, fmt.Sprint(logItems.CopTasks.P90ProcessTime.Seconds()),
+					SlowLogCopProcMax, fmt.Sprint(logItems.CopTasks.MaxProcessTime.Seconds()),
+					SlowLogCopProcAddr, logItems.CopTasks.MaxProcessAddress)
+				w.writeItems(SlowLogCopWaitAvg, fmt.Sprint(logItems.CopTasks.AvgWaitTime.Seconds()),
+					SlowLogCopWaitP90, fmt.Sprint(logItems.CopTasks.P90WaitTime.Seconds()),
+					SlowLogCopWaitMax, fmt.Sprint(logItems.CopTasks.MaxWaitTime.Seconds()),
+					SlowLogCopWaitAddr, logItems.CopTasks.MaxWaitAddress)
 				for _, backoff := range backoffs {
 					backoffPrefix := SlowLogCopBackoffPrefix + backoff + "_"
-					buf.WriteString(SlowLogRowPrefixStr + fmt.Sprintf("%v%v%v %v%v%v %v%v%v %v%v%v %v%v%v %v%v%v\n",
-						backoffPrefix+"total_times", SlowLogSpaceMarkStr, logItems.CopTasks.TotBackoffTimes[backoff],
-						backoffPrefix+"total_time", SlowLogSpaceMarkStr, logItems.CopTasks.TotBackoffTime[backoff].Seconds(),
-						backoffPrefix+"max_time", SlowLogSpaceMarkStr, logItems.CopTasks.MaxBackoffTime[backoff].Seconds(),
-						backoffPrefix+"max_addr", SlowLogSpaceMarkStr, logItems.CopTasks.MaxBackoffAddress[backoff],
-						backoffPrefix+"avg_time", SlowLogSpaceMarkStr, logItems.CopTasks.AvgBackoffTime[backoff].Seconds(),
-						backoffPrefix+"p90_time", SlowLogSpaceMarkStr, logItems.CopTasks.P90BackoffTime[backoff].Seconds(),
-					))
+					w.writeItems(backoffPrefix+"total_times", fmt.Sprint(logItems.CopTasks.TotBackoffTimes[backoff]),
+						backoffPrefix+"total_time", fmt.Sprint(logItems.CopTasks.TotBackoffTime[backoff].Seconds()),
+						backoffPrefix+"max_time", fmt.Sprint(logItems.CopTasks.MaxBackoffTime[backoff].Seconds()),
+						backoffPrefix+"max_addr", logItems.CopTasks.MaxBackoffAddress[backoff],
+						backoffPrefix+"avg_time", fmt.Sprint(logItems.CopTasks.AvgBackoffTime[backoff].Seconds()),
+						backoffPrefix+"p90_time", fmt.Sprint(logItems.CopTasks.P90BackoffTime[backoff].Seconds()))
 				}
 			}
 		}
 	}
 	if logItems.MemMax > 0 {
-		writeSlowLogItem(&buf, SlowLogMemMax, strconv.FormatInt(logItems.MemMax, 10))
+		w.writeItems(SlowLogMemMax, strconv.FormatInt(logItems.MemMax, 10))
 	}
 	if logItems.DiskMax > 0 {
-		writeSlowLogItem(&buf, SlowLogDiskMax, strconv.FormatInt(logItems.DiskMax, 10))
-	}
-
-	writeSlowLogItem(&buf, SlowLogPrepared, strconv.FormatBool(logItems.Prepared))
-	writeSlowLogItem(&buf, SlowLogPlanFromCache, strconv.FormatBool(logItems.PlanFromCache))
-	writeSlowLogItem(&buf, SlowLogPlanFromBinding, strconv.FormatBool(logItems.PlanFromBinding))
-	writeSlowLogItem(&buf, SlowLogHasMoreResults, strconv.FormatBool(logItems.HasMoreResults))
-	writeSlowLogItem(&buf, SlowLogKVTotal, strconv.FormatFloat(logItems.KVTotal.Seconds(), 'f', -1, 64))
-	writeSlowLogItem(&buf, SlowLogPDTotal, strconv.FormatFloat(logItems.PDTotal.Seconds(), 'f', -1, 64))
-	writeSlowLogItem(&buf, SlowLogBackoffTotal, strconv.FormatFloat(logItems.BackoffTotal.Seconds(), 'f', -1, 64))
-	writeSlowLogItem(&buf, SlowLogWriteSQLRespTotal, strconv.FormatFloat(logItems.WriteSQLRespTotal.Seconds(), 'f', -1, 64))
-	writeSlowLogItem(&buf, SlowLogSucc, strconv.FormatBool(logItems.Succ))
+		w.writeItems(SlowLogDiskMax, strconv.FormatInt(logItems.DiskMax, 10))
+	}
+	if logItems.OOMActions != "" {
+		w.writeItems(SlowLogOOMActions, logItems.OOMActions)
+	}
+
+	w.writeItems(SlowLogPrepared, strconv.FormatBool(logItems.Prepared))
+	w.writeItems(SlowLogPlanFromCache, strconv.FormatBool(logItems.PlanFromCache))
+	w.writeItems(SlowLogPlanFromBinding, strconv.FormatBool(logItems.PlanFromBinding))
+	w.writeItems(SlowLogHasMoreResults, strconv.FormatBool(logItems.HasMoreResults))
+	w.writeItems(SlowLogKVTotal, strconv.FormatFloat(logItems.KVTotal.Seconds(), 'f', -1, 64))
+	w.writeItems(SlowLogPDTotal, strconv.FormatFloat(logItems.PDTotal.Seconds(), 'f', -1, 64))
+	w.writeItems(SlowLogBackoffTotal, strconv.FormatFloat(logItems.BackoffTotal.Seconds(), 'f', -1, 64))
+	w.writeItems(SlowLogWriteSQLRespTotal, strconv.FormatFloat(logItems.WriteSQLRespTotal.Seconds(), 'f', -1, 64))
+	if logItems.WriteSQLRespBytes > 0 {
+		w.writeItems(SlowLogWriteSQLRespBytes, strconv.FormatUint(logItems.WriteSQLRespBytes, 10))
+	}
+	w.writeItems(SlowLogSucc, strconv.FormatBool(logItems.Succ))
+	if logItems.Sampled {
+		w.writeItems(SlowLogIsSampled, strconv.FormatBool(logItems.Sampled))
+	}
+	if logItems.SampleRate > 0 && logItems.SampleRate < 1 {
+		w.writeItems(SlowLogSampleRateStr, strconv.FormatFloat(logItems.SampleRate, 'f', -1, 64))
+	}
+	if len(logItems.ImplicitCasts) > 0 {
+		w.writeItems(SlowLogImplicitCasts, strings.Join(logItems.ImplicitCasts, "; "))
+	}
 	if len(logItems.Plan) != 0 {
-		writeSlowLogItem(&buf, SlowLogPlan, logItems.Plan)
+		w.writeItems(SlowLogPlan, logItems.Plan)
 	}
 	if len(logItems.PlanDigest) != 0 {
-		writeSlowLogItem(&buf, SlowLogPlanDigest, logItems.PlanDigest)
+		w.writeItems(SlowLogPlanDigest, logItems.PlanDigest)
 	}
 
 	if logItems.PrevStmt != "" {
-		writeSlowLogItem(&buf, SlowLogPrevStmt, logItems.PrevStmt)
+		w.writeItems(SlowLogPrevStmt, logItems.PrevStmt)
 	}
 
 	if s.CurrentDBChanged {
-		buf.WriteString(fmt.Sprintf("use %s;\n", s.CurrentDB))
+		// The JSON format only has the field DB.
+		if !json {
+			w.buf.WriteString(fmt.Sprintf("use %s;\n", s.CurrentDB))
+		}
 		s.CurrentDBChanged = false
 	}
 
-	buf.WriteString(logItems.SQL)
-	if len(logItems.SQL) == 0 || logItems.SQL[len(logItems.SQL)-1] != ';' {
-		buf.WriteString(";")
+	sql := logItems.SQL
+	if len(sql) == 0 || sql[len(sql)-1] != ';' {
+		sql += ";"
+	}
+	if json {
+		w.writeItems(SlowLogQuerySQLStr, sql)
+		w.buf.WriteByte('}')
+	} else {
+		w.buf.WriteString(sql)
 	}
-	return buf.String()
+	return w.buf.String()
 }
 
-// writeSlowLogItem writes a slow log item in the form of: "# ${key}:${value}"
-func writeSlowLogItem(buf *bytes.Buffer, key, value string) {
-	buf.WriteString(SlowLogRowPrefixStr + key + SlowLogSpaceMarkStr + value + "\n")
+// slowLogWriter writes the items of the slow log in the text format or the JSON format.
+type slowLogWriter struct {
+	buf  bytes.Buffer
+	json bool
+}
+
+// writeItems writes the keys followed by their values. In the text format, they're in a row in the form of:
+// "# ${key1}: ${value1} ${key2}: ${value2}".
+func (w *slowLogWriter) writeItems(kvs ...string) {
+	if !w.json {
+		w.buf.WriteString(SlowLogRowPrefixStr)
+		for i := 0; i+1 < len(kvs); i += 2 {
+			if i > 0 {
+				w.buf.WriteByte(' ')
+			}
+			w.buf.WriteString(kvs[i] + SlowLogSpaceMarkStr + kvs[i+1])
+		}
+		w.buf.WriteByte('\n')
+		return
+	}
+	for i := 0; i+1 < len(kvs); i += 2 {
+		if w.buf.Len() == 0 {
+			w.buf.WriteByte('{')
+		} else {
+			w.buf.WriteByte(',')
+		}
+		writeJSONString(&w.buf, kvs[i])
+		w.buf.WriteByte(':')
+		if value := kvs[i+1]; !slowLogStringFields[kvs[i]] && (value == "true" || value == "false" || jsonNumberRegexp.MatchString(value)) {
+			w.buf.WriteString(value)
+		} else {
+			writeJSONString(&w.buf, value)
+		}
+	}
+}
+
+// writeJoinedItems writes the items which are joined in the form of "${key1}: ${value1} ${key2}: ${value2}".
+func (w *slowLogWriter) writeJoinedItems(items string) {
+	if !w.json {
+		w.buf.WriteString(SlowLogRowPrefixStr + items + "\n")
+		return
+	}
+	var kvs []string
+	for _, token := range strings.Split(items, " ") {
+		if strings.HasSuffix(token, ":") {
+			kvs = append(kvs, token[:len(token)-1], "")
+		} else if len(kvs) > 0 {
+			if value := kvs[len(kvs)-1]; value != "" {
+				token = value + " " + token
+			}
+			kvs[len(kvs)-1] = token
+		}
+	}
+	w.writeItems(kvs...)
+}
+
+// slowLogStringFields are the fields which are always quoted in the JSON format, though they may look like numbers.
+var slowLogStringFields = map[string]bool{
+	SlowLogDigestStr:     true,
+	SlowLogPlanDigest:    true,
+	SlowLogDBStr:         true,
+	SlowLogQuerySQLStr:   true,
+	SlowLogPrevStmt:      true,
+	SlowLogIndexNamesStr: true,
+}
+
+var jsonNumberRegexp = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	// Marshaling a string never fails.
+	b, _ := json.Marshal(s)
+	buf.Write(b)
 }
 
 // QueryInfo represents the information of last executed query. It's used to expose information for test purpose.
@@ -0,0 +1,172 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package variable
+
+import (
+	"strconv"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/sessionctx/sessionstates"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+)
+
+// EncodeSessionStates saves the session states which are stored in SessionVars into SessionStates.
+// The session can't be migrated in a transaction because the transaction can't be migrated.
+func (s *SessionVars) EncodeSessionStates(sessionStates *sessionstates.SessionStates) error {
+	if s.InTxn() {
+		return errors.New("the session states can't be encoded in a transaction")
+	}
+
+	s.UsersLock.RLock()
+	sessionStates.UserVars = make(map[string]*sessionstates.UserVar, len(s.Users))
+	for name, d := range s.Users {
+		userVar, err := encodeUserVar(d)
+		if err != nil {
+			s.UsersLock.RUnlock()
+			return err
+		}
+		sessionStates.UserVars[name] = userVar
+	}
+	sessionStates.UserVarTypes = make(map[string]*types.FieldType, len(s.UserVarTypes))
+	for name, ft := range s.UserVarTypes {
+		sessionStates.UserVarTypes[name] = ft.Clone()
+	}
+	s.UsersLock.RUnlock()
+
+	sessionStates.SystemVars = make(map[string]string, len(s.systems))
+	for name, val := range s.systems {
+		sv := GetSysVar(name)
+		if sv == nil || sv.ReadOnly || sv.Scope&ScopeSession == 0 || name == TiDBSessionStates {
+			continue
+		}
+		sessionStates.SystemVars[name] = val
+	}
+
+	sessionStates.PreparedStmts = make(map[uint32]*sessionstates.PreparedStmtInfo, len(s.PreparedStmts))
+	for id, stmt := range s.PreparedStmts {
+		encoder, ok := stmt.(sessionstates.PreparedStmtEncoder)
+		if !ok {
+			return errors.Errorf("the prepared statement %d can't be encoded", id)
+		}
+		stmtText, stmtDB := encoder.EncodePreparedStmt()
+		sessionStates.PreparedStmts[id] = &sessionstates.PreparedStmtInfo{StmtText: stmtText, StmtDB: stmtDB}
+	}
+	for name, id := range s.PreparedStmtNameToID {
+		if info, ok := sessionStates.PreparedStmts[id]; ok {
+			info.Name = name
+		}
+	}
+	sessionStates.PreparedStmtID = s.preparedStmtID
+
+	sessionStates.CurrentDB = s.CurrentDB
+	sessionStates.LastFoundRows = s.LastFoundRows
+	return nil
+}
+
+// DecodeSessionStates restores the session states which are stored in SessionVars from SessionStates.
+// The system variables, the prepared statements and the current database are not restored here because they
+// are restored by the executor in the same way as SET, PREPARE and USE, and the counter of the prepared
+// statement ID should be restored by SetPreparedStmtID after that.
+func (s *SessionVars) DecodeSessionStates(sessionStates *sessionstates.SessionStates) error {
+	if s.InTxn() {
+		return errors.New("the session states can't be decoded in a transaction")
+	}
+
+	s.UsersLock.Lock()
+	for name, userVar := range sessionStates.UserVars {
+		d, err := decodeUserVar(s, userVar)
+		if err != nil {
+			s.UsersLock.Unlock()
+			return err
+		}
+		s.Users[name] = d
+	}
+	for name, ft := range sessionStates.UserVarTypes {
+		s.UserVarTypes[name] = ft
+	}
+	s.UsersLock.Unlock()
+
+	s.LastFoundRows = sessionStates.LastFoundRows
+	return nil
+}
+
+// SetPreparedStmtID sets the counter of the prepared statement ID, it's used when the session states are restored.
+func (s *SessionVars) SetPreparedStmtID(id uint32) {
+	if id > s.preparedStmtID {
+		s.preparedStmtID = id
+	}
+}
+
+func encodeUserVar(d types.Datum) (*sessionstates.UserVar, error) {
+	userVar := &sessionstates.UserVar{Kind: d.Kind(), Collation: d.Collation()}
+	switch d.Kind() {
+	case types.KindNull:
+		return userVar, nil
+	case types.KindMysqlTime:
+		t := d.GetMysqlTime()
+		userVar.Type, userVar.Fsp = t.Type(), t.Fsp()
+	case types.KindMysqlDuration:
+		userVar.Fsp = d.GetMysqlDuration().Fsp
+	}
+	val, err := d.ToString()
+	if err != nil {
+		return nil, err
+	}
+	userVar.Value = val
+	return userVar, nil
+}
+
+func decodeUserVar(s *SessionVars, userVar *sessionstates.UserVar) (d types.Datum, err error) {
+	switch userVar.Kind {
+	case types.KindNull:
+	case types.KindInt64:
+		var i int64
+		i, err = strconv.ParseInt(userVar.Value, 10, 64)
+		d.SetInt64(i)
+	case types.KindUint64:
+		var u uint64
+		u, err = strconv.ParseUint(userVar.Value, 10, 64)
+		d.SetUint64(u)
+	case types.KindFloat32:
+		var f float64
+		f, err = strconv.ParseFloat(userVar.Value, 32)
+		d.SetFloat32(float32(f))
+	case types.KindFloat64:
+		var f float64
+		f, err = strconv.ParseFloat(userVar.Value, 64)
+		d.SetFloat64(f)
+	case types.KindMysqlDecimal:
+		dec := new(types.MyDecimal)
+		err = dec.FromString([]byte(userVar.Value))
+		d.SetMysqlDecimal(dec)
+	case types.KindMysqlTime:
+		var t types.Time
+		t, err = types.ParseTime(s.StmtCtx, userVar.Value, userVar.Type, userVar.Fsp)
+		d.SetMysqlTime(t)
+	case types.KindMysqlDuration:
+		var dur types.Duration
+		dur, err = types.ParseDuration(s.StmtCtx, userVar.Value, userVar.Fsp)
+		d.SetMysqlDuration(dur)
+	case types.KindMysqlJSON:
+		var j json.BinaryJSON
+		j, err = json.ParseBinaryFromString(userVar.Value)
+		d.SetMysqlJSON(j)
+	case types.KindBytes, types.KindBinaryLiteral, types.KindMysqlBit:
+		d.SetBytes([]byte(userVar.Value))
+	default:
+		d.SetString(userVar.Value, userVar.Collation)
+	}
+	return d, errors.Trace(err)
+}
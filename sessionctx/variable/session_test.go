@@ -14,6 +14,8 @@
 package variable_test
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 
 	. "github.com/pingcap/check"
@@ -268,6 +270,66 @@ func (*testSessionSuite) TestSlowLogFormat(c *C) {
 	logString = seVar.SlowLogFormat(logItems)
 	c.Assert(logString, Equals, resultFields+"\n"+"use test;\n"+sql)
 	c.Assert(seVar.CurrentDBChanged, IsFalse)
+
+	logItems.Sampled = true
+	logString = seVar.SlowLogFormat(logItems)
+	c.Assert(logString, Equals, resultFields+"\n# Is_sampled: true\n"+sql)
+
+	logItems.Sampled = false
+	logItems.ImplicitCasts = []string{"cast a", "cast b"}
+	logString = seVar.SlowLogFormat(logItems)
+	c.Assert(logString, Equals, resultFields+"\n# Implicit_casts: cast a; cast b\n"+sql)
+
+	logItems.ImplicitCasts = nil
+	logItems.SampleRate = 0.25
+	logString = seVar.SlowLogFormat(logItems)
+	c.Assert(logString, Equals, resultFields+"\n# Sample_rate: 0.25\n"+sql)
+}
+
+func (*testSessionSuite) TestSlowLogJSONFormat(c *C) {
+	ctx := mock.NewContext()
+	seVar := ctx.GetSessionVars()
+	seVar.User = &auth.UserIdentity{Username: "root", Hostname: "192.168.0.1"}
+	seVar.ConnectionID = 1
+	seVar.CurrentDB = "test"
+	seVar.CurrentDBChanged = true
+
+	sql := "select * from t where a = \"1\";"
+	_, digest := parser.NormalizeDigest(sql)
+	logItems := &variable.SlowQueryLogItems{
+		TxnTS:      406649736972468225,
+		SQL:        sql,
+		Digest:     digest,
+		TimeTotal:  time.Second,
+		IndexNames: "[t1:a]",
+		ExecDetail: execdetails.ExecDetails{
+			RequestCount: 2,
+			ScanDetail:   &util.ScanDetail{ProcessedKeys: 20001, TotalKeys: 10000},
+		},
+		MemMax:     2333,
+		Succ:       true,
+		SampleRate: 0.5,
+	}
+	logString := seVar.SlowLogJSONFormat(logItems)
+	c.Assert(strings.Contains(logString, "\n"), IsFalse)
+	// The database isn't switched by a use statement in the JSON format, it's kept in the DB field.
+	c.Assert(strings.Contains(logString, "use test;"), IsFalse)
+
+	decoder := json.NewDecoder(strings.NewReader(logString))
+	decoder.UseNumber()
+	var fields map[string]interface{}
+	c.Assert(decoder.Decode(&fields), IsNil)
+	c.Assert(fields[variable.SlowLogTxnStartTSStr], Equals, json.Number("406649736972468225"))
+	c.Assert(fields[variable.SlowLogUserAndHostStr], Equals, "root[root] @ 192.168.0.1 [192.168.0.1]")
+	c.Assert(fields[variable.SlowLogQueryTimeStr], Equals, json.Number("1"))
+	c.Assert(fields[variable.SlowLogDBStr], Equals, "test")
+	c.Assert(fields[variable.SlowLogIndexNamesStr], Equals, "[t1:a]")
+	c.Assert(fields[variable.SlowLogDigestStr], Equals, digest)
+	c.Assert(fields[variable.SlowLogMemMax], Equals, json.Number("2333"))
+	c.Assert(fields[variable.SlowLogSucc], Equals, true)
+	c.Assert(fields[variable.SlowLogSampleRateStr], Equals, json.Number("0.5"))
+	c.Assert(fields["Process_keys"], Equals, json.Number("20001"))
+	c.Assert(fields[variable.SlowLogQuerySQLStr], Equals, sql)
 }
 
 func (*testSessionSuite) TestIsolationRead(c *C) {
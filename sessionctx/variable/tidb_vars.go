@@ -69,6 +69,12 @@ const (
 	TiDBAutoAnalyzeStartTime = "tidb_auto_analyze_start_time"
 	TiDBAutoAnalyzeEndTime   = "tidb_auto_analyze_end_time"
 
+	// TiDBEnableAutoAnalyze indicates whether to run the auto analyze, the auto analyze is paused when it's off.
+	TiDBEnableAutoAnalyze = "tidb_enable_auto_analyze"
+
+	// TiDBAutoAnalyzeConcurrency is the max number of the auto analyze jobs run concurrently.
+	TiDBAutoAnalyzeConcurrency = "tidb_auto_analyze_concurrency"
+
 	// tidb_checksum_table_concurrency is used to speed up the ADMIN CHECKSUM TABLE
 	// statement, when a table has multiple indices, those indices can be
 	// scanned concurrently, with the cost of higher system performance impact.
@@ -84,6 +90,12 @@ const (
 	// TiDBLastTxnInfo is used to get the last query info within the current session.
 	TiDBLastQueryInfo = "tidb_last_query_info"
 
+	// TiDBSessionStates is used to get or restore the session states, so that the session can be migrated to another tidb-server.
+	TiDBSessionStates = "tidb_session_states"
+
+	// TiDBSessionToken is used to get the token to authenticate the migrated session on another tidb-server.
+	TiDBSessionToken = "tidb_session_token"
+
 	// tidb_config is a read-only variable that shows the config of the current server.
 	TiDBConfig = "tidb_config"
 
@@ -181,6 +193,9 @@ const (
 	// tidb_slow_log_threshold is used to set the slow log threshold in the server.
 	TiDBSlowLogThreshold = "tidb_slow_log_threshold"
 
+	// tidb_slow_log_sample_rate is used to set the fraction of statements below the slow log threshold that are still logged.
+	TiDBSlowLogSampleRate = "tidb_slow_log_sample_rate"
+
 	// tidb_record_plan_in_slow_log is used to log the plan of the slow query.
 	TiDBRecordPlanInSlowLog = "tidb_record_plan_in_slow_log"
 
@@ -204,6 +219,14 @@ const (
 
 	// TiDBTxnScope indicates whether using global transactions or local transactions.
 	TiDBTxnScope = "txn_scope"
+
+	// TiDBReadStaleness indicates the staleness in seconds of the data read by the auto-commit read-only statements,
+	// it should be a negative value, 0 means the stale read is disabled.
+	TiDBReadStaleness = "tidb_read_staleness"
+
+	// TiDBResourceGroup is the name of the resource group the session is bound to. It's initialized by the resource
+	// group bound to the user account when the session is authenticated.
+	TiDBResourceGroup = "tidb_resource_group"
 )
 
 // TiDB system variable names that both in session and global scope.
@@ -360,6 +383,14 @@ const (
 	// tidb_ddl_error_count_limit defines the count of ddl error limit.
 	TiDBDDLErrorCountLimit = "tidb_ddl_error_count_limit"
 
+	// tidb_ddl_enable_fast_reorg indicates whether to backfill the indexes by writing them into a local
+	// sorted engine and ingesting them in order, instead of adding them in transactions row by row.
+	TiDBDDLEnableFastReorg = "tidb_ddl_enable_fast_reorg"
+
+	// tidb_enable_concurrent_ddl indicates whether to put the DDL jobs which only change one table into the
+	// table job queues, so the jobs of different tables can be run concurrently.
+	TiDBEnableConcurrentDDL = "tidb_enable_concurrent_ddl"
+
 	// tidb_ddl_reorg_priority defines the operations priority of adding indices.
 	// It can be: PRIORITY_LOW, PRIORITY_NORMAL, PRIORITY_HIGH
 	TiDBDDLReorgPriority = "tidb_ddl_reorg_priority"
@@ -410,6 +441,18 @@ const (
 	// tidb_enable_vectorized_expression is used to control whether to enable the vectorized expression evaluation.
 	TiDBEnableVectorizedExpression = "tidb_enable_vectorized_expression"
 
+	// tidb_enable_expression_codegen is used to control whether to compile hot filters into fused closures.
+	// It is an experimental feature.
+	TiDBEnableExprCodegen = "tidb_enable_expression_codegen"
+
+	// tidb_invalid_char_handling controls how the character set conversions handle the invalid characters,
+	// REPLACE replaces them by '?' with a warning, ERROR fails the statement.
+	TiDBInvalidCharHandling = "tidb_invalid_char_handling"
+
+	// tidb_enable_implicit_cast_diagnostics is used to control whether to report the implicit casts of the compared
+	// columns, which may prevent the indexes from being used, as notes and in the slow log.
+	TiDBEnableImplicitCastDiagnostics = "tidb_enable_implicit_cast_diagnostics"
+
 	// TIDBOptJoinReorderThreshold defines the threshold less than which
 	// we'll choose a rather time consuming algorithm to calculate the join order.
 	TiDBOptJoinReorderThreshold = "tidb_opt_join_reorder_threshold"
@@ -481,6 +524,14 @@ const (
 	// TiDBEnableClusteredIndex indicates if clustered index feature is enabled.
 	TiDBEnableClusteredIndex = "tidb_enable_clustered_index"
 
+	// TiDBEnableGeneratedInvisiblePK indicates whether to add an invisible auto_random primary key
+	// to the tables created without a primary key.
+	TiDBEnableGeneratedInvisiblePK = "tidb_enable_generated_invisible_primary_key"
+
+	// TiDBShowGeneratedInvisiblePK indicates whether the generated invisible primary key is visible
+	// in `SELECT *`, `SHOW CREATE TABLE` and `information_schema`.
+	TiDBShowGeneratedInvisiblePK = "tidb_show_generated_invisible_primary_key"
+
 	// TiDBPartitionPruneMode indicates the partition prune mode used.
 	TiDBPartitionPruneMode = "tidb_partition_prune_mode"
 
@@ -505,6 +556,10 @@ const (
 	// TiDBEnableRateLimitAction indicates whether enabled ratelimit action
 	TiDBEnableRateLimitAction = "tidb_enable_rate_limit_action"
 
+	// TiDBMemOOMActionChain is the comma separated OOM actions taken in order when the memory quota of a statement is
+	// exceeded, the final action set by oom-action in the config file is taken after all of them.
+	TiDBMemOOMActionChain = "tidb_mem_oom_action_chain"
+
 	// TiDBEnableAsyncCommit indicates whether to enable the async commit feature.
 	TiDBEnableAsyncCommit = "tidb_enable_async_commit"
 
@@ -514,6 +569,16 @@ const (
 	// TiDBGuaranteeLinearizability indicates whether to guarantee linearizability.
 	TiDBGuaranteeLinearizability = "tidb_guarantee_linearizability"
 
+	// TiDBPipelinedAutoCommit indicates whether to acknowledge the auto-commit statements once their keys are
+	// prewritten. The statements are committed by 1PC or async commit only if tidb_enable_1pc or
+	// tidb_enable_async_commit is on respectively, and the keys are committed in the background.
+	// The acknowledged changes survive the crash of the tidb-server because the prewritten locks are persisted in
+	// TiKV, but they are not committed yet in the durability window between the acknowledgment and the end of the
+	// background commit: the readers meeting the locks have to check the primary lock to resolve them, and the
+	// changes are finished by the lock resolver if the tidb-server crashes in the window. The statements too large
+	// for async commit fall back to the normal 2PC.
+	TiDBPipelinedAutoCommit = "tidb_pipelined_autocommit"
+
 	// TiDBAnalyzeVersion indicates the how tidb collects the analyzed statistics and how use to it.
 	TiDBAnalyzeVersion = "tidb_analyze_version"
 
@@ -532,6 +597,45 @@ const (
 
 	// TiDBEnableDynamicPrivileges enables MySQL 8.0 compatible dynamic privileges (experimental).
 	TiDBEnableDynamicPrivileges = "tidb_enable_dynamic_privileges"
+
+	// TiDBAnalyzeSampleRate indicates the rate of rows sampled by analyze, 0 means the number of samples is fixed.
+	TiDBAnalyzeSampleRate = "tidb_analyze_sample_rate"
+
+	// TiDBForceAnalyzeLockedStats indicates whether the manual analyze replaces the locked stats of the tables.
+	TiDBForceAnalyzeLockedStats = "tidb_force_analyze_locked_stats"
+
+	// TiDBPersistAnalyzeOptions indicates whether the BUCKETS and TOPN options of analyze are saved for the tables and
+	// reused by the later analyze.
+	TiDBPersistAnalyzeOptions = "tidb_persist_analyze_options"
+
+	// TiDBEnableColumnTracking indicates whether the columns used by the predicates, joins or group-by of the queries
+	// are recorded.
+	TiDBEnableColumnTracking = "tidb_enable_column_tracking"
+
+	// TiDBAnalyzePredicateColumns indicates whether the manual analyze only collects the stats of the predicate columns,
+	// the index columns and the handle columns, the auto analyze always does so.
+	TiDBAnalyzePredicateColumns = "tidb_analyze_predicate_columns"
+
+	// TiDBIdleTransactionTimeout is the timeout in seconds of the explicit transactions which are idle, the connection
+	// of an idle transaction is killed when the timeout is exceeded, 0 means no timeout.
+	TiDBIdleTransactionTimeout = "tidb_idle_transaction_timeout"
+
+	// TiDBMaxTransactionDuration is the max lifetime in seconds of the explicit transactions, the connection of a
+	// transaction is killed when its lifetime is exceeded, 0 means no limit.
+	TiDBMaxTransactionDuration = "tidb_max_transaction_duration"
+
+	// TiDBTmpTableMemQuota is the memory quota in bytes of the local temporary tables of a session, the data
+	// exceeding the quota is spilled to the disk.
+	TiDBTmpTableMemQuota = "tidb_tmp_table_mem_quota"
+
+	// TiDBLoadDataLocalCompression is the compression of the files uploaded by LOAD DATA LOCAL INFILE, the data is
+	// decompressed while it's streamed into the load pipeline. `auto` detects the compression by the magic number.
+	TiDBLoadDataLocalCompression = "tidb_load_data_local_compression"
+
+	// TiDBPreSplitIndexRegions is the number of regions the index created by CREATE INDEX or ALTER TABLE ... ADD INDEX
+	// is pre-split into before it's backfilled, the split keys are the quantiles of the statistics of its first column.
+	// 0 means the index isn't pre-split.
+	TiDBPreSplitIndexRegions = "tidb_pre_split_index_regions"
 )
 
 // TiDB vars that have only global scope
@@ -549,6 +653,19 @@ const (
 	TiDBGCScanLockMode = "tidb_gc_scan_lock_mode"
 	// TiDBEnableEnhancedSecurity restricts SUPER users from certain operations.
 	TiDBEnableEnhancedSecurity = "tidb_enable_enhanced_security"
+	// TiDBTTLJobEnable enables the TTL jobs which delete the expired rows of TTL tables.
+	TiDBTTLJobEnable = "tidb_ttl_job_enable"
+	// TiDBTTLJobInterval is the interval between the starts of two TTL jobs of a table.
+	TiDBTTLJobInterval = "tidb_ttl_job_interval"
+	// TiDBTTLDeleteBatchSize is the number of expired rows deleted in a transaction by TTL jobs.
+	TiDBTTLDeleteBatchSize = "tidb_ttl_delete_batch_size"
+	// TiDBTTLDeleteRateLimit is the maximum number of rows deleted per second by a TTL job, 0 means no limit.
+	TiDBTTLDeleteRateLimit = "tidb_ttl_delete_rate_limit"
+	// TiDBStatsCacheMemQuota is the memory quota in bytes of the stats cache of each tidb-server, 0 means no limit.
+	TiDBStatsCacheMemQuota = "tidb_stats_cache_mem_quota"
+	// TiDBReadOnlyGracePeriod is how long in seconds the in-flight transactions can keep writing and committing
+	// after the server turns read-only.
+	TiDBReadOnlyGracePeriod = "tidb_read_only_grace_period"
 )
 
 // Default TiDB system variable values.
@@ -564,6 +681,8 @@ const (
 	DefAutoAnalyzeRatio                = 0.5
 	DefAutoAnalyzeStartTime            = "00:00 +0000"
 	DefAutoAnalyzeEndTime              = "23:59 +0000"
+	DefTiDBEnableAutoAnalyze           = true
+	DefTiDBAutoAnalyzeConcurrency      = 1
 	DefAutoIncrementIncrement          = 1
 	DefAutoIncrementOffset             = 1
 	DefChecksumTableConcurrency        = 4
@@ -621,6 +740,8 @@ const (
 	DefTiDBDDLReorgWorkerCount         = 4
 	DefTiDBDDLReorgBatchSize           = 256
 	DefTiDBDDLErrorCountLimit          = 512
+	DefTiDBDDLEnableFastReorg          = false
+	DefTiDBEnableConcurrentDDL         = false
 	DefTiDBMaxDeltaSchemaCount         = 1024
 	DefTiDBChangeColumnType            = false
 	DefTiDBChangeMultiSchema           = false
@@ -636,6 +757,9 @@ const (
 	DefEnableWindowFunction            = true
 	DefEnableStrictDoubleTypeCheck     = true
 	DefEnableVectorizedExpression      = true
+	DefEnableExprCodegen               = false
+	DefTiDBInvalidCharHandling         = "REPLACE"
+	DefTiDBEnableImplicitCastDiag      = false
 	DefTiDBOptJoinReorderThreshold     = 0
 	DefTiDBDDLSlowOprThreshold         = 300
 	DefTiDBUseFastAnalyze              = false
@@ -660,6 +784,8 @@ const (
 	DefTiDBEnableCollectExecutionInfo  = true
 	DefTiDBAllowAutoRandExplicitInsert = false
 	DefTiDBEnableClusteredIndex        = ClusteredIndexDefModeIntOnly
+	DefTiDBEnableGeneratedInvisiblePK  = false
+	DefTiDBShowGeneratedInvisiblePK    = false
 	DefTiDBRedactLog                   = false
 	DefTiDBShardAllocateStep           = math.MaxInt64
 	DefTiDBEnableTelemetry             = true
@@ -667,13 +793,39 @@ const (
 	DefTiDBEnableAmendPessimisticTxn   = false
 	DefTiDBPartitionPruneMode          = "static"
 	DefTiDBEnableRateLimitAction       = true
+	DefTiDBMemOOMActionChain           = "spill,rate_limit"
 	DefTiDBEnableAsyncCommit           = false
 	DefTiDBEnable1PC                   = false
 	DefTiDBGuaranteeLinearizability    = true
+	DefTiDBPipelinedAutoCommit         = false
 	DefTiDBAnalyzeVersion              = 1
+	DefTiDBAnalyzeSampleRate           = 0.0
+	DefTiDBForceAnalyzeLockedStats     = false
+	DefTiDBPersistAnalyzeOptions       = true
+	DefTiDBEnableColumnTracking        = false
+	DefTiDBAnalyzePredicateColumns     = false
 	DefTiDBEnableIndexMergeJoin        = false
 	DefTiDBTrackAggregateMemoryUsage   = true
 	DefTiDBEnableExchangePartition     = false
+	DefTiDBTTLJobEnable                = true
+	DefTiDBTTLJobInterval              = "1h0m0s"
+	DefTiDBTTLDeleteBatchSize          = 100
+	DefTiDBTTLDeleteRateLimit          = 0
+	DefTiDBStatsCacheMemQuota          = 0
+	DefTiDBIdleTransactionTimeout      = 0
+	DefTiDBMaxTransactionDuration      = 0
+	DefTiDBTmpTableMemQuota            = 64 << 20 // 64MB.
+	DefTiDBReadOnlyGracePeriod         = 10
+	DefTiDBLoadDataLocalCompression    = LoadDataCompressionNone
+	DefTiDBPreSplitIndexRegions        = 0
+)
+
+// The compressions of the files uploaded by LOAD DATA LOCAL INFILE.
+const (
+	LoadDataCompressionNone = "none"
+	LoadDataCompressionGzip = "gzip"
+	LoadDataCompressionZstd = "zstd"
+	LoadDataCompressionAuto = "auto"
 )
 
 // Process global variables.
@@ -684,6 +836,7 @@ var (
 	maxDDLReorgWorkerCount int32 = 128
 	ddlReorgBatchSize      int32 = DefTiDBDDLReorgBatchSize
 	ddlErrorCountlimit     int64 = DefTiDBDDLErrorCountLimit
+	ddlEnableFastReorg           = atomic.NewBool(DefTiDBDDLEnableFastReorg)
 	ddlReorgRowFormat      int64 = DefTiDBRowFormatV2
 	maxDeltaSchemaCount    int64 = DefTiDBMaxDeltaSchemaCount
 	// Export for testing.
@@ -699,6 +852,7 @@ var (
 	CapturePlanBaseline                   = serverGlobalVariable{globalVal: BoolOff}
 	DefExecutorConcurrency                = 5
 	MemoryUsageAlarmRatio                 = atomic.NewFloat64(config.GetGlobalConfig().Performance.MemoryUsageAlarmRatio)
+	SlowLogSampleRate                     = atomic.NewFloat64(config.GetGlobalConfig().Log.SlowLogSampleRate)
 )
 
 // FeatureSwitchVariables is used to filter result of show variables, these switches should be turn blind to users.
@@ -717,6 +871,12 @@ var FeatureSwitchVariables = []string{
 	TiDBEnableIndexMergeJoin,
 }
 
+// IsSessionStatesVar returns whether the variable is only used to migrate the session. They are not listed by
+// show variables because computing them may fail or sign a new session token, they should be read by name.
+func IsSessionStatesVar(name string) bool {
+	return name == TiDBSessionStates || name == TiDBSessionToken
+}
+
 // FilterImplicitFeatureSwitch is used to filter result of show variables, these switches should be turn blind to users.
 func FilterImplicitFeatureSwitch(sysVar *SysVar) bool {
 	for _, one := range FeatureSwitchVariables {
@@ -26,7 +26,9 @@ import (
 	"github.com/pingcap/parser/charset"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/sessionctx/sessionstates"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/encoding"
 	"github.com/pingcap/tidb/util/timeutil"
 )
 
@@ -74,6 +76,16 @@ func GetDDLErrorCountLimit() int64 {
 	return atomic.LoadInt64(&ddlErrorCountlimit)
 }
 
+// SetDDLEnableFastReorg sets whether to backfill the indexes by the local sorted ingest.
+func SetDDLEnableFastReorg(enable bool) {
+	ddlEnableFastReorg.Store(enable)
+}
+
+// GetDDLEnableFastReorg gets whether to backfill the indexes by the local sorted ingest.
+func GetDDLEnableFastReorg() bool {
+	return ddlEnableFastReorg.Load()
+}
+
 // SetDDLReorgRowFormat sets ddlReorgRowFormat version.
 func SetDDLReorgRowFormat(format int64) {
 	atomic.StoreInt64(&ddlReorgRowFormat, format)
@@ -120,6 +132,15 @@ func checkCharacterValid(normalizedValue string, argName string) (string, error)
 	return cht, nil
 }
 
+// checkClientCharacterValid is like checkCharacterValid, but it also accepts the character sets which
+// are only supported by the conversions between the client and the server, such as gbk.
+func checkClientCharacterValid(normalizedValue string, argName string) (string, error) {
+	if cht := strings.ToLower(normalizedValue); encoding.IsSupported(cht) && !charset.ValidCharsetAndCollation(cht, "") {
+		return cht, nil
+	}
+	return checkCharacterValid(normalizedValue, argName)
+}
+
 // checkReadOnly requires TiDBEnableNoopFuncs=1 for the same scope otherwise an error will be returned.
 func checkReadOnly(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag, offlineMode bool) (string, error) {
 	feature := "READ ONLY"
@@ -181,6 +202,29 @@ func GetSessionOnlySysVars(s *SessionVars, key string) (string, bool, error) {
 			return "", true, err
 		}
 		return string(info), true, nil
+	case TiDBSessionStates:
+		states := &sessionstates.SessionStates{}
+		if err := s.EncodeSessionStates(states); err != nil {
+			return "", true, err
+		}
+		info, err := json.Marshal(states)
+		if err != nil {
+			return "", true, err
+		}
+		return string(info), true, nil
+	case TiDBSessionToken:
+		if s.User == nil {
+			return "", true, errors.New("the session is not authenticated")
+		}
+		token, err := sessionstates.CreateSessionToken(s.User.Username, s.User.Hostname)
+		if err != nil {
+			return "", true, err
+		}
+		info, err := json.Marshal(token)
+		if err != nil {
+			return "", true, err
+		}
+		return string(info), true, nil
 	case TiDBGeneralLog:
 		return BoolToOnOff(ProcessGeneralLog.Load()), true, nil
 	case TiDBPProfSQLCPU:
@@ -210,6 +254,8 @@ func GetSessionOnlySysVars(s *SessionVars, key string) (string, bool, error) {
 		return config.GetGlobalConfig().Plugin.Load, true, nil
 	case TiDBSlowLogThreshold:
 		return strconv.FormatUint(atomic.LoadUint64(&config.GetGlobalConfig().Log.SlowThreshold), 10), true, nil
+	case TiDBSlowLogSampleRate:
+		return fmt.Sprintf("%g", SlowLogSampleRate.Load()), true, nil
 	case TiDBRecordPlanInSlowLog:
 		return strconv.FormatUint(uint64(atomic.LoadUint32(&config.GetGlobalConfig().Log.RecordPlanInSlowLog)), 10), true, nil
 	case TiDBEnableSlowLog:
@@ -510,3 +556,82 @@ func (v *serverGlobalVariable) GetVal() string {
 	}
 	return v.globalVal
 }
+
+// optimizerSwitchUseInvisibleIndexes is the flag of optimizer_switch which makes the optimizer use invisible indexes.
+// The other flags are only kept for the compatibility with MySQL.
+const optimizerSwitchUseInvisibleIndexes = "use_invisible_indexes"
+
+// defOptimizerSwitch is the default value of optimizer_switch.
+const defOptimizerSwitch = "index_merge=on,index_merge_union=on,index_merge_sort_union=on,index_merge_intersection=on," +
+	"engine_condition_pushdown=on,index_condition_pushdown=on,mrr=on,mrr_cost_based=on,block_nested_loop=on," +
+	"batched_key_access=off,materialization=on,semijoin=on,loosescan=on,firstmatch=on," +
+	"subquery_materialization_cost_based=on,use_index_extensions=on,use_invisible_indexes=off"
+
+// parseOptimizerSwitch parses the optimizer_switch value into the flag names in order and their values.
+func parseOptimizerSwitch(value string) ([]string, map[string]string) {
+	names := make([]string, 0, 20)
+	flags := make(map[string]string, 20)
+	for _, item := range strings.Split(value, ",") {
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSpace(kv[0]))
+		if _, ok := flags[name]; !ok {
+			names = append(names, name)
+		}
+		flags[name] = strings.ToLower(strings.TrimSpace(kv[1]))
+	}
+	return names, flags
+}
+
+// mergeOptimizerSwitch sets the flags in value to the optimizer_switch value current, the flags which
+// don't appear in value are unchanged. A flag can be set to "default", and "default" resets all the flags.
+func mergeOptimizerSwitch(current, value string) (string, error) {
+	names, defFlags := parseOptimizerSwitch(defOptimizerSwitch)
+	_, flags := parseOptimizerSwitch(current)
+	for _, name := range names {
+		if _, ok := flags[name]; !ok {
+			flags[name] = defFlags[name]
+		}
+	}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		if strings.EqualFold(item, "default") {
+			for name, val := range defFlags {
+				flags[name] = val
+			}
+			continue
+		}
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 {
+			return current, ErrWrongValueForVar.GenWithStackByArgs(OptimizerSwitch, value)
+		}
+		name, val := strings.ToLower(strings.TrimSpace(kv[0])), strings.ToLower(strings.TrimSpace(kv[1]))
+		if _, ok := defFlags[name]; !ok {
+			return current, ErrWrongValueForVar.GenWithStackByArgs(OptimizerSwitch, value)
+		}
+		switch val {
+		case "on", "off":
+		case "default":
+			val = defFlags[name]
+		default:
+			return current, ErrWrongValueForVar.GenWithStackByArgs(OptimizerSwitch, value)
+		}
+		flags[name] = val
+	}
+	items := make([]string, 0, len(names))
+	for _, name := range names {
+		items = append(items, name+"="+flags[name])
+	}
+	return strings.Join(items, ","), nil
+}
+
+// optimizerSwitchFlagOn checks whether the flag of the optimizer_switch value is on.
+func optimizerSwitchFlagOn(value, flag string) bool {
+	_, flags := parseOptimizerSwitch(value)
+	return flags[flag] == "on"
+}
@@ -16,6 +16,7 @@ package variable
 import (
 	"encoding/json"
 	"reflect"
+	"strings"
 	"time"
 
 	. "github.com/pingcap/check"
@@ -24,6 +25,7 @@ import (
 	"github.com/pingcap/tidb/config"
 	tikvstore "github.com/pingcap/tidb/store/tikv/kv"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/encoding"
 	"github.com/pingcap/tidb/util/testleak"
 )
 
@@ -162,6 +164,21 @@ func (s *testVarsutilSuite) TestVarsutil(c *C) {
 	c.Assert(collation, Equals, "utf8_general_ci")
 
 	c.Assert(SetSessionSystemVar(v, "character_set_results", types.Datum{}), IsNil)
+	// gbk is only supported by the conversions between the client and the server.
+	c.Assert(SetSessionSystemVar(v, "character_set_client", types.NewStringDatum("GBK")), IsNil)
+	c.Assert(SetSessionSystemVar(v, "character_set_results", types.NewStringDatum("gbk")), IsNil)
+	val, err = GetSessionSystemVar(v, CharacterSetClient)
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "gbk")
+	val, err = GetSessionSystemVar(v, CharacterSetResults)
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, "gbk")
+	c.Assert(SetSessionSystemVar(v, "character_set_connection", types.NewStringDatum("gbk")), NotNil)
+
+	c.Assert(SetSessionSystemVar(v, TiDBInvalidCharHandling, types.NewStringDatum("error")), IsNil)
+	c.Assert(v.InvalidCharHandling, Equals, encoding.InvalidCharError)
+	c.Assert(SetSessionSystemVar(v, TiDBInvalidCharHandling, types.NewStringDatum("REPLACE")), IsNil)
+	c.Assert(v.InvalidCharHandling, Equals, encoding.InvalidCharReplace)
 
 	// Test case for time_zone session variable.
 	tests := []struct {
@@ -724,3 +741,44 @@ func (s *testVarsutilSuite) TestConcurrencyVariables(c *C) {
 	c.Assert(vars.StreamAggConcurrency(), Equals, saConcurrency)
 
 }
+
+func (s *testVarsutilSuite) TestOptimizerSwitch(c *C) {
+	defer testleak.AfterTest(c)()
+	v := NewSessionVars()
+	v.GlobalVarsAccessor = NewMockGlobalAccessor()
+	c.Assert(v.UseInvisibleIndexes(), IsFalse)
+
+	err := SetSessionSystemVar(v, OptimizerSwitch, types.NewStringDatum("USE_INVISIBLE_INDEXES = ON"))
+	c.Assert(err, IsNil)
+	c.Assert(v.OptimizerUseInvisibleIndexes, IsTrue)
+	c.Assert(v.UseInvisibleIndexes(), IsTrue)
+	val, err := GetSessionSystemVar(v, OptimizerSwitch)
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, strings.Replace(defOptimizerSwitch, "use_invisible_indexes=off", "use_invisible_indexes=on", 1))
+
+	// The other flags are unchanged.
+	err = SetSessionSystemVar(v, OptimizerSwitch, types.NewStringDatum("mrr=off"))
+	c.Assert(err, IsNil)
+	val, err = GetSessionSystemVar(v, OptimizerSwitch)
+	c.Assert(err, IsNil)
+	c.Assert(strings.Contains(val, ",mrr=off,"), IsTrue)
+	c.Assert(v.UseInvisibleIndexes(), IsTrue)
+
+	// The statement variable set by SET_VAR hint takes precedence over the session variable.
+	c.Assert(SetStmtVar(v, OptimizerSwitch, "use_invisible_indexes=default"), IsNil)
+	c.Assert(v.UseInvisibleIndexes(), IsFalse)
+	v.ClearStmtVars()
+	c.Assert(v.UseInvisibleIndexes(), IsTrue)
+
+	err = SetSessionSystemVar(v, OptimizerSwitch, types.NewStringDatum("default"))
+	c.Assert(err, IsNil)
+	val, err = GetSessionSystemVar(v, OptimizerSwitch)
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, defOptimizerSwitch)
+	c.Assert(v.UseInvisibleIndexes(), IsFalse)
+
+	for _, invalid := range []string{"use_invisible_indexes", "unknown_flag=on", "use_invisible_indexes=yes"} {
+		err = SetSessionSystemVar(v, OptimizerSwitch, types.NewStringDatum(invalid))
+		c.Assert(ErrWrongValueForVar.Equal(err), IsTrue, Commentf("value %s", invalid))
+	}
+}
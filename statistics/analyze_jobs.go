@@ -30,11 +30,15 @@ var analyzeStatus = analyzeJobs{jobs: make(map[*AnalyzeJob]struct{}), history: m
 // AnalyzeJob is used to represent the status of one analyze job.
 type AnalyzeJob struct {
 	sync.Mutex
+	// ID is the ID of the job in mysql.analyze_jobs, 0 means the job is not persisted.
+	ID            uint64
 	DBName        string
 	TableName     string
 	PartitionName string
 	JobInfo       string
 	RowCount      int64
+	// TotalRowCount is the estimated number of rows to be processed, 0 means it is unknown.
+	TotalRowCount int64
 	StartTime     time.Time
 	EndTime       time.Time
 	State         string
@@ -131,6 +135,33 @@ func (job *AnalyzeJob) Finish(meetError bool) {
 	job.Mutex.Unlock()
 }
 
+// IsRunning returns whether the analyze job is running.
+func (job *AnalyzeJob) IsRunning() bool {
+	if job == nil {
+		return false
+	}
+	job.Mutex.Lock()
+	defer job.Mutex.Unlock()
+	return job.State == running
+}
+
+// Progress returns the percentage of the processed rows, it returns false if the number of rows to be processed is
+// unknown. The caller should hold the lock of the job.
+func (job *AnalyzeJob) Progress() (float64, bool) {
+	if job.State == finished {
+		return 100, true
+	}
+	if job.TotalRowCount <= 0 {
+		return 0, false
+	}
+	progress := float64(job.RowCount) / float64(job.TotalRowCount) * 100
+	// The number of rows to be processed is estimated by the stats, which may be less than the actual one.
+	if progress > 99 {
+		progress = 99
+	}
+	return progress, true
+}
+
 func (job *AnalyzeJob) getUpdateTime() time.Time {
 	if job == nil {
 		return time.Time{}
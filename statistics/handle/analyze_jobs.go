@@ -0,0 +1,165 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handle
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/statistics"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"go.uber.org/zap"
+)
+
+// analyzeJobsKeepDuration is how long the finished analyze jobs are kept in mysql.analyze_jobs.
+const analyzeJobsKeepDuration = 7 * 24 * time.Hour
+
+// analyzeJobInstance returns the address of the tidb-server, which is used to find the analyze jobs interrupted by the
+// restart of the tidb-server.
+func analyzeJobInstance() string {
+	cfg := config.GetGlobalConfig()
+	return net.JoinHostPort(cfg.AdvertiseAddress, strconv.Itoa(int(cfg.Port)))
+}
+
+func analyzeJobTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.Format(types.TimeFormat)
+}
+
+// InsertAnalyzeJob persists the analyze job into mysql.analyze_jobs and sets the ID of the job.
+func (h *Handle) InsertAnalyzeJob(job *statistics.AnalyzeJob) error {
+	if job == nil {
+		return nil
+	}
+	job.Lock()
+	dbName, tableName, partitionName, jobInfo, totalRows := job.DBName, job.TableName, job.PartitionName, job.JobInfo, job.TotalRowCount
+	job.Unlock()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	exec := h.mu.ctx.(sqlexec.SQLExecutor)
+	const sql = "insert into mysql.analyze_jobs (table_schema, table_name, partition_name, job_info, total_rows, state, instance) values (%?, %?, %?, %?, %?, 'pending', %?)"
+	if _, err := exec.ExecuteInternal(context.Background(), sql, dbName, tableName, partitionName, jobInfo, totalRows, analyzeJobInstance()); err != nil {
+		return errors.Trace(err)
+	}
+	id := h.mu.ctx.GetSessionVars().StmtCtx.LastInsertID
+	job.Lock()
+	job.ID = id
+	job.Unlock()
+	return nil
+}
+
+// UpdateAnalyzeJobProgress persists the state and the number of the processed rows of the analyze job.
+func (h *Handle) UpdateAnalyzeJobProgress(job *statistics.AnalyzeJob) error {
+	if job == nil {
+		return nil
+	}
+	job.Lock()
+	id, state, rowCount, startTime := job.ID, job.State, job.RowCount, job.StartTime
+	job.Unlock()
+	if id == 0 {
+		return nil
+	}
+	const sql = "update mysql.analyze_jobs set state = %?, processed_rows = %?, start_time = %? where id = %?"
+	_, _, err := h.execRestrictedSQL(context.Background(), sql, state, rowCount, analyzeJobTime(startTime), id)
+	return errors.Trace(err)
+}
+
+// FinishAnalyzeJob persists the final state of the analyze job.
+func (h *Handle) FinishAnalyzeJob(job *statistics.AnalyzeJob) error {
+	if job == nil {
+		return nil
+	}
+	job.Lock()
+	id, state, rowCount, startTime, endTime := job.ID, job.State, job.RowCount, job.StartTime, job.EndTime
+	job.Unlock()
+	if id == 0 {
+		return nil
+	}
+	const sql = "update mysql.analyze_jobs set state = %?, processed_rows = %?, start_time = %?, end_time = %? where id = %?"
+	_, _, err := h.execRestrictedSQL(context.Background(), sql, state, rowCount, analyzeJobTime(startTime), analyzeJobTime(endTime), id)
+	return errors.Trace(err)
+}
+
+// MarkInterruptedAnalyzeJobs marks the unfinished analyze jobs of this tidb-server as interrupted. It should be called
+// when the tidb-server starts, then the interrupted jobs are resumed by the auto analyze.
+func (h *Handle) MarkInterruptedAnalyzeJobs() error {
+	const sql = "update mysql.analyze_jobs set state = 'interrupted' where state in ('pending', 'running') and instance = %?"
+	_, _, err := h.execRestrictedSQL(context.Background(), sql, analyzeJobInstance())
+	return errors.Trace(err)
+}
+
+// resumeInterruptedAnalyzeJobs analyzes the tables, partitions or indexes again whose analyze jobs are interrupted.
+// The stats saved by the finished jobs of the same statement are kept, so only the unfinished parts are analyzed.
+func (h *Handle) resumeInterruptedAnalyzeJobs(is infoschema.InfoSchema) (analyzed bool) {
+	ctx := context.Background()
+	rows, _, err := h.execRestrictedSQL(ctx, "select id, table_schema, table_name, partition_name, job_info from mysql.analyze_jobs where state = 'interrupted' order by id")
+	if err != nil {
+		logutil.BgLogger().Error("[stats] get the interrupted analyze jobs failed", zap.Error(err))
+		return false
+	}
+	sqls := make([]string, 0, len(rows))
+	resumed := make(map[string]struct{}, len(rows))
+	for _, row := range rows {
+		id, dbName, tableName, partitionName, jobInfo := row.GetUint64(0), row.GetString(1), row.GetString(2), row.GetString(3), row.GetString(4)
+		// Mark the job as resumed first, so that the job is not resumed again and again if the analyze keeps failing.
+		if _, _, err := h.execRestrictedSQL(ctx, "update mysql.analyze_jobs set state = 'resumed' where id = %?", id); err != nil {
+			logutil.BgLogger().Error("[stats] mark the interrupted analyze job as resumed failed", zap.Uint64("id", id), zap.Error(err))
+			return false
+		}
+		if !is.TableExists(model.NewCIStr(dbName), model.NewCIStr(tableName)) {
+			continue
+		}
+		sql, params := "analyze table %n.%n", []interface{}{dbName, tableName}
+		if partitionName != "" {
+			sql += " partition %n"
+			params = append(params, partitionName)
+		}
+		jobInfo = strings.TrimPrefix(jobInfo, "auto ")
+		if strings.HasPrefix(jobInfo, "analyze index ") {
+			sql += " index %n"
+			params = append(params, strings.TrimPrefix(jobInfo, "analyze index "))
+		}
+		escaped, err := sqlexec.EscapeSQL(sql, params...)
+		if err != nil {
+			continue
+		}
+		if _, ok := resumed[escaped]; !ok {
+			resumed[escaped] = struct{}{}
+			sqls = append(sqls, escaped)
+		}
+	}
+	for _, sql := range sqls {
+		logutil.BgLogger().Info("[stats] resume the interrupted analyze job", zap.String("sql", sql))
+		h.execAutoAnalyze(h.mu.ctx.GetSessionVars().AnalyzeVersion, sql)
+	}
+	return len(sqls) > 0
+}
+
+// gcAnalyzeJobs removes the analyze jobs which are not updated for a long time.
+func (h *Handle) gcAnalyzeJobs() error {
+	const sql = "delete from mysql.analyze_jobs where update_time < %? and state not in ('pending', 'running', 'interrupted')"
+	_, _, err := h.execRestrictedSQL(context.Background(), sql, time.Now().Add(-analyzeJobsKeepDuration).Format(types.TimeFormat))
+	return errors.Trace(err)
+}
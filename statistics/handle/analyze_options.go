@@ -0,0 +1,75 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handle
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// SaveAnalyzeOptions saves the BUCKETS and TOPN options specified explicitly by the analyze of the tables, the options
+// saved before are kept if they are not specified this time.
+func (h *Handle) SaveAnalyzeOptions(tableIDs []int64, opts map[ast.AnalyzeOptionType]uint64) (err error) {
+	if len(opts) == 0 {
+		return nil
+	}
+	var buckets, topN interface{}
+	if val, ok := opts[ast.AnalyzeOptNumBuckets]; ok {
+		buckets = val
+	}
+	if val, ok := opts[ast.AnalyzeOptNumTopN]; ok {
+		topN = val
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ctx := context.Background()
+	exec := h.mu.ctx.(sqlexec.SQLExecutor)
+	_, err = exec.ExecuteInternal(ctx, "begin")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		err = finishTransaction(ctx, exec, err)
+	}()
+	for _, id := range tableIDs {
+		const sql = "insert into mysql.analyze_options (table_id, buckets, topn) values (%?, %?, %?) " +
+			"on duplicate key update buckets = ifnull(values(buckets), buckets), topn = ifnull(values(topn), topn)"
+		if _, err = exec.ExecuteInternal(ctx, sql, id, buckets, topN); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetAnalyzeOptions returns the BUCKETS and TOPN options saved for the table.
+func (h *Handle) GetAnalyzeOptions(tableID int64) (map[ast.AnalyzeOptionType]uint64, error) {
+	rows, _, err := h.execRestrictedSQL(context.Background(), "select buckets, topn from mysql.analyze_options where table_id = %?", tableID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	opts := make(map[ast.AnalyzeOptionType]uint64, 2)
+	if len(rows) == 0 {
+		return opts, nil
+	}
+	if !rows[0].IsNull(0) {
+		opts[ast.AnalyzeOptNumBuckets] = uint64(rows[0].GetInt64(0))
+	}
+	if !rows[0].IsNull(1) {
+		opts[ast.AnalyzeOptNumTopN] = uint64(rows[0].GetInt64(1))
+	}
+	return opts, nil
+}
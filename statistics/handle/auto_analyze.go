@@ -0,0 +1,199 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handle
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/statistics"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/timeutil"
+	"go.uber.org/zap"
+)
+
+// autoAnalyzeJob is a job of the auto analyze, which analyzes a table or some partitions, or merges the global-level
+// stats of a partitioned table.
+type autoAnalyzeJob struct {
+	priority float64
+	// seq is the order the job is pushed, the jobs of the same priority are run in this order.
+	seq  int
+	exec func() bool
+}
+
+func (h *Handle) newAutoAnalyzeJob(statsTbl *statistics.Table, queryCount int64, statsVer int, msg, escaped, reason, sql string, params ...interface{}) *autoAnalyzeJob {
+	return &autoAnalyzeJob{
+		priority: autoAnalyzePriority(statsTbl, queryCount),
+		exec: func() bool {
+			logutil.BgLogger().Info(msg, zap.String("sql", escaped), zap.String("reason", reason))
+			h.execAutoAnalyze(statsVer, sql, params...)
+			return true
+		},
+	}
+}
+
+// autoAnalyzeStaleness returns the ratio of the modified rows since the last analyze, the unanalyzed stats are at
+// least as stale as the stats whose rows are all modified.
+func autoAnalyzeStaleness(statsTbl *statistics.Table) float64 {
+	staleness := 1.0
+	if statsTbl.Count > 0 {
+		staleness = float64(statsTbl.ModifyCount) / float64(statsTbl.Count)
+	}
+	if !TableAnalyzed(statsTbl) {
+		staleness = math.Max(staleness, 1)
+	}
+	return staleness
+}
+
+// autoAnalyzePriority returns the priority of the job which analyzes the stats, it's the product of the staleness,
+// the size and the query frequency. The size and the query frequency are log-scaled to prevent the large or hot
+// tables from starving the others.
+func autoAnalyzePriority(statsTbl *statistics.Table, queryCount int64) float64 {
+	return autoAnalyzeStaleness(statsTbl) * math.Log2(float64(statsTbl.Count)+2) * math.Log2(float64(queryCount)+2)
+}
+
+// autoAnalyzeQueue is a priority queue of the auto analyze jobs, the job of the highest priority is popped first.
+type autoAnalyzeQueue struct {
+	mu   sync.Mutex
+	jobs []*autoAnalyzeJob
+	seq  int
+}
+
+// Len implements the heap.Interface.
+func (q *autoAnalyzeQueue) Len() int {
+	return len(q.jobs)
+}
+
+// Less implements the heap.Interface.
+func (q *autoAnalyzeQueue) Less(i, j int) bool {
+	if q.jobs[i].priority != q.jobs[j].priority {
+		return q.jobs[i].priority > q.jobs[j].priority
+	}
+	return q.jobs[i].seq < q.jobs[j].seq
+}
+
+// Swap implements the heap.Interface.
+func (q *autoAnalyzeQueue) Swap(i, j int) {
+	q.jobs[i], q.jobs[j] = q.jobs[j], q.jobs[i]
+}
+
+// Push implements the heap.Interface.
+func (q *autoAnalyzeQueue) Push(x interface{}) {
+	q.jobs = append(q.jobs, x.(*autoAnalyzeJob))
+}
+
+// Pop implements the heap.Interface.
+func (q *autoAnalyzeQueue) Pop() interface{} {
+	n := len(q.jobs)
+	job := q.jobs[n-1]
+	q.jobs = q.jobs[:n-1]
+	return job
+}
+
+func (q *autoAnalyzeQueue) push(job *autoAnalyzeJob) {
+	if job == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job.seq = q.seq
+	q.seq++
+	heap.Push(q, job)
+}
+
+func (q *autoAnalyzeQueue) pop() *autoAnalyzeJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.jobs) == 0 {
+		return nil
+	}
+	return heap.Pop(q).(*autoAnalyzeJob)
+}
+
+// runAutoAnalyzeJobs runs the jobs in the queue by at most `concurrency` workers. The auto analyze parameters are
+// checked again before each job starts, so the remaining jobs are dropped once the auto analyze is paused or the
+// time is out of the auto analyze period.
+func (h *Handle) runAutoAnalyzeJobs(queue *autoAnalyzeQueue, concurrency int) (analyzed bool) {
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	workers := concurrency
+	if queue.Len() < workers {
+		workers = queue.Len()
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go util.WithRecovery(func() {
+			defer wg.Done()
+			for {
+				job := queue.pop()
+				if job == nil || !h.autoAnalyzeAllowed() {
+					return
+				}
+				if job.exec() {
+					mu.Lock()
+					analyzed = true
+					mu.Unlock()
+				}
+			}
+		}, func(r interface{}) {
+			if r != nil {
+				logutil.BgLogger().Error("[stats] auto analyze worker panicked", zap.Reflect("r", r), zap.Stack("stack"))
+			}
+		})
+	}
+	wg.Wait()
+	return analyzed
+}
+
+// autoAnalyzeAllowed checks whether a new auto analyze job can be started by the freshest parameters.
+func (h *Handle) autoAnalyzeAllowed() bool {
+	parameters := h.getAutoAnalyzeParameters()
+	if !parseEnableAutoAnalyze(parameters[variable.TiDBEnableAutoAnalyze]) {
+		return false
+	}
+	start, end, err := parseAnalyzePeriod(parameters[variable.TiDBAutoAnalyzeStartTime], parameters[variable.TiDBAutoAnalyzeEndTime])
+	if err != nil {
+		logutil.BgLogger().Error("[stats] parse auto analyze period failed", zap.Error(err))
+		return false
+	}
+	return timeutil.WithinDayTimePeriod(start, end, time.Now())
+}
+
+// getTableQueryCounts returns the number of the queries reading the tables by their indexes.
+func (h *Handle) getTableQueryCounts() (map[int64]int64, error) {
+	rows, _, err := h.execRestrictedSQL(context.Background(), "select table_id, sum(query_count) from mysql.schema_index_usage group by table_id")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	queryCounts := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		if row.IsNull(1) {
+			continue
+		}
+		count, err := row.GetMyDecimal(1).ToInt()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		queryCounts[row.GetInt64(0)] = count
+	}
+	return queryCounts, nil
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/tidb/ddl/util"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/sqlexec"
 )
@@ -83,6 +84,14 @@ func (h *Handle) updateGlobalStats(tblInfo *model.TableInfo) error {
 	if globalStats == nil {
 		return nil
 	}
+	// Keep the stats version of the current global-stats.
+	statsVer := statistics.Version2
+	for _, col := range globalStats.Columns {
+		if col.StatsVer != statistics.Version0 {
+			statsVer = int(col.StatsVer)
+			break
+		}
+	}
 	opts := make(map[ast.AnalyzeOptionType]uint64, len(analyzeOptionDefault))
 	for key, val := range analyzeOptionDefault {
 		opts[key] = val
@@ -108,46 +117,58 @@ func (h *Handle) updateGlobalStats(tblInfo *model.TableInfo) error {
 	// Generate the new column global-stats
 	newColGlobalStats, err := h.mergePartitionStats2GlobalStats(h.mu.ctx, opts, is, tblInfo, 0, 0)
 	if err != nil {
+		if types.ErrPartitionStatsMissing.Equal(err) {
+			// Some partitions are not analyzed yet, the global-stats will be merged after they are analyzed.
+			return nil
+		}
 		return err
 	}
 	for i := 0; i < newColGlobalStats.Num; i++ {
 		hg, cms, topN, fms := newColGlobalStats.Hg[i], newColGlobalStats.Cms[i], newColGlobalStats.TopN[i], newColGlobalStats.Fms[i]
-		err = h.SaveStatsToStorage(tableID, newColGlobalStats.Count, 0, hg, cms, topN, fms, 2, 1)
+		err = h.SaveStatsToStorage(tableID, newColGlobalStats.Count, 0, hg, cms, topN, fms, statsVer, 1)
 		if err != nil {
 			return err
 		}
 	}
+	partitionVersions := newColGlobalStats.PartitionVersions
 
 	// Generate the new index global-stats
-	globalIdxStatsTopNNum, globalIdxStatsBucketNum := 0, 0
-	for idx := range tblInfo.Indices {
-		globalIdxStatsTopN := globalStats.Indices[int64(idx)].TopN
-		if globalIdxStatsTopN != nil && len(globalIdxStatsTopN.TopN) > globalIdxStatsTopNNum {
-			globalIdxStatsTopNNum = len(globalIdxStatsTopN.TopN)
+	for _, idx := range tblInfo.Indices {
+		globalIdxStats := globalStats.Indices[idx.ID]
+		if globalIdxStats == nil {
+			continue
 		}
-		globalIdxStats := globalStats.Indices[int64(idx)]
-		if globalIdxStats != nil && len(globalIdxStats.Buckets) > globalIdxStatsBucketNum {
-			globalIdxStatsBucketNum = len(globalIdxStats.Buckets)
+		idxOpts := make(map[ast.AnalyzeOptionType]uint64, len(analyzeOptionDefault))
+		for key, val := range analyzeOptionDefault {
+			idxOpts[key] = val
 		}
-		if globalIdxStatsTopNNum != 0 {
-			opts[ast.AnalyzeOptNumTopN] = uint64(globalIdxStatsTopNNum)
+		if globalIdxStats.TopN != nil && len(globalIdxStats.TopN.TopN) != 0 {
+			idxOpts[ast.AnalyzeOptNumTopN] = uint64(len(globalIdxStats.TopN.TopN))
 		}
-		if globalIdxStatsBucketNum != 0 {
-			opts[ast.AnalyzeOptNumBuckets] = uint64(globalIdxStatsBucketNum)
+		if len(globalIdxStats.Buckets) != 0 {
+			idxOpts[ast.AnalyzeOptNumBuckets] = uint64(len(globalIdxStats.Buckets))
 		}
-		newIndexGlobalStats, err := h.mergePartitionStats2GlobalStats(h.mu.ctx, opts, is, tblInfo, 1, int64(idx))
+		newIndexGlobalStats, err := h.mergePartitionStats2GlobalStats(h.mu.ctx, idxOpts, is, tblInfo, 1, idx.ID)
 		if err != nil {
+			if types.ErrPartitionStatsMissing.Equal(err) {
+				continue
+			}
 			return err
 		}
 		for i := 0; i < newIndexGlobalStats.Num; i++ {
 			hg, cms, topN, fms := newIndexGlobalStats.Hg[i], newIndexGlobalStats.Cms[i], newIndexGlobalStats.TopN[i], newIndexGlobalStats.Fms[i]
-			err = h.SaveStatsToStorage(tableID, newIndexGlobalStats.Count, 1, hg, cms, topN, fms, 2, 1)
+			err = h.SaveStatsToStorage(tableID, newIndexGlobalStats.Count, 1, hg, cms, topN, fms, statsVer, 1)
 			if err != nil {
 				return err
 			}
 		}
+		for partitionID, version := range newIndexGlobalStats.PartitionVersions {
+			if version > partitionVersions[partitionID] {
+				partitionVersions[partitionID] = version
+			}
+		}
 	}
-	return nil
+	return h.SaveGlobalStatsVersions(tableID, partitionVersions)
 }
 
 func (h *Handle) getInitStateTableIDs(tblInfo *model.TableInfo) (ids []int64) {
@@ -14,6 +14,10 @@
 package handle
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
 	"time"
 
 	"github.com/pingcap/errors"
@@ -28,16 +32,22 @@ import (
 	"github.com/pingcap/tipb/go-tipb"
 )
 
+// JSONTableFormatVersion is the version of the format of the dumped statistics. It's increased when the format is
+// changed incompatibly, and the statistics dumped by a newer version are refused to be loaded.
+const JSONTableFormatVersion = 1
+
 // JSONTable is used for dumping statistics.
 type JSONTable struct {
-	DatabaseName string                 `json:"database_name"`
-	TableName    string                 `json:"table_name"`
-	Columns      map[string]*jsonColumn `json:"columns"`
-	Indices      map[string]*jsonColumn `json:"indices"`
-	ExtStats     []*jsonExtendedStats   `json:"ext_stats"`
-	Count        int64                  `json:"count"`
-	ModifyCount  int64                  `json:"modify_count"`
-	Partitions   map[string]*JSONTable  `json:"partitions"`
+	// FormatVersion is 0 for the statistics dumped before the format is versioned.
+	FormatVersion int                    `json:"format_version,omitempty"`
+	DatabaseName  string                 `json:"database_name"`
+	TableName     string                 `json:"table_name"`
+	Columns       map[string]*jsonColumn `json:"columns"`
+	Indices       map[string]*jsonColumn `json:"indices"`
+	ExtStats      []*jsonExtendedStats   `json:"ext_stats"`
+	Count         int64                  `json:"count"`
+	ModifyCount   int64                  `json:"modify_count"`
+	Partitions    map[string]*JSONTable  `json:"partitions"`
 }
 
 type jsonExtendedStats struct {
@@ -127,12 +137,18 @@ func (h *Handle) DumpStatsToJSON(dbName string, tableInfo *model.TableInfo, hist
 func (h *Handle) DumpStatsToJSONBySnapshot(dbName string, tableInfo *model.TableInfo, snapshot uint64) (*JSONTable, error) {
 	pi := tableInfo.GetPartitionInfo()
 	if pi == nil {
-		return h.tableStatsToJSON(dbName, tableInfo, tableInfo.ID, snapshot)
+		jsonTbl, err := h.tableStatsToJSON(dbName, tableInfo, tableInfo.ID, snapshot)
+		if err != nil || jsonTbl == nil {
+			return jsonTbl, err
+		}
+		jsonTbl.FormatVersion = JSONTableFormatVersion
+		return jsonTbl, nil
 	}
 	jsonTbl := &JSONTable{
-		DatabaseName: dbName,
-		TableName:    tableInfo.Name.L,
-		Partitions:   make(map[string]*JSONTable, len(pi.Definitions)),
+		FormatVersion: JSONTableFormatVersion,
+		DatabaseName:  dbName,
+		TableName:     tableInfo.Name.L,
+		Partitions:    make(map[string]*JSONTable, len(pi.Definitions)),
 	}
 	for _, def := range pi.Definitions {
 		tbl, err := h.tableStatsToJSON(dbName, tableInfo, def.ID, snapshot)
@@ -183,7 +199,7 @@ func (h *Handle) tableStatsToJSON(dbName string, tableInfo *model.TableInfo, phy
 	}
 
 	for _, idx := range tbl.Indices {
-		jsonTbl.Indices[idx.Info.Name.L] = dumpJSONCol(&idx.Histogram, idx.CMSketch, idx.TopN, nil, &idx.StatsVer)
+		jsonTbl.Indices[idx.Info.Name.L] = dumpJSONCol(&idx.Histogram, idx.CMSketch, idx.TopN, idx.FMSketch, &idx.StatsVer)
 	}
 	jsonTbl.ExtStats = dumpJSONExtendedStats(tbl.ExtendedStats)
 	return jsonTbl, nil
@@ -191,6 +207,9 @@ func (h *Handle) tableStatsToJSON(dbName string, tableInfo *model.TableInfo, phy
 
 // LoadStatsFromJSON will load statistic from JSONTable, and save it to the storage.
 func (h *Handle) LoadStatsFromJSON(is infoschema.InfoSchema, jsonTbl *JSONTable) error {
+	if jsonTbl.FormatVersion > JSONTableFormatVersion {
+		return errors.Errorf("the format version %d of the statistics is newer than the supported version %d", jsonTbl.FormatVersion, JSONTableFormatVersion)
+	}
 	table, err := is.TableByName(model.NewCIStr(jsonTbl.DatabaseName), model.NewCIStr(jsonTbl.TableName))
 	if err != nil {
 		return errors.Trace(err)
@@ -236,7 +255,7 @@ func (h *Handle) loadStatsFromJSON(tableInfo *model.TableInfo, physicalID int64,
 		}
 	}
 	for _, idx := range tbl.Indices {
-		err = h.SaveStatsToStorage(tbl.PhysicalID, tbl.Count, 1, &idx.Histogram, idx.CMSketch, idx.TopN, nil, int(idx.StatsVer), 1)
+		err = h.SaveStatsToStorage(tbl.PhysicalID, tbl.Count, 1, &idx.Histogram, idx.CMSketch, idx.TopN, idx.FMSketch, int(idx.StatsVer), 1)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -279,6 +298,7 @@ func TableStatsFromJSON(tableInfo *model.TableInfo, physicalID int64, jsonTbl *J
 				Histogram: *hist,
 				CMSketch:  cm,
 				TopN:      topN,
+				FMSketch:  statistics.FMSketchFromProto(jsonIdx.FMSketch),
 				Info:      idxInfo,
 				StatsVer:  statsVer,
 			}
@@ -323,3 +343,38 @@ func TableStatsFromJSON(tableInfo *model.TableInfo, physicalID int64, jsonTbl *J
 	tbl.ExtendedStats = extendedStatsFromJSON(jsonTbl.ExtStats)
 	return tbl, nil
 }
+
+// CompressJSONTable encodes the statistics into the JSON compressed by gzip.
+func CompressJSONTable(jsonTbl *JSONTable) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(w).Encode(jsonTbl); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodeJSONTable decodes the statistics from the JSON, which is decompressed first if it's compressed by gzip.
+func DecodeJSONTable(data []byte) (*JSONTable, error) {
+	if len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b {
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		data, err = ioutil.ReadAll(r)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err = r.Close(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	jsonTbl := &JSONTable{}
+	if err := json.Unmarshal(data, jsonTbl); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return jsonTbl, nil
+}
@@ -337,3 +337,46 @@ func (s *testStatsSuite) TestDumpVer2Stats(c *C) {
 	// the statistics.Table in the stats cache is the same as the unmarshalled statistics.Table
 	assertTableEqual(c, statsCacheTbl, loadTbl)
 }
+
+func (s *testStatsSuite) TestDumpCompressedStats(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("set @@tidb_analyze_version = 2")
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b varchar(10), index idx(a, b))")
+	tk.MustExec("insert into t value(1, 'aaa'), (3, 'aab'), (5, 'bba'), (2, 'bbb'), (4, 'cca'), (6, 'ccc')")
+	tk.MustExec("analyze table t")
+	h := s.do.StatsHandle()
+	is := s.do.InfoSchema()
+	tableInfo, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tbl := h.GetTableStats(tableInfo.Meta())
+
+	dumpJSONTable, err := h.DumpStatsToJSON("test", tableInfo.Meta(), nil)
+	c.Assert(err, IsNil)
+	c.Assert(dumpJSONTable.FormatVersion, Equals, handle.JSONTableFormatVersion)
+	c.Assert(dumpJSONTable.Indices["idx"].FMSketch, NotNil)
+	compressed, err := handle.CompressJSONTable(dumpJSONTable)
+	c.Assert(err, IsNil)
+	jsonBytes, err := json.Marshal(dumpJSONTable)
+	c.Assert(err, IsNil)
+	c.Assert(len(compressed), Less, len(jsonBytes))
+
+	// Both the compressed and the plain statistics can be decoded.
+	loadJSONTable, err := handle.DecodeJSONTable(compressed)
+	c.Assert(err, IsNil)
+	c.Assert(loadJSONTable, DeepEquals, dumpJSONTable)
+	loadJSONTable, err = handle.DecodeJSONTable(jsonBytes)
+	c.Assert(err, IsNil)
+	c.Assert(loadJSONTable, DeepEquals, dumpJSONTable)
+
+	tk.MustExec("drop stats t")
+	c.Assert(h.LoadStatsFromJSON(is, loadJSONTable), IsNil)
+	c.Assert(h.Update(is), IsNil)
+	assertTableEqual(c, h.GetTableStats(tableInfo.Meta()), tbl)
+
+	// The statistics dumped in a newer format are refused.
+	loadJSONTable.FormatVersion = handle.JSONTableFormatVersion + 1
+	c.Assert(h.LoadStatsFromJSON(is, loadJSONTable), ErrorMatches, ".*newer than the supported version.*")
+}
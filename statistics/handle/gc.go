@@ -49,6 +49,9 @@ func (h *Handle) GCStats(is infoschema.InfoSchema, ddlLease time.Duration) error
 			return errors.Trace(err)
 		}
 	}
+	if err := h.gcAnalyzeJobs(); err != nil {
+		return errors.Trace(err)
+	}
 	return h.removeDeletedExtendedStats(gcVer)
 }
 
@@ -65,6 +68,18 @@ func (h *Handle) gcTableStats(is infoschema.InfoSchema, physicalID int64) error
 		if err != nil {
 			return errors.Trace(err)
 		}
+		_, _, err = h.execRestrictedSQL(ctx, "delete from mysql.stats_table_locked where table_id = %?", physicalID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		_, _, err = h.execRestrictedSQL(ctx, "delete from mysql.analyze_options where table_id = %?", physicalID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		_, _, err = h.execRestrictedSQL(ctx, "delete from mysql.column_stats_usage where table_id = %?", physicalID)
+		if err != nil {
+			return errors.Trace(err)
+		}
 	}
 	h.mu.Lock()
 	tbl, ok := h.getTableByPhysicalID(is, physicalID)
@@ -220,6 +235,9 @@ func (h *Handle) DeleteTableStatsFromKV(statsIDs []int64) (err error) {
 		if _, err = exec.ExecuteInternal(ctx, "delete from mysql.stats_fm_sketch where table_id = %?", statsID); err != nil {
 			return err
 		}
+		if _, err = exec.ExecuteInternal(ctx, "delete from mysql.stats_global_merge where table_id = %?", statsID); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -32,6 +32,7 @@ import (
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/ddl/util"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
@@ -89,6 +90,11 @@ type Handle struct {
 		atomic.Value
 		memTracker *memory.Tracker
 	}
+	// statsLRU records the order in which the tables in the stats cache are used, it decides which tables' column
+	// stats are evicted when the memory usage of the stats cache exceeds statsCacheMemQuota.
+	statsLRU *statsLRU
+	// statsCacheMemQuota is the memory quota in bytes of the stats cache, 0 means no limit.
+	statsCacheMemQuota atomic2.Int64
 
 	pool sessionPool
 
@@ -101,6 +107,8 @@ type Handle struct {
 	globalMap tableDeltaMap
 	// feedback is used to store query feedback info.
 	feedback *statistics.QueryFeedbackMap
+	// predicateColumns contains the predicate columns from collectors when we dump them to KV.
+	predicateColumns predicateColumnsMap
 
 	lease atomic2.Duration
 
@@ -163,6 +171,7 @@ func (h *Handle) Clear() {
 	h.statsCache.Store(statsCache{tables: make(map[int64]*statistics.Table)})
 	h.statsCache.memTracker = memory.NewTracker(memory.LabelForStatsCache, -1)
 	h.statsCache.Unlock()
+	h.statsLRU = newStatsLRU()
 	for len(h.ddlEventCh) > 0 {
 		<-h.ddlEventCh
 	}
@@ -171,8 +180,9 @@ func (h *Handle) Clear() {
 	h.mu.ctx.GetSessionVars().MaxChunkSize = 1
 	h.mu.ctx.GetSessionVars().EnableChunkRPC = false
 	h.mu.ctx.GetSessionVars().SetProjectionConcurrency(0)
-	h.listHead = &SessionStatsCollector{mapper: make(tableDeltaMap), rateMap: make(errorRateDeltaMap)}
+	h.listHead = &SessionStatsCollector{mapper: make(tableDeltaMap), rateMap: make(errorRateDeltaMap), colMap: make(predicateColumnsMap)}
 	h.globalMap = make(tableDeltaMap)
+	h.predicateColumns = make(predicateColumnsMap)
 	h.mu.rateMap = make(errorRateDeltaMap)
 	h.mu.Unlock()
 }
@@ -186,10 +196,12 @@ type sessionPool interface {
 func NewHandle(ctx sessionctx.Context, lease time.Duration, pool sessionPool) (*Handle, error) {
 	handle := &Handle{
 		ddlEventCh:       make(chan *util.Event, 100),
-		listHead:         &SessionStatsCollector{mapper: make(tableDeltaMap), rateMap: make(errorRateDeltaMap)},
+		listHead:         &SessionStatsCollector{mapper: make(tableDeltaMap), rateMap: make(errorRateDeltaMap), colMap: make(predicateColumnsMap)},
 		globalMap:        make(tableDeltaMap),
+		predicateColumns: make(predicateColumnsMap),
 		feedback:         statistics.NewQueryFeedbackMap(),
 		idxUsageListHead: &SessionIndexUsageCollector{mapper: make(indexUsageMap)},
+		statsLRU:         newStatsLRU(),
 		pool:             pool,
 	}
 	handle.lease.Store(lease)
@@ -283,6 +295,9 @@ func (h *Handle) Update(is infoschema.InfoSchema) error {
 		tables = append(tables, tbl)
 	}
 	h.updateStatsCache(oldCache.update(tables, deletedTableIDs, lastVersion))
+	h.statsLRU.remove(deletedTableIDs)
+	h.updateStatsCacheMemQuota()
+	h.evictStatsCache()
 	return nil
 }
 
@@ -314,6 +329,8 @@ type GlobalStats struct {
 	Cms   []*statistics.CMSketch
 	TopN  []*statistics.TopN
 	Fms   []*statistics.FMSketch
+	// PartitionVersions is the versions of the partition-level stats which are merged, the key is the partition ID.
+	PartitionVersions map[int64]uint64
 }
 
 // MergePartitionStats2GlobalStatsByTableID merge the partition-level stats to global-level stats based on the tableID.
@@ -350,6 +367,7 @@ func (h *Handle) mergePartitionStats2GlobalStats(sc sessionctx.Context, opts map
 	globalStats.Cms = make([]*statistics.CMSketch, globalStats.Num)
 	globalStats.TopN = make([]*statistics.TopN, globalStats.Num)
 	globalStats.Fms = make([]*statistics.FMSketch, globalStats.Num)
+	globalStats.PartitionVersions = make(map[int64]uint64, partitionNum)
 
 	// The first dimension of slice is means the number of column or index stats in the globalStats.
 	// The second dimension of slice is means the number of partition tables.
@@ -381,7 +399,7 @@ func (h *Handle) mergePartitionStats2GlobalStats(sc sessionctx.Context, opts map
 			return
 		}
 		// if the err == nil && partitionStats == nil, it means we lack the partition-level stats which the physicalID is equal to partitionID.
-		if partitionStats == nil {
+		if partitionStats == nil || (isIndex != 0 && partitionStats.Indices[idxID] == nil) {
 			var errMsg string
 			if isIndex == 0 {
 				errMsg = fmt.Sprintf("`%s`", tableInfo.Name.L)
@@ -397,11 +415,15 @@ func (h *Handle) mergePartitionStats2GlobalStats(sc sessionctx.Context, opts map
 			err = types.ErrPartitionStatsMissing.GenWithStackByArgs(errMsg)
 			return
 		}
+		globalStats.PartitionVersions[partitionID] = PartitionStatsVersion(partitionStats)
 		for i := 0; i < globalStats.Num; i++ {
 			ID := tableInfo.Columns[i].ID
 			if isIndex != 0 {
 				// If the statistics is the index stats, we should use the index ID to replace the column ID.
 				ID = idxID
+			} else if partitionStats.Columns[ID] == nil {
+				err = types.ErrPartitionStatsMissing.GenWithStackByArgs(fmt.Sprintf("`%s` column: `%s`", tableInfo.Name.L, tableInfo.Columns[i].Name.L))
+				return
 			}
 			count, hg, cms, topN, fms := partitionStats.GetStatsInfo(ID, isIndex == 1)
 			if i == 0 {
@@ -463,6 +485,82 @@ func (h *Handle) mergePartitionStats2GlobalStats(sc sessionctx.Context, opts map
 	return
 }
 
+// PartitionStatsVersion returns the version of the partition-level stats, which is the latest version of its histograms.
+func PartitionStatsVersion(partitionStats *statistics.Table) uint64 {
+	var version uint64
+	for _, col := range partitionStats.Columns {
+		if col.LastUpdateVersion > version {
+			version = col.LastUpdateVersion
+		}
+	}
+	for _, idx := range partitionStats.Indices {
+		if idx.LastUpdateVersion > version {
+			version = idx.LastUpdateVersion
+		}
+	}
+	return version
+}
+
+// SaveGlobalStatsVersions saves the versions of the partition-level stats which are merged into the global-level stats.
+// The versions of the partitions which aren't merged this time are removed.
+func (h *Handle) SaveGlobalStatsVersions(tableID int64, partitionVersions map[int64]uint64) (err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ctx := context.TODO()
+	exec := h.mu.ctx.(sqlexec.SQLExecutor)
+	_, err = exec.ExecuteInternal(ctx, "begin")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		err = finishTransaction(context.Background(), exec, err)
+	}()
+	if _, err = exec.ExecuteInternal(ctx, "delete from mysql.stats_global_merge where table_id = %?", tableID); err != nil {
+		return err
+	}
+	for partitionID, version := range partitionVersions {
+		if _, err = exec.ExecuteInternal(ctx, "insert into mysql.stats_global_merge (table_id, partition_id, version) values (%?, %?, %?)", tableID, partitionID, version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GlobalStatsOutdatedPartitions returns the partitions which are analyzed, added or dropped after the global-level stats
+// of the table are merged. It returns nil if the global-level stats of the table are never merged.
+func (h *Handle) GlobalStatsOutdatedPartitions(tblInfo *model.TableInfo) ([]int64, error) {
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil {
+		return nil, nil
+	}
+	rows, _, err := h.execRestrictedSQL(context.Background(), "select partition_id, version from mysql.stats_global_merge where table_id = %?", tblInfo.ID)
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	mergedVersions := make(map[int64]uint64, len(rows))
+	for _, row := range rows {
+		mergedVersions[row.GetInt64(0)] = row.GetUint64(1)
+	}
+	var outdated []int64
+	for _, def := range pi.Definitions {
+		version, ok := mergedVersions[def.ID]
+		delete(mergedVersions, def.ID)
+		if !ok {
+			outdated = append(outdated, def.ID)
+			continue
+		}
+		partitionStats := h.GetPartitionStats(tblInfo, def.ID)
+		if !partitionStats.Pseudo && PartitionStatsVersion(partitionStats) > version {
+			outdated = append(outdated, def.ID)
+		}
+	}
+	// The remaining ones are the dropped partitions.
+	for partitionID := range mergedVersions {
+		outdated = append(outdated, partitionID)
+	}
+	return outdated, nil
+}
+
 func (h *Handle) getTableByPhysicalID(is infoschema.InfoSchema, physicalID int64) (table.Table, bool) {
 	if is.SchemaMetaVersion() != h.mu.schemaVersion {
 		h.mu.schemaVersion = is.SchemaMetaVersion()
@@ -507,11 +605,16 @@ func (h *Handle) GetPartitionStats(tblInfo *model.TableInfo, pid int64) *statist
 	statsCache := h.statsCache.Load().(statsCache)
 	tbl, ok := statsCache.tables[pid]
 	if !ok {
+		statsCacheMissCounter.Inc()
 		tbl = statistics.PseudoTable(tblInfo)
 		tbl.PhysicalID = pid
 		h.updateStatsCache(statsCache.update([]*statistics.Table{tbl}, nil, statsCache.version))
 		return tbl
 	}
+	statsCacheHitCounter.Inc()
+	if h.statsCacheMemQuota.Load() > 0 {
+		h.statsLRU.touch(pid)
+	}
 	return tbl
 }
 
@@ -594,6 +697,7 @@ func (h *Handle) LoadNeededHistograms() (err error) {
 		}
 	}()
 
+	defer h.evictStatsCache()
 	for _, col := range cols {
 		oldCache := h.statsCache.Load().(statsCache)
 		tbl, ok := oldCache.tables[col.TableID]
@@ -605,6 +709,7 @@ func (h *Handle) LoadNeededHistograms() (err error) {
 			statistics.HistogramNeededColumns.Delete(col)
 			continue
 		}
+		start := time.Now()
 		hg, err := h.histogramFromStorage(reader, col.TableID, c.ID, &c.Info.FieldType, c.Histogram.NDV, 0, c.LastUpdateVersion, c.NullCount, c.TotColSize, c.Correlation)
 		if err != nil {
 			return errors.Trace(err)
@@ -648,6 +753,7 @@ func (h *Handle) LoadNeededHistograms() (err error) {
 		if h.updateStatsCache(oldCache.update([]*statistics.Table{tbl}, nil, oldCache.version)) {
 			statistics.HistogramNeededColumns.Delete(col)
 		}
+		metrics.StatsHistogramLoadDuration.Observe(time.Since(start).Seconds())
 	}
 	return nil
 }
@@ -58,6 +58,8 @@ func cleanEnv(c *C, store kv.Storage, do *domain.Domain) {
 	tk.MustExec("delete from mysql.stats_extended")
 	tk.MustExec("delete from mysql.stats_fm_sketch")
 	tk.MustExec("delete from mysql.schema_index_usage")
+	tk.MustExec("delete from mysql.column_stats_usage")
+	tk.MustExec("delete from mysql.analyze_jobs")
 	do.StatsHandle().Clear()
 }
 
@@ -595,6 +597,61 @@ func (s *testStatsSuite) TestLoadStats(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (s *testStatsSuite) TestStatsCacheMemQuota(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	testKit := testkit.NewTestKit(c, s.store)
+	testKit.MustExec("use test")
+	testKit.MustExec("create table t1(a int, b int, key idx(a))")
+	testKit.MustExec("create table t2(a int, b int, key idx(a))")
+	testKit.MustExec("insert into t1 values (1,1),(2,2),(3,3)")
+	testKit.MustExec("insert into t2 values (1,1),(2,2),(3,3)")
+
+	h := s.do.StatsHandle()
+	oriLease := h.Lease()
+	h.SetLease(1)
+	defer func() {
+		h.SetLease(oriLease)
+		testKit.MustExec("set @@global.tidb_stats_cache_mem_quota = 0")
+	}()
+	testKit.MustExec("analyze table t1, t2")
+	// Set a large quota so that the usage of the tables is recorded.
+	testKit.MustExec("set @@global.tidb_stats_cache_mem_quota = 1099511627776")
+	is := s.do.InfoSchema()
+	c.Assert(h.Update(is), IsNil)
+
+	tbl1, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t1"))
+	c.Assert(err, IsNil)
+	tbl2, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t2"))
+	c.Assert(err, IsNil)
+	colID := tbl1.Meta().Columns[1].ID
+	sc := testKit.Se.GetSessionVars().StmtCtx
+	loadColumn := func(tblInfo *model.TableInfo) {
+		_, err := h.GetTableStats(tblInfo).ColumnEqualRowCount(sc, types.NewIntDatum(1), colID)
+		c.Assert(err, IsNil)
+		c.Assert(h.LoadNeededHistograms(), IsNil)
+	}
+	loadColumn(tbl1.Meta())
+	loadColumn(tbl2.Meta())
+	col1 := h.GetTableStats(tbl1.Meta()).Columns[colID]
+	c.Assert(col1.Len(), Greater, 0)
+	c.Assert(h.GetTableStats(tbl2.Meta()).Columns[colID].Len(), Greater, 0)
+
+	// The column stats of t1, which is the least recently used table, are evicted.
+	quota := h.GetMemConsumed() - col1.MemoryUsage()/2
+	testKit.MustExec(fmt.Sprintf("set @@global.tidb_stats_cache_mem_quota = %d", quota))
+	c.Assert(h.Update(is), IsNil)
+	c.Assert(h.GetMemConsumed(), LessEqual, quota)
+	c.Assert(h.GetTableStats(tbl1.Meta()).Columns[colID].Len(), Equals, 0)
+	c.Assert(h.GetTableStats(tbl2.Meta()).Columns[colID].Len(), Greater, 0)
+	c.Assert(h.GetTableStats(tbl1.Meta()).Indices[tbl1.Meta().Indices[0].ID].Len(), Greater, 0)
+
+	// The evicted column stats are loaded again when they are needed, and the ones of t2 are evicted instead.
+	loadColumn(tbl1.Meta())
+	c.Assert(h.GetMemConsumed(), LessEqual, quota)
+	c.Assert(h.GetTableStats(tbl2.Meta()).Columns[colID].Len(), Equals, 0)
+	c.Assert(h.GetTableStats(tbl1.Meta()).Columns[colID].Len(), Greater, 0)
+}
+
 func newStoreWithBootstrap() (kv.Storage, *domain.Domain, error) {
 	store, err := mockstore.NewMockStore()
 	if err != nil {
@@ -858,6 +915,7 @@ func (s *testStatsSuite) TestAnalyzeGlobalStatsWithOpts2(c *C) {
 	defer cleanEnv(c, s.store, s.do)
 	tk := testkit.NewTestKit(c, s.store)
 	s.prepareForGlobalStatsWithOpts(c, tk)
+	tk.MustExec("set @@tidb_persist_analyze_options = 0")
 
 	tk.MustExec("analyze table t with 20 topn, 50 buckets")
 	s.checkForGlobalStatsWithOpts(c, tk, "global", 20, 50)
@@ -1630,6 +1688,68 @@ func (s *testSerialStatsSuite) TestDDLPartition4GlobalStats(c *C) {
 	c.Assert(globalStats.Count, Equals, int64(7))
 }
 
+func (s *testSerialStatsSuite) TestGlobalStatsMergedVersions(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("set @@session.tidb_analyze_version=2")
+	tk.MustExec("set @@tidb_partition_prune_mode='dynamic'")
+	tk.MustExec(`create table t (a int, b int, key idx_b(b)) partition by range (a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20),
+		partition p2 values less than (30)
+	)`)
+	do := s.do
+	is := do.InfoSchema()
+	h := do.StatsHandle()
+	c.Assert(h.HandleDDLEvent(<-h.DDLEventCh()), IsNil)
+	tk.MustExec("insert into t values (1, 1), (2, 2), (11, 11), (12, 12), (21, 21), (22, 22), (23, 23)")
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	c.Assert(h.Update(is), IsNil)
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tableInfo := tbl.Meta()
+	pi := tableInfo.GetPartitionInfo()
+
+	// The global-stats are never merged.
+	outdated, err := h.GlobalStatsOutdatedPartitions(tableInfo)
+	c.Assert(err, IsNil)
+	c.Assert(outdated, HasLen, 0)
+
+	tk.MustExec("analyze table t")
+	c.Assert(h.Update(is), IsNil)
+	tk.MustQuery(fmt.Sprintf("select count(*) from mysql.stats_global_merge where table_id = %d", tableInfo.ID)).Check(testkit.Rows("3"))
+	outdated, err = h.GlobalStatsOutdatedPartitions(tableInfo)
+	c.Assert(err, IsNil)
+	c.Assert(outdated, HasLen, 0)
+
+	// Analyzing a partition in static mode doesn't merge the global-stats, so they become outdated.
+	tk.MustExec("set @@tidb_partition_prune_mode='static'")
+	tk.MustExec("analyze table t partition p0")
+	c.Assert(h.Update(is), IsNil)
+	outdated, err = h.GlobalStatsOutdatedPartitions(tableInfo)
+	c.Assert(err, IsNil)
+	c.Assert(outdated, DeepEquals, []int64{pi.Definitions[0].ID})
+
+	// Dropping a partition merges the global-stats of both the columns and the indexes again.
+	tk.MustExec("set @@tidb_partition_prune_mode='dynamic'")
+	tk.MustExec("alter table t drop partition p2")
+	c.Assert(h.HandleDDLEvent(<-h.DDLEventCh()), IsNil)
+	is = do.InfoSchema()
+	c.Assert(h.Update(is), IsNil)
+	tbl, err = is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tableInfo = tbl.Meta()
+	tk.MustQuery(fmt.Sprintf("select count(*) from mysql.stats_global_merge where table_id = %d", tableInfo.ID)).Check(testkit.Rows("2"))
+	outdated, err = h.GlobalStatsOutdatedPartitions(tableInfo)
+	c.Assert(err, IsNil)
+	c.Assert(outdated, HasLen, 0)
+	globalStats := h.GetTableStats(tableInfo)
+	c.Assert(globalStats.Count, Equals, int64(4))
+	c.Assert(globalStats.Indices[tableInfo.Indices[0].ID].NDV, Equals, int64(4))
+}
+
 func (s *testStatsSuite) TestMergeGlobalTopN(c *C) {
 	defer cleanEnv(c, s.store, s.do)
 	tk := testkit.NewTestKit(c, s.store)
@@ -2734,3 +2854,78 @@ func (s *testSerialStatsSuite) TestCorrelationWithDefinedCollate(c *C) {
 	c.Assert(rows[0][3], Equals, "[b,c]")
 	c.Assert(rows[0][5], Equals, "-1.000000")
 }
+
+func (s *testStatsSuite) TestLockTableStats(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_partition_prune_mode='static'")
+	tk.MustExec("create table t (a int)")
+	h := s.do.StatsHandle()
+	c.Assert(h.HandleDDLEvent(<-h.DDLEventCh()), IsNil)
+	is := s.do.InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tableInfo := tbl.Meta()
+	tk.MustExec("insert into t values (1), (2), (3), (4), (5)")
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	tk.MustExec("analyze table t")
+	c.Assert(h.Update(is), IsNil)
+	c.Assert(h.GetTableStats(tableInfo).Count, Equals, int64(5))
+
+	physicalIDs, err := handle.GetLockStatsPhysicalIDs(tableInfo, nil)
+	c.Assert(err, IsNil)
+	c.Assert(h.LockTableStats(physicalIDs), IsNil)
+	c.Assert(tk.MustQuery("show stats_meta where table_name = 't'").Rows()[0][6], Equals, "1")
+
+	// Neither the manual analyze nor the auto analyze replaces the locked stats.
+	tk.MustExec("insert into t values (6), (7), (8), (9), (10)")
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	tk.MustExec("analyze table t")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 skip analyze table test.t because its stats are locked"))
+	c.Assert(h.Update(is), IsNil)
+	c.Assert(h.GetTableStats(tableInfo).Count, Equals, int64(10))
+	c.Assert(h.GetTableStats(tableInfo).ModifyCount, Equals, int64(5))
+	handle.AutoAnalyzeMinCnt = 0
+	tk.MustExec("set global tidb_auto_analyze_ratio = 0.2")
+	defer func() {
+		handle.AutoAnalyzeMinCnt = 1000
+		tk.MustExec("set global tidb_auto_analyze_ratio = 0.0")
+	}()
+	c.Assert(h.HandleAutoAnalyze(is), IsFalse)
+	c.Assert(h.Update(is), IsNil)
+	c.Assert(h.GetTableStats(tableInfo).ModifyCount, Equals, int64(5))
+
+	// The forced analyze replaces the locked stats.
+	tk.MustExec("set @@tidb_force_analyze_locked_stats = 1")
+	tk.MustExec("analyze table t")
+	tk.MustExec("set @@tidb_force_analyze_locked_stats = 0")
+	c.Assert(h.Update(is), IsNil)
+	c.Assert(h.GetTableStats(tableInfo).ModifyCount, Equals, int64(0))
+
+	c.Assert(h.UnlockTableStats(physicalIDs), IsNil)
+	c.Assert(tk.MustQuery("show stats_meta where table_name = 't'").Rows()[0][6], Equals, "0")
+
+	// Only the locked partitions are skipped.
+	tk.MustExec("create table pt (a int) partition by range (a) (partition p0 values less than (10), partition p1 values less than (20))")
+	c.Assert(h.HandleDDLEvent(<-h.DDLEventCh()), IsNil)
+	is = s.do.InfoSchema()
+	tbl, err = is.TableByName(model.NewCIStr("test"), model.NewCIStr("pt"))
+	c.Assert(err, IsNil)
+	physicalIDs, err = handle.GetLockStatsPhysicalIDs(tbl.Meta(), []model.CIStr{model.NewCIStr("p0")})
+	c.Assert(err, IsNil)
+	c.Assert(physicalIDs, DeepEquals, []int64{tbl.Meta().Partition.Definitions[0].ID})
+	_, err = handle.GetLockStatsPhysicalIDs(tbl.Meta(), []model.CIStr{model.NewCIStr("p2")})
+	c.Assert(err, NotNil)
+	c.Assert(h.LockTableStats(physicalIDs), IsNil)
+	tk.MustExec("insert into pt values (1), (11)")
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	tk.MustExec("analyze table pt")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 skip analyze partition p0 of table test.pt because its stats are locked"))
+	c.Assert(h.Update(is), IsNil)
+	c.Assert(h.GetPartitionStats(tbl.Meta(), physicalIDs[0]).ModifyCount, Equals, int64(1))
+	c.Assert(h.GetPartitionStats(tbl.Meta(), tbl.Meta().Partition.Definitions[1].ID).ModifyCount, Equals, int64(0))
+	locked, err := h.GetLockedTables()
+	c.Assert(err, IsNil)
+	c.Assert(locked, HasLen, 1)
+}
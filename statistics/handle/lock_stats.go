@@ -0,0 +1,135 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handle
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// LockTableStats locks the stats of the given physical tables, so that they are replaced by neither the auto
+// analyze nor the manual analyze unless the analyze is forced by `tidb_force_analyze_locked_stats`.
+func (h *Handle) LockTableStats(physicalIDs []int64) (err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ctx := context.Background()
+	exec := h.mu.ctx.(sqlexec.SQLExecutor)
+	_, err = exec.ExecuteInternal(ctx, "begin")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		err = finishTransaction(ctx, exec, err)
+	}()
+	txn, err := h.mu.ctx.Txn(true)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	version := txn.StartTS()
+	for _, id := range physicalIDs {
+		if _, err = exec.ExecuteInternal(ctx, "insert ignore into mysql.stats_table_locked (table_id, version) values (%?, %?)", id, version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnlockTableStats unlocks the stats of the given physical tables.
+func (h *Handle) UnlockTableStats(physicalIDs []int64) (err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ctx := context.Background()
+	exec := h.mu.ctx.(sqlexec.SQLExecutor)
+	_, err = exec.ExecuteInternal(ctx, "begin")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		err = finishTransaction(ctx, exec, err)
+	}()
+	for _, id := range physicalIDs {
+		if _, err = exec.ExecuteInternal(ctx, "delete from mysql.stats_table_locked where table_id = %?", id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetLockedTables returns the physical tables whose stats are locked among the given ones. All the locked tables are
+// returned if no physical table is given.
+func (h *Handle) GetLockedTables(physicalIDs ...int64) (map[int64]struct{}, error) {
+	var sql strings.Builder
+	sql.WriteString("select table_id from mysql.stats_table_locked")
+	params := make([]interface{}, 0, len(physicalIDs))
+	for i, id := range physicalIDs {
+		if i == 0 {
+			sql.WriteString(" where table_id in (%?")
+		} else {
+			sql.WriteString(", %?")
+		}
+		params = append(params, id)
+	}
+	if len(physicalIDs) > 0 {
+		sql.WriteString(")")
+	}
+	rows, _, err := h.execRestrictedSQL(context.Background(), sql.String(), params...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	locked := make(map[int64]struct{}, len(rows))
+	for _, row := range rows {
+		locked[row.GetInt64(0)] = struct{}{}
+	}
+	return locked, nil
+}
+
+// GetLockStatsPhysicalIDs returns the physical tables whose stats are locked or unlocked by a `LOCK STATS` on the
+// table or its partitions. The table itself is included when no partition is specified, so that its global-level stats
+// are locked together with the partitions.
+func GetLockStatsPhysicalIDs(tblInfo *model.TableInfo, partitionNames []model.CIStr) ([]int64, error) {
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil {
+		if len(partitionNames) != 0 {
+			return nil, errors.Errorf("table %s is not a partitioned table", tblInfo.Name.O)
+		}
+		return []int64{tblInfo.ID}, nil
+	}
+	if len(partitionNames) == 0 {
+		ids := make([]int64, 0, len(pi.Definitions)+1)
+		ids = append(ids, tblInfo.ID)
+		for _, def := range pi.Definitions {
+			ids = append(ids, def.ID)
+		}
+		return ids, nil
+	}
+	ids := make([]int64, 0, len(partitionNames))
+	for _, name := range partitionNames {
+		found := false
+		for _, def := range pi.Definitions {
+			if def.Name.L == name.L {
+				found = true
+				ids = append(ids, def.ID)
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("can not found the specified partition name %s in the table definition", name.O)
+		}
+	}
+	return ids, nil
+}
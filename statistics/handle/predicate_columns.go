@@ -0,0 +1,67 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handle
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/types"
+)
+
+// TableColumnID is the key type of the predicate columns.
+type TableColumnID struct {
+	TableID  int64
+	ColumnID int64
+}
+
+// predicateColumnsMap maps the predicate columns to the time they are used last time.
+type predicateColumnsMap map[TableColumnID]time.Time
+
+func (m predicateColumnsMap) merge(rm predicateColumnsMap) {
+	for id, lastUsedAt := range rm {
+		if lastUsedAt.After(m[id]) {
+			m[id] = lastUsedAt
+		}
+	}
+}
+
+// dumpPredicateColumnsToKV dumps the predicate columns collected from the sessions to KV.
+func (h *Handle) dumpPredicateColumnsToKV() error {
+	ctx := context.Background()
+	for id, lastUsedAt := range h.predicateColumns {
+		const sql = "insert into mysql.column_stats_usage (table_id, column_id, last_used_at) values (%?, %?, %?) " +
+			"on duplicate key update last_used_at = greatest(last_used_at, values(last_used_at))"
+		if _, _, err := h.execRestrictedSQL(ctx, sql, id.TableID, id.ColumnID, lastUsedAt.Format(types.TimeFormat)); err != nil {
+			return errors.Trace(err)
+		}
+		delete(h.predicateColumns, id)
+	}
+	return nil
+}
+
+// GetPredicateColumns returns the IDs of the columns of the table which are used by the predicates, joins or group-by
+// of the queries.
+func (h *Handle) GetPredicateColumns(tableID int64) ([]int64, error) {
+	rows, _, err := h.execRestrictedSQL(context.Background(), "select column_id from mysql.column_stats_usage where table_id = %?", tableID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	colIDs := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		colIDs = append(colIDs, row.GetInt64(0))
+	}
+	return colIDs, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handle
+
+import (
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/statistics"
+	"github.com/pingcap/tidb/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// statsHealthyGauges are the gauges of the number of the tables whose healthy of stats falls in the range.
+var statsHealthyGauges = []struct {
+	minHealthy int64
+	maxHealthy int64
+	gauge      prometheus.Gauge
+}{
+	{0, 50, metrics.StatsHealthyGauge.WithLabelValues("[0,50)")},
+	{50, 80, metrics.StatsHealthyGauge.WithLabelValues("[50,80)")},
+	{80, 100, metrics.StatsHealthyGauge.WithLabelValues("[80,100)")},
+	{100, 101, metrics.StatsHealthyGauge.WithLabelValues("[100,100]")},
+	{0, 101, metrics.StatsHealthyGauge.WithLabelValues("[0,100]")},
+}
+
+var statsPseudoGauge = metrics.StatsHealthyGauge.WithLabelValues("pseudo")
+
+// statsHealthSummary counts the tables by the healthy of their stats.
+type statsHealthSummary struct {
+	healthyCounts      []int
+	pseudoCount        int
+	missingColumnStats int
+}
+
+func (s *statsHealthSummary) add(tblInfo *model.TableInfo, statsTbl *statistics.Table) {
+	healthy, ok := statsTbl.GetStatsHealthy()
+	if !ok {
+		// The tables without stats are not in the stats cache.
+		s.pseudoCount++
+		return
+	}
+	for i, g := range statsHealthyGauges {
+		if healthy >= g.minHealthy && healthy < g.maxHealthy {
+			s.healthyCounts[i]++
+		}
+	}
+	if statsTbl.MissingColumnStatsCount(tblInfo) > 0 {
+		s.missingColumnStats++
+	}
+}
+
+// UpdateStatsHealthyMetrics updates the metrics of the healthy of the stats of all the user tables and partitions.
+// It reads the stats cache directly, so the order of the LRU and the hit and miss metrics of the cache are not affected.
+func (h *Handle) UpdateStatsHealthyMetrics(is infoschema.InfoSchema) {
+	tables := h.statsCache.Load().(statsCache).tables
+	summary := &statsHealthSummary{healthyCounts: make([]int, len(statsHealthyGauges))}
+	for _, db := range is.AllSchemas() {
+		if util.IsMemOrSysDB(db.Name.L) {
+			continue
+		}
+		for _, tblInfo := range db.Tables {
+			if tblInfo.IsView() || tblInfo.IsSequence() {
+				continue
+			}
+			pi := tblInfo.GetPartitionInfo()
+			if pi == nil {
+				summary.add(tblInfo, tables[tblInfo.ID])
+				continue
+			}
+			// The global stats of the partitioned table only exist in the dynamic prune mode.
+			if globalStats, ok := tables[tblInfo.ID]; ok && !globalStats.Pseudo {
+				summary.add(tblInfo, globalStats)
+			}
+			for _, def := range pi.Definitions {
+				summary.add(tblInfo, tables[def.ID])
+			}
+		}
+	}
+	for i, g := range statsHealthyGauges {
+		g.gauge.Set(float64(summary.healthyCounts[i]))
+	}
+	statsPseudoGauge.Set(float64(summary.pseudoCount))
+	metrics.StatsMissingColumnStatsGauge.Set(float64(summary.missingColumnStats))
+}
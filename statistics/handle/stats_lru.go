@@ -0,0 +1,167 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handle
+
+import (
+	"container/list"
+	"strconv"
+	"sync"
+
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/statistics"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+var (
+	statsCacheHitCounter   = metrics.StatsCacheLRUCounter.WithLabelValues("hit")
+	statsCacheMissCounter  = metrics.StatsCacheLRUCounter.WithLabelValues("miss")
+	statsCacheEvictCounter = metrics.StatsCacheLRUCounter.WithLabelValues("evict")
+)
+
+// statsLRU records the order in which the tables in the stats cache are used, the front is the most recently used.
+type statsLRU struct {
+	sync.Mutex
+	list     *list.List
+	elements map[int64]*list.Element
+}
+
+func newStatsLRU() *statsLRU {
+	return &statsLRU{list: list.New(), elements: make(map[int64]*list.Element)}
+}
+
+// touch marks the table as the most recently used one.
+func (l *statsLRU) touch(id int64) {
+	l.Lock()
+	if elem, ok := l.elements[id]; ok {
+		l.list.MoveToFront(elem)
+	} else {
+		l.elements[id] = l.list.PushFront(id)
+	}
+	l.Unlock()
+}
+
+func (l *statsLRU) remove(ids []int64) {
+	l.Lock()
+	for _, id := range ids {
+		if elem, ok := l.elements[id]; ok {
+			l.list.Remove(elem)
+			delete(l.elements, id)
+		}
+	}
+	l.Unlock()
+}
+
+// evictionOrder returns the IDs of the tables in the order they should be evicted: the tables which are never used
+// first, then the least recently used ones.
+func (l *statsLRU) evictionOrder(tables map[int64]*statistics.Table) []int64 {
+	l.Lock()
+	defer l.Unlock()
+	ids := make([]int64, 0, len(tables))
+	for id := range tables {
+		if _, ok := l.elements[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	for elem := l.list.Back(); elem != nil; elem = elem.Prev() {
+		id := elem.Value.(int64)
+		if _, ok := tables[id]; ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// evictColumnStats returns a copy of the table whose column histograms, CM sketches, TopN and FM sketches are dropped.
+// The dropped stats are loaded again asynchronously by `LoadNeededHistograms` when they are needed by the queries.
+// It returns nil if there is nothing to evict.
+func evictColumnStats(tbl *statistics.Table) *statistics.Table {
+	var newTbl *statistics.Table
+	for id, col := range tbl.Columns {
+		// The stats of the handle columns are always loaded, see `columnStatsFromStorage`.
+		if col.IsHandle || col.MemoryUsage() == 0 {
+			continue
+		}
+		if newTbl == nil {
+			newTbl = tbl.Copy()
+		}
+		newCol := &statistics.Column{
+			PhysicalID: col.PhysicalID,
+			Histogram:  *statistics.NewHistogram(col.ID, col.Histogram.NDV, col.NullCount, col.LastUpdateVersion, &col.Info.FieldType, 0, col.TotColSize),
+			Info:       col.Info,
+			Count:      col.Count,
+			ErrorRate:  col.ErrorRate,
+			IsHandle:   col.IsHandle,
+			Flag:       col.Flag,
+			StatsVer:   col.StatsVer,
+		}
+		newCol.Histogram.Correlation = col.Histogram.Correlation
+		col.LastAnalyzePos.Copy(&newCol.LastAnalyzePos)
+		newTbl.Columns[id] = newCol
+	}
+	return newTbl
+}
+
+// updateStatsCacheMemQuota reads the memory quota of the stats cache from the global variable.
+func (h *Handle) updateStatsCacheMemQuota() {
+	h.mu.Lock()
+	val, err := h.mu.ctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.TiDBStatsCacheMemQuota)
+	h.mu.Unlock()
+	if err != nil {
+		logutil.BgLogger().Warn("[stats] get the memory quota of the stats cache failed", zap.Error(err))
+		return
+	}
+	quota, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		quota = variable.DefTiDBStatsCacheMemQuota
+	}
+	h.statsCacheMemQuota.Store(quota)
+}
+
+// evictStatsCache drops the column stats of the least recently used tables until the memory usage of the stats cache
+// is under the quota. The index stats are kept since they are not loaded on demand.
+func (h *Handle) evictStatsCache() {
+	quota := h.statsCacheMemQuota.Load()
+	if quota <= 0 {
+		return
+	}
+	for {
+		oldCache := h.statsCache.Load().(statsCache)
+		if oldCache.memUsage <= quota {
+			return
+		}
+		memUsage := oldCache.memUsage
+		tables := make([]*statistics.Table, 0)
+		for _, id := range h.statsLRU.evictionOrder(oldCache.tables) {
+			if memUsage <= quota {
+				break
+			}
+			tbl := oldCache.tables[id]
+			newTbl := evictColumnStats(tbl)
+			if newTbl == nil {
+				continue
+			}
+			memUsage -= tbl.MemoryUsage() - newTbl.MemoryUsage()
+			tables = append(tables, newTbl)
+		}
+		if len(tables) == 0 {
+			return
+		}
+		if h.updateStatsCache(oldCache.update(tables, nil, oldCache.version)) {
+			statsCacheEvictCounter.Add(float64(len(tables)))
+			return
+		}
+	}
+}
@@ -132,6 +132,8 @@ func (h *Handle) merge(s *SessionStatsCollector, rateMap errorRateDeltaMap) {
 	s.rateMap = make(errorRateDeltaMap)
 	h.feedback.Merge(s.feedback)
 	s.feedback = statistics.NewQueryFeedbackMap()
+	h.predicateColumns.merge(s.colMap)
+	s.colMap = make(predicateColumnsMap)
 }
 
 // SessionStatsCollector is a list item that holds the delta mapper. If you want to write or read mapper, you must lock it.
@@ -141,6 +143,7 @@ type SessionStatsCollector struct {
 	mapper   tableDeltaMap
 	feedback *statistics.QueryFeedbackMap
 	rateMap  errorRateDeltaMap
+	colMap   predicateColumnsMap
 	next     *SessionStatsCollector
 	// deleted is set to true when a session is closed. Every time we sweep the list, we will remove the useless collector.
 	deleted bool
@@ -160,6 +163,16 @@ func (s *SessionStatsCollector) Update(id int64, delta int64, count int64, colSi
 	s.mapper.update(id, delta, count, colSize)
 }
 
+// UpdatePredicateColumns records the columns of the table used by the predicates, joins or group-by of the queries.
+func (s *SessionStatsCollector) UpdatePredicateColumns(tableID int64, colIDs []int64) {
+	now := time.Now()
+	s.Lock()
+	defer s.Unlock()
+	for _, colID := range colIDs {
+		s.colMap[TableColumnID{TableID: tableID, ColumnID: colID}] = now
+	}
+}
+
 var (
 	// MinLogScanCount is the minimum scan count for a feedback to be logged.
 	MinLogScanCount = int64(1000)
@@ -201,6 +214,7 @@ func (h *Handle) NewSessionStatsCollector() *SessionStatsCollector {
 	newCollector := &SessionStatsCollector{
 		mapper:   make(tableDeltaMap),
 		rateMap:  make(errorRateDeltaMap),
+		colMap:   make(predicateColumnsMap),
 		next:     h.listHead.next,
 		feedback: statistics.NewQueryFeedbackMap(),
 	}
@@ -440,7 +454,7 @@ func (h *Handle) DumpStatsDeltaToKV(mode dumpMode) error {
 			h.globalMap[id] = m
 		}
 	}
-	return nil
+	return errors.Trace(h.dumpPredicateColumnsToKV())
 }
 
 // dumpTableStatDeltaToKV dumps a single delta with some table to KV and updates the version.
@@ -883,8 +897,9 @@ func NeedAnalyzeTable(tbl *statistics.Table, limit time.Duration, autoAnalyzeRat
 
 func (h *Handle) getAutoAnalyzeParameters() map[string]string {
 	ctx := context.Background()
-	sql := "select variable_name, variable_value from mysql.global_variables where variable_name in (%?, %?, %?)"
-	rows, _, err := h.execRestrictedSQL(ctx, sql, variable.TiDBAutoAnalyzeRatio, variable.TiDBAutoAnalyzeStartTime, variable.TiDBAutoAnalyzeEndTime)
+	sql := "select variable_name, variable_value from mysql.global_variables where variable_name in (%?, %?, %?, %?, %?)"
+	rows, _, err := h.execRestrictedSQL(ctx, sql, variable.TiDBAutoAnalyzeRatio, variable.TiDBAutoAnalyzeStartTime, variable.TiDBAutoAnalyzeEndTime,
+		variable.TiDBEnableAutoAnalyze, variable.TiDBAutoAnalyzeConcurrency)
 	if err != nil {
 		return map[string]string{}
 	}
@@ -903,6 +918,21 @@ func parseAutoAnalyzeRatio(ratio string) float64 {
 	return math.Max(autoAnalyzeRatio, 0)
 }
 
+func parseEnableAutoAnalyze(enable string) bool {
+	if enable == "" {
+		return variable.DefTiDBEnableAutoAnalyze
+	}
+	return variable.TiDBOptOn(enable)
+}
+
+func parseAutoAnalyzeConcurrency(concurrency string) int {
+	c, err := strconv.Atoi(concurrency)
+	if err != nil || c <= 0 {
+		return variable.DefTiDBAutoAnalyzeConcurrency
+	}
+	return c
+}
+
 func parseAnalyzePeriod(start, end string) (time.Time, time.Time, error) {
 	if start == "" {
 		start = variable.DefAutoAnalyzeStartTime
@@ -918,7 +948,9 @@ func parseAnalyzePeriod(start, end string) (time.Time, time.Time, error) {
 	return s, e, err
 }
 
-// HandleAutoAnalyze analyzes the newly created table or index.
+// HandleAutoAnalyze analyzes the newly created table or index, and the tables modified too much. The jobs to analyze
+// the tables are pushed into a priority queue ordered by the staleness, the size and the query frequency of the
+// tables, then they are run by at most tidb_auto_analyze_concurrency workers.
 func (h *Handle) HandleAutoAnalyze(is infoschema.InfoSchema) (analyzed bool) {
 	err := h.UpdateSessionVar()
 	if err != nil {
@@ -927,86 +959,101 @@ func (h *Handle) HandleAutoAnalyze(is infoschema.InfoSchema) (analyzed bool) {
 	}
 	dbs := is.AllSchemaNames()
 	parameters := h.getAutoAnalyzeParameters()
+	// The auto analyze is paused.
+	if !parseEnableAutoAnalyze(parameters[variable.TiDBEnableAutoAnalyze]) {
+		return false
+	}
+	if h.resumeInterruptedAnalyzeJobs(is) {
+		return true
+	}
 	autoAnalyzeRatio := parseAutoAnalyzeRatio(parameters[variable.TiDBAutoAnalyzeRatio])
 	start, end, err := parseAnalyzePeriod(parameters[variable.TiDBAutoAnalyzeStartTime], parameters[variable.TiDBAutoAnalyzeEndTime])
 	if err != nil {
 		logutil.BgLogger().Error("[stats] parse auto analyze period failed", zap.Error(err))
 		return false
 	}
+	concurrency := parseAutoAnalyzeConcurrency(parameters[variable.TiDBAutoAnalyzeConcurrency])
+	locked, err := h.GetLockedTables()
+	if err != nil {
+		logutil.BgLogger().Error("[stats] get the tables whose stats are locked failed", zap.Error(err))
+		return false
+	}
+	queryCounts, err := h.getTableQueryCounts()
+	if err != nil {
+		logutil.BgLogger().Error("[stats] get the query counts of tables failed", zap.Error(err))
+		return false
+	}
 	pruneMode := h.CurrentPruneMode()
+	queue := &autoAnalyzeQueue{}
 	for _, db := range dbs {
 		tbls := is.SchemaTables(model.NewCIStr(db))
 		for _, tbl := range tbls {
 			tblInfo := tbl.Meta()
+			if _, ok := locked[tblInfo.ID]; ok {
+				continue
+			}
 			pi := tblInfo.GetPartitionInfo()
 			if pi == nil {
 				statsTbl := h.GetTableStats(tblInfo)
 				sql := "analyze table %n.%n"
-				analyzed := h.autoAnalyzeTable(tblInfo, statsTbl, start, end, autoAnalyzeRatio, sql, db, tblInfo.Name.O)
-				if analyzed {
-					// analyze one table at a time to let it get the freshest parameters.
-					// others will be analyzed next round which is just 3s later.
-					return true
-				}
+				queue.push(h.autoAnalyzeTable(tblInfo, statsTbl, start, end, autoAnalyzeRatio, queryCounts[tblInfo.ID], sql, db, tblInfo.Name.O))
 				continue
 			}
 			if pruneMode == variable.Dynamic {
-				analyzed := h.autoAnalyzePartitionTable(tblInfo, pi, db, start, end, autoAnalyzeRatio)
-				if analyzed {
-					return true
-				}
+				queue.push(h.autoAnalyzePartitionTable(tblInfo, pi, db, start, end, autoAnalyzeRatio, locked, queryCounts[tblInfo.ID]))
 				continue
 			}
 			for _, def := range pi.Definitions {
+				if _, ok := locked[def.ID]; ok {
+					continue
+				}
 				sql := "analyze table %n.%n partition %n"
 				statsTbl := h.GetPartitionStats(tblInfo, def.ID)
-				analyzed := h.autoAnalyzeTable(tblInfo, statsTbl, start, end, autoAnalyzeRatio, sql, db, tblInfo.Name.O, def.Name.O)
-				if analyzed {
-					return true
-				}
+				queue.push(h.autoAnalyzeTable(tblInfo, statsTbl, start, end, autoAnalyzeRatio, queryCounts[tblInfo.ID], sql, db, tblInfo.Name.O, def.Name.O))
 			}
 		}
 	}
-	return false
+	return h.runAutoAnalyzeJobs(queue, concurrency)
 }
 
-func (h *Handle) autoAnalyzeTable(tblInfo *model.TableInfo, statsTbl *statistics.Table, start, end time.Time, ratio float64, sql string, params ...interface{}) bool {
+func (h *Handle) autoAnalyzeTable(tblInfo *model.TableInfo, statsTbl *statistics.Table, start, end time.Time, ratio float64, queryCount int64, sql string, params ...interface{}) *autoAnalyzeJob {
 	if statsTbl.Pseudo || statsTbl.Count < AutoAnalyzeMinCnt {
-		return false
+		return nil
 	}
 	if needAnalyze, reason := NeedAnalyzeTable(statsTbl, 20*h.Lease(), ratio, start, end, time.Now()); needAnalyze {
 		escaped, err := sqlexec.EscapeSQL(sql, params...)
 		if err != nil {
-			return false
+			return nil
 		}
-		logutil.BgLogger().Info("[stats] auto analyze triggered", zap.String("sql", escaped), zap.String("reason", reason))
 		tableStatsVer := h.mu.ctx.GetSessionVars().AnalyzeVersion
 		statistics.CheckAnalyzeVerOnTable(statsTbl, &tableStatsVer)
-		h.execAutoAnalyze(tableStatsVer, sql, params...)
-		return true
+		return h.newAutoAnalyzeJob(statsTbl, queryCount, tableStatsVer, "[stats] auto analyze triggered", escaped, reason, sql, params...)
 	}
 	for _, idx := range tblInfo.Indices {
 		if _, ok := statsTbl.Indices[idx.ID]; !ok && idx.State == model.StatePublic {
-			sqlWithIdx := sql + "index %n"
+			sqlWithIdx := sql + " index %n"
 			paramsWithIdx := append(params, idx.Name.O)
-			escaped, err := sqlexec.EscapeSQL(sql, params...)
+			escaped, err := sqlexec.EscapeSQL(sqlWithIdx, paramsWithIdx...)
 			if err != nil {
-				return false
+				return nil
 			}
-			logutil.BgLogger().Info("[stats] auto analyze for unanalyzed", zap.String("sql", escaped))
 			tableStatsVer := h.mu.ctx.GetSessionVars().AnalyzeVersion
 			statistics.CheckAnalyzeVerOnTable(statsTbl, &tableStatsVer)
-			h.execAutoAnalyze(tableStatsVer, sqlWithIdx, paramsWithIdx...)
-			return true
+			return h.newAutoAnalyzeJob(statsTbl, queryCount, tableStatsVer, "[stats] auto analyze for unanalyzed", escaped, "index unanalyzed", sqlWithIdx, paramsWithIdx...)
 		}
 	}
-	return false
+	return nil
 }
 
-func (h *Handle) autoAnalyzePartitionTable(tblInfo *model.TableInfo, pi *model.PartitionInfo, db string, start, end time.Time, ratio float64) bool {
+func (h *Handle) autoAnalyzePartitionTable(tblInfo *model.TableInfo, pi *model.PartitionInfo, db string, start, end time.Time, ratio float64, locked map[int64]struct{}, queryCount int64) *autoAnalyzeJob {
 	tableStatsVer := h.mu.ctx.GetSessionVars().AnalyzeVersion
 	partitionNames := make([]interface{}, 0, len(pi.Definitions))
+	// The priority of the job is computed by the most stale partition.
+	var staleStatsTbl *statistics.Table
 	for _, def := range pi.Definitions {
+		if _, ok := locked[def.ID]; ok {
+			continue
+		}
 		partitionStatsTbl := h.GetPartitionStats(tblInfo, def.ID)
 		if partitionStatsTbl.Pseudo || partitionStatsTbl.Count < AutoAnalyzeMinCnt {
 			continue
@@ -1014,6 +1061,9 @@ func (h *Handle) autoAnalyzePartitionTable(tblInfo *model.TableInfo, pi *model.P
 		if needAnalyze, _ := NeedAnalyzeTable(partitionStatsTbl, 20*h.Lease(), ratio, start, end, time.Now()); needAnalyze {
 			partitionNames = append(partitionNames, def.Name.O)
 			statistics.CheckAnalyzeVerOnTable(partitionStatsTbl, &tableStatsVer)
+			if staleStatsTbl == nil || autoAnalyzeStaleness(partitionStatsTbl) > autoAnalyzeStaleness(staleStatsTbl) {
+				staleStatsTbl = partitionStatsTbl
+			}
 		}
 	}
 	getSQL := func(prefix, suffix string, numPartitions int) string {
@@ -1029,37 +1079,77 @@ func (h *Handle) autoAnalyzePartitionTable(tblInfo *model.TableInfo, pi *model.P
 		return sqlBuilder.String()
 	}
 	if len(partitionNames) > 0 {
-		logutil.BgLogger().Info("[stats] auto analyze triggered")
 		sql := getSQL("analyze table %n.%n partition", "", len(partitionNames))
 		params := append([]interface{}{db, tblInfo.Name.O}, partitionNames...)
 		statsTbl := h.GetTableStats(tblInfo)
 		statistics.CheckAnalyzeVerOnTable(statsTbl, &tableStatsVer)
-		h.execAutoAnalyze(tableStatsVer, sql, params...)
-		return true
+		escaped, err := sqlexec.EscapeSQL(sql, params...)
+		if err != nil {
+			return nil
+		}
+		return h.newAutoAnalyzeJob(staleStatsTbl, queryCount, tableStatsVer, "[stats] auto analyze triggered", escaped, "partitions need analyze", sql, params...)
 	}
 	for _, idx := range tblInfo.Indices {
 		if idx.State != model.StatePublic {
 			continue
 		}
 		for _, def := range pi.Definitions {
+			if _, ok := locked[def.ID]; ok {
+				continue
+			}
 			partitionStatsTbl := h.GetPartitionStats(tblInfo, def.ID)
 			if _, ok := partitionStatsTbl.Indices[idx.ID]; !ok {
 				partitionNames = append(partitionNames, def.Name.O)
 				statistics.CheckAnalyzeVerOnTable(partitionStatsTbl, &tableStatsVer)
+				if staleStatsTbl == nil || partitionStatsTbl.Count > staleStatsTbl.Count {
+					staleStatsTbl = partitionStatsTbl
+				}
 			}
 		}
 		if len(partitionNames) > 0 {
-			logutil.BgLogger().Info("[stats] auto analyze for unanalyzed")
 			sql := getSQL("analyze table %n.%n partition", " index %n", len(partitionNames))
 			params := append([]interface{}{db, tblInfo.Name.O}, partitionNames...)
 			params = append(params, idx.Name.O)
 			statsTbl := h.GetTableStats(tblInfo)
 			statistics.CheckAnalyzeVerOnTable(statsTbl, &tableStatsVer)
-			h.execAutoAnalyze(tableStatsVer, sql, params...)
-			return true
+			escaped, err := sqlexec.EscapeSQL(sql, params...)
+			if err != nil {
+				return nil
+			}
+			return h.newAutoAnalyzeJob(staleStatsTbl, queryCount, tableStatsVer, "[stats] auto analyze for unanalyzed", escaped, "index unanalyzed", sql, params...)
 		}
 	}
-	return false
+	return h.autoMergeGlobalStats(tblInfo, pi, queryCount)
+}
+
+// autoMergeGlobalStats returns the job to merge the partition-level stats into global-stats again if some partitions
+// are analyzed, added or dropped after the last merge.
+func (h *Handle) autoMergeGlobalStats(tblInfo *model.TableInfo, pi *model.PartitionInfo, queryCount int64) *autoAnalyzeJob {
+	outdated, err := h.GlobalStatsOutdatedPartitions(tblInfo)
+	if err != nil {
+		logutil.BgLogger().Error("[stats] get the outdated partitions of global-stats failed", zap.String("table", tblInfo.Name.O), zap.Error(err))
+		return nil
+	}
+	if len(outdated) == 0 {
+		return nil
+	}
+	for _, def := range pi.Definitions {
+		if h.GetPartitionStats(tblInfo, def.ID).Pseudo {
+			return nil
+		}
+	}
+	statsTbl := h.GetTableStats(tblInfo)
+	return &autoAnalyzeJob{
+		priority: autoAnalyzePriority(statsTbl, queryCount),
+		exec: func() bool {
+			logutil.BgLogger().Info("[stats] auto merge global-stats triggered", zap.String("table", tblInfo.Name.O), zap.Int64s("outdated partitions", outdated))
+			if err := h.updateGlobalStats(tblInfo); err != nil {
+				logutil.BgLogger().Error("[stats] auto merge global-stats failed", zap.String("table", tblInfo.Name.O), zap.Error(err))
+				return false
+			}
+			return true
+		},
+	}
 }
 
 var execOptionForAnalyze = map[int]sqlexec.OptionFuncAlias{
@@ -47,3 +47,40 @@ func (s *testUpdateListSuite) TestInsertAndDelete(c *C) {
 	h.sweepList()
 	c.Assert(h.listHead.next, IsNil)
 }
+
+func (s *testUpdateListSuite) TestAutoAnalyzeQueue(c *C) {
+	newAnalyzedTable := func(count, modifyCount int64) *statistics.Table {
+		return &statistics.Table{HistColl: statistics.HistColl{
+			Count:       count,
+			ModifyCount: modifyCount,
+			Columns:     map[int64]*statistics.Column{1: {Count: count}},
+		}}
+	}
+	stale := newAnalyzedTable(100, 80)
+	fresh := newAnalyzedTable(100, 60)
+	// The staleness, the size and the query frequency all raise the priority.
+	c.Assert(autoAnalyzePriority(stale, 0), Greater, autoAnalyzePriority(fresh, 0))
+	c.Assert(autoAnalyzePriority(fresh, 100), Greater, autoAnalyzePriority(fresh, 0))
+	c.Assert(autoAnalyzePriority(newAnalyzedTable(10000, 6000), 0), Greater, autoAnalyzePriority(fresh, 0))
+	// The unanalyzed table is at least as stale as the table whose rows are all modified.
+	c.Assert(autoAnalyzePriority(&statistics.Table{HistColl: statistics.HistColl{Count: 100}}, 0), Equals, autoAnalyzePriority(newAnalyzedTable(100, 100), 0))
+
+	queue := &autoAnalyzeQueue{}
+	var order []int
+	newJob := func(id int, priority float64) *autoAnalyzeJob {
+		return &autoAnalyzeJob{priority: priority, exec: func() bool {
+			order = append(order, id)
+			return true
+		}}
+	}
+	queue.push(newJob(1, 1))
+	queue.push(nil)
+	queue.push(newJob(2, 3))
+	queue.push(newJob(3, 2))
+	queue.push(newJob(4, 3))
+	for job := queue.pop(); job != nil; job = queue.pop() {
+		job.exec()
+	}
+	// The jobs of the same priority are popped in the order they are pushed.
+	c.Assert(order, DeepEquals, []int{2, 4, 3, 1})
+}
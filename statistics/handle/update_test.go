@@ -18,6 +18,7 @@ import (
 	"math"
 	"math/rand"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -538,6 +539,143 @@ func (s *testStatsSuite) TestAutoUpdatePartition(c *C) {
 	})
 }
 
+func (s *testStatsSuite) TestPredicateColumns(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (a int, b int, c int, d int, index idx(d))")
+	tk.MustExec("create table t2 (a int, b int, c int)")
+	tk.MustExec("insert into t1 values (1, 1, 1, 1), (2, 2, 2, 2)")
+	h := s.do.StatsHandle()
+	is := s.do.InfoSchema()
+	tbl1, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t1"))
+	c.Assert(err, IsNil)
+	tbl2, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t2"))
+	c.Assert(err, IsNil)
+	checkPredicateColumns := func(tblInfo *model.TableInfo, offsets ...int) {
+		c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+		colIDs, err := h.GetPredicateColumns(tblInfo.ID)
+		c.Assert(err, IsNil)
+		expected := make([]int64, 0, len(offsets))
+		for _, offset := range offsets {
+			expected = append(expected, tblInfo.Columns[offset].ID)
+		}
+		sort.Slice(colIDs, func(i, j int) bool { return colIDs[i] < colIDs[j] })
+		c.Assert(colIDs, DeepEquals, expected)
+	}
+
+	// The columns aren't tracked unless tidb_enable_column_tracking is on.
+	tk.MustQuery("select * from t1 where a > 1")
+	checkPredicateColumns(tbl1.Meta())
+
+	tk.MustExec("set @@tidb_enable_column_tracking = 1")
+	tk.MustQuery("select * from t1 where a > 1")
+	tk.MustQuery("select count(*) from (select a as x, b from t1) t join t2 on t.b = t2.a group by t2.b")
+	checkPredicateColumns(tbl1.Meta(), 0, 1)
+	checkPredicateColumns(tbl2.Meta(), 0, 1)
+
+	// Only the predicate columns and the index columns are analyzed.
+	tk.MustExec("set @@tidb_analyze_predicate_columns = 1")
+	tk.MustExec("analyze table t1")
+	rows := tk.MustQuery(fmt.Sprintf("select hist_id from mysql.stats_histograms where table_id = %d and is_index = 0 and distinct_count > 0 order by hist_id", tbl1.Meta().ID)).Rows()
+	c.Assert(len(rows), Equals, 3)
+	for i, offset := range []int{0, 1, 3} {
+		c.Assert(rows[i][0], Equals, fmt.Sprintf("%d", tbl1.Meta().Columns[offset].ID))
+	}
+
+	// All the columns are analyzed if the predicate columns aren't recorded.
+	tk.MustExec("set @@tidb_enable_column_tracking = 0")
+	tk.MustExec("create table t3 (a int, b int)")
+	tk.MustExec("insert into t3 values (1, 1)")
+	tk.MustExec("analyze table t3")
+	tk.MustQuery("select count(*) from mysql.stats_histograms where table_id = (select tidb_table_id from information_schema.tables where table_schema = 'test' and table_name = 't3') and is_index = 0 and distinct_count > 0").Check(testkit.Rows("2"))
+}
+
+func (s *testStatsSuite) TestResumeAnalyzeJobs(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int, index idx(b)) partition by range (a) (partition p0 values less than (10), partition p1 values less than (20))")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (11, 11)")
+	tk.MustExec("analyze table t")
+	tk.MustQuery("select partition_name, job_info, processed_rows, total_rows, state from mysql.analyze_jobs order by partition_name, job_info").Check(testkit.Rows(
+		"p0 analyze columns 2 0 finished",
+		"p0 analyze index idx 2 0 finished",
+		"p1 analyze columns 1 0 finished",
+		"p1 analyze index idx 1 0 finished",
+	))
+
+	// The row count in the stats is used to estimate the progress.
+	tk.MustExec("delete from mysql.analyze_jobs")
+	tk.MustExec("analyze table t partition p0")
+	tk.MustQuery("select job_info, processed_rows, total_rows, state from mysql.analyze_jobs order by job_info").Check(testkit.Rows(
+		"analyze columns 2 2 finished",
+		"analyze index idx 2 2 finished",
+	))
+
+	// Simulate that the tidb-server restarts when analyzing the index of p0.
+	tk.MustExec("update mysql.analyze_jobs set state = 'running' where job_info = 'analyze index idx'")
+	h := s.do.StatsHandle()
+	c.Assert(h.MarkInterruptedAnalyzeJobs(), IsNil)
+	tk.MustQuery("select job_info, state from mysql.analyze_jobs order by job_info").Check(testkit.Rows(
+		"analyze columns finished",
+		"analyze index idx interrupted",
+	))
+	tk.MustExec("delete from mysql.stats_histograms where is_index = 1")
+	c.Assert(h.Update(s.do.InfoSchema()), IsNil)
+	c.Assert(h.HandleAutoAnalyze(s.do.InfoSchema()), IsTrue)
+	tk.MustQuery("select job_info, state from mysql.analyze_jobs order by id").Check(testkit.Rows(
+		"analyze columns finished",
+		"analyze index idx resumed",
+		"auto analyze index idx finished",
+	))
+	tk.MustQuery("select partition_name from mysql.analyze_jobs where job_info = 'auto analyze index idx'").Check(testkit.Rows("p0"))
+	// The resumed jobs aren't resumed again.
+	c.Assert(h.HandleAutoAnalyze(s.do.InfoSchema()), IsFalse)
+}
+
+func (s *testStatsSuite) TestAutoAnalyzeScheduler(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("create table t2 (a int)")
+	tk.MustExec("analyze table t1, t2")
+
+	handle.AutoAnalyzeMinCnt = 0
+	tk.MustExec("set global tidb_auto_analyze_ratio = 0.2")
+	defer func() {
+		handle.AutoAnalyzeMinCnt = 1000
+		tk.MustExec("set global tidb_auto_analyze_ratio = 0.0")
+		tk.MustExec("set global tidb_enable_auto_analyze = 1")
+		tk.MustExec("set global tidb_auto_analyze_concurrency = 1")
+	}()
+	h := s.do.StatsHandle()
+	is := s.do.InfoSchema()
+	tk.MustExec("insert into t1 values (1), (2), (3)")
+	tk.MustExec("insert into t2 values (1), (2), (3)")
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	c.Assert(h.Update(is), IsNil)
+
+	// The auto analyze is paused.
+	tk.MustExec("set global tidb_enable_auto_analyze = 0")
+	c.Assert(h.HandleAutoAnalyze(is), IsFalse)
+
+	// All the jobs in the queue are run by the workers.
+	tk.MustExec("set global tidb_enable_auto_analyze = 1")
+	tk.MustExec("set global tidb_auto_analyze_concurrency = 2")
+	c.Assert(h.HandleAutoAnalyze(is), IsTrue)
+	c.Assert(h.Update(is), IsNil)
+	for _, name := range []string{"t1", "t2"} {
+		tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr(name))
+		c.Assert(err, IsNil)
+		stats := h.GetTableStats(tbl.Meta())
+		c.Assert(stats.Count, Equals, int64(3))
+		c.Assert(stats.ModifyCount, Equals, int64(0))
+	}
+	c.Assert(h.HandleAutoAnalyze(is), IsFalse)
+}
+
 func (s *testSerialStatsSuite) TestAutoAnalyzeOnEmptyTable(c *C) {
 	defer cleanEnv(c, s.store, s.do)
 	tk := testkit.NewTestKit(c, s.store)
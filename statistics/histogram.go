@@ -1986,3 +1986,69 @@ func MergePartitionHist2GlobalHist(sc *stmtctx.StatementContext, hists []*Histog
 	}
 	return globalHist, nil
 }
+
+// SplitPoint is a memcomparable value and the number of the rows it counts, such as a value of the TopN or the upper
+// bound of a bucket, which counts the rows of the bucket.
+type SplitPoint struct {
+	Value []byte
+	Count int64
+}
+
+// SplitPoints returns the values of the TopN and the upper bounds of the buckets of the index as the split points.
+func (idx *Index) SplitPoints() []SplitPoint {
+	points := make([]SplitPoint, 0, idx.Len())
+	var lastCount int64
+	for i := 0; i < idx.Len(); i++ {
+		points = append(points, SplitPoint{Value: idx.GetUpper(i).GetBytes(), Count: idx.Buckets[i].Count - lastCount})
+		lastCount = idx.Buckets[i].Count
+	}
+	if idx.TopN != nil {
+		for _, meta := range idx.TopN.TopN {
+			points = append(points, SplitPoint{Value: meta.Encoded, Count: int64(meta.Count)})
+		}
+	}
+	return points
+}
+
+// SplitValues returns at most `num-1` values of the points, which divide the rows counted by the points into `num`
+// parts of about the same size, they are used to split the regions by the distribution of the data. The smallest
+// value is never returned because no row is less than it.
+func SplitValues(points []SplitPoint, num int) [][]byte {
+	sort.Slice(points, func(i, j int) bool {
+		return bytes.Compare(points[i].Value, points[j].Value) < 0
+	})
+	// befores[i] is the number of the rows less than the value of points[i].
+	befores := make([]int64, 0, len(points))
+	merged := points[:0]
+	var total int64
+	for _, point := range points {
+		if len(merged) > 0 && bytes.Equal(merged[len(merged)-1].Value, point.Value) {
+			merged[len(merged)-1].Count += point.Count
+		} else {
+			merged = append(merged, point)
+			befores = append(befores, total)
+		}
+		total += point.Count
+	}
+	points = merged
+
+	values := make([][]byte, 0, num-1)
+	last, i := 0, 1
+	for part := 1; part < num; part++ {
+		target := total * int64(part) / int64(num)
+		for i < len(points) && befores[i] < target {
+			i++
+		}
+		// points[i] is the first point not less than the target, the point in front of it may be closer.
+		j := i
+		if j-1 > last && (j == len(points) || target-befores[j-1] < befores[j]-target) {
+			j--
+		}
+		if j <= last || j >= len(points) {
+			continue
+		}
+		values = append(values, points[j].Value)
+		last = j
+	}
+	return values
+}
@@ -464,3 +464,34 @@ func (s *testStatisticsSuite) TestMergeBucketNDV(c *C) {
 		c.Assert(t.result.disjointNDV, Equals, res.disjointNDV)
 	}
 }
+
+func (s *testStatisticsSuite) TestSplitValues(c *C) {
+	type point struct {
+		value string
+		count int64
+	}
+	cases := []struct {
+		points []point
+		num    int
+		values []string
+	}{
+		{nil, 4, nil},
+		{[]point{{"a", 10}}, 4, nil},
+		{[]point{{"d", 1}, {"c", 1}, {"b", 1}, {"a", 1}}, 4, []string{"b", "c", "d"}},
+		{[]point{{"h", 1}, {"g", 1}, {"f", 1}, {"e", 1}, {"d", 1}, {"c", 1}, {"b", 1}, {"a", 1}}, 4, []string{"c", "e", "g"}},
+		// The skewed value gets a region of its own.
+		{[]point{{"a", 1}, {"b", 100}, {"c", 1}, {"d", 1}}, 4, []string{"b", "c"}},
+		{[]point{{"a", 100}, {"b", 1}, {"c", 1}}, 4, []string{"b"}},
+	}
+	for _, ca := range cases {
+		points := make([]SplitPoint, 0, len(ca.points))
+		for _, p := range ca.points {
+			points = append(points, SplitPoint{Value: []byte(p.value), Count: p.count})
+		}
+		values := SplitValues(points, ca.num)
+		c.Assert(len(values), Equals, len(ca.values), Commentf("%v", ca.points))
+		for i, value := range values {
+			c.Assert(string(value), Equals, ca.values[i])
+		}
+	}
+}
@@ -158,6 +158,11 @@ func SampleCollectorFromProto(collector *tipb.SampleCollector) *SampleCollector
 	return s
 }
 
+// Collect collects the encoded value of a row into the sample collector.
+func (c *SampleCollector) Collect(sc *stmtctx.StatementContext, d types.Datum) error {
+	return c.collect(sc, d)
+}
+
 func (c *SampleCollector) collect(sc *stmtctx.StatementContext, d types.Datum) error {
 	if !c.IsMerger {
 		if d.IsNull() {
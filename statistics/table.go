@@ -251,6 +251,61 @@ func (t *Table) IsOutdated() bool {
 	return false
 }
 
+// GetStatsHealthy returns the healthy score of the table stats, which is 100 minus the percentage of the modified rows.
+// It returns false if the stats is pseudo.
+func (t *Table) GetStatsHealthy() (int64, bool) {
+	if t == nil || t.Pseudo {
+		return 0, false
+	}
+	var healthy int64
+	if t.ModifyCount < t.Count {
+		healthy = int64((1.0 - float64(t.ModifyCount)/float64(t.Count)) * 100.0)
+	} else if t.ModifyCount == 0 {
+		healthy = 100
+	}
+	return healthy, true
+}
+
+// ModifyRatio returns the ratio of the modified rows to the total rows of the table.
+func (t *Table) ModifyRatio() float64 {
+	if t.Count == 0 {
+		if t.ModifyCount == 0 {
+			return 0
+		}
+		return 1
+	}
+	return float64(t.ModifyCount) / float64(t.Count)
+}
+
+// LastAnalyzeVersion returns the max version of the column and index stats which are collected by analyze.
+// It returns 0 if the table has never been analyzed.
+func (t *Table) LastAnalyzeVersion() (version uint64) {
+	for _, col := range t.Columns {
+		if col.StatsVer != Version0 && col.LastUpdateVersion > version {
+			version = col.LastUpdateVersion
+		}
+	}
+	for _, idx := range t.Indices {
+		if idx.StatsVer != Version0 && idx.LastUpdateVersion > version {
+			version = idx.LastUpdateVersion
+		}
+	}
+	return version
+}
+
+// MissingColumnStatsCount returns the number of the public columns of the table which have no stats collected.
+func (t *Table) MissingColumnStatsCount(tblInfo *model.TableInfo) (count int) {
+	for _, colInfo := range tblInfo.Columns {
+		if colInfo.State != model.StatePublic {
+			continue
+		}
+		if col, ok := t.Columns[colInfo.ID]; !ok || col.StatsVer == Version0 {
+			count++
+		}
+	}
+	return count
+}
+
 // ColumnGreaterRowCount estimates the row count where the column greater than value.
 func (t *Table) ColumnGreaterRowCount(sc *stmtctx.StatementContext, value types.Datum, colID int64) float64 {
 	c, ok := t.Columns[colID]
@@ -35,6 +35,7 @@ import (
 	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/kv"
 	tidbmetrics "github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/resourcegroup"
 	"github.com/pingcap/tidb/store/tikv"
 	tikvstore "github.com/pingcap/tidb/store/tikv/kv"
 	"github.com/pingcap/tidb/store/tikv/logutil"
@@ -692,6 +693,14 @@ func (worker *copIteratorWorker) handleTaskOnce(bo *tikv.Backoffer, task *copTas
 		}
 	}
 
+	groupName := worker.req.ResourceGroupName
+	if groupName != "" {
+		err := resourcegroup.GlobalManager().Consume(bo.GetCtx(), groupName, tidbmetrics.LblRead, resourcegroup.ReadRequestRU)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
 	req := tikvrpc.NewReplicaReadRequest(task.cmdType, &copReq, worker.req.ReplicaRead, &worker.replicaReadSeed, kvrpcpb.Context{
 		IsolationLevel: tikv.IsolationLevelToPB(worker.req.IsolationLevel),
 		Priority:       tikv.PriorityToPB(worker.req.Priority),
@@ -734,7 +743,11 @@ func (worker *copIteratorWorker) handleTaskOnce(bo *tikv.Backoffer, task *copTas
 	}
 
 	// Handles the response for non-streaming copTask.
-	return worker.handleCopResponse(bo, rpcCtx, &copResponse{pbResp: resp.Resp.(*coprocessor.Response)}, cacheKey, cacheValue, task, ch, nil, costTime)
+	pbResp := resp.Resp.(*coprocessor.Response)
+	if groupName != "" {
+		resourcegroup.GlobalManager().Charge(groupName, tidbmetrics.LblRead, resourcegroup.ReadBytesRU(len(pbResp.Data)))
+	}
+	return worker.handleCopResponse(bo, rpcCtx, &copResponse{pbResp: pbResp}, cacheKey, cacheValue, task, ch, nil, costTime)
 }
 
 const (
@@ -1124,6 +1137,7 @@ func (e *rateLimitAction) Action(t *memory.Tracker) {
 			zap.Uint("remaining token count", e.cond.remainingTokenNum))
 		e.cond.exceeded = true
 		e.cond.triggerCountForTest++
+		t.RecordTriggeredAction(memory.ActionNameRateLimit)
 	})
 }
 
@@ -22,18 +22,20 @@ import (
 	"github.com/pingcap/tidb/sessionctx/binloginfo"
 	"github.com/pingcap/tidb/store/tikv"
 	tikvstore "github.com/pingcap/tidb/store/tikv/kv"
+	"github.com/pingcap/tidb/store/tikv/unionstore"
 	"github.com/pingcap/tidb/tablecodec"
 )
 
 type tikvTxn struct {
 	*tikv.KVTxn
 	idxNameCache map[int64]*model.TableInfo
+	tempTables   kv.TemporaryTableRetriever
 }
 
 // NewTiKVTxn returns a new Transaction.
 func NewTiKVTxn(txn *tikv.KVTxn) kv.Transaction {
 	txn.SetOption(tikvstore.KVFilter, TiDBKVFilter{})
-	return &tikvTxn{txn, make(map[int64]*model.TableInfo)}
+	return &tikvTxn{txn, make(map[int64]*model.TableInfo), nil}
 }
 
 func (txn *tikvTxn) GetTableInfo(id int64) *model.TableInfo {
@@ -46,6 +48,13 @@ func (txn *tikvTxn) CacheTableInfo(id int64, info *model.TableInfo) {
 
 // lockWaitTime in ms, except that kv.LockAlwaysWait(0) means always wait lock, kv.LockNowait(-1) means nowait lock
 func (txn *tikvTxn) LockKeys(ctx context.Context, lockCtx *kv.LockCtx, keysInput ...kv.Key) error {
+	if txn.tempTables != nil {
+		// The keys of local temporary tables are never written to the storage, so they needn't be locked.
+		keysInput = txn.filterTemporaryTableKeys(keysInput)
+		if len(keysInput) == 0 {
+			return nil
+		}
+	}
 	keys := toTiKVKeys(keysInput)
 	err := txn.KVTxn.LockKeys(ctx, lockCtx, keys...)
 	return txn.extractKeyErr(err)
@@ -66,6 +75,9 @@ func (txn *tikvTxn) GetSnapshot() kv.Snapshot {
 // It yields only keys that < upperBound. If upperBound is nil, it means the upperBound is unbounded.
 // The Iterator must be Closed after use.
 func (txn *tikvTxn) Iter(k kv.Key, upperBound kv.Key) (kv.Iterator, error) {
+	if isTemporaryTableKey(txn.tempTables, k) {
+		return txn.iterTemporaryTable(k, upperBound)
+	}
 	it, err := txn.KVTxn.Iter(k, upperBound)
 	return newKVIterator(it), errors.Trace(err)
 }
@@ -80,7 +92,37 @@ func (txn *tikvTxn) IterReverse(k kv.Key) (kv.Iterator, error) {
 }
 
 func (txn *tikvTxn) BatchGet(ctx context.Context, keys []kv.Key) (map[string][]byte, error) {
-	return txn.KVTxn.BatchGet(ctx, toTiKVKeys(keys))
+	if txn.tempTables == nil {
+		return txn.KVTxn.BatchGet(ctx, toTiKVKeys(keys))
+	}
+	normalKeys := make([]kv.Key, 0, len(keys))
+	tempKeys := make([]kv.Key, 0)
+	for _, k := range keys {
+		if isTemporaryTableKey(txn.tempTables, k) {
+			tempKeys = append(tempKeys, k)
+		} else {
+			normalKeys = append(normalKeys, k)
+		}
+	}
+	values := make(map[string][]byte, len(keys))
+	if len(normalKeys) > 0 {
+		var err error
+		values, err = txn.KVTxn.BatchGet(ctx, toTiKVKeys(normalKeys))
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, k := range tempKeys {
+		val, err := txn.getTemporaryTableValue(ctx, k)
+		if kv.IsErrNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		values[string(k)] = val
+	}
+	return values, nil
 }
 
 func (txn *tikvTxn) Delete(k kv.Key) error {
@@ -88,6 +130,9 @@ func (txn *tikvTxn) Delete(k kv.Key) error {
 }
 
 func (txn *tikvTxn) Get(ctx context.Context, k kv.Key) ([]byte, error) {
+	if isTemporaryTableKey(txn.tempTables, k) {
+		return txn.getTemporaryTableValue(ctx, k)
+	}
 	return txn.KVTxn.Get(ctx, k)
 }
 
@@ -99,6 +144,10 @@ func (txn *tikvTxn) GetMemBuffer() kv.MemBuffer {
 	return newMemBuffer(txn.KVTxn.GetMemBuffer())
 }
 
+func (txn *tikvTxn) RevertToMemCheckpoint(cp kv.MemCheckpoint) {
+	txn.KVTxn.GetMemBuffer().RevertToCheckpoint(cp.(*unionstore.MemDBCheckpoint))
+}
+
 func (txn *tikvTxn) GetUnionStore() kv.UnionStore {
 	return &tikvUnionStore{txn.KVTxn.GetUnionStore()}
 }
@@ -110,6 +159,9 @@ func (txn *tikvTxn) SetOption(opt int, val interface{}) {
 			txn:     txn.KVTxn,
 			binInfo: val.(*binloginfo.BinlogInfo), // val cannot be other type.
 		})
+	case tikvstore.TemporaryTables:
+		txn.tempTables, _ = val.(kv.TemporaryTableRetriever)
+		txn.KVTxn.SetOption(tikvstore.KVFilter, TiDBKVFilter{tempTables: txn.tempTables})
 	default:
 		txn.KVTxn.SetOption(opt, val)
 	}
@@ -143,10 +195,106 @@ func (txn *tikvTxn) extractKeyExistsErr(key kv.Key) error {
 	return extractKeyExistsErrFromIndex(key, value, tblInfo, indexID)
 }
 
+// getTemporaryTableValue reads the key of a local temporary table, the uncommitted value in
+// the mem buffer is read first, then the committed value kept by the session.
+func (txn *tikvTxn) getTemporaryTableValue(ctx context.Context, k kv.Key) ([]byte, error) {
+	val, err := txn.KVTxn.GetMemBuffer().Get(k)
+	if kv.IsErrNotFound(err) {
+		val, err = txn.tempTables.Get(ctx, k)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// The key has been deleted.
+	if len(val) == 0 {
+		return nil, kv.ErrNotExist
+	}
+	return val, nil
+}
+
+func (txn *tikvTxn) iterTemporaryTable(k kv.Key, upperBound kv.Key) (kv.Iterator, error) {
+	dirtyIt, err := txn.KVTxn.GetMemBuffer().Iter(k, upperBound)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	it, err := txn.tempTables.Iter(k, upperBound)
+	if err != nil {
+		dirtyIt.Close()
+		return nil, errors.Trace(err)
+	}
+	snapshotIt, err := newTemporaryTableIter(it)
+	if err != nil {
+		dirtyIt.Close()
+		it.Close()
+		return nil, errors.Trace(err)
+	}
+	unionIt, err := unionstore.NewUnionIter(dirtyIt, snapshotIt, false)
+	if err != nil {
+		dirtyIt.Close()
+		it.Close()
+		return nil, errors.Trace(err)
+	}
+	return newKVIterator(unionIt), nil
+}
+
+func (txn *tikvTxn) filterTemporaryTableKeys(keys []kv.Key) []kv.Key {
+	filtered := keys[:0:0]
+	for _, k := range keys {
+		if !isTemporaryTableKey(txn.tempTables, k) {
+			filtered = append(filtered, k)
+		}
+	}
+	return filtered
+}
+
+func isTemporaryTableKey(tempTables kv.TemporaryTableRetriever, key []byte) bool {
+	if tempTables == nil || len(key) < tablecodec.TableSplitKeyLen {
+		return false
+	}
+	tableID := tablecodec.DecodeTableID(key)
+	return tableID != 0 && tempTables.IsTemporaryTable(tableID)
+}
+
+// temporaryTableIter wraps the kv.Iterator of the committed local temporary table data as unionstore.Iterator,
+// the deleted keys are skipped.
+type temporaryTableIter struct {
+	kv.Iterator
+}
+
+func newTemporaryTableIter(it kv.Iterator) (*temporaryTableIter, error) {
+	iter := &temporaryTableIter{Iterator: it}
+	return iter, iter.skipDeleted()
+}
+
+func (it *temporaryTableIter) Key() []byte {
+	return it.Iterator.Key()
+}
+
+func (it *temporaryTableIter) Next() error {
+	if err := it.Iterator.Next(); err != nil {
+		return err
+	}
+	return it.skipDeleted()
+}
+
+func (it *temporaryTableIter) skipDeleted() error {
+	for it.Iterator.Valid() && len(it.Iterator.Value()) == 0 {
+		if err := it.Iterator.Next(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // TiDBKVFilter is the filter specific to TiDB to filter out KV pairs that needn't be committed.
-type TiDBKVFilter struct{}
+type TiDBKVFilter struct {
+	tempTables kv.TemporaryTableRetriever
+}
 
 // IsUnnecessaryKeyValue defines which kinds of KV pairs from TiDB needn't be committed.
 func (f TiDBKVFilter) IsUnnecessaryKeyValue(key, value []byte, flags tikvstore.KeyFlags) bool {
+	if isTemporaryTableKey(f.tempTables, key) {
+		return true
+	}
 	return tablecodec.IsUntouchedIndexKValue(key, value)
 }
@@ -92,6 +92,11 @@ type memBuffer struct {
 	*unionstore.MemDB
 }
 
+// NewMemBuffer creates a kv.MemBuffer which doesn't belong to any transaction.
+func NewMemBuffer() kv.MemBuffer {
+	return newMemBuffer(unionstore.NewUnionStore(nil).GetMemBuffer())
+}
+
 func newMemBuffer(m *unionstore.MemDB) kv.MemBuffer {
 	if m == nil {
 		return nil
@@ -134,6 +139,14 @@ func (m *memBuffer) InspectStage(handle kv.StagingHandle, f func(kv.Key, tikvsto
 	m.MemDB.InspectStage(int(handle), tf)
 }
 
+func (m *memBuffer) Checkpoint() kv.MemCheckpoint {
+	return m.MemDB.Checkpoint()
+}
+
+func (m *memBuffer) RevertToCheckpoint(cp kv.MemCheckpoint) {
+	m.MemDB.RevertToCheckpoint(cp.(*unionstore.MemDBCheckpoint))
+}
+
 func (m *memBuffer) Set(key kv.Key, value []byte) error {
 	return m.MemDB.Set(key, value)
 }
@@ -832,6 +832,7 @@ func (c *twoPhaseCommitter) checkAsyncCommit() bool {
 	}
 
 	enableAsyncCommitOption := c.txn.us.GetOption(kv.EnableAsyncCommit)
+	// The pipelined transactions use async commit only if it's enabled, they don't bypass the switch.
 	enableAsyncCommit := enableAsyncCommitOption != nil && enableAsyncCommitOption.(bool)
 	asyncCommitCfg := config.GetGlobalConfig().TiKVClient.AsyncCommit
 	// TODO the keys limit need more tests, this value makes the unit test pass by now.
@@ -860,7 +861,9 @@ func (c *twoPhaseCommitter) checkOnePC() bool {
 	}
 
 	enable1PCOption := c.txn.us.GetOption(kv.Enable1PC)
-	return c.sessionID > 0 && !c.shouldWriteBinlog() && enable1PCOption != nil && enable1PCOption.(bool)
+	// The pipelined transactions use 1PC only if it's enabled, they don't bypass the switch.
+	enable1PC := enable1PCOption != nil && enable1PCOption.(bool)
+	return c.sessionID > 0 && !c.shouldWriteBinlog() && enable1PC
 }
 
 func (c *twoPhaseCommitter) needLinearizability() bool {
@@ -37,4 +37,7 @@ type LockCtx struct {
 	ValuesLock            sync.Mutex
 	LockExpired           *uint32
 	Stats                 *util.LockKeysDetails
+	// OnLockWait is called when acquiring the lock of the key is blocked by the lock of the transaction holdingTxnID,
+	// it may be called concurrently.
+	OnLockWait func(key []byte, holdingTxnID uint64)
 }
@@ -61,6 +61,16 @@ const (
 	MatchStoreLabels
 	// KVFilter filters out the key-value pairs in the memBuf that is unnecessary to be committed
 	KVFilter
+	// TemporaryTables is used to read and write the local temporary tables of the session,
+	// the keys of these tables are never sent to the storage.
+	TemporaryTables
+	// XAPrepare makes Commit only prewrite the transaction, which is the first phase of the XA transactions.
+	// The prepared transaction is committed by KVTxn.CommitPrepared or rolled back by KVTxn.RollbackPrepared.
+	XAPrepare
+	// PipelinedCommit marks the auto-commit transaction acknowledged right after its keys are prewritten. It only
+	// takes effect together with Enable1PC or EnableAsyncCommit, the transaction uses 1PC or async commit only if
+	// the corresponding option is set.
+	PipelinedCommit
 )
 
 // Priority value for transaction priority.
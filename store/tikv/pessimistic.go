@@ -161,6 +161,9 @@ func (action actionPessimisticLock) handleSingleBatch(c *twoPhaseCommitter, bo *
 				return errors.Trace(err1)
 			}
 			locks = append(locks, lock)
+			if action.OnLockWait != nil {
+				action.OnLockWait(lock.Key, lock.TxnID)
+			}
 		}
 		// Because we already waited on tikv, no need to Backoff here.
 		// tikv default will wait 3s(also the maximum wait value) when lock error occurs
@@ -314,6 +314,31 @@ func (c *RegionCache) Close() {
 	close(c.closeCh)
 }
 
+// RegionCacheStats is a snapshot of the region cache size.
+type RegionCacheStats struct {
+	Regions        int `json:"regions"`
+	ExpiredRegions int `json:"expired_regions"`
+	Stores         int `json:"stores"`
+}
+
+// Stats returns the current number of cached regions and stores.
+func (c *RegionCache) Stats() RegionCacheStats {
+	var stats RegionCacheStats
+	ts := time.Now().Unix()
+	c.mu.RLock()
+	for _, r := range c.mu.regions {
+		if !r.checkRegionCacheTTL(ts) {
+			stats.ExpiredRegions++
+		}
+	}
+	stats.Regions = len(c.mu.regions)
+	c.mu.RUnlock()
+	c.storeMu.RLock()
+	stats.Stores = len(c.storeMu.stores)
+	c.storeMu.RUnlock()
+	return stats
+}
+
 // asyncCheckAndResolveLoop with
 func (c *RegionCache) asyncCheckAndResolveLoop(interval time.Duration) {
 	ticker := time.NewTicker(interval)
@@ -226,6 +226,59 @@ func (s *testOnePCSuite) Test1PCDisallowMultiRegion(c *C) {
 	}
 }
 
+func (s *testOnePCSuite) TestPipelinedCommit(c *C) {
+	// This test doesn't support tikv mode.
+	if *WithTiKV {
+		return
+	}
+
+	ctx := context.WithValue(context.Background(), util.SessionID, uint64(1))
+	beginPipelined := func(enabled bool) tikv.TxnProbe {
+		txn := s.begin(c)
+		txn.SetOption(kv.PipelinedCommit, true)
+		txn.SetOption(kv.Enable1PC, enabled)
+		txn.SetOption(kv.EnableAsyncCommit, enabled)
+		return txn
+	}
+
+	// The pipelined transaction doesn't use 1PC or async commit if they aren't enabled.
+	txn := beginPipelined(false)
+	c.Assert(txn.Set([]byte("k0"), []byte("v0")), IsNil)
+	c.Assert(txn.Commit(ctx), IsNil)
+	c.Assert(txn.GetCommitter().IsOnePC(), IsFalse)
+	c.Assert(txn.GetCommitter().IsAsyncCommit(), IsFalse)
+	s.mustPointGet(c, []byte("k0"), []byte("v0"))
+
+	// The pipelined transaction uses 1PC if it's enabled.
+	txn = beginPipelined(true)
+	c.Assert(txn.Set([]byte("k1"), []byte("v1")), IsNil)
+	c.Assert(txn.Commit(ctx), IsNil)
+	c.Assert(txn.GetCommitter().IsOnePC(), IsTrue)
+	s.mustGetFromSnapshot(c, txn.GetCommitTS(), []byte("k1"), []byte("v1"))
+
+	// The pipelined transaction uses async commit if it's enabled and the transaction affects multiple regions.
+	loc, err := s.store.GetRegionCache().LocateKey(s.bo, []byte("k2"))
+	c.Assert(err, IsNil)
+	newRegionID := s.cluster.AllocID()
+	newPeerID := s.cluster.AllocID()
+	s.cluster.Split(loc.Region.GetID(), newRegionID, []byte("k2"), []uint64{newPeerID}, newPeerID)
+	txn = beginPipelined(true)
+	c.Assert(txn.Set([]byte("k1"), []byte("v1new")), IsNil)
+	c.Assert(txn.Set([]byte("k2"), []byte("v2")), IsNil)
+	c.Assert(txn.Commit(ctx), IsNil)
+	c.Assert(txn.GetCommitter().IsOnePC(), IsFalse)
+	c.Assert(txn.GetCommitter().IsAsyncCommit(), IsTrue)
+	s.mustPointGet(c, []byte("k1"), []byte("v1new"))
+	s.mustPointGet(c, []byte("k2"), []byte("v2"))
+
+	// The pipelined transaction doesn't work if sessionID == 0.
+	txn = beginPipelined(true)
+	c.Assert(txn.Set([]byte("k3"), []byte("v3")), IsNil)
+	c.Assert(txn.Commit(context.Background()), IsNil)
+	c.Assert(txn.GetCommitter().IsOnePC(), IsFalse)
+	c.Assert(txn.GetCommitter().IsAsyncCommit(), IsFalse)
+}
+
 // It's just a simple validation of linearizability.
 // Extra tests are needed to test this feature with the control of the TiKV cluster.
 func (s *testOnePCSuite) Test1PCLinearizability(c *C) {
@@ -289,6 +289,9 @@ func (txn *KVTxn) Commit(ctx context.Context) error {
 			}
 		}
 	}()
+	if txn.us.GetOption(kv.XAPrepare) != nil {
+		return errors.Trace(committer.prepareXA(ctx))
+	}
 	// latches disabled
 	// pessimistic transaction should also bypass latch.
 	if txn.store.txnLatches == nil || txn.IsPessimistic() {
@@ -64,14 +64,14 @@ type MemDB struct {
 
 	vlogInvalid bool
 	dirty       bool
-	stages      []memdbCheckpoint
+	stages      []MemDBCheckpoint
 }
 
 func newMemDB() *MemDB {
 	db := new(MemDB)
 	db.allocator.init()
 	db.root = nullAddr
-	db.stages = make([]memdbCheckpoint, 0, 2)
+	db.stages = make([]MemDBCheckpoint, 0, 2)
 	db.entrySizeLimit = atomic.LoadUint64(&tidbkv.TxnEntrySizeLimit)
 	db.bufferSizeLimit = atomic.LoadUint64(&tidbkv.TxnTotalSizeLimit)
 	return db
@@ -132,6 +132,37 @@ func (db *MemDB) Cleanup(h int) {
 	db.stages = db.stages[:h-1]
 }
 
+// Checkpoint returns the current position of the MemDB, the modifications after it can be reverted by
+// RevertToCheckpoint.
+func (db *MemDB) Checkpoint() *MemDBCheckpoint {
+	db.RLock()
+	defer db.RUnlock()
+	cp := db.vlog.checkpoint()
+	return &cp
+}
+
+// RevertToCheckpoint discards all the modifications after the checkpoint. The flags are not reverted, so the
+// locked keys are still locked. The staging buffers created after the checkpoint are kept but become empty.
+func (db *MemDB) RevertToCheckpoint(cp *MemDBCheckpoint) {
+	db.Lock()
+	defer db.Unlock()
+	if db.vlogInvalid {
+		// panic for easier debugging.
+		panic("vlog is resetted")
+	}
+	curr := db.vlog.checkpoint()
+	if !curr.isAfter(cp) {
+		return
+	}
+	db.vlog.revertToCheckpoint(db, cp)
+	db.vlog.truncate(cp)
+	for i := range db.stages {
+		if db.stages[i].isAfter(cp) {
+			db.stages[i] = *cp
+		}
+	}
+}
+
 // Reset resets the MemBuffer to initial states.
 func (db *MemDB) Reset() {
 	db.root = nullAddr
@@ -312,7 +343,7 @@ func (db *MemDB) set(key []byte, value []byte, ops ...kv.FlagsOp) error {
 }
 
 func (db *MemDB) setValue(x memdbNodeAddr, value []byte) {
-	var activeCp *memdbCheckpoint
+	var activeCp *MemDBCheckpoint
 	if len(db.stages) > 0 {
 		activeCp = &db.stages[len(db.stages)-1]
 	}
@@ -134,18 +134,26 @@ func (a *memdbArenaBlock) reset() {
 	a.length = 0
 }
 
-type memdbCheckpoint struct {
+// MemDBCheckpoint is the position of the value log of MemDB, the modifications after it can be reverted.
+type MemDBCheckpoint struct {
 	blockSize     int
 	blocks        int
 	offsetInBlock int
 }
 
-func (cp *memdbCheckpoint) isSamePosition(other *memdbCheckpoint) bool {
+func (cp *MemDBCheckpoint) isSamePosition(other *MemDBCheckpoint) bool {
 	return cp.blocks == other.blocks && cp.offsetInBlock == other.offsetInBlock
 }
 
-func (a *memdbArena) checkpoint() memdbCheckpoint {
-	snap := memdbCheckpoint{
+func (cp *MemDBCheckpoint) isAfter(other *MemDBCheckpoint) bool {
+	if cp.blocks != other.blocks {
+		return cp.blocks > other.blocks
+	}
+	return cp.offsetInBlock > other.offsetInBlock
+}
+
+func (a *memdbArena) checkpoint() MemDBCheckpoint {
+	snap := MemDBCheckpoint{
 		blockSize: a.blockSize,
 		blocks:    len(a.blocks),
 	}
@@ -155,7 +163,7 @@ func (a *memdbArena) checkpoint() memdbCheckpoint {
 	return snap
 }
 
-func (a *memdbArena) truncate(snap *memdbCheckpoint) {
+func (a *memdbArena) truncate(snap *MemDBCheckpoint) {
 	for i := snap.blocks; i < len(a.blocks); i++ {
 		a.blocks[i] = memdbArenaBlock{}
 	}
@@ -277,7 +285,7 @@ func (l *memdbVlog) getValue(addr memdbArenaAddr) []byte {
 	return block[valueOff:lenOff:lenOff]
 }
 
-func (l *memdbVlog) getSnapshotValue(addr memdbArenaAddr, snap *memdbCheckpoint) ([]byte, bool) {
+func (l *memdbVlog) getSnapshotValue(addr memdbArenaAddr, snap *MemDBCheckpoint) ([]byte, bool) {
 	result := l.selectValueHistory(addr, func(addr memdbArenaAddr) bool {
 		return !l.canModify(snap, addr)
 	})
@@ -299,7 +307,7 @@ func (l *memdbVlog) selectValueHistory(addr memdbArenaAddr, predicate func(memdb
 	return nullAddr
 }
 
-func (l *memdbVlog) revertToCheckpoint(db *MemDB, cp *memdbCheckpoint) {
+func (l *memdbVlog) revertToCheckpoint(db *MemDB, cp *MemDBCheckpoint) {
 	cursor := l.checkpoint()
 	for !cp.isSamePosition(&cursor) {
 		hdrOff := cursor.offsetInBlock - memdbVlogHdrSize
@@ -328,7 +336,7 @@ func (l *memdbVlog) revertToCheckpoint(db *MemDB, cp *memdbCheckpoint) {
 	}
 }
 
-func (l *memdbVlog) inspectKVInLog(db *MemDB, head, tail *memdbCheckpoint, f func([]byte, kv.KeyFlags, []byte)) {
+func (l *memdbVlog) inspectKVInLog(db *MemDB, head, tail *MemDBCheckpoint, f func([]byte, kv.KeyFlags, []byte)) {
 	cursor := *tail
 	for !head.isSamePosition(&cursor) {
 		cursorAddr := memdbArenaAddr{idx: uint32(cursor.blocks - 1), off: uint32(cursor.offsetInBlock)}
@@ -348,7 +356,7 @@ func (l *memdbVlog) inspectKVInLog(db *MemDB, head, tail *memdbCheckpoint, f fun
 	}
 }
 
-func (l *memdbVlog) moveBackCursor(cursor *memdbCheckpoint, hdr *memdbVlogHdr) {
+func (l *memdbVlog) moveBackCursor(cursor *MemDBCheckpoint, hdr *memdbVlogHdr) {
 	cursor.offsetInBlock -= (memdbVlogHdrSize + int(hdr.valueLen))
 	if cursor.offsetInBlock == 0 {
 		cursor.blocks--
@@ -358,7 +366,7 @@ func (l *memdbVlog) moveBackCursor(cursor *memdbCheckpoint, hdr *memdbVlogHdr) {
 	}
 }
 
-func (l *memdbVlog) canModify(cp *memdbCheckpoint, addr memdbArenaAddr) bool {
+func (l *memdbVlog) canModify(cp *MemDBCheckpoint, addr memdbArenaAddr) bool {
 	if cp == nil {
 		return true
 	}
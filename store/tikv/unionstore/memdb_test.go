@@ -529,6 +529,51 @@ func (s *testMemDBSuite) TestFlags(c *C) {
 	}
 }
 
+func (s *testMemDBSuite) TestCheckpoint(c *C) {
+	db := newMemDB()
+	db.Set([]byte{1}, []byte{1})
+	cp1 := db.Checkpoint()
+
+	h := db.Staging()
+	db.Set([]byte{1}, []byte{2})
+	db.SetWithFlags([]byte{2}, []byte{2}, kv.SetKeyLocked)
+	db.Release(h)
+	cp2 := db.Checkpoint()
+
+	h = db.Staging()
+	db.Set([]byte{3}, []byte{3})
+	// The staging buffer becomes empty if it's created after the checkpoint.
+	db.RevertToCheckpoint(cp2)
+	_, err := db.Get([]byte{3})
+	c.Assert(err, NotNil)
+	db.Set([]byte{4}, []byte{4})
+	db.Cleanup(h)
+	_, err = db.Get([]byte{4})
+	c.Assert(err, NotNil)
+	v, err := db.Get([]byte{2})
+	c.Assert(err, IsNil)
+	c.Assert(v, BytesEquals, []byte{2})
+
+	db.RevertToCheckpoint(cp1)
+	v, err = db.Get([]byte{1})
+	c.Assert(err, IsNil)
+	c.Assert(v, BytesEquals, []byte{1})
+	_, err = db.Get([]byte{2})
+	c.Assert(err, NotNil)
+	// The flags are not reverted.
+	flags, err := db.GetFlags([]byte{2})
+	c.Assert(err, IsNil)
+	c.Assert(flags.HasLocked(), IsTrue)
+	c.Assert(db.Len(), Equals, 2)
+	c.Assert(db.Size(), Equals, 3)
+
+	// Reverting to a checkpoint after the current position is a no-op.
+	db.RevertToCheckpoint(cp2)
+	v, err = db.Get([]byte{1})
+	c.Assert(err, IsNil)
+	c.Assert(v, BytesEquals, []byte{1})
+}
+
 func (s *testMemDBSuite) checkConsist(c *C, p1 *MemDB, p2 *leveldb.DB) {
 	c.Assert(p1.Len(), Equals, p2.Len())
 	c.Assert(p1.Size(), Equals, p2.Size())
@@ -0,0 +1,187 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tikv
+
+import (
+	"context"
+	"encoding/hex"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/errors"
+	pb "github.com/pingcap/kvproto/pkg/kvrpcpb"
+	"github.com/pingcap/tidb/store/tikv/kv"
+	"github.com/pingcap/tidb/store/tikv/logutil"
+	"github.com/pingcap/tidb/store/tikv/oracle"
+	"github.com/pingcap/tidb/store/tikv/tikvrpc"
+	"go.uber.org/zap"
+)
+
+// prepareXA prewrites the mutations without committing them, and extends the TTL of the primary lock, so the locks
+// are kept until the prepared transaction is committed or rolled back. The schema is checked here instead of at
+// the commit, so the prepared transaction can always be committed.
+func (c *twoPhaseCommitter) prepareXA(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil && c.getUndeterminedErr() == nil {
+			c.cleanup(ctx)
+		}
+	}()
+	c.prewriteStarted = true
+	prewriteBo := NewBackofferWithVars(ctx, PrewriteMaxBackoff, c.txn.vars)
+	start := time.Now()
+	err = c.prewriteMutations(prewriteBo, c.mutations)
+	c.getDetail().PrewriteTime = time.Since(start)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.stripNoNeedCommitKeys()
+
+	bo := NewBackofferWithVars(ctx, tsoMaxBackoff, c.txn.vars)
+	checkTS, err := c.store.getTimestampWithRetry(bo, c.txn.GetUnionStore().GetOption(kv.TxnScope).(string))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, _, err = c.checkSchemaValid(ctx, checkTS, c.txn.txnInfoSchema, false); err != nil {
+		return errors.Trace(err)
+	}
+	uptime := uint64(oracle.ExtractPhysical(checkTS) - oracle.ExtractPhysical(c.startTS))
+	_, err = sendTxnHeartBeat(NewBackofferWithVars(ctx, pessimisticLockMaxBackoff, c.txn.vars), c.store, c.primary(), c.startTS, uptime+atomic.LoadUint64(&ManagedLockTTL))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	logutil.Logger(ctx).Info("XA transaction prepared",
+		zap.Uint64("txnStartTS", c.startTS), zap.String("primary", hex.EncodeToString(c.primary())))
+	return nil
+}
+
+// PreparedPrimary returns the primary key of the transaction prepared by the XAPrepare option, it returns nil if
+// the transaction writes nothing.
+func (txn *KVTxn) PreparedPrimary() []byte {
+	if txn.committer == nil || !txn.committer.prewriteStarted {
+		return nil
+	}
+	return txn.committer.primary()
+}
+
+// CommitPrepared commits all the keys of the transaction prepared by the XAPrepare option.
+func (txn *KVTxn) CommitPrepared(ctx context.Context) error {
+	c := txn.committer
+	if c == nil || !c.prewriteStarted {
+		return nil
+	}
+	bo := NewBackofferWithVars(ctx, tsoMaxBackoff, txn.vars)
+	commitTS, err := txn.store.getTimestampWithRetry(bo, txn.GetUnionStore().GetOption(kv.TxnScope).(string))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	atomic.StoreUint64(&c.commitTS, commitTS)
+	if err = c.commitTxn(ctx, c.getDetail()); err != nil {
+		return errors.Trace(err)
+	}
+	txn.commitTS = commitTS
+	return nil
+}
+
+// RollbackPrepared rolls back all the keys of the transaction prepared by the XAPrepare option.
+func (txn *KVTxn) RollbackPrepared(ctx context.Context) error {
+	c := txn.committer
+	if c == nil || !c.prewriteStarted {
+		return nil
+	}
+	bo := NewBackofferWithVars(ctx, cleanupMaxBackoff, txn.vars)
+	return errors.Trace(c.cleanupMutations(bo, c.mutations))
+}
+
+// CommitPreparedTxn commits the prepared transaction by committing its primary key, the secondary keys are
+// committed when they are met by the other transactions. It's used to commit the prepared transactions after the
+// tidb-server preparing them restarts.
+func (s *KVStore) CommitPreparedTxn(ctx context.Context, primary []byte, startTS uint64) error {
+	bo := NewBackofferWithVars(ctx, int(atomic.LoadUint64(&CommitMaxBackoff)), nil)
+	commitTS, err := s.getTimestampWithRetry(bo, oracle.GlobalTxnScope)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req := tikvrpc.NewRequest(tikvrpc.CmdCommit, &pb.CommitRequest{
+		StartVersion:  startTS,
+		Keys:          [][]byte{primary},
+		CommitVersion: commitTS,
+	})
+	resp, err := s.sendPrimaryReq(bo, req, primary)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if keyErr := resp.Resp.(*pb.CommitResponse).GetError(); keyErr != nil {
+		return errors.Errorf("commit prepared txn %d failed, primary key = %v, err = %s", startTS, hex.EncodeToString(primary), extractKeyErr(keyErr))
+	}
+	return nil
+}
+
+// RollbackPreparedTxn rolls back the prepared transaction by rolling back its primary key, the secondary keys are
+// rolled back when they are met by the other transactions.
+func (s *KVStore) RollbackPreparedTxn(ctx context.Context, primary []byte, startTS uint64) error {
+	bo := NewBackofferWithVars(ctx, cleanupMaxBackoff, nil)
+	req := tikvrpc.NewRequest(tikvrpc.CmdBatchRollback, &pb.BatchRollbackRequest{
+		Keys:         [][]byte{primary},
+		StartVersion: startTS,
+	})
+	resp, err := s.sendPrimaryReq(bo, req, primary)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if keyErr := resp.Resp.(*pb.BatchRollbackResponse).GetError(); keyErr != nil {
+		return errors.Errorf("rollback prepared txn %d failed, primary key = %v, err = %s", startTS, hex.EncodeToString(primary), extractKeyErr(keyErr))
+	}
+	return nil
+}
+
+// KeepPreparedTxnAlive extends the TTL of the primary lock of the prepared transaction, so it's not rolled back by
+// the other transactions meeting its locks.
+func (s *KVStore) KeepPreparedTxnAlive(ctx context.Context, primary []byte, startTS uint64) error {
+	bo := NewBackofferWithVars(ctx, pessimisticLockMaxBackoff, nil)
+	now, err := s.GetOracle().GetTimestamp(ctx, &oracle.Option{TxnScope: oracle.GlobalTxnScope})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	uptime := uint64(oracle.ExtractPhysical(now) - oracle.ExtractPhysical(startTS))
+	_, err = sendTxnHeartBeat(bo, s, primary, startTS, uptime+atomic.LoadUint64(&ManagedLockTTL))
+	return errors.Trace(err)
+}
+
+// sendPrimaryReq sends the request to the region of the primary key, and retries on the region errors.
+func (s *KVStore) sendPrimaryReq(bo *Backoffer, req *tikvrpc.Request, primary []byte) (*tikvrpc.Response, error) {
+	for {
+		loc, err := s.GetRegionCache().LocateKey(bo, primary)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		resp, err := s.SendReq(bo, req, loc.Region, ReadTimeoutShort)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		regionErr, err := resp.GetRegionError()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if regionErr != nil {
+			if err = bo.Backoff(BoRegionMiss, errors.New(regionErr.String())); err != nil {
+				return nil, errors.Trace(err)
+			}
+			continue
+		}
+		if resp.Resp == nil {
+			return nil, errors.Trace(kv.ErrBodyMissing)
+		}
+		return resp, nil
+	}
+}
@@ -340,11 +340,24 @@ func NewHashReverseIter(t *TxStructure, key []byte) (*ReverseHashIterator, error
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return &ReverseHashIterator{
+	iter := &ReverseHashIterator{
 		t:      t,
 		iter:   it,
 		prefix: dataPrefix,
-	}, nil
+	}
+	if !it.Valid() {
+		return iter, nil
+	}
+	// The first key may belong to another key when the hash is empty.
+	if !it.Key().HasPrefix(dataPrefix) {
+		iter.done = true
+		return iter, nil
+	}
+	_, iter.field, err = t.decodeHashDataKey(it.Key())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return iter, nil
 }
 
 func (t *TxStructure) iterReverseHash(key []byte, fn func(k []byte, v []byte) (bool, error)) error {
@@ -82,3 +82,13 @@ type Index interface {
 	// and return the buffer, if it is nil, FetchValues will allocate the buffer instead.
 	FetchValues(row []types.Datum, columns []types.Datum) ([]types.Datum, error)
 }
+
+// FulltextIndex is the interface for the FULLTEXT index on KV store, which is an inverted index
+// maps each word of the indexed columns to the rows containing it.
+type FulltextIndex interface {
+	Index
+	// FetchHandles fetches the handles of the rows containing the word from the retriever.
+	// The handles of the deleted entries, which only exist in the mem buffer of a transaction,
+	// are returned separately.
+	FetchHandles(sc *stmtctx.StatementContext, r kv.Retriever, word string) (handles, deleted []kv.Handle, err error)
+}
@@ -0,0 +1,141 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tables
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/fulltext"
+)
+
+// fulltextIndex is the FULLTEXT index in the KV store. A row has an entry for each distinct word of
+// its indexed columns, the entry is encoded the same as the one of a non-unique index on a single
+// column whose value is the word, so the rows containing a word can be found by the key prefix.
+type fulltextIndex struct {
+	*index
+	// words is the single column index the entries of the words are encoded by.
+	words *index
+}
+
+var _ table.FulltextIndex = &fulltextIndex{}
+
+func newFulltextIndex(idx *index) *fulltextIndex {
+	wordsInfo := idx.idxInfo.Clone()
+	wordsInfo.Columns = wordsInfo.Columns[:1]
+	wordsInfo.Unique = false
+	return &fulltextIndex{
+		index: idx,
+		words: &index{
+			idxInfo:  wordsInfo,
+			tblInfo:  idx.tblInfo,
+			prefix:   idx.prefix,
+			phyTblID: idx.phyTblID,
+		},
+	}
+}
+
+// fulltextWords returns the distinct words of the indexed values.
+func fulltextWords(indexedValues []types.Datum) []types.Datum {
+	texts := make([]string, 0, len(indexedValues))
+	for _, v := range indexedValues {
+		if !v.IsNull() {
+			texts = append(texts, v.GetString())
+		}
+	}
+	words := fulltext.Words(texts...)
+	datums := make([]types.Datum, 0, len(words))
+	for _, word := range words {
+		datums = append(datums, types.NewStringDatum(word))
+	}
+	return datums
+}
+
+// GenIndexKey generates the key of the entry of the word, which is the only value in indexedValues.
+func (c *fulltextIndex) GenIndexKey(sc *stmtctx.StatementContext, indexedValues []types.Datum, h kv.Handle, buf []byte) (key []byte, distinct bool, err error) {
+	return c.words.GenIndexKey(sc, indexedValues, h, buf)
+}
+
+// Create creates the entries of the words of indexedValues.
+func (c *fulltextIndex) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle, handleRestoreData []types.Datum, opts ...table.CreateIdxOptFunc) (kv.Handle, error) {
+	for _, word := range fulltextWords(indexedValues) {
+		if _, err := c.words.Create(sctx, txn, []types.Datum{word}, h, handleRestoreData, opts...); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// Delete removes the entries of the words of indexedValues.
+func (c *fulltextIndex) Delete(sc *stmtctx.StatementContext, us kv.UnionStore, indexedValues []types.Datum, h kv.Handle) error {
+	for _, word := range fulltextWords(indexedValues) {
+		if err := c.words.Delete(sc, us, []types.Datum{word}, h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Exist checks whether the entries of all the words of indexedValues exist.
+func (c *fulltextIndex) Exist(sc *stmtctx.StatementContext, us kv.UnionStore, indexedValues []types.Datum, h kv.Handle) (bool, kv.Handle, error) {
+	for _, word := range fulltextWords(indexedValues) {
+		exist, _, err := c.words.Exist(sc, us, []types.Datum{word}, h)
+		if err != nil || !exist {
+			return false, nil, err
+		}
+	}
+	return true, h, nil
+}
+
+// Seek searches the entries from the word, which is the only value in indexedValues.
+func (c *fulltextIndex) Seek(sc *stmtctx.StatementContext, r kv.Retriever, indexedValues []types.Datum) (iter table.IndexIterator, hit bool, err error) {
+	return c.words.Seek(sc, r, indexedValues)
+}
+
+// SeekFirst returns an iterator which points to the first entry, it returns the word of each entry.
+func (c *fulltextIndex) SeekFirst(r kv.Retriever) (iter table.IndexIterator, err error) {
+	return c.words.SeekFirst(r)
+}
+
+// FetchHandles implements the table.FulltextIndex interface.
+func (c *fulltextIndex) FetchHandles(sc *stmtctx.StatementContext, r kv.Retriever, word string) (handles, deleted []kv.Handle, err error) {
+	prefix, _, err := c.words.GenIndexKey(sc, []types.Datum{types.NewStringDatum(word)}, nil, nil)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	it, err := r.Iter(prefix, kv.Key(prefix).PrefixNext())
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	defer it.Close()
+	for it.Valid() && it.Key().HasPrefix(prefix) {
+		h, err := tablecodec.DecodeIndexHandle(it.Key(), it.Value(), len(c.words.idxInfo.Columns))
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		if len(it.Value()) == 0 {
+			deleted = append(deleted, h)
+		} else {
+			handles = append(handles, h)
+		}
+		if err = it.Next(); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
+	return handles, deleted, nil
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/fulltext"
 	"github.com/pingcap/tidb/util/rowcodec"
 )
 
@@ -126,6 +127,9 @@ func NewIndex(physicalID int64, tblInfo *model.TableInfo, indexInfo *model.Index
 		prefix:   prefix,
 		phyTblID: physicalID,
 	}
+	if fulltext.IsIndex(indexInfo) {
+		return newFulltextIndex(index)
+	}
 	return index
 }
 
@@ -205,8 +209,11 @@ func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValue
 	}
 
 	var value []byte
-	if sctx.GetSessionVars().LazyCheckKeyNotExists() {
+	if lazyCheckKeyNotExists(sctx, c.tblInfo.ID) {
 		value, err = us.GetMemBuffer().Get(ctx, key)
+	} else if sctx.GetSessionVars().IsLocalTemporaryTable(c.tblInfo.ID) {
+		// The committed data of local temporary tables is only visible through the transaction.
+		value, err = txn.Get(ctx, key)
 	} else {
 		value, err = us.Get(ctx, key)
 	}
@@ -214,7 +221,7 @@ func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValue
 		return nil, err
 	}
 	if err != nil || len(value) == 0 {
-		if sctx.GetSessionVars().LazyCheckKeyNotExists() && err != nil {
+		if lazyCheckKeyNotExists(sctx, c.tblInfo.ID) && err != nil {
 			err = us.GetMemBuffer().SetWithFlags(key, idxVal, tikvstore.SetPresumeKeyNotExists)
 		} else {
 			err = us.GetMemBuffer().Set(key, idxVal)
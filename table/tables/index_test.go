@@ -15,6 +15,7 @@ package tables_test
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"time"
 
@@ -231,6 +232,88 @@ func (s *testIndexSuite) TestIndex(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (s *testIndexSuite) TestFulltextIndex(c *C) {
+	tblInfo := &model.TableInfo{
+		ID: 3,
+		Indices: []*model.IndexInfo{
+			{
+				ID:   2,
+				Name: model.NewCIStr("ft"),
+				Tp:   model.IndexTypeFulltext,
+				Columns: []*model.IndexColumn{
+					{Offset: 0, Length: types.UnspecifiedLength},
+					{Offset: 1, Length: types.UnspecifiedLength},
+				},
+			},
+		},
+		Columns: []*model.ColumnInfo{
+			{ID: 1, Name: model.NewCIStr("title"), State: model.StatePublic, Offset: 0, FieldType: *types.NewFieldType(mysql.TypeVarchar)},
+			{ID: 2, Name: model.NewCIStr("body"), State: model.StatePublic, Offset: 1, FieldType: *types.NewFieldType(mysql.TypeBlob)},
+		},
+	}
+	index := tables.NewIndex(tblInfo.ID, tblInfo, tblInfo.Indices[0])
+	ftIndex, ok := index.(table.FulltextIndex)
+	c.Assert(ok, IsTrue)
+
+	txn, err := s.s.Begin()
+	c.Assert(err, IsNil)
+	mockCtx := mock.NewContext()
+	sc := &stmtctx.StatementContext{TimeZone: time.Local}
+	row1 := types.MakeDatums("TiDB database", "a distributed SQL database")
+	row2 := []types.Datum{types.NewStringDatum("MySQL database"), {}}
+	_, err = index.Create(mockCtx, txn, row1, kv.IntHandle(1), nil)
+	c.Assert(err, IsNil)
+	_, err = index.Create(mockCtx, txn, row2, kv.IntHandle(2), nil)
+	c.Assert(err, IsNil)
+
+	// Each row has an entry for each distinct word.
+	it, err := index.SeekFirst(txn)
+	c.Assert(err, IsNil)
+	var entries []string
+	for {
+		vals, h, err := it.Next()
+		if terror.ErrorEqual(err, io.EOF) {
+			break
+		}
+		c.Assert(err, IsNil)
+		c.Assert(vals, HasLen, 1)
+		entries = append(entries, fmt.Sprintf("%s %d", vals[0].GetString(), h.IntValue()))
+	}
+	it.Close()
+	c.Assert(entries, DeepEquals, []string{"database 1", "database 2", "distributed 1", "mysql 2", "sql 1", "tidb 1"})
+
+	handles, deleted, err := ftIndex.FetchHandles(sc, txn, "database")
+	c.Assert(err, IsNil)
+	c.Assert(handles, DeepEquals, []kv.Handle{kv.IntHandle(1), kv.IntHandle(2)})
+	c.Assert(deleted, HasLen, 0)
+	handles, _, err = ftIndex.FetchHandles(sc, txn, "the")
+	c.Assert(err, IsNil)
+	c.Assert(handles, HasLen, 0)
+	exist, _, err := index.Exist(sc, txn.GetUnionStore(), row1, kv.IntHandle(1))
+	c.Assert(err, IsNil)
+	c.Assert(exist, IsTrue)
+	exist, _, err = index.Exist(sc, txn.GetUnionStore(), row1, kv.IntHandle(2))
+	c.Assert(err, IsNil)
+	c.Assert(exist, IsFalse)
+	err = txn.Commit(context.Background())
+	c.Assert(err, IsNil)
+
+	// The deleted entries are returned separately from the mem buffer.
+	txn, err = s.s.Begin()
+	c.Assert(err, IsNil)
+	err = index.Delete(sc, txn.GetUnionStore(), row2, kv.IntHandle(2))
+	c.Assert(err, IsNil)
+	handles, deleted, err = ftIndex.FetchHandles(sc, txn.GetMemBuffer(), "database")
+	c.Assert(err, IsNil)
+	c.Assert(handles, HasLen, 0)
+	c.Assert(deleted, DeepEquals, []kv.Handle{kv.IntHandle(2)})
+	handles, _, err = ftIndex.FetchHandles(sc, txn, "database")
+	c.Assert(err, IsNil)
+	c.Assert(handles, DeepEquals, []kv.Handle{kv.IntHandle(1)})
+	err = txn.Rollback()
+	c.Assert(err, IsNil)
+}
+
 func (s *testIndexSuite) TestCombineIndexSeek(c *C) {
 	tblInfo := &model.TableInfo{
 		ID: 1,
@@ -80,6 +80,10 @@ type partitionedTable struct {
 	partitions      map[int64]*partition
 	evalBufferTypes []*types.FieldType
 	evalBufferPool  sync.Pool
+	// reorgTable is the table of the other partition layout when the partitions are being reorganized.
+	// The rows are written into the partitions of both layouts, so the reorganized partitions are
+	// complete whichever layout is read.
+	reorgTable *partitionedTable
 }
 
 func newPartitionedTable(tbl *TableCommon, tblInfo *model.TableInfo) (table.Table, error) {
@@ -109,9 +113,62 @@ func newPartitionedTable(tbl *TableCommon, tblInfo *model.TableInfo) (table.Tabl
 		partitions[p.ID] = &t
 	}
 	ret.partitions = partitions
+	if from, to := reorganizingDefinitions(pi); len(from) > 0 {
+		reorgInfo := tblInfo.Clone()
+		reorgPi := *pi
+		reorgPi.Definitions = ReorganizedDefinitions(pi.Definitions, from, to)
+		reorgPi.AddingDefinitions, reorgPi.DroppingDefinitions = nil, nil
+		reorgInfo.Partition = &reorgPi
+		reorgCommon := *tbl
+		reorgCommon.meta = reorgInfo
+		reorgTbl, err := newPartitionedTable(&reorgCommon, reorgInfo)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ret.reorgTable = reorgTbl.(*partitionedTable)
+	}
 	return ret, nil
 }
 
+// reorganizingDefinitions returns the partitions of the current layout which are being reorganized,
+// and the partitions which replace them in the other layout. The DDL job puts the new partitions
+// into AddingDefinitions and the old ones into DroppingDefinitions when reorganizing partitions,
+// and the current layout turns to the new one after the rows are backfilled.
+func reorganizingDefinitions(pi *model.PartitionInfo) (from, to []model.PartitionDefinition) {
+	if len(pi.AddingDefinitions) == 0 || len(pi.DroppingDefinitions) == 0 {
+		return nil, nil
+	}
+	for _, def := range pi.Definitions {
+		if def.ID == pi.DroppingDefinitions[0].ID {
+			return pi.DroppingDefinitions, pi.AddingDefinitions
+		}
+	}
+	return pi.AddingDefinitions, pi.DroppingDefinitions
+}
+
+// ReorganizedDefinitions returns the definitions in which the partitions in from are replaced by the
+// ones in to, which are put at the position of the first partition in from.
+func ReorganizedDefinitions(defs, from, to []model.PartitionDefinition) []model.PartitionDefinition {
+	newDefs := make([]model.PartitionDefinition, 0, len(defs)-len(from)+len(to))
+	for _, def := range defs {
+		if def.ID == from[0].ID {
+			newDefs = append(newDefs, to...)
+			continue
+		}
+		replaced := false
+		for _, fromDef := range from {
+			if def.ID == fromDef.ID {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			newDefs = append(newDefs, def)
+		}
+	}
+	return newDefs
+}
+
 func newPartitionExpr(tblInfo *model.TableInfo) (*PartitionExpr, error) {
 	ctx := mock.NewContext()
 	dbName := model.NewCIStr(ctx.GetSessionVars().CurrentDB)
@@ -1099,7 +1156,11 @@ func partitionedTableAddRecord(ctx sessionctx.Context, t *partitionedTable, r []
 		}
 	}
 	tbl := t.GetPartition(pid)
-	return tbl.AddRecord(ctx, r, opts...)
+	recordID, err = tbl.AddRecord(ctx, r, opts...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return recordID, t.addReorgRecord(ctx, recordID, r)
 }
 
 // partitionTableWithGivenSets is used for this kind of grammar: partition (p0,p1)
@@ -1143,7 +1204,50 @@ func (t *partitionedTable) RemoveRecord(ctx sessionctx.Context, h kv.Handle, r [
 	}
 
 	tbl := t.GetPartition(pid)
-	return tbl.RemoveRecord(ctx, h, r)
+	if err = tbl.RemoveRecord(ctx, h, r); err != nil {
+		return errors.Trace(err)
+	}
+	return t.removeReorgRecord(ctx, h, r)
+}
+
+// locateReorgPartition returns the partition of the other layout which the row belongs to when the
+// partitions are being reorganized. It returns nil if the partition also belongs to the current
+// layout or its state is before the given state.
+func (t *partitionedTable) locateReorgPartition(ctx sessionctx.Context, r []types.Datum, state model.SchemaState) (*partition, error) {
+	if t.reorgTable == nil {
+		return nil, nil
+	}
+	pid, err := t.reorgTable.locatePartition(ctx, t.reorgTable.meta.Partition, r)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, ok := t.partitions[pid]; ok || t.meta.Partition.GetStateByID(pid) < state {
+		return nil, nil
+	}
+	return t.reorgTable.partitions[pid], nil
+}
+
+// addReorgRecord writes the row into the other layout with the same handle.
+func (t *partitionedTable) addReorgRecord(ctx sessionctx.Context, h kv.Handle, r []types.Datum) error {
+	p, err := t.locateReorgPartition(ctx, r, model.StateWriteOnly)
+	if p == nil || err != nil {
+		return err
+	}
+	if !t.meta.PKIsHandle && !t.meta.IsCommonHandle && len(r) == len(t.Cols()) {
+		// The last value is _tidb_rowid.
+		r = append(r[:len(r):len(r)], types.NewIntDatum(h.IntValue()))
+	}
+	_, err = p.AddRecord(ctx, r)
+	return errors.Trace(err)
+}
+
+// removeReorgRecord removes the row from the other layout.
+func (t *partitionedTable) removeReorgRecord(ctx sessionctx.Context, h kv.Handle, r []types.Datum) error {
+	p, err := t.locateReorgPartition(ctx, r, model.StateDeleteOnly)
+	if p == nil || err != nil {
+		return err
+	}
+	return errors.Trace(p.RemoveRecord(ctx, h, r))
 }
 
 func (t *partitionedTable) GetAllPartitionIDs() []int64 {
@@ -1183,8 +1287,9 @@ func partitionedTableUpdateRecord(gctx context.Context, ctx sessionctx.Context,
 
 	// The old and new data locate in different partitions.
 	// Remove record from old partition and add record to new partition.
+	newHandle := h
 	if from != to {
-		_, err = t.GetPartition(to).AddRecord(ctx, newData)
+		newHandle, err = t.GetPartition(to).AddRecord(ctx, newData)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -1199,11 +1304,18 @@ func partitionedTableUpdateRecord(gctx context.Context, ctx sessionctx.Context,
 			logutil.BgLogger().Error("update partition record fails", zap.String("message", "new record inserted while old record is not removed"), zap.Error(err))
 			return errors.Trace(err)
 		}
-		return nil
+	} else {
+		tbl := t.GetPartition(to)
+		if err = tbl.UpdateRecord(gctx, ctx, h, currData, newData, touched); err != nil {
+			return errors.Trace(err)
+		}
 	}
 
-	tbl := t.GetPartition(to)
-	return tbl.UpdateRecord(gctx, ctx, h, currData, newData, touched)
+	// The row in the other layout is replaced, since it may have not been backfilled yet.
+	if err = t.removeReorgRecord(ctx, h, currData); err != nil {
+		return errors.Trace(err)
+	}
+	return t.addReorgRecord(ctx, newHandle, newData)
 }
 
 // FindPartitionByName finds partition in table meta by name.
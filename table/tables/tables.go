@@ -505,6 +505,24 @@ func FindPrimaryIndex(tblInfo *model.TableInfo) *model.IndexInfo {
 	return pkIdx
 }
 
+// GeneratedInvisiblePKName is the name of the invisible primary key column generated for
+// the tables created without a primary key.
+const GeneratedInvisiblePKName = "_tidb_gipk"
+
+// IsGeneratedInvisiblePK checks whether the column is the generated invisible primary key.
+func IsGeneratedInvisiblePK(col *model.ColumnInfo) bool {
+	return col.Hidden && col.Name.L == GeneratedInvisiblePKName && mysql.HasPriKeyFlag(col.Flag)
+}
+
+// IsHiddenToSession checks whether the column is hidden to the session. The generated invisible
+// primary key is visible if `tidb_show_generated_invisible_primary_key` is enabled.
+func IsHiddenToSession(vars *variable.SessionVars, col *model.ColumnInfo) bool {
+	if !col.Hidden {
+		return false
+	}
+	return !(vars.ShowGeneratedInvisiblePK && IsGeneratedInvisiblePK(col))
+}
+
 // CommonAddRecordCtx is used in `AddRecord` to avoid memory malloc for some temp slices.
 // This is useful in lightning parse row data to key-values pairs. This can gain upto 5%  performance
 // improvement in lightning's local mode.
@@ -734,7 +752,7 @@ func (t *TableCommon) AddRecord(sctx sessionctx.Context, r []types.Datum, opts .
 	var setPresume bool
 	skipCheck := sctx.GetSessionVars().StmtCtx.BatchCheck
 	if (t.meta.IsCommonHandle || t.meta.PKIsHandle) && !skipCheck && !opt.SkipHandleCheck {
-		if sctx.GetSessionVars().LazyCheckKeyNotExists() {
+		if lazyCheckKeyNotExists(sctx, t.meta.ID) {
 			var v []byte
 			v, err = txn.GetMemBuffer().Get(ctx, key)
 			if err != nil {
@@ -1386,9 +1404,20 @@ func shouldWriteBinlog(ctx sessionctx.Context, tblInfo *model.TableInfo) bool {
 	if ctx.GetSessionVars().BinlogClient == nil {
 		return false
 	}
+	// The data of local temporary tables is never committed, so it's not replicated.
+	if ctx.GetSessionVars().IsLocalTemporaryTable(tblInfo.ID) {
+		return false
+	}
 	return !ctx.GetSessionVars().InRestrictedSQL && !tblInfo.IsCommonHandle
 }
 
+// lazyCheckKeyNotExists returns whether the key existence can be checked lazily when committing or locking.
+// The keys of local temporary tables are never sent to the storage, so they are always checked immediately.
+func lazyCheckKeyNotExists(ctx sessionctx.Context, tableID int64) bool {
+	vars := ctx.GetSessionVars()
+	return vars.LazyCheckKeyNotExists() && !vars.IsLocalTemporaryTable(tableID)
+}
+
 func (t *TableCommon) getMutation(ctx sessionctx.Context) *binlog.TableMutation {
 	return ctx.StmtGetMutation(t.tableID)
 }
@@ -1584,7 +1613,34 @@ func (t *TableCommon) GetSequenceNextVal(ctx interface{}, dbName, seqName string
 	}
 	seq.mu.Lock()
 	defer seq.mu.Unlock()
+	return t.getSequenceNextVal(ctx, dbName, seqName, 1)
+}
 
+// GetSequenceNextVals implements util.SequenceTable GetSequenceNextVals interface.
+// It's used by the bulk insert, the values missed in the cache are allocated in one request.
+func (t *TableCommon) GetSequenceNextVals(ctx interface{}, dbName, seqName string, n int) ([]int64, error) {
+	seq := t.sequence
+	if seq == nil {
+		// TODO: refine the error.
+		return nil, errors.New("sequenceCommon is nil")
+	}
+	seq.mu.Lock()
+	defer seq.mu.Unlock()
+	vals := make([]int64, 0, n)
+	for len(vals) < n {
+		nextVal, err := t.getSequenceNextVal(ctx, dbName, seqName, int64(n-len(vals)))
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, nextVal)
+	}
+	return vals, nil
+}
+
+// getSequenceNextVal gets the next value, if the cache is empty, the new cache covers at least the needed values.
+// It's used under GetSequenceNextVal & GetSequenceNextVals, which mu is locked.
+func (t *TableCommon) getSequenceNextVal(ctx interface{}, dbName, seqName string, needed int64) (nextVal int64, err error) {
+	seq := t.sequence
 	err = func() error {
 		// Check if need to update the cache batch from storage.
 		// Because seq.base is not always the last allocated value (may be set by setval()).
@@ -1618,7 +1674,7 @@ func (t *TableCommon) GetSequenceNextVal(ctx interface{}, dbName, seqName string
 			return err1
 		}
 		var base, end, round int64
-		base, end, round, err1 = sequenceAlloc.AllocSeqCache(t.tableID)
+		base, end, round, err1 = sequenceAlloc.AllocSeqBatch(t.tableID, needed)
 		if err1 != nil {
 			return err1
 		}
@@ -0,0 +1,241 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package temptable
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// runChunkSize is the number of the key-value pairs in a chunk of the sorted runs.
+const runChunkSize = 1024
+
+// runFieldTypes are the field types of the chunks of the sorted runs, the chunks have a key column and
+// a value column.
+var runFieldTypes = []*types.FieldType{
+	types.NewFieldType(mysql.TypeVarString),
+	types.NewFieldType(mysql.TypeVarString),
+}
+
+// sortedRun is the key-value pairs spilled to the disk, which are sorted by the keys.
+type sortedRun struct {
+	list *chunk.ListInDisk
+	// firstKeys are the first keys of the chunks, they're used to locate the chunk of a key.
+	firstKeys [][]byte
+	// cachedChk is the last chunk read from the disk, which speeds up the consecutive point reads.
+	cachedChk    *chunk.Chunk
+	cachedChkIdx int
+}
+
+// writeRun writes the key-value pairs of the iterator into a new sortedRun, the deleted keys are dropped if
+// dropDeleted is true, the keys in [start, end) are always dropped. It returns nil if nothing is written.
+func writeRun(it kv.Iterator, dropDeleted bool, start, end kv.Key) (_ *sortedRun, err error) {
+	run := &sortedRun{list: chunk.NewListInDisk(runFieldTypes), cachedChkIdx: -1}
+	defer func() {
+		if err != nil {
+			run.close()
+		}
+	}()
+	chk := chunk.New(runFieldTypes, runChunkSize, runChunkSize)
+	for ; it.Valid(); err = it.Next() {
+		if err != nil {
+			return nil, err
+		}
+		if (dropDeleted && len(it.Value()) == 0) || inRange(it.Key(), start, end) {
+			continue
+		}
+		chk.AppendBytes(0, it.Key())
+		chk.AppendBytes(1, it.Value())
+		if chk.NumRows() == runChunkSize {
+			if err = run.add(chk); err != nil {
+				return nil, err
+			}
+			chk = chunk.New(runFieldTypes, runChunkSize, runChunkSize)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if chk.NumRows() > 0 {
+		if err = run.add(chk); err != nil {
+			return nil, err
+		}
+	}
+	if len(run.firstKeys) == 0 {
+		run.close()
+		return nil, nil
+	}
+	return run, nil
+}
+
+func (r *sortedRun) add(chk *chunk.Chunk) error {
+	r.firstKeys = append(r.firstKeys, append([]byte(nil), chk.GetRow(0).GetBytes(0)...))
+	return r.list.Add(chk)
+}
+
+func (r *sortedRun) getChunk(chkIdx int) (*chunk.Chunk, error) {
+	if chkIdx != r.cachedChkIdx {
+		chk, err := r.list.GetChunk(chkIdx)
+		if err != nil {
+			return nil, err
+		}
+		r.cachedChk, r.cachedChkIdx = chk, chkIdx
+	}
+	return r.cachedChk, nil
+}
+
+// chunkOf returns the index of the chunk which may contain the key, it's -1 if the key is less than all the keys.
+func (r *sortedRun) chunkOf(k []byte) int {
+	return sort.Search(len(r.firstKeys), func(i int) bool { return bytes.Compare(r.firstKeys[i], k) > 0 }) - 1
+}
+
+func (r *sortedRun) get(k []byte) ([]byte, bool, error) {
+	chkIdx := r.chunkOf(k)
+	if chkIdx < 0 {
+		return nil, false, nil
+	}
+	chk, err := r.getChunk(chkIdx)
+	if err != nil {
+		return nil, false, err
+	}
+	rowIdx := searchChunk(chk, k)
+	if rowIdx == chk.NumRows() || !bytes.Equal(chk.GetRow(rowIdx).GetBytes(0), k) {
+		return nil, false, nil
+	}
+	return chk.GetRow(rowIdx).GetBytes(1), true, nil
+}
+
+// searchChunk returns the index of the first row whose key is not less than k.
+func searchChunk(chk *chunk.Chunk, k []byte) int {
+	return sort.Search(chk.NumRows(), func(i int) bool { return bytes.Compare(chk.GetRow(i).GetBytes(0), k) >= 0 })
+}
+
+// iter creates an iterator positioned on the first key not less than k, or the last key less than k if reverse
+// is true. A nil k means the first key, or the last key if reverse is true.
+func (r *sortedRun) iter(k []byte, reverse bool) (*sortedRunIter, error) {
+	it := &sortedRunIter{run: r, reverse: reverse}
+	var chkIdx int
+	switch {
+	case !reverse && k != nil:
+		chkIdx = r.chunkOf(k)
+		if chkIdx < 0 {
+			chkIdx = 0
+		}
+	case reverse && k == nil:
+		chkIdx = len(r.firstKeys) - 1
+	case reverse:
+		// The chunk of the last key less than k.
+		chkIdx = sort.Search(len(r.firstKeys), func(i int) bool { return bytes.Compare(r.firstKeys[i], k) >= 0 }) - 1
+	}
+	if chkIdx < 0 || chkIdx >= len(r.firstKeys) {
+		it.chkIdx = -1
+		return it, nil
+	}
+	if err := it.load(chkIdx); err != nil {
+		return nil, err
+	}
+	switch {
+	case !reverse && k != nil:
+		it.rowIdx = searchChunk(it.chk, k)
+	case reverse && k == nil:
+		it.rowIdx = it.chk.NumRows() - 1
+	case reverse:
+		it.rowIdx = searchChunk(it.chk, k) - 1
+	}
+	return it, it.skipExhausted()
+}
+
+// sortedRunIter iterates the key-value pairs of a sortedRun.
+type sortedRunIter struct {
+	run     *sortedRun
+	reverse bool
+	chk     *chunk.Chunk
+	// chkIdx is -1 if the iterator is invalid.
+	chkIdx int
+	rowIdx int
+}
+
+func (it *sortedRunIter) load(chkIdx int) error {
+	chk, err := it.run.getChunk(chkIdx)
+	if err != nil {
+		return err
+	}
+	it.chk, it.chkIdx = chk, chkIdx
+	return nil
+}
+
+// skipExhausted moves the iterator to the next chunk if the current one is exhausted.
+func (it *sortedRunIter) skipExhausted() error {
+	if !it.reverse && it.rowIdx >= it.chk.NumRows() {
+		if it.chkIdx+1 >= len(it.run.firstKeys) {
+			it.chkIdx = -1
+			return nil
+		}
+		if err := it.load(it.chkIdx + 1); err != nil {
+			return err
+		}
+		it.rowIdx = 0
+	} else if it.reverse && it.rowIdx < 0 {
+		if it.chkIdx == 0 {
+			it.chkIdx = -1
+			return nil
+		}
+		if err := it.load(it.chkIdx - 1); err != nil {
+			return err
+		}
+		it.rowIdx = it.chk.NumRows() - 1
+	}
+	return nil
+}
+
+// Valid implements kv.Iterator interface.
+func (it *sortedRunIter) Valid() bool {
+	return it.chkIdx >= 0
+}
+
+// Key implements kv.Iterator interface.
+func (it *sortedRunIter) Key() kv.Key {
+	return it.chk.GetRow(it.rowIdx).GetBytes(0)
+}
+
+// Value implements kv.Iterator interface.
+func (it *sortedRunIter) Value() []byte {
+	return it.chk.GetRow(it.rowIdx).GetBytes(1)
+}
+
+// Next implements kv.Iterator interface.
+func (it *sortedRunIter) Next() error {
+	if it.reverse {
+		it.rowIdx--
+	} else {
+		it.rowIdx++
+	}
+	return it.skipExhausted()
+}
+
+// Close implements kv.Iterator interface.
+func (it *sortedRunIter) Close() {
+	it.chkIdx = -1
+}
+
+func (r *sortedRun) close() {
+	r.cachedChk = nil
+	terror.Log(r.list.Close())
+}
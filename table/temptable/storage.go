@@ -0,0 +1,338 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package temptable
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/store/tikv/unionstore"
+)
+
+// maxSpilledRuns is the max number of the spilled runs, they are merged into one when it's exceeded.
+const maxSpilledRuns = 4
+
+// Storage keeps the committed data of the local temporary tables of a session. The data is kept in a memory buffer
+// until its size exceeds the quota, then the buffer is spilled to the disk as a run sorted by the keys, so the large
+// temporary tables never exhaust the memory of tidb-server and never touch the storage.
+//
+// Like kv.MemBuffer, a deleted key is kept as an empty value until it's dropped by merging the spilled runs, so the
+// readers should skip the empty values. Storage is not thread safe.
+type Storage struct {
+	mem *unionstore.MemDB
+	// runs are the data spilled to the disk, from the oldest to the newest.
+	runs     []*sortedRun
+	memQuota int64
+}
+
+// NewStorage creates a Storage which keeps at most memQuota bytes in memory.
+func NewStorage(memQuota int64) *Storage {
+	return &Storage{
+		mem:      newMemDB(),
+		memQuota: memQuota,
+	}
+}
+
+func newMemDB() *unionstore.MemDB {
+	return unionstore.NewUnionStore(nil).GetMemBuffer()
+}
+
+// SetMemQuota sets the memory quota, the data is spilled on the next write if the quota is exceeded.
+func (s *Storage) SetMemQuota(memQuota int64) {
+	s.memQuota = memQuota
+}
+
+// MemUsage returns the bytes of the data kept in memory.
+func (s *Storage) MemUsage() int64 {
+	return int64(s.mem.Size())
+}
+
+// DiskUsage returns the bytes of the data spilled to the disk.
+func (s *Storage) DiskUsage() int64 {
+	var usage int64
+	for _, run := range s.runs {
+		usage += run.list.GetDiskTracker().BytesConsumed()
+	}
+	return usage
+}
+
+// Get gets the value of the key, it returns kv.ErrNotExist if the key is never written, and an empty value if
+// the key is deleted.
+func (s *Storage) Get(_ context.Context, k kv.Key) ([]byte, error) {
+	val, err := s.mem.Get(k)
+	if !kv.IsErrNotFound(err) {
+		return val, err
+	}
+	for i := len(s.runs) - 1; i >= 0; i-- {
+		val, ok, err := s.runs[i].get(k)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return val, nil
+		}
+	}
+	return nil, kv.ErrNotExist
+}
+
+// Iter creates an Iterator positioned on the first entry that k <= entry's key, it yields only the keys
+// less than upperBound. The Iterator must be closed after use.
+func (s *Storage) Iter(k kv.Key, upperBound kv.Key) (kv.Iterator, error) {
+	memIt, err := s.mem.Iter(k, upperBound)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	iters := make([]kv.Iterator, 0, len(s.runs)+1)
+	iters = append(iters, &memDBIter{Iterator: memIt})
+	for i := len(s.runs) - 1; i >= 0; i-- {
+		it, err := s.runs[i].iter(k, false)
+		if err != nil {
+			closeIters(iters)
+			return nil, err
+		}
+		iters = append(iters, it)
+	}
+	return newMergeIter(iters, upperBound, false), nil
+}
+
+// IterReverse creates a reversed Iterator positioned on the first entry which key is less than k.
+// The Iterator must be closed after use.
+func (s *Storage) IterReverse(k kv.Key) (kv.Iterator, error) {
+	memIt, err := s.mem.IterReverse(k)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	iters := make([]kv.Iterator, 0, len(s.runs)+1)
+	iters = append(iters, &memDBIter{Iterator: memIt})
+	for i := len(s.runs) - 1; i >= 0; i-- {
+		it, err := s.runs[i].iter(k, true)
+		if err != nil {
+			closeIters(iters)
+			return nil, err
+		}
+		iters = append(iters, it)
+	}
+	return newMergeIter(iters, nil, true), nil
+}
+
+// Set sets the value of the key, the value must not be empty.
+func (s *Storage) Set(k kv.Key, v []byte) error {
+	if len(v) == 0 {
+		return kv.ErrCannotSetNilValue
+	}
+	if err := s.mem.Set(k, v); err != nil {
+		return err
+	}
+	return s.spillIfExceeded()
+}
+
+// Delete deletes the key.
+func (s *Storage) Delete(k kv.Key) error {
+	if err := s.mem.Delete(k); err != nil {
+		return err
+	}
+	return s.spillIfExceeded()
+}
+
+// DeleteRange deletes the keys in [start, end). Unlike deleting the keys one by one, the spilled keys in
+// the range are dropped from the disk.
+func (s *Storage) DeleteRange(start, end kv.Key) error {
+	mem := newMemDB()
+	it, err := s.mem.Iter(nil, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for ; it.Valid(); err = it.Next() {
+		if err != nil {
+			it.Close()
+			return errors.Trace(err)
+		}
+		if inRange(it.Key(), start, end) {
+			continue
+		}
+		if len(it.Value()) == 0 {
+			err = mem.Delete(it.Key())
+		} else {
+			err = mem.Set(it.Key(), it.Value())
+		}
+		if err != nil {
+			it.Close()
+			return errors.Trace(err)
+		}
+	}
+	it.Close()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	s.mem = mem
+	if len(s.runs) > 0 {
+		return s.mergeRuns(start, end)
+	}
+	return nil
+}
+
+// Close removes the spilled data and releases the memory.
+func (s *Storage) Close() {
+	for _, run := range s.runs {
+		run.close()
+	}
+	s.runs = nil
+	s.mem = newMemDB()
+}
+
+func (s *Storage) spillIfExceeded() error {
+	if int64(s.mem.Size()) <= s.memQuota || s.mem.Len() == 0 {
+		return nil
+	}
+	it, err := s.mem.Iter(nil, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// The deleted keys only shadow the spilled ones, they're dropped if nothing has been spilled.
+	run, err := writeRun(&memDBIter{Iterator: it}, len(s.runs) == 0, nil, nil)
+	if err != nil {
+		return err
+	}
+	if run != nil {
+		s.runs = append(s.runs, run)
+	}
+	s.mem = newMemDB()
+	if len(s.runs) > maxSpilledRuns {
+		return s.mergeRuns(nil, nil)
+	}
+	return nil
+}
+
+// mergeRuns merges all the spilled runs into one, the deleted keys and the keys in [start, end) are dropped.
+func (s *Storage) mergeRuns(start, end kv.Key) error {
+	iters := make([]kv.Iterator, 0, len(s.runs))
+	for i := len(s.runs) - 1; i >= 0; i-- {
+		it, err := s.runs[i].iter(nil, false)
+		if err != nil {
+			closeIters(iters)
+			return err
+		}
+		iters = append(iters, it)
+	}
+	it := newMergeIter(iters, nil, false)
+	run, err := writeRun(it, true, start, end)
+	it.Close()
+	if err != nil {
+		return err
+	}
+	for _, r := range s.runs {
+		r.close()
+	}
+	s.runs = s.runs[:0]
+	if run != nil {
+		s.runs = append(s.runs, run)
+	}
+	return nil
+}
+
+func inRange(k, start, end []byte) bool {
+	return start != nil && bytes.Compare(k, start) >= 0 && (end == nil || bytes.Compare(k, end) < 0)
+}
+
+func closeIters(iters []kv.Iterator) {
+	for _, it := range iters {
+		it.Close()
+	}
+}
+
+// memDBIter wraps the unionstore.Iterator of the memory buffer as kv.Iterator.
+type memDBIter struct {
+	unionstore.Iterator
+}
+
+func (it *memDBIter) Key() kv.Key {
+	return it.Iterator.Key()
+}
+
+// mergeIter merges the iterators, when several of them have the same key, the first one wins.
+type mergeIter struct {
+	// iters are from the newest to the oldest.
+	iters      []kv.Iterator
+	upperBound kv.Key
+	reverse    bool
+	// cur is the index of the iterator of the current key, it's -1 if the mergeIter is invalid.
+	cur int
+}
+
+func newMergeIter(iters []kv.Iterator, upperBound kv.Key, reverse bool) *mergeIter {
+	it := &mergeIter{iters: iters, upperBound: upperBound, reverse: reverse}
+	it.locate()
+	return it
+}
+
+func (it *mergeIter) locate() {
+	it.cur = -1
+	for i, iter := range it.iters {
+		if !iter.Valid() {
+			continue
+		}
+		if it.cur < 0 {
+			it.cur = i
+			continue
+		}
+		cmp := bytes.Compare(iter.Key(), it.iters[it.cur].Key())
+		if (!it.reverse && cmp < 0) || (it.reverse && cmp > 0) {
+			it.cur = i
+		}
+	}
+	if it.cur >= 0 && it.upperBound != nil && bytes.Compare(it.iters[it.cur].Key(), it.upperBound) >= 0 {
+		it.cur = -1
+	}
+}
+
+// Valid implements kv.Iterator interface.
+func (it *mergeIter) Valid() bool {
+	return it.cur >= 0
+}
+
+// Key implements kv.Iterator interface.
+func (it *mergeIter) Key() kv.Key {
+	return it.iters[it.cur].Key()
+}
+
+// Value implements kv.Iterator interface.
+func (it *mergeIter) Value() []byte {
+	return it.iters[it.cur].Value()
+}
+
+// Next implements kv.Iterator interface.
+func (it *mergeIter) Next() error {
+	key := it.Key()
+	// The shadowed iterators are moved before the current one, which may overwrite the key.
+	for i, iter := range it.iters {
+		if i != it.cur && iter.Valid() && bytes.Equal(iter.Key(), key) {
+			if err := iter.Next(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := it.iters[it.cur].Next(); err != nil {
+		return err
+	}
+	it.locate()
+	return nil
+}
+
+// Close implements kv.Iterator interface.
+func (it *mergeIter) Close() {
+	closeIters(it.iters)
+	it.cur = -1
+}
@@ -0,0 +1,137 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package temptable
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/kv"
+)
+
+func TestT(t *testing.T) {
+	CustomVerboseFlag = true
+	TestingT(t)
+}
+
+var _ = Suite(&testStorageSuite{})
+
+type testStorageSuite struct{}
+
+func encodeKey(i int) kv.Key {
+	return kv.Key(fmt.Sprintf("k%06d", i))
+}
+
+// checkStorage checks the data of the storage is the same as the expected one.
+func checkStorage(c *C, s *Storage, expected map[string]string) {
+	keys := make([]string, 0, len(expected))
+	for k := range expected {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for k, v := range expected {
+		val, err := s.Get(context.Background(), kv.Key(k))
+		c.Assert(err, IsNil)
+		c.Assert(string(val), Equals, v)
+	}
+
+	iterKeys := func(it kv.Iterator, err error) []string {
+		c.Assert(err, IsNil)
+		defer it.Close()
+		result := make([]string, 0, len(keys))
+		for ; it.Valid(); err = it.Next() {
+			c.Assert(err, IsNil)
+			if len(it.Value()) == 0 {
+				continue
+			}
+			c.Assert(string(it.Value()), Equals, expected[string(it.Key())])
+			result = append(result, string(it.Key()))
+		}
+		c.Assert(err, IsNil)
+		return result
+	}
+	c.Assert(iterKeys(s.Iter(nil, nil)), DeepEquals, keys)
+	if len(keys) > 2 {
+		lower, upper := keys[len(keys)/3], keys[len(keys)*2/3]
+		c.Assert(iterKeys(s.Iter(kv.Key(lower), kv.Key(upper))), DeepEquals, keys[len(keys)/3:len(keys)*2/3])
+		reversed := iterKeys(s.IterReverse(kv.Key(upper)))
+		c.Assert(len(reversed), Equals, len(keys)*2/3)
+		for i, k := range reversed {
+			c.Assert(k, Equals, keys[len(keys)*2/3-1-i])
+		}
+	}
+}
+
+func (ts *testStorageSuite) TestSpill(c *C) {
+	s := NewStorage(4096)
+	defer s.Close()
+	expected := make(map[string]string)
+	for i := 0; i < 5000; i++ {
+		k := encodeKey(rand.Intn(2000))
+		if rand.Intn(4) == 0 {
+			c.Assert(s.Delete(k), IsNil)
+			delete(expected, string(k))
+			continue
+		}
+		v := fmt.Sprintf("v%d", i)
+		c.Assert(s.Set(k, []byte(v)), IsNil)
+		expected[string(k)] = v
+	}
+	c.Assert(s.DiskUsage(), Greater, int64(0))
+	c.Assert(len(s.runs), LessEqual, maxSpilledRuns)
+	c.Assert(s.MemUsage(), LessEqual, int64(4096))
+	checkStorage(c, s, expected)
+
+	_, err := s.Get(context.Background(), encodeKey(2000))
+	c.Assert(kv.IsErrNotFound(err), IsTrue)
+
+	// Delete the keys in a range, which drops the spilled keys too.
+	c.Assert(s.DeleteRange(encodeKey(500), encodeKey(1500)), IsNil)
+	for k := range expected {
+		if k >= string(encodeKey(500)) && k < string(encodeKey(1500)) {
+			delete(expected, k)
+		}
+	}
+	c.Assert(len(s.runs), LessEqual, 1)
+	checkStorage(c, s, expected)
+
+	s.Close()
+	c.Assert(s.DiskUsage(), Equals, int64(0))
+	checkStorage(c, s, map[string]string{})
+}
+
+func (ts *testStorageSuite) TestSetMemQuota(c *C) {
+	s := NewStorage(1 << 20)
+	defer s.Close()
+	expected := make(map[string]string)
+	for i := 0; i < 100; i++ {
+		c.Assert(s.Set(encodeKey(i), []byte("v")), IsNil)
+		expected[string(encodeKey(i))] = "v"
+	}
+	c.Assert(s.DiskUsage(), Equals, int64(0))
+
+	// The data is spilled on the next write after the quota is decreased.
+	s.SetMemQuota(1)
+	c.Assert(s.Delete(encodeKey(0)), IsNil)
+	delete(expected, string(encodeKey(0)))
+	c.Assert(s.DiskUsage(), Greater, int64(0))
+	c.Assert(s.MemUsage(), Equals, int64(0))
+	checkStorage(c, s, expected)
+	c.Assert(s.Set(encodeKey(0), nil), NotNil)
+}
@@ -43,6 +43,7 @@ import (
 	"github.com/pingcap/tidb/plugin"
 	"github.com/pingcap/tidb/privilege/privileges"
 	"github.com/pingcap/tidb/server"
+	"github.com/pingcap/tidb/server/audit"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx/binloginfo"
 	"github.com/pingcap/tidb/sessionctx/variable"
@@ -146,7 +147,7 @@ var (
 	metricsInterval = flag.Uint(nmMetricsInterval, 15, "prometheus client push interval in second, set \"0\" to disable prometheus push.")
 
 	// PROXY Protocol
-	proxyProtocolNetworks      = flag.String(nmProxyProtocolNetworks, "", "proxy protocol networks allowed IP or *, empty mean disable proxy protocol support")
+	proxyProtocolNetworks      = flag.String(nmProxyProtocolNetworks, "", "proxy protocol networks allowed IP, CIDR or *, empty mean disable proxy protocol support")
 	proxyProtocolHeaderTimeout = flag.Uint(nmProxyProtocolHeaderTimeout, 5, "proxy protocol header read timeout, unit is second.")
 )
 
@@ -537,6 +538,7 @@ func setGlobalVars() {
 	variable.SetSysVar(variable.TiDBSlowQueryFile, cfg.Log.SlowQueryFile)
 	variable.SetSysVar(variable.TiDBIsolationReadEngines, strings.Join(cfg.IsolationRead.Engines, ", "))
 	variable.MemoryUsageAlarmRatio.Store(cfg.Performance.MemoryUsageAlarmRatio)
+	variable.SlowLogSampleRate.Store(cfg.Log.SlowLogSampleRate)
 
 	if cfg.Security.EnableSEM {
 		sem.Enable()
@@ -607,6 +609,11 @@ func printInfo() {
 
 func createServer(storage kv.Storage, dom *domain.Domain) *server.Server {
 	cfg := config.GetGlobalConfig()
+	// The audit log is set up after the plugins are loaded, so the sinks registered by the plugins can be used.
+	if err := audit.Setup(&cfg.Audit); err != nil {
+		closeDomainAndStorage(storage, dom)
+		log.Fatal("failed to set up the audit log", zap.Error(err))
+	}
 	driver := server.NewTiDBDriver(storage)
 	svr, err := server.NewServer(cfg, driver)
 	// Both domain and storage have started, so we have to clean them before exiting.
@@ -667,6 +674,7 @@ func cleanup(svr *server.Server, storage kv.Storage, dom *domain.Domain, gracefu
 		svr.TryGracefulDown()
 	}
 	plugin.Shutdown(context.Background())
+	audit.Close()
 	closeDomainAndStorage(storage, dom)
 	disk.CleanUp()
 }
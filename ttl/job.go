@@ -0,0 +1,390 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ttl
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/timeutil"
+	"go.uber.org/zap"
+)
+
+// jobParams are the global parameters of the TTL jobs.
+type jobParams struct {
+	enable          bool
+	interval        time.Duration
+	deleteBatchSize int
+	deleteRateLimit int64
+}
+
+// JobManager runs the TTL jobs, which delete the expired rows of the TTL tables. It should only run on the TTL owner.
+type JobManager struct {
+	sctx sessionctx.Context
+}
+
+// NewJobManager creates a JobManager running the TTL jobs in the session.
+func NewJobManager(sctx sessionctx.Context) *JobManager {
+	return &JobManager{sctx: sctx}
+}
+
+// RunJobs runs the TTL jobs of the tables whose jobs are due and whose job windows contain the current time.
+// A job interrupted by the end of its job window or by ctx continues in the next run.
+func (m *JobManager) RunJobs(ctx context.Context, is infoschema.InfoSchema) error {
+	params, err := m.loadJobParams(ctx)
+	if err != nil || !params.enable {
+		return err
+	}
+	statuses, err := loadTableStatuses(ctx, m.sctx, "")
+	if err != nil {
+		return err
+	}
+	statusMap := make(map[int64]*TableStatus, len(statuses))
+	for _, status := range statuses {
+		statusMap[status.TableID] = status
+	}
+	for _, db := range is.AllSchemas() {
+		for _, tbl := range is.SchemaTables(db.Name) {
+			tblInfo := tbl.Meta()
+			if tblInfo.TTLInfo == nil {
+				continue
+			}
+			status, ok := statusMap[tblInfo.ID]
+			delete(statusMap, tblInfo.ID)
+			if !ok {
+				status = &TableStatus{TableID: tblInfo.ID}
+			}
+			if !tblInfo.TTLInfo.Enable || !m.isJobDue(tblInfo.TTLInfo, status, params) {
+				continue
+			}
+			if err = m.runJob(ctx, db.Name, tblInfo, status); err != nil {
+				logutil.BgLogger().Warn("[ttl] TTL job failed", zap.String("table", db.Name.O+"."+tblInfo.Name.O), zap.Error(err))
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+	}
+	// The left statuses belong to the tables which are dropped, truncated or not TTL tables anymore.
+	for tableID := range statusMap {
+		if err = removeTableStatus(ctx, m.sctx, tableID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *JobManager) loadJobParams(ctx context.Context) (*jobParams, error) {
+	exec := m.sctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(ctx, "SELECT variable_name, variable_value FROM %n.%n WHERE variable_name IN (%?, %?, %?, %?)",
+		mysql.SystemDB, mysql.GlobalVariablesTable, variable.TiDBTTLJobEnable, variable.TiDBTTLJobInterval,
+		variable.TiDBTTLDeleteBatchSize, variable.TiDBTTLDeleteRateLimit)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	params := &jobParams{
+		enable:          variable.DefTiDBTTLJobEnable,
+		deleteBatchSize: variable.DefTiDBTTLDeleteBatchSize,
+		deleteRateLimit: variable.DefTiDBTTLDeleteRateLimit,
+	}
+	params.interval, _ = time.ParseDuration(variable.DefTiDBTTLJobInterval)
+	for _, row := range rows {
+		value := row.GetString(1)
+		switch row.GetString(0) {
+		case variable.TiDBTTLJobEnable:
+			params.enable = variable.TiDBOptOn(value)
+		case variable.TiDBTTLJobInterval:
+			if d, err := time.ParseDuration(value); err == nil {
+				params.interval = d
+			}
+		case variable.TiDBTTLDeleteBatchSize:
+			if n, err := strconv.Atoi(value); err == nil && n > 0 {
+				params.deleteBatchSize = n
+			}
+		case variable.TiDBTTLDeleteRateLimit:
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil && n >= 0 {
+				params.deleteRateLimit = n
+			}
+		}
+	}
+	return params, nil
+}
+
+// isJobDue checks whether the TTL job of the table should run now. An unfinished job is always due
+// in its job window, a new job is due when the job interval has passed since the start of the last one.
+func (m *JobManager) isJobDue(info *model.TTLInfo, status *TableStatus, params *jobParams) bool {
+	now := time.Now()
+	if !m.inJobWindow(info, now) {
+		return false
+	}
+	if status.LastJobStatus == JobStatusRunning || status.LastJobStartTime == "" {
+		return true
+	}
+	lastStart, err := time.ParseInLocation(types.TimeFormat, status.LastJobStartTime, m.sctx.GetSessionVars().Location())
+	if err != nil {
+		return true
+	}
+	return now.Sub(lastStart) >= params.interval
+}
+
+func (m *JobManager) inJobWindow(info *model.TTLInfo, now time.Time) bool {
+	start, err := time.ParseInLocation(variable.FullDayTimeFormat, info.JobWindowStart, time.UTC)
+	if err != nil {
+		return false
+	}
+	end, err := time.ParseInLocation(variable.FullDayTimeFormat, info.JobWindowEnd, time.UTC)
+	if err != nil {
+		return false
+	}
+	return timeutil.WithinDayTimePeriod(start, end, now)
+}
+
+// runJob deletes the expired rows of a table in batches. Each batch scans the next range of the
+// expired rows in the handle order and deletes them in a transaction, the progress is saved after
+// each batch.
+func (m *JobManager) runJob(ctx context.Context, dbName model.CIStr, tblInfo *model.TableInfo, status *TableStatus) (err error) {
+	deletedRows := status.LastJobDeletedRows
+	if status.LastJobStatus != JobStatusRunning {
+		deletedRows = 0
+		_, err = execSQL(ctx, m.sctx, `INSERT INTO %n.%n (table_id, last_job_start_time, last_job_status) VALUES (%?, NOW(), %?)
+			ON DUPLICATE KEY UPDATE last_job_start_time = NOW(), last_job_finish_time = NULL,
+			last_job_deleted_rows = 0, last_job_status = VALUES(last_job_status), last_job_error = NULL`,
+			mysql.SystemDB, TableName, tblInfo.ID, JobStatusRunning)
+		if err != nil {
+			return err
+		}
+	}
+	logutil.BgLogger().Info("[ttl] run TTL job", zap.String("table", dbName.O+"."+tblInfo.Name.O), zap.Int64("deletedRows", deletedRows))
+	defer func() {
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		metrics.TTLJobCounter.WithLabelValues(metrics.RetLabel(err)).Inc()
+		_, updateErr := execSQL(context.Background(), m.sctx, `UPDATE %n.%n SET last_job_finish_time = NOW(),
+			last_job_status = %?, last_job_error = %? WHERE table_id = %?`,
+			mysql.SystemDB, TableName, JobStatusFailed, err.Error(), tblInfo.ID)
+		if updateErr != nil {
+			logutil.BgLogger().Warn("[ttl] update TTL job status failed", zap.Error(updateErr))
+		}
+	}()
+
+	info := tblInfo.TTLInfo
+	scanner := newRangeScanner(dbName, tblInfo, info.ColumnName.O, ExpireTime(info, time.Now().In(m.sctx.GetSessionVars().Location())))
+	for {
+		// The job parameters may change while the job is running.
+		params, err := m.loadJobParams(ctx)
+		if err != nil {
+			return err
+		}
+		if !params.enable || !m.inJobWindow(info, time.Now()) || ctx.Err() != nil {
+			// Continue the job in the next job window.
+			return nil
+		}
+		start := time.Now()
+		handles, err := scanner.scan(ctx, m.sctx, params.deleteBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(handles) > 0 {
+			affectedRows, err := execSQL(ctx, m.sctx, scanner.deleteSQL(handles))
+			if err != nil {
+				return err
+			}
+			metrics.TTLDeleteDuration.Observe(time.Since(start).Seconds())
+			metrics.TTLDeletedRowsCounter.Add(float64(affectedRows))
+			deletedRows += int64(affectedRows)
+			_, err = execSQL(ctx, m.sctx, "UPDATE %n.%n SET last_job_deleted_rows = %? WHERE table_id = %?",
+				mysql.SystemDB, TableName, deletedRows, tblInfo.ID)
+			if err != nil {
+				return err
+			}
+		}
+		if len(handles) < params.deleteBatchSize {
+			break
+		}
+		if params.deleteRateLimit > 0 {
+			wait := time.Duration(float64(len(handles))/float64(params.deleteRateLimit)*float64(time.Second)) - time.Since(start)
+			if wait > 0 {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(wait):
+				}
+			}
+		}
+	}
+
+	metrics.TTLJobCounter.WithLabelValues(metrics.RetLabel(nil)).Inc()
+	_, err = execSQL(ctx, m.sctx, "UPDATE %n.%n SET last_job_finish_time = NOW(), last_job_status = %? WHERE table_id = %?",
+		mysql.SystemDB, TableName, JobStatusFinished, tblInfo.ID)
+	logutil.BgLogger().Info("[ttl] TTL job finished", zap.String("table", dbName.O+"."+tblInfo.Name.O), zap.Int64("deletedRows", deletedRows))
+	return err
+}
+
+// rangeScanner scans the expired rows of a table in the handle order.
+type rangeScanner struct {
+	dbName     model.CIStr
+	tblName    model.CIStr
+	timeColumn string
+	expireTime time.Time
+	handleCols []string
+	// lastHandle is the handle of the last scanned row.
+	lastHandle []interface{}
+}
+
+func newRangeScanner(dbName model.CIStr, tblInfo *model.TableInfo, timeColumn string, expireTime time.Time) *rangeScanner {
+	var handleCols []string
+	switch {
+	case tblInfo.PKIsHandle:
+		handleCols = []string{tblInfo.GetPkColInfo().Name.O}
+	case tblInfo.IsCommonHandle:
+		for _, col := range tables.FindPrimaryIndex(tblInfo).Columns {
+			handleCols = append(handleCols, col.Name.O)
+		}
+	default:
+		handleCols = []string{model.ExtraHandleName.O}
+	}
+	return &rangeScanner{
+		dbName:     dbName,
+		tblName:    tblInfo.Name,
+		timeColumn: timeColumn,
+		expireTime: expireTime,
+		handleCols: handleCols,
+	}
+}
+
+// scan returns the handles of the next batch of expired rows.
+func (s *rangeScanner) scan(ctx context.Context, sctx sessionctx.Context, batchSize int) ([][]interface{}, error) {
+	var sql strings.Builder
+	sql.WriteString("SELECT ")
+	s.writeColList(&sql)
+	sqlexec.MustFormatSQL(&sql, " FROM %n.%n WHERE %n < %?", s.dbName.O, s.tblName.O, s.timeColumn, s.expireTime)
+	if s.lastHandle != nil {
+		sql.WriteString(" AND ")
+		s.writeHandleCols(&sql)
+		sql.WriteString(" > ")
+		s.writeHandle(&sql, s.lastHandle)
+	}
+	sql.WriteString(" ORDER BY ")
+	s.writeColList(&sql)
+	sqlexec.MustFormatSQL(&sql, " LIMIT %?", batchSize)
+
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(ctx, sql.String())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rows, fields, err := exec.ExecRestrictedStmt(ctx, stmt)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	handles := make([][]interface{}, 0, len(rows))
+	for _, row := range rows {
+		handle := make([]interface{}, len(fields))
+		for i, field := range fields {
+			handle[i], err = datumToArg(row.GetDatum(i, &field.Column.FieldType))
+			if err != nil {
+				return nil, err
+			}
+		}
+		handles = append(handles, handle)
+	}
+	if len(handles) > 0 {
+		s.lastHandle = handles[len(handles)-1]
+	}
+	return handles, nil
+}
+
+// deleteSQL returns the statement deleting the rows of the handles which are still expired.
+func (s *rangeScanner) deleteSQL(handles [][]interface{}) string {
+	var sql strings.Builder
+	sqlexec.MustFormatSQL(&sql, "DELETE FROM %n.%n WHERE %n < %? AND ", s.dbName.O, s.tblName.O, s.timeColumn, s.expireTime)
+	s.writeHandleCols(&sql)
+	sql.WriteString(" IN (")
+	for i, handle := range handles {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		s.writeHandle(&sql, handle)
+	}
+	sql.WriteString(")")
+	return sql.String()
+}
+
+// writeHandleCols writes the handle columns as a row constructor if there are more than one.
+func (s *rangeScanner) writeHandleCols(sql *strings.Builder) {
+	if len(s.handleCols) > 1 {
+		sql.WriteString("(")
+	}
+	s.writeColList(sql)
+	if len(s.handleCols) > 1 {
+		sql.WriteString(")")
+	}
+}
+
+func (s *rangeScanner) writeColList(sql *strings.Builder) {
+	for i, col := range s.handleCols {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		sqlexec.MustFormatSQL(sql, "%n", col)
+	}
+}
+
+func (s *rangeScanner) writeHandle(sql *strings.Builder, handle []interface{}) {
+	if len(handle) > 1 {
+		sql.WriteString("(")
+	}
+	for i, val := range handle {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		sqlexec.MustFormatSQL(sql, "%?", val)
+	}
+	if len(handle) > 1 {
+		sql.WriteString(")")
+	}
+}
+
+// datumToArg converts a handle value to an argument of sqlexec.MustFormatSQL. Integers are kept
+// to compare them exactly, other values are compared as strings.
+func datumToArg(d types.Datum) (interface{}, error) {
+	switch d.Kind() {
+	case types.KindInt64:
+		return d.GetInt64(), nil
+	case types.KindUint64:
+		return d.GetUint64(), nil
+	case types.KindBytes:
+		return d.GetBytes(), nil
+	default:
+		return d.ToString()
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ttl
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+const (
+	// OwnerKey is the TTL owner path that is saved to etcd.
+	OwnerKey = "/tidb/ttl/owner"
+	// Prompt is the prompt for TTL owner manager.
+	Prompt = "ttl"
+	// CheckInterval is the interval of checking whether the TTL jobs should run.
+	CheckInterval = time.Minute
+
+	// TableName is the name of the system table storing the status of the TTL jobs.
+	TableName = "tidb_ttl_table"
+)
+
+// The status of the last TTL job of a table.
+const (
+	JobStatusRunning  = "running"
+	JobStatusFinished = "finished"
+	JobStatusFailed   = "failed"
+)
+
+const (
+	defJobWindowStart  = "00:00 +0000"
+	defJobWindowEnd    = "23:59 +0000"
+	localDayTimeFormat = "15:04"
+)
+
+// The supported units of the TTL interval.
+const (
+	UnitSecond = "SECOND"
+	UnitMinute = "MINUTE"
+	UnitHour   = "HOUR"
+	UnitDay    = "DAY"
+	UnitWeek   = "WEEK"
+	UnitMonth  = "MONTH"
+	UnitYear   = "YEAR"
+)
+
+// CheckTTLInfo checks the TTL attributes of the table against its columns and normalizes them.
+func CheckTTLInfo(tblInfo *model.TableInfo) error {
+	info := tblInfo.TTLInfo
+	if info == nil {
+		return nil
+	}
+	col := model.FindColumnInfo(tblInfo.Columns, info.ColumnName.L)
+	if col == nil {
+		return errors.Errorf("unknown column '%s' in table '%s'", info.ColumnName.O, tblInfo.Name.O)
+	}
+	if err := CheckTTLColumn(col); err != nil {
+		return err
+	}
+	info.ColumnName = col.Name
+	if info.IntervalValue <= 0 {
+		return errors.Errorf("the TTL interval must be positive, got %d", info.IntervalValue)
+	}
+	info.IntervalUnit = strings.ToUpper(info.IntervalUnit)
+	switch info.IntervalUnit {
+	case UnitSecond, UnitMinute, UnitHour, UnitDay, UnitWeek, UnitMonth, UnitYear:
+	default:
+		return errors.Errorf("unsupported TTL interval unit '%s'", info.IntervalUnit)
+	}
+	var err error
+	if info.JobWindowStart, err = normalizeDayTime(info.JobWindowStart, defJobWindowStart); err != nil {
+		return err
+	}
+	info.JobWindowEnd, err = normalizeDayTime(info.JobWindowEnd, defJobWindowEnd)
+	return err
+}
+
+// CheckTTLColumn checks whether the column can be the TTL column.
+func CheckTTLColumn(col *model.ColumnInfo) error {
+	switch col.Tp {
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		return nil
+	default:
+		return errors.Errorf("the TTL column '%s' must be of DATE, DATETIME or TIMESTAMP type", col.Name.O)
+	}
+}
+
+// normalizeDayTime converts a time of day in the format of `15:04` in the local time zone or
+// `15:04 -0700` to the latter.
+func normalizeDayTime(value, defValue string) (string, error) {
+	if value == "" {
+		return defValue, nil
+	}
+	format := variable.FullDayTimeFormat
+	if len(value) <= len(localDayTimeFormat) {
+		format = localDayTimeFormat
+	}
+	t, err := time.ParseInLocation(format, value, time.Local)
+	if err != nil {
+		return "", errors.Errorf("invalid TTL job window time '%s'", value)
+	}
+	return t.Format(variable.FullDayTimeFormat), nil
+}
+
+// ExpireTime returns the time before which the rows of the TTL table expire.
+func ExpireTime(info *model.TTLInfo, now time.Time) time.Time {
+	n := int(info.IntervalValue)
+	switch info.IntervalUnit {
+	case UnitSecond:
+		return now.Add(-time.Duration(n) * time.Second)
+	case UnitMinute:
+		return now.Add(-time.Duration(n) * time.Minute)
+	case UnitHour:
+		return now.Add(-time.Duration(n) * time.Hour)
+	case UnitWeek:
+		return now.AddDate(0, 0, -7*n)
+	case UnitMonth:
+		return now.AddDate(0, -n, 0)
+	case UnitYear:
+		return now.AddDate(-n, 0, 0)
+	default:
+		return now.AddDate(0, 0, -n)
+	}
+}
+
+// TableStatus is the status of the last TTL job of a table.
+type TableStatus struct {
+	TableID            int64  `json:"table_id"`
+	LastJobStartTime   string `json:"last_job_start_time"`
+	LastJobFinishTime  string `json:"last_job_finish_time"`
+	LastJobDeletedRows int64  `json:"last_job_deleted_rows"`
+	LastJobStatus      string `json:"last_job_status"`
+	LastJobError       string `json:"last_job_error"`
+}
+
+// removeTableStatus removes the job status of a table which isn't a TTL table anymore.
+func removeTableStatus(ctx context.Context, sctx sessionctx.Context, tableID int64) error {
+	_, err := execSQL(ctx, sctx, "DELETE FROM %n.%n WHERE table_id = %?", mysql.SystemDB, TableName, tableID)
+	return err
+}
+
+// LoadTableStatus loads the status of the last TTL job of a table. It returns nil if no job of the table has run.
+func LoadTableStatus(sctx sessionctx.Context, tableID int64) (*TableStatus, error) {
+	statuses, err := loadTableStatuses(context.Background(), sctx, "WHERE table_id = %?", tableID)
+	if err != nil || len(statuses) == 0 {
+		return nil, err
+	}
+	return statuses[0], nil
+}
+
+func loadTableStatuses(ctx context.Context, sctx sessionctx.Context, where string, args ...interface{}) ([]*TableStatus, error) {
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	sql := `SELECT table_id, last_job_start_time, last_job_finish_time, last_job_deleted_rows, last_job_status, last_job_error
+		FROM %n.%n ` + where
+	stmt, err := exec.ParseWithParams(ctx, sql, append([]interface{}{mysql.SystemDB, TableName}, args...)...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	statuses := make([]*TableStatus, 0, len(rows))
+	for _, row := range rows {
+		status := &TableStatus{
+			TableID:            row.GetInt64(0),
+			LastJobDeletedRows: row.GetInt64(3),
+			LastJobStatus:      row.GetString(4),
+		}
+		if !row.IsNull(1) {
+			status.LastJobStartTime = row.GetTime(1).String()
+		}
+		if !row.IsNull(2) {
+			status.LastJobFinishTime = row.GetTime(2).String()
+		}
+		if !row.IsNull(5) {
+			status.LastJobError = row.GetString(5)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// execSQL executes a statement in the session and returns the number of affected rows.
+func execSQL(ctx context.Context, sctx sessionctx.Context, sql string, args ...interface{}) (uint64, error) {
+	_, err := sctx.(sqlexec.SQLExecutor).ExecuteInternal(ctx, sql, args...)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return sctx.GetSessionVars().StmtCtx.AffectedRows(), nil
+}
@@ -0,0 +1,248 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ttl_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/ttl"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testTTLSuite{})
+
+type testTTLSuite struct {
+	store kv.Storage
+	dom   *domain.Domain
+	se    session.Session
+}
+
+func (s *testTTLSuite) SetUpSuite(c *C) {
+	store, err := mockstore.NewMockStore()
+	c.Assert(err, IsNil)
+	s.store = store
+	session.SetSchemaLease(0)
+	s.dom, err = session.BootstrapSession(s.store)
+	c.Assert(err, IsNil)
+	s.se, err = session.CreateSession4Test(s.store)
+	c.Assert(err, IsNil)
+}
+
+func (s *testTTLSuite) TearDownSuite(c *C) {
+	s.se.Close()
+	s.dom.Close()
+	s.store.Close()
+}
+
+func (s *testTTLSuite) tableInfo(c *C, db, table string) *model.TableInfo {
+	tbl, err := s.dom.InfoSchema().TableByName(model.NewCIStr(db), model.NewCIStr(table))
+	c.Assert(err, IsNil)
+	return tbl.Meta()
+}
+
+func (s *testTTLSuite) TestTTLOptions(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists ttl_options")
+	tk.MustExec("create table ttl_options (id int primary key, name varchar(10), Created_At datetime) ttl = created_at + interval 1 day")
+	info := s.tableInfo(c, "test", "ttl_options").TTLInfo
+	c.Assert(info, NotNil)
+	c.Assert(info.ColumnName.O, Equals, "Created_At")
+	c.Assert(info.IntervalValue, Equals, int64(1))
+	c.Assert(info.IntervalUnit, Equals, ttl.UnitDay)
+	c.Assert(info.JobWindowStart, Equals, "00:00 +0000")
+	c.Assert(info.JobWindowEnd, Equals, "23:59 +0000")
+	c.Assert(info.Enable, IsTrue)
+	tk.MustQuery("show create table ttl_options").Check(testkit.Rows("ttl_options CREATE TABLE `ttl_options` (\n" +
+		"  `id` int(11) NOT NULL,\n" +
+		"  `name` varchar(10) DEFAULT NULL,\n" +
+		"  `Created_At` datetime DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`) /*T![clustered_index] CLUSTERED */\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin" +
+		" /*T![ttl] TTL=`Created_At` + INTERVAL 1 DAY */ /*T![ttl] TTL_ENABLE='ON' */" +
+		" /*T![ttl] TTL_JOB_WINDOW_START='00:00 +0000' TTL_JOB_WINDOW_END='23:59 +0000' */"))
+
+	tk.MustExec("alter table ttl_options ttl_enable = 'off' ttl_job_window_start = '01:00 +0800' ttl_job_window_end = '05:30 +0800'")
+	info = s.tableInfo(c, "test", "ttl_options").TTLInfo
+	c.Assert(info.ColumnName.O, Equals, "Created_At")
+	c.Assert(info.IntervalUnit, Equals, ttl.UnitDay)
+	c.Assert(info.JobWindowStart, Equals, "01:00 +0800")
+	c.Assert(info.JobWindowEnd, Equals, "05:30 +0800")
+	c.Assert(info.Enable, IsFalse)
+	tk.MustExec("alter table ttl_options ttl = created_at + interval 3 hour")
+	info = s.tableInfo(c, "test", "ttl_options").TTLInfo
+	c.Assert(info.IntervalValue, Equals, int64(3))
+	c.Assert(info.IntervalUnit, Equals, ttl.UnitHour)
+	c.Assert(info.JobWindowStart, Equals, "01:00 +0800")
+
+	// The TTL column can't be dropped or renamed, and must be a time column.
+	tk.MustGetErrMsg("alter table ttl_options drop column created_at", "[ddl:8200]Unsupported drop the TTL column 'created_at'")
+	tk.MustGetErrMsg("alter table ttl_options rename column created_at to c", "[ddl:8200]Unsupported rename the TTL column 'created_at'")
+	tk.MustGetErrMsg("alter table ttl_options change created_at c datetime", "[ddl:8200]Unsupported rename the TTL column 'Created_At'")
+	tk.MustExec("set @@tidb_enable_change_column_type = 1")
+	tk.MustGetErrMsg("alter table ttl_options modify created_at varchar(30)", "the TTL column 'created_at' must be of DATE, DATETIME or TIMESTAMP type")
+	tk.MustExec("alter table ttl_options modify created_at timestamp")
+
+	cases := []struct {
+		sql string
+		err string
+	}{
+		{"ttl = c + interval 1 day", "unknown column 'c' in table 'ttl_options'"},
+		{"ttl = name + interval 1 day", ".*must be of DATE, DATETIME or TIMESTAMP type"},
+		{"ttl = created_at + interval 0 day", "the TTL interval must be positive, got 0"},
+		{"ttl = created_at + interval 1 quarter", "unsupported TTL interval unit 'QUARTER'"},
+		{"ttl_job_window_start = '25:00'", "invalid TTL job window time '25:00'"},
+	}
+	for _, ca := range cases {
+		_, err := tk.Exec("alter table ttl_options " + ca.sql)
+		c.Assert(err, ErrorMatches, ca.err, Commentf(ca.sql))
+	}
+	tk.MustGetErrMsg("create table ttl_no_ttl (id int) ttl_enable = 'on'", "the TTL option is required to set the other TTL options")
+
+	tk.MustExec("alter table ttl_options remove ttl")
+	c.Assert(s.tableInfo(c, "test", "ttl_options").TTLInfo, IsNil)
+	tk.MustGetErrMsg("alter table ttl_options ttl_enable = 'on'", "the TTL option is required to set the other TTL options")
+	tk.MustExec("alter table ttl_options drop column created_at")
+}
+
+func (s *testTTLSuite) TestExpireTime(c *C) {
+	now := time.Date(2021, 3, 31, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		interval int64
+		unit     string
+		expected time.Time
+	}{
+		{30, ttl.UnitSecond, time.Date(2021, 3, 31, 11, 59, 30, 0, time.UTC)},
+		{30, ttl.UnitMinute, time.Date(2021, 3, 31, 11, 30, 0, 0, time.UTC)},
+		{13, ttl.UnitHour, time.Date(2021, 3, 30, 23, 0, 0, 0, time.UTC)},
+		{2, ttl.UnitDay, time.Date(2021, 3, 29, 12, 0, 0, 0, time.UTC)},
+		{1, ttl.UnitWeek, time.Date(2021, 3, 24, 12, 0, 0, 0, time.UTC)},
+		{1, ttl.UnitMonth, time.Date(2021, 3, 3, 12, 0, 0, 0, time.UTC)},
+		{1, ttl.UnitYear, time.Date(2020, 3, 31, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, ca := range cases {
+		info := &model.TTLInfo{IntervalValue: ca.interval, IntervalUnit: ca.unit}
+		c.Assert(ttl.ExpireTime(info, now), DeepEquals, ca.expected, Commentf("%d %s", ca.interval, ca.unit))
+	}
+}
+
+func (s *testTTLSuite) TestRunJobs(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists ttl_int_pk, ttl_row_id, ttl_common_pk")
+	tk.MustExec("create table ttl_int_pk (id int primary key, created_at datetime)")
+	tk.MustExec("create table ttl_row_id (id int, created_at timestamp)")
+	tk.MustExec("create table ttl_common_pk (a varchar(10), b int, created_at date, primary key (a, b) clustered)")
+	for _, name := range []string{"ttl_int_pk", "ttl_row_id", "ttl_common_pk"} {
+		tk.MustExec(fmt.Sprintf("alter table %s ttl = created_at + interval 1 day", name))
+	}
+	for i := 0; i < 5; i++ {
+		tk.MustExec(fmt.Sprintf("insert into ttl_int_pk values (%d, now() - interval 2 day), (%d, now())", i, i+10))
+		tk.MustExec(fmt.Sprintf("insert into ttl_row_id values (%d, now() - interval 2 day), (%d, now())", i, i+10))
+		tk.MustExec(fmt.Sprintf("insert into ttl_common_pk values ('a%d', %d, now() - interval 2 day), ('b%d', %d, now())", i, i, i, i))
+	}
+	// Delete in small batches to cover the paginated scan.
+	tk.MustExec(fmt.Sprintf("set @@global.%s = 2", variable.TiDBTTLDeleteBatchSize))
+	defer tk.MustExec(fmt.Sprintf("set @@global.%s = default", variable.TiDBTTLDeleteBatchSize))
+
+	tables := []string{"ttl_int_pk", "ttl_row_id", "ttl_common_pk"}
+
+	// No job runs when the TTL jobs are disabled.
+	tk.MustExec(fmt.Sprintf("set @@global.%s = off", variable.TiDBTTLJobEnable))
+	c.Assert(ttl.NewJobManager(s.se).RunJobs(context.Background(), s.dom.InfoSchema()), IsNil)
+	tk.MustQuery("select count(*) from ttl_int_pk").Check(testkit.Rows("10"))
+	tk.MustExec(fmt.Sprintf("set @@global.%s = on", variable.TiDBTTLJobEnable))
+
+	c.Assert(ttl.NewJobManager(s.se).RunJobs(context.Background(), s.dom.InfoSchema()), IsNil)
+	for _, name := range tables {
+		tk.MustQuery(fmt.Sprintf("select count(*) from %s where created_at < now() - interval 1 day", name)).Check(testkit.Rows("0"))
+		tk.MustQuery(fmt.Sprintf("select count(*) from %s", name)).Check(testkit.Rows("5"))
+		status, err := ttl.LoadTableStatus(s.se, s.tableInfo(c, "test", name).ID)
+		c.Assert(err, IsNil)
+		c.Assert(status.LastJobStatus, Equals, ttl.JobStatusFinished, Commentf("%s: %s", name, status.LastJobError))
+		c.Assert(status.LastJobDeletedRows, Equals, int64(5))
+		c.Assert(status.LastJobFinishTime, Not(Equals), "")
+	}
+
+	// The job isn't due before the job interval passes.
+	tk.MustExec("insert into ttl_int_pk values (100, now() - interval 2 day)")
+	c.Assert(ttl.NewJobManager(s.se).RunJobs(context.Background(), s.dom.InfoSchema()), IsNil)
+	tk.MustQuery("select count(*) from ttl_int_pk").Check(testkit.Rows("6"))
+
+	// The job doesn't run outside of the job window.
+	tblInfo := s.tableInfo(c, "test", "ttl_int_pk")
+	now := time.Now()
+	tk.MustExec(fmt.Sprintf("alter table ttl_int_pk ttl_job_window_start = '%s' ttl_job_window_end = '%s'",
+		now.Add(2*time.Hour).Format("15:04"), now.Add(3*time.Hour).Format("15:04")))
+	tk.MustExec("update mysql.tidb_ttl_table set last_job_start_time = null where table_id = ?", tblInfo.ID)
+	c.Assert(ttl.NewJobManager(s.se).RunJobs(context.Background(), s.dom.InfoSchema()), IsNil)
+	tk.MustQuery("select count(*) from ttl_int_pk").Check(testkit.Rows("6"))
+
+	// The job doesn't run when the TTL of the table is disabled.
+	tk.MustExec("alter table ttl_int_pk ttl_enable = 'off' ttl_job_window_start = '00:00' ttl_job_window_end = '23:59'")
+	c.Assert(ttl.NewJobManager(s.se).RunJobs(context.Background(), s.dom.InfoSchema()), IsNil)
+	tk.MustQuery("select count(*) from ttl_int_pk").Check(testkit.Rows("6"))
+
+	// The statuses of the dropped tables are removed.
+	tk.MustExec("drop table ttl_int_pk, ttl_row_id, ttl_common_pk")
+	c.Assert(ttl.NewJobManager(s.se).RunJobs(context.Background(), s.dom.InfoSchema()), IsNil)
+	tk.MustQuery("select count(*) from mysql.tidb_ttl_table").Check(testkit.Rows("0"))
+}
+
+func (s *testTTLSuite) TestTruncateTable(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists ttl_truncate, ttl_truncate_part")
+	tk.MustExec("create table ttl_truncate (id int primary key, created_at datetime) ttl = created_at + interval 1 day ttl_enable = 'on'")
+	tk.MustExec("create table ttl_truncate_part (id int primary key, created_at datetime) ttl = created_at + interval 1 day " +
+		"partition by range (id) (partition p0 values less than (10), partition p1 values less than (20))")
+	c.Assert(ttl.NewJobManager(s.se).RunJobs(context.Background(), s.dom.InfoSchema()), IsNil)
+
+	// The TTL attributes are kept by truncating the table or its partitions.
+	tk.MustExec("truncate table ttl_truncate")
+	tk.MustExec("alter table ttl_truncate_part truncate partition p0")
+	for _, name := range []string{"ttl_truncate", "ttl_truncate_part"} {
+		info := s.tableInfo(c, "test", name).TTLInfo
+		c.Assert(info, NotNil, Commentf(name))
+		c.Assert(info.ColumnName.L, Equals, "created_at")
+		tk.MustExec(fmt.Sprintf("insert into %s values (1, now() - interval 2 day), (2, now()), (11, now() - interval 2 day)", name))
+	}
+
+	// The status of the truncated table is replaced by the one of the new table ID,
+	// the partitioned table keeps its ID, so make its job due again.
+	tk.MustExec("update mysql.tidb_ttl_table set last_job_start_time = null where table_id = ?", s.tableInfo(c, "test", "ttl_truncate_part").ID)
+	c.Assert(ttl.NewJobManager(s.se).RunJobs(context.Background(), s.dom.InfoSchema()), IsNil)
+	for _, name := range []string{"ttl_truncate", "ttl_truncate_part"} {
+		tk.MustQuery(fmt.Sprintf("select id from %s", name)).Check(testkit.Rows("2"))
+	}
+	tblInfo := s.tableInfo(c, "test", "ttl_truncate")
+	tk.MustQuery("select count(*) from mysql.tidb_ttl_table where table_id = ?", tblInfo.ID).Check(testkit.Rows("1"))
+	tk.MustExec("drop table ttl_truncate, ttl_truncate_part")
+	c.Assert(ttl.NewJobManager(s.se).RunJobs(context.Background(), s.dom.InfoSchema()), IsNil)
+	tk.MustQuery("select count(*) from mysql.tidb_ttl_table").Check(testkit.Rows("0"))
+}
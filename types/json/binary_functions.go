@@ -232,20 +232,19 @@ func (bj BinaryJSON) extractTo(buf []BinaryJSON, pathExpr PathExpression) []Bina
 		return append(buf, bj)
 	}
 	currentLeg, subPathExpr := pathExpr.popOneLeg()
-	if currentLeg.typ == pathLegIndex {
+	if currentLeg.typ == pathLegIndex || currentLeg.typ == pathLegRange {
 		if bj.TypeCode != TypeCodeArray {
-			if currentLeg.arrayIndex <= 0 && currentLeg.arrayIndex != arrayIndexAsterisk {
-				buf = bj.extractTo(buf, subPathExpr)
+			// A non-array value is treated as an array of itself, unless it is selected by '[*]'.
+			if currentLeg.arrayIndex != arrayIndexAsterisk || currentLeg.typ != pathLegIndex {
+				if start, end := currentLeg.arraySelection(1); start == 0 && end == 0 {
+					buf = bj.extractTo(buf, subPathExpr)
+				}
 			}
 			return buf
 		}
-		elemCount := bj.GetElemCount()
-		if currentLeg.arrayIndex == arrayIndexAsterisk {
-			for i := 0; i < elemCount; i++ {
-				buf = bj.arrayGetElem(i).extractTo(buf, subPathExpr)
-			}
-		} else if currentLeg.arrayIndex < elemCount {
-			buf = bj.arrayGetElem(currentLeg.arrayIndex).extractTo(buf, subPathExpr)
+		start, end := currentLeg.arraySelection(bj.GetElemCount())
+		for i := start; i <= end; i++ {
+			buf = bj.arrayGetElem(i).extractTo(buf, subPathExpr)
 		}
 	} else if currentLeg.typ == pathLegKey && bj.TypeCode == TypeCodeObject {
 		elemCount := bj.GetElemCount()
@@ -339,7 +338,7 @@ func buildBinaryObject(keys [][]byte, elems []BinaryJSON) (BinaryJSON, error) {
 }
 
 // Modify modifies a JSON object by insert, replace or set.
-// All path expressions cannot contain * or ** wildcard.
+// All path expressions cannot contain * or ** wildcard, or an array range.
 // If any error occurs, the input won't be changed.
 func (bj BinaryJSON) Modify(pathExprList []PathExpression, values []BinaryJSON, mt ModifyType) (retj BinaryJSON, err error) {
 	if len(pathExprList) != len(values) {
@@ -347,9 +346,8 @@ func (bj BinaryJSON) Modify(pathExprList []PathExpression, values []BinaryJSON,
 		return retj, errors.New("Incorrect parameter count")
 	}
 	for _, pathExpr := range pathExprList {
-		if pathExpr.flags.containsAnyAsterisk() {
-			// TODO: should return 3149(42000)
-			return retj, errors.New("Invalid path expression")
+		if pathExpr.flags.couldMatchMultipleValues() {
+			return retj, ErrInvalidJSONPathWildcard
 		}
 	}
 	for i := 0; i < len(pathExprList); i++ {
@@ -388,9 +386,11 @@ func (bj BinaryJSON) ArrayInsert(pathExpr PathExpression, value BinaryJSON) (res
 		return bj, nil
 	}
 
-	idx := lastLeg.arrayIndex
 	count := obj.GetElemCount()
-	if idx >= count {
+	idx := resolveArrayIndex(lastLeg.arrayIndex, lastLeg.fromLast, count)
+	if idx < 0 {
+		idx = 0
+	} else if idx >= count {
 		idx = count
 	}
 	// Insert into the array
@@ -414,15 +414,15 @@ func (bj BinaryJSON) ArrayInsert(pathExpr PathExpression, value BinaryJSON) (res
 }
 
 // Remove removes the elements indicated by pathExprList from JSON.
+// All path expressions cannot contain * or ** wildcard, or an array range.
 func (bj BinaryJSON) Remove(pathExprList []PathExpression) (BinaryJSON, error) {
 	for _, pathExpr := range pathExprList {
 		if len(pathExpr.legs) == 0 {
 			// TODO: should return 3153(42000)
 			return bj, errors.New("Invalid path expression")
 		}
-		if pathExpr.flags.containsAnyAsterisk() {
-			// TODO: should return 3149(42000)
-			return bj, errors.New("Invalid path expression")
+		if pathExpr.flags.couldMatchMultipleValues() {
+			return bj, ErrInvalidJSONPathWildcard
 		}
 		modifer := &binaryModifier{bj: bj}
 		bj = modifer.remove(pathExpr)
@@ -557,9 +557,10 @@ func (bm *binaryModifier) doRemove(path PathExpression) {
 		}
 		bm.modifyPtr = &parentBj.Value[0]
 		elemCount := parentBj.GetElemCount()
+		removeIdx, _ := lastLeg.arraySelection(elemCount)
 		elems := make([]BinaryJSON, 0, elemCount-1)
 		for i := 0; i < elemCount; i++ {
-			if i != lastLeg.arrayIndex {
+			if i != removeIdx {
 				elems = append(elems, parentBj.arrayGetElem(i))
 			}
 		}
@@ -1024,21 +1025,12 @@ func (bj BinaryJSON) extractToCallback(pathExpr PathExpression, callbackFn extra
 	}
 
 	currentLeg, subPathExpr := pathExpr.popOneLeg()
-	if currentLeg.typ == pathLegIndex && bj.TypeCode == TypeCodeArray {
-		elemCount := bj.GetElemCount()
-		if currentLeg.arrayIndex == arrayIndexAsterisk {
-			for i := 0; i < elemCount; i++ {
-				// buf = bj.arrayGetElem(i).extractTo(buf, subPathExpr)
-				path := fullpath.pushBackOneIndexLeg(i)
-				stop, err = bj.arrayGetElem(i).extractToCallback(subPathExpr, callbackFn, path)
-				if stop || err != nil {
-					return
-				}
-			}
-		} else if currentLeg.arrayIndex < elemCount {
-			// buf = bj.arrayGetElem(currentLeg.arrayIndex).extractTo(buf, subPathExpr)
-			path := fullpath.pushBackOneIndexLeg(currentLeg.arrayIndex)
-			stop, err = bj.arrayGetElem(currentLeg.arrayIndex).extractToCallback(subPathExpr, callbackFn, path)
+	if (currentLeg.typ == pathLegIndex || currentLeg.typ == pathLegRange) && bj.TypeCode == TypeCodeArray {
+		start, end := currentLeg.arraySelection(bj.GetElemCount())
+		for i := start; i <= end; i++ {
+			// buf = bj.arrayGetElem(i).extractTo(buf, subPathExpr)
+			path := fullpath.pushBackOneIndexLeg(i)
+			stop, err = bj.arrayGetElem(i).extractToCallback(subPathExpr, callbackFn, path)
 			if stop || err != nil {
 				return
 			}
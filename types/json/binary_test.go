@@ -66,6 +66,18 @@ func (s *testJSONSuite) TestBinaryJSONExtract(c *C) {
 		{bj1, []string{`$.a[*]."aa"`}, mustParseBinaryFromString(c, `["bb", "cc"]`), true, nil},
 		{bj1, []string{`$."\"hello\""`}, mustParseBinaryFromString(c, `"world"`), true, nil},
 		{bj1, []string{`$**[1]`}, mustParseBinaryFromString(c, `"2"`), true, nil},
+		{bj2, []string{"$[last]"}, mustParseBinaryFromString(c, `true`), true, nil},
+		{bj2, []string{"$[last-1]"}, mustParseBinaryFromString(c, `null`), true, nil},
+		{bj2, []string{"$[last-6]"}, mustParseBinaryFromString(c, "null"), false, nil},
+		{bj2, []string{"$[last-2 to last]"}, mustParseBinaryFromString(c, `["hello, world", null, true]`), true, nil},
+		{bj2, []string{"$[4 to 100]"}, mustParseBinaryFromString(c, `[null, true]`), true, nil},
+		{bj2, []string{"$[last-100 to 1]"}, mustParseBinaryFromString(c, `[{"a": 1, "b": true}, 3]`), true, nil},
+		{bj2, []string{"$[2 to last-3]"}, mustParseBinaryFromString(c, `3.5`), true, nil},
+		{bj2, []string{"$[3 to last-3]"}, mustParseBinaryFromString(c, "null"), false, nil},
+		{bj1, []string{"$.*[last]"}, mustParseBinaryFromString(c, `["world", {"aa": "cc"}, true, "d"]`), true, nil},
+		{bj1, []string{"$.a[1 to last].aa"}, mustParseBinaryFromString(c, `["bb", "cc"]`), true, nil},
+		{bj1, []string{"$**[1 to 2]"}, mustParseBinaryFromString(c, `["2", {"aa": "bb"}]`), true, nil},
+		{bj1, []string{"$[0 to last]"}, bj1, true, nil}, // in Extract, autowraped bj1 as an array.
 
 		// test extract with multi path expressions.
 		{bj1, []string{"$.a", "$[5]"}, mustParseBinaryFromString(c, `[[1, "2", {"aa": "bb"}, 4.0, {"aa": "cc"}]]`), true, nil},
@@ -188,11 +200,20 @@ func (s *testJSONSuite) TestBinaryJSONModify(c *C) {
 		// nothing changed because we want to replace but the full path doesn't exist.
 		{`{"a": [3, 4]}`, "$.a[2]", `30`, `{"a": [3, 4]}`, true, ModifyReplace},
 
+		// array indexes counted from the last element.
+		{`[1, 2, 3]`, "$[last]", `30`, `[1, 2, 30]`, true, ModifySet},
+		{`[1, 2, 3]`, "$[last-2]", `10`, `[10, 2, 3]`, true, ModifyReplace},
+		{`[1, 2, 3]`, "$[last-3]", `10`, `[1, 2, 3]`, true, ModifyReplace},
+
 		// bad path expression.
 		{"null", "$.*", "{}", "null", false, ModifySet},
 		{"null", "$[*]", "{}", "null", false, ModifySet},
 		{"null", "$**.a", "{}", "null", false, ModifySet},
 		{"null", "$**[3]", "{}", "null", false, ModifySet},
+		{`[1, 2, 3, 4]`, "$[1 to last]", `0`, `[1, 2, 3, 4]`, false, ModifySet},
+		{`[1, 2, 3, 4]`, "$[1 to 2]", `0`, `[1, 2, 3, 4]`, false, ModifyReplace},
+		{"null", "$.*", "{}", "null", false, ModifyInsert},
+		{"null", "$[0 to 1]", "{}", "null", false, ModifyInsert},
 	}
 	for _, tt := range tests {
 		pathExpr, err := ParseJSONPathExpr(tt.setField)
@@ -230,6 +251,11 @@ func (s *testJSONSuite) TestBinaryJSONRemove(c *C) {
 		{`{"a":[3,4,5]}`, "$.a[1]", `{"a":[3,5]}`, true},
 		{`{"a":[3,4,5]}`, "$.a[4]", `{"a":[3,4,5]}`, true},
 		{`{"a": [1, 2, {"aa": "xx"}]}`, "$.a[2].aa", `{"a": [1, 2, {}]}`, true},
+		{`{"a":[3,4,5]}`, "$.a[last]", `{"a":[3,4]}`, true},
+		{`{"a":[3,4,5]}`, "$.a[last-1]", `{"a":[3,5]}`, true},
+		{`{"a":[3,4,5]}`, "$.a[last-3]", `{"a":[3,4,5]}`, true},
+		{`[1,2,3,4,5]`, "$[1 to 3]", `[1,2,3,4,5]`, false},
+		{`{"a":[1,[2,3]],"b":{"a":4,"c":{"a":5}}}`, "$**.a", `{"a":[1,[2,3]],"b":{"a":4,"c":{"a":5}}}`, false},
 	}
 	for _, tt := range tests {
 		pathExpr, err := ParseJSONPathExpr(tt.path)
@@ -28,9 +28,12 @@ import (
 		columnReference ::= // omit...
 		pathLeg ::= member | arrayLocation | '**'
 		member ::= '.' (keyName | '*')
-		arrayLocation ::= '[' (non-negative-integer | '*') ']'
+		arrayLocation ::= '[' (arrayIndex | arrayIndex 'to' arrayIndex | '*') ']'
+		arrayIndex ::= non-negative-integer | 'last' [ '-' non-negative-integer ]
 		keyName ::= ECMAScript-identifier | ECMAScript-string-literal
 
+	The 'last' and 'to' forms of arrayLocation are added in MySQL 8.0.
+
 	And some implementation limits in MySQL 5.7:
 		1) columnReference in scope must be empty now;
 		2) double asterisk(**) could not be last leg;
@@ -43,11 +46,17 @@ import (
 		select json_extract('{"a": "b", "c": [1, "2"]}', '$.c[2]') -> NULL
 		select json_extract('{"a": "b", "c": [1, "2"]}', '$.c[*]') -> [1, "2"]
 		select json_extract('{"a": "b", "c": [1, "2"]}', '$.*') -> ["b", [1, "2"]]
+		select json_extract('[1, 2, 3, 4]', '$[last]') -> 4
+		select json_extract('[1, 2, 3, 4]', '$[last-2 to last]') -> [2, 3, 4]
 */
 
 // [a-zA-Z_][a-zA-Z0-9_]* matches any identifier;
 // "[^"\\]*(\\.[^"\\]*)*" matches any string literal which can carry escaped quotes;
-var jsonPathExprLegRe = regexp.MustCompile(`(\.\s*([a-zA-Z_][a-zA-Z0-9_]*|\*|"[^"\\]*(\\.[^"\\]*)*")|(\[\s*([0-9]+|\*)\s*\])|\*\*)`)
+// [0-9]+|last(\s*-\s*[0-9]+)? matches any array index, optionally counted from the last element;
+var jsonPathExprLegRe = regexp.MustCompile(`(\.\s*([a-zA-Z_][a-zA-Z0-9_]*|\*|"[^"\\]*(\\.[^"\\]*)*")|(\[\s*(([0-9]+|last(\s*-\s*[0-9]+)?)(\s+to\s+([0-9]+|last(\s*-\s*[0-9]+)?))?|\*)\s*\])|\*\*)`)
+
+// jsonPathArrayRangeRe splits the bounds of an array range like '1 to last'.
+var jsonPathArrayRangeRe = regexp.MustCompile(`\s+to\s+`)
 
 type pathLegType byte
 
@@ -58,25 +67,63 @@ const (
 	pathLegIndex pathLegType = 0x02
 	// pathLegDoubleAsterisk indicates the path leg with form '**'.
 	pathLegDoubleAsterisk pathLegType = 0x03
+	// pathLegRange indicates the path leg with form '[number to number]'.
+	pathLegRange pathLegType = 0x04
 )
 
 // pathLeg is only used by PathExpression.
 type pathLeg struct {
-	typ        pathLegType
-	arrayIndex int    // if typ is pathLegIndex, the value should be parsed into here.
-	dotKey     string // if typ is pathLegKey, the key should be parsed into here.
+	typ              pathLegType
+	arrayIndex       int    // if typ is pathLegIndex or pathLegRange, the value (or the range start) should be parsed into here.
+	fromLast         bool   // if true, arrayIndex counts backwards from the last element.
+	rangeEnd         int    // if typ is pathLegRange, the range end should be parsed into here.
+	rangeEndFromLast bool   // if true, rangeEnd counts backwards from the last element.
+	dotKey           string // if typ is pathLegKey, the key should be parsed into here.
 }
 
 // arrayIndexAsterisk is for parsing `*` into a number.
 // we need this number represent "all".
 const arrayIndexAsterisk = -1
 
+// arraySelection resolves an index or range leg against an array with elemCount elements.
+// It returns the positions of the first and the last selected elements, the selection is
+// empty if start > end.
+func (leg pathLeg) arraySelection(elemCount int) (start, end int) {
+	if leg.typ == pathLegIndex {
+		if leg.arrayIndex == arrayIndexAsterisk {
+			return 0, elemCount - 1
+		}
+		idx := resolveArrayIndex(leg.arrayIndex, leg.fromLast, elemCount)
+		if idx < 0 || idx >= elemCount {
+			return 0, -1
+		}
+		return idx, idx
+	}
+	start = resolveArrayIndex(leg.arrayIndex, leg.fromLast, elemCount)
+	end = resolveArrayIndex(leg.rangeEnd, leg.rangeEndFromLast, elemCount)
+	if start < 0 {
+		start = 0
+	}
+	if end >= elemCount {
+		end = elemCount - 1
+	}
+	return start, end
+}
+
+func resolveArrayIndex(index int, fromLast bool, elemCount int) int {
+	if fromLast {
+		return elemCount - 1 - index
+	}
+	return index
+}
+
 // pathExpressionFlag holds attributes of PathExpression
 type pathExpressionFlag byte
 
 const (
 	pathExpressionContainsAsterisk       pathExpressionFlag = 0x01
 	pathExpressionContainsDoubleAsterisk pathExpressionFlag = 0x02
+	pathExpressionContainsRange          pathExpressionFlag = 0x04
 )
 
 // containsAnyAsterisk returns true if pef contains any asterisk.
@@ -85,6 +132,12 @@ func (pef pathExpressionFlag) containsAnyAsterisk() bool {
 	return byte(pef) != 0
 }
 
+// couldMatchMultipleValues returns true if pef contains any asterisk or array range.
+func (pef pathExpressionFlag) couldMatchMultipleValues() bool {
+	pef &= pathExpressionContainsAsterisk | pathExpressionContainsDoubleAsterisk | pathExpressionContainsRange
+	return byte(pef) != 0
+}
+
 // PathExpression is for JSON path expression.
 type PathExpression struct {
 	legs  []pathLeg
@@ -104,6 +157,8 @@ func (pe PathExpression) popOneLeg() (pathLeg, PathExpression) {
 			newPe.flags |= pathExpressionContainsAsterisk
 		} else if leg.typ == pathLegDoubleAsterisk {
 			newPe.flags |= pathExpressionContainsDoubleAsterisk
+		} else if leg.typ == pathLegRange {
+			newPe.flags |= pathExpressionContainsRange
 		}
 	}
 	return pe.legs[0], newPe
@@ -146,6 +201,12 @@ func (pe PathExpression) ContainsAnyAsterisk() bool {
 	return pe.flags.containsAnyAsterisk()
 }
 
+// CouldMatchMultipleValues returns true if pe contains any asterisk or array range,
+// which means it may match more than one value.
+func (pe PathExpression) CouldMatchMultipleValues() bool {
+	return pe.flags.couldMatchMultipleValues()
+}
+
 // ParseJSONPathExpr parses a JSON path expression. Returns a PathExpression
 // object which can be used in JSON_EXTRACT, JSON_SET and so on.
 func ParseJSONPathExpr(pathExpr string) (pe PathExpression, err error) {
@@ -190,17 +251,35 @@ func ParseJSONPathExpr(pathExpr string) (pe PathExpression, err error) {
 			// The leg is an index of a JSON array.
 			var leg = strings.TrimFunc(pathExprSuffix[start+1:end], isBlank)
 			var indexStr = strings.TrimFunc(leg[0:len(leg)-1], isBlank)
-			var index int
 			if len(indexStr) == 1 && indexStr[0] == '*' {
 				pe.flags |= pathExpressionContainsAsterisk
-				index = arrayIndexAsterisk
+				pe.legs = append(pe.legs, pathLeg{typ: pathLegIndex, arrayIndex: arrayIndexAsterisk})
+				continue
+			}
+			var newLeg pathLeg
+			if bounds := jsonPathArrayRangeRe.Split(indexStr, 2); len(bounds) == 2 {
+				newLeg.typ = pathLegRange
+				if newLeg.arrayIndex, newLeg.fromLast, err = parseArrayIndex(bounds[0]); err != nil {
+					return
+				}
+				if newLeg.rangeEnd, newLeg.rangeEndFromLast, err = parseArrayIndex(bounds[1]); err != nil {
+					return
+				}
+				// A range whose bounds are both counted from the same end can be checked here,
+				// the others may only turn out to be empty when evaluated.
+				if (newLeg.fromLast == newLeg.rangeEndFromLast) &&
+					((!newLeg.fromLast && newLeg.arrayIndex > newLeg.rangeEnd) || (newLeg.fromLast && newLeg.arrayIndex < newLeg.rangeEnd)) {
+					err = ErrInvalidJSONPath.GenWithStackByArgs(pathExpr)
+					return
+				}
+				pe.flags |= pathExpressionContainsRange
 			} else {
-				if index, err = strconv.Atoi(indexStr); err != nil {
-					err = errors.Trace(err)
+				newLeg.typ = pathLegIndex
+				if newLeg.arrayIndex, newLeg.fromLast, err = parseArrayIndex(indexStr); err != nil {
 					return
 				}
 			}
-			pe.legs = append(pe.legs, pathLeg{typ: pathLegIndex, arrayIndex: index})
+			pe.legs = append(pe.legs, newLeg)
 		} else if pathExprSuffix[start] == '.' {
 			// The leg is a key of a JSON object.
 			var key = strings.TrimFunc(pathExprSuffix[start+1:end], isBlank)
@@ -230,6 +309,23 @@ func ParseJSONPathExpr(pathExpr string) (pe PathExpression, err error) {
 	return
 }
 
+// parseArrayIndex parses an array index like '3', 'last' or 'last-3'.
+func parseArrayIndex(indexStr string) (index int, fromLast bool, err error) {
+	if strings.HasPrefix(indexStr, "last") {
+		fromLast = true
+		indexStr = strings.TrimFunc(indexStr[len("last"):], isBlank)
+		if len(indexStr) == 0 {
+			return 0, true, nil
+		}
+		// The regexp guarantees that a minus sign follows.
+		indexStr = strings.TrimFunc(indexStr[1:], isBlank)
+	}
+	if index, err = strconv.Atoi(indexStr); err != nil {
+		return 0, false, errors.Trace(err)
+	}
+	return index, fromLast, nil
+}
+
 func isBlank(c rune) bool {
 	if c == '\n' || c == '\r' || c == '\t' || c == ' ' {
 		return true
@@ -248,9 +344,15 @@ func (pe PathExpression) String() string {
 				s.WriteString("[*]")
 			} else {
 				s.WriteString("[")
-				s.WriteString(strconv.Itoa(leg.arrayIndex))
+				writeArrayIndex(&s, leg.arrayIndex, leg.fromLast)
 				s.WriteString("]")
 			}
+		case pathLegRange:
+			s.WriteString("[")
+			writeArrayIndex(&s, leg.arrayIndex, leg.fromLast)
+			s.WriteString(" to ")
+			writeArrayIndex(&s, leg.rangeEnd, leg.rangeEndFromLast)
+			s.WriteString("]")
 		case pathLegKey:
 			s.WriteString(".")
 			s.WriteString(quoteString(leg.dotKey))
@@ -260,3 +362,15 @@ func (pe PathExpression) String() string {
 	}
 	return s.String()
 }
+
+func writeArrayIndex(s *strings.Builder, index int, fromLast bool) {
+	if !fromLast {
+		s.WriteString(strconv.Itoa(index))
+		return
+	}
+	s.WriteString("last")
+	if index > 0 {
+		s.WriteString("-")
+		s.WriteString(strconv.Itoa(index))
+	}
+}
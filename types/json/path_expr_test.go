@@ -26,6 +26,7 @@ func (s *testJSONSuite) TestContainsAnyAsterisk(c *C) {
 		{"$.a[*]", true},
 		{"$.*[b]", true},
 		{"$**.a[b]", true},
+		{"$.a[1 to 2]", false},
 	}
 	for _, tt := range tests {
 		pe, err := ParseJSONPathExpr(tt.exprString)
@@ -34,6 +35,30 @@ func (s *testJSONSuite) TestContainsAnyAsterisk(c *C) {
 	}
 }
 
+func (s *testJSONSuite) TestCouldMatchMultipleValues(c *C) {
+	var tests = []struct {
+		exprString     string
+		multipleValues bool
+	}{
+		{"$.a[1]", false},
+		{"$.a[last-1]", false},
+		{"$.a[*]", true},
+		{"$**.a", true},
+		{"$.a[1 to 2]", true},
+		{"$.a[last-2 to last]", true},
+	}
+	for _, tt := range tests {
+		pe, err := ParseJSONPathExpr(tt.exprString)
+		c.Assert(err, IsNil)
+		c.Assert(pe.CouldMatchMultipleValues(), Equals, tt.multipleValues)
+
+		pe, err = ParseJSONPathExpr("$.k" + tt.exprString[1:])
+		c.Assert(err, IsNil)
+		_, subPe := pe.popOneLeg()
+		c.Assert(subPe.CouldMatchMultipleValues(), Equals, tt.multipleValues)
+	}
+}
+
 func (s *testJSONSuite) TestValidatePathExpr(c *C) {
 	var tests = []struct {
 		exprString string
@@ -45,11 +70,21 @@ func (s *testJSONSuite) TestValidatePathExpr(c *C) {
 		{"   $ .   key1  [  3  ]**[*].*.key3", true, 6},
 		{`$."key1 string"[  3  ][*].*.key3`, true, 5},
 		{`$."hello \"escaped quotes\" world\\n"[3][*].*.key3`, true, 5},
+		{"$[last]", true, 1},
+		{"$[ last - 1 ][1 to last][last-3 to 2]", true, 3},
+		{"$[last-2 to last-1]", true, 1},
 
 		{`$.\"escaped quotes\"[3][*].*.key3`, false, 0},
 		{`$.hello \"escaped quotes\" world[3][*].*.key3`, false, 0},
 		{`$NoValidLegsHere`, false, 0},
 		{`$        No Valid Legs Here .a.b.c`, false, 0},
+		{"$[lastt]", false, 0},
+		{"$[last+1]", false, 0},
+		{"$[-1]", false, 0},
+		{"$[1to2]", false, 0},
+		{"$[2 to 1]", false, 0},
+		{"$[last-1 to last-2]", false, 0},
+		{"$[1 to *]", false, 0},
 	}
 
 	for _, tt := range tests {
@@ -72,6 +107,10 @@ func (s *testJSONSuite) TestPathExprToString(c *C) {
 		{"$.*[2]"},
 		{"$**.a[3]"},
 		{`$."\"hello\""`},
+		{"$[last]"},
+		{"$[last-2]"},
+		{"$[1 to last-1]"},
+		{"$.*[last-3 to 5]"},
 	}
 	for _, tt := range tests {
 		pe, err := ParseJSONPathExpr(tt.exprString)
@@ -0,0 +1,285 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spatial
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/pingcap/errors"
+	mysql "github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/util/dbterror"
+)
+
+/*
+   The geometry storage format is the same as MySQL:
+
+   geometry ::= srid wkb
+   srid     ::= uint32 (little-endian)
+   wkb      ::= byte-order type body
+   byte-order ::= 0x00 (big-endian) | 0x01 (little-endian)
+   type     ::= uint32, 1 for point, 2 for linestring, 3 for polygon
+   body     ::=
+       point      ::= x y                    // two float64
+       linestring ::= num-points point*      // num-points is uint32
+       polygon    ::= num-rings linestring*  // num-rings is uint32, every ring is closed
+
+   Only the point, linestring and polygon types are supported for now.
+*/
+
+// GeometryType is the type of a geometry value.
+type GeometryType uint32
+
+// Geometry types, the values are the same as the WKB type codes.
+const (
+	TypePoint      GeometryType = 1
+	TypeLineString GeometryType = 2
+	TypePolygon    GeometryType = 3
+)
+
+// String implements the fmt.Stringer interface.
+func (t GeometryType) String() string {
+	switch t {
+	case TypePoint:
+		return "POINT"
+	case TypeLineString:
+		return "LINESTRING"
+	case TypePolygon:
+		return "POLYGON"
+	}
+	return "UNKNOWN"
+}
+
+const (
+	sridLen         = 4
+	wkbHeaderLen    = 5
+	wkbBigEndian    = 0
+	wkbLittleEndian = 1
+	pointLen        = 16
+)
+
+var (
+	// ErrCantCreateGeometryObject means the data can't be decoded as a geometry.
+	ErrCantCreateGeometryObject = dbterror.ClassTypes.NewStd(mysql.ErrCantCreateGeometryObject)
+	// ErrGISInvalidData means invalid GIS data is provided to a spatial function.
+	ErrGISInvalidData = dbterror.ClassTypes.NewStd(mysql.ErrGISInvalidData)
+	// ErrGISDifferentSRIDs means the two geometries of a binary spatial function have different SRIDs.
+	ErrGISDifferentSRIDs = dbterror.ClassTypes.NewStd(mysql.ErrGISDifferentSRIDs)
+	// ErrGISUnsupportedArgument means the spatial function doesn't support the geometry type yet.
+	ErrGISUnsupportedArgument = dbterror.ClassTypes.NewStd(mysql.ErrGISUnsupportedArgument)
+)
+
+// Point is a point in a two-dimensional Cartesian coordinate system.
+type Point struct {
+	X float64
+	Y float64
+}
+
+// Geometry is a decoded geometry value.
+// A point holds exactly one element in Points, a linestring holds its vertices in Points,
+// and a polygon holds its rings in Rings, the first ring is the exterior ring.
+type Geometry struct {
+	SRID   uint32
+	Type   GeometryType
+	Points []Point
+	Rings  [][]Point
+}
+
+// NewPoint creates a point geometry.
+func NewPoint(srid uint32, x, y float64) Geometry {
+	return Geometry{SRID: srid, Type: TypePoint, Points: []Point{{X: x, Y: y}}}
+}
+
+// Encode encodes the geometry into the storage format.
+func (g Geometry) Encode() []byte {
+	buf := make([]byte, 0, sridLen+wkbHeaderLen+4+pointLen*len(g.Points))
+	buf = appendUint32(buf, g.SRID)
+	buf = append(buf, wkbLittleEndian)
+	buf = appendUint32(buf, uint32(g.Type))
+	switch g.Type {
+	case TypePoint:
+		buf = appendPoint(buf, g.Points[0])
+	case TypeLineString:
+		buf = appendPoints(buf, g.Points)
+	case TypePolygon:
+		buf = appendUint32(buf, uint32(len(g.Rings)))
+		for _, ring := range g.Rings {
+			buf = appendPoints(buf, ring)
+		}
+	}
+	return buf
+}
+
+// Decode decodes a geometry from the storage format.
+func Decode(data []byte) (Geometry, error) {
+	if len(data) < sridLen+wkbHeaderLen {
+		return Geometry{}, ErrCantCreateGeometryObject.GenWithStackByArgs()
+	}
+	g := Geometry{SRID: binary.LittleEndian.Uint32(data)}
+	d := &wkbDecoder{data: data[sridLen:]}
+	if err := d.decodeHeader(&g); err != nil {
+		return Geometry{}, err
+	}
+	switch g.Type {
+	case TypePoint:
+		p, err := d.point()
+		if err != nil {
+			return Geometry{}, err
+		}
+		g.Points = []Point{p}
+	case TypeLineString:
+		points, err := d.points()
+		if err != nil {
+			return Geometry{}, err
+		}
+		g.Points = points
+	case TypePolygon:
+		n, err := d.uint32()
+		if err != nil {
+			return Geometry{}, err
+		}
+		g.Rings = make([][]Point, 0, n)
+		for i := uint32(0); i < n; i++ {
+			ring, err := d.points()
+			if err != nil {
+				return Geometry{}, err
+			}
+			g.Rings = append(g.Rings, ring)
+		}
+	}
+	if len(d.data) != 0 {
+		return Geometry{}, ErrCantCreateGeometryObject.GenWithStackByArgs()
+	}
+	if err := g.validate(); err != nil {
+		return Geometry{}, ErrCantCreateGeometryObject.GenWithStackByArgs()
+	}
+	return g, nil
+}
+
+// validate checks the structure of the geometry, it's shared by the WKB decoder and the WKT parser.
+func (g Geometry) validate() error {
+	switch g.Type {
+	case TypePoint:
+		if len(g.Points) != 1 {
+			return errors.New("point must have exactly one coordinate")
+		}
+	case TypeLineString:
+		if len(g.Points) < 2 {
+			return errors.New("linestring must have at least two points")
+		}
+	case TypePolygon:
+		if len(g.Rings) == 0 {
+			return errors.New("polygon must have at least one ring")
+		}
+		for _, ring := range g.Rings {
+			if len(ring) < 4 || ring[0] != ring[len(ring)-1] {
+				return errors.New("polygon ring must be closed and have at least four points")
+			}
+		}
+	default:
+		return errors.Errorf("unsupported geometry type %d", g.Type)
+	}
+	return nil
+}
+
+type wkbDecoder struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+func (d *wkbDecoder) decodeHeader(g *Geometry) error {
+	switch d.data[0] {
+	case wkbBigEndian:
+		d.order = binary.BigEndian
+	case wkbLittleEndian:
+		d.order = binary.LittleEndian
+	default:
+		return ErrCantCreateGeometryObject.GenWithStackByArgs()
+	}
+	d.data = d.data[1:]
+	tp, err := d.uint32()
+	if err != nil {
+		return err
+	}
+	g.Type = GeometryType(tp)
+	switch g.Type {
+	case TypePoint, TypeLineString, TypePolygon:
+		return nil
+	}
+	return ErrCantCreateGeometryObject.GenWithStackByArgs()
+}
+
+func (d *wkbDecoder) uint32() (uint32, error) {
+	if len(d.data) < 4 {
+		return 0, ErrCantCreateGeometryObject.GenWithStackByArgs()
+	}
+	v := d.order.Uint32(d.data)
+	d.data = d.data[4:]
+	return v, nil
+}
+
+func (d *wkbDecoder) point() (Point, error) {
+	if len(d.data) < pointLen {
+		return Point{}, ErrCantCreateGeometryObject.GenWithStackByArgs()
+	}
+	p := Point{
+		X: math.Float64frombits(d.order.Uint64(d.data)),
+		Y: math.Float64frombits(d.order.Uint64(d.data[8:])),
+	}
+	d.data = d.data[pointLen:]
+	if math.IsNaN(p.X) || math.IsNaN(p.Y) || math.IsInf(p.X, 0) || math.IsInf(p.Y, 0) {
+		return Point{}, ErrCantCreateGeometryObject.GenWithStackByArgs()
+	}
+	return p, nil
+}
+
+func (d *wkbDecoder) points() ([]Point, error) {
+	n, err := d.uint32()
+	if err != nil {
+		return nil, err
+	}
+	if uint64(n)*pointLen > uint64(len(d.data)) {
+		return nil, ErrCantCreateGeometryObject.GenWithStackByArgs()
+	}
+	points := make([]Point, 0, n)
+	for i := uint32(0); i < n; i++ {
+		p, err := d.point()
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	return append(buf, b[:]...)
+}
+
+func appendPoint(buf []byte, p Point) []byte {
+	var b [pointLen]byte
+	binary.LittleEndian.PutUint64(b[:], math.Float64bits(p.X))
+	binary.LittleEndian.PutUint64(b[8:], math.Float64bits(p.Y))
+	return append(buf, b[:]...)
+}
+
+func appendPoints(buf []byte, points []Point) []byte {
+	buf = appendUint32(buf, uint32(len(points)))
+	for _, p := range points {
+		buf = appendPoint(buf, p)
+	}
+	return buf
+}
@@ -0,0 +1,137 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spatial
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testGeometrySuite{})
+
+type testGeometrySuite struct{}
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+func (s *testGeometrySuite) TestWKTRoundTrip(c *C) {
+	c.Parallel()
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{"POINT(1 2)", "POINT(1 2)"},
+		{" point ( -1.5   2e3 ) ", "POINT(-1.5 2000)"},
+		{"LineString(0 0, 1 1, 2 0)", "LINESTRING(0 0,1 1,2 0)"},
+		{"POLYGON((0 0,10 0,10 10,0 10,0 0),(2 2,4 2,4 4,2 2))", "POLYGON((0 0,10 0,10 10,0 10,0 0),(2 2,4 2,4 4,2 2))"},
+	}
+	for _, t := range tests {
+		g, err := ParseWKT(t.input, 4326)
+		c.Assert(err, IsNil, Commentf("%s", t.input))
+		c.Assert(g.WKT(), Equals, t.output)
+
+		decoded, err := Decode(g.Encode())
+		c.Assert(err, IsNil)
+		c.Assert(decoded.SRID, Equals, uint32(4326))
+		c.Assert(decoded.WKT(), Equals, t.output)
+	}
+
+	invalids := []string{
+		"", "POINT", "POINT(1)", "POINT(1 2 3)", "POINT(1 2", "POINT(1 2) x", "POINT(a b)",
+		"LINESTRING(0 0)", "POLYGON((0 0,1 0,1 1))", "POLYGON((0 0,1 0,1 1,0 2))", "CIRCLE(0 0)",
+	}
+	for _, input := range invalids {
+		_, err := ParseWKT(input, 0)
+		c.Assert(err, NotNil, Commentf("%s", input))
+	}
+}
+
+func (s *testGeometrySuite) TestDecode(c *C) {
+	c.Parallel()
+	// SRID 0, big-endian WKB of POINT(1 -1).
+	data := []byte{0, 0, 0, 0, 0, 0, 0, 0, 1, 0x3f, 0xf0, 0, 0, 0, 0, 0, 0, 0xbf, 0xf0, 0, 0, 0, 0, 0, 0}
+	g, err := Decode(data)
+	c.Assert(err, IsNil)
+	c.Assert(g.WKT(), Equals, "POINT(1 -1)")
+
+	for _, invalid := range [][]byte{nil, data[:8], data[:len(data)-1], append(data, 0), {0, 0, 0, 0, 2, 1, 0, 0, 0}} {
+		_, err = Decode(invalid)
+		c.Assert(ErrCantCreateGeometryObject.Equal(err), IsTrue)
+	}
+}
+
+func (s *testGeometrySuite) TestDistance(c *C) {
+	c.Parallel()
+	tests := []struct {
+		g1, g2 string
+		dist   float64
+	}{
+		{"POINT(0 0)", "POINT(3 4)", 5},
+		{"POINT(0 0)", "POINT(0 0)", 0},
+		{"POINT(0 5)", "LINESTRING(-1 0,1 0)", 5},
+		{"LINESTRING(-1 0,1 0)", "POINT(2 0)", 1},
+		{"LINESTRING(0 0,2 2)", "LINESTRING(0 2,2 0)", 0},
+		{"POLYGON((0 0,10 0,10 10,0 10,0 0))", "POINT(5 5)", 0},
+		{"POLYGON((0 0,10 0,10 10,0 10,0 0))", "POINT(13 14)", 5},
+		{"POLYGON((0 0,10 0,10 10,0 10,0 0),(2 2,8 2,8 8,2 8,2 2))", "POINT(5 6)", 2},
+		{"POLYGON((0 0,10 0,10 10,0 10,0 0))", "POLYGON((1 1,2 1,2 2,1 1))", 0},
+	}
+	for _, t := range tests {
+		g1, err := ParseWKT(t.g1, 0)
+		c.Assert(err, IsNil)
+		g2, err := ParseWKT(t.g2, 0)
+		c.Assert(err, IsNil)
+		dist, err := Distance(g1, g2)
+		c.Assert(err, IsNil)
+		c.Assert(dist, Equals, t.dist, Commentf("%s %s", t.g1, t.g2))
+	}
+
+	_, err := Distance(NewPoint(0, 0, 0), NewPoint(4326, 0, 0))
+	c.Assert(ErrGISDifferentSRIDs.Equal(err), IsTrue)
+}
+
+func (s *testGeometrySuite) TestContains(c *C) {
+	c.Parallel()
+	square := "POLYGON((0 0,10 0,10 10,0 10,0 0))"
+	tests := []struct {
+		g1, g2   string
+		contains bool
+	}{
+		{square, "POINT(5 5)", true},
+		{square, "POINT(0 5)", false},
+		{square, "POINT(11 5)", false},
+		{"POLYGON((0 0,10 0,10 10,0 10,0 0),(2 2,8 2,8 8,2 8,2 2))", "POINT(5 5)", false},
+		{square, "LINESTRING(1 1,9 9)", true},
+		{square, "LINESTRING(0 0,10 0)", false},
+		{square, "LINESTRING(5 5,15 5)", false},
+		{square, "POLYGON((1 1,2 1,2 2,1 1))", true},
+		{square, square, true},
+		{"LINESTRING(0 0,10 0)", "POINT(5 0)", true},
+		{"LINESTRING(0 0,10 0)", "POINT(0 0)", false},
+		{"LINESTRING(0 0,10 0)", "LINESTRING(2 0,4 0)", true},
+		{"POINT(1 1)", "POINT(1 1)", true},
+		{"POINT(1 1)", "POINT(1 2)", false},
+	}
+	for _, t := range tests {
+		g1, err := ParseWKT(t.g1, 0)
+		c.Assert(err, IsNil)
+		g2, err := ParseWKT(t.g2, 0)
+		c.Assert(err, IsNil)
+		contains, err := Contains(g1, g2)
+		c.Assert(err, IsNil)
+		c.Assert(contains, Equals, t.contains, Commentf("%s %s", t.g1, t.g2))
+	}
+}
@@ -0,0 +1,279 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spatial
+
+import (
+	"math"
+)
+
+// All the relations here use planar (Cartesian) math, SRIDs are only required to be equal.
+
+// segment is a line segment between two points.
+type segment struct {
+	a, b Point
+}
+
+// segments returns the edges of the geometry, a point has no edge.
+func (g Geometry) segments() []segment {
+	var segs []segment
+	appendRing := func(points []Point) {
+		for i := 0; i+1 < len(points); i++ {
+			segs = append(segs, segment{points[i], points[i+1]})
+		}
+	}
+	switch g.Type {
+	case TypeLineString:
+		appendRing(g.Points)
+	case TypePolygon:
+		for _, ring := range g.Rings {
+			appendRing(ring)
+		}
+	}
+	return segs
+}
+
+// vertices returns all the points which make up the geometry.
+func (g Geometry) vertices() []Point {
+	if g.Type != TypePolygon {
+		return g.Points
+	}
+	var points []Point
+	for _, ring := range g.Rings {
+		points = append(points, ring...)
+	}
+	return points
+}
+
+// Distance returns the minimum planar distance between two geometries.
+func Distance(g1, g2 Geometry) (float64, error) {
+	if g1.SRID != g2.SRID {
+		return 0, ErrGISDifferentSRIDs.GenWithStackByArgs("st_distance", g1.SRID, g2.SRID)
+	}
+	if intersects(g1, g2) {
+		return 0, nil
+	}
+	dist := math.Inf(1)
+	segs1, segs2 := g1.segments(), g2.segments()
+	for _, p := range g1.vertices() {
+		for _, s := range segs2 {
+			dist = math.Min(dist, pointSegmentDistance(p, s))
+		}
+		if g2.Type == TypePoint {
+			dist = math.Min(dist, pointDistance(p, g2.Points[0]))
+		}
+	}
+	for _, p := range g2.vertices() {
+		for _, s := range segs1 {
+			dist = math.Min(dist, pointSegmentDistance(p, s))
+		}
+	}
+	return dist, nil
+}
+
+// Contains returns whether g1 completely contains g2, that is, no point of g2 lies in
+// the exterior of g1, and at least one point of the interior of g2 lies in the interior of g1.
+func Contains(g1, g2 Geometry) (bool, error) {
+	if g1.SRID != g2.SRID {
+		return false, ErrGISDifferentSRIDs.GenWithStackByArgs("st_contains", g1.SRID, g2.SRID)
+	}
+	switch g1.Type {
+	case TypePoint:
+		return g2.Type == TypePoint && g1.Points[0] == g2.Points[0], nil
+	case TypeLineString:
+		switch g2.Type {
+		case TypePoint:
+			p := g2.Points[0]
+			// the end points are the boundary of a linestring.
+			if p == g1.Points[0] || p == g1.Points[len(g1.Points)-1] {
+				return false, nil
+			}
+			return onSegments(p, g1.segments()), nil
+		case TypeLineString:
+			for _, s := range g2.segments() {
+				if !onSegments(s.a, g1.segments()) || !onSegments(s.b, g1.segments()) || !onSegments(midPoint(s), g1.segments()) {
+					return false, nil
+				}
+			}
+			return true, nil
+		}
+		return false, nil
+	case TypePolygon:
+		if g2.Type == TypePoint {
+			return pointInPolygon(g2.Points[0], g1) == locInterior, nil
+		}
+		for _, p := range g2.vertices() {
+			if pointInPolygon(p, g1) == locExterior {
+				return false, nil
+			}
+		}
+		edges := g1.segments()
+		hasInterior := false
+		for _, s := range g2.segments() {
+			for _, e := range edges {
+				if properIntersect(s, e) {
+					return false, nil
+				}
+			}
+			switch pointInPolygon(midPoint(s), g1) {
+			case locExterior:
+				return false, nil
+			case locInterior:
+				hasInterior = true
+			}
+		}
+		if !hasInterior && g2.Type == TypePolygon {
+			// all the edges lie on the boundary, e.g. two equal polygons, check a point of the interior of g2.
+			c := centroid(g2.Rings[0][:len(g2.Rings[0])-1])
+			hasInterior = pointInPolygon(c, g2) == locInterior && pointInPolygon(c, g1) == locInterior
+		}
+		return hasInterior, nil
+	}
+	return false, ErrGISUnsupportedArgument.GenWithStackByArgs("st_contains")
+}
+
+// intersects returns whether the two geometries share at least one point.
+func intersects(g1, g2 Geometry) bool {
+	if g1.Type == TypePoint && g2.Type == TypePoint {
+		return g1.Points[0] == g2.Points[0]
+	}
+	if g1.Type == TypePoint {
+		g1, g2 = g2, g1
+	}
+	segs1 := g1.segments()
+	if g2.Type == TypePoint {
+		p := g2.Points[0]
+		if g1.Type == TypePolygon {
+			return pointInPolygon(p, g1) != locExterior
+		}
+		return onSegments(p, segs1)
+	}
+	for _, s1 := range segs1 {
+		for _, s2 := range g2.segments() {
+			if segmentsIntersect(s1, s2) {
+				return true
+			}
+		}
+	}
+	// one geometry may lie completely inside a polygon without touching its edges.
+	if g1.Type == TypePolygon && pointInPolygon(g2.vertices()[0], g1) != locExterior {
+		return true
+	}
+	if g2.Type == TypePolygon && pointInPolygon(g1.vertices()[0], g2) != locExterior {
+		return true
+	}
+	return false
+}
+
+type location int
+
+const (
+	locExterior location = iota
+	locBoundary
+	locInterior
+)
+
+// pointInPolygon locates a point against a polygon with the ray casting algorithm.
+func pointInPolygon(p Point, g Geometry) location {
+	if onSegments(p, g.segments()) {
+		return locBoundary
+	}
+	for i, ring := range g.Rings {
+		inside := false
+		for j, k := 0, len(ring)-1; j < len(ring); k, j = j, j+1 {
+			a, b := ring[j], ring[k]
+			if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+				inside = !inside
+			}
+		}
+		// the point must be inside the exterior ring and outside all the holes.
+		if (i == 0) != inside {
+			return locExterior
+		}
+	}
+	return locInterior
+}
+
+func onSegments(p Point, segs []segment) bool {
+	for _, s := range segs {
+		if orientation(s.a, s.b, p) == 0 && onSegment(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// onSegment returns whether p, which is collinear with s, lies on s.
+func onSegment(s segment, p Point) bool {
+	return p.X >= math.Min(s.a.X, s.b.X) && p.X <= math.Max(s.a.X, s.b.X) &&
+		p.Y >= math.Min(s.a.Y, s.b.Y) && p.Y <= math.Max(s.a.Y, s.b.Y)
+}
+
+// orientation returns the sign of the cross product of (b - a) and (c - a).
+func orientation(a, b, c Point) int {
+	v := (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+func segmentsIntersect(s1, s2 segment) bool {
+	o1, o2 := orientation(s1.a, s1.b, s2.a), orientation(s1.a, s1.b, s2.b)
+	o3, o4 := orientation(s2.a, s2.b, s1.a), orientation(s2.a, s2.b, s1.b)
+	if o1 != o2 && o3 != o4 {
+		return true
+	}
+	return (o1 == 0 && onSegment(s1, s2.a)) || (o2 == 0 && onSegment(s1, s2.b)) ||
+		(o3 == 0 && onSegment(s2, s1.a)) || (o4 == 0 && onSegment(s2, s1.b))
+}
+
+// properIntersect returns whether the two segments cross each other at a single point
+// which is interior to both of them.
+func properIntersect(s1, s2 segment) bool {
+	o1, o2 := orientation(s1.a, s1.b, s2.a), orientation(s1.a, s1.b, s2.b)
+	o3, o4 := orientation(s2.a, s2.b, s1.a), orientation(s2.a, s2.b, s1.b)
+	return o1*o2 < 0 && o3*o4 < 0
+}
+
+func midPoint(s segment) Point {
+	return Point{X: (s.a.X + s.b.X) / 2, Y: (s.a.Y + s.b.Y) / 2}
+}
+
+func centroid(points []Point) Point {
+	var c Point
+	for _, p := range points {
+		c.X += p.X
+		c.Y += p.Y
+	}
+	c.X /= float64(len(points))
+	c.Y /= float64(len(points))
+	return c
+}
+
+func pointDistance(p1, p2 Point) float64 {
+	return math.Hypot(p1.X-p2.X, p1.Y-p2.Y)
+}
+
+func pointSegmentDistance(p Point, s segment) float64 {
+	dx, dy := s.b.X-s.a.X, s.b.Y-s.a.Y
+	if dx == 0 && dy == 0 {
+		return pointDistance(p, s.a)
+	}
+	t := ((p.X-s.a.X)*dx + (p.Y-s.a.Y)*dy) / (dx*dx + dy*dy)
+	t = math.Max(0, math.Min(1, t))
+	return pointDistance(p, Point{X: s.a.X + t*dx, Y: s.a.Y + t*dy})
+}
@@ -0,0 +1,219 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spatial
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// ParseWKT parses a geometry from its well-known text representation, e.g.
+// `POINT(1 2)`, `LINESTRING(0 0,1 1)` or `POLYGON((0 0,10 0,10 10,0 10,0 0))`.
+func ParseWKT(wkt string, srid uint32) (Geometry, error) {
+	p := &wktParser{s: wkt}
+	g, err := p.parse()
+	if err != nil {
+		return Geometry{}, err
+	}
+	g.SRID = srid
+	return g, nil
+}
+
+type wktParser struct {
+	s   string
+	pos int
+}
+
+func (p *wktParser) parse() (Geometry, error) {
+	var g Geometry
+	switch word := strings.ToUpper(p.word()); word {
+	case "POINT":
+		g.Type = TypePoint
+		if err := p.expect('('); err != nil {
+			return g, err
+		}
+		pt, err := p.point()
+		if err != nil {
+			return g, err
+		}
+		g.Points = []Point{pt}
+		if err := p.expect(')'); err != nil {
+			return g, err
+		}
+	case "LINESTRING":
+		g.Type = TypeLineString
+		points, err := p.points()
+		if err != nil {
+			return g, err
+		}
+		g.Points = points
+	case "POLYGON":
+		g.Type = TypePolygon
+		if err := p.expect('('); err != nil {
+			return g, err
+		}
+		for {
+			ring, err := p.points()
+			if err != nil {
+				return g, err
+			}
+			g.Rings = append(g.Rings, ring)
+			if !p.consume(',') {
+				break
+			}
+		}
+		if err := p.expect(')'); err != nil {
+			return g, err
+		}
+	default:
+		return g, errors.Errorf("unsupported geometry type %q", word)
+	}
+	p.skipSpaces()
+	if p.pos != len(p.s) {
+		return g, errors.Errorf("unexpected trailing characters at %d", p.pos)
+	}
+	return g, g.validate()
+}
+
+func (p *wktParser) skipSpaces() {
+	for p.pos < len(p.s) && isSpace(p.s[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *wktParser) word() string {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.s) && isLetter(p.s[p.pos]) {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *wktParser) consume(ch byte) bool {
+	p.skipSpaces()
+	if p.pos < len(p.s) && p.s[p.pos] == ch {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *wktParser) expect(ch byte) error {
+	if !p.consume(ch) {
+		return errors.Errorf("expect '%c' at %d", ch, p.pos)
+	}
+	return nil
+}
+
+func (p *wktParser) number() (float64, error) {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.s) && !isSpace(p.s[p.pos]) && p.s[p.pos] != ',' && p.s[p.pos] != ')' {
+		p.pos++
+	}
+	f, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, errors.Errorf("invalid number %q", p.s[start:p.pos])
+	}
+	return f, nil
+}
+
+func (p *wktParser) point() (Point, error) {
+	x, err := p.number()
+	if err != nil {
+		return Point{}, err
+	}
+	y, err := p.number()
+	if err != nil {
+		return Point{}, err
+	}
+	return Point{X: x, Y: y}, nil
+}
+
+// points parses a parenthesized, comma separated point list.
+func (p *wktParser) points() ([]Point, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	var points []Point
+	for {
+		pt, err := p.point()
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, pt)
+		if !p.consume(',') {
+			break
+		}
+	}
+	return points, p.expect(')')
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// WKT returns the well-known text representation of the geometry.
+func (g Geometry) WKT() string {
+	var sb strings.Builder
+	sb.WriteString(g.Type.String())
+	sb.WriteByte('(')
+	switch g.Type {
+	case TypePoint:
+		writePoint(&sb, g.Points[0])
+	case TypeLineString:
+		writePoints(&sb, g.Points)
+	case TypePolygon:
+		for i, ring := range g.Rings {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteByte('(')
+			writePoints(&sb, ring)
+			sb.WriteByte(')')
+		}
+	}
+	sb.WriteByte(')')
+	return sb.String()
+}
+
+func writePoint(sb *strings.Builder, p Point) {
+	sb.WriteString(formatCoordinate(p.X))
+	sb.WriteByte(' ')
+	sb.WriteString(formatCoordinate(p.Y))
+}
+
+func writePoints(sb *strings.Builder, points []Point) {
+	for i, p := range points {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		writePoint(sb, p)
+	}
+}
+
+func formatCoordinate(f float64) string {
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-15 || abs >= 1e15) {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -68,6 +68,15 @@ func GetDDLInfo(txn kv.Transaction) (*DDLInfo, error) {
 	if addIdxJob != nil {
 		info.Jobs = append(info.Jobs, addIdxJob)
 	}
+	for i := 0; i < meta.TableJobListKeyCnt; i++ {
+		tableJob, err := t.GetDDLJobByIdx(0, meta.TableJobListKey(i))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if tableJob != nil {
+			info.Jobs = append(info.Jobs, tableJob)
+		}
+	}
 
 	info.SchemaVer, err = t.GetSchemaVersion()
 	if err != nil {
@@ -110,11 +119,14 @@ func IsJobRollbackable(job *model.Job) bool {
 		}
 	case model.ActionAddTablePartition:
 		return job.SchemaState == model.StateNone || job.SchemaState == model.StateReplicaOnly
+	case model.ActionModifyTableCharsetAndCollate:
+		// The job is done when its schema state is public.
+		return job.SchemaState != model.StatePublic
 	case model.ActionDropColumn, model.ActionDropColumns, model.ActionDropTablePartition,
 		model.ActionRebaseAutoID, model.ActionShardRowID,
 		model.ActionTruncateTable, model.ActionAddForeignKey,
 		model.ActionDropForeignKey, model.ActionRenameTable,
-		model.ActionModifyTableCharsetAndCollate, model.ActionTruncateTablePartition,
+		model.ActionTruncateTablePartition,
 		model.ActionModifySchemaCharsetAndCollate, model.ActionRepairTable, model.ActionModifyTableAutoIdCache:
 		return job.SchemaState == model.StateNone
 	}
@@ -129,15 +141,10 @@ func CancelJobs(txn kv.Transaction, ids []int64) ([]error, error) {
 
 	errs := make([]error, len(ids))
 	t := meta.NewMeta(txn)
-	generalJobs, err := getDDLJobsInQueue(t, meta.DefaultJobListKey)
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-	addIdxJobs, err := getDDLJobsInQueue(t, meta.AddIndexJobListKey)
+	jobs, jobListKeys, offsets, err := getAllDDLJobsInQueues(t)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	jobs := append(generalJobs, addIdxJobs...)
 
 	for i, id := range ids {
 		found := false
@@ -170,12 +177,78 @@ func CancelJobs(txn kv.Transaction, ids []int64) ([]error, error) {
 				errs[i] = errors.Trace(err)
 				continue
 			}
-			if job.Type == model.ActionAddIndex || job.Type == model.ActionAddPrimaryKey {
-				offset := int64(j - len(generalJobs))
-				err = t.UpdateDDLJob(offset, job, true, meta.AddIndexJobListKey)
-			} else {
-				err = t.UpdateDDLJob(int64(j), job, true)
+			err = t.UpdateDDLJob(offsets[j], job, true, jobListKeys[j])
+			if err != nil {
+				errs[i] = errors.Trace(err)
+			}
+		}
+		if !found {
+			errs[i] = ErrDDLJobNotFound.GenWithStackByArgs(id)
+		}
+	}
+	return errs, nil
+}
+
+// PauseJobs pauses the DDL jobs. The paused reorg job continues from its reorg progress after it's resumed,
+// instead of being rolled back like the cancelled one.
+func PauseJobs(txn kv.Transaction, ids []int64) ([]error, error) {
+	return updateDDLJobsState(txn, ids, func(job *model.Job) (bool, error) {
+		switch job.State {
+		case model.JobStateNone, model.JobStateRunning:
+			job.State = model.JobStatePausing
+			return true, nil
+		case model.JobStatePausing, model.JobStatePaused:
+			return false, nil
+		default:
+			return false, ErrCannotPauseDDLJob.GenWithStackByArgs(job.ID, job.State)
+		}
+	})
+}
+
+// ResumeJobs resumes the paused DDL jobs.
+func ResumeJobs(txn kv.Transaction, ids []int64) ([]error, error) {
+	return updateDDLJobsState(txn, ids, func(job *model.Job) (bool, error) {
+		if !job.IsPausing() && !job.IsPaused() {
+			return false, ErrCannotResumeDDLJob.GenWithStackByArgs(job.ID, job.State)
+		}
+		job.State = model.JobStateRunning
+		return true, nil
+	})
+}
+
+// updateDDLJobsState updates the states of the DDL jobs in the queues by fn,
+// fn returns whether the job needs to be updated.
+func updateDDLJobsState(txn kv.Transaction, ids []int64, fn func(job *model.Job) (bool, error)) ([]error, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	errs := make([]error, len(ids))
+	t := meta.NewMeta(txn)
+	jobs, jobListKeys, offsets, err := getAllDDLJobsInQueues(t)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	for i, id := range ids {
+		found := false
+		for j, job := range jobs {
+			if id != job.ID {
+				continue
+			}
+			found = true
+			changed, err := fn(job)
+			if err != nil || !changed {
+				errs[i] = err
+				continue
 			}
+			// Make sure RawArgs isn't overwritten.
+			err = json.Unmarshal(job.RawArgs, &job.Args)
+			if err != nil {
+				errs[i] = errors.Trace(err)
+				continue
+			}
+			err = t.UpdateDDLJob(offsets[j], job, true, jobListKeys[j])
 			if err != nil {
 				errs[i] = errors.Trace(err)
 			}
@@ -202,18 +275,35 @@ func getDDLJobsInQueue(t *meta.Meta, jobListKey meta.JobListKeyType) ([]*model.J
 	return jobs, nil
 }
 
+// getAllDDLJobsInQueues gets the jobs in all the DDL job queues,
+// it also returns the queue and the offset in the queue of every job.
+func getAllDDLJobsInQueues(t *meta.Meta) ([]*model.Job, []meta.JobListKeyType, []int64, error) {
+	var (
+		jobs        []*model.Job
+		jobListKeys []meta.JobListKeyType
+		offsets     []int64
+	)
+	for _, jobListKey := range meta.AllJobListKeys() {
+		queueJobs, err := getDDLJobsInQueue(t, jobListKey)
+		if err != nil {
+			return nil, nil, nil, errors.Trace(err)
+		}
+		for i, job := range queueJobs {
+			jobs = append(jobs, job)
+			jobListKeys = append(jobListKeys, jobListKey)
+			offsets = append(offsets, int64(i))
+		}
+	}
+	return jobs, jobListKeys, offsets, nil
+}
+
 // GetDDLJobs get all DDL jobs and sorts jobs by job.ID.
 func GetDDLJobs(txn kv.Transaction) ([]*model.Job, error) {
 	t := meta.NewMeta(txn)
-	generalJobs, err := getDDLJobsInQueue(t, meta.DefaultJobListKey)
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-	addIdxJobs, err := getDDLJobsInQueue(t, meta.AddIndexJobListKey)
+	jobs, _, _, err := getAllDDLJobsInQueues(t)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	jobs := append(generalJobs, addIdxJobs...)
 	sort.Sort(jobArray(jobs))
 	return jobs, nil
 }
@@ -488,6 +578,10 @@ var (
 	ErrCancelFinishedDDLJob = dbterror.ClassAdmin.NewStd(errno.ErrCancelFinishedDDLJob)
 	// ErrCannotCancelDDLJob returns when cancel a almost finished ddl job, because cancel in now may cause data inconsistency.
 	ErrCannotCancelDDLJob = dbterror.ClassAdmin.NewStd(errno.ErrCannotCancelDDLJob)
+	// ErrCannotPauseDDLJob returns when pause a DDL job which isn't running, such as a finished or rolling back job.
+	ErrCannotPauseDDLJob = dbterror.ClassAdmin.NewStd(errno.ErrCannotPauseDDLJob)
+	// ErrCannotResumeDDLJob returns when resume a DDL job which isn't paused.
+	ErrCannotResumeDDLJob = dbterror.ClassAdmin.NewStd(errno.ErrCannotResumeDDLJob)
 	// ErrAdminCheckTable returns when the table records is inconsistent with the index values.
 	ErrAdminCheckTable = dbterror.ClassAdmin.NewStd(errno.ErrAdminCheckTable)
 )
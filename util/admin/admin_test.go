@@ -308,6 +308,65 @@ func (s *testSuite) TestCancelJobs(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (s *testSuite) TestPauseAndResumeJobs(c *C) {
+	txn, err := s.store.Begin()
+	c.Assert(err, IsNil)
+	t := meta.NewMeta(txn)
+	states := []model.JobState{model.JobStateRunning, model.JobStateNone, model.JobStateDone, model.JobStateRollingback}
+	for i, state := range states {
+		job := &model.Job{
+			ID:       int64(i),
+			SchemaID: 1,
+			Type:     model.ActionCreateTable,
+			State:    state,
+		}
+		err = t.EnQueueDDLJob(job)
+		c.Assert(err, IsNil)
+	}
+	addIdxJob := &model.Job{
+		ID:       int64(len(states)),
+		SchemaID: 1,
+		Type:     model.ActionAddIndex,
+		State:    model.JobStateRunning,
+	}
+	err = t.EnQueueDDLJob(addIdxJob, meta.AddIndexJobListKey)
+	c.Assert(err, IsNil)
+
+	errs, err := PauseJobs(txn, []int64{0, 1, 2, 3, 4, -1})
+	c.Assert(err, IsNil)
+	c.Assert(errs[0], IsNil)
+	c.Assert(errs[1], IsNil)
+	c.Assert(errs[2].Error(), Matches, "*This job:2 is done, can't be paused now")
+	c.Assert(errs[3].Error(), Matches, "*This job:3 is rollingback, can't be paused now")
+	c.Assert(errs[4], IsNil)
+	c.Assert(errs[5].Error(), Matches, "*DDL Job:-1 not found")
+	jobs, err := GetDDLJobs(txn)
+	c.Assert(err, IsNil)
+	for _, job := range jobs {
+		c.Assert(job.IsPausing() || job.IsPaused(), Equals, job.ID == 0 || job.ID == 1 || job.ID == 4)
+	}
+	c.Assert(jobs[0].State.String(), Equals, "pausing")
+
+	// Pausing a paused job is a no-op.
+	errs, err = PauseJobs(txn, []int64{0})
+	c.Assert(err, IsNil)
+	c.Assert(errs[0], IsNil)
+
+	errs, err = ResumeJobs(txn, []int64{0, 2, 4})
+	c.Assert(err, IsNil)
+	c.Assert(errs[0], IsNil)
+	c.Assert(errs[1].Error(), Matches, "*This job:2 is done, only the paused job can be resumed")
+	c.Assert(errs[2], IsNil)
+	jobs, err = GetDDLJobs(txn)
+	c.Assert(err, IsNil)
+	c.Assert(jobs[0].State, Equals, model.JobStateRunning)
+	c.Assert(jobs[1].IsPaused() || jobs[1].IsPausing(), IsTrue)
+	c.Assert(jobs[4].State, Equals, model.JobStateRunning)
+
+	err = txn.Rollback()
+	c.Assert(err, IsNil)
+}
+
 func (s *testSuite) TestGetHistoryDDLJobs(c *C) {
 	txn, err := s.store.Begin()
 	c.Assert(err, IsNil)
@@ -311,6 +311,7 @@ func (a *SpillDiskAction) Action(t *memory.Tracker) {
 		a.once.Do(func() {
 			logutil.BgLogger().Info("memory exceeds quota, spill to disk now.",
 				zap.Int64("consumed", t.BytesConsumed()), zap.Int64("quota", t.GetBytesLimit()))
+			t.RecordTriggeredAction(memory.ActionNameSpill)
 			if a.testSyncInputFunc != nil {
 				a.testSyncInputFunc()
 				c := a.c
@@ -506,6 +507,7 @@ func (a *SortAndSpillDiskAction) Action(t *memory.Tracker) {
 		a.once.Do(func() {
 			logutil.BgLogger().Info("memory exceeds quota, spill to disk now.",
 				zap.Int64("consumed", t.BytesConsumed()), zap.Int64("quota", t.GetBytesLimit()))
+			t.RecordTriggeredAction(memory.ActionNameSpill)
 			if a.testSyncInputFunc != nil {
 				a.testSyncInputFunc()
 				c := a.c
@@ -0,0 +1,136 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deadlockhistory
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// DefaultCapacity is the default max number of the deadlock records kept in memory.
+const DefaultCapacity = 10
+
+// GlobalDeadlockHistory records the recent deadlocks met by the transactions on this tidb-server.
+var GlobalDeadlockHistory = NewDeadlockHistory(DefaultCapacity)
+
+// WaitChainItem is an edge of the wait chain of a deadlock: the transaction TryLockTxn tries to lock Key, which is
+// held by the transaction TxnHoldingLock. Key and TxnHoldingLock are empty if they are unknown.
+type WaitChainItem struct {
+	TryLockTxn     uint64
+	SQLDigest      string
+	SQLDigestText  string
+	Key            []byte
+	TxnHoldingLock uint64
+}
+
+// DeadlockRecord is a deadlock met by a transaction.
+type DeadlockRecord struct {
+	// ID is assigned when the record is pushed into the history, it starts from 1.
+	ID          uint64
+	OccurTime   time.Time
+	IsRetryable bool
+	WaitChain   []WaitChainItem
+}
+
+// Digest returns the digest of the wait chain, which is the same for the deadlocks on the same keys caused by the
+// same statements, so it can be used to find out the deadlocks happen again and again.
+func (r *DeadlockRecord) Digest() string {
+	hasher := sha256.New()
+	var buf [8]byte
+	for _, item := range r.WaitChain {
+		hasher.Write([]byte(item.SQLDigest))
+		binary.BigEndian.PutUint64(buf[:], uint64(len(item.Key)))
+		hasher.Write(buf[:])
+		hasher.Write(item.Key)
+	}
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// DeadlockHistory is a ring buffer of the recent deadlock records.
+type DeadlockHistory struct {
+	sync.Mutex
+	records []*DeadlockRecord
+	// head is the index of the oldest record, size is the number of the records.
+	head   int
+	size   int
+	nextID uint64
+}
+
+// NewDeadlockHistory creates a DeadlockHistory which keeps at most capacity records.
+func NewDeadlockHistory(capacity uint) *DeadlockHistory {
+	return &DeadlockHistory{records: make([]*DeadlockRecord, capacity), nextID: 1}
+}
+
+// Push assigns the ID to the record and adds it into the history, the oldest record is dropped if the history is
+// full. The record is not kept if the capacity is 0, but it still gets an ID.
+func (d *DeadlockHistory) Push(record *DeadlockRecord) {
+	d.Lock()
+	defer d.Unlock()
+	record.ID = d.nextID
+	d.nextID++
+	capacity := len(d.records)
+	if capacity == 0 {
+		return
+	}
+	d.records[(d.head+d.size)%capacity] = record
+	if d.size == capacity {
+		d.head = (d.head + 1) % capacity
+	} else {
+		d.size++
+	}
+}
+
+// GetAll returns the records from the oldest to the newest.
+func (d *DeadlockHistory) GetAll() []*DeadlockRecord {
+	d.Lock()
+	defer d.Unlock()
+	return d.getAll()
+}
+
+func (d *DeadlockHistory) getAll() []*DeadlockRecord {
+	records := make([]*DeadlockRecord, 0, d.size)
+	for i := 0; i < d.size; i++ {
+		records = append(records, d.records[(d.head+i)%len(d.records)])
+	}
+	return records
+}
+
+// Resize changes the capacity of the history, the oldest records are dropped if there are more than capacity.
+func (d *DeadlockHistory) Resize(capacity uint) {
+	d.Lock()
+	defer d.Unlock()
+	if int(capacity) == len(d.records) {
+		return
+	}
+	records := d.getAll()
+	if len(records) > int(capacity) {
+		records = records[len(records)-int(capacity):]
+	}
+	d.records = make([]*DeadlockRecord, capacity)
+	copy(d.records, records)
+	d.head, d.size = 0, len(records)
+}
+
+// Clear removes all the records.
+func (d *DeadlockHistory) Clear() {
+	d.Lock()
+	defer d.Unlock()
+	for i := range d.records {
+		d.records[i] = nil
+	}
+	d.head, d.size = 0, 0
+}
@@ -0,0 +1,84 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deadlockhistory
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testDeadlockHistorySuite{})
+
+type testDeadlockHistorySuite struct {
+}
+
+func getIDs(h *DeadlockHistory) []uint64 {
+	var ids []uint64
+	for _, record := range h.GetAll() {
+		ids = append(ids, record.ID)
+	}
+	return ids
+}
+
+func (s *testDeadlockHistorySuite) TestDeadlockHistory(c *C) {
+	h := NewDeadlockHistory(3)
+	c.Assert(h.GetAll(), HasLen, 0)
+	for i := 0; i < 2; i++ {
+		h.Push(&DeadlockRecord{})
+	}
+	c.Assert(getIDs(h), DeepEquals, []uint64{1, 2})
+
+	// The oldest records are dropped when the history is full.
+	for i := 0; i < 3; i++ {
+		h.Push(&DeadlockRecord{})
+	}
+	c.Assert(getIDs(h), DeepEquals, []uint64{3, 4, 5})
+
+	h.Resize(2)
+	c.Assert(getIDs(h), DeepEquals, []uint64{4, 5})
+	h.Resize(4)
+	h.Push(&DeadlockRecord{})
+	c.Assert(getIDs(h), DeepEquals, []uint64{4, 5, 6})
+
+	h.Clear()
+	c.Assert(h.GetAll(), HasLen, 0)
+	// The records are not kept if the capacity is 0, but the IDs are still assigned.
+	h.Resize(0)
+	record := &DeadlockRecord{}
+	h.Push(record)
+	c.Assert(record.ID, Equals, uint64(7))
+	c.Assert(h.GetAll(), HasLen, 0)
+}
+
+func (s *testDeadlockHistorySuite) TestDigest(c *C) {
+	r1 := &DeadlockRecord{WaitChain: []WaitChainItem{
+		{TryLockTxn: 1, SQLDigest: "digest1", Key: []byte("k1"), TxnHoldingLock: 2},
+		{TryLockTxn: 2, SQLDigest: "digest2"},
+	}}
+	// The digest doesn't depend on the transactions.
+	r2 := &DeadlockRecord{ID: 2, WaitChain: []WaitChainItem{
+		{TryLockTxn: 3, SQLDigest: "digest1", Key: []byte("k1"), TxnHoldingLock: 4},
+		{TryLockTxn: 4, SQLDigest: "digest2"},
+	}}
+	c.Assert(r1.Digest(), Equals, r2.Digest())
+	c.Assert(r1.Digest(), HasLen, 64)
+
+	r2.WaitChain[0].Key = []byte("k2")
+	c.Assert(r1.Digest(), Not(Equals), r2.Digest())
+}
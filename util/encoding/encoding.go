@@ -0,0 +1,322 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoding
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/util/dbterror"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/transform"
+)
+
+// CharsetGBK is the name of the gbk character set, which isn't supported by the parser yet.
+const CharsetGBK = "gbk"
+
+var (
+	// ErrInvalidCharacterString is returned when a string contains a byte sequence which is invalid
+	// in its character set, or a character which can't be represented in the target character set.
+	ErrInvalidCharacterString = dbterror.ClassTypes.NewStd(mysql.ErrInvalidCharacterString)
+	// ErrUnknownCharacterSet is returned when the character set isn't supported by the conversions.
+	ErrUnknownCharacterSet = dbterror.ClassTypes.NewStd(mysql.ErrUnknownCharacterSet)
+)
+
+// InvalidCharHandling is how a conversion handles the invalid byte sequences of the source
+// character set and the characters which can't be represented in the target one.
+type InvalidCharHandling int
+
+const (
+	// InvalidCharReplace replaces each of them by '?'.
+	InvalidCharReplace InvalidCharHandling = iota
+	// InvalidCharError fails the conversion with ErrInvalidCharacterString.
+	InvalidCharError
+)
+
+// encoding converts the characters of a character set from and to UTF-8, which is the representation
+// of all the non-binary strings in TiDB. Each method handles the first character in src and returns
+// its length in src, ok is false if the character is invalid, then nothing is appended to dst.
+type encoding interface {
+	// toUTF8 decodes the first character in src, which is encoded in the character set, to UTF-8.
+	toUTF8(dst, src []byte) (_ []byte, n int, ok bool)
+	// fromUTF8 encodes the first UTF-8 character in src in the character set.
+	fromUTF8(dst, src []byte) (_ []byte, n int, ok bool)
+	// validUTF8 checks the first UTF-8 character in src can be represented in the character set.
+	validUTF8(src []byte) (n int, ok bool)
+}
+
+// encodingInfo describes a character set supported by the conversions.
+type encodingInfo struct {
+	newEncoding func() encoding
+	// utf8Compatible is true if the characters are encoded the same as UTF-8 in the character set,
+	// so the strings don't need to be transcoded when they are read or written.
+	utf8Compatible bool
+	// covers reports whether all the characters of the character set from can be represented.
+	covers func(from string) bool
+}
+
+// encodings is the table of the character sets supported by the conversions. The binary character set
+// isn't in it, since its strings are the raw bytes.
+var encodings = map[string]*encodingInfo{
+	charset.CharsetUTF8MB4: {
+		newEncoding:    func() encoding { return utf8Encoding{maxLen: utf8.UTFMax} },
+		utf8Compatible: true,
+		covers:         func(string) bool { return true },
+	},
+	charset.CharsetUTF8: {
+		newEncoding:    func() encoding { return utf8Encoding{maxLen: 3} },
+		utf8Compatible: true,
+		covers: func(from string) bool {
+			return from == charset.CharsetUTF8 || from == charset.CharsetASCII
+		},
+	},
+	charset.CharsetASCII: {
+		newEncoding:    func() encoding { return utf8Encoding{maxLen: 1} },
+		utf8Compatible: true,
+		covers:         func(from string) bool { return from == charset.CharsetASCII },
+	},
+	// TiDB has always stored the latin1 strings as they are given, which are UTF-8 in most cases,
+	// so latin1 is kept as an alias of UTF-8 without any check for the compatibility.
+	charset.CharsetLatin1: {
+		newEncoding:    func() encoding { return latin1Encoding{} },
+		utf8Compatible: true,
+		covers:         func(string) bool { return true },
+	},
+	CharsetGBK: {
+		newEncoding: func() encoding {
+			return &gbkEncoding{
+				decoder: simplifiedchinese.GBK.NewDecoder(),
+				encoder: simplifiedchinese.GBK.NewEncoder(),
+			}
+		},
+		covers: func(from string) bool { return from == CharsetGBK || from == charset.CharsetASCII },
+	},
+}
+
+// IsSupported reports whether the character set is supported by the conversions.
+func IsSupported(name string) bool {
+	name = strings.ToLower(name)
+	return name == charset.CharsetBin || encodings[name] != nil
+}
+
+// IsUTF8Compatible reports whether the characters are encoded the same as UTF-8 in the character set,
+// the strings of such character sets are read and written without transcoding.
+func IsUTF8Compatible(name string) bool {
+	info := encodings[strings.ToLower(name)]
+	return info == nil || info.utf8Compatible
+}
+
+// NeedConvert reports whether converting the strings from one character set to another is not a no-op.
+func NeedConvert(from, to string) bool {
+	from, to = strings.ToLower(from), strings.ToLower(to)
+	if from == to {
+		return false
+	}
+	if to == charset.CharsetBin {
+		return !IsUTF8Compatible(from)
+	}
+	toInfo := encodings[to]
+	if toInfo == nil {
+		return false
+	}
+	if from == charset.CharsetBin {
+		// The binary strings are reinterpreted in the target character set, they are checked unless it's latin1.
+		return to != charset.CharsetLatin1
+	}
+	return !toInfo.covers(from)
+}
+
+// Converter converts the strings from one character set to another. Converting a non-binary string
+// to binary encodes it in its character set; converting a binary string to a non-binary character
+// set decodes it in that character set; converting between two non-binary character sets checks
+// that each character can be represented in the target one. A Converter can be used concurrently.
+type Converter struct {
+	fromName, toName string
+	// from and to are nil for the binary character set.
+	from, to    encoding
+	needConvert bool
+}
+
+// NewConverter creates a Converter from the character set from to to.
+func NewConverter(from, to string) (*Converter, error) {
+	c := &Converter{
+		fromName:    strings.ToLower(from),
+		toName:      strings.ToLower(to),
+		needConvert: NeedConvert(from, to),
+	}
+	var err error
+	if c.from, err = newEncoding(c.fromName); err != nil {
+		return nil, err
+	}
+	if c.to, err = newEncoding(c.toName); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func newEncoding(name string) (encoding, error) {
+	if name == charset.CharsetBin {
+		return nil, nil
+	}
+	info := encodings[name]
+	if info == nil {
+		return nil, ErrUnknownCharacterSet.GenWithStackByArgs(name)
+	}
+	return info.newEncoding(), nil
+}
+
+// Convert converts src and appends the result to dst. If src contains an invalid byte sequence or
+// a character which can't be represented, Convert returns ErrInvalidCharacterString as err if handling
+// is InvalidCharError; if handling is InvalidCharReplace, it's replaced by '?' and the error is returned
+// as warn, which should be reported by the caller.
+func (c *Converter) Convert(dst, src []byte, handling InvalidCharHandling) (_ []byte, warn error, err error) {
+	if !c.needConvert {
+		return append(dst, src...), nil, nil
+	}
+	for len(src) > 0 {
+		var (
+			n         int
+			ok        bool
+			checkedBy string
+		)
+		switch {
+		case c.from == nil:
+			dst, n, ok = c.to.toUTF8(dst, src)
+			checkedBy = c.toName
+		case c.to == nil:
+			dst, n, ok = c.from.fromUTF8(dst, src)
+			checkedBy = c.fromName
+		default:
+			if n, ok = c.to.validUTF8(src); ok {
+				dst = append(dst, src[:n]...)
+			}
+			checkedBy = c.toName
+		}
+		if !ok {
+			invalid := ErrInvalidCharacterString.GenWithStackByArgs(checkedBy, fmt.Sprintf("%X", src[:n]))
+			if handling == InvalidCharError {
+				return nil, nil, invalid
+			}
+			if warn == nil {
+				warn = invalid
+			}
+			dst = append(dst, '?')
+		}
+		src = src[n:]
+	}
+	return dst, warn, nil
+}
+
+// utf8Encoding is the encoding of the Unicode character sets whose characters are at most maxLen
+// bytes in UTF-8, ascii is treated as one of them.
+type utf8Encoding struct {
+	maxLen int
+}
+
+func (e utf8Encoding) toUTF8(dst, src []byte) ([]byte, int, bool) {
+	n, ok := e.validUTF8(src)
+	if !ok {
+		return dst, n, false
+	}
+	return append(dst, src[:n]...), n, true
+}
+
+func (e utf8Encoding) fromUTF8(dst, src []byte) ([]byte, int, bool) {
+	return e.toUTF8(dst, src)
+}
+
+func (e utf8Encoding) validUTF8(src []byte) (int, bool) {
+	r, n := utf8.DecodeRune(src)
+	if r == utf8.RuneError && n <= 1 {
+		return 1, false
+	}
+	return n, n <= e.maxLen
+}
+
+// latin1Encoding keeps the bytes as they are, see the comment in encodings.
+type latin1Encoding struct{}
+
+func (latin1Encoding) toUTF8(dst, src []byte) ([]byte, int, bool) {
+	return append(dst, src[0]), 1, true
+}
+
+func (latin1Encoding) fromUTF8(dst, src []byte) ([]byte, int, bool) {
+	return append(dst, src[0]), 1, true
+}
+
+func (latin1Encoding) validUTF8([]byte) (int, bool) {
+	return 1, true
+}
+
+// gbkEncoding is the encoding of gbk, in which a character is either an ASCII byte or two bytes.
+// The transformers of gbk are stateless, so it can be used concurrently.
+type gbkEncoding struct {
+	decoder transform.Transformer
+	encoder transform.Transformer
+}
+
+// transformChar transforms a character by t and appends the result to dst, without allocating
+// a temporary buffer.
+func transformChar(t transform.Transformer, dst, char []byte) ([]byte, int, error) {
+	if cap(dst)-len(dst) < utf8.UTFMax {
+		newDst := make([]byte, len(dst), 2*cap(dst)+utf8.UTFMax)
+		copy(newDst, dst)
+		dst = newDst
+	}
+	nDst, _, err := t.Transform(dst[len(dst):len(dst)+utf8.UTFMax], char, true)
+	return dst, nDst, err
+}
+
+func (e *gbkEncoding) toUTF8(dst, src []byte) ([]byte, int, bool) {
+	if src[0] < utf8.RuneSelf {
+		return append(dst, src[0]), 1, true
+	}
+	// The lead byte is in [0x81, 0xFE], the trail byte is in [0x40, 0xFE] except 0x7F.
+	if src[0] == 0x80 || src[0] == 0xFF || len(src) < 2 || src[1] < 0x40 || src[1] == 0x7F || src[1] == 0xFF {
+		return dst, 1, false
+	}
+	dst, nDst, err := transformChar(e.decoder, dst, src[:2])
+	if err != nil {
+		return dst, 1, false
+	}
+	if r, _ := utf8.DecodeRune(dst[len(dst) : len(dst)+nDst]); r == utf8.RuneError {
+		return dst, 2, false
+	}
+	return dst[:len(dst)+nDst], 2, true
+}
+
+func (e *gbkEncoding) fromUTF8(dst, src []byte) ([]byte, int, bool) {
+	r, n := utf8.DecodeRune(src)
+	if r == utf8.RuneError && n <= 1 {
+		return dst, 1, false
+	}
+	if r < utf8.RuneSelf {
+		return append(dst, src[0]), 1, true
+	}
+	dst, nDst, err := transformChar(e.encoder, dst, src[:n])
+	// The single byte 0x80, which is the euro sign in the code page 936, isn't a gbk character.
+	if err != nil || nDst != 2 {
+		return dst, n, false
+	}
+	return dst[:len(dst)+nDst], n, true
+}
+
+func (e *gbkEncoding) validUTF8(src []byte) (int, bool) {
+	var buf [utf8.UTFMax]byte
+	_, n, ok := e.fromUTF8(buf[:0], src)
+	return n, ok
+}
@@ -0,0 +1,122 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoding
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/util/testleak"
+)
+
+func TestT(t *testing.T) {
+	CustomVerboseFlag = true
+	TestingT(t)
+}
+
+var _ = Suite(&testEncodingSuite{})
+
+type testEncodingSuite struct {
+}
+
+func (s *testEncodingSuite) TestNeedConvert(c *C) {
+	defer testleak.AfterTest(c)()
+	table := []struct {
+		from, to string
+		expect   bool
+	}{
+		{"utf8mb4", "utf8mb4", false},
+		{"UTF8MB4", "utf8mb4", false},
+		{"binary", "binary", false},
+		{"utf8", "utf8mb4", false},
+		{"ascii", "utf8", false},
+		{"gbk", "utf8mb4", false},
+		{"utf8mb4", "latin1", false},
+		{"utf8mb4", "utf8", true},
+		{"utf8", "ascii", true},
+		{"utf8mb4", "gbk", true},
+		{"gbk", "utf8", true},
+		{"utf8mb4", "binary", false},
+		{"latin1", "binary", false},
+		{"gbk", "binary", true},
+		{"binary", "latin1", false},
+		{"binary", "utf8mb4", true},
+		{"binary", "gbk", true},
+	}
+	for _, t := range table {
+		c.Assert(NeedConvert(t.from, t.to), Equals, t.expect, Commentf("%s to %s", t.from, t.to))
+	}
+	c.Assert(IsSupported("GBK"), IsTrue)
+	c.Assert(IsSupported("binary"), IsTrue)
+	c.Assert(IsSupported("koi8r"), IsFalse)
+	c.Assert(IsUTF8Compatible("latin1"), IsTrue)
+	c.Assert(IsUTF8Compatible("gbk"), IsFalse)
+}
+
+func (s *testEncodingSuite) TestConvert(c *C) {
+	defer testleak.AfterTest(c)()
+	table := []struct {
+		from, to string
+		src      string
+		expect   string
+		invalid  bool
+	}{
+		{"binary", "binary", "\xff\x00", "\xff\x00", false},
+		{"utf8mb4", "utf8mb4", "a\xff", "a\xff", false},
+		{"binary", "utf8mb4", "中文😀", "中文😀", false},
+		{"binary", "utf8mb4", "a\xffb\xe4\xb8", "a?b??", true},
+		{"binary", "utf8", "a😀", "a?", true},
+		{"binary", "ascii", "a中", "a?", true},
+		{"binary", "latin1", "\xff", "\xff", false},
+		{"utf8mb4", "utf8", "中😀文", "中?文", true},
+		{"utf8mb4", "ascii", "abc", "abc", false},
+		{"utf8mb4", "ascii", "a中c", "a?c", true},
+		{"utf8mb4", "gbk", "中文", "中文", false},
+		{"utf8mb4", "gbk", "a😀€", "a??", true},
+		{"binary", "gbk", "\xd6\xd0\xce\xc4a", "中文a", false},
+		{"binary", "gbk", "\xd6\xd0\x80\xffa\xd6", "中??a?", true},
+		{"gbk", "binary", "中文a", "\xd6\xd0\xce\xc4a", false},
+		{"gbk", "binary", "中€", "\xd6\xd0?", true},
+		{"utf8mb4", "binary", "中", "中", false},
+	}
+	for _, t := range table {
+		comment := Commentf("%s to %s: %q", t.from, t.to, t.src)
+		conv, err := NewConverter(t.from, t.to)
+		c.Assert(err, IsNil, comment)
+		res, warn, err := conv.Convert(nil, []byte(t.src), InvalidCharReplace)
+		c.Assert(err, IsNil, comment)
+		c.Assert(string(res), Equals, t.expect, comment)
+		c.Assert(warn != nil, Equals, t.invalid, comment)
+		if t.invalid {
+			c.Assert(ErrInvalidCharacterString.Equal(warn), IsTrue, comment)
+		}
+
+		res, warn, err = conv.Convert(nil, []byte(t.src), InvalidCharError)
+		c.Assert(warn, IsNil, comment)
+		if t.invalid {
+			c.Assert(ErrInvalidCharacterString.Equal(err), IsTrue, comment)
+		} else {
+			c.Assert(err, IsNil, comment)
+			c.Assert(string(res), Equals, t.expect, comment)
+		}
+	}
+
+	conv, err := NewConverter("utf8mb4", "ascii")
+	c.Assert(err, IsNil)
+	_, _, err = conv.Convert(nil, []byte("ab中"), InvalidCharError)
+	c.Assert(err, ErrorMatches, ".*Invalid ascii character string: 'E4B8AD'")
+
+	_, err = NewConverter("koi8r", "utf8mb4")
+	c.Assert(ErrUnknownCharacterSet.Equal(err), IsTrue)
+}
@@ -52,6 +52,8 @@ var StmtExecDetailKey = stmtExecDetailKeyType{}
 // StmtExecDetails contains stmt level execution detail info.
 type StmtExecDetails struct {
 	WriteSQLRespDuration time.Duration
+	// WriteSQLRespBytes is the number of bytes of the result sets written to the client.
+	WriteSQLRespBytes uint64
 }
 
 const (
@@ -424,6 +426,8 @@ const (
 	TpIndexMergeRunTimeStats
 	// TpBasicCopRunTimeStats is the tp for TpBasicCopRunTimeStats
 	TpBasicCopRunTimeStats
+	// TpOOMActionRuntimeStats is the tp for OOMActionRuntimeStats
+	TpOOMActionRuntimeStats
 )
 
 // RuntimeStats is used to express the executor runtime information.
@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/txntimeout"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -60,7 +61,15 @@ func (eqh *Handle) Run() {
 		select {
 		case <-ticker.C:
 			processInfo := sm.ShowProcessList()
+			now := time.Now()
 			for _, info := range processInfo {
+				if !info.ExceedTxnTimeout {
+					if reason := txntimeout.Check(info, now); reason != "" {
+						info.ExceedTxnTimeout = true
+						killTxn(sm, info, reason, now)
+						continue
+					}
+				}
 				if len(info.Info) == 0 {
 					continue
 				}
@@ -177,3 +186,16 @@ func genLogFields(costTime time.Duration, info *util.ProcessInfo) []zap.Field {
 func logExpensiveQuery(costTime time.Duration, info *util.ProcessInfo) {
 	logutil.BgLogger().Warn("expensive_query", genLogFields(costTime, info)...)
 }
+
+// killTxn kills the connection whose transaction exceeds the idle timeout or the max lifetime, the transaction is
+// rolled back when the connection is closed.
+func killTxn(sm util.SessionManager, info *util.ProcessInfo, reason string, now time.Time) {
+	txntimeout.GlobalHistory.Push(txntimeout.NewEvent(info, reason, now))
+	logutil.BgLogger().Warn("kill the connection for the transaction timeout",
+		zap.Uint64("conn", info.ID),
+		zap.String("user", info.User),
+		zap.Uint64("txnStartTS", info.CurTxnStartTS),
+		zap.Time("txnStartTime", info.TxnStartTime),
+		zap.String("reason", reason))
+	sm.Kill(info.ID, false)
+}
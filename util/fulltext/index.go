@@ -0,0 +1,41 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fulltext
+
+import (
+	"github.com/pingcap/parser/model"
+)
+
+// IsIndex checks whether the index is a FULLTEXT index. Unlike the B-tree index, the FULLTEXT index is an
+// inverted index: for each distinct word of the indexed columns of a row, there is an entry encoded from the
+// word and the handle of the row. So it can't be used to look up the rows by the values of the columns.
+func IsIndex(idxInfo *model.IndexInfo) bool {
+	return idxInfo.Tp == model.IndexTypeFulltext
+}
+
+// Words returns the distinct words of the texts in the order they appear.
+func Words(texts ...string) []string {
+	seen := make(map[string]struct{})
+	var words []string
+	for _, text := range texts {
+		for _, word := range Tokenize(text) {
+			if _, ok := seen[word]; ok {
+				continue
+			}
+			seen[word] = struct{}{}
+			words = append(words, word)
+		}
+	}
+	return words
+}
@@ -0,0 +1,97 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fulltext
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+const (
+	// MinTokenSize is the minimum length of an indexed word, same as the default innodb_ft_min_token_size.
+	MinTokenSize = 3
+	// MaxTokenSize is the maximum length of an indexed word, same as the default innodb_ft_max_token_size.
+	MaxTokenSize = 84
+)
+
+// stopwords is the default stopword list of InnoDB, see INFORMATION_SCHEMA.INNODB_FT_DEFAULT_STOPWORD.
+var stopwords = map[string]struct{}{
+	"a": {}, "about": {}, "an": {}, "are": {}, "as": {}, "at": {}, "be": {}, "by": {}, "com": {},
+	"de": {}, "en": {}, "for": {}, "from": {}, "how": {}, "i": {}, "in": {}, "is": {}, "it": {},
+	"la": {}, "of": {}, "on": {}, "or": {}, "that": {}, "the": {}, "this": {}, "to": {}, "was": {},
+	"what": {}, "when": {}, "where": {}, "who": {}, "will": {}, "with": {}, "und": {}, "www": {},
+}
+
+// Tokenize splits text into lower-cased words. A word is a run of letters, digits, underscores or apostrophes.
+// Words shorter than MinTokenSize or longer than MaxTokenSize and stopwords are dropped.
+func Tokenize(text string) []string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '\''
+	})
+	tokens := words[:0]
+	for _, word := range words {
+		word = strings.ToLower(strings.Trim(word, "'"))
+		if n := len([]rune(word)); n < MinTokenSize || n > MaxTokenSize {
+			continue
+		}
+		if _, ok := stopwords[word]; ok {
+			continue
+		}
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
+
+// Query is a natural language mode search query.
+type Query struct {
+	terms []string
+}
+
+// NewQuery tokenizes the search string of a natural language mode query.
+func NewQuery(search string) *Query {
+	return &Query{terms: Words(search)}
+}
+
+// Terms returns the distinct words of the search string.
+func (q *Query) Terms() []string {
+	return q.terms
+}
+
+// Relevance returns the relevance of the documents to the query, 0 means not matched.
+// Without corpus statistics every term has the same weight, so the relevance only grows
+// with the number of matched terms and, logarithmically, with their frequencies.
+func (q *Query) Relevance(docs ...string) float64 {
+	if len(q.terms) == 0 {
+		return 0
+	}
+	freq := make(map[string]int, len(q.terms))
+	for _, term := range q.terms {
+		freq[term] = 0
+	}
+	for _, doc := range docs {
+		for _, token := range Tokenize(doc) {
+			if n, ok := freq[token]; ok {
+				freq[token] = n + 1
+			}
+		}
+	}
+	var relevance float64
+	for _, n := range freq {
+		if n > 0 {
+			relevance += math.Log(float64(n)) + 1
+		}
+	}
+	return relevance
+}
@@ -0,0 +1,54 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fulltext
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	. "github.com/pingcap/check"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testFulltextSuite{})
+
+type testFulltextSuite struct{}
+
+func (s *testFulltextSuite) TestTokenize(c *C) {
+	c.Assert(Tokenize("The quick-brown FOX, isn't it? a_b 12345 go"), DeepEquals,
+		[]string{"quick", "brown", "fox", "isn't", "a_b", "12345"})
+	c.Assert(Tokenize("Ünïcode wörds"), DeepEquals, []string{"ünïcode", "wörds"})
+	c.Assert(Tokenize(" ,. "), HasLen, 0)
+}
+
+func (s *testFulltextSuite) TestRelevance(c *C) {
+	q := NewQuery("database Database tidb")
+	c.Assert(q.terms, DeepEquals, []string{"database", "tidb"})
+	c.Assert(q.Relevance("nothing here"), Equals, 0.0)
+	c.Assert(q.Relevance("a database"), Equals, 1.0)
+	c.Assert(q.Relevance("TiDB is a database", "another database"), Equals, 2+math.Log(2))
+
+	c.Assert(NewQuery("the of").Relevance("the of"), Equals, 0.0)
+}
+
+func (s *testFulltextSuite) TestWords(c *C) {
+	c.Assert(Words("TiDB database", "the Database", "tidb"), DeepEquals, []string{"tidb", "database"})
+	c.Assert(Words(strings.Repeat("x", MaxTokenSize), strings.Repeat("y", MaxTokenSize+1)), DeepEquals,
+		[]string{strings.Repeat("x", MaxTokenSize)})
+	c.Assert(Words(), HasLen, 0)
+}
@@ -68,25 +68,48 @@ func ValidateSnapshotWithGCSafePoint(snapshotTS, safePointTS uint64) error {
 	return nil
 }
 
+// ValidateStaleReadTS checks that the read timestamp of the stale read is after GC safe point time. Unlike
+// ValidateSnapshot, it passes if GC safe point hasn't been initialized, which means no data has been GCed yet.
+func ValidateStaleReadTS(ctx sessionctx.Context, readTS uint64) error {
+	safePointTS, exists, err := getGCSafePoint(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !exists {
+		return nil
+	}
+	return ValidateSnapshotWithGCSafePoint(readTS, safePointTS)
+}
+
 // GetGCSafePoint loads GC safe point time from mysql.tidb.
 func GetGCSafePoint(ctx sessionctx.Context) (uint64, error) {
+	ts, exists, err := getGCSafePoint(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, errors.New("can not get 'tikv_gc_safe_point'")
+	}
+	return ts, nil
+}
+
+func getGCSafePoint(ctx sessionctx.Context) (ts uint64, exists bool, err error) {
 	exec := ctx.(sqlexec.RestrictedSQLExecutor)
 	stmt, err := exec.ParseWithParams(context.Background(), selectVariableValueSQL, "tikv_gc_safe_point")
 	if err != nil {
-		return 0, errors.Trace(err)
+		return 0, false, errors.Trace(err)
 	}
 	rows, _, err := exec.ExecRestrictedStmt(context.Background(), stmt)
 	if err != nil {
-		return 0, errors.Trace(err)
+		return 0, false, errors.Trace(err)
 	}
 	if len(rows) != 1 {
-		return 0, errors.New("can not get 'tikv_gc_safe_point'")
+		return 0, false, nil
 	}
 	safePointString := rows[0].GetString(0)
 	safePointTime, err := util.CompatibleParseGCTime(safePointString)
 	if err != nil {
-		return 0, errors.Trace(err)
+		return 0, false, errors.Trace(err)
 	}
-	ts := oracle.GoTimeToTS(safePointTime)
-	return ts, nil
+	return oracle.GoTimeToTS(safePointTime), true, nil
 }
@@ -0,0 +1,215 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/pingcap/errors"
+)
+
+// The classes and the constructed flag of the BER identifier octets.
+const (
+	classUniversal   = 0x00
+	classApplication = 0x40
+	classContext     = 0x80
+	constructed      = 0x20
+)
+
+// The universal tags used by LDAP.
+const (
+	tagBoolean     = 0x01
+	tagInteger     = 0x02
+	tagOctetString = 0x04
+	tagEnumerated  = 0x0a
+	tagSequence    = 0x10 | constructed
+	tagSet         = 0x11 | constructed
+)
+
+// maxPacketLength limits the length of the messages read from the server.
+const maxPacketLength = 16 << 20
+
+// packet is a BER element, the children are decoded if it's constructed.
+type packet struct {
+	tag      byte
+	value    []byte
+	children []*packet
+}
+
+func newPacket(tag byte, value []byte) *packet {
+	return &packet{tag: tag, value: value}
+}
+
+func newConstructed(tag byte, children ...*packet) *packet {
+	return &packet{tag: tag | constructed, children: children}
+}
+
+func newString(tag byte, s string) *packet {
+	return newPacket(tag, []byte(s))
+}
+
+func newInteger(tag byte, n int64) *packet {
+	// The integers are encoded in the minimal two's complement form.
+	b := []byte{byte(n)}
+	for n >= 0x80 || n < -0x80 {
+		n >>= 8
+		b = append([]byte{byte(n)}, b...)
+	}
+	return newPacket(tag, b)
+}
+
+func newBoolean(b bool) *packet {
+	if b {
+		return newPacket(tagBoolean, []byte{0xff})
+	}
+	return newPacket(tagBoolean, []byte{0})
+}
+
+func (p *packet) add(children ...*packet) *packet {
+	p.children = append(p.children, children...)
+	return p
+}
+
+func (p *packet) isConstructed() bool {
+	return p.tag&constructed != 0
+}
+
+// encode returns the BER encoding of the packet, the children of the constructed packets are encoded as the value.
+func (p *packet) encode() []byte {
+	value := p.value
+	if p.isConstructed() {
+		value = nil
+		for _, child := range p.children {
+			value = append(value, child.encode()...)
+		}
+	}
+	b := append([]byte{p.tag}, encodeLength(len(value))...)
+	return append(b, value...)
+}
+
+func encodeLength(n int) []byte {
+	if n < 0x80 {
+		return []byte{byte(n)}
+	}
+	var b []byte
+	for ; n > 0; n >>= 8 {
+		b = append([]byte{byte(n)}, b...)
+	}
+	return append([]byte{0x80 | byte(len(b))}, b...)
+}
+
+func (p *packet) str() string {
+	return string(p.value)
+}
+
+func (p *packet) integer() (int64, error) {
+	if len(p.value) == 0 || len(p.value) > 8 {
+		return 0, errors.Errorf("invalid BER integer of %d bytes", len(p.value))
+	}
+	n := int64(int8(p.value[0]))
+	for _, b := range p.value[1:] {
+		n = n<<8 | int64(b)
+	}
+	return n, nil
+}
+
+// child returns the i-th child of the packet, it fails if the packet doesn't have enough children.
+func (p *packet) child(i int) (*packet, error) {
+	if i >= len(p.children) {
+		return nil, errors.Errorf("malformed LDAP message: tag 0x%02x has %d elements, expect at least %d", p.tag, len(p.children), i+1)
+	}
+	return p.children[i], nil
+}
+
+// readPacket reads a BER element from the reader.
+func readPacket(r *bufio.Reader) (*packet, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	if tag&0x1f == 0x1f {
+		return nil, errors.Errorf("unsupported BER tag 0x%02x", tag)
+	}
+	length, err := readLength(r)
+	if err != nil {
+		return nil, err
+	}
+	value := make([]byte, length)
+	if _, err = io.ReadFull(r, value); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return decodePacket(tag, value)
+}
+
+func readLength(r *bufio.Reader) (int, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if b < 0x80 {
+		return int(b), nil
+	}
+	n := int(b & 0x7f)
+	if n == 0 || n > 4 {
+		return 0, errors.Errorf("unsupported BER length of %d bytes", n)
+	}
+	length := 0
+	for i := 0; i < n; i++ {
+		if b, err = r.ReadByte(); err != nil {
+			return 0, errors.Trace(err)
+		}
+		length = length<<8 | int(b)
+	}
+	if length > maxPacketLength {
+		return 0, errors.Errorf("the LDAP message of %d bytes is too large", length)
+	}
+	return length, nil
+}
+
+// decodePacket decodes the element, whose children are decoded from the value if it's constructed.
+func decodePacket(tag byte, value []byte) (*packet, error) {
+	p := newPacket(tag, value)
+	if !p.isConstructed() {
+		return p, nil
+	}
+	for len(value) > 0 {
+		if len(value) < 2 {
+			return nil, errors.New("truncated BER element")
+		}
+		childTag := value[0]
+		length, n := int(value[1]), 2
+		if length >= 0x80 {
+			size := length & 0x7f
+			if size == 0 || size > 4 || len(value) < 2+size {
+				return nil, errors.New("invalid BER length")
+			}
+			length = 0
+			for _, b := range value[2 : 2+size] {
+				length = length<<8 | int(b)
+			}
+			n += size
+		}
+		if length < 0 || len(value) < n+length {
+			return nil, errors.New("truncated BER element")
+		}
+		child, err := decodePacket(childTag, value[n:n+length])
+		if err != nil {
+			return nil, err
+		}
+		p.children = append(p.children, child)
+		value = value[n+length:]
+	}
+	return p, nil
+}
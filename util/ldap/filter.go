@@ -0,0 +1,185 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// The choices of the search filter, see RFC 4511 section 4.5.1.7.
+const (
+	filterAnd            = classContext | 0
+	filterOr             = classContext | 1
+	filterNot            = classContext | 2
+	filterEquality       = classContext | 3
+	filterSubstrings     = classContext | 4
+	filterGreaterOrEqual = classContext | 5
+	filterLessOrEqual    = classContext | 6
+	filterPresent        = classContext | 7
+	filterApprox         = classContext | 8
+
+	substringInitial = classContext | 0
+	substringAny     = classContext | 1
+	substringFinal   = classContext | 2
+)
+
+// compileFilter compiles the string representation of the search filter defined by RFC 4515, e.g.
+// `(&(objectClass=posixGroup)(memberUid=user))`, into its BER encoding.
+func compileFilter(filter string) (*packet, error) {
+	p, rest, err := parseFilter(filter)
+	if err != nil {
+		return nil, errors.Annotatef(err, "invalid LDAP filter %q", filter)
+	}
+	if rest != "" {
+		return nil, errors.Errorf("invalid LDAP filter %q: unexpected %q", filter, rest)
+	}
+	return p, nil
+}
+
+func parseFilter(s string) (p *packet, rest string, err error) {
+	if !strings.HasPrefix(s, "(") || len(s) < 2 {
+		return nil, "", errors.New("a filter must be enclosed in parentheses")
+	}
+	s = s[1:]
+	switch s[0] {
+	case '&', '|':
+		tag := byte(filterAnd)
+		if s[0] == '|' {
+			tag = filterOr
+		}
+		p, s = newConstructed(tag), s[1:]
+		for strings.HasPrefix(s, "(") {
+			var child *packet
+			if child, s, err = parseFilter(s); err != nil {
+				return nil, "", err
+			}
+			p.add(child)
+		}
+		if len(p.children) == 0 {
+			return nil, "", errors.New("empty filter list")
+		}
+	case '!':
+		var child *packet
+		if child, s, err = parseFilter(s[1:]); err != nil {
+			return nil, "", err
+		}
+		p = newConstructed(filterNot, child)
+	default:
+		end := strings.IndexByte(s, ')')
+		if end < 0 {
+			return nil, "", errors.New("unbalanced parentheses")
+		}
+		if p, err = parseFilterItem(s[:end]); err != nil {
+			return nil, "", err
+		}
+		s = s[end:]
+	}
+	if !strings.HasPrefix(s, ")") {
+		return nil, "", errors.New("unbalanced parentheses")
+	}
+	return p, s[1:], nil
+}
+
+// parseFilterItem parses the simple, present and substrings filters.
+func parseFilterItem(item string) (*packet, error) {
+	eq := strings.IndexByte(item, '=')
+	if eq <= 0 {
+		return nil, errors.Errorf("invalid filter item %q", item)
+	}
+	attr, value := item[:eq], item[eq+1:]
+	tag := byte(filterEquality)
+	switch attr[len(attr)-1] {
+	case '~':
+		tag, attr = filterApprox, attr[:len(attr)-1]
+	case '>':
+		tag, attr = filterGreaterOrEqual, attr[:len(attr)-1]
+	case '<':
+		tag, attr = filterLessOrEqual, attr[:len(attr)-1]
+	}
+	if attr == "" {
+		return nil, errors.Errorf("invalid filter item %q", item)
+	}
+	if tag == filterEquality && value == "*" {
+		return newString(filterPresent, attr), nil
+	}
+	if tag == filterEquality && strings.Contains(value, "*") {
+		parts := strings.Split(value, "*")
+		substrings := newConstructed(tagSequence)
+		for i, part := range parts {
+			if part == "" {
+				continue
+			}
+			v, err := unescapeFilterValue(part)
+			if err != nil {
+				return nil, err
+			}
+			switch i {
+			case 0:
+				substrings.add(newString(substringInitial, v))
+			case len(parts) - 1:
+				substrings.add(newString(substringFinal, v))
+			default:
+				substrings.add(newString(substringAny, v))
+			}
+		}
+		return newConstructed(filterSubstrings, newString(tagOctetString, attr), substrings), nil
+	}
+	v, err := unescapeFilterValue(value)
+	if err != nil {
+		return nil, err
+	}
+	return newConstructed(tag, newString(tagOctetString, attr), newString(tagOctetString, v)), nil
+}
+
+// unescapeFilterValue decodes the `\XX` escapes of the assertion values.
+func unescapeFilterValue(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+3 > len(s) {
+			return "", errors.Errorf("invalid escape in %q", s)
+		}
+		c, err := hex.DecodeString(s[i+1 : i+3])
+		if err != nil {
+			return "", errors.Errorf("invalid escape in %q", s)
+		}
+		b.Write(c)
+		i += 2
+	}
+	return b.String(), nil
+}
+
+// EscapeFilter escapes the special characters of the assertion value in the search filters.
+func EscapeFilter(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '*', '(', ')', '\\', 0:
+			b.WriteByte('\\')
+			b.WriteString(hex.EncodeToString([]byte{c}))
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,366 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ldap implements a minimal LDAPv3 client for the LDAP authentication plugins, which supports the simple and
+// SASL binds, the searches and StartTLS. See RFC 4511 for the protocol.
+package ldap
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+)
+
+// The result codes of the LDAP operations, see RFC 4511 appendix A.
+const (
+	ResultSuccess            = 0
+	ResultSASLBindInProgress = 14
+	ResultInvalidCredentials = 49
+)
+
+// The scopes of the searches.
+const (
+	ScopeBaseObject   = 0
+	ScopeSingleLevel  = 1
+	ScopeWholeSubtree = 2
+)
+
+// The protocol operations of the LDAP messages.
+const (
+	opBindRequest           = classApplication | constructed | 0
+	opBindResponse          = classApplication | constructed | 1
+	opUnbindRequest         = classApplication | 2
+	opSearchRequest         = classApplication | constructed | 3
+	opSearchResultEntry     = classApplication | constructed | 4
+	opSearchResultDone      = classApplication | constructed | 5
+	opSearchResultReference = classApplication | constructed | 19
+	opExtendedRequest       = classApplication | constructed | 23
+	opExtendedResponse      = classApplication | constructed | 24
+
+	bindAuthSimple      = classContext | 0
+	bindAuthSASL        = classContext | constructed | 3
+	bindServerSASLCreds = classContext | 7
+	extendedRequestName = classContext | 0
+
+	protocolVersion = 3
+	startTLSOID     = "1.3.6.1.4.1.1466.20037"
+)
+
+// Error is the failed result of an LDAP operation.
+type Error struct {
+	ResultCode int64
+	Message    string
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("LDAP result code %d", e.ResultCode)
+	}
+	return fmt.Sprintf("LDAP result code %d: %s", e.ResultCode, e.Message)
+}
+
+// IsResultCode checks whether the error is the LDAP result of the code.
+func IsResultCode(err error, code int64) bool {
+	e, ok := errors.Cause(err).(*Error)
+	return ok && e.ResultCode == code
+}
+
+// Entry is an entry returned by the searches.
+type Entry struct {
+	DN         string
+	Attributes []*Attribute
+}
+
+// Attribute is an attribute of the entries.
+type Attribute struct {
+	Name   string
+	Values []string
+}
+
+// Values returns the values of the attribute, the attribute names are case-insensitive.
+func (e *Entry) Values(name string) []string {
+	for _, attr := range e.Attributes {
+		if strings.EqualFold(attr.Name, name) {
+			return attr.Values
+		}
+	}
+	return nil
+}
+
+// SearchRequest is the arguments of a search.
+type SearchRequest struct {
+	BaseDN string
+	Scope  int
+	// Filter is the string representation of the filter defined by RFC 4515.
+	Filter     string
+	Attributes []string
+	// SizeLimit is the maximum number of the entries returned, 0 means no limit.
+	SizeLimit int
+}
+
+// Conn is a connection to the LDAP server, it isn't safe for concurrent use.
+type Conn struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	timeout time.Duration
+	msgID   int64
+}
+
+// Dial connects to the LDAP server at the address, the connection is secured by TLS at once if the TLS config is
+// given, which is the LDAPS protocol. The timeout applies to the dial and every operation.
+func Dial(addr string, tlsConfig *tls.Config, timeout time.Duration) (*Conn, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	var (
+		conn net.Conn
+		err  error
+	)
+	if tlsConfig != nil {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Conn{conn: conn, reader: bufio.NewReader(conn), timeout: timeout}, nil
+}
+
+// StartTLS upgrades the connection to TLS by the StartTLS extended operation.
+func (c *Conn) StartTLS(tlsConfig *tls.Config) error {
+	resp, err := c.roundTrip(newConstructed(opExtendedRequest, newString(extendedRequestName, startTLSOID)))
+	if err != nil {
+		return err
+	}
+	if err = checkResult(resp, opExtendedResponse); err != nil {
+		return err
+	}
+	tlsConn := tls.Client(c.conn, tlsConfig)
+	if err = tlsConn.SetDeadline(c.deadline()); err != nil {
+		return errors.Trace(err)
+	}
+	if err = tlsConn.Handshake(); err != nil {
+		return errors.Trace(err)
+	}
+	c.conn, c.reader = tlsConn, bufio.NewReader(tlsConn)
+	return nil
+}
+
+// Bind authenticates the connection by the simple bind. Note that the bind with an empty password is an
+// unauthenticated bind, which succeeds on most servers, the callers verifying the passwords should reject it.
+func (c *Conn) Bind(dn, password string) error {
+	resp, err := c.roundTrip(newConstructed(opBindRequest,
+		newInteger(tagInteger, protocolVersion),
+		newString(tagOctetString, dn),
+		newString(bindAuthSimple, password)))
+	if err != nil {
+		return err
+	}
+	return checkResult(resp, opBindResponse)
+}
+
+// SASLBind sends a step of the SASL bind, it returns the credentials of the server and whether the bind is done.
+// The bind is in progress if it isn't done, and the next step should send the response to the credentials.
+func (c *Conn) SASLBind(mechanism string, credentials []byte) (serverCredentials []byte, done bool, err error) {
+	sasl := newConstructed(bindAuthSASL, newString(tagOctetString, mechanism))
+	if credentials != nil {
+		sasl.add(newPacket(tagOctetString, credentials))
+	}
+	resp, err := c.roundTrip(newConstructed(opBindRequest,
+		newInteger(tagInteger, protocolVersion),
+		newString(tagOctetString, ""),
+		sasl))
+	if err != nil {
+		return nil, false, err
+	}
+	for _, child := range resp.children {
+		if child.tag == bindServerSASLCreds {
+			serverCredentials = child.value
+		}
+	}
+	err = checkResult(resp, opBindResponse)
+	if IsResultCode(err, ResultSASLBindInProgress) {
+		return serverCredentials, false, nil
+	}
+	return serverCredentials, err == nil, err
+}
+
+// Search returns the entries matching the search request, the references to the other servers are ignored.
+func (c *Conn) Search(req *SearchRequest) ([]*Entry, error) {
+	filter, err := compileFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	attrs := newConstructed(tagSequence)
+	for _, attr := range req.Attributes {
+		attrs.add(newString(tagOctetString, attr))
+	}
+	id, err := c.send(newConstructed(opSearchRequest,
+		newString(tagOctetString, req.BaseDN),
+		newInteger(tagEnumerated, int64(req.Scope)),
+		// Never dereference the aliases.
+		newInteger(tagEnumerated, 0),
+		newInteger(tagInteger, int64(req.SizeLimit)),
+		newInteger(tagInteger, int64(c.timeout/time.Second)),
+		newBoolean(false),
+		filter,
+		attrs))
+	if err != nil {
+		return nil, err
+	}
+	var entries []*Entry
+	for {
+		resp, err := c.receive(id)
+		if err != nil {
+			return nil, err
+		}
+		switch resp.tag {
+		case opSearchResultEntry:
+			entry, err := decodeEntry(resp)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		case opSearchResultReference:
+		default:
+			if err = checkResult(resp, opSearchResultDone); err != nil {
+				return nil, err
+			}
+			return entries, nil
+		}
+	}
+}
+
+// Close unbinds and closes the connection.
+func (c *Conn) Close() error {
+	_, err := c.send(newPacket(opUnbindRequest, nil))
+	if closeErr := c.conn.Close(); err == nil {
+		err = errors.Trace(closeErr)
+	}
+	return err
+}
+
+func (c *Conn) deadline() time.Time {
+	if c.timeout <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(c.timeout)
+}
+
+func (c *Conn) roundTrip(op *packet) (*packet, error) {
+	id, err := c.send(op)
+	if err != nil {
+		return nil, err
+	}
+	return c.receive(id)
+}
+
+func (c *Conn) send(op *packet) (int64, error) {
+	c.msgID++
+	msg := newConstructed(tagSequence, newInteger(tagInteger, c.msgID), op)
+	if err := c.conn.SetDeadline(c.deadline()); err != nil {
+		return 0, errors.Trace(err)
+	}
+	if _, err := c.conn.Write(msg.encode()); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return c.msgID, nil
+}
+
+// receive reads the protocol operation of the next response to the message.
+func (c *Conn) receive(id int64) (*packet, error) {
+	for {
+		msg, err := readPacket(c.reader)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		msgID, err := msg.child(0)
+		if err != nil {
+			return nil, err
+		}
+		op, err := msg.child(1)
+		if err != nil {
+			return nil, err
+		}
+		n, err := msgID.integer()
+		if err != nil {
+			return nil, err
+		}
+		switch n {
+		case id:
+			return op, nil
+		case 0:
+			// The unsolicited notification, e.g. the notice of disconnection.
+			if err = checkResult(op, op.tag); err == nil {
+				err = errors.New("unexpected unsolicited notification from the LDAP server")
+			}
+			return nil, err
+		}
+	}
+}
+
+// checkResult checks the tag and the LDAPResult of the response, it returns an *Error if the result isn't success.
+func checkResult(resp *packet, tag byte) error {
+	if resp.tag != tag {
+		return errors.Errorf("unexpected LDAP response 0x%02x, expect 0x%02x", resp.tag, tag)
+	}
+	code, err := resp.child(0)
+	if err != nil {
+		return err
+	}
+	msg, err := resp.child(2)
+	if err != nil {
+		return err
+	}
+	n, err := code.integer()
+	if err != nil {
+		return err
+	}
+	if n != ResultSuccess {
+		return &Error{ResultCode: n, Message: msg.str()}
+	}
+	return nil
+}
+
+func decodeEntry(p *packet) (*Entry, error) {
+	dn, err := p.child(0)
+	if err != nil {
+		return nil, err
+	}
+	attrs, err := p.child(1)
+	if err != nil {
+		return nil, err
+	}
+	entry := &Entry{DN: dn.str()}
+	for _, a := range attrs.children {
+		name, err := a.child(0)
+		if err != nil {
+			return nil, err
+		}
+		vals, err := a.child(1)
+		if err != nil {
+			return nil, err
+		}
+		attr := &Attribute{Name: name.str()}
+		for _, v := range vals.children {
+			attr.Values = append(attr.Values, v.str())
+		}
+		entry.Attributes = append(entry.Attributes, attr)
+	}
+	return entry, nil
+}
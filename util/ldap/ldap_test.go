@@ -0,0 +1,222 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/util/testleak"
+)
+
+func TestT(t *testing.T) {
+	CustomVerboseFlag = true
+	TestingT(t)
+}
+
+var _ = Suite(&testLDAPSuite{})
+
+type testLDAPSuite struct {
+}
+
+var testEntries = []*Entry{
+	{DN: "uid=alice,ou=People,dc=example,dc=com", Attributes: []*Attribute{
+		{Name: "objectClass", Values: []string{"inetOrgPerson"}},
+		{Name: "uid", Values: []string{"alice"}},
+		{Name: "cn", Values: []string{"Alice Liddell"}},
+		{Name: "userPassword", Values: []string{"secret"}},
+	}},
+	{DN: "uid=bob,ou=People,dc=example,dc=com", Attributes: []*Attribute{
+		{Name: "objectClass", Values: []string{"inetOrgPerson"}},
+		{Name: "uid", Values: []string{"bob"}},
+		{Name: "userPassword", Values: []string{"bobpwd"}},
+	}},
+	{DN: "cn=dev,ou=Groups,dc=example,dc=com", Attributes: []*Attribute{
+		{Name: "objectClass", Values: []string{"posixGroup"}},
+		{Name: "cn", Values: []string{"dev"}},
+		{Name: "memberUid", Values: []string{"alice", "bob"}},
+	}},
+}
+
+func (s *testLDAPSuite) TestBER(c *C) {
+	defer testleak.AfterTest(c)()
+	for _, n := range []int64{0, 1, 127, 128, 255, 256, -1, -128, -129, 1 << 31, -1 << 40} {
+		p := newInteger(tagInteger, n)
+		decoded, err := readPacket(bufio.NewReader(bytes.NewReader(p.encode())))
+		c.Assert(err, IsNil)
+		v, err := decoded.integer()
+		c.Assert(err, IsNil)
+		c.Assert(v, Equals, n)
+	}
+	c.Assert(newInteger(tagInteger, 128).value, DeepEquals, []byte{0x00, 0x80})
+	c.Assert(newInteger(tagInteger, -129).value, DeepEquals, []byte{0xff, 0x7f})
+
+	long := bytes.Repeat([]byte{'a'}, 300)
+	p := newConstructed(tagSequence, newPacket(tagOctetString, long), newBoolean(true))
+	encoded := p.encode()
+	c.Assert(encoded[:4], DeepEquals, []byte{tagSequence, 0x82, 0x01, 0x33})
+	decoded, err := readPacket(bufio.NewReader(bytes.NewReader(encoded)))
+	c.Assert(err, IsNil)
+	c.Assert(decoded.children, HasLen, 2)
+	c.Assert(decoded.children[0].value, DeepEquals, long)
+
+	_, err = readPacket(bufio.NewReader(bytes.NewReader(encoded[:100])))
+	c.Assert(err, NotNil)
+	_, err = decodePacket(tagSequence, []byte{tagOctetString, 0x05, 'a'})
+	c.Assert(err, NotNil)
+}
+
+func (s *testLDAPSuite) TestFilter(c *C) {
+	defer testleak.AfterTest(c)()
+	tests := []struct {
+		filter  string
+		matches []string
+	}{
+		{"(uid=alice)", []string{"alice"}},
+		{"(UID=ALICE)", []string{"alice"}},
+		{"(objectClass=*)", []string{"alice", "bob", "dev"}},
+		{"(&(objectClass=inetOrgPerson)(!(uid=bob)))", []string{"alice"}},
+		{"(|(uid=bob)(cn=dev))", []string{"bob", "dev"}},
+		{"(cn=A*Lid*ll)", []string{"alice"}},
+		{"(cn=*dell)", []string{"alice"}},
+		{"(uid>=b)", []string{"bob"}},
+		{"(uid<=b)", []string{"alice"}},
+		{"(&(objectClass=posixGroup)(memberUid=alice))", []string{"dev"}},
+		{`(cn=Alice\20Liddell)`, []string{"alice"}},
+	}
+	for _, t := range tests {
+		f, err := compileFilter(t.filter)
+		c.Assert(err, IsNil, Commentf("%s", t.filter))
+		var matches []string
+		for _, e := range testEntries {
+			if matchFilter(f, e) {
+				matches = append(matches, e.Values("uid")...)
+				if len(e.Values("uid")) == 0 {
+					matches = append(matches, e.Values("cn")...)
+				}
+			}
+		}
+		c.Assert(matches, DeepEquals, t.matches, Commentf("%s", t.filter))
+	}
+	for _, filter := range []string{"", "uid=alice", "(uid=alice", "(uid=alice))", "(&)", "(=alice)", `(uid=\4)`, `(uid=\zz)`} {
+		_, err := compileFilter(filter)
+		c.Assert(err, NotNil, Commentf("%s", filter))
+	}
+	c.Assert(EscapeFilter(`a*(b)\c`+"\x00"), Equals, `a\2a\28b\29\5cc\00`)
+	f, err := compileFilter("(uid=" + EscapeFilter("ali*") + ")")
+	c.Assert(err, IsNil)
+	c.Assert(matchFilter(f, testEntries[0]), IsFalse)
+}
+
+func (s *testLDAPSuite) TestConn(c *C) {
+	server, err := NewMockServer(testEntries, nil)
+	c.Assert(err, IsNil)
+	defer server.Close()
+
+	conn, err := Dial(server.Addr(), nil, 5*time.Second)
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	c.Assert(conn.Bind("", ""), IsNil)
+	entries, err := conn.Search(&SearchRequest{
+		BaseDN:     "dc=example,dc=com",
+		Scope:      ScopeWholeSubtree,
+		Filter:     "(&(objectClass=posixGroup)(memberUid=bob))",
+		Attributes: []string{"cn"},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(entries, HasLen, 1)
+	c.Assert(entries[0].DN, Equals, "cn=dev,ou=Groups,dc=example,dc=com")
+	c.Assert(entries[0].Attributes, HasLen, 1)
+	c.Assert(entries[0].Values("CN"), DeepEquals, []string{"dev"})
+	entries, err = conn.Search(&SearchRequest{BaseDN: "ou=People,dc=example,dc=com", Scope: ScopeBaseObject, Filter: "(objectClass=*)"})
+	c.Assert(err, IsNil)
+	c.Assert(entries, HasLen, 0)
+	_, err = conn.Search(&SearchRequest{Filter: "(uid=alice"})
+	c.Assert(err, NotNil)
+
+	c.Assert(conn.Bind("uid=alice,ou=People,dc=example,dc=com", "secret"), IsNil)
+	err = conn.Bind("uid=alice,ou=People,dc=example,dc=com", "wrong")
+	c.Assert(IsResultCode(err, ResultInvalidCredentials), IsTrue, Commentf("err %v", err))
+	c.Assert(err.Error(), Equals, "LDAP result code 49: invalid credentials")
+
+	creds, done, err := conn.SASLBind(MockSASLMechanism, []byte("bob"))
+	c.Assert(err, IsNil)
+	c.Assert(done, IsFalse)
+	c.Assert(string(creds), Equals, mockChallenge)
+	_, done, err = conn.SASLBind(MockSASLMechanism, []byte("bobpwd"))
+	c.Assert(err, IsNil)
+	c.Assert(done, IsTrue)
+	_, _, err = conn.SASLBind("SCRAM-SHA-1", []byte("n,,n=bob,r=abc"))
+	c.Assert(IsResultCode(err, resultAuthMethodNotSupported), IsTrue, Commentf("err %v", err))
+	c.Assert(server.Binds(), Equals, int64(6))
+
+	// StartTLS isn't supported without the TLS config.
+	err = conn.StartTLS(&tls.Config{InsecureSkipVerify: true})
+	c.Assert(IsResultCode(err, resultUnwillingToPerform), IsTrue, Commentf("err %v", err))
+}
+
+func (s *testLDAPSuite) TestStartTLS(c *C) {
+	cert, pool := generateTestCert(c)
+	server, err := NewMockServer(testEntries, &tls.Config{Certificates: []tls.Certificate{cert}})
+	c.Assert(err, IsNil)
+	defer server.Close()
+
+	conn, err := Dial(server.Addr(), nil, 5*time.Second)
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	c.Assert(conn.StartTLS(&tls.Config{RootCAs: pool, ServerName: "127.0.0.1"}), IsNil)
+	_, ok := conn.conn.(*tls.Conn)
+	c.Assert(ok, IsTrue)
+	c.Assert(conn.Bind("uid=bob,ou=People,dc=example,dc=com", "bobpwd"), IsNil)
+
+	// The certificate is verified.
+	conn2, err := Dial(server.Addr(), nil, 5*time.Second)
+	c.Assert(err, IsNil)
+	defer conn2.Close()
+	c.Assert(conn2.StartTLS(&tls.Config{ServerName: "127.0.0.1"}), NotNil)
+}
+
+func generateTestCert(c *C) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, IsNil)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ldap-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	c.Assert(err, IsNil)
+	parsed, err := x509.ParseCertificate(der)
+	c.Assert(err, IsNil)
+	pool := x509.NewCertPool()
+	pool.AddCert(parsed)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pool
+}
@@ -0,0 +1,327 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"bufio"
+	"crypto/tls"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pingcap/errors"
+)
+
+// MockSASLMechanism is the SASL mechanism of the mock server, the client sends the uid of the user first, and sends
+// the userPassword after the server challenges it.
+const MockSASLMechanism = "MOCK"
+
+// The result codes only returned by the mock server.
+const (
+	resultProtocolError          = 2
+	resultAuthMethodNotSupported = 7
+	resultUnwillingToPerform     = 53
+)
+
+const (
+	mockChallenge        = "password?"
+	mockUserPasswordAttr = "userPassword"
+	mockUIDAttr          = "uid"
+)
+
+// MockServer is an in-memory LDAP server for the tests. The simple binds are verified by the userPassword attribute
+// of the entries, the anonymous bind is allowed, and the SASL binds only support MockSASLMechanism.
+type MockServer struct {
+	listener  net.Listener
+	entries   []*Entry
+	tlsConfig *tls.Config
+	binds     int64
+	wg        sync.WaitGroup
+}
+
+// NewMockServer starts a mock server of the entries on a random local port, StartTLS is supported if the TLS config
+// is given.
+func NewMockServer(entries []*Entry, tlsConfig *tls.Config) (*MockServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	s := &MockServer{listener: listener, entries: entries, tlsConfig: tlsConfig}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Addr returns the address the server listens on.
+func (s *MockServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Binds returns the number of the bind requests the server has received.
+func (s *MockServer) Binds() int64 {
+	return atomic.LoadInt64(&s.binds)
+}
+
+// Close stops the server, the established connections are closed by the clients.
+func (s *MockServer) Close() {
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *MockServer) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *MockServer) handle(conn net.Conn) {
+	// The connection is replaced after StartTLS.
+	defer func() {
+		conn.Close()
+	}()
+	reader := bufio.NewReader(conn)
+	var saslUser string
+	for {
+		msg, err := readPacket(reader)
+		if err != nil || len(msg.children) < 2 {
+			return
+		}
+		id, err := msg.children[0].integer()
+		if err != nil {
+			return
+		}
+		op := msg.children[1]
+		var resp []*packet
+		switch op.tag {
+		case opBindRequest:
+			atomic.AddInt64(&s.binds, 1)
+			resp = []*packet{s.bind(op, &saslUser)}
+		case opSearchRequest:
+			resp = s.search(op)
+		case opExtendedRequest:
+			code := int64(ResultSuccess)
+			if s.tlsConfig == nil || len(op.children) == 0 || op.children[0].str() != startTLSOID {
+				code = resultUnwillingToPerform
+			}
+			resp = []*packet{newResult(opExtendedResponse, code, "")}
+		default:
+			// The unbind and the unsupported operations close the connection.
+			return
+		}
+		for _, r := range resp {
+			if _, err = conn.Write(newConstructed(tagSequence, newInteger(tagInteger, id), r).encode()); err != nil {
+				return
+			}
+		}
+		if op.tag == opExtendedRequest && s.tlsConfig != nil {
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err = tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn, reader = tlsConn, bufio.NewReader(tlsConn)
+		}
+	}
+}
+
+func newResult(tag byte, code int64, msg string) *packet {
+	return newConstructed(tag, newInteger(tagEnumerated, code), newString(tagOctetString, ""), newString(tagOctetString, msg))
+}
+
+func (s *MockServer) bind(op *packet, saslUser *string) *packet {
+	if len(op.children) < 3 {
+		return newResult(opBindResponse, resultProtocolError, "malformed bind request")
+	}
+	dn, auth := op.children[1].str(), op.children[2]
+	if auth.tag == bindAuthSimple {
+		pwd := auth.str()
+		if dn == "" && pwd == "" {
+			return newResult(opBindResponse, ResultSuccess, "")
+		}
+		for _, e := range s.entries {
+			if strings.EqualFold(e.DN, dn) && pwd != "" && containsValue(e.Values(mockUserPasswordAttr), pwd) {
+				return newResult(opBindResponse, ResultSuccess, "")
+			}
+		}
+		return newResult(opBindResponse, ResultInvalidCredentials, "invalid credentials")
+	}
+	if auth.tag != bindAuthSASL || len(auth.children) == 0 || auth.children[0].str() != MockSASLMechanism {
+		return newResult(opBindResponse, resultAuthMethodNotSupported, "unsupported authentication method")
+	}
+	var creds string
+	if len(auth.children) > 1 {
+		creds = auth.children[1].str()
+	}
+	if *saslUser == "" {
+		*saslUser = creds
+		return newResult(opBindResponse, ResultSASLBindInProgress, "").add(newString(bindServerSASLCreds, mockChallenge))
+	}
+	user := *saslUser
+	*saslUser = ""
+	for _, e := range s.entries {
+		if containsValue(e.Values(mockUIDAttr), user) && creds != "" && containsValue(e.Values(mockUserPasswordAttr), creds) {
+			return newResult(opBindResponse, ResultSuccess, "")
+		}
+	}
+	return newResult(opBindResponse, ResultInvalidCredentials, "invalid credentials")
+}
+
+func (s *MockServer) search(op *packet) []*packet {
+	if len(op.children) < 8 {
+		return []*packet{newResult(opSearchResultDone, resultProtocolError, "malformed search request")}
+	}
+	base := strings.ToLower(op.children[0].str())
+	scope, err := op.children[1].integer()
+	if err != nil {
+		return []*packet{newResult(opSearchResultDone, resultProtocolError, err.Error())}
+	}
+	filter, attrs := op.children[6], op.children[7]
+	var resp []*packet
+	for _, e := range s.entries {
+		dn := strings.ToLower(e.DN)
+		switch {
+		case dn == base:
+			if scope == ScopeSingleLevel {
+				continue
+			}
+		case base == "" || strings.HasSuffix(dn, ","+base):
+			if scope == ScopeBaseObject {
+				continue
+			}
+		default:
+			continue
+		}
+		if !matchFilter(filter, e) {
+			continue
+		}
+		partialAttrs := newConstructed(tagSequence)
+		for _, attr := range e.Attributes {
+			if len(attrs.children) > 0 && !attrRequested(attrs, attr.Name) {
+				continue
+			}
+			vals := newConstructed(tagSet)
+			for _, v := range attr.Values {
+				vals.add(newString(tagOctetString, v))
+			}
+			partialAttrs.add(newConstructed(tagSequence, newString(tagOctetString, attr.Name), vals))
+		}
+		resp = append(resp, newConstructed(opSearchResultEntry, newString(tagOctetString, e.DN), partialAttrs))
+	}
+	return append(resp, newResult(opSearchResultDone, ResultSuccess, ""))
+}
+
+func attrRequested(attrs *packet, name string) bool {
+	for _, a := range attrs.children {
+		if strings.EqualFold(a.str(), name) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsValue(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// matchFilter evaluates the BER encoded filter on the entry, the values are compared case-insensitively.
+func matchFilter(f *packet, e *Entry) bool {
+	switch f.tag {
+	case filterAnd | constructed:
+		for _, child := range f.children {
+			if !matchFilter(child, e) {
+				return false
+			}
+		}
+		return true
+	case filterOr | constructed:
+		for _, child := range f.children {
+			if matchFilter(child, e) {
+				return true
+			}
+		}
+		return false
+	case filterNot | constructed:
+		return len(f.children) == 1 && !matchFilter(f.children[0], e)
+	case filterPresent:
+		return len(e.Values(f.str())) > 0
+	case filterEquality | constructed, filterApprox | constructed, filterGreaterOrEqual | constructed, filterLessOrEqual | constructed:
+		if len(f.children) != 2 {
+			return false
+		}
+		expected := strings.ToLower(f.children[1].str())
+		for _, v := range e.Values(f.children[0].str()) {
+			v = strings.ToLower(v)
+			switch f.tag {
+			case filterGreaterOrEqual | constructed:
+				if v >= expected {
+					return true
+				}
+			case filterLessOrEqual | constructed:
+				if v <= expected {
+					return true
+				}
+			default:
+				if v == expected {
+					return true
+				}
+			}
+		}
+		return false
+	case filterSubstrings | constructed:
+		if len(f.children) != 2 {
+			return false
+		}
+		for _, v := range e.Values(f.children[0].str()) {
+			if matchSubstrings(f.children[1], strings.ToLower(v)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchSubstrings(substrings *packet, v string) bool {
+	for _, sub := range substrings.children {
+		s := strings.ToLower(sub.str())
+		switch sub.tag {
+		case substringInitial:
+			if !strings.HasPrefix(v, s) {
+				return false
+			}
+			v = v[len(s):]
+		case substringFinal:
+			if !strings.HasSuffix(v, s) {
+				return false
+			}
+			v = v[:len(v)-len(s)]
+		default:
+			i := strings.Index(v, s)
+			if i < 0 {
+				return false
+			}
+			v = v[i+len(s):]
+		}
+	}
+	return true
+}
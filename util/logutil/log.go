@@ -51,6 +51,10 @@ const (
 	DefaultRecordPlanInSlowLog = 1
 	// DefaultTiDBEnableSlowLog enables TiDB to log slow queries.
 	DefaultTiDBEnableSlowLog = true
+	// SlowLogFormatText is the format of the slow log compatible with MySQL.
+	SlowLogFormatText = "text"
+	// SlowLogFormatJSON is the format of the slow log in which every entry is a JSON object in a line.
+	SlowLogFormatJSON = "json"
 )
 
 // EmptyFileLogConfig is an empty FileLogConfig.
@@ -75,6 +79,22 @@ type LogConfig struct {
 
 	// SlowQueryFile filename, default to File log config on empty.
 	SlowQueryFile string
+	// SlowQueryFormat is the format of the slow log, one of SlowLogFormatText or SlowLogFormatJSON.
+	SlowQueryFormat string
+	// SlowQueryRotation rotates the slow log file, the zero fields default to the ones of File.
+	SlowQueryRotation SlowLogRotation
+}
+
+// SlowLogRotation is the rotation config of the slow log file.
+type SlowLogRotation struct {
+	// MaxSize is the max size in MB of the file before it's rotated.
+	MaxSize int
+	// MaxDays is the max number of days to retain the rotated files.
+	MaxDays int
+	// MaxBackups is the max number of the rotated files to retain.
+	MaxBackups int
+	// Compress indicates whether to compress the rotated files by gzip.
+	Compress bool
 }
 
 // NewLogConfig creates a LogConfig.
@@ -86,7 +106,8 @@ func NewLogConfig(level, format, slowQueryFile string, fileCfg FileLogConfig, di
 			DisableTimestamp: disableTimestamp,
 			File:             fileCfg.FileLogConfig,
 		},
-		SlowQueryFile: slowQueryFile,
+		SlowQueryFile:   slowQueryFile,
+		SlowQueryFormat: SlowLogFormatText,
 	}
 	for _, opt := range opts {
 		opt(&c.Config)
@@ -212,6 +233,29 @@ func (f *slowLogFormatter) Format(entry *log.Entry) ([]byte, error) {
 	return b.Bytes(), nil
 }
 
+// slowLogJSONFormatter writes the entries which are JSON objects in lines, the time is added as the first field.
+type slowLogJSONFormatter struct{}
+
+func (f *slowLogJSONFormatter) Format(entry *log.Entry) ([]byte, error) {
+	var b *bytes.Buffer
+	if entry.Buffer != nil {
+		b = entry.Buffer
+	} else {
+		b = &bytes.Buffer{}
+	}
+
+	msg := entry.Message
+	fmt.Fprintf(b, `{"Time":%q`, entry.Time.Format(SlowLogTimeFormat))
+	if strings.HasPrefix(msg, "{") && len(msg) > 2 {
+		b.WriteByte(',')
+		b.WriteString(msg[1:])
+	} else {
+		b.WriteByte('}')
+	}
+	b.WriteByte('\n')
+	return b.Bytes(), nil
+}
+
 func stringToLogFormatter(format string, disableTimestamp bool) log.Formatter {
 	switch strings.ToLower(format) {
 	case "text":
@@ -225,6 +269,11 @@ func stringToLogFormatter(format string, disableTimestamp bool) log.Formatter {
 
 // initFileLog initializes file based logging options.
 func initFileLog(cfg *zaplog.FileLogConfig, logger *log.Logger) error {
+	return initRotatedFileLog(cfg, false, logger)
+}
+
+// initRotatedFileLog initializes file based logging options, the rotated files are compressed if compress is true.
+func initRotatedFileLog(cfg *zaplog.FileLogConfig, compress bool, logger *log.Logger) error {
 	if st, err := os.Stat(cfg.Filename); err == nil {
 		if st.IsDir() {
 			return errors.New("can't use directory as log file name")
@@ -241,6 +290,7 @@ func initFileLog(cfg *zaplog.FileLogConfig, logger *log.Logger) error {
 		MaxBackups: cfg.MaxBackups,
 		MaxAge:     cfg.MaxDays,
 		LocalTime:  true,
+		Compress:   compress,
 	}
 
 	if logger == nil {
@@ -278,10 +328,24 @@ func InitLogger(cfg *LogConfig) error {
 		SlowQueryLogger = log.New()
 		tmp := cfg.File
 		tmp.Filename = cfg.SlowQueryFile
-		if err := initFileLog(&tmp, SlowQueryLogger); err != nil {
+		rotation := cfg.SlowQueryRotation
+		if rotation.MaxSize > 0 {
+			tmp.MaxSize = rotation.MaxSize
+		}
+		if rotation.MaxDays > 0 {
+			tmp.MaxDays = rotation.MaxDays
+		}
+		if rotation.MaxBackups > 0 {
+			tmp.MaxBackups = rotation.MaxBackups
+		}
+		if err := initRotatedFileLog(&tmp, rotation.Compress, SlowQueryLogger); err != nil {
 			return errors.Trace(err)
 		}
-		SlowQueryLogger.Formatter = &slowLogFormatter{}
+		if cfg.SlowQueryFormat == SlowLogFormatJSON {
+			SlowQueryLogger.Formatter = &slowLogJSONFormatter{}
+		} else {
+			SlowQueryLogger.Formatter = &slowLogFormatter{}
+		}
 	}
 
 	// Setup log key for tikv client.
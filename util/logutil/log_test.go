@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"runtime"
 	"strings"
@@ -129,6 +130,29 @@ func (s *testLogSuite) TestSlowQueryLogger(c *C) {
 	c.Assert(err, Equals, io.EOF)
 }
 
+func (s *testLogSuite) TestSlowQueryJSONLogger(c *C) {
+	fileName := "slow_query_json"
+	os.Remove(fileName)
+	conf := NewLogConfig("info", DefaultLogFormat, fileName, NewFileLogConfig(DefaultLogMaxSize), false)
+	conf.SlowQueryFormat = SlowLogFormatJSON
+	c.Assert(InitLogger(conf), IsNil)
+	defer func() {
+		c.Assert(InitLogger(NewLogConfig("info", DefaultLogFormat, "", EmptyFileLogConfig, false)), IsNil)
+		os.Remove(fileName)
+	}()
+
+	SlowQueryLogger.Warn(`{"Query_time":1,"Query":"select 1;"}`)
+	SlowQueryLogger.Warn(`{}`)
+
+	content, err := ioutil.ReadFile(fileName)
+	c.Assert(err, IsNil)
+	lines := strings.Split(string(content), "\n")
+	c.Assert(lines, HasLen, 3)
+	c.Assert(lines[0], Matches, `\{"Time":"[^"]+","Query_time":1,"Query":"select 1;"\}`)
+	c.Assert(lines[1], Matches, `\{"Time":"[^"]+"\}`)
+	c.Assert(lines[2], Equals, "")
+}
+
 func (s *testLogSuite) TestLoggerKeepOrder(c *C) {
 	conf := NewLogConfig("warn", DefaultLogFormat, "", EmptyFileLogConfig, true)
 	c.Assert(InitLogger(conf), IsNil)
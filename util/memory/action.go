@@ -15,6 +15,7 @@ package memory
 
 import (
 	"fmt"
+	"strconv"
 	"sync"
 
 	"github.com/pingcap/tidb/errno"
@@ -65,6 +66,35 @@ const (
 	DefRateLimitPriority
 )
 
+// Names of the OOM actions, they are used to configure the escalation chain of the statements and to record the
+// actions triggered by the statements.
+const (
+	ActionNameCancel    = "cancel"
+	ActionNameLog       = "log"
+	ActionNameSpill     = "spill"
+	ActionNameRateLimit = "rate_limit"
+)
+
+// ActionName returns the name of the action, which is decided by its default priority.
+func ActionName(a ActionOnExceed) string {
+	switch a.GetPriority() {
+	case DefPanicPriority:
+		return ActionNameCancel
+	case DefLogPriority:
+		return ActionNameLog
+	case DefSpillPriority:
+		return ActionNameSpill
+	case DefRateLimitPriority:
+		return ActionNameRateLimit
+	}
+	return strconv.FormatInt(a.GetPriority(), 10)
+}
+
+// isFinalAction checks whether the action is the final action of the chain, which is always taken at last.
+func isFinalAction(a ActionOnExceed) bool {
+	return a.GetPriority() <= DefLogPriority
+}
+
 // LogOnExceed logs a warning only once when memory usage exceeds memory quota.
 type LogOnExceed struct {
 	BaseOOMAction
@@ -85,6 +115,7 @@ func (a *LogOnExceed) Action(t *Tracker) {
 	defer a.mutex.Unlock()
 	if !a.acted {
 		a.acted = true
+		t.RecordTriggeredAction(ActionNameLog)
 		if a.logHook == nil {
 			logutil.BgLogger().Warn("memory exceeds quota",
 				zap.Error(errMemExceedThreshold.GenWithStackByArgs(t.label, t.BytesConsumed(), t.bytesLimit, t.String())))
@@ -122,6 +153,7 @@ func (a *PanicOnExceed) Action(t *Tracker) {
 	}
 	a.acted = true
 	a.mutex.Unlock()
+	t.RecordTriggeredAction(ActionNameCancel)
 	if a.logHook != nil {
 		a.logHook(a.ConnID)
 	}
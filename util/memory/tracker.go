@@ -49,6 +49,16 @@ type Tracker struct {
 	actionMu struct {
 		sync.Mutex
 		actionOnExceed ActionOnExceed
+		// escalation is the names of the actions taken in order before the final action, it's only used when
+		// hasEscalation is true. See SetEscalationChain.
+		escalation    []string
+		hasEscalation bool
+	}
+	// triggeredMu records the actions triggered by the tracker in order, the same actions triggered continuously
+	// are merged into one step.
+	triggeredMu struct {
+		sync.Mutex
+		steps []triggeredAction
 	}
 	parMu struct {
 		sync.Mutex
@@ -117,12 +127,40 @@ func (t *Tracker) SetActionOnExceed(a ActionOnExceed) {
 	t.actionMu.Unlock()
 }
 
+// SetEscalationChain sets the names of the actions taken in order when memory usage exceeds bytesLimit, the final
+// action set by SetActionOnExceed is taken after all of them. The actions not in the chain are ignored by
+// FallbackOldAndSetNewAction, and the actions are ordered by their default priorities if the chain is never set.
+func (t *Tracker) SetEscalationChain(names []string) {
+	t.actionMu.Lock()
+	defer t.actionMu.Unlock()
+	t.actionMu.escalation = names
+	t.actionMu.hasEscalation = true
+}
+
 // FallbackOldAndSetNewAction sets the action when memory usage exceeds bytesLimit
 // and set the original action as its fallback.
 func (t *Tracker) FallbackOldAndSetNewAction(a ActionOnExceed) {
 	t.actionMu.Lock()
 	defer t.actionMu.Unlock()
-	t.actionMu.actionOnExceed = reArrangeFallback(t.actionMu.actionOnExceed, a)
+	if a != nil && t.actionRank(a) < 0 {
+		return
+	}
+	t.actionMu.actionOnExceed = reArrangeFallback(t.actionMu.actionOnExceed, a, t.actionRank)
+}
+
+// actionRank returns the rank of the action in the chain, the action with a higher rank is taken earlier. A negative
+// rank means the action is not in the escalation chain. It should be called with actionMu held.
+func (t *Tracker) actionRank(a ActionOnExceed) int64 {
+	if !t.actionMu.hasEscalation || isFinalAction(a) {
+		return a.GetPriority()
+	}
+	name := ActionName(a)
+	for i, step := range t.actionMu.escalation {
+		if step == name {
+			return DefLogPriority + int64(len(t.actionMu.escalation)-i)
+		}
+	}
+	return -1
 }
 
 // GetFallbackForTest get the oom action used by test.
@@ -132,23 +170,56 @@ func (t *Tracker) GetFallbackForTest() ActionOnExceed {
 	return t.actionMu.actionOnExceed
 }
 
-// reArrangeFallback merge two action chains and rearrange them by priority in descending order.
-func reArrangeFallback(a ActionOnExceed, b ActionOnExceed) ActionOnExceed {
+// reArrangeFallback merge two action chains and rearrange them by rank in descending order.
+func reArrangeFallback(a ActionOnExceed, b ActionOnExceed, rank func(ActionOnExceed) int64) ActionOnExceed {
 	if a == nil {
 		return b
 	}
 	if b == nil {
 		return a
 	}
-	if a.GetPriority() < b.GetPriority() {
+	if rank(a) < rank(b) {
 		a, b = b, a
 		a.SetFallback(b)
 	} else {
-		a.SetFallback(reArrangeFallback(a.GetFallback(), b))
+		a.SetFallback(reArrangeFallback(a.GetFallback(), b, rank))
 	}
 	return a
 }
 
+// triggeredAction is a step of the actions triggered by the tracker.
+type triggeredAction struct {
+	name  string
+	count int
+}
+
+// RecordTriggeredAction records that the action is triggered by the tracker. The actions should call it with the
+// tracker passed to ActionOnExceed.Action when they take effect.
+func (t *Tracker) RecordTriggeredAction(name string) {
+	t.triggeredMu.Lock()
+	defer t.triggeredMu.Unlock()
+	if n := len(t.triggeredMu.steps); n > 0 && t.triggeredMu.steps[n-1].name == name {
+		t.triggeredMu.steps[n-1].count++
+		return
+	}
+	t.triggeredMu.steps = append(t.triggeredMu.steps, triggeredAction{name: name, count: 1})
+}
+
+// TriggeredActions returns the actions triggered by the tracker in order, like "spill(2),rate_limit(3),cancel(1)".
+// It returns an empty string if no action is triggered.
+func (t *Tracker) TriggeredActions() string {
+	t.triggeredMu.Lock()
+	defer t.triggeredMu.Unlock()
+	var buf bytes.Buffer
+	for i, step := range t.triggeredMu.steps {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(&buf, "%s(%d)", step.name, step.count)
+	}
+	return buf.String()
+}
+
 // SetLabel sets the label of a Tracker.
 func (t *Tracker) SetLabel(label int) {
 	t.label = label
@@ -338,6 +409,41 @@ func (t *Tracker) toString(indent string, buffer *bytes.Buffer) {
 	buffer.WriteString(indent + "}\n")
 }
 
+// TrackerSnapshot is a point-in-time copy of a Tracker tree.
+type TrackerSnapshot struct {
+	Label         int                `json:"label"`
+	BytesConsumed int64              `json:"bytes_consumed"`
+	MaxConsumed   int64              `json:"max_consumed"`
+	BytesLimit    int64              `json:"bytes_limit,omitempty"`
+	Children      []*TrackerSnapshot `json:"children,omitempty"`
+}
+
+// Snapshot returns a copy of this Tracker tree, children are ordered by label.
+// Note that a global tracker doesn't maintain its children, so only its own consumption is reported.
+func (t *Tracker) Snapshot() *TrackerSnapshot {
+	snap := &TrackerSnapshot{
+		Label:         t.label,
+		BytesConsumed: t.BytesConsumed(),
+		MaxConsumed:   t.MaxConsumed(),
+		BytesLimit:    t.GetBytesLimit(),
+	}
+	t.mu.Lock()
+	labels := make([]int, 0, len(t.mu.children))
+	for label := range t.mu.children {
+		labels = append(labels, label)
+	}
+	sort.Ints(labels)
+	children := make([]*Tracker, 0, len(t.mu.children))
+	for _, label := range labels {
+		children = append(children, t.mu.children[label]...)
+	}
+	t.mu.Unlock()
+	for _, child := range children {
+		snap.Children = append(snap.Children, child.Snapshot())
+	}
+	return snap
+}
+
 // FormatBytes uses to format bytes, this function will prune precision before format bytes.
 func (t *Tracker) FormatBytes(numBytes int64) string {
 	return FormatBytes(numBytes)
@@ -491,4 +597,36 @@ const (
 	LabelForApplyCache int = -17
 	// LabelForSimpleTask represents the label of the simple task
 	LabelForSimpleTask int = -18
+	// LabelForCursorFetch represents the label of the rows kept by the server-side cursor
+	LabelForCursorFetch int = -19
 )
+
+var labelNames = map[int]string{
+	LabelForSQLText:              "SQLText",
+	LabelForIndexWorker:          "IndexWorker",
+	LabelForInnerList:            "InnerList",
+	LabelForInnerTable:           "InnerTable",
+	LabelForOuterTable:           "OuterTable",
+	LabelForCoprocessor:          "Coprocessor",
+	LabelForChunkList:            "ChunkList",
+	LabelForGlobalSimpleLRUCache: "GlobalSimpleLRUCache",
+	LabelForChunkListInDisk:      "ChunkListInDisk",
+	LabelForRowContainer:         "RowContainer",
+	LabelForGlobalStorage:        "GlobalStorage",
+	LabelForGlobalMemory:         "GlobalMemory",
+	LabelForBuildSideResult:      "BuildSideResult",
+	LabelForRowChunks:            "RowChunks",
+	LabelForStatsCache:           "StatsCache",
+	LabelForOuterList:            "OuterList",
+	LabelForApplyCache:           "ApplyCache",
+	LabelForSimpleTask:           "SimpleTask",
+}
+
+// LabelName returns the readable name of the predefined label. The labels which are not predefined are the IDs of
+// the plans, whose names should be resolved by the plan, so the ID is returned as the name.
+func LabelName(label int) string {
+	if name, ok := labelNames[label]; ok {
+		return name
+	}
+	return strconv.Itoa(label)
+}
@@ -134,6 +134,7 @@ func (a *mockAction) Action(t *Tracker) {
 		return
 	}
 	a.called = true
+	t.RecordTriggeredAction(ActionName(a))
 }
 
 func (a *mockAction) GetPriority() int64 {
@@ -258,6 +259,26 @@ func (s *testSuite) TestToString(c *C) {
 `)
 }
 
+func (s *testSuite) TestSnapshot(c *C) {
+	parent := NewTracker(1, -1)
+	child1 := NewTracker(3, 1000)
+	child2 := NewTracker(2, -1)
+	child1.AttachTo(parent)
+	child2.AttachTo(parent)
+	child1.Consume(100)
+	child2.Consume(200)
+
+	snap := parent.Snapshot()
+	c.Assert(snap.Label, Equals, 1)
+	c.Assert(snap.BytesConsumed, Equals, int64(300))
+	c.Assert(snap.Children, HasLen, 2)
+	c.Assert(snap.Children[0].Label, Equals, 2)
+	c.Assert(snap.Children[0].BytesConsumed, Equals, int64(200))
+	c.Assert(snap.Children[1].Label, Equals, 3)
+	c.Assert(snap.Children[1].BytesLimit, Equals, int64(1000))
+	c.Assert(snap.Children[1].Children, HasLen, 0)
+}
+
 func (s *testSuite) TestMaxConsumed(c *C) {
 	r := NewTracker(1, -1)
 	c1 := NewTracker(2, -1)
@@ -466,3 +487,44 @@ func (s *testSuite) TestOOMActionPriority(c *C) {
 		}
 	}
 }
+
+func (s *testSuite) TestOOMActionEscalationChain(c *C) {
+	tracker := NewTracker(1, 1)
+	tracker.SetEscalationChain([]string{ActionNameSpill, ActionNameRateLimit})
+	rateLimit := &mockAction{priority: DefRateLimitPriority}
+	spill1 := &mockAction{priority: DefSpillPriority}
+	spill2 := &mockAction{priority: DefSpillPriority}
+	tracker.FallbackOldAndSetNewAction(rateLimit)
+	tracker.FallbackOldAndSetNewAction(spill1)
+	tracker.FallbackOldAndSetNewAction(spill2)
+	c.Assert(tracker.TriggeredActions(), Equals, "")
+
+	tracker.Consume(10)
+	c.Assert(spill1.called, IsTrue)
+	c.Assert(spill2.called, IsFalse)
+	tracker.Consume(10)
+	c.Assert(spill2.called, IsTrue)
+	c.Assert(rateLimit.called, IsFalse)
+	tracker.Consume(10)
+	c.Assert(rateLimit.called, IsTrue)
+	tracker.Consume(10)
+	c.Assert(tracker.TriggeredActions(), Equals, "spill(2),rate_limit(1),log(1)")
+
+	// The actions not in the chain are ignored.
+	tracker = NewTracker(1, 1)
+	tracker.SetEscalationChain([]string{ActionNameRateLimit})
+	tracker.FallbackOldAndSetNewAction(&mockAction{priority: DefSpillPriority})
+	tracker.FallbackOldAndSetNewAction(&mockAction{priority: DefRateLimitPriority})
+	action := tracker.GetFallbackForTest()
+	c.Assert(action.GetPriority(), Equals, int64(DefRateLimitPriority))
+	c.Assert(action.GetFallback().GetPriority(), Equals, int64(DefLogPriority))
+	c.Assert(action.GetFallback().GetFallback(), IsNil)
+
+	// The final action is taken directly with an empty chain.
+	tracker = NewTracker(1, 1)
+	tracker.SetEscalationChain(nil)
+	tracker.FallbackOldAndSetNewAction(&mockAction{priority: DefSpillPriority})
+	tracker.FallbackOldAndSetNewAction(&mockAction{priority: DefRateLimitPriority})
+	c.Assert(tracker.GetFallbackForTest().GetPriority(), Equals, int64(DefLogPriority))
+	c.Assert(tracker.GetFallbackForTest().GetFallback(), IsNil)
+}
@@ -281,12 +281,15 @@ const (
 	DNS = SANType("DNS")
 	// IP indicates ip info in SAN.
 	IP = SANType("IP")
+	// EmailAddr indicates email info in SAN.
+	EmailAddr = SANType("EMAIL")
 )
 
 var supportSAN = map[SANType]struct{}{
-	URI: {},
-	DNS: {},
-	IP:  {},
+	URI:       {},
+	DNS:       {},
+	IP:        {},
+	EmailAddr: {},
 }
 
 // ParseAndCheckSAN parses and check SAN str.
@@ -424,6 +427,7 @@ type SequenceSchema interface {
 type SequenceTable interface {
 	GetSequenceID() int64
 	GetSequenceNextVal(ctx interface{}, dbName, seqName string) (int64, error)
+	GetSequenceNextVals(ctx interface{}, dbName, seqName string, n int) ([]int64, error)
 	SetSequenceVal(ctx interface{}, newVal int64, dbName, seqName string) (int64, bool, error)
 }
 
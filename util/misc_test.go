@@ -152,6 +152,11 @@ func (s *testMiscSuite) TestBasicFunc(c *C) {
 	row3 := pi.ToRow(time.UTC)
 	c.Assert(row3[:8], DeepEquals, row)
 	c.Assert(row3[9], Equals, int64(0))
+	c.Assert(row3[12:], DeepEquals, []interface{}{nil, nil, nil})
+
+	pi.ConnectAttrs = map[string]string{"program_name": "mysql", "_client_version": "8.0.23"}
+	row3 = pi.ToRow(time.UTC)
+	c.Assert(row3[12:], DeepEquals, []interface{}{"mysql", nil, "8.0.23"})
 
 	// Test for RandomBuf.
 	buf := fastrand.Buf(5)
@@ -254,6 +254,9 @@ func (c *Context) GoCtx() context.Context {
 // StoreQueryFeedback stores the query feedback.
 func (c *Context) StoreQueryFeedback(_ interface{}) {}
 
+// StorePredicateColumns stores the predicate columns.
+func (c *Context) StorePredicateColumns(_ int64, _ []int64) {}
+
 // StoreIndexUsage strores the index usage information.
 func (c *Context) StoreIndexUsage(_ int64, _ int64, _ int64) {}
 
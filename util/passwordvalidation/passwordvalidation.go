@@ -0,0 +1,213 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package passwordvalidation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pingcap/tidb/sessionctx/variable"
+)
+
+// The levels of the password policy, a level includes all the requirements of the lower levels.
+const (
+	// PolicyLow only requires the length of the passwords.
+	PolicyLow = iota
+	// PolicyMedium requires the numbers, the lowercase and uppercase letters and the special characters.
+	PolicyMedium
+	// PolicyStrong requires the passwords not to contain any word of the dictionary.
+	PolicyStrong
+)
+
+// minDictionaryWordLength is the minimum length of the substrings of the passwords checked against the dictionary.
+const minDictionaryWordLength = 4
+
+// Policy is the policy of the passwords, it's configured by the validate_password_* system variables.
+type Policy struct {
+	Enabled          bool
+	Level            int
+	CheckUserName    bool
+	Length           int
+	MixedCaseCount   int
+	NumberCount      int
+	SpecialCharCount int
+	// Dictionary is the set of the lowercase words the passwords can't contain.
+	Dictionary map[string]struct{}
+}
+
+// LoadPolicy loads the password policy from the global system variables.
+func LoadPolicy(accessor variable.GlobalVarAccessor) (*Policy, error) {
+	values := make(map[string]string)
+	for _, name := range []string{
+		variable.ValidatePasswordEnable,
+		variable.ValidatePasswordPolicy,
+		variable.ValidatePasswordCheckUserName,
+		variable.ValidatePasswordLength,
+		variable.ValidatePasswordMixedCaseCount,
+		variable.ValidatePasswordNumberCount,
+		variable.ValidatePasswordSpecialCharCount,
+		variable.ValidatePasswordDictionary,
+	} {
+		val, err := accessor.GetGlobalSysVar(name)
+		if err != nil {
+			return nil, err
+		}
+		values[name] = val
+	}
+	p := &Policy{
+		Enabled:          variable.TiDBOptOn(values[variable.ValidatePasswordEnable]),
+		CheckUserName:    variable.TiDBOptOn(values[variable.ValidatePasswordCheckUserName]),
+		Length:           atoi(values[variable.ValidatePasswordLength]),
+		MixedCaseCount:   atoi(values[variable.ValidatePasswordMixedCaseCount]),
+		NumberCount:      atoi(values[variable.ValidatePasswordNumberCount]),
+		SpecialCharCount: atoi(values[variable.ValidatePasswordSpecialCharCount]),
+		Dictionary:       ParseDictionary(values[variable.ValidatePasswordDictionary]),
+	}
+	switch strings.ToUpper(values[variable.ValidatePasswordPolicy]) {
+	case "LOW":
+		p.Level = PolicyLow
+	case "STRONG":
+		p.Level = PolicyStrong
+	default:
+		p.Level = PolicyMedium
+	}
+	return p, nil
+}
+
+func atoi(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// ParseDictionary parses the words separated by semicolons, the words shorter than 4 characters are ignored since
+// they can't match any substring checked by the policy.
+func ParseDictionary(words string) map[string]struct{} {
+	dict := make(map[string]struct{})
+	for _, word := range strings.Split(words, ";") {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if len([]rune(word)) >= minDictionaryWordLength {
+			dict[word] = struct{}{}
+		}
+	}
+	return dict
+}
+
+// MinLength returns the effective minimum length of the passwords, which is at least the number of the characters
+// required by the medium policy.
+func (p *Policy) MinLength() int {
+	minLength := p.Length
+	if p.Level >= PolicyMedium {
+		if required := p.NumberCount + p.SpecialCharCount + 2*p.MixedCaseCount; required > minLength {
+			minLength = required
+		}
+	}
+	return minLength
+}
+
+// Validate checks whether the password of the user satisfies the policy, it returns the first unsatisfied
+// requirement, or an empty string if the password is valid.
+func (p *Policy) Validate(user, password string) string {
+	if !p.Enabled {
+		return ""
+	}
+	if p.CheckUserName && user != "" && (strings.EqualFold(password, user) || strings.EqualFold(password, reverse(user))) {
+		return "Password Contains User Name"
+	}
+	return p.check(password, p.Level)
+}
+
+// check returns the first requirement of the level the password doesn't satisfy.
+func (p *Policy) check(password string, level int) string {
+	runes := []rune(password)
+	if minLength := p.MinLength(); len(runes) < minLength {
+		return fmt.Sprintf("Require Password Length: %d", minLength)
+	}
+	if level < PolicyMedium {
+		return ""
+	}
+	var lower, upper, number, special int
+	for _, r := range runes {
+		switch {
+		case unicode.IsLower(r):
+			lower++
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsDigit(r):
+			number++
+		default:
+			special++
+		}
+	}
+	if lower < p.MixedCaseCount {
+		return fmt.Sprintf("Require Password Lowercase Count: %d", p.MixedCaseCount)
+	}
+	if upper < p.MixedCaseCount {
+		return fmt.Sprintf("Require Password Uppercase Count: %d", p.MixedCaseCount)
+	}
+	if number < p.NumberCount {
+		return fmt.Sprintf("Require Password Digit Count: %d", p.NumberCount)
+	}
+	if special < p.SpecialCharCount {
+		return fmt.Sprintf("Require Password Non-alphanumeric Count: %d", p.SpecialCharCount)
+	}
+	if level < PolicyStrong || len(p.Dictionary) == 0 {
+		return ""
+	}
+	lowered := []rune(strings.ToLower(password))
+	for i := 0; i < len(lowered); i++ {
+		for j := i + minDictionaryWordLength; j <= len(lowered); j++ {
+			if _, ok := p.Dictionary[string(lowered[i:j])]; ok {
+				return fmt.Sprintf("Password contains word in the dictionary: %s", string(lowered[i:j]))
+			}
+		}
+	}
+	return ""
+}
+
+// Strength returns the strength of the password from 0 to 100 like the VALIDATE_PASSWORD_STRENGTH function of MySQL:
+// 0 for the passwords shorter than 4 characters, 25 for the ones shorter than the validate_password_length, and 50, 75
+// or 100 for the ones satisfying the low, medium or strong policy. It's always 0 if the validation is disabled.
+func (p *Policy) Strength(password string) int {
+	if !p.Enabled {
+		return 0
+	}
+	length := len([]rune(password))
+	if length < minDictionaryWordLength {
+		return 0
+	}
+	if length < p.Length {
+		return 25
+	}
+	strength := 25
+	for level := PolicyLow; level <= PolicyStrong; level++ {
+		if p.check(password, level) != "" {
+			break
+		}
+		strength += 25
+	}
+	return strength
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
@@ -0,0 +1,92 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package passwordvalidation
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/testleak"
+)
+
+func TestT(t *testing.T) {
+	CustomVerboseFlag = true
+	TestingT(t)
+}
+
+var _ = Suite(&testPasswordValidationSuite{})
+
+type testPasswordValidationSuite struct {
+}
+
+func (s *testPasswordValidationSuite) TestLoadPolicy(c *C) {
+	defer testleak.AfterTest(c)()
+	accessor := variable.NewMockGlobalAccessor()
+	p, err := LoadPolicy(accessor)
+	c.Assert(err, IsNil)
+	c.Assert(p.Enabled, IsFalse)
+	c.Assert(p.Level, Equals, PolicyMedium)
+	c.Assert(p.Length, Equals, 8)
+	c.Assert(p.MinLength(), Equals, 8)
+	c.Assert(p.Dictionary, HasLen, 0)
+	c.Assert(p.Validate("u", ""), Equals, "")
+	c.Assert(p.Strength("Abcd1234!"), Equals, 0)
+
+	c.Assert(ParseDictionary(" Secret ;tidb;abc;;"), DeepEquals, map[string]struct{}{"secret": {}, "tidb": {}})
+}
+
+func (s *testPasswordValidationSuite) TestValidate(c *C) {
+	defer testleak.AfterTest(c)()
+	p := &Policy{
+		Enabled:          true,
+		Level:            PolicyStrong,
+		CheckUserName:    true,
+		Length:           6,
+		MixedCaseCount:   2,
+		NumberCount:      2,
+		SpecialCharCount: 1,
+		Dictionary:       ParseDictionary("password"),
+	}
+	c.Assert(p.MinLength(), Equals, 7)
+	tests := []struct {
+		pwd      string
+		reason   string
+		strength int
+	}{
+		{"abc", "Require Password Length: 7", 0},
+		{"abcdef", "Require Password Length: 7", 25},
+		{"ABCDEFg", "Require Password Lowercase Count: 2", 50},
+		{"abcdefG", "Require Password Uppercase Count: 2", 50},
+		{"ABcdef1", "Require Password Digit Count: 2", 50},
+		{"ABcde12", "Require Password Non-alphanumeric Count: 1", 50},
+		{"ABcd12!", "", 100},
+		{"ABcd12!PassWord", "Password contains word in the dictionary: password", 75},
+		{"ÀÉcd12!", "", 100},
+		{"User-Name", "Password Contains User Name", 50},
+		{"EMAN-RESU", "Password Contains User Name", 50},
+	}
+	for _, t := range tests {
+		c.Assert(p.Validate("user-name", t.pwd), Equals, t.reason, Commentf("%s", t.pwd))
+		c.Assert(p.Strength(t.pwd), Equals, t.strength, Commentf("%s", t.pwd))
+	}
+
+	p.Level = PolicyLow
+	c.Assert(p.MinLength(), Equals, 6)
+	c.Assert(p.Validate("user-name", "abcdef"), Equals, "")
+	p.CheckUserName = false
+	c.Assert(p.Validate("user-name", "user-name"), Equals, "")
+	p.Enabled = false
+	c.Assert(p.Validate("user-name", ""), Equals, "")
+}
@@ -120,6 +120,8 @@ const (
 	TypeIndexFullScan = "IndexFullScan"
 	// TypeIndexRangeScan is the type of IndexRangeScan.
 	TypeIndexRangeScan = "IndexRangeScan"
+	// TypeFullTextReader is the type of FullTextReader.
+	TypeFullTextReader = "FullTextReader"
 )
 
 // plan id.
@@ -174,6 +176,7 @@ const (
 	typeIndexRangeScan        int = 47
 	typeExchangeReceiver      int = 48
 	typeExchangeSender        int = 49
+	typeFullTextReader        int = 50
 )
 
 // TypeStringToPhysicalID converts the plan type string to plan id.
@@ -277,6 +280,8 @@ func TypeStringToPhysicalID(tp string) int {
 		return typeExchangeReceiver
 	case TypeExchangeSender:
 		return typeExchangeSender
+	case TypeFullTextReader:
+		return typeFullTextReader
 	}
 	// Should never reach here.
 	return 0
@@ -381,6 +386,8 @@ func PhysicalIDToTypeString(id int) string {
 		return TypeExchangeReceiver
 	case typeExchangeSender:
 		return TypeExchangeSender
+	case typeFullTextReader:
+		return TypeFullTextReader
 	}
 
 	// Should never reach here.
@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/store/tikv/oracle"
 	"github.com/pingcap/tidb/util/execdetails"
+	"github.com/pingcap/tidb/util/txninfo"
 )
 
 // ProcessInfo is a struct used for show processlist statement.
@@ -46,10 +47,22 @@ type ProcessInfo struct {
 	// MaxExecutionTime is the timeout for select statement, in milliseconds.
 	// If the query takes too long, kill it.
 	MaxExecutionTime uint64
+	// TxnStartTime is the start time of the explicit transaction of the session, it's zero if the session is not in
+	// an explicit transaction.
+	TxnStartTime time.Time
+	// IdleStartTime is the time when the session finished its last command, it's zero if the session is running one.
+	IdleStartTime time.Time
+	// IdleTxnTimeout and MaxTxnDuration are the idle timeout and the max lifetime of the explicit transaction.
+	// The connection is killed if any of them is exceeded.
+	IdleTxnTimeout time.Duration
+	MaxTxnDuration time.Duration
+	// ConnectAttrs are the connection attributes sent by the client in the handshake.
+	ConnectAttrs map[string]string
 
 	State                     uint16
 	Command                   byte
 	ExceedExpensiveTimeThresh bool
+	ExceedTxnTimeout          bool
 	RedactSQL                 bool
 }
 
@@ -107,7 +120,16 @@ func (pi *ProcessInfo) ToRow(tz *time.Location) []interface{} {
 			diskConsumed = pi.StmtCtx.DiskTracker.BytesConsumed()
 		}
 	}
-	return append(pi.ToRowForShow(true), pi.Digest, bytesConsumed, diskConsumed, pi.txnStartTs(tz))
+	return append(pi.ToRowForShow(true), pi.Digest, bytesConsumed, diskConsumed, pi.txnStartTs(tz),
+		pi.connectAttr("program_name"), pi.connectAttr("_client_name"), pi.connectAttr("_client_version"))
+}
+
+// connectAttr returns the connection attribute of the name, or nil if the client doesn't send it.
+func (pi *ProcessInfo) connectAttr(name string) interface{} {
+	if v, ok := pi.ConnectAttrs[name]; ok {
+		return v
+	}
+	return nil
 }
 
 // ascServerStatus is a slice of all defined server status in ascending order.
@@ -161,6 +183,7 @@ func serverStatus2Str(state uint16) string {
 // kill statement rely on this interface.
 type SessionManager interface {
 	ShowProcessList() map[uint64]*ProcessInfo
+	ShowTxnList() []*txninfo.TxnInfo
 	GetProcessInfo(id uint64) (*ProcessInfo, bool)
 	Kill(connectionID uint64, query bool)
 	KillAllConnections()
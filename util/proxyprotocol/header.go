@@ -0,0 +1,304 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxyprotocol
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+)
+
+// Ref: https://www.haproxy.org/download/2.3/doc/proxy-protocol.txt
+const (
+	v1Prefix       = "PROXY "
+	v1MaxHeaderLen = 107
+
+	v2HeaderLen = 16
+	v2Version   = 0x20
+	v2CmdLocal  = 0x00
+	v2CmdProxy  = 0x01
+
+	v2FamilyUnspec = 0x00
+	v2FamilyInet   = 0x10
+	v2FamilyInet6  = 0x20
+	v2FamilyUnix   = 0x30
+	v2TransStream  = 0x01
+
+	v2AddrLenInet  = 12
+	v2AddrLenInet6 = 36
+	v2AddrLenUnix  = 216
+)
+
+var v2Signature = []byte{0x0D, 0x0A, 0x0D, 0x0A, 0x00, 0x0D, 0x0A, 0x51, 0x55, 0x49, 0x54, 0x0A}
+
+// The types of the TLVs in the v2 header.
+const (
+	TLVTypeALPN      = 0x01
+	TLVTypeAuthority = 0x02
+	TLVTypeCRC32C    = 0x03
+	TLVTypeNoop      = 0x04
+	TLVTypeUniqueID  = 0x05
+	TLVTypeSSL       = 0x20
+	TLVTypeNetNS     = 0x30
+)
+
+// The sub-types of the TLVs in the value of TLVTypeSSL.
+const (
+	TLVSubtypeSSLVersion = 0x21
+	TLVSubtypeSSLCN      = 0x22
+	TLVSubtypeSSLCipher  = 0x23
+	TLVSubtypeSSLSigAlg  = 0x24
+	TLVSubtypeSSLKeyAlg  = 0x25
+)
+
+// TLV is a type-length-value extension of the v2 header.
+type TLV struct {
+	Type  byte
+	Value []byte
+}
+
+var (
+	// ErrHeaderInvalid is returned if the header is malformed.
+	ErrHeaderInvalid = errors.New("PROXY protocol header is invalid")
+	// ErrHeaderReadTimeout is returned if the header isn't received in the header timeout.
+	ErrHeaderReadTimeout = errors.New("PROXY protocol header read timeout")
+)
+
+// IsProxyProtocolError checks whether the error is caused by the header of a connection, the listener can keep
+// accepting the other connections after such errors.
+func IsProxyProtocolError(err error) bool {
+	cause := errors.Cause(err)
+	return cause == ErrHeaderInvalid || cause == ErrHeaderReadTimeout
+}
+
+// header is the parsed PROXY protocol header. The addresses are nil if the header doesn't carry the addresses,
+// e.g. the LOCAL command sent by the health checks of the proxy.
+type header struct {
+	version int
+	srcAddr net.Addr
+	dstAddr net.Addr
+	tlvs    []TLV
+}
+
+// readHeader reads a v1 or v2 header from the reader, only the bytes of the header are consumed.
+func readHeader(r *bufio.Reader) (*header, error) {
+	// The shortest header is "PROXY UNKNOWN\r\n".
+	prefix, err := r.Peek(len(v1Prefix))
+	if err != nil {
+		return nil, readError(err)
+	}
+	if string(prefix) == v1Prefix {
+		return readV1Header(r)
+	}
+	sig, err := r.Peek(len(v2Signature))
+	if err != nil {
+		return nil, readError(err)
+	}
+	if bytes.Equal(sig, v2Signature) {
+		return readV2Header(r)
+	}
+	return nil, errors.Annotate(ErrHeaderInvalid, "unknown signature")
+}
+
+func readError(err error) error {
+	if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+		return errors.Trace(ErrHeaderReadTimeout)
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return errors.Annotate(ErrHeaderInvalid, "unexpected EOF")
+	}
+	return errors.Trace(err)
+}
+
+// readV1Header reads the human-readable header, e.g. "PROXY TCP4 192.168.0.1 192.168.0.11 56324 443\r\n".
+func readV1Header(r *bufio.Reader) (*header, error) {
+	var line []byte
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, readError(err)
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+		if len(line) >= v1MaxHeaderLen {
+			return nil, errors.Annotate(ErrHeaderInvalid, "v1 header is too long")
+		}
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return nil, errors.Annotate(ErrHeaderInvalid, "v1 header is not terminated by CRLF")
+	}
+	parts := strings.Split(string(line[len(v1Prefix):len(line)-2]), " ")
+	h := &header{version: 1}
+	switch parts[0] {
+	case "UNKNOWN":
+		// The rest of the line is ignored.
+		return h, nil
+	case "TCP4", "TCP6":
+	default:
+		return nil, errors.Annotatef(ErrHeaderInvalid, "unknown v1 protocol %s", parts[0])
+	}
+	if len(parts) != 5 {
+		return nil, errors.Annotate(ErrHeaderInvalid, "wrong number of v1 fields")
+	}
+	src, err := parseV1Addr(parts[0], parts[1], parts[3])
+	if err != nil {
+		return nil, err
+	}
+	dst, err := parseV1Addr(parts[0], parts[2], parts[4])
+	if err != nil {
+		return nil, err
+	}
+	h.srcAddr, h.dstAddr = src, dst
+	return h, nil
+}
+
+func parseV1Addr(protocol, ip, port string) (*net.TCPAddr, error) {
+	addr := net.ParseIP(ip)
+	if addr == nil || (protocol == "TCP4") != (addr.To4() != nil) {
+		return nil, errors.Annotatef(ErrHeaderInvalid, "invalid v1 address %s", ip)
+	}
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return nil, errors.Annotatef(ErrHeaderInvalid, "invalid v1 port %s", port)
+	}
+	return &net.TCPAddr{IP: addr, Port: int(p)}, nil
+}
+
+// readV2Header reads the binary header, which is the signature, the version and command, the family and transport,
+// the length of the rest, the addresses and the TLVs.
+func readV2Header(r *bufio.Reader) (*header, error) {
+	buf := make([]byte, v2HeaderLen)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, readError(err)
+	}
+	verCmd, famTrans := buf[12], buf[13]
+	if verCmd&0xF0 != v2Version {
+		return nil, errors.Annotatef(ErrHeaderInvalid, "unknown version %#x", verCmd>>4)
+	}
+	length := int(binary.BigEndian.Uint16(buf[14:16]))
+	buf = append(buf, make([]byte, length)...)
+	if _, err := io.ReadFull(r, buf[v2HeaderLen:]); err != nil {
+		return nil, readError(err)
+	}
+	payload := buf[v2HeaderLen:]
+
+	h := &header{version: 2}
+	var addrLen int
+	switch cmd := verCmd & 0x0F; cmd {
+	case v2CmdLocal:
+		// The connection is established by the proxy itself, e.g. the health checks, the whole block is discarded.
+		return h, nil
+	case v2CmdProxy:
+		family := famTrans & 0xF0
+		switch family {
+		case v2FamilyInet:
+			addrLen = v2AddrLenInet
+		case v2FamilyInet6:
+			addrLen = v2AddrLenInet6
+		case v2FamilyUnix:
+			addrLen = v2AddrLenUnix
+		case v2FamilyUnspec:
+		default:
+			return nil, errors.Annotatef(ErrHeaderInvalid, "unknown address family %#x", famTrans>>4)
+		}
+		if len(payload) < addrLen {
+			return nil, errors.Annotate(ErrHeaderInvalid, "v2 addresses are truncated")
+		}
+		// Only the TCP addresses are used, the others are skipped like the unspecified family.
+		if (family == v2FamilyInet || family == v2FamilyInet6) && famTrans&0x0F == v2TransStream {
+			ipLen := (addrLen - 4) / 2
+			h.srcAddr = &net.TCPAddr{
+				IP:   net.IP(append([]byte(nil), payload[:ipLen]...)),
+				Port: int(binary.BigEndian.Uint16(payload[2*ipLen:])),
+			}
+			h.dstAddr = &net.TCPAddr{
+				IP:   net.IP(append([]byte(nil), payload[ipLen:2*ipLen]...)),
+				Port: int(binary.BigEndian.Uint16(payload[2*ipLen+2:])),
+			}
+		}
+	default:
+		return nil, errors.Annotatef(ErrHeaderInvalid, "unknown command %#x", cmd)
+	}
+
+	tlvs, err := parseTLVs(payload[addrLen:])
+	if err != nil {
+		return nil, err
+	}
+	// The checksum is dropped after it's verified.
+	for _, tlv := range tlvs {
+		if tlv.Type != TLVTypeCRC32C {
+			h.tlvs = append(h.tlvs, tlv)
+		} else if err := checkCRC32C(buf, tlv); err != nil {
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+func parseTLVs(data []byte) ([]TLV, error) {
+	var tlvs []TLV
+	for len(data) > 0 {
+		if len(data) < 3 {
+			return nil, errors.Annotate(ErrHeaderInvalid, "TLV is truncated")
+		}
+		length := int(binary.BigEndian.Uint16(data[1:3]))
+		if len(data) < 3+length {
+			return nil, errors.Annotate(ErrHeaderInvalid, "TLV is truncated")
+		}
+		tlvs = append(tlvs, TLV{Type: data[0], Value: data[3 : 3+length]})
+		data = data[3+length:]
+	}
+	return tlvs, nil
+}
+
+// checkCRC32C checks the checksum of the whole header, which is calculated with the value of the TLV set to zeros.
+func checkCRC32C(buf []byte, tlv TLV) error {
+	if len(tlv.Value) != 4 {
+		return errors.Annotate(ErrHeaderInvalid, "invalid CRC32C TLV")
+	}
+	expected := binary.BigEndian.Uint32(tlv.Value)
+	data := append([]byte(nil), buf...)
+	// The value is a sub-slice of buf, so its offset in buf is the offset in data.
+	offset := cap(buf) - cap(tlv.Value)
+	copy(data[offset:offset+4], []byte{0, 0, 0, 0})
+	if crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli)) != expected {
+		return errors.Annotate(ErrHeaderInvalid, "CRC32C mismatch")
+	}
+	return nil
+}
+
+// SSLInfo returns the sub-TLVs of the SSL TLV and whether the client connected to the proxy over SSL/TLS.
+func SSLInfo(tlvs []TLV) (subTLVs []TLV, ssl bool, err error) {
+	for _, tlv := range tlvs {
+		if tlv.Type != TLVTypeSSL {
+			continue
+		}
+		// The value is the client flags (1 byte), the verify result (4 bytes) and the sub-TLVs.
+		if len(tlv.Value) < 5 {
+			return nil, false, errors.Annotate(ErrHeaderInvalid, "invalid SSL TLV")
+		}
+		subTLVs, err = parseTLVs(tlv.Value[5:])
+		return subTLVs, tlv.Value[0]&0x01 != 0, err
+	}
+	return nil, false, nil
+}
@@ -0,0 +1,258 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxyprotocol
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+)
+
+// Listener reads the PROXY protocol headers of the connections from the allowed frontends, the header is required
+// for these connections. The connections from the other addresses are accepted as they are.
+type Listener struct {
+	listener      net.Listener
+	allowAll      bool
+	allowedNets   []*net.IPNet
+	headerTimeout time.Duration
+
+	acceptCh  chan acceptResult
+	closeCh   chan struct{}
+	closeOnce sync.Once
+}
+
+type acceptResult struct {
+	conn net.Conn
+	err  error
+}
+
+// NewListener creates a PROXY protocol listener. The allowed frontends are the IP addresses or the CIDRs separated by
+// commas, "*" means all the addresses. The header must be received in the header timeout.
+func NewListener(listener net.Listener, allowed string, headerTimeout time.Duration) (*Listener, error) {
+	l := &Listener{
+		listener:      listener,
+		headerTimeout: headerTimeout,
+		acceptCh:      make(chan acceptResult),
+		closeCh:       make(chan struct{}),
+	}
+	var err error
+	if l.allowAll, l.allowedNets, err = ParseNetworks(allowed); err != nil {
+		return nil, err
+	}
+	go l.acceptLoop()
+	return l, nil
+}
+
+// ParseNetworks parses the IP addresses or the CIDRs separated by commas, "*" means all the addresses.
+func ParseNetworks(networks string) (allowAll bool, nets []*net.IPNet, err error) {
+	for _, network := range strings.Split(networks, ",") {
+		network = strings.TrimSpace(network)
+		switch {
+		case network == "":
+		case network == "*":
+			allowAll = true
+		case strings.Contains(network, "/"):
+			_, ipNet, err := net.ParseCIDR(network)
+			if err != nil {
+				return false, nil, errors.Trace(err)
+			}
+			nets = append(nets, ipNet)
+		default:
+			ip := net.ParseIP(network)
+			if ip == nil {
+				return false, nil, errors.Errorf("invalid IP address %s", network)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		}
+	}
+	return allowAll, nets, nil
+}
+
+func (l *Listener) allowed(addr net.Addr) bool {
+	if l.allowAll {
+		return true
+	}
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	for _, ipNet := range l.allowedNets {
+		if ipNet.Contains(tcpAddr.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptLoop accepts the connections, the headers are read in their own goroutines so a slow frontend doesn't block
+// the others.
+func (l *Listener) acceptLoop() {
+	for {
+		conn, err := l.listener.Accept()
+		if err != nil {
+			l.deliver(nil, err)
+			if isClosedError(err) {
+				return
+			}
+			continue
+		}
+		if !l.allowed(conn.RemoteAddr()) {
+			l.deliver(conn, nil)
+			continue
+		}
+		go func() {
+			ppConn, err := newConn(conn, l.headerTimeout)
+			if err != nil {
+				// The connection is closed for the invalid header, the error is returned by Accept.
+				_ = conn.Close()
+				l.deliver(nil, errors.Annotatef(err, "PROXY protocol header from %s", conn.RemoteAddr()))
+				return
+			}
+			l.deliver(ppConn, nil)
+		}()
+	}
+}
+
+func (l *Listener) deliver(conn net.Conn, err error) {
+	select {
+	case l.acceptCh <- acceptResult{conn: conn, err: err}:
+	case <-l.closeCh:
+		if conn != nil {
+			_ = conn.Close()
+		}
+	}
+}
+
+func isClosedError(err error) bool {
+	opErr, ok := err.(*net.OpError)
+	return ok && opErr.Err.Error() == "use of closed network connection"
+}
+
+// Accept implements net.Listener interface. The error is a PROXY protocol error if the header of a connection is
+// invalid, the listener can keep accepting the other connections.
+func (l *Listener) Accept() (net.Conn, error) {
+	select {
+	case r := <-l.acceptCh:
+		return r.conn, r.err
+	case <-l.closeCh:
+		return nil, &net.OpError{Op: "accept", Net: l.Addr().Network(), Addr: l.Addr(), Err: errListenerClosed}
+	}
+}
+
+var errListenerClosed = errors.New("use of closed network connection")
+
+// Close implements net.Listener interface.
+func (l *Listener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.closeCh)
+	})
+	return l.listener.Close()
+}
+
+// Addr implements net.Listener interface.
+func (l *Listener) Addr() net.Addr {
+	return l.listener.Addr()
+}
+
+// Conn is a connection whose PROXY protocol header is read, the remote address is the address of the client.
+type Conn struct {
+	net.Conn
+	// reader holds the bytes received after the header, it's dropped after they're read.
+	reader     *bufio.Reader
+	srcAddr    net.Addr
+	dstAddr    net.Addr
+	version    int
+	tlvs       []TLV
+	remoteAddr net.Addr
+}
+
+func newConn(conn net.Conn, headerTimeout time.Duration) (*Conn, error) {
+	if err := conn.SetReadDeadline(time.Now().Add(headerTimeout)); err != nil {
+		return nil, errors.Trace(err)
+	}
+	r := bufio.NewReader(conn)
+	h, err := readHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	if err = conn.SetReadDeadline(time.Time{}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	c := &Conn{
+		Conn:       conn,
+		reader:     r,
+		srcAddr:    h.srcAddr,
+		dstAddr:    h.dstAddr,
+		version:    h.version,
+		tlvs:       h.tlvs,
+		remoteAddr: conn.RemoteAddr(),
+	}
+	if r.Buffered() == 0 {
+		c.reader = nil
+	}
+	return c, nil
+}
+
+// Read implements net.Conn interface.
+func (c *Conn) Read(b []byte) (int, error) {
+	if c.reader != nil {
+		n, err := c.reader.Read(b)
+		if c.reader.Buffered() == 0 {
+			c.reader = nil
+		}
+		return n, err
+	}
+	return c.Conn.Read(b)
+}
+
+// RemoteAddr returns the address of the client, it's the address of the frontend if the header doesn't carry the
+// addresses, e.g. the LOCAL command or the UNKNOWN protocol.
+func (c *Conn) RemoteAddr() net.Addr {
+	if c.srcAddr != nil {
+		return c.srcAddr
+	}
+	return c.remoteAddr
+}
+
+// LocalAddr returns the address which the client connects to, it's the local address of the connection if the
+// header doesn't carry the addresses.
+func (c *Conn) LocalAddr() net.Addr {
+	if c.dstAddr != nil {
+		return c.dstAddr
+	}
+	return c.Conn.LocalAddr()
+}
+
+// ProxyAddr returns the address of the frontend which sends the header.
+func (c *Conn) ProxyAddr() net.Addr {
+	return c.remoteAddr
+}
+
+// Version returns the version of the header, which is 1 or 2.
+func (c *Conn) Version() int {
+	return c.version
+}
+
+// TLVs returns the TLV extensions of the v2 header.
+func (c *Conn) TLVs() []TLV {
+	return c.tlvs
+}
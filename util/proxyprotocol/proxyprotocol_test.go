@@ -0,0 +1,287 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proxyprotocol
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/errors"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testProxyProtocolSuite{})
+
+type testProxyProtocolSuite struct{}
+
+// buildV2Header builds a v2 header with the addresses and the TLVs, the CRC32C TLV is appended if crc is true.
+func buildV2Header(cmd, famTrans byte, addrs []byte, tlvs []TLV, crc bool) []byte {
+	var payload []byte
+	payload = append(payload, addrs...)
+	for _, tlv := range tlvs {
+		payload = append(payload, tlv.Type, byte(len(tlv.Value)>>8), byte(len(tlv.Value)))
+		payload = append(payload, tlv.Value...)
+	}
+	if crc {
+		payload = append(payload, TLVTypeCRC32C, 0, 4, 0, 0, 0, 0)
+	}
+	buf := append([]byte(nil), v2Signature...)
+	buf = append(buf, v2Version|cmd, famTrans, byte(len(payload)>>8), byte(len(payload)))
+	buf = append(buf, payload...)
+	if crc {
+		checksum := crc32.Checksum(buf, crc32.MakeTable(crc32.Castagnoli))
+		binary.BigEndian.PutUint32(buf[len(buf)-4:], checksum)
+	}
+	return buf
+}
+
+func inetAddrs(src, dst string, srcPort, dstPort uint16) []byte {
+	var addrs []byte
+	srcIP, dstIP := net.ParseIP(src), net.ParseIP(dst)
+	if srcIP.To4() != nil {
+		srcIP, dstIP = srcIP.To4(), dstIP.To4()
+	}
+	addrs = append(addrs, srcIP...)
+	addrs = append(addrs, dstIP...)
+	addrs = append(addrs, byte(srcPort>>8), byte(srcPort), byte(dstPort>>8), byte(dstPort))
+	return addrs
+}
+
+func (s *testProxyProtocolSuite) TestReadHeader(c *C) {
+	inet := inetAddrs("192.168.1.1", "10.0.0.1", 51234, 4000)
+	inet6 := inetAddrs("fe80::1", "fe80::2", 51234, 4000)
+	tests := []struct {
+		data    []byte
+		version int
+		src     string
+		dst     string
+		tlvs    []TLV
+		err     string
+	}{
+		{[]byte("PROXY TCP4 192.168.1.1 10.0.0.1 51234 4000\r\n"), 1, "192.168.1.1:51234", "10.0.0.1:4000", nil, ""},
+		{[]byte("PROXY TCP6 fe80::1 fe80::2 51234 4000\r\n"), 1, "[fe80::1]:51234", "[fe80::2]:4000", nil, ""},
+		{[]byte("PROXY UNKNOWN ffff::1 ffff::2 1 2\r\n"), 1, "", "", nil, ""},
+		{[]byte("PROXY TCP4 fe80::1 10.0.0.1 51234 4000\r\n"), 0, "", "", nil, ".*invalid v1 address fe80::1.*"},
+		{[]byte("PROXY TCP4 192.168.1.1 10.0.0.1 51234 65536\r\n"), 0, "", "", nil, ".*invalid v1 port 65536.*"},
+		{[]byte("PROXY TCP4 192.168.1.1 10.0.0.1 51234\r\n"), 0, "", "", nil, ".*wrong number of v1 fields.*"},
+		{[]byte("PROXY UDP4 192.168.1.1 10.0.0.1 51234 4000\r\n"), 0, "", "", nil, ".*unknown v1 protocol UDP4.*"},
+		{[]byte("PROXY TCP4 192.168.1.1 10.0.0.1 51234 4000\n"), 0, "", "", nil, ".*not terminated by CRLF.*"},
+		{append([]byte("PROXY UNKNOWN "), bytes.Repeat([]byte("a"), 100)...), 0, "", "", nil, ".*v1 header is too long.*"},
+		{[]byte("PROXY TCP4 192.168.1.1"), 0, "", "", nil, ".*unexpected EOF.*"},
+		{[]byte("GET / HTTP/1.1\r\n"), 0, "", "", nil, ".*unknown signature.*"},
+		{buildV2Header(v2CmdProxy, v2FamilyInet|v2TransStream, inet, nil, false), 2, "192.168.1.1:51234", "10.0.0.1:4000", nil, ""},
+		{buildV2Header(v2CmdProxy, v2FamilyInet6|v2TransStream, inet6, nil, false), 2, "[fe80::1]:51234", "[fe80::2]:4000", nil, ""},
+		{
+			buildV2Header(v2CmdProxy, v2FamilyInet|v2TransStream, inet, []TLV{{TLVTypeAuthority, []byte("tidb.example.com")}, {TLVTypeUniqueID, []byte{1, 2}}}, false),
+			2, "192.168.1.1:51234", "10.0.0.1:4000",
+			[]TLV{{TLVTypeAuthority, []byte("tidb.example.com")}, {TLVTypeUniqueID, []byte{1, 2}}}, "",
+		},
+		{
+			buildV2Header(v2CmdProxy, v2FamilyInet|v2TransStream, inet, []TLV{{TLVTypeNoop, nil}}, true),
+			2, "192.168.1.1:51234", "10.0.0.1:4000", []TLV{{TLVTypeNoop, []byte{}}}, "",
+		},
+		// The UDP and the unix addresses are skipped.
+		{buildV2Header(v2CmdProxy, v2FamilyInet|0x02, inet, nil, false), 2, "", "", nil, ""},
+		{buildV2Header(v2CmdProxy, v2FamilyUnix|v2TransStream, make([]byte, v2AddrLenUnix), nil, false), 2, "", "", nil, ""},
+		{buildV2Header(v2CmdProxy, v2FamilyUnspec, nil, nil, false), 2, "", "", nil, ""},
+		// The block of the LOCAL command is discarded.
+		{buildV2Header(v2CmdLocal, v2FamilyInet|v2TransStream, inet, []TLV{{TLVTypeNoop, []byte{1}}}, false), 2, "", "", nil, ""},
+		{buildV2Header(0x02, v2FamilyInet|v2TransStream, inet, nil, false), 0, "", "", nil, ".*unknown command 0x2.*"},
+		{buildV2Header(v2CmdProxy, 0x40|v2TransStream, inet, nil, false), 0, "", "", nil, ".*unknown address family 0x4.*"},
+		{buildV2Header(v2CmdProxy, v2FamilyInet6|v2TransStream, inet, nil, false), 0, "", "", nil, ".*v2 addresses are truncated.*"},
+		{buildV2Header(v2CmdProxy, v2FamilyInet|v2TransStream, append(inet, TLVTypeNoop, 0, 2, 0), nil, false), 0, "", "", nil, ".*TLV is truncated.*"},
+		{buildV2Header(v2CmdProxy, v2FamilyInet|v2TransStream, inet, nil, false)[:20], 0, "", "", nil, ".*unexpected EOF.*"},
+	}
+	for i, t := range tests {
+		comment := Commentf("case %d", i)
+		h, err := readHeader(bufio.NewReader(bytes.NewReader(t.data)))
+		if t.err != "" {
+			c.Assert(err, ErrorMatches, t.err, comment)
+			c.Assert(IsProxyProtocolError(err), IsTrue, comment)
+			continue
+		}
+		c.Assert(err, IsNil, comment)
+		c.Assert(h.version, Equals, t.version, comment)
+		if t.src == "" {
+			c.Assert(h.srcAddr, IsNil, comment)
+			c.Assert(h.dstAddr, IsNil, comment)
+		} else {
+			c.Assert(h.srcAddr.String(), Equals, t.src, comment)
+			c.Assert(h.dstAddr.String(), Equals, t.dst, comment)
+		}
+		c.Assert(h.tlvs, DeepEquals, t.tlvs, comment)
+	}
+
+	// The version must be 2.
+	data := buildV2Header(v2CmdProxy, v2FamilyInet|v2TransStream, inet, nil, false)
+	data[12] = 0x11
+	_, err := readHeader(bufio.NewReader(bytes.NewReader(data)))
+	c.Assert(err, ErrorMatches, ".*unknown version 0x1.*")
+
+	// The checksum is verified.
+	data = buildV2Header(v2CmdProxy, v2FamilyInet|v2TransStream, inet, nil, true)
+	data[v2HeaderLen] ^= 0xFF
+	_, err = readHeader(bufio.NewReader(bytes.NewReader(data)))
+	c.Assert(err, ErrorMatches, ".*CRC32C mismatch.*")
+}
+
+func (s *testProxyProtocolSuite) TestSSLInfo(c *C) {
+	value := []byte{0x01, 0, 0, 0, 0}
+	value = append(value, TLVSubtypeSSLVersion, 0, 7)
+	value = append(value, "TLSv1.3"...)
+	value = append(value, TLVSubtypeSSLCN, 0, 4)
+	value = append(value, "tidb"...)
+	subTLVs, ssl, err := SSLInfo([]TLV{{TLVTypeAuthority, []byte("a")}, {TLVTypeSSL, value}})
+	c.Assert(err, IsNil)
+	c.Assert(ssl, IsTrue)
+	c.Assert(subTLVs, DeepEquals, []TLV{{TLVSubtypeSSLVersion, []byte("TLSv1.3")}, {TLVSubtypeSSLCN, []byte("tidb")}})
+
+	subTLVs, ssl, err = SSLInfo([]TLV{{TLVTypeAuthority, []byte("a")}})
+	c.Assert(err, IsNil)
+	c.Assert(ssl, IsFalse)
+	c.Assert(subTLVs, IsNil)
+
+	_, _, err = SSLInfo([]TLV{{TLVTypeSSL, []byte{1}}})
+	c.Assert(err, ErrorMatches, ".*invalid SSL TLV.*")
+}
+
+func (s *testProxyProtocolSuite) TestParseNetworks(c *C) {
+	allowAll, nets, err := ParseNetworks("*")
+	c.Assert(err, IsNil)
+	c.Assert(allowAll, IsTrue)
+	c.Assert(nets, HasLen, 0)
+
+	allowAll, nets, err = ParseNetworks("192.168.1.0/24, 10.0.0.1,fe80::1,fd00::/8")
+	c.Assert(err, IsNil)
+	c.Assert(allowAll, IsFalse)
+	c.Assert(nets, HasLen, 4)
+	l := &Listener{allowedNets: nets}
+	for _, t := range []struct {
+		ip      string
+		allowed bool
+	}{
+		{"192.168.1.100", true},
+		{"192.168.2.1", false},
+		{"10.0.0.1", true},
+		{"10.0.0.2", false},
+		{"fe80::1", true},
+		{"fe80::2", false},
+		{"fd12::1", true},
+	} {
+		c.Assert(l.allowed(&net.TCPAddr{IP: net.ParseIP(t.ip)}), Equals, t.allowed, Commentf("%s", t.ip))
+	}
+	c.Assert(l.allowed(&net.UnixAddr{Name: "/tmp/tidb.sock"}), IsFalse)
+
+	_, _, err = ParseNetworks("192.168.1.0/33")
+	c.Assert(err, NotNil)
+	_, _, err = ParseNetworks("192.168.1")
+	c.Assert(err, ErrorMatches, "invalid IP address 192.168.1")
+}
+
+func (s *testProxyProtocolSuite) TestListener(c *C) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	l, err := NewListener(ln, "127.0.0.1", 200*time.Millisecond)
+	c.Assert(err, IsNil)
+
+	// The bytes sent right after the header are readable.
+	header := buildV2Header(v2CmdProxy, v2FamilyInet|v2TransStream, inetAddrs("192.168.1.1", "10.0.0.1", 51234, 4000),
+		[]TLV{{TLVTypeAuthority, []byte("tidb")}}, true)
+	client, err := net.Dial("tcp", ln.Addr().String())
+	c.Assert(err, IsNil)
+	_, err = client.Write(append(header, "hello"...))
+	c.Assert(err, IsNil)
+	c.Assert(client.(*net.TCPConn).CloseWrite(), IsNil)
+	conn, err := l.Accept()
+	c.Assert(err, IsNil)
+	ppConn := conn.(*Conn)
+	c.Assert(ppConn.RemoteAddr().String(), Equals, "192.168.1.1:51234")
+	c.Assert(ppConn.LocalAddr().String(), Equals, "10.0.0.1:4000")
+	c.Assert(ppConn.ProxyAddr().String(), Equals, client.LocalAddr().String())
+	c.Assert(ppConn.Version(), Equals, 2)
+	c.Assert(ppConn.TLVs(), DeepEquals, []TLV{{TLVTypeAuthority, []byte("tidb")}})
+	data, err := ioutil.ReadAll(conn)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "hello")
+	c.Assert(conn.Close(), IsNil)
+	c.Assert(client.Close(), IsNil)
+
+	// The LOCAL command uses the real addresses.
+	client, err = net.Dial("tcp", ln.Addr().String())
+	c.Assert(err, IsNil)
+	_, err = client.Write(buildV2Header(v2CmdLocal, v2FamilyUnspec, nil, nil, false))
+	c.Assert(err, IsNil)
+	conn, err = l.Accept()
+	c.Assert(err, IsNil)
+	c.Assert(conn.RemoteAddr().String(), Equals, client.LocalAddr().String())
+	c.Assert(conn.LocalAddr().String(), Equals, ln.Addr().String())
+	c.Assert(conn.Close(), IsNil)
+	c.Assert(client.Close(), IsNil)
+
+	// The invalid header and the timeout are reported by Accept, the listener keeps working.
+	client, err = net.Dial("tcp", ln.Addr().String())
+	c.Assert(err, IsNil)
+	_, err = client.Write([]byte("GET / HTTP/1.1\r\n"))
+	c.Assert(err, IsNil)
+	_, err = l.Accept()
+	c.Assert(IsProxyProtocolError(err), IsTrue)
+	c.Assert(client.Close(), IsNil)
+	client, err = net.Dial("tcp", ln.Addr().String())
+	c.Assert(err, IsNil)
+	_, err = l.Accept()
+	c.Assert(IsProxyProtocolError(err), IsTrue)
+	c.Assert(errors.Cause(err), Equals, ErrHeaderReadTimeout)
+	c.Assert(client.Close(), IsNil)
+
+	c.Assert(l.Close(), IsNil)
+	_, err = l.Accept()
+	opErr, ok := err.(*net.OpError)
+	c.Assert(ok, IsTrue)
+	c.Assert(opErr.Err.Error(), Equals, "use of closed network connection")
+}
+
+func (s *testProxyProtocolSuite) TestListenerNotAllowed(c *C) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	l, err := NewListener(ln, "192.168.1.0/24", time.Second)
+	c.Assert(err, IsNil)
+	defer l.Close()
+
+	// The connections from the other addresses are accepted as they are, the header isn't parsed.
+	client, err := net.Dial("tcp", ln.Addr().String())
+	c.Assert(err, IsNil)
+	defer client.Close()
+	_, err = client.Write([]byte("PROXY TCP4 192.168.1.1 10.0.0.1 51234 4000\r\n"))
+	c.Assert(err, IsNil)
+	conn, err := l.Accept()
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	_, ok := conn.(*Conn)
+	c.Assert(ok, IsFalse)
+	c.Assert(conn.RemoteAddr().String(), Equals, client.LocalAddr().String())
+	data := make([]byte, 5)
+	_, err = conn.Read(data)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "PROXY")
+}
@@ -105,7 +105,11 @@ func (rd *RowDecoder) DecodeAndEvalRowWithMap(ctx sessionctx.Context, handle kv.
 		colInfo := dCol.Col.ColumnInfo
 		val, ok := row[colInfo.ID]
 		if ok || dCol.GenExpr != nil {
-			rd.mutRow.SetValue(colInfo.Offset, val.GetValue())
+			// The value of the changing column is decoded with the new type, it's not consistent with the type in the mutRow.
+			// Since it's not referenced by the generated columns, skip it.
+			if dCol.Col.ChangeStateInfo == nil || !ok {
+				rd.mutRow.SetValue(colInfo.Offset, val.GetValue())
+			}
 			continue
 		}
 		if dCol.Col.ChangeStateInfo != nil {
@@ -99,6 +99,17 @@ type SQLParser interface {
 	ParseSQL(sql, charset, collation string) ([]ast.StmtNode, error)
 }
 
+// XATxnExecutor is an interface provides the XA transactions, the session is its implementation.
+// See https://dev.mysql.com/doc/refman/5.7/en/xa-statements.html
+type XATxnExecutor interface {
+	XAStart(ctx context.Context, xid ast.XID) error
+	XAEnd(xid ast.XID) error
+	XAPrepare(ctx context.Context, xid ast.XID) error
+	XACommit(ctx context.Context, xid ast.XID, onePhase bool) error
+	XARollback(ctx context.Context, xid ast.XID) error
+	XARecover(ctx context.Context) ([]ast.XID, error)
+}
+
 // Statement is an interface for SQL execution.
 // NOTE: all Statement implementations must be safe for
 // concurrent using by multiple goroutines.
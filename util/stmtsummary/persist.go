@@ -0,0 +1,161 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stmtsummary
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// persistBatchSize is the max number of the summaries written in an INSERT statement.
+const persistBatchSize = 64
+
+// persistedSummary is a row of mysql.statements_summary_history without the instance.
+type persistedSummary struct {
+	row        []types.Datum
+	sampleUser string
+}
+
+// collectHistoryToPersist collects the history summaries which have ended before now and haven't been persisted.
+func (ssMap *stmtSummaryByDigestMap) collectHistoryToPersist(now int64) ([][]types.Datum, []*stmtSummaryByDigestElement) {
+	ssMap.Lock()
+	values := ssMap.summaryMap.Values()
+	ssMap.Unlock()
+
+	historySize := ssMap.historySize()
+	var rows [][]types.Datum
+	var elements []*stmtSummaryByDigestElement
+	for _, value := range values {
+		ssbd := value.(*stmtSummaryByDigest)
+		for _, ssElement := range ssbd.collectHistorySummaries(historySize) {
+			ssElement.Lock()
+			ended := !ssElement.persisted && ssElement.endTime <= now
+			ssElement.Unlock()
+			if ended {
+				rows = append(rows, ssElement.toDatum(ssbd))
+				elements = append(elements, ssElement)
+			}
+		}
+	}
+	return rows, elements
+}
+
+func (ssMap *stmtSummaryByDigestMap) setPersistedHistory(history []persistedSummary) {
+	ssMap.Lock()
+	defer ssMap.Unlock()
+	ssMap.persistedHistory = history
+}
+
+// PersistHistory writes the history summaries of the instance which have ended and haven't been persisted to
+// mysql.statements_summary_history.
+func PersistHistory(ctx context.Context, exec sqlexec.RestrictedSQLExecutor, instance string) error {
+	rows, elements := StmtSummaryByDigestMap.collectHistoryToPersist(time.Now().Unix())
+	for len(rows) > 0 {
+		n := len(rows)
+		if n > persistBatchSize {
+			n = persistBatchSize
+		}
+		var sql strings.Builder
+		args := make([]interface{}, 0, n*(len(rows[0])+1))
+		sql.WriteString("INSERT HIGH_PRIORITY INTO mysql.statements_summary_history VALUES ")
+		for i, row := range rows[:n] {
+			if i > 0 {
+				sql.WriteString(", ")
+			}
+			sql.WriteString("(%?")
+			args = append(args, instance)
+			for _, d := range row {
+				sql.WriteString(", %?")
+				args = append(args, datumToArg(d))
+			}
+			sql.WriteString(")")
+		}
+		stmt, err := exec.ParseWithParams(ctx, sql.String(), args...)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if _, _, err = exec.ExecRestrictedStmt(ctx, stmt); err != nil {
+			return errors.Trace(err)
+		}
+		// Mark the summaries after they're written, so they're written again next time if it fails.
+		for _, ssElement := range elements[:n] {
+			ssElement.Lock()
+			ssElement.persisted = true
+			ssElement.Unlock()
+		}
+		rows, elements = rows[n:], elements[n:]
+	}
+	return nil
+}
+
+func datumToArg(d types.Datum) interface{} {
+	if d.Kind() == types.KindMysqlTime {
+		return d.GetMysqlTime().String()
+	}
+	return d.GetValue()
+}
+
+// LoadPersistedHistory loads the history summaries persisted by the instance before it restarts, so they're shown
+// in statements_summary_history again. Only the history in the window of history-size intervals is loaded, the
+// older ones can be queried in mysql.statements_summary_history.
+func LoadPersistedHistory(ctx context.Context, exec sqlexec.RestrictedSQLExecutor, instance string) error {
+	window := int64(StmtSummaryByDigestMap.historySize()) * StmtSummaryByDigestMap.refreshInterval()
+	since := time.Unix(time.Now().Unix()-window, 0)
+	stmt, err := exec.ParseWithParams(ctx, "SELECT * FROM mysql.statements_summary_history WHERE instance = %? AND summary_end_time > %? ORDER BY summary_begin_time",
+		instance, since)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	rows, fields, err := exec.ExecRestrictedStmt(ctx, stmt)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	fieldTypes := make([]*types.FieldType, 0, len(fields))
+	sampleUserIdx := -1
+	for i, field := range fields {
+		fieldTypes = append(fieldTypes, &field.Column.FieldType)
+		if strings.EqualFold(field.Column.Name.O, "sample_user") {
+			sampleUserIdx = i
+		}
+	}
+	history := make([]persistedSummary, 0, len(rows))
+	for _, row := range rows {
+		summary := persistedSummary{row: row.GetDatumRow(fieldTypes)[1:]}
+		if sampleUserIdx >= 0 && !row.IsNull(sampleUserIdx) {
+			summary.sampleUser = row.GetString(sampleUserIdx)
+		}
+		history = append(history, summary)
+	}
+	StmtSummaryByDigestMap.setPersistedHistory(history)
+	return nil
+}
+
+// DeleteExpiredHistory deletes the persisted history summaries of all the instances which have ended maxDays ago.
+func DeleteExpiredHistory(ctx context.Context, exec sqlexec.RestrictedSQLExecutor, maxDays int) error {
+	if maxDays <= 0 {
+		return nil
+	}
+	before := time.Now().AddDate(0, 0, -maxDays)
+	stmt, err := exec.ParseWithParams(ctx, "DELETE FROM mysql.statements_summary_history WHERE summary_end_time < %?", before)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, _, err = exec.ExecRestrictedStmt(ctx, stmt)
+	return errors.Trace(err)
+}
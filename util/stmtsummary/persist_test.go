@@ -0,0 +1,76 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stmtsummary
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/auth"
+	"github.com/pingcap/tidb/types"
+)
+
+func (s *testStmtSummarySuite) TestCollectHistoryToPersist(c *C) {
+	s.ssMap.Clear()
+	now := time.Now().Unix()
+	s.ssMap.beginTimeForCurInterval = now + 60
+
+	stmtExecInfo1 := generateAnyExecInfo()
+	s.ssMap.AddStatement(stmtExecInfo1)
+	// The summary of the current interval isn't persisted.
+	rows, elements := s.ssMap.collectHistoryToPersist(now)
+	c.Assert(rows, HasLen, 0)
+	c.Assert(elements, HasLen, 0)
+
+	// The summary is persisted after its interval ends.
+	rows, elements = s.ssMap.collectHistoryToPersist(now + 1800 + 60)
+	c.Assert(rows, HasLen, 1)
+	c.Assert(elements, HasLen, 1)
+	c.Assert(rows[0], DeepEquals, s.ssMap.ToHistoryDatum(nil, true)[0])
+
+	// The persisted summary isn't persisted again.
+	elements[0].persisted = true
+	rows, _ = s.ssMap.collectHistoryToPersist(now + 1800 + 60)
+	c.Assert(rows, HasLen, 0)
+}
+
+func (s *testStmtSummarySuite) TestPersistedHistory(c *C) {
+	s.ssMap.Clear()
+	now := time.Now().Unix()
+	s.ssMap.beginTimeForCurInterval = now + 60
+
+	stmtExecInfo1 := generateAnyExecInfo()
+	s.ssMap.AddStatement(stmtExecInfo1)
+	s.ssMap.setPersistedHistory([]persistedSummary{
+		{row: types.MakeDatums("persisted1"), sampleUser: "user"},
+		{row: types.MakeDatums("persisted2"), sampleUser: "user2"},
+	})
+
+	// The persisted history is shown after the history in memory.
+	rows := s.ssMap.ToHistoryDatum(nil, true)
+	c.Assert(rows, HasLen, 3)
+	c.Assert(rows[1], DeepEquals, types.MakeDatums("persisted1"))
+	c.Assert(rows[2], DeepEquals, types.MakeDatums("persisted2"))
+
+	// Only the sample user can see the persisted history.
+	rows = s.ssMap.ToHistoryDatum(&auth.UserIdentity{Username: "user2"}, false)
+	c.Assert(rows, HasLen, 1)
+	c.Assert(rows[0], DeepEquals, types.MakeDatums("persisted2"))
+	rows = s.ssMap.ToHistoryDatum(&auth.UserIdentity{Username: "user2"}, true)
+	c.Assert(rows, HasLen, 3)
+
+	// The persisted history is cleared with the history in memory.
+	s.ssMap.Clear()
+	c.Assert(s.ssMap.ToHistoryDatum(nil, true), HasLen, 0)
+}
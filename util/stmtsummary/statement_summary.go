@@ -73,6 +73,9 @@ type stmtSummaryByDigestMap struct {
 
 	// sysVars encapsulates system variables needed to control statement summary.
 	sysVars *systemVars
+
+	// persistedHistory is the history persisted before tidb-server restarts, it isn't in summaryMap.
+	persistedHistory []persistedSummary
 }
 
 // StmtSummaryByDigestMap is a global map containing all statement summaries.
@@ -184,6 +187,8 @@ type stmtSummaryByDigestElement struct {
 	sumPDTotal           time.Duration
 	sumBackoffTotal      time.Duration
 	sumWriteSQLRespTotal time.Duration
+	sumWriteSQLRespBytes uint64
+	maxWriteSQLRespBytes uint64
 	prepared             bool
 	// The first time this type of SQL executes.
 	firstSeen time.Time
@@ -193,9 +198,13 @@ type stmtSummaryByDigestElement struct {
 	planInCache   bool
 	planCacheHits int64
 	planInBinding bool
+	// stats
+	usedStats string
 	// pessimistic execution retry information.
 	execRetryCount uint
 	execRetryTime  time.Duration
+	// persisted indicates whether the summary has been persisted to mysql.statements_summary_history.
+	persisted bool
 }
 
 // StmtExecInfo records execution information of each statement.
@@ -230,6 +239,8 @@ type StmtExecInfo struct {
 	execdetails.StmtExecDetails
 	TiKVExecDetails util.ExecDetails
 	Prepared        bool
+	// Sampled indicates the statement is sampled by tidb_slow_log_sample_rate, it replaces the samples of the summary.
+	Sampled bool
 }
 
 // newStmtSummaryByDigestMap creates an empty stmtSummaryByDigestMap.
@@ -305,6 +316,7 @@ func (ssMap *stmtSummaryByDigestMap) Clear() {
 
 	ssMap.summaryMap.DeleteAll()
 	ssMap.beginTimeForCurInterval = 0
+	ssMap.persistedHistory = nil
 }
 
 // clearInternal removes all statement summaries which are internal summaries.
@@ -344,14 +356,21 @@ func (ssMap *stmtSummaryByDigestMap) ToCurrentDatum(user *auth.UserIdentity, isS
 func (ssMap *stmtSummaryByDigestMap) ToHistoryDatum(user *auth.UserIdentity, isSuper bool) [][]types.Datum {
 	ssMap.Lock()
 	values := ssMap.summaryMap.Values()
+	persistedHistory := ssMap.persistedHistory
 	ssMap.Unlock()
 
 	historySize := ssMap.historySize()
-	rows := make([][]types.Datum, 0, len(values)*historySize)
+	rows := make([][]types.Datum, 0, len(values)*historySize+len(persistedHistory))
 	for _, value := range values {
 		records := value.(*stmtSummaryByDigest).toHistoryDatum(historySize, user, isSuper)
 		rows = append(rows, records...)
 	}
+	// Only the sample user is persisted, so the other users can't see the persisted history.
+	for _, summary := range persistedHistory {
+		if user == nil || isSuper || summary.sampleUser == user.Username {
+			rows = append(rows, summary.row)
+		}
+	}
 	return rows
 }
 
@@ -673,6 +692,16 @@ func (ssElement *stmtSummaryByDigestElement) add(sei *StmtExecInfo, intervalSeco
 		ssElement.authUsers[sei.User] = struct{}{}
 	}
 
+	// The samples are refreshed by the sampled statements, so they show the details of a recent execution instead
+	// of the first one. The sampling rate bounds the cost of encoding the plans.
+	if sei.Sampled && ssElement.execCount > 0 {
+		ssElement.sampleSQL = formatSQL(sei.OriginalSQL)
+		ssElement.charset, ssElement.collation = sei.Charset, sei.Collation
+		ssElement.prevSQL = sei.PrevSQL
+		ssElement.samplePlan, ssElement.planHint = sei.PlanGenerator()
+		ssElement.indexNames = sei.StmtCtx.IndexNames
+	}
+
 	// refreshInterval may change anytime, update endTime ASAP.
 	ssElement.endTime = ssElement.beginTime + intervalSeconds
 	ssElement.execCount++
@@ -825,6 +854,11 @@ func (ssElement *stmtSummaryByDigestElement) add(sei *StmtExecInfo, intervalSeco
 		ssElement.planInBinding = false
 	}
 
+	// stats, the statements using the cached plan keep the stats versions of the statement which compiles the plan.
+	if usedStats := sei.StmtCtx.UsedStatsString(); usedStats != "" {
+		ssElement.usedStats = usedStats
+	}
+
 	// other
 	ssElement.sumAffectedRows += sei.StmtCtx.AffectedRows()
 	ssElement.sumMem += sei.MemMax
@@ -849,6 +883,10 @@ func (ssElement *stmtSummaryByDigestElement) add(sei *StmtExecInfo, intervalSeco
 	ssElement.sumPDTotal += time.Duration(atomic.LoadInt64(&sei.TiKVExecDetails.WaitPDRespDuration))
 	ssElement.sumBackoffTotal += time.Duration(atomic.LoadInt64(&sei.TiKVExecDetails.BackoffDuration))
 	ssElement.sumWriteSQLRespTotal += sei.StmtExecDetails.WriteSQLRespDuration
+	ssElement.sumWriteSQLRespBytes += sei.StmtExecDetails.WriteSQLRespBytes
+	if sei.StmtExecDetails.WriteSQLRespBytes > ssElement.maxWriteSQLRespBytes {
+		ssElement.maxWriteSQLRespBytes = sei.StmtExecDetails.WriteSQLRespBytes
+	}
 }
 
 func (ssElement *stmtSummaryByDigestElement) toDatum(ssbd *stmtSummaryByDigest) []types.Datum {
@@ -946,6 +984,8 @@ func (ssElement *stmtSummaryByDigestElement) toDatum(ssbd *stmtSummaryByDigest)
 		avgInt(int64(ssElement.sumPDTotal), ssElement.commitCount),
 		avgInt(int64(ssElement.sumBackoffTotal), ssElement.commitCount),
 		avgInt(int64(ssElement.sumWriteSQLRespTotal), ssElement.commitCount),
+		avgInt(int64(ssElement.sumWriteSQLRespBytes), ssElement.execCount),
+		ssElement.maxWriteSQLRespBytes,
 		ssElement.prepared,
 		avgFloat(int64(ssElement.sumAffectedRows), ssElement.execCount),
 		types.NewTime(types.FromGoTime(ssElement.firstSeen), mysql.TypeTimestamp, 0),
@@ -957,6 +997,7 @@ func (ssElement *stmtSummaryByDigestElement) toDatum(ssbd *stmtSummaryByDigest)
 		ssElement.prevSQL,
 		ssbd.planDigest,
 		plan,
+		convertEmptyToNil(ssElement.usedStats),
 	)
 }
 
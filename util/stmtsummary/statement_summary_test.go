@@ -134,6 +134,8 @@ func (s *testStmtSummarySuite) TestAddStatement(c *C) {
 		maxMem:               stmtExecInfo1.MemMax,
 		sumDisk:              stmtExecInfo1.DiskMax,
 		maxDisk:              stmtExecInfo1.DiskMax,
+		sumWriteSQLRespBytes: stmtExecInfo1.WriteSQLRespBytes,
+		maxWriteSQLRespBytes: stmtExecInfo1.WriteSQLRespBytes,
 		sumAffectedRows:      stmtExecInfo1.StmtCtx.AffectedRows(),
 		firstSeen:            stmtExecInfo1.StartTime,
 		lastSeen:             stmtExecInfo1.StartTime,
@@ -225,6 +227,7 @@ func (s *testStmtSummarySuite) TestAddStatement(c *C) {
 		Succeed:   true,
 	}
 	stmtExecInfo2.StmtCtx.AddAffectedRows(200)
+	stmtExecInfo2.WriteSQLRespBytes = 20000
 	expectedSummaryElement.execCount++
 	expectedSummaryElement.sumLatency += stmtExecInfo2.TotalLatency
 	expectedSummaryElement.maxLatency = stmtExecInfo2.TotalLatency
@@ -273,6 +276,8 @@ func (s *testStmtSummarySuite) TestAddStatement(c *C) {
 	expectedSummaryElement.maxMem = stmtExecInfo2.MemMax
 	expectedSummaryElement.sumDisk += stmtExecInfo2.DiskMax
 	expectedSummaryElement.maxDisk = stmtExecInfo2.DiskMax
+	expectedSummaryElement.sumWriteSQLRespBytes += stmtExecInfo2.WriteSQLRespBytes
+	expectedSummaryElement.maxWriteSQLRespBytes = stmtExecInfo2.WriteSQLRespBytes
 	expectedSummaryElement.sumAffectedRows += stmtExecInfo2.StmtCtx.AffectedRows()
 	expectedSummaryElement.lastSeen = stmtExecInfo2.StartTime
 
@@ -352,6 +357,7 @@ func (s *testStmtSummarySuite) TestAddStatement(c *C) {
 		Succeed:   true,
 	}
 	stmtExecInfo3.StmtCtx.AddAffectedRows(20000)
+	stmtExecInfo3.WriteSQLRespBytes = 200
 	expectedSummaryElement.execCount++
 	expectedSummaryElement.sumLatency += stmtExecInfo3.TotalLatency
 	expectedSummaryElement.minLatency = stmtExecInfo3.TotalLatency
@@ -377,6 +383,7 @@ func (s *testStmtSummarySuite) TestAddStatement(c *C) {
 	expectedSummaryElement.backoffTypes[tikv.BoTxnLock] = 2
 	expectedSummaryElement.sumMem += stmtExecInfo3.MemMax
 	expectedSummaryElement.sumDisk += stmtExecInfo3.DiskMax
+	expectedSummaryElement.sumWriteSQLRespBytes += stmtExecInfo3.WriteSQLRespBytes
 	expectedSummaryElement.sumAffectedRows += stmtExecInfo3.StmtCtx.AffectedRows()
 	expectedSummaryElement.firstSeen = stmtExecInfo3.StartTime
 
@@ -499,6 +506,8 @@ func matchStmtSummaryByDigest(first, second *stmtSummaryByDigest) bool {
 			ssElement1.sumBackoffTimes != ssElement2.sumBackoffTimes ||
 			ssElement1.sumMem != ssElement2.sumMem ||
 			ssElement1.maxMem != ssElement2.maxMem ||
+			ssElement1.sumWriteSQLRespBytes != ssElement2.sumWriteSQLRespBytes ||
+			ssElement1.maxWriteSQLRespBytes != ssElement2.maxWriteSQLRespBytes ||
 			ssElement1.sumAffectedRows != ssElement2.sumAffectedRows ||
 			ssElement1.firstSeen != ssElement2.firstSeen ||
 			ssElement1.lastSeen != ssElement2.lastSeen {
@@ -608,6 +617,7 @@ func generateAnyExecInfo() *StmtExecInfo {
 		Succeed:   true,
 	}
 	stmtExecInfo.StmtCtx.AddAffectedRows(10000)
+	stmtExecInfo.WriteSQLRespBytes = 10000
 	return stmtExecInfo
 }
 
@@ -619,6 +629,8 @@ func (s *testStmtSummarySuite) TestToDatum(c *C) {
 	s.ssMap.beginTimeForCurInterval = now + 60
 
 	stmtExecInfo1 := generateAnyExecInfo()
+	stmtExecInfo1.StmtCtx.RecordUsedStats(1, stmtctx.UsedStatsInfo{Name: "tb1", Version: 2, HistVersion: 1})
+	stmtExecInfo1.StmtCtx.RecordUsedStats(2, stmtctx.UsedStatsInfo{Name: "tb2"})
 	s.ssMap.AddStatement(stmtExecInfo1)
 	datums := s.ssMap.ToCurrentDatum(nil, true)
 	c.Assert(len(datums), Equals, 1)
@@ -651,12 +663,20 @@ func (s *testStmtSummarySuite) TestToDatum(c *C) {
 		stmtExecInfo1.ExecDetail.CommitDetail.PrewriteRegionNum, stmtExecInfo1.ExecDetail.CommitDetail.PrewriteRegionNum,
 		stmtExecInfo1.ExecDetail.CommitDetail.TxnRetry, stmtExecInfo1.ExecDetail.CommitDetail.TxnRetry, 0, 0, 1,
 		"txnLock:1", stmtExecInfo1.MemMax, stmtExecInfo1.MemMax, stmtExecInfo1.DiskMax, stmtExecInfo1.DiskMax,
-		0, 0, 0, 0, 0, stmtExecInfo1.StmtCtx.AffectedRows(),
-		t, t, 0, 0, 0, stmtExecInfo1.OriginalSQL, stmtExecInfo1.PrevSQL, "plan_digest", ""}
+		0, 0, 0, 0, stmtExecInfo1.WriteSQLRespBytes, stmtExecInfo1.WriteSQLRespBytes, 0, stmtExecInfo1.StmtCtx.AffectedRows(),
+		t, t, 0, 0, 0, stmtExecInfo1.OriginalSQL, stmtExecInfo1.PrevSQL, "plan_digest", "", "tb1:2[hist:1],tb2:pseudo"}
 	match(c, datums[0], expectedDatum...)
 	datums = s.ssMap.ToHistoryDatum(nil, true)
 	c.Assert(len(datums), Equals, 1)
 	match(c, datums[0], expectedDatum...)
+
+	// The statement using the cached plan keeps the stats versions recorded when the plan is compiled.
+	stmtExecInfo2 := generateAnyExecInfo()
+	stmtExecInfo2.PlanInCache = true
+	s.ssMap.AddStatement(stmtExecInfo2)
+	datums = s.ssMap.ToCurrentDatum(nil, true)
+	c.Assert(len(datums), Equals, 1)
+	c.Assert(datums[0][len(datums[0])-1].GetString(), Equals, "tb1:2[hist:1],tb2:pseudo")
 }
 
 // Test AddStatement and ToDatum parallel.
@@ -1166,6 +1186,38 @@ func (s *testStmtSummarySuite) TestPointGet(c *C) {
 	c.Assert(ssElement.execCount, Equals, int64(2))
 }
 
+func (s *testStmtSummarySuite) TestSampledStatement(c *C) {
+	s.ssMap.Clear()
+	now := time.Now().Unix()
+	s.ssMap.beginTimeForCurInterval = now - 100
+
+	stmtExecInfo1 := generateAnyExecInfo()
+	s.ssMap.AddStatement(stmtExecInfo1)
+	key := &stmtSummaryByDigestKey{
+		schemaName: stmtExecInfo1.SchemaName,
+		digest:     stmtExecInfo1.Digest,
+		planDigest: stmtExecInfo1.PlanDigest,
+	}
+	value, ok := s.ssMap.summaryMap.Get(key)
+	c.Assert(ok, IsTrue)
+	ssElement := value.(*stmtSummaryByDigest).history.Back().Value.(*stmtSummaryByDigestElement)
+
+	// The samples are kept if the statement isn't sampled.
+	stmtExecInfo2 := *stmtExecInfo1
+	stmtExecInfo2.OriginalSQL = "original_sql2"
+	stmtExecInfo2.StmtCtx = &stmtctx.StatementContext{IndexNames: []string{"b"}}
+	s.ssMap.AddStatement(&stmtExecInfo2)
+	c.Assert(ssElement.sampleSQL, Equals, "original_sql1")
+	c.Assert(ssElement.indexNames, DeepEquals, []string{"a"})
+
+	// The sampled statement replaces the samples.
+	stmtExecInfo2.Sampled = true
+	s.ssMap.AddStatement(&stmtExecInfo2)
+	c.Assert(ssElement.sampleSQL, Equals, "original_sql2")
+	c.Assert(ssElement.indexNames, DeepEquals, []string{"b"})
+	c.Assert(ssElement.execCount, Equals, int64(3))
+}
+
 func (s *testStmtSummarySuite) TestAccessPrivilege(c *C) {
 	s.ssMap.Clear()
 
@@ -0,0 +1,67 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txninfo
+
+import (
+	"time"
+)
+
+// TxnRunningState is the current state of a transaction.
+type TxnRunningState = int32
+
+const (
+	// TxnRunning means the transaction is running, i.e. executing a statement or waiting for the next one.
+	TxnRunning TxnRunningState = iota
+	// TxnLockWaiting means the transaction is acquiring the pessimistic locks.
+	TxnLockWaiting
+	// TxnCommitting means the transaction is committing.
+	TxnCommitting
+	// TxnRollingBack means the transaction is rolling back.
+	TxnRollingBack
+)
+
+// TxnRunningStateStrs is the names of the TxnRunningStates.
+var TxnRunningStateStrs = []string{
+	"Running", "LockWaiting", "Committing", "RollingBack",
+}
+
+// LockWait is a key the transaction is waiting for, which is locked by the transaction HoldingTxnID.
+type LockWait struct {
+	Key          []byte
+	HoldingTxnID uint64
+}
+
+// TxnInfo is the information of a running transaction.
+type TxnInfo struct {
+	StartTS uint64
+	// CurrentSQLDigest is the digest of the statement the transaction is executing, or the last executed one.
+	CurrentSQLDigest     string
+	CurrentSQLDigestText string
+	State                TxnRunningState
+	// BlockStartTime is the time when the transaction starts to acquire the pessimistic locks, it's only set when the
+	// state is TxnLockWaiting.
+	BlockStartTime time.Time
+	// LockWaits are the keys the transaction is blocked by, they are reported when the lock requests return from
+	// TiKV, so they may be empty during the first round of the lock waiting.
+	LockWaits []LockWait
+	// EntriesCount and EntriesSize are the count and the size of the entries in the membuffer of the transaction,
+	// they are updated when the statements finish.
+	EntriesCount uint64
+	EntriesSize  uint64
+
+	// The following fields are filled by the session.
+	ConnectionID uint64
+	Username     string
+	CurrentDB    string
+}
@@ -0,0 +1,116 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txntimeout
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/util"
+)
+
+const (
+	// ReasonIdle means the transaction is idle for longer than tidb_idle_transaction_timeout.
+	ReasonIdle = "idle"
+	// ReasonLifetime means the transaction lives longer than tidb_max_transaction_duration.
+	ReasonLifetime = "lifetime"
+)
+
+// historyCapacity is the max number of the events kept in GlobalHistory.
+const historyCapacity = 1024
+
+// GlobalHistory records the transactions killed by the timeouts on this tidb-server.
+var GlobalHistory = NewHistory(historyCapacity)
+
+// Check returns the reason why the transaction of the session should be killed, or an empty string if it should not.
+func Check(pi *util.ProcessInfo, now time.Time) string {
+	if pi.TxnStartTime.IsZero() {
+		return ""
+	}
+	if pi.Command == mysql.ComSleep && pi.IdleTxnTimeout > 0 && !pi.IdleStartTime.IsZero() &&
+		now.Sub(pi.IdleStartTime) >= pi.IdleTxnTimeout {
+		return ReasonIdle
+	}
+	if pi.MaxTxnDuration > 0 && now.Sub(pi.TxnStartTime) >= pi.MaxTxnDuration {
+		return ReasonLifetime
+	}
+	return ""
+}
+
+// Event is a transaction killed by the timeouts.
+type Event struct {
+	ConnID    uint64
+	User      string
+	Host      string
+	DB        string
+	StartTS   uint64
+	StartTime time.Time
+	KillTime  time.Time
+	Reason    string
+}
+
+// NewEvent builds the event of the transaction of the session killed for the reason.
+func NewEvent(pi *util.ProcessInfo, reason string, now time.Time) Event {
+	return Event{
+		ConnID:    pi.ID,
+		User:      pi.User,
+		Host:      pi.Host,
+		DB:        pi.DB,
+		StartTS:   pi.CurTxnStartTS,
+		StartTime: pi.TxnStartTime,
+		KillTime:  now,
+		Reason:    reason,
+	}
+}
+
+// History is a fixed size ring buffer of the recent events.
+type History struct {
+	mu     sync.Mutex
+	events []Event
+	next   int
+	full   bool
+}
+
+// NewHistory creates a History which keeps at most capacity events.
+func NewHistory(capacity int) *History {
+	return &History{events: make([]Event, capacity)}
+}
+
+// Push records the event, the oldest event is dropped if the history is full.
+func (h *History) Push(e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.events) == 0 {
+		return
+	}
+	h.events[h.next] = e
+	h.next++
+	if h.next == len(h.events) {
+		h.next = 0
+		h.full = true
+	}
+}
+
+// Events returns a copy of the recorded events, from the oldest to the newest.
+func (h *History) Events() []Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.full {
+		return append([]Event(nil), h.events[:h.next]...)
+	}
+	events := make([]Event, 0, len(h.events))
+	events = append(events, h.events[h.next:]...)
+	return append(events, h.events[:h.next]...)
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txntimeout
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/util"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testTxnTimeoutSuite{})
+
+type testTxnTimeoutSuite struct {
+}
+
+func (s *testTxnTimeoutSuite) TestCheck(c *C) {
+	now := time.Now()
+	pi := &util.ProcessInfo{
+		Command:        mysql.ComSleep,
+		IdleStartTime:  now.Add(-2 * time.Second),
+		IdleTxnTimeout: time.Second,
+		MaxTxnDuration: time.Minute,
+	}
+	// Not in a transaction.
+	c.Assert(Check(pi, now), Equals, "")
+
+	pi.TxnStartTime = now.Add(-3 * time.Second)
+	c.Assert(Check(pi, now), Equals, ReasonIdle)
+
+	// The idle timeout doesn't apply to the running statement.
+	pi.Command = mysql.ComQuery
+	c.Assert(Check(pi, now), Equals, "")
+	pi.TxnStartTime = now.Add(-time.Hour)
+	c.Assert(Check(pi, now), Equals, ReasonLifetime)
+
+	// Zero disables the timeouts.
+	pi.Command = mysql.ComSleep
+	pi.IdleTxnTimeout, pi.MaxTxnDuration = 0, 0
+	c.Assert(Check(pi, now), Equals, "")
+}
+
+func (s *testTxnTimeoutSuite) TestHistory(c *C) {
+	h := NewHistory(3)
+	c.Assert(h.Events(), HasLen, 0)
+	for i := uint64(1); i <= 2; i++ {
+		h.Push(Event{ConnID: i})
+	}
+	events := h.Events()
+	c.Assert(events, HasLen, 2)
+	c.Assert(events[0].ConnID, Equals, uint64(1))
+	c.Assert(events[1].ConnID, Equals, uint64(2))
+
+	// The oldest events are dropped when the history is full.
+	for i := uint64(3); i <= 5; i++ {
+		h.Push(Event{ConnID: i})
+	}
+	events = h.Events()
+	c.Assert(events, HasLen, 3)
+	for i, event := range events {
+		c.Assert(event.ConnID, Equals, uint64(i+3))
+	}
+}